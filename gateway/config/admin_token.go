@@ -0,0 +1,29 @@
+package config
+
+// The scopes an admin token can be issued with
+const (
+	// AdminScopeConfigRead allows reading the config of the cluster and its projects
+	AdminScopeConfigRead = "config:read"
+	// AdminScopeConfigWrite allows modifying the config of projects
+	AdminScopeConfigWrite = "config:write"
+	// AdminScopeMetricsRead allows reading the metrics, usage and health of the cluster
+	AdminScopeMetricsRead = "metrics:read"
+	// AdminScopeClusterAdmin allows every operation including managing the cluster, integrations and admin tokens
+	AdminScopeClusterAdmin = "cluster:admin"
+)
+
+// AdminScopes are the scopes an admin token can be issued with
+var AdminScopes = []string{AdminScopeConfigRead, AdminScopeConfigWrite, AdminScopeMetricsRead, AdminScopeClusterAdmin}
+
+// AdminTokens describes the admin tokens issued in the cluster
+type AdminTokens map[string]*AdminToken
+
+// AdminToken describes an admin token issued with a limited set of scopes. Deleting it revokes the token.
+type AdminToken struct {
+	ID        string   `json:"id" yaml:"id" mapstructure:"id"`
+	Name      string   `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Scopes    []string `json:"scopes" yaml:"scopes" mapstructure:"scopes"`
+	CreatedAt int64    `json:"createdAt" yaml:"createdAt" mapstructure:"createdAt"`
+	// ExpiresAt is the unix timestamp in seconds after which the token is rejected. The token never expires if it's zero.
+	ExpiresAt int64 `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty" mapstructure:"expiresAt"`
+}
@@ -0,0 +1,39 @@
+package config
+
+// BackupConfig describes the periodic backups of the data of a project. Backups are taken by the leader of the cluster.
+type BackupConfig struct {
+	ID      string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Enabled bool   `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Interval is the interval in seconds at which backups are taken. It defaults to a day
+	Interval    int                 `json:"interval,omitempty" yaml:"interval,omitempty" mapstructure:"interval"`
+	Collections []*BackupCollection `json:"collections" yaml:"collections" mapstructure:"collections"`
+	Store       *BackupStore        `json:"store" yaml:"store" mapstructure:"store"`
+	Retention   *BackupRetention    `json:"retention,omitempty" yaml:"retention,omitempty" mapstructure:"retention"`
+}
+
+// BackupCollection describes a collection whose documents are backed up
+type BackupCollection struct {
+	DbAlias string `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	Col     string `json:"col" yaml:"col" mapstructure:"col"`
+}
+
+// BackupStore describes the bucket the backups are stored in. The store type is either amazon-s3, gcp-storage or local.
+// The credentials are picked from the environment of the gateway.
+type BackupStore struct {
+	StoreType string `json:"storeType" yaml:"storeType" mapstructure:"storeType"`
+	// Conn is the region for amazon-s3 and the root directory for local stores
+	Conn           string `json:"conn,omitempty" yaml:"conn,omitempty" mapstructure:"conn"`
+	Endpoint       string `json:"endpoint,omitempty" yaml:"endpoint,omitempty" mapstructure:"endpoint"`
+	Bucket         string `json:"bucket,omitempty" yaml:"bucket,omitempty" mapstructure:"bucket"`
+	Prefix         string `json:"prefix,omitempty" yaml:"prefix,omitempty" mapstructure:"prefix"`
+	DisableSSL     *bool  `json:"disableSSL,omitempty" yaml:"disableSSL,omitempty" mapstructure:"disableSSL"`
+	ForcePathStyle *bool  `json:"forcePathStyle,omitempty" yaml:"forcePathStyle,omitempty" mapstructure:"forcePathStyle"`
+}
+
+// BackupRetention describes how long backups are kept. Backups are kept forever if neither limit is set.
+type BackupRetention struct {
+	// MaxBackups is the number of most recent backups which are kept
+	MaxBackups int `json:"maxBackups,omitempty" yaml:"maxBackups,omitempty" mapstructure:"maxBackups"`
+	// MaxAge is the number of days after which a backup is deleted
+	MaxAge int `json:"maxAge,omitempty" yaml:"maxAge,omitempty" mapstructure:"maxAge"`
+}
@@ -12,12 +12,177 @@ type Config struct {
 	Integrations     Integrations     `json:"integrations" yaml:"integrations" mapstructure:"integrations"`
 	IntegrationHooks IntegrationHooks `json:"integrationsHooks" yaml:"integrationsHooks" mapstructure:"integrationsHooks"`
 	CacheConfig      *CacheConfig     `json:"cacheConfig" yaml:"cacheConfig" mapstructure:"cacheConfig"`
+	ProjectTemplates ProjectTemplates `json:"projectTemplates,omitempty" yaml:"projectTemplates,omitempty" mapstructure:"projectTemplates"`
+	AdminTokens      AdminTokens      `json:"adminTokens,omitempty" yaml:"adminTokens,omitempty" mapstructure:"adminTokens"`
 }
 
 // ClusterConfig holds the cluster level configuration
 type ClusterConfig struct {
-	LetsEncryptEmail string `json:"letsencryptEmail" yaml:"letsencryptEmail" mapstructure:"letsencryptEmail"`
-	EnableTelemetry  bool   `json:"enableTelemetry" yaml:"enableTelemetry" mapstructure:"enableTelemetry"`
+	LetsEncryptEmail string             `json:"letsencryptEmail" yaml:"letsencryptEmail" mapstructure:"letsencryptEmail"`
+	EnableTelemetry  bool               `json:"enableTelemetry" yaml:"enableTelemetry" mapstructure:"enableTelemetry"`
+	Tracing          *TracingConfig     `json:"tracing,omitempty" yaml:"tracing,omitempty" mapstructure:"tracing"`
+	Logging          *LoggingConfig     `json:"logging,omitempty" yaml:"logging,omitempty" mapstructure:"logging"`
+	Accounting       *AccountingConfig  `json:"accounting,omitempty" yaml:"accounting,omitempty" mapstructure:"accounting"`
+	Admission        *AdmissionConfig   `json:"admission,omitempty" yaml:"admission,omitempty" mapstructure:"admission"`
+	Compression      *CompressionConfig `json:"compression,omitempty" yaml:"compression,omitempty" mapstructure:"compression"`
+	Metrics          *MetricsConfig     `json:"metrics,omitempty" yaml:"metrics,omitempty" mapstructure:"metrics"`
+	Diagnostics      *DiagnosticsConfig `json:"diagnostics,omitempty" yaml:"diagnostics,omitempty" mapstructure:"diagnostics"`
+}
+
+// DiagnosticsConfig exposes the pprof profiles, the gc stats and the goroutine dumps of the gateways on the admin
+// apis. It is meant to be turned on while diagnosing an issue in production.
+type DiagnosticsConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// BlockProfileRate is the rate at which the blocking events are sampled in nanoseconds of blocking. Zero disables
+	// the block profile
+	BlockProfileRate int `json:"blockProfileRate,omitempty" yaml:"blockProfileRate,omitempty" mapstructure:"blockProfileRate"`
+	// MutexProfileFraction samples 1 in n of the contended mutexes. Zero disables the mutex profile
+	MutexProfileFraction int `json:"mutexProfileFraction,omitempty" yaml:"mutexProfileFraction,omitempty" mapstructure:"mutexProfileFraction"`
+}
+
+// MetricsConfig describes the sinks the operational metrics of the gateway are pushed to. The metrics are exported on
+// the prometheus endpoint irrespective of it.
+type MetricsConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// FlushInterval is the interval in seconds at which the metrics are pushed. It defaults to 10
+	FlushInterval int `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" mapstructure:"flushInterval"`
+	// Prefix is prepended to the names of the metrics. It defaults to space_cloud
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty" mapstructure:"prefix"`
+	// Tags are added to every metric pushed
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" mapstructure:"tags"`
+	// TagMapping renames the labels of the metrics to the tags used by the sinks. Labels mapped to an empty string
+	// are dropped.
+	TagMapping map[string]string `json:"tagMapping,omitempty" yaml:"tagMapping,omitempty" mapstructure:"tagMapping"`
+	Sinks      []*MetricsSink    `json:"sinks,omitempty" yaml:"sinks,omitempty" mapstructure:"sinks"`
+}
+
+// MetricsSink is a monitoring backend the metrics are pushed to
+type MetricsSink struct {
+	// Type is one of statsd, dogstatsd or cloudwatch
+	Type string `json:"type" yaml:"type" mapstructure:"type"`
+
+	// Address is the host:port of the statsd agent. It defaults to localhost:8125
+	Address string `json:"address,omitempty" yaml:"address,omitempty" mapstructure:"address"`
+
+	// Namespace & Region are used by the cloudwatch sink. The namespace defaults to SpaceCloud. The credentials are
+	// picked from the default credential chain of the aws sdk.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty" mapstructure:"namespace"`
+	Region    string `json:"region,omitempty" yaml:"region,omitempty" mapstructure:"region"`
+}
+
+// CompressionConfig describes the compression of the responses of the gateway. The encoding is negotiated with the
+// Accept-Encoding header of the request. Responses which are already compressed, event streams and the responses
+// marked with the no-transform cache control directive are never compressed.
+type CompressionConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Encodings are the encodings offered in the order of preference. They default to zstd, br and gzip.
+	Encodings []string `json:"encodings,omitempty" yaml:"encodings,omitempty" mapstructure:"encodings"`
+	// Levels overrides the default compression level of the encodings. Key here is the encoding
+	Levels map[string]int `json:"levels,omitempty" yaml:"levels,omitempty" mapstructure:"levels"`
+	// MinSize is the size in bytes below which responses aren't compressed. It defaults to 1024
+	MinSize int `json:"minSize,omitempty" yaml:"minSize,omitempty" mapstructure:"minSize"`
+	// ExcludePaths are the prefixes of the url paths whose responses are never compressed
+	ExcludePaths []string `json:"excludePaths,omitempty" yaml:"excludePaths,omitempty" mapstructure:"excludePaths"`
+}
+
+// AdmissionConfig protects a gateway node from overload by shedding its low priority traffic. The node is overloaded
+// when the requests in flight exceed MaxConcurrency or their average latency exceeds MaxLatency. A zero value disables
+// the corresponding threshold.
+type AdmissionConfig struct {
+	Enabled        bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	MaxConcurrency int  `json:"maxConcurrency,omitempty" yaml:"maxConcurrency,omitempty" mapstructure:"maxConcurrency"`
+	MaxLatency     int  `json:"maxLatency,omitempty" yaml:"maxLatency,omitempty" mapstructure:"maxLatency"` // in milli seconds
+
+	// Requests exceeding the max concurrency wait in a priority queue for up to QueueTimeout milli seconds (defaults
+	// to 1000). The shed requests are asked to retry after RetryAfter seconds (defaults to 5).
+	QueueTimeout int `json:"queueTimeout,omitempty" yaml:"queueTimeout,omitempty" mapstructure:"queueTimeout"`
+	RetryAfter   int `json:"retryAfter,omitempty" yaml:"retryAfter,omitempty" mapstructure:"retryAfter"`
+
+	// DefaultPriority is the priority of the client api requests which don't match any rule. It defaults to normal.
+	// The config apis and the other requests of the gateway are critical unless a rule says otherwise.
+	DefaultPriority RequestPriority  `json:"defaultPriority,omitempty" yaml:"defaultPriority,omitempty" mapstructure:"defaultPriority"`
+	Rules           []*AdmissionRule `json:"rules,omitempty" yaml:"rules,omitempty" mapstructure:"rules"`
+}
+
+// AdmissionRule sets the priority of the requests of a project or a route. The first matching rule is used.
+type AdmissionRule struct {
+	Project  string          `json:"project,omitempty" yaml:"project,omitempty" mapstructure:"project"`
+	Path     string          `json:"path,omitempty" yaml:"path,omitempty" mapstructure:"path"` // prefix of the url path
+	Priority RequestPriority `json:"priority" yaml:"priority" mapstructure:"priority"`
+}
+
+// RequestPriority decides the order in which requests are shed when a gateway node is overloaded
+type RequestPriority string
+
+const (
+	// PriorityCritical requests are never shed
+	PriorityCritical RequestPriority = "critical"
+
+	// PriorityHigh requests are queued when the concurrency is exceeded and are only shed by timing out in the queue.
+	// They are served first as requests complete.
+	PriorityHigh RequestPriority = "high"
+
+	// PriorityNormal requests are queued when the concurrency is exceeded and are shed when the latency is exceeded
+	PriorityNormal RequestPriority = "normal"
+
+	// PriorityLow requests are shed as soon as the node is overloaded
+	PriorityLow RequestPriority = "low"
+)
+
+// AccountingConfig describes the periodic export of the resource usage of every project
+type AccountingConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// ExportInterval is the interval in seconds at which the usage is exported. It defaults to an hour
+	ExportInterval int `json:"exportInterval,omitempty" yaml:"exportInterval,omitempty" mapstructure:"exportInterval"`
+	// CSVPath is the file to which the usage is appended in the csv format
+	CSVPath string `json:"csvPath,omitempty" yaml:"csvPath,omitempty" mapstructure:"csvPath"`
+	// WebhookURL receives the usage as a json array. Headers are added to the webhook requests
+	WebhookURL string            `json:"webhookUrl,omitempty" yaml:"webhookUrl,omitempty" mapstructure:"webhookUrl"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+}
+
+// LoggingConfig describes the configuration of the structured access and error logs
+type LoggingConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Level is one of debug, info, warn or error. It defaults to info
+	Level string `json:"level,omitempty" yaml:"level,omitempty" mapstructure:"level"`
+	// ProjectLevels overrides the log level for individual projects. Key here is the project id
+	ProjectLevels map[string]string `json:"projectLevels,omitempty" yaml:"projectLevels,omitempty" mapstructure:"projectLevels"`
+	// SampleRate is the fraction of successful requests which get logged. Errors are always logged. It defaults to 1
+	SampleRate *float64 `json:"sampleRate,omitempty" yaml:"sampleRate,omitempty" mapstructure:"sampleRate"`
+	// RedactFields are redacted in addition to the well known secret and PII fields
+	RedactFields []string   `json:"redactFields,omitempty" yaml:"redactFields,omitempty" mapstructure:"redactFields"`
+	Sinks        []*LogSink `json:"sinks,omitempty" yaml:"sinks,omitempty" mapstructure:"sinks"`
+	// LogRuleDenials logs every request denied by the security rules along with the paths of the failing rules,
+	// irrespective of the log level
+	LogRuleDenials bool `json:"logRuleDenials,omitempty" yaml:"logRuleDenials,omitempty" mapstructure:"logRuleDenials"`
+}
+
+// LogSink describes a destination of the structured logs
+type LogSink struct {
+	// Type is one of stdout, file, http or loki
+	Type string `json:"type" yaml:"type" mapstructure:"type"`
+
+	// Path, MaxSizeMB & MaxBackups are used by the file sink
+	Path       string `json:"path,omitempty" yaml:"path,omitempty" mapstructure:"path"`
+	MaxSizeMB  int    `json:"maxSizeMB,omitempty" yaml:"maxSizeMB,omitempty" mapstructure:"maxSizeMB"`
+	MaxBackups int    `json:"maxBackups,omitempty" yaml:"maxBackups,omitempty" mapstructure:"maxBackups"`
+
+	// URL & Headers are used by the http and loki sinks
+	URL     string            `json:"url,omitempty" yaml:"url,omitempty" mapstructure:"url"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+}
+
+// TracingConfig describes the configuration of the OpenTelemetry tracing exporter
+type TracingConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Endpoint is the OTLP/gRPC address of the collector, either as host:port or as an http(s) url deciding whether tls is used
+	Endpoint string            `json:"endpoint" yaml:"endpoint" mapstructure:"endpoint"`
+	Headers  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+	// ServiceName defaults to space-cloud
+	ServiceName string `json:"serviceName,omitempty" yaml:"serviceName,omitempty" mapstructure:"serviceName"`
+	// SampleRatio is the fraction of root spans which get sampled. It defaults to 1
+	SampleRatio *float64 `json:"sampleRatio,omitempty" yaml:"sampleRatio,omitempty" mapstructure:"sampleRatio"`
 }
 
 // Projects is a map which stores config information of all project in a cluster
@@ -41,6 +206,12 @@ type EventingSchemas map[string]*EventingSchema // Key here is resource id --> c
 // EventingRules is a map which stores database config information
 type EventingRules map[string]*Rule // Key here is resource id --> clusterId--projectId--resourceType--ruleId
 
+// EventingSources is a map which stores the custom event sources of eventing
+type EventingSources map[string]*EventingSource // Key here is resource id --> clusterId--projectId--resourceType--sourceId
+
+// EventingWorkflows is a map which stores the workflows of eventing
+type EventingWorkflows map[string]*EventingWorkflow // Key here is resource id --> clusterId--projectId--resourceType--workflowId
+
 // EventingTriggers is a map which stores database config information
 type EventingTriggers map[string]*EventingTrigger // Key here is resource id --> clusterId--projectId--resourceType--triggerId
 
@@ -59,10 +230,12 @@ type Project struct {
 	DatabaseRules           DatabaseRules           `json:"dbRules" yaml:"dbRules" mapstructure:"dbRules"`
 	DatabasePreparedQueries DatabasePreparedQueries `json:"dbPreparedQuery" yaml:"dbPreparedQuery" mapstructure:"dbPreparedQuery"`
 
-	EventingConfig   *EventingConfig  `json:"eventingConfig" yaml:"eventingConfig" mapstructure:"eventingConfig"`
-	EventingSchemas  EventingSchemas  `json:"eventingSchemas" yaml:"eventingSchemas" mapstructure:"eventingSchemas"`
-	EventingRules    EventingRules    `json:"eventingRules" yaml:"eventingRules" mapstructure:"eventingRules"`
-	EventingTriggers EventingTriggers `json:"eventingTriggers" yaml:"eventingTriggers" mapstructure:"eventingTriggers"`
+	EventingConfig    *EventingConfig   `json:"eventingConfig" yaml:"eventingConfig" mapstructure:"eventingConfig"`
+	EventingSchemas   EventingSchemas   `json:"eventingSchemas" yaml:"eventingSchemas" mapstructure:"eventingSchemas"`
+	EventingRules     EventingRules     `json:"eventingRules" yaml:"eventingRules" mapstructure:"eventingRules"`
+	EventingTriggers  EventingTriggers  `json:"eventingTriggers" yaml:"eventingTriggers" mapstructure:"eventingTriggers"`
+	EventingSources   EventingSources   `json:"eventingSources,omitempty" yaml:"eventingSources,omitempty" mapstructure:"eventingSources"`
+	EventingWorkflows EventingWorkflows `json:"eventingWorkflows,omitempty" yaml:"eventingWorkflows,omitempty" mapstructure:"eventingWorkflows"`
 
 	FileStoreConfig *FileStoreConfig `json:"fileStoreConfig" yaml:"fileStoreConfig" mapstructure:"fileStoreConfig"`
 	FileStoreRules  FileStoreRules   `json:"fileStoreRules" yaml:"fileStoreRules" mapstructure:"fileStoreRules"`
@@ -71,10 +244,22 @@ type Project struct {
 
 	LetsEncrypt *LetsEncrypt `json:"letsencrypt" yaml:"letsencrypt" mapstructure:"letsencrypt"`
 
+	SearchConfig *SearchConfig `json:"searchConfig,omitempty" yaml:"searchConfig,omitempty" mapstructure:"searchConfig"`
+
+	BackupConfig *BackupConfig `json:"backupConfig,omitempty" yaml:"backupConfig,omitempty" mapstructure:"backupConfig"`
+
+	KVConfig *KVConfig `json:"kvConfig,omitempty" yaml:"kvConfig,omitempty" mapstructure:"kvConfig"`
+
+	PrivacyConfig *PrivacyConfig `json:"privacyConfig,omitempty" yaml:"privacyConfig,omitempty" mapstructure:"privacyConfig"`
+
+	FeatureFlags FeatureFlags `json:"featureFlags,omitempty" yaml:"featureFlags,omitempty" mapstructure:"featureFlags"`
+
 	IngressRoutes IngressRoutes       `json:"ingressRoute" yaml:"ingressRoute" mapstructure:"ingressRoute"`
 	IngressGlobal *GlobalRoutesConfig `json:"ingressGlobal" yaml:"ingressGlobal" mapstructure:"ingressGlobal"`
 
 	RemoteService Services `json:"remoteServices" yaml:"remoteServices" mapstructure:"remoteServices"`
+
+	DeploySecrets DeploySecrets `json:"deploySecrets,omitempty" yaml:"deploySecrets,omitempty" mapstructure:"deploySecrets"`
 }
 
 // ProjectConfig stores information of individual project
@@ -87,14 +272,109 @@ type ProjectConfig struct {
 	AESKey             string    `json:"aesKey,omitempty" yaml:"aesKey,omitempty" mapstructure:"aesKey"`
 	DockerRegistry     string    `json:"dockerRegistry,omitempty" yaml:"dockerRegistry,omitempty" mapstructure:"dockerRegistry"`
 	ContextTimeGraphQL int       `json:"contextTimeGraphQL,omitempty" yaml:"contextTimeGraphQL,omitempty" mapstructure:"contextTimeGraphQL"` // contextTime sets the timeout of query
+
+	GraphQLLimits    *GraphQLLimits    `json:"graphqlLimits,omitempty" yaml:"graphqlLimits,omitempty" mapstructure:"graphqlLimits"`
+	PersistedQueries *PersistedQueries `json:"persistedQueries,omitempty" yaml:"persistedQueries,omitempty" mapstructure:"persistedQueries"`
+	QueryBudget      *QueryBudget      `json:"queryBudget,omitempty" yaml:"queryBudget,omitempty" mapstructure:"queryBudget"`
+
+	// DisableIntrospection rejects the graphql introspection queries and restricts the export of the graphql schema
+	// to admins. It is meant for production environments.
+	DisableIntrospection bool `json:"disableIntrospection,omitempty" yaml:"disableIntrospection,omitempty" mapstructure:"disableIntrospection"`
+
+	Maintenance *MaintenanceConfig `json:"maintenance,omitempty" yaml:"maintenance,omitempty" mapstructure:"maintenance"`
+
+	Sessions *SessionsConfig `json:"sessions,omitempty" yaml:"sessions,omitempty" mapstructure:"sessions"`
+
+	SLOs []*SLOConfig `json:"slos,omitempty" yaml:"slos,omitempty" mapstructure:"slos"`
+
+	Fixtures *FixturesConfig `json:"fixtures,omitempty" yaml:"fixtures,omitempty" mapstructure:"fixtures"`
+}
+
+// MaintenanceMode is the mode in which a project serves the client apis
+type MaintenanceMode string
+
+const (
+	// MaintenanceModeOff serves the requests as usual
+	MaintenanceModeOff MaintenanceMode = "off"
+
+	// MaintenanceModeReadOnly rejects the writes while the reads keep working
+	MaintenanceModeReadOnly MaintenanceMode = "read-only"
+
+	// MaintenanceModeFull answers every request of the client apis with the configured response
+	MaintenanceModeFull MaintenanceMode = "maintenance"
+)
+
+// MaintenanceConfig takes a project offline during migrations and incidents. Rejected requests are told to retry
+// after RetryAfter seconds. The config apis are never affected so that the mode can always be turned off.
+type MaintenanceConfig struct {
+	Mode       MaintenanceMode `json:"mode" yaml:"mode" mapstructure:"mode"`
+	RetryAfter int             `json:"retryAfter,omitempty" yaml:"retryAfter,omitempty" mapstructure:"retryAfter"` // in seconds, defaults to 60
+	Message    string          `json:"message,omitempty" yaml:"message,omitempty" mapstructure:"message"`
+
+	// The response served in the maintenance mode. The error envelope carrying the message is served if the body isn't set.
+	StatusCode  int    `json:"statusCode,omitempty" yaml:"statusCode,omitempty" mapstructure:"statusCode"` // defaults to 503
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty" mapstructure:"contentType"`
+	Body        string `json:"body,omitempty" yaml:"body,omitempty" mapstructure:"body"`
+}
+
+// PersistedQueries lets graphql clients send the sha256 hash of a query instead of its text
+type PersistedQueries struct {
+	// Queries maps the hex encoded sha256 hash of a query to its text
+	Queries map[string]string `json:"queries,omitempty" yaml:"queries,omitempty" mapstructure:"queries"`
+	// AllowListOnly rejects the queries which aren't persisted, which makes the persisted queries a strict allow-list
+	AllowListOnly bool `json:"allowListOnly,omitempty" yaml:"allowListOnly,omitempty" mapstructure:"allowListOnly"`
+	// AutoRegister remembers the queries sent along with their hash in memory, so that later requests only need to send
+	// the hash. It is meant for development, the queries to be used in production should be added to Queries.
+	AutoRegister bool `json:"autoRegister,omitempty" yaml:"autoRegister,omitempty" mapstructure:"autoRegister"`
+}
+
+// GraphQLLimits protects the graphql endpoint of a project from expensive queries. The limits are checked before
+// the query gets executed. A zero value disables the corresponding limit.
+type GraphQLLimits struct {
+	MaxDepth      int `json:"maxDepth,omitempty" yaml:"maxDepth,omitempty" mapstructure:"maxDepth"`                // max nesting of the selection sets
+	MaxComplexity int `json:"maxComplexity,omitempty" yaml:"maxComplexity,omitempty" mapstructure:"maxComplexity"` // max number of fields selected
+	MaxAliases    int `json:"maxAliases,omitempty" yaml:"maxAliases,omitempty" mapstructure:"maxAliases"`          // max number of aliased fields
+
+	// Operations sent as an array in a single request are executed as a batch. They are executed one after the other
+	// unless BatchParallelism is greater than one.
+	MaxBatchSize     int `json:"maxBatchSize,omitempty" yaml:"maxBatchSize,omitempty" mapstructure:"maxBatchSize"`
+	BatchParallelism int `json:"batchParallelism,omitempty" yaml:"batchParallelism,omitempty" mapstructure:"batchParallelism"`
+}
+
+// QueryBudget limits the cost of the reads made to the databases of a project over a sliding window, so that a single
+// misbehaving client can't degrade the project for everyone. The cost of a read is the number of rows it returns
+// weighed by RowCost, plus JoinCost for every join weighed by its depth. A zero budget disables the corresponding limit.
+type QueryBudget struct {
+	Window     int     `json:"window,omitempty" yaml:"window,omitempty" mapstructure:"window"`             // in seconds, defaults to 60
+	PerToken   float64 `json:"perToken,omitempty" yaml:"perToken,omitempty" mapstructure:"perToken"`       // callers are identified by the id claim of their token
+	PerProject float64 `json:"perProject,omitempty" yaml:"perProject,omitempty" mapstructure:"perProject"` // shared by all the callers of the project
+	RowCost    float64 `json:"rowCost,omitempty" yaml:"rowCost,omitempty" mapstructure:"rowCost"`          // defaults to 1
+	JoinCost   float64 `json:"joinCost,omitempty" yaml:"joinCost,omitempty" mapstructure:"joinCost"`       // defaults to 10
+
+	// OverBudget decides what happens to the reads of the callers which have exhausted their budget
+	OverBudget OverBudgetAction `json:"overBudget,omitempty" yaml:"overBudget,omitempty" mapstructure:"overBudget"`
 }
 
+// OverBudgetAction is the action taken on the reads of the callers which have exhausted their query budget
+type OverBudgetAction string
+
+const (
+	// OverBudgetReject rejects the reads with a rate limited error. It is the default action.
+	OverBudgetReject OverBudgetAction = "reject"
+
+	// OverBudgetDeprioritize executes the reads one at a time, so that they can't compete with the reads of the callers
+	// within their budget
+	OverBudgetDeprioritize OverBudgetAction = "deprioritize"
+)
+
 // DriverConfig stores the parameters for drivers of Databases.
 type DriverConfig struct {
-	MaxConn        int    `json:"maxConn,omitempty" yaml:"maxConn,omitempty" mapstructure:"maxConn"`                      // for SQL and Mongo
-	MaxIdleTimeout int    `json:"maxIdleTimeout,omitempty" yaml:"maxIdleTimeout,omitempty" mapstructure:"maxIdleTimeout"` // for SQL and Mongo
-	MinConn        uint64 `json:"minConn,omitempty" yaml:"minConn,omitempty" mapstructure:"minConn"`                      // only for Mongo
-	MaxIdleConn    int    `json:"maxIdleConn,omitempty" yaml:"maxIdleConn,omitempty" mapstructure:"maxIdleConn"`          // only for SQL
+	MaxConn            int    `json:"maxConn,omitempty" yaml:"maxConn,omitempty" mapstructure:"maxConn"`                                  // for SQL and Mongo
+	MaxIdleTimeout     int    `json:"maxIdleTimeout,omitempty" yaml:"maxIdleTimeout,omitempty" mapstructure:"maxIdleTimeout"`             // for SQL and Mongo
+	MinConn            uint64 `json:"minConn,omitempty" yaml:"minConn,omitempty" mapstructure:"minConn"`                                  // only for Mongo
+	MaxIdleConn        int    `json:"maxIdleConn,omitempty" yaml:"maxIdleConn,omitempty" mapstructure:"maxIdleConn"`                      // only for SQL
+	StatementCacheSize int    `json:"statementCacheSize,omitempty" yaml:"statementCacheSize,omitempty" mapstructure:"statementCacheSize"` // only for SQL, a negative value disables it
+	QueryComments      bool   `json:"queryComments,omitempty" yaml:"queryComments,omitempty" mapstructure:"queryComments"`                // for SQL and Mongo, tags the queries with the request id. Sampled spans always tag them with their traceparent.
 }
 
 // DatabaseConfig stores information of database config
@@ -108,9 +388,50 @@ type DatabaseConfig struct {
 	BatchTime    int          `json:"batchTime,omitempty" yaml:"batchTime" mapstructure:"batchTime"`          // time in milli seconds
 	BatchRecords int          `json:"batchRecords,omitempty" yaml:"batchRecords" mapstructure:"batchRecords"` // indicates number of records per batch
 	Limit        int64        `json:"limit,omitempty" yaml:"limit" mapstructure:"limit"`                      // indicates number of records to send per request
+	StreamLimit  int64        `json:"streamLimit,omitempty" yaml:"streamLimit" mapstructure:"streamLimit"`    // indicates max number of records to stream per request
 	DriverConf   DriverConfig `json:"driverConf,omitempty" yaml:"driverConf" mapstructure:"driverConf"`
+
+	SlowQueryThreshold int `json:"slowQueryThreshold,omitempty" yaml:"slowQueryThreshold" mapstructure:"slowQueryThreshold"` // time in milli seconds
+
+	Tenancy *TenancyConfig `json:"tenancy,omitempty" yaml:"tenancy,omitempty" mapstructure:"tenancy"`
+
+	// RowLevelSecurity pushes the security rules of the tables down to postgres as row level security policies, so
+	// that the clients accessing the database directly are subject to the same rules as the ones using the gateway.
+	// Such clients set the spacecloud.auth setting of their session to a token signed with the primary HS256 secret of the
+	// project. The secret in the database follows the secrets of the project as they get rotated or revoked.
+	RowLevelSecurity bool `json:"rowLevelSecurity,omitempty" yaml:"rowLevelSecurity,omitempty" mapstructure:"rowLevelSecurity"`
+
+	// ExactCounts allows the reads to count the matching documents exactly. Exact counts scan every matching document,
+	// hence they are estimated unless enabled.
+	ExactCounts bool `json:"exactCounts,omitempty" yaml:"exactCounts,omitempty" mapstructure:"exactCounts"`
 }
 
+// TenancyConfig isolates the data of the tenants sharing a database. The tenant of a request is derived from a jwt claim.
+type TenancyConfig struct {
+	Enabled bool        `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	Mode    TenancyMode `json:"mode" yaml:"mode" mapstructure:"mode"`
+	Claim   string      `json:"claim,omitempty" yaml:"claim,omitempty" mapstructure:"claim"` // defaults to tenant
+
+	// Used in the column mode. The column gets injected in every query and write, as well as in the on clause of every
+	// join, hence the joined tables need to have it too. Defaults to tenant_id.
+	Column string `json:"column,omitempty" yaml:"column,omitempty" mapstructure:"column"`
+
+	// Used in the database mode. Maps the tenant id to the db alias of the database holding the data of that tenant.
+	// The requests are forwarded as is, hence these databases need to have their own schema and connection config.
+	Databases map[string]string `json:"databases,omitempty" yaml:"databases,omitempty" mapstructure:"databases"`
+}
+
+// TenancyMode is the way in which the data of the tenants is isolated
+type TenancyMode string
+
+const (
+	// TenancyModeColumn stores the data of all tenants in the same tables, separated by a tenant id column
+	TenancyModeColumn TenancyMode = "column"
+
+	// TenancyModeDatabase stores the data of every tenant in a separate database or schema
+	TenancyModeDatabase TenancyMode = "database"
+)
+
 // DatabaseSchema stores information of db schemas
 type DatabaseSchema struct {
 	Table   string `json:"col,omitempty" yaml:"col" mapstructure:"col"`
@@ -125,13 +446,115 @@ type DatabaseRule struct {
 	IsRealTimeEnabled       bool             `json:"isRealtimeEnabled,omitempty" yaml:"isRealtimeEnabled" mapstructure:"isRealtimeEnabled"`
 	EnableCacheInvalidation bool             `json:"enableCacheInvalidation,omitempty" yaml:"enableCacheInvalidation" mapstructure:"enableCacheInvalidation"`
 	Rules                   map[string]*Rule `json:"rules,omitempty" yaml:"rules" mapstructure:"rules"`
+	Hooks                   *DatabaseHooks   `json:"hooks,omitempty" yaml:"hooks,omitempty" mapstructure:"hooks"`
+	Masks                   []*FieldMask     `json:"masks,omitempty" yaml:"masks,omitempty" mapstructure:"masks"`
+	ShardedCounters         *ShardedCounters `json:"shardedCounters,omitempty" yaml:"shardedCounters,omitempty" mapstructure:"shardedCounters"`
+	Retention               *RetentionPolicy `json:"retention,omitempty" yaml:"retention,omitempty" mapstructure:"retention"`
+}
+
+// The actions taken on the documents which have outlived the retention policy of their collection
+const (
+	RetentionActionDelete  = "delete"
+	RetentionActionArchive = "archive"
+)
+
+// RetentionPolicy deletes or archives the documents of a collection once they are older than the max age. The age of
+// a document is measured from its timestamp column. Policies are enforced by the leader of the cluster in batches.
+type RetentionPolicy struct {
+	Enabled bool   `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	Column  string `json:"column" yaml:"column" mapstructure:"column"`
+	// MaxAge is the number of days after which a document expires
+	MaxAge int `json:"maxAge" yaml:"maxAge" mapstructure:"maxAge"`
+	// Action is either delete or archive. Defaults to delete
+	Action string `json:"action,omitempty" yaml:"action,omitempty" mapstructure:"action"`
+	// ArchiveCol is the collection of the same database the expired documents are moved to when archiving
+	ArchiveCol string `json:"archiveCol,omitempty" yaml:"archiveCol,omitempty" mapstructure:"archiveCol"`
+	// BatchSize is the number of documents deleted or archived at a time. Defaults to 1000
+	BatchSize int `json:"batchSize,omitempty" yaml:"batchSize,omitempty" mapstructure:"batchSize"`
+	// Interval is the interval in seconds at which the policy is enforced. Defaults to an hour
+	Interval int `json:"interval,omitempty" yaml:"interval,omitempty" mapstructure:"interval"`
+}
+
+// ShardedCounters spreads the increments of the counter fields of a collection over a number of shard rows, so that
+// concurrent increments of a popular document don't contend on the same row. Every increment is made with the atomic
+// increment of the database on a shard picked at random, and the value of a counter is the sum of its shards, which
+// is computed when the documents are read. Increments are written right away, hence none of them are lost.
+//
+// The shard rows are kept in a separate collection with a doc field holding the key of the document, a shard field
+// holding the index of the shard and a field for every counter. In sql databases, the doc and shard fields must make
+// up a unique key of the table.
+type ShardedCounters struct {
+	Fields []string `json:"fields" yaml:"fields" mapstructure:"fields"`
+	// Shards is the number of shard rows of a document. Defaults to 10
+	Shards int `json:"shards,omitempty" yaml:"shards,omitempty" mapstructure:"shards"`
+	// Collection holds the shard rows. Defaults to the name of the collection followed by _counter_shards
+	Collection string `json:"collection,omitempty" yaml:"collection,omitempty" mapstructure:"collection"`
+	// Key is the field identifying the documents, which increments of sharded counters must match a single value of.
+	// Defaults to the id field of the database
+	Key string `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+}
+
+// FieldMask hides the raw value of a field from the callers reading a collection. The mask applies to the callers
+// whose role claim is one of the roles provided, or to every caller if no roles are provided.
+type FieldMask struct {
+	Field string        `json:"field" yaml:"field" mapstructure:"field"`
+	Type  FieldMaskType `json:"type" yaml:"type" mapstructure:"type"`
+	Roles []string      `json:"roles,omitempty" yaml:"roles,omitempty" mapstructure:"roles"`
+	// Visible is the number of trailing letters and digits left as is by a partial mask
+	Visible int `json:"visible,omitempty" yaml:"visible,omitempty" mapstructure:"visible"`
+}
+
+// FieldMaskType describes how the value of a masked field is hidden
+type FieldMaskType string
+
+const (
+	// FieldMaskNull replaces the value with null
+	FieldMaskNull FieldMaskType = "null"
+
+	// FieldMaskHash replaces the value with a keyed hash of it, which still allows the masked values to be grouped
+	// and compared with each other
+	FieldMaskHash FieldMaskType = "hash"
+
+	// FieldMaskPartial replaces all the letters and digits of the value except the trailing ones with '*', keeping
+	// separators as is (e.g. ***-**-1234)
+	FieldMaskPartial FieldMaskType = "partial"
+)
+
+// DatabaseHooks are the endpoints of remote services which are called synchronously around the writes made to a
+// collection. The before write hook can modify or reject the write.
+type DatabaseHooks struct {
+	BeforeWrite *DatabaseHook `json:"beforeWrite,omitempty" yaml:"beforeWrite,omitempty" mapstructure:"beforeWrite"`
+	AfterWrite  *DatabaseHook `json:"afterWrite,omitempty" yaml:"afterWrite,omitempty" mapstructure:"afterWrite"`
+}
+
+// DatabaseHook is the endpoint of a remote service called as a hook
+type DatabaseHook struct {
+	Service  string `json:"service" yaml:"service" mapstructure:"service"`
+	Endpoint string `json:"endpoint" yaml:"endpoint" mapstructure:"endpoint"`
+	Timeout  int    `json:"timeout,omitempty" yaml:"timeout,omitempty" mapstructure:"timeout"` // in seconds
 }
 
 // EventingConfig stores information of eventing config
 type EventingConfig struct {
-	Enabled       bool             `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
-	DBAlias       string           `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
-	InternalRules EventingTriggers `json:"internalRules,omitempty" yaml:"internalRules,omitempty" mapstructure:"internalRules"`
+	Enabled       bool               `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	DBAlias       string             `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	InternalRules EventingTriggers   `json:"internalRules,omitempty" yaml:"internalRules,omitempty" mapstructure:"internalRules"`
+	Retention     *EventingRetention `json:"retention,omitempty" yaml:"retention,omitempty" mapstructure:"retention"`
+	DLQAlert      *EventingDLQAlert  `json:"dlqAlert,omitempty" yaml:"dlqAlert,omitempty" mapstructure:"dlqAlert"`
+}
+
+// EventingRetention describes for how many days the processed and failed events are kept in the event log. Events
+// are kept forever if the number of days is zero.
+type EventingRetention struct {
+	ProcessedDays int `json:"processedDays" yaml:"processedDays" mapstructure:"processedDays"`
+	FailedDays    int `json:"failedDays" yaml:"failedDays" mapstructure:"failedDays"`
+}
+
+// EventingDLQAlert is the webhook which is called when the number of events in the dead letter queue, which are the
+// events that failed even after their retries, crosses the threshold
+type EventingDLQAlert struct {
+	URL       string `json:"url" yaml:"url" mapstructure:"url"`
+	Threshold int    `json:"threshold" yaml:"threshold" mapstructure:"threshold"`
 }
 
 // EventingSchema stores information of eventing schema
@@ -219,11 +642,65 @@ type SSL struct {
 	Key     string `json:"key" yaml:"key" mapstructure:"key"`
 }
 
+// Listener describes the protocols served by the http and https listeners of the gateway. It is provided through
+// flags since the listeners are started before the config of the cluster is loaded.
+type Listener struct {
+	// HTTP2 is served on the https listener unless it is disabled
+	DisableHTTP2 bool `json:"disableHttp2,omitempty" yaml:"disableHttp2,omitempty" mapstructure:"disableHttp2"`
+	// H2C serves cleartext http2 on the http listener. It is meant for the internal routes behind a load balancer
+	// terminating tls.
+	H2C bool `json:"h2c,omitempty" yaml:"h2c,omitempty" mapstructure:"h2c"`
+	// HTTP3 serves http3 over QUIC on the udp port of the https listener. It is experimental and only available in
+	// the gateways built with the http3 tag.
+	HTTP3 bool `json:"http3,omitempty" yaml:"http3,omitempty" mapstructure:"http3"`
+	// MaxConcurrentStreams is the number of concurrent streams allowed per http2 connection. It defaults to 250.
+	MaxConcurrentStreams uint32 `json:"maxConcurrentStreams,omitempty" yaml:"maxConcurrentStreams,omitempty" mapstructure:"maxConcurrentStreams"`
+
+	// UnixSocket is the path of a unix domain socket on which the http listener is served in addition to its port.
+	// It is meant for the sidecars and local proxies. The permissions of the socket default to 0660.
+	UnixSocket     string `json:"unixSocket,omitempty" yaml:"unixSocket,omitempty" mapstructure:"unixSocket"`
+	UnixSocketMode uint32 `json:"unixSocketMode,omitempty" yaml:"unixSocketMode,omitempty" mapstructure:"unixSocketMode"`
+	// SystemdActivation serves the sockets passed by systemd socket activation instead of binding the http port
+	SystemdActivation bool `json:"systemdActivation,omitempty" yaml:"systemdActivation,omitempty" mapstructure:"systemdActivation"`
+}
+
+// GitOps describes the git repository from which the config of the cluster gets synced. It is provided through
+// flags since the synced config cannot hold the location it gets synced from.
+type GitOps struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Repository is the url of the repository. Credentials, if any, can be a part of the url
+	Repository string `json:"repository" yaml:"repository" mapstructure:"repository"`
+	// Branch defaults to main
+	Branch string `json:"branch" yaml:"branch" mapstructure:"branch"`
+	// Path is the file in the repository containing either a config bundle or a config file. It defaults to config.yaml
+	Path string `json:"path" yaml:"path" mapstructure:"path"`
+	// PollInterval is the interval in seconds at which the repository is polled. It defaults to a minute
+	PollInterval int `json:"pollInterval" yaml:"pollInterval" mapstructure:"pollInterval"`
+	// Strategy is either merge or replace. It defaults to replace
+	Strategy string `json:"strategy" yaml:"strategy" mapstructure:"strategy"`
+	// WebhookSecret is used to verify the push webhooks of the git provider
+	WebhookSecret string `json:"webhookSecret" yaml:"webhookSecret" mapstructure:"webhookSecret"`
+}
+
 // Deployments store all services information for particular project
 type Deployments struct {
 	Services interface{} `json:"services" yaml:"services" mapstructure:"services"`
 }
 
+// DeploySecrets holds the secrets of the services deployed by the runner. The key here is the resource id
+type DeploySecrets map[string]*DeploySecret
+
+// DeploySecret is a secret which gets injected in the containers of the services referring to it by its id. The
+// values of its data are encrypted with the aes key of the project.
+type DeploySecret struct {
+	ID string `json:"id" yaml:"id" mapstructure:"id"`
+	// Type is either file, env or docker
+	Type string `json:"type" yaml:"type" mapstructure:"type"`
+	// RootPath is the directory the keys of file secrets are mounted in
+	RootPath string            `json:"rootPath,omitempty" yaml:"rootPath,omitempty" mapstructure:"rootPath"`
+	Data     map[string]string `json:"data" yaml:"data" mapstructure:"data"`
+}
+
 // Crud holds the mapping of database level configuration
 type Crud map[string]*CrudStub // The key here is the alias for database type
 
@@ -248,6 +725,14 @@ type DatbasePreparedQuery struct {
 	Rule      *Rule    `json:"rule" yaml:"rule" mapstructure:"rule"`
 	DbAlias   string   `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
 	Arguments []string `json:"args" yaml:"args" mapstructure:"args"`
+	// IsView exposes the query as a read-only virtual collection named after its id. Views are read through the
+	// regular crud and graphql endpoints, which filter, sort and paginate the rows returned by the query. The
+	// arguments of a sql view are bound to the ? placeholders in its query.
+	IsView bool `json:"isView,omitempty" yaml:"isView,omitempty" mapstructure:"isView"`
+	// Pipeline is the aggregation pipeline of a view in mongo which runs on the collection Col. String values in it
+	// which match one of the arguments are replaced by the value of that argument.
+	Col      string        `json:"col,omitempty" yaml:"col,omitempty" mapstructure:"col"`
+	Pipeline []interface{} `json:"pipeline,omitempty" yaml:"pipeline,omitempty" mapstructure:"pipeline"`
 }
 
 // TableRule contains the config at the collection level
@@ -292,6 +777,96 @@ type AuthStub struct {
 	ID      string `json:"id" yaml:"id" mapstructure:"id"`
 	Enabled bool   `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
 	Secret  string `json:"secret" yaml:"secret" mapstructure:"secret"`
+	// Email configures the verification, password reset, password policy and lockout of the email sign in method
+	Email *EmailAuthConfig `json:"email,omitempty" yaml:"email,omitempty" mapstructure:"email"`
+	// SCIM configures the scim provisioning endpoint. It is used by the auth method having the scim id
+	SCIM *SCIMConfig `json:"scim,omitempty" yaml:"scim,omitempty" mapstructure:"scim"`
+}
+
+// SCIMConfig describes the SCIM 2.0 endpoint identity providers like Okta and Azure AD provision the users and groups
+// of the project through. The users are kept in the users collection, which must have the active, external_id and
+// groups fields. The groups are kept in the groups collection with the name, external_id and members fields. The
+// names of the groups of a user are issued as the groups claim of its tokens.
+type SCIMConfig struct {
+	// DBAlias is the database having the users and groups collections
+	DBAlias string `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	// Token is the bearer token the identity provider authenticates with. It can be a reference to an external
+	// secret manager
+	Token string `json:"token" yaml:"token" mapstructure:"token"`
+	// GroupsCollection is the collection having the groups. Defaults to groups
+	GroupsCollection string `json:"groupsCollection,omitempty" yaml:"groupsCollection,omitempty" mapstructure:"groupsCollection"`
+	// DefaultRole is the role of the users provisioned. Defaults to user
+	DefaultRole string `json:"defaultRole,omitempty" yaml:"defaultRole,omitempty" mapstructure:"defaultRole"`
+}
+
+// EmailAuthConfig describes the flows of the email sign in method. The state of the flows is kept in the verified,
+// failed_attempts and locked_until fields of the users collection, hence it must have them if the flows are used.
+type EmailAuthConfig struct {
+	Mail *MailConfig `json:"mail,omitempty" yaml:"mail,omitempty" mapstructure:"mail"`
+
+	// VerificationURL is the link mailed to the users to verify their email. The token is added to it as the token
+	// query parameter. Verification mails are only sent if it is set.
+	VerificationURL string `json:"verificationURL,omitempty" yaml:"verificationURL,omitempty" mapstructure:"verificationURL"`
+	// RequireVerification denies the sign in of the users who haven't verified their email
+	RequireVerification bool `json:"requireVerification,omitempty" yaml:"requireVerification,omitempty" mapstructure:"requireVerification"`
+	// VerificationTTL is the time in minutes a verification link is valid for. Defaults to a day
+	VerificationTTL int `json:"verificationTTL,omitempty" yaml:"verificationTTL,omitempty" mapstructure:"verificationTTL"`
+
+	// ResetURL is the link mailed to the users to reset their password. The token is added to it as the token query
+	// parameter. Password resets are only allowed if it is set.
+	ResetURL string `json:"resetURL,omitempty" yaml:"resetURL,omitempty" mapstructure:"resetURL"`
+	// ResetTTL is the time in minutes a password reset link is valid for. Defaults to an hour
+	ResetTTL int `json:"resetTTL,omitempty" yaml:"resetTTL,omitempty" mapstructure:"resetTTL"`
+
+	PasswordPolicy *PasswordPolicy `json:"passwordPolicy,omitempty" yaml:"passwordPolicy,omitempty" mapstructure:"passwordPolicy"`
+	Lockout        *AccountLockout `json:"lockout,omitempty" yaml:"lockout,omitempty" mapstructure:"lockout"`
+	TwoFactor      *TwoFactorAuth  `json:"twoFactor,omitempty" yaml:"twoFactor,omitempty" mapstructure:"twoFactor"`
+}
+
+// TwoFactorAuth lets the users enroll in two factor authentication with time based one time passwords. The users
+// who have enrolled are asked for a code after their password. The tokens issued carry the mfa claim telling if
+// the second factor was provided, so that security rules can require it.
+type TwoFactorAuth struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Issuer is the name of the app shown by the authenticator apps. Defaults to the id of the project
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty" mapstructure:"issuer"`
+	// BackupCodes is the number of single use backup codes given on enrollment. Defaults to 10
+	BackupCodes int `json:"backupCodes,omitempty" yaml:"backupCodes,omitempty" mapstructure:"backupCodes"`
+}
+
+// MailConfig describes how mails are sent. The provider is either smtp or sendgrid.
+type MailConfig struct {
+	Provider string `json:"provider" yaml:"provider" mapstructure:"provider"`
+	From     string `json:"from" yaml:"from" mapstructure:"from"`
+	// Host and Port are the address of the smtp server
+	Host     string `json:"host,omitempty" yaml:"host,omitempty" mapstructure:"host"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty" mapstructure:"port"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty" mapstructure:"username"`
+	// Password of the smtp server and APIKey of sendgrid can be references to an external secret manager
+	Password string `json:"password,omitempty" yaml:"password,omitempty" mapstructure:"password"`
+	APIKey   string `json:"apiKey,omitempty" yaml:"apiKey,omitempty" mapstructure:"apiKey"`
+}
+
+// The providers mails can be sent with
+const (
+	MailProviderSMTP     = "smtp"
+	MailProviderSendGrid = "sendgrid"
+)
+
+// PasswordPolicy describes the passwords the users may set
+type PasswordPolicy struct {
+	MinLength     int  `json:"minLength,omitempty" yaml:"minLength,omitempty" mapstructure:"minLength"`
+	RequireUpper  bool `json:"requireUpper,omitempty" yaml:"requireUpper,omitempty" mapstructure:"requireUpper"`
+	RequireLower  bool `json:"requireLower,omitempty" yaml:"requireLower,omitempty" mapstructure:"requireLower"`
+	RequireDigit  bool `json:"requireDigit,omitempty" yaml:"requireDigit,omitempty" mapstructure:"requireDigit"`
+	RequireSymbol bool `json:"requireSymbol,omitempty" yaml:"requireSymbol,omitempty" mapstructure:"requireSymbol"`
+}
+
+// AccountLockout locks the account of a user out of signing in after consecutive failed attempts
+type AccountLockout struct {
+	MaxAttempts int `json:"maxAttempts" yaml:"maxAttempts" mapstructure:"maxAttempts"`
+	// Duration is the time in minutes the account stays locked. Defaults to 15 minutes
+	Duration int `json:"duration,omitempty" yaml:"duration,omitempty" mapstructure:"duration"`
 }
 
 // ServicesModule holds the config for the service module
@@ -306,7 +881,7 @@ type Services map[string]*Service
 // Service holds the config of service
 type Service struct {
 	ID        string               `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`    // eg. http://localhost:8080
-	URL       string               `json:"url,omitempty" yaml:"url,omitempty" mapstructure:"url"` // eg. http://localhost:8080
+	URL       string               `json:"url,omitempty" yaml:"url,omitempty" mapstructure:"url"` // eg. http://localhost:8080, consul://orders or srv://_orders._tcp.example.com
 	Endpoints map[string]*Endpoint `json:"endpoints,omitempty" yaml:"endpoints,omitempty" mapstructure:"endpoints"`
 }
 
@@ -359,6 +934,9 @@ type TemplatingEngine string
 const (
 	// TemplatingEngineGo describes the go templating engine
 	TemplatingEngineGo TemplatingEngine = "go"
+
+	// TemplatingEngineJQ describes the jq templating engine
+	TemplatingEngineJQ TemplatingEngine = "jq"
 )
 
 // Header describes the operation to be performed on the header
@@ -429,6 +1007,8 @@ type Eventing struct {
 	InternalRules map[string]*EventingTrigger `json:"internalTriggers,omitempty" yaml:"internalTriggers,omitempty" mapstructure:"internalTriggers"`
 	SecurityRules map[string]*Rule            `json:"securityRules,omitempty" yaml:"securityRules,omitempty" mapstructure:"securityRules"`
 	Schemas       map[string]SchemaObject     `json:"schemas,omitempty" yaml:"schemas,omitempty" mapstructure:"schemas"`
+	Retention     *EventingRetention          `json:"retention,omitempty" yaml:"retention,omitempty" mapstructure:"retention"`
+	DLQAlert      *EventingDLQAlert           `json:"dlqAlert,omitempty" yaml:"dlqAlert,omitempty" mapstructure:"dlqAlert"`
 }
 
 // EventingTrigger stores information of eventing trigger
@@ -445,6 +1025,120 @@ type EventingTrigger struct {
 	Claims          string            `json:"claims" yaml:"claims" mapstructure:"claims"`
 	Filter          *Rule             `json:"filter" yaml:"filter" mapstructure:"filter"`
 	TriggerType     string            `json:"triggerType" yaml:"triggerType" mapstructure:"triggerType"`
+
+	// FilterExpression is evaluated over the payload of the event, like `doc.amount > 100`, before the event is
+	// queued. Events for which it doesn't evaluate to true don't fire the trigger.
+	FilterExpression string `json:"filterExpression,omitempty" yaml:"filterExpression,omitempty" mapstructure:"filterExpression"`
+
+	// Target is where the events are delivered. Events are posted to the url by default. Google cloud functions are
+	// called on their url with an identity token of the service account of the gateway, while aws lambda functions
+	// are invoked using the aws sdk.
+	Target string        `json:"target,omitempty" yaml:"target,omitempty" mapstructure:"target"`
+	Lambda *LambdaTarget `json:"lambda,omitempty" yaml:"lambda,omitempty" mapstructure:"lambda"`
+
+	// OrderBy is the path of a field in the payload of the event, like `doc.id`. Events of the trigger having the same
+	// value for this field are delivered one after the other in the order they were queued.
+	OrderBy string `json:"orderBy,omitempty" yaml:"orderBy,omitempty" mapstructure:"orderBy"`
+
+	// Batch delivers the events of the trigger in batches instead of one event per call
+	Batch *EventingBatch `json:"batch,omitempty" yaml:"batch,omitempty" mapstructure:"batch"`
+}
+
+// EventingBatch describes how events are batched for a trigger. A batch is delivered as an array of events once it
+// has MaxEvents events or its oldest event has waited for Window seconds, whichever happens first.
+type EventingBatch struct {
+	MaxEvents int `json:"maxEvents" yaml:"maxEvents" mapstructure:"maxEvents"`
+	Window    int `json:"window" yaml:"window" mapstructure:"window"` // Window is in seconds
+}
+
+const (
+	// EventingTargetWebhook delivers events to the url of the trigger
+	EventingTargetWebhook = "webhook"
+	// EventingTargetGCPFunction delivers events to a google cloud function requiring authentication
+	EventingTargetGCPFunction = "gcp-function"
+	// EventingTargetAWSLambda delivers events to an aws lambda function
+	EventingTargetAWSLambda = "aws-lambda"
+	// EventingTargetWorkflow starts a run of a workflow. It is only used by the internal triggers of workflows.
+	EventingTargetWorkflow = "workflow"
+)
+
+// LambdaTarget is the aws lambda function events are delivered to. The credentials are picked up from the default
+// credential chain of the aws sdk.
+type LambdaTarget struct {
+	Function  string `json:"function" yaml:"function" mapstructure:"function"` // Name or arn of the function
+	Region    string `json:"region,omitempty" yaml:"region,omitempty" mapstructure:"region"`
+	Qualifier string `json:"qualifier,omitempty" yaml:"qualifier,omitempty" mapstructure:"qualifier"` // Version or alias of the function
+}
+
+// EventingSource is a custom event source which ingests the webhooks of third party services (like Stripe or Github)
+// into the eventing pipeline
+type EventingSource struct {
+	ID string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+
+	// Schema is the graphql schema of the payload. The payloads which don't match it are rejected.
+	Schema string `json:"schema,omitempty" yaml:"schema,omitempty" mapstructure:"schema"`
+
+	// Rule authorises the requests. The payload is available as args.params and the headers as args.headers.
+	Rule *Rule `json:"rule" yaml:"rule" mapstructure:"rule"`
+
+	// Signature verifies the hmac signature sent by the third party service
+	Signature *EventingSourceSignature `json:"signature,omitempty" yaml:"signature,omitempty" mapstructure:"signature"`
+
+	// The type of the event sent by the third party service is read from the header or the field of the payload
+	// provided. Types maps it to the type of the internal event which is queued. DefaultType is used for the types
+	// which aren't mapped. The events of unmapped types are rejected if it is empty.
+	TypeHeader  string            `json:"typeHeader,omitempty" yaml:"typeHeader,omitempty" mapstructure:"typeHeader"`
+	TypeField   string            `json:"typeField,omitempty" yaml:"typeField,omitempty" mapstructure:"typeField"`
+	Types       map[string]string `json:"types,omitempty" yaml:"types,omitempty" mapstructure:"types"`
+	DefaultType string            `json:"defaultType,omitempty" yaml:"defaultType,omitempty" mapstructure:"defaultType"`
+}
+
+// EventingSourceSignature describes the hmac sha256 signature of the request body. The signature is hex encoded and
+// may have a prefix like `sha256=`.
+type EventingSourceSignature struct {
+	Header string `json:"header" yaml:"header" mapstructure:"header"`
+	Secret string `json:"secret" yaml:"secret" mapstructure:"secret"`
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty" mapstructure:"prefix"`
+}
+
+// EventingWorkflow is a saga made up of steps which are executed one after the other whenever an event of the trigger
+// type is queued. If a step fails, the compensating actions of the steps completed till then are executed in the
+// reverse order.
+type EventingWorkflow struct {
+	ID      string            `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Trigger string            `json:"trigger" yaml:"trigger" mapstructure:"trigger"` // Type of the event which starts the workflow
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty" mapstructure:"options"`
+	Steps   []*WorkflowStep   `json:"steps" yaml:"steps" mapstructure:"steps"`
+	Timeout int               `json:"timeout,omitempty" yaml:"timeout,omitempty" mapstructure:"timeout"` // Timeout of a run in seconds
+}
+
+// WorkflowStep is a step of a workflow. The result of the action of a step is available to the steps after it.
+type WorkflowStep struct {
+	ID         string          `json:"id" yaml:"id" mapstructure:"id"`
+	Action     *WorkflowAction `json:"action" yaml:"action" mapstructure:"action"`
+	Compensate *WorkflowAction `json:"compensate,omitempty" yaml:"compensate,omitempty" mapstructure:"compensate"`
+	Retries    int             `json:"retries,omitempty" yaml:"retries,omitempty" mapstructure:"retries"`
+	Timeout    int             `json:"timeout,omitempty" yaml:"timeout,omitempty" mapstructure:"timeout"` // Timeout is in seconds
+}
+
+// WorkflowAction either calls an endpoint of a remote service or performs a database operation. The remote service
+// receives the state of the run, which has the payload of the event (`event`) and the results of the completed
+// steps (`steps.<step id>`), as its params. String values of the database operation starting with `args.` are
+// replaced by the value at that path of the state, like `args.event.doc.id`.
+type WorkflowAction struct {
+	Service  string               `json:"service,omitempty" yaml:"service,omitempty" mapstructure:"service"`
+	Endpoint string               `json:"endpoint,omitempty" yaml:"endpoint,omitempty" mapstructure:"endpoint"`
+	DB       *WorkflowDBOperation `json:"db,omitempty" yaml:"db,omitempty" mapstructure:"db"`
+}
+
+// WorkflowDBOperation is a create, update or delete operation on a collection
+type WorkflowDBOperation struct {
+	DBAlias string                 `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	Col     string                 `json:"col" yaml:"col" mapstructure:"col"`
+	Op      string                 `json:"op" yaml:"op" mapstructure:"op"`
+	Doc     map[string]interface{} `json:"doc,omitempty" yaml:"doc,omitempty" mapstructure:"doc"`
+	Find    map[string]interface{} `json:"find,omitempty" yaml:"find,omitempty" mapstructure:"find"`
+	Update  map[string]interface{} `json:"update,omitempty" yaml:"update,omitempty" mapstructure:"update"`
 }
 
 // SchemaObject is the body of the request for adding schema
@@ -459,6 +1153,31 @@ type LetsEncrypt struct {
 	WhitelistedDomains []string `json:"domains" yaml:"domains" mapstructure:"domains"`
 }
 
+// SearchConfig describes the configuration of the full text search engine the collections of a project are synced to
+type SearchConfig struct {
+	ID      string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Enabled bool   `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Engine is either elasticsearch or meilisearch
+	Engine string `json:"engine" yaml:"engine" mapstructure:"engine"`
+	URL    string `json:"url" yaml:"url" mapstructure:"url"`
+	// APIKey is used by meilisearch and for api key authentication in elasticsearch. Username and Password are used
+	// for basic authentication in elasticsearch. All three can be references to an external secret manager
+	APIKey   string `json:"apiKey,omitempty" yaml:"apiKey,omitempty" mapstructure:"apiKey"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty" mapstructure:"username"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty" mapstructure:"password"`
+	// IndexPrefix is prepended to the name of every index. Indexes are named <prefix><project>-<dbAlias>-<col>
+	IndexPrefix string              `json:"indexPrefix,omitempty" yaml:"indexPrefix,omitempty" mapstructure:"indexPrefix"`
+	Collections []*SearchCollection `json:"collections" yaml:"collections" mapstructure:"collections"`
+}
+
+// SearchCollection describes a collection which is indexed in the search engine
+type SearchCollection struct {
+	DbAlias string `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	Col     string `json:"col" yaml:"col" mapstructure:"col"`
+	// Fields are the fields which get indexed and searched. All fields are indexed if none are provided
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty" mapstructure:"fields"`
+}
+
 // ReadCacheOptions describes the cache options in requests
 type ReadCacheOptions struct {
 	TTL               int64 `json:"ttl" yaml:"ttl" mapstructure:"ttl"` // here ttl is represented in seconds
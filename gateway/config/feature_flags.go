@@ -0,0 +1,28 @@
+package config
+
+// FeatureFlags is a map which stores the feature flags of a project
+type FeatureFlags map[string]*FeatureFlag // Key here is resource id --> clusterId--projectId--resourceType--flagId
+
+// FeatureFlag is a flag which turns a feature on for a subset of the callers of a project. A flag is evaluated
+// against the claims of the token of the caller. It is on for the callers matching any of its targets, and for the
+// rollout percentage of the remaining callers.
+type FeatureFlag struct {
+	ID          string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty" mapstructure:"description"`
+	// Enabled is the kill switch of the flag. A disabled flag is off for everyone
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Rollout is the percentage of the callers the flag is on for. Callers are bucketed by the id claim of their
+	// token, so a caller keeps getting the same result. It defaults to 100 for flags without targets and 0 otherwise.
+	Rollout *float64 `json:"rollout,omitempty" yaml:"rollout,omitempty" mapstructure:"rollout"`
+	// Targets turn the flag on for the callers whose claims match, irrespective of the rollout
+	Targets []*FeatureFlagTarget `json:"targets,omitempty" yaml:"targets,omitempty" mapstructure:"targets"`
+}
+
+// FeatureFlagTarget matches the callers having a claim with the provided value
+type FeatureFlagTarget struct {
+	// Claim is the path of the claim in the token. Nested claims are separated by dots
+	Claim string `json:"claim" yaml:"claim" mapstructure:"claim"`
+	// Op is one of ==, !=, in, notIn or exists. It defaults to ==
+	Op    string      `json:"op,omitempty" yaml:"op,omitempty" mapstructure:"op"`
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty" mapstructure:"value"`
+}
@@ -0,0 +1,33 @@
+package config
+
+// FixturesConfig is the seed data of a project. Fixtures are only loaded by the gateways running in test mode, so
+// that the end to end tests against the gateway start from a known state.
+type FixturesConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// LoadOnStart loads the fixtures once the gateway starts. Otherwise they are loaded through the fixtures api
+	LoadOnStart bool                 `json:"loadOnStart,omitempty" yaml:"loadOnStart,omitempty" mapstructure:"loadOnStart"`
+	Collections []*CollectionFixture `json:"collections,omitempty" yaml:"collections,omitempty" mapstructure:"collections"`
+	Files       []*FileFixture       `json:"files,omitempty" yaml:"files,omitempty" mapstructure:"files"`
+}
+
+// CollectionFixture are the documents loaded into a collection
+type CollectionFixture struct {
+	DbAlias string `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	Col     string `json:"col" yaml:"col" mapstructure:"col"`
+	// Key is the field identifying the documents which get deleted on teardown. It defaults to _id for mongo and the
+	// embedded database and to id for the sql databases
+	Key string `json:"key,omitempty" yaml:"key,omitempty" mapstructure:"key"`
+	// Truncate deletes all the documents of the collection before the fixtures get loaded and on teardown
+	Truncate bool                     `json:"truncate,omitempty" yaml:"truncate,omitempty" mapstructure:"truncate"`
+	Docs     []map[string]interface{} `json:"docs" yaml:"docs" mapstructure:"docs"`
+}
+
+// FileFixture is a file uploaded to the file storage of the project
+type FileFixture struct {
+	// Path is the directory of the file. It gets created if it doesn't exist
+	Path    string `json:"path" yaml:"path" mapstructure:"path"`
+	Name    string `json:"name" yaml:"name" mapstructure:"name"`
+	Content string `json:"content" yaml:"content" mapstructure:"content"`
+	// Base64 is set when the content is base64 encoded
+	Base64 bool `json:"base64,omitempty" yaml:"base64,omitempty" mapstructure:"base64"`
+}
@@ -0,0 +1,46 @@
+package config
+
+// KVConfig describes the key value store of a project. It is meant for small app state like feature flags and
+// metadata which doesn't warrant a collection. Keys are grouped in namespaces, each having its own security rules.
+type KVConfig struct {
+	ID      string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Enabled bool   `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Store is either cluster, which keeps the keys in the redis replicated across the gateways of the cluster, or
+	// redis, which keeps them in the redis at Conn. It defaults to cluster.
+	Store string `json:"store,omitempty" yaml:"store,omitempty" mapstructure:"store"`
+	// Conn is the address of the redis store. It can be a reference to an external secret manager
+	Conn string `json:"conn,omitempty" yaml:"conn,omitempty" mapstructure:"conn"`
+	// MaxValueSize is the max size in bytes of the json encoded value of a key. It defaults to 64 kb
+	MaxValueSize int `json:"maxValueSize,omitempty" yaml:"maxValueSize,omitempty" mapstructure:"maxValueSize"`
+	// Namespaces maps the name of a namespace to its config. The default namespace applies to the namespaces which
+	// aren't configured.
+	Namespaces map[string]*KVNamespace `json:"namespaces" yaml:"namespaces" mapstructure:"namespaces"`
+}
+
+// KVNamespace describes a namespace of the key value store
+type KVNamespace struct {
+	// Rules maps an operation (read, write, delete or list) to its security rule
+	Rules map[KVOp]*Rule `json:"rules" yaml:"rules" mapstructure:"rules"`
+	// DefaultTTL is the time in seconds after which the keys set without a ttl expire. They never expire by default
+	DefaultTTL int `json:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty" mapstructure:"defaultTTL"`
+}
+
+// KVOp is an operation on the key value store
+type KVOp string
+
+const (
+	// KVRead reads a key
+	KVRead KVOp = "read"
+	// KVWrite sets a key
+	KVWrite KVOp = "write"
+	// KVDelete deletes a key
+	KVDelete KVOp = "delete"
+	// KVList lists the keys starting with a prefix
+	KVList KVOp = "list"
+)
+
+// The stores of the key value store
+const (
+	KVStoreCluster = "cluster"
+	KVStoreRedis   = "redis"
+)
@@ -0,0 +1,36 @@
+package config
+
+// PrivacyConfig describes where the personal data of the users of a project lives, so that it can be exported or
+// erased on the request of a user
+type PrivacyConfig struct {
+	ID          string               `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id"`
+	Enabled     bool                 `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	Collections []*PrivacyCollection `json:"collections" yaml:"collections" mapstructure:"collections"`
+	// Audit is the collection the reports of the requests are written to. The reports are only logged if it isn't set
+	Audit *PrivacyAudit `json:"audit,omitempty" yaml:"audit,omitempty" mapstructure:"audit"`
+}
+
+// PrivacyCollection describes a collection holding the personal data of users
+type PrivacyCollection struct {
+	DbAlias string `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	Col     string `json:"col" yaml:"col" mapstructure:"col"`
+	// Field is the field of the documents holding the id of the user they belong to
+	Field string `json:"field" yaml:"field" mapstructure:"field"`
+	// Erasure is either delete, which deletes the documents of the user, or anonymize, which replaces their personal
+	// fields. Defaults to delete
+	Erasure string `json:"erasure,omitempty" yaml:"erasure,omitempty" mapstructure:"erasure"`
+	// Anonymize maps the personal fields to the values they are replaced with when anonymizing
+	Anonymize map[string]interface{} `json:"anonymize,omitempty" yaml:"anonymize,omitempty" mapstructure:"anonymize"`
+}
+
+// PrivacyAudit describes the collection the reports of the privacy requests are written to
+type PrivacyAudit struct {
+	DbAlias string `json:"dbAlias" yaml:"dbAlias" mapstructure:"dbAlias"`
+	Col     string `json:"col" yaml:"col" mapstructure:"col"`
+}
+
+// The ways the documents of a user are erased
+const (
+	PrivacyErasureDelete    = "delete"
+	PrivacyErasureAnonymize = "anonymize"
+)
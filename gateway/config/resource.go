@@ -18,15 +18,58 @@ var ResourceFetchingOrder = []Resource{
 	ResourceEventingTrigger,
 	ResourceEventingRule,
 	ResourceEventingSchema,
+	ResourceEventingSource,
+	ResourceEventingWorkflow,
 	ResourceRemoteService,
+	ResourceDeploySecret,
 	ResourceIngressGlobal,
 	ResourceIngressRoute,
 	ResourceAuthProvider,
 	ResourceProjectLetsEncrypt,
+	ResourceSearchConfig,
+	ResourceBackupConfig,
+	ResourceKVConfig,
+	ResourcePrivacyConfig,
+	ResourceFeatureFlag,
 	ResourceCluster,
 	ResourceIntegration,
 	ResourceIntegrationHook,
 	ResourceCacheConfig,
+	ResourceProjectTemplate,
+	ResourceAdminToken,
+}
+
+// ResourceMinProtocolVersion is the protocol version which introduced a resource type. Resource types which
+// aren't listed here are understood by every version of the protocol.
+var ResourceMinProtocolVersion = map[Resource]int{
+	ResourceSearchConfig:     2,
+	ResourceEventingSource:   2,
+	ResourceEventingWorkflow: 2,
+	ResourceDeploySecret:     2,
+	ResourceProjectTemplate:  2,
+	ResourceAdminToken:       2,
+	ResourceBackupConfig:     2,
+	ResourceKVConfig:         2,
+	ResourcePrivacyConfig:    2,
+	ResourceFeatureFlag:      2,
+}
+
+// GetResourceMinProtocolVersion returns the minimum protocol version a node must speak to understand the resource type
+func GetResourceMinProtocolVersion(resourceType Resource) int {
+	if v, p := ResourceMinProtocolVersion[resourceType]; p {
+		return v
+	}
+	return 1
+}
+
+// IsKnownResource checks if the resource type is understood by this version of space cloud
+func IsKnownResource(resourceType Resource) bool {
+	for _, r := range ResourceFetchingOrder {
+		if r == resourceType {
+			return true
+		}
+	}
+	return false
 }
 
 // Resource is a resource type
@@ -56,6 +99,10 @@ const (
 	ResourceEventingTrigger Resource = "eventing-trigger"
 	// ResourceEventingRule is a resource
 	ResourceEventingRule Resource = "eventing-rule"
+	// ResourceEventingSource is a resource
+	ResourceEventingSource Resource = "eventing-source"
+	// ResourceEventingWorkflow is a resource
+	ResourceEventingWorkflow Resource = "eventing-workflow"
 
 	// ResourceFileStoreConfig is a resource
 	ResourceFileStoreConfig Resource = "filestore-config"
@@ -65,6 +112,17 @@ const (
 	// ResourceProjectLetsEncrypt is a resource
 	ResourceProjectLetsEncrypt Resource = "letsencrypt"
 
+	// ResourceSearchConfig is a resource
+	ResourceSearchConfig Resource = "search-config"
+	// ResourceBackupConfig is a resource
+	ResourceBackupConfig Resource = "backup-config"
+	// ResourceKVConfig is a resource
+	ResourceKVConfig Resource = "kv-config"
+	// ResourcePrivacyConfig is a resource
+	ResourcePrivacyConfig Resource = "privacy-config"
+	// ResourceFeatureFlag is a resource
+	ResourceFeatureFlag Resource = "feature-flag"
+
 	// ResourceIngressRoute is a resource
 	ResourceIngressRoute Resource = "ingress-route"
 	// ResourceIngressGlobal is a resource
@@ -73,6 +131,9 @@ const (
 	// ResourceRemoteService is a resource
 	ResourceRemoteService Resource = "remote-service"
 
+	// ResourceDeploySecret is a resource
+	ResourceDeploySecret Resource = "deploy-secret"
+
 	// ResourceIntegration is a resource
 	ResourceIntegration Resource = "integration"
 	// ResourceIntegrationHook is a resource
@@ -83,6 +144,12 @@ const (
 	// ResourceCacheConfig is a resource
 	ResourceCacheConfig Resource = "cache-config"
 
+	// ResourceProjectTemplate is a resource
+	ResourceProjectTemplate Resource = "project-template"
+
+	// ResourceAdminToken is a resource
+	ResourceAdminToken Resource = "admin-token"
+
 	// ResourceDeployService is a resource
 	// ResourceDeployService Resource = "service"
 	// ResourceDeployServiceRoute is a resource
@@ -83,6 +83,51 @@ func (r *Route) SelectTarget(ctx context.Context, weight int32) (RouteTarget, er
 	return RouteTarget{}, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No target found for route (%s) - make sure you have defined atleast one target with proper weights", r.Source.URL), nil, nil)
 }
 
+// SelectZoneTarget returns a target based on the weights assigned preferring the healthy targets in the provided zone.
+// The healthy targets in the other zones are used when none of the targets in the zone are healthy, and all the
+// targets are used when none of them are healthy. The weights of the preferred targets are scaled up so that their
+// share of the traffic stays in proportion.
+func (r *Route) SelectZoneTarget(ctx context.Context, zone string, weight int32, isHealthy func(target RouteTarget) bool) (RouteTarget, error) {
+	targets := r.filterTargets(func(target RouteTarget) bool { return zone != "" && target.Zone == zone && isHealthy(target) })
+	if len(targets) == 0 {
+		targets = r.filterTargets(isHealthy)
+	}
+	if len(targets) == 0 || len(targets) == len(r.Targets) {
+		return r.SelectTarget(ctx, weight)
+	}
+
+	// Generate a random float in the range 0 to 100 if provided weight in lesser than zero
+	if weight < 0 {
+		weight = rand.Int31n(100)
+	}
+
+	var totalWeight int32
+	for _, target := range targets {
+		totalWeight += target.Weight
+	}
+	weight = weight * totalWeight / 100
+
+	var cumulativeWeight int32
+	for _, target := range targets {
+		cumulativeWeight += target.Weight
+		if weight <= cumulativeWeight {
+			return target, nil
+		}
+	}
+	return targets[len(targets)-1], nil
+}
+
+// filterTargets returns the targets which can receive traffic and satisfy the filter
+func (r *Route) filterTargets(filter func(target RouteTarget) bool) []RouteTarget {
+	targets := make([]RouteTarget, 0)
+	for _, target := range r.Targets {
+		if target.Weight > 0 && filter(target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
 // RouteSource is the source of routing
 type RouteSource struct {
 	Hosts      []string     `json:"hosts" yaml:"hosts" mapstructure:"hosts"`
@@ -101,6 +146,8 @@ type RouteTarget struct {
 	Weight  int32           `json:"weight" yaml:"weight" mapstructure:"weight"`
 	Version string          `json:"version" yaml:"version" mapstructure:"version"`
 	Type    RouteTargetType `json:"type" yaml:"type" mapstructure:"type"`
+	// Zone is the zone the target runs in. The targets in the zone of the gateway are preferred.
+	Zone string `json:"zone,omitempty" yaml:"zone,omitempty" mapstructure:"zone"`
 }
 
 // RouteURLType describes how the url should be evaluated / matched
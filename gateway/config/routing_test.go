@@ -75,3 +75,46 @@ func TestRoute_SelectTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestRoute_SelectZoneTarget(t *testing.T) {
+	targets := []RouteTarget{
+		{Host: "a1", Zone: "zone-a", Weight: 25},
+		{Host: "a2", Zone: "zone-a", Weight: 25},
+		{Host: "b1", Zone: "zone-b", Weight: 50},
+	}
+	tests := []struct {
+		name   string
+		zone   string
+		weight int32
+		failed []string
+		want   string
+	}{
+		{name: "no zone", weight: 60, want: "b1"},
+		{name: "zone without targets", zone: "zone-c", weight: 60, want: "b1"},
+		{name: "same zone target is preferred", zone: "zone-a", weight: 100, want: "a2"},
+		{name: "same zone weights are scaled", zone: "zone-a", weight: 40, want: "a1"},
+		{name: "only same zone target", zone: "zone-b", weight: 10, want: "b1"},
+		{name: "failed same zone target is skipped", zone: "zone-a", weight: 10, failed: []string{"a1"}, want: "a2"},
+		{name: "fails over to another zone", zone: "zone-b", weight: 10, failed: []string{"b1"}, want: "a1"},
+		{name: "all targets failed", zone: "zone-b", weight: 60, failed: []string{"a1", "a2", "b1"}, want: "b1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Route{Targets: targets}
+			got, err := r.SelectZoneTarget(context.Background(), tt.zone, tt.weight, func(target RouteTarget) bool {
+				for _, host := range tt.failed {
+					if target.Host == host {
+						return false
+					}
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatalf("SelectZoneTarget() error = %v", err)
+			}
+			if got.Host != tt.want {
+				t.Errorf("SelectZoneTarget() got = %v, want %v", got.Host, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package config
+
+// SessionsConfig enables the tracking of the sessions of the tokens issued by the user management module, so that
+// they can be listed and revoked before they expire. The sessions are kept in the redis database of the cluster.
+type SessionsConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// MaxSessions is the maximum number of active sessions of a user. The oldest sessions are revoked when a user
+	// signs in once more. Zero means no limit
+	MaxSessions int `json:"maxSessions,omitempty" yaml:"maxSessions,omitempty" mapstructure:"maxSessions"`
+}
@@ -0,0 +1,30 @@
+package config
+
+// SLOConfig is a service level objective of the client apis of a project. The requests it covers are selected by
+// the module and the route. A request is bad if it results in a server error or takes longer than the latency
+// threshold.
+type SLOConfig struct {
+	ID string `json:"id" yaml:"id" mapstructure:"id"`
+	// Module is one of the api modules like crud, graphql or functions. An empty module selects every module
+	Module string `json:"module,omitempty" yaml:"module,omitempty" mapstructure:"module"`
+	// Route is a prefix of the path of the requests following /v1/api/{project}. An empty route selects every route
+	Route string `json:"route,omitempty" yaml:"route,omitempty" mapstructure:"route"`
+	// Objective is the fraction of the requests which need to be good, eg 0.999
+	Objective float64 `json:"objective" yaml:"objective" mapstructure:"objective"`
+	// Latency is the threshold in milliseconds above which a request is bad. Zero only counts the server errors
+	Latency int `json:"latency,omitempty" yaml:"latency,omitempty" mapstructure:"latency"`
+
+	// Alerts are evaluated every minute. They default to burn rates of 14.4 over an hour and 6 over six hours.
+	Alerts []*SLOAlert `json:"alerts,omitempty" yaml:"alerts,omitempty" mapstructure:"alerts"`
+	// WebhookURL receives the alerts as they fire and resolve. Headers are added to the webhook requests
+	WebhookURL string            `json:"webhookUrl,omitempty" yaml:"webhookUrl,omitempty" mapstructure:"webhookUrl"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+}
+
+// SLOAlert fires when the error budget burns faster than the burn rate over both the long and the short window. The
+// short window makes the alert resolve soon after the burn stops. Windows are in minutes.
+type SLOAlert struct {
+	LongWindow  int     `json:"longWindow" yaml:"longWindow" mapstructure:"longWindow"`
+	ShortWindow int     `json:"shortWindow" yaml:"shortWindow" mapstructure:"shortWindow"`
+	BurnRate    float64 `json:"burnRate" yaml:"burnRate" mapstructure:"burnRate"`
+}
@@ -0,0 +1,22 @@
+package config
+
+// ProjectTemplates describes the custom project templates registered in the cluster
+type ProjectTemplates map[string]*ProjectTemplate
+
+// ProjectTemplate describes the collections, security rules and sample event triggers a project gets created with
+type ProjectTemplate struct {
+	ID          string                         `json:"id" yaml:"id" mapstructure:"id"`
+	Name        string                         `json:"name" yaml:"name" mapstructure:"name"`
+	Description string                         `json:"description,omitempty" yaml:"description,omitempty" mapstructure:"description"`
+	Collections map[string]*TemplateCollection `json:"collections" yaml:"collections" mapstructure:"collections"` // The key here is the table name
+	// Triggers are the sample event triggers of the template. Urls starting with a `/` are resolved against the
+	// webhook url provided while creating the project. The key here is the trigger name.
+	Triggers map[string]*EventingTrigger `json:"triggers,omitempty" yaml:"triggers,omitempty" mapstructure:"triggers"`
+}
+
+// TemplateCollection describes the schema and security rules of a single table of a project template
+type TemplateCollection struct {
+	Schema            string           `json:"schema" yaml:"schema" mapstructure:"schema"`
+	Rules             map[string]*Rule `json:"rules,omitempty" yaml:"rules,omitempty" mapstructure:"rules"` // The key here is create, read, update or delete
+	IsRealTimeEnabled bool             `json:"isRealtimeEnabled,omitempty" yaml:"isRealtimeEnabled,omitempty" mapstructure:"isRealtimeEnabled"`
+}
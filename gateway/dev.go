@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/bolt"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+// The defaults of the dev mode. They are fixed so that every run of the dev mode starts the same stack.
+const (
+	devClusterID = "dev"
+	devNodeID    = "dev"
+	devProjectID = "dev"
+	devDBAlias   = "db"
+	devDBFile    = "dev.db"
+	devSecret    = "dev-secret"
+)
+
+// setupDevMode prepares a stack without any external dependencies. The config and the embedded database are kept in
+// the provided directory, and the pub sub clients use the broker in the memory of the process unless redis is
+// provided explicitly.
+func setupDevMode(dir, seedDir string) error {
+	if dir == "" {
+		dir = filepath.Join(utils.UserHomeDir(), ".space-cloud", "dev")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	if os.Getenv("REDIS_CONN") == "" {
+		if err := os.Setenv("REDIS_CONN", pubsub.InMemoryConn); err != nil {
+			return err
+		}
+	}
+
+	configPath := os.Getenv("CONFIG")
+	if configPath == "" {
+		configPath = filepath.Join(dir, "config.yaml")
+		if err := os.Setenv("CONFIG", configPath); err != nil {
+			return err
+		}
+	}
+
+	dbPath := filepath.Join(dir, devDBFile)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Creating config of the dev mode", map[string]interface{}{"path": configPath})
+		if err := config.StoreConfigToFile(generateDevConfig(dbPath), configPath); err != nil {
+			return err
+		}
+	}
+
+	if seedDir == "" {
+		return nil
+	}
+	return seedDevDatabase(context.TODO(), dbPath, seedDir)
+}
+
+// generateDevConfig returns the config of a project with the embedded database which can be accessed by anyone
+func generateDevConfig(dbPath string) *config.Config {
+	conf := config.GenerateEmptyConfig()
+	conf.ClusterConfig = &config.ClusterConfig{EnableTelemetry: false}
+
+	project := config.GenerateEmptyProject(&config.ProjectConfig{
+		ID:      devProjectID,
+		Name:    devProjectID,
+		Secrets: []*config.Secret{{KID: devProjectID, Alg: config.HS256, Secret: devSecret, IsPrimary: true}},
+	})
+	project.DatabaseConfigs[config.GenerateResourceID(devClusterID, devProjectID, config.ResourceDatabaseConfig, devDBAlias, "config")] = &config.DatabaseConfig{
+		DbAlias: devDBAlias, Type: string(model.EmbeddedDB), DBName: devProjectID, Conn: dbPath, Enabled: true,
+	}
+	project.DatabaseRules[config.GenerateResourceID(devClusterID, devProjectID, config.ResourceDatabaseRule, devDBAlias, "default", "rule")] = &config.DatabaseRule{
+		Table: "default", DbAlias: devDBAlias,
+		Rules: map[string]*config.Rule{
+			"create": {Rule: "allow"},
+			"read":   {Rule: "allow"},
+			"update": {Rule: "allow"},
+			"delete": {Rule: "allow"},
+		},
+	}
+	conf.Projects[devProjectID] = project
+	return conf
+}
+
+// seedDevDatabase loads the json files of the seed directory into the embedded database. Each file holds an array of
+// the documents of the collection it is named after. Collections which already have documents are left untouched, so
+// that the seed data gets loaded only once.
+func seedDevDatabase(ctx context.Context, dbPath, seedDir string) error {
+	files, err := filepath.Glob(filepath.Join(seedDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	if len(files) == 0 {
+		return nil
+	}
+
+	db, err := bolt.Init(true, dbPath, devProjectID)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to open the embedded database of the dev mode", err, nil)
+	}
+	defer utils.CloseTheCloser(db)
+
+	for _, file := range files {
+		col := strings.TrimSuffix(filepath.Base(file), ".json")
+		docs, err := loadSeedFile(file, col)
+		if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to load seed file (%s)", file), err, nil)
+		}
+
+		count, _, _, _, err := db.Read(ctx, col, &model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.Count})
+		if err != nil {
+			return err
+		}
+		if count > 0 || len(docs) == 0 {
+			continue
+		}
+
+		if _, err := db.Create(ctx, col, &model.CreateRequest{Document: docs, Operation: utils.All}); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to seed collection (%s)", col), err, nil)
+		}
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "Seeded collection of the dev mode", map[string]interface{}{"col": col, "count": len(docs)})
+	}
+	return nil
+}
+
+// loadSeedFile reads the documents of a seed file. The documents without an _id get one derived from their position
+// in the file.
+func loadSeedFile(file, col string) ([]interface{}, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]interface{}, 0)
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, err
+	}
+	for i, doc := range docs {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("document (%d) is not an object", i)
+		}
+		if _, p := obj["_id"]; !p {
+			obj["_id"] = fmt.Sprintf("%s-%d", col, i+1)
+		}
+	}
+	return docs, nil
+}
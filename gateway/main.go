@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,8 +15,12 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/server"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/lint"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
 )
 
 var essentialFlags = []cli.Flag{
@@ -42,6 +47,21 @@ var essentialFlags = []cli.Flag{
 		Usage:  "Run space-cloud in development mode",
 		EnvVar: "DEV",
 	},
+	cli.StringFlag{
+		Name:   "dev-dir",
+		Usage:  "Directory holding the config and the embedded database of the development mode",
+		EnvVar: "DEV_DIR",
+	},
+	cli.StringFlag{
+		Name:   "dev-seed",
+		Usage:  "Load the json files of `DIR` into the embedded database of the development mode",
+		EnvVar: "DEV_SEED",
+	},
+	cli.BoolFlag{
+		Name:   "test-mode",
+		Usage:  "Let the projects load their fixtures. Never enable it on a cluster serving production data",
+		EnvVar: "TEST_MODE",
+	},
 	cli.BoolFlag{
 		Name:   "profiler",
 		Usage:  "Enable profiler endpoints for profiling",
@@ -55,14 +75,63 @@ var essentialFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:   "store-type",
-		Usage:  "The config store to use for storing project configs and other meta data",
+		Usage:  "The config store to use for storing project configs and other meta data. The memory store loses the config on restart",
 		EnvVar: "STORE_TYPE",
 		Value:  "local",
 	},
+	cli.StringFlag{
+		Name:   "cluster-mode",
+		Usage:  "The way the nodes of the cluster coordinate. Use none to run a single node which doesn't need redis",
+		EnvVar: "CLUSTER_MODE",
+		Value:  syncman.ClusterModeRedis,
+	},
+	cli.StringFlag{
+		Name:   "advertise-addr",
+		Usage:  "The host or host:port at which the other nodes of the cluster can reach this node. Defaults to the ip of this node and the port of the apis",
+		EnvVar: "ADVERTISE_ADDR",
+	},
+	cli.StringFlag{
+		Name:   "advertise-scheme",
+		Usage:  "The scheme [http | https] at which the other nodes of the cluster can reach this node",
+		EnvVar: "ADVERTISE_SCHEME",
+		Value:  "http",
+	},
+	cli.StringFlag{
+		Name:   "zone",
+		Usage:  "The availability zone or region this node runs in. It is advertised to the other nodes of the cluster",
+		EnvVar: "ZONE",
+	},
+	cli.IntFlag{
+		Name:   "capacity",
+		Usage:  "The relative share of the load this node can take. It is advertised to the other nodes of the cluster",
+		EnvVar: "CAPACITY",
+	},
+	cli.StringFlag{
+		Name:   "node-labels",
+		Usage:  "Comma separated key=value labels advertised to the other nodes of the cluster",
+		EnvVar: "NODE_LABELS",
+	},
 	cli.IntFlag{
 		Name:  "port",
 		Value: 4122,
 	},
+	cli.StringFlag{
+		Name:   "wal-dir",
+		Usage:  "Persist the queued status updates of the events and the metrics yet to be pushed in `DIR` so that they survive a crash",
+		EnvVar: "WAL_DIR",
+	},
+	cli.IntFlag{
+		Name:   "wal-max-size",
+		Usage:  "The size in MB at which the write ahead log stops growing by slowing down the delivery of events",
+		EnvVar: "WAL_MAX_SIZE",
+		Value:  256,
+	},
+	cli.IntFlag{
+		Name:   "drain-timeout",
+		Usage:  "Time in seconds to wait for in flight requests and events to complete on shutdown",
+		EnvVar: "DRAIN_TIMEOUT",
+		Value:  30,
+	},
 	cli.StringFlag{
 		Name:   "restrict-hosts",
 		EnvVar: "RESTRICT_HOSTS",
@@ -94,6 +163,44 @@ var essentialFlags = []cli.Flag{
 		EnvVar: "SSL_KEY",
 	},
 
+	// Flags for the protocols of the listeners
+	cli.BoolFlag{
+		Name:   "disable-http2",
+		Usage:  "Disable http2 on the https port",
+		EnvVar: "DISABLE_HTTP2",
+	},
+	cli.BoolFlag{
+		Name:   "h2c",
+		Usage:  "Serve cleartext http2 on the http port for the internal routes behind a load balancer",
+		EnvVar: "ENABLE_H2C",
+	},
+	cli.BoolFlag{
+		Name:   "http3",
+		Usage:  "Serve experimental http3 on the udp port of https (requires a gateway built with the http3 tag)",
+		EnvVar: "ENABLE_HTTP3",
+	},
+	cli.IntFlag{
+		Name:   "http2-max-streams",
+		Usage:  "Max number of concurrent streams per http2 connection",
+		EnvVar: "HTTP2_MAX_STREAMS",
+	},
+	cli.StringFlag{
+		Name:   "unix-socket",
+		Usage:  "Serve http on the unix domain socket at `PATH` in addition to the http port",
+		EnvVar: "UNIX_SOCKET",
+	},
+	cli.StringFlag{
+		Name:   "unix-socket-mode",
+		Usage:  "Permissions of the unix domain socket in octal",
+		EnvVar: "UNIX_SOCKET_MODE",
+		Value:  "0660",
+	},
+	cli.BoolFlag{
+		Name:   "systemd-socket",
+		Usage:  "Serve http on the sockets passed by systemd socket activation instead of binding the http port",
+		EnvVar: "SYSTEMD_SOCKET_ACTIVATION",
+	},
+
 	// flags for admin man
 	cli.StringFlag{
 		Name:   "admin-user",
@@ -121,6 +228,42 @@ var essentialFlags = []cli.Flag{
 		EnvVar: "DISABLE_METRICS",
 	},
 
+	// Flags for the gitops mode
+	cli.StringFlag{
+		Name:   "gitops-repo",
+		Usage:  "Sync the config of the cluster from the git repository at `URL`",
+		EnvVar: "GITOPS_REPO",
+	},
+	cli.StringFlag{
+		Name:   "gitops-branch",
+		Usage:  "The branch of the gitops repository",
+		EnvVar: "GITOPS_BRANCH",
+		Value:  "main",
+	},
+	cli.StringFlag{
+		Name:   "gitops-path",
+		Usage:  "The config bundle or config file in the gitops repository",
+		EnvVar: "GITOPS_PATH",
+		Value:  "config.yaml",
+	},
+	cli.IntFlag{
+		Name:   "gitops-poll-interval",
+		Usage:  "Interval in seconds at which the gitops repository is polled",
+		EnvVar: "GITOPS_POLL_INTERVAL",
+		Value:  60,
+	},
+	cli.StringFlag{
+		Name:   "gitops-strategy",
+		Usage:  "Strategy used to apply the config of the gitops repository (merge or replace)",
+		EnvVar: "GITOPS_STRATEGY",
+		Value:  "replace",
+	},
+	cli.StringFlag{
+		Name:   "gitops-webhook-secret",
+		Usage:  "Secret used to verify the push webhooks of the gitops repository",
+		EnvVar: "GITOPS_WEBHOOK_SECRET",
+	},
+
 	// Flag to disable downloading mission-control
 	cli.BoolFlag{
 		Name:   "disable-ui",
@@ -142,6 +285,24 @@ func main() {
 			Action: actionRun,
 			Flags:  essentialFlags,
 		},
+		{
+			Name:      "lint",
+			Usage:     "checks a config file for errors before it gets applied to the cluster",
+			ArgsUsage: "<config.yaml>",
+			Action:    actionLint,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Usage: "Format of the findings [text | json | sarif]",
+					Value: lint.FormatText,
+				},
+				cli.StringFlag{
+					Name:  "fail-on",
+					Usage: "Exit with a non zero code on findings of this severity or higher [error | warning | info]",
+					Value: lint.SeverityError,
+				},
+			},
+		},
 		{
 			Name:   "health-check",
 			Usage:  "check the health of gateway instance",
@@ -151,6 +312,10 @@ func main() {
 					Name:  "timeout",
 					Value: 5,
 				},
+				cli.IntFlag{
+					Name:  "port",
+					Value: 4122,
+				},
 			},
 		},
 	}
@@ -190,6 +355,31 @@ func actionRun(c *cli.Context) error {
 	// Load flags related to clustering
 	clusterID := c.String("cluster")
 	storeType := c.String("store-type")
+	clusterMode := c.String("cluster-mode")
+
+	// The dev mode runs a single node without any external dependencies
+	if isDev {
+		if err := setupDevMode(c.String("dev-dir"), c.String("dev-seed")); err != nil {
+			return err
+		}
+		if clusterID == "" {
+			clusterID = devClusterID
+		}
+		if nodeID == "none" {
+			nodeID = devNodeID
+		}
+		if !c.IsSet("cluster-mode") {
+			clusterMode = syncman.ClusterModeNone
+		}
+	}
+
+	// A single node keeps the pub sub messages of its projects in memory unless redis is provided explicitly
+	if clusterMode == syncman.ClusterModeNone && os.Getenv("REDIS_CONN") == "" {
+		if err := os.Setenv("REDIS_CONN", pubsub.InMemoryConn); err != nil {
+			return err
+		}
+	}
+
 	if clusterID == "" {
 		return fmt.Errorf("provider cluster id through --cluster flag or using setting enviornment vairable CLUSTER_ID")
 	}
@@ -209,6 +399,24 @@ func actionRun(c *cli.Context) error {
 		ssl = &config.SSL{Enabled: true, Crt: sslCert, Key: sslKey}
 	}
 
+	// Set the protocols of the listeners
+	listener := &config.Listener{
+		DisableHTTP2:         c.Bool("disable-http2"),
+		H2C:                  c.Bool("h2c"),
+		HTTP3:                c.Bool("http3"),
+		MaxConcurrentStreams: uint32(c.Int("http2-max-streams")),
+		UnixSocket:           c.String("unix-socket"),
+		SystemdActivation:    c.Bool("systemd-socket"),
+	}
+	socketMode, err := strconv.ParseUint(c.String("unix-socket-mode"), 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid unix socket mode (%s) provided - %v", c.String("unix-socket-mode"), err)
+	}
+	listener.UnixSocketMode = uint32(socketMode)
+	if listener.HTTP3 && !sslEnable {
+		return fmt.Errorf("http3 requires ssl to be enabled through --ssl-enable flag")
+	}
+
 	// Override the admin config if provided
 	if adminUser == "" {
 		adminUser = "admin"
@@ -220,10 +428,45 @@ func actionRun(c *cli.Context) error {
 		adminSecret = "some-secret"
 	}
 	adminUserInfo := &config.AdminUser{User: adminUser, Pass: adminPass, Secret: adminSecret}
-	s, err := server.New(nodeID, clusterID, storeType, runnerAddr, isDev, adminUserInfo, ssl)
+
+	// Set the gitops config
+	gitOps := &config.GitOps{
+		Enabled:       c.String("gitops-repo") != "",
+		Repository:    c.String("gitops-repo"),
+		Branch:        c.String("gitops-branch"),
+		Path:          c.String("gitops-path"),
+		PollInterval:  c.Int("gitops-poll-interval"),
+		Strategy:      c.String("gitops-strategy"),
+		WebhookSecret: c.String("gitops-webhook-secret"),
+	}
+
+	s, err := server.New(nodeID, clusterID, storeType, clusterMode, runnerAddr, isDev, adminUserInfo, ssl, listener, gitOps)
+	if err != nil {
+		return err
+	}
+
+	s.SetTestMode(c.Bool("test-mode"))
+	if err := s.SetWAL(c.String("wal-dir"), int64(c.Int("wal-max-size"))*1024*1024); err != nil {
+		return err
+	}
+	if err := s.SetAdvertiseAddr(c.String("advertise-addr"), c.String("advertise-scheme")); err != nil {
+		return err
+	}
+	labels, err := parseNodeLabels(c.String("node-labels"))
 	if err != nil {
 		return err
 	}
+	if err := s.SetNodeMetadata(&model.NodeMetadata{Zone: c.String("zone"), Capacity: c.Int("capacity"), Labels: labels}); err != nil {
+		return err
+	}
+
+	if isDev {
+		token, err := s.GetAdminToken()
+		if err != nil {
+			return err
+		}
+		helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Started in dev mode", map[string]interface{}{"project": devProjectID, "adminToken": token})
+	}
 
 	staticPath := ""
 	if !disableUI {
@@ -234,7 +477,50 @@ func actionRun(c *cli.Context) error {
 		}
 	}
 
-	return s.Start(false, staticPath, port, strings.Split(c.String("restrict-hosts"), ","))
+	drainTimeout := time.Duration(c.Int("drain-timeout")) * time.Second
+	return s.Start(false, staticPath, port, strings.Split(c.String("restrict-hosts"), ","), drainTimeout)
+}
+
+func actionLint(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.NewExitError("provide the path of the config file to lint", 2)
+	}
+	failOn := c.String("fail-on")
+	if !lint.IsValidSeverity(failOn) {
+		return cli.NewExitError(fmt.Sprintf("invalid severity (%s) provided to fail on", failOn), 2)
+	}
+
+	path := c.Args().First()
+	conf, err := config.LoadConfigFromFile(path)
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("unable to load config file (%s) - %v", path, err), 2)
+	}
+
+	findings := lint.Lint(conf)
+	if err := lint.Write(os.Stdout, c.String("format"), path, utils.BuildVersion, findings); err != nil {
+		return cli.NewExitError(err.Error(), 2)
+	}
+
+	if lint.HasFindings(findings, failOn) {
+		return cli.NewExitError("", 1)
+	}
+	return nil
+}
+
+// parseNodeLabels parses the comma separated key=value labels of the node
+func parseNodeLabels(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	labels := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		arr := strings.SplitN(pair, "=", 2)
+		if len(arr) != 2 || strings.TrimSpace(arr[0]) == "" {
+			return nil, fmt.Errorf("invalid node label (%s) provided - it must be of the form key=value", pair)
+		}
+		labels[strings.TrimSpace(arr[0])] = strings.TrimSpace(arr[1])
+	}
+	return labels, nil
 }
 
 func actionHealthCheck(c *cli.Context) error {
@@ -242,7 +528,7 @@ func actionHealthCheck(c *cli.Context) error {
 	defer cancel()
 
 	// Make a request object
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:4122/v1/api/health-check", nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://localhost:%d/v1/api/health-check", c.Int("port")), nil)
 	if err != nil {
 		return err
 	}
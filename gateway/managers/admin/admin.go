@@ -14,6 +14,7 @@ type Manager struct {
 	lock         sync.RWMutex
 	user         *config.AdminUser
 	integrations config.Integrations
+	tokens       map[string]*config.AdminToken // The key here is the token id
 
 	services model.ScServices
 	isProd   bool
@@ -32,6 +33,7 @@ func New(nodeID, clusterID string, isDev bool, adminUserInfo *config.AdminUser)
 	m.clusterID = clusterID
 
 	m.integrations = make(config.Integrations)
+	m.tokens = make(map[string]*config.AdminToken)
 	m.user = adminUserInfo
 	return m
 }
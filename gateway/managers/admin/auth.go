@@ -34,14 +34,20 @@ func (m *Manager) GenerateToken(ctx context.Context, token string, tokenClaims m
 }
 
 func (m *Manager) createToken(tokenClaims map[string]interface{}) (string, error) {
+	// Add expiry of one week
+	return m.signToken(tokenClaims, time.Now().Add(24*7*time.Hour).Unix())
+}
 
+// signToken signs the claims with the admin secret. The token never expires if the expiry is zero.
+func (m *Manager) signToken(tokenClaims map[string]interface{}, expiry int64) (string, error) {
 	claims := jwt.MapClaims{}
 	for k, v := range tokenClaims {
 		claims[k] = v
 	}
-
-	// Add expiry of one week
-	claims["exp"] = time.Now().Add(24 * 7 * time.Hour).Unix()
+	delete(claims, "exp")
+	if expiry != 0 {
+		claims["exp"] = expiry
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token.Header["kid"] = utils.AdminSecretKID
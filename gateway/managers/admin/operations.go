@@ -20,6 +20,14 @@ func (m *Manager) GetInternalAccessToken() (string, error) {
 	return m.createToken(map[string]interface{}{"id": utils.InternalUserID})
 }
 
+// GetAdminToken returns a token of the admin user
+func (m *Manager) GetAdminToken() (string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.createToken(map[string]interface{}{"id": m.user.User, "role": "admin"})
+}
+
 // IsTokenValid checks if the token is valid
 func (m *Manager) IsTokenValid(ctx context.Context, token, resource, op string, attr map[string]string) (model.RequestParams, error) {
 	m.lock.RLock()
@@ -34,6 +42,10 @@ func (m *Manager) IsTokenValid(ctx context.Context, token, resource, op string,
 		return model.RequestParams{}, err
 	}
 
+	if err := m.checkScope(ctx, claims, getRequiredScope(resource, op)); err != nil {
+		return model.RequestParams{}, err
+	}
+
 	// Check if its an integration request and return the integration response if its an integration request
 	res := m.integrationMan.HandleConfigAuth(ctx, resource, op, claims, attr)
 	if res.CheckResponse() && res.Error() != nil {
@@ -44,11 +56,15 @@ func (m *Manager) IsTokenValid(ctx context.Context, token, resource, op string,
 	return model.RequestParams{Resource: resource, Op: op, Attributes: attr, Claims: claims}, nil
 }
 
-// CheckIfAdmin simply checks the token
+// CheckIfAdmin simply checks the token. Scoped admin tokens need the cluster:admin scope.
 func (m *Manager) CheckIfAdmin(ctx context.Context, token string) error {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
+	return m.checkAdmin(ctx, token, config.AdminScopeClusterAdmin)
+}
+
+func (m *Manager) checkAdmin(ctx context.Context, token, scope string) error {
 	if !m.isProd {
 		return nil
 	}
@@ -68,7 +84,7 @@ func (m *Manager) CheckIfAdmin(ctx context.Context, token string) error {
 		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Only admins are authorised to make this request.", nil, nil)
 	}
 
-	return nil
+	return m.checkScope(ctx, claims, scope)
 }
 
 // IsDBConfigValid checks if the database config is valid
@@ -136,6 +152,9 @@ func (m *Manager) RefreshToken(ctx context.Context, token string) (string, error
 	if err != nil {
 		return "", err
 	}
+	if _, p := tokenClaims["tokenId"]; p {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Scoped admin tokens cannot be refreshed", nil, nil)
+	}
 	// Create a new token
 	newToken, err := m.createToken(tokenClaims)
 	if err != nil {
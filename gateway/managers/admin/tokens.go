@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// SetAdminTokens sets the admin tokens issued in the cluster. Tokens absent in the provided config stand revoked.
+func (m *Manager) SetAdminTokens(tokens config.AdminTokens) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.tokens = make(map[string]*config.AdminToken, len(tokens))
+	for _, token := range tokens {
+		m.tokens[token.ID] = token
+	}
+}
+
+// IssueAdminToken creates a signed token for the provided admin token
+func (m *Manager) IssueAdminToken(token *config.AdminToken) (string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.signToken(map[string]interface{}{"id": token.ID, "role": "admin", "tokenId": token.ID, "scopes": token.Scopes}, token.ExpiresAt)
+}
+
+// CheckAdminScope checks if the token belongs to an admin with the provided scope
+func (m *Manager) CheckAdminScope(ctx context.Context, token, scope string) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.checkAdmin(ctx, token, scope)
+}
+
+// checkScope checks if the claims grant the provided scope. Tokens issued without a token id, like the ones created on
+// login, carry every scope. Scoped tokens must not be revoked or expired.
+func (m *Manager) checkScope(ctx context.Context, claims map[string]interface{}, scope string) error {
+	tokenID, p := claims["tokenId"]
+	if !p {
+		return nil
+	}
+
+	id, ok := tokenID.(string)
+	if !ok {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid token provided. Claim `tokenId` must be a string.", nil, nil)
+	}
+
+	token, p := m.tokens[id]
+	if !p {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Admin token (%s) has been revoked", id), nil, nil)
+	}
+	if token.ExpiresAt != 0 && time.Now().Unix() > token.ExpiresAt {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Admin token (%s) has expired", id), nil, nil)
+	}
+
+	for _, s := range token.Scopes {
+		if s == scope || s == config.AdminScopeClusterAdmin {
+			return nil
+		}
+	}
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Admin token (%s) does not have the scope (%s) required for this request", id, scope), nil, nil)
+}
+
+// getRequiredScope returns the scope required to perform an operation on a config level resource
+func getRequiredScope(resource, op string) string {
+	switch resource {
+	case "cluster":
+		// The cluster config carries the credentials of the tracing exporter and the log and metric sinks
+		if op == "read" {
+			return config.AdminScopeConfigRead
+		}
+		return config.AdminScopeClusterAdmin
+	case "integration", "integration-hook", "admin-token", "creds", "internal-token", "runner", "privacy", "diagnostics":
+		return config.AdminScopeClusterAdmin
+	case "operations":
+		if op == "read" {
+			return config.AdminScopeMetricsRead
+		}
+		return config.AdminScopeClusterAdmin
+	case "metrics", "usage":
+		return config.AdminScopeMetricsRead
+	}
+
+	if op == "read" {
+		return config.AdminScopeConfigRead
+	}
+	return config.AdminScopeConfigWrite
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestManager_checkScope(t *testing.T) {
+	m := New("", "clusterID", false, &config.AdminUser{Secret: "some-secret"})
+	m.SetAdminTokens(config.AdminTokens{
+		"clusterID--noProject--admin-token--reader":  {ID: "reader", Scopes: []string{config.AdminScopeConfigRead}},
+		"clusterID--noProject--admin-token--root":    {ID: "root", Scopes: []string{config.AdminScopeClusterAdmin}},
+		"clusterID--noProject--admin-token--expired": {ID: "expired", Scopes: []string{config.AdminScopeConfigRead}, ExpiresAt: time.Now().Add(-time.Minute).Unix()},
+	})
+
+	tests := []struct {
+		name    string
+		claims  map[string]interface{}
+		scope   string
+		wantErr bool
+	}{
+		{name: "token issued on login", claims: map[string]interface{}{"id": "admin", "role": "admin"}, scope: config.AdminScopeClusterAdmin},
+		{name: "token with the required scope", claims: map[string]interface{}{"tokenId": "reader"}, scope: config.AdminScopeConfigRead},
+		{name: "token without the required scope", claims: map[string]interface{}{"tokenId": "reader"}, scope: config.AdminScopeConfigWrite, wantErr: true},
+		{name: "cluster admin token", claims: map[string]interface{}{"tokenId": "root"}, scope: config.AdminScopeMetricsRead},
+		{name: "expired token", claims: map[string]interface{}{"tokenId": "expired"}, scope: config.AdminScopeConfigRead, wantErr: true},
+		{name: "revoked token", claims: map[string]interface{}{"tokenId": "deleted"}, scope: config.AdminScopeConfigRead, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.checkScope(context.Background(), tt.claims, tt.scope); (err != nil) != tt.wantErr {
+				t.Errorf("checkScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_IssueAdminToken(t *testing.T) {
+	m := New("", "clusterID", false, &config.AdminUser{Secret: "some-secret"})
+	m.isProd = true
+	token := &config.AdminToken{ID: "reader", Scopes: []string{config.AdminScopeConfigRead}}
+	m.SetAdminTokens(config.AdminTokens{"clusterID--noProject--admin-token--reader": token})
+
+	signed, err := m.IssueAdminToken(token)
+	if err != nil {
+		t.Fatalf("IssueAdminToken() error = %v", err)
+	}
+
+	if err := m.CheckAdminScope(context.Background(), signed, config.AdminScopeConfigRead); err != nil {
+		t.Errorf("CheckAdminScope() error = %v, want nil", err)
+	}
+	if err := m.CheckIfAdmin(context.Background(), signed); err == nil {
+		t.Errorf("CheckIfAdmin() accepted a token without the cluster:admin scope")
+	}
+	if _, err := m.RefreshToken(context.Background(), signed); err == nil {
+		t.Errorf("RefreshToken() refreshed a scoped admin token")
+	}
+
+	// Revoke the token
+	m.SetAdminTokens(config.AdminTokens{})
+	if err := m.CheckAdminScope(context.Background(), signed, config.AdminScopeConfigRead); err == nil {
+		t.Errorf("CheckAdminScope() accepted a revoked token")
+	}
+}
+
+func Test_getRequiredScope(t *testing.T) {
+	tests := []struct {
+		resource, op string
+		want         string
+	}{
+		{resource: "db-config", op: "read", want: config.AdminScopeConfigRead},
+		{resource: "db-config", op: "modify", want: config.AdminScopeConfigWrite},
+		{resource: "cluster", op: "read", want: config.AdminScopeConfigRead},
+		{resource: "cluster", op: "modify", want: config.AdminScopeClusterAdmin},
+		{resource: "metrics", op: "read", want: config.AdminScopeMetricsRead},
+		{resource: "operations", op: "read", want: config.AdminScopeMetricsRead},
+		{resource: "operations", op: "delete", want: config.AdminScopeClusterAdmin},
+		{resource: "admin-token", op: "read", want: config.AdminScopeClusterAdmin},
+		{resource: "privacy", op: "read", want: config.AdminScopeClusterAdmin},
+		{resource: "privacy", op: "modify", want: config.AdminScopeClusterAdmin},
+		{resource: "diagnostics", op: "read", want: config.AdminScopeClusterAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource+"-"+tt.op, func(t *testing.T) {
+			if got := getRequiredScope(tt.resource, tt.op); got != tt.want {
+				t.Errorf("getRequiredScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
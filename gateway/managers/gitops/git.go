@@ -0,0 +1,82 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// repository is the source of the config
+type repository interface {
+	// pull fetches the latest commit of the branch and returns its hash
+	pull(ctx context.Context) (string, error)
+	readFile(path string) ([]byte, error)
+}
+
+// gitRepository is a shallow clone of a branch maintained with the git binary
+type gitRepository struct {
+	url, branch, dir string
+}
+
+func newGitRepository(url, branch, dir string) *gitRepository {
+	return &gitRepository{url: url, branch: branch, dir: dir}
+}
+
+func (g *gitRepository) pull(ctx context.Context) (string, error) {
+	if _, err := os.Stat(filepath.Join(g.dir, ".git")); err != nil {
+		if err := os.RemoveAll(g.dir); err != nil {
+			return "", err
+		}
+		if _, err := g.run(ctx, "clone", "--depth", "1", "--single-branch", "--branch", g.branch, g.url, g.dir); err != nil {
+			return "", err
+		}
+	} else {
+		if _, err := g.run(ctx, "-C", g.dir, "fetch", "--depth", "1", "origin", g.branch); err != nil {
+			return "", err
+		}
+		if _, err := g.run(ctx, "-C", g.dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return "", err
+		}
+	}
+
+	commit, err := g.run(ctx, "-C", g.dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+func (g *gitRepository) readFile(path string) ([]byte, error) {
+	// Prevent the path from escaping the repository
+	return ioutil.ReadFile(filepath.Join(g.dir, filepath.Clean("/"+path)))
+}
+
+func (g *gitRepository) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		// The url may hold credentials
+		output := strings.ReplaceAll(strings.TrimSpace(stderr.String()), g.url, redactURL(g.url))
+		return "", fmt.Errorf("git %s failed: %v: %s", args[0], err, output)
+	}
+	return stdout.String(), nil
+}
+
+// redactURL removes the credentials from the url of the repository
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = url.User("redacted")
+	return u.String()
+}
@@ -0,0 +1,267 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+const (
+	defaultBranch       = "main"
+	defaultPath         = "config.yaml"
+	defaultPollInterval = time.Minute
+	syncTimeout         = 5 * time.Minute
+
+	// maxHistory is the number of recent syncs kept in memory
+	maxHistory = 20
+)
+
+// syncManInterface is the subset of the sync manager used to apply the config of the repository
+type syncManInterface interface {
+	ImportConfig(ctx context.Context, bundle *model.ConfigBundle, strategy string, params model.RequestParams) (int, *model.ConfigImportResult, error)
+	CompareConfig(ctx context.Context, c *config.Config) (*model.ConfigDrift, error)
+	IsLeader() bool
+}
+
+// Manager keeps the config of the cluster in sync with a git repository. The repository is polled by
+// the leader of the cluster while push webhooks trigger a sync on the node receiving them.
+type Manager struct {
+	lock sync.Mutex // serialises the syncs
+
+	config  *config.GitOps
+	syncMan syncManInterface
+	repo    repository
+
+	statusLock sync.RWMutex
+	lastCommit string
+	desired    *config.Config
+	history    []*model.GitOpsSync // most recent sync first
+
+	trigger chan string
+	done    chan struct{}
+	once    sync.Once
+}
+
+// New creates a new gitops manager. Nothing gets synced till the manager is started.
+func New(nodeID string, c *config.GitOps, syncMan syncManInterface) *Manager {
+	if c == nil {
+		c = &config.GitOps{}
+	}
+	if c.Branch == "" {
+		c.Branch = defaultBranch
+	}
+	if c.Path == "" {
+		c.Path = defaultPath
+	}
+	if c.Strategy == "" {
+		c.Strategy = model.ConfigImportReplace
+	}
+
+	dir := filepath.Join(os.TempDir(), "space-cloud-gitops-"+nodeID)
+	return &Manager{
+		config:  c,
+		syncMan: syncMan,
+		repo:    newGitRepository(c.Repository, c.Branch, dir),
+		history: []*model.GitOpsSync{},
+		trigger: make(chan string, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start begins polling the repository if the gitops mode is enabled
+func (m *Manager) Start() error {
+	if !m.config.Enabled {
+		return nil
+	}
+	if m.config.Repository == "" {
+		return errors.New("repository is required to enable the gitops mode")
+	}
+	if m.config.Strategy != model.ConfigImportMerge && m.config.Strategy != model.ConfigImportReplace {
+		return fmt.Errorf("invalid gitops strategy (%s) provided", m.config.Strategy)
+	}
+
+	go m.routineSync()
+	return nil
+}
+
+// Close stops polling the repository
+func (m *Manager) Close() {
+	m.once.Do(func() { close(m.done) })
+}
+
+// IsEnabled returns true if the gitops mode is enabled
+func (m *Manager) IsEnabled() bool {
+	return m.config.Enabled
+}
+
+// TriggerSync queues a sync of the repository without waiting for it to complete
+func (m *Manager) TriggerSync(trigger string) {
+	select {
+	case m.trigger <- trigger:
+	default:
+		// A sync is already queued
+	}
+}
+
+func (m *Manager) routineSync() {
+	interval := defaultPollInterval
+	if m.config.PollInterval > 0 {
+		interval = time.Duration(m.config.PollInterval) * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		trigger := model.GitOpsTriggerPoll
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			// Only the leader polls the repository. The changes reach the other nodes through the store.
+			if !m.syncMan.IsLeader() {
+				continue
+			}
+		case trigger = <-m.trigger:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+		_, _ = m.Sync(ctx, trigger)
+		cancel()
+	}
+}
+
+// Sync pulls the repository and applies its config to the cluster. Polls skip the commits which have
+// already been synced, so that changes made outside the repository are only reported as drift.
+func (m *Manager) Sync(ctx context.Context, trigger string) (*model.GitOpsSync, error) {
+	if !m.config.Enabled {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "GitOps mode is not enabled", nil, nil)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	start := time.Now()
+	record := &model.GitOpsSync{Trigger: trigger, StartedAt: start}
+
+	bundle, err := m.load(ctx, record)
+	if err == nil && bundle == nil {
+		// Nothing has changed since the last sync
+		return nil, nil
+	}
+	if err == nil {
+		params := model.RequestParams{Claims: map[string]interface{}{"role": "sc-internal"}, Resource: "config", Op: "modify"}
+		_, record.Result, err = m.syncMan.ImportConfig(ctx, bundle, m.config.Strategy, params)
+	}
+	record.Duration = time.Since(start).String()
+
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	m.history = append([]*model.GitOpsSync{record}, m.history...)
+	if len(m.history) > maxHistory {
+		m.history = m.history[:maxHistory]
+	}
+
+	if err != nil {
+		record.Status = model.GitOpsStatusFailed
+		record.Error = err.Error()
+		return record, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to sync config from commit (%s) of the gitops repository", record.Commit), err, nil)
+	}
+
+	record.Status = model.GitOpsStatusSynced
+	m.lastCommit = record.Commit
+	m.desired = bundle.Config
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Synced config from commit (%s) of the gitops repository", record.Commit), map[string]interface{}{"trigger": trigger})
+	return record, nil
+}
+
+// load pulls the repository and parses the config at the configured path. A nil bundle is returned if a poll
+// finds the commit which has already been synced.
+func (m *Manager) load(ctx context.Context, record *model.GitOpsSync) (*model.ConfigBundle, error) {
+	commit, err := m.repo.pull(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record.Commit = commit
+
+	m.statusLock.RLock()
+	lastCommit := m.lastCommit
+	m.statusLock.RUnlock()
+	if record.Trigger == model.GitOpsTriggerPoll && commit == lastCommit {
+		return nil, nil
+	}
+
+	data, err := m.repo.readFile(m.config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read (%s) from the repository: %v", m.config.Path, err)
+	}
+	return parseBundle(data)
+}
+
+// parseBundle parses either a config bundle generated by the export api or a plain config file
+func parseBundle(data []byte) (*model.ConfigBundle, error) {
+	bundle := new(model.ConfigBundle)
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("invalid config provided: %v", err)
+	}
+	if bundle.Config != nil {
+		return bundle, nil
+	}
+
+	c := new(config.Config)
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid config provided: %v", err)
+	}
+	if c.Projects == nil {
+		c.Projects = config.Projects{}
+	}
+	return &model.ConfigBundle{Version: model.ConfigBundleVersion, Config: c}, nil
+}
+
+// GetStatus returns the status of the recent syncs along with the drift of the cluster from the last synced commit
+func (m *Manager) GetStatus(ctx context.Context) (int, *model.GitOpsStatus, error) {
+	if !m.config.Enabled {
+		return http.StatusOK, &model.GitOpsStatus{Enabled: false}, nil
+	}
+
+	m.statusLock.RLock()
+	status := &model.GitOpsStatus{
+		Enabled:    true,
+		Repository: redactURL(m.config.Repository),
+		Branch:     m.config.Branch,
+		Path:       m.config.Path,
+		Strategy:   m.config.Strategy,
+		History:    append([]*model.GitOpsSync{}, m.history...),
+	}
+	desired := m.desired
+	m.statusLock.RUnlock()
+
+	if len(status.History) > 0 {
+		status.LastSync = status.History[0]
+	}
+	if desired == nil {
+		return http.StatusOK, status, nil
+	}
+
+	drift, err := m.syncMan.CompareConfig(ctx, desired)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	if m.config.Strategy == model.ConfigImportMerge {
+		// Resources outside the repository aren't managed by it in the merge strategy
+		drift.Added = []string{}
+	}
+	status.Drift = drift
+	status.Drifted = drift.HasDrift()
+	return http.StatusOK, status, nil
+}
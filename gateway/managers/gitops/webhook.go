@@ -0,0 +1,38 @@
+package gitops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// VerifyWebhook checks if a push webhook has been sent by the git provider. Github webhooks are verified
+// with the signature of the body while gitlab webhooks carry the secret as is.
+func (m *Manager) VerifyWebhook(header http.Header, body []byte) error {
+	if m.config.WebhookSecret == "" {
+		return errors.New("webhook secret has not been configured for the gitops mode")
+	}
+
+	if signature := header.Get("X-Hub-Signature-256"); signature != "" {
+		mac := hmac.New(sha256.New, []byte(m.config.WebhookSecret))
+		_, _ = mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+			return errors.New("invalid webhook signature provided")
+		}
+		return nil
+	}
+
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(m.config.WebhookSecret)) != 1 {
+			return errors.New("invalid webhook token provided")
+		}
+		return nil
+	}
+
+	return errors.New("webhook is neither signed nor carries a token")
+}
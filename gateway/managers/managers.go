@@ -3,6 +3,7 @@ package managers
 import (
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/gitops"
 	"github.com/spaceuptech/space-cloud/gateway/managers/integration"
 	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
 )
@@ -12,21 +13,24 @@ type Managers struct {
 	adminMan       *admin.Manager
 	syncMan        *syncman.Manager
 	integrationMan *integration.Manager
+	gitOpsMan      *gitops.Manager
 }
 
 // New creates a new managers instance
-func New(nodeID, clusterID, storeType, runnerAddr string, isDev bool, adminUserInfo *config.AdminUser, ssl *config.SSL) (*Managers, error) {
+func New(nodeID, clusterID, storeType, clusterMode, runnerAddr string, isDev bool, adminUserInfo *config.AdminUser, ssl *config.SSL, gitOps *config.GitOps) (*Managers, error) {
 	// Create the fundamental modules
 	adminMan := admin.New(nodeID, clusterID, isDev, adminUserInfo)
 	i := integration.New(adminMan)
-	syncMan, err := syncman.New(nodeID, clusterID, storeType, runnerAddr, adminMan, i, ssl)
+	syncMan, err := syncman.New(nodeID, clusterID, storeType, clusterMode, runnerAddr, adminMan, i, ssl)
 	if err != nil {
 		return nil, err
 	}
 	adminMan.SetSyncMan(syncMan)
 	adminMan.SetIntegrationMan(i)
 
-	return &Managers{adminMan: adminMan, syncMan: syncMan, integrationMan: i}, nil
+	gitOpsMan := gitops.New(nodeID, gitOps, syncMan)
+
+	return &Managers{adminMan: adminMan, syncMan: syncMan, integrationMan: i, gitOpsMan: gitOpsMan}, nil
 }
 
 // Admin returns the admin manager
@@ -43,3 +47,8 @@ func (m *Managers) Sync() *syncman.Manager {
 func (m *Managers) Integration() *integration.Manager {
 	return m.integrationMan
 }
+
+// GitOps returns the gitops manager
+func (m *Managers) GitOps() *gitops.Manager {
+	return m.gitOpsMan
+}
@@ -136,6 +136,34 @@ func (s *Manager) validateResource(ctx context.Context, eventType string, resour
 		}
 		return false, nil
 
+	case config.ResourceProjectTemplate:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.ProjectTemplate)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.ProjectTemplate{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(globalConfig.ProjectTemplates[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case config.ResourceAdminToken:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.AdminToken)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.AdminToken{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(globalConfig.AdminTokens[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
+
 	}
 
 	if resourceType == config.ResourceProject {
@@ -252,6 +280,32 @@ func (s *Manager) validateResource(ctx context.Context, eventType string, resour
 			}
 		}
 		return false, nil
+	case config.ResourceEventingSource:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.EventingSource)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.EventingSource{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.EventingSources[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case config.ResourceEventingWorkflow:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.EventingWorkflow)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.EventingWorkflow{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.EventingWorkflows[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
 	case config.ResourceEventingRule:
 		switch eventType {
 		case config.ResourceAddEvent, config.ResourceUpdateEvent:
@@ -317,6 +371,71 @@ func (s *Manager) validateResource(ctx context.Context, eventType string, resour
 			}
 		}
 		return false, nil
+	case config.ResourceSearchConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.SearchConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.SearchConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.SearchConfig, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case config.ResourceBackupConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.BackupConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.BackupConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.BackupConfig, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case config.ResourceKVConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.KVConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.KVConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.KVConfig, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case config.ResourcePrivacyConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.PrivacyConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.PrivacyConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.PrivacyConfig, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case config.ResourceFeatureFlag:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.FeatureFlag)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.FeatureFlag{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.FeatureFlags[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
 	case config.ResourceIngressRoute:
 		switch eventType {
 		case config.ResourceAddEvent, config.ResourceUpdateEvent:
@@ -356,6 +475,19 @@ func (s *Manager) validateResource(ctx context.Context, eventType string, resour
 			}
 		}
 		return false, nil
+	case config.ResourceDeploySecret:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.DeploySecret)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return false, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.DeploySecret{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if reflect.DeepEqual(project.DeploySecrets[resourceID], value) {
+				return true, nil
+			}
+		}
+		return false, nil
 	default:
 		return false, fmt.Errorf("unknown resource type (%s) provided", resourceType)
 	}
@@ -444,6 +576,44 @@ func updateResource(ctx context.Context, eventType string, globalConfig *config.
 		}
 
 		return nil
+
+	case config.ResourceProjectTemplate:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.ProjectTemplate)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.ProjectTemplate{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if globalConfig.ProjectTemplates == nil {
+				globalConfig.ProjectTemplates = config.ProjectTemplates{resourceID: value}
+			} else {
+				globalConfig.ProjectTemplates[resourceID] = value
+			}
+
+		case config.ResourceDeleteEvent:
+			delete(globalConfig.ProjectTemplates, resourceID)
+		}
+		return nil
+
+	case config.ResourceAdminToken:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.AdminToken)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.AdminToken{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if globalConfig.AdminTokens == nil {
+				globalConfig.AdminTokens = config.AdminTokens{resourceID: value}
+			} else {
+				globalConfig.AdminTokens[resourceID] = value
+			}
+
+		case config.ResourceDeleteEvent:
+			delete(globalConfig.AdminTokens, resourceID)
+		}
+		return nil
 	}
 
 	// check project level resources
@@ -602,6 +772,44 @@ func updateResource(ctx context.Context, eventType string, globalConfig *config.
 
 		return nil
 
+	case config.ResourceEventingSource:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.EventingSource)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.EventingSource{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if project.EventingSources == nil {
+				project.EventingSources = config.EventingSources{resourceID: value}
+			} else {
+				project.EventingSources[resourceID] = value
+			}
+		case config.ResourceDeleteEvent:
+			delete(project.EventingSources, resourceID)
+		}
+
+		return nil
+
+	case config.ResourceEventingWorkflow:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.EventingWorkflow)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.EventingWorkflow{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if project.EventingWorkflows == nil {
+				project.EventingWorkflows = config.EventingWorkflows{resourceID: value}
+			} else {
+				project.EventingWorkflows[resourceID] = value
+			}
+		case config.ResourceDeleteEvent:
+			delete(project.EventingWorkflows, resourceID)
+		}
+
+		return nil
+
 	case config.ResourceEventingRule:
 		switch eventType {
 		case config.ResourceAddEvent, config.ResourceUpdateEvent:
@@ -686,6 +894,85 @@ func updateResource(ctx context.Context, eventType string, globalConfig *config.
 
 		return nil
 
+	case config.ResourceSearchConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.SearchConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.SearchConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			project.SearchConfig = value
+		case config.ResourceDeleteEvent:
+			project.SearchConfig = nil
+		}
+
+		return nil
+
+	case config.ResourceBackupConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.BackupConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.BackupConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			project.BackupConfig = value
+		case config.ResourceDeleteEvent:
+			project.BackupConfig = nil
+		}
+
+		return nil
+
+	case config.ResourceKVConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.KVConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.KVConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			project.KVConfig = value
+		case config.ResourceDeleteEvent:
+			project.KVConfig = nil
+		}
+
+		return nil
+
+	case config.ResourcePrivacyConfig:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.PrivacyConfig)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.PrivacyConfig{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			project.PrivacyConfig = value
+		case config.ResourceDeleteEvent:
+			project.PrivacyConfig = nil
+		}
+
+		return nil
+
+	case config.ResourceFeatureFlag:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.FeatureFlag)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.FeatureFlag{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if project.FeatureFlags == nil {
+				project.FeatureFlags = config.FeatureFlags{resourceID: value}
+			} else {
+				project.FeatureFlags[resourceID] = value
+			}
+		case config.ResourceDeleteEvent:
+			delete(project.FeatureFlags, resourceID)
+		}
+
+		return nil
+
 	case config.ResourceIngressRoute:
 		switch eventType {
 		case config.ResourceAddEvent, config.ResourceUpdateEvent:
@@ -739,6 +1026,26 @@ func updateResource(ctx context.Context, eventType string, globalConfig *config.
 
 		return nil
 
+	case config.ResourceDeploySecret:
+		switch eventType {
+		case config.ResourceAddEvent, config.ResourceUpdateEvent:
+			value := new(config.DeploySecret)
+			if err := mapstructure.Decode(resource, value); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type provided for resource (%s) expecting (%v) got (%v)", resourceType, "config.DeploySecret{}", reflect.TypeOf(resource)), nil, nil)
+			}
+
+			if project.DeploySecrets == nil {
+				project.DeploySecrets = config.DeploySecrets{resourceID: value}
+			} else {
+				project.DeploySecrets[resourceID] = value
+			}
+
+		case config.ResourceDeleteEvent:
+			delete(project.DeploySecrets, resourceID)
+		}
+
+		return nil
+
 	default:
 		return fmt.Errorf("unknown resource type (%s) provided", resourceType)
 	}
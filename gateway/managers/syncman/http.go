@@ -3,18 +3,27 @@ package syncman
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
+	"io/ioutil"
 	"net/http"
-	"strconv"
 
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 	"golang.org/x/net/context"
 
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 // MakeHTTPRequest fires an http request and returns a response
 func (s *Manager) MakeHTTPRequest(ctx context.Context, method, url, token, scToken string, params, vPtr interface{}) error {
+	ctx, span := tracing.StartSpan(ctx, "HTTP "+method, trace.SpanKindClient, label.String("http.method", method), label.String("http.url", url))
+	err := s.makeHTTPRequest(ctx, method, url, token, scToken, params, vPtr)
+	tracing.EndSpan(ctx, span, err)
+	return err
+}
+
+func (s *Manager) makeHTTPRequest(ctx context.Context, method, url, token, scToken string, params, vPtr interface{}) error {
 	// Marshal json into byte array
 	data, _ := json.Marshal(params)
 	// Make a request object
@@ -31,6 +40,10 @@ func (s *Manager) MakeHTTPRequest(ctx context.Context, method, url, token, scTok
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("x-sc-token", "Bearer "+scToken)
 
+	// Propagate the trace context and the request id
+	tracing.Inject(ctx, req.Header)
+	utils.InjectRequestID(ctx, req.Header)
+
 	// Create a http client and fire the request
 	client := &http.Client{}
 
@@ -45,12 +58,17 @@ func (s *Manager) MakeHTTPRequest(ctx context.Context, method, url, token, scTok
 	}
 	defer utils.CloseTheCloser(resp.Body)
 
-	if err := json.NewDecoder(resp.Body).Decode(vPtr); err != nil {
-		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable decode response", err, nil)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable read response", err, nil)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return errors.New("service responded with status code " + strconv.Itoa(resp.StatusCode))
+		return utils.ParseErrorResponse(resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, vPtr); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable decode response", err, nil)
 	}
 	return nil
 }
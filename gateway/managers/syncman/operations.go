@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/spaceuptech/helpers"
+
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 // GetSpaceCloudPort returns the port sc is running on
@@ -71,6 +74,11 @@ func (s *Manager) GetRealtimeURL(project string) string {
 	return fmt.Sprintf("http://localhost:%d/v1/api/%s/realtime/handle", s.port, project)
 }
 
+// GetSearchURL returns the url at which database events are delivered to the search module
+func (s *Manager) GetSearchURL(project string) string {
+	return fmt.Sprintf("http://localhost:%d/v1/api/%s/search/handle", s.port, project)
+}
+
 // GetAssignedTokens returns the array or tokens assigned to this node
 func (s *Manager) GetAssignedTokens() (start, end int) {
 	s.lockServices.RLock()
@@ -114,6 +122,25 @@ func (s *Manager) SetClusterConfig(ctx context.Context, req *config.ClusterConfi
 
 	s.globalModules.SetMetricsConfig(s.projectConfig.ClusterConfig.EnableTelemetry)
 	s.modules.LetsEncrypt().SetLetsEncryptEmail(req.LetsEncryptEmail)
+	tracing.SetConfig(req.Tracing)
+	if err := s.globalModules.SetLoggingConfig(req.Logging); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply logging config", err, nil)
+	}
+	if err := s.globalModules.SetAccountingConfig(req.Accounting); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply accounting config", err, nil)
+	}
+	if err := s.globalModules.SetAdmissionConfig(req.Admission); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply admission config", err, nil)
+	}
+	if err := s.globalModules.SetCompressionConfig(req.Compression); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply compression config", err, nil)
+	}
+	if err := s.globalModules.SetMetricsSinksConfig(req.Metrics); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply metrics config", err, nil)
+	}
+	if err := s.globalModules.SetDiagnosticsConfig(req.Diagnostics); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply diagnostics config", err, nil)
+	}
 
 	return http.StatusOK, nil
 }
@@ -157,3 +184,42 @@ func (s *Manager) HealthCheck() error {
 	defer s.lock.RUnlock()
 	return nil
 }
+
+// CheckClusterHealth checks if this node has discovered the members of the cluster and if the broker used for
+// communicating with the other nodes is reachable
+func (s *Manager) CheckClusterHealth(ctx context.Context) []*model.ComponentHealth {
+	s.lockServices.RLock()
+	var membershipErr error
+	switch {
+	case s.isDraining:
+		membershipErr = fmt.Errorf("node (%s) is leaving cluster (%s)", s.nodeID, s.clusterID)
+	case len(s.services) == 0:
+		membershipErr = fmt.Errorf("node (%s) has not discovered the members of cluster (%s) yet", s.nodeID, s.clusterID)
+	}
+	s.lockServices.RUnlock()
+
+	components := []*model.ComponentHealth{model.NewComponentHealth("membership", model.HealthComponentCluster, "", membershipErr)}
+
+	// A single node coordinates in memory and hence has no broker to check
+	if s.clusterMode != ClusterModeNone {
+		components = append(components, model.NewComponentHealth("redis", model.HealthComponentBroker, "", s.pubsubClient.Ping(ctx)))
+	}
+	return components
+}
+
+// StartDraining marks the node as draining. The readiness checks fail from here on so that the load balancers
+// stop routing new requests to this node.
+func (s *Manager) StartDraining() {
+	s.lockServices.Lock()
+	defer s.lockServices.Unlock()
+	s.isDraining = true
+}
+
+// Leave cleanly leaves the cluster by giving up the leader position and closing the connection to the broker
+func (s *Manager) Leave(ctx context.Context) error {
+	s.StartDraining()
+
+	err := s.leader.Resign(ctx)
+	s.pubsubClient.Close()
+	return err
+}
@@ -0,0 +1,60 @@
+package syncman
+
+import (
+	"context"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// MemoryStore keeps the config of a single node cluster in memory. The config is lost once the gateway stops, which
+// makes it suitable for tests and throwaway environments.
+type MemoryStore struct {
+	globalConfig *config.Config
+	services     model.ScServices
+}
+
+// NewMemoryStore creates a new memory store with an empty config
+func NewMemoryStore(nodeID string, ssl *config.SSL) *MemoryStore {
+	conf := config.GenerateEmptyConfig()
+	conf.ClusterConfig = &config.ClusterConfig{}
+	if ssl != nil && ssl.Enabled {
+		conf.SSL = ssl
+	}
+	return &MemoryStore{globalConfig: conf, services: model.ScServices{&model.Service{ID: nodeID}}}
+}
+
+// Register registers space cloud to the memory store
+func (s *MemoryStore) Register() {}
+
+// WatchResources maintains consistency over all projects. There is nothing to watch for a single node.
+func (s *MemoryStore) WatchResources(cb func(eventType, resourceId string, resourceType config.Resource, resource interface{})) error {
+	return nil
+}
+
+// WatchServices reports this node as the only service of the cluster
+func (s *MemoryStore) WatchServices(cb func(string, string, model.ScServices)) error {
+	cb(config.ResourceAddEvent, s.services[0].ID, s.services)
+	return nil
+}
+
+// SetResource sets the resource in the config
+func (s *MemoryStore) SetResource(ctx context.Context, resourceID string, resource interface{}) error {
+	return updateResource(ctx, config.ResourceAddEvent, s.globalConfig, resourceID, "", resource)
+}
+
+// DeleteResource deletes the resource from the config
+func (s *MemoryStore) DeleteResource(ctx context.Context, resourceID string) error {
+	return updateResource(ctx, config.ResourceDeleteEvent, s.globalConfig, resourceID, "", nil)
+}
+
+// DeleteProject deletes the project from the config
+func (s *MemoryStore) DeleteProject(ctx context.Context, projectID string) error {
+	delete(s.globalConfig.Projects, projectID)
+	return nil
+}
+
+// GetGlobalConfig gets the config of all projects
+func (s *MemoryStore) GetGlobalConfig() (*config.Config, error) {
+	return s.globalConfig, nil
+}
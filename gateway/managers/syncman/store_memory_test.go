@@ -0,0 +1,68 @@
+package syncman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_validateClusterMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterMode string
+		storeType   string
+		wantErr     bool
+	}{
+		{name: "redis with the kube store", clusterMode: ClusterModeRedis, storeType: "kube"},
+		{name: "default mode", clusterMode: "", storeType: "local"},
+		{name: "single node with the local store", clusterMode: ClusterModeNone, storeType: "local"},
+		{name: "single node with the memory store", clusterMode: ClusterModeNone, storeType: "memory"},
+		{name: "single node with the kube store", clusterMode: ClusterModeNone, storeType: "kube", wantErr: true},
+		{name: "invalid mode", clusterMode: "raft", storeType: "local", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateClusterMode(tt.clusterMode, tt.storeType); (err != nil) != tt.wantErr {
+				t.Errorf("validateClusterMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_ClusterModeNone(t *testing.T) {
+	// Redis isn't running in the tests, hence the manager can only be created if it coordinates in memory
+	m, err := New("node-1", "cluster", "memory", ClusterModeNone, "", nil, nil, &config.SSL{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = m.leader.Resign(context.Background()) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !m.IsLeader() {
+		if time.Now().After(deadline) {
+			t.Fatalf("New() the single node didn't become the leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The other single nodes of the process are clusters of their own
+	other, err := New("node-2", "cluster", "memory", ClusterModeNone, "", nil, nil, &config.SSL{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = other.leader.Resign(context.Background()) }()
+
+	deadline = time.Now().Add(5 * time.Second)
+	for !other.IsLeader() {
+		if time.Now().After(deadline) {
+			t.Fatalf("New() the second single node didn't become the leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := m.store.(*MemoryStore).services; len(got) != 1 || got[0].ID != "node-1" {
+		t.Errorf("New() memory store services = %v, want only the node itself", got)
+	}
+}
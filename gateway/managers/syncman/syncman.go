@@ -13,6 +13,15 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils/leader"
 	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+)
+
+// The modes in which the nodes of a cluster coordinate
+const (
+	// ClusterModeRedis elects the leader and tracks the nodes of the cluster through redis
+	ClusterModeRedis = "redis"
+	// ClusterModeNone runs a single node which is always the leader. It needs neither redis nor any other node.
+	ClusterModeNone = "none"
 )
 
 // Manager syncs the project config between folders
@@ -28,13 +37,26 @@ type Manager struct {
 	clusterID  string
 	runnerAddr string
 	port       int
+	startedAt  time.Time
+
+	// advertiseAddr and advertiseScheme are the address and the scheme at which the other nodes can reach this node.
+	// The address may leave out the port in which case the port of the apis is used.
+	advertiseAddr   string
+	advertiseScheme string
 
 	leader       *leader.Module
 	pubsubClient *pubsub.Module
 	// Configuration for clustering
-	storeType string
-	store     Store
-	services  model.ScServices
+	storeType   string
+	clusterMode string
+	store       Store
+	services    model.ScServices
+
+	// isDraining is set once the node starts shutting down
+	isDraining bool
+
+	// clusterProtocolVersion is the lowest config replication protocol version spoken by the nodes of the cluster
+	clusterProtocolVersion int
 
 	// For authentication
 	adminMan       AdminSyncmanInterface
@@ -43,13 +65,28 @@ type Manager struct {
 	// Modules
 	modules       ModulesInterface
 	globalModules GlobalModulesInterface
+
+	// The metadata advertised by this node, the nodes last seen in the heartbeats and the subscribers of the
+	// membership events
+	nodeMetadata  *model.NodeMetadata
+	lockMembers   sync.Mutex
+	members       map[string]*model.ClusterNode
+	membershipCbs map[string]func(event *model.MembershipEvent)
+
+	// Subscribers of the admin events
+	lockAdminEvents sync.RWMutex
+	adminEventSubs  map[string]chan *model.AdminEvent
 }
 
 // New creates a new instance of the sync manager
-func New(nodeID, clusterID, storeType, runnerAddr string, adminMan AdminSyncmanInterface, integrationMan integrationInterface, ssl *config.SSL) (*Manager, error) {
+func New(nodeID, clusterID, storeType, clusterMode, runnerAddr string, adminMan AdminSyncmanInterface, integrationMan integrationInterface, ssl *config.SSL) (*Manager, error) {
 
 	// Create a new manager instance
-	m := &Manager{nodeID: nodeID, clusterID: clusterID, storeType: storeType, runnerAddr: runnerAddr, adminMan: adminMan, integrationMan: integrationMan}
+	m := &Manager{nodeID: nodeID, clusterID: clusterID, storeType: storeType, clusterMode: clusterMode, runnerAddr: runnerAddr, adminMan: adminMan, integrationMan: integrationMan, startedAt: time.Now()}
+
+	if err := validateClusterMode(clusterMode, storeType); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Cannot initialize syncman", err, nil)
+	}
 
 	// Initialise the consul client if enabled
 	var s Store
@@ -59,6 +96,8 @@ func New(nodeID, clusterID, storeType, runnerAddr string, adminMan AdminSyncmanI
 		s, err = NewLocalStore(nodeID, ssl)
 	case "kube":
 		s, err = NewKubeStore(clusterID)
+	case "memory":
+		s = NewMemoryStore(nodeID, ssl)
 	default:
 		return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Cannot initialize syncaman as invalid store type (%v) provided", storeType), nil, nil)
 	}
@@ -69,9 +108,13 @@ func New(nodeID, clusterID, storeType, runnerAddr string, adminMan AdminSyncmanI
 	m.store = s
 	m.store.Register()
 
-	pubsubClient, err := pubsub.New("license-manager", os.Getenv("REDIS_CONN"))
-	if err != nil {
-		return nil, helpers.Logger.LogError("syncman-new", "Unable to initialize pub sub client required for sync module, ensure that redis database is running", err, nil)
+	// A single node is the only participant of the leader election, hence it coordinates in memory
+	pubsubClient := pubsub.NewInMemory("license-manager")
+	if clusterMode != ClusterModeNone {
+		pubsubClient, err = pubsub.New("license-manager", os.Getenv("REDIS_CONN"))
+		if err != nil {
+			return nil, helpers.Logger.LogError("syncman-new", "Unable to initialize pub sub client required for sync module, ensure that redis database is running", err, nil)
+		}
 	}
 	m.pubsubClient = pubsubClient
 	m.leader = leader.New(nodeID, pubsubClient)
@@ -79,6 +122,22 @@ func New(nodeID, clusterID, storeType, runnerAddr string, adminMan AdminSyncmanI
 	return m, nil
 }
 
+// validateClusterMode checks if the nodes can coordinate in the cluster mode with the store
+func validateClusterMode(clusterMode, storeType string) error {
+	switch clusterMode {
+	case ClusterModeRedis, "":
+		return nil
+	case ClusterModeNone:
+		// The kube store is shared by all the pods of a deployment, hence it cannot be used by a single node
+		if storeType == "kube" {
+			return fmt.Errorf("cluster mode (%s) cannot be used with the kube store", clusterMode)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid cluster mode (%s) provided - it must be one of %s or %s", clusterMode, ClusterModeRedis, ClusterModeNone)
+	}
+}
+
 // Start begins the sync manager operations
 func (s *Manager) Start(port int) error {
 
@@ -89,6 +148,7 @@ func (s *Manager) Start(port int) error {
 		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Updating services", map[string]interface{}{"services": services, "eventType": eventType, "id": serviceID})
 
 		s.adminMan.SetServices(eventType, services)
+		s.emitServiceChanges(s.services, services)
 		s.services = services
 	}); err != nil {
 		return err
@@ -119,6 +179,7 @@ func (s *Manager) Start(port int) error {
 
 	s.adminMan.SetServices(config.ResourceAddEvent, s.services)
 	s.adminMan.SetIntegrationConfig(globalConfig.Integrations)
+	s.adminMan.SetAdminTokens(globalConfig.AdminTokens)
 	_ = s.integrationMan.SetConfig(globalConfig.Integrations, globalConfig.IntegrationHooks)
 
 	s.leader.AddCallBack("admin-set-service", func() {
@@ -126,6 +187,10 @@ func (s *Manager) Start(port int) error {
 		s.adminMan.SetServices(config.ResourceDeleteEvent, s.services)
 		s.lockServices.RUnlock()
 	})
+	s.leader.AddCallBack("admin-events", func() {
+		s.PublishAdminEvent(context.TODO(), &model.AdminEvent{Type: model.AdminEventLeaderChanged, Payload: map[string]interface{}{"leader": s.nodeID}})
+	})
+	go s.routineAdminEvents()
 
 	// Set caching config
 	if err := s.modules.Caching().SetCachingConfig(context.TODO(), globalConfig.CacheConfig); err != nil {
@@ -136,6 +201,39 @@ func (s *Manager) Start(port int) error {
 	helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Successfully loaded initial copy of config file", map[string]interface{}{})
 	s.globalModules.SetMetricsConfig(globalConfig.ClusterConfig.EnableTelemetry)
 
+	// Set tracing config
+	tracing.SetConfig(globalConfig.ClusterConfig.Tracing)
+
+	// Set logging config
+	if err := s.globalModules.SetLoggingConfig(globalConfig.ClusterConfig.Logging); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply logging config", err, nil)
+	}
+
+	// Set accounting config
+	if err := s.globalModules.SetAccountingConfig(globalConfig.ClusterConfig.Accounting); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply accounting config", err, nil)
+	}
+
+	// Set admission config
+	if err := s.globalModules.SetAdmissionConfig(globalConfig.ClusterConfig.Admission); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply admission config", err, nil)
+	}
+
+	// Set compression config
+	if err := s.globalModules.SetCompressionConfig(globalConfig.ClusterConfig.Compression); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply compression config", err, nil)
+	}
+
+	// Set metrics sinks config
+	if err := s.globalModules.SetMetricsSinksConfig(globalConfig.ClusterConfig.Metrics); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply metrics config", err, nil)
+	}
+
+	// Set diagnostics config
+	if err := s.globalModules.SetDiagnosticsConfig(globalConfig.ClusterConfig.Diagnostics); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply diagnostics config", err, nil)
+	}
+
 	// Set letsencrypt config
 	if globalConfig.ClusterConfig.LetsEncryptEmail != "" {
 		s.modules.LetsEncrypt().SetLetsEncryptEmail(globalConfig.ClusterConfig.LetsEncryptEmail)
@@ -152,6 +250,13 @@ func (s *Manager) Start(port int) error {
 	if err := s.store.WatchResources(func(eventType, resourceID string, resourceType config.Resource, resource interface{}) {
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 		defer cancel()
+
+		// Resources created by newer nodes during a rolling upgrade are skipped instead of failing the watch
+		if resourceType != "" && !config.IsKnownResource(resourceType) {
+			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Skipping resource (%s) of unknown type (%s) - it was probably created by a newer version of space cloud", resourceID, resourceType), nil)
+			return
+		}
+
 		isSkip, err := s.validateResource(ctx, eventType, resourceID, resourceType, resource)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to update resources", err, nil)
@@ -176,6 +281,7 @@ func (s *Manager) Start(port int) error {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to update resources", err, nil)
 			return
 		}
+		defer s.emitConfigApplied(eventType, resourceID, projectID, resourceType)
 
 		switch resourceType {
 		case config.ResourceProject:
@@ -208,6 +314,12 @@ func (s *Manager) Start(port int) error {
 		case config.ResourceEventingSchema:
 			_ = s.modules.SetEventingSchemaConfig(ctx, projectID, s.projectConfig.Projects[projectID].EventingSchemas)
 
+		case config.ResourceEventingSource:
+			_ = s.modules.SetEventingSourceConfig(ctx, projectID, s.projectConfig.Projects[projectID].EventingSources)
+
+		case config.ResourceEventingWorkflow:
+			_ = s.modules.SetEventingWorkflowConfig(ctx, projectID, s.projectConfig.Projects[projectID].EventingWorkflows)
+
 		case config.ResourceEventingRule:
 			_ = s.modules.SetEventingRuleConfig(ctx, projectID, s.projectConfig.Projects[projectID].EventingRules)
 
@@ -223,6 +335,21 @@ func (s *Manager) Start(port int) error {
 		case config.ResourceProjectLetsEncrypt:
 			_ = s.modules.SetLetsencryptConfig(ctx, projectID, s.projectConfig.Projects[projectID].LetsEncrypt)
 
+		case config.ResourceSearchConfig:
+			_ = s.modules.SetSearchConfig(ctx, projectID, s.projectConfig.Projects[projectID].SearchConfig)
+
+		case config.ResourceBackupConfig:
+			_ = s.modules.SetBackupConfig(ctx, projectID, s.projectConfig.Projects[projectID].BackupConfig)
+
+		case config.ResourceKVConfig:
+			_ = s.modules.SetKVConfig(ctx, projectID, s.projectConfig.Projects[projectID].KVConfig)
+
+		case config.ResourcePrivacyConfig:
+			_ = s.modules.SetPrivacyConfig(ctx, projectID, s.projectConfig.Projects[projectID].PrivacyConfig)
+
+		case config.ResourceFeatureFlag:
+			_ = s.modules.SetFeatureFlagsConfig(ctx, projectID, s.projectConfig.Projects[projectID].FeatureFlags)
+
 		case config.ResourceIngressRoute:
 			_ = s.modules.SetIngressRouteConfig(ctx, projectID, s.projectConfig.Projects[projectID].IngressRoutes)
 
@@ -232,9 +359,31 @@ func (s *Manager) Start(port int) error {
 		case config.ResourceRemoteService:
 			_ = s.modules.SetRemoteServiceConfig(ctx, projectID, s.projectConfig.Projects[projectID].RemoteService)
 
+		case config.ResourceDeploySecret:
+			// Deploy secrets are applied to the runner by the gateway which received the config request
+
 		case config.ResourceCluster:
 			s.globalModules.SetMetricsConfig(s.projectConfig.ClusterConfig.EnableTelemetry)
 			s.modules.LetsEncrypt().SetLetsEncryptEmail(s.projectConfig.ClusterConfig.LetsEncryptEmail)
+			tracing.SetConfig(s.projectConfig.ClusterConfig.Tracing)
+			if err := s.globalModules.SetLoggingConfig(s.projectConfig.ClusterConfig.Logging); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply logging config", err, nil)
+			}
+			if err := s.globalModules.SetAccountingConfig(s.projectConfig.ClusterConfig.Accounting); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply accounting config", err, nil)
+			}
+			if err := s.globalModules.SetAdmissionConfig(s.projectConfig.ClusterConfig.Admission); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply admission config", err, nil)
+			}
+			if err := s.globalModules.SetCompressionConfig(s.projectConfig.ClusterConfig.Compression); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply compression config", err, nil)
+			}
+			if err := s.globalModules.SetMetricsSinksConfig(s.projectConfig.ClusterConfig.Metrics); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply metrics config", err, nil)
+			}
+			if err := s.globalModules.SetDiagnosticsConfig(s.projectConfig.ClusterConfig.Diagnostics); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply diagnostics config", err, nil)
+			}
 
 		case config.ResourceIntegration:
 			if err := s.integrationMan.SetIntegrations(s.projectConfig.Integrations); err != nil {
@@ -251,6 +400,12 @@ func (s *Manager) Start(port int) error {
 				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to apply admin config provided by other space cloud service", err, map[string]interface{}{})
 				return
 			}
+
+		case config.ResourceProjectTemplate:
+			// Project templates are only read while creating projects
+
+		case config.ResourceAdminToken:
+			s.adminMan.SetAdminTokens(s.projectConfig.AdminTokens)
 		default:
 			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unknown resource type provided", err, map[string]interface{}{"resourceType": resourceType})
 			return
@@ -259,6 +414,13 @@ func (s *Manager) Start(port int) error {
 		return err
 	}
 
+	// Start advertising this node to the rest of the cluster
+	if err := s.sendHeartbeat(); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to send heartbeat to the cluster", err, nil)
+	}
+	s.refreshTopology()
+	go s.routineHeartbeat()
+
 	helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Exiting syncman start", nil)
 	return nil
 }
@@ -286,3 +448,25 @@ func (s *Manager) SetModules(modulesInterface ModulesInterface) {
 func (s *Manager) SetGlobalModules(a GlobalModulesInterface) {
 	s.globalModules = a
 }
+
+// ReloadSecrets re-applies the configs which can reference secrets stored in external secret managers.
+// It gets invoked when one of the referenced secrets gets rotated.
+func (s *Manager) ReloadSecrets() {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for projectID, p := range s.projectConfig.Projects {
+		if err := s.modules.SetProjectConfig(ctx, p.ProjectConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to reload project config after secret rotation", err, map[string]interface{}{"project": projectID})
+		}
+		if err := s.modules.SetDatabaseConfig(ctx, projectID, p.DatabaseConfigs, p.DatabaseSchemas, p.DatabaseRules, p.DatabasePreparedQueries); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to reload database config after secret rotation", err, map[string]interface{}{"project": projectID})
+		}
+		if err := s.modules.SetFileStoreConfig(ctx, projectID, p.FileStoreConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to reload file store config after secret rotation", err, map[string]interface{}{"project": projectID})
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package syncman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// adminEventsBuffer is the number of admin events buffered for a subscriber. Events are dropped for the subscribers
+// which can't keep up, so that a slow dashboard never holds up the cluster.
+const adminEventsBuffer = 100
+
+// SubscribeAdminEvents returns the channel the admin events of the cluster are delivered on. The channel is closed
+// once unsubscribed.
+func (s *Manager) SubscribeAdminEvents(id string) <-chan *model.AdminEvent {
+	s.lockAdminEvents.Lock()
+	defer s.lockAdminEvents.Unlock()
+
+	if s.adminEventSubs == nil {
+		s.adminEventSubs = map[string]chan *model.AdminEvent{}
+	}
+	ch := make(chan *model.AdminEvent, adminEventsBuffer)
+	s.adminEventSubs[id] = ch
+	return ch
+}
+
+// UnsubscribeAdminEvents stops delivering the admin events to the subscriber
+func (s *Manager) UnsubscribeAdminEvents(id string) {
+	s.lockAdminEvents.Lock()
+	defer s.lockAdminEvents.Unlock()
+
+	if ch, p := s.adminEventSubs[id]; p {
+		close(ch)
+		delete(s.adminEventSubs, id)
+	}
+}
+
+// PublishAdminEvent delivers an admin event to the subscribers of every node. It is used for the events observed by
+// a single node, while the ones observed by all the nodes are only delivered to the subscribers of the node itself.
+func (s *Manager) PublishAdminEvent(ctx context.Context, event *model.AdminEvent) {
+	s.fillAdminEvent(event)
+
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = s.pubsubClient.Publish(ctx, s.getAdminEventsTopic(), string(data))
+	}
+	if err != nil {
+		// The subscribers of this node get the event at least
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to broadcast admin event", err, map[string]interface{}{"type": event.Type})
+		s.emitAdminEvent(event)
+	}
+}
+
+// emitAdminEvent delivers an admin event to the subscribers of this node
+func (s *Manager) emitAdminEvent(event *model.AdminEvent) {
+	s.fillAdminEvent(event)
+
+	s.lockAdminEvents.RLock()
+	defer s.lockAdminEvents.RUnlock()
+
+	for id, ch := range s.adminEventSubs {
+		select {
+		case ch <- event:
+		default:
+			helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Dropping admin event for slow subscriber", map[string]interface{}{"subscriber": id, "type": event.Type})
+		}
+	}
+}
+
+func (s *Manager) fillAdminEvent(event *model.AdminEvent) {
+	if event.NodeID == "" {
+		event.NodeID = s.nodeID
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+}
+
+// routineAdminEvents delivers the admin events published by the nodes of the cluster to the subscribers of this node
+func (s *Manager) routineAdminEvents() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ch, err := s.pubsubClient.Subscribe(ctx, s.getAdminEventsTopic())
+	cancel()
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to subscribe to the admin events of the cluster", err, nil)
+		return
+	}
+
+	for msg := range ch {
+		event := new(model.AdminEvent)
+		if err := json.Unmarshal([]byte(msg.Payload), event); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to unmarshal admin event", err, nil)
+			continue
+		}
+		s.emitAdminEvent(event)
+	}
+}
+
+// emitServiceChanges emits the admin events for the nodes which joined or left the cluster
+func (s *Manager) emitServiceChanges(old, services model.ScServices) {
+	joined, left := getServiceChanges(old, services)
+	for _, id := range joined {
+		s.emitAdminEvent(&model.AdminEvent{Type: model.AdminEventNodeJoined, Payload: map[string]interface{}{"node": id}})
+	}
+	for _, id := range left {
+		s.emitAdminEvent(&model.AdminEvent{Type: model.AdminEventNodeLeft, Payload: map[string]interface{}{"node": id}})
+	}
+}
+
+// emitConfigApplied emits the admin event for a change of the config applied by this node
+func (s *Manager) emitConfigApplied(eventType, resourceID, projectID string, resourceType config.Resource) {
+	s.emitAdminEvent(&model.AdminEvent{
+		Type:    model.AdminEventConfigApplied,
+		Project: projectID,
+		Payload: map[string]interface{}{"event": eventType, "resourceId": resourceID, "resourceType": resourceType},
+	})
+}
+
+// getServiceChanges returns the ids of the services which were added and removed
+func getServiceChanges(old, services model.ScServices) (joined, left []string) {
+	oldIDs := make(map[string]bool, len(old))
+	for _, service := range old {
+		oldIDs[service.ID] = true
+	}
+	ids := make(map[string]bool, len(services))
+	for _, service := range services {
+		ids[service.ID] = true
+		if !oldIDs[service.ID] {
+			joined = append(joined, service.ID)
+		}
+	}
+	for _, service := range old {
+		if !ids[service.ID] {
+			left = append(left, service.ID)
+		}
+	}
+	return joined, left
+}
+
+func (s *Manager) getAdminEventsTopic() string {
+	return fmt.Sprintf("admin-events/%s", s.clusterID)
+}
@@ -0,0 +1,60 @@
+package syncman
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_getServiceChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		old        model.ScServices
+		services   model.ScServices
+		wantJoined []string
+		wantLeft   []string
+	}{
+		{name: "no change", old: model.ScServices{{ID: "1"}}, services: model.ScServices{{ID: "1"}}},
+		{name: "first services", services: model.ScServices{{ID: "1"}, {ID: "2"}}, wantJoined: []string{"1", "2"}},
+		{name: "joined and left", old: model.ScServices{{ID: "1"}, {ID: "2"}}, services: model.ScServices{{ID: "2"}, {ID: "3"}}, wantJoined: []string{"3"}, wantLeft: []string{"1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined, left := getServiceChanges(tt.old, tt.services)
+			if !reflect.DeepEqual(joined, tt.wantJoined) {
+				t.Errorf("getServiceChanges() joined = %v, want %v", joined, tt.wantJoined)
+			}
+			if !reflect.DeepEqual(left, tt.wantLeft) {
+				t.Errorf("getServiceChanges() left = %v, want %v", left, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestManager_emitAdminEvent(t *testing.T) {
+	s := &Manager{nodeID: "node"}
+	events := s.SubscribeAdminEvents("1")
+
+	s.emitServiceChanges(model.ScServices{{ID: "a"}}, model.ScServices{{ID: "b"}})
+	got := []*model.AdminEvent{<-events, <-events}
+	if got[0].Type != model.AdminEventNodeJoined || got[0].Payload["node"] != "b" || got[0].NodeID != "node" || got[0].Timestamp.IsZero() {
+		t.Errorf("emitServiceChanges() emitted %v, want node b joined", got[0])
+	}
+	if got[1].Type != model.AdminEventNodeLeft || got[1].Payload["node"] != "a" {
+		t.Errorf("emitServiceChanges() emitted %v, want node a left", got[1])
+	}
+
+	// The events are dropped for the subscribers which can't keep up
+	for i := 0; i < adminEventsBuffer+10; i++ {
+		s.emitAdminEvent(&model.AdminEvent{Type: model.AdminEventConfigApplied})
+	}
+	if len(events) != adminEventsBuffer {
+		t.Errorf("emitAdminEvent() buffered %d events, want %d", len(events), adminEventsBuffer)
+	}
+
+	s.UnsubscribeAdminEvents("1")
+	for range events {
+	}
+	s.emitAdminEvent(&model.AdminEvent{Type: model.AdminEventConfigApplied})
+}
@@ -0,0 +1,156 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// CreateAdminToken issues an admin token with the requested scopes and expiry
+func (s *Manager) CreateAdminToken(ctx context.Context, req *model.AdminTokenRequest, params model.RequestParams) (int, *model.AdminTokenResponse, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceAdminToken); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	now := time.Now().Unix()
+	if err := validateAdminToken(req, now); err != nil {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid admin token request provided", err, nil)
+	}
+
+	value := &config.AdminToken{ID: ksuid.New().String(), Name: req.Name, Scopes: req.Scopes, CreatedAt: now, ExpiresAt: req.ExpiresAt}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	resourceID := config.GenerateResourceID(s.clusterID, "noProject", config.ResourceAdminToken, value.ID)
+	if s.projectConfig.AdminTokens == nil {
+		s.projectConfig.AdminTokens = config.AdminTokens{resourceID: value}
+	} else {
+		s.projectConfig.AdminTokens[resourceID] = value
+	}
+
+	if err := s.store.SetResource(ctx, resourceID, value); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	s.adminMan.SetAdminTokens(s.projectConfig.AdminTokens)
+
+	token, err := s.adminMan.IssueAdminToken(value)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to sign admin token (%s)", value.ID), err, nil)
+	}
+
+	return http.StatusOK, &model.AdminTokenResponse{Token: token, Details: value}, nil
+}
+
+// DeleteAdminToken revokes an admin token
+func (s *Manager) DeleteAdminToken(ctx context.Context, id string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	resourceID := config.GenerateResourceID(s.clusterID, "noProject", config.ResourceAdminToken, id)
+	if _, p := s.projectConfig.AdminTokens[resourceID]; !p {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Admin token (%s) does not exist", id), nil, nil)
+	}
+
+	delete(s.projectConfig.AdminTokens, resourceID)
+	if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	s.adminMan.SetAdminTokens(s.projectConfig.AdminTokens)
+
+	return http.StatusOK, nil
+}
+
+// GetAdminTokens returns the details of the admin tokens issued in the cluster
+func (s *Manager) GetAdminTokens(ctx context.Context, id string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	// Acquire a lock
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if id != "*" {
+		token, p := s.projectConfig.AdminTokens[config.GenerateResourceID(s.clusterID, "noProject", config.ResourceAdminToken, id)]
+		if !p {
+			return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Admin token (%s) does not exist", id), nil, nil)
+		}
+		return http.StatusOK, []interface{}{token}, nil
+	}
+
+	tokens := make([]*config.AdminToken, 0, len(s.projectConfig.AdminTokens))
+	for _, token := range s.projectConfig.AdminTokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt < tokens[j].CreatedAt })
+
+	result := make([]interface{}, len(tokens))
+	for i, token := range tokens {
+		result[i] = token
+	}
+	return http.StatusOK, result, nil
+}
+
+func validateAdminToken(req *model.AdminTokenRequest, now int64) error {
+	if len(req.Scopes) == 0 {
+		return fmt.Errorf("at least one scope must be provided")
+	}
+	for _, scope := range req.Scopes {
+		valid := false
+		for _, s := range config.AdminScopes {
+			if s == scope {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown scope (%s) provided", scope)
+		}
+	}
+	if req.ExpiresAt != 0 && req.ExpiresAt <= now {
+		return fmt.Errorf("expiry must be in the future")
+	}
+	return nil
+}
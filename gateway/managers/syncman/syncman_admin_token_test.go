@@ -0,0 +1,29 @@
+package syncman
+
+import (
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_validateAdminToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *model.AdminTokenRequest
+		wantErr bool
+	}{
+		{name: "valid token", req: &model.AdminTokenRequest{Scopes: []string{config.AdminScopeConfigRead, config.AdminScopeMetricsRead}, ExpiresAt: 2000}},
+		{name: "token without expiry", req: &model.AdminTokenRequest{Scopes: []string{config.AdminScopeClusterAdmin}}},
+		{name: "no scopes", req: &model.AdminTokenRequest{}, wantErr: true},
+		{name: "unknown scope", req: &model.AdminTokenRequest{Scopes: []string{"config:delete"}}, wantErr: true},
+		{name: "expiry in the past", req: &model.AdminTokenRequest{Scopes: []string{config.AdminScopeConfigRead}, ExpiresAt: 500}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAdminToken(tt.req, 1000); (err != nil) != tt.wantErr {
+				t.Errorf("validateAdminToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
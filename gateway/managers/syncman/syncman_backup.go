@@ -0,0 +1,80 @@
+package syncman
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// SetBackupConfig sets the config of the backup module of a project
+func (s *Manager) SetBackupConfig(ctx context.Context, project string, value *config.BackupConfig, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceBackupConfig); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	projectConfig.BackupConfig = value
+
+	if err := s.modules.SetBackupConfig(ctx, project, value); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting backup config", err, nil)
+	}
+
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceBackupConfig, "backup")
+	if err := s.store.SetResource(ctx, resourceID, value); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetBackupConfig returns the config of the backup module of a project
+func (s *Manager) GetBackupConfig(ctx context.Context, project string, params model.RequestParams) (int, interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result(), nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	if projectConfig.BackupConfig == nil {
+		return http.StatusOK, config.BackupConfig{Collections: []*config.BackupCollection{}}, nil
+	}
+	return http.StatusOK, projectConfig.BackupConfig, nil
+}
@@ -0,0 +1,430 @@
+package syncman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+)
+
+// bundleResource is a single resource of a config bundle
+type bundleResource struct {
+	id           string
+	resourceType config.Resource
+	resource     interface{}
+}
+
+// ExportConfig returns a bundle of the config of all projects along with the cluster level config. The ssl
+// config is left out since it is specific to a node. Deployments are managed by the runner and aren't a part of the bundle.
+// Admin tokens are left out as well since they are signed with the admin secret of this cluster.
+func (s *Manager) ExportConfig(ctx context.Context, params model.RequestParams) (int, *model.ConfigBundle, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	// Copy the config so that the bundle can be encoded without holding the lock
+	data, err := json.Marshal(s.projectConfig)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to marshal config for export", err, nil)
+	}
+	c := new(config.Config)
+	if err := json.Unmarshal(data, c); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to copy config for export", err, nil)
+	}
+	c.SSL = nil
+	c.AdminTokens = nil
+
+	return http.StatusOK, &model.ConfigBundle{Version: model.ConfigBundleVersion, ClusterID: s.clusterID, ExportedAt: time.Now().UTC(), Config: c}, nil
+}
+
+// ImportConfig applies a config bundle to the cluster. Resources of the bundle are moved to the current cluster, which
+// allows bundles to be promoted across environments. The merge strategy adds or overwrites the resources of the bundle
+// while the replace strategy additionally deletes the projects and project resources which are absent in the bundle.
+// Cluster level config is overwritten only if it is present in the bundle.
+func (s *Manager) ImportConfig(ctx context.Context, bundle *model.ConfigBundle, strategy string, params model.RequestParams) (int, *model.ConfigImportResult, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	if strategy == "" {
+		strategy = model.ConfigImportMerge
+	}
+	if strategy != model.ConfigImportMerge && strategy != model.ConfigImportReplace {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid import strategy (%s) provided", strategy), nil, nil)
+	}
+	if bundle == nil || bundle.Config == nil {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Config bundle does not contain any config", nil, nil)
+	}
+	if bundle.Version > model.ConfigBundleVersion {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Config bundle version (%d) is not supported by this version of space cloud", bundle.Version), nil, nil)
+	}
+
+	return s.importConfig(ctx, bundle.Config, strategy)
+}
+
+// importConfig applies the config to the cluster with the provided strategy
+func (s *Manager) importConfig(ctx context.Context, c *config.Config, strategy string) (int, *model.ConfigImportResult, error) {
+	resources, err := flattenConfig(ctx, s.clusterID, c)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	existing, err := flattenConfig(ctx, s.clusterID, s.projectConfig)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	existingByID := make(map[string]*bundleResource, len(existing))
+	for _, r := range existing {
+		existingByID[r.id] = r
+	}
+
+	// Find the resources which need to be deleted before making any changes
+	result := &model.ConfigImportResult{Strategy: strategy, DeletedProjects: []string{}}
+	staleResources := make([]*bundleResource, 0)
+	if strategy == model.ConfigImportReplace {
+		ids := make(map[string]struct{}, len(resources))
+		for _, r := range resources {
+			ids[r.id] = struct{}{}
+		}
+		for projectID := range s.projectConfig.Projects {
+			if _, p := c.Projects[projectID]; !p {
+				result.DeletedProjects = append(result.DeletedProjects, projectID)
+			}
+		}
+		sort.Strings(result.DeletedProjects)
+
+		for _, r := range existing {
+			_, projectID, _, _ := splitResourceID(ctx, r.id)
+			if _, p := ids[r.id]; p || projectID == "noProject" || r.resourceType == config.ResourceProject {
+				continue
+			}
+			if _, p := c.Projects[projectID]; !p {
+				// The entire project gets deleted
+				continue
+			}
+			staleResources = append(staleResources, r)
+		}
+	}
+
+	token, err := s.adminMan.GetInternalAccessToken()
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	for _, r := range resources {
+		// Only the resources which have changed are applied
+		if cur, p := existingByID[r.id]; p && isResourceEqual(cur.resource, r.resource) {
+			result.Unchanged++
+			continue
+		}
+
+		if r.resourceType == config.ResourceProject {
+			project := r.resource.(*config.ProjectConfig)
+			if _, p := s.projectConfig.Projects[project.ID]; !p {
+				if !s.adminMan.ValidateProjectSyncOperation(s.projectConfig, project) {
+					return http.StatusUpgradeRequired, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Upgrade your plan to create more projects", nil, nil)
+				}
+
+				// Create a project in the runner as well
+				if s.runnerAddr != "" {
+					params := map[string]interface{}{"id": project.ID}
+					if err := s.MakeHTTPRequest(ctx, "POST", fmt.Sprintf("http://%s/v1/runner/project/%s", s.runnerAddr, project.ID), token, "", params, &map[string]interface{}{}); err != nil {
+						return http.StatusInternalServerError, nil, err
+					}
+				}
+			}
+		}
+
+		if err := updateResource(ctx, config.ResourceAddEvent, s.projectConfig, r.id, r.resourceType, r.resource); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		if err := s.store.SetResource(ctx, r.id, r.resource); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+		result.Applied++
+	}
+
+	for _, r := range staleResources {
+		if err := updateResource(ctx, config.ResourceDeleteEvent, s.projectConfig, r.id, r.resourceType, nil); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+		if err := s.store.DeleteResource(ctx, r.id); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+		result.Deleted++
+	}
+
+	for _, projectID := range result.DeletedProjects {
+		delete(s.projectConfig.Projects, projectID)
+		s.modules.Delete(projectID)
+		if err := s.store.DeleteProject(ctx, projectID); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+
+	// Apply the new config to the modules
+	if err := s.modules.SetInitialProjectConfig(ctx, s.projectConfig.Projects); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	if c.ClusterConfig != nil {
+		cluster := s.projectConfig.ClusterConfig
+		s.globalModules.SetMetricsConfig(cluster.EnableTelemetry)
+		s.modules.LetsEncrypt().SetLetsEncryptEmail(cluster.LetsEncryptEmail)
+		tracing.SetConfig(cluster.Tracing)
+		if err := s.globalModules.SetLoggingConfig(cluster.Logging); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply logging config", err, nil)
+		}
+		if err := s.globalModules.SetAccountingConfig(cluster.Accounting); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply accounting config", err, nil)
+		}
+		if err := s.globalModules.SetAdmissionConfig(cluster.Admission); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply admission config", err, nil)
+		}
+		if err := s.globalModules.SetCompressionConfig(cluster.Compression); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply compression config", err, nil)
+		}
+		if err := s.globalModules.SetMetricsSinksConfig(cluster.Metrics); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply metrics config", err, nil)
+		}
+		if err := s.globalModules.SetDiagnosticsConfig(cluster.Diagnostics); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply diagnostics config", err, nil)
+		}
+	}
+
+	if c.CacheConfig != nil {
+		if err := s.modules.Caching().SetCachingConfig(ctx, s.projectConfig.CacheConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply caching config", err, nil)
+		}
+	}
+
+	if len(c.Integrations) > 0 || len(c.IntegrationHooks) > 0 {
+		if err := s.integrationMan.SetConfig(s.projectConfig.Integrations, s.projectConfig.IntegrationHooks); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to apply integration config", err, nil)
+		}
+		s.adminMan.SetIntegrationConfig(s.projectConfig.Integrations)
+	}
+
+	return http.StatusOK, result, nil
+}
+
+// CompareConfig returns the resources of the cluster which differ from the provided config. Cluster level
+// resources absent in the provided config aren't reported since importing the config leaves them untouched.
+func (s *Manager) CompareConfig(ctx context.Context, c *config.Config) (*model.ConfigDrift, error) {
+	desired, err := flattenConfig(ctx, s.clusterID, c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	current, err := flattenConfig(ctx, s.clusterID, s.projectConfig)
+	s.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return compareResources(ctx, current, desired), nil
+}
+
+func compareResources(ctx context.Context, current, desired []*bundleResource) *model.ConfigDrift {
+	drift := &model.ConfigDrift{Added: []string{}, Modified: []string{}, Missing: []string{}}
+
+	currentByID := make(map[string]*bundleResource, len(current))
+	for _, r := range current {
+		currentByID[r.id] = r
+	}
+	desiredByID := make(map[string]struct{}, len(desired))
+	for _, r := range desired {
+		desiredByID[r.id] = struct{}{}
+		cur, p := currentByID[r.id]
+		switch {
+		case !p:
+			drift.Missing = append(drift.Missing, r.id)
+		case !isResourceEqual(cur.resource, r.resource):
+			drift.Modified = append(drift.Modified, r.id)
+		}
+	}
+	for _, r := range current {
+		if _, p := desiredByID[r.id]; p {
+			continue
+		}
+		if _, projectID, _, _ := splitResourceID(ctx, r.id); projectID == "noProject" {
+			continue
+		}
+		drift.Added = append(drift.Added, r.id)
+	}
+	return drift
+}
+
+// isResourceEqual compares the json representation of the resources so that numbers decoded into
+// different types compare equal
+func isResourceEqual(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// flattenConfig returns the resources of the config with their ids moved to the provided cluster. Resources
+// are ordered such that the ones a resource depends on come before it.
+func flattenConfig(ctx context.Context, clusterID string, c *config.Config) ([]*bundleResource, error) {
+	resources := make([]*bundleResource, 0)
+	add := func(projectID string, resourceType config.Resource, id string, resource interface{}) error {
+		_, p, rt, err := splitResourceID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if p != projectID || rt != resourceType {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Resource id (%s) does not belong to project (%s) and resource type (%s)", id, projectID, resourceType), nil, nil)
+		}
+		resources = append(resources, &bundleResource{id: rebaseResourceID(clusterID, id), resourceType: resourceType, resource: resource})
+		return nil
+	}
+
+	if c.ClusterConfig != nil {
+		_ = add("noProject", config.ResourceCluster, config.GenerateResourceID(clusterID, "noProject", config.ResourceCluster, "cluster"), c.ClusterConfig)
+	}
+	if c.CacheConfig != nil {
+		_ = add("noProject", config.ResourceCacheConfig, config.GenerateResourceID(clusterID, "noProject", config.ResourceCacheConfig, "cache"), c.CacheConfig)
+	}
+	for _, id := range sortedKeys(c.Integrations) {
+		if err := add("noProject", config.ResourceIntegration, id, c.Integrations[id]); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range sortedKeys(c.IntegrationHooks) {
+		if err := add("noProject", config.ResourceIntegrationHook, id, c.IntegrationHooks[id]); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range sortedKeys(c.ProjectTemplates) {
+		if err := add("noProject", config.ResourceProjectTemplate, id, c.ProjectTemplates[id]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, projectID := range sortedKeys(c.Projects) {
+		project := c.Projects[projectID]
+		if project == nil || project.ProjectConfig == nil || project.ProjectConfig.ID != projectID {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project config of project (%s) is either missing or has a different id", projectID), nil, nil)
+		}
+
+		if err := add(projectID, config.ResourceProject, config.GenerateResourceID(clusterID, projectID, config.ResourceProject, projectID), project.ProjectConfig); err != nil {
+			return nil, err
+		}
+
+		type group struct {
+			resourceType config.Resource
+			ids          []string
+			get          func(id string) interface{}
+		}
+		groups := []group{
+			{config.ResourceDatabaseConfig, sortedKeys(project.DatabaseConfigs), func(id string) interface{} { return project.DatabaseConfigs[id] }},
+			{config.ResourceDatabaseSchema, sortedKeys(project.DatabaseSchemas), func(id string) interface{} { return project.DatabaseSchemas[id] }},
+			{config.ResourceDatabaseRule, sortedKeys(project.DatabaseRules), func(id string) interface{} { return project.DatabaseRules[id] }},
+			{config.ResourceDatabasePreparedQuery, sortedKeys(project.DatabasePreparedQueries), func(id string) interface{} { return project.DatabasePreparedQueries[id] }},
+			{config.ResourceEventingSchema, sortedKeys(project.EventingSchemas), func(id string) interface{} { return project.EventingSchemas[id] }},
+			{config.ResourceEventingRule, sortedKeys(project.EventingRules), func(id string) interface{} { return project.EventingRules[id] }},
+			{config.ResourceEventingTrigger, sortedKeys(project.EventingTriggers), func(id string) interface{} { return project.EventingTriggers[id] }},
+			{config.ResourceEventingSource, sortedKeys(project.EventingSources), func(id string) interface{} { return project.EventingSources[id] }},
+			{config.ResourceEventingWorkflow, sortedKeys(project.EventingWorkflows), func(id string) interface{} { return project.EventingWorkflows[id] }},
+			{config.ResourceFileStoreRule, sortedKeys(project.FileStoreRules), func(id string) interface{} { return project.FileStoreRules[id] }},
+			{config.ResourceAuthProvider, sortedKeys(project.Auths), func(id string) interface{} { return project.Auths[id] }},
+			{config.ResourceIngressRoute, sortedKeys(project.IngressRoutes), func(id string) interface{} { return project.IngressRoutes[id] }},
+			{config.ResourceFeatureFlag, sortedKeys(project.FeatureFlags), func(id string) interface{} { return project.FeatureFlags[id] }},
+		}
+		if project.EventingConfig != nil {
+			groups = append(groups, group{config.ResourceEventingConfig, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceEventingConfig, "eventing")}, func(string) interface{} { return project.EventingConfig }})
+		}
+		if project.FileStoreConfig != nil {
+			groups = append(groups, group{config.ResourceFileStoreConfig, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceFileStoreConfig, "filestore")}, func(string) interface{} { return project.FileStoreConfig }})
+		}
+		if project.LetsEncrypt != nil {
+			groups = append(groups, group{config.ResourceProjectLetsEncrypt, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceProjectLetsEncrypt, "letsencrypt")}, func(string) interface{} { return project.LetsEncrypt }})
+		}
+		if project.SearchConfig != nil {
+			groups = append(groups, group{config.ResourceSearchConfig, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceSearchConfig, "search")}, func(string) interface{} { return project.SearchConfig }})
+		}
+		if project.BackupConfig != nil {
+			groups = append(groups, group{config.ResourceBackupConfig, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceBackupConfig, "backup")}, func(string) interface{} { return project.BackupConfig }})
+		}
+		if project.KVConfig != nil {
+			groups = append(groups, group{config.ResourceKVConfig, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceKVConfig, "kv")}, func(string) interface{} { return project.KVConfig }})
+		}
+		if project.PrivacyConfig != nil {
+			groups = append(groups, group{config.ResourcePrivacyConfig, []string{config.GenerateResourceID(clusterID, projectID, config.ResourcePrivacyConfig, "privacy")}, func(string) interface{} { return project.PrivacyConfig }})
+		}
+		if project.IngressGlobal != nil {
+			groups = append(groups, group{config.ResourceIngressGlobal, []string{config.GenerateResourceID(clusterID, projectID, config.ResourceIngressGlobal, "global")}, func(string) interface{} { return project.IngressGlobal }})
+		}
+		if project.RemoteService != nil {
+			groups = append(groups, group{config.ResourceRemoteService, sortedKeys(project.RemoteService), func(id string) interface{} { return project.RemoteService[id] }})
+		}
+		if project.DeploySecrets != nil {
+			groups = append(groups, group{config.ResourceDeploySecret, sortedKeys(project.DeploySecrets), func(id string) interface{} { return project.DeploySecrets[id] }})
+		}
+
+		for _, g := range groups {
+			for _, id := range g.ids {
+				if err := add(projectID, g.resourceType, id, g.get(id)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// rebaseResourceID replaces the cluster id of the resource id
+func rebaseResourceID(clusterID, id string) string {
+	arr := strings.SplitN(id, "--", 2)
+	if len(arr) < 2 {
+		return id
+	}
+	return clusterID + "--" + arr[1]
+}
+
+// sortedKeys returns the keys of a map with string keys in a sorted order
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	arr := make([]string, len(keys))
+	for i, key := range keys {
+		arr[i] = key.String()
+	}
+	sort.Strings(arr)
+	return arr
+}
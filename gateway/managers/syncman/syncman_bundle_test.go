@@ -0,0 +1,92 @@
+package syncman
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestFlattenConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *config.Config
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "resources are moved to the new cluster in dependency order",
+			config: &config.Config{
+				ClusterConfig: &config.ClusterConfig{EnableTelemetry: true},
+				Projects: config.Projects{
+					"myproject": {
+						ProjectConfig:   &config.ProjectConfig{ID: "myproject"},
+						DatabaseConfigs: config.DatabaseConfigs{"old--myproject--db-config--db": &config.DatabaseConfig{DbAlias: "db"}},
+						DatabaseRules:   config.DatabaseRules{"old--myproject--db-rule--db-users-rule": &config.DatabaseRule{}},
+						DatabaseSchemas: config.DatabaseSchemas{"old--myproject--db-schema--db-users": &config.DatabaseSchema{}},
+						FileStoreConfig: &config.FileStoreConfig{Enabled: true},
+					},
+				},
+			},
+			want: []string{
+				"new--noProject--cluster--cluster",
+				"new--myproject--project--myproject",
+				"new--myproject--db-config--db",
+				"new--myproject--db-schema--db-users",
+				"new--myproject--db-rule--db-users-rule",
+				"new--myproject--filestore-config--filestore",
+			},
+		},
+		{
+			name: "project config with a different id",
+			config: &config.Config{
+				Projects: config.Projects{"myproject": {ProjectConfig: &config.ProjectConfig{ID: "other"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "resource of another project",
+			config: &config.Config{
+				Projects: config.Projects{
+					"myproject": {
+						ProjectConfig:   &config.ProjectConfig{ID: "myproject"},
+						DatabaseConfigs: config.DatabaseConfigs{"old--other--db-config--db": &config.DatabaseConfig{DbAlias: "db"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "resource with an invalid id",
+			config: &config.Config{
+				Projects: config.Projects{
+					"myproject": {
+						ProjectConfig:  &config.ProjectConfig{ID: "myproject"},
+						FileStoreRules: config.FileStoreRules{"rule": &config.FileRule{}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := flattenConfig(context.Background(), "new", tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("flattenConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, len(resources))
+			for i, r := range resources {
+				got[i] = r.id
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flattenConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
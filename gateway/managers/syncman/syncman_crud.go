@@ -98,15 +98,29 @@ func (s *Manager) SetDatabaseConnection(ctx context.Context, project, dbAlias st
 	v.DbAlias = dbAlias
 	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseConfig, dbAlias)
 	if projectConfig.DatabaseConfigs == nil {
-		projectConfig.DatabaseConfigs = config.DatabaseConfigs{resourceID: v}
-	} else {
-		projectConfig.DatabaseConfigs[resourceID] = v
+		projectConfig.DatabaseConfigs = config.DatabaseConfigs{}
 	}
+	prevConfig, hadConfig := projectConfig.DatabaseConfigs[resourceID]
+	projectConfig.DatabaseConfigs[resourceID] = v
 
 	if err := s.modules.SetDatabaseConfig(ctx, project, projectConfig.DatabaseConfigs, projectConfig.DatabaseSchemas, projectConfig.DatabaseRules, projectConfig.DatabasePreparedQueries); err != nil {
 		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set crud config", err, nil)
 	}
 
+	// The existing tables get their rules pushed down once row level security is enabled. This needs the connection
+	// to the database, hence the previous config is restored if it fails.
+	if err := s.applyRowLevelSecurityToAll(ctx, projectConfig, project, dbAlias); err != nil {
+		if hadConfig {
+			projectConfig.DatabaseConfigs[resourceID] = prevConfig
+		} else {
+			delete(projectConfig.DatabaseConfigs, resourceID)
+		}
+		if err := s.modules.SetDatabaseConfig(ctx, project, projectConfig.DatabaseConfigs, projectConfig.DatabaseSchemas, projectConfig.DatabaseRules, projectConfig.DatabasePreparedQueries); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to restore crud config", err, nil)
+		}
+		return http.StatusInternalServerError, err
+	}
+
 	if err := s.store.SetResource(ctx, resourceID, v); err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -380,6 +394,11 @@ func (s *Manager) SetModifySchema(ctx context.Context, project, dbAlias, col str
 		return http.StatusInternalServerError, err
 	}
 
+	// The table might have just been created
+	if err := s.applyRowLevelSecurity(ctx, projectConfig, project, dbAlias, col); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
 	if projectConfig.DatabaseSchemas == nil {
 		projectConfig.DatabaseSchemas = config.DatabaseSchemas{resourceID: v}
 	} else {
@@ -429,13 +448,37 @@ func (s *Manager) setCollectionRules(ctx context.Context, projectConfig *config.
 		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to set collection/table rules as provided db alias (%s) does not exists", dbAlias), nil, nil)
 	}
 
+	for _, mask := range v.Masks {
+		if mask.Field == "" {
+			return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field of a mask of collection/table (%s) not provided", col), nil, nil)
+		}
+		switch mask.Type {
+		case config.FieldMaskNull, config.FieldMaskHash, config.FieldMaskPartial:
+		default:
+			return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type (%s) provided for the mask of field (%s)", mask.Type, mask.Field), nil, nil)
+		}
+		if mask.Visible < 0 {
+			return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Number of visible characters of the mask of field (%s) cannot be negative", mask.Field), nil, nil)
+		}
+	}
+
 	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseRule, dbAlias, col, "rule")
 	v.Table = col
 	v.DbAlias = dbAlias
 	if projectConfig.DatabaseRules == nil {
-		projectConfig.DatabaseRules = config.DatabaseRules{resourceID: v}
-	} else {
-		projectConfig.DatabaseRules[resourceID] = v
+		projectConfig.DatabaseRules = config.DatabaseRules{}
+	}
+	prevRule, hadRule := projectConfig.DatabaseRules[resourceID]
+	projectConfig.DatabaseRules[resourceID] = v
+
+	// Push the rules down to the database before the modules start enforcing them
+	if err := s.applyRowLevelSecurity(ctx, projectConfig, project, dbAlias, col); err != nil {
+		if hadRule {
+			projectConfig.DatabaseRules[resourceID] = prevRule
+		} else {
+			delete(projectConfig.DatabaseRules, resourceID)
+		}
+		return http.StatusInternalServerError, err
 	}
 
 	if err := s.modules.SetDatabaseRulesConfig(ctx, project, projectConfig.DatabaseRules); err != nil {
@@ -477,13 +520,19 @@ func (s *Manager) DeleteCollectionRules(ctx context.Context, project, dbAlias, c
 	}
 
 	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseRule, dbAlias, col, "rule")
-	_, ok := projectConfig.DatabaseRules[resourceID]
+	prevRule, ok := projectConfig.DatabaseRules[resourceID]
 	if !ok {
 		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete collection rules as provided table or collection (%s) does not exists", col), nil, nil)
 	}
 
 	delete(projectConfig.DatabaseRules, resourceID)
 
+	// The table falls back to the default rules of the database
+	if err := s.applyRowLevelSecurity(ctx, projectConfig, project, dbAlias, col); err != nil {
+		projectConfig.DatabaseRules[resourceID] = prevRule
+		return http.StatusInternalServerError, err
+	}
+
 	if err := s.modules.SetDatabaseRulesConfig(ctx, project, projectConfig.DatabaseRules); err != nil {
 		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set database rules config", err, nil)
 	}
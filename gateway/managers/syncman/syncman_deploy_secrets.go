@@ -0,0 +1,298 @@
+package syncman
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// maskedSecretValue replaces the values of the secrets returned by the config apis
+const maskedSecretValue = "********"
+
+// SetDeploySecret creates or replaces a secret of the deployed services. The values of the secret are encrypted with
+// the aes key of the project before being stored, and the secret is applied to the runner.
+func (s *Manager) SetDeploySecret(ctx context.Context, project string, value *config.DeploySecret, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceDeploySecret); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if err := validateDeploySecret(value); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid secret (%s) provided", value.ID), err, nil)
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	aesKey, err := getProjectAESKey(projectConfig)
+	if err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to encrypt secret", err, nil)
+	}
+
+	sealed := &config.DeploySecret{ID: value.ID, Type: value.Type, RootPath: value.RootPath, Data: make(map[string]string, len(value.Data))}
+	for k, v := range value.Data {
+		sealedValue, err := utils.Seal(aesKey, v)
+		if err != nil {
+			return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to encrypt secret", err, nil)
+		}
+		sealed.Data[k] = sealedValue
+	}
+
+	// Apply the secret to the runner first so that the stored config doesn't hold secrets the runner rejected
+	if err := s.applyDeploySecretToRunner(ctx, project, value); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDeploySecret, value.ID)
+	if projectConfig.DeploySecrets == nil {
+		projectConfig.DeploySecrets = config.DeploySecrets{resourceID: sealed}
+	} else {
+		projectConfig.DeploySecrets[resourceID] = sealed
+	}
+
+	if err := s.store.SetResource(ctx, resourceID, sealed); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// DeleteDeploySecret deletes a secret of the deployed services
+func (s *Manager) DeleteDeploySecret(ctx context.Context, project, id string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceDeploySecret, id)
+	if _, p := projectConfig.DeploySecrets[resourceID]; !p {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Secret with id (%s) does not exist", id), nil, nil)
+	}
+
+	if s.runnerAddr != "" {
+		token, err := s.adminMan.GetInternalAccessToken()
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if err := s.MakeHTTPRequest(ctx, http.MethodDelete, fmt.Sprintf("http://%s/v1/runner/%s/secrets/%s", s.runnerAddr, project, id), token, "", map[string]interface{}{}, &map[string]interface{}{}); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	delete(projectConfig.DeploySecrets, resourceID)
+	if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetDeploySecrets returns the secrets of the deployed services. The values of the secrets are masked.
+func (s *Manager) GetDeploySecrets(ctx context.Context, project, id string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	// Acquire a lock
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	if id != "*" {
+		secret, ok := projectConfig.DeploySecrets[config.GenerateResourceID(s.clusterID, project, config.ResourceDeploySecret, id)]
+		if !ok {
+			return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Secret with id (%s) does not exist", id), nil, nil)
+		}
+		return http.StatusOK, []interface{}{maskDeploySecret(secret)}, nil
+	}
+
+	secrets := make([]*config.DeploySecret, 0, len(projectConfig.DeploySecrets))
+	for _, secret := range projectConfig.DeploySecrets {
+		secrets = append(secrets, maskDeploySecret(secret))
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].ID < secrets[j].ID })
+
+	result := make([]interface{}, len(secrets))
+	for i, secret := range secrets {
+		result[i] = secret
+	}
+	return http.StatusOK, result, nil
+}
+
+// applyReferencedDeploySecrets applies the secrets referred to by the tasks of a service to the runner before the
+// service gets applied. This makes sure the runner has the secrets even if they were created before the runner was
+// set up or were lost by it.
+func (s *Manager) applyReferencedDeploySecrets(ctx context.Context, project string, service []byte) error {
+	var spec struct {
+		Tasks []struct {
+			Secrets []string `json:"secrets"`
+			Docker  struct {
+				Secret string `json:"secret"`
+			} `json:"docker"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(service, &spec); err != nil {
+		// The runner is responsible for validating the service
+		return nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil || len(projectConfig.DeploySecrets) == 0 {
+		return nil
+	}
+
+	applied := map[string]struct{}{}
+	for _, task := range spec.Tasks {
+		names := append([]string{}, task.Secrets...)
+		if task.Docker.Secret != "" {
+			names = append(names, task.Docker.Secret)
+		}
+
+		for _, name := range names {
+			if _, p := applied[name]; p {
+				continue
+			}
+			applied[name] = struct{}{}
+
+			// Secrets which aren't a part of the config may have been created on the runner directly
+			sealed, p := projectConfig.DeploySecrets[config.GenerateResourceID(s.clusterID, project, config.ResourceDeploySecret, name)]
+			if !p {
+				continue
+			}
+			secret, err := unsealDeploySecret(projectConfig, sealed)
+			if err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to decrypt secret (%s)", name), err, nil)
+			}
+			if err := s.applyDeploySecretToRunner(ctx, project, secret); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyDeploySecretToRunner creates or replaces the secret in the runner
+func (s *Manager) applyDeploySecretToRunner(ctx context.Context, project string, secret *config.DeploySecret) error {
+	if s.runnerAddr == "" {
+		return nil
+	}
+
+	token, err := s.adminMan.GetInternalAccessToken()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("http://%s/v1/runner/%s/secrets/%s", s.runnerAddr, project, secret.ID)
+	if err := s.MakeHTTPRequest(ctx, http.MethodPost, url, token, "", secret, &map[string]interface{}{}); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to apply secret (%s) to the runner", secret.ID), err, nil)
+	}
+	return nil
+}
+
+func validateDeploySecret(secret *config.DeploySecret) error {
+	switch secret.Type {
+	case "env":
+	case "file":
+		if secret.RootPath == "" {
+			return fmt.Errorf("root path is required for secrets of type file")
+		}
+	case "docker":
+		for _, key := range []string{"username", "password", "url"} {
+			if _, p := secret.Data[key]; !p {
+				return fmt.Errorf("key (%s) is required for secrets of type docker", key)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid secret type (%s) provided - it must be one of file, env or docker", secret.Type)
+	}
+	return nil
+}
+
+func getProjectAESKey(projectConfig *config.Project) ([]byte, error) {
+	if projectConfig.ProjectConfig == nil || projectConfig.ProjectConfig.AESKey == "" {
+		return nil, fmt.Errorf("aes key of the project is not set")
+	}
+	return base64.StdEncoding.DecodeString(projectConfig.ProjectConfig.AESKey)
+}
+
+func unsealDeploySecret(projectConfig *config.Project, sealed *config.DeploySecret) (*config.DeploySecret, error) {
+	aesKey, err := getProjectAESKey(projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &config.DeploySecret{ID: sealed.ID, Type: sealed.Type, RootPath: sealed.RootPath, Data: make(map[string]string, len(sealed.Data))}
+	for k, v := range sealed.Data {
+		value, err := utils.Unseal(aesKey, v)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[k] = value
+	}
+	return secret, nil
+}
+
+func maskDeploySecret(secret *config.DeploySecret) *config.DeploySecret {
+	masked := &config.DeploySecret{ID: secret.ID, Type: secret.Type, RootPath: secret.RootPath, Data: make(map[string]string, len(secret.Data))}
+	for k := range secret.Data {
+		masked.Data[k] = maskedSecretValue
+	}
+	return masked
+}
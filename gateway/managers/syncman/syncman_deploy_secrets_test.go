@@ -0,0 +1,50 @@
+package syncman
+
+import (
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_validateDeploySecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  *config.DeploySecret
+		wantErr bool
+	}{
+		{
+			name:   "Env secret",
+			secret: &config.DeploySecret{ID: "db", Type: "env", Data: map[string]string{"PASSWORD": "pass"}},
+		},
+		{
+			name:   "File secret",
+			secret: &config.DeploySecret{ID: "certs", Type: "file", RootPath: "/certs", Data: map[string]string{"tls.crt": "crt"}},
+		},
+		{
+			name:    "File secret without root path",
+			secret:  &config.DeploySecret{ID: "certs", Type: "file", Data: map[string]string{"tls.crt": "crt"}},
+			wantErr: true,
+		},
+		{
+			name:   "Docker secret",
+			secret: &config.DeploySecret{ID: "registry", Type: "docker", Data: map[string]string{"username": "user", "password": "pass", "url": "https://registry.example.com"}},
+		},
+		{
+			name:    "Docker secret without url",
+			secret:  &config.DeploySecret{ID: "registry", Type: "docker", Data: map[string]string{"username": "user", "password": "pass"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid type",
+			secret:  &config.DeploySecret{ID: "db", Type: "vault"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateDeploySecret(tt.secret); (err != nil) != tt.wantErr {
+				t.Errorf("validateDeploySecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
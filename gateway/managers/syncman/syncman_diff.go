@@ -0,0 +1,217 @@
+package syncman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// secretFields are the fields whose values are masked in a config diff
+var secretFields = map[string]struct{}{"conn": {}, "secret": {}, "aesKey": {}, "privateKey": {}, "password": {}}
+
+// DiffProjectConfig returns the changes which applying the provided config would make to the live config of a project.
+// The changes between two versions of the config are returned instead if a base config is provided.
+func (s *Manager) DiffProjectConfig(ctx context.Context, projectID string, req *model.ConfigDiffRequest, params model.RequestParams) (int, *model.ConfigDiff, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	if req.Config == nil {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Config to compare must be provided", nil, nil)
+	}
+
+	desired, err := s.flattenProjectConfig(ctx, projectID, req.Config)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	var current []*bundleResource
+	if req.From != nil {
+		current, err = s.flattenProjectConfig(ctx, projectID, req.From)
+		if err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+	} else {
+		s.lock.RLock()
+		project, p := s.projectConfig.Projects[projectID]
+		if p {
+			current, err = flattenConfig(ctx, s.clusterID, &config.Config{Projects: config.Projects{projectID: project}})
+		}
+		s.lock.RUnlock()
+		if err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+
+	return http.StatusOK, diffResources(projectID, current, desired), nil
+}
+
+// flattenProjectConfig returns the resources of the config of a single project
+func (s *Manager) flattenProjectConfig(ctx context.Context, projectID string, project *config.Project) ([]*bundleResource, error) {
+	if project.ProjectConfig == nil {
+		project.ProjectConfig = &config.ProjectConfig{ID: projectID}
+	}
+	if project.ProjectConfig.ID == "" {
+		project.ProjectConfig.ID = projectID
+	}
+	if project.ProjectConfig.ID != projectID {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Config of project (%s) cannot be compared with project (%s)", project.ProjectConfig.ID, projectID), nil, nil)
+	}
+	return flattenConfig(ctx, s.clusterID, &config.Config{Projects: config.Projects{projectID: project}})
+}
+
+// diffResources returns the changes between two versions of the resources of a project grouped by module
+func diffResources(projectID string, from, to []*bundleResource) *model.ConfigDiff {
+	diff := &model.ConfigDiff{Project: projectID, Modules: map[string][]*model.ResourceDiff{}}
+	add := func(r *bundleResource, change string, fields []*model.FieldDiff) {
+		module := getResourceModule(r.resourceType)
+		diff.Modules[module] = append(diff.Modules[module], &model.ResourceDiff{ID: r.id, Type: r.resourceType, Change: change, Fields: fields})
+	}
+
+	fromByID := make(map[string]*bundleResource, len(from))
+	for _, r := range from {
+		fromByID[r.id] = r
+	}
+	toByID := make(map[string]struct{}, len(to))
+	for _, r := range to {
+		toByID[r.id] = struct{}{}
+		old, p := fromByID[r.id]
+		switch {
+		case !p:
+			add(r, model.ConfigChangeAdded, nil)
+			diff.Added++
+		case !isResourceEqual(old.resource, r.resource):
+			add(r, model.ConfigChangeModified, diffFields(r.resourceType, old.resource, r.resource))
+			diff.Modified++
+		}
+	}
+	for _, r := range from {
+		if _, p := toByID[r.id]; !p {
+			add(r, model.ConfigChangeRemoved, nil)
+			diff.Removed++
+		}
+	}
+
+	for _, resources := range diff.Modules {
+		sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+	}
+	diff.HasChanges = diff.Added+diff.Modified+diff.Removed > 0
+	return diff
+}
+
+// diffFields returns the fields which differ between two versions of a resource
+func diffFields(resourceType config.Resource, a, b interface{}) []*model.FieldDiff {
+	fields := make([]*model.FieldDiff, 0)
+	var walk func(path string, a, b interface{})
+	walk = func(path string, a, b interface{}) {
+		mapA, okA := a.(map[string]interface{})
+		mapB, okB := b.(map[string]interface{})
+		if okA && okB {
+			keys := make(map[string]struct{}, len(mapA)+len(mapB))
+			for k := range mapA {
+				keys[k] = struct{}{}
+			}
+			for k := range mapB {
+				keys[k] = struct{}{}
+			}
+			for _, k := range sortedKeys(keys) {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				walk(p, mapA[k], mapB[k])
+			}
+			return
+		}
+		if reflect.DeepEqual(a, b) {
+			return
+		}
+		if isSecretField(resourceType, path) {
+			a, b = maskDiffValue(a), maskDiffValue(b)
+		}
+		fields = append(fields, &model.FieldDiff{Path: path, Old: a, New: b})
+	}
+	walk("", toGenericValue(a), toGenericValue(b))
+	return fields
+}
+
+// toGenericValue returns the json representation of the value made of maps, slices and scalars
+func toGenericValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	_ = json.Unmarshal(data, &generic)
+	return generic
+}
+
+func isSecretField(resourceType config.Resource, path string) bool {
+	if resourceType == config.ResourceDeploySecret && strings.HasPrefix(path, "data") {
+		return true
+	}
+	for _, field := range strings.Split(path, ".") {
+		if _, p := secretFields[field]; p {
+			return true
+		}
+	}
+	return false
+}
+
+func maskDiffValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return maskedSecretValue
+}
+
+// getResourceModule returns the module a resource type belongs to
+func getResourceModule(resourceType config.Resource) string {
+	switch resourceType {
+	case config.ResourceDatabaseConfig, config.ResourceDatabaseSchema, config.ResourceDatabaseRule, config.ResourceDatabasePreparedQuery:
+		return "database"
+	case config.ResourceEventingConfig, config.ResourceEventingSchema, config.ResourceEventingRule, config.ResourceEventingTrigger, config.ResourceEventingSource, config.ResourceEventingWorkflow:
+		return "eventing"
+	case config.ResourceFileStoreConfig, config.ResourceFileStoreRule:
+		return "filestore"
+	case config.ResourceAuthProvider:
+		return "auth"
+	case config.ResourceIngressRoute, config.ResourceIngressGlobal:
+		return "ingress"
+	case config.ResourceRemoteService:
+		return "remote-services"
+	case config.ResourceDeploySecret:
+		return "deployments"
+	case config.ResourceProjectLetsEncrypt:
+		return "letsencrypt"
+	case config.ResourceSearchConfig:
+		return "search"
+	case config.ResourceBackupConfig:
+		return "backup"
+	case config.ResourceKVConfig:
+		return "kv"
+	case config.ResourcePrivacyConfig:
+		return "privacy"
+	case config.ResourceFeatureFlag:
+		return "feature-flags"
+	default:
+		return "project"
+	}
+}
@@ -0,0 +1,50 @@
+package syncman
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_diffResources(t *testing.T) {
+	from := []*bundleResource{
+		{id: "chicago--myproject--db-config--db", resourceType: config.ResourceDatabaseConfig, resource: &config.DatabaseConfig{DbAlias: "db", Type: "postgres", Conn: "postgres://old", Enabled: true}},
+		{id: "chicago--myproject--db-rule--db-users-rule", resourceType: config.ResourceDatabaseRule, resource: &config.DatabaseRule{Table: "users", DbAlias: "db", Rules: map[string]*config.Rule{"read": {Rule: "allow"}}}},
+		{id: "chicago--myproject--eventing-trigger--welcome", resourceType: config.ResourceEventingTrigger, resource: &config.EventingTrigger{ID: "welcome", Type: "DB_INSERT", URL: "http://mailer"}},
+	}
+	to := []*bundleResource{
+		{id: "chicago--myproject--db-config--db", resourceType: config.ResourceDatabaseConfig, resource: &config.DatabaseConfig{DbAlias: "db", Type: "postgres", Conn: "postgres://new", Enabled: true}},
+		{id: "chicago--myproject--db-rule--db-users-rule", resourceType: config.ResourceDatabaseRule, resource: &config.DatabaseRule{Table: "users", DbAlias: "db", Rules: map[string]*config.Rule{"read": {Rule: "authenticated"}}}},
+		{id: "chicago--myproject--filestore-rule--images", resourceType: config.ResourceFileStoreRule, resource: &config.FileRule{ID: "images", Prefix: "/images"}},
+	}
+
+	want := &model.ConfigDiff{
+		Project:    "myproject",
+		HasChanges: true,
+		Added:      1,
+		Modified:   2,
+		Removed:    1,
+		Modules: map[string][]*model.ResourceDiff{
+			"database": {
+				{ID: "chicago--myproject--db-config--db", Type: config.ResourceDatabaseConfig, Change: model.ConfigChangeModified, Fields: []*model.FieldDiff{{Path: "conn", Old: maskedSecretValue, New: maskedSecretValue}}},
+				{ID: "chicago--myproject--db-rule--db-users-rule", Type: config.ResourceDatabaseRule, Change: model.ConfigChangeModified, Fields: []*model.FieldDiff{{Path: "rules.read.rule", Old: "allow", New: "authenticated"}}},
+			},
+			"eventing": {
+				{ID: "chicago--myproject--eventing-trigger--welcome", Type: config.ResourceEventingTrigger, Change: model.ConfigChangeRemoved},
+			},
+			"filestore": {
+				{ID: "chicago--myproject--filestore-rule--images", Type: config.ResourceFileStoreRule, Change: model.ConfigChangeAdded},
+			},
+		},
+	}
+
+	if got := diffResources("myproject", from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffResources() = %v, want %v", got, want)
+	}
+
+	if got := diffResources("myproject", from, from); got.HasChanges || len(got.Modules) != 0 {
+		t.Errorf("diffResources() reported changes between identical configs: %v", got)
+	}
+}
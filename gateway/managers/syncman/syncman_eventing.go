@@ -92,7 +92,7 @@ func (s *Manager) SetDeleteEventingRule(ctx context.Context, project, ruleName s
 }
 
 // SetEventingConfig sets the eventing config
-func (s *Manager) SetEventingConfig(ctx context.Context, project, dbAlias string, enabled bool, params model.RequestParams) (int, error) {
+func (s *Manager) SetEventingConfig(ctx context.Context, project, dbAlias string, enabled bool, retention *config.EventingRetention, dlqAlert *config.EventingDLQAlert, params model.RequestParams) (int, error) {
 	// Check if the request has been hijacked
 	hookResponse := s.integrationMan.InvokeHook(ctx, params)
 	if hookResponse.CheckResponse() {
@@ -114,6 +114,11 @@ func (s *Manager) SetEventingConfig(ctx context.Context, project, dbAlias string
 		return http.StatusBadRequest, err
 	}
 
+	return s.setEventingConfig(ctx, projectConfig, project, dbAlias, enabled, retention, dlqAlert)
+}
+
+// setEventingConfig applies the eventing config to the provided project config. The eventing tables are created if eventing gets enabled.
+func (s *Manager) setEventingConfig(ctx context.Context, projectConfig *config.Project, project, dbAlias string, enabled bool, retention *config.EventingRetention, dlqAlert *config.EventingDLQAlert) (int, error) {
 	dbConfig, p := s.checkIfDbAliasExists(projectConfig.DatabaseConfigs, dbAlias)
 	if !p && enabled {
 		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unknown db alias (%s) provided while setting eventing config", dbAlias), nil, nil)
@@ -121,6 +126,8 @@ func (s *Manager) SetEventingConfig(ctx context.Context, project, dbAlias string
 
 	projectConfig.EventingConfig.DBAlias = dbAlias
 	projectConfig.EventingConfig.Enabled = enabled
+	projectConfig.EventingConfig.Retention = retention
+	projectConfig.EventingConfig.DLQAlert = dlqAlert
 
 	if err := s.modules.SetEventingConfig(ctx, project, projectConfig.EventingConfig, projectConfig.EventingRules, projectConfig.EventingSchemas, projectConfig.EventingTriggers); err != nil {
 		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting eventing config", err, nil)
@@ -131,6 +138,7 @@ func (s *Manager) SetEventingConfig(ctx context.Context, project, dbAlias string
 			Collections: map[string]*config.TableRule{
 				utils.TableEventingLogs:   {Schema: utils.SchemaEventLogs, Rules: map[string]*config.Rule{"create": {Rule: "deny"}, "read": {Rule: "deny"}, "update": {Rule: "deny"}, "delete": {Rule: "deny"}}},
 				utils.TableInvocationLogs: {Schema: utils.SchemaInvocationLogs, Rules: map[string]*config.Rule{"create": {Rule: "deny"}, "read": {Rule: "deny"}, "update": {Rule: "deny"}, "delete": {Rule: "deny"}}},
+				utils.TableWorkflowRuns:   {Schema: utils.SchemaWorkflowRuns, Rules: map[string]*config.Rule{"create": {Rule: "deny"}, "read": {Rule: "deny"}, "update": {Rule: "deny"}, "delete": {Rule: "deny"}}},
 			},
 			DBName: dbConfig.DBName,
 		}); err != nil {
@@ -144,6 +152,10 @@ func (s *Manager) SetEventingConfig(ctx context.Context, project, dbAlias string
 		if err != nil {
 			return status, err
 		}
+		status, err = s.setCollectionRules(ctx, projectConfig, project, dbAlias, utils.TableWorkflowRuns, &config.DatabaseRule{Rules: map[string]*config.Rule{"create": {Rule: "deny"}, "read": {Rule: "deny"}, "update": {Rule: "deny"}, "delete": {Rule: "deny"}}})
+		if err != nil {
+			return status, err
+		}
 	}
 
 	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingConfig, "eventing")
@@ -453,3 +465,245 @@ func (s *Manager) GetEventingSecurityRules(ctx context.Context, project, id stri
 	}
 	return http.StatusOK, services, nil
 }
+
+// SetEventingSource sets a custom event source of eventing
+func (s *Manager) SetEventingSource(ctx context.Context, project, id string, value *config.EventingSource, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceEventingSource); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	value.ID = id
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingSource, id)
+	if projectConfig.EventingSources == nil {
+		projectConfig.EventingSources = config.EventingSources{resourceID: value}
+	} else {
+		projectConfig.EventingSources[resourceID] = value
+	}
+
+	if err := s.modules.SetEventingSourceConfig(ctx, project, projectConfig.EventingSources); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting eventing source config", err, nil)
+	}
+
+	if err := s.store.SetResource(ctx, resourceID, value); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// SetDeleteEventingSource deletes a custom event source of eventing
+func (s *Manager) SetDeleteEventingSource(ctx context.Context, project, id string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingSource, id)
+	delete(projectConfig.EventingSources, resourceID)
+
+	if err := s.modules.SetEventingSourceConfig(ctx, project, projectConfig.EventingSources); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting eventing source config", err, nil)
+	}
+
+	if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetEventingSources returns the custom event sources of eventing
+func (s *Manager) GetEventingSources(ctx context.Context, project, id string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	if id != "*" {
+		resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingSource, id)
+		source, ok := projectConfig.EventingSources[resourceID]
+		if !ok {
+			return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Event source (%s) does not exists in eventing config", id), nil, nil)
+		}
+		return http.StatusOK, []interface{}{source}, nil
+	}
+
+	sources := []interface{}{}
+	for _, value := range projectConfig.EventingSources {
+		sources = append(sources, value)
+	}
+	return http.StatusOK, sources, nil
+}
+
+// SetEventingWorkflow sets a workflow of eventing
+func (s *Manager) SetEventingWorkflow(ctx context.Context, project, id string, value *config.EventingWorkflow, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceEventingWorkflow); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	value.ID = id
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingWorkflow, id)
+	if projectConfig.EventingWorkflows == nil {
+		projectConfig.EventingWorkflows = config.EventingWorkflows{resourceID: value}
+	} else {
+		projectConfig.EventingWorkflows[resourceID] = value
+	}
+
+	if err := s.modules.SetEventingWorkflowConfig(ctx, project, projectConfig.EventingWorkflows); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting eventing workflow config", err, nil)
+	}
+
+	if err := s.store.SetResource(ctx, resourceID, value); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// SetDeleteEventingWorkflow deletes a workflow of eventing
+func (s *Manager) SetDeleteEventingWorkflow(ctx context.Context, project, id string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingWorkflow, id)
+	delete(projectConfig.EventingWorkflows, resourceID)
+
+	if err := s.modules.SetEventingWorkflowConfig(ctx, project, projectConfig.EventingWorkflows); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting eventing workflow config", err, nil)
+	}
+
+	if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetEventingWorkflows returns the workflows of eventing
+func (s *Manager) GetEventingWorkflows(ctx context.Context, project, id string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	if id != "*" {
+		resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceEventingWorkflow, id)
+		workflow, ok := projectConfig.EventingWorkflows[resourceID]
+		if !ok {
+			return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Workflow (%s) does not exists in eventing config", id), nil, nil)
+		}
+		return http.StatusOK, []interface{}{workflow}, nil
+	}
+
+	workflows := []interface{}{}
+	for _, value := range projectConfig.EventingWorkflows {
+		workflows = append(workflows, value)
+	}
+	return http.StatusOK, workflows, nil
+}
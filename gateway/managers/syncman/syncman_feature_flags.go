@@ -0,0 +1,133 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// SetFeatureFlag sets a feature flag of the project
+func (s *Manager) SetFeatureFlag(ctx context.Context, project, id string, value *config.FeatureFlag, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceFeatureFlag); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	value.ID = id
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceFeatureFlag, id)
+	if projectConfig.FeatureFlags == nil {
+		projectConfig.FeatureFlags = config.FeatureFlags{resourceID: value}
+	} else {
+		projectConfig.FeatureFlags[resourceID] = value
+	}
+
+	if err := s.modules.SetFeatureFlagsConfig(ctx, project, projectConfig.FeatureFlags); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting feature flags config", err, nil)
+	}
+
+	if err := s.store.SetResource(ctx, resourceID, value); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// SetDeleteFeatureFlag deletes a feature flag of the project
+func (s *Manager) SetDeleteFeatureFlag(ctx context.Context, project, id string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceFeatureFlag, id)
+	delete(projectConfig.FeatureFlags, resourceID)
+
+	if err := s.modules.SetFeatureFlagsConfig(ctx, project, projectConfig.FeatureFlags); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting feature flags config", err, nil)
+	}
+
+	if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetFeatureFlags returns the feature flags of the project
+func (s *Manager) GetFeatureFlags(ctx context.Context, project, id string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	if id != "*" {
+		resourceID := config.GenerateResourceID(s.clusterID, project, config.ResourceFeatureFlag, id)
+		flag, ok := projectConfig.FeatureFlags[resourceID]
+		if !ok {
+			return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Feature flag (%s) does not exist in project config", id), nil, nil)
+		}
+		return http.StatusOK, []interface{}{flag}, nil
+	}
+
+	flags := []interface{}{}
+	for _, value := range projectConfig.FeatureFlags {
+		flags = append(flags, value)
+	}
+	return http.StatusOK, flags, nil
+}
@@ -0,0 +1,56 @@
+package syncman
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// OnBecomeLeader registers a callback which gets invoked whenever this node becomes the leader of the cluster.
+// It is invoked right away if this node is already the leader. Modules and embedders use it to run cluster
+// singleton background work like cron scheduling or cleanup exactly once across the cluster.
+func (s *Manager) OnBecomeLeader(id string, cb func()) {
+	s.leader.AddCallBack(id, cb)
+}
+
+// OnLoseLeadership registers a callback which gets invoked whenever this node loses the leader position.
+// The singleton work started on becoming the leader must be stopped in it.
+func (s *Manager) OnLoseLeadership(id string, cb func()) {
+	s.leader.AddLostCallBack(id, cb)
+}
+
+// RemoveLeaderCallBacks removes the leadership callbacks registered with the id
+func (s *Manager) RemoveLeaderCallBacks(id string) {
+	s.leader.RemoveCallBack(id)
+}
+
+// IsLeader returns true if this node currently holds the leader position
+func (s *Manager) IsLeader() bool {
+	return s.leader.IsLeaderNode()
+}
+
+// GetClusterRole returns the role of this node in the cluster along with the id of the current leader
+func (s *Manager) GetClusterRole(ctx context.Context) (*model.ClusterRole, error) {
+	role := &model.ClusterRole{NodeID: s.nodeID, Role: model.ClusterRoleFollower}
+	if s.leader.IsLeaderNode() {
+		role.Role = model.ClusterRoleLeader
+	}
+
+	leaderID, err := s.leader.GetLeaderNodeID(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	role.LeaderID = leaderID
+
+	// The url of the leader is only known once it has sent its first heartbeat
+	if leaderID != "" {
+		leaderURL, err := s.getNodeURL(ctx, leaderID)
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		role.LeaderURL = leaderURL
+	}
+	return role, nil
+}
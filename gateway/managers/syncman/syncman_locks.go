@@ -0,0 +1,87 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+const (
+	// defaultLockTTL is used when the ttl isn't provided while acquiring or renewing a lock
+	defaultLockTTL = 30 * time.Second
+
+	// maxLockTTL is the maximum time for which a lock can be held without renewing it
+	maxLockTTL = time.Hour
+)
+
+func (s *Manager) getLockKey(projectID, name string) string {
+	return fmt.Sprintf("locks/%s/%s/%s", s.clusterID, projectID, name)
+}
+
+func getLockTTL(ttl int) (time.Duration, error) {
+	if ttl == 0 {
+		return defaultLockTTL, nil
+	}
+	d := time.Duration(ttl) * time.Second
+	if d < 0 || d > maxLockTTL {
+		return 0, fmt.Errorf("lock ttl must be between 1 and %d seconds", int(maxLockTTL.Seconds()))
+	}
+	return d, nil
+}
+
+// AcquireLock acquires the named lock of a project for the provided ttl in seconds. The lock is shared by all
+// nodes of the cluster. Status conflict is returned if the lock is held by someone else.
+func (s *Manager) AcquireLock(ctx context.Context, projectID, name string, ttl int) (int, *model.Lock, error) {
+	d, err := getLockTTL(ttl)
+	if err != nil {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid lock ttl provided", err, nil)
+	}
+
+	token := ksuid.New().String()
+	isAcquired, err := s.pubsubClient.SetKeyIfNotExists(ctx, s.getLockKey(projectID, name), token, d)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to acquire lock (%s)", name), err, map[string]interface{}{"project": projectID})
+	}
+	if !isAcquired {
+		return http.StatusConflict, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Lock (%s) is held by someone else", name), nil, map[string]interface{}{"project": projectID})
+	}
+
+	return http.StatusOK, &model.Lock{Name: name, Token: token, ExpiresAt: time.Now().Add(d)}, nil
+}
+
+// RenewLock extends the lease on a lock held by the owner of the token. Status conflict is returned if the
+// lease has expired or the lock is held by someone else.
+func (s *Manager) RenewLock(ctx context.Context, projectID, name, token string, ttl int) (int, *model.Lock, error) {
+	d, err := getLockTTL(ttl)
+	if err != nil {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid lock ttl provided", err, nil)
+	}
+
+	isRenewed, err := s.pubsubClient.CompareAndRenewKey(ctx, s.getLockKey(projectID, name), token, d)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to renew lock (%s)", name), err, map[string]interface{}{"project": projectID})
+	}
+	if !isRenewed {
+		return http.StatusConflict, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Lock (%s) is not held by the provided token", name), nil, map[string]interface{}{"project": projectID})
+	}
+
+	return http.StatusOK, &model.Lock{Name: name, Token: token, ExpiresAt: time.Now().Add(d)}, nil
+}
+
+// ReleaseLock releases a lock held by the owner of the token
+func (s *Manager) ReleaseLock(ctx context.Context, projectID, name, token string) (int, error) {
+	isReleased, err := s.pubsubClient.CompareAndDeleteKey(ctx, s.getLockKey(projectID, name), token)
+	if err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to release lock (%s)", name), err, map[string]interface{}{"project": projectID})
+	}
+	if !isReleased {
+		return http.StatusConflict, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Lock (%s) is not held by the provided token", name), nil, map[string]interface{}{"project": projectID})
+	}
+	return http.StatusOK, nil
+}
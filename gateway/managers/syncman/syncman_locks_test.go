@@ -0,0 +1,31 @@
+package syncman
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetLockTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     int
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default ttl", ttl: 0, want: defaultLockTTL},
+		{name: "valid ttl", ttl: 60, want: time.Minute},
+		{name: "negative ttl", ttl: -1, wantErr: true},
+		{name: "ttl above the maximum", ttl: int(maxLockTTL.Seconds()) + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getLockTTL(tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getLockTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getLockTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// SetProjectMaintenance puts the project in the read only or maintenance mode, or brings it back online. The mode is
+// a part of the project config, hence it gets replicated to every node of the cluster.
+func (s *Manager) SetProjectMaintenance(ctx context.Context, project string, value *config.MaintenanceConfig, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := validateMaintenanceConfig(value); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid maintenance config provided", err, nil)
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if value.Mode == config.MaintenanceModeOff {
+		projectConfig.ProjectConfig.Maintenance = nil
+	} else {
+		projectConfig.ProjectConfig.Maintenance = value
+	}
+
+	if err := s.modules.SetProjectConfig(ctx, projectConfig.ProjectConfig); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err := s.store.SetResource(ctx, config.GenerateResourceID(s.clusterID, project, config.ResourceProject, project), projectConfig.ProjectConfig); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Project (%s) switched to mode (%s)", project, value.Mode), nil)
+	return http.StatusOK, nil
+}
+
+// GetProjectMaintenance returns the mode in which the project serves the client apis
+func (s *Manager) GetProjectMaintenance(ctx context.Context, project string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	// Acquire a lock
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	if projectConfig.ProjectConfig.Maintenance == nil {
+		return http.StatusOK, []interface{}{&config.MaintenanceConfig{Mode: config.MaintenanceModeOff}}, nil
+	}
+	return http.StatusOK, []interface{}{projectConfig.ProjectConfig.Maintenance}, nil
+}
+
+func validateMaintenanceConfig(c *config.MaintenanceConfig) error {
+	switch c.Mode {
+	case config.MaintenanceModeOff, config.MaintenanceModeReadOnly, config.MaintenanceModeFull:
+	default:
+		return fmt.Errorf("invalid mode (%s) provided - it must be one of off, read-only or maintenance", c.Mode)
+	}
+
+	if c.RetryAfter < 0 {
+		return fmt.Errorf("retry after cannot be negative")
+	}
+	if c.StatusCode != 0 && (c.StatusCode < 200 || c.StatusCode > 599) {
+		return fmt.Errorf("invalid status code (%d) provided", c.StatusCode)
+	}
+	if c.Mode != config.MaintenanceModeFull && (c.StatusCode != 0 || c.Body != "" || c.ContentType != "") {
+		return fmt.Errorf("a custom response can only be served in the maintenance mode")
+	}
+	return nil
+}
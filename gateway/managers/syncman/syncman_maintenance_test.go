@@ -0,0 +1,55 @@
+package syncman
+
+import (
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_validateMaintenanceConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *config.MaintenanceConfig
+		wantErr bool
+	}{
+		{
+			name: "read only mode",
+			c:    &config.MaintenanceConfig{Mode: config.MaintenanceModeReadOnly, RetryAfter: 120, Message: "Migrating the database"},
+		},
+		{
+			name: "maintenance mode with a custom response",
+			c:    &config.MaintenanceConfig{Mode: config.MaintenanceModeFull, StatusCode: 503, ContentType: "text/html", Body: "<h1>Back soon</h1>"},
+		},
+		{
+			name: "turning the mode off",
+			c:    &config.MaintenanceConfig{Mode: config.MaintenanceModeOff},
+		},
+		{
+			name:    "unknown mode",
+			c:       &config.MaintenanceConfig{Mode: "offline"},
+			wantErr: true,
+		},
+		{
+			name:    "negative retry after",
+			c:       &config.MaintenanceConfig{Mode: config.MaintenanceModeReadOnly, RetryAfter: -1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid status code",
+			c:       &config.MaintenanceConfig{Mode: config.MaintenanceModeFull, StatusCode: 42},
+			wantErr: true,
+		},
+		{
+			name:    "custom response in the read only mode",
+			c:       &config.MaintenanceConfig{Mode: config.MaintenanceModeReadOnly, Body: "read only"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMaintenanceConfig(tt.c); (err != nil) != tt.wantErr {
+				t.Errorf("validateMaintenanceConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package syncman
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// SetNodeMetadata sets the custom metadata this node advertises to the rest of the cluster in its heartbeats. The other
+// nodes observe the change as a membership update.
+func (s *Manager) SetNodeMetadata(metadata *model.NodeMetadata) error {
+	if metadata != nil {
+		if metadata.Capacity < 0 {
+			return errors.New("capacity of the node cannot be negative")
+		}
+		for key := range metadata.Labels {
+			if key == "" {
+				return errors.New("labels of the node cannot have an empty key")
+			}
+		}
+	}
+
+	s.lockServices.Lock()
+	defer s.lockServices.Unlock()
+	s.nodeMetadata = copyNodeMetadata(metadata)
+	return nil
+}
+
+// getNodeMetadata returns a copy of the metadata of this node, so that it can be advertised without holding the lock
+func (s *Manager) getNodeMetadata() *model.NodeMetadata {
+	s.lockServices.RLock()
+	defer s.lockServices.RUnlock()
+	return copyNodeMetadata(s.nodeMetadata)
+}
+
+// GetNodeZone returns the zone this node runs in. It is empty unless provided in the metadata of the node.
+func (s *Manager) GetNodeZone() string {
+	s.lockServices.RLock()
+	defer s.lockServices.RUnlock()
+	if s.nodeMetadata == nil {
+		return ""
+	}
+	return s.nodeMetadata.Zone
+}
+
+// OnMembershipEvent registers a callback which gets invoked whenever a node joins, leaves or updates what it
+// advertises to the cluster. The nodes are observed from their heartbeats, hence the events arrive within a heartbeat
+// interval. The callbacks are invoked one after the other and must not block.
+func (s *Manager) OnMembershipEvent(id string, cb func(event *model.MembershipEvent)) {
+	s.lockMembers.Lock()
+	defer s.lockMembers.Unlock()
+
+	if s.membershipCbs == nil {
+		s.membershipCbs = map[string]func(event *model.MembershipEvent){}
+	}
+	s.membershipCbs[id] = cb
+}
+
+// RemoveMembershipCallBack removes the membership callback registered with the id
+func (s *Manager) RemoveMembershipCallBack(id string) {
+	s.lockMembers.Lock()
+	defer s.lockMembers.Unlock()
+	delete(s.membershipCbs, id)
+}
+
+// GetMembers returns the nodes of the cluster as last seen in their heartbeats
+func (s *Manager) GetMembers() []*model.ClusterNode {
+	s.lockMembers.Lock()
+	defer s.lockMembers.Unlock()
+
+	nodes := make([]*model.ClusterNode, 0, len(s.members))
+	for _, node := range s.members {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// updateMembership compares the nodes with the ones seen last time and notifies the changes to the subscribers
+func (s *Manager) updateMembership(nodes []*model.ClusterNode) {
+	s.lockMembers.Lock()
+	events := getMembershipEvents(s.members, nodes)
+	s.members = make(map[string]*model.ClusterNode, len(nodes))
+	for _, node := range nodes {
+		s.members[node.ID] = node
+	}
+	cbs := make([]func(event *model.MembershipEvent), 0, len(s.membershipCbs))
+	for _, cb := range s.membershipCbs {
+		cbs = append(cbs, cb)
+	}
+	s.lockMembers.Unlock()
+
+	for _, event := range events {
+		// The joins and leaves are already emitted as admin events from the services of the store
+		if event.Type == model.MembershipEventUpdate {
+			s.emitAdminEvent(&model.AdminEvent{Type: model.AdminEventNodeUpdated, Payload: map[string]interface{}{"node": event.Node.ID}})
+		}
+		for _, cb := range cbs {
+			cb(event)
+		}
+	}
+}
+
+// getMembershipEvents returns the events which turn the old nodes into the new ones sorted by the ids of the nodes
+func getMembershipEvents(old map[string]*model.ClusterNode, nodes []*model.ClusterNode) []*model.MembershipEvent {
+	events := make([]*model.MembershipEvent, 0)
+	ids := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		ids[node.ID] = struct{}{}
+		prev, p := old[node.ID]
+		switch {
+		case !p:
+			events = append(events, &model.MembershipEvent{Type: model.MembershipEventJoin, Node: node})
+		case isNodeUpdated(prev, node):
+			events = append(events, &model.MembershipEvent{Type: model.MembershipEventUpdate, Node: node})
+		}
+	}
+	for id, node := range old {
+		if _, p := ids[id]; !p {
+			events = append(events, &model.MembershipEvent{Type: model.MembershipEventLeave, Node: node})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Node.ID < events[j].Node.ID })
+	return events
+}
+
+// isNodeUpdated checks if a node changed what it advertises. The heartbeat times and the derived fields like the
+// health change all the time and hence are ignored.
+func isNodeUpdated(prev, node *model.ClusterNode) bool {
+	return prev.Address != node.Address || prev.Scheme != node.Scheme || prev.Version != node.Version ||
+		prev.ProtocolVersion != node.ProtocolVersion || prev.IsDraining != node.IsDraining ||
+		!reflect.DeepEqual(prev.Metadata, node.Metadata)
+}
+
+func copyNodeMetadata(metadata *model.NodeMetadata) *model.NodeMetadata {
+	if metadata == nil {
+		return nil
+	}
+	c := &model.NodeMetadata{Zone: metadata.Zone, Capacity: metadata.Capacity}
+	if metadata.Labels != nil {
+		c.Labels = make(map[string]string, len(metadata.Labels))
+		for k, v := range metadata.Labels {
+			c.Labels[k] = v
+		}
+	}
+	return c
+}
@@ -0,0 +1,123 @@
+package syncman
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_getMembershipEvents(t *testing.T) {
+	node1 := &model.ClusterNode{ID: "node-1", Address: "10.0.0.1:4122", Metadata: &model.NodeMetadata{Zone: "us-east-1a"}}
+	node2 := &model.ClusterNode{ID: "node-2", Address: "10.0.0.2:4122"}
+
+	tests := []struct {
+		name  string
+		old   map[string]*model.ClusterNode
+		nodes []*model.ClusterNode
+		want  []*model.MembershipEvent
+	}{
+		{
+			name:  "First heartbeats",
+			nodes: []*model.ClusterNode{node2, node1},
+			want: []*model.MembershipEvent{
+				{Type: model.MembershipEventJoin, Node: node1},
+				{Type: model.MembershipEventJoin, Node: node2},
+			},
+		},
+		{
+			name:  "No change apart from the heartbeat",
+			old:   map[string]*model.ClusterNode{"node-1": node1},
+			nodes: []*model.ClusterNode{{ID: "node-1", Address: "10.0.0.1:4122", Metadata: &model.NodeMetadata{Zone: "us-east-1a"}, Health: model.NodeHealthSuspect}},
+			want:  []*model.MembershipEvent{},
+		},
+		{
+			name:  "Node left",
+			old:   map[string]*model.ClusterNode{"node-1": node1, "node-2": node2},
+			nodes: []*model.ClusterNode{node1},
+			want:  []*model.MembershipEvent{{Type: model.MembershipEventLeave, Node: node2}},
+		},
+		{
+			name:  "Metadata updated",
+			old:   map[string]*model.ClusterNode{"node-1": node1},
+			nodes: []*model.ClusterNode{{ID: "node-1", Address: "10.0.0.1:4122", Metadata: &model.NodeMetadata{Zone: "us-east-1b"}}},
+			want: []*model.MembershipEvent{
+				{Type: model.MembershipEventUpdate, Node: &model.ClusterNode{ID: "node-1", Address: "10.0.0.1:4122", Metadata: &model.NodeMetadata{Zone: "us-east-1b"}}},
+			},
+		},
+		{
+			name:  "Node started draining",
+			old:   map[string]*model.ClusterNode{"node-2": node2},
+			nodes: []*model.ClusterNode{{ID: "node-2", Address: "10.0.0.2:4122", IsDraining: true}},
+			want: []*model.MembershipEvent{
+				{Type: model.MembershipEventUpdate, Node: &model.ClusterNode{ID: "node-2", Address: "10.0.0.2:4122", IsDraining: true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getMembershipEvents(tt.old, tt.nodes)
+			if arr := deep.Equal(got, tt.want); len(arr) > 0 {
+				t.Errorf("getMembershipEvents() differences = %v", arr)
+			}
+		})
+	}
+}
+
+func TestManager_SetNodeMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata *model.NodeMetadata
+		wantErr  bool
+	}{
+		{name: "No metadata", metadata: nil},
+		{name: "Valid metadata", metadata: &model.NodeMetadata{Zone: "us-east-1a", Capacity: 2, Labels: map[string]string{"tier": "edge"}}},
+		{name: "Negative capacity", metadata: &model.NodeMetadata{Capacity: -1}, wantErr: true},
+		{name: "Label with empty key", metadata: &model.NodeMetadata{Labels: map[string]string{"": "edge"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Manager{}
+			err := s.SetNodeMetadata(tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetNodeMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if arr := deep.Equal(s.getNodeMetadata(), tt.metadata); len(arr) > 0 {
+				t.Errorf("getNodeMetadata() differences = %v", arr)
+			}
+		})
+	}
+}
+
+func TestManager_updateMembership(t *testing.T) {
+	s := &Manager{nodeID: "node-1"}
+	got := make([]*model.MembershipEvent, 0)
+	s.OnMembershipEvent("test", func(event *model.MembershipEvent) { got = append(got, event) })
+
+	node1 := &model.ClusterNode{ID: "node-1"}
+	node2 := &model.ClusterNode{ID: "node-2"}
+	s.updateMembership([]*model.ClusterNode{node1, node2})
+	s.updateMembership([]*model.ClusterNode{node1})
+
+	want := []*model.MembershipEvent{
+		{Type: model.MembershipEventJoin, Node: node1},
+		{Type: model.MembershipEventJoin, Node: node2},
+		{Type: model.MembershipEventLeave, Node: node2},
+	}
+	if arr := deep.Equal(got, want); len(arr) > 0 {
+		t.Errorf("updateMembership() events differences = %v", arr)
+	}
+	if arr := deep.Equal(s.GetMembers(), []*model.ClusterNode{node1}); len(arr) > 0 {
+		t.Errorf("GetMembers() differences = %v", arr)
+	}
+
+	s.RemoveMembershipCallBack("test")
+	s.updateMembership(nil)
+	if len(got) != len(want) {
+		t.Errorf("updateMembership() invoked a removed callback")
+	}
+}
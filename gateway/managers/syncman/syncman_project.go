@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/spaceuptech/helpers"
 
@@ -45,6 +46,12 @@ func (s *Manager) ApplyProjectConfig(ctx context.Context, project *config.Projec
 	}
 	p, ok := s.projectConfig.Projects[project.ID]
 	if ok {
+		// The databases verifying tokens on their own must stop accepting the secrets which got rotated out
+		if !reflect.DeepEqual(p.ProjectConfig.Secrets, project.Secrets) {
+			if err := s.setRowLevelSecuritySecrets(ctx, p, project.ID, project); err != nil {
+				return http.StatusInternalServerError, err
+			}
+		}
 		p.ProjectConfig = project
 	} else {
 		s.projectConfig.Projects[project.ID] = config.GenerateEmptyProject(project)
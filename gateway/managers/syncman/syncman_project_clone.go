@@ -0,0 +1,230 @@
+package syncman
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// CloneProject copies the config of a project into another project while applying the provided overrides. Cloning
+// into an existing project promotes the config of the source project to it, like from a staging to a production project.
+// The tables of the databases and the deploy secrets get applied as well.
+func (s *Manager) CloneProject(ctx context.Context, sourceID string, req *model.ProjectCloneRequest, params model.RequestParams) (int, *model.ConfigImportResult, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	if req.ID == "" || req.ID == sourceID {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Id of the project to clone into must be provided and differ from the source project", nil, nil)
+	}
+
+	s.lock.RLock()
+	source, err := s.getConfigWithoutLock(ctx, sourceID)
+	target, _ := s.getConfigWithoutLock(ctx, req.ID)
+	s.lock.RUnlock()
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if target != nil && !req.Overwrite {
+		return http.StatusConflict, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project (%s) already exists - set overwrite to promote the config to it", req.ID), nil, nil)
+	}
+
+	clone, err := cloneProject(source, target, req)
+	if err != nil {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to clone project (%s)", sourceID), err, nil)
+	}
+
+	status, result, err := s.importConfig(ctx, &config.Config{Projects: config.Projects{req.ID: clone}}, model.ConfigImportMerge)
+	if err != nil {
+		return status, nil, err
+	}
+
+	for _, sealed := range clone.DeploySecrets {
+		secret, err := unsealDeploySecret(clone, sealed)
+		if err != nil {
+			return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to decrypt secret (%s)", sealed.ID), err, nil)
+		}
+		if err := s.applyDeploySecretToRunner(ctx, req.ID, secret); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+
+	// Create the tables in the databases of the clone
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(req.ID)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	for _, dbConfig := range clone.DatabaseConfigs {
+		schemas := make(config.DatabaseSchemas)
+		for id, schema := range clone.DatabaseSchemas {
+			if schema.DbAlias == dbConfig.DbAlias {
+				schemas[rebaseResourceID(s.clusterID, id)] = schema
+			}
+		}
+		if len(schemas) == 0 || !dbConfig.Enabled {
+			continue
+		}
+		if err := schemaMod.SchemaModifyAll(ctx, dbConfig.DbAlias, dbConfig.DBName, schemas); err != nil {
+			return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create the tables of database (%s) in project (%s)", dbConfig.DbAlias, req.ID), err, nil)
+		}
+	}
+
+	return http.StatusOK, result, nil
+}
+
+// cloneProject moves the resources of the provided project to the project of the clone request and applies its
+// overrides. The jwt secrets, aes key and whitelisted domains of the target project are kept unless they are
+// overridden. The target is nil if the project doesn't exist yet. The provided project gets modified.
+func cloneProject(project, target *config.Project, req *model.ProjectCloneRequest) (*config.Project, error) {
+	if project.ProjectConfig == nil {
+		return nil, fmt.Errorf("project config is missing")
+	}
+	sourceAESKey := project.ProjectConfig.AESKey
+
+	// Every map of the project is keyed by the resource id which contains the project id
+	v := reflect.ValueOf(project).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Map || field.IsNil() || field.Type().Key().Kind() != reflect.String {
+			continue
+		}
+		moved := reflect.MakeMapWithSize(field.Type(), field.Len())
+		iter := field.MapRange()
+		for iter.Next() {
+			moved.SetMapIndex(reflect.ValueOf(moveResourceID(iter.Key().String(), req.ID)).Convert(field.Type().Key()), iter.Value())
+		}
+		field.Set(moved)
+	}
+
+	project.ProjectConfig.ID = req.ID
+	if req.Name != "" {
+		project.ProjectConfig.Name = req.Name
+	}
+	for _, route := range project.IngressRoutes {
+		route.Project = req.ID
+	}
+
+	overrides := req.Overrides
+	if overrides == nil {
+		overrides = new(model.ProjectCloneOverrides)
+	}
+
+	for dbAlias, override := range overrides.Databases {
+		var dbConfig *config.DatabaseConfig
+		for _, c := range project.DatabaseConfigs {
+			if c.DbAlias == dbAlias {
+				dbConfig = c
+			}
+		}
+		if dbConfig == nil {
+			return nil, fmt.Errorf("database (%s) does not exist in the source project", dbAlias)
+		}
+		if override.Conn != "" {
+			dbConfig.Conn = override.Conn
+		}
+		if override.DBName != "" {
+			dbConfig.DBName = override.DBName
+		}
+	}
+
+	domains := []string{}
+	if target != nil && target.ProjectConfig != nil {
+		project.ProjectConfig.Secrets = target.ProjectConfig.Secrets
+		project.ProjectConfig.AESKey = target.ProjectConfig.AESKey
+	}
+	if target != nil && target.LetsEncrypt != nil && target.LetsEncrypt.WhitelistedDomains != nil {
+		domains = target.LetsEncrypt.WhitelistedDomains
+	}
+	if len(overrides.Secrets) > 0 {
+		project.ProjectConfig.Secrets = overrides.Secrets
+	}
+	if overrides.Domains != nil {
+		domains = overrides.Domains
+	}
+	if project.LetsEncrypt == nil {
+		project.LetsEncrypt = new(config.LetsEncrypt)
+	}
+	project.LetsEncrypt.WhitelistedDomains = domains
+
+	if overrides.AESKey != "" {
+		if _, err := base64.StdEncoding.DecodeString(overrides.AESKey); err != nil {
+			return nil, fmt.Errorf("aes key must be base64 encoded: %v", err)
+		}
+		project.ProjectConfig.AESKey = overrides.AESKey
+	}
+
+	if project.ProjectConfig.AESKey == sourceAESKey && len(overrides.DeploySecrets) == 0 {
+		return project, nil
+	}
+
+	// The deploy secrets need to be encrypted again since their values or the aes key have changed
+	secrets := make(map[string]*config.DeploySecret, len(project.DeploySecrets))
+	for id, sealed := range project.DeploySecrets {
+		secret, err := unsealDeploySecret(&config.Project{ProjectConfig: &config.ProjectConfig{AESKey: sourceAESKey}}, sealed)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt secret (%s): %v", sealed.ID, err)
+		}
+		secrets[id] = secret
+	}
+	for secretID, data := range overrides.DeploySecrets {
+		var secret *config.DeploySecret
+		for _, s := range secrets {
+			if s.ID == secretID {
+				secret = s
+			}
+		}
+		if secret == nil {
+			return nil, fmt.Errorf("secret (%s) does not exist in the source project", secretID)
+		}
+		for k, v := range data {
+			secret.Data[k] = v
+		}
+	}
+
+	aesKey, err := getProjectAESKey(project)
+	if err != nil {
+		return nil, err
+	}
+	for id, secret := range secrets {
+		for k, v := range secret.Data {
+			sealedValue, err := utils.Seal(aesKey, v)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encrypt secret (%s): %v", secret.ID, err)
+			}
+			secret.Data[k] = sealedValue
+		}
+		project.DeploySecrets[id] = secret
+	}
+
+	return project, nil
+}
+
+// moveResourceID replaces the project id of the resource id. The id of the project resource itself ends with the project id as well.
+func moveResourceID(id, projectID string) string {
+	arr := strings.SplitN(id, "--", 4)
+	if len(arr) < 4 {
+		return id
+	}
+	if config.Resource(arr[2]) == config.ResourceProject {
+		arr[3] = projectID
+	}
+	arr[1] = projectID
+	return strings.Join(arr, "--")
+}
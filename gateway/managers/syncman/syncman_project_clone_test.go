@@ -0,0 +1,156 @@
+package syncman
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_moveResourceID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "db config", id: "chicago--dev--db-config--db", want: "chicago--prod--db-config--db"},
+		{name: "db schema", id: "chicago--dev--db-schema--db-users", want: "chicago--prod--db-schema--db-users"},
+		{name: "project", id: "chicago--dev--project--dev", want: "chicago--prod--project--prod"},
+		{name: "invalid id", id: "dev", want: "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moveResourceID(tt.id, "prod"); got != tt.want {
+				t.Errorf("moveResourceID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cloneProject(t *testing.T) {
+	devKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	prodKey := base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+
+	newSource := func() *config.Project {
+		sealed, _ := utils.Seal([]byte("0123456789abcdef0123456789abcdef"), "dev-password")
+		return &config.Project{
+			ProjectConfig:   &config.ProjectConfig{ID: "dev", Name: "Dev", AESKey: devKey, Secrets: []*config.Secret{{KID: "dev", Secret: "dev-secret"}}},
+			DatabaseConfigs: config.DatabaseConfigs{"chicago--dev--db-config--db": {DbAlias: "db", Type: "postgres", Conn: "postgres://dev", DBName: "public", Enabled: true}},
+			DatabaseSchemas: config.DatabaseSchemas{"chicago--dev--db-schema--db-users": {DbAlias: "db", Table: "users", Schema: "type users { id: ID! @primary }"}},
+			IngressRoutes:   config.IngressRoutes{"chicago--dev--ingress-route--home": {ID: "home", Project: "dev"}},
+			LetsEncrypt:     &config.LetsEncrypt{WhitelistedDomains: []string{"dev.example.com"}},
+			DeploySecrets:   config.DeploySecrets{"chicago--dev--deploy-secret--db": {ID: "db", Type: "env", Data: map[string]string{"PASSWORD": sealed}}},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		target        *config.Project
+		req           *model.ProjectCloneRequest
+		wantConn      string
+		wantSecretKID string
+		wantDomains   []string
+		wantAESKey    string
+		wantPassword  string
+		wantErr       bool
+	}{
+		{
+			name:          "clone into a new project",
+			req:           &model.ProjectCloneRequest{ID: "staging"},
+			wantConn:      "postgres://dev",
+			wantSecretKID: "dev",
+			wantDomains:   []string{},
+			wantAESKey:    devKey,
+			wantPassword:  "dev-password",
+		},
+		{
+			name: "clone with overrides",
+			req: &model.ProjectCloneRequest{ID: "staging", Overrides: &model.ProjectCloneOverrides{
+				Databases:     map[string]*model.DatabaseOverride{"db": {Conn: "postgres://staging"}},
+				Secrets:       []*config.Secret{{KID: "staging", Secret: "staging-secret"}},
+				AESKey:        prodKey,
+				DeploySecrets: map[string]map[string]string{"db": {"PASSWORD": "staging-password"}},
+				Domains:       []string{"staging.example.com"},
+			}},
+			wantConn:      "postgres://staging",
+			wantSecretKID: "staging",
+			wantDomains:   []string{"staging.example.com"},
+			wantAESKey:    prodKey,
+			wantPassword:  "staging-password",
+		},
+		{
+			name: "promote to an existing project",
+			target: &config.Project{
+				ProjectConfig: &config.ProjectConfig{ID: "prod", AESKey: prodKey, Secrets: []*config.Secret{{KID: "prod", Secret: "prod-secret"}}},
+				LetsEncrypt:   &config.LetsEncrypt{WhitelistedDomains: []string{"example.com"}},
+			},
+			req: &model.ProjectCloneRequest{ID: "prod", Overwrite: true, Overrides: &model.ProjectCloneOverrides{
+				Databases: map[string]*model.DatabaseOverride{"db": {Conn: "postgres://prod"}},
+			}},
+			wantConn:      "postgres://prod",
+			wantSecretKID: "prod",
+			wantDomains:   []string{"example.com"},
+			wantAESKey:    prodKey,
+			wantPassword:  "dev-password",
+		},
+		{
+			name:    "override of an unknown database",
+			req:     &model.ProjectCloneRequest{ID: "staging", Overrides: &model.ProjectCloneOverrides{Databases: map[string]*model.DatabaseOverride{"mongo": {Conn: "mongodb://staging"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "override of an unknown deploy secret",
+			req:     &model.ProjectCloneRequest{ID: "staging", Overrides: &model.ProjectCloneOverrides{DeploySecrets: map[string]map[string]string{"api": {"KEY": "value"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cloneProject(newSource(), tt.target, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cloneProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			id := tt.req.ID
+			if got.ProjectConfig.ID != id {
+				t.Errorf("cloneProject() project id = %v, want %v", got.ProjectConfig.ID, id)
+			}
+			dbConfig, ok := got.DatabaseConfigs["chicago--"+id+"--db-config--db"]
+			if !ok {
+				t.Fatalf("cloneProject() db config wasn't moved to project (%s): %v", id, got.DatabaseConfigs)
+			}
+			if dbConfig.Conn != tt.wantConn {
+				t.Errorf("cloneProject() conn = %v, want %v", dbConfig.Conn, tt.wantConn)
+			}
+			if _, ok := got.DatabaseSchemas["chicago--"+id+"--db-schema--db-users"]; !ok {
+				t.Errorf("cloneProject() db schema wasn't moved to project (%s): %v", id, got.DatabaseSchemas)
+			}
+			if route := got.IngressRoutes["chicago--"+id+"--ingress-route--home"]; route == nil || route.Project != id {
+				t.Errorf("cloneProject() ingress route wasn't moved to project (%s): %v", id, got.IngressRoutes)
+			}
+			if got.ProjectConfig.Secrets[0].KID != tt.wantSecretKID {
+				t.Errorf("cloneProject() jwt secret = %v, want %v", got.ProjectConfig.Secrets[0].KID, tt.wantSecretKID)
+			}
+			if !reflect.DeepEqual(got.LetsEncrypt.WhitelistedDomains, tt.wantDomains) {
+				t.Errorf("cloneProject() domains = %v, want %v", got.LetsEncrypt.WhitelistedDomains, tt.wantDomains)
+			}
+			if got.ProjectConfig.AESKey != tt.wantAESKey {
+				t.Errorf("cloneProject() aes key = %v, want %v", got.ProjectConfig.AESKey, tt.wantAESKey)
+			}
+
+			secret, err := unsealDeploySecret(got, got.DeploySecrets["chicago--"+id+"--deploy-secret--db"])
+			if err != nil {
+				t.Fatalf("cloneProject() unable to decrypt deploy secret with the aes key of the clone: %v", err)
+			}
+			if secret.Data["PASSWORD"] != tt.wantPassword {
+				t.Errorf("cloneProject() deploy secret = %v, want %v", secret.Data["PASSWORD"], tt.wantPassword)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// GetRowLevelSecurityPolicies returns the queries creating the row level security policies of a postgres table
+func (s *Manager) GetRowLevelSecurityPolicies(ctx context.Context, project, dbAlias, col string, params model.RequestParams) (int, []string, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	// Acquire a lock
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	dbConfig, ok := projectConfig.DatabaseConfigs[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseConfig, dbAlias)]
+	if !ok {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Provided db alias (%s) does not exists", dbAlias), nil, nil)
+	}
+
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	queries, err := schemaMod.GetRowLevelSecurityQueries(ctx, dbAlias, dbConfig.DBName, col, s.getEffectiveRules(projectConfig, project, dbAlias, col))
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	return http.StatusOK, queries, nil
+}
+
+// applyRowLevelSecurity pushes the security rules of a table down to the database if row level security is enabled on
+// it. The default rules are pushed down to all the tables which don't have rules of their own.
+func (s *Manager) applyRowLevelSecurity(ctx context.Context, projectConfig *config.Project, project, dbAlias, col string) error {
+	cols := []string{col}
+	if col == "default" {
+		cols = []string{}
+		for _, table := range getTables(projectConfig, dbAlias) {
+			if _, ok := projectConfig.DatabaseRules[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseRule, dbAlias, table, "rule")]; !ok {
+				cols = append(cols, table)
+			}
+		}
+	}
+	return s.applyRowLevelSecurityToTables(ctx, projectConfig, project, dbAlias, cols)
+}
+
+// applyRowLevelSecurityToAll pushes the security rules of every table of the database down to it if row level security
+// is enabled on it
+func (s *Manager) applyRowLevelSecurityToAll(ctx context.Context, projectConfig *config.Project, project, dbAlias string) error {
+	return s.applyRowLevelSecurityToTables(ctx, projectConfig, project, dbAlias, getTables(projectConfig, dbAlias))
+}
+
+func (s *Manager) applyRowLevelSecurityToTables(ctx context.Context, projectConfig *config.Project, project, dbAlias string, cols []string) error {
+	dbConfig, ok := projectConfig.DatabaseConfigs[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseConfig, dbAlias)]
+	if !ok || !dbConfig.RowLevelSecurity {
+		return nil
+	}
+	if dbType := strings.TrimPrefix(dbConfig.Type, "sql-"); model.DBType(dbType) != model.Postgres {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Row level security is only supported on postgres - database (%s) is of type (%s)", dbAlias, dbType), nil, nil)
+	}
+
+	secret := getRowLevelSecuritySecret(projectConfig.ProjectConfig)
+	if secret == "" {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Row level security of database (%s) requires the project to have an HS256 secret", dbAlias), nil, nil)
+	}
+
+	schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+	if err != nil {
+		return err
+	}
+	for _, c := range cols {
+		if err := schemaMod.ApplyRowLevelSecurity(ctx, dbAlias, dbConfig.DBName, c, secret, s.getEffectiveRules(projectConfig, project, dbAlias, c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRowLevelSecuritySecrets replaces the secret the tokens are verified with in every database having row level
+// security enabled, so that rotating or revoking a secret of the project applies to the clients accessing the databases
+// directly as well. The databases stop accepting any token once the project has no HS256 secret.
+func (s *Manager) setRowLevelSecuritySecrets(ctx context.Context, projectConfig *config.Project, project string, settings *config.ProjectConfig) error {
+	secret := getRowLevelSecuritySecret(settings)
+	for _, dbConfig := range projectConfig.DatabaseConfigs {
+		if !dbConfig.RowLevelSecurity {
+			continue
+		}
+
+		schemaMod, err := s.modules.GetSchemaModuleForSyncMan(project)
+		if err != nil {
+			return err
+		}
+		if err := schemaMod.SetRowLevelSecuritySecret(ctx, dbConfig.DbAlias, dbConfig.DBName, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRowLevelSecuritySecret returns the secret the tokens of the clients accessing the database directly are verified
+// with, which is the primary HS256 secret of the project
+func getRowLevelSecuritySecret(settings *config.ProjectConfig) string {
+	var secret string
+	if settings == nil {
+		return secret
+	}
+	for _, sec := range settings.Secrets {
+		if sec.Alg == config.HS256 && (secret == "" || sec.IsPrimary) {
+			secret = sec.Secret
+		}
+	}
+	return secret
+}
+
+// getTables returns the tables of the database having a schema
+func getTables(projectConfig *config.Project, dbAlias string) []string {
+	tables := make([]string, 0)
+	for _, dbSchema := range projectConfig.DatabaseSchemas {
+		if dbSchema.DbAlias == dbAlias && dbSchema.Table != "default" {
+			tables = append(tables, dbSchema.Table)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// getEffectiveRules returns the rules of a table. The default rules of the database apply if it doesn't have any.
+func (s *Manager) getEffectiveRules(projectConfig *config.Project, project, dbAlias, col string) map[string]*config.Rule {
+	for _, c := range []string{col, "default"} {
+		if rule, ok := projectConfig.DatabaseRules[config.GenerateResourceID(s.clusterID, project, config.ResourceDatabaseRule, dbAlias, c, "rule")]; ok {
+			return rule.Rules
+		}
+	}
+	return nil
+}
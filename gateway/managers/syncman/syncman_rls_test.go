@@ -0,0 +1,108 @@
+package syncman
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// getRLSProject returns a project having a postgres database with row level security enabled on it
+func getRLSProject(secrets ...*config.Secret) *config.Project {
+	return &config.Project{
+		ProjectConfig: &config.ProjectConfig{ID: "1", Secrets: secrets},
+		DatabaseConfigs: config.DatabaseConfigs{
+			config.GenerateResourceID("chicago", "1", config.ResourceDatabaseConfig, "db"): {DbAlias: "db", Type: "sql-postgres", DBName: "public", RowLevelSecurity: true},
+		},
+		DatabaseSchemas: config.DatabaseSchemas{
+			config.GenerateResourceID("chicago", "1", config.ResourceDatabaseSchema, "db", "posts"):    {DbAlias: "db", Table: "posts"},
+			config.GenerateResourceID("chicago", "1", config.ResourceDatabaseSchema, "db", "comments"): {DbAlias: "db", Table: "comments"},
+			config.GenerateResourceID("chicago", "1", config.ResourceDatabaseSchema, "db", "default"):  {DbAlias: "db", Table: "default"},
+		},
+	}
+}
+
+func TestManager_SetDatabaseConnection_rowLevelSecurity(t *testing.T) {
+	tests := []struct {
+		name     string
+		applyErr error
+		wantErr  bool
+	}{
+		{name: "rules of existing tables are pushed down"},
+		{name: "config is restored if the rules can't be pushed down", applyErr: errors.New("connection refused"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := getRLSProject(&config.Secret{Alg: config.HS256, Secret: "secret", IsPrimary: true})
+			resourceID := config.GenerateResourceID("chicago", "1", config.ResourceDatabaseConfig, "db")
+			prevConfig := project.DatabaseConfigs[resourceID]
+			prevConfig.RowLevelSecurity = false
+
+			mockSchema := mockSchemaEventingInterface{}
+			mockModules := mockModulesInterface{}
+			mockStore := mockStoreInterface{}
+			mockModules.On("SetDatabaseConfig", mock.Anything, "1", mock.Anything).Return(nil)
+			mockModules.On("GetSchemaModuleForSyncMan", "1").Return(&mockSchema, nil)
+			mockSchema.On("ApplyRowLevelSecurity", mock.Anything, "db", "public", "comments", "secret", map[string]*config.Rule(nil)).Return(tt.applyErr)
+			if !tt.wantErr {
+				mockSchema.On("ApplyRowLevelSecurity", mock.Anything, "db", "public", "posts", "secret", map[string]*config.Rule(nil)).Return(nil)
+				mockStore.On("SetResource", mock.Anything, resourceID, mock.Anything).Return(nil)
+			}
+
+			s := &Manager{clusterID: "chicago", projectConfig: &config.Config{Projects: config.Projects{"1": project}}, modules: &mockModules, store: &mockStore, integrationMan: &mockIntegrationManager{skip: true}}
+			v := &config.DatabaseConfig{Type: "sql-postgres", DBName: "public", RowLevelSecurity: true}
+			if _, err := s.SetDatabaseConnection(context.Background(), "1", "db", v, model.RequestParams{}); (err != nil) != tt.wantErr {
+				t.Fatalf("SetDatabaseConnection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && project.DatabaseConfigs[resourceID] != prevConfig {
+				t.Errorf("SetDatabaseConnection() did not restore the previous config of the database")
+			}
+
+			mockSchema.AssertExpectations(t)
+			mockModules.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestManager_ApplyProjectConfig_rowLevelSecurity(t *testing.T) {
+	oldSecret := &config.Secret{Alg: config.HS256, Secret: "old", IsPrimary: true}
+	tests := []struct {
+		name       string
+		secrets    []*config.Secret
+		wantSecret string
+		wantPushed bool
+	}{
+		{name: "rotated secret", secrets: []*config.Secret{{Alg: config.HS256, Secret: "new", IsPrimary: true}}, wantSecret: "new", wantPushed: true},
+		{name: "revoked secret", secrets: []*config.Secret{{Alg: config.RS256, PublicKey: "key", IsPrimary: true}}, wantSecret: "", wantPushed: true},
+		{name: "unchanged secret", secrets: []*config.Secret{{Alg: config.HS256, Secret: "old", IsPrimary: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdmin := mockAdminSyncmanInterface{}
+			mockSchema := mockSchemaEventingInterface{}
+			mockModules := mockModulesInterface{}
+			mockStore := mockStoreInterface{}
+			mockAdmin.On("ValidateProjectSyncOperation", mock.Anything, mock.Anything).Return(true)
+			mockAdmin.On("GetInternalAccessToken").Return("token", nil)
+			mockModules.On("SetProjectConfig", mock.Anything, mock.Anything).Return(nil)
+			mockStore.On("SetResource", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			if tt.wantPushed {
+				mockModules.On("GetSchemaModuleForSyncMan", "1").Return(&mockSchema, nil)
+				mockSchema.On("SetRowLevelSecuritySecret", mock.Anything, "db", "public", tt.wantSecret).Return(nil)
+			}
+
+			s := &Manager{clusterID: "chicago", projectConfig: &config.Config{Projects: config.Projects{"1": getRLSProject(oldSecret)}}, adminMan: &mockAdmin, modules: &mockModules, store: &mockStore, integrationMan: &mockIntegrationManager{skip: true}}
+			if _, err := s.ApplyProjectConfig(context.Background(), &config.ProjectConfig{ID: "1", Secrets: tt.secrets, ContextTimeGraphQL: 10}, model.RequestParams{}); err != nil {
+				t.Fatalf("ApplyProjectConfig() unexpected error = %v", err)
+			}
+
+			mockSchema.AssertExpectations(t)
+			mockModules.AssertExpectations(t)
+		})
+	}
+}
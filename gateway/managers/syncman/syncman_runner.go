@@ -33,7 +33,7 @@ func (s *Manager) HandleRunnerRequests(admin *admin.Manager) http.HandlerFunc {
 		reqParams, err := admin.IsTokenValid(ctx, token, "runner", "modify", nil)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -58,7 +58,7 @@ func (s *Manager) HandleRunnerApplySecret(admin *admin.Manager) http.HandlerFunc
 		reqParams, err := admin.IsTokenValid(ctx, token, "secret", "modify", map[string]string{"project": projectID, "id": vars["id"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -87,7 +87,7 @@ func (s *Manager) HandleRunnerListSecret(admin *admin.Manager) http.HandlerFunc
 		reqParams, err := admin.IsTokenValid(ctx, token, "secret", "read", map[string]string{"project": projectID, "id": id})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -112,7 +112,7 @@ func (s *Manager) HandleRunnerSetFileSecretRootPath(admin *admin.Manager) http.H
 		reqParams, err := admin.IsTokenValid(ctx, token, "secret", "modify", map[string]string{"project": projectID, "id": vars["id"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -137,7 +137,7 @@ func (s *Manager) HandleRunnerDeleteSecret(admin *admin.Manager) http.HandlerFun
 		reqParams, err := admin.IsTokenValid(ctx, token, "secret", "modify", map[string]string{"project": projectID, "id": vars["id"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -162,7 +162,7 @@ func (s *Manager) HandleRunnerSetSecretKey(admin *admin.Manager) http.HandlerFun
 		reqParams, err := admin.IsTokenValid(ctx, token, "secret", "modify", map[string]string{"project": projectID, "id": vars["id"], "key": vars["key"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -187,7 +187,7 @@ func (s *Manager) HandleRunnerDeleteSecretKey(admin *admin.Manager) http.Handler
 		reqParams, err := admin.IsTokenValid(ctx, token, "secret", "modify", map[string]string{"project": projectID, "id": vars["id"], "key": vars["key"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -212,13 +212,26 @@ func (s *Manager) HandleRunnerApplyService(admin *admin.Manager) http.HandlerFun
 		reqParams, err := admin.IsTokenValid(ctx, token, "service", "modify", map[string]string{"project": projectID, "id": vars["serviceId"], "version": vars["version"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		// Create a context of execution
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 
+		// Apply the deploy secrets referred to by the service before the service itself
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read request body", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		if err := s.applyReferencedDeploySecrets(ctx, projectID, data); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewBuffer(data))
+
 		s.forwardRequestToRunner(ctx, w, r, admin, reqParams)
 	}
 }
@@ -245,7 +258,7 @@ func (s *Manager) HandleRunnerGetServices(admin *admin.Manager) http.HandlerFunc
 		reqParams, err := admin.IsTokenValid(ctx, token, "service", "read", map[string]string{"project": projectID, "id": id, "version": version})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -270,7 +283,7 @@ func (s *Manager) HandleRunnerGetDeploymentStatus(admin *admin.Manager) http.Han
 		reqParams, err := admin.IsTokenValid(ctx, token, "service", "read", map[string]string{"project": projectID})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 		// Create a context of execution
@@ -294,7 +307,7 @@ func (s *Manager) HandleRunnerDeleteService(admin *admin.Manager) http.HandlerFu
 		reqParams, err := admin.IsTokenValid(ctx, token, "service", "modify", map[string]string{"project": projectID, "id": vars["serviceId"], "version": vars["version"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -319,7 +332,7 @@ func (s *Manager) HandleRunnerServiceRoutingRequest(admin *admin.Manager) http.H
 		reqParams, err := admin.IsTokenValid(ctx, token, "service-route", "modify", map[string]string{"project": projectID, "id": vars["serviceId"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -349,7 +362,62 @@ func (s *Manager) HandleRunnerGetServiceRoutingRequest(admin *admin.Manager) htt
 		reqParams, err := admin.IsTokenValid(ctx, token, "service-route", "read", map[string]string{"project": projectID, "id": id})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		// Create a context of execution
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		s.forwardRequestToRunner(ctx, w, r, admin, reqParams)
+	}
+}
+
+// HandleRunnerRolloutRequest handles requests of the runner to start, promote or abort a rollout of a service
+func (s *Manager) HandleRunnerRolloutRequest(admin *admin.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+		defer cancel()
+
+		reqParams, err := admin.IsTokenValid(ctx, token, "service-route", "modify", map[string]string{"project": projectID, "id": vars["serviceId"]})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		// Create a context of execution
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		s.forwardRequestToRunner(ctx, w, r, admin, reqParams)
+	}
+}
+
+// HandleRunnerGetRollouts handles requests of the runner
+func (s *Manager) HandleRunnerGetRollouts(admin *admin.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			id = "*"
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+		defer cancel()
+
+		reqParams, err := admin.IsTokenValid(ctx, token, "service-route", "read", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -374,7 +442,7 @@ func (s *Manager) HandleRunnerSetServiceRole(admin *admin.Manager) http.HandlerF
 		reqParams, err := admin.IsTokenValid(ctx, token, "service-role", "modify", map[string]string{"project": projectID, "serviceId": vars["serviceId"], "id": vars["roleId"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -409,7 +477,7 @@ func (s *Manager) HandleRunnerGetServiceRoleRequest(admin *admin.Manager) http.H
 		reqParams, err := admin.IsTokenValid(ctx, token, "service-role", "read", map[string]string{"project": projectID, "serviceId": serviceID, "id": roleID})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -434,7 +502,7 @@ func (s *Manager) HandleRunnerDeleteServiceRole(admin *admin.Manager) http.Handl
 		reqParams, err := admin.IsTokenValid(ctx, token, "service-role", "modify", map[string]string{"project": projectID, "serviceId": vars["serviceId"], "id": vars["roleId"]})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -454,7 +522,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 				_ = helpers.Response.SendResponse(r.Context(), w, http.StatusOK, model.Response{Result: []interface{}{}})
 				return
 			}
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusBadRequest, errors.New("Space cloud cannot process this request, as you haven't started space cloud in kubernetes"))
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, errors.New("Space cloud cannot process this request, as you haven't started space cloud in kubernetes"))
 			return
 		}
 
@@ -466,7 +534,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 		params, err := admin.IsTokenValid(r.Context(), userToken, "service", "read", map[string]string{"project": projectID})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), fmt.Sprintf("Unable to forward  runner request failed to validate token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -485,7 +553,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 			// Check if an error occurred
 			if err := hookResponse.Error(); err != nil {
 				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Integration hook responded with an error", err, nil)
-				_ = helpers.Response.SendErrorResponse(ctx, w, hookResponse.Status(), err)
+				_ = utils.SendErrorResponse(ctx, w, hookResponse.Status(), err)
 				return
 			}
 
@@ -516,7 +584,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 		token, err := admin.GetInternalAccessToken()
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), fmt.Sprintf("Unable to forward  runner request failed to generate internal access token -%v", err), err, nil)
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
 			return
 		}
 		r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
@@ -524,17 +592,17 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 		// TODO: Use http2 client if that was the incoming request protocol
 		response, err := http.DefaultClient.Do(r)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
 			return
 		}
 		defer utils.CloseTheCloser(response.Body)
 		if response.StatusCode != 200 {
-			respBody := map[string]interface{}{}
-			if err := json.NewDecoder(response.Body).Decode(&respBody); err != nil {
-				_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				_ = utils.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
 				return
 			}
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, fmt.Errorf("received invalid status code (%d) got error - %v", response.StatusCode, respBody["error"]))
+			_ = utils.SendErrorResponse(r.Context(), w, response.StatusCode, utils.ParseErrorResponse(response.StatusCode, body))
 			return
 		}
 		streamData := false
@@ -554,7 +622,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 			done := r.Context().Done()
 			flusher, ok := w.(http.Flusher)
 			if !ok {
-				_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, errors.New("expected http.ResponseWriter to be an http.Flusher"))
+				_ = utils.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, errors.New("expected http.ResponseWriter to be an http.Flusher"))
 				return
 			}
 			w.Header().Set("X-Content-Type-Options", "nosniff")
@@ -571,7 +639,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 							w.WriteHeader(http.StatusNoContent)
 							return
 						}
-						_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
+						_ = utils.SendErrorResponse(r.Context(), w, http.StatusInternalServerError, err)
 					}
 					if str != "\n" {
 						fmt.Fprintf(w, "%s", str)
@@ -580,7 +648,7 @@ func (s *Manager) HandleRunnerGetServiceLogs(admin *admin.Manager) http.HandlerF
 				}
 			}
 		} else {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusBadRequest, errors.New("Missing headers X-Content-Type-Options & nosniff"))
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, errors.New("Missing headers X-Content-Type-Options & nosniff"))
 		}
 	}
 }
@@ -592,7 +660,7 @@ func (s *Manager) forwardRequestToRunner(ctx context.Context, w http.ResponseWri
 			_ = helpers.Response.SendResponse(r.Context(), w, http.StatusOK, model.Response{Result: []interface{}{}})
 			return
 		}
-		_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusBadRequest, errors.New("Space cloud cannot process this request, as you haven't started space cloud in kubernetes"))
+		_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, errors.New("Space cloud cannot process this request, as you haven't started space cloud in kubernetes"))
 		return
 	}
 
@@ -603,12 +671,16 @@ func (s *Manager) forwardRequestToRunner(ctx context.Context, w http.ResponseWri
 		data, err := ioutil.ReadAll(r.Body)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read request body", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
+		// Requests like promoting a rollout don't have a body
+		if len(data) == 0 {
+			data = []byte("{}")
+		}
 		if err := json.Unmarshal(data, &payload); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read unmarshal request body", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -628,7 +700,7 @@ func (s *Manager) forwardRequestToRunner(ctx context.Context, w http.ResponseWri
 		// Check if an error occurred
 		if err := hookResponse.Error(); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Integration hook responded with an error", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, hookResponse.Status(), err)
+			_ = utils.SendErrorResponse(ctx, w, hookResponse.Status(), err)
 			return
 		}
 
@@ -659,7 +731,7 @@ func (s *Manager) forwardRequestToRunner(ctx context.Context, w http.ResponseWri
 	token, err := admin.GetInternalAccessToken()
 	if err != nil {
 		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to forward  runner request failed to generate internal access token -%v", err), err, nil)
-		_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+		_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
@@ -667,7 +739,7 @@ func (s *Manager) forwardRequestToRunner(ctx context.Context, w http.ResponseWri
 	// TODO: Use http2 client if that was the incoming request protocol
 	response, err := http.DefaultClient.Do(r)
 	if err != nil {
-		_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+		_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 	defer utils.CloseTheCloser(response.Body)
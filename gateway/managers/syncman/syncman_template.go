@@ -0,0 +1,458 @@
+package syncman
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// builtInProjectTemplates are the project templates shipped with space cloud. Custom templates can't use their ids.
+var builtInProjectTemplates = map[string]*config.ProjectTemplate{
+	"blog": {
+		ID:          "blog",
+		Name:        "Blog",
+		Description: "Posts and comments which can be read by anyone and written by their authors",
+		Collections: map[string]*config.TemplateCollection{
+			"posts": {
+				Schema: `type posts {
+	id: ID! @primary
+	author_id: ID!
+	title: String!
+	body: String
+	published: Boolean @default(value: false)
+	created_at: DateTime @createdAt
+	updated_at: DateTime @updatedAt
+}`,
+				Rules: templateOwnerRules("author_id"),
+			},
+			"comments": {
+				Schema: `type comments {
+	id: ID! @primary
+	post_id: ID! @index
+	author_id: ID!
+	body: String!
+	created_at: DateTime @createdAt
+}`,
+				Rules: templateOwnerRules("author_id"),
+			},
+		},
+		Triggers: map[string]*config.EventingTrigger{
+			"on-post-created":    {Type: utils.EventDBCreate, Options: map[string]string{"col": "posts"}, URL: "/on-post-created", Retries: 3, Timeout: 5000},
+			"on-comment-created": {Type: utils.EventDBCreate, Options: map[string]string{"col": "comments"}, URL: "/on-comment-created", Retries: 3, Timeout: 5000},
+		},
+	},
+	"chat": {
+		ID:          "chat",
+		Name:        "Chat",
+		Description: "Rooms and realtime messages which can be read by signed in users",
+		Collections: map[string]*config.TemplateCollection{
+			"rooms": {
+				Schema: `type rooms {
+	id: ID! @primary
+	owner_id: ID!
+	name: String!
+	created_at: DateTime @createdAt
+}`,
+				Rules: templateSignedInRules("owner_id"),
+			},
+			"messages": {
+				Schema: `type messages {
+	id: ID! @primary
+	room_id: ID! @index
+	sender_id: ID!
+	text: String!
+	sent_at: DateTime @createdAt
+}`,
+				Rules:             templateSignedInRules("sender_id"),
+				IsRealTimeEnabled: true,
+			},
+		},
+		Triggers: map[string]*config.EventingTrigger{
+			"on-message-sent": {Type: utils.EventDBCreate, Options: map[string]string{"col": "messages"}, URL: "/on-message-sent", Retries: 3, Timeout: 5000},
+		},
+	},
+	"e-commerce": {
+		ID:          "e-commerce",
+		Name:        "E-commerce",
+		Description: "A public product catalogue along with orders which can only be accessed by the customers who placed them",
+		Collections: map[string]*config.TemplateCollection{
+			"products": {
+				Schema: `type products {
+	id: ID! @primary
+	name: String!
+	description: String
+	price: Float!
+	stock: Integer @default(value: 0)
+	created_at: DateTime @createdAt
+}`,
+				Rules: map[string]*config.Rule{
+					"create": {Rule: "deny"},
+					"read":   {Rule: "allow"},
+					"update": {Rule: "deny"},
+					"delete": {Rule: "deny"},
+				},
+			},
+			"orders": {
+				Schema: `type orders {
+	id: ID! @primary
+	customer_id: ID! @index
+	status: String @default(value: "pending")
+	total: Float!
+	created_at: DateTime @createdAt
+	updated_at: DateTime @updatedAt
+}`,
+				Rules: templateOwnerRules("customer_id"),
+			},
+			"order_items": {
+				Schema: `type order_items {
+	id: ID! @primary
+	order_id: ID! @index
+	product_id: ID!
+	quantity: Integer!
+	price: Float!
+}`,
+				Rules: map[string]*config.Rule{
+					"create": {Rule: "authenticated"},
+					"read":   {Rule: "authenticated"},
+					"update": {Rule: "deny"},
+					"delete": {Rule: "deny"},
+				},
+			},
+		},
+		Triggers: map[string]*config.EventingTrigger{
+			"on-order-placed":  {Type: utils.EventDBCreate, Options: map[string]string{"col": "orders"}, URL: "/on-order-placed", Retries: 3, Timeout: 5000},
+			"on-order-updated": {Type: utils.EventDBUpdate, Options: map[string]string{"col": "orders"}, URL: "/on-order-updated", Retries: 3, Timeout: 5000},
+		},
+	},
+}
+
+// templateOwnerRules returns rules which let anyone read a row while only its owner can write it
+func templateOwnerRules(ownerField string) map[string]*config.Rule {
+	return map[string]*config.Rule{
+		"create": {Rule: "match", Type: "string", Eval: "==", F1: "args.auth.id", F2: "args.doc." + ownerField},
+		"read":   {Rule: "allow"},
+		"update": {Rule: "match", Type: "string", Eval: "==", F1: "args.auth.id", F2: "args.find." + ownerField},
+		"delete": {Rule: "match", Type: "string", Eval: "==", F1: "args.auth.id", F2: "args.find." + ownerField},
+	}
+}
+
+// templateSignedInRules returns rules which let signed in users read every row while only its owner can write it
+func templateSignedInRules(ownerField string) map[string]*config.Rule {
+	rules := templateOwnerRules(ownerField)
+	rules["read"] = &config.Rule{Rule: "authenticated"}
+	return rules
+}
+
+// SetProjectTemplate registers a custom project template
+func (s *Manager) SetProjectTemplate(ctx context.Context, value *config.ProjectTemplate, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	if err := s.checkResourceSupported(ctx, config.ResourceProjectTemplate); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if _, p := builtInProjectTemplates[value.ID]; p {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project template (%s) is a built in template and cannot be replaced", value.ID), nil, nil)
+	}
+	if err := validateProjectTemplate(value); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid project template (%s) provided", value.ID), err, nil)
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	resourceID := config.GenerateResourceID(s.clusterID, "noProject", config.ResourceProjectTemplate, value.ID)
+	if s.projectConfig.ProjectTemplates == nil {
+		s.projectConfig.ProjectTemplates = config.ProjectTemplates{resourceID: value}
+	} else {
+		s.projectConfig.ProjectTemplates[resourceID] = value
+	}
+
+	if err := s.store.SetResource(ctx, resourceID, value); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// DeleteProjectTemplate deletes a custom project template
+func (s *Manager) DeleteProjectTemplate(ctx context.Context, id string, params model.RequestParams) (int, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	resourceID := config.GenerateResourceID(s.clusterID, "noProject", config.ResourceProjectTemplate, id)
+	if _, p := s.projectConfig.ProjectTemplates[resourceID]; !p {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project template (%s) does not exist", id), nil, nil)
+	}
+
+	delete(s.projectConfig.ProjectTemplates, resourceID)
+	if err := s.store.DeleteResource(ctx, resourceID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetProjectTemplates returns the built in and custom project templates
+func (s *Manager) GetProjectTemplates(ctx context.Context, id string, params model.RequestParams) (int, []interface{}, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), hookResponse.Result().([]interface{}), nil
+	}
+
+	// Acquire a lock
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if id != "*" {
+		template, ok := s.getProjectTemplate(id)
+		if !ok {
+			return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project template (%s) does not exist", id), nil, nil)
+		}
+		return http.StatusOK, []interface{}{template}, nil
+	}
+
+	templates := make([]*config.ProjectTemplate, 0, len(builtInProjectTemplates)+len(s.projectConfig.ProjectTemplates))
+	for _, template := range builtInProjectTemplates {
+		templates = append(templates, template)
+	}
+	for _, template := range s.projectConfig.ProjectTemplates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	result := make([]interface{}, len(templates))
+	for i, template := range templates {
+		result[i] = template
+	}
+	return http.StatusOK, result, nil
+}
+
+// CreateProjectFromTemplate creates a project along with the database, schema, security rules and sample event
+// triggers of a project template in one call
+func (s *Manager) CreateProjectFromTemplate(ctx context.Context, req *model.ProjectFromTemplateRequest, params model.RequestParams) (int, *model.ProjectFromTemplateResult, error) {
+	// Check if the request has been hijacked
+	hookResponse := s.integrationMan.InvokeHook(ctx, params)
+	if hookResponse.CheckResponse() {
+		// Check if an error occurred
+		if err := hookResponse.Error(); err != nil {
+			return hookResponse.Status(), nil, err
+		}
+
+		// Gracefully return
+		return hookResponse.Status(), nil, nil
+	}
+
+	if req.Project == nil || req.Project.ID == "" {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Project config with a project id must be provided", nil, nil)
+	}
+	if req.Database == nil || req.Database.DbAlias == "" || req.Database.Type == "" {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Database config with a db alias and type must be provided", nil, nil)
+	}
+
+	// Acquire a lock
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	template, ok := s.getProjectTemplate(req.Template)
+	if !ok {
+		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project template (%s) does not exist", req.Template), nil, nil)
+	}
+
+	project := req.Project
+	if _, p := s.projectConfig.Projects[project.ID]; p {
+		return http.StatusConflict, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Project (%s) already exists", project.ID), nil, nil)
+	}
+	if !s.adminMan.ValidateProjectSyncOperation(s.projectConfig, project) {
+		return http.StatusUpgradeRequired, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Upgrade your plan to create more projects", nil, nil)
+	}
+
+	// set default context time
+	if project.ContextTimeGraphQL == 0 {
+		project.ContextTimeGraphQL = 10
+	}
+
+	// Create the project
+	s.projectConfig.Projects[project.ID] = config.GenerateEmptyProject(project)
+	if s.runnerAddr != "" {
+		token, err := s.adminMan.GetInternalAccessToken()
+		if err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+		params := map[string]interface{}{"id": project.ID}
+		if err := s.MakeHTTPRequest(ctx, "POST", fmt.Sprintf("http://%s/v1/runner/project/%s", s.runnerAddr, project.ID), token, "", params, &map[string]interface{}{}); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+	if err := s.modules.SetProjectConfig(ctx, project); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	if err := s.store.SetResource(ctx, config.GenerateResourceID(s.clusterID, project.ID, config.ResourceProject, project.ID), project); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	projectConfig, err := s.getConfigWithoutLock(ctx, project.ID)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	// Connect to the database
+	dbAlias := req.Database.DbAlias
+	resourceID := config.GenerateResourceID(s.clusterID, project.ID, config.ResourceDatabaseConfig, dbAlias)
+	req.Database.Enabled = true
+	projectConfig.DatabaseConfigs[resourceID] = req.Database
+	if err := s.modules.SetDatabaseConfig(ctx, project.ID, projectConfig.DatabaseConfigs, projectConfig.DatabaseSchemas, projectConfig.DatabaseRules, projectConfig.DatabasePreparedQueries); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set crud config", err, nil)
+	}
+	if err := s.store.SetResource(ctx, resourceID, req.Database); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	// Create the tables along with their security rules
+	collections, triggers := generateTemplateResources(template, dbAlias, req.WebhookURL)
+	stub := config.CrudStub{DBName: req.Database.DBName, Collections: make(map[string]*config.TableRule, len(template.Collections))}
+	for name, collection := range template.Collections {
+		stub.Collections[name] = &config.TableRule{Schema: collection.Schema}
+	}
+	if err := s.applySchemas(ctx, project.ID, dbAlias, projectConfig, stub); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create the tables of project template (%s)", template.ID), err, nil)
+	}
+
+	result := &model.ProjectFromTemplateResult{Project: project.ID, Template: template.ID, Collections: sortedKeys(collections), Triggers: sortedKeys(triggers)}
+	for _, name := range result.Collections {
+		if status, err := s.setCollectionRules(ctx, projectConfig, project.ID, dbAlias, name, collections[name]); err != nil {
+			return status, nil, err
+		}
+	}
+
+	// Enable eventing and add the sample event triggers
+	if len(triggers) == 0 {
+		return http.StatusOK, result, nil
+	}
+	if status, err := s.setEventingConfig(ctx, projectConfig, project.ID, dbAlias, true, nil, nil); err != nil {
+		return status, nil, err
+	}
+	for _, name := range result.Triggers {
+		resourceID := config.GenerateResourceID(s.clusterID, project.ID, config.ResourceEventingTrigger, name)
+		projectConfig.EventingTriggers[resourceID] = triggers[name]
+		if err := s.store.SetResource(ctx, resourceID, triggers[name]); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+	if err := s.modules.SetEventingTriggerConfig(ctx, project.ID, projectConfig.EventingTriggers); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error setting eventing config", err, nil)
+	}
+
+	return http.StatusOK, result, nil
+}
+
+// getProjectTemplate returns the built in or custom project template with the provided id
+func (s *Manager) getProjectTemplate(id string) (*config.ProjectTemplate, bool) {
+	if template, p := builtInProjectTemplates[id]; p {
+		return template, true
+	}
+	template, p := s.projectConfig.ProjectTemplates[config.GenerateResourceID(s.clusterID, "noProject", config.ResourceProjectTemplate, id)]
+	return template, p
+}
+
+// generateTemplateResources returns the collection rules and event triggers of a project template for the provided
+// database. Triggers are left out if no webhook url is provided.
+func generateTemplateResources(template *config.ProjectTemplate, dbAlias, webhookURL string) (map[string]*config.DatabaseRule, map[string]*config.EventingTrigger) {
+	collections := make(map[string]*config.DatabaseRule, len(template.Collections))
+	for name, collection := range template.Collections {
+		rules := make(map[string]*config.Rule, len(collection.Rules))
+		for op, rule := range collection.Rules {
+			rules[op] = rule
+		}
+		collections[name] = &config.DatabaseRule{Table: name, DbAlias: dbAlias, IsRealTimeEnabled: collection.IsRealTimeEnabled, Rules: rules}
+	}
+
+	triggers := make(map[string]*config.EventingTrigger)
+	if webhookURL == "" {
+		return collections, triggers
+	}
+	for name, t := range template.Triggers {
+		trigger := *t
+		trigger.ID = name
+		if strings.HasPrefix(trigger.URL, "/") {
+			trigger.URL = strings.TrimSuffix(webhookURL, "/") + trigger.URL
+		}
+		trigger.Options = map[string]string{"db": dbAlias}
+		for k, v := range t.Options {
+			trigger.Options[k] = v
+		}
+		triggers[name] = &trigger
+	}
+	return collections, triggers
+}
+
+func validateProjectTemplate(template *config.ProjectTemplate) error {
+	if template.ID == "" {
+		return fmt.Errorf("id of the template is required")
+	}
+	if len(template.Collections) == 0 {
+		return fmt.Errorf("template must contain at least one collection")
+	}
+	for name, collection := range template.Collections {
+		if collection == nil || collection.Schema == "" {
+			return fmt.Errorf("schema of collection (%s) is required", name)
+		}
+		for op := range collection.Rules {
+			switch op {
+			case "create", "read", "update", "delete":
+			default:
+				return fmt.Errorf("invalid operation (%s) provided in the rules of collection (%s)", op, name)
+			}
+		}
+	}
+	for name, trigger := range template.Triggers {
+		if trigger == nil || trigger.Type == "" || trigger.URL == "" {
+			return fmt.Errorf("type and url of trigger (%s) are required", name)
+		}
+		if col, p := trigger.Options["col"]; p {
+			if _, p := template.Collections[col]; !p {
+				return fmt.Errorf("trigger (%s) refers to collection (%s) which is not a part of the template", name, col)
+			}
+		}
+	}
+	return nil
+}
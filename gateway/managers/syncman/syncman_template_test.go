@@ -0,0 +1,123 @@
+package syncman
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_validateProjectTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *config.ProjectTemplate
+		wantErr  bool
+	}{
+		{
+			name: "valid template",
+			template: &config.ProjectTemplate{
+				ID:          "todo",
+				Collections: map[string]*config.TemplateCollection{"todos": {Schema: "type todos { id: ID! @primary }", Rules: map[string]*config.Rule{"read": {Rule: "allow"}}}},
+				Triggers:    map[string]*config.EventingTrigger{"on-todo": {Type: utils.EventDBCreate, URL: "/on-todo", Options: map[string]string{"col": "todos"}}},
+			},
+		},
+		{
+			name:     "missing id",
+			template: &config.ProjectTemplate{Collections: map[string]*config.TemplateCollection{"todos": {Schema: "type todos { id: ID! @primary }"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "no collections",
+			template: &config.ProjectTemplate{ID: "todo"},
+			wantErr:  true,
+		},
+		{
+			name:     "collection without a schema",
+			template: &config.ProjectTemplate{ID: "todo", Collections: map[string]*config.TemplateCollection{"todos": {}}},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown rule operation",
+			template: &config.ProjectTemplate{ID: "todo", Collections: map[string]*config.TemplateCollection{"todos": {Schema: "type todos { id: ID! @primary }", Rules: map[string]*config.Rule{"query": {Rule: "allow"}}}}},
+			wantErr:  true,
+		},
+		{
+			name: "trigger on an unknown collection",
+			template: &config.ProjectTemplate{
+				ID:          "todo",
+				Collections: map[string]*config.TemplateCollection{"todos": {Schema: "type todos { id: ID! @primary }"}},
+				Triggers:    map[string]*config.EventingTrigger{"on-user": {Type: utils.EventDBCreate, URL: "/on-user", Options: map[string]string{"col": "users"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateProjectTemplate(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("validateProjectTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	for id, template := range builtInProjectTemplates {
+		if err := validateProjectTemplate(template); err != nil {
+			t.Errorf("validateProjectTemplate() built in template (%s) is invalid: %v", id, err)
+		}
+	}
+}
+
+func Test_generateTemplateResources(t *testing.T) {
+	template := &config.ProjectTemplate{
+		ID: "todo",
+		Collections: map[string]*config.TemplateCollection{
+			"todos": {Schema: "type todos { id: ID! @primary }", Rules: map[string]*config.Rule{"read": {Rule: "allow"}}, IsRealTimeEnabled: true},
+		},
+		Triggers: map[string]*config.EventingTrigger{
+			"on-todo":   {Type: utils.EventDBCreate, URL: "/on-todo", Options: map[string]string{"col": "todos"}},
+			"on-remote": {Type: utils.EventDBDelete, URL: "https://hooks.example.com/todo", Options: map[string]string{"col": "todos"}},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		webhookURL      string
+		wantCollections map[string]*config.DatabaseRule
+		wantTriggers    map[string]*config.EventingTrigger
+	}{
+		{
+			name:       "triggers are resolved against the webhook url",
+			webhookURL: "http://functions:8080/",
+			wantCollections: map[string]*config.DatabaseRule{
+				"todos": {Table: "todos", DbAlias: "db", IsRealTimeEnabled: true, Rules: map[string]*config.Rule{"read": {Rule: "allow"}}},
+			},
+			wantTriggers: map[string]*config.EventingTrigger{
+				"on-todo":   {ID: "on-todo", Type: utils.EventDBCreate, URL: "http://functions:8080/on-todo", Options: map[string]string{"db": "db", "col": "todos"}},
+				"on-remote": {ID: "on-remote", Type: utils.EventDBDelete, URL: "https://hooks.example.com/todo", Options: map[string]string{"db": "db", "col": "todos"}},
+			},
+		},
+		{
+			name: "triggers are skipped without a webhook url",
+			wantCollections: map[string]*config.DatabaseRule{
+				"todos": {Table: "todos", DbAlias: "db", IsRealTimeEnabled: true, Rules: map[string]*config.Rule{"read": {Rule: "allow"}}},
+			},
+			wantTriggers: map[string]*config.EventingTrigger{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collections, triggers := generateTemplateResources(template, "db", tt.webhookURL)
+			if !reflect.DeepEqual(collections, tt.wantCollections) {
+				t.Errorf("generateTemplateResources() collections = %v, want %v", collections, tt.wantCollections)
+			}
+			if !reflect.DeepEqual(triggers, tt.wantTriggers) {
+				t.Errorf("generateTemplateResources() triggers = %v, want %v", triggers, tt.wantTriggers)
+			}
+		})
+	}
+
+	// The template itself must not be modified
+	if template.Triggers["on-todo"].URL != "/on-todo" || template.Triggers["on-todo"].Options["db"] != "" {
+		t.Errorf("generateTemplateResources() modified the triggers of the template")
+	}
+}
@@ -0,0 +1,293 @@
+package syncman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	// heartbeatInterval is the interval at which a node advertises itself to the cluster
+	heartbeatInterval = 5 * time.Second
+
+	// heartbeatTTL is the time after which a node which stopped sending heartbeats is dropped from the topology
+	heartbeatTTL = 30 * time.Second
+
+	// suspectAfter is the time after which a node which stopped sending heartbeats is marked as suspect
+	suspectAfter = 3 * heartbeatInterval
+)
+
+// SetAdvertiseAddr sets the address and the scheme this node advertises to the other nodes of the cluster. The address
+// is either a host or a host and a port. The node advertises the first ip address of its interfaces if no address
+// is provided.
+func (s *Manager) SetAdvertiseAddr(addr, scheme string) error {
+	if scheme != "" && scheme != "http" && scheme != "https" {
+		return fmt.Errorf("invalid advertise scheme (%s) provided - it must be one of http or https", scheme)
+	}
+	if strings.Contains(addr, ":") {
+		if _, port, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid advertise address (%s) provided - %v", addr, err)
+		} else if _, err := strconv.Atoi(port); err != nil {
+			return fmt.Errorf("invalid port (%s) of advertise address (%s) provided", port, addr)
+		}
+	}
+
+	s.lockServices.Lock()
+	defer s.lockServices.Unlock()
+	s.advertiseAddr, s.advertiseScheme = addr, scheme
+	return nil
+}
+
+// GetLocalURL returns the url at which this node can reach its own apis
+func (s *Manager) GetLocalURL() string {
+	return fmt.Sprintf("http://localhost:%d", s.port)
+}
+
+// getNodeURL returns the url advertised by a node of the cluster
+func (s *Manager) getNodeURL(ctx context.Context, nodeID string) (string, error) {
+	if nodeID == s.nodeID {
+		return s.getNodeInfo().GetURL(), nil
+	}
+
+	value, err := s.pubsubClient.GetKey(ctx, s.getHeartbeatPrefix()+nodeID)
+	if err != nil {
+		return "", err
+	}
+	node := new(model.ClusterNode)
+	if err := json.Unmarshal([]byte(value), node); err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid heartbeat of node (%s) found in cluster (%s)", nodeID, s.clusterID), err, nil)
+	}
+	return node.GetURL(), nil
+}
+
+// GetClusterTopology returns every node of the cluster along with its role, health and the projects it has loaded
+func (s *Manager) GetClusterTopology(ctx context.Context) (*model.ClusterTopology, error) {
+	heartbeats, err := s.pubsubClient.GetKeysWithPrefix(ctx, s.getHeartbeatPrefix())
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to get heartbeats of the cluster nodes", err, nil)
+	}
+
+	nodes := make([]*model.ClusterNode, 0, len(heartbeats)+1)
+	for key, value := range heartbeats {
+		node := new(model.ClusterNode)
+		if err := json.Unmarshal([]byte(value), node); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid heartbeat (%s) found in cluster (%s)", key, s.clusterID), err, nil)
+			continue
+		}
+
+		// The details of this node are always up to date
+		if node.ID == s.nodeID {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	nodes = append(nodes, s.getNodeInfo())
+
+	leaderID, err := s.leader.GetLeaderNodeID(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	s.lockServices.RLock()
+	members := len(s.services)
+	s.lockServices.RUnlock()
+
+	return generateClusterTopology(time.Now(), s.clusterID, leaderID, members, nodes), nil
+}
+
+// checkResourceSupported makes sure every node of the cluster understands the resource type before it gets
+// replicated. New resource types can only be used once a rolling upgrade has completed.
+func (s *Manager) checkResourceSupported(ctx context.Context, resourceType config.Resource) error {
+	s.lockServices.RLock()
+	clusterVersion := s.clusterProtocolVersion
+	s.lockServices.RUnlock()
+
+	// The protocol version of the cluster isn't known until the node has started
+	if clusterVersion == 0 {
+		return nil
+	}
+
+	if required := config.GetResourceMinProtocolVersion(resourceType); clusterVersion < required {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Resource (%s) requires all nodes of the cluster to speak protocol version (%d) but some nodes speak version (%d) - complete the upgrade of the cluster first", resourceType, required, clusterVersion), nil, nil)
+	}
+	return nil
+}
+
+// refreshTopology negotiates the protocol version of the cluster and notifies the membership changes from the
+// heartbeats of its nodes
+func (s *Manager) refreshTopology() {
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval)
+	defer cancel()
+
+	topology, err := s.GetClusterTopology(ctx)
+	if err != nil {
+		return
+	}
+	s.updateClusterProtocolVersion(ctx, topology)
+	s.updateMembership(topology.Nodes)
+}
+
+// updateClusterProtocolVersion negotiates the protocol version of the cluster from the heartbeats of its nodes
+func (s *Manager) updateClusterProtocolVersion(ctx context.Context, topology *model.ClusterTopology) {
+	for _, node := range topology.Nodes {
+		if diff := node.ProtocolVersion - utils.ProtocolVersion; diff > 1 || diff < -1 {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Node (%s) speaks protocol version (%d) which is more than one version apart from version (%d) of this node - upgrade one version at a time", node.ID, node.ProtocolVersion, utils.ProtocolVersion), nil, nil)
+		}
+	}
+
+	s.lockServices.Lock()
+	if s.clusterProtocolVersion != topology.MinProtocolVersion {
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Protocol version of cluster (%s) is now (%d)", s.clusterID, topology.MinProtocolVersion), nil)
+	}
+	s.clusterProtocolVersion = topology.MinProtocolVersion
+	s.lockServices.Unlock()
+}
+
+// generateClusterTopology derives the role, health and uptime of the nodes from their heartbeats
+func generateClusterTopology(now time.Time, clusterID, leaderID string, members int, nodes []*model.ClusterNode) *model.ClusterTopology {
+	for _, node := range nodes {
+		switch {
+		case node.IsDraining:
+			// Draining nodes don't serve requests or take part in the leader election anymore
+			node.Role = model.ClusterRoleObserver
+		case node.ID == leaderID:
+			node.Role = model.ClusterRoleLeader
+		default:
+			node.Role = model.ClusterRoleFollower
+		}
+
+		node.Health = model.NodeHealthAlive
+		if now.Sub(node.LastHeartbeat) > suspectAfter {
+			node.Health = model.NodeHealthSuspect
+		}
+
+		node.Uptime = int64(now.Sub(node.StartedAt).Seconds())
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	// Members which don't send heartbeats predate the version handshake
+	minProtocolVersion := utils.ProtocolVersion
+	if len(nodes) < members {
+		minProtocolVersion = 1
+	}
+	for _, node := range nodes {
+		v := node.ProtocolVersion
+		if v == 0 {
+			v = 1
+		}
+		if v < minProtocolVersion {
+			minProtocolVersion = v
+		}
+	}
+
+	return &model.ClusterTopology{ClusterID: clusterID, LeaderID: leaderID, Members: members, MinProtocolVersion: minProtocolVersion, Nodes: nodes}
+}
+
+func (s *Manager) routineHeartbeat() {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.sendHeartbeat(); err != nil {
+			s.lockServices.RLock()
+			isDraining := s.isDraining
+			s.lockServices.RUnlock()
+
+			// The connection to the broker is closed once the node leaves the cluster
+			if isDraining {
+				return
+			}
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to send heartbeat to the cluster", err, nil)
+		}
+		s.refreshTopology()
+	}
+}
+
+func (s *Manager) sendHeartbeat() error {
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval)
+	defer cancel()
+
+	data, err := json.Marshal(s.getNodeInfo())
+	if err != nil {
+		return err
+	}
+	return s.pubsubClient.SetKey(ctx, s.getHeartbeatKey(), string(data), heartbeatTTL)
+}
+
+// getNodeInfo returns the details of this node advertised in its heartbeats
+func (s *Manager) getNodeInfo() *model.ClusterNode {
+	s.lockServices.RLock()
+	isDraining := s.isDraining
+	s.lockServices.RUnlock()
+
+	s.lock.RLock()
+	projects := make([]string, 0)
+	if s.projectConfig != nil {
+		for projectID := range s.projectConfig.Projects {
+			projects = append(projects, projectID)
+		}
+	}
+	s.lock.RUnlock()
+	sort.Strings(projects)
+
+	s.lockServices.RLock()
+	address, scheme := getNodeAddress(s.advertiseAddr, s.port), s.advertiseScheme
+	s.lockServices.RUnlock()
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	return &model.ClusterNode{
+		ID:              s.nodeID,
+		Address:         address,
+		Scheme:          scheme,
+		Metadata:        s.getNodeMetadata(),
+		Version:         utils.BuildVersion,
+		ProtocolVersion: utils.ProtocolVersion,
+		StartedAt:       s.startedAt,
+		Projects:        projects,
+		IsDraining:      isDraining,
+		LastHeartbeat:   time.Now(),
+	}
+}
+
+func (s *Manager) getHeartbeatPrefix() string {
+	return fmt.Sprintf("%s::node::", s.clusterID)
+}
+
+func (s *Manager) getHeartbeatKey() string {
+	return s.getHeartbeatPrefix() + s.nodeID
+}
+
+// getNodeAddress returns the address at which the other nodes can reach this node. The advertised address is preferred
+// over the address of the interfaces.
+func getNodeAddress(advertiseAddr string, port int) string {
+	if advertiseAddr != "" {
+		if strings.Contains(advertiseAddr, ":") {
+			return advertiseAddr
+		}
+		return net.JoinHostPort(advertiseAddr, strconv.Itoa(port))
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return fmt.Sprintf("%s:%d", ipNet.IP.String(), port)
+			}
+		}
+	}
+	return fmt.Sprintf("localhost:%d", port)
+}
@@ -0,0 +1,168 @@
+package syncman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_generateClusterTopology(t *testing.T) {
+	now := time.Now()
+	startedAt := now.Add(-time.Hour)
+
+	nodes := []*model.ClusterNode{
+		{ID: "node-3", ProtocolVersion: utils.ProtocolVersion, StartedAt: startedAt, LastHeartbeat: now.Add(-time.Minute)},
+		{ID: "node-1", ProtocolVersion: utils.ProtocolVersion, StartedAt: startedAt, LastHeartbeat: now, Projects: []string{"myproject"}},
+		{ID: "node-2", ProtocolVersion: utils.ProtocolVersion, StartedAt: now.Add(-time.Minute), LastHeartbeat: now.Add(-time.Second), IsDraining: true},
+	}
+
+	want := &model.ClusterTopology{
+		ClusterID:          "chicago",
+		LeaderID:           "node-1",
+		Members:            3,
+		MinProtocolVersion: utils.ProtocolVersion,
+		Nodes: []*model.ClusterNode{
+			{ID: "node-1", Role: model.ClusterRoleLeader, Health: model.NodeHealthAlive, ProtocolVersion: utils.ProtocolVersion, StartedAt: startedAt, Uptime: 3600, LastHeartbeat: now, Projects: []string{"myproject"}},
+			{ID: "node-2", Role: model.ClusterRoleObserver, Health: model.NodeHealthAlive, ProtocolVersion: utils.ProtocolVersion, StartedAt: now.Add(-time.Minute), Uptime: 60, LastHeartbeat: now.Add(-time.Second), IsDraining: true},
+			{ID: "node-3", Role: model.ClusterRoleFollower, Health: model.NodeHealthSuspect, ProtocolVersion: utils.ProtocolVersion, StartedAt: startedAt, Uptime: 3600, LastHeartbeat: now.Add(-time.Minute)},
+		},
+	}
+
+	got := generateClusterTopology(now, "chicago", "node-1", 3, nodes)
+	if arr := deep.Equal(got, want); len(arr) > 0 {
+		t.Errorf("generateClusterTopology() differences = %v", arr)
+	}
+}
+
+func Test_generateClusterTopology_MinProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []int
+		members  int
+		want     int
+	}{
+		{name: "All nodes upgraded", versions: []int{utils.ProtocolVersion, utils.ProtocolVersion}, members: 2, want: utils.ProtocolVersion},
+		{name: "Node one version behind", versions: []int{utils.ProtocolVersion, utils.ProtocolVersion - 1}, members: 2, want: utils.ProtocolVersion - 1},
+		{name: "Node one version ahead", versions: []int{utils.ProtocolVersion, utils.ProtocolVersion + 1}, members: 2, want: utils.ProtocolVersion},
+		{name: "Member without heartbeats", versions: []int{utils.ProtocolVersion}, members: 2, want: 1},
+		{name: "Heartbeat without protocol version", versions: []int{utils.ProtocolVersion, 0}, members: 2, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := make([]*model.ClusterNode, len(tt.versions))
+			for i, v := range tt.versions {
+				nodes[i] = &model.ClusterNode{ID: string(rune('a' + i)), ProtocolVersion: v, LastHeartbeat: time.Now()}
+			}
+			if got := generateClusterTopology(time.Now(), "chicago", "a", tt.members, nodes).MinProtocolVersion; got != tt.want {
+				t.Errorf("generateClusterTopology() min protocol version = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_checkResourceSupported(t *testing.T) {
+	tests := []struct {
+		name           string
+		clusterVersion int
+		resourceType   config.Resource
+		wantErr        bool
+	}{
+		{name: "Cluster version not negotiated yet", clusterVersion: 0, resourceType: config.ResourceDeploySecret},
+		{name: "Resource understood by every version", clusterVersion: 1, resourceType: config.ResourceDatabaseRule},
+		{name: "New resource in an upgraded cluster", clusterVersion: 2, resourceType: config.ResourceDeploySecret},
+		{name: "New resource during a rolling upgrade", clusterVersion: 1, resourceType: config.ResourceDeploySecret, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Manager{clusterProtocolVersion: tt.clusterVersion}
+			if err := s.checkResourceSupported(context.Background(), tt.resourceType); (err != nil) != tt.wantErr {
+				t.Errorf("checkResourceSupported() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_SetAdvertiseAddr(t *testing.T) {
+	tests := []struct {
+		name        string
+		addr        string
+		scheme      string
+		wantAddress string
+		wantURL     string
+		wantErr     bool
+	}{
+		{name: "Host with the port of the apis", addr: "gateway.example.com", scheme: "https", wantAddress: "gateway.example.com:4122", wantURL: "https://gateway.example.com:4122"},
+		{name: "Host and port behind a nat", addr: "203.0.113.10:8080", wantAddress: "203.0.113.10:8080", wantURL: "http://203.0.113.10:8080"},
+		{name: "Ipv6 host and port", addr: "[2001:db8::1]:4122", scheme: "http", wantAddress: "[2001:db8::1]:4122", wantURL: "http://[2001:db8::1]:4122"},
+		{name: "Invalid scheme", addr: "gateway.example.com", scheme: "ftp", wantErr: true},
+		{name: "Invalid port", addr: "gateway.example.com:http", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Manager{nodeID: "node-1", port: 4122}
+			if err := s.SetAdvertiseAddr(tt.addr, tt.scheme); (err != nil) != tt.wantErr {
+				t.Fatalf("SetAdvertiseAddr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			node := s.getNodeInfo()
+			if node.Address != tt.wantAddress {
+				t.Errorf("getNodeInfo() address = %v, want %v", node.Address, tt.wantAddress)
+			}
+			if got := node.GetURL(); got != tt.wantURL {
+				t.Errorf("GetURL() = %v, want %v", got, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestManager_GetClusterRole_LeaderURL(t *testing.T) {
+	ctx := context.Background()
+	leader, err := New("node-1", "cluster", "memory", ClusterModeNone, "", nil, nil, &config.SSL{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = leader.leader.Resign(ctx) }()
+	leader.port = 4122
+	if err := leader.SetAdvertiseAddr("leader.example.com:443", "https"); err != nil {
+		t.Fatalf("SetAdvertiseAddr() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !leader.IsLeader() {
+		if time.Now().After(deadline) {
+			t.Fatalf("New() the node didn't become the leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The leader knows its own url before sending any heartbeat
+	role, err := leader.GetClusterRole(ctx)
+	if err != nil {
+		t.Fatalf("GetClusterRole() error = %v", err)
+	}
+	if role.LeaderURL != "https://leader.example.com:443" {
+		t.Errorf("GetClusterRole() leader url = %v, want https://leader.example.com:443", role.LeaderURL)
+	}
+
+	// The followers learn the url of the leader from its heartbeat
+	if err := leader.sendHeartbeat(); err != nil {
+		t.Fatalf("sendHeartbeat() error = %v", err)
+	}
+	follower := &Manager{nodeID: "node-2", clusterID: "cluster", pubsubClient: leader.pubsubClient}
+	url, err := follower.getNodeURL(ctx, "node-1")
+	if err != nil {
+		t.Fatalf("getNodeURL() error = %v", err)
+	}
+	if url != "https://leader.example.com:443" {
+		t.Errorf("getNodeURL() = %v, want https://leader.example.com:443", url)
+	}
+}
@@ -17,6 +17,8 @@ type AdminSyncmanInterface interface {
 	SetServices(eventType string, services model.ScServices)
 	ValidateProjectSyncOperation(c *config.Config, project *config.ProjectConfig) bool
 	SetIntegrationConfig(integrations config.Integrations)
+	SetAdminTokens(tokens config.AdminTokens)
+	IssueAdminToken(token *config.AdminToken) (string, error)
 
 	// For integrations
 	GetIntegrationToken(id string) (string, error)
@@ -51,6 +53,16 @@ type ModulesInterface interface {
 	SetRemoteServiceConfig(ctx context.Context, projectID string, services config.Services) error
 
 	SetLetsencryptConfig(ctx context.Context, projectID string, c *config.LetsEncrypt) error
+	// SetSearchConfig sets the config of the full text search module
+	SetSearchConfig(ctx context.Context, projectID string, c *config.SearchConfig) error
+	// SetBackupConfig sets the config of the backup module
+	SetBackupConfig(ctx context.Context, projectID string, c *config.BackupConfig) error
+	// SetKVConfig sets the config of the key value store
+	SetKVConfig(ctx context.Context, projectID string, c *config.KVConfig) error
+	// SetPrivacyConfig sets the config of the privacy module
+	SetPrivacyConfig(ctx context.Context, projectID string, c *config.PrivacyConfig) error
+	// SetFeatureFlagsConfig sets the feature flags of the project
+	SetFeatureFlagsConfig(ctx context.Context, projectID string, featureFlags config.FeatureFlags) error
 
 	SetIngressRouteConfig(ctx context.Context, projectID string, routes config.IngressRoutes) error
 	SetIngressGlobalRouteConfig(ctx context.Context, projectID string, c *config.GlobalRoutesConfig) error
@@ -60,6 +72,8 @@ type ModulesInterface interface {
 	SetEventingSchemaConfig(ctx context.Context, projectID string, schemaObj config.EventingSchemas) error
 	SetEventingTriggerConfig(ctx context.Context, projectID string, triggerObj config.EventingTriggers) error
 	SetEventingRuleConfig(ctx context.Context, projectID string, secureObj config.EventingRules) error
+	SetEventingSourceConfig(ctx context.Context, projectID string, sources config.EventingSources) error
+	SetEventingWorkflowConfig(ctx context.Context, projectID string, workflows config.EventingWorkflows) error
 
 	// SetUsermanConfig set the config of the userman module
 	SetUsermanConfig(ctx context.Context, projectID string, auth config.Auths) error
@@ -79,4 +93,22 @@ type ModulesInterface interface {
 type GlobalModulesInterface interface {
 	// SetMetricsConfig set the config of the metrics module
 	SetMetricsConfig(isMetricsEnabled bool)
+
+	// SetLoggingConfig sets the config of the structured request logger
+	SetLoggingConfig(c *config.LoggingConfig) error
+
+	// SetAccountingConfig sets the config of the resource usage export
+	SetAccountingConfig(c *config.AccountingConfig) error
+
+	// SetAdmissionConfig sets the config of the admission control
+	SetAdmissionConfig(c *config.AdmissionConfig) error
+
+	// SetCompressionConfig sets the config of the response compression
+	SetCompressionConfig(c *config.CompressionConfig) error
+
+	// SetMetricsSinksConfig sets the config of the sinks the metrics are pushed to
+	SetMetricsSinksConfig(c *config.MetricsConfig) error
+
+	// SetDiagnosticsConfig sets the config of the runtime diagnostics
+	SetDiagnosticsConfig(c *config.DiagnosticsConfig) error
 }
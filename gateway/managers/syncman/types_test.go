@@ -84,6 +84,15 @@ func (m *mockAdminSyncmanInterface) SetIntegrationConfig(integrations config.Int
 	m.Called(integrations)
 }
 
+func (m *mockAdminSyncmanInterface) SetAdminTokens(tokens config.AdminTokens) {
+	m.Called(tokens)
+}
+
+func (m *mockAdminSyncmanInterface) IssueAdminToken(token *config.AdminToken) (string, error) {
+	c := m.Called(token)
+	return c.String(0), c.Error(1)
+}
+
 func (m *mockAdminSyncmanInterface) ValidateIntegrationSyncOperation(integrations config.Integrations) error {
 	return m.Called(integrations).Error(0)
 }
@@ -170,6 +179,26 @@ func (m *mockModulesInterface) SetLetsencryptConfig(ctx context.Context, project
 	return m.Called(ctx, projectID, c).Error(0)
 }
 
+func (m *mockModulesInterface) SetSearchConfig(ctx context.Context, projectID string, c *config.SearchConfig) error {
+	return m.Called(ctx, projectID, c).Error(0)
+}
+
+func (m *mockModulesInterface) SetBackupConfig(ctx context.Context, projectID string, c *config.BackupConfig) error {
+	return m.Called(ctx, projectID, c).Error(0)
+}
+
+func (m *mockModulesInterface) SetKVConfig(ctx context.Context, projectID string, c *config.KVConfig) error {
+	return m.Called(ctx, projectID, c).Error(0)
+}
+
+func (m *mockModulesInterface) SetPrivacyConfig(ctx context.Context, projectID string, c *config.PrivacyConfig) error {
+	return m.Called(ctx, projectID, c).Error(0)
+}
+
+func (m *mockModulesInterface) SetFeatureFlagsConfig(ctx context.Context, projectID string, featureFlags config.FeatureFlags) error {
+	return m.Called(ctx, projectID, featureFlags).Error(0)
+}
+
 func (m *mockModulesInterface) SetIngressRouteConfig(ctx context.Context, projectID string, routes config.IngressRoutes) error {
 	return m.Called(ctx, projectID, routes).Error(0)
 }
@@ -187,6 +216,14 @@ func (m *mockModulesInterface) SetEventingSchemaConfig(ctx context.Context, proj
 	return m.Called(ctx, projectID, schemaObj).Error(0)
 }
 
+func (m *mockModulesInterface) SetEventingSourceConfig(ctx context.Context, projectID string, sources config.EventingSources) error {
+	return m.Called(ctx, projectID, sources).Error(0)
+}
+
+func (m *mockModulesInterface) SetEventingWorkflowConfig(ctx context.Context, projectID string, workflows config.EventingWorkflows) error {
+	return m.Called(ctx, projectID, workflows).Error(0)
+}
+
 func (m *mockModulesInterface) SetEventingTriggerConfig(ctx context.Context, projectID string, triggerObj config.EventingTriggers) error {
 	return m.Called(ctx, projectID, triggerObj).Error(0)
 }
@@ -336,3 +373,18 @@ func (m *mockSchemaEventingInterface) GetSchemaForDB(ctx context.Context, dbAlia
 	c := m.Called(ctx, dbAlias, col, format)
 	return c.Get(0).([]interface{}), c.Error(1)
 }
+
+func (m *mockSchemaEventingInterface) GetRowLevelSecurityQueries(ctx context.Context, dbAlias, logicalDBName, col string, rules map[string]*config.Rule) ([]string, error) {
+	c := m.Called(ctx, dbAlias, logicalDBName, col, rules)
+	return c.Get(0).([]string), c.Error(1)
+}
+
+func (m *mockSchemaEventingInterface) ApplyRowLevelSecurity(ctx context.Context, dbAlias, logicalDBName, col, secret string, rules map[string]*config.Rule) error {
+	c := m.Called(ctx, dbAlias, logicalDBName, col, secret, rules)
+	return c.Error(0)
+}
+
+func (m *mockSchemaEventingInterface) SetRowLevelSecuritySecret(ctx context.Context, dbAlias, logicalDBName, secret string) error {
+	c := m.Called(ctx, dbAlias, logicalDBName, secret)
+	return c.Error(0)
+}
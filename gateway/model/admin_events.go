@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// The types of the admin events
+const (
+	// AdminEventConfigApplied is emitted by a node once it has applied a change of the config
+	AdminEventConfigApplied = "config-applied"
+	// AdminEventNodeJoined and AdminEventNodeLeft are emitted when a node joins or leaves the cluster
+	AdminEventNodeJoined = "node-joined"
+	AdminEventNodeLeft   = "node-left"
+	// AdminEventNodeUpdated is emitted when a node changes the address or metadata it advertises
+	AdminEventNodeUpdated = "node-updated"
+	// AdminEventLeaderChanged is emitted by a node once it becomes the leader of the cluster
+	AdminEventLeaderChanged = "leader-changed"
+	// AdminEventDLQGrowth is emitted when the number of events in the dead letter queue of a project grows
+	AdminEventDLQGrowth = "eventing-dlq-growth"
+)
+
+// AdminEvent is an event of the cluster streamed to admin clients like mission control, so that they can update live
+// instead of polling
+type AdminEvent struct {
+	Type string `json:"type"`
+	// NodeID is the node which emitted the event
+	NodeID    string                 `json:"nodeId"`
+	Project   string                 `json:"project,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "github.com/spaceuptech/space-cloud/gateway/config"
+
+// AdminTokenRequest is the request to issue a scoped admin token
+type AdminTokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresAt is the unix timestamp in seconds after which the token is rejected. The token never expires if it's zero.
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// AdminTokenResponse holds a newly issued admin token. The token itself can't be retrieved later on.
+type AdminTokenResponse struct {
+	Token   string             `json:"token"`
+	Details *config.AdminToken `json:"details"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// The triggers of a backup
+const (
+	BackupTriggerScheduled = "scheduled"
+	BackupTriggerManual    = "manual"
+)
+
+// BackupInfo describes a backup of the data of a project
+type BackupInfo struct {
+	ID          string                  `json:"id"`
+	Project     string                  `json:"project"`
+	Trigger     string                  `json:"trigger"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	Duration    string                  `json:"duration"`
+	Collections []*BackupCollectionInfo `json:"collections"`
+}
+
+// BackupCollectionInfo describes the documents of a collection stored in a backup
+type BackupCollectionInfo struct {
+	DbAlias   string `json:"dbAlias"`
+	Col       string `json:"col"`
+	Documents int64  `json:"documents"`
+	File      string `json:"file"`
+}
+
+// BackupRestoreRequest is the request to restore a backup. All collections of the backup are restored if none are provided.
+// Truncate deletes the documents of a collection before the documents of the backup are written to it.
+type BackupRestoreRequest struct {
+	Collections []*config.BackupCollection `json:"collections,omitempty"`
+	Truncate    bool                       `json:"truncate"`
+}
+
+// BackupRestoreResult describes the documents restored in every collection
+type BackupRestoreResult struct {
+	Backup      string                     `json:"backup"`
+	Collections []*BackupRestoreCollection `json:"collections"`
+}
+
+// BackupRestoreCollection describes the documents restored in a collection
+type BackupRestoreCollection struct {
+	DbAlias string            `json:"dbAlias"`
+	Col     string            `json:"col"`
+	Result  *BulkImportResult `json:"result"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// BulkImportResult describes the outcome of a bulk import of documents in a collection
+type BulkImportResult struct {
+	Total    int `json:"total"`
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"`
+	// Errors holds the errors of the rows which could not be imported. Only the first few errors are reported
+	Errors          []*BulkRowError `json:"errors"`
+	ErrorsTruncated bool            `json:"errorsTruncated,omitempty"`
+}
+
+// BulkRowError is the error of a row of a bulk import. Row is the 1 based position of the document in the file.
+type BulkRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
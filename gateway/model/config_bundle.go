@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// ConfigBundleVersion is the version of the config bundle format
+const ConfigBundleVersion = 1
+
+// The strategies with which a config bundle can be imported
+const (
+	// ConfigImportMerge adds or overwrites the resources present in the bundle and leaves the rest untouched
+	ConfigImportMerge = "merge"
+	// ConfigImportReplace additionally deletes the projects and project resources which are absent in the bundle
+	ConfigImportReplace = "replace"
+)
+
+// ConfigBundle is a snapshot of the config of all projects along with the cluster level config
+type ConfigBundle struct {
+	Version    int            `json:"version" yaml:"version"`
+	ClusterID  string         `json:"clusterId" yaml:"clusterId"`
+	ExportedAt time.Time      `json:"exportedAt" yaml:"exportedAt"`
+	Config     *config.Config `json:"config" yaml:"config"`
+}
+
+// ConfigImportResult describes the changes made while importing a config bundle
+type ConfigImportResult struct {
+	Strategy        string   `json:"strategy"`
+	Applied         int      `json:"applied"`
+	Unchanged       int      `json:"unchanged"`
+	Deleted         int      `json:"deleted"`
+	DeletedProjects []string `json:"deletedProjects,omitempty"`
+}
+
+// ConfigDrift lists the resources of the cluster which differ from a config bundle
+type ConfigDrift struct {
+	// Added resources are present in the cluster but absent in the bundle
+	Added []string `json:"added"`
+	// Modified resources are present in both but differ
+	Modified []string `json:"modified"`
+	// Missing resources are present in the bundle but absent in the cluster
+	Missing []string `json:"missing"`
+}
+
+// HasDrift returns true if the cluster differs from the bundle
+func (d *ConfigDrift) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Modified) > 0 || len(d.Missing) > 0
+}
+
+// The kinds of changes of a config diff
+const (
+	// ConfigChangeAdded resources are absent in the base config
+	ConfigChangeAdded = "added"
+	// ConfigChangeModified resources are present in both configs but differ
+	ConfigChangeModified = "modified"
+	// ConfigChangeRemoved resources are absent in the new config
+	ConfigChangeRemoved = "removed"
+)
+
+// ConfigDiffRequest is the request to diff the config of a project. The config is compared with the live config of
+// the project unless a base config to compare it with is provided.
+type ConfigDiffRequest struct {
+	Config *config.Project `json:"config" yaml:"config"`
+	From   *config.Project `json:"from,omitempty" yaml:"from,omitempty"`
+}
+
+// ConfigDiff is the difference between two versions of the config of a project grouped by the module a resource belongs to
+type ConfigDiff struct {
+	Project    string                     `json:"project"`
+	HasChanges bool                       `json:"hasChanges"`
+	Added      int                        `json:"added"`
+	Modified   int                        `json:"modified"`
+	Removed    int                        `json:"removed"`
+	Modules    map[string][]*ResourceDiff `json:"modules"`
+}
+
+// ResourceDiff describes the change of a single resource. Fields are only listed for modified resources.
+type ResourceDiff struct {
+	ID     string          `json:"id"`
+	Type   config.Resource `json:"type"`
+	Change string          `json:"change"`
+	Fields []*FieldDiff    `json:"fields,omitempty"`
+}
+
+// FieldDiff describes the change of a single field of a resource. Values of secret fields are masked.
+type FieldDiff struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// IncrementRequest is the http body received for an atomic increment of the counter fields of a document
+type IncrementRequest struct {
+	Find map[string]interface{} `json:"find"`
+	// Inc holds the amount every counter field is incremented by. Negative amounts decrement the counter.
+	Inc map[string]interface{} `json:"inc"`
+	// Upsert creates the document matching the where clause with the counters set to the amounts if it doesn't exist.
+	// On postgres and mysql the fields of the where clause must make up a unique key of the table. Increments of sharded
+	// counters never create the document.
+	Upsert bool `json:"upsert"`
+	// Returning makes the operation return the incremented documents
+	Returning bool `json:"returning"`
+}
@@ -1,12 +1,18 @@
 package model
 
-import "github.com/spaceuptech/space-cloud/gateway/config"
+import (
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
 
 // CreateRequest is the http body received for a create request
 type CreateRequest struct {
 	Document  interface{} `json:"doc"`
 	Operation string      `json:"op"`
 	IsBatch   bool        `json:"isBatch"`
+	// Returning makes the operation return the created documents as stored in the database
+	Returning bool `json:"returning"`
 }
 
 // ReadRequest is the http body received for a read request
@@ -35,7 +41,17 @@ type ReadOptions struct {
 	Distinct   *string          `json:"distinct"`
 	Join       []*JoinOption    `json:"join"`
 	ReturnType string           `json:"returnType"`
-	HasOptions bool             `json:"hasOptions"` // used internally
+	// ReadAt reads the documents as they were at the provided time (RFC3339). It is only supported on the collections
+	// marked with the @history directive.
+	ReadAt string `json:"readAt"`
+	// PageInfo makes the read return the pagination metadata of the page along with the documents
+	PageInfo bool `json:"pageInfo"`
+	// TotalCount makes the read return the number of documents matching the where clause. It is either exact or
+	// estimated. Exact counts are estimated unless the database allows them.
+	TotalCount string `json:"totalCount"`
+	// After is the cursor of the document after which the page starts. It takes precedence over skip.
+	After      string `json:"after"`
+	HasOptions bool   `json:"hasOptions"` // used internally
 }
 
 // JoinOption describes the way a join needs to be performed
@@ -50,19 +66,38 @@ type JoinOption struct {
 	As    string                 `json:"as" mapstructure:"as"`
 	On    map[string]interface{} `json:"on" mapstructure:"on"`
 	Join  []*JoinOption          `json:"join" mapstructure:"join"`
+	// Strategy decides the way the rows of the joined table are fetched. They are flattened into the rows of the
+	// parent table unless the strategy is JoinStrategyJSON.
+	Strategy string `json:"strategy,omitempty" mapstructure:"strategy"`
+	// MatchWhere holds the conditions of the rules of the joined table for joins using JoinStrategyJSON, since they
+	// are applied while aggregating the rows of the joined table
+	MatchWhere []map[string]interface{} `json:"-" mapstructure:"-"`
 }
 
+// The strategies of performing a join
+const (
+	// JoinStrategyRows flattens the rows of the joined table into the rows of the parent table
+	JoinStrategyRows = "rows"
+	// JoinStrategyJSON aggregates the rows of the joined table into a json array of each row of the parent table. The
+	// parent rows don't get multiplied by the joined rows, hence limits apply to the parent table only.
+	JoinStrategyJSON = "json"
+)
+
 // UpdateRequest is the http body received for an update request
 type UpdateRequest struct {
 	Find      map[string]interface{} `json:"find"`
 	Operation string                 `json:"op"`
 	Update    map[string]interface{} `json:"update"`
+	// Returning makes the operation return the updated documents
+	Returning bool `json:"returning"`
 }
 
 // DeleteRequest is the http body received for a delete request
 type DeleteRequest struct {
 	Find      map[string]interface{} `json:"find"`
 	Operation string                 `json:"op"`
+	// Returning makes the operation return the deleted documents
+	Returning bool `json:"returning"`
 }
 
 // PreparedQueryRequest is the http body received for a PreparedQuery request
@@ -100,14 +135,62 @@ type SQLMetaData struct {
 	QueryTime string        `json:"queryTime" structs:"queryTime"`
 }
 
+// DBPoolStats stores the connection pool statistics of a database
+type DBPoolStats struct {
+	MaxOpenConnections int   `json:"maxOpenConnections"`
+	OpenConnections    int   `json:"openConnections"`
+	InUse              int   `json:"inUse"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"waitCount"`
+}
+
+// QueryStats stores the latency distribution of an operation on a collection
+type QueryStats struct {
+	Col    string  `json:"col"`
+	Op     string  `json:"op"`
+	Count  int64   `json:"count"`
+	Errors int64   `json:"errors"`
+	AvgMS  float64 `json:"avgMs"`
+	P50MS  float64 `json:"p50Ms"`
+	P95MS  float64 `json:"p95Ms"`
+	P99MS  float64 `json:"p99Ms"`
+	MaxMS  float64 `json:"maxMs"`
+}
+
+// SlowQuery is a query which took longer than the slow query threshold of the database.
+// The values in the query are replaced by placeholders.
+type SlowQuery struct {
+	Time       time.Time   `json:"ts"`
+	RequestID  string      `json:"requestId,omitempty"`
+	Col        string      `json:"col,omitempty"`
+	Op         string      `json:"op"`
+	DurationMS float64     `json:"durationMs"`
+	Query      interface{} `json:"query,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// SlowQueryResponse is the response of the slow query api
+type SlowQueryResponse struct {
+	Stats       []*QueryStats `json:"stats"`
+	SlowQueries []*SlowQuery  `json:"slowQueries"`
+}
+
 // BatchRequest is the http body for a batch request
 type BatchRequest struct {
 	Requests []*AllRequest `json:"reqs"`
+	// Events are published if and only if the batch gets committed
+	Events []*QueueEventRequest `json:"events,omitempty"`
 }
 
 // DBType is the type of database used for a particular crud operation
 type DBType string
 
+// IsReadOnlyDB returns whether the database only serves reads. Writes and realtime queries aren't supported on such
+// databases.
+func IsReadOnlyDB(dbType string) bool {
+	return dbType == string(BigQuery) || dbType == string(ClickHouse)
+}
+
 const (
 	// Mongo is the type used for MongoDB
 	Mongo DBType = "mongo"
@@ -124,9 +207,56 @@ const (
 	// SQLServer is the type used for MsSQL
 	SQLServer DBType = "sqlserver"
 
+	// Redis is the type used for Redis
+	Redis DBType = "redis"
+
+	// BigQuery is the type used for Google BigQuery. It is read only.
+	BigQuery DBType = "bigquery"
+
+	// ClickHouse is the type used for ClickHouse. It is read only.
+	ClickHouse DBType = "clickhouse"
+
 	// DefaultValidate is used for default validation operation
 	DefaultValidate = "default"
 
 	// DefaultFetchLimit is the default value to be used as a limit to fetch rows/collection in each read query
 	DefaultFetchLimit = 1000
 )
+
+// View is a read-only virtual collection backed by a raw sql query or a mongo aggregation pipeline
+type View struct {
+	Name string
+	// SQL is the query of a sql view and Args are the values bound to its placeholders
+	SQL  string
+	Args []interface{}
+	// Pipeline is the aggregation pipeline of a mongo view with its arguments already bound. It runs on Col.
+	Col      string
+	Pipeline []interface{}
+}
+
+// WriteHookRequest is the payload sent to the remote service configured as a write hook of a collection
+type WriteHookRequest struct {
+	DBAlias string        `json:"db"`
+	Col     string        `json:"col"`
+	Op      OperationType `json:"op"`
+	Stage   string        `json:"stage"` // before or after
+
+	// The fields of the write. Doc is only set for creates and Update for updates.
+	Doc    interface{}            `json:"doc,omitempty"`
+	Find   map[string]interface{} `json:"find,omitempty"`
+	Update map[string]interface{} `json:"update,omitempty"`
+
+	// Count and Result are sent to the after write hook. The documents affected by the write are only sent if the
+	// request asked for them.
+	Count  int64         `json:"count,omitempty"`
+	Result []interface{} `json:"result,omitempty"`
+}
+
+// WriteHookResponse is the response of a before write hook. The fields which are set replace the corresponding fields
+// of the write, while an error rejects it.
+type WriteHookResponse struct {
+	Doc    interface{}            `json:"doc" mapstructure:"doc"`
+	Find   map[string]interface{} `json:"find" mapstructure:"find"`
+	Update map[string]interface{} `json:"update" mapstructure:"update"`
+	Error  string                 `json:"error" mapstructure:"error"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// RuntimeStats describes the memory and the garbage collection of a gateway
+type RuntimeStats struct {
+	GoVersion  string `json:"goVersion"`
+	NumCPU     int    `json:"numCpu"`
+	Goroutines int    `json:"goroutines"`
+
+	// Memory stats are in bytes
+	HeapAlloc    uint64 `json:"heapAlloc"`
+	HeapInuse    uint64 `json:"heapInuse"`
+	HeapIdle     uint64 `json:"heapIdle"`
+	HeapReleased uint64 `json:"heapReleased"`
+	HeapObjects  uint64 `json:"heapObjects"`
+	StackInuse   uint64 `json:"stackInuse"`
+	Sys          uint64 `json:"sys"`
+	TotalAlloc   uint64 `json:"totalAlloc"`
+	Mallocs      uint64 `json:"mallocs"`
+	Frees        uint64 `json:"frees"`
+
+	NumGC         uint32    `json:"numGc"`
+	NextGC        uint64    `json:"nextGc"`
+	LastGC        time.Time `json:"lastGc"`
+	PauseTotal    string    `json:"pauseTotal"`
+	GCCPUFraction float64   `json:"gcCpuFraction"`
+	// RecentPauses are the durations of the most recent gc pauses, the latest first
+	RecentPauses []string `json:"recentPauses"`
+}
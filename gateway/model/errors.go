@@ -0,0 +1,52 @@
+package model
+
+// ErrorResponse is the envelope of every error returned by the apis of space cloud. Error holds the human readable
+// message and is kept for compatibility with older clients. Code is a stable machine readable identifier of the
+// failure and Module is the module which served the request. Retryable tells the clients if retrying the same request
+// later can succeed. Details carries additional information specific to the code, like the violated validation rule.
+// RequestID is the id of the request which failed, to be quoted when looking up the logs of the request.
+type ErrorResponse struct {
+	Error     string                 `json:"error"`
+	RawError  string                 `json:"rawError"`
+	Code      string                 `json:"code"`
+	Module    string                 `json:"module,omitempty"`
+	Retryable bool                   `json:"retryable"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+}
+
+// The codes of the errors returned by the apis. Codes are part of the api contract and must never be renamed.
+const (
+	// ErrorCodeInvalidRequest is returned when the request is malformed or fails validation
+	ErrorCodeInvalidRequest = "invalid_request"
+	// ErrorCodeUnauthenticated is returned when the token is missing or invalid
+	ErrorCodeUnauthenticated = "unauthenticated"
+	// ErrorCodePermissionDenied is returned when the security rules or admin scopes deny the request
+	ErrorCodePermissionDenied = "permission_denied"
+	// ErrorCodeNotFound is returned when the requested resource doesn't exist
+	ErrorCodeNotFound = "not_found"
+	// ErrorCodeConflict is returned when the request conflicts with the current state of the resource
+	ErrorCodeConflict = "conflict"
+	// ErrorCodeVersionConflict is returned when a document was updated against a stale version
+	ErrorCodeVersionConflict = "version_conflict"
+	// ErrorCodeValidationFailed is returned when a document violates the validation directives of its schema
+	ErrorCodeValidationFailed = "validation_failed"
+	// ErrorCodePayloadTooLarge is returned when the body of the request exceeds the allowed size
+	ErrorCodePayloadTooLarge = "payload_too_large"
+	// ErrorCodeUnprocessable is returned when the request is well formed but can't be processed
+	ErrorCodeUnprocessable = "unprocessable"
+	// ErrorCodeIdempotencyKeyReused is returned when an idempotency key is reused for a different request
+	ErrorCodeIdempotencyKeyReused = "idempotency_key_reused"
+	// ErrorCodeRequestInProgress is returned when a request with the same idempotency key is still being processed
+	ErrorCodeRequestInProgress = "request_in_progress"
+	// ErrorCodeRateLimited is returned when the caller exceeded its rate limit or quota
+	ErrorCodeRateLimited = "rate_limited"
+	// ErrorCodeInternal is returned when the request failed due to an unexpected error
+	ErrorCodeInternal = "internal"
+	// ErrorCodeUpstream is returned when a database, service or node the request depends on failed
+	ErrorCodeUpstream = "upstream_error"
+	// ErrorCodeUnavailable is returned when the node can't serve the request at the moment
+	ErrorCodeUnavailable = "unavailable"
+	// ErrorCodeTimeout is returned when the request didn't complete in time
+	ErrorCodeTimeout = "timeout"
+)
@@ -79,3 +79,20 @@ type EventResponseMessage struct {
 	BatchID  string      `json:"batchId" mapstructure:"batchId"`
 	Response interface{} `json:"response" mapstructure:"response"`
 }
+
+// DeadLetterEvent is an event in the dead letter queue along with the history of its invocations
+type DeadLetterEvent struct {
+	*EventDocument
+	Invocations []*InvocationDocument `json:"invocations"`
+}
+
+// EventLogFilter selects events from the event log
+type EventLogFilter struct {
+	IDs     []string `json:"ids,omitempty"`
+	Trigger string   `json:"trigger,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	From    string   `json:"from,omitempty"` // RFC3339 timestamp
+	To      string   `json:"to,omitempty"`   // RFC3339 timestamp
+	Skip    int64    `json:"skip,omitempty"`
+	Limit   int64    `json:"limit,omitempty"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// FixturesResult describes the fixtures loaded into or removed from a project
+type FixturesResult struct {
+	Collections []*CollectionFixtureResult `json:"collections"`
+	Files       int                        `json:"files"`
+}
+
+// CollectionFixtureResult is the number of documents loaded into or removed from a collection. Truncated collections
+// report the number of documents loaded.
+type CollectionFixtureResult struct {
+	DbAlias string `json:"dbAlias"`
+	Col     string `json:"col"`
+	Count   int    `json:"count"`
+}
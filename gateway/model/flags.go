@@ -0,0 +1,13 @@
+package model
+
+// FlagsRequest is the request to subscribe to the feature flags of a project over a websocket
+type FlagsRequest struct {
+	Token string `json:"token" mapstructure:"token"`
+}
+
+// FlagsResponse is the response to a feature flags subscription. It carries the current values of the flags.
+type FlagsResponse struct {
+	Ack   bool            `json:"ack"`
+	Flags map[string]bool `json:"flags,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
@@ -8,3 +8,32 @@ type FunctionsRequest struct {
 	Timeout int                      `json:"timeout"`
 	Cache   *config.ReadCacheOptions `json:"cache"`
 }
+
+// FunctionsStreamChunk is a line of the streamed response of a remote service. A service streams its response by
+// responding with the `application/x-ndjson` content type and writing a json object per line. The line of type
+// `result` has the final result of the call. The other lines, like `chunk` and `progress`, are forwarded to the
+// client as they arrive.
+type FunctionsStreamChunk struct {
+	Type     string      `json:"type"`
+	Progress float64     `json:"progress,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+}
+
+// FunctionsStreamCallback is used to forward the chunks of a streamed response
+type FunctionsStreamCallback func(chunk *FunctionsStreamChunk)
+
+// WebsocketFunctionsRequest is the request to call a function over a websocket
+type WebsocketFunctionsRequest struct {
+	Service  string      `json:"service"`
+	Endpoint string      `json:"endpoint"`
+	Token    string      `json:"token"`
+	Params   interface{} `json:"params"`
+}
+
+// WebsocketFunctionsResponse is the final response of a function called over a websocket
+type WebsocketFunctionsResponse struct {
+	Ack    bool        `json:"ack"`
+	Status int         `json:"status,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
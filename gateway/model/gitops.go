@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// The statuses of a gitops sync
+const (
+	GitOpsStatusSynced = "synced"
+	GitOpsStatusFailed = "failed"
+)
+
+// The events which trigger a gitops sync
+const (
+	GitOpsTriggerPoll    = "poll"
+	GitOpsTriggerWebhook = "webhook"
+	GitOpsTriggerManual  = "manual"
+)
+
+// GitOpsSync describes the sync of a commit of the gitops repository
+type GitOpsSync struct {
+	Commit    string              `json:"commit,omitempty"`
+	Trigger   string              `json:"trigger"`
+	Status    string              `json:"status"`
+	Error     string              `json:"error,omitempty"`
+	Result    *ConfigImportResult `json:"result,omitempty"`
+	StartedAt time.Time           `json:"startedAt"`
+	Duration  string              `json:"duration"`
+}
+
+// GitOpsStatus is the status of the gitops sync mode. Drift lists the changes made to the cluster
+// outside of the repository since the last successful sync.
+type GitOpsStatus struct {
+	Enabled    bool          `json:"enabled"`
+	Repository string        `json:"repository,omitempty"`
+	Branch     string        `json:"branch,omitempty"`
+	Path       string        `json:"path,omitempty"`
+	Strategy   string        `json:"strategy,omitempty"`
+	LastSync   *GitOpsSync   `json:"lastSync,omitempty"`
+	History    []*GitOpsSync `json:"history,omitempty"`
+	Drifted    bool          `json:"drifted"`
+	Drift      *ConfigDrift  `json:"drift,omitempty"`
+}
@@ -7,6 +7,19 @@ type GraphQLRequest struct {
 	Query         string                 `json:"query"`
 	OperationName string                 `json:"operationName"`
 	Variables     map[string]interface{} `json:"variables"`
+	Extensions    *GraphQLExtensions     `json:"extensions,omitempty"`
+}
+
+// GraphQLExtensions holds the extensions of a graphql request
+type GraphQLExtensions struct {
+	PersistedQuery *PersistedQuery `json:"persistedQuery,omitempty"`
+}
+
+// PersistedQuery identifies a query by the sha256 hash of its text. The query text can be omitted from the request
+// once the query is known to the gateway.
+type PersistedQuery struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
 }
 
 // ReadRequestKey is the key type for the dataloader
@@ -0,0 +1,126 @@
+package model
+
+import "time"
+
+// The statuses reported by the health checks
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// The types of components checked by the readiness probe
+const (
+	HealthComponentCluster  = "cluster"
+	HealthComponentBroker   = "broker"
+	HealthComponentDatabase = "database"
+)
+
+// ComponentHealth is the status of a dependency of the gateway
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Project string `json:"project,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthResponse is the response of the liveness and readiness probes
+type HealthResponse struct {
+	Status     string             `json:"status"`
+	NodeID     string             `json:"nodeId"`
+	Components []*ComponentHealth `json:"components,omitempty"`
+}
+
+// NewComponentHealth creates the status of a component from the result of its check
+func NewComponentHealth(name, componentType, project string, err error) *ComponentHealth {
+	c := &ComponentHealth{Name: name, Type: componentType, Project: project, Status: HealthStatusUp}
+	if err != nil {
+		c.Status = HealthStatusDown
+		c.Error = err.Error()
+	}
+	return c
+}
+
+// The roles of a node in the cluster
+const (
+	ClusterRoleLeader   = "leader"
+	ClusterRoleFollower = "follower"
+	ClusterRoleObserver = "observer"
+)
+
+// ClusterRole describes the role of a node in the cluster
+type ClusterRole struct {
+	NodeID   string `json:"nodeId"`
+	Role     string `json:"role"`
+	LeaderID string `json:"leaderId,omitempty"`
+	// LeaderURL is the url advertised by the leader. Requests meant for the leader can be forwarded to it.
+	LeaderURL string `json:"leaderUrl,omitempty"`
+}
+
+// The gossip health of a node as seen from its heartbeats
+const (
+	NodeHealthAlive   = "alive"
+	NodeHealthSuspect = "suspect"
+)
+
+// ClusterNode describes a node of the cluster as advertised by its heartbeats
+type ClusterNode struct {
+	ID string `json:"id"`
+	// Address and Scheme are advertised by the node so that the other nodes can reach its apis even if it runs behind
+	// a nat or on a non default port. The address includes the port.
+	Address string `json:"address"`
+	Scheme  string `json:"scheme,omitempty"`
+	// Metadata is the custom metadata attached to the node by its operator or embedder
+	Metadata        *NodeMetadata `json:"metadata,omitempty"`
+	Role            string        `json:"role"`
+	Health          string        `json:"health"`
+	Version         string        `json:"version"`
+	ProtocolVersion int           `json:"protocolVersion"`
+	StartedAt       time.Time     `json:"startedAt"`
+	Uptime          int64         `json:"uptime"`
+	Projects        []string      `json:"projects"`
+	IsDraining      bool          `json:"isDraining,omitempty"`
+	LastHeartbeat   time.Time     `json:"lastHeartbeat"`
+}
+
+// NodeMetadata is the custom metadata a node advertises to the rest of the cluster
+type NodeMetadata struct {
+	// Zone is the availability zone or region the node runs in
+	Zone string `json:"zone,omitempty"`
+	// Capacity is the relative share of the load the node can take. Zero means the node doesn't advertise it.
+	Capacity int               `json:"capacity,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// The types of the membership events of the cluster
+const (
+	MembershipEventJoin   = "join"
+	MembershipEventLeave  = "leave"
+	MembershipEventUpdate = "update"
+)
+
+// MembershipEvent is a change of the nodes of the cluster observed from their heartbeats. The node of a leave event
+// is the last known state of the node.
+type MembershipEvent struct {
+	Type string       `json:"type"`
+	Node *ClusterNode `json:"node"`
+}
+
+// ClusterTopology describes the nodes of the cluster. MinProtocolVersion is the lowest config replication protocol
+// version spoken in the cluster. Members which don't send heartbeats predate the handshake and count as version 1.
+type ClusterTopology struct {
+	ClusterID          string         `json:"clusterId"`
+	LeaderID           string         `json:"leaderId,omitempty"`
+	Members            int            `json:"members"`
+	MinProtocolVersion int            `json:"minProtocolVersion"`
+	Nodes              []*ClusterNode `json:"nodes"`
+}
+
+// GetURL returns the base url of the apis of the node. Nodes which predate the advertised scheme serve http.
+func (n *ClusterNode) GetURL() string {
+	scheme := n.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	return scheme + "://" + n.Address
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// KVSetRequest is the http body received to set a key of the key value store
+type KVSetRequest struct {
+	Value interface{} `json:"value"`
+	// TTL is the time in seconds after which the key expires. The default ttl of the namespace is used if it is zero.
+	TTL int `json:"ttl,omitempty"`
+}
+
+// KVEntry is a key of the key value store along with its value
+type KVEntry struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+	// ExpiresAt is only set for the keys which expire
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
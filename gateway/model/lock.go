@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Lock is a lease on a named lock of a project. The token proves the ownership of the lease and is
+// required to renew or release it.
+type Lock struct {
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// LockRequest is the http body received to acquire, renew or release a lock
+type LockRequest struct {
+	// TTL is the time in seconds for which the lock is held unless renewed
+	TTL   int    `json:"ttl"`
+	Token string `json:"token"`
+}
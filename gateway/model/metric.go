@@ -24,4 +24,7 @@ const (
 
 	// Aggregation is the type used for aggregations
 	Aggregation OperationType = "aggr"
+
+	// PreparedQuery is the type used for the execution of prepared queries
+	PreparedQuery OperationType = "prepared-query"
 )
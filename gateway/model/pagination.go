@@ -0,0 +1,21 @@
+package model
+
+// The ways of counting the documents matching a read
+const (
+	// TotalCountExact counts the matching documents
+	TotalCountExact = "exact"
+
+	// TotalCountEstimated estimates the number of matching documents from the statistics of the database
+	TotalCountEstimated = "estimated"
+)
+
+// PageInfo is the pagination metadata of a page of documents
+type PageInfo struct {
+	// TotalCount is only set if it was asked for and the database could provide it
+	TotalCount      *int64 `json:"totalCount,omitempty"`
+	TotalCountType  string `json:"totalCountType,omitempty"`
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor,omitempty"`
+	EndCursor       string `json:"endCursor,omitempty"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// The types of the privacy requests of a user
+const (
+	PrivacyRequestExport  = "export"
+	PrivacyRequestErasure = "erasure"
+)
+
+// The statuses of a privacy request
+const (
+	PrivacyStatusCompleted = "completed"
+	PrivacyStatusFailed    = "failed"
+)
+
+// PrivacyRequest is the http body of a request to export or erase the data of a user
+type PrivacyRequest struct {
+	// Reason is recorded in the report of the request
+	Reason string `json:"reason,omitempty"`
+}
+
+// PrivacyReport is the auditable report of a request to export or erase the data of a user
+type PrivacyReport struct {
+	ID          string                     `json:"id"`
+	Type        string                     `json:"type"`
+	UserID      string                     `json:"userId"`
+	RequestedBy string                     `json:"requestedBy,omitempty"`
+	Reason      string                     `json:"reason,omitempty"`
+	Status      string                     `json:"status"`
+	Error       string                     `json:"error,omitempty"`
+	StartedAt   time.Time                  `json:"startedAt"`
+	CompletedAt time.Time                  `json:"completedAt"`
+	Collections []*PrivacyCollectionReport `json:"collections"`
+}
+
+// PrivacyCollectionReport is the number of documents of a user exported or erased from a collection
+type PrivacyCollectionReport struct {
+	DbAlias string `json:"dbAlias"`
+	Col     string `json:"col"`
+	// Action is either export, delete or anonymize
+	Action    string `json:"action"`
+	Documents int64  `json:"documents"`
+	// Committed is whether the documents have been exported or the erasure of them has been committed
+	Committed bool `json:"committed"`
+}
+
+// PrivacyExport is the data of a user exported from the collections holding personal data
+type PrivacyExport struct {
+	Report      *PrivacyReport             `json:"report"`
+	Collections []*PrivacyExportCollection `json:"collections"`
+}
+
+// PrivacyExportCollection is the documents of a user in a collection
+type PrivacyExportCollection struct {
+	DbAlias   string        `json:"dbAlias"`
+	Col       string        `json:"col"`
+	Documents []interface{} `json:"documents"`
+}
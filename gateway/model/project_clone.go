@@ -0,0 +1,33 @@
+package model
+
+import "github.com/spaceuptech/space-cloud/gateway/config"
+
+// ProjectCloneRequest is the request to clone the config of a project into another project
+type ProjectCloneRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Overwrite allows cloning into an existing project which is how a project gets promoted across environments.
+	// Resources of the existing project which are absent in the source project are left untouched.
+	Overwrite bool                   `json:"overwrite,omitempty"`
+	Overrides *ProjectCloneOverrides `json:"overrides,omitempty"`
+}
+
+// ProjectCloneOverrides are the values which differ between the source project and its clone
+type ProjectCloneOverrides struct {
+	Databases map[string]*DatabaseOverride `json:"databases,omitempty"` // The key here is the db alias
+	// Secrets replace the jwt secrets of the project
+	Secrets []*config.Secret `json:"secrets,omitempty"`
+	// AESKey replaces the aes key of the project. The deploy secrets get encrypted with the new key.
+	AESKey string `json:"aesKey,omitempty"`
+	// DeploySecrets are merged with the values of the deploy secrets of the source project. The key here is the secret id
+	DeploySecrets map[string]map[string]string `json:"deploySecrets,omitempty"`
+	// Domains replace the domains whitelisted for let's encrypt. The clone doesn't whitelist any domain by default
+	// since a domain can only be served by a single project.
+	Domains []string `json:"domains,omitempty"`
+}
+
+// DatabaseOverride describes the database a database config of the clone connects to
+type DatabaseOverride struct {
+	Conn   string `json:"conn,omitempty"`
+	DBName string `json:"name,omitempty"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "github.com/spaceuptech/space-cloud/gateway/config"
+
+// ProjectFromTemplateRequest is the request to create a project from a project template
+type ProjectFromTemplateRequest struct {
+	Template string                 `json:"template"`
+	Project  *config.ProjectConfig  `json:"project"`
+	Database *config.DatabaseConfig `json:"database"`
+	// WebhookURL is the base url of the sample event triggers of the template. The triggers are skipped if it is empty
+	WebhookURL string `json:"webhookUrl,omitempty"`
+}
+
+// ProjectFromTemplateResult describes the resources created from a project template
+type ProjectFromTemplateResult struct {
+	Project     string   `json:"project"`
+	Template    string   `json:"template"`
+	Collections []string `json:"collections"`
+	Triggers    []string `json:"triggers"`
+}
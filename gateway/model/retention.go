@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// RetentionStatus is the progress of the retention policy of a collection. It is reported by the node enforcing the
+// policy, which is the leader of the cluster.
+type RetentionStatus struct {
+	DbAlias string `json:"dbAlias"`
+	Col     string `json:"col"`
+	Action  string `json:"action"`
+	Running bool   `json:"running"`
+	// Processed and Batches are the number of documents deleted or archived and the batches they took in the current
+	// run, or the last one if the policy isn't running
+	Processed   int64      `json:"processed"`
+	Batches     int        `json:"batches"`
+	LastRunAt   *time.Time `json:"lastRunAt,omitempty"`
+	LastRunTime string     `json:"lastRunTime,omitempty"`
+	NextRunAt   *time.Time `json:"nextRunAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "github.com/spaceuptech/space-cloud/gateway/config"
+
+// RuleEvaluation is the outcome of a security rule along with the outcome of its clauses
+type RuleEvaluation struct {
+	// Path locates the rule in the rule tree. Eg. rule.clauses[1].clause
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Name    string `json:"name,omitempty"`
+	Allowed bool   `json:"allowed"`
+	Error   string `json:"error,omitempty"`
+	// Values holds the resolved operands of match rules
+	Values  map[string]interface{} `json:"values,omitempty"`
+	Clauses []*RuleEvaluation      `json:"clauses,omitempty"`
+}
+
+// RuleTrace collects the evaluations of the security rules of a request
+type RuleTrace struct {
+	Evaluations []*RuleEvaluation `json:"evaluations"`
+}
+
+// FailedRules returns the paths of the rules which caused the denials recorded in the trace. These are the
+// failed rules whose clauses (if any) did not fail themselves.
+func (t *RuleTrace) FailedRules() []string {
+	paths := make([]string, 0)
+	var walk func(evals []*RuleEvaluation) bool
+	walk = func(evals []*RuleEvaluation) bool {
+		failed := false
+		for _, eval := range evals {
+			if eval.Allowed {
+				continue
+			}
+			failed = true
+			if !walk(eval.Clauses) {
+				paths = append(paths, eval.Path)
+			}
+		}
+		return failed
+	}
+	walk(t.Evaluations)
+	return paths
+}
+
+// RuleDebugRequest is the request to evaluate a security rule. The rule of the database collection is
+// evaluated if no rule is provided. Claims are taken from the token if it is provided.
+type RuleDebugRequest struct {
+	Rule    *config.Rule           `json:"rule"`
+	DBAlias string                 `json:"dbAlias"`
+	Col     string                 `json:"col"`
+	Op      string                 `json:"op"`
+	Args    map[string]interface{} `json:"args"`
+	Claims  map[string]interface{} `json:"claims"`
+	Token   string                 `json:"token"`
+}
+
+// RuleDebugResponse is the decision of a security rule along with its trace
+type RuleDebugResponse struct {
+	Allowed     bool                   `json:"allowed"`
+	Error       string                 `json:"error,omitempty"`
+	Trace       []*RuleEvaluation      `json:"trace"`
+	PostProcess *PostProcess           `json:"postProcess,omitempty"`
+	Args        map[string]interface{} `json:"args"`
+}
@@ -22,16 +22,32 @@ type (
 		// For directives
 		IsCreatedAt     bool `json:"isCreatedAt"`
 		IsUpdatedAt     bool `json:"isUpdatedAt"`
+		IsVersion       bool `json:"isVersion"`
 		IsLinked        bool `json:"isLinked"`
 		IsForeign       bool `json:"isForeign"`
 		IsDefault       bool `json:"isDefault"`
 		IsAutoIncrement bool
+		IsAutoGenerate  bool               `json:"isAutoGenerate"`
 		PrimaryKeyInfo  *TableProperties   `json:"primaryKeyInfo"`
 		IndexInfo       []*TableProperties `json:"indexInfo"`
 		LinkedTable     *TableProperties   `json:"linkedTable"`
 		JointTable      *TableProperties   `json:"jointTable"`
 		Default         interface{}        `json:"default"`
 		TypeIDSize      int                `json:"size"`
+		// EnumValues are the values allowed in a field of type enum
+		EnumValues []string `json:"enumValues"`
+		// Validation holds the constraints added with the validation directives
+		Validation *FieldValidation `json:"validation,omitempty"`
+	}
+
+	// FieldValidation are the constraints on the value of a field which are checked before it is written
+	FieldValidation struct {
+		Min       *float64 `json:"min,omitempty"`
+		Max       *float64 `json:"max,omitempty"`
+		MinLength *int     `json:"minLength,omitempty"`
+		MaxLength *int     `json:"maxLength,omitempty"`
+		Regex     string   `json:"regex,omitempty"`
+		Email     bool     `json:"email,omitempty"`
 	}
 
 	// FieldArgs are properties of the column
@@ -60,6 +76,8 @@ type (
 		Sort           string
 		Order          int
 		ConstraintName string
+		// Where is the predicate of a partial index. Only the rows matching it are indexed.
+		Where string
 	}
 )
 
@@ -103,6 +121,10 @@ const (
 	TypeObject string = "Object"
 	// TypeEnum is a variable type enum
 	TypeEnum string = "Enum"
+	// TypePoint is used for fields storing a geographic point
+	TypePoint string = "Point"
+	// TypeGeometry is used for fields storing any geographic geometry
+	TypeGeometry string = "Geometry"
 	// DirectiveUnique is used in schema module to add unique index
 	DirectiveUnique string = "unique"
 	// DirectiveIndex is used in schema module to add index
@@ -113,10 +135,14 @@ const (
 	DirectivePrimary string = "primary"
 	// DirectiveAutoIncrement is used in schema module to add primary key
 	DirectiveAutoIncrement string = "autoIncrement"
+	// DirectiveAutoGenerate is used in schema module to generate the value of a UUID field when it isn't provided
+	DirectiveAutoGenerate string = "autoGenerate"
 	// DirectiveCreatedAt is used in schema module to specify the created location
 	DirectiveCreatedAt string = "createdAt"
 	// DirectiveUpdatedAt  is used in schema module to add Updated location
 	DirectiveUpdatedAt string = "updatedAt"
+	// DirectiveVersion is used in schema module to mark the field holding the version of a document for optimistic locking
+	DirectiveVersion string = "version"
 	// DirectiveLink is used in schema module to add link
 	DirectiveLink string = "link"
 	// DirectiveDefault is used to add default key
@@ -125,6 +151,29 @@ const (
 	DirectiveArgs string = "args"
 	// DirectiveStringSize denotes the maximum allowable character for field type Char, Varchar, ID
 	DirectiveStringSize string = "size"
+	// DirectiveMin is used in schema module to specify the minimum value of a number
+	DirectiveMin string = "min"
+	// DirectiveMax is used in schema module to specify the maximum value of a number
+	DirectiveMax string = "max"
+	// DirectiveLength is used in schema module to specify the minimum and maximum length of a string
+	DirectiveLength string = "length"
+	// DirectiveRegex is used in schema module to specify the pattern a string must match
+	DirectiveRegex string = "regex"
+	// DirectiveEmail is used in schema module to specify that a string must be an email address
+	DirectiveEmail string = "email"
+	// DirectiveHistory is used in schema module to maintain an append-only history table of a collection
+	DirectiveHistory string = "history"
+
+	// HistoryTableSuffix is appended to the name of a collection to get the name of its history table
+	HistoryTableSuffix string = "_history"
+	// HistoryFieldID is the primary key of a history table
+	HistoryFieldID string = "_history_id"
+	// HistoryFieldOp is the field of a history table holding the operation which produced the version of a document
+	HistoryFieldOp string = "_history_op"
+	// HistoryFieldAt is the field of a history table holding the time from which a version of a document was valid
+	HistoryFieldAt string = "_history_at"
+	// HistoryFieldDocID holds the _id of a mongo document in its history collection since _id identifies the version
+	HistoryFieldDocID string = "_history_doc_id"
 
 	// DefaultIndexSort specifies default order of sorting
 	DefaultIndexSort string = "asc"
@@ -183,4 +232,34 @@ type IndexType struct {
 	IsUnique bool `db:"IS_UNIQUE"`
 	// IsPrimary specifies whether the column has a index
 	IsPrimary bool `db:"IS_PRIMARY"`
+	// Where is the predicate of a partial index. It is empty for indexes on all the rows.
+	Where string `db:"WHERE_CLAUSE"`
+}
+
+// IndexDescription describes an index of a collection
+type IndexDescription struct {
+	// Group is the name of the index in the schema
+	Group string `json:"group"`
+	// Name is the name of the index in the database. It is empty for indexes which don't exist yet.
+	Name   string             `json:"name,omitempty"`
+	Unique bool               `json:"unique"`
+	Fields []IndexFieldDetail `json:"fields"`
+	Where  string             `json:"where,omitempty"`
+}
+
+// IndexFieldDetail is a column of an index
+type IndexFieldDetail struct {
+	Field string `json:"field"`
+	Sort  string `json:"sort"`
+}
+
+// IndexDrift shows the difference between the indexes declared in the schema of a collection and the ones present in
+// the database. The groups in missing, extra and changed are the ones the next schema migration will create, drop
+// and recreate respectively.
+type IndexDrift struct {
+	Declared []*IndexDescription `json:"declared"`
+	Actual   []*IndexDescription `json:"actual"`
+	Missing  []string            `json:"missing"`
+	Extra    []string            `json:"extra"`
+	Changed  []string            `json:"changed"`
 }
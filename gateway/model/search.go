@@ -0,0 +1,12 @@
+package model
+
+// SearchRequest is the request to run a full text search on a collection
+type SearchRequest struct {
+	Query string `json:"query" mapstructure:"query"`
+	// Fields restricts the search to a subset of the indexed fields
+	Fields []string `json:"fields,omitempty" mapstructure:"fields"`
+	Limit  int      `json:"limit,omitempty" mapstructure:"limit"`
+	Offset int      `json:"offset,omitempty" mapstructure:"offset"`
+	// Highlight adds the matching fragments of every field to the documents in the result
+	Highlight bool `json:"highlight,omitempty" mapstructure:"highlight"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Session is an active session of a user. The tokens of a session carry its id in the sid claim.
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Current marks the session of the token used to list the sessions
+	Current bool `json:"current,omitempty"`
+}
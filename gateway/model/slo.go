@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// SLOStatus is the current state of a service level objective on a gateway
+type SLOStatus struct {
+	ID        string  `json:"id"`
+	Module    string  `json:"module,omitempty"`
+	Route     string  `json:"route,omitempty"`
+	Objective float64 `json:"objective"`
+	// Requests and BadRequests are counted over the longest window of the alerts
+	Window      int    `json:"window"`
+	Requests    uint64 `json:"requests"`
+	BadRequests uint64 `json:"badRequests"`
+	// ErrorBudgetRemaining is the fraction of the error budget of the window which hasn't been used
+	ErrorBudgetRemaining float64           `json:"errorBudgetRemaining"`
+	Alerts               []*SLOAlertStatus `json:"alerts"`
+}
+
+// SLOAlertStatus is the current state of a burn rate alert of a service level objective
+type SLOAlertStatus struct {
+	LongWindow    int     `json:"longWindow"`
+	ShortWindow   int     `json:"shortWindow"`
+	BurnRate      float64 `json:"burnRate"`
+	LongBurnRate  float64 `json:"longBurnRate"`
+	ShortBurnRate float64 `json:"shortBurnRate"`
+	Firing        bool    `json:"firing"`
+	// FiringSince is set while the alert is firing
+	FiringSince *time.Time `json:"firingSince,omitempty"`
+}
@@ -32,12 +32,16 @@ type SchemaEventingInterface interface {
 	SchemaInspection(ctx context.Context, dbAlias, project, col string, realSchema Collection) (string, error)
 	GetSchema(dbAlias, col string) (Fields, bool)
 	GetSchemaForDB(ctx context.Context, dbAlias, col, format string) ([]interface{}, error)
+	GetRowLevelSecurityQueries(ctx context.Context, dbAlias, logicalDBName, col string, rules map[string]*config.Rule) ([]string, error)
+	ApplyRowLevelSecurity(ctx context.Context, dbAlias, logicalDBName, col, secret string, rules map[string]*config.Rule) error
+	SetRowLevelSecuritySecret(ctx context.Context, dbAlias, logicalDBName, secret string) error
 }
 
 // CrudEventingInterface is an interface consisting of functions of crud module used by Eventing module
 type CrudEventingInterface interface {
 	InternalCreate(ctx context.Context, dbAlias, project, col string, req *CreateRequest, isIgnoreMetrics bool) error
 	InternalUpdate(ctx context.Context, dbAlias, project, col string, req *UpdateRequest) error
+	InternalDelete(ctx context.Context, dbAlias, project, col string, req *DeleteRequest) error
 	Read(ctx context.Context, dbAlias, col string, req *ReadRequest, params RequestParams) (interface{}, *SQLMetaData, error)
 	GetDBType(dbAlias string) (string, error)
 	GetSchema(dbAlias, col string) (Fields, bool)
@@ -49,6 +53,7 @@ type AuthEventingInterface interface {
 	GetInternalAccessToken(ctx context.Context) (string, error)
 	GetSCAccessToken(ctx context.Context) (string, error)
 	IsEventingOpAuthorised(ctx context.Context, project, token string, event *QueueEventRequest) (RequestParams, error)
+	ParseToken(ctx context.Context, token string) (map[string]interface{}, error)
 	MatchRule(ctx context.Context, project string, rule *config.Rule, args, auth map[string]interface{}, returnWhere ReturnWhereStub) (*PostProcess, error)
 }
 
@@ -95,6 +100,7 @@ type AuthRealtimeInterface interface {
 // CrudRealtimeInterface is an interface consisting of functions of crud module used by RealTime module
 type CrudRealtimeInterface interface {
 	Read(ctx context.Context, dbAlias, col string, req *ReadRequest, param RequestParams) (interface{}, *SQLMetaData, error)
+	GetDBType(dbAlias string) (string, error)
 }
 
 // CrudSchemaInterface is an interface consisting of functions of crud module used by Schema module
@@ -111,6 +117,8 @@ type CrudUserInterface interface {
 	Read(ctx context.Context, dbAlias, col string, req *ReadRequest, params RequestParams) (interface{}, *SQLMetaData, error)
 	Create(ctx context.Context, dbAlias, col string, req *CreateRequest, params RequestParams) error
 	Update(ctx context.Context, dbAlias, col string, req *UpdateRequest, params RequestParams) error
+	UpdateCount(ctx context.Context, dbAlias, col string, req *UpdateRequest, params RequestParams) (int64, error)
+	Delete(ctx context.Context, dbAlias, col string, req *DeleteRequest, params RequestParams) error
 }
 
 // AuthUserInterface is an interface consisting of functions of auth module used by User module
@@ -118,6 +126,7 @@ type AuthUserInterface interface {
 	IsReadOpAuthorised(ctx context.Context, project, dbType, col, token string, req *ReadRequest, stub ReturnWhereStub) (*PostProcess, RequestParams, error)
 	CreateToken(ctx context.Context, tokenClaims TokenClaims) (string, error)
 	IsUpdateOpAuthorised(ctx context.Context, project, dbType, col, token string, req *UpdateRequest) (RequestParams, error)
+	ParseToken(ctx context.Context, token string) (map[string]interface{}, error)
 }
 
 // SyncmanEventingInterface is an interface consisting of functions of syncman module used by eventing module
@@ -128,6 +137,7 @@ type SyncmanEventingInterface interface {
 	GetSpaceCloudPort() int
 	GetNodeID() string
 	MakeHTTPRequest(ctx context.Context, method, url, token, scToken string, params, vPtr interface{}) error
+	PublishAdminEvent(ctx context.Context, event *AdminEvent)
 }
 
 // AdminEventingInterface is an interface consisting of functions of admin module used by eventing module
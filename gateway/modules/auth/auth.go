@@ -29,6 +29,8 @@ type Module struct {
 	project          string
 	fileStoreType    string
 	makeHTTPRequest  utils.TypeMakeHTTPRequest
+	resolveSecret    utils.ResolveSecret
+	evaluateFlag     utils.EvaluateFeatureFlag
 	aesKey           []byte
 
 	// Admin Manager
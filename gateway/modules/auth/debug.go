@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+const (
+	defaultMintedTokenExpiry = time.Hour
+	maxMintedTokenExpiry     = 24 * time.Hour
+)
+
+// MintToken creates a token of the project with the provided claims. It is used to impersonate the users of
+// the project while debugging the security rules, hence the tokens always expire.
+func (m *Module) MintToken(ctx context.Context, claims map[string]interface{}, expiresIn time.Duration) (string, error) {
+	if expiresIn <= 0 {
+		expiresIn = defaultMintedTokenExpiry
+	}
+	if expiresIn > maxMintedTokenExpiry {
+		expiresIn = maxMintedTokenExpiry
+	}
+
+	return m.jwt.CreateTokenWithExpiry(ctx, claims, expiresIn)
+}
+
+// DebugRule evaluates a security rule against the provided args and claims and returns the decision along
+// with the trace of the evaluation. Rules performing queries or calling webhooks do so while being evaluated.
+func (m *Module) DebugRule(ctx context.Context, project string, req *model.RuleDebugRequest) (*model.RuleDebugResponse, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	claims := req.Claims
+	if req.Token != "" {
+		c, err := m.jwt.ParseToken(ctx, req.Token)
+		if err != nil {
+			return nil, err
+		}
+		claims = c
+	}
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+
+	rule := req.Rule
+	if rule == nil {
+		if req.DBAlias == "" || req.Col == "" || req.Op == "" {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Either provide a rule or the database alias, collection and operation whose rule needs to be evaluated", errors.New("rule not provided"), nil)
+		}
+		r, err := m.getCrudRule(ctx, project, req.DBAlias, req.Col, model.OperationType(req.Op))
+		if err != nil {
+			return nil, err
+		}
+		rule = r
+	}
+	// Rules store the defaults in them while being evaluated
+	rule = copyRule(rule)
+
+	args := map[string]interface{}{"auth": claims, "token": req.Token}
+	for k, v := range req.Args {
+		args[k] = v
+	}
+
+	ctx, trace := WithRuleTrace(ctx)
+	postProcess, err := m.matchRule(ctx, project, rule, map[string]interface{}{"args": args}, claims, model.ReturnWhereStub{})
+
+	res := &model.RuleDebugResponse{Allowed: err == nil, Trace: trace.Evaluations, PostProcess: postProcess, Args: args}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+func copyRule(rule *config.Rule) *config.Rule {
+	if rule == nil {
+		return nil
+	}
+	r := *rule
+	r.Clause = copyRule(rule.Clause)
+	if rule.Clauses != nil {
+		r.Clauses = make([]*config.Rule, len(rule.Clauses))
+		for i, clause := range rule.Clauses {
+			r.Clauses[i] = copyRule(clause)
+		}
+	}
+	return &r
+}
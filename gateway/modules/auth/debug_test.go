@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
+)
+
+func TestModule_DebugRule(t *testing.T) {
+	ownerRule := &config.Rule{Rule: "match", Eval: "==", Type: "string", F1: "args.auth.id", F2: "args.doc.owner", Name: "owner"}
+	dbRules := config.DatabaseRules{
+		config.GenerateResourceID("chicago", "project", config.ResourceDatabaseRule, "db", "todos", "rule"): &config.DatabaseRule{
+			Table: "todos", DbAlias: "db", Rules: map[string]*config.Rule{"create": ownerRule},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		req         *model.RuleDebugRequest
+		wantAllowed bool
+		wantPaths   []string
+		wantErr     bool
+	}{
+		{
+			name:        "rule of the collection is picked",
+			req:         &model.RuleDebugRequest{DBAlias: "db", Col: "todos", Op: "create", Claims: map[string]interface{}{"id": "1"}, Args: map[string]interface{}{"doc": map[string]interface{}{"owner": "1"}}},
+			wantAllowed: true,
+			wantPaths:   []string{"rule"},
+		},
+		{
+			name: "failing clauses are traced",
+			req: &model.RuleDebugRequest{
+				Rule:   &config.Rule{Rule: "or", Clauses: []*config.Rule{{Rule: "deny"}, {Rule: "and", Clauses: []*config.Rule{ownerRule, {Rule: "deny"}}}}},
+				Claims: map[string]interface{}{"id": "1"},
+				Args:   map[string]interface{}{"doc": map[string]interface{}{"owner": "1"}},
+			},
+			wantAllowed: false,
+			wantPaths:   []string{"rule", "rule.clauses[0]", "rule.clauses[1]", "rule.clauses[1].clauses[0]", "rule.clauses[1].clauses[1]"},
+		},
+		{
+			name:    "collection without a rule",
+			req:     &model.RuleDebugRequest{DBAlias: "db", Col: "users", Op: "create"},
+			wantErr: true,
+		},
+		{
+			name:    "neither rule nor collection",
+			req:     &model.RuleDebugRequest{},
+			wantErr: true,
+		},
+	}
+
+	m := Init("chicago", "1", &crud.Module{}, nil, nil)
+	if err := m.SetConfig(context.TODO(), "local", &config.ProjectConfig{ID: "project", Secrets: []*config.Secret{{IsPrimary: true, Secret: "mySecretKey"}}}, dbRules, config.DatabasePreparedQueries{}, config.FileStoreRules{}, config.Services{}, config.EventingRules{}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := m.DebugRule(context.Background(), "project", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DebugRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if res.Allowed != tt.wantAllowed {
+				t.Errorf("DebugRule() allowed = %v, want %v (%s)", res.Allowed, tt.wantAllowed, res.Error)
+			}
+
+			paths := make([]string, 0)
+			var walk func(evals []*model.RuleEvaluation)
+			walk = func(evals []*model.RuleEvaluation) {
+				for _, e := range evals {
+					paths = append(paths, e.Path)
+					walk(e.Clauses)
+				}
+			}
+			walk(res.Trace)
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("DebugRule() traced paths = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+
+	// Only the failing clauses of the rules which denied the request are reported
+	ctx, trace := WithRuleTrace(context.Background())
+	rule := &config.Rule{Rule: "and", Clauses: []*config.Rule{{Rule: "or", Clauses: []*config.Rule{{Rule: "deny"}, {Rule: "allow"}}}, {Rule: "deny"}}}
+	_, _ = m.matchRule(ctx, "project", rule, map[string]interface{}{"args": map[string]interface{}{}}, map[string]interface{}{}, model.ReturnWhereStub{})
+	if got, want := trace.FailedRules(), []string{"rule.clauses[1]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FailedRules() = %v, want %v", got, want)
+	}
+
+	// The operands of match rules are resolved
+	res, _ := m.DebugRule(context.Background(), "project", &model.RuleDebugRequest{Rule: ownerRule, Claims: map[string]interface{}{"id": "1"}, Args: map[string]interface{}{"doc": map[string]interface{}{"owner": "2"}}})
+	if values := res.Trace[0].Values; values["f1"] != "1" || values["f2"] != "2" || res.Trace[0].Allowed {
+		t.Errorf("DebugRule() match evaluation = %+v", res.Trace[0])
+	}
+}
+
+func TestModule_MintToken(t *testing.T) {
+	m := Init("chicago", "1", &crud.Module{}, nil, nil)
+	if err := m.SetConfig(context.TODO(), "local", &config.ProjectConfig{ID: "project", Secrets: []*config.Secret{{IsPrimary: true, Secret: "mySecretKey"}}}, config.DatabaseRules{}, config.DatabasePreparedQueries{}, config.FileStoreRules{}, config.Services{}, config.EventingRules{}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	token, err := m.MintToken(context.Background(), map[string]interface{}{"id": "user-1", "role": "user"}, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("MintToken() error = %v", err)
+	}
+	claims, err := m.ParseToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("ParseToken() error = %v", err)
+	}
+	if claims["id"] != "user-1" || claims["role"] != "user" {
+		t.Errorf("MintToken() claims = %v", claims)
+	}
+	exp, _ := claims["exp"].(float64)
+	if expiresIn := time.Until(time.Unix(int64(exp), 0)); expiresIn > maxMintedTokenExpiry || expiresIn < maxMintedTokenExpiry-time.Minute {
+		t.Errorf("MintToken() token expires in %v, want %v", expiresIn, maxMintedTokenExpiry)
+	}
+}
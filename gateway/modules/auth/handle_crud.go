@@ -106,6 +106,11 @@ func (m *Module) IsReadOpAuthorised(ctx context.Context, project, dbAlias, col,
 		return nil, model.RequestParams{}, err
 	}
 
+	actions, err = m.applyFieldMasks(ctx, project, dbAlias, col, token, auth, req, actions)
+	if err != nil {
+		return nil, model.RequestParams{}, err
+	}
+
 	attr := map[string]string{"project": project, "db": dbAlias, "col": col}
 	return actions, model.RequestParams{Claims: auth, Resource: "db-read", Op: "access", Attributes: attr}, nil
 }
@@ -264,7 +269,11 @@ func (m *Module) RunAuthForJoins(ctx context.Context, project, dbType, dbAlias,
 		}
 
 		if len(returnWhere.Where) > 0 {
-			req.MatchWhere = append(req.MatchWhere, returnWhere.Where)
+			if j.Strategy == model.JoinStrategyJSON {
+				j.MatchWhere = append(j.MatchWhere, returnWhere.Where)
+			} else {
+				req.MatchWhere = append(req.MatchWhere, returnWhere.Where)
+			}
 		}
 
 		req.PostProcess[j.Table] = actions
@@ -312,6 +321,14 @@ func (m *Module) authenticatePreparedQueryRequest(ctx context.Context, projectID
 }
 
 func (m *Module) getCrudRule(ctx context.Context, projectID, dbAlias, col string, query model.OperationType) (*config.Rule, error) {
+	// Views are read with the security rule of the prepared query defining them
+	if query == model.Read {
+		view, ok := m.dbPrepQueryRules[config.GenerateResourceID(m.clusterID, projectID, config.ResourceDatabasePreparedQuery, dbAlias, col)]
+		if ok && view.IsView && view.Rule != nil {
+			return view.Rule, nil
+		}
+	}
+
 	resourceIDs := []string{
 		config.GenerateResourceID(m.clusterID, projectID, config.ResourceDatabaseRule, dbAlias, col, "rule"),
 		config.GenerateResourceID(m.clusterID, projectID, config.ResourceDatabaseRule, dbAlias, "default", "rule"),
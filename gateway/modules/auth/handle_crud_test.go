@@ -454,7 +454,8 @@ func Test_getPrepareQueryRule(t *testing.T) {
 
 func TestModule_getCrudRule(t *testing.T) {
 	type fields struct {
-		rules map[string]*config.DatabaseRule
+		rules     map[string]*config.DatabaseRule
+		prepRules map[string]*config.DatbasePreparedQuery
 	}
 	type args struct {
 		dbAlias string
@@ -499,13 +500,26 @@ func TestModule_getCrudRule(t *testing.T) {
 			args:    args{project: "project", query: "op-bad", dbAlias: "db", col: "col"},
 			wantErr: true,
 		},
+		{
+			name:   "view - read",
+			fields: fields{prepRules: map[string]*config.DatbasePreparedQuery{config.GenerateResourceID("chicago", "project", config.ResourceDatabasePreparedQuery, "db", "view"): {ID: "view", IsView: true, Rule: &config.Rule{Type: "allow"}}}},
+			args:   args{project: "project", query: model.Read, dbAlias: "db", col: "view"},
+			want:   &config.Rule{Type: "allow"},
+		},
+		{
+			name:    "prepared query which isn't a view",
+			fields:  fields{prepRules: map[string]*config.DatbasePreparedQuery{config.GenerateResourceID("chicago", "project", config.ResourceDatabasePreparedQuery, "db", "view"): {ID: "view", Rule: &config.Rule{Type: "allow"}}}},
+			args:    args{project: "project", query: model.Read, dbAlias: "db", col: "view"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := &Module{
-				clusterID: "chicago",
-				dbRules:   tt.fields.rules,
+				clusterID:        "chicago",
+				dbRules:          tt.fields.rules,
+				dbPrepQueryRules: tt.fields.prepRules,
 			}
 			got, err := m.getCrudRule(context.Background(), tt.args.project, tt.args.dbAlias, tt.args.col, tt.args.query)
 			if (err != nil) != tt.wantErr {
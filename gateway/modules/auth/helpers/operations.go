@@ -3,14 +3,17 @@ package helpers
 import (
 	"context"
 	"crypto/aes"
+	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"unicode"
 
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
@@ -106,6 +109,20 @@ func PostProcessMethod(ctx context.Context, aesKey []byte, postProcess *model.Po
 
 				}
 
+			case "mask":
+				mask, ok := field.Value.(*config.FieldMask)
+				if !ok {
+					return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid mask provided in post process", fmt.Errorf("value should be of type field mask got (%T)", field.Value), nil)
+				}
+				// Fields which haven't been selected or are null have nothing to hide
+				loadedValue, err := utils.LoadValue(field.Field, map[string]interface{}{"res": doc})
+				if err != nil || loadedValue == nil {
+					continue
+				}
+				if err := utils.StoreValue(ctx, field.Field, maskValue(aesKey, mask, loadedValue), map[string]interface{}{"res": doc}); err != nil {
+					return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to store value in post process", err, map[string]interface{}{"mask": true})
+				}
+
 			default:
 				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid action (%s) received in post processing read op", field.Action), nil, nil)
 			}
@@ -113,3 +130,35 @@ func PostProcessMethod(ctx context.Context, aesKey []byte, postProcess *model.Po
 	}
 	return nil
 }
+
+// maskValue hides the raw value of a field. Hashes are keyed with the aes key of the project, so that masked values
+// with little entropy (like phone numbers) can't be recovered by hashing all the possible values.
+func maskValue(aesKey []byte, mask *config.FieldMask, value interface{}) interface{} {
+	stringValue, ok := value.(string)
+	if !ok {
+		stringValue = fmt.Sprintf("%v", value)
+	}
+
+	switch mask.Type {
+	case config.FieldMaskHash:
+		h := hmac.New(sha256.New, aesKey)
+		_, _ = h.Write([]byte(stringValue))
+		return hex.EncodeToString(h.Sum(nil))
+
+	case config.FieldMaskPartial:
+		visible := mask.Visible
+		masked := []rune(stringValue)
+		for i := len(masked) - 1; i >= 0; i-- {
+			if !unicode.IsLetter(masked[i]) && !unicode.IsDigit(masked[i]) {
+				continue
+			}
+			if visible > 0 {
+				visible--
+				continue
+			}
+			masked[i] = '*'
+		}
+		return string(masked)
+	}
+	return nil
+}
@@ -2,12 +2,14 @@ package helpers
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"reflect"
 	"testing"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 )
 
@@ -184,6 +186,31 @@ func TestPostProcessMethod(t *testing.T) {
 			result:      map[string]interface{}{"password": "password"},
 			finalResult: map[string]interface{}{"password": hash("password")},
 		},
+		{
+			testName:    "partial mask keeping separators",
+			postProcess: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.ssn", Value: &config.FieldMask{Field: "ssn", Type: config.FieldMaskPartial, Visible: 4}}}},
+			result:      []interface{}{map[string]interface{}{"ssn": "123-45-6789"}, map[string]interface{}{"ssn": int64(98765)}},
+			finalResult: []interface{}{map[string]interface{}{"ssn": "***-**-6789"}, map[string]interface{}{"ssn": "*8765"}},
+		},
+		{
+			testName:    "null mask of a nested field",
+			postProcess: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.address.street", Value: &config.FieldMask{Field: "address.street", Type: config.FieldMaskNull}}}},
+			result:      map[string]interface{}{"address": map[string]interface{}{"street": "baker street", "city": "london"}},
+			finalResult: map[string]interface{}{"address": map[string]interface{}{"street": nil, "city": "london"}},
+		},
+		{
+			testName:    "hash mask keyed with the aes key",
+			aesKey:      []byte("key"),
+			postProcess: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.email", Value: &config.FieldMask{Field: "email", Type: config.FieldMaskHash}}}},
+			result:      map[string]interface{}{"email": "john@example.com"},
+			finalResult: map[string]interface{}{"email": hmacHash([]byte("key"), "john@example.com")},
+		},
+		{
+			testName:    "mask of a field which isn't selected",
+			postProcess: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.ssn", Value: &config.FieldMask{Field: "ssn", Type: config.FieldMaskNull}}}},
+			result:      map[string]interface{}{"name": "john"},
+			finalResult: map[string]interface{}{"name": "john"},
+		},
 	}
 
 	for _, test := range authMatchQuery {
@@ -208,3 +235,9 @@ func hash(s string) string {
 	hashed := hex.EncodeToString(h.Sum(nil))
 	return hashed
 }
+
+func hmacHash(key []byte, s string) string {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// applyFieldMasks adds the masks applicable to the caller to the post processing actions of a read. Filtering,
+// sorting, grouping or aggregating on a masked field is rejected since it would reveal its raw value.
+func (m *Module) applyFieldMasks(ctx context.Context, project, dbAlias, col, token string, auth map[string]interface{}, req *model.ReadRequest, actions *model.PostProcess) (*model.PostProcess, error) {
+	masks := m.getFieldMasks(project, dbAlias, col)
+	if len(masks) == 0 {
+		return actions, nil
+	}
+
+	// The token isn't parsed when the read rule is allow
+	if auth == nil && token != "" {
+		var err error
+		auth, err = m.jwt.ParseToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if id, ok := auth["id"]; ok && id == utils.InternalUserID {
+		return actions, nil
+	}
+	role, _ := auth["role"].(string)
+
+	for _, mask := range masks {
+		if len(mask.Roles) > 0 && !utils.StringExists(mask.Roles, role) {
+			continue
+		}
+		if isFieldFiltered(req.Find, mask.Field) {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Masked field (%s) of collection/table (%s) cannot be used in the where clause", mask.Field, col), nil, nil)
+		}
+		if option, ok := getOptionUsingField(req, mask.Field); ok {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Masked field (%s) of collection/table (%s) cannot be used in the (%s) option", mask.Field, col, option), nil, nil)
+		}
+		if actions == nil {
+			actions = &model.PostProcess{}
+		}
+		actions.PostProcessAction = append(actions.PostProcessAction, model.PostProcessAction{Action: "mask", Field: "res." + mask.Field, Value: mask})
+	}
+	return actions, nil
+}
+
+// getFieldMasks returns the masks of a collection. The masks of the default rule apply to the collections which
+// don't have a rule of their own.
+func (m *Module) getFieldMasks(project, dbAlias, col string) []*config.FieldMask {
+	for _, c := range []string{col, "default"} {
+		if rule, ok := m.dbRules[config.GenerateResourceID(m.clusterID, project, config.ResourceDatabaseRule, dbAlias, c, "rule")]; ok {
+			return rule.Masks
+		}
+	}
+	return nil
+}
+
+// isFieldFiltered checks if the where clause of a request refers to a field or any of its nested fields
+func isFieldFiltered(find map[string]interface{}, field string) bool {
+	for key, value := range find {
+		if isSameField(key, field) {
+			return true
+		}
+		if key == "$or" || key == "$and" {
+			clauses, _ := value.([]interface{})
+			for _, clause := range clauses {
+				if c, ok := clause.(map[string]interface{}); ok && isFieldFiltered(c, field) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// getOptionUsingField returns the option of a read which refers to a field or any of its nested fields. Distinct
+// values, sorting, groups and aggregates all reveal the raw values of the fields they use.
+func getOptionUsingField(req *model.ReadRequest, field string) (string, bool) {
+	if req.Options != nil {
+		if req.Options.Distinct != nil && isSameField(*req.Options.Distinct, field) {
+			return "distinct", true
+		}
+		for _, sort := range req.Options.Sort {
+			if isSameField(strings.TrimPrefix(sort, "-"), field) {
+				return "sort", true
+			}
+		}
+	}
+	for _, group := range req.GroupBy {
+		if g, ok := group.(string); ok && isSameField(g, field) {
+			return "group", true
+		}
+	}
+	for function, columns := range req.Aggregate {
+		for _, column := range columns {
+			// Aggregate columns are of the form returnField:column
+			arr := strings.Split(column, ":")
+			if len(arr) > 1 && isSameField(arr[1], field) {
+				return "aggregate." + function, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isSameField checks if the key refers to a field, any of its nested fields or the object holding it
+func isSameField(key, field string) bool {
+	return key == field || strings.HasPrefix(key, field+".") || strings.HasPrefix(field, key+".")
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestModule_applyFieldMasks(t *testing.T) {
+	ssnMask := &config.FieldMask{Field: "ssn", Type: config.FieldMaskPartial, Visible: 4, Roles: []string{"analyst"}}
+	emailMask := &config.FieldMask{Field: "email", Type: config.FieldMaskHash}
+	m := &Module{clusterID: "chicago", dbRules: config.DatabaseRules{
+		config.GenerateResourceID("chicago", "project", config.ResourceDatabaseRule, "db", "users", "rule"):   {Masks: []*config.FieldMask{ssnMask, emailMask}},
+		config.GenerateResourceID("chicago", "project", config.ResourceDatabaseRule, "db", "default", "rule"): {Masks: []*config.FieldMask{emailMask}},
+	}}
+
+	distinctField := "ssn"
+	tests := []struct {
+		name    string
+		col     string
+		auth    map[string]interface{}
+		req     *model.ReadRequest
+		want    *model.PostProcess
+		wantErr bool
+	}{
+		{
+			name: "caller with a masked role",
+			col:  "users",
+			auth: map[string]interface{}{"id": "1", "role": "analyst"},
+			want: &model.PostProcess{PostProcessAction: []model.PostProcessAction{
+				{Action: "mask", Field: "res.ssn", Value: ssnMask},
+				{Action: "mask", Field: "res.email", Value: emailMask},
+			}},
+		},
+		{
+			name: "caller with any other role",
+			col:  "users",
+			auth: map[string]interface{}{"id": "1", "role": "user"},
+			want: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.email", Value: emailMask}}},
+		},
+		{
+			name: "collection without rules of its own",
+			col:  "posts",
+			want: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.email", Value: emailMask}}},
+		},
+		{
+			name: "internal requests aren't masked",
+			col:  "users",
+			auth: map[string]interface{}{"id": utils.InternalUserID},
+		},
+		{
+			name:    "filtering on a masked field",
+			col:     "users",
+			auth:    map[string]interface{}{"id": "1", "role": "analyst"},
+			req:     &model.ReadRequest{Find: map[string]interface{}{"$or": []interface{}{map[string]interface{}{"name": "john"}, map[string]interface{}{"ssn": "123-45-6789"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "distinct values of a masked field",
+			col:     "users",
+			auth:    map[string]interface{}{"id": "1", "role": "analyst"},
+			req:     &model.ReadRequest{Options: &model.ReadOptions{Distinct: &distinctField}},
+			wantErr: true,
+		},
+		{
+			name:    "sorting on a masked field",
+			col:     "users",
+			auth:    map[string]interface{}{"id": "1", "role": "user"},
+			req:     &model.ReadRequest{Options: &model.ReadOptions{Sort: []string{"name", "-email"}}},
+			wantErr: true,
+		},
+		{
+			name:    "grouping by a masked field",
+			col:     "users",
+			auth:    map[string]interface{}{"id": "1", "role": "user"},
+			req:     &model.ReadRequest{GroupBy: []interface{}{"email"}},
+			wantErr: true,
+		},
+		{
+			name:    "aggregating a masked field",
+			col:     "users",
+			auth:    map[string]interface{}{"id": "1", "role": "analyst"},
+			req:     &model.ReadRequest{Aggregate: map[string][]string{"max": {"highest:ssn"}}},
+			wantErr: true,
+		},
+		{
+			name: "counting the documents",
+			col:  "users",
+			auth: map[string]interface{}{"id": "1", "role": "user"},
+			req:  &model.ReadRequest{Aggregate: map[string][]string{"count": {"total:*"}}},
+			want: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.email", Value: emailMask}}},
+		},
+		{
+			name: "filtering on a field masked for other roles",
+			col:  "users",
+			auth: map[string]interface{}{"id": "1", "role": "user"},
+			req:  &model.ReadRequest{Find: map[string]interface{}{"ssn": "123-45-6789"}},
+			want: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "mask", Field: "res.email", Value: emailMask}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req == nil {
+				tt.req = &model.ReadRequest{}
+			}
+			got, err := m.applyFieldMasks(context.Background(), "project", "db", tt.col, "", tt.auth, tt.req, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyFieldMasks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyFieldMasks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,11 +10,14 @@ import (
 	"strings"
 
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 // MatchRule checks if the rule is matched or not
@@ -26,6 +29,15 @@ func (m *Module) MatchRule(ctx context.Context, project string, rule *config.Rul
 }
 
 func (m *Module) matchRule(ctx context.Context, project string, rule *config.Rule, args, auth map[string]interface{}, returnWhere model.ReturnWhereStub) (*model.PostProcess, error) {
+	ctx, span := tracing.StartSpan(ctx, "auth.match_rule", trace.SpanKindInternal, label.String("auth.rule", rule.Rule))
+	ctx, eval := startRuleEvaluation(ctx, rule, args)
+	postProcess, err := m.evaluateRule(ctx, project, rule, args, auth, returnWhere)
+	endRuleEvaluation(eval, err)
+	tracing.EndSpan(ctx, span, err)
+	return postProcess, err
+}
+
+func (m *Module) evaluateRule(ctx context.Context, project string, rule *config.Rule, args, auth map[string]interface{}, returnWhere model.ReturnWhereStub) (*model.PostProcess, error) {
 	if project != m.project {
 		return nil, formatError(ctx, rule, errors.New("invalid project details provided"))
 	}
@@ -74,11 +86,33 @@ func (m *Module) matchRule(ctx context.Context, project string, rule *config.Rul
 	case "hash":
 		return m.matchHash(ctx, project, rule, args, auth)
 
+	case "flag":
+		return nil, m.matchFlag(ctx, rule, auth)
+
 	default:
 		return nil, formatError(ctx, rule, fmt.Errorf("invalid rule type (%s) provided", rule.Rule))
 	}
 }
 
+// matchFlag allows the request only if the feature flag having the name of the rule is on for the caller
+func (m *Module) matchFlag(ctx context.Context, rule *config.Rule, auth map[string]interface{}) error {
+	if m.evaluateFlag == nil {
+		return formatError(ctx, rule, errors.New("feature flags are not available"))
+	}
+	if auth == nil {
+		auth = map[string]interface{}{}
+	}
+
+	on, err := m.evaluateFlag(rule.Name, auth)
+	if err != nil {
+		return formatError(ctx, rule, err)
+	}
+	if !on {
+		return formatError(ctx, rule, fmt.Errorf("feature flag (%s) is off for the caller", rule.Name))
+	}
+	return nil
+}
+
 func (m *Module) matchFunc(ctx context.Context, rule *config.Rule, MakeHTTPRequest utils.TypeMakeHTTPRequest, args map[string]interface{}) error {
 	newArgs := args["args"].(map[string]interface{})
 
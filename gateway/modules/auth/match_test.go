@@ -761,3 +761,35 @@ func TestModule_matchFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestModule_matchFlag(t *testing.T) {
+	evaluate := func(id string, claims map[string]interface{}) (bool, error) {
+		switch id {
+		case "beta":
+			return claims["role"] == "admin", nil
+		default:
+			return false, errors.New("feature flag does not exist")
+		}
+	}
+	tests := []struct {
+		name     string
+		evaluate utils.EvaluateFeatureFlag
+		rule     *config.Rule
+		auth     map[string]interface{}
+		wantErr  bool
+	}{
+		{name: "flag on", evaluate: evaluate, rule: &config.Rule{Rule: "flag", Name: "beta"}, auth: map[string]interface{}{"role": "admin"}},
+		{name: "flag off", evaluate: evaluate, rule: &config.Rule{Rule: "flag", Name: "beta"}, auth: map[string]interface{}{"role": "user"}, wantErr: true},
+		{name: "unknown flag", evaluate: evaluate, rule: &config.Rule{Rule: "flag", Name: "gamma"}, auth: map[string]interface{}{"role": "admin"}, wantErr: true},
+		{name: "feature flags not available", rule: &config.Rule{Rule: "flag", Name: "beta"}, auth: map[string]interface{}{"role": "admin"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Module{project: "project"}
+			m.SetEvaluateFeatureFlag(tt.evaluate)
+			if _, err := m.matchRule(context.Background(), "project", tt.rule, map[string]interface{}{}, tt.auth, model.ReturnWhereStub{}); (err != nil) != tt.wantErr {
+				t.Errorf("matchRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 
+	"github.com/spaceuptech/helpers"
+
 	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
@@ -18,7 +22,11 @@ func (m *Module) SetProjectConfig(projectConfig *config.ProjectConfig) error {
 		projectConfig.Secrets = []*config.Secret{{KID: utils.AdminSecretKID, Secret: m.adminMan.GetSecret(), IsPrimary: true, Alg: config.HS256}}
 	}
 
-	if err := m.jwt.SetSecrets(projectConfig.Secrets); err != nil {
+	jwtSecrets, err := m.resolveSecrets(projectConfig.Secrets)
+	if err != nil {
+		return err
+	}
+	if err := m.jwt.SetSecrets(jwtSecrets); err != nil {
 		return err
 	}
 
@@ -115,3 +123,61 @@ func (m *Module) SetMakeHTTPRequest(function utils.TypeMakeHTTPRequest) {
 
 	m.makeHTTPRequest = function
 }
+
+// SetResolveSecret sets the function to resolve secrets from external secret managers
+func (m *Module) SetResolveSecret(function utils.ResolveSecret) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.resolveSecret = function
+}
+
+// SetEvaluateFeatureFlag sets the function to evaluate the feature flags of the project
+func (m *Module) SetEvaluateFeatureFlag(function utils.EvaluateFeatureFlag) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.evaluateFlag = function
+}
+
+// SetIsTokenRevoked sets the function to check if a token has been revoked
+func (m *Module) SetIsTokenRevoked(function func(claims map[string]interface{}) bool) {
+	m.jwt.SetIsRevoked(function)
+}
+
+// resolveSecrets returns the jwt secrets with the references to external secret managers replaced by their values.
+// Secrets having references are copied so that the resolved values never end up in the stored config.
+func (m *Module) resolveSecrets(jwtSecrets []*config.Secret) ([]*config.Secret, error) {
+	if m.resolveSecret == nil {
+		return jwtSecrets, nil
+	}
+
+	arr := make([]*config.Secret, len(jwtSecrets))
+	for i, secret := range jwtSecrets {
+		if !secrets.IsReference(secret.Secret) && !secrets.IsReference(secret.PublicKey) && !secrets.IsReference(secret.PrivateKey) {
+			arr[i] = secret
+			continue
+		}
+
+		// Derive the kid from the references so that it doesn't change when the secret gets rotated
+		if secret.KID == "" {
+			h := sha256.New()
+			_, _ = h.Write([]byte(secret.Secret + secret.PublicKey))
+			secret.KID = base64.StdEncoding.EncodeToString(h.Sum(nil))
+		}
+
+		resolved := *secret
+		for _, field := range []*string{&resolved.Secret, &resolved.PublicKey, &resolved.PrivateKey} {
+			if !secrets.IsReference(*field) {
+				continue
+			}
+			value, err := m.resolveSecret(context.TODO(), *field)
+			if err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to resolve jwt secret", err, map[string]interface{}{"project": m.project, "kid": secret.KID})
+			}
+			*field = value
+		}
+		arr[i] = &resolved
+	}
+	return arr, nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type ruleTraceKey struct{}
+
+// ruleTracer records the evaluations of the rules matched with a context
+type ruleTracer struct {
+	lock   sync.Mutex
+	trace  *model.RuleTrace
+	parent *model.RuleEvaluation
+}
+
+// WithRuleTrace returns a context which records the evaluations of the security rules matched with it.
+// The trace must only be read once the rules have been evaluated.
+func WithRuleTrace(ctx context.Context) (context.Context, *model.RuleTrace) {
+	trace := &model.RuleTrace{Evaluations: []*model.RuleEvaluation{}}
+	return context.WithValue(ctx, ruleTraceKey{}, &ruleTracer{trace: trace}), trace
+}
+
+// GetRuleTrace returns the trace of the security rules recorded by a context created with WithRuleTrace
+func GetRuleTrace(ctx context.Context) (*model.RuleTrace, bool) {
+	tracer, ok := ctx.Value(ruleTraceKey{}).(*ruleTracer)
+	if !ok {
+		return nil, false
+	}
+	return tracer.trace, true
+}
+
+// startRuleEvaluation records the evaluation of a rule under the rule being evaluated in the context
+func startRuleEvaluation(ctx context.Context, rule *config.Rule, args map[string]interface{}) (context.Context, *model.RuleEvaluation) {
+	tracer, ok := ctx.Value(ruleTraceKey{}).(*ruleTracer)
+	if !ok {
+		return ctx, nil
+	}
+
+	eval := &model.RuleEvaluation{Rule: rule.Rule, Name: rule.Name}
+	if rule.Rule == "match" {
+		eval.Values = map[string]interface{}{"type": rule.Type, "eval": rule.Eval, "f1": loadOperand(rule.F1, args), "f2": loadOperand(rule.F2, args)}
+	}
+
+	tracer.lock.Lock()
+	if tracer.parent == nil {
+		eval.Path = "rule"
+		tracer.trace.Evaluations = append(tracer.trace.Evaluations, eval)
+	} else {
+		eval.Path = tracer.parent.Path + ".clause"
+		if tracer.parent.Rule == "and" || tracer.parent.Rule == "or" {
+			eval.Path = fmt.Sprintf("%s.clauses[%d]", tracer.parent.Path, len(tracer.parent.Clauses))
+		}
+		tracer.parent.Clauses = append(tracer.parent.Clauses, eval)
+	}
+	tracer.lock.Unlock()
+
+	// The clauses of the rule get recorded under it
+	return context.WithValue(ctx, ruleTraceKey{}, &ruleTracer{trace: tracer.trace, parent: eval}), eval
+}
+
+// endRuleEvaluation records the outcome of the rule
+func endRuleEvaluation(eval *model.RuleEvaluation, err error) {
+	if eval == nil {
+		return
+	}
+	eval.Allowed = err == nil
+	if err != nil {
+		eval.Error = err.Error()
+	}
+}
+
+// loadOperand resolves the variables used as operands in match rules. Literals are returned as is.
+func loadOperand(operand interface{}, args map[string]interface{}) interface{} {
+	s, ok := operand.(string)
+	if !ok {
+		return operand
+	}
+	value, err := utils.LoadValue(s, args)
+	if err != nil {
+		return s
+	}
+	return value
+}
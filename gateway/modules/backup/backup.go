@@ -0,0 +1,190 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/filestore"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	// defaultInterval is used when the interval isn't set in the backup config
+	defaultInterval = 24 * time.Hour
+
+	// schedulerTick is the interval at which the leader checks if a backup is due
+	schedulerTick = time.Minute
+
+	// backupTimeout is the maximum time a scheduled backup may take
+	backupTimeout = time.Hour
+)
+
+// Module periodically backs up the documents of the configured collections of a project to a bucket. Backups are
+// only scheduled on the leader of the cluster, while any node can take a backup on demand or restore one.
+type Module struct {
+	lock sync.RWMutex
+
+	project  string
+	config   *config.BackupConfig
+	store    filestore.FileStore
+	interval time.Duration
+
+	// backupLock serialises the backups and restores
+	backupLock sync.Mutex
+
+	// lastBackupAt is the time of the most recent backup. It is loaded from the store when this node becomes the leader.
+	lastBackupAt time.Time
+	wasLeader    bool
+
+	crud     crudInterface
+	isLeader func() bool
+
+	done    chan struct{}
+	stopped chan struct{}
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// New creates a new instance of the backup module
+func New(project string, crud crudInterface, isLeader func() bool) *Module {
+	return &Module{project: project, crud: crud, isLeader: isLeader, now: time.Now}
+}
+
+// SetConfig sets the config of the backup module and starts scheduling the backups
+func (m *Module) SetConfig(c *config.BackupConfig) error {
+	m.stopScheduler()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config, m.store = nil, nil
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	if len(c.Collections) == 0 {
+		return errors.New("at least one collection must be provided to be backed up")
+	}
+	store, err := newStore(c.Store)
+	if err != nil {
+		return err
+	}
+
+	m.interval = defaultInterval
+	if c.Interval > 0 {
+		m.interval = time.Duration(c.Interval) * time.Second
+	}
+	m.config = c
+	m.store = store
+	m.wasLeader = false
+	m.done = make(chan struct{})
+	m.stopped = make(chan struct{})
+	go m.routineSchedule(m.done, m.stopped)
+	return nil
+}
+
+// CloseConfig stops scheduling the backups
+func (m *Module) CloseConfig() error {
+	m.stopScheduler()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config, m.store = nil, nil
+	return nil
+}
+
+func (m *Module) stopScheduler() {
+	m.lock.Lock()
+	done, stopped := m.done, m.stopped
+	m.done, m.stopped = nil, nil
+	m.lock.Unlock()
+
+	if done == nil {
+		return
+	}
+	close(done)
+	<-stopped
+}
+
+func newStore(c *config.BackupStore) (filestore.FileStore, error) {
+	if c == nil {
+		return nil, errors.New("store of the backups not provided")
+	}
+	switch utils.FileStoreType(c.StoreType) {
+	case utils.Local, utils.AmazonS3, utils.GCPStorage:
+	default:
+		return nil, fmt.Errorf("invalid store type (%s) provided for backups", c.StoreType)
+	}
+	return filestore.NewStore(&config.FileStoreConfig{Enabled: true, StoreType: c.StoreType, Conn: c.Conn, Endpoint: c.Endpoint, Bucket: c.Bucket, DisableSSL: c.DisableSSL, ForcePathStyle: c.ForcePathStyle})
+}
+
+func (m *Module) routineSchedule(done, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if !m.isBackupDue() {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+			if _, err := m.Backup(ctx, model.BackupTriggerScheduled); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to take scheduled backup of project (%s)", m.project), err, nil)
+			}
+			cancel()
+		}
+	}
+}
+
+// isBackupDue checks if this node is the leader and the interval has passed since the most recent backup
+func (m *Module) isBackupDue() bool {
+	isLeader := m.isLeader()
+
+	m.lock.Lock()
+	wasLeader := m.wasLeader
+	m.wasLeader = isLeader
+	m.lock.Unlock()
+
+	if !isLeader {
+		return false
+	}
+
+	// The previous leader might have taken backups in the meantime
+	if !wasLeader {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		backups, err := m.ListBackups(ctx)
+		cancel()
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unable to list the backups of project (%s)", m.project), err, nil)
+			m.lock.Lock()
+			m.wasLeader = false
+			m.lock.Unlock()
+			return false
+		}
+
+		m.lock.Lock()
+		if len(backups) > 0 && backups[0].CreatedAt.After(m.lastBackupAt) {
+			m.lastBackupAt = backups[0].CreatedAt
+		}
+		m.lock.Unlock()
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.now().Sub(m.lastBackupAt) >= m.interval
+}
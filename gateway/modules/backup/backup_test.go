@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
+	"github.com/spaceuptech/space-cloud/gateway/utils/docformat"
+)
+
+type fakeCrud struct {
+	docs      map[string][]interface{} // The key here is the collection
+	restored  map[string][]interface{}
+	truncated []string
+}
+
+func (c *fakeCrud) Export(_ context.Context, _, col string, _ *model.ReadRequest, batchSize int, _ model.RequestParams, write func(docs []interface{}) error) error {
+	docs := c.docs[col]
+	for len(docs) > batchSize {
+		if err := write(docs[:batchSize]); err != nil {
+			return err
+		}
+		docs = docs[batchSize:]
+	}
+	return write(docs)
+}
+
+func (c *fakeCrud) Import(_ context.Context, _, col string, decoder docformat.Decoder, _ int, _ crud.BulkAuthoriser, _ model.RequestParams) (*model.BulkImportResult, error) {
+	result := new(model.BulkImportResult)
+	for {
+		doc, err := decoder.Decode()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.restored[col] = append(c.restored[col], doc)
+		result.Total++
+		result.Imported++
+	}
+}
+
+func (c *fakeCrud) Delete(_ context.Context, _, col string, _ *model.DeleteRequest, _ model.RequestParams) error {
+	c.truncated = append(c.truncated, col)
+	return nil
+}
+
+func TestModule_BackupAndRestore(t *testing.T) {
+	c := &fakeCrud{
+		docs: map[string][]interface{}{
+			"users": {map[string]interface{}{"id": "1", "name": "alice"}, map[string]interface{}{"id": "2", "name": "bob"}},
+			"posts": {},
+		},
+		restored: map[string][]interface{}{},
+	}
+	m := New("myproject", c, func() bool { return false })
+	now := time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	err := m.SetConfig(&config.BackupConfig{
+		Enabled:     true,
+		Collections: []*config.BackupCollection{{DbAlias: "db", Col: "users"}, {DbAlias: "db", Col: "posts"}},
+		Store:       &config.BackupStore{StoreType: "local", Conn: t.TempDir(), Prefix: "backups"},
+		Retention:   &config.BackupRetention{MaxBackups: 2},
+	})
+	if err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	defer func() { _ = m.CloseConfig() }()
+
+	ids := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		info, err := m.Backup(context.Background(), model.BackupTriggerManual)
+		if err != nil {
+			t.Fatalf("Backup() error = %v", err)
+		}
+		if info.Collections[0].Documents != 2 || info.Collections[1].Documents != 0 {
+			t.Errorf("Backup() documents = %v, %v, want 2, 0", info.Collections[0].Documents, info.Collections[1].Documents)
+		}
+		ids = append(ids, info.ID)
+		now = now.Add(time.Hour)
+	}
+
+	backups, err := m.ListBackups(context.Background())
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	got := make([]string, len(backups))
+	for i, b := range backups {
+		got[i] = b.ID
+	}
+	if want := []string{ids[2], ids[1]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListBackups() = %v, want %v", got, want)
+	}
+
+	result, err := m.Restore(context.Background(), ids[2], &model.BackupRestoreRequest{Collections: []*config.BackupCollection{{DbAlias: "db", Col: "users"}}, Truncate: true})
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(result.Collections) != 1 || result.Collections[0].Result.Imported != 2 {
+		t.Errorf("Restore() result = %v, want 2 documents restored in users", result.Collections)
+	}
+	if !reflect.DeepEqual(c.restored["users"], c.docs["users"]) {
+		t.Errorf("Restore() restored documents = %v, want %v", c.restored["users"], c.docs["users"])
+	}
+	if !reflect.DeepEqual(c.truncated, []string{"users"}) {
+		t.Errorf("Restore() truncated collections = %v, want [users]", c.truncated)
+	}
+
+	if _, err := m.Restore(context.Background(), ids[0], &model.BackupRestoreRequest{}); err == nil {
+		t.Errorf("Restore() restored a backup which was past its retention")
+	}
+	if _, err := m.Restore(context.Background(), "../other", &model.BackupRestoreRequest{}); err == nil {
+		t.Errorf("Restore() accepted an invalid backup id")
+	}
+}
+
+func Test_getExpiredBackups(t *testing.T) {
+	now := time.Date(2020, 10, 10, 0, 0, 0, 0, time.UTC)
+	backups := []*model.BackupInfo{
+		{ID: "3", CreatedAt: now.Add(-24 * time.Hour)},
+		{ID: "2", CreatedAt: now.Add(-3 * 24 * time.Hour)},
+		{ID: "1", CreatedAt: now.Add(-8 * 24 * time.Hour)},
+	}
+	tests := []struct {
+		name      string
+		retention *config.BackupRetention
+		want      []string
+	}{
+		{name: "no limits", retention: &config.BackupRetention{}, want: []string{}},
+		{name: "max backups", retention: &config.BackupRetention{MaxBackups: 1}, want: []string{"2", "1"}},
+		{name: "max age", retention: &config.BackupRetention{MaxAge: 7}, want: []string{"1"}},
+		{name: "both limits", retention: &config.BackupRetention{MaxBackups: 2, MaxAge: 2}, want: []string{"2", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getExpiredBackups(backups, tt.retention, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getExpiredBackups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
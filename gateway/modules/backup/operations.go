@@ -0,0 +1,301 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/filestore"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/docformat"
+)
+
+const (
+	manifestFile = "manifest.json"
+
+	// idFormat is the format of the backup ids, which sort in the order the backups were taken
+	idFormat = "20060102-150405.000"
+
+	batchSize = 1000
+)
+
+// Backup writes the documents of the configured collections to a new backup and deletes the backups which are past
+// their retention. Each collection is stored as a gzipped ndjson file next to a manifest describing the backup.
+func (m *Module) Backup(ctx context.Context, trigger string) (*model.BackupInfo, error) {
+	store, c, err := m.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	m.backupLock.Lock()
+	defer m.backupLock.Unlock()
+
+	start := m.now().UTC()
+	info := &model.BackupInfo{ID: start.Format(idFormat), Project: m.project, Trigger: trigger, CreatedAt: start, Collections: make([]*model.BackupCollectionInfo, 0, len(c.Collections))}
+	dir := m.getBackupDir(c, info.ID)
+
+	for _, col := range c.Collections {
+		colInfo, err := m.backupCollection(ctx, store, dir, col)
+		if err != nil {
+			_ = store.DeleteDir(ctx, dir)
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to back up collection (%s) of database (%s)", col.Col, col.DbAlias), err, nil)
+		}
+		info.Collections = append(info.Collections, colInfo)
+	}
+	info.Duration = m.now().UTC().Sub(start).String()
+
+	// The manifest is written last so that incomplete backups are never listed
+	data, err := json.Marshal(info)
+	if err != nil {
+		_ = store.DeleteDir(ctx, dir)
+		return nil, err
+	}
+	if err := store.CreateFile(ctx, &model.CreateFileRequest{Path: dir, Name: manifestFile, Type: "file", MakeAll: true}, strings.NewReader(string(data))); err != nil {
+		_ = store.DeleteDir(ctx, dir)
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to write manifest of backup (%s)", info.ID), err, nil)
+	}
+
+	m.lock.Lock()
+	m.lastBackupAt = start
+	m.lock.Unlock()
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Took backup (%s) of project (%s)", info.ID, m.project), map[string]interface{}{"trigger": trigger, "duration": info.Duration})
+
+	if err := m.applyRetention(ctx, store, c); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete the expired backups of project (%s)", m.project), err, nil)
+	}
+	return info, nil
+}
+
+func (m *Module) backupCollection(ctx context.Context, store filestore.FileStore, dir string, col *config.BackupCollection) (*model.BackupCollectionInfo, error) {
+	info := &model.BackupCollectionInfo{DbAlias: col.DbAlias, Col: col.Col, File: fmt.Sprintf("%s.%s.ndjson.gz", col.DbAlias, col.Col)}
+	attr := map[string]string{"project": m.project, "db": col.DbAlias, "col": col.Col}
+	params := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+
+	// The documents are streamed to the store while they are read from the database
+	pr, pw := io.Pipe()
+	exported := make(chan error, 1)
+	go func() {
+		gz := gzip.NewWriter(pw)
+		encoder, _ := docformat.NewEncoder(docformat.NDJSON, gz, nil)
+		err := m.crud.Export(ctx, col.DbAlias, col.Col, &model.ReadRequest{Find: map[string]interface{}{}}, batchSize, params, func(docs []interface{}) error {
+			for _, doc := range docs {
+				obj, ok := doc.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("database returned a document of unexpected type (%T)", doc)
+				}
+				if err := encoder.Encode(obj); err != nil {
+					return err
+				}
+				info.Documents++
+			}
+			return nil
+		})
+		if err == nil {
+			err = gz.Close()
+		}
+		_ = pw.CloseWithError(err)
+		exported <- err
+	}()
+
+	err := store.CreateFile(ctx, &model.CreateFileRequest{Path: dir, Name: info.File, Type: "file", MakeAll: true}, pr)
+	_ = pr.CloseWithError(errors.New("backup aborted"))
+	if exportErr := <-exported; exportErr != nil {
+		return nil, exportErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ListBackups returns the complete backups of the project with the most recent backup first
+func (m *Module) ListBackups(ctx context.Context) ([]*model.BackupInfo, error) {
+	store, c, err := m.getStore()
+	if err != nil {
+		return nil, err
+	}
+	return m.listBackups(ctx, store, c)
+}
+
+func (m *Module) listBackups(ctx context.Context, store filestore.FileStore, c *config.BackupConfig) ([]*model.BackupInfo, error) {
+	root := m.getProjectDir(c)
+	if err := store.DoesExists(ctx, root); err != nil {
+		// No backups have been taken yet
+		return []*model.BackupInfo{}, nil
+	}
+
+	dirs, err := store.ListDir(ctx, &model.ListFilesRequest{Path: root, Type: "dir"})
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]*model.BackupInfo, 0, len(dirs))
+	for _, dir := range dirs {
+		info, err := readManifest(ctx, store, root+"/"+dir.Name)
+		if err != nil {
+			// The backup is either incomplete or isn't one taken by space cloud
+			continue
+		}
+		backups = append(backups, info)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Restore writes the documents of a backup back to their collections
+func (m *Module) Restore(ctx context.Context, id string, req *model.BackupRestoreRequest) (*model.BackupRestoreResult, error) {
+	store, c, err := m.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := time.Parse(idFormat, id); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid backup id (%s) provided", id), nil, nil)
+	}
+
+	m.backupLock.Lock()
+	defer m.backupLock.Unlock()
+
+	dir := m.getBackupDir(c, id)
+	info, err := readManifest(ctx, store, dir)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Backup (%s) does not exist", id), err, nil)
+	}
+
+	collections := make([]*model.BackupCollectionInfo, 0, len(info.Collections))
+	for _, col := range info.Collections {
+		if isCollectionRequested(req.Collections, col) {
+			collections = append(collections, col)
+		}
+	}
+	if len(collections) != len(req.Collections) && len(req.Collections) > 0 {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Backup (%s) doesn't contain all the requested collections", id), nil, nil)
+	}
+
+	result := &model.BackupRestoreResult{Backup: id, Collections: make([]*model.BackupRestoreCollection, 0, len(collections))}
+	for _, col := range collections {
+		res, err := m.restoreCollection(ctx, store, dir, col, req.Truncate)
+		if err != nil {
+			return result, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to restore collection (%s) of database (%s) from backup (%s)", col.Col, col.DbAlias, id), err, nil)
+		}
+		result.Collections = append(result.Collections, &model.BackupRestoreCollection{DbAlias: col.DbAlias, Col: col.Col, Result: res})
+	}
+	return result, nil
+}
+
+func (m *Module) restoreCollection(ctx context.Context, store filestore.FileStore, dir string, col *model.BackupCollectionInfo, truncate bool) (*model.BulkImportResult, error) {
+	attr := map[string]string{"project": m.project, "db": col.DbAlias, "col": col.Col}
+
+	if truncate {
+		params := model.RequestParams{Resource: "db-delete", Op: "access", Attributes: attr}
+		if err := m.crud.Delete(ctx, col.DbAlias, col.Col, &model.DeleteRequest{Find: map[string]interface{}{}, Operation: utils.All}, params); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := store.ReadFile(ctx, dir+"/"+col.File)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file.File)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := docformat.NewDecoder(docformat.NDJSON, gz, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	params := model.RequestParams{Resource: "db-create", Op: "access", Attributes: attr}
+	return m.crud.Import(ctx, col.DbAlias, col.Col, decoder, batchSize, nil, params)
+}
+
+func (m *Module) applyRetention(ctx context.Context, store filestore.FileStore, c *config.BackupConfig) error {
+	if c.Retention == nil {
+		return nil
+	}
+
+	backups, err := m.listBackups(ctx, store, c)
+	if err != nil {
+		return err
+	}
+	for _, id := range getExpiredBackups(backups, c.Retention, m.now()) {
+		if err := store.DeleteDir(ctx, m.getBackupDir(c, id)); err != nil {
+			return err
+		}
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Deleted expired backup (%s) of project (%s)", id, m.project), nil)
+	}
+	return nil
+}
+
+// getExpiredBackups returns the ids of the backups which are past the retention. The backups must be sorted with the most recent backup first.
+func getExpiredBackups(backups []*model.BackupInfo, retention *config.BackupRetention, now time.Time) []string {
+	expired := make([]string, 0)
+	for i, backup := range backups {
+		tooMany := retention.MaxBackups > 0 && i >= retention.MaxBackups
+		tooOld := retention.MaxAge > 0 && now.Sub(backup.CreatedAt) > time.Duration(retention.MaxAge)*24*time.Hour
+		if tooMany || tooOld {
+			expired = append(expired, backup.ID)
+		}
+	}
+	return expired
+}
+
+func readManifest(ctx context.Context, store filestore.FileStore, dir string) (*model.BackupInfo, error) {
+	file, err := store.ReadFile(ctx, dir+"/"+manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info := new(model.BackupInfo)
+	if err := json.NewDecoder(file.File).Decode(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func isCollectionRequested(requested []*config.BackupCollection, col *model.BackupCollectionInfo) bool {
+	if len(requested) == 0 {
+		return true
+	}
+	for _, r := range requested {
+		if r.DbAlias == col.DbAlias && r.Col == col.Col {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Module) getStore() (filestore.FileStore, *config.BackupConfig, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.store == nil {
+		return nil, nil, fmt.Errorf("backups are not enabled for project (%s)", m.project)
+	}
+	return m.store, m.config, nil
+}
+
+func (m *Module) getProjectDir(c *config.BackupConfig) string {
+	if prefix := strings.Trim(c.Store.Prefix, "/"); prefix != "" {
+		return "/" + prefix + "/" + m.project
+	}
+	return "/" + m.project
+}
+
+func (m *Module) getBackupDir(c *config.BackupConfig, id string) string {
+	return m.getProjectDir(c) + "/" + id
+}
@@ -0,0 +1,15 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
+	"github.com/spaceuptech/space-cloud/gateway/utils/docformat"
+)
+
+type crudInterface interface {
+	Export(ctx context.Context, dbAlias, col string, req *model.ReadRequest, batchSize int, params model.RequestParams, write func(docs []interface{}) error) error
+	Import(ctx context.Context, dbAlias, col string, decoder docformat.Decoder, batchSize int, authorise crud.BulkAuthoriser, params model.RequestParams) (*model.BulkImportResult, error)
+	Delete(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) error
+}
@@ -0,0 +1,144 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Analytics holds the client of a read only analytical database. The reads are translated to sql queries which are
+// fired on the database by its driver. Writes aren't supported.
+type Analytics struct {
+	lock            sync.RWMutex
+	dbType          model.DBType
+	enabled         bool
+	connection      string
+	name            string
+	driverConf      config.DriverConfig
+	queryFetchLimit *int64
+	client          driver
+}
+
+// driver fires the sql queries on an analytical database
+type driver interface {
+	// query runs the query and returns the rows it produced
+	query(ctx context.Context, query string) ([]map[string]interface{}, error)
+	// tables returns the names of the tables in the database
+	tables(ctx context.Context) ([]string, error)
+	close() error
+}
+
+// Init initialises a new analytical database instance
+func Init(dbType model.DBType, enabled bool, connection, dbName string, driverConf config.DriverConfig) (a *Analytics, err error) {
+	a = &Analytics{dbType: dbType, enabled: enabled, connection: connection, name: dbName, driverConf: driverConf}
+
+	if a.enabled {
+		err = a.connect()
+	}
+
+	return
+}
+
+func (a *Analytics) connect() error {
+	timeout := 10 * time.Second
+	if a.driverConf.MaxIdleTimeout > 0 {
+		timeout = time.Duration(a.driverConf.MaxIdleTimeout) * time.Millisecond
+	}
+
+	var client driver
+	var err error
+	switch a.dbType {
+	case model.BigQuery:
+		client, err = newBigQuery(a.connection, a.name)
+	case model.ClickHouse:
+		client, err = newClickHouse(a.connection, a.name, a.driverConf.MaxConn, timeout)
+	default:
+		return fmt.Errorf("invalid analytical database (%s) provided", a.dbType)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := client.query(ctx, "SELECT 1"); err != nil {
+		_ = client.close()
+		return err
+	}
+
+	a.lock.Lock()
+	a.client = client
+	a.lock.Unlock()
+	return nil
+}
+
+func (a *Analytics) getClient() driver {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.client
+}
+
+// Close gracefully closes the client of the database
+func (a *Analytics) Close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.client == nil {
+		return nil
+	}
+	err := a.client.close()
+	a.client = nil
+	return err
+}
+
+// IsSame checks if we've got the same connection string
+func (a *Analytics) IsSame(conn, dbName string, driverConf config.DriverConfig) bool {
+	return a.connection == conn && a.name == dbName && driverConf.MaxConn == a.driverConf.MaxConn && driverConf.MaxIdleTimeout == a.driverConf.MaxIdleTimeout
+}
+
+// IsClientSafe checks whether database is enabled and connected
+func (a *Analytics) IsClientSafe(ctx context.Context) error {
+	if !a.enabled {
+		return utils.ErrDatabaseDisabled
+	}
+
+	if a.getClient() == nil {
+		if err := a.connect(); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to connect to %s", a.dbType), err, nil)
+		}
+	}
+
+	return nil
+}
+
+// GetDBType returns the dbType of the crud block
+func (a *Analytics) GetDBType() model.DBType {
+	return a.dbType
+}
+
+// SetQueryFetchLimit sets data fetch limit
+func (a *Analytics) SetQueryFetchLimit(limit int64) {
+	a.queryFetchLimit = &limit
+}
+
+// SetProjectAESKey sets aes key
+func (a *Analytics) SetProjectAESKey(aesKey []byte) {
+}
+
+// GetConnectionState : function to check connection state
+func (a *Analytics) GetConnectionState(ctx context.Context) bool {
+	client := a.getClient()
+	if !a.enabled || client == nil {
+		return false
+	}
+
+	_, err := client.query(ctx, "SELECT 1")
+	return err == nil
+}
@@ -0,0 +1,181 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+// bigQuery fires the queries as BigQuery jobs. The tables are resolved from the dataset the database is named after.
+type bigQuery struct {
+	project string
+	dataset string
+	service *bq.Service
+}
+
+// newBigQuery creates a driver for the project in the connection string. The connection string is either the id of
+// the project, in which case the application default credentials are used, or the json key of a service account.
+func newBigQuery(connection, dataset string) (*bigQuery, error) {
+	opts := []option.ClientOption{option.WithScopes(bq.BigqueryScope)}
+	project := strings.TrimSpace(connection)
+	if strings.HasPrefix(project, "{") {
+		key := struct {
+			ProjectID string `json:"project_id"`
+		}{}
+		if err := json.Unmarshal([]byte(project), &key); err != nil {
+			return nil, err
+		}
+		opts = append(opts, option.WithCredentialsJSON([]byte(project)))
+		project = key.ProjectID
+	}
+	if project == "" {
+		return nil, errors.New("project id of bigquery not provided")
+	}
+
+	service, err := bq.NewService(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &bigQuery{project: project, dataset: dataset, service: service}, nil
+}
+
+func (b *bigQuery) query(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	useLegacySQL := false
+	req := &bq.QueryRequest{Query: query, UseLegacySql: &useLegacySQL}
+	if b.dataset != "" {
+		req.DefaultDataset = &bq.DatasetReference{ProjectId: b.project, DatasetId: b.dataset}
+	}
+	res, err := b.service.Jobs.Query(b.project, req).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	complete, pageToken, schema := res.JobComplete, res.PageToken, res.Schema
+	rows := convertBigQueryRows(schema, res.Rows)
+
+	// Poll for the results till the job completes and fetch the remaining pages of the results
+	for !complete || pageToken != "" {
+		call := b.service.Jobs.GetQueryResults(b.project, res.JobReference.JobId).Location(res.JobReference.Location).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		page, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		if !page.JobComplete {
+			continue
+		}
+		if !complete {
+			complete, schema = true, page.Schema
+		}
+		pageToken = page.PageToken
+		rows = append(rows, convertBigQueryRows(schema, page.Rows)...)
+	}
+	return rows, nil
+}
+
+func (b *bigQuery) tables(ctx context.Context) ([]string, error) {
+	tables := make([]string, 0)
+	err := b.service.Tables.List(b.project, b.dataset).Pages(ctx, func(list *bq.TableList) error {
+		for _, table := range list.Tables {
+			tables = append(tables, table.TableReference.TableId)
+		}
+		return nil
+	})
+	return tables, err
+}
+
+func (b *bigQuery) close() error {
+	return nil
+}
+
+// convertBigQueryRows converts the rows returned by the rest api, in which every value is a string, to documents
+// having the values of the types in the schema
+func convertBigQueryRows(schema *bq.TableSchema, rows []*bq.TableRow) []map[string]interface{} {
+	docs := make([]map[string]interface{}, 0, len(rows))
+	if schema == nil {
+		return docs
+	}
+	for _, row := range rows {
+		cells := make([]interface{}, len(row.F))
+		for i, cell := range row.F {
+			cells[i] = map[string]interface{}{"v": cell.V}
+		}
+		docs = append(docs, convertBigQueryRecord(schema.Fields, cells))
+	}
+	return docs
+}
+
+func convertBigQueryRecord(fields []*bq.TableFieldSchema, cells []interface{}) map[string]interface{} {
+	doc := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		if i >= len(cells) {
+			break
+		}
+		var v interface{}
+		if cell, ok := cells[i].(map[string]interface{}); ok {
+			v = cell["v"]
+		}
+		doc[field.Name] = convertBigQueryValue(field, v)
+	}
+	return doc
+}
+
+func convertBigQueryValue(field *bq.TableFieldSchema, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	if field.Mode == "REPEATED" {
+		items, _ := v.([]interface{})
+		arr := make([]interface{}, len(items))
+		element := *field
+		element.Mode = "NULLABLE"
+		for i, item := range items {
+			if cell, ok := item.(map[string]interface{}); ok {
+				arr[i] = convertBigQueryValue(&element, cell["v"])
+			}
+		}
+		return arr
+	}
+
+	if field.Type == "RECORD" || field.Type == "STRUCT" {
+		record, _ := v.(map[string]interface{})
+		cells, _ := record["f"].([]interface{})
+		return convertBigQueryRecord(field.Fields, cells)
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch field.Type {
+	case "INTEGER", "INT64":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case "FLOAT", "FLOAT64", "NUMERIC", "BIGNUMERIC":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "BOOLEAN", "BOOL":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "TIMESTAMP":
+		// Timestamps are returned as the number of seconds since the epoch
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			sec, frac := math.Modf(f)
+			return time.Unix(int64(sec), int64(math.Round(frac*1e6))*1e3).UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func Test_convertBigQueryRows(t *testing.T) {
+	schema := &bq.TableSchema{Fields: []*bq.TableFieldSchema{
+		{Name: "id", Type: "INTEGER"},
+		{Name: "score", Type: "FLOAT"},
+		{Name: "active", Type: "BOOLEAN"},
+		{Name: "createdAt", Type: "TIMESTAMP"},
+		{Name: "name", Type: "STRING"},
+		{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+		{Name: "address", Type: "RECORD", Fields: []*bq.TableFieldSchema{{Name: "city", Type: "STRING"}, {Name: "pin", Type: "INTEGER"}}},
+		{Name: "deletedAt", Type: "TIMESTAMP"},
+	}}
+	rows := []*bq.TableRow{{F: []*bq.TableCell{
+		{V: "1"},
+		{V: "2.5"},
+		{V: "true"},
+		{V: "1.6094592E9"},
+		{V: "a"},
+		{V: []interface{}{map[string]interface{}{"v": "x"}, map[string]interface{}{"v": "y"}}},
+		{V: map[string]interface{}{"f": []interface{}{map[string]interface{}{"v": "Pune"}, map[string]interface{}{"v": "411001"}}}},
+		{V: nil},
+	}}}
+
+	want := []map[string]interface{}{{
+		"id":        int64(1),
+		"score":     2.5,
+		"active":    true,
+		"createdAt": "2021-01-01T00:00:00Z",
+		"name":      "a",
+		"tags":      []interface{}{"x", "y"},
+		"address":   map[string]interface{}{"city": "Pune", "pin": int64(411001)},
+		"deletedAt": nil,
+	}}
+	if arr := deep.Equal(convertBigQueryRows(schema, rows), want); len(arr) > 0 {
+		t.Errorf("convertBigQueryRows() differences = %v", arr)
+	}
+}
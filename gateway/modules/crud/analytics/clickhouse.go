@@ -0,0 +1,139 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clickHouse fires the queries over the http interface of ClickHouse. The queries are sent as GET requests which
+// ClickHouse runs in the read only mode.
+type clickHouse struct {
+	url      string
+	user     string
+	password string
+	database string
+	client   *http.Client
+}
+
+type clickHouseResponse struct {
+	Data []map[string]interface{} `json:"data"`
+}
+
+// newClickHouse creates a driver for the ClickHouse server at the url. The credentials are read from the url.
+func newClickHouse(connection, database string, maxConn int, timeout time.Duration) (*clickHouse, error) {
+	u, err := url.Parse(connection)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid clickhouse url (%s) provided - the http interface of clickhouse is to be used", u.Redacted())
+	}
+
+	c := &clickHouse{database: database}
+	if u.User != nil {
+		c.user = u.User.Username()
+		c.password, _ = u.User.Password()
+		u.User = nil
+	}
+	c.url = u.String()
+
+	if maxConn == 0 {
+		maxConn = 100
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = maxConn
+	transport.MaxIdleConnsPerHost = maxConn
+	c.client = &http.Client{Transport: transport, Timeout: timeout}
+	return c, nil
+}
+
+func (c *clickHouse) query(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("default_format", "JSON")
+	params.Set("output_format_json_quote_64bit_integers", "0")
+	if c.database != "" {
+		params.Set("database", c.database)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.user != "" {
+		req.Header.Set("X-ClickHouse-User", c.user)
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(res.Body)
+		return nil, fmt.Errorf("clickhouse responded with status code (%d) - %s", res.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	result := new(clickHouseResponse)
+	decoder := json.NewDecoder(res.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(result); err != nil {
+		return nil, err
+	}
+
+	for _, row := range result.Data {
+		for k, v := range row {
+			row[k] = normaliseNumbers(v)
+		}
+	}
+	return result.Data, nil
+}
+
+func (c *clickHouse) tables(ctx context.Context) ([]string, error) {
+	rows, err := c.query(ctx, "SHOW TABLES")
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if name, ok := row["name"].(string); ok {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+func (c *clickHouse) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// normaliseNumbers converts the json numbers in the value to int64 if they are integers and to float64 otherwise
+func normaliseNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normaliseNumbers(item)
+		}
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = normaliseNumbers(item)
+		}
+	}
+	return v
+}
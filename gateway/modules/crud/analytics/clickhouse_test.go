@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestClickHouse_query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("database") != "analytics" || r.URL.Query().Get("default_format") != "JSON" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("X-ClickHouse-User") != "admin" || r.Header.Get("X-ClickHouse-Key") != "secret" {
+			http.Error(w, "Code: 516. Authentication failed", http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("query") != "SELECT 1" {
+			http.Error(w, "Code: 62. Syntax error", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"meta":[],"data":[{"count":12,"avg":1.5,"name":"a","tags":[1,2]}],"rows":1}`))
+	}))
+	defer server.Close()
+
+	c, err := newClickHouse("http://admin:secret@"+server.Listener.Addr().String(), "analytics", 0, time.Second)
+	if err != nil {
+		t.Fatalf("newClickHouse() error = %v", err)
+	}
+
+	got, err := c.query(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("query() error = %v", err)
+	}
+	want := []map[string]interface{}{{"count": int64(12), "avg": 1.5, "name": "a", "tags": []interface{}{int64(1), int64(2)}}}
+	if arr := deep.Equal(got, want); len(arr) > 0 {
+		t.Errorf("query() differences = %v", arr)
+	}
+
+	if _, err := c.query(context.Background(), "SELEC 1"); err == nil {
+		t.Errorf("query() error = nil, want the error returned by clickhouse")
+	}
+
+	if _, err := newClickHouse("tcp://localhost:9000", "analytics", 0, time.Second); err == nil {
+		t.Errorf("newClickHouse() error = nil, want error for the native protocol")
+	}
+}
@@ -0,0 +1,40 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// GetCollections returns collection / tables name of specified database
+func (a *Analytics) GetCollections(ctx context.Context) ([]utils.DatabaseCollections, error) {
+	client := a.getClient()
+	if client == nil {
+		return nil, utils.ErrDatabaseDisabled
+	}
+
+	tables, err := client.tables(ctx)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to query database to get tables in database (%s)", a.name), err, nil)
+	}
+
+	dbCols := make([]utils.DatabaseCollections, len(tables))
+	for i, table := range tables {
+		dbCols[i] = utils.DatabaseCollections{TableName: table}
+	}
+	return dbCols, nil
+}
+
+// DescribeTable return a structure of sql table
+func (a *Analytics) DescribeTable(ctx context.Context, col string) ([]model.InspectorFieldType, []model.IndexType, error) {
+	return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Describe table operation not supported for selected database", nil, nil)
+}
+
+// Aggregate performs a pipeline aggregation. Aggregations are done with the aggregate clause of reads instead.
+func (a *Analytics) Aggregate(ctx context.Context, col string, req *model.AggregateRequest) (interface{}, error) {
+	return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "aggregate operation not supported for selected database", nil, nil)
+}
@@ -0,0 +1,281 @@
+package analytics
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func init() {
+	goqu.RegisterDialect(string(model.BigQuery), dialectOptions())
+	goqu.RegisterDialect(string(model.ClickHouse), dialectOptions())
+}
+
+// dialectOptions returns the options of the dialects of the analytical databases. Both of them quote identifiers with
+// backticks and escape the quotes in string literals with a backslash.
+func dialectOptions() *goqu.SQLDialectOptions {
+	opts := goqu.DefaultDialectOptions()
+	opts.QuoteRune = '`'
+	opts.SupportsReturn = false
+	opts.SupportsDistinctOn = false
+	opts.TimeFormat = "2006-01-02 15:04:05.999999"
+	opts.EscapedRunes = map[rune][]byte{
+		'\'': []byte("\\'"),
+		'"':  []byte("\\\""),
+		'\\': []byte("\\\\"),
+		'\n': []byte("\\n"),
+		'\r': []byte("\\r"),
+		0:    []byte("\\x00"),
+	}
+	return opts
+}
+
+// generateReadQuery makes a query for read operation. The values are inlined in the query since the drivers don't
+// take query arguments.
+func (a *Analytics) generateReadQuery(col string, req *model.ReadRequest) (string, error) {
+	query := goqu.Dialect(string(a.dbType)).From(col)
+
+	where := make([]exp.Expression, 0, len(req.MatchWhere)+1)
+	for _, find := range append(req.MatchWhere, req.Find) {
+		if len(find) == 0 {
+			continue
+		}
+		e, err := a.generateWhere(find)
+		if err != nil {
+			return "", err
+		}
+		where = append(where, e)
+	}
+	if len(where) > 0 {
+		query = query.Where(where...)
+	}
+
+	opts := req.Options
+	if len(opts.Join) > 0 {
+		return "", fmt.Errorf("joins are not supported by %s", a.dbType)
+	}
+	if opts.Skip != nil {
+		query = query.Offset(uint(*opts.Skip))
+	}
+	if opts.Limit != nil {
+		query = query.Limit(uint(*opts.Limit))
+	}
+	if len(opts.Sort) > 0 {
+		orderBys := make([]exp.OrderedExpression, len(opts.Sort))
+		for i, value := range opts.Sort {
+			if strings.HasPrefix(value, "-") {
+				orderBys[i] = goqu.I(strings.TrimPrefix(value, "-")).Desc()
+			} else {
+				orderBys[i] = goqu.I(value).Asc()
+			}
+		}
+		query = query.Order(orderBys...)
+	}
+
+	selArray := make([]interface{}, 0, len(opts.Select))
+	for _, key := range sortedKeys(opts.Select) {
+		if key != "_dbFetchTs" {
+			selArray = append(selArray, key)
+		}
+	}
+
+	switch req.Operation {
+	case utils.Count:
+		query = query.Select(goqu.COUNT("*").As("count"))
+	case utils.Distinct:
+		if opts.Distinct == nil {
+			return "", utils.ErrInvalidParams
+		}
+		query = query.SelectDistinct(*opts.Distinct)
+	case utils.One:
+		query = query.Select(selArray...)
+	case utils.All:
+		for _, function := range sortedKeys(req.Aggregate) {
+			for _, column := range req.Aggregate[function] {
+				name, as := getAggregateColumnName(column), getAggregateAsColumnName(function, column)
+				switch function {
+				case "sum":
+					selArray = append(selArray, goqu.SUM(name).As(as))
+				case "max":
+					selArray = append(selArray, goqu.MAX(name).As(as))
+				case "min":
+					selArray = append(selArray, goqu.MIN(name).As(as))
+				case "avg":
+					selArray = append(selArray, goqu.AVG(name).As(as))
+				case "count":
+					selArray = append(selArray, goqu.COUNT(name).As(as))
+				default:
+					return "", fmt.Errorf("unknown aggregate function (%s)", function)
+				}
+			}
+		}
+		query = query.Select(selArray...)
+		if len(req.GroupBy) > 0 {
+			query = query.GroupBy(req.GroupBy...)
+		}
+	default:
+		return "", utils.ErrInvalidParams
+	}
+
+	sqlString, _, err := query.ToSQL()
+	return sqlString, err
+}
+
+// generateWhere translates the find clause to a sql expression. The fields are iterated in a sorted order so that
+// the same find clause always results in the same query.
+func (a *Analytics) generateWhere(find map[string]interface{}) (exp.Expression, error) {
+	array := make([]exp.Expression, 0, len(find))
+	for _, k := range sortedKeys(find) {
+		v := find[k]
+		if strings.HasPrefix(k, "$or") {
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("value of (%s) should be an array", k)
+			}
+			orArray := make([]exp.Expression, 0, len(items))
+			for _, item := range items {
+				f, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("elements of (%s) should be objects", k)
+				}
+				// An empty find matches every row
+				if len(f) == 0 {
+					orArray = append(orArray, goqu.L("1 = 1"))
+					continue
+				}
+				e, err := a.generateWhere(f)
+				if err != nil {
+					return nil, err
+				}
+				orArray = append(orArray, e)
+			}
+			array = append(array, goqu.Or(orArray...))
+			continue
+		}
+
+		obj, isObj := v.(map[string]interface{})
+		if !isObj {
+			array = append(array, goqu.I(k).Eq(v))
+			continue
+		}
+
+		for _, op := range sortedKeys(obj) {
+			col, value := goqu.I(k), obj[op]
+			switch op {
+			case "$eq":
+				array = append(array, col.Eq(value))
+			case "$ne":
+				array = append(array, col.Neq(value))
+			case "$gt":
+				array = append(array, col.Gt(value))
+			case "$gte":
+				array = append(array, col.Gte(value))
+			case "$lt":
+				array = append(array, col.Lt(value))
+			case "$lte":
+				array = append(array, col.Lte(value))
+			case "$in":
+				array = append(array, col.In(value))
+			case "$nin":
+				array = append(array, col.NotIn(value))
+			case "$like":
+				array = append(array, col.Like(value))
+			case "$regex":
+				if a.dbType == model.BigQuery {
+					array = append(array, goqu.L("REGEXP_CONTAINS(?, ?)", col, value))
+				} else {
+					array = append(array, goqu.L("match(?, ?)", col, value))
+				}
+			default:
+				return nil, fmt.Errorf("operator (%s) is not supported by %s", op, a.dbType)
+			}
+		}
+	}
+	return goqu.And(array...), nil
+}
+
+// interpolate inlines the arguments in place of the placeholders of the query
+func (a *Analytics) interpolate(query string, args []interface{}) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+	sqlString, _, err := goqu.Dialect(string(a.dbType)).Select(goqu.L(query, args...)).ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(sqlString, "SELECT "), nil
+}
+
+// sortedKeys returns the keys of a map with string keys in a sorted order
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	arr := make([]string, len(keys))
+	for i, key := range keys {
+		arr[i] = key.String()
+	}
+	sort.Strings(arr)
+	return arr
+}
+
+func getAggregateColumnName(column string) string {
+	columnName := strings.Split(column, ":")[1]
+	// NOTE: This is a special case for count aggregate operation
+	if strings.HasSuffix(columnName, "*") {
+		return "*"
+	}
+	return columnName
+}
+
+// getAggregateAsColumnName returns the alias of an aggregated column which processAggregate uses to place the value in
+// the document. It follows the convention of the sql databases.
+func getAggregateAsColumnName(function, column string) string {
+	format := "nested"
+	arr := strings.Split(column, ":")
+
+	returnField := arr[0]
+	column = arr[1]
+	// NOTE: This is a special case for count aggregate operation
+	if strings.HasSuffix(column, "*") {
+		column = strings.Replace(column, "*", returnField, 1)
+	}
+	if len(arr) == 3 && arr[2] == "table" {
+		format = "table"
+	}
+
+	return fmt.Sprintf("%s___%s___%s___%s___%s", utils.GraphQLAggregate, format, returnField, function, strings.Join(strings.Split(column, "."), "__"))
+}
+
+// processAggregate moves the aggregated columns of the row under the aggregate field
+func processAggregate(row map[string]interface{}) {
+	funcMap := map[string]interface{}{}
+	for asColumnName, value := range row {
+		v := strings.Split(asColumnName, "___")
+		if len(v) != 5 || v[0] != utils.GraphQLAggregate {
+			continue
+		}
+		delete(row, asColumnName)
+
+		format, returnField, functionName, columnName := v[1], v[2], v[3], v[4]
+		if format == "table" {
+			row[returnField] = value
+			continue
+		}
+
+		funcValue, ok := funcMap[functionName]
+		if !ok {
+			funcMap[functionName] = map[string]interface{}{columnName: value}
+			continue
+		}
+		funcValue.(map[string]interface{})[columnName] = value
+	}
+	if len(funcMap) > 0 {
+		row[utils.GraphQLAggregate] = funcMap
+	}
+}
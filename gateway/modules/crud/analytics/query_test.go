@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestAnalytics_generateReadQuery(t *testing.T) {
+	limit, skip := int64(10), int64(20)
+	distinct := "country"
+
+	tests := []struct {
+		name    string
+		dbType  model.DBType
+		req     *model.ReadRequest
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "All with where clause, sort and pagination",
+			dbType: model.ClickHouse,
+			req: &model.ReadRequest{
+				Operation: utils.All,
+				Find:      map[string]interface{}{"country": "it's", "age": map[string]interface{}{"$gte": 18}},
+				Options:   &model.ReadOptions{Select: map[string]int32{"name": 1}, Sort: []string{"-age"}, Limit: &limit, Skip: &skip},
+			},
+			want: "SELECT `name` FROM `users` WHERE ((`age` >= 18) AND (`country` = 'it\\'s')) ORDER BY `age` DESC LIMIT 10 OFFSET 20",
+		},
+		{
+			name:   "Or and regex",
+			dbType: model.BigQuery,
+			req: &model.ReadRequest{
+				Operation: utils.All,
+				Find:      map[string]interface{}{"$or": []interface{}{map[string]interface{}{"name": map[string]interface{}{"$regex": "^a"}}, map[string]interface{}{"active": true}}},
+				Options:   &model.ReadOptions{},
+			},
+			want: "SELECT * FROM `users` WHERE (REGEXP_CONTAINS(`name`, '^a') OR (`active` IS TRUE))",
+		},
+		{
+			name:   "Regex on clickhouse",
+			dbType: model.ClickHouse,
+			req: &model.ReadRequest{
+				Operation: utils.All,
+				Find:      map[string]interface{}{"name": map[string]interface{}{"$regex": "^a"}},
+				Options:   &model.ReadOptions{},
+			},
+			want: "SELECT * FROM `users` WHERE match(`name`, '^a')",
+		},
+		{
+			name:   "Count",
+			dbType: model.BigQuery,
+			req:    &model.ReadRequest{Operation: utils.Count, Find: map[string]interface{}{"country": map[string]interface{}{"$in": []interface{}{"in", "us"}}}, Options: &model.ReadOptions{}},
+			want:   "SELECT COUNT(*) AS `count` FROM `users` WHERE (`country` IN ('in', 'us'))",
+		},
+		{
+			name:   "Distinct",
+			dbType: model.BigQuery,
+			req:    &model.ReadRequest{Operation: utils.Distinct, Options: &model.ReadOptions{Distinct: &distinct}},
+			want:   "SELECT DISTINCT `country` FROM `users`",
+		},
+		{
+			name:   "Aggregate with group by",
+			dbType: model.ClickHouse,
+			req: &model.ReadRequest{
+				Operation: utils.All,
+				Aggregate: map[string][]string{"sum": {"amount:amount"}},
+				GroupBy:   []interface{}{"country"},
+				Options:   &model.ReadOptions{Select: map[string]int32{"country": 1}},
+			},
+			want: "SELECT `country`, SUM(`amount`) AS `aggregate___nested___amount___sum___amount` FROM `users` GROUP BY `country`",
+		},
+		{
+			name:    "Unsupported operator",
+			dbType:  model.BigQuery,
+			req:     &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{"tags": map[string]interface{}{"$contains": "a"}}, Options: &model.ReadOptions{}},
+			wantErr: true,
+		},
+		{
+			name:    "Join",
+			dbType:  model.BigQuery,
+			req:     &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Join: []*model.JoinOption{{Table: "orders"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Analytics{dbType: tt.dbType}
+			got, err := a.generateReadQuery("users", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generateReadQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("generateReadQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalytics_interpolate(t *testing.T) {
+	a := &Analytics{dbType: model.ClickHouse}
+	got, err := a.interpolate("SELECT * FROM users WHERE id = ? AND name = ?", []interface{}{float64(1), "a'b"})
+	if err != nil {
+		t.Fatalf("interpolate() error = %v", err)
+	}
+	if want := "SELECT * FROM users WHERE id = 1 AND name = 'a\\'b'"; got != want {
+		t.Errorf("interpolate() = %v, want %v", got, want)
+	}
+}
+
+func Test_isReadStatement(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "SELECT 1", want: true},
+		{query: "  with t as (select 1) select * from t", want: true},
+		{query: "(SELECT 1) UNION ALL (SELECT 2)", want: true},
+		{query: "SHOW TABLES", want: true},
+		{query: "INSERT INTO users VALUES (1)", want: false},
+		{query: "DROP TABLE users", want: false},
+		{query: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := isReadStatement(tt.query); got != tt.want {
+				t.Errorf("isReadStatement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_processAggregate(t *testing.T) {
+	row := map[string]interface{}{
+		"country": "in",
+		"aggregate___nested___amount___sum___amount": int64(10),
+		"aggregate___nested___amount___max___amount": int64(4),
+		"aggregate___table___total___count___total":  int64(3),
+	}
+	processAggregate(row)
+
+	want := map[string]interface{}{
+		"country":   "in",
+		"total":     int64(3),
+		"aggregate": map[string]interface{}{"sum": map[string]interface{}{"amount": int64(10)}, "max": map[string]interface{}{"amount": int64(4)}},
+	}
+	if arr := deep.Equal(row, want); len(arr) > 0 {
+		t.Errorf("processAggregate() differences = %v", arr)
+	}
+}
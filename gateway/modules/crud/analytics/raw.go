@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// readStatements are the statements a raw query can begin with since the database is read only
+var readStatements = []string{"SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXPLAIN"}
+
+// RawQuery runs a read only sql query. The arguments replace the '?' placeholders of the query.
+func (a *Analytics) RawQuery(ctx context.Context, query string, isDebug bool, args []interface{}) (int64, interface{}, *model.SQLMetaData, error) {
+	if !isReadStatement(query) {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Only read queries can be run on %s", a.dbType), nil, nil)
+	}
+
+	sqlString, err := a.interpolate(query, args)
+	if err != nil {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to prepare raw query for %s", a.dbType), err, nil)
+	}
+
+	client := a.getClient()
+	if client == nil {
+		return 0, nil, nil, utils.ErrDatabaseDisabled
+	}
+
+	start := time.Now()
+	rows, err := client.query(ctx, sqlString)
+	if err != nil {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to run raw query on %s", a.dbType), err, nil)
+	}
+	metaData := &model.SQLMetaData{SQL: query, Args: args, QueryTime: time.Since(start).String()}
+
+	array := make([]interface{}, len(rows))
+	for i, row := range rows {
+		if isDebug {
+			row["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+		array[i] = row
+	}
+	return int64(len(array)), array, metaData, nil
+}
+
+// CreateDatabaseIfNotExist creates a project if none exist
+func (a *Analytics) CreateDatabaseIfNotExist(ctx context.Context, project string) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create database operation cannot be performed over selected database", nil, nil)
+}
+
+// RawBatch performs a batch operation for schema creation
+// NOTE: not to be exposed externally
+func (a *Analytics) RawBatch(ctx context.Context, batchedQueries []string) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create raw batch operation cannot be performed over selected database", nil, nil)
+}
+
+func isReadStatement(query string) bool {
+	fields := strings.Fields(strings.TrimLeft(query, "( \t\n"))
+	if len(fields) == 0 {
+		return false
+	}
+	for _, statement := range readStatements {
+		if strings.EqualFold(fields[0], statement) {
+			return true
+		}
+	}
+	return false
+}
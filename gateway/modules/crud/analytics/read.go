@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Read queries document(s) from the database
+func (a *Analytics) Read(ctx context.Context, col string, req *model.ReadRequest) (int64, interface{}, map[string]map[string]string, *model.SQLMetaData, error) {
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+	if req.Options.Limit == nil {
+		req.Options.Limit = a.queryFetchLimit
+		req.Options.HasOptions = true
+	}
+
+	sqlString, err := a.generateReadQuery(col, req)
+	if err != nil {
+		return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to generate read query for %s", a.dbType), err, nil)
+	}
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Executing %s read query", a.dbType), map[string]interface{}{"sqlQuery": sqlString})
+
+	client := a.getClient()
+	if client == nil {
+		return 0, nil, nil, nil, utils.ErrDatabaseDisabled
+	}
+
+	start := time.Now()
+	rows, err := client.query(ctx, sqlString)
+	if err != nil {
+		return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read data from %s", a.dbType), err, nil)
+	}
+	metaData := &model.SQLMetaData{Col: col, SQL: sqlString, QueryTime: time.Since(start).String()}
+
+	switch req.Operation {
+	case utils.Count:
+		if len(rows) == 0 {
+			return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No response from %s for count query", a.dbType), nil, nil)
+		}
+		count, ok := rows[0]["count"].(int64)
+		if !ok {
+			return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid count (%v) returned by %s", rows[0]["count"], a.dbType), nil, nil)
+		}
+		return count, count, map[string]map[string]string{}, metaData, nil
+
+	default:
+		array := make([]interface{}, len(rows))
+		for i, row := range rows {
+			if len(req.Aggregate) > 0 {
+				processAggregate(row)
+			}
+			if req.Options.Debug {
+				row["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+			}
+			array[i] = row
+		}
+
+		if req.Operation == utils.One {
+			if len(array) == 0 {
+				return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "No match found for specified find clause", nil, nil)
+			}
+			return 1, array[0], map[string]map[string]string{}, metaData, nil
+		}
+		return int64(len(array)), array, map[string]map[string]string{}, metaData, nil
+	}
+}
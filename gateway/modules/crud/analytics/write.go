@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// Create isn't supported since the database is read only
+func (a *Analytics) Create(ctx context.Context, col string, req *model.CreateRequest) (int64, error) {
+	return 0, a.errReadOnly(ctx, "Create")
+}
+
+// Update isn't supported since the database is read only
+func (a *Analytics) Update(ctx context.Context, col string, req *model.UpdateRequest) (int64, error) {
+	return 0, a.errReadOnly(ctx, "Update")
+}
+
+// Delete isn't supported since the database is read only
+func (a *Analytics) Delete(ctx context.Context, col string, req *model.DeleteRequest) (int64, error) {
+	return 0, a.errReadOnly(ctx, "Delete")
+}
+
+// Batch isn't supported since the database is read only
+func (a *Analytics) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, error) {
+	return nil, a.errReadOnly(ctx, "Batch")
+}
+
+// DeleteCollection isn't supported since the database is read only
+func (a *Analytics) DeleteCollection(ctx context.Context, col string) error {
+	return a.errReadOnly(ctx, "Delete collection")
+}
+
+func (a *Analytics) errReadOnly(ctx context.Context, operation string) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("%s operation not supported for selected database - %s is read only", operation, a.dbType), nil, nil)
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/spaceuptech/helpers"
 	"go.etcd.io/bbolt"
@@ -32,13 +35,8 @@ func (b *Bolt) Update(ctx context.Context, col string, req *model.UpdateRequest)
 				}
 				// if valid then update
 				if utils.Validate(string(model.EmbeddedDB), req.Find, currentObj) {
-					objToSet, ok := req.Update["$set"].(map[string]interface{})
-					if !ok {
-						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update in bbolt - $set db operator not found or the operator value is not map", nil, nil)
-					}
-
-					for objToSetKey, objToSetValue := range objToSet {
-						currentObj[objToSetKey] = objToSetValue
+					if err := applyUpdate(currentObj, req.Update); err != nil {
+						return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update in bbolt", err, nil)
 					}
 					value, err := json.Marshal(&currentObj)
 					if err != nil {
@@ -63,9 +61,9 @@ func (b *Bolt) Update(ctx context.Context, col string, req *model.UpdateRequest)
 		}
 
 		if req.Operation == utils.Upsert && count == 0 {
-			objToSet, ok := req.Update["$set"].(map[string]interface{})
-			if !ok {
-				return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update in bbolt - $set db operator not found or the operator value is not map", nil, nil)
+			objToSet := map[string]interface{}{}
+			if err := applyUpdate(objToSet, req.Update); err != nil {
+				return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to upsert in bbolt", err, nil)
 			}
 
 			for findName, findValue := range req.Find {
@@ -86,3 +84,151 @@ func (b *Bolt) Update(ctx context.Context, col string, req *model.UpdateRequest)
 		return 0, utils.ErrInvalidParams
 	}
 }
+
+// applyUpdate applies the update operators to a document. Keys with dots refer to nested fields, with numeric parts
+// being the index of an element in an array.
+func applyUpdate(doc, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return errors.New("no update operators provided")
+	}
+	for op, v := range update {
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("value of update operator (%s) should be an object", op)
+		}
+
+		for key, value := range fields {
+			path := strings.Split(key, ".")
+			switch op {
+			case "$set":
+				if err := setNestedValue(doc, path, value); err != nil {
+					return err
+				}
+
+			case "$push", "$addToSet", "$pull":
+				var arr []interface{}
+				if current := getNestedValue(doc, path); current != nil {
+					if arr, ok = current.([]interface{}); !ok {
+						return fmt.Errorf("field (%s) is not an array", key)
+					}
+				}
+				arr, err := applyArrayOperation(op, arr, value)
+				if err != nil {
+					return err
+				}
+				if err := setNestedValue(doc, path, arr); err != nil {
+					return err
+				}
+
+			default:
+				return fmt.Errorf("update operator (%s) is not supported by embedded db", op)
+			}
+		}
+	}
+	return nil
+}
+
+// applyArrayOperation returns a copy of the array with $push, $addToSet or $pull applied. $push and $addToSet accept
+// either a single value or {"$each": [...]} while $pull accepts either a single value or {"$in": [...]}. Elements are
+// compared by their JSON encoding.
+func applyArrayOperation(op string, arr []interface{}, value interface{}) ([]interface{}, error) {
+	modifier := "$each"
+	if op == "$pull" {
+		modifier = "$in"
+	}
+	values := []interface{}{value}
+	if obj, ok := value.(map[string]interface{}); ok && len(obj) == 1 {
+		if v, ok := obj[modifier]; ok {
+			if values, ok = v.([]interface{}); !ok {
+				return nil, fmt.Errorf("value of %s should be an array", modifier)
+			}
+		}
+	}
+
+	if op == "$push" {
+		return append(append(make([]interface{}, 0, len(arr)+len(values)), arr...), values...), nil
+	}
+
+	// For $addToSet the set holds the elements of the array and for $pull the values to be removed
+	set := map[string]struct{}{}
+	from, to := arr, values
+	if op == "$pull" {
+		from, to = values, arr
+	}
+	for _, v := range from {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		set[string(data)] = struct{}{}
+	}
+
+	result := make([]interface{}, 0, len(arr)+len(values))
+	if op == "$addToSet" {
+		result = append(result, arr...)
+	}
+	for _, v := range to {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		_, ok := set[string(data)]
+		switch {
+		case op == "$addToSet" && !ok:
+			set[string(data)] = struct{}{}
+			result = append(result, v)
+		case op == "$pull" && !ok:
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// getNestedValue returns the value at a path or nil if it doesn't exist
+func getNestedValue(obj interface{}, path []string) interface{} {
+	for _, p := range path {
+		switch o := obj.(type) {
+		case map[string]interface{}:
+			obj = o[p]
+		case []interface{}:
+			index, err := strconv.Atoi(p)
+			if err != nil || index < 0 || index >= len(o) {
+				return nil
+			}
+			obj = o[index]
+		default:
+			return nil
+		}
+	}
+	return obj
+}
+
+// setNestedValue sets the value at a path, creating the objects along the path which are missing. Elements of arrays
+// can only be set if they exist.
+func setNestedValue(obj interface{}, path []string, value interface{}) error {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			o[path[0]] = value
+			return nil
+		}
+		child, ok := o[path[0]]
+		if !ok || child == nil {
+			child = map[string]interface{}{}
+			o[path[0]] = child
+		}
+		return setNestedValue(child, path[1:], value)
+
+	case []interface{}:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index >= len(o) {
+			return fmt.Errorf("invalid array index (%s)", path[0])
+		}
+		if len(path) == 1 {
+			o[index] = value
+			return nil
+		}
+		return setNestedValue(o[index], path[1:], value)
+	}
+	return fmt.Errorf("field (%s) cannot be set on a value which is neither an object nor an array", path[0])
+}
@@ -236,3 +236,67 @@ func TestBolt_Update(t *testing.T) {
 		t.Error("error removing database file")
 	}
 }
+
+func Test_applyUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     map[string]interface{}
+		update  map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "set element of array and nested field",
+			doc:    map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			update: map[string]interface{}{"$set": map[string]interface{}{"tags.1": "c", "meta.views": 1}},
+			want:   map[string]interface{}{"tags": []interface{}{"a", "c"}, "meta": map[string]interface{}{"views": 1}},
+		},
+		{
+			name:   "push values to missing array",
+			doc:    map[string]interface{}{},
+			update: map[string]interface{}{"$push": map[string]interface{}{"tags": map[string]interface{}{"$each": []interface{}{"a", "a"}}}},
+			want:   map[string]interface{}{"tags": []interface{}{"a", "a"}},
+		},
+		{
+			name:   "add values which are not present",
+			doc:    map[string]interface{}{"scores": []interface{}{float64(1), float64(2)}},
+			update: map[string]interface{}{"$addToSet": map[string]interface{}{"scores": map[string]interface{}{"$each": []interface{}{2, 3, 3}}}},
+			want:   map[string]interface{}{"scores": []interface{}{float64(1), float64(2), 3}},
+		},
+		{
+			name:   "pull values from nested array",
+			doc:    map[string]interface{}{"meta": map[string]interface{}{"tags": []interface{}{"a", "b", "a", map[string]interface{}{"id": float64(1)}}}},
+			update: map[string]interface{}{"$pull": map[string]interface{}{"meta.tags": map[string]interface{}{"$in": []interface{}{"a", map[string]interface{}{"id": 1}}}}},
+			want:   map[string]interface{}{"meta": map[string]interface{}{"tags": []interface{}{"b"}}},
+		},
+		{
+			name:    "push to field which isn't an array",
+			doc:     map[string]interface{}{"tags": "a"},
+			update:  map[string]interface{}{"$push": map[string]interface{}{"tags": "b"}},
+			wantErr: true,
+		},
+		{
+			name:    "set element out of bounds",
+			doc:     map[string]interface{}{"tags": []interface{}{"a"}},
+			update:  map[string]interface{}{"$set": map[string]interface{}{"tags.1": "b"}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported operator",
+			doc:     map[string]interface{}{},
+			update:  map[string]interface{}{"$rename": map[string]interface{}{"a": "b"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := applyUpdate(tt.doc, tt.update)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.doc, tt.want) {
+				t.Errorf("applyUpdate() doc = %v, want %v", tt.doc, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	defaultBudgetWindow = 60 * time.Second
+	defaultRowCost      = 1
+	defaultJoinCost     = 10
+
+	// budgetBuckets is the number of buckets the sliding window of a budget is split into
+	budgetBuckets = 10
+
+	// maxBudgetCallers is the number of callers tracked after which the idle ones are dropped
+	maxBudgetCallers = 10000
+)
+
+// queryBudget tracks the cost of the reads made to a project over a sliding window and enforces its budgets
+type queryBudget struct {
+	lock sync.Mutex
+
+	config   *config.QueryBudget
+	window   time.Duration
+	rowCost  float64
+	joinCost float64
+
+	project *costWindow
+	callers map[string]*costWindow
+
+	// slot is held by the over budget read being executed when over budget reads are deprioritized
+	slot chan struct{}
+}
+
+// costWindow is the cost spent in the buckets of a sliding window. A bucket only counts if it belongs to the
+// current window.
+type costWindow struct {
+	costs  [budgetBuckets]float64
+	epochs [budgetBuckets]int64
+}
+
+func newQueryBudget(c *config.QueryBudget) *queryBudget {
+	b := &queryBudget{config: c, window: defaultBudgetWindow, rowCost: defaultRowCost, joinCost: defaultJoinCost, project: new(costWindow), callers: map[string]*costWindow{}, slot: make(chan struct{}, 1)}
+	if c.Window > 0 {
+		b.window = time.Duration(c.Window) * time.Second
+	}
+	if c.RowCost > 0 {
+		b.rowCost = c.RowCost
+	}
+	if c.JoinCost > 0 {
+		b.joinCost = c.JoinCost
+	}
+	return b
+}
+
+// SetQueryBudget sets the budgets enforced on the reads of the project. The costs spent so far are reset.
+func (m *Module) SetQueryBudget(budget *config.QueryBudget) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.budget = nil
+	if budget != nil && (budget.PerToken > 0 || budget.PerProject > 0) {
+		m.budget = newQueryBudget(budget)
+	}
+}
+
+func (m *Module) getQueryBudget() *queryBudget {
+	m.RLock()
+	defer m.RUnlock()
+	return m.budget
+}
+
+type budgetChargedKey struct{}
+
+// withBudgetCharged marks the reads made with the context as already charged to their callers. It is used by the
+// data loader which executes the linked reads admitted individually.
+func withBudgetCharged(ctx context.Context) context.Context {
+	return context.WithValue(ctx, budgetChargedKey{}, true)
+}
+
+func isBudgetCharged(ctx context.Context) bool {
+	v, _ := ctx.Value(budgetChargedKey{}).(bool)
+	return v
+}
+
+// admit reserves the estimated cost of a read from the budgets of the caller and the project. The reads which don't
+// fit are either rejected or wait for their turn to be executed one at a time. The returned function must be called
+// with the result of the read to replace the reservation with its actual cost.
+//
+// Callers are identified by the id claim of their token. The reads made by the gateway itself carry the internal
+// user id and are exempt, while the reads of callers without an id share a single budget.
+func (b *queryBudget) admit(ctx context.Context, req *model.ReadRequest, params model.RequestParams, fetchLimit int64) (func(result interface{}, err error), error) {
+	caller, _ := params.Claims["id"].(string)
+	if caller == utils.InternalUserID || isBudgetCharged(ctx) {
+		return func(interface{}, error) {}, nil
+	}
+
+	now := time.Now()
+	estimate := b.cost(req, estimateRows(req, fetchLimit))
+	spent, budget, ok := b.reserve(caller, estimate, now)
+
+	release := func() {}
+	if !ok {
+		if b.config.OverBudget != config.OverBudgetDeprioritize {
+			err := fmt.Errorf("query budget of %v per %v exhausted - %v has been spent and the read is estimated to cost %v", budget, b.window, spent, estimate)
+			return nil, utils.NewError(model.ErrorCodeRateLimited, err).WithDetails(map[string]interface{}{"budget": budget, "spent": spent, "estimate": estimate, "window": b.window.Seconds()})
+		}
+
+		select {
+		case b.slot <- struct{}{}:
+			release = func() { <-b.slot }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		// Deprioritized reads are executed regardless of the budget, but their cost still gets charged
+		b.charge(caller, estimate, now)
+	}
+
+	return func(result interface{}, err error) {
+		release()
+		actual := 0.0
+		if err == nil {
+			actual = b.cost(req, countRows(result))
+		}
+		b.adjust(caller, actual-estimate, now)
+	}, nil
+}
+
+// reserve adds the estimated cost of a read to the windows of the caller and the project if it fits in their
+// budgets. The check and the reservation are made under the same lock, so that concurrent reads can't be admitted
+// on the basis of the same spent cost. The budget which would be exceeded is returned along with the cost spent
+// from it.
+func (b *queryBudget) reserve(caller string, estimate float64, now time.Time) (float64, float64, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	epoch := b.epoch(now)
+	if b.config.PerProject > 0 {
+		if spent := b.project.total(epoch); spent+estimate > b.config.PerProject {
+			return spent, b.config.PerProject, false
+		}
+	}
+	if b.config.PerToken > 0 {
+		if spent := b.getCaller(caller, epoch).total(epoch); spent+estimate > b.config.PerToken {
+			return spent, b.config.PerToken, false
+		}
+	}
+	b.add(caller, epoch, estimate)
+	return 0, 0, true
+}
+
+// charge adds the cost of a read to the windows of the caller and the project
+func (b *queryBudget) charge(caller string, cost float64, now time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.add(caller, b.epoch(now), cost)
+}
+
+// adjust corrects the cost charged for a read at the provided time. Nothing is corrected once the bucket the read
+// was charged to has slid out of the window.
+func (b *queryBudget) adjust(caller string, delta float64, at time.Time) {
+	if delta == 0 {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	epoch := b.epoch(at)
+	b.project.adjust(epoch, delta)
+	if w, ok := b.callers[caller]; ok && b.config.PerToken > 0 {
+		w.adjust(epoch, delta)
+	}
+}
+
+func (b *queryBudget) add(caller string, epoch int64, cost float64) {
+	b.project.add(epoch, cost)
+	if b.config.PerToken > 0 {
+		b.getCaller(caller, epoch).add(epoch, cost)
+	}
+}
+
+// getCaller returns the window of a caller. The lock must be held by the caller.
+func (b *queryBudget) getCaller(caller string, epoch int64) *costWindow {
+	w, ok := b.callers[caller]
+	if !ok {
+		if len(b.callers) >= maxBudgetCallers {
+			for c, cw := range b.callers {
+				if cw.total(epoch) == 0 {
+					delete(b.callers, c)
+				}
+			}
+		}
+		w = new(costWindow)
+		b.callers[caller] = w
+	}
+	return w
+}
+
+// cost returns the cost of a read returning the provided number of rows. Joins get more expensive the deeper they are.
+func (b *queryBudget) cost(req *model.ReadRequest, rows int) float64 {
+	cost := b.rowCost * float64(rows)
+	if req.Options != nil {
+		cost += b.joinCost * float64(joinWeight(req.Options.Join, 1))
+	}
+	return cost
+}
+
+func (b *queryBudget) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(b.window/budgetBuckets)
+}
+
+func (w *costWindow) add(epoch int64, cost float64) {
+	i := epoch % budgetBuckets
+	if w.epochs[i] != epoch {
+		w.epochs[i], w.costs[i] = epoch, 0
+	}
+	w.costs[i] += cost
+}
+
+func (w *costWindow) adjust(epoch int64, delta float64) {
+	if i := epoch % budgetBuckets; w.epochs[i] == epoch {
+		w.costs[i] += delta
+	}
+}
+
+func (w *costWindow) total(epoch int64) float64 {
+	var total float64
+	for i, e := range w.epochs {
+		if e > epoch-budgetBuckets && e <= epoch {
+			total += w.costs[i]
+		}
+	}
+	return total
+}
+
+// joinWeight returns the sum of the depths of the joins
+func joinWeight(joins []*model.JoinOption, depth int) int {
+	weight := 0
+	for _, join := range joins {
+		weight += depth + joinWeight(join.Join, depth+1)
+	}
+	return weight
+}
+
+// estimateRows returns the number of rows a read is expected to return before it gets executed. Reads without a
+// limit are expected to return as many rows as the database sends per request.
+func estimateRows(req *model.ReadRequest, fetchLimit int64) int {
+	if req.Operation != utils.All && req.Operation != utils.Distinct {
+		return 1
+	}
+	if req.Options != nil && req.Options.Limit != nil {
+		return int(*req.Options.Limit)
+	}
+	if fetchLimit <= 0 {
+		return model.DefaultFetchLimit
+	}
+	return int(fetchLimit)
+}
+
+// countRows returns the number of rows in the result of a read
+func countRows(result interface{}) int {
+	switch v := result.(type) {
+	case []interface{}:
+		return len(v)
+	case []map[string]interface{}:
+		return len(v)
+	case nil:
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,216 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_queryBudget_cost(t *testing.T) {
+	limit := int64(50)
+	b := newQueryBudget(&config.QueryBudget{PerToken: 100})
+
+	tests := []struct {
+		name string
+		req  *model.ReadRequest
+		rows int
+		want float64
+	}{
+		{name: "rows returned", req: &model.ReadRequest{Operation: utils.All}, rows: 20, want: 20},
+		{
+			name: "nested joins get more expensive",
+			req:  &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Join: []*model.JoinOption{{Table: "a", Join: []*model.JoinOption{{Table: "b"}}}, {Table: "c"}}}},
+			rows: 5,
+			want: 5 + 10*(1+2+1),
+		},
+		{name: "estimate of a read with a limit", req: &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}, rows: estimateRows(&model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}, 1000), want: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.cost(tt.req, tt.rows); got != tt.want {
+				t.Errorf("cost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_queryBudget_reserve(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	type charge struct {
+		caller string
+		cost   float64
+		at     time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		config   *config.QueryBudget
+		charges  []charge
+		caller   string
+		estimate float64
+		at       time.Duration
+		want     bool
+	}{
+		{
+			name:     "caller within its budget",
+			config:   &config.QueryBudget{PerToken: 10},
+			charges:  []charge{{caller: "1", cost: 5}},
+			caller:   "1",
+			estimate: 5,
+			want:     true,
+		},
+		{
+			name:     "caller over its budget",
+			config:   &config.QueryBudget{PerToken: 10},
+			charges:  []charge{{caller: "1", cost: 8}},
+			caller:   "1",
+			estimate: 5,
+		},
+		{
+			name:     "budgets of other callers are independent",
+			config:   &config.QueryBudget{PerToken: 10},
+			charges:  []charge{{caller: "1", cost: 10}},
+			caller:   "2",
+			estimate: 5,
+			want:     true,
+		},
+		{
+			name:     "project over its budget",
+			config:   &config.QueryBudget{PerToken: 10, PerProject: 15},
+			charges:  []charge{{caller: "1", cost: 10}, {caller: "2", cost: 4}},
+			caller:   "3",
+			estimate: 2,
+		},
+		{
+			name:     "first read of a caller bigger than its budget",
+			config:   &config.QueryBudget{PerToken: 10},
+			caller:   "1",
+			estimate: 11,
+		},
+		{
+			name:     "costs slide out of the window",
+			config:   &config.QueryBudget{PerToken: 10, Window: 60},
+			charges:  []charge{{caller: "1", cost: 8}, {caller: "1", cost: 2, at: 30 * time.Second}},
+			caller:   "1",
+			estimate: 5,
+			at:       65 * time.Second,
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newQueryBudget(tt.config)
+			for _, c := range tt.charges {
+				b.charge(c.caller, c.cost, now.Add(c.at))
+			}
+			if _, _, got := b.reserve(tt.caller, tt.estimate, now.Add(tt.at)); got != tt.want {
+				t.Errorf("reserve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_estimateRows(t *testing.T) {
+	limit := int64(50)
+	tests := []struct {
+		name       string
+		req        *model.ReadRequest
+		fetchLimit int64
+		want       int
+	}{
+		{name: "read of a single row", req: &model.ReadRequest{Operation: utils.One}, fetchLimit: 1000, want: 1},
+		{name: "count", req: &model.ReadRequest{Operation: utils.Count}, fetchLimit: 1000, want: 1},
+		{name: "read with a limit", req: &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}, fetchLimit: 1000, want: 50},
+		{name: "read without a limit", req: &model.ReadRequest{Operation: utils.All}, fetchLimit: 200, want: 200},
+		{name: "distinct without a limit", req: &model.ReadRequest{Operation: utils.Distinct, Options: &model.ReadOptions{}}, fetchLimit: 200, want: 200},
+		{name: "read without a fetch limit", req: &model.ReadRequest{Operation: utils.All}, want: model.DefaultFetchLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateRows(tt.req, tt.fetchLimit); got != tt.want {
+				t.Errorf("estimateRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_queryBudget_admit(t *testing.T) {
+	limit := int64(1)
+	req := &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}
+	claims := model.RequestParams{Claims: map[string]interface{}{"id": "1"}}
+
+	b := newQueryBudget(&config.QueryBudget{PerToken: 3})
+	for i := 0; i < 3; i++ {
+		charge, err := b.admit(context.Background(), req, claims, 1000)
+		if err != nil {
+			t.Fatalf("admit() of read (%d) returned error - %v", i, err)
+		}
+		charge([]interface{}{map[string]interface{}{}}, nil)
+	}
+
+	var apiErr *utils.Error
+	if _, err := b.admit(context.Background(), req, claims, 1000); !errors.As(err, &apiErr) || apiErr.Code != model.ErrorCodeRateLimited {
+		t.Errorf("admit() of over budget read returned error (%v), want a rate limited error", err)
+	}
+	if _, err := b.admit(context.Background(), req, model.RequestParams{Claims: map[string]interface{}{"id": utils.InternalUserID}}, 1000); err != nil {
+		t.Errorf("admit() of internal read returned error - %v", err)
+	}
+
+	// Deprioritized reads wait for the over budget read being executed
+	b.config.OverBudget = config.OverBudgetDeprioritize
+	charge, err := b.admit(context.Background(), req, claims, 1000)
+	if err != nil {
+		t.Fatalf("admit() of deprioritized read returned error - %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.admit(ctx, req, claims, 1000); err != context.DeadlineExceeded {
+		t.Errorf("admit() of second deprioritized read returned error (%v), want it to wait", err)
+	}
+	charge(nil, nil)
+	if _, err := b.admit(context.Background(), req, claims, 1000); err != nil {
+		t.Errorf("admit() of deprioritized read after the slot was released returned error - %v", err)
+	}
+}
+
+func Test_queryBudget_admit_reservation(t *testing.T) {
+	limit := int64(5)
+	req := &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Limit: &limit}}
+	claims := model.RequestParams{Claims: map[string]interface{}{"id": "1"}}
+	b := newQueryBudget(&config.QueryBudget{PerToken: 10})
+
+	// Reads in flight hold the reservation of their estimated cost
+	charges := make([]func(interface{}, error), 0)
+	for i := 0; i < 2; i++ {
+		charge, err := b.admit(context.Background(), req, claims, 1000)
+		if err != nil {
+			t.Fatalf("admit() of read (%d) returned error - %v", i, err)
+		}
+		charges = append(charges, charge)
+	}
+	if _, err := b.admit(context.Background(), req, claims, 1000); err == nil {
+		t.Fatalf("admit() of read exceeding the reservations of the reads in flight returned no error")
+	}
+
+	// The reservations are replaced by the actual cost of the reads, while failed reads cost nothing
+	charges[0]([]interface{}{map[string]interface{}{}}, nil)
+	charges[1](nil, errors.New("failed"))
+	if spent := b.callers["1"].total(b.epoch(time.Now())); spent != 1 {
+		t.Errorf("cost spent after the reads = %v, want 1", spent)
+	}
+
+	// Reads without a limit are estimated to return as many rows as the database sends per request
+	if _, err := b.admit(context.Background(), &model.ReadRequest{Operation: utils.All}, claims, 20); err == nil {
+		t.Errorf("admit() of read without a limit returned no error, want it to be estimated with the fetch limit")
+	}
+
+	// Reads already charged to their callers aren't charged again
+	if _, err := b.admit(withBudgetCharged(context.Background()), &model.ReadRequest{Operation: utils.All}, claims, 20); err != nil {
+		t.Errorf("admit() of read already charged returned error - %v", err)
+	}
+}
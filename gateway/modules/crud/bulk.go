@@ -0,0 +1,176 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/docformat"
+)
+
+const (
+	defaultBulkBatchSize = 100
+	maxBulkBatchSize     = 1000
+	maxBulkRowErrors     = 100
+)
+
+// BulkAuthoriser checks if a document of a bulk import may be written. It may modify the document as per the
+// security rules of the collection.
+type BulkAuthoriser func(ctx context.Context, doc map[string]interface{}) error
+
+// Import writes the documents read by the decoder in batches. Rows which can't be decoded or aren't authorised are
+// skipped and reported in the result. A batch which fails on a sql database is retried one document at a time to
+// find the failing rows. Other databases may write a part of a failing batch, hence all of its rows are reported.
+func (m *Module) Import(ctx context.Context, dbAlias, col string, decoder docformat.Decoder, batchSize int, authorise BulkAuthoriser, params model.RequestParams) (*model.BulkImportResult, error) {
+	dbType, err := m.GetDBType(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+	isAtomic := dbType != string(model.Mongo) && dbType != string(model.EmbeddedDB) && dbType != string(model.Redis)
+
+	batchSize = getBulkBatchSize(batchSize)
+	result := &model.BulkImportResult{Errors: []*model.BulkRowError{}}
+	rows := make([]int, 0, batchSize)
+	docs := make([]interface{}, 0, batchSize)
+
+	flush := func() {
+		if len(docs) == 0 {
+			return
+		}
+		m.importBatch(ctx, dbAlias, col, rows, docs, isAtomic, params, result)
+		rows, docs = make([]int, 0, batchSize), make([]interface{}, 0, batchSize)
+	}
+
+	for row := 1; ; row++ {
+		if err := ctx.Err(); err != nil {
+			flush()
+			return result, err
+		}
+
+		doc, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var rowErr *docformat.RowError
+			if !errors.As(err, &rowErr) {
+				flush()
+				return result, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read row (%d) of the documents to be imported in (%s)", row, col), err, nil)
+			}
+			result.Total++
+			addBulkRowError(result, row, err)
+			continue
+		}
+
+		result.Total++
+		if authorise != nil {
+			if err := authorise(ctx, doc); err != nil {
+				addBulkRowError(result, row, err)
+				continue
+			}
+		}
+
+		rows = append(rows, row)
+		docs = append(docs, doc)
+		if len(docs) >= batchSize {
+			flush()
+		}
+	}
+
+	flush()
+	return result, nil
+}
+
+func (m *Module) importBatch(ctx context.Context, dbAlias, col string, rows []int, docs []interface{}, isAtomic bool, params model.RequestParams, result *model.BulkImportResult) {
+	err := m.Create(ctx, dbAlias, col, &model.CreateRequest{Document: docs, Operation: utils.All}, params)
+	if err == nil {
+		result.Imported += len(docs)
+		return
+	}
+
+	if !isAtomic || len(docs) == 1 {
+		for _, row := range rows {
+			addBulkRowError(result, row, err)
+		}
+		return
+	}
+
+	for i, doc := range docs {
+		if err := m.Create(ctx, dbAlias, col, &model.CreateRequest{Document: doc, Operation: utils.One}, params); err != nil {
+			addBulkRowError(result, rows[i], err)
+			continue
+		}
+		result.Imported++
+	}
+}
+
+func addBulkRowError(result *model.BulkImportResult, row int, err error) {
+	result.Failed++
+	if len(result.Errors) >= maxBulkRowErrors {
+		result.ErrorsTruncated = true
+		return
+	}
+	result.Errors = append(result.Errors, &model.BulkRowError{Row: row, Error: err.Error()})
+}
+
+// Export reads the documents matching the request in pages of the batch size and passes every page to write. Pages are
+// sorted on the primary keys of the collection unless a sort order is provided, so that they stay stable across reads.
+func (m *Module) Export(ctx context.Context, dbAlias, col string, req *model.ReadRequest, batchSize int, params model.RequestParams, write func(docs []interface{}) error) error {
+	batchSize = getBulkBatchSize(batchSize)
+	if req.Options == nil {
+		req.Options = new(model.ReadOptions)
+	}
+	if len(req.Options.Sort) == 0 {
+		req.Options.Sort = m.GetPrimaryKeys(dbAlias, col)
+	}
+
+	for skip := int64(0); ; skip += int64(batchSize) {
+		limit, offset := int64(batchSize), skip
+		options := *req.Options
+		options.Limit, options.Skip = &limit, &offset
+
+		page := *req
+		page.Operation = utils.All
+		page.Options = &options
+
+		result, _, err := m.Read(ctx, dbAlias, col, &page, params)
+		if err != nil {
+			return err
+		}
+		docs, ok := result.([]interface{})
+		if !ok {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to export documents of (%s) - database returned an unexpected result of type (%T)", col, result), nil, nil)
+		}
+
+		if err := write(docs); err != nil {
+			return err
+		}
+		if len(docs) < batchSize {
+			return nil
+		}
+	}
+}
+
+// GetPrimaryKeys returns the primary keys of a collection in a stable order
+func (m *Module) GetPrimaryKeys(dbAlias, col string) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	dbType, _ := m.getDBType(dbAlias)
+	return m.getPrimaryKeys(dbAlias, dbType, col)
+}
+
+func getBulkBatchSize(batchSize int) int {
+	if batchSize <= 0 {
+		return defaultBulkBatchSize
+	}
+	if batchSize > maxBulkBatchSize {
+		return maxBulkBatchSize
+	}
+	return batchSize
+}
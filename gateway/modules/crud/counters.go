@@ -0,0 +1,315 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	// defaultCounterShards is the number of shard rows a document is given for its sharded counters
+	defaultCounterShards = 10
+
+	// The fields of the shard rows identifying the document and the shard they belong to
+	counterShardDoc   = "doc"
+	counterShardIndex = "shard"
+)
+
+// Increment atomically increments the counter fields of the documents matching the where clause. The increments are
+// made with the native atomic operation of the database, so concurrent increments from any number of gateways never
+// lose an update. Increments of sharded counters are made on one of the shard rows of the document instead.
+func (m *Module) Increment(ctx context.Context, dbAlias, col string, req *model.IncrementRequest, params model.RequestParams) ([]interface{}, error) {
+	inc, err := normalizeIncrements(req.Inc)
+	if err != nil {
+		return nil, err
+	}
+
+	m.RLock()
+	counters, ok := m.counters[getWriteHookKey(dbAlias, col)]
+	dbType, _ := m.getDBType(dbAlias)
+	m.RUnlock()
+
+	direct, sharded := splitIncrements(counters, inc)
+	if len(sharded) > 0 {
+		if err := m.incrementShard(ctx, counters, dbAlias, col, dbType, req.Find, sharded, params); err != nil {
+			return nil, err
+		}
+	}
+	if len(direct) == 0 {
+		if !req.Returning {
+			return []interface{}{}, nil
+		}
+		// The counters are summed up by the read
+		result, _, err := m.Read(ctx, dbAlias, col, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}}, params)
+		if err != nil {
+			return nil, err
+		}
+		docs, _ := result.([]interface{})
+		return docs, nil
+	}
+
+	op := utils.All
+	if req.Upsert {
+		op = utils.Upsert
+	}
+	docs, err := m.UpdateReturning(ctx, dbAlias, col, &model.UpdateRequest{Find: req.Find, Operation: op, Update: map[string]interface{}{"$inc": direct}, Returning: req.Returning}, params)
+	if err != nil || !ok || !req.Returning {
+		return docs, err
+	}
+	if err := m.sumCounterShards(ctx, counters, dbAlias, col, dbType, nil, docs, params); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// SetShardedCounters sets the sharded counters of the collections from their database rules
+func (m *Module) SetShardedCounters(rules config.DatabaseRules) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.counters = map[string]*config.ShardedCounters{}
+	for _, rule := range rules {
+		if rule.ShardedCounters != nil && len(rule.ShardedCounters.Fields) > 0 {
+			m.counters[getWriteHookKey(rule.DbAlias, rule.Table)] = rule.ShardedCounters
+		}
+	}
+}
+
+// incrementShard increments the counters on a shard row of the document picked at random. Since the shard rows are
+// upserted with the atomic increment of the database, concurrent increments spread over the shards instead of
+// contending on the row of the document.
+func (m *Module) incrementShard(ctx context.Context, c *config.ShardedCounters, dbAlias, col, dbType string, find, inc map[string]interface{}, params model.RequestParams) error {
+	key := getCounterKey(c, dbType)
+	doc, ok := find[key]
+	if _, isOp := doc.(map[string]interface{}); !ok || isOp || doc == nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Increments of the sharded counters of (%s) must match a single document by its field (%s)", col, key), nil, nil)
+	}
+
+	shard := int64(rand.Intn(getCounterShards(c)))
+	req := &model.UpdateRequest{
+		Find:      map[string]interface{}{counterShardDoc: fmt.Sprintf("%v", doc), counterShardIndex: shard},
+		Operation: utils.Upsert,
+		Update:    map[string]interface{}{"$inc": inc},
+	}
+	return m.Update(ctx, dbAlias, getCounterShardsCollection(c, col), req, params)
+}
+
+// sumShardedCounters adds the shards of the sharded counters to the documents returned by a read
+func (m *Module) sumShardedCounters(ctx context.Context, dbAlias, col string, req *model.ReadRequest, result interface{}, params model.RequestParams) error {
+	m.RLock()
+	c, ok := m.counters[getWriteHookKey(dbAlias, col)]
+	dbType, _ := m.getDBType(dbAlias)
+	m.RUnlock()
+
+	// Only the documents themselves carry the counters
+	if !ok || len(req.Aggregate) > 0 || len(req.GroupBy) > 0 || (req.Options != nil && req.Options.ReadAt != "") {
+		return nil
+	}
+
+	var docs []interface{}
+	switch v := result.(type) {
+	case []interface{}:
+		docs = v
+	case map[string]interface{}:
+		docs = []interface{}{v}
+	default:
+		return nil
+	}
+
+	var sel map[string]int32
+	if req.Options != nil {
+		sel = req.Options.Select
+	}
+	return m.sumCounterShards(ctx, c, dbAlias, col, dbType, sel, docs, params)
+}
+
+// sumCounterShards sets the value of the sharded counters of the documents to the sum of their shards. The value kept
+// in the document itself, if any, is treated as another shard. Counters left out of the select clause are skipped.
+func (m *Module) sumCounterShards(ctx context.Context, c *config.ShardedCounters, dbAlias, col, dbType string, sel map[string]int32, docs []interface{}, params model.RequestParams) error {
+	fields := make([]string, 0, len(c.Fields))
+	for _, field := range c.Fields {
+		if _, p := sel[field]; len(sel) == 0 || p {
+			fields = append(fields, field)
+		}
+	}
+	key := getCounterKey(c, dbType)
+	keys := make([]interface{}, 0, len(docs))
+	for _, item := range docs {
+		if doc, ok := item.(map[string]interface{}); ok && doc[key] != nil {
+			keys = append(keys, fmt.Sprintf("%v", doc[key]))
+		}
+	}
+	if len(fields) == 0 || len(keys) == 0 {
+		return nil
+	}
+
+	// The shards are read in chunks of documents so that the shard rows of a chunk fit in a single page
+	fetchLimit, err := m.getPageLimit(dbAlias, nil)
+	if err != nil {
+		fetchLimit = model.DefaultFetchLimit
+	}
+	chunk := int(fetchLimit) / getCounterShards(c)
+	if chunk < 1 {
+		chunk = 1
+	}
+
+	sums := map[string]*counterSum{}
+	shardsCol := getCounterShardsCollection(c, col)
+	for start := 0; start < len(keys); start += chunk {
+		end := start + chunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		req := &model.ReadRequest{Find: map[string]interface{}{counterShardDoc: map[string]interface{}{"$in": keys[start:end]}}, Operation: utils.All, Options: &model.ReadOptions{}}
+		result, _, err := m.Read(withBudgetCharged(ctx), dbAlias, shardsCol, req, params)
+		if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read the counter shards of (%s)", col), err, nil)
+		}
+		shards, _ := result.([]interface{})
+		for _, item := range shards {
+			shard, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			doc := fmt.Sprintf("%v", shard[counterShardDoc])
+			if sums[doc] == nil {
+				sums[doc] = newCounterSum()
+			}
+			sums[doc].add(shard, fields)
+		}
+	}
+
+	for _, item := range docs {
+		doc, ok := item.(map[string]interface{})
+		if !ok || doc[key] == nil {
+			continue
+		}
+		sum := newCounterSum()
+		sum.add(doc, fields)
+		if shards, ok := sums[fmt.Sprintf("%v", doc[key])]; ok {
+			sum.merge(shards)
+		}
+		for _, field := range fields {
+			doc[field] = sum.value(field)
+		}
+	}
+	return nil
+}
+
+func splitIncrements(c *config.ShardedCounters, inc map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if c == nil {
+		return inc, nil
+	}
+
+	direct, sharded := map[string]interface{}{}, map[string]interface{}{}
+	for k, v := range inc {
+		if utils.StringExists(c.Fields, k) {
+			sharded[k] = v
+			continue
+		}
+		direct[k] = v
+	}
+	return direct, sharded
+}
+
+func getCounterKey(c *config.ShardedCounters, dbType string) string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return utils.GetIDVariable(dbType)
+}
+
+func getCounterShards(c *config.ShardedCounters) int {
+	if c.Shards <= 0 {
+		return defaultCounterShards
+	}
+	return c.Shards
+}
+
+func getCounterShardsCollection(c *config.ShardedCounters, col string) string {
+	if c.Collection != "" {
+		return c.Collection
+	}
+	return col + "_counter_shards"
+}
+
+// counterSum sums up the shards of counters. The integer parts are kept separate so that integer counters don't turn
+// into floating point ones.
+type counterSum struct {
+	ints   map[string]int64
+	floats map[string]float64
+}
+
+func newCounterSum() *counterSum {
+	return &counterSum{ints: map[string]int64{}, floats: map[string]float64{}}
+}
+
+func (s *counterSum) add(doc map[string]interface{}, fields []string) {
+	for _, field := range fields {
+		switch v := doc[field].(type) {
+		case int:
+			s.ints[field] += int64(v)
+		case int32:
+			s.ints[field] += int64(v)
+		case int64:
+			s.ints[field] += v
+		case float32:
+			s.floats[field] += float64(v)
+		case float64:
+			s.floats[field] += v
+		}
+	}
+}
+
+func (s *counterSum) merge(other *counterSum) {
+	for k, v := range other.ints {
+		s.ints[k] += v
+	}
+	for k, v := range other.floats {
+		s.floats[k] += v
+	}
+}
+
+func (s *counterSum) value(field string) interface{} {
+	if f, ok := s.floats[field]; ok {
+		return float64(s.ints[field]) + f
+	}
+	return s.ints[field]
+}
+
+// normalizeIncrements checks that the increments are numbers. Whole numbers are converted to integers so that they
+// don't turn integer fields into floating point ones.
+func normalizeIncrements(inc map[string]interface{}) (map[string]interface{}, error) {
+	if len(inc) == 0 {
+		return nil, errors.New("no counter fields provided to increment")
+	}
+
+	result := make(map[string]interface{}, len(inc))
+	for k, v := range inc {
+		switch val := v.(type) {
+		case int:
+			result[k] = int64(val)
+		case int32:
+			result[k] = int64(val)
+		case int64:
+			result[k] = val
+		case float64:
+			if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+				result[k] = int64(val)
+				continue
+			}
+			result[k] = val
+		default:
+			return nil, fmt.Errorf("increment of counter field (%s) must be a number", k)
+		}
+	}
+	return result, nil
+}
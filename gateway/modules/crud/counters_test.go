@@ -0,0 +1,206 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/caching"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_normalizeIncrements(t *testing.T) {
+	tests := []struct {
+		name    string
+		inc     map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{name: "whole numbers", inc: map[string]interface{}{"likes": float64(1), "views": -2}, want: map[string]interface{}{"likes": int64(1), "views": int64(-2)}},
+		{name: "fractions", inc: map[string]interface{}{"score": 0.5}, want: map[string]interface{}{"score": 0.5}},
+		{name: "no fields", inc: map[string]interface{}{}, wantErr: true},
+		{name: "not a number", inc: map[string]interface{}{"likes": "1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeIncrements(tt.inc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("normalizeIncrements() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeIncrements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// counterCrud keeps the documents in memory. Only equality and $in where clauses and $inc updates of integers are
+// supported.
+type counterCrud struct {
+	fakeCrud
+	lock sync.Mutex
+	cols map[string][]map[string]interface{}
+}
+
+func (c *counterCrud) Read(_ context.Context, col string, req *model.ReadRequest) (int64, interface{}, map[string]map[string]string, *model.SQLMetaData, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	docs := []interface{}{}
+	for _, doc := range c.cols[col] {
+		if !matchesFind(doc, req.Find) {
+			continue
+		}
+		copied := map[string]interface{}{}
+		for k, v := range doc {
+			if _, p := req.Options.Select[k]; len(req.Options.Select) == 0 || p {
+				copied[k] = v
+			}
+		}
+		docs = append(docs, copied)
+	}
+	if req.Operation == utils.One {
+		if len(docs) == 0 {
+			return 0, nil, nil, nil, errors.New("no document found")
+		}
+		return 1, docs[0], nil, nil, nil
+	}
+	return int64(len(docs)), docs, nil, nil, nil
+}
+
+func (c *counterCrud) Update(_ context.Context, col string, req *model.UpdateRequest) (int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var n int64
+	for _, doc := range c.cols[col] {
+		if matchesFind(doc, req.Find) {
+			increment(doc, req.Update["$inc"].(map[string]interface{}))
+			n++
+		}
+	}
+	if n == 0 && req.Operation == utils.Upsert {
+		doc := map[string]interface{}{}
+		for k, v := range req.Find {
+			doc[k] = v
+		}
+		increment(doc, req.Update["$inc"].(map[string]interface{}))
+		c.cols[col] = append(c.cols[col], doc)
+		n = 1
+	}
+	return n, nil
+}
+
+func (c *counterCrud) UpdateReturning(ctx context.Context, col string, req *model.UpdateRequest) (int64, []interface{}, error) {
+	n, err := c.Update(ctx, col, req)
+	if err != nil {
+		return 0, nil, err
+	}
+	_, docs, _, _, err := c.Read(ctx, col, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}})
+	return n, docs.([]interface{}), err
+}
+
+func (c *counterCrud) CreateReturning(context.Context, string, *model.CreateRequest) (int64, []interface{}, error) {
+	return 0, nil, errors.New("not supported")
+}
+
+func (c *counterCrud) DeleteReturning(context.Context, string, *model.DeleteRequest) (int64, []interface{}, error) {
+	return 0, nil, errors.New("not supported")
+}
+
+func matchesFind(doc, find map[string]interface{}) bool {
+	for k, v := range find {
+		if cond, ok := v.(map[string]interface{}); ok {
+			if !utils.ArrayContains(cond["$in"].([]interface{}), doc[k]) {
+				return false
+			}
+			continue
+		}
+		if doc[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func increment(doc, inc map[string]interface{}) {
+	for k, v := range inc {
+		current, _ := doc[k].(int64)
+		doc[k] = current + v.(int64)
+	}
+}
+
+// uncachedCaching never has the result of a read cached
+type uncachedCaching struct{ fakeCaching }
+
+func (uncachedCaching) GetDatabaseKey(context.Context, string, string, string, *model.ReadRequest) (*caching.CacheResult, error) {
+	return new(caching.CacheResult), nil
+}
+
+func TestModule_shardedCounters(t *testing.T) {
+	block := &counterCrud{cols: map[string][]map[string]interface{}{
+		"posts": {{"id": "1", "title": "a", "likes": int64(5), "views": int64(0)}, {"id": "2", "title": "b"}},
+	}}
+	m := Init()
+	m.blocks["db"] = block
+	m.databaseConfigs["db"] = &config.DatabaseConfig{DbAlias: "db", Type: string(model.Postgres)}
+	m.integrationMan = fakeIntegrationManager{}
+	m.caching = uncachedCaching{}
+	m.schemaDoc = model.Type{"db": model.Collection{}}
+	m.metricHook = func(string, string, string, int64, model.OperationType) {}
+	m.SetShardedCounters(config.DatabaseRules{"posts": &config.DatabaseRule{DbAlias: "db", Table: "posts", ShardedCounters: &config.ShardedCounters{Fields: []string{"likes"}, Shards: 4}}})
+
+	// Concurrent increments are spread over the shards without losing any of them
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Increment(context.Background(), "db", "posts", &model.IncrementRequest{Find: map[string]interface{}{"id": "1"}, Inc: map[string]interface{}{"likes": 1}}, model.RequestParams{}); err != nil {
+				t.Errorf("Increment() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if shards := len(block.cols["posts_counter_shards"]); shards == 0 || shards > 4 {
+		t.Errorf("Increment() wrote %d shard rows, want between 1 and 4", shards)
+	}
+
+	// The counters are summed up with the value kept in the document when they are read
+	result, _, err := m.Read(context.Background(), "db", "posts", &model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.All, Options: &model.ReadOptions{}}, model.RequestParams{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []interface{}{
+		map[string]interface{}{"id": "1", "title": "a", "likes": int64(105), "views": int64(0)},
+		map[string]interface{}{"id": "2", "title": "b", "likes": int64(0)},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Read() = %v, want %v", result, want)
+	}
+
+	// Counters left out of the select clause aren't summed up
+	result, _, err = m.Read(context.Background(), "db", "posts", &model.ReadRequest{Find: map[string]interface{}{"id": "1"}, Operation: utils.One, Options: &model.ReadOptions{Select: map[string]int32{"id": 1, "title": 1}}}, model.RequestParams{})
+	if want := map[string]interface{}{"id": "1", "title": "a"}; err != nil || !reflect.DeepEqual(result, want) {
+		t.Errorf("Read() = %v error = %v, want %v", result, err, want)
+	}
+
+	// Sharded and direct counters are incremented together and returned summed up
+	docs, err := m.Increment(context.Background(), "db", "posts", &model.IncrementRequest{Find: map[string]interface{}{"id": "1"}, Inc: map[string]interface{}{"likes": 2, "views": 1}, Returning: true}, model.RequestParams{})
+	if want := []interface{}{map[string]interface{}{"id": "1", "title": "a", "likes": int64(107), "views": int64(1)}}; err != nil || !reflect.DeepEqual(docs, want) {
+		t.Errorf("Increment() = %v error = %v, want %v", docs, err, want)
+	}
+
+	// Sharded counters are incremented on a single document
+	if _, err := m.Increment(context.Background(), "db", "posts", &model.IncrementRequest{Find: map[string]interface{}{"title": "a"}, Inc: map[string]interface{}{"likes": 1}}, model.RequestParams{}); err == nil {
+		t.Errorf("Increment() of sharded counters without the key of the document did not fail")
+	}
+	if _, err := m.Increment(context.Background(), "db", "posts", &model.IncrementRequest{Find: map[string]interface{}{"id": map[string]interface{}{"$in": []interface{}{"1", "2"}}}, Inc: map[string]interface{}{"likes": 1}}, model.RequestParams{}); err == nil {
+		t.Errorf("Increment() of sharded counters of many documents did not fail")
+	}
+}
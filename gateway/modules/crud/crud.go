@@ -12,10 +12,12 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/analytics"
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud/bolt"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud/mgo"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud/redis"
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud/sql"
 )
 
@@ -34,13 +36,28 @@ type Module struct {
 	// Variables to store the hooks
 	metricHook model.MetricCrudHook
 
+	// Query latencies and slow queries of every db alias
+	queryStats map[string]*queryStats
+
+	// Budgets enforced on the cost of the reads of the project
+	budget *queryBudget
+
 	// Extra variables for enterprise
 	blocks         map[string]Crud
 	admin          *admin.Manager
 	integrationMan integrationManagerInterface
 	caching        cachingInterface
+	functions      functionsInterface
+	eventing       eventingInterface
+
+	// Remote services called around the writes made to a collection
+	writeHooks map[string]*config.DatabaseHooks
+	// Sharded counters of the collections. Keys are dbAlias::col
+	counters map[string]*config.ShardedCounters
 	// function to get secrets from runner
 	getSecrets utils.GetSecrets
+	// function to resolve secrets from external secret managers
+	resolveSecret utils.ResolveSecret
 
 	// Schema module
 	schemaDoc model.Type
@@ -74,9 +91,31 @@ type Crud interface {
 	SetProjectAESKey(aesKey []byte)
 }
 
+// poolStatsProvider is implemented by the crud blocks which maintain a connection pool
+type poolStatsProvider interface {
+	GetPoolStats() model.DBPoolStats
+}
+
+// returningCrud is implemented by the crud blocks which can return the documents affected by a mutation
+type returningCrud interface {
+	CreateReturning(ctx context.Context, col string, req *model.CreateRequest) (int64, []interface{}, error)
+	UpdateReturning(ctx context.Context, col string, req *model.UpdateRequest) (int64, []interface{}, error)
+	DeleteReturning(ctx context.Context, col string, req *model.DeleteRequest) (int64, []interface{}, error)
+}
+
+// viewReader is implemented by the crud blocks which can read from views defined as prepared queries
+type viewReader interface {
+	ReadView(ctx context.Context, view *model.View, req *model.ReadRequest) (int64, interface{}, *model.SQLMetaData, error)
+}
+
+// streamingCrud is implemented by the crud blocks which can stream the result of a read without buffering it
+type streamingCrud interface {
+	ReadStream(ctx context.Context, col string, req *model.ReadRequest, fn func(doc map[string]interface{}) error) (int64, error)
+}
+
 // Init create a new instance of the Module object
 func Init() *Module {
-	return &Module{batchMapTableToChan: make(batchMap), databaseConfigs: config.DatabaseConfigs{}, blocks: map[string]Crud{}, dataLoader: loader{loaderMap: map[string]*dataloader.Loader{}}}
+	return &Module{batchMapTableToChan: make(batchMap), databaseConfigs: config.DatabaseConfigs{}, blocks: map[string]Crud{}, queryStats: map[string]*queryStats{}, dataLoader: loader{loaderMap: map[string]*dataloader.Loader{}}}
 }
 
 func (m *Module) initBlock(dbType model.DBType, enabled bool, connection, dbName string, driverConf config.DriverConfig) (Crud, error) {
@@ -85,6 +124,10 @@ func (m *Module) initBlock(dbType model.DBType, enabled bool, connection, dbName
 		return mgo.Init(enabled, connection, dbName, driverConf)
 	case model.EmbeddedDB:
 		return bolt.Init(enabled, connection, dbName)
+	case model.Redis:
+		return redis.Init(enabled, connection, dbName, driverConf)
+	case model.BigQuery, model.ClickHouse:
+		return analytics.Init(dbType, enabled, connection, dbName, driverConf)
 	case model.MySQL, model.Postgres, model.SQLServer:
 		c, err := sql.Init(dbType, enabled, connection, dbName, driverConf)
 		if err == nil && enabled {
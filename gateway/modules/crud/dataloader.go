@@ -3,6 +3,7 @@ package crud
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 
 	"github.com/graph-gophers/dataloader"
@@ -11,6 +12,9 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
+// LinkBatchCapacity is the maximum number of linked reads merged into a single query on the database
+const LinkBatchCapacity = 500
+
 type resultsHolder struct {
 	sync.Mutex
 	results []*dataloader.Result
@@ -136,17 +140,70 @@ func (m *Module) createLoader(key string) *dataloader.Loader {
 	defer m.dataLoader.dataLoaderLock.Unlock()
 	// DataLoaderBatchFn is the batch function of the data loader
 	cache := &dataloader.NoCache{}
-	loader := dataloader.NewBatchedLoader(m.dataLoaderBatchFn, dataloader.WithCache(cache))
+	loader := dataloader.NewBatchedLoader(m.dataLoaderBatchFn, dataloader.WithCache(cache), dataloader.WithBatchCapacity(LinkBatchCapacity))
 	m.dataLoader.loaderMap[key] = loader
 	return loader
 }
 
 func (m *Module) dataLoaderBatchFn(c context.Context, keys dataloader.Keys) []*dataloader.Result {
+	// The requests have already been scoped to their tenants and charged to the query budgets of their callers
+	// before being queued
+	ctx := withBudgetCharged(withTenancyScoped(c))
+
+	// The linked reads of different callers are merged separately, so that the merged reads carry the claims of
+	// their callers
+	groups := groupKeysByCaller(keys)
+	if len(groups) == 1 {
+		return m.batchRead(ctx, keys)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*dataloader.Result, len(keys))
+	for _, g := range groups {
+		wg.Add(1)
+		go func(g keyGroup) {
+			defer wg.Done()
+			for i, result := range m.batchRead(ctx, g.keys) {
+				results[g.indices[i]] = result
+			}
+		}(g)
+	}
+	wg.Wait()
+	return results
+}
+
+// keyGroup is a group of the keys queued in the data loader along with their indices
+type keyGroup struct {
+	keys    dataloader.Keys
+	indices []int
+}
+
+// groupKeysByCaller groups the keys by the id claim of their callers preserving their order
+func groupKeysByCaller(keys dataloader.Keys) []keyGroup {
+	groups := make([]keyGroup, 0, 1)
+	index := map[string]int{}
+	for i, key := range keys {
+		caller, _ := key.(model.ReadRequestKey).ReqParams.Claims["id"].(string)
+		g, ok := index[caller]
+		if !ok {
+			g = len(groups)
+			index[caller] = g
+			groups = append(groups, keyGroup{})
+		}
+		groups[g].keys = append(groups[g].keys, key)
+		groups[g].indices = append(groups[g].indices, i)
+	}
+	return groups
+}
+
+// batchRead executes the linked reads of a single caller, merging the ones without options into a single query
+func (m *Module) batchRead(c context.Context, keys dataloader.Keys) []*dataloader.Result {
 	var wg sync.WaitGroup
 	ctx, cancel := context.WithCancel(c)
 	defer cancel()
 
 	var dbAlias, col string
+	var params model.RequestParams
 
 	// Return if there are no keys
 	if len(keys) == 0 {
@@ -163,6 +220,7 @@ func (m *Module) dataLoaderBatchFn(c context.Context, keys dataloader.Keys) []*d
 
 		dbAlias = req.DBAlias
 		col = req.Col
+		params = req.ReqParams
 
 		// Execute query immediately if it has options
 		if req.HasOptions {
@@ -205,9 +263,10 @@ func (m *Module) dataLoaderBatchFn(c context.Context, keys dataloader.Keys) []*d
 	// Fire the query only if where clauses exist
 	if len(clauses) > 0 {
 		// Prepare a merged request
-		req := model.ReadRequest{Find: map[string]interface{}{"$or": clauses}, Operation: utils.All, Options: &model.ReadOptions{}}
+		req := model.ReadRequest{Find: mergeWhereClauses(clauses), Operation: utils.All, Options: &model.ReadOptions{}}
 		// Fire the merged request
-		res, metaData, err := m.Read(ctx, dbAlias, col, &req, model.RequestParams{Resource: "db-read", Op: "access", Attributes: map[string]string{"project": m.project, "db": dbAlias, "col": col}})
+		reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: map[string]string{"project": m.project, "db": dbAlias, "col": col}, Claims: params.Claims}
+		res, metaData, err := m.Read(ctx, dbAlias, col, &req, reqParams)
 		if err != nil {
 			holder.fillErrorMessage(err)
 		} else {
@@ -219,3 +278,78 @@ func (m *Module) dataLoaderBatchFn(c context.Context, keys dataloader.Keys) []*d
 	// append to this list resolved values
 	return holder.getResults()
 }
+
+// mergeWhereClauses merges the where clauses of the batched requests into the where clause of a single query. The
+// clauses of a linked field only differ in the value of the field used to link the tables, in which case they are
+// merged into a single $in lookup. They are combined with an $or otherwise.
+func mergeWhereClauses(clauses []interface{}) map[string]interface{} {
+	first, field, ok := getLookupField(clauses)
+	if !ok {
+		return map[string]interface{}{"$or": clauses}
+	}
+
+	find := make(map[string]interface{}, len(first))
+	for k, v := range first {
+		find[k] = v
+	}
+	if field == "" {
+		// All the clauses are the same
+		return find
+	}
+
+	values := make([]interface{}, 0, len(clauses))
+	seen := make(map[interface{}]struct{}, len(clauses))
+	for _, clause := range clauses {
+		value := clause.(map[string]interface{})[field]
+		if _, p := seen[value]; p {
+			continue
+		}
+		seen[value] = struct{}{}
+		values = append(values, value)
+	}
+	find[field] = map[string]interface{}{"$in": values}
+	return find
+}
+
+// getLookupField returns the only field whose value differs across the where clauses. An empty field is returned
+// if all the clauses are the same. The clauses can't be merged into a lookup if they have different fields, or the
+// value of the differing field isn't a scalar.
+func getLookupField(clauses []interface{}) (map[string]interface{}, string, bool) {
+	if len(clauses) == 0 {
+		return nil, "", false
+	}
+	first, ok := clauses[0].(map[string]interface{})
+	if !ok {
+		return nil, "", false
+	}
+
+	field := ""
+	for _, c := range clauses[1:] {
+		clause, ok := c.(map[string]interface{})
+		if !ok || len(clause) != len(first) {
+			return nil, "", false
+		}
+		for k, v := range clause {
+			firstValue, p := first[k]
+			if !p {
+				return nil, "", false
+			}
+			if reflect.DeepEqual(v, firstValue) {
+				continue
+			}
+			if (field != "" && field != k) || !isScalar(v) || !isScalar(firstValue) {
+				return nil, "", false
+			}
+			field = k
+		}
+	}
+	return first, field, true
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int32, int64, float32, float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package crud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graph-gophers/dataloader"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_mergeWhereClauses(t *testing.T) {
+	tests := []struct {
+		name    string
+		clauses []interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name: "linked field lookups",
+			clauses: []interface{}{
+				map[string]interface{}{"author_id": "1"},
+				map[string]interface{}{"author_id": "2"},
+				map[string]interface{}{"author_id": "1"},
+			},
+			want: map[string]interface{}{"author_id": map[string]interface{}{"$in": []interface{}{"1", "2"}}},
+		},
+		{
+			name: "linked field lookups with a common security rule clause",
+			clauses: []interface{}{
+				map[string]interface{}{"author_id": 1, "published": true},
+				map[string]interface{}{"author_id": 2, "published": true},
+			},
+			want: map[string]interface{}{"author_id": map[string]interface{}{"$in": []interface{}{1, 2}}, "published": true},
+		},
+		{
+			name: "same clauses",
+			clauses: []interface{}{
+				map[string]interface{}{"author_id": 1},
+				map[string]interface{}{"author_id": 1},
+			},
+			want: map[string]interface{}{"author_id": 1},
+		},
+		{
+			name: "clauses differing in multiple fields",
+			clauses: []interface{}{
+				map[string]interface{}{"author_id": 1, "category": "a"},
+				map[string]interface{}{"author_id": 2, "category": "b"},
+			},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"author_id": 1, "category": "a"},
+				map[string]interface{}{"author_id": 2, "category": "b"},
+			}},
+		},
+		{
+			name: "clauses differing in an operator",
+			clauses: []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$gt": 10}},
+				map[string]interface{}{"age": map[string]interface{}{"$gt": 20}},
+			},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"age": map[string]interface{}{"$gt": 10}},
+				map[string]interface{}{"age": map[string]interface{}{"$gt": 20}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeWhereClauses(tt.clauses); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeWhereClauses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_groupKeysByCaller(t *testing.T) {
+	key := func(col string, id interface{}) model.ReadRequestKey {
+		params := model.RequestParams{}
+		if id != nil {
+			params.Claims = map[string]interface{}{"id": id}
+		}
+		return model.ReadRequestKey{Col: col, ReqParams: params}
+	}
+	keys := dataloader.Keys{key("a", "1"), key("b", "2"), key("c", "1"), key("d", nil)}
+
+	groups := groupKeysByCaller(keys)
+	want := [][]int{{0, 2}, {1}, {3}}
+	if len(groups) != len(want) {
+		t.Fatalf("groupKeysByCaller() returned %d groups, want %d", len(groups), len(want))
+	}
+	for i, g := range groups {
+		if !reflect.DeepEqual(g.indices, want[i]) || len(g.keys) != len(want[i]) {
+			t.Errorf("groupKeysByCaller() group (%d) has indices %v, want %v", i, g.indices, want[i])
+		}
+		for j, k := range g.keys {
+			if k.(model.ReadRequestKey).Col != keys[want[i][j]].(model.ReadRequestKey).Col {
+				t.Errorf("groupKeysByCaller() group (%d) has key (%d) out of order", i, j)
+			}
+		}
+	}
+}
@@ -62,6 +62,16 @@ func (m *Module) getCrudBlock(dbAlias string) (Crud, error) {
 	return block, nil
 }
 
+// getReturningBlock returns the crud block of a database if it can return the documents affected by a mutation
+func getReturningBlock(ctx context.Context, dbAlias string, block Crud) (returningCrud, error) {
+	block = unwrapCrud(block)
+	r, ok := block.(returningCrud)
+	if !ok {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Database (%s) of type (%s) cannot return the documents affected by a mutation", dbAlias, block.GetDBType()), nil, nil)
+	}
+	return r, nil
+}
+
 // splitConnectionString splits the connection string
 func splitConnectionString(connection string) (string, bool) {
 	s := strings.Split(connection, ".")
@@ -0,0 +1,483 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// historyWrite holds what is needed to record the versions of the documents affected by a write on a collection
+// marked with the @history directive
+type historyWrite struct {
+	dbType string
+	col    string
+	table  string
+	op     model.OperationType
+	keys   []string
+	find   map[string]interface{}
+	upsert bool
+
+	// docs are the documents read before the write. Only their primary keys are read for an update, since their new
+	// versions are read once it has been made.
+	docs []interface{}
+}
+
+// checkNotHistory returns an error if the collection is the history table of another collection since it is
+// maintained by the gateway and must stay append only
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) checkNotHistory(ctx context.Context, dbAlias, col string) error {
+	if schemaHelpers.IsHistoryTable(m.schemaDoc, dbAlias, col) {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot modify history table (%s) of database (%s) since it is maintained by space cloud", col, dbAlias), nil, nil)
+	}
+	return nil
+}
+
+// prepareHistory reads the documents a write is about to affect if the collection maintains a history. It returns nil
+// if it doesn't. The documents are read outside of the write, hence a concurrent write on them may go unrecorded.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) prepareHistory(ctx context.Context, crud Crud, dbAlias, dbType, col string, op model.OperationType, find map[string]interface{}, operation string) (*historyWrite, error) {
+	table, ok := schemaHelpers.GetHistoryTable(m.schemaDoc, dbAlias, col)
+	if !ok {
+		return nil, nil
+	}
+
+	keys := m.getPrimaryKeys(dbAlias, dbType, col)
+	if len(keys) == 0 {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot maintain the history of (%s) as it doesn't have a primary key", col), nil, nil)
+	}
+
+	h := &historyWrite{dbType: dbType, col: col, table: table, op: op, keys: keys, find: find, upsert: operation == utils.Upsert}
+
+	var options *model.ReadOptions
+	switch op {
+	case model.Update:
+		options = &model.ReadOptions{Select: make(map[string]int32, len(keys))}
+		for _, key := range keys {
+			options.Select[key] = 1
+		}
+	case model.Delete:
+		options = new(model.ReadOptions)
+	default:
+		return h, nil
+	}
+
+	// Only the first matching document is affected by a write on one document
+	if operation == utils.One {
+		limit := int64(1)
+		options.Limit = &limit
+	}
+
+	docs, err := readDocs(ctx, crud, col, find, options)
+	if err != nil {
+		return nil, err
+	}
+	h.docs = docs
+	return h, nil
+}
+
+// recordHistory appends the versions of the documents affected by a write to the history table of the collection.
+// The write has already been made by then, hence a failure is only logged.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) recordHistory(ctx context.Context, crud Crud, dbAlias string, h *historyWrite, created interface{}) {
+	if h == nil {
+		return
+	}
+
+	var docs []interface{}
+	var err error
+	switch h.op {
+	case model.Create:
+		switch v := created.(type) {
+		case []interface{}:
+			docs = v
+		case map[string]interface{}:
+			docs = []interface{}{v}
+		}
+	case model.Update:
+		switch {
+		case len(h.docs) > 0:
+			docs, err = readDocs(ctx, crud, h.col, getKeysFind(h.keys, h.keys, h.docs), nil)
+		case h.upsert:
+			// The document got inserted by the upsert
+			docs, err = readDocs(ctx, crud, h.col, h.find, nil)
+		}
+	case model.Delete:
+		docs = h.docs
+	}
+
+	if err == nil {
+		err = m.writeHistory(ctx, crud, dbAlias, h, docs)
+	}
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to record the history of (%s)", h.col), err, map[string]interface{}{"op": h.op})
+	}
+}
+
+// writeHistory writes a version of each of the documents to the history table
+func (m *Module) writeHistory(ctx context.Context, crud Crud, dbAlias string, h *historyWrite, docs []interface{}) error {
+	fields := m.schemaDoc[dbAlias][h.table]
+	at := time.Now().UTC()
+
+	rows := make([]interface{}, 0, len(docs))
+	for _, d := range docs {
+		doc, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !hasKeys(h.keys, doc) {
+			helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Skipping the history of a document of (%s) as its primary key isn't known - make the write return the documents to record them", h.col), nil)
+			continue
+		}
+		rows = append(rows, toHistoryRow(h.dbType, fields, doc, h.op, at))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	req := &model.CreateRequest{Document: rows, Operation: utils.All}
+	if h.dbType != string(model.Mongo) {
+		if err := schemaHelpers.ValidateCreateOperation(ctx, dbAlias, h.dbType, h.table, m.schemaDoc, req); err != nil {
+			return err
+		}
+	}
+	_, err := crud.Create(ctx, h.table, req)
+	return err
+}
+
+// readAt reads the documents of a collection as they were at the time provided in the options of the request. The
+// latest version of every matching document till then is read from the history table of the collection.
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) readAt(ctx context.Context, crud Crud, dbAlias, dbType, col string, req *model.ReadRequest) (interface{}, error) {
+	table, ok := schemaHelpers.GetHistoryTable(m.schemaDoc, dbAlias, col)
+	if !ok {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot read (%s) at a point in time as it isn't marked with the @history directive", col), nil, nil)
+	}
+	at, err := time.Parse(time.RFC3339Nano, req.Options.ReadAt)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid value (%s) provided for readAt - use the RFC3339 format", req.Options.ReadAt), err, nil)
+	}
+	if len(req.Aggregate) > 0 || len(req.GroupBy) > 0 || len(req.Options.Join) > 0 || req.Options.Distinct != nil || req.Operation == utils.Distinct {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Aggregations, joins and distinct aren't supported while reading (%s) at a point in time", col), nil, nil)
+	}
+
+	keys := m.getPrimaryKeys(dbAlias, dbType, col)
+	historyKeys := make([]string, len(keys))
+	for i, key := range keys {
+		historyKeys[i] = getHistoryField(dbType, key)
+	}
+	till := map[string]interface{}{"$lte": at.UTC()}
+
+	// Find the documents which matched the query in any of their versions till then
+	find := map[string]interface{}{model.HistoryFieldAt: till}
+	for k, v := range req.Find {
+		find[getHistoryField(dbType, k)] = v
+	}
+	options := &model.ReadOptions{Select: make(map[string]int32, len(historyKeys))}
+	for _, key := range historyKeys {
+		options.Select[key] = 1
+	}
+	matched, err := readDocs(ctx, crud, table, find, options)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]interface{}, 0)
+	if len(matched) > 0 {
+		// Read all the versions of those documents in the order they were written and keep the latest one
+		find = getKeysFind(historyKeys, historyKeys, matched)
+		find[model.HistoryFieldAt] = till
+		versions, err := readDocs(ctx, crud, table, find, &model.ReadOptions{Sort: []string{model.HistoryFieldAt}})
+		if err != nil {
+			return nil, err
+		}
+
+		order := make([]string, 0)
+		latest := make(map[string]map[string]interface{})
+		for _, v := range versions {
+			version, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := getDocKey(historyKeys, version)
+			if _, ok := latest[key]; !ok {
+				order = append(order, key)
+			}
+			latest[key] = version
+		}
+
+		for _, key := range order {
+			version := latest[key]
+			if version[model.HistoryFieldOp] == string(model.Delete) {
+				continue
+			}
+			doc := fromHistoryRow(dbType, version)
+			if utils.Validate(dbType, req.Find, doc) {
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	sortDocs(docs, req.Options.Sort)
+	docs = paginateDocs(docs, req.Options.Skip, req.Options.Limit)
+	selectFields(docs, req.Options.Select)
+	m.metricHook(m.project, dbAlias, col, int64(len(docs)), model.Read)
+
+	switch req.Operation {
+	case utils.Count:
+		return int64(len(docs)), nil
+	case utils.One:
+		if len(docs) == 0 {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No document of (%s) matched the query at (%s)", col, req.Options.ReadAt), nil, nil)
+		}
+		return docs[0], nil
+	default:
+		return docs, nil
+	}
+}
+
+// getPrimaryKeys returns the primary keys of a collection in a stable order
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) getPrimaryKeys(dbAlias, dbType, col string) []string {
+	keys := make([]string, 0)
+	for name, field := range m.schemaDoc[dbAlias][col] {
+		if field.IsPrimary {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 && dbType == string(model.Mongo) {
+		keys = append(keys, "_id")
+	}
+	return keys
+}
+
+func readDocs(ctx context.Context, crud Crud, col string, find map[string]interface{}, options *model.ReadOptions) ([]interface{}, error) {
+	_, result, _, _, err := crud.Read(ctx, col, &model.ReadRequest{Find: find, Operation: utils.All, Options: options})
+	if err != nil {
+		return nil, err
+	}
+	docs, ok := result.([]interface{})
+	if !ok {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read documents of (%s) - database returned an unexpected result of type (%T)", col, result), nil, nil)
+	}
+	return docs, nil
+}
+
+// getKeysFind returns a where clause matching the documents with the same primary keys as the provided ones. The
+// keys are read from the fields of the documents and matched on the fields of the where clause.
+func getKeysFind(fields, keys []string, docs []interface{}) map[string]interface{} {
+	if len(keys) == 1 {
+		values := make([]interface{}, 0, len(docs))
+		for _, d := range docs {
+			if doc, ok := d.(map[string]interface{}); ok {
+				values = append(values, doc[fields[0]])
+			}
+		}
+		return map[string]interface{}{keys[0]: map[string]interface{}{"$in": values}}
+	}
+
+	or := make([]interface{}, 0, len(docs))
+	for _, d := range docs {
+		doc, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		where := make(map[string]interface{}, len(keys))
+		for i, key := range keys {
+			where[key] = doc[fields[i]]
+		}
+		or = append(or, where)
+	}
+	return map[string]interface{}{"$or": or}
+}
+
+func getDocKey(keys []string, doc map[string]interface{}) string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = fmt.Sprintf("%v", doc[key])
+	}
+	return strings.Join(values, "::")
+}
+
+func hasKeys(keys []string, doc map[string]interface{}) bool {
+	for _, key := range keys {
+		if v, ok := doc[key]; !ok || v == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// getHistoryField returns the field of the history table holding a field of the collection. The _id of a mongo
+// document is moved to another field since _id identifies a version in the history table.
+func getHistoryField(dbType, field string) string {
+	if dbType == string(model.Mongo) && field == "_id" {
+		return model.HistoryFieldDocID
+	}
+	return field
+}
+
+// toHistoryRow returns a version of the document to be written to the history table. Fields which aren't in the schema
+// of the history table are dropped for sql databases.
+func toHistoryRow(dbType string, fields model.Fields, doc map[string]interface{}, op model.OperationType, at time.Time) map[string]interface{} {
+	row := make(map[string]interface{}, len(doc)+3)
+	for k, v := range doc {
+		if _, ok := fields[k]; !ok && dbType != string(model.Mongo) {
+			continue
+		}
+		row[getHistoryField(dbType, k)] = v
+	}
+	row[model.HistoryFieldID] = ksuid.New().String()
+	row[model.HistoryFieldOp] = string(op)
+	row[model.HistoryFieldAt] = at
+	return row
+}
+
+// fromHistoryRow returns the document held by a version read from the history table
+func fromHistoryRow(dbType string, row map[string]interface{}) map[string]interface{} {
+	doc := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		switch k {
+		case model.HistoryFieldID, model.HistoryFieldOp, model.HistoryFieldAt:
+			continue
+		case "_id":
+			if dbType == string(model.Mongo) {
+				continue
+			}
+		case model.HistoryFieldDocID:
+			if dbType == string(model.Mongo) {
+				k = "_id"
+			}
+		}
+		doc[k] = v
+	}
+	return doc
+}
+
+// sortDocs sorts the documents in place. A field prefixed with '-' is sorted in the descending order.
+func sortDocs(docs []interface{}, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		a, b := docs[i].(map[string]interface{}), docs[j].(map[string]interface{})
+		for _, field := range fields {
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimPrefix(field, "-")
+			c := compareValues(a[field], b[field])
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != desc
+		}
+		return false
+	})
+}
+
+// compareValues returns -1, 0 or 1 if a is less than, equal to or greater than b. Missing values are the smallest.
+func compareValues(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	switch x := a.(type) {
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	}
+
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float32:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+	return 0, false
+}
+
+func paginateDocs(docs []interface{}, skip, limit *int64) []interface{} {
+	if skip != nil && *skip > 0 {
+		if *skip >= int64(len(docs)) {
+			return docs[:0]
+		}
+		docs = docs[*skip:]
+	}
+	if limit != nil && *limit >= 0 && *limit < int64(len(docs)) {
+		docs = docs[:*limit]
+	}
+	return docs
+}
+
+// selectFields keeps only the selected fields of the documents
+func selectFields(docs []interface{}, fields map[string]int32) {
+	if len(fields) == 0 {
+		return
+	}
+	for _, d := range docs {
+		doc := d.(map[string]interface{})
+		for k := range doc {
+			if fields[k] != 1 {
+				delete(doc, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package crud
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_historyRow(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := model.Fields{"id": {FieldName: "id"}, "name": {FieldName: "name"}}
+
+	tests := []struct {
+		name    string
+		dbType  string
+		doc     map[string]interface{}
+		wantRow map[string]interface{}
+	}{
+		{
+			name:    "sql database drops the fields which aren't in the schema",
+			dbType:  string(model.Postgres),
+			doc:     map[string]interface{}{"id": "1", "name": "john", "unknown": true},
+			wantRow: map[string]interface{}{"id": "1", "name": "john", model.HistoryFieldOp: "update", model.HistoryFieldAt: at},
+		},
+		{
+			name:    "mongo moves the _id of the document",
+			dbType:  string(model.Mongo),
+			doc:     map[string]interface{}{"_id": "1", "name": "john"},
+			wantRow: map[string]interface{}{model.HistoryFieldDocID: "1", "name": "john", model.HistoryFieldOp: "update", model.HistoryFieldAt: at},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := toHistoryRow(tt.dbType, fields, tt.doc, model.Update, at)
+			if row[model.HistoryFieldID] == "" {
+				t.Errorf("toHistoryRow() didn't generate the %s", model.HistoryFieldID)
+			}
+			got := make(map[string]interface{}, len(row))
+			for k, v := range row {
+				if k != model.HistoryFieldID {
+					got[k] = v
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantRow) {
+				t.Errorf("toHistoryRow() = %v, want %v", got, tt.wantRow)
+			}
+
+			// The document should be restored from its version
+			wantDoc := make(map[string]interface{})
+			for k, v := range tt.doc {
+				if k != "unknown" {
+					wantDoc[k] = v
+				}
+			}
+			if got := fromHistoryRow(tt.dbType, row); !reflect.DeepEqual(got, wantDoc) {
+				t.Errorf("fromHistoryRow() = %v, want %v", got, wantDoc)
+			}
+		})
+	}
+}
+
+func Test_getKeysFind(t *testing.T) {
+	docs := []interface{}{map[string]interface{}{"a": 1, "b": "x"}, map[string]interface{}{"a": 2, "b": "y"}}
+	tests := []struct {
+		name   string
+		fields []string
+		keys   []string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "single key",
+			fields: []string{"a"},
+			keys:   []string{"a"},
+			want:   map[string]interface{}{"a": map[string]interface{}{"$in": []interface{}{1, 2}}},
+		},
+		{
+			name:   "single key matched on another field",
+			fields: []string{"a"},
+			keys:   []string{"c"},
+			want:   map[string]interface{}{"c": map[string]interface{}{"$in": []interface{}{1, 2}}},
+		},
+		{
+			name:   "composite key",
+			fields: []string{"a", "b"},
+			keys:   []string{"a", "b"},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"a": 1, "b": "x"},
+				map[string]interface{}{"a": 2, "b": "y"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getKeysFind(tt.fields, tt.keys, docs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getKeysFind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sortDocs(t *testing.T) {
+	newDocs := func() []interface{} {
+		return []interface{}{
+			map[string]interface{}{"id": "1", "age": int64(30), "city": "pune"},
+			map[string]interface{}{"id": "2", "age": 25.0, "city": "delhi"},
+			map[string]interface{}{"id": "3", "age": int64(30), "city": "agra"},
+			map[string]interface{}{"id": "4", "city": "pune"},
+		}
+	}
+	one, two := int64(1), int64(2)
+
+	tests := []struct {
+		name        string
+		sort        []string
+		skip, limit *int64
+		want        []string
+	}{
+		{name: "no sort", want: []string{"1", "2", "3", "4"}},
+		{name: "ascending with missing values first", sort: []string{"age"}, want: []string{"4", "2", "1", "3"}},
+		{name: "descending and then ascending", sort: []string{"-age", "city"}, want: []string{"3", "1", "2", "4"}},
+		{name: "skip and limit", sort: []string{"city"}, skip: &one, limit: &two, want: []string{"2", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := newDocs()
+			sortDocs(docs, tt.sort)
+			docs = paginateDocs(docs, tt.skip, tt.limit)
+
+			got := make([]string, len(docs))
+			for i, doc := range docs {
+				got[i] = doc.(map[string]interface{})["id"].(string)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortDocs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// defaultWriteHookTimeout is the timeout in seconds of write hooks which don't specify one
+const defaultWriteHookTimeout = 10
+
+// functionsInterface is used to call the remote services configured as write hooks
+type functionsInterface interface {
+	CallWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest) (int, interface{}, error)
+}
+
+// SetFunctionsModule sets the functions module used to invoke the write hooks
+func (m *Module) SetFunctionsModule(f functionsInterface) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.functions = f
+}
+
+// SetWriteHooks sets the write hooks of the collections from their database rules
+func (m *Module) SetWriteHooks(rules config.DatabaseRules) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.writeHooks = map[string]*config.DatabaseHooks{}
+	for _, rule := range rules {
+		if rule.Hooks != nil && (rule.Hooks.BeforeWrite != nil || rule.Hooks.AfterWrite != nil) {
+			m.writeHooks[getWriteHookKey(rule.DbAlias, rule.Table)] = rule.Hooks
+		}
+	}
+}
+
+func getWriteHookKey(dbAlias, col string) string {
+	return fmt.Sprintf("%s::%s", dbAlias, col)
+}
+
+// invokeBeforeWriteHook calls the before write hook of the collection if it has one. The fields returned by the hook
+// replace the ones of the write. The write is rejected if the hook fails or returns an error.
+func (m *Module) invokeBeforeWriteHook(ctx context.Context, dbAlias, col string, params model.RequestParams, req *model.WriteHookRequest) (*model.WriteHookResponse, error) {
+	hooks, ok := m.writeHooks[getWriteHookKey(dbAlias, col)]
+	if !ok || hooks.BeforeWrite == nil {
+		return new(model.WriteHookResponse), nil
+	}
+
+	req.Stage = "before"
+	status, result, err := m.callWriteHook(ctx, hooks.BeforeWrite, params, req)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to invoke the before write hook of collection (%s)", col), err, nil)
+	}
+
+	res := new(model.WriteHookResponse)
+	if obj, ok := result.(map[string]interface{}); ok {
+		if err := mapstructure.Decode(obj, res); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid response received from the before write hook of collection (%s)", col), err, nil)
+		}
+	}
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Write rejected by the before write hook of collection (%s) with status (%d)", col, status), nil, nil)
+	}
+	return res, nil
+}
+
+// invokeAfterWriteHook calls the after write hook of the collection if it has one. The write has already been
+// performed at this point. Hence errors are only logged.
+func (m *Module) invokeAfterWriteHook(ctx context.Context, dbAlias, col string, params model.RequestParams, req *model.WriteHookRequest) {
+	hooks, ok := m.writeHooks[getWriteHookKey(dbAlias, col)]
+	if !ok || hooks.AfterWrite == nil {
+		return
+	}
+
+	req.Stage = "after"
+	status, _, err := m.callWriteHook(ctx, hooks.AfterWrite, params, req)
+	if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("After write hook of collection (%s) failed with status (%d)", col, status), err, nil)
+	}
+}
+
+func (m *Module) callWriteHook(ctx context.Context, hook *config.DatabaseHook, params model.RequestParams, req *model.WriteHookRequest) (int, interface{}, error) {
+	if m.functions == nil {
+		return 0, nil, errors.New("functions module has not been initialised")
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultWriteHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	return m.functions.CallWithContext(ctx, hook.Service, hook.Endpoint, "", params, &model.FunctionsRequest{Params: req, Timeout: timeout})
+}
+
+func (m *Module) invokeBatchBeforeWriteHooks(ctx context.Context, dbAlias string, req *model.BatchRequest, params model.RequestParams) error {
+	for _, r := range req.Requests {
+		hookReq := &model.WriteHookRequest{DBAlias: dbAlias, Col: r.Col, Op: model.OperationType(r.Type), Find: r.Find, Update: r.Update}
+		if r.Type == string(model.Create) {
+			hookReq.Doc = r.Document
+		}
+		res, err := m.invokeBeforeWriteHook(ctx, dbAlias, r.Col, params, hookReq)
+		if err != nil {
+			return err
+		}
+		if res.Doc != nil {
+			r.Document = res.Doc
+		}
+		if res.Find != nil {
+			r.Find = res.Find
+		}
+		if res.Update != nil {
+			r.Update = res.Update
+		}
+	}
+	return nil
+}
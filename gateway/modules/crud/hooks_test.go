@@ -0,0 +1,67 @@
+package crud
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type fakeFunctions struct {
+	status int
+	result interface{}
+	calls  int
+}
+
+func (f *fakeFunctions) CallWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest) (int, interface{}, error) {
+	f.calls++
+	return f.status, f.result, nil
+}
+
+func TestModule_invokeBeforeWriteHook(t *testing.T) {
+	rules := config.DatabaseRules{
+		"db--users--rule": &config.DatabaseRule{DbAlias: "db", Table: "users", Hooks: &config.DatabaseHooks{BeforeWrite: &config.DatabaseHook{Service: "validator", Endpoint: "users"}}},
+	}
+
+	tests := []struct {
+		name      string
+		col       string
+		functions *fakeFunctions
+		want      *model.WriteHookResponse
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "collection without hook", col: "posts", functions: &fakeFunctions{status: http.StatusOK}, want: &model.WriteHookResponse{}},
+		{
+			name:      "hook mutating the document",
+			col:       "users",
+			functions: &fakeFunctions{status: http.StatusOK, result: map[string]interface{}{"doc": map[string]interface{}{"name": "JOHN"}}},
+			want:      &model.WriteHookResponse{Doc: map[string]interface{}{"name": "JOHN"}},
+			wantCalls: 1,
+		},
+		{name: "hook rejecting with an error", col: "users", functions: &fakeFunctions{status: http.StatusOK, result: map[string]interface{}{"error": "name is reserved"}}, wantCalls: 1, wantErr: true},
+		{name: "hook rejecting with a status", col: "users", functions: &fakeFunctions{status: http.StatusForbidden}, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Module{}
+			m.SetFunctionsModule(tt.functions)
+			m.SetWriteHooks(rules)
+
+			req := &model.WriteHookRequest{DBAlias: "db", Col: tt.col, Op: model.Create, Doc: map[string]interface{}{"name": "john"}}
+			got, err := m.invokeBeforeWriteHook(context.Background(), "db", tt.col, model.RequestParams{}, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("invokeBeforeWriteHook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invokeBeforeWriteHook() = %+v, want %+v", got, tt.want)
+			}
+			if tt.functions.calls != tt.wantCalls {
+				t.Errorf("invokeBeforeWriteHook() calls = %d, want %d", tt.functions.calls, tt.wantCalls)
+			}
+		})
+	}
+}
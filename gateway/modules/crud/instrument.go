@@ -0,0 +1,125 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+)
+
+// instrumentedCrud wraps a database driver to record a span and the latency of every query fired against the database
+type instrumentedCrud struct {
+	Crud
+	dbName string
+	stats  *queryStats
+}
+
+func newInstrumentedCrud(dbName string, stats *queryStats, block Crud) Crud {
+	return &instrumentedCrud{Crud: block, dbName: dbName, stats: stats}
+}
+
+// unwrapCrud returns the database driver wrapped by the crud block. The optional interfaces of the drivers are
+// checked on the driver itself since the wrapper doesn't implement them.
+func unwrapCrud(block Crud) Crud {
+	if t, ok := block.(*instrumentedCrud); ok {
+		return t.Crud
+	}
+	return block
+}
+
+func (t *instrumentedCrud) startSpan(ctx context.Context, op, col string) (context.Context, trace.Span) {
+	attrs := []label.KeyValue{
+		label.String("db.system", string(t.GetDBType())),
+		label.String("db.name", t.dbName),
+		label.String("db.operation", op),
+	}
+	if col != "" {
+		attrs = append(attrs, label.String("db.sql.table", col))
+	}
+	return tracing.StartSpan(ctx, "db."+op, trace.SpanKindClient, attrs...)
+}
+
+// Create records a span and the latency of the create operation
+func (t *instrumentedCrud) Create(ctx context.Context, col string, req *model.CreateRequest) (int64, error) {
+	ctx, span := t.startSpan(ctx, "create", col)
+	start := time.Now()
+	n, err := t.Crud.Create(ctx, col, req)
+	t.stats.record(ctx, col, "create", nil, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return n, err
+}
+
+// Read records a span and the latency of the read operation
+func (t *instrumentedCrud) Read(ctx context.Context, col string, req *model.ReadRequest) (int64, interface{}, map[string]map[string]string, *model.SQLMetaData, error) {
+	ctx, span := t.startSpan(ctx, "read", col)
+	start := time.Now()
+	n, result, joinMap, metaData, err := t.Crud.Read(ctx, col, req)
+	t.stats.record(ctx, col, "read", req.Find, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return n, result, joinMap, metaData, err
+}
+
+// Update records a span and the latency of the update operation
+func (t *instrumentedCrud) Update(ctx context.Context, col string, req *model.UpdateRequest) (int64, error) {
+	ctx, span := t.startSpan(ctx, "update", col)
+	start := time.Now()
+	n, err := t.Crud.Update(ctx, col, req)
+	t.stats.record(ctx, col, "update", req.Find, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return n, err
+}
+
+// Delete records a span and the latency of the delete operation
+func (t *instrumentedCrud) Delete(ctx context.Context, col string, req *model.DeleteRequest) (int64, error) {
+	ctx, span := t.startSpan(ctx, "delete", col)
+	start := time.Now()
+	n, err := t.Crud.Delete(ctx, col, req)
+	t.stats.record(ctx, col, "delete", req.Find, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return n, err
+}
+
+// Aggregate records a span and the latency of the aggregate operation
+func (t *instrumentedCrud) Aggregate(ctx context.Context, col string, req *model.AggregateRequest) (interface{}, error) {
+	ctx, span := t.startSpan(ctx, "aggregate", col)
+	start := time.Now()
+	result, err := t.Crud.Aggregate(ctx, col, req)
+	t.stats.record(ctx, col, "aggregate", req.Pipeline, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return result, err
+}
+
+// Batch records a span and the latency of the batch operation
+func (t *instrumentedCrud) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, error) {
+	ctx, span := t.startSpan(ctx, "batch", "")
+	start := time.Now()
+	counts, err := t.Crud.Batch(ctx, req)
+	t.stats.record(ctx, "", "batch", nil, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return counts, err
+}
+
+// RawQuery records a span and the latency of the raw query
+func (t *instrumentedCrud) RawQuery(ctx context.Context, query string, isDebug bool, args []interface{}) (int64, interface{}, *model.SQLMetaData, error) {
+	ctx, span := t.startSpan(ctx, "raw_query", "")
+	span.SetAttributes(label.String("db.statement", query))
+	start := time.Now()
+	n, result, metaData, err := t.Crud.RawQuery(ctx, query, isDebug, args)
+	t.stats.record(ctx, "", "raw_query", query, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return n, result, metaData, err
+}
+
+// RawBatch records a span and the latency of the raw batch
+func (t *instrumentedCrud) RawBatch(ctx context.Context, batchedQueries []string) error {
+	ctx, span := t.startSpan(ctx, "raw_batch", "")
+	start := time.Now()
+	err := t.Crud.RawBatch(ctx, batchedQueries)
+	t.stats.record(ctx, "", "raw_batch", nil, time.Since(start), err)
+	tracing.EndSpan(ctx, span, err)
+	return err
+}
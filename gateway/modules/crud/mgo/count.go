@@ -0,0 +1,21 @@
+package mgo
+
+import (
+	"context"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// EstimateCount returns the number of documents in the collection from its metadata. It is false for reads with a
+// where clause since mongo can only estimate the size of the whole collection.
+func (m *Mongo) EstimateCount(ctx context.Context, col string, req *model.ReadRequest) (int64, bool, error) {
+	if len(req.Find) > 0 {
+		return 0, false, nil
+	}
+
+	count, err := m.getClient().Database(m.dbName).Collection(col).EstimatedDocumentCount(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
+}
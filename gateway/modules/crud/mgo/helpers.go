@@ -2,9 +2,18 @@ package mgo
 
 import (
 	"context"
+	"fmt"
 	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/geo"
 )
 
+// earthRadius is the radius of the earth in meters mongo uses to convert distances to radians
+const earthRadius = 6378100
+
 func sanitizeWhereClause(ctx context.Context, col string, find map[string]interface{}) map[string]interface{} {
 	for key, value := range find {
 		arr := strings.Split(key, ".")
@@ -27,8 +36,82 @@ func sanitizeWhereClause(ctx context.Context, col string, find map[string]interf
 			obj, ok := value.(map[string]interface{})
 			if ok {
 				sanitizeWhereClause(ctx, col, obj)
+				translateGeoOperators(ctx, obj)
 			}
 		}
 	}
 	return find
 }
+
+// translateGeoOperators replaces the geospatial operators of a field with their mongo counterparts. $near gets
+// translated to a $geoWithin with a $centerSphere which, unlike mongo's own $near, neither needs a 2dsphere index nor
+// fails while counting documents. Operators which cannot be translated are left as is for mongo to reject.
+func translateGeoOperators(ctx context.Context, cond map[string]interface{}) {
+	translated := map[string]interface{}{}
+	for op, value := range cond {
+		var err error
+		switch op {
+		case geo.OpNear:
+			var near *geo.Near
+			if near, err = geo.ParseNear(value); err != nil {
+				break
+			}
+			center := []interface{}{near.Lng, near.Lat}
+			if near.MaxDistance > 0 {
+				err = setGeoOperator(translated, "$geoWithin", map[string]interface{}{"$centerSphere": []interface{}{center, near.MaxDistance / earthRadius}})
+			}
+			if err == nil && near.MinDistance > 0 {
+				err = setGeoOperator(translated, "$not", map[string]interface{}{"$geoWithin": map[string]interface{}{"$centerSphere": []interface{}{center, near.MinDistance / earthRadius}}})
+			}
+
+		case geo.OpGeoWithin:
+			var polygon map[string]interface{}
+			if polygon, err = geo.ParseWithin(value); err != nil {
+				break
+			}
+			err = setGeoOperator(translated, "$geoWithin", map[string]interface{}{"$geometry": polygon})
+
+		case geo.OpGeoBox:
+			var box *geo.Box
+			if box, err = geo.ParseBox(value); err != nil {
+				break
+			}
+			err = setGeoOperator(translated, "$geoWithin", map[string]interface{}{"$geometry": box.Polygon()})
+
+		default:
+			continue
+		}
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to translate geospatial operator (%s)", op), err, nil)
+			return
+		}
+	}
+
+	for op := range translated {
+		if _, ok := cond[op]; ok && !geo.IsOperator(op) {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Geospatial operators cannot be combined with operator (%s) on the same field", op), nil, nil)
+			return
+		}
+	}
+	for op := range cond {
+		if geo.IsOperator(op) {
+			delete(cond, op)
+		}
+	}
+	for op, value := range translated {
+		cond[op] = value
+	}
+}
+
+func setGeoOperator(cond map[string]interface{}, op string, value interface{}) error {
+	if _, ok := cond[op]; ok {
+		return fmt.Errorf("geospatial operators which translate to (%s) cannot be combined on the same field", op)
+	}
+	cond[op] = value
+	return nil
+}
+
+// getQueryComment returns the $comment which tags the queries of a request with its id and trace context
+func (m *Mongo) getQueryComment(ctx context.Context) string {
+	return utils.GetQueryTags(ctx, m.driverConf.QueryComments)
+}
@@ -76,6 +76,36 @@ func Test_sanitizeWhereClause(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Where clause with geospatial operators",
+			args: args{
+				ctx: context.Background(),
+				col: "shops",
+				find: map[string]interface{}{
+					"shops.location": map[string]interface{}{"$near": map[string]interface{}{"lat": 10, "lng": 20, "maxDistance": 6378100, "minDistance": 637810}},
+					"$or": []interface{}{
+						map[string]interface{}{"area": map[string]interface{}{"$geoBox": map[string]interface{}{"minLng": 0, "minLat": 0, "maxLng": 1, "maxLat": 1}}},
+						map[string]interface{}{"area": map[string]interface{}{"$geoWithin": map[string]interface{}{"polygon": []interface{}{[]interface{}{0, 0}, []interface{}{1, 0}, []interface{}{1, 1}}}}},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"location": map[string]interface{}{
+					"$geoWithin": map[string]interface{}{"$centerSphere": []interface{}{[]interface{}{float64(20), float64(10)}, float64(1)}},
+					"$not":       map[string]interface{}{"$geoWithin": map[string]interface{}{"$centerSphere": []interface{}{[]interface{}{float64(20), float64(10)}, 0.1}}},
+				},
+				"$or": []interface{}{
+					map[string]interface{}{"area": map[string]interface{}{"$geoWithin": map[string]interface{}{"$geometry": map[string]interface{}{
+						"type":        "Polygon",
+						"coordinates": []interface{}{[]interface{}{[]interface{}{float64(0), float64(0)}, []interface{}{float64(1), float64(0)}, []interface{}{float64(1), float64(1)}, []interface{}{float64(0), float64(1)}, []interface{}{float64(0), float64(0)}}},
+					}}}},
+					map[string]interface{}{"area": map[string]interface{}{"$geoWithin": map[string]interface{}{"$geometry": map[string]interface{}{
+						"type":        "Polygon",
+						"coordinates": []interface{}{[]interface{}{[]interface{}{float64(0), float64(0)}, []interface{}{float64(1), float64(0)}, []interface{}{float64(1), float64(1)}, []interface{}{float64(0), float64(0)}}},
+					}}}},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -78,7 +78,7 @@ func (m *Mongo) Close() error {
 
 // IsSame checks if we've got the same connection string
 func (m *Mongo) IsSame(conn, dbName string, driverConf config.DriverConfig) bool {
-	return strings.HasPrefix(m.connection, conn) && dbName == m.dbName && driverConf.MaxConn == m.driverConf.MaxConn && driverConf.MaxIdleTimeout == m.driverConf.MaxIdleTimeout && driverConf.MinConn == m.driverConf.MinConn
+	return strings.HasPrefix(m.connection, conn) && dbName == m.dbName && driverConf.MaxConn == m.driverConf.MaxConn && driverConf.MaxIdleTimeout == m.driverConf.MaxIdleTimeout && driverConf.MinConn == m.driverConf.MinConn && driverConf.QueryComments == m.driverConf.QueryComments
 }
 
 // IsClientSafe checks whether database is enabled and connected
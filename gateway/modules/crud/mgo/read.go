@@ -68,6 +68,9 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 
 	case utils.All:
 		findOptions := options.Find()
+		if comment := m.getQueryComment(ctx); comment != "" {
+			findOptions = findOptions.SetComment(comment)
+		}
 
 		if req.Options != nil {
 			if req.Options.Select != nil {
@@ -132,7 +135,11 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 
 		if len(req.Aggregate) > 0 {
 			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo aggregate", map[string]interface{}{"col": col, "pipeline": pipeline})
-			cur, err = collection.Aggregate(ctx, pipeline)
+			aggregateOptions := options.Aggregate()
+			if comment := m.getQueryComment(ctx); comment != "" {
+				aggregateOptions = aggregateOptions.SetComment(comment)
+			}
+			cur, err = collection.Aggregate(ctx, pipeline, aggregateOptions)
 		} else {
 			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo query", map[string]interface{}{"col": col, "find": req.Find, "options": findOptions})
 			cur, err = collection.Find(ctx, req.Find, findOptions)
@@ -177,6 +184,9 @@ func (m *Mongo) Read(ctx context.Context, col string, req *model.ReadRequest) (i
 
 	case utils.One:
 		findOneOptions := options.FindOne()
+		if comment := m.getQueryComment(ctx); comment != "" {
+			findOneOptions = findOneOptions.SetComment(comment)
+		}
 
 		if req.Options != nil {
 			if req.Options.Select != nil {
@@ -0,0 +1,170 @@
+package mgo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// CreateReturning inserts the documents and returns them along with the ids generated for them
+func (m *Mongo) CreateReturning(ctx context.Context, col string, req *model.CreateRequest) (int64, []interface{}, error) {
+	collection := m.getClient().Database(m.dbName).Collection(col)
+
+	objs := []interface{}{req.Document}
+	if req.Operation != utils.One {
+		var ok bool
+		if objs, ok = req.Document.([]interface{}); !ok {
+			return 0, nil, utils.ErrInvalidParams
+		}
+	}
+
+	docs := make([]interface{}, len(objs))
+	for i, obj := range objs {
+		doc, ok := obj.(map[string]interface{})
+		if !ok {
+			return 0, nil, utils.ErrInvalidParams
+		}
+		// Copy the document so that the id can be added to it without altering the request
+		temp := make(map[string]interface{}, len(doc)+1)
+		for k, v := range doc {
+			temp[k] = v
+		}
+		docs[i] = temp
+	}
+
+	res, err := collection.InsertMany(ctx, objs)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i, id := range res.InsertedIDs {
+		docs[i].(map[string]interface{})["_id"] = id
+	}
+
+	return int64(len(docs)), docs, nil
+}
+
+// UpdateReturning updates the documents and returns them after the update. Operations on a single document are atomic
+// since they use findAndModify, while the documents updated by the "all" operation are read back by their ids.
+func (m *Mongo) UpdateReturning(ctx context.Context, col string, req *model.UpdateRequest) (int64, []interface{}, error) {
+	collection := m.getClient().Database(m.dbName).Collection(col)
+	req.Find = sanitizeWhereClause(ctx, col, req.Find)
+
+	switch req.Operation {
+	case utils.One, utils.Upsert:
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetUpsert(req.Operation == utils.Upsert)
+
+		var doc map[string]interface{}
+		if err := collection.FindOneAndUpdate(ctx, req.Find, req.Update, opts).Decode(&doc); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return 0, []interface{}{}, nil
+			}
+			return 0, nil, err
+		}
+		return 1, []interface{}{doc}, nil
+
+	case utils.All:
+		ids, err := m.findIDs(ctx, collection, req.Find)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(ids) == 0 {
+			return 0, []interface{}{}, nil
+		}
+
+		find := map[string]interface{}{"_id": map[string]interface{}{"$in": ids}}
+		res, err := collection.UpdateMany(ctx, find, req.Update)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		docs, err := m.findDocuments(ctx, collection, find)
+		if err != nil {
+			return 0, nil, err
+		}
+		return res.MatchedCount, docs, nil
+
+	default:
+		return 0, nil, utils.ErrInvalidParams
+	}
+}
+
+// DeleteReturning deletes the documents and returns them as they were before being deleted
+func (m *Mongo) DeleteReturning(ctx context.Context, col string, req *model.DeleteRequest) (int64, []interface{}, error) {
+	collection := m.getClient().Database(m.dbName).Collection(col)
+	req.Find = sanitizeWhereClause(ctx, col, req.Find)
+
+	switch req.Operation {
+	case utils.One:
+		var doc map[string]interface{}
+		if err := collection.FindOneAndDelete(ctx, req.Find).Decode(&doc); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return 0, []interface{}{}, nil
+			}
+			return 0, nil, err
+		}
+		return 1, []interface{}{doc}, nil
+
+	case utils.All:
+		docs, err := m.findDocuments(ctx, collection, req.Find)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(docs) == 0 {
+			return 0, docs, nil
+		}
+
+		// Only the documents which were read are deleted so that the response matches what was removed
+		ids := make([]interface{}, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.(map[string]interface{})["_id"]
+		}
+		res, err := collection.DeleteMany(ctx, map[string]interface{}{"_id": map[string]interface{}{"$in": ids}})
+		if err != nil {
+			return 0, nil, err
+		}
+		return res.DeletedCount, docs, nil
+
+	default:
+		return 0, nil, utils.ErrInvalidParams
+	}
+}
+
+func (m *Mongo) findIDs(ctx context.Context, collection *mongo.Collection, find map[string]interface{}) ([]interface{}, error) {
+	cur, err := collection.Find(ctx, find, options.Find().SetProjection(map[string]interface{}{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cur.Close(ctx) }()
+
+	ids := make([]interface{}, 0)
+	for cur.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc["_id"])
+	}
+	return ids, cur.Err()
+}
+
+func (m *Mongo) findDocuments(ctx context.Context, collection *mongo.Collection, find map[string]interface{}) ([]interface{}, error) {
+	cur, err := collection.Find(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cur.Close(ctx) }()
+
+	docs := make([]interface{}, 0)
+	for cur.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, cur.Err()
+}
@@ -0,0 +1,57 @@
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// ReadStream reads the documents matching the request and passes them to fn one at a time, without holding the result
+// in memory. The cursor fetches the next batch only after the documents of the current one are consumed.
+func (m *Mongo) ReadStream(ctx context.Context, col string, req *model.ReadRequest, fn func(doc map[string]interface{}) error) (int64, error) {
+	collection := m.getClient().Database(m.dbName).Collection(col)
+	req.Find = sanitizeWhereClause(ctx, col, req.Find)
+
+	findOptions := options.Find()
+	if comment := m.getQueryComment(ctx); comment != "" {
+		findOptions = findOptions.SetComment(comment)
+	}
+	if req.Options.Select != nil {
+		findOptions = findOptions.SetProjection(req.Options.Select)
+	}
+	if req.Options.Skip != nil {
+		findOptions = findOptions.SetSkip(*req.Options.Skip)
+	}
+	if req.Options.Limit != nil {
+		findOptions = findOptions.SetLimit(*req.Options.Limit)
+	}
+	if req.Options.Sort != nil {
+		findOptions = findOptions.SetSort(generateSortOptions(req.Options.Sort))
+	}
+
+	cur, err := collection.Find(ctx, req.Find, findOptions)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = cur.Close(ctx) }()
+
+	var count int64
+	for cur.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cur.Decode(&doc); err != nil {
+			return count, err
+		}
+		if req.Options.Debug {
+			doc["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+
+		if err := fn(doc); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cur.Err()
+}
@@ -0,0 +1,121 @@
+package mgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// ReadView queries document(s) from a view. The stages required to filter, sort and paginate the documents are
+// appended to the pipeline of the view.
+func (m *Mongo) ReadView(ctx context.Context, view *model.View, req *model.ReadRequest) (int64, interface{}, *model.SQLMetaData, error) {
+	pipeline, err := m.generateViewPipeline(ctx, view, req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Mongo view aggregate", map[string]interface{}{"view": view.Name, "col": view.Col, "pipeline": pipeline})
+	cur, err := m.getClient().Database(m.dbName).Collection(view.Col).Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer func() { _ = cur.Close(ctx) }()
+
+	results := []interface{}{}
+	for cur.Next(ctx) {
+		var doc map[string]interface{}
+		if err := cur.Decode(&doc); err != nil {
+			return 0, nil, nil, err
+		}
+		if req.Options.Debug {
+			doc["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+		results = append(results, doc)
+	}
+	if err := cur.Err(); err != nil {
+		return 0, nil, nil, err
+	}
+
+	switch req.Operation {
+	case utils.Count:
+		if len(results) == 0 {
+			return 0, int64(0), nil, nil
+		}
+		count, err := toInt64(results[0].(map[string]interface{})["count"])
+		return count, count, nil, err
+	case utils.One:
+		if len(results) == 0 {
+			return 0, nil, nil, mongo.ErrNoDocuments
+		}
+		return 1, results[0], nil, nil
+	default:
+		return int64(len(results)), results, nil, nil
+	}
+}
+
+func (m *Mongo) generateViewPipeline(ctx context.Context, view *model.View, req *model.ReadRequest) ([]interface{}, error) {
+	if view.Col == "" {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Collection of view (%s) is not provided", view.Name), nil, nil)
+	}
+	if len(req.Aggregate) > 0 || (req.Options != nil && len(req.Options.Join) > 0) {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Aggregations and joins cannot be performed on view (%s)", view.Name), nil, nil)
+	}
+
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+	if req.Options.Limit == nil {
+		req.Options.Limit = m.queryFetchLimit
+		req.Options.HasOptions = true
+	}
+
+	pipeline := make([]interface{}, len(view.Pipeline), len(view.Pipeline)+5)
+	copy(pipeline, view.Pipeline)
+
+	req.Find = sanitizeWhereClause(ctx, view.Name, req.Find)
+	if len(req.Find) > 0 {
+		pipeline = append(pipeline, bson.M{"$match": req.Find})
+	}
+
+	if req.Operation == utils.Count {
+		return append(pipeline, bson.M{"$count": "count"}), nil
+	}
+	if req.Operation != utils.All && req.Operation != utils.One {
+		return nil, utils.ErrInvalidParams
+	}
+
+	if len(req.Options.Sort) > 0 {
+		pipeline = append(pipeline, bson.M{"$sort": generateSortOptions(req.Options.Sort)})
+	}
+	if req.Options.Skip != nil {
+		pipeline = append(pipeline, bson.M{"$skip": *req.Options.Skip})
+	}
+	if req.Operation == utils.One {
+		pipeline = append(pipeline, bson.M{"$limit": 1})
+	} else if req.Options.Limit != nil {
+		pipeline = append(pipeline, bson.M{"$limit": *req.Options.Limit})
+	}
+	if len(req.Options.Select) > 0 {
+		pipeline = append(pipeline, bson.M{"$project": req.Options.Select})
+	}
+	return pipeline, nil
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case float64:
+		return int64(n), nil
+	}
+	return 0, fmt.Errorf("unexpected count (%v) of type (%T)", v, v)
+}
@@ -6,22 +6,53 @@ import (
 
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/plugins"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 // Create inserts a documents (or multiple when op is "all") into the database based on dbType
 func (m *Module) Create(ctx context.Context, dbAlias, col string, req *model.CreateRequest, params model.RequestParams) error {
+	_, err := m.CreateReturning(ctx, dbAlias, col, req, params)
+	return err
+}
+
+// CreateReturning inserts the document(s) like Create. The created documents, including the values generated for them
+// by the database, are returned if the request asks for them.
+func (m *Module) CreateReturning(ctx context.Context, dbAlias, col string, req *model.CreateRequest, params model.RequestParams) ([]interface{}, error) {
 	m.RLock()
 	defer m.RUnlock()
 
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return nil, err
+	}
+	hookAlias := dbAlias
+	dbAlias = t.dbAlias
+	if err := m.checkNotView(ctx, dbAlias, col); err != nil {
+		return nil, err
+	}
+	if err := m.checkNotHistory(ctx, dbAlias, col); err != nil {
+		return nil, err
+	}
+
+	hookRes, err := m.invokeBeforeWriteHook(ctx, hookAlias, col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: col, Op: model.Create, Doc: req.Document})
+	if err != nil {
+		return nil, err
+	}
+	if hookRes.Doc != nil {
+		req.Document = hookRes.Doc
+	}
+	t.scopeDocument(req.Document)
+
 	dbType, err := m.getDBType(dbAlias)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := schemaHelpers.ValidateCreateOperation(ctx, dbAlias, dbType, col, m.schemaDoc, req); err != nil {
-		return err
+		return nil, err
 	}
 
 	params.Payload = req
@@ -29,44 +60,117 @@ func (m *Module) Create(ctx context.Context, dbAlias, col string, req *model.Cre
 	if hookResponse.CheckResponse() {
 		// Check if an error occurred
 		if err := hookResponse.Error(); err != nil {
-			return err
+			return nil, err
 		}
 
 		// Gracefully return
-		return nil
+		return nil, nil
 	}
 
 	crud, err := m.getCrudBlock(dbAlias)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := crud.IsClientSafe(ctx); err != nil {
-		return err
+		return nil, err
+	}
+
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: col, Op: model.Create, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return nil, err
+	}
+
+	history, err := m.prepareHistory(ctx, crud, dbAlias, dbType, col, model.Create, nil, req.Operation)
+	if err != nil {
+		plugins.AfterCrud(ctx, pluginReq, int64(0), err)
+		return nil, err
 	}
 
 	var n int64
-	if req.IsBatch {
+	var docs []interface{}
+	switch {
+	case req.Returning:
+		// The documents can't be returned by the batcher since it merges the inserts of several requests
+		var r returningCrud
+		if r, err = getReturningBlock(ctx, dbAlias, crud); err == nil {
+			n, docs, err = r.CreateReturning(ctx, col, req)
+		}
+	case req.IsBatch:
 		// add the request for batch operation
 		n, err = m.createBatch(ctx, m.project, dbAlias, col, req.Document)
-	} else {
+	default:
 		// Perform the create operation
 		n, err = crud.Create(ctx, col, req)
 	}
+	plugins.AfterCrud(ctx, pluginReq, n, err)
 
-	// Invoke the metric hook if the operation was successful
-	if err == nil {
-		m.metricHook(m.project, dbAlias, col, n, model.Create)
+	if err != nil {
+		return nil, err
 	}
 
-	return err
+	// Invoke the metric hook if the operation was successful
+	m.metricHook(m.project, dbAlias, col, n, model.Create)
+
+	docs, err = m.postProcessReturning(ctx, dbAlias, dbType, col, docs)
+	if err != nil {
+		return nil, err
+	}
+	if req.Returning {
+		m.recordHistory(ctx, crud, dbAlias, history, docs)
+	} else {
+		m.recordHistory(ctx, crud, dbAlias, history, req.Document)
+	}
+	m.invokeAfterWriteHook(ctx, hookAlias, col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: col, Op: model.Create, Doc: req.Document, Count: n, Result: docs})
+	return docs, nil
 }
 
 // Read returns the documents(s) which match a query from the database based on dbType
 func (m *Module) Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	// Over budget reads are admitted before the lock is acquired since they might have to wait for their turn
+	budget := m.getQueryBudget()
+	if budget == nil {
+		return m.readCounters(ctx, dbAlias, col, req, params)
+	}
+
+	// Reads without a limit are estimated to return as many rows as the database sends per request
+	fetchLimit, err := m.getPageLimit(dbAlias, nil)
+	if err != nil {
+		fetchLimit = model.DefaultFetchLimit
+	}
+	charge, err := budget.admit(ctx, req, params, fetchLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, metaData, err := m.readCounters(ctx, dbAlias, col, req, params)
+	charge(result, err)
+	return result, metaData, err
+}
+
+// readCounters reads the documents along with the sum of the shards of their sharded counters. The shards are read
+// after the documents since reading them needs the lock.
+func (m *Module) readCounters(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	result, metaData, err := m.read(ctx, dbAlias, col, req, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.sumShardedCounters(ctx, dbAlias, col, req, result, params); err != nil {
+		return nil, nil, err
+	}
+	return result, metaData, nil
+}
+
+func (m *Module) read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
 	m.RLock()
 	defer m.RUnlock()
 
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbAlias = t.dbAlias
+	t.scopeRead(col, req)
+
 	// Adjust where clause
 	dbType, err := m.getDBType(dbAlias)
 	if err != nil {
@@ -97,11 +201,37 @@ func (m *Module) Read(ctx context.Context, dbAlias, col string, req *model.ReadR
 		return hookResponse.Result(), nil, nil
 	}
 
-	if req.IsBatch {
-		dbType, err := m.getDBType(dbAlias)
-		if err != nil {
-			return nil, nil, err
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: col, Op: model.Read, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return nil, nil, err
+	}
+
+	result, metaData, err := m.execRead(ctx, crud, dbAlias, dbType, col, req, params)
+	plugins.AfterCrud(ctx, pluginReq, result, err)
+	return result, metaData, err
+}
+
+// execRead executes a read which has been passed to the crud interceptors
+func (m *Module) execRead(ctx context.Context, crud Crud, dbAlias, dbType, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	// Views are read directly from the database since their results depend on the arguments they are invoked with
+	if view, ok := m.getView(dbAlias, col); ok {
+		result, metaData, err := m.readView(ctx, crud, dbAlias, view, req, params)
+		if err == nil {
+			err = schemaHelpers.CrudPostProcess(ctx, dbAlias, dbType, col, m.schemaDoc, result)
+		}
+		return result, metaData, err
+	}
+
+	// Point in time reads are answered from the history table of the collection
+	if req.Options != nil && req.Options.ReadAt != "" {
+		result, err := m.readAt(ctx, crud, dbAlias, dbType, col, req)
+		if err == nil {
+			err = schemaHelpers.CrudPostProcess(ctx, dbAlias, dbType, col, m.schemaDoc, result)
 		}
+		return result, nil, err
+	}
+
+	if req.IsBatch {
 		key := model.ReadRequestKey{DBType: dbType, DBAlias: dbAlias, Col: col, HasOptions: req.Options.HasOptions, Req: *req, ReqParams: params}
 		dataLoader, ok := m.getLoader(fmt.Sprintf("%s-%s-%s", m.project, dbAlias, col))
 		if !ok {
@@ -116,7 +246,7 @@ func (m *Module) Read(ctx context.Context, dbAlias, col string, req *model.ReadR
 			res.metaData.DbAlias = dbAlias
 			res.metaData.Col = col
 		}
-		return res.doc, res.metaData, err
+		return res.doc, res.metaData, nil
 	}
 
 	dbCacheOptions, err := m.caching.GetDatabaseKey(ctx, m.project, dbAlias, col, req)
@@ -166,15 +296,58 @@ func (m *Module) Read(ctx context.Context, dbAlias, col string, req *model.ReadR
 
 // Update updates the documents(s) which match a query from the database based on dbType
 func (m *Module) Update(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) error {
+	_, _, err := m.update(ctx, dbAlias, col, req, params)
+	return err
+}
+
+// UpdateReturning updates the document(s) like Update. The updated documents are returned if the request asks for them.
+func (m *Module) UpdateReturning(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) ([]interface{}, error) {
+	_, docs, err := m.update(ctx, dbAlias, col, req, params)
+	return docs, err
+}
+
+// UpdateCount updates the document(s) like Update and returns the number of documents updated
+func (m *Module) UpdateCount(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) (int64, error) {
+	n, _, err := m.update(ctx, dbAlias, col, req, params)
+	return n, err
+}
+
+func (m *Module) update(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) (int64, []interface{}, error) {
 	m.RLock()
 	defer m.RUnlock()
 
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return 0, nil, err
+	}
+	hookAlias := dbAlias
+	dbAlias = t.dbAlias
+	if err := m.checkNotView(ctx, dbAlias, col); err != nil {
+		return 0, nil, err
+	}
+	if err := m.checkNotHistory(ctx, dbAlias, col); err != nil {
+		return 0, nil, err
+	}
+
+	hookRes, err := m.invokeBeforeWriteHook(ctx, hookAlias, col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: col, Op: model.Update, Find: req.Find, Update: req.Update})
+	if err != nil {
+		return 0, nil, err
+	}
+	if hookRes.Find != nil {
+		req.Find = hookRes.Find
+	}
+	if hookRes.Update != nil {
+		req.Update = hookRes.Update
+	}
+	req.Find = t.scopeFind(req.Find)
+	req.Update = t.scopeUpdate(req.Operation, req.Update)
+
 	dbType, err := m.getDBType(dbAlias)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 	if err := schemaHelpers.ValidateUpdateOperation(ctx, dbAlias, dbType, col, req.Operation, req.Update, req.Find, m.schemaDoc); err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	params.Payload = req
@@ -182,59 +355,119 @@ func (m *Module) Update(ctx context.Context, dbAlias, col string, req *model.Upd
 	if hookResponse.CheckResponse() {
 		// Check if an error occurred
 		if err := hookResponse.Error(); err != nil {
-			return err
+			return 0, nil, err
 		}
 
 		// Gracefully return
-		return nil
+		return 0, nil, nil
 	}
 
 	crud, err := m.getCrudBlock(dbAlias)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	if err := crud.IsClientSafe(ctx); err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	// Adjust where clause
 	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
-		return err
+		return 0, nil, err
+	}
+
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: col, Op: model.Update, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return 0, nil, err
+	}
+
+	history, err := m.prepareHistory(ctx, crud, dbAlias, dbType, col, model.Update, req.Find, req.Operation)
+	if err != nil {
+		plugins.AfterCrud(ctx, pluginReq, int64(0), err)
+		return 0, nil, err
 	}
 
 	// Perform the update operation
-	n, err := crud.Update(ctx, col, req)
+	var n int64
+	var docs []interface{}
+	if req.Returning {
+		var r returningCrud
+		if r, err = getReturningBlock(ctx, dbAlias, crud); err == nil {
+			n, docs, err = r.UpdateReturning(ctx, col, req)
+		}
+	} else {
+		n, err = crud.Update(ctx, col, req)
+	}
+	plugins.AfterCrud(ctx, pluginReq, n, err)
+
+	if err != nil {
+		return 0, nil, err
+	}
 
 	// Invoke the metric hook if the operation was successful
-	if err == nil {
-		m.metricHook(m.project, dbAlias, col, n, model.Update)
+	m.metricHook(m.project, dbAlias, col, n, model.Update)
+	if err := m.checkVersionConflict(ctx, crud, dbAlias, col, req.Operation, req.Find, n); err != nil {
+		return 0, nil, err
 	}
 
-	return err
+	docs, err = m.postProcessReturning(ctx, dbAlias, dbType, col, docs)
+	if err != nil {
+		return 0, nil, err
+	}
+	m.recordHistory(ctx, crud, dbAlias, history, nil)
+	m.invokeAfterWriteHook(ctx, hookAlias, col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: col, Op: model.Update, Find: req.Find, Update: req.Update, Count: n, Result: docs})
+	return n, docs, nil
 }
 
 // Delete removes the documents(s) which match a query from the database based on dbType
 func (m *Module) Delete(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) error {
+	_, err := m.DeleteReturning(ctx, dbAlias, col, req, params)
+	return err
+}
+
+// DeleteReturning removes the document(s) like Delete. The deleted documents are returned if the request asks for them.
+func (m *Module) DeleteReturning(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) ([]interface{}, error) {
 	m.RLock()
 	defer m.RUnlock()
 
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return nil, err
+	}
+	hookAlias := dbAlias
+	dbAlias = t.dbAlias
+	if err := m.checkNotView(ctx, dbAlias, col); err != nil {
+		return nil, err
+	}
+	if err := m.checkNotHistory(ctx, dbAlias, col); err != nil {
+		return nil, err
+	}
+
+	hookRes, err := m.invokeBeforeWriteHook(ctx, hookAlias, col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: col, Op: model.Delete, Find: req.Find})
+	if err != nil {
+		return nil, err
+	}
+	if hookRes.Find != nil {
+		req.Find = hookRes.Find
+	}
+	req.Find = t.scopeFind(req.Find)
+
 	crud, err := m.getCrudBlock(dbAlias)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := crud.IsClientSafe(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Adjust where clause
 	dbType, err := m.getDBType(dbAlias)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
-		return err
+		return nil, err
 	}
 
 	params.Payload = req
@@ -242,22 +475,62 @@ func (m *Module) Delete(ctx context.Context, dbAlias, col string, req *model.Del
 	if hookResponse.CheckResponse() {
 		// Check if an error occurred
 		if err := hookResponse.Error(); err != nil {
-			return err
+			return nil, err
 		}
 
 		// Gracefully return
-		return nil
+		return nil, nil
+	}
+
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: col, Op: model.Delete, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return nil, err
+	}
+
+	history, err := m.prepareHistory(ctx, crud, dbAlias, dbType, col, model.Delete, req.Find, req.Operation)
+	if err != nil {
+		plugins.AfterCrud(ctx, pluginReq, int64(0), err)
+		return nil, err
 	}
 
 	// Perform the delete operation
-	n, err := crud.Delete(ctx, col, req)
+	var n int64
+	var docs []interface{}
+	if req.Returning {
+		var r returningCrud
+		if r, err = getReturningBlock(ctx, dbAlias, crud); err == nil {
+			n, docs, err = r.DeleteReturning(ctx, col, req)
+		}
+	} else {
+		n, err = crud.Delete(ctx, col, req)
+	}
+	plugins.AfterCrud(ctx, pluginReq, n, err)
+
+	if err != nil {
+		return nil, err
+	}
 
 	// Invoke the metric hook if the operation was successful
-	if err == nil {
-		m.metricHook(m.project, dbAlias, col, n, model.Delete)
+	m.metricHook(m.project, dbAlias, col, n, model.Delete)
+
+	docs, err = m.postProcessReturning(ctx, dbAlias, dbType, col, docs)
+	if err != nil {
+		return nil, err
 	}
+	m.recordHistory(ctx, crud, dbAlias, history, nil)
+	m.invokeAfterWriteHook(ctx, hookAlias, col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: col, Op: model.Delete, Find: req.Find, Count: n, Result: docs})
+	return docs, nil
+}
 
-	return err
+// postProcessReturning processes the documents returned by a mutation the same way as the result of a read
+func (m *Module) postProcessReturning(ctx context.Context, dbAlias, dbType, col string, docs []interface{}) ([]interface{}, error) {
+	if docs == nil {
+		return nil, nil
+	}
+	if err := schemaHelpers.CrudPostProcess(ctx, dbAlias, dbType, col, m.schemaDoc, docs); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to perform schema post process on the documents returned by the mutation on col (%s)", col), err, nil)
+	}
+	return docs, nil
 }
 
 // ExecPreparedQuery executes PreparedQueries request
@@ -265,6 +538,14 @@ func (m *Module) ExecPreparedQuery(ctx context.Context, dbAlias, id string, req
 	m.RLock()
 	defer m.RUnlock()
 
+	// Prepared queries are forwarded to the database of the tenant. In the column mode, the query needs to
+	// filter on the tenant claim itself by using it in its arguments.
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbAlias = t.dbAlias
+
 	params.Payload = req
 	hookResponse := m.integrationMan.InvokeHook(ctx, params)
 	if hookResponse.CheckResponse() {
@@ -292,6 +573,24 @@ func (m *Module) ExecPreparedQuery(ctx context.Context, dbAlias, id string, req
 		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Prepared Query for given id (%s) does not exist", id), nil, nil)
 	}
 
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: id, Op: model.PreparedQuery, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return nil, nil, err
+	}
+
+	result, metaData, err := m.execPreparedQuery(ctx, crud, dbAlias, id, preparedQuery, req, params)
+	plugins.AfterCrud(ctx, pluginReq, result, err)
+	return result, metaData, err
+}
+
+// execPreparedQuery executes a prepared query which has been passed to the crud interceptors
+func (m *Module) execPreparedQuery(ctx context.Context, crud Crud, dbAlias, id string, preparedQuery *config.DatbasePreparedQuery, req *model.PreparedQueryRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	// Executing a view returns all of its rows
+	if preparedQuery.IsView {
+		readReq := &model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.All, Extras: req.Params, Options: &model.ReadOptions{Debug: req.Debug}}
+		return m.readView(ctx, crud, dbAlias, preparedQuery, readReq, params)
+	}
+
 	// Load the arguments
 	var args []interface{}
 	for i := 0; i < len(preparedQuery.Arguments); i++ {
@@ -316,6 +615,13 @@ func (m *Module) Aggregate(ctx context.Context, dbAlias, col string, req *model.
 	m.RLock()
 	defer m.RUnlock()
 
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return nil, err
+	}
+	dbAlias = t.dbAlias
+	req.Pipeline = t.scopePipeline(req.Pipeline)
+
 	params.Payload = req
 	hookResponse := m.integrationMan.InvokeHook(ctx, params)
 	if hookResponse.CheckResponse() {
@@ -337,7 +643,14 @@ func (m *Module) Aggregate(ctx context.Context, dbAlias, col string, req *model.
 		return nil, err
 	}
 
-	return crud.Aggregate(ctx, col, req)
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: col, Op: model.Aggregation, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return nil, err
+	}
+
+	result, err := crud.Aggregate(ctx, col, req)
+	plugins.AfterCrud(ctx, pluginReq, result, err)
+	return result, err
 }
 
 // Batch performs a batch operation on the database
@@ -345,6 +658,28 @@ func (m *Module) Batch(ctx context.Context, dbAlias string, req *model.BatchRequ
 	m.RLock()
 	defer m.RUnlock()
 
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return err
+	}
+
+	// The before write hooks of all the requests are invoked before the batch is performed. Hence a hook rejecting
+	// any one of the requests aborts the entire batch.
+	if err := m.invokeBatchBeforeWriteHooks(ctx, dbAlias, req, params); err != nil {
+		return err
+	}
+	hookAlias := dbAlias
+	dbAlias = t.dbAlias
+	t.scopeBatch(req)
+	for _, r := range req.Requests {
+		if err := m.checkNotView(ctx, dbAlias, r.Col); err != nil {
+			return err
+		}
+		if err := m.checkNotHistory(ctx, dbAlias, r.Col); err != nil {
+			return err
+		}
+	}
+
 	crud, err := m.getCrudBlock(dbAlias)
 	if err != nil {
 		return err
@@ -386,14 +721,46 @@ func (m *Module) Batch(ctx context.Context, dbAlias string, req *model.BatchRequ
 		return err
 	}
 
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Op: model.Batch, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return err
+	}
+
+	histories := make([]*historyWrite, len(req.Requests))
+	for i, r := range req.Requests {
+		if histories[i], err = m.prepareHistory(ctx, crud, dbAlias, dbType, r.Col, model.OperationType(r.Type), r.Find, r.Operation); err != nil {
+			plugins.AfterCrud(ctx, pluginReq, nil, err)
+			return err
+		}
+	}
+
+	// Events of the batch are written to the event log as a part of the batch
+	batch, intent, err := m.withOutboxEvents(ctx, dbAlias, req)
+	if err != nil {
+		plugins.AfterCrud(ctx, pluginReq, nil, err)
+		return err
+	}
+
 	// Perform the batch operation
-	counts, err := crud.Batch(ctx, req)
+	counts, err := crud.Batch(ctx, batch)
+	plugins.AfterCrud(ctx, pluginReq, counts, err)
 
 	// Invoke the metric hook if the operation was successful
 	if err == nil {
+		if intent != nil {
+			m.eventing.TransmitOutboxEvents(intent, req.Events)
+		}
 		for i, r := range req.Requests {
 			m.metricHook(m.project, dbAlias, r.Col, counts[i], model.OperationType(r.Type))
 		}
+		if err := m.checkBatchVersionConflicts(ctx, crud, dbAlias, req, counts); err != nil {
+			return err
+		}
+		for i, r := range req.Requests {
+			m.recordHistory(ctx, crud, dbAlias, histories[i], r.Document)
+			m.invokeAfterWriteHook(ctx, hookAlias, r.Col, params, &model.WriteHookRequest{DBAlias: hookAlias, Col: r.Col, Op: model.OperationType(r.Type), Doc: r.Document, Find: r.Find, Update: r.Update, Count: counts[i]})
+		}
+		return nil
 	}
 
 	return err
@@ -467,6 +834,61 @@ func (m *Module) GetConnectionState(ctx context.Context, dbAlias string) bool {
 	return crud.GetConnectionState(ctx)
 }
 
+// CheckHealth checks the connectivity of all enabled databases. The key of the returned map is the db alias.
+func (m *Module) CheckHealth(ctx context.Context) map[string]error {
+	m.RLock()
+	blocks := make(map[string]Crud, len(m.databaseConfigs))
+	for dbAlias, c := range m.databaseConfigs {
+		if c.Enabled {
+			blocks[dbAlias] = m.blocks[dbAlias]
+		}
+	}
+	m.RUnlock()
+
+	// The connection state is checked without holding the lock since it might take a while for unreachable databases
+	result := make(map[string]error, len(blocks))
+	for dbAlias, block := range blocks {
+		if block == nil {
+			result[dbAlias] = fmt.Errorf("crud module not initialized for database (%s)", dbAlias)
+			continue
+		}
+		if !block.GetConnectionState(ctx) {
+			result[dbAlias] = fmt.Errorf("unable to connect to database (%s)", dbAlias)
+			continue
+		}
+		result[dbAlias] = nil
+	}
+	return result
+}
+
+// GetPoolStats returns the connection pool statistics of all databases which expose them. The key of the returned map is the db alias.
+func (m *Module) GetPoolStats() map[string]model.DBPoolStats {
+	m.RLock()
+	defer m.RUnlock()
+
+	stats := map[string]model.DBPoolStats{}
+	for dbAlias, block := range m.blocks {
+		if p, ok := unwrapCrud(block).(poolStatsProvider); ok {
+			stats[dbAlias] = p.GetPoolStats()
+		}
+	}
+	return stats
+}
+
+// GetQueryStats returns the query latency distribution and the recent slow queries of a database.
+// Only the queries of the provided collection are returned if col isn't empty.
+func (m *Module) GetQueryStats(ctx context.Context, dbAlias, col string) (*model.SlowQueryResponse, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	stats, p := m.queryStats[dbAlias]
+	if !p {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to get query stats. Ensure you have added a database", fmt.Errorf("crud module not initialized for database (%s)", dbAlias), nil)
+	}
+
+	return &model.SlowQueryResponse{Stats: stats.getStats(col), SlowQueries: stats.getSlowQueries(col)}, nil
+}
+
 // DeleteTable drop specified table from database
 func (m *Module) DeleteTable(ctx context.Context, dbAlias, col string) error {
 	m.RLock()
@@ -0,0 +1,177 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/caching"
+	"github.com/spaceuptech/space-cloud/gateway/plugins"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// interceptorsTestProject is the project of the requests recorded by the interceptor registered by the tests
+const interceptorsTestProject = "interceptors-test"
+
+type recordingInterceptor struct {
+	lock   sync.Mutex
+	before []model.OperationType
+	after  []model.OperationType
+	errs   []error
+}
+
+var testInterceptor = &recordingInterceptor{}
+
+func init() {
+	_ = plugins.RegisterCrudInterceptor(testInterceptor)
+}
+
+func (i *recordingInterceptor) Name() string { return "crud-operations-test" }
+
+func (i *recordingInterceptor) BeforeCrud(_ context.Context, req *plugins.CrudRequest) error {
+	if req.Project == interceptorsTestProject {
+		i.lock.Lock()
+		i.before = append(i.before, req.Op)
+		i.lock.Unlock()
+	}
+	return nil
+}
+
+func (i *recordingInterceptor) AfterCrud(_ context.Context, req *plugins.CrudRequest, _ interface{}, err error) {
+	if req.Project == interceptorsTestProject {
+		i.lock.Lock()
+		i.after = append(i.after, req.Op)
+		i.errs = append(i.errs, err)
+		i.lock.Unlock()
+	}
+}
+
+func (i *recordingInterceptor) reset() {
+	i.lock.Lock()
+	i.before, i.after, i.errs = nil, nil, nil
+	i.lock.Unlock()
+}
+
+type fakeIntegrationManager struct{}
+
+func (fakeIntegrationManager) InvokeHook(context.Context, model.RequestParams) config.IntegrationAuthResponse {
+	return fakeHookResponse{}
+}
+
+type fakeHookResponse struct{}
+
+func (fakeHookResponse) CheckResponse() bool { return false }
+func (fakeHookResponse) Error() error        { return nil }
+func (fakeHookResponse) Status() int         { return 0 }
+func (fakeHookResponse) Result() interface{} { return nil }
+
+type fakeCaching struct{ err error }
+
+func (c fakeCaching) SetDatabaseKey(context.Context, string, string, string, *model.CacheDatabaseResult, *caching.CacheResult, *config.ReadCacheOptions, map[string]map[string]string) error {
+	return c.err
+}
+
+func (c fakeCaching) GetDatabaseKey(context.Context, string, string, string, *model.ReadRequest) (*caching.CacheResult, error) {
+	return nil, c.err
+}
+
+// fakeCrud is a crud block answering the operations used by the tests. The other operations panic.
+type fakeCrud struct {
+	Crud
+	err error
+}
+
+func (c fakeCrud) GetDBType() model.DBType            { return model.Postgres }
+func (c fakeCrud) IsClientSafe(context.Context) error { return nil }
+
+func (c fakeCrud) Aggregate(context.Context, string, *model.AggregateRequest) (interface{}, error) {
+	return []interface{}{}, c.err
+}
+
+func (c fakeCrud) RawQuery(context.Context, string, bool, []interface{}) (int64, interface{}, *model.SQLMetaData, error) {
+	return 0, []interface{}{}, nil, c.err
+}
+
+func (c fakeCrud) Batch(_ context.Context, req *model.BatchRequest) ([]int64, error) {
+	return make([]int64, len(req.Requests)), c.err
+}
+
+func TestModule_crudInterceptors(t *testing.T) {
+	dbErr := errors.New("database unavailable")
+	tests := []struct {
+		name    string
+		err     error
+		caching cachingInterface
+		op      model.OperationType
+		run     func(m *Module) error
+	}{
+		{
+			name: "aggregate",
+			op:   model.Aggregation,
+			run: func(m *Module) error {
+				_, err := m.Aggregate(context.Background(), "db", "posts", &model.AggregateRequest{Operation: utils.All}, model.RequestParams{})
+				return err
+			},
+		},
+		{
+			name: "prepared query",
+			err:  dbErr,
+			op:   model.PreparedQuery,
+			run: func(m *Module) error {
+				_, _, err := m.ExecPreparedQuery(context.Background(), "db", "top-posts", &model.PreparedQueryRequest{}, model.RequestParams{})
+				return err
+			},
+		},
+		{
+			name: "batch",
+			op:   model.Batch,
+			run: func(m *Module) error {
+				req := &model.BatchRequest{Requests: []*model.AllRequest{{Type: string(model.Delete), Col: "posts", Operation: utils.All, Find: map[string]interface{}{}}}}
+				return m.Batch(context.Background(), "db", req, model.RequestParams{})
+			},
+		},
+		{
+			name:    "read failing after the interceptors",
+			caching: fakeCaching{err: dbErr},
+			op:      model.Read,
+			run: func(m *Module) error {
+				_, _, err := m.Read(context.Background(), "db", "posts", &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{}}, model.RequestParams{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testInterceptor.reset()
+			m := Init()
+			m.project = interceptorsTestProject
+			m.blocks["db"] = fakeCrud{err: tt.err}
+			m.databaseConfigs["db"] = &config.DatabaseConfig{DbAlias: "db", Type: string(model.Postgres)}
+			m.queries = config.DatabasePreparedQueries{getPreparedQueryKey("db", "top-posts"): &config.DatbasePreparedQuery{ID: "top-posts", DbAlias: "db", SQL: "SELECT 1"}}
+			m.integrationMan = fakeIntegrationManager{}
+			m.caching = tt.caching
+			m.metricHook = func(string, string, string, int64, model.OperationType) {}
+
+			err := tt.run(m)
+			wantErr := tt.err
+			if c, ok := tt.caching.(fakeCaching); ok {
+				wantErr = c.err
+			}
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("operation returned error (%v), want (%v)", err, wantErr)
+			}
+
+			testInterceptor.lock.Lock()
+			defer testInterceptor.lock.Unlock()
+			if len(testInterceptor.before) != 1 || testInterceptor.before[0] != tt.op {
+				t.Errorf("BeforeCrud() invoked for %v, want %v", testInterceptor.before, tt.op)
+			}
+			if len(testInterceptor.after) != 1 || testInterceptor.after[0] != tt.op || !errors.Is(testInterceptor.errs[0], wantErr) {
+				t.Errorf("AfterCrud() invoked for %v with errors %v, want %v with error (%v)", testInterceptor.after, testInterceptor.errs, tt.op, wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package crud
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fatih/structs"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// eventingInterface is used to publish the events of a batch through the event log of the eventing module
+type eventingInterface interface {
+	CreateOutboxIntent(ctx context.Context, dbAlias string, events []*model.QueueEventRequest) (*model.EventIntent, error)
+	TransmitOutboxEvents(intent *model.EventIntent, events []*model.QueueEventRequest)
+}
+
+// SetEventingModule sets the eventing module used to publish the events of batches
+func (m *Module) SetEventingModule(e eventingInterface) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.eventing = e
+}
+
+// withOutboxEvents returns the batch along with a request writing its events to the event log. The events thus get
+// written in the same transaction as the rest of the batch.
+func (m *Module) withOutboxEvents(ctx context.Context, dbAlias string, req *model.BatchRequest) (*model.BatchRequest, *model.EventIntent, error) {
+	if len(req.Events) == 0 {
+		return req, nil, nil
+	}
+	if m.eventing == nil {
+		return nil, nil, errors.New("cannot publish events along with the batch as eventing isn't available")
+	}
+
+	intent, err := m.eventing.CreateOutboxIntent(ctx, dbAlias, req.Events)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(intent.Docs) == 0 {
+		return req, intent, nil
+	}
+
+	docs := make([]interface{}, len(intent.Docs))
+	for i, doc := range intent.Docs {
+		docs[i] = structs.Map(doc)
+	}
+
+	requests := make([]*model.AllRequest, len(req.Requests), len(req.Requests)+1)
+	copy(requests, req.Requests)
+	requests = append(requests, &model.AllRequest{Type: string(model.Create), Col: utils.TableEventingLogs, Document: docs, Operation: utils.All, DBAlias: dbAlias})
+	return &model.BatchRequest{Requests: requests}, intent, nil
+}
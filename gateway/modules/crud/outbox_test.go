@@ -0,0 +1,63 @@
+package crud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type fakeEventing struct {
+	docs []*model.EventDocument
+}
+
+func (f *fakeEventing) CreateOutboxIntent(ctx context.Context, dbAlias string, events []*model.QueueEventRequest) (*model.EventIntent, error) {
+	return &model.EventIntent{BatchID: "batch", Docs: f.docs}, nil
+}
+
+func (f *fakeEventing) TransmitOutboxEvents(intent *model.EventIntent, events []*model.QueueEventRequest) {
+}
+
+func TestModule_withOutboxEvents(t *testing.T) {
+	writes := []*model.AllRequest{{Type: string(model.Create), Col: "orders", Document: map[string]interface{}{"id": "1"}, Operation: utils.One}}
+	events := []*model.QueueEventRequest{{Type: "order_placed", Payload: map[string]interface{}{"id": "1"}}}
+
+	tests := []struct {
+		name         string
+		eventing     eventingInterface
+		req          *model.BatchRequest
+		wantRequests int
+		wantErr      bool
+	}{
+		{name: "batch without events", req: &model.BatchRequest{Requests: writes}, wantRequests: 1},
+		{name: "events without eventing", req: &model.BatchRequest{Requests: writes, Events: events}, wantErr: true},
+		{name: "events without matching triggers", eventing: &fakeEventing{}, req: &model.BatchRequest{Requests: writes, Events: events}, wantRequests: 1},
+		{name: "events written to the event log", eventing: &fakeEventing{docs: []*model.EventDocument{{ID: "event", Type: "order_placed", Status: utils.EventStatusStaged}}}, req: &model.BatchRequest{Requests: writes, Events: events}, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Module{eventing: tt.eventing}
+			got, _, err := m.withOutboxEvents(context.Background(), "db", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("withOutboxEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got.Requests) != tt.wantRequests {
+				t.Fatalf("withOutboxEvents() requests = %d, want %d", len(got.Requests), tt.wantRequests)
+			}
+			if len(tt.req.Requests) != 1 {
+				t.Errorf("withOutboxEvents() modified the requests of the batch")
+			}
+			if tt.wantRequests == 2 {
+				outbox := got.Requests[1]
+				docs := outbox.Document.([]interface{})
+				if outbox.Col != utils.TableEventingLogs || outbox.Type != string(model.Create) || docs[0].(map[string]interface{})["_id"] != "event" {
+					t.Errorf("withOutboxEvents() outbox request = %+v", outbox)
+				}
+			}
+		})
+	}
+}
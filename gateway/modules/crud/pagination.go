@@ -0,0 +1,204 @@
+package crud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// cursorPrefix marks the cursors of the pages. The cursors are the offsets of the documents encoded to keep them opaque.
+const cursorPrefix = "offset:"
+
+// estimatingCrud is implemented by the crud blocks which can estimate the number of documents matching a read
+type estimatingCrud interface {
+	EstimateCount(ctx context.Context, col string, req *model.ReadRequest) (int64, bool, error)
+}
+
+// ReadPage reads a page of documents like Read and returns the pagination metadata of the page. One document more
+// than the limit is read to find out if there is a next page.
+func (m *Module) ReadPage(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.PageInfo, error) {
+	if req.Operation != utils.All {
+		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Pagination metadata is only available for reads with op (%s)", utils.All), nil, nil)
+	}
+	if req.Options == nil {
+		req.Options = new(model.ReadOptions)
+	}
+	options := req.Options
+	switch options.TotalCount {
+	case "", model.TotalCountExact, model.TotalCountEstimated:
+	default:
+		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid total count (%s) provided. It must be either %s or %s", options.TotalCount, model.TotalCountExact, model.TotalCountEstimated), nil, nil)
+	}
+
+	var skip int64
+	if options.After != "" {
+		offset, err := decodeCursor(options.After)
+		if err != nil {
+			return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid cursor provided", err, nil)
+		}
+		skip = offset + 1
+		options.Skip = &skip
+	} else if options.Skip != nil {
+		skip = *options.Skip
+	}
+
+	// The page is bound by the fetch limit of the database if the request doesn't set a limit
+	limit, err := m.getPageLimit(dbAlias, options.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if limit > 0 {
+		fetch := limit + 1
+		options.Limit = &fetch
+	}
+	options.HasOptions = true
+
+	// The count is made first since reading scopes the where clause of the request in place
+	pageInfo := &model.PageInfo{HasPreviousPage: skip > 0}
+	if options.TotalCount != "" {
+		count, countType, err := m.countDocuments(ctx, dbAlias, col, req, params)
+		if err != nil {
+			return nil, nil, err
+		}
+		pageInfo.TotalCount, pageInfo.TotalCountType = count, countType
+	}
+
+	result, _, err := m.Read(ctx, dbAlias, col, req, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docs, ok := result.([]interface{})
+	if !ok {
+		return result, pageInfo, nil
+	}
+	if limit > 0 && int64(len(docs)) > limit {
+		pageInfo.HasNextPage = true
+		docs = docs[:limit]
+	}
+	if len(docs) > 0 {
+		pageInfo.StartCursor = encodeCursor(skip)
+		pageInfo.EndCursor = encodeCursor(skip + int64(len(docs)) - 1)
+	}
+	return docs, pageInfo, nil
+}
+
+func (m *Module) getPageLimit(dbAlias string, limit *int64) (int64, error) {
+	if limit != nil {
+		return *limit, nil
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+	dbInfo, err := m.getDBInfo(dbAlias)
+	if err != nil {
+		return 0, err
+	}
+	return dbInfo.Limit, nil
+}
+
+// countDocuments counts the documents matching the where clause of the read. Exact counts are estimated unless the
+// database allows them, while estimates fall back to exact counts only if the database allows them. The count is nil
+// if neither is possible.
+func (m *Module) countDocuments(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (*int64, string, error) {
+	countReq := func() *model.ReadRequest {
+		find := make(map[string]interface{}, len(req.Find))
+		for k, v := range req.Find {
+			find[k] = v
+		}
+		return &model.ReadRequest{Find: find, MatchWhere: req.MatchWhere, Operation: utils.Count, Options: &model.ReadOptions{}}
+	}
+
+	count, estimated, exactAllowed, err := m.estimateCount(ctx, dbAlias, col, countReq(), params, req.Options.TotalCount)
+	if err != nil {
+		return nil, "", err
+	}
+	if estimated {
+		return &count, model.TotalCountEstimated, nil
+	}
+	if !exactAllowed {
+		return nil, "", nil
+	}
+
+	result, _, err := m.Read(ctx, dbAlias, col, countReq(), params)
+	if err != nil {
+		return nil, "", err
+	}
+	n, ok := result.(int64)
+	if !ok {
+		return nil, "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to count the documents of (%s)", col), fmt.Errorf("count of type (%T) returned by the database", result), nil)
+	}
+	return &n, model.TotalCountExact, nil
+}
+
+// estimateCount estimates the number of documents matching the read unless an exact count was asked for and the
+// database allows it. It also returns whether the database allows exact counts.
+func (m *Module) estimateCount(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams, countType string) (int64, bool, bool, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return 0, false, false, err
+	}
+	dbInfo, err := m.getDBInfo(t.dbAlias)
+	if err != nil {
+		return 0, false, false, err
+	}
+	if countType == model.TotalCountExact && dbInfo.ExactCounts {
+		return 0, false, true, nil
+	}
+	if _, ok := m.getView(t.dbAlias, col); ok {
+		return 0, false, dbInfo.ExactCounts, nil
+	}
+
+	req.Find = t.scopeFind(req.Find)
+	dbType, err := m.getDBType(t.dbAlias)
+	if err != nil {
+		return 0, false, false, err
+	}
+	if err := schemaHelpers.AdjustWhereClause(ctx, t.dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
+		return 0, false, false, err
+	}
+
+	block, err := m.getCrudBlock(t.dbAlias)
+	if err != nil {
+		return 0, false, false, err
+	}
+	estimator, ok := unwrapCrud(block).(estimatingCrud)
+	if !ok {
+		return 0, false, dbInfo.ExactCounts, nil
+	}
+	count, estimated, err := estimator.EstimateCount(ctx, col, req)
+	if err != nil {
+		return 0, false, false, err
+	}
+	return count, estimated, dbInfo.ExactCounts, nil
+}
+
+func encodeCursor(offset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(offset, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(string(data), cursorPrefix) {
+		return 0, fmt.Errorf("cursor (%s) is not a page cursor", cursor)
+	}
+	offset, err := strconv.ParseInt(strings.TrimPrefix(string(data), cursorPrefix), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("cursor (%s) has an invalid offset", cursor)
+	}
+	return offset, nil
+}
@@ -0,0 +1,33 @@
+package crud
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func Test_decodeCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    int64
+		wantErr bool
+	}{
+		{name: "first document", cursor: encodeCursor(0), want: 0},
+		{name: "later document", cursor: encodeCursor(120), want: 120},
+		{name: "not base64", cursor: "offset:1", wantErr: true},
+		{name: "not a page cursor", cursor: base64.RawURLEncoding.EncodeToString([]byte("id:1")), wantErr: true},
+		{name: "negative offset", cursor: base64.RawURLEncoding.EncodeToString([]byte("offset:-1")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("decodeCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// Aggregate performs a redis aggregation
+func (r *Redis) Aggregate(ctx context.Context, col string, req *model.AggregateRequest) (interface{}, error) {
+	return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "aggregate operation not supported for selected database", nil, nil)
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// GetCollections returns collection / tables name of specified database
+func (r *Redis) GetCollections(ctx context.Context) ([]utils.DatabaseCollections, error) {
+	prefix := ""
+	if r.name != "" {
+		prefix = r.name + ":"
+	}
+
+	cols := make(map[string]bool)
+	iter := r.client.Scan(ctx, 0, prefix+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		arr := strings.SplitN(strings.TrimPrefix(iter.Val(), prefix), ":", 2)
+		if len(arr) == 2 {
+			cols[arr[0]] = true
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to query database to get tables in database (%s)", r.name), err, nil)
+	}
+
+	dbCols := make([]utils.DatabaseCollections, 0)
+	for col := range cols {
+		dbCols = append(dbCols, utils.DatabaseCollections{TableName: col})
+	}
+	return dbCols, nil
+}
+
+// DeleteCollection deletes collection / tables name of specified database
+func (r *Redis) DeleteCollection(ctx context.Context, col string) error {
+	keys, err := r.getKeys(ctx, col, nil)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "error deleting collection from redis", err, nil)
+	}
+
+	for len(keys) > 0 {
+		n := scanCount
+		if len(keys) < n {
+			n = len(keys)
+		}
+		if err := r.client.Del(ctx, keys[:n]...).Err(); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "error deleting collection from redis", err, nil)
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
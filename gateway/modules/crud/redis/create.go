@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Create inserts a document (or multiple when op is "all") into the database
+func (r *Redis) Create(ctx context.Context, col string, req *model.CreateRequest) (int64, error) {
+	if !isValidCol(col) {
+		return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid collection name (%s) provided", col), nil, nil)
+	}
+
+	var docs []interface{}
+	switch req.Operation {
+	case utils.One:
+		doc, ok := req.Document.(map[string]interface{})
+		if !ok {
+			return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to insert data into redis cannot assert document to map", nil, nil)
+		}
+		docs = []interface{}{doc}
+
+	case utils.All:
+		arr, ok := req.Document.([]interface{})
+		if !ok {
+			return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to insert data into redis cannot assert document to slice of interface", nil, nil)
+		}
+		docs = arr
+
+	default:
+		return 0, utils.ErrInvalidParams
+	}
+
+	var count int64
+	for _, v := range docs {
+		doc, ok := v.(map[string]interface{})
+		if !ok {
+			return count, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to insert data into redis cannot assert document to map", nil, nil)
+		}
+		id, ok := doc[fieldID]
+		if !ok {
+			return count, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to insert data _id not found in create request", nil, nil)
+		}
+
+		key := r.getKey(col, id)
+		err := r.watch(ctx, func(tx *redis.Tx) error {
+			exists, err := tx.Exists(ctx, key).Result()
+			if err != nil {
+				return err
+			}
+			if exists > 0 {
+				return fmt.Errorf("document with id (%v) already exists", id)
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				return writeDoc(ctx, pipe, key, doc, 0)
+			})
+			return err
+		}, key)
+		if err != nil {
+			return count, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to insert data into redis", err, nil)
+		}
+		count++
+	}
+	return count, nil
+}
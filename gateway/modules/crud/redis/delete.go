@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Delete deletes a document (or multiple when op is "all") from the database
+func (r *Redis) Delete(ctx context.Context, col string, req *model.DeleteRequest) (int64, error) {
+	switch req.Operation {
+	case utils.One, utils.All:
+	default:
+		return 0, utils.ErrInvalidParams
+	}
+
+	keys, err := r.getKeys(ctx, col, req.Find)
+	if err != nil {
+		return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read data from redis", err, nil)
+	}
+
+	var count int64
+	for _, key := range keys {
+		deleted := false
+		err := r.watch(ctx, func(tx *redis.Tx) error {
+			doc, _, err := r.loadDoc(ctx, tx, col, key)
+			if err != nil || doc == nil || !utils.Validate(string(model.Redis), req.Find, doc) {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Del(ctx, key)
+				return nil
+			})
+			deleted = err == nil
+			return err
+		}, key)
+		if err != nil {
+			return count, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to delete data from redis", err, nil)
+		}
+		if deleted {
+			count++
+			if req.Operation == utils.One {
+				break
+			}
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,301 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// The fields of the documents having a special meaning
+const (
+	fieldID     = "_id"
+	fieldTTL    = "_ttl"
+	fieldValue  = "value"
+	fieldValues = "values"
+)
+
+// The types of the keys the documents are stored as
+const (
+	typeNone   = "none"
+	typeString = "string"
+	typeHash   = "hash"
+	typeList   = "list"
+)
+
+// scanCount is the number of keys fetched in an iteration of a scan
+const scanCount = 100
+
+// maxWatchRetries is the number of times a transaction is attempted when the keys it watches keep getting modified
+const maxWatchRetries = 10
+
+// isValidCol checks that a collection name doesn't contain the separator of the keys or the characters of a redis glob
+// pattern, so that the keys of a collection can never match the keys of another collection
+func isValidCol(col string) bool {
+	return col != "" && !strings.ContainsAny(col, ":*?[]\\")
+}
+
+func (r *Redis) getColPrefix(col string) string {
+	if r.name == "" {
+		return col + ":"
+	}
+	return r.name + ":" + col + ":"
+}
+
+func (r *Redis) getKey(col string, id interface{}) string {
+	return r.getColPrefix(col) + fmt.Sprintf("%v", id)
+}
+
+// watch executes the function in a transaction watching the keys. The transaction is retried if any of the keys gets
+// modified before it commits.
+func (r *Redis) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	return retryWatch(func() error {
+		return r.client.Watch(ctx, fn, keys...)
+	})
+}
+
+// retryWatch retries a transaction as long as it fails because of a watched key, up to maxWatchRetries times
+func retryWatch(tx func() error) error {
+	var err error
+	for i := 0; i < maxWatchRetries; i++ {
+		if err = tx(); err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return err
+}
+
+// getKeys returns the keys of the documents which can match the find clause. The keys are looked up directly when the
+// find clause has the ids of the documents, else all the keys of the collection are scanned.
+func (r *Redis) getKeys(ctx context.Context, col string, find map[string]interface{}) ([]string, error) {
+	if !isValidCol(col) {
+		return nil, fmt.Errorf("invalid collection name (%s) provided", col)
+	}
+
+	switch id := find[fieldID].(type) {
+	case string:
+		return []string{r.getKey(col, id)}, nil
+	case map[string]interface{}:
+		if in, ok := id["$in"].([]interface{}); ok && len(id) == 1 {
+			keys := make([]string, len(in))
+			for i, v := range in {
+				keys[i] = r.getKey(col, v)
+			}
+			return keys, nil
+		}
+	}
+
+	keys := make([]string, 0)
+	iter := r.client.Scan(ctx, 0, r.getColPrefix(col)+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// loadDoc returns the document stored under the key along with the type of the key. The document is nil if the key
+// doesn't exist.
+func (r *Redis) loadDoc(ctx context.Context, client redis.Cmdable, col, key string) (map[string]interface{}, string, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	doc := map[string]interface{}{fieldID: strings.TrimPrefix(key, r.getColPrefix(col))}
+	switch keyType {
+	case typeNone:
+		return nil, keyType, nil
+
+	case typeString:
+		value, err := client.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return nil, typeNone, nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		doc[fieldValue] = decodeValue(value)
+
+	case typeHash:
+		fields, err := client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, "", err
+		}
+		if len(fields) == 0 {
+			return nil, typeNone, nil
+		}
+		for k, v := range fields {
+			doc[k] = decodeValue(v)
+		}
+
+	case typeList:
+		values, err := client.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return nil, "", err
+		}
+		arr := make([]interface{}, len(values))
+		for i, v := range values {
+			arr[i] = decodeValue(v)
+		}
+		doc[fieldValues] = arr
+
+	default:
+		return nil, "", fmt.Errorf("key (%s) is a %s which isn't supported - only strings, hashes and lists can be accessed", key, keyType)
+	}
+	return doc, keyType, nil
+}
+
+// writeDoc replaces the key with the document. The key expires after the ttl of the document if it has one, else
+// after the provided ttl unless it is zero or less.
+func writeDoc(ctx context.Context, pipe redis.Pipeliner, key string, doc map[string]interface{}, ttl time.Duration) error {
+	if _, p := doc[fieldTTL]; p {
+		var ok bool
+		if ttl, ok = ttlOf(doc); !ok {
+			return fmt.Errorf("%s of document (%v) should be a positive number of seconds", fieldTTL, doc[fieldID])
+		}
+	}
+
+	pipe.Del(ctx, key)
+	switch getDocType(doc) {
+	case typeString:
+		value, err := encodeValue(doc[fieldValue])
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, key, value, 0)
+
+	case typeList:
+		arr := doc[fieldValues].([]interface{})
+		if len(arr) == 0 {
+			return fmt.Errorf("%s of document (%v) cannot be empty", fieldValues, doc[fieldID])
+		}
+		values := make([]interface{}, len(arr))
+		for i, v := range arr {
+			value, err := encodeValue(v)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+		}
+		pipe.RPush(ctx, key, values...)
+
+	default:
+		fields := make([]interface{}, 0, 2*len(doc))
+		for k, v := range doc {
+			if k == fieldID || k == fieldTTL {
+				continue
+			}
+			value, err := encodeValue(v)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, k, value)
+		}
+		if len(fields) == 0 {
+			return fmt.Errorf("document (%v) needs at least one field besides %s", doc[fieldID], fieldID)
+		}
+		pipe.HSet(ctx, key, fields...)
+	}
+
+	if ttl > 0 {
+		pipe.PExpire(ctx, key, ttl)
+	}
+	return nil
+}
+
+// getDocType returns the type of the key a document is stored as
+func getDocType(doc map[string]interface{}) string {
+	fields := 0
+	for k := range doc {
+		if k != fieldID && k != fieldTTL {
+			fields++
+		}
+	}
+	if fields != 1 {
+		return typeHash
+	}
+	if _, p := doc[fieldValue]; p {
+		return typeString
+	}
+	if _, ok := doc[fieldValues].([]interface{}); ok {
+		return typeList
+	}
+	return typeHash
+}
+
+func encodeValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// decodeValue decodes a json encoded value. Values which aren't json, like the ones written by other clients of
+// redis, are returned as strings.
+func decodeValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
+
+// sortDocs sorts the documents in place. A field prefixed with '-' is sorted in the descending order.
+func sortDocs(docs []interface{}, fields []string) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		a, b := docs[i].(map[string]interface{}), docs[j].(map[string]interface{})
+		for _, field := range fields {
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimPrefix(field, "-")
+			c := compareValues(a[field], b[field])
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != desc
+		}
+		return false
+	})
+}
+
+// compareValues returns -1, 0 or 1 if a is less than, equal to or greater than b. Missing values are the smallest
+// and values of different types are compared by their json encoding.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	}
+
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return strings.Compare(string(x), string(y))
+}
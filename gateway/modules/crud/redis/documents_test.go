@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-test/deep"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestRedis_getKey(t *testing.T) {
+	tests := []struct {
+		name string
+		db   string
+		col  string
+		id   interface{}
+		want string
+	}{
+		{name: "With database name", db: "cache", col: "sessions", id: "1", want: "cache:sessions:1"},
+		{name: "Without database name", col: "sessions", id: "1", want: "sessions:1"},
+		{name: "Numeric id", db: "cache", col: "sessions", id: float64(12), want: "cache:sessions:12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Redis{name: tt.db}
+			if got := r.getKey(tt.col, tt.id); got != tt.want {
+				t.Errorf("getKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getDocType(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  map[string]interface{}
+		want string
+	}{
+		{name: "Value", doc: map[string]interface{}{"_id": "1", "value": "a"}, want: typeString},
+		{name: "Value with ttl", doc: map[string]interface{}{"_id": "1", "value": "a", "_ttl": float64(10)}, want: typeString},
+		{name: "Values", doc: map[string]interface{}{"_id": "1", "values": []interface{}{"a", "b"}}, want: typeList},
+		{name: "Values which aren't an array", doc: map[string]interface{}{"_id": "1", "values": "a"}, want: typeHash},
+		{name: "Value with other fields", doc: map[string]interface{}{"_id": "1", "value": "a", "name": "b"}, want: typeHash},
+		{name: "Fields", doc: map[string]interface{}{"_id": "1", "name": "a"}, want: typeHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDocType(tt.doc); got != tt.want {
+				t.Errorf("getDocType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decodeValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "String", value: "hello world"},
+		{name: "Number", value: float64(12.5)},
+		{name: "Bool", value: true},
+		{name: "Object", value: map[string]interface{}{"a": []interface{}{float64(1), "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := encodeValue(tt.value)
+			if err != nil {
+				t.Fatalf("encodeValue() error = %v", err)
+			}
+			if arr := deep.Equal(decodeValue(s), tt.value); len(arr) > 0 {
+				t.Errorf("decodeValue() differences = %v", arr)
+			}
+		})
+	}
+
+	// Values written by other clients aren't json encoded
+	if got := decodeValue("plain text"); got != "plain text" {
+		t.Errorf("decodeValue() = %v, want %v", got, "plain text")
+	}
+}
+
+func Test_sortDocs(t *testing.T) {
+	doc1 := map[string]interface{}{"_id": "1", "age": float64(30), "name": "b"}
+	doc2 := map[string]interface{}{"_id": "2", "age": float64(20), "name": "a"}
+	doc3 := map[string]interface{}{"_id": "3", "age": float64(30), "name": "a"}
+	doc4 := map[string]interface{}{"_id": "4"}
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   []interface{}
+	}{
+		{name: "Ascending", fields: []string{"age"}, want: []interface{}{doc4, doc2, doc1, doc3}},
+		{name: "Descending", fields: []string{"-age"}, want: []interface{}{doc1, doc3, doc2, doc4}},
+		{name: "Multiple fields", fields: []string{"-age", "name"}, want: []interface{}{doc3, doc1, doc2, doc4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := []interface{}{doc1, doc2, doc3, doc4}
+			sortDocs(docs, tt.fields)
+			if arr := deep.Equal(docs, tt.want); len(arr) > 0 {
+				t.Errorf("sortDocs() differences = %v", arr)
+			}
+		})
+	}
+}
+
+func Test_retryWatch(t *testing.T) {
+	failed := errors.New("document already exists")
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantTries int
+	}{
+		{name: "transaction committed", errs: []error{nil}, wantTries: 1},
+		{name: "watched key modified once", errs: []error{redis.TxFailedErr, nil}, wantTries: 2},
+		{name: "other errors aren't retried", errs: []error{failed}, wantErr: failed, wantTries: 1},
+		{name: "watched key keeps getting modified", wantErr: redis.TxFailedErr, wantTries: maxWatchRetries},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tries := 0
+			err := retryWatch(func() error {
+				tries++
+				if tries <= len(tt.errs) {
+					return tt.errs[tries-1]
+				}
+				return redis.TxFailedErr
+			})
+			if err != tt.wantErr || tries != tt.wantTries {
+				t.Errorf("retryWatch() = (%v) after %d tries, want (%v) after %d tries", err, tries, tt.wantErr, tt.wantTries)
+			}
+		})
+	}
+}
+
+func TestRedis_invalidCollection(t *testing.T) {
+	// The client is nil, hence any attempt to scan the keys of the collection would panic
+	r := &Redis{name: "cache", queryFetchLimit: new(int64)}
+	for _, col := range []string{"*", "user?", "users:admin", "[a-z]*", `users\*`, ""} {
+		t.Run(col, func(t *testing.T) {
+			if _, res, _, _, err := r.Read(context.Background(), col, &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{}}); err == nil || res != nil {
+				t.Errorf("Read() returned (%v) for collection (%s), want an error", res, col)
+			}
+			if _, err := r.Delete(context.Background(), col, &model.DeleteRequest{Operation: utils.All, Find: map[string]interface{}{}}); err == nil {
+				t.Errorf("Delete() succeeded for collection (%s), want an error", col)
+			}
+			if _, err := r.Create(context.Background(), col, &model.CreateRequest{Operation: utils.One, Document: map[string]interface{}{"_id": "1", "value": "a"}}); err == nil {
+				t.Errorf("Create() succeeded for collection (%s), want an error", col)
+			}
+		})
+	}
+}
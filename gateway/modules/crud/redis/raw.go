@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// RawQuery runs a redis command. The arguments are appended to the words of the query, so that values containing
+// spaces can be passed as arguments.
+func (r *Redis) RawQuery(ctx context.Context, query string, isDebug bool, args []interface{}) (int64, interface{}, *model.SQLMetaData, error) {
+	cmd := make([]interface{}, 0)
+	for _, word := range strings.Fields(query) {
+		cmd = append(cmd, word)
+	}
+	cmd = append(cmd, args...)
+	if len(cmd) == 0 {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to run raw query on redis empty command provided", nil, nil)
+	}
+
+	result, err := r.client.Do(ctx, cmd...).Result()
+	if err == redis.Nil {
+		return 0, nil, nil, nil
+	}
+	if err != nil {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to run raw query on redis", err, nil)
+	}
+
+	var count int64 = 1
+	if arr, ok := result.([]interface{}); ok {
+		count = int64(len(arr))
+	}
+	return count, result, nil, nil
+}
+
+// CreateDatabaseIfNotExist creates a project if none exist
+func (r *Redis) CreateDatabaseIfNotExist(ctx context.Context, project string) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create database operation cannot be performed over selected database", nil, nil)
+}
+
+// RawBatch performs a batch operation for schema creation
+// NOTE: not to be exposed externally
+func (r *Redis) RawBatch(ctx context.Context, batchedQueries []string) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create raw batch operation cannot be performed over selected database", nil, nil)
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Read queries document(s) from the database. The documents are sorted and paginated in the gateway since redis
+// doesn't index the fields of the documents.
+func (r *Redis) Read(ctx context.Context, col string, req *model.ReadRequest) (int64, interface{}, map[string]map[string]string, *model.SQLMetaData, error) {
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+	if req.Options.Limit == nil {
+		req.Options.Limit = r.queryFetchLimit
+		req.Options.HasOptions = true
+	}
+
+	switch req.Operation {
+	case utils.All, utils.One, utils.Count:
+	default:
+		return 0, nil, nil, nil, utils.ErrInvalidParams
+	}
+
+	results, err := r.find(ctx, col, req.Find)
+	if err != nil {
+		return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read data from redis", err, nil)
+	}
+
+	if req.Operation == utils.Count {
+		return int64(len(results)), nil, nil, nil, nil
+	}
+
+	sortFields := req.Options.Sort
+	if len(sortFields) == 0 {
+		sortFields = []string{fieldID}
+	}
+	sortDocs(results, sortFields)
+
+	if skip := req.Options.Skip; skip != nil {
+		if *skip >= int64(len(results)) {
+			results = []interface{}{}
+		} else if *skip > 0 {
+			results = results[*skip:]
+		}
+	}
+	if limit := req.Options.Limit; limit != nil && *limit >= 0 && *limit < int64(len(results)) {
+		results = results[:*limit]
+	}
+
+	if req.Options.Debug {
+		for _, result := range results {
+			result.(map[string]interface{})["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+	}
+
+	if req.Operation == utils.One {
+		if len(results) == 0 {
+			return 0, nil, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "No match found for specified find clause", nil, nil)
+		}
+		return 1, results[0], nil, nil, nil
+	}
+
+	return int64(len(results)), results, nil, nil, nil
+}
+
+// find returns the documents of the collection matching the find clause
+func (r *Redis) find(ctx context.Context, col string, find map[string]interface{}) ([]interface{}, error) {
+	keys, err := r.getKeys(ctx, col, find)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, 0)
+	for _, key := range keys {
+		doc, _, err := r.loadDoc(ctx, r.client, col, key)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil && utils.Validate(string(model.Redis), find, doc) {
+			results = append(results, doc)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Redis holds the redis client. The documents of a collection are stored under the keys `<name>:<col>:<_id>`, where
+// the name is the name of the database. It is left out of the keys if empty.
+//
+// A document having just a `value` field besides its `_id` is stored as a string, a document having just a `values`
+// array is stored as a list, and every other document is stored as a hash. The values are stored json encoded. The
+// `_ttl` field of a document sets the number of seconds after which it expires.
+type Redis struct {
+	queryFetchLimit *int64
+	enabled         bool
+	connection      string
+	name            string
+	driverConf      config.DriverConfig
+	client          *redis.Client
+}
+
+// Init initialises a new redis instance
+func Init(enabled bool, connection, name string, driverConf config.DriverConfig) (r *Redis, err error) {
+	r = &Redis{enabled: enabled, connection: connection, name: name, driverConf: driverConf}
+
+	if r.enabled {
+		err = r.connect()
+	}
+
+	return
+}
+
+// Close gracefully the Redis client
+func (r *Redis) Close() error {
+	if r.client != nil {
+		return r.client.Close()
+	}
+
+	return nil
+}
+
+// IsSame checks if we've got the same connection string
+func (r *Redis) IsSame(conn, dbName string, driverConf config.DriverConfig) bool {
+	return r.connection == conn && dbName == r.name && driverConf.MaxConn == r.driverConf.MaxConn && driverConf.MinConn == r.driverConf.MinConn && driverConf.MaxIdleTimeout == r.driverConf.MaxIdleTimeout
+}
+
+// IsClientSafe checks whether database is enabled and connected
+func (r *Redis) IsClientSafe(ctx context.Context) error {
+	if !r.enabled {
+		return utils.ErrDatabaseDisabled
+	}
+
+	if r.client == nil {
+		if err := r.connect(); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to connect to redis", err, nil)
+		}
+	}
+
+	return nil
+}
+
+func (r *Redis) connect() error {
+	if err := r.Close(); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to close previous redis connection", err, nil)
+	}
+
+	opts := &redis.Options{Addr: r.connection}
+	if strings.HasPrefix(r.connection, "redis://") || strings.HasPrefix(r.connection, "rediss://") {
+		var err error
+		opts, err = redis.ParseURL(r.connection)
+		if err != nil {
+			return err
+		}
+	}
+
+	maxConn := r.driverConf.MaxConn
+	if maxConn == 0 {
+		maxConn = 100
+	}
+
+	maxIdleTimeout := r.driverConf.MaxIdleTimeout
+	if maxIdleTimeout == 0 {
+		maxIdleTimeout = 60 * 5 * 1000
+	}
+
+	opts.PoolSize = maxConn
+	opts.MinIdleConns = int(r.driverConf.MinConn)
+	opts.IdleTimeout = time.Duration(maxIdleTimeout) * time.Millisecond
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return err
+	}
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Successfully connected to redis", nil)
+	r.client = client
+	return nil
+}
+
+// GetDBType returns the dbType of the crud block
+func (r *Redis) GetDBType() model.DBType {
+	return model.Redis
+}
+
+// SetQueryFetchLimit sets data fetch limit
+func (r *Redis) SetQueryFetchLimit(limit int64) {
+	r.queryFetchLimit = &limit
+}
+
+// SetProjectAESKey sets aes key
+func (r *Redis) SetProjectAESKey(aesKey []byte) {
+}
+
+// GetPoolStats returns the stats of the connection pool
+func (r *Redis) GetPoolStats() model.DBPoolStats {
+	if r.client == nil {
+		return model.DBPoolStats{}
+	}
+
+	stats := r.client.PoolStats()
+	return model.DBPoolStats{
+		MaxOpenConnections: r.client.Options().PoolSize,
+		OpenConnections:    int(stats.TotalConns),
+		InUse:              int(stats.TotalConns - stats.IdleConns),
+		Idle:               int(stats.IdleConns),
+		WaitCount:          int64(stats.Timeouts),
+	}
+}
+
+// GetConnectionState : function to check connection state
+func (r *Redis) GetConnectionState(ctx context.Context) bool {
+	if !r.enabled || r.client == nil {
+		return false
+	}
+
+	// Ping to check if connection is established
+	return r.client.Ping(ctx).Err() == nil
+}
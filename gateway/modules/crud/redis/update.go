@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Update updates the document(s) which match the condition provided.
+func (r *Redis) Update(ctx context.Context, col string, req *model.UpdateRequest) (int64, error) {
+	switch req.Operation {
+	case utils.One, utils.All, utils.Upsert:
+	default:
+		return 0, utils.ErrInvalidParams
+	}
+
+	keys, err := r.getKeys(ctx, col, req.Find)
+	if err != nil {
+		return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read data from redis", err, nil)
+	}
+
+	var count int64
+	for _, key := range keys {
+		updated := false
+		err := r.watch(ctx, func(tx *redis.Tx) error {
+			doc, _, err := r.loadDoc(ctx, tx, col, key)
+			if err != nil || doc == nil || !utils.Validate(string(model.Redis), req.Find, doc) {
+				return err
+			}
+			if err := applyUpdate(doc, req.Update); err != nil {
+				return err
+			}
+
+			// Keep the remaining time to live of the key unless the update sets a new one
+			ttl, err := tx.PTTL(ctx, key).Result()
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				return writeDoc(ctx, pipe, key, doc, ttl)
+			})
+			updated = err == nil
+			return err
+		}, key)
+		if err != nil {
+			return count, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update data in redis", err, nil)
+		}
+		if updated {
+			count++
+			if req.Operation == utils.One {
+				break
+			}
+		}
+	}
+
+	if req.Operation == utils.Upsert && count == 0 {
+		n, err := r.upsert(ctx, col, req)
+		if err != nil {
+			return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to upsert in redis", err, nil)
+		}
+		return n, nil
+	}
+	return count, nil
+}
+
+// upsert inserts the document made up of the find clause and the update. The document is updated instead if it has
+// been inserted by someone else after the find clause didn't match any document. The document is read and written in
+// a single transaction, so that concurrent upserts don't fail with a conflict.
+func (r *Redis) upsert(ctx context.Context, col string, req *model.UpdateRequest) (int64, error) {
+	doc := map[string]interface{}{}
+	for k, v := range req.Find {
+		// Only the fields being compared for equality make up the document
+		if _, ok := v.(map[string]interface{}); !ok {
+			doc[k] = v
+		}
+	}
+	id, ok := doc[fieldID]
+	if !ok {
+		return 0, errors.New("_id must be provided in the find clause to upsert")
+	}
+
+	key := r.getKey(col, id)
+	err := r.watch(ctx, func(tx *redis.Tx) error {
+		// The document is copied since the transaction might be retried
+		newDoc := make(map[string]interface{}, len(doc))
+		for k, v := range doc {
+			newDoc[k] = v
+		}
+
+		var ttl time.Duration
+		current, _, err := r.loadDoc(ctx, tx, col, key)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			if !utils.Validate(string(model.Redis), req.Find, current) {
+				return fmt.Errorf("document with id (%v) already exists", id)
+			}
+			if ttl, err = tx.PTTL(ctx, key).Result(); err != nil {
+				return err
+			}
+			newDoc = current
+		}
+		if err := applyUpdate(newDoc, req.Update); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return writeDoc(ctx, pipe, key, newDoc, ttl)
+		})
+		return err
+	}, key)
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// applyUpdate applies the update operators to the top level fields of a document. The operators $set, $unset, $inc
+// and $push are supported.
+func applyUpdate(doc, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return errors.New("no update operators provided")
+	}
+	for op, v := range update {
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("value of update operator (%s) should be an object", op)
+		}
+
+		for key, value := range fields {
+			if key == fieldID {
+				return fmt.Errorf("field (%s) cannot be updated", fieldID)
+			}
+
+			switch op {
+			case "$set":
+				doc[key] = value
+
+			case "$unset":
+				delete(doc, key)
+
+			case "$inc":
+				by, ok := toFloat(value)
+				if !ok {
+					return fmt.Errorf("value to increment field (%s) by should be a number", key)
+				}
+				current, ok := toFloat(doc[key])
+				if !ok && doc[key] != nil {
+					return fmt.Errorf("field (%s) is not a number", key)
+				}
+				doc[key] = current + by
+
+			case "$push":
+				var arr []interface{}
+				if current, p := doc[key]; p {
+					if arr, ok = current.([]interface{}); !ok {
+						return fmt.Errorf("field (%s) is not an array", key)
+					}
+				}
+				doc[key] = append(arr, value)
+
+			default:
+				return fmt.Errorf("update operator (%s) is not supported by redis", op)
+			}
+		}
+	}
+	return nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// ttlOf returns the time to live of a document in the ttl field
+func ttlOf(doc map[string]interface{}) (time.Duration, bool) {
+	seconds, ok := toFloat(doc[fieldTTL])
+	if !ok || seconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func Test_applyUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     map[string]interface{}
+		update  map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "Set and unset",
+			doc:    map[string]interface{}{"_id": "1", "name": "a", "age": float64(1)},
+			update: map[string]interface{}{"$set": map[string]interface{}{"name": "b"}, "$unset": map[string]interface{}{"age": ""}},
+			want:   map[string]interface{}{"_id": "1", "name": "b"},
+		},
+		{
+			name:   "Increment",
+			doc:    map[string]interface{}{"_id": "1", "hits": float64(1)},
+			update: map[string]interface{}{"$inc": map[string]interface{}{"hits": float64(2), "misses": float64(1)}},
+			want:   map[string]interface{}{"_id": "1", "hits": float64(3), "misses": float64(1)},
+		},
+		{
+			name:    "Increment a field which isn't a number",
+			doc:     map[string]interface{}{"_id": "1", "hits": "a"},
+			update:  map[string]interface{}{"$inc": map[string]interface{}{"hits": float64(2)}},
+			wantErr: true,
+		},
+		{
+			name:   "Push",
+			doc:    map[string]interface{}{"_id": "1", "values": []interface{}{"a"}},
+			update: map[string]interface{}{"$push": map[string]interface{}{"values": "b", "tags": "c"}},
+			want:   map[string]interface{}{"_id": "1", "values": []interface{}{"a", "b"}, "tags": []interface{}{"c"}},
+		},
+		{
+			name:    "Update the id",
+			doc:     map[string]interface{}{"_id": "1"},
+			update:  map[string]interface{}{"$set": map[string]interface{}{"_id": "2"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported operator",
+			doc:     map[string]interface{}{"_id": "1"},
+			update:  map[string]interface{}{"$min": map[string]interface{}{"age": float64(2)}},
+			wantErr: true,
+		},
+		{
+			name:    "No operators",
+			doc:     map[string]interface{}{"_id": "1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := applyUpdate(tt.doc, tt.update)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if arr := deep.Equal(tt.doc, tt.want); len(arr) > 0 {
+				t.Errorf("applyUpdate() differences = %v", arr)
+			}
+		})
+	}
+}
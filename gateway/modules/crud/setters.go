@@ -11,6 +11,7 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
@@ -23,6 +24,12 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 		return err
 	}
 
+	for _, v := range crud {
+		if err := validateTenancyConfig(v.DbAlias, v.Tenancy, crud); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid tenancy config provided", err, map[string]interface{}{"project": project})
+		}
+	}
+
 	m.project = project
 
 	for dbAlias, v := range m.blocks {
@@ -38,6 +45,8 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 			// Database that has been removed, close the db connections to free connection pool
 			_ = v.Close()
 			delete(m.blocks, dbAlias)
+			delete(m.databaseConfigs, dbAlias)
+			delete(m.queryStats, dbAlias)
 		}
 	}
 
@@ -71,8 +80,21 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 			if err != nil {
 				return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to fetch connection string secret from runner", err, map[string]interface{}{"project": project})
 			}
+		} else if secrets.IsReference(v.Conn) && m.resolveSecret != nil {
+			var err error
+			connectionString, err = m.resolveSecret(context.TODO(), v.Conn)
+			if err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to resolve connection string secret", err, map[string]interface{}{"project": project, "dbAlias": v.DbAlias})
+			}
 		}
 
+		stats, p := m.queryStats[blockKey]
+		if !p {
+			stats = newQueryStats()
+			m.queryStats[blockKey] = stats
+		}
+		stats.setThreshold(v.SlowQueryThreshold)
+
 		if block, p := m.blocks[blockKey]; p {
 
 			block.SetQueryFetchLimit(v.Limit)
@@ -94,6 +116,9 @@ func (m *Module) SetConfig(project string, crud config.DatabaseConfigs) error {
 
 		v.Type = strings.TrimPrefix(v.Type, "sql-")
 		c, err = m.initBlock(model.DBType(v.Type), v.Enabled, connectionString, v.DBName, v.DriverConf)
+		if err == nil {
+			c = newInstrumentedCrud(v.DBName, stats, c)
+		}
 
 		if v.Enabled {
 			if err != nil {
@@ -155,6 +180,14 @@ func (m *Module) SetGetSecrets(function utils.GetSecrets) {
 	m.getSecrets = function
 }
 
+// SetResolveSecret sets the function to resolve secrets from external secret managers
+func (m *Module) SetResolveSecret(function utils.ResolveSecret) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.resolveSecret = function
+}
+
 // SetHooks sets the internal hooks
 func (m *Module) SetHooks(metricHook model.MetricCrudHook) {
 	m.metricHook = metricHook
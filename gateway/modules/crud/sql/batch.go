@@ -25,7 +25,7 @@ func (s *SQL) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, erro
 			if err != nil {
 				return counts, err
 			}
-			res, err := doExecContext(ctx, sqlQuery, args, tx)
+			res, err := s.doExecContext(ctx, sqlQuery, args, tx)
 			if err != nil {
 				return counts, err
 			}
@@ -36,7 +36,7 @@ func (s *SQL) Batch(ctx context.Context, req *model.BatchRequest) ([]int64, erro
 			if err != nil {
 				return counts, err
 			}
-			res, err := doExecContext(ctx, sqlQuery, args, tx)
+			res, err := s.doExecContext(ctx, sqlQuery, args, tx)
 			if err != nil {
 				return counts, err
 			}
@@ -0,0 +1,84 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// EstimateCount returns the number of rows matching the where clause as estimated by the query planner. It is false
+// if the database doesn't provide estimates.
+func (s *SQL) EstimateCount(ctx context.Context, col string, req *model.ReadRequest) (int64, bool, error) {
+	switch model.DBType(s.dbType) {
+	case model.Postgres, model.MySQL:
+	default:
+		return 0, false, nil
+	}
+
+	query := goqu.Dialect(s.dbType).From(s.getColName(col)).Prepared(true).Select(goqu.Star())
+	query = s.generateWhereClause(ctx, query, req.Find, req.MatchWhere, getQueryTables(col, nil))
+	sqlString, args, err := query.ToSQL()
+	if err != nil {
+		return 0, false, err
+	}
+	sqlString = strings.Replace(sqlString, "\"", "", -1)
+
+	if model.DBType(s.dbType) == model.Postgres {
+		var plan []byte
+		if err := s.getClient().QueryRowxContext(ctx, "EXPLAIN (FORMAT JSON) "+sqlString, args...).Scan(&plan); err != nil {
+			return 0, false, err
+		}
+		var plans []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal(plan, &plans); err != nil || len(plans) == 0 {
+			return 0, false, fmt.Errorf("unable to parse the query plan of table (%s)", col)
+		}
+		return int64(plans[0].Plan.Rows), true, nil
+	}
+
+	rows, err := s.getClient().QueryxContext(ctx, "EXPLAIN "+sqlString, args...)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		return 0, false, fmt.Errorf("unable to get the query plan of table (%s)", col)
+	}
+	plan := map[string]interface{}{}
+	if err := rows.MapScan(plan); err != nil {
+		return 0, false, err
+	}
+
+	// The rows examined are narrowed down by the percentage of them estimated to match the where clause
+	estimate, ok := parsePlanNumber(plan["rows"])
+	if !ok {
+		return 0, false, nil
+	}
+	if filtered, ok := parsePlanNumber(plan["filtered"]); ok {
+		estimate = estimate * filtered / 100
+	}
+	return int64(estimate), true, nil
+}
+
+// parsePlanNumber parses a number of a query plan, which the driver returns either as a number or as text
+func parsePlanNumber(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case []byte:
+		f, err := strconv.ParseFloat(string(val), 64)
+		return f, err == nil
+	case nil:
+		return 0, false
+	default:
+		f, err := strconv.ParseFloat(fmt.Sprint(val), 64)
+		return f, err == nil
+	}
+}
@@ -20,7 +20,7 @@ func (s *SQL) Delete(ctx context.Context, col string, req *model.DeleteRequest)
 	if err != nil {
 		return 0, err
 	}
-	res, err := doExecContext(ctx, sqlString, args, s.getClient())
+	res, err := s.doExecContext(ctx, sqlString, args, s.getClient())
 	if err != nil {
 		return 0, err
 	}
@@ -42,7 +42,7 @@ func (s *SQL) generateDeleteQuery(ctx context.Context, req *model.DeleteRequest,
 
 	if req.Find != nil {
 		// Get the where clause from query object
-		query = s.generateWhereClause(ctx, query, req.Find, nil)
+		query = s.generateWhereClause(ctx, query, req.Find, nil, map[string]struct{}{col: {}})
 	}
 
 	// Generate SQL string and arguments
@@ -32,7 +32,7 @@ select a.table_schema  AS 'TABLE_SCHEMA',
        a.table_name AS 'TABLE_NAME',
 
        a.column_name AS 'COLUMN_NAME',
-       a.data_type 'DATA_TYPE',
+       IF(a.data_type = 'enum', a.column_type, a.data_type) 'DATA_TYPE',
        a.is_nullable AS 'IS_NULLABLE',
        a.ordinal_position AS 'ORDINAL_POSITION',
        CASE
@@ -71,7 +71,11 @@ where a.table_name= ? and a.table_schema= ? ;
        c.table_name AS "TABLE_NAME",
 
        c.column_name AS "COLUMN_NAME",
-       c.data_type "DATA_TYPE",
+       case when c.data_type = 'USER-DEFINED' then (select format_type(a.atttypid, a.atttypmod) from pg_attribute a
+           where a.attrelid = format('%I.%I', c.table_schema, c.table_name)::regclass and a.attname = c.column_name)
+           when exists(select 1 from pg_constraint k where k.conrelid = format('%I.%I', c.table_schema, c.table_name)::regclass
+               and k.conname = lower(format('enum_check_%s_%s', c.table_name, c.column_name))) then 'enum'
+           else c.data_type end AS "DATA_TYPE",
        c.is_nullable AS "IS_NULLABLE",
        c.ordinal_position AS "ORDINAL_POSITION",
        SPLIT_PART(REPLACE(coalesce(c.column_default,''),'''',''), '::', 1) AS "DEFAULT",
@@ -112,7 +116,9 @@ select c.table_schema AS 'TABLE_SCHEMA',
        c.table_name  AS 'TABLE_NAME',
 
        c.column_name AS 'COLUMN_NAME',
-       case when upper(ckc.check_clause) like '%ISJSON%' then 'json' else c.data_type end    AS 'DATA_TYPE',
+       case when upper(ckc.check_clause) like '%ISJSON%' then 'json'
+           when ckc.constraint_name = concat('enum_check_', c.table_name, '_', c.column_name) then 'enum'
+           else c.data_type end    AS 'DATA_TYPE',
        c.is_nullable AS 'IS_NULLABLE',
        c.ordinal_position AS 'ORDINAL_POSITION',
        CASE
@@ -208,7 +214,8 @@ select b.table_schema AS 'TABLE_SCHEMA',
        b.seq_in_index AS 'SEQ_IN_INDEX',
 	   case when b.collation = "A" then "asc" else "desc" end as SORT,
        case when b.non_unique=0 then true else false end 'IS_UNIQUE',
-       case when upper(b.index_name)='PRIMARY' then 1 else 0 end 'IS_PRIMARY'
+       case when upper(b.index_name)='PRIMARY' then 1 else 0 end 'IS_PRIMARY',
+       '' AS 'WHERE_CLAUSE'
 from INFORMATION_SCHEMA.STATISTICS  b
 where b.table_schema= ? and b.table_name= ?;`
 
@@ -221,7 +228,8 @@ where b.table_schema= ? and b.table_name= ?;`
     array_position(i.indkey, b.attnum)+1 "SEQ_IN_INDEX",
 	case when i.indoption[array_position(i.indkey, b.attnum)] = 0 then 'asc' else 'desc' END AS "SORT",
     i.indisunique AS "IS_UNIQUE",
-    i.indisprimary "IS_PRIMARY"
+    i.indisprimary "IS_PRIMARY",
+    coalesce(pg_get_expr(i.indpred, i.indrelid), '') AS "WHERE_CLAUSE"
 from pg_class a
          left join pg_namespace n on n.oid = a.relnamespace
          left join pg_index i on a.oid = i.indexrelid and a.relkind='i' and i.indisvalid = true
@@ -238,7 +246,8 @@ select
     d.index_key AS 'SEQ_IN_INDEX',
     lower(d.index_sort_order) AS 'SORT',
     case when i.is_unique = 1 then 'true' else 'false' end AS 'IS_UNIQUE',
-    case when i.is_primary_key = 1 then 'true' else 'false' end AS 'IS_PRIMARY'
+    case when i.is_primary_key = 1 then 'true' else 'false' end AS 'IS_PRIMARY',
+    coalesce(i.filter_definition, '') AS 'WHERE_CLAUSE'
 from sys.objects t
          inner join sys.indexes i
                     on t.object_id = i.object_id
@@ -11,6 +11,7 @@ import (
 	"time"
 	"unicode"
 
+	mssql "github.com/denisenkom/go-mssqldb"
 	"github.com/spaceuptech/helpers"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
@@ -18,9 +19,10 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/geo"
 )
 
-func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin bool) goqu.Expression {
+func (s *SQL) generator(ctx context.Context, find map[string]interface{}, tables map[string]struct{}, isJoin bool) goqu.Expression {
 	array := []goqu.Expression{}
 	for k, v := range find {
 		if strings.HasPrefix(k, "$or") {
@@ -36,7 +38,7 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 					continue
 				}
 
-				exp := s.generator(ctx, f2, isJoin)
+				exp := s.generator(ctx, f2, tables, isJoin)
 				orFinalArray = append(orFinalArray, exp)
 			}
 
@@ -44,6 +46,11 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 			continue
 		}
 
+		if column, path, ok := splitJSONPath(k, tables); ok && !isJoin {
+			array = append(array, s.generateJSONPathExpression(ctx, column, path, v))
+			continue
+		}
+
 		val, isObj := v.(map[string]interface{})
 		if isObj {
 			for k2, v2 := range val {
@@ -96,6 +103,9 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 
 				case "$nin":
 					array = append(array, goqu.I(k).NotIn(v2))
+
+				case geo.OpNear, geo.OpGeoWithin, geo.OpGeoBox:
+					array = append(array, s.generateGeoExpression(ctx, k, k2, v2))
 				}
 			}
 		} else {
@@ -109,16 +119,68 @@ func (s *SQL) generator(ctx context.Context, find map[string]interface{}, isJoin
 	return goqu.And(array...)
 }
 
-func (s *SQL) generateWhereClause(ctx context.Context, q *goqu.SelectDataset, find map[string]interface{}, matchWhere []map[string]interface{}) (query *goqu.SelectDataset) {
+// generateGeoExpression translates a geospatial operator to its PostGIS counterpart. Distances are measured on the
+// spheroid by casting the column to geography. Operators which cannot be translated match no rows, since dropping
+// the filter would return rows which weren't asked for.
+func (s *SQL) generateGeoExpression(ctx context.Context, field, op string, value interface{}) goqu.Expression {
+	noMatch := goqu.L("1 = 0")
+	if s.dbType != string(model.Postgres) {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Geospatial operator (%s) is not supported for database (%s)", op, s.dbType), nil, nil)
+		return noMatch
+	}
+
+	col := goqu.I(field)
+	switch op {
+	case geo.OpNear:
+		near, err := geo.ParseNear(value)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid value provided for $near", err, nil)
+			return noMatch
+		}
+		point := goqu.L("ST_SetSRID(ST_MakePoint(?, ?), ?)::geography", near.Lng, near.Lat, geo.SRID)
+		exps := make([]goqu.Expression, 0, 2)
+		if near.MaxDistance > 0 {
+			exps = append(exps, goqu.L("ST_DWithin(?::geography, ?, ?)", col, point, near.MaxDistance))
+		}
+		if near.MinDistance > 0 {
+			exps = append(exps, goqu.L("NOT ST_DWithin(?::geography, ?, ?)", col, point, near.MinDistance))
+		}
+		return goqu.And(exps...)
+
+	case geo.OpGeoWithin:
+		polygon, err := geo.ParseWithin(value)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid value provided for $geoWithin", err, nil)
+			return noMatch
+		}
+		data, err := json.Marshal(polygon)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "error marshalling $geoWithin data", err, nil)
+			return noMatch
+		}
+		return goqu.L("ST_Within(?::geometry, ST_SetSRID(ST_GeomFromGeoJSON(?), ?))", col, string(data), geo.SRID)
+
+	case geo.OpGeoBox:
+		box, err := geo.ParseBox(value)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid value provided for $geoBox", err, nil)
+			return noMatch
+		}
+		return goqu.L("ST_Within(?::geometry, ST_MakeEnvelope(?, ?, ?, ?, ?))", col, box.MinLng, box.MinLat, box.MaxLng, box.MaxLat, geo.SRID)
+	}
+	return noMatch
+}
+
+func (s *SQL) generateWhereClause(ctx context.Context, q *goqu.SelectDataset, find map[string]interface{}, matchWhere []map[string]interface{}, tables map[string]struct{}) (query *goqu.SelectDataset) {
 	query = q
 
 	exps := make([]goqu.Expression, len(matchWhere))
 	for i, f := range matchWhere {
-		exps[i] = s.generator(ctx, f, false)
+		exps[i] = s.generator(ctx, f, tables, false)
 	}
 
 	if len(find) > 0 {
-		exp := s.generator(ctx, find, false)
+		exp := s.generator(ctx, find, tables, false)
 		exps = append(exps, exp)
 	}
 
@@ -193,7 +255,21 @@ func mysqlTypeCheck(ctx context.Context, dbType model.DBType, types []*sql.Colum
 				if err := json.Unmarshal(v, &val); err == nil {
 					mapping[colType.Name()] = val
 				}
-			case "VARCHAR", "CHAR", "TEXT", "NAME", "BPCHAR":
+			case "":
+				// Postgres doesn't report the name of types added by extensions. Columns of the PostGIS geometry
+				// and geography types are returned as hex encoded EWKB which gets converted to GeoJSON.
+				if dbType == model.Postgres {
+					if val, ok := geo.DecodeHexEWKB(string(v)); ok {
+						mapping[colType.Name()] = val
+					}
+				}
+			case "UNIQUEIDENTIFIER":
+				// SQL server stores the first three groups of a uuid in little endian order
+				var id mssql.UniqueIdentifier
+				if err := id.Scan(v); err == nil {
+					mapping[colType.Name()] = strings.ToLower(id.String())
+				}
+			case "VARCHAR", "CHAR", "TEXT", "NAME", "BPCHAR", "UUID":
 				// NOTE: The NAME data type is only valid for Postgres database, as it exists for Postgres only (Name is a 63 byte (varchar) type used for storing system identifiers.)
 				val, ok := mapping[colType.Name()].([]byte)
 				if ok {
@@ -209,7 +285,10 @@ func mysqlTypeCheck(ctx context.Context, dbType model.DBType, types []*sql.Colum
 				if err != nil {
 					helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error:%v", err), nil)
 				}
-			case "DECIMAL", "NUMERIC", "FLOAT":
+			case "DECIMAL", "NUMERIC":
+				// Decimals are encoded as json numbers without converting them to floats to retain their precision
+				mapping[colType.Name()] = json.Number(v)
+			case "FLOAT":
 				mapping[colType.Name()], err = strconv.ParseFloat(string(v), 64)
 				if err != nil {
 					helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Error:%v", err), nil)
@@ -250,9 +329,18 @@ func mysqlTypeCheck(ctx context.Context, dbType model.DBType, types []*sql.Colum
 	}
 }
 
-func (s *SQL) processJoins(ctx context.Context, query *goqu.SelectDataset, join []*model.JoinOption, sel map[string]int32, isAggregate bool) (*goqu.SelectDataset, error) {
+func (s *SQL) processJoins(ctx context.Context, query *goqu.SelectDataset, join []*model.JoinOption, tables map[string]struct{}, sel map[string]int32, selArray *[]interface{}, isAggregate bool) (*goqu.SelectDataset, error) {
 	for _, j := range join {
-		on := s.generator(ctx, j.On, true)
+		if j.Strategy == model.JoinStrategyJSON {
+			q, err := s.processJSONJoin(ctx, query, j, sel, selArray, isAggregate)
+			if err != nil {
+				return nil, err
+			}
+			query = q
+			continue
+		}
+
+		on := s.generator(ctx, j.On, tables, true)
 		switch j.Type {
 		case "", "LEFT":
 			query = query.LeftJoin(goqu.T(s.getColName(j.Table)), goqu.On(on))
@@ -276,7 +364,7 @@ func (s *SQL) processJoins(ctx context.Context, query *goqu.SelectDataset, join
 		}
 
 		if j.Join != nil {
-			q, err := s.processJoins(ctx, query, j.Join, sel, isAggregate)
+			q, err := s.processJoins(ctx, query, j.Join, tables, sel, selArray, isAggregate)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// getJoinAlias returns the name the result of a join is returned as
+func getJoinAlias(j *model.JoinOption) string {
+	if j.As != "" {
+		return j.As
+	}
+	return j.Table
+}
+
+// getJSONJoinColumn returns the column the aggregated rows of a join are selected as. Postgres folds unquoted names
+// to lower case, hence the column is always in lower case.
+func getJSONJoinColumn(j *model.JoinOption) string {
+	return strings.ToLower(getJoinAlias(j))
+}
+
+// processJSONJoin left joins the rows of the joined table aggregated into a json array for each value of the join
+// columns. For example, the orders of the users are joined with:
+//
+//	LEFT JOIN (SELECT json_agg(json_build_object('id', orders.id)) AS value, orders.user_id AS __key0 FROM orders
+//	GROUP BY orders.user_id) AS orders__json ON users.id = orders__json.__key0
+//
+// The selected columns of the joined table are moved from the select clause into the aggregation.
+func (s *SQL) processJSONJoin(ctx context.Context, query *goqu.SelectDataset, j *model.JoinOption, sel map[string]int32, selArray *[]interface{}, isAggregate bool) (*goqu.SelectDataset, error) {
+	var objectFn, aggFn string
+	switch model.DBType(s.dbType) {
+	case model.Postgres:
+		objectFn, aggFn = "json_build_object", "json_agg"
+	case model.MySQL:
+		objectFn, aggFn = "JSON_OBJECT", "JSON_ARRAYAGG"
+	default:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Join strategy (%s) isn't supported by %s", model.JoinStrategyJSON, s.dbType), nil, nil)
+	}
+
+	switch {
+	case isAggregate:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Join on table (%s) with strategy (%s) cannot be used with aggregations", j.Table, model.JoinStrategyJSON), nil, nil)
+	case j.Type != "" && j.Type != "LEFT":
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Join on table (%s) with strategy (%s) must be a left join", j.Table, model.JoinStrategyJSON), nil, nil)
+	case len(j.Join) > 0:
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Join on table (%s) with strategy (%s) cannot have nested joins", j.Table, model.JoinStrategyJSON), nil, nil)
+	}
+
+	prefix := j.Table + "."
+	columns := make([]string, 0)
+	for key := range sel {
+		if strings.HasPrefix(key, prefix) {
+			columns = append(columns, strings.TrimPrefix(key, prefix))
+			delete(sel, key)
+		}
+	}
+	if len(columns) == 0 {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Select cannot be empty for join on table (%s) with strategy (%s)", j.Table, model.JoinStrategyJSON), nil, nil)
+	}
+	sort.Strings(columns)
+
+	pairs := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		pairs[i] = fmt.Sprintf("'%s', ?", strings.Replace(column, "'", "''", -1))
+		args[i] = goqu.I(prefix + column)
+	}
+	subSelect := []interface{}{goqu.L(fmt.Sprintf("%s(%s(%s))", aggFn, objectFn, strings.Join(pairs, ", ")), args...).As("value")}
+
+	derived := getJSONJoinColumn(j) + "__json"
+	keys := make([]string, 0, len(j.On))
+	for k := range j.On {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parent string
+	groupBy := make([]interface{}, 0, len(keys))
+	on := make([]exp.Expression, 0, len(keys))
+	for i, k := range keys {
+		ref, ok := j.On[k].(string)
+		arr := strings.Split(k, ".")
+		if !ok || len(arr) != 2 || !strings.HasPrefix(ref, prefix) || (parent != "" && parent != arr[0]) {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Join on table (%s) with strategy (%s) must match columns of the parent table with the columns of the joined table", j.Table, model.JoinStrategyJSON), nil, nil)
+		}
+		parent = arr[0]
+
+		keyColumn := fmt.Sprintf("__key%d", i)
+		subSelect = append(subSelect, goqu.I(ref).As(keyColumn))
+		groupBy = append(groupBy, goqu.I(ref))
+		on = append(on, goqu.I(k).Eq(goqu.I(derived+"."+keyColumn)))
+	}
+	if parent == "" {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Join on table (%s) with strategy (%s) needs an on clause", j.Table, model.JoinStrategyJSON), nil, nil)
+	}
+
+	sub := goqu.Dialect(s.dbType).From(goqu.T(s.getColName(j.Table))).Prepared(true)
+	sub = s.generateWhereClause(ctx, sub, nil, j.MatchWhere, map[string]struct{}{j.Table: {}})
+	sub = sub.Select(subSelect...).GroupBy(groupBy...)
+
+	*selArray = append(*selArray, goqu.I(derived+".value").As(parent+"__"+getJSONJoinColumn(j)))
+	return query.LeftJoin(sub.As(derived), goqu.On(on...)), nil
+}
+
+// processJSONJoinResult replaces the aggregated rows of a join using JoinStrategyJSON in the document of the parent
+// row with their decoded value
+func (s *SQL) processJSONJoinResult(ctx context.Context, j *model.JoinOption, m map[string]interface{}, postProcess map[string]*model.PostProcess) {
+	column := getJSONJoinColumn(j)
+	value := m[column]
+	delete(m, column)
+
+	var arr []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		arr = v
+	case string:
+		_ = json.Unmarshal([]byte(v), &arr)
+	case []byte:
+		_ = json.Unmarshal(v, &arr)
+	}
+	if arr == nil {
+		arr = []interface{}{}
+	}
+
+	if postProcess != nil {
+		for _, doc := range arr {
+			_ = authHelpers.PostProcessMethod(ctx, s.aesKey, postProcess[j.Table], doc)
+		}
+	}
+
+	alias := getJoinAlias(j)
+	if j.Op == utils.All || j.Op == "" {
+		m[alias] = arr
+		return
+	}
+	if len(arr) > 0 {
+		m[alias] = arr[0]
+		return
+	}
+	m[alias] = map[string]interface{}{}
+}
@@ -0,0 +1,405 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// getQueryTables returns the names of the tables a query runs on. They are needed to tell keys of the form
+// table.column apart from paths into JSON columns.
+func getQueryTables(col string, join []*model.JoinOption) map[string]struct{} {
+	tables := map[string]struct{}{col: {}}
+	var addJoins func(join []*model.JoinOption)
+	addJoins = func(join []*model.JoinOption) {
+		for _, j := range join {
+			tables[j.Table] = struct{}{}
+			addJoins(j.Join)
+		}
+	}
+	addJoins(join)
+	return tables
+}
+
+// splitJSONPath splits a key of the form column.path or table.column.path into the column and the path of a field
+// within the JSON document stored in it. It returns false if the key refers to a column.
+func splitJSONPath(key string, tables map[string]struct{}) (string, []string, bool) {
+	arr := strings.Split(key, ".")
+	if _, ok := tables[arr[0]]; ok {
+		if len(arr) < 3 {
+			return "", nil, false
+		}
+		return arr[0] + "." + arr[1], arr[2:], true
+	}
+	if len(arr) < 2 {
+		return "", nil, false
+	}
+	return arr[0], arr[1:], true
+}
+
+// postgresJSONPath returns the path as a postgres text array
+func postgresJSONPath(path []string) string {
+	arr := make([]string, len(path))
+	for i, p := range path {
+		arr[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(p) + `"`
+	}
+	return "{" + strings.Join(arr, ",") + "}"
+}
+
+// mysqlJSONPath returns the path as a mysql path expression. Numeric parts of the path are treated as array indexes.
+func mysqlJSONPath(path []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, p := range path {
+		if _, err := strconv.ParseUint(p, 10, 32); err == nil {
+			b.WriteString("[" + p + "]")
+			continue
+		}
+		b.WriteString(`."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(p) + `"`)
+	}
+	return b.String()
+}
+
+func marshalJSONValue(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// generateJSONPathExpression translates the conditions on a field within a JSON column. Values are compared as JSON,
+// which means numbers, booleans and strings keep their types. Conditions which cannot be translated match no rows.
+func (s *SQL) generateJSONPathExpression(ctx context.Context, column string, path []string, value interface{}) goqu.Expression {
+	cond, ok := value.(map[string]interface{})
+	if !ok || !isOperatorMap(cond) {
+		cond = map[string]interface{}{"$eq": value}
+	}
+
+	exps := make([]goqu.Expression, 0, len(cond))
+	for op, v := range cond {
+		exp, err := s.generateJSONPathCondition(column, path, op, v)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to query field (%s) of json column (%s)", strings.Join(path, "."), column), err, nil)
+			return goqu.L("1 = 0")
+		}
+		exps = append(exps, exp)
+	}
+	return goqu.And(exps...)
+}
+
+func (s *SQL) generateJSONPathCondition(column string, path []string, op string, value interface{}) (goqu.Expression, error) {
+	var target, text goqu.Expression
+	var castJSON string
+	switch model.DBType(s.dbType) {
+	case model.Postgres:
+		target = goqu.L("(?::jsonb #> ?::text[])", goqu.I(column), postgresJSONPath(path))
+		text = goqu.L("(?::jsonb #>> ?::text[])", goqu.I(column), postgresJSONPath(path))
+		castJSON = "?::jsonb"
+	case model.MySQL:
+		target = goqu.L("JSON_EXTRACT(?, ?)", goqu.I(column), mysqlJSONPath(path))
+		text = goqu.L("JSON_UNQUOTE(JSON_EXTRACT(?, ?))", goqu.I(column), mysqlJSONPath(path))
+		castJSON = "CAST(? AS JSON)"
+	default:
+		return nil, fmt.Errorf("querying json fields is not supported for database (%s)", s.dbType)
+	}
+
+	switch op {
+	case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+		data, err := marshalJSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+		operator := map[string]string{"$eq": "=", "$ne": "<>", "$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[op]
+		return goqu.L("? "+operator+" "+castJSON, target, data), nil
+
+	case "$in", "$nin":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("value of %s should be an array", op)
+		}
+		if len(arr) == 0 {
+			if op == "$in" {
+				return goqu.L("1 = 0"), nil
+			}
+			return goqu.L("1 = 1"), nil
+		}
+		// Comparing json values with IN isn't supported by mysql
+		exps := make([]goqu.Expression, len(arr))
+		for i, v := range arr {
+			data, err := marshalJSONValue(v)
+			if err != nil {
+				return nil, err
+			}
+			if op == "$in" {
+				exps[i] = goqu.L("? = "+castJSON, target, data)
+			} else {
+				exps[i] = goqu.L("? <> "+castJSON, target, data)
+			}
+		}
+		if op == "$in" {
+			return goqu.Or(exps...), nil
+		}
+		return goqu.And(exps...), nil
+
+	case "$contains":
+		data, err := marshalJSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if model.DBType(s.dbType) == model.MySQL {
+			return goqu.L("JSON_CONTAINS(?, ?, ?)", goqu.I(column), data, mysqlJSONPath(path)), nil
+		}
+		return goqu.L("? @> ?::jsonb", target, data), nil
+
+	case "$regex":
+		if model.DBType(s.dbType) == model.MySQL {
+			return goqu.L("? REGEXP ?", text, value), nil
+		}
+		return goqu.L("? ~ ?", text, value), nil
+
+	case "$like":
+		return goqu.L("? LIKE ?", text, value), nil
+	}
+	return nil, fmt.Errorf("operator (%s) is not supported on json fields", op)
+}
+
+// isOperatorMap returns true if all the keys of a condition are operators
+func isOperatorMap(cond map[string]interface{}) bool {
+	if len(cond) == 0 {
+		return false
+	}
+	for k := range cond {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// setJSONPaths replaces the keys of an update record which point into JSON columns with an expression which sets
+// those fields, leaving the rest of the JSON document untouched
+func (s *SQL) setJSONPaths(col string, record goqu.Record) error {
+	tables := map[string]struct{}{col: {}}
+	paths := map[string][]string{}
+	for key := range record {
+		column, _, ok := splitJSONPath(key, tables)
+		if !ok {
+			continue
+		}
+		column = strings.TrimPrefix(column, col+".")
+		paths[column] = append(paths[column], key)
+	}
+
+	for column, keys := range paths {
+		if _, ok := record[column]; ok {
+			return fmt.Errorf("column (%s) cannot be set along with its fields", column)
+		}
+		// Sort the keys so that the query is the same for the same update
+		sort.Strings(keys)
+
+		var exp goqu.Expression
+		switch model.DBType(s.dbType) {
+		case model.Postgres:
+			exp = goqu.L("COALESCE(?::jsonb, '{}'::jsonb)", goqu.I(column))
+			for _, key := range keys {
+				_, path, _ := splitJSONPath(key, tables)
+				data, err := marshalJSONValue(record[key])
+				if err != nil {
+					return err
+				}
+				exp = goqu.L("jsonb_set(?, ?::text[], ?::jsonb, true)", exp, postgresJSONPath(path), data)
+			}
+		case model.MySQL:
+			args := []interface{}{goqu.I(column)}
+			placeholders := make([]string, 0, len(keys))
+			for _, key := range keys {
+				_, path, _ := splitJSONPath(key, tables)
+				data, err := marshalJSONValue(record[key])
+				if err != nil {
+					return err
+				}
+				args = append(args, mysqlJSONPath(path), data)
+				placeholders = append(placeholders, "?, CAST(? AS JSON)")
+			}
+			exp = goqu.L("JSON_SET(COALESCE(?, JSON_OBJECT()), "+strings.Join(placeholders, ", ")+")", args...)
+		default:
+			return fmt.Errorf("updating json fields is not supported for database (%s)", s.dbType)
+		}
+
+		for _, key := range keys {
+			delete(record, key)
+		}
+		record[column] = exp
+	}
+	return nil
+}
+
+// getArrayOperands returns the values an array update operator is applied with. $push and $addToSet accept either a
+// single value or {"$each": [...]} while $pull accepts either a single value or {"$in": [...]}.
+func getArrayOperands(op string, value interface{}) ([]interface{}, error) {
+	modifier := "$each"
+	if op == "$pull" {
+		modifier = "$in"
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok || len(obj) != 1 {
+		return []interface{}{value}, nil
+	}
+	v, ok := obj[modifier]
+	if !ok {
+		return []interface{}{value}, nil
+	}
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("value of %s should be an array", modifier)
+	}
+	return arr, nil
+}
+
+// uniqueJSONValues removes the values which are repeated, comparing them by their JSON encoding
+func uniqueJSONValues(values []interface{}) ([]interface{}, error) {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		data, err := marshalJSONValue(v)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[data]; ok {
+			continue
+		}
+		seen[data] = struct{}{}
+		unique = append(unique, v)
+	}
+	return unique, nil
+}
+
+// generateArrayUpdateRecord returns the update record for the $push, $addToSet and $pull operators. Arrays are stored
+// in JSON columns, so a key either refers to a column holding an array or to an array within the JSON document of a
+// column. Elements are compared as JSON.
+func (s *SQL) generateArrayUpdateRecord(col, op string, m map[string]interface{}) (goqu.Record, error) {
+	if dbType := model.DBType(s.dbType); dbType != model.Postgres && dbType != model.MySQL {
+		return nil, fmt.Errorf("array update operators are not supported for database (%s)", s.dbType)
+	}
+
+	tables := map[string]struct{}{col: {}}
+	columns := map[string][]string{}
+	for key := range m {
+		column := key
+		if c, _, ok := splitJSONPath(key, tables); ok {
+			column = c
+		}
+		column = strings.TrimPrefix(column, col+".")
+		columns[column] = append(columns[column], key)
+	}
+
+	record := goqu.Record{}
+	for column, keys := range columns {
+		// Sort the keys so that the query is the same for the same update
+		sort.Strings(keys)
+
+		// The arrays are read from the stored document rather than the one being built, which requires the paths to
+		// not overlap
+		for i := 1; i < len(keys); i++ {
+			if strings.HasPrefix(keys[i], keys[i-1]+".") {
+				return nil, fmt.Errorf("fields (%s) and (%s) cannot be updated together", keys[i-1], keys[i])
+			}
+		}
+
+		var exp goqu.Expression
+		for _, key := range keys {
+			values, err := getArrayOperands(op, m[key])
+			if err != nil {
+				return nil, err
+			}
+			if op == "$addToSet" {
+				if values, err = uniqueJSONValues(values); err != nil {
+					return nil, err
+				}
+			}
+			data, err := marshalJSONValue(values)
+			if err != nil {
+				return nil, err
+			}
+
+			_, path, ok := splitJSONPath(key, tables)
+			if !ok {
+				if len(keys) > 1 {
+					return nil, fmt.Errorf("column (%s) cannot be updated along with its fields", column)
+				}
+				exp = s.arrayOperation(op, s.jsonColumn(column, "array"), data)
+				continue
+			}
+			if exp == nil {
+				exp = s.jsonColumn(column, "object")
+			}
+			arr := s.jsonExtractArray(s.jsonColumn(column, "object"), path)
+			exp = s.jsonSetArray(exp, path, s.arrayOperation(op, arr, data))
+		}
+		record[column] = exp
+	}
+	return record, nil
+}
+
+// jsonColumn returns the JSON stored in a column, defaulting to an empty array or object if the column is null
+func (s *SQL) jsonColumn(column, kind string) goqu.Expression {
+	if model.DBType(s.dbType) == model.MySQL {
+		if kind == "array" {
+			return goqu.L("COALESCE(?, JSON_ARRAY())", goqu.I(column))
+		}
+		return goqu.L("COALESCE(?, JSON_OBJECT())", goqu.I(column))
+	}
+	if kind == "array" {
+		return goqu.L("COALESCE(?::jsonb, '[]'::jsonb)", goqu.I(column))
+	}
+	return goqu.L("COALESCE(?::jsonb, '{}'::jsonb)", goqu.I(column))
+}
+
+// jsonExtractArray returns the array at a path within a JSON document, defaulting to an empty array if it is missing
+func (s *SQL) jsonExtractArray(doc goqu.Expression, path []string) goqu.Expression {
+	if model.DBType(s.dbType) == model.MySQL {
+		return goqu.L("COALESCE(JSON_EXTRACT(?, ?), JSON_ARRAY())", doc, mysqlJSONPath(path))
+	}
+	return goqu.L("COALESCE(? #> ?::text[], '[]'::jsonb)", doc, postgresJSONPath(path))
+}
+
+// jsonSetArray returns the JSON document with the array at a path replaced
+func (s *SQL) jsonSetArray(doc goqu.Expression, path []string, arr goqu.Expression) goqu.Expression {
+	if model.DBType(s.dbType) == model.MySQL {
+		return goqu.L("JSON_SET(?, ?, ?)", doc, mysqlJSONPath(path), arr)
+	}
+	return goqu.L("jsonb_set(?, ?::text[], ?, true)", doc, postgresJSONPath(path), arr)
+}
+
+// arrayOperation applies an array update operator to a JSON array. The values are passed as a single JSON array.
+func (s *SQL) arrayOperation(op string, arr goqu.Expression, values string) goqu.Expression {
+	if model.DBType(s.dbType) == model.MySQL {
+		// JSON_TABLE requires mysql 8
+		switch op {
+		case "$push":
+			return goqu.L("JSON_MERGE_PRESERVE(?, CAST(? AS JSON))", arr, values)
+		case "$addToSet":
+			return goqu.L("JSON_MERGE_PRESERVE(?, COALESCE((SELECT JSON_ARRAYAGG(v.value) FROM JSON_TABLE(CAST(? AS JSON), '$[*]' COLUMNS (value JSON PATH '$')) AS v WHERE v.value NOT IN (SELECT e.value FROM JSON_TABLE(?, '$[*]' COLUMNS (value JSON PATH '$')) AS e)), JSON_ARRAY()))", arr, values, arr)
+		default:
+			return goqu.L("COALESCE((SELECT JSON_ARRAYAGG(e.value) FROM JSON_TABLE(?, '$[*]' COLUMNS (value JSON PATH '$')) AS e WHERE e.value NOT IN (SELECT v.value FROM JSON_TABLE(CAST(? AS JSON), '$[*]' COLUMNS (value JSON PATH '$')) AS v)), JSON_ARRAY())", arr, values)
+		}
+	}
+
+	switch op {
+	case "$push":
+		return goqu.L("(? || ?::jsonb)", arr, values)
+	case "$addToSet":
+		return goqu.L("(? || COALESCE((SELECT jsonb_agg(v.value ORDER BY v.ordinality) FROM jsonb_array_elements(?::jsonb) WITH ORDINALITY AS v(value, ordinality) WHERE v.value NOT IN (SELECT jsonb_array_elements(?))), '[]'::jsonb))", arr, values, arr)
+	default:
+		return goqu.L("COALESCE((SELECT jsonb_agg(e.value ORDER BY e.ordinality) FROM jsonb_array_elements(?) WITH ORDINALITY AS e(value, ordinality) WHERE e.value NOT IN (SELECT jsonb_array_elements(?::jsonb))), '[]'::jsonb)", arr, values)
+	}
+}
@@ -37,6 +37,10 @@ func (s *SQL) RawBatch(ctx context.Context, queries []string) error {
 		return err
 	}
 
+	// The statements prepared before the schema changed may no longer be valid
+	if s.statements != nil {
+		s.statements.purge()
+	}
 	return nil
 }
 
@@ -63,6 +67,23 @@ func (s *SQL) GetConnectionState(ctx context.Context) bool {
 	return true
 }
 
+// GetPoolStats returns the statistics of the underlying connection pool
+func (s *SQL) GetPoolStats() model.DBPoolStats {
+	client := s.getClient()
+	if !s.enabled || client == nil {
+		return model.DBPoolStats{}
+	}
+
+	stats := client.Stats()
+	return model.DBPoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+	}
+}
+
 // CreateDatabaseIfNotExist creates a schema / database
 func (s *SQL) CreateDatabaseIfNotExist(ctx context.Context, name string) error {
 	var sql string
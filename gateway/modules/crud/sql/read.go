@@ -24,6 +24,11 @@ import (
 
 // generateReadQuery makes a query for read operation
 func (s *SQL) generateReadQuery(ctx context.Context, col string, req *model.ReadRequest) (string, []interface{}, error) {
+	return s.generateReadQueryFrom(ctx, s.getColName(col), col, req)
+}
+
+// generateReadQueryFrom generates the read query on the provided source, which is either a table or a sub query
+func (s *SQL) generateReadQueryFrom(ctx context.Context, from interface{}, col string, req *model.ReadRequest) (string, []interface{}, error) {
 	dbType := s.dbType
 	if dbType == string(model.SQLServer) {
 		dbType = string(model.Postgres)
@@ -38,10 +43,11 @@ func (s *SQL) generateReadQuery(ctx context.Context, col string, req *model.Read
 	}
 
 	dialect := goqu.Dialect(dbType)
-	query := dialect.From(s.getColName(col)).Prepared(true)
+	query := dialect.From(from).Prepared(true)
 
 	// Get the where clause from query object
-	query = s.generateWhereClause(ctx, query, req.Find, req.MatchWhere)
+	tables := getQueryTables(col, req.Options.Join)
+	query = s.generateWhereClause(ctx, query, req.Find, req.MatchWhere, tables)
 
 	selArray := make([]interface{}, 0)
 	if req.Options != nil {
@@ -81,7 +87,7 @@ func (s *SQL) generateReadQuery(ctx context.Context, col string, req *model.Read
 			query = query.Order(orderBys...)
 		}
 
-		q, err := s.processJoins(ctx, query, req.Options.Join, req.Options.Select, len(req.Aggregate) > 0)
+		q, err := s.processJoins(ctx, query, req.Options.Join, tables, req.Options.Select, &selArray, len(req.Aggregate) > 0)
 		if err != nil {
 			return "", nil, err
 		}
@@ -218,11 +224,11 @@ func (s *SQL) readExec(ctx context.Context, col, sqlString string, args []interf
 	operation := req.Operation
 	isAggregate := len(req.Aggregate) > 0
 	metaData := new(model.SQLMetaData)
-	stmt, err := executor.PreparexContext(ctx, sqlString)
+	stmt, release, err := s.prepare(ctx, executor, sqlString)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
-	defer func() { _ = stmt.Close() }()
+	defer release()
 
 	start := time.Now()
 	rows, err := stmt.QueryxContext(ctx, args...)
@@ -381,7 +387,8 @@ func (s *SQL) processRows(ctx context.Context, isDebug bool, table []string, isA
 	// Check if key exists in mapping. This can happen if the row has multiple
 	// sub rows else append self to final array.
 	var mapLength int
-	if m2, p := mapping[key]; p {
+	m2, isProcessed := mapping[key]
+	if isProcessed {
 		mapLength = len(m2)
 		m = m2
 	} else {
@@ -406,6 +413,14 @@ func (s *SQL) processRows(ctx context.Context, isDebug bool, table []string, isA
 	}
 
 	for _, j := range join {
+		// The rows of json joins come aggregated in a column of the parent row
+		if j.Strategy == model.JoinStrategyJSON {
+			if !isProcessed {
+				s.processJSONJoinResult(ctx, j, m, postProcess)
+			}
+			continue
+		}
+
 		var arr []interface{}
 		utils.GenerateJoinKeys(j.Table, j.On, row, joinMapping)
 		// Check if table name is already present in parent row. If not, create a new array
@@ -225,6 +225,55 @@ func TestSQL_generateReadQuery(t *testing.T) {
 			want1:   nil,
 			wantErr: true,
 		},
+		{
+			name:   "json join",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "t1",
+				req: &model.ReadRequest{
+					Find: map[string]interface{}{"t1.col1": map[string]interface{}{"$eq": 1}},
+					Options: &model.ReadOptions{
+						Select: map[string]int32{"t1.col1": 1, "t2.col3": 1},
+						Join: []*model.JoinOption{
+							{Table: "t2", As: "items", Type: "LEFT", Strategy: model.JoinStrategyJSON, On: map[string]interface{}{"t1.col1": "t2.col2"}, MatchWhere: []map[string]interface{}{{"t2.col4": 2}}},
+						}},
+					Operation: "all"}},
+			want:    []string{"SELECT items__json.value AS t1__items, t1.col1 AS t1__col1 FROM t1 LEFT JOIN (SELECT JSON_ARRAYAGG(JSON_OBJECT('col3', t2.col3)) AS value, t2.col2 AS __key0 FROM t2 WHERE (t2.col4 = ?) GROUP BY t2.col2) AS items__json ON (t1.col1 = items__json.__key0) WHERE (t1.col1 = ?)"},
+			want1:   []interface{}{int64(2), int64(1)},
+			wantErr: false,
+		},
+		{
+			name:   "json join scoped to a tenant",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "t1",
+				req: &model.ReadRequest{
+					Find: map[string]interface{}{"t1.tenant_id": "acme"},
+					Options: &model.ReadOptions{
+						Select: map[string]int32{"t1.col1": 1, "t2.col3": 1},
+						Join: []*model.JoinOption{
+							{Table: "t2", As: "items", Type: "LEFT", Strategy: model.JoinStrategyJSON, On: map[string]interface{}{"t1.col1": "t2.col2", "t1.tenant_id": "t2.tenant_id"}},
+						}},
+					Operation: "all"}},
+			want:    []string{"SELECT items__json.value AS t1__items, t1.col1 AS t1__col1 FROM t1 LEFT JOIN (SELECT JSON_ARRAYAGG(JSON_OBJECT('col3', t2.col3)) AS value, t2.col2 AS __key0, t2.tenant_id AS __key1 FROM t2 GROUP BY t2.col2, t2.tenant_id) AS items__json ON ((t1.col1 = items__json.__key0) AND (t1.tenant_id = items__json.__key1)) WHERE (t1.tenant_id = ?)"},
+			want1:   []interface{}{"acme"},
+			wantErr: false,
+		},
+		{
+			name:   "json join with nested join",
+			fields: fields{dbType: "mysql"},
+			args: args{project: "test", col: "t1",
+				req: &model.ReadRequest{
+					Options: &model.ReadOptions{
+						Select: map[string]int32{"t1.col1": 1, "t2.col3": 1},
+						Join: []*model.JoinOption{
+							{Table: "t2", Strategy: model.JoinStrategyJSON, On: map[string]interface{}{"t1.col1": "t2.col2"}, Join: []*model.JoinOption{
+								{Table: "t3", On: map[string]interface{}{"t2.col3": "t3.col4"}},
+							}},
+						}},
+					Operation: "all"}},
+			want:    []string{""},
+			want1:   nil,
+			wantErr: true,
+		},
 		// This is a valid test case, but we are commenting it out because,
 		// the resultant sql string can have more than 16 combination, all of them are valid
 		// {
@@ -247,9 +296,50 @@ func TestSQL_generateReadQuery(t *testing.T) {
 		// 	wantErr: false,
 		// },
 
+		{
+			name:    "Contains within json column",
+			fields:  fields{dbType: "mysql"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"meta.tags": map[string]interface{}{"$contains": "beta"}}}},
+			want:    []string{"SELECT * FROM table WHERE JSON_CONTAINS(meta, ?, ?)"},
+			want1:   []interface{}{`"beta"`, `$."tags"`},
+			wantErr: false,
+		},
+		{
+			name:    "Regex on field within json column",
+			fields:  fields{dbType: "mysql"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"meta.title": map[string]interface{}{"$regex": "^go"}}}},
+			want:    []string{"SELECT * FROM table WHERE JSON_UNQUOTE(JSON_EXTRACT(meta, ?)) REGEXP ?"},
+			want1:   []interface{}{`$."title"`, "^go"},
+			wantErr: false,
+		},
+		{
+			name:    "Geospatial filter matches nothing",
+			fields:  fields{dbType: "mysql"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"location": map[string]interface{}{"$geoBox": map[string]interface{}{"minLng": -1, "minLat": -2, "maxLng": 1, "maxLat": 2}}}}},
+			want:    []string{"SELECT * FROM table WHERE 1 = 0"},
+			want1:   []interface{}{},
+			wantErr: false,
+		},
 		// #######################################################################################
 		// ###################################  Postgres  ########################################
 		// #######################################################################################
+		{
+			name:   "json join",
+			fields: fields{dbType: "postgres"},
+			args: args{project: "test", col: "t1",
+				req: &model.ReadRequest{
+					Find: map[string]interface{}{"t1.col1": map[string]interface{}{"$eq": 1}},
+					Options: &model.ReadOptions{
+						Select: map[string]int32{"t1.col1": 1, "t2.col3": 1},
+						Limit:  iti(10),
+						Join: []*model.JoinOption{
+							{Table: "t2", As: "items", Op: "one", Strategy: model.JoinStrategyJSON, On: map[string]interface{}{"t1.col1": "t2.col2"}, MatchWhere: []map[string]interface{}{{"t2.col4": 2}}},
+						}},
+					Operation: "all"}},
+			want:    []string{"SELECT items__json.value AS t1__items, t1.col1 AS t1__col1 FROM test.t1 LEFT JOIN (SELECT json_agg(json_build_object('col3', t2.col3)) AS value, t2.col2 AS __key0 FROM test.t2 WHERE (t2.col4 = $1) GROUP BY t2.col2) AS items__json ON (t1.col1 = items__json.__key0) WHERE (t1.col1 = $2) LIMIT $3"},
+			want1:   []interface{}{int64(2), int64(1), int64(10)},
+			wantErr: false,
+		},
 		{
 			name:    "String1 = ?",
 			fields:  fields{dbType: "postgres"},
@@ -386,6 +476,54 @@ func TestSQL_generateReadQuery(t *testing.T) {
 			want1:   []interface{}{`{"obj1":"value1"}`},
 			wantErr: false,
 		},
+		{
+			name:    "Contains within json column",
+			fields:  fields{dbType: "postgres"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"meta.tags": map[string]interface{}{"$contains": "beta"}}}},
+			want:    []string{"SELECT * FROM test.table WHERE (meta::jsonb #> $1::text[]) @> $2::jsonb"},
+			want1:   []interface{}{`{"tags"}`, `"beta"`},
+			wantErr: false,
+		},
+		{
+			name:    "Compare field within json column of table",
+			fields:  fields{dbType: "postgres"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"table.meta.views": map[string]interface{}{"$gt": 10}}}},
+			want:    []string{"SELECT * FROM test.table WHERE (table.meta::jsonb #> $1::text[]) > $2::jsonb"},
+			want1:   []interface{}{`{"views"}`, "10"},
+			wantErr: false,
+		},
+		{
+			name:    "Near a point",
+			fields:  fields{dbType: "postgres"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"lat": 19.07, "lng": 72.87, "maxDistance": 1000, "minDistance": 100}}}}},
+			want:    []string{"SELECT * FROM test.table WHERE (ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint($1, $2), $3)::geography, $4) AND NOT ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint($5, $6), $7)::geography, $8))"},
+			want1:   []interface{}{72.87, 19.07, int64(4326), float64(1000), 72.87, 19.07, int64(4326), float64(100)},
+			wantErr: false,
+		},
+		{
+			name:    "Within a polygon",
+			fields:  fields{dbType: "postgres"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"location": map[string]interface{}{"$geoWithin": map[string]interface{}{"polygon": []interface{}{[]interface{}{0, 0}, []interface{}{1, 0}, []interface{}{1, 1}}}}}}},
+			want:    []string{"SELECT * FROM test.table WHERE ST_Within(location::geometry, ST_SetSRID(ST_GeomFromGeoJSON($1), $2))"},
+			want1:   []interface{}{`{"coordinates":[[[0,0],[1,0],[1,1],[0,0]]],"type":"Polygon"}`, int64(4326)},
+			wantErr: false,
+		},
+		{
+			name:    "Within a bounding box",
+			fields:  fields{dbType: "postgres"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"location": map[string]interface{}{"$geoBox": map[string]interface{}{"minLng": -1, "minLat": -2, "maxLng": 1, "maxLat": 2}}}}},
+			want:    []string{"SELECT * FROM test.table WHERE ST_Within(location::geometry, ST_MakeEnvelope($1, $2, $3, $4, $5))"},
+			want1:   []interface{}{float64(-1), float64(-2), float64(1), float64(2), int64(4326)},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid geospatial filter matches nothing",
+			fields:  fields{dbType: "postgres"},
+			args:    args{project: "test", col: "table", req: &model.ReadRequest{Find: map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"lat": 100, "lng": 72.87, "maxDistance": 1000}}}}},
+			want:    []string{"SELECT * FROM test.table WHERE 1 = 0"},
+			want1:   []interface{}{},
+			wantErr: false,
+		},
 		{
 			name:   "Select column in asc and desc",
 			fields: fields{dbType: "postgres"},
@@ -755,6 +893,34 @@ func Test_processRows(t *testing.T) {
 				}, "t4": []interface{}{}},
 			},
 		},
+		{
+			name: "json join",
+			args: args{
+				table: "t1",
+				rows: []interface{}{
+					map[string]interface{}{"t1__c1": "a1", "t1__items": []interface{}{map[string]interface{}{"c3": "b1"}, map[string]interface{}{"c3": "b2"}}, "t1__owner": `[{"c4": "d1"}]`, "t3__c5": "e1"},
+					map[string]interface{}{"t1__c1": "a1", "t1__items": []interface{}{map[string]interface{}{"c3": "b1"}, map[string]interface{}{"c3": "b2"}}, "t1__owner": `[{"c4": "d1"}]`, "t3__c5": "e2"},
+					map[string]interface{}{"t1__c1": "c1", "t1__items": nil, "t1__owner": nil, "t3__c5": nil},
+				},
+				join: []*model.JoinOption{
+					{Table: "t2", As: "items", Strategy: model.JoinStrategyJSON},
+					{Table: "t4", As: "owner", Op: "one", Strategy: model.JoinStrategyJSON},
+					{Table: "t3"},
+				},
+			},
+			result: []interface{}{
+				map[string]interface{}{"c1": "a1", "items": []interface{}{
+					map[string]interface{}{"c3": "b1"},
+					map[string]interface{}{"c3": "b2"},
+				}, "owner": map[string]interface{}{"c4": "d1"}, "t3": []interface{}{
+					map[string]interface{}{"c5": "e1"},
+					map[string]interface{}{"c5": "e2"},
+				}},
+				map[string]interface{}{"c1": "c1", "items": []interface{}{}, "owner": map[string]interface{}{}, "t3": []interface{}{
+					map[string]interface{}{"c5": nil},
+				}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,248 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// CreateReturning inserts the documents and returns them as stored in the database, which includes the values
+// generated by it. Postgres returns the rows of the insert itself, while the other databases read them back by their
+// primary key in the same transaction.
+func (s *SQL) CreateReturning(ctx context.Context, col string, req *model.CreateRequest) (int64, []interface{}, error) {
+	tx, err := s.getClient().BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var docs []interface{}
+	if model.DBType(s.dbType) == model.Postgres {
+		sqlQuery, args, err := s.generateCreateQuery(col, req)
+		if err != nil {
+			return 0, nil, err
+		}
+		if docs, err = s.queryDocuments(ctx, sqlQuery+" RETURNING *", args, tx); err != nil {
+			return 0, nil, err
+		}
+	} else {
+		keys, autoIncrement, err := s.getPrimaryKeys(ctx, col)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		insert := []interface{}{req.Document}
+		if req.Operation != utils.One {
+			var ok bool
+			if insert, ok = req.Document.([]interface{}); !ok {
+				return 0, nil, utils.ErrInvalidParams
+			}
+		}
+
+		// The documents are inserted one at a time to know the value generated for the auto incremented column
+		for _, temp := range insert {
+			doc, ok := temp.(map[string]interface{})
+			if !ok {
+				return 0, nil, utils.ErrInvalidParams
+			}
+			sqlQuery, args, err := s.generateCreateQuery(col, &model.CreateRequest{Document: doc, Operation: utils.One})
+			if err != nil {
+				return 0, nil, err
+			}
+			res, err := s.doExecContext(ctx, sqlQuery, args, tx)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			find := make(map[string]interface{}, len(keys))
+			for _, key := range keys {
+				if v, ok := doc[key]; ok {
+					find[key] = v
+					continue
+				}
+				if key != autoIncrement {
+					return 0, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read back the created document - value of primary key (%s) of table (%s) was generated by the database", key, col), nil, nil)
+				}
+				id, err := res.LastInsertId()
+				if err != nil {
+					return 0, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read back the created document - value of auto incremented column (%s) of table (%s) is unknown", key, col), err, nil)
+				}
+				find[key] = id
+			}
+
+			_, result, _, _, err := s.read(ctx, col, &model.ReadRequest{Find: find, Operation: utils.One, Options: &model.ReadOptions{}}, tx)
+			if err != nil {
+				return 0, nil, err
+			}
+			docs = append(docs, result)
+		}
+	}
+
+	return int64(len(docs)), docs, tx.Commit()
+}
+
+// UpdateReturning updates the documents and returns them after the update. The primary keys of the documents are read
+// before the update, since the update might change the fields of the where clause, and the documents are read back
+// by them in the same transaction.
+func (s *SQL) UpdateReturning(ctx context.Context, col string, req *model.UpdateRequest) (int64, []interface{}, error) {
+	keys, _, err := s.getPrimaryKeys(ctx, col)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	tx, err := s.getClient().BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	selection := make(map[string]int32, len(keys))
+	for _, key := range keys {
+		selection[key] = 1
+	}
+	_, result, _, _, err := s.read(ctx, col, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{Select: selection}}, tx)
+	if err != nil {
+		return 0, nil, err
+	}
+	matched := result.([]interface{})
+
+	count, err := s.update(ctx, col, req, tx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// An upsert which didn't match anything has inserted a document matching the where clause
+	find := req.Find
+	switch {
+	case len(matched) > 0:
+		find = findByKeys(keys, matched)
+	case req.Operation != utils.Upsert:
+		return count, []interface{}{}, tx.Commit()
+	}
+	_, result, _, _, err = s.read(ctx, col, &model.ReadRequest{Find: find, Operation: utils.All, Options: &model.ReadOptions{}}, tx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return count, result.([]interface{}), tx.Commit()
+}
+
+// DeleteReturning deletes the documents and returns them as they were before being deleted. Postgres returns the rows
+// of the delete itself, while the other databases read them in the same transaction before deleting them.
+func (s *SQL) DeleteReturning(ctx context.Context, col string, req *model.DeleteRequest) (int64, []interface{}, error) {
+	tx, err := s.getClient().BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sqlQuery, args, err := s.generateDeleteQuery(ctx, req, col)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var docs []interface{}
+	var count int64
+	if model.DBType(s.dbType) == model.Postgres {
+		if docs, err = s.queryDocuments(ctx, sqlQuery+" RETURNING *", args, tx); err != nil {
+			return 0, nil, err
+		}
+		count = int64(len(docs))
+	} else {
+		_, result, _, _, err := s.read(ctx, col, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}}, tx)
+		if err != nil {
+			return 0, nil, err
+		}
+		docs = result.([]interface{})
+
+		res, err := s.doExecContext(ctx, sqlQuery, args, tx)
+		if err != nil {
+			return 0, nil, err
+		}
+		if count, err = res.RowsAffected(); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return count, docs, tx.Commit()
+}
+
+// queryDocuments runs a query which returns rows and converts them to documents
+func (s *SQL) queryDocuments(ctx context.Context, sqlQuery string, args []interface{}, executor executor) ([]interface{}, error) {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Executing returning query", map[string]interface{}{"sqlQuery": sqlQuery, "queryArgs": args})
+	stmt, release, err := s.prepare(ctx, executor, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	rowTypes, _ := rows.ColumnTypes()
+	docs := make([]interface{}, 0)
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := rows.MapScan(row); err != nil {
+			return nil, err
+		}
+		mysqlTypeCheck(ctx, s.GetDBType(), rowTypes, row)
+		docs = append(docs, row)
+	}
+	return docs, rows.Err()
+}
+
+// getPrimaryKeys returns the columns of the primary key of a table along with the column which is auto incremented
+func (s *SQL) getPrimaryKeys(ctx context.Context, col string) ([]string, string, error) {
+	fields, indexes, err := s.DescribeTable(ctx, col)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys := make([]string, 0)
+	for _, index := range indexes {
+		if index.IsPrimary {
+			keys = append(keys, index.ColumnName)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Table (%s) needs a primary key to return the documents affected by an operation", col), nil, nil)
+	}
+
+	var autoIncrement string
+	for _, field := range fields {
+		if field.AutoIncrement == "true" {
+			autoIncrement = field.ColumnName
+		}
+	}
+	return keys, autoIncrement, nil
+}
+
+// findByKeys returns a where clause which matches the rows with the provided primary keys. There must be at least
+// one row.
+func findByKeys(keys []string, rows []interface{}) map[string]interface{} {
+	if len(keys) == 1 {
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = row.(map[string]interface{})[keys[0]]
+		}
+		return map[string]interface{}{keys[0]: map[string]interface{}{"$in": values}}
+	}
+
+	conditions := make([]interface{}, len(rows))
+	for i, row := range rows {
+		cond := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			cond[key] = row.(map[string]interface{})[key]
+		}
+		conditions[i] = cond
+	}
+	return map[string]interface{}{"$or": conditions}
+}
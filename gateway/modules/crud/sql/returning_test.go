@@ -0,0 +1,38 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_findByKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		rows []interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "single primary key",
+			keys: []string{"id"},
+			rows: []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}},
+			want: map[string]interface{}{"id": map[string]interface{}{"$in": []interface{}{1, 2}}},
+		},
+		{
+			name: "composite primary key",
+			keys: []string{"id", "tenant"},
+			rows: []interface{}{map[string]interface{}{"id": 1, "tenant": "a"}, map[string]interface{}{"id": 1, "tenant": "b"}},
+			want: map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"id": 1, "tenant": "a"},
+				map[string]interface{}{"id": 1, "tenant": "b"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findByKeys(tt.keys, tt.rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findByKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ type SQL struct {
 	name                string // logical db name or schema name according to the database type
 	driverConf          config.DriverConfig
 	connRetryCloserChan chan struct{}
+	statements          *statementCache // nil when the statements shouldn't be reused
 
 	// 	Auth module
 	aesKey []byte
@@ -40,6 +41,13 @@ type SQL struct {
 func Init(dbType model.DBType, enabled bool, connection string, dbName string, driverConf config.DriverConfig) (s *SQL, err error) {
 	s = &SQL{enabled: enabled, connection: connection, name: dbName, client: nil, driverConf: driverConf}
 
+	switch size := driverConf.StatementCacheSize; {
+	case size == 0:
+		s.statements = newStatementCache(defaultStatementCacheSize)
+	case size > 0:
+		s.statements = newStatementCache(size)
+	}
+
 	switch dbType {
 	case model.Postgres:
 		s.dbType = "postgres"
@@ -88,13 +96,16 @@ func Init(dbType model.DBType, enabled bool, connection string, dbName string, d
 
 // IsSame checks if we've got the same connection string
 func (s *SQL) IsSame(conn, dbName string, driverConf config.DriverConfig) bool {
-	return strings.HasPrefix(s.connection, conn) && dbName == s.name && driverConf.MaxConn == s.driverConf.MaxConn && driverConf.MaxIdleTimeout == s.driverConf.MaxIdleTimeout && driverConf.MaxIdleConn == s.driverConf.MaxIdleConn
+	return strings.HasPrefix(s.connection, conn) && dbName == s.name && driverConf.MaxConn == s.driverConf.MaxConn && driverConf.MaxIdleTimeout == s.driverConf.MaxIdleTimeout && driverConf.MaxIdleConn == s.driverConf.MaxIdleConn && driverConf.QueryComments == s.driverConf.QueryComments
 }
 
 // Close gracefully the SQL client
 func (s *SQL) Close() error {
 	if s.getClient() != nil {
 		s.connRetryCloserChan <- struct{}{}
+		if s.statements != nil {
+			s.statements.purge()
+		}
 		if err := s.getClient().Close(); err != nil {
 			_ = helpers.Logger.LogError("close", fmt.Sprintf("Unable to close (%s) db (%s) connection", s.dbType, s.name), err, nil)
 		}
@@ -144,6 +155,10 @@ func (s *SQL) connect() error {
 		return err
 	}
 
+	// The statements prepared on the previous pool can't be used with the new one
+	if s.statements != nil {
+		s.statements.purge()
+	}
 	s.setClient(sql)
 
 	maxConn := s.driverConf.MaxConn
@@ -172,12 +187,30 @@ type executor interface {
 	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
 }
 
-func doExecContext(ctx context.Context, query string, args []interface{}, executor executor) (sql.Result, error) {
+// prepare returns a prepared statement for the query along with a function to release it. Statements executed on the
+// connection pool are reused across requests, while the ones of a transaction are prepared on it. Queries tagged with
+// the request id or the trace context are unique to the request, hence they are never reused.
+func (s *SQL) prepare(ctx context.Context, executor executor, query string) (*sqlx.Stmt, func(), error) {
+	comment := utils.GetQueryComment(ctx, s.driverConf.QueryComments)
+
+	if db, ok := executor.(*sqlx.DB); ok && s.statements != nil && comment == "" {
+		return s.statements.prepare(ctx, db, query)
+	}
+	query = comment + query
+
 	stmt, err := executor.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stmt, func() { _ = stmt.Close() }, nil
+}
+
+func (s *SQL) doExecContext(ctx context.Context, query string, args []interface{}, executor executor) (sql.Result, error) {
+	stmt, release, err := s.prepare(ctx, executor, query)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = stmt.Close() }()
+	defer release()
 
 	return stmt.ExecContext(ctx, args...)
 }
@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultStatementCacheSize is the number of prepared statements kept per connection pool by default
+const defaultStatementCacheSize = 500
+
+// statementCache holds the statements prepared on a connection pool keyed by their query. Since the generated queries
+// use placeholders for every value, all requests having the same shape share a statement. The least recently used
+// statement is closed once the cache is full.
+type statementCache struct {
+	lock  sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+}
+
+type cachedStatement struct {
+	query   string
+	stmt    *sqlx.Stmt
+	refs    int
+	evicted bool
+}
+
+func newStatementCache(size int) *statementCache {
+	return &statementCache{size: size, items: map[string]*list.Element{}, order: list.New()}
+}
+
+// prepare returns the statement of the query, preparing it on the pool if it isn't cached yet. The returned function
+// must be called once the statement is no longer used.
+func (c *statementCache) prepare(ctx context.Context, db *sqlx.DB, query string) (*sqlx.Stmt, func(), error) {
+	c.lock.Lock()
+	if item, ok := c.items[query]; ok {
+		c.order.MoveToFront(item)
+		entry := item.Value.(*cachedStatement)
+		entry.refs++
+		c.lock.Unlock()
+		return entry.stmt, func() { c.release(entry) }, nil
+	}
+	c.lock.Unlock()
+
+	// The statement is prepared without holding the lock so that other queries aren't blocked by the round trip
+	stmt, err := db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Use the statement prepared by a concurrent request if it won the race
+	if item, ok := c.items[query]; ok {
+		_ = stmt.Close()
+		c.order.MoveToFront(item)
+		entry := item.Value.(*cachedStatement)
+		entry.refs++
+		return entry.stmt, func() { c.release(entry) }, nil
+	}
+
+	entry := &cachedStatement{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.evict(c.order.Back())
+	}
+	return stmt, func() { c.release(entry) }, nil
+}
+
+// purge closes all the cached statements. It is called when the statements may have become invalid, like after the
+// schema of a table is changed.
+func (c *statementCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for c.order.Len() > 0 {
+		c.evict(c.order.Back())
+	}
+}
+
+func (c *statementCache) release(entry *cachedStatement) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// evict removes a statement from the cache. Statements still in use are closed once they are released.
+// NOTE: the caller should take lock on the cache before calling this function
+func (c *statementCache) evict(item *list.Element) {
+	entry := c.order.Remove(item).(*cachedStatement)
+	delete(c.items, entry.query)
+	entry.evicted = true
+	if entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
@@ -0,0 +1,53 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func Test_statementCache(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	client := sqlx.NewDb(db, "postgres")
+
+	// Every query is expected to be prepared only once as long as it stays in the cache
+	mock.ExpectPrepare("SELECT 1").WillBeClosed()
+	mock.ExpectPrepare("SELECT 2").WillBeClosed()
+	mock.ExpectPrepare("SELECT 3")
+
+	ctx := context.Background()
+	c := newStatementCache(2)
+	for _, query := range []string{"SELECT 1", "SELECT 1", "SELECT 2", "SELECT 3"} {
+		_, release, err := c.prepare(ctx, client, query)
+		if err != nil {
+			t.Fatalf("prepare(%s) error = %v", query, err)
+		}
+		release()
+	}
+	if c.order.Len() != 2 {
+		t.Errorf("prepare() cached %d statements, want 2", c.order.Len())
+	}
+	if _, ok := c.items["SELECT 1"]; ok {
+		t.Errorf("prepare() did not evict the least recently used statement")
+	}
+
+	// A statement evicted while in use is closed only after it is released
+	_, release, err := c.prepare(ctx, client, "SELECT 2")
+	if err != nil {
+		t.Fatalf("prepare() error = %v", err)
+	}
+	c.purge()
+	if err := mock.ExpectationsWereMet(); err == nil {
+		t.Errorf("purge() closed a statement which is in use")
+	}
+	release()
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("statements were not prepared and closed as expected - %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// ReadStream reads the rows matching the request and passes them to fn one at a time, without holding the result in
+// memory. The next row is fetched only after fn returns, hence a slow consumer slows down the read.
+func (s *SQL) ReadStream(ctx context.Context, col string, req *model.ReadRequest, fn func(doc map[string]interface{}) error) (int64, error) {
+	sqlString, args, err := s.generateReadQuery(ctx, col, req)
+	if err != nil {
+		return 0, err
+	}
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Executing sql stream query", map[string]interface{}{"sqlQuery": sqlString, "queryArgs": args})
+
+	stmt, release, err := s.prepare(ctx, s.getClient(), sqlString)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	rows, err := stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rowTypes []*sql.ColumnType
+	switch s.GetDBType() {
+	case model.MySQL, model.Postgres, model.SQLServer:
+		rowTypes, _ = rows.ColumnTypes()
+	}
+
+	var count int64
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := rows.MapScan(row); err != nil {
+			return count, err
+		}
+
+		switch s.GetDBType() {
+		case model.MySQL, model.Postgres, model.SQLServer:
+			mysqlTypeCheck(ctx, s.GetDBType(), rowTypes, row)
+		}
+
+		if req.Options.Debug {
+			row["_dbFetchTs"] = time.Now().Format(time.RFC3339Nano)
+		}
+
+		if err := fn(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -43,15 +44,15 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 	switch req.Operation {
 	case utils.All:
 		var count int64
-		for k := range req.Update {
+		for _, k := range getUpdateOperators(req.Update) {
 			switch k {
-			case "$set", "$inc", "$mul", "$max", "$min", "$currentDate":
+			case "$set", "$inc", "$mul", "$max", "$min", "$currentDate", "$push", "$addToSet", "$pull":
 				sqlQuery, args, err := s.generateUpdateQuery(ctx, col, req, k)
 				if err != nil {
 					return 0, err
 				}
 				helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Update Query", map[string]interface{}{"sqlQuery": sqlQuery, "queryArgs": args})
-				res, err := doExecContext(ctx, sqlQuery, args, executor)
+				res, err := s.doExecContext(ctx, sqlQuery, args, executor)
 				if err != nil {
 					return 0, err
 				}
@@ -59,7 +60,7 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 				c, _ := res.RowsAffected()
 				count += c
 
-			default: // (case "$push", "$unset", "$rename")
+			default: // (case "$unset", "$rename")
 				return 0, utils.ErrInvalidParams
 			}
 		}
@@ -67,6 +68,16 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 		return count, nil
 
 	case utils.Upsert:
+		// Increments are upserted atomically so that concurrent increments of a counter never lose an update
+		if sqlQuery, args, ok := s.generateIncrementUpsertQuery(col, req); ok {
+			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Upsert Query", map[string]interface{}{"sqlQuery": sqlQuery, "queryArgs": args})
+			if _, err := s.doExecContext(ctx, sqlQuery, args, executor); err != nil {
+				return 0, err
+			}
+			// MySQL reports two affected rows when the existing row gets updated
+			return 1, nil
+		}
+
 		count, _, _, _, err := s.read(ctx, col, &model.ReadRequest{Find: req.Find, Operation: utils.All}, executor)
 		if err != nil {
 			return 0, err
@@ -93,7 +104,8 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 				if !ok {
 					return 0, utils.ErrInvalidParams
 				}
-				if op == "$currentDate" {
+				switch op {
+				case "$currentDate":
 					err := s.flattenForDate(ctx, &m)
 					if err != nil {
 						return 0, err
@@ -101,7 +113,27 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 					for k, v := range m { // k -> column name, v -> function name
 						dates[k] = v
 					}
-				} else {
+				case "$push", "$addToSet":
+					// The inserted document starts off with the values being added
+					for k, v := range m {
+						values, err := getArrayOperands(op, v)
+						if err != nil {
+							return 0, err
+						}
+						if op == "$addToSet" {
+							if values, err = uniqueJSONValues(values); err != nil {
+								return 0, err
+							}
+						}
+						data, err := marshalJSONValue(values)
+						if err != nil {
+							return 0, err
+						}
+						doc[k] = data
+					}
+				case "$pull":
+					// There is nothing to pull from a document which is being inserted
+				default:
 					for k, v := range m { // k -> column name
 						doc[k] = v
 					}
@@ -119,7 +151,7 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 				sqlQuery = sqlQuery[:l] + ", " + v.(string) + sqlQuery[l:]
 			}
 
-			res, err := doExecContext(ctx, sqlQuery, args, executor)
+			res, err := s.doExecContext(ctx, sqlQuery, args, executor)
 			if err != nil {
 				return 0, err
 			}
@@ -133,6 +165,68 @@ func (s *SQL) update(ctx context.Context, col string, req *model.UpdateRequest,
 	}
 }
 
+// generateIncrementUpsertQuery makes an insert query which increments the existing row instead if it conflicts with
+// it. It is only made for upserts of postgres and mysql which solely increment fields of the row matching a where
+// clause of equalities. The fields of the where clause must make up a unique key of the table.
+func (s *SQL) generateIncrementUpsertQuery(col string, req *model.UpdateRequest) (string, []interface{}, bool) {
+	if len(req.Update) != 1 || len(req.Find) == 0 {
+		return "", nil, false
+	}
+	inc, ok := req.Update["$inc"].(map[string]interface{})
+	if !ok || len(inc) == 0 {
+		return "", nil, false
+	}
+	if s.dbType != string(model.Postgres) && s.dbType != string(model.MySQL) {
+		return "", nil, false
+	}
+
+	doc := make(map[string]interface{}, len(req.Find)+len(inc))
+	keys := make([]string, 0, len(req.Find))
+	for k, v := range req.Find {
+		if obj, ok := v.(map[string]interface{}); ok {
+			eq, p := obj["$eq"]
+			if !p || len(obj) != 1 {
+				return "", nil, false
+			}
+			v = eq
+		}
+		if strings.Contains(k, ".") || strings.HasPrefix(k, "$") {
+			return "", nil, false
+		}
+		doc[k] = v
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(inc))
+	for k, v := range inc {
+		if _, p := doc[k]; p || strings.Contains(k, ".") {
+			return "", nil, false
+		}
+		doc[k] = v
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	sqlQuery, args, err := s.generateCreateQuery(col, &model.CreateRequest{Document: doc, Operation: utils.One})
+	if err != nil {
+		return "", nil, false
+	}
+
+	sets := make([]string, len(fields))
+	for i, field := range fields {
+		if s.dbType == string(model.MySQL) {
+			sets[i] = fmt.Sprintf("%s = %s + VALUES(%s)", field, field, field)
+			continue
+		}
+		sets[i] = fmt.Sprintf("%s = %s.%s + EXCLUDED.%s", field, s.getColName(col), field, field)
+	}
+	if s.dbType == string(model.MySQL) {
+		return sqlQuery + " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", "), args, true
+	}
+	return sqlQuery + " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO UPDATE SET " + strings.Join(sets, ", "), args, true
+}
+
 // generateUpdateQuery makes query for update operations
 func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.UpdateRequest, op string) (string, []interface{}, error) {
 	// Generate a prepared query builder
@@ -146,7 +240,7 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 
 	if req.Find != nil {
 		// Get the where clause from query object
-		query = s.generateWhereClause(ctx, query, req.Find, nil)
+		query = s.generateWhereClause(ctx, query, req.Find, nil, map[string]struct{}{col: {}})
 	}
 
 	if req.Update == nil {
@@ -168,6 +262,17 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 	if err != nil {
 		return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error generating update query unable to generate record %s", op), err, nil)
 	}
+	switch op {
+	case "$set":
+		if err := s.setJSONPaths(col, record); err != nil {
+			return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error generating update query unable to set json fields", err, nil)
+		}
+	case "$push", "$addToSet", "$pull":
+		record, err = s.generateArrayUpdateRecord(col, op, m)
+		if err != nil {
+			return "", nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error generating update query unable to apply %s", op), err, nil)
+		}
+	}
 
 	// Generate SQL string and arguments
 	sqlString, args, err := query.Update().Set(record).ToSQL()
@@ -178,15 +283,9 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 	sqlString = strings.Replace(sqlString, "\"", "", -1)
 	switch op {
 	case "$set":
-		for k := range m {
-			if s.dbType == string(model.Postgres) {
-				arr := strings.Split(k, ".")
-				if len(arr) >= 2 {
-					sqlString = strings.Replace(sqlString, k+"=$", fmt.Sprintf("%s=jsonb_set(%s, '{%s}', $", arr[0], arr[0], strings.Join(arr[1:], ",")), -1)
-					sqlString = s.sanitiseUpdateQuery(sqlString)
-				}
-			}
-		}
+		// Fields of json columns have already been replaced by setJSONPaths
+	case "$push", "$addToSet", "$pull":
+		// The record has already been generated by generateArrayUpdateRecord
 	case "$inc":
 		for k, v := range m {
 			_, err := checkIfNum(v)
@@ -275,6 +374,23 @@ func (s *SQL) generateUpdateQuery(ctx context.Context, col string, req *model.Up
 	return sqlString, args, nil
 }
 
+// getUpdateOperators returns the operators of an update in the order their queries are run. Each operator is run as
+// a separate query with the same where clause. $inc runs last since it increments the version of versioned documents,
+// which the where clause checks.
+func getUpdateOperators(update map[string]interface{}) []string {
+	ops := make([]string, 0, len(update))
+	for op := range update {
+		if op != "$inc" {
+			ops = append(ops, op)
+		}
+	}
+	sort.Strings(ops)
+	if _, ok := update["$inc"]; ok {
+		ops = append(ops, "$inc")
+	}
+	return ops
+}
+
 func checkIfNum(v interface{}) (string, error) {
 	switch val := v.(type) {
 	case float64:
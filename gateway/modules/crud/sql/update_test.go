@@ -39,6 +39,71 @@ func TestSQL_generateUpdateQuery(t *testing.T) {
 		// #######################################################################################
 		// ###################################  MySQL  ###########################################
 		// #######################################################################################
+		{
+			name:   "mysql: set fields within json column",
+			fields: fields{dbType: "mysql"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$set",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$set": map[string]interface{}{"meta.tags.0": "beta", "meta.views": 10}},
+					Find:   map[string]interface{}{"meta.owner": map[string]interface{}{"$in": []interface{}{"john", "jane"}}},
+				},
+			},
+			want:    "UPDATE col SET meta=JSON_SET(COALESCE(meta, JSON_OBJECT()), ?, CAST(? AS JSON), ?, CAST(? AS JSON)) WHERE (JSON_EXTRACT(meta, ?) = CAST(? AS JSON) OR JSON_EXTRACT(meta, ?) = CAST(? AS JSON))",
+			want1:   []interface{}{`$."tags"[0]`, `"beta"`, `$."views"`, "10", `$."owner"`, `"john"`, `$."owner"`, `"jane"`},
+			wantErr: false,
+		},
+		{
+			name:   "mysql: push values to json array column",
+			fields: fields{dbType: "mysql"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$push",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$push": map[string]interface{}{"tags": map[string]interface{}{"$each": []interface{}{"a", "b"}}}},
+					Find:   map[string]interface{}{"id": "1"},
+				},
+			},
+			want:    "UPDATE col SET tags=JSON_MERGE_PRESERVE(COALESCE(tags, JSON_ARRAY()), CAST(? AS JSON)) WHERE (id = ?)",
+			want1:   []interface{}{`["a","b"]`, "1"},
+			wantErr: false,
+		},
+		{
+			name:   "mysql: pull values from json array column",
+			fields: fields{dbType: "mysql"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$pull",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$pull": map[string]interface{}{"tags": map[string]interface{}{"$in": []interface{}{"a"}}}},
+					Find:   map[string]interface{}{"id": "1"},
+				},
+			},
+			want:    "UPDATE col SET tags=COALESCE((SELECT JSON_ARRAYAGG(e.value) FROM JSON_TABLE(COALESCE(tags, JSON_ARRAY()), '$[*]' COLUMNS (value JSON PATH '$')) AS e WHERE e.value NOT IN (SELECT v.value FROM JSON_TABLE(CAST(? AS JSON), '$[*]' COLUMNS (value JSON PATH '$')) AS v)), JSON_ARRAY()) WHERE (id = ?)",
+			want1:   []interface{}{`["a"]`, "1"},
+			wantErr: false,
+		},
+		{
+			name:   "mysql: pull from overlapping json fields",
+			fields: fields{dbType: "mysql"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$pull",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$pull": map[string]interface{}{"meta.tags": "a", "meta.tags.0": "b"}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name:   "msyql: valid set find on json",
 			fields: fields{dbType: "mysql"},
@@ -459,6 +524,85 @@ func TestSQL_generateUpdateQuery(t *testing.T) {
 		// #######################################################################################
 		// ###################################  Postgres  ########################################
 		// #######################################################################################
+		{
+			name:   "postgres: set fields within json column",
+			fields: fields{dbType: "postgres"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$set",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$set": map[string]interface{}{"meta.tags": []interface{}{"beta"}, "col.meta.views": 10, "String1": "1"}},
+					Find:   map[string]interface{}{"meta.owner": "john"},
+				},
+			},
+			want:    "UPDATE project.col SET String1=$1,meta=jsonb_set(jsonb_set(COALESCE(meta::jsonb, '{}'::jsonb), $2::text[], $3::jsonb, true), $4::text[], $5::jsonb, true) WHERE (meta::jsonb #> $6::text[]) = $7::jsonb",
+			want1:   []interface{}{"1", `{"views"}`, "10", `{"tags"}`, `["beta"]`, `{"owner"}`, `"john"`},
+			wantErr: false,
+		},
+		{
+			name:   "postgres: set json column along with its fields",
+			fields: fields{dbType: "postgres"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$set",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$set": map[string]interface{}{"meta.tags": []interface{}{"beta"}, "meta": "{}"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "postgres: add values to json array within json column",
+			fields: fields{dbType: "postgres"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$addToSet",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$addToSet": map[string]interface{}{"meta.tags": map[string]interface{}{"$each": []interface{}{"a", "a", "b"}}}},
+					Find:   map[string]interface{}{"id": "1"},
+				},
+			},
+			want:    "UPDATE project.col SET meta=jsonb_set(COALESCE(meta::jsonb, '{}'::jsonb), $1::text[], (COALESCE(COALESCE(meta::jsonb, '{}'::jsonb) #> $2::text[], '[]'::jsonb) || COALESCE((SELECT jsonb_agg(v.value ORDER BY v.ordinality) FROM jsonb_array_elements($3::jsonb) WITH ORDINALITY AS v(value, ordinality) WHERE v.value NOT IN (SELECT jsonb_array_elements(COALESCE(COALESCE(meta::jsonb, '{}'::jsonb) #> $4::text[], '[]'::jsonb)))), '[]'::jsonb)), true) WHERE (id = $5)",
+			want1:   []interface{}{`{"tags"}`, `{"tags"}`, `["a","b"]`, `{"tags"}`, "1"},
+			wantErr: false,
+		},
+		{
+			name:   "postgres: pull value from json array column",
+			fields: fields{dbType: "postgres"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$pull",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$pull": map[string]interface{}{"tags": map[string]interface{}{"id": 1}}},
+					Find:   map[string]interface{}{"id": "1"},
+				},
+			},
+			want:    "UPDATE project.col SET tags=COALESCE((SELECT jsonb_agg(e.value ORDER BY e.ordinality) FROM jsonb_array_elements(COALESCE(tags::jsonb, '[]'::jsonb)) WITH ORDINALITY AS e(value, ordinality) WHERE e.value NOT IN (SELECT jsonb_array_elements($1::jsonb))), '[]'::jsonb) WHERE (id = $2)",
+			want1:   []interface{}{`[{"id":1}]`, "1"},
+			wantErr: false,
+		},
+		{
+			name:   "postgres: push to json column along with its fields",
+			fields: fields{dbType: "postgres"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$push",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$push": map[string]interface{}{"meta": "a", "meta.tags": "b"}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name:   "postgres: valid set find on json",
 			fields: fields{dbType: "postgres"},
@@ -897,6 +1041,20 @@ func TestSQL_generateUpdateQuery(t *testing.T) {
 		// #######################################################################################
 		// ###################################  SQLServer  #######################################
 		// #######################################################################################
+		{
+			name:   "sqlserver: push to array",
+			fields: fields{dbType: "sqlserver"},
+			args: args{
+				ctx:     context.TODO(),
+				project: "project",
+				col:     "col",
+				op:      "$push",
+				req: model.UpdateRequest{
+					Update: map[string]interface{}{"$push": map[string]interface{}{"tags": "a"}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name:   "sqlserver: valid set ",
 			fields: fields{dbType: "sqlserver"},
@@ -1341,3 +1499,69 @@ func TestSQL_generateUpdateQuery(t *testing.T) {
 		})
 	}
 }
+
+func Test_getUpdateOperators(t *testing.T) {
+	update := map[string]interface{}{"$set": nil, "$inc": nil, "$push": nil, "$currentDate": nil}
+	want := []string{"$currentDate", "$push", "$set", "$inc"}
+	if got := getUpdateOperators(update); !reflect.DeepEqual(got, want) {
+		t.Errorf("getUpdateOperators() = %v, want %v", got, want)
+	}
+}
+
+func TestSQL_generateIncrementUpsertQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbType string
+		req    *model.UpdateRequest
+		want   string
+		want1  []interface{}
+		wantOk bool
+	}{
+		{
+			name:   "postgres: increment counters of a row",
+			dbType: "postgres",
+			req:    &model.UpdateRequest{Find: map[string]interface{}{"id": "1", "day": map[string]interface{}{"$eq": "mon"}}, Update: map[string]interface{}{"$inc": map[string]interface{}{"views": int64(1), "likes": int64(2)}}},
+			want:   "INSERT INTO project.posts (day, id, likes, views) VALUES ($1, $2, $3, $4) ON CONFLICT (day, id) DO UPDATE SET likes = project.posts.likes + EXCLUDED.likes, views = project.posts.views + EXCLUDED.views",
+			want1:  []interface{}{"mon", "1", int64(2), int64(1)},
+			wantOk: true,
+		},
+		{
+			name:   "mysql: increment counters of a row",
+			dbType: "mysql",
+			req:    &model.UpdateRequest{Find: map[string]interface{}{"id": "1"}, Update: map[string]interface{}{"$inc": map[string]interface{}{"views": int64(1)}}},
+			want:   "INSERT INTO posts (id, views) VALUES (?, ?) ON DUPLICATE KEY UPDATE views = views + VALUES(views)",
+			want1:  []interface{}{"1", int64(1)},
+			wantOk: true,
+		},
+		{
+			name:   "postgres: set along with the increment",
+			dbType: "postgres",
+			req:    &model.UpdateRequest{Find: map[string]interface{}{"id": "1"}, Update: map[string]interface{}{"$inc": map[string]interface{}{"views": int64(1)}, "$set": map[string]interface{}{"title": "a"}}},
+		},
+		{
+			name:   "postgres: where clause other than an equality",
+			dbType: "postgres",
+			req:    &model.UpdateRequest{Find: map[string]interface{}{"id": map[string]interface{}{"$gt": "1"}}, Update: map[string]interface{}{"$inc": map[string]interface{}{"views": int64(1)}}},
+		},
+		{
+			name:   "sqlserver: increment counters of a row",
+			dbType: "sqlserver",
+			req:    &model.UpdateRequest{Find: map[string]interface{}{"id": "1"}, Update: map[string]interface{}{"$inc": map[string]interface{}{"views": int64(1)}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SQL{dbType: tt.dbType, name: "project"}
+			got, got1, ok := s.generateIncrementUpsertQuery("posts", tt.req)
+			if ok != tt.wantOk {
+				t.Fatalf("SQL.generateIncrementUpsertQuery() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("SQL.generateIncrementUpsertQuery() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("SQL.generateIncrementUpsertQuery() got1 = %v, want1 %v", got1, tt.want1)
+			}
+		})
+	}
+}
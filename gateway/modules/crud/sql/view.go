@@ -0,0 +1,32 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// ReadView queries document(s) from a view. The query of the view is used as a sub query which gets filtered, sorted
+// and paginated like a table.
+func (s *SQL) ReadView(ctx context.Context, view *model.View, req *model.ReadRequest) (int64, interface{}, *model.SQLMetaData, error) {
+	if req.Options != nil && len(req.Options.Join) > 0 {
+		return 0, nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Joins cannot be performed on view (%s)", view.Name), nil, nil)
+	}
+
+	sqlString, args, err := s.generateViewQuery(ctx, view, req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Executing sql view query", map[string]interface{}{"sqlQuery": sqlString, "queryArgs": args})
+
+	n, result, _, metaData, err := s.readExec(ctx, view.Name, sqlString, args, s.getClient(), req)
+	return n, result, metaData, err
+}
+
+func (s *SQL) generateViewQuery(ctx context.Context, view *model.View, req *model.ReadRequest) (string, []interface{}, error) {
+	return s.generateReadQueryFrom(ctx, goqu.L("("+view.SQL+")", view.Args...).As(view.Name), view.Name, req)
+}
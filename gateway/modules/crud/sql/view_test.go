@@ -0,0 +1,54 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestSQL_generateViewQuery(t *testing.T) {
+	limit := int64(10)
+	tests := []struct {
+		name     string
+		dbType   string
+		view     *model.View
+		req      *model.ReadRequest
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "postgres view with arguments and a where clause",
+			dbType:   string(model.Postgres),
+			view:     &model.View{Name: "active_users", SQL: "SELECT id, name FROM test.users WHERE org = ? AND active = ?", Args: []interface{}{"org1", true}},
+			req:      &model.ReadRequest{Find: map[string]interface{}{"name": "john"}, Operation: utils.All, Options: &model.ReadOptions{Limit: &limit, Sort: []string{"-id"}}},
+			want:     "SELECT * FROM (SELECT id, name FROM test.users WHERE org = $1 AND active = $2) AS active_users WHERE (name = $3) ORDER BY id DESC LIMIT $4",
+			wantArgs: []interface{}{"org1", true, "john", int64(10)},
+		},
+		{
+			name:     "mysql view counting rows",
+			dbType:   string(model.MySQL),
+			view:     &model.View{Name: "orders_summary", SQL: "SELECT user_id, count(*) AS total FROM orders GROUP BY user_id"},
+			req:      &model.ReadRequest{Find: map[string]interface{}{"total": map[string]interface{}{"$gt": 5}}, Operation: utils.Count, Options: &model.ReadOptions{}},
+			want:     "SELECT COUNT(*) FROM (SELECT user_id, count(*) AS total FROM orders GROUP BY user_id) AS orders_summary WHERE (total > ?)",
+			wantArgs: []interface{}{int64(5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SQL{dbType: tt.dbType, name: "test"}
+			got, args, err := s.generateViewQuery(context.Background(), tt.view, tt.req)
+			if err != nil {
+				t.Fatalf("SQL.generateViewQuery() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SQL.generateViewQuery() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQL.generateViewQuery() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package crud
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+const (
+	// defaultSlowQueryThreshold is used when the slow query threshold isn't set in the database config
+	defaultSlowQueryThreshold = time.Second
+
+	// maxLatencySamples is the number of recent latencies kept per collection and operation to compute the percentiles
+	maxLatencySamples = 1000
+
+	// maxSlowQueries is the number of recent slow queries kept per database
+	maxSlowQueries = 100
+
+	// placeholder replaces the values in the recorded slow queries
+	placeholder = "?"
+)
+
+// queryStats collects the latency distribution and the slow queries of a database
+type queryStats struct {
+	lock sync.Mutex
+
+	threshold   time.Duration
+	ops         map[opKey]*opStats
+	slowQueries []*model.SlowQuery // ring buffer
+	next        int
+}
+
+type opKey struct {
+	col, op string
+}
+
+type opStats struct {
+	count, errors int64
+	total, max    time.Duration
+	samples       []time.Duration // ring buffer of the recent latencies
+	next          int
+}
+
+func newQueryStats() *queryStats {
+	return &queryStats{threshold: defaultSlowQueryThreshold, ops: map[opKey]*opStats{}}
+}
+
+// setThreshold sets the slow query threshold in milli seconds
+func (s *queryStats) setThreshold(ms int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.threshold = defaultSlowQueryThreshold
+	if ms > 0 {
+		s.threshold = time.Duration(ms) * time.Millisecond
+	}
+}
+
+// record adds the latency of an operation. The query gets normalised and recorded if it exceeds the threshold.
+func (s *queryStats) record(ctx context.Context, col, op string, query interface{}, duration time.Duration, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := opKey{col: col, op: op}
+	stats, p := s.ops[key]
+	if !p {
+		stats = &opStats{samples: make([]time.Duration, 0, 16)}
+		s.ops[key] = stats
+	}
+	stats.add(duration, err)
+
+	if duration < s.threshold {
+		return
+	}
+
+	slowQuery := &model.SlowQuery{
+		Time:       time.Now().Add(-duration),
+		RequestID:  helpers.GetRequestID(ctx),
+		Col:        col,
+		Op:         op,
+		DurationMS: toMS(duration),
+		Query:      normaliseQuery(query),
+	}
+	if err != nil {
+		slowQuery.Error = err.Error()
+	}
+
+	if len(s.slowQueries) < maxSlowQueries {
+		s.slowQueries = append(s.slowQueries, slowQuery)
+		return
+	}
+	s.slowQueries[s.next] = slowQuery
+	s.next = (s.next + 1) % maxSlowQueries
+}
+
+func (o *opStats) add(duration time.Duration, err error) {
+	o.count++
+	if err != nil {
+		o.errors++
+	}
+	o.total += duration
+	if duration > o.max {
+		o.max = duration
+	}
+
+	if len(o.samples) < maxLatencySamples {
+		o.samples = append(o.samples, duration)
+		return
+	}
+	o.samples[o.next] = duration
+	o.next = (o.next + 1) % maxLatencySamples
+}
+
+// getStats returns the latency distribution of the operations on the provided collection. Stats of all
+// collections are returned if col is empty.
+func (s *queryStats) getStats(col string) []*model.QueryStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	arr := make([]*model.QueryStats, 0, len(s.ops))
+	for key, stats := range s.ops {
+		if col != "" && key.col != col {
+			continue
+		}
+
+		samples := make([]time.Duration, len(stats.samples))
+		copy(samples, stats.samples)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+		arr = append(arr, &model.QueryStats{
+			Col:    key.col,
+			Op:     key.op,
+			Count:  stats.count,
+			Errors: stats.errors,
+			AvgMS:  toMS(stats.total / time.Duration(stats.count)),
+			P50MS:  toMS(percentile(samples, 0.50)),
+			P95MS:  toMS(percentile(samples, 0.95)),
+			P99MS:  toMS(percentile(samples, 0.99)),
+			MaxMS:  toMS(stats.max),
+		})
+	}
+
+	// Slowest operations come first
+	sort.Slice(arr, func(i, j int) bool {
+		if arr[i].P95MS != arr[j].P95MS {
+			return arr[i].P95MS > arr[j].P95MS
+		}
+		if arr[i].Col != arr[j].Col {
+			return arr[i].Col < arr[j].Col
+		}
+		return arr[i].Op < arr[j].Op
+	})
+	return arr
+}
+
+// getSlowQueries returns the recorded slow queries of the provided collection with the most recent one first.
+// Slow queries of all collections are returned if col is empty.
+func (s *queryStats) getSlowQueries(col string) []*model.SlowQuery {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	arr := make([]*model.SlowQuery, 0, len(s.slowQueries))
+	for i := len(s.slowQueries) - 1; i >= 0; i-- {
+		q := s.slowQueries[(s.next+i)%len(s.slowQueries)]
+		if col != "" && q.Col != col {
+			continue
+		}
+		arr = append(arr, q)
+	}
+	return arr
+}
+
+// percentile returns the nearest rank percentile of the sorted samples
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	index := int(p*float64(len(samples))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(samples) {
+		index = len(samples) - 1
+	}
+	return samples[index]
+}
+
+func toMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// normaliseQuery replaces the values in a query with placeholders while retaining its shape, so that
+// similar queries look the same and no user data ends up in the slow query log
+func normaliseQuery(query interface{}) interface{} {
+	switch v := query.(type) {
+	case nil:
+		return nil
+	case string:
+		// Raw queries are already parameterised
+		return v
+	case map[string]interface{}:
+		return normaliseValue(v)
+	case []interface{}:
+		return normaliseValue(v)
+	default:
+		return placeholder
+	}
+}
+
+func normaliseValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			obj[k] = normaliseValue(val)
+		}
+		return obj
+	case []interface{}:
+		// Arrays of values (like the ones used by the in operator) collapse into a single placeholder
+		arr := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				arr = append(arr, normaliseValue(item))
+			default:
+				if len(arr) == 0 || arr[len(arr)-1] != placeholder {
+					arr = append(arr, placeholder)
+				}
+			}
+		}
+		return arr
+	default:
+		return placeholder
+	}
+}
@@ -0,0 +1,98 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormaliseQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query interface{}
+		want  interface{}
+	}{
+		{name: "no query", query: nil, want: nil},
+		{name: "raw query is left untouched", query: "SELECT * FROM users WHERE id = $1", want: "SELECT * FROM users WHERE id = $1"},
+		{
+			name:  "values are replaced with placeholders",
+			query: map[string]interface{}{"id": "1", "age": map[string]interface{}{"$gt": 20}},
+			want:  map[string]interface{}{"id": "?", "age": map[string]interface{}{"$gt": "?"}},
+		},
+		{
+			name:  "arrays of values collapse into a single placeholder",
+			query: map[string]interface{}{"id": map[string]interface{}{"$in": []interface{}{1, 2, 3}}},
+			want:  map[string]interface{}{"id": map[string]interface{}{"$in": []interface{}{"?"}}},
+		},
+		{
+			name:  "nested clauses retain their shape",
+			query: map[string]interface{}{"$or": []interface{}{map[string]interface{}{"a": 1}, map[string]interface{}{"b": true}}},
+			want:  map[string]interface{}{"$or": []interface{}{map[string]interface{}{"a": "?"}, map[string]interface{}{"b": "?"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normaliseQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normaliseQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryStats(t *testing.T) {
+	s := newQueryStats()
+	s.setThreshold(50)
+
+	ctx := context.Background()
+	for i := 1; i <= 100; i++ {
+		s.record(ctx, "users", "read", map[string]interface{}{"id": i}, time.Duration(i)*time.Millisecond, nil)
+	}
+	s.record(ctx, "orders", "update", nil, 10*time.Millisecond, errors.New("failed"))
+
+	stats := s.getStats("")
+	if len(stats) != 2 {
+		t.Fatalf("getStats() returned %d stats, want 2", len(stats))
+	}
+	users := stats[0]
+	if users.Col != "users" || users.Count != 100 || users.P50MS != 50 || users.P95MS != 95 || users.P99MS != 99 || users.MaxMS != 100 || users.AvgMS != 50.5 {
+		t.Errorf("getStats() users = %+v", users)
+	}
+	if orders := stats[1]; orders.Col != "orders" || orders.Errors != 1 {
+		t.Errorf("getStats() orders = %+v", orders)
+	}
+
+	if got := s.getStats("orders"); len(got) != 1 || got[0].Col != "orders" {
+		t.Errorf("getStats(orders) = %+v", got)
+	}
+
+	slowQueries := s.getSlowQueries("users")
+	if len(slowQueries) != 51 {
+		t.Fatalf("getSlowQueries() returned %d queries, want 51", len(slowQueries))
+	}
+	if slowQueries[0].DurationMS != 100 || !reflect.DeepEqual(slowQueries[0].Query, map[string]interface{}{"id": "?"}) {
+		t.Errorf("getSlowQueries() most recent = %+v", slowQueries[0])
+	}
+	if len(s.getSlowQueries("orders")) != 0 {
+		t.Errorf("getSlowQueries() recorded a query below the threshold")
+	}
+}
+
+func TestQueryStats_SlowQueryLimit(t *testing.T) {
+	s := newQueryStats()
+	s.setThreshold(1)
+
+	ctx := context.Background()
+	for i := 1; i <= maxSlowQueries+10; i++ {
+		s.record(ctx, "users", "read", nil, time.Duration(i)*time.Millisecond, nil)
+	}
+
+	slowQueries := s.getSlowQueries("")
+	if len(slowQueries) != maxSlowQueries {
+		t.Fatalf("getSlowQueries() returned %d queries, want %d", len(slowQueries), maxSlowQueries)
+	}
+	if first, last := slowQueries[0].DurationMS, slowQueries[maxSlowQueries-1].DurationMS; first != maxSlowQueries+10 || last != 11 {
+		t.Errorf("getSlowQueries() returned queries from %vms to %vms, want from %vms to 11ms", first, last, maxSlowQueries+10)
+	}
+}
@@ -0,0 +1,103 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/plugins"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// defaultStreamLimit is the max number of documents streamed by a request when the database doesn't configure one
+const defaultStreamLimit int64 = 1000000
+
+// ReadStream reads the documents matching a query and passes them to fn one at a time. Unlike Read, the result is
+// never held in memory, which makes it suitable for large exports. The number of documents streamed per request is
+// capped by the stream limit of the database.
+func (m *Module) ReadStream(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams, fn func(doc map[string]interface{}) error) error {
+	m.RLock()
+	defer m.RUnlock()
+
+	t, err := m.getTenant(ctx, dbAlias, params)
+	if err != nil {
+		return err
+	}
+	dbAlias = t.dbAlias
+	req.Find = t.scopeFind(req.Find)
+
+	if err := validateStreamRequest(ctx, col, req); err != nil {
+		return err
+	}
+	if _, ok := m.getView(dbAlias, col); ok {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot stream view (%s)", col), nil, nil)
+	}
+
+	dbType, err := m.getDBType(dbAlias)
+	if err != nil {
+		return err
+	}
+	if err := schemaHelpers.AdjustWhereClause(ctx, dbAlias, model.DBType(dbType), col, m.schemaDoc, req.Find); err != nil {
+		return err
+	}
+
+	dbInfo, err := m.getDBInfo(dbAlias)
+	if err != nil {
+		return err
+	}
+	limit := dbInfo.StreamLimit
+	if limit <= 0 {
+		limit = defaultStreamLimit
+	}
+	if req.Options.Limit == nil || *req.Options.Limit > limit {
+		req.Options.Limit = &limit
+		req.Options.HasOptions = true
+	}
+
+	crud, err := m.getCrudBlock(dbAlias)
+	if err != nil {
+		return err
+	}
+	if err := crud.IsClientSafe(ctx); err != nil {
+		return err
+	}
+
+	block := unwrapCrud(crud)
+	s, ok := block.(streamingCrud)
+	if !ok {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Database (%s) of type (%s) does not support streaming reads", dbAlias, dbType), nil, nil)
+	}
+
+	pluginReq := &plugins.CrudRequest{Project: m.project, DBAlias: dbAlias, Col: col, Op: model.Read, Payload: req, Params: params}
+	if err := plugins.BeforeCrud(ctx, pluginReq); err != nil {
+		return err
+	}
+
+	n, err := s.ReadStream(ctx, col, req, func(doc map[string]interface{}) error {
+		if err := schemaHelpers.CrudPostProcess(ctx, dbAlias, dbType, col, m.schemaDoc, doc); err != nil {
+			return err
+		}
+		return fn(doc)
+	})
+	m.metricHook(m.project, dbAlias, col, n, model.Read)
+	plugins.AfterCrud(ctx, pluginReq, nil, err)
+	return err
+}
+
+// validateStreamRequest checks that the documents of a read can be sent as they are read. Joins and aggregations
+// need the entire result before the first document can be sent, hence they can't be streamed.
+func validateStreamRequest(ctx context.Context, col string, req *model.ReadRequest) error {
+	if req.Options == nil {
+		req.Options = &model.ReadOptions{}
+	}
+	if req.Operation != utils.All {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid operation (%s) provided to stream collection (%s) - only %s is supported", req.Operation, col, utils.All), nil, nil)
+	}
+	if len(req.Aggregate) > 0 || len(req.GroupBy) > 0 || len(req.Options.Join) > 0 || req.Options.Distinct != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Joins, aggregations and distinct cannot be used while streaming collection (%s)", col), nil, nil)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package crud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_validateStreamRequest(t *testing.T) {
+	distinct := "name"
+	tests := []struct {
+		name    string
+		req     *model.ReadRequest
+		wantErr bool
+	}{
+		{
+			name: "read all without options",
+			req:  &model.ReadRequest{Operation: utils.All},
+		},
+		{
+			name:    "read one",
+			req:     &model.ReadRequest{Operation: utils.One},
+			wantErr: true,
+		},
+		{
+			name:    "read with joins",
+			req:     &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Join: []*model.JoinOption{{Table: "posts"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "read with aggregations",
+			req:     &model.ReadRequest{Operation: utils.All, Aggregate: map[string][]string{"count": {"id"}}},
+			wantErr: true,
+		},
+		{
+			name:    "read distinct",
+			req:     &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Distinct: &distinct}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateStreamRequest(context.Background(), "users", tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("validateStreamRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.req.Options == nil {
+				t.Errorf("validateStreamRequest() did not initialise the read options")
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	defaultTenantClaim  = "tenant"
+	defaultTenantColumn = "tenant_id"
+)
+
+type tenancyScopedKey struct{}
+
+// withTenancyScoped marks the requests made with the context as already scoped to a tenant. It is used by
+// the data loader which merges requests that have been scoped individually.
+func withTenancyScoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenancyScopedKey{}, true)
+}
+
+func isTenancyScoped(ctx context.Context) bool {
+	v, _ := ctx.Value(tenancyScopedKey{}).(bool)
+	return v
+}
+
+// tenant scopes a request to the tenant derived from the claims of the request
+type tenant struct {
+	// dbAlias is the database the request needs to be forwarded to
+	dbAlias string
+
+	// column and id are set in the column mode only
+	column string
+	id     interface{}
+}
+
+// validateTenancyConfig checks if the tenancy config of a database is valid
+func validateTenancyConfig(dbAlias string, c *config.TenancyConfig, crud config.DatabaseConfigs) error {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	switch c.Mode {
+	case config.TenancyModeColumn, "":
+		return nil
+	case config.TenancyModeDatabase:
+		for tenantID, alias := range c.Databases {
+			p := false
+			for _, dbConfig := range crud {
+				if dbConfig.DbAlias == alias {
+					p = dbConfig.Tenancy == nil || !dbConfig.Tenancy.Enabled
+					break
+				}
+			}
+			if !p {
+				return fmt.Errorf("database (%s) of tenant (%s) in the tenancy config of (%s) either does not exist or has tenancy enabled itself", alias, tenantID, dbAlias)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid tenancy mode (%s) provided for database (%s)", c.Mode, dbAlias)
+	}
+}
+
+// getTenant returns the tenant of the request. Requests to databases without tenancy are left untouched.
+//
+// The gateway itself makes requests on behalf of every tenant, like signing in with email, scim provisioning and
+// enforcing the retention policies. Its requests carry the internal user id and are left unscoped unless they have
+// the tenant claim, in which case they get scoped like any other request. Unscoped requests in the database mode are
+// forwarded to the database the tenancy is configured on.
+func (m *Module) getTenant(ctx context.Context, dbAlias string, params model.RequestParams) (*tenant, error) {
+	dbConfig, p := m.databaseConfigs[dbAlias]
+	if !p || dbConfig.Tenancy == nil || !dbConfig.Tenancy.Enabled || isTenancyScoped(ctx) {
+		return &tenant{dbAlias: dbAlias}, nil
+	}
+	c := dbConfig.Tenancy
+
+	claim := c.Claim
+	if claim == "" {
+		claim = defaultTenantClaim
+	}
+	id, err := utils.LoadValue("auth."+claim, map[string]interface{}{"auth": params.Claims})
+	if err != nil || id == nil || id == "" {
+		if caller, _ := params.Claims["id"].(string); caller == utils.InternalUserID {
+			return &tenant{dbAlias: dbAlias}, nil
+		}
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to determine tenant, claim (%s) is missing in the token", claim), err, map[string]interface{}{"dbAlias": dbAlias})
+	}
+
+	if c.Mode == config.TenancyModeDatabase {
+		alias, p := c.Databases[fmt.Sprintf("%v", id)]
+		if !p {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No database has been provisioned for tenant (%v)", id), nil, map[string]interface{}{"dbAlias": dbAlias})
+		}
+		return &tenant{dbAlias: strings.TrimPrefix(alias, "sql-")}, nil
+	}
+
+	column := c.Column
+	if column == "" {
+		column = defaultTenantColumn
+	}
+	return &tenant{dbAlias: dbAlias, column: column, id: id}, nil
+}
+
+func (t *tenant) isColumnMode() bool {
+	return t.column != ""
+}
+
+// scopeDocument sets the tenant id in the documents being inserted
+func (t *tenant) scopeDocument(doc interface{}) {
+	if !t.isColumnMode() {
+		return
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		v[t.column] = t.id
+	case []interface{}:
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				obj[t.column] = t.id
+			}
+		}
+	}
+}
+
+// scopeFind restricts the where clause to the documents of the tenant. A condition on the tenant column
+// provided by the client gets overwritten.
+func (t *tenant) scopeFind(find map[string]interface{}) map[string]interface{} {
+	if !t.isColumnMode() {
+		return find
+	}
+
+	if find == nil {
+		find = map[string]interface{}{}
+	}
+	find[t.column] = t.id
+	return find
+}
+
+// scopeRead restricts a read to the documents of the tenant. The tables joined in a read have a tenant column of their
+// own, hence the tenant column in the where clause is qualified with the table being read and the rows of every joined
+// table are matched with the tenant of the row they get joined to.
+func (t *tenant) scopeRead(col string, req *model.ReadRequest) {
+	if !t.isColumnMode() {
+		return
+	}
+
+	if req.Options == nil || len(req.Options.Join) == 0 {
+		req.Find = t.scopeFind(req.Find)
+		return
+	}
+
+	if req.Find == nil {
+		req.Find = map[string]interface{}{}
+	}
+	req.Find[col+"."+t.column] = t.id
+	t.scopeJoins(col, req.Options.Join)
+}
+
+// scopeJoins adds the tenant column to the on clause of the joins. The tenant column of the parent table is the key
+// so that it's matched with the tenant column of the joined table, as required by every join strategy.
+func (t *tenant) scopeJoins(parent string, joins []*model.JoinOption) {
+	for _, j := range joins {
+		if j.On == nil {
+			j.On = map[string]interface{}{}
+		}
+		j.On[parent+"."+t.column] = j.Table + "." + t.column
+		t.scopeJoins(j.Table, j.Join)
+	}
+}
+
+// scopeUpdate prevents the tenant column from being modified. Upserts set the tenant id in the inserted document.
+func (t *tenant) scopeUpdate(op string, update map[string]interface{}) map[string]interface{} {
+	if !t.isColumnMode() {
+		return update
+	}
+
+	for _, v := range update {
+		if obj, ok := v.(map[string]interface{}); ok {
+			delete(obj, t.column)
+		}
+	}
+
+	if op == utils.Upsert {
+		if update == nil {
+			update = map[string]interface{}{}
+		}
+		set, ok := update["$set"].(map[string]interface{})
+		if !ok {
+			set = map[string]interface{}{}
+			update["$set"] = set
+		}
+		set[t.column] = t.id
+	}
+	return update
+}
+
+// scopePipeline adds a match stage for the tenant at the start of an aggregation pipeline
+func (t *tenant) scopePipeline(pipeline interface{}) interface{} {
+	if !t.isColumnMode() {
+		return pipeline
+	}
+
+	stages, _ := pipeline.([]interface{})
+	return append([]interface{}{map[string]interface{}{"$match": map[string]interface{}{t.column: t.id}}}, stages...)
+}
+
+// scopeBatch scopes every request of a batch to the tenant
+func (t *tenant) scopeBatch(req *model.BatchRequest) {
+	for _, r := range req.Requests {
+		switch r.Type {
+		case string(model.Create):
+			t.scopeDocument(r.Document)
+		case string(model.Update):
+			r.Find = t.scopeFind(r.Find)
+			r.Update = t.scopeUpdate(r.Operation, r.Update)
+		case string(model.Delete):
+			r.Find = t.scopeFind(r.Find)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestModule_getTenant(t *testing.T) {
+	m := &Module{databaseConfigs: config.DatabaseConfigs{
+		"shared":  &config.DatabaseConfig{DbAlias: "shared", Tenancy: &config.TenancyConfig{Enabled: true, Mode: config.TenancyModeColumn, Claim: "org.id"}},
+		"tenants": &config.DatabaseConfig{DbAlias: "tenants", Tenancy: &config.TenancyConfig{Enabled: true, Mode: config.TenancyModeDatabase, Databases: map[string]string{"acme": "sql-acme"}}},
+		"plain":   &config.DatabaseConfig{DbAlias: "plain"},
+	}}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		dbAlias string
+		claims  map[string]interface{}
+		want    *tenant
+		wantErr bool
+	}{
+		{name: "database without tenancy", dbAlias: "plain", want: &tenant{dbAlias: "plain"}},
+		{name: "column mode with nested claim", dbAlias: "shared", claims: map[string]interface{}{"org": map[string]interface{}{"id": 7}}, want: &tenant{dbAlias: "shared", column: defaultTenantColumn, id: 7}},
+		{name: "column mode without claim", dbAlias: "shared", claims: map[string]interface{}{"id": "user"}, wantErr: true},
+		{name: "database mode", dbAlias: "tenants", claims: map[string]interface{}{"tenant": "acme"}, want: &tenant{dbAlias: "acme"}},
+		{name: "database mode with unknown tenant", dbAlias: "tenants", claims: map[string]interface{}{"tenant": "globex"}, wantErr: true},
+		{name: "internal request in column mode", dbAlias: "shared", claims: utils.InternalClaims(), want: &tenant{dbAlias: "shared"}},
+		{name: "internal request in database mode", dbAlias: "tenants", claims: utils.InternalClaims(), want: &tenant{dbAlias: "tenants"}},
+		{name: "internal request with tenant claim", dbAlias: "tenants", claims: map[string]interface{}{"id": utils.InternalUserID, "tenant": "acme"}, want: &tenant{dbAlias: "acme"}},
+		{name: "already scoped request", ctx: withTenancyScoped(context.Background()), dbAlias: "shared", want: &tenant{dbAlias: "shared"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			got, err := m.getTenant(ctx, tt.dbAlias, model.RequestParams{Claims: tt.claims})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getTenant() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenant_scope(t *testing.T) {
+	tn := &tenant{dbAlias: "db", column: "tenant_id", id: "acme"}
+
+	docs := []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b", "tenant_id": "globex"}}
+	tn.scopeDocument(docs)
+	if want := []interface{}{map[string]interface{}{"name": "a", "tenant_id": "acme"}, map[string]interface{}{"name": "b", "tenant_id": "acme"}}; !reflect.DeepEqual(docs, want) {
+		t.Errorf("scopeDocument() = %v, want %v", docs, want)
+	}
+
+	if got, want := tn.scopeFind(nil), map[string]interface{}{"tenant_id": "acme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scopeFind() = %v, want %v", got, want)
+	}
+	if got, want := tn.scopeFind(map[string]interface{}{"tenant_id": "globex", "id": 1}), map[string]interface{}{"tenant_id": "acme", "id": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scopeFind() = %v, want %v", got, want)
+	}
+
+	update := tn.scopeUpdate(utils.All, map[string]interface{}{"$set": map[string]interface{}{"tenant_id": "globex", "name": "a"}})
+	if want := map[string]interface{}{"$set": map[string]interface{}{"name": "a"}}; !reflect.DeepEqual(update, want) {
+		t.Errorf("scopeUpdate() = %v, want %v", update, want)
+	}
+	update = tn.scopeUpdate(utils.Upsert, map[string]interface{}{"$inc": map[string]interface{}{"count": 1}})
+	if want := map[string]interface{}{"$inc": map[string]interface{}{"count": 1}, "$set": map[string]interface{}{"tenant_id": "acme"}}; !reflect.DeepEqual(update, want) {
+		t.Errorf("scopeUpdate() upsert = %v, want %v", update, want)
+	}
+
+	pipeline := tn.scopePipeline([]interface{}{map[string]interface{}{"$limit": 1}})
+	if want := []interface{}{map[string]interface{}{"$match": map[string]interface{}{"tenant_id": "acme"}}, map[string]interface{}{"$limit": 1}}; !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("scopePipeline() = %v, want %v", pipeline, want)
+	}
+
+	// Requests to databases in the database mode are left untouched
+	find := map[string]interface{}{"id": 1}
+	if got := (&tenant{dbAlias: "acme"}).scopeFind(find); !reflect.DeepEqual(got, map[string]interface{}{"id": 1}) {
+		t.Errorf("scopeFind() modified the request in database mode = %v", got)
+	}
+}
+
+func TestTenant_scopeRead(t *testing.T) {
+	tn := &tenant{dbAlias: "db", column: "tenant_id", id: "acme"}
+
+	tests := []struct {
+		name     string
+		req      *model.ReadRequest
+		wantFind map[string]interface{}
+		wantJoin []*model.JoinOption
+	}{
+		{
+			name:     "read without joins",
+			req:      &model.ReadRequest{Find: map[string]interface{}{"id": 1}, Options: &model.ReadOptions{}},
+			wantFind: map[string]interface{}{"id": 1, "tenant_id": "acme"},
+		},
+		{
+			name: "read with nested joins",
+			req: &model.ReadRequest{
+				Find: map[string]interface{}{"posts.tenant_id": "globex"},
+				Options: &model.ReadOptions{Join: []*model.JoinOption{
+					{Table: "comments", On: map[string]interface{}{"posts.id": "comments.post_id"}, Join: []*model.JoinOption{
+						{Table: "likes", On: map[string]interface{}{"comments.id": "likes.comment_id"}},
+					}},
+					{Table: "tags", Strategy: model.JoinStrategyJSON, On: map[string]interface{}{"posts.id": "tags.post_id"}},
+				}},
+			},
+			wantFind: map[string]interface{}{"posts.tenant_id": "acme"},
+			wantJoin: []*model.JoinOption{
+				{Table: "comments", On: map[string]interface{}{"posts.id": "comments.post_id", "posts.tenant_id": "comments.tenant_id"}, Join: []*model.JoinOption{
+					{Table: "likes", On: map[string]interface{}{"comments.id": "likes.comment_id", "comments.tenant_id": "likes.tenant_id"}},
+				}},
+				{Table: "tags", Strategy: model.JoinStrategyJSON, On: map[string]interface{}{"posts.id": "tags.post_id", "posts.tenant_id": "tags.tenant_id"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tn.scopeRead("posts", tt.req)
+			if !reflect.DeepEqual(tt.req.Find, tt.wantFind) {
+				t.Errorf("scopeRead() find = %v, want %v", tt.req.Find, tt.wantFind)
+			}
+			if !reflect.DeepEqual(tt.req.Options.Join, tt.wantJoin) {
+				t.Errorf("scopeRead() join = %v, want %v", tt.req.Options.Join, tt.wantJoin)
+			}
+		})
+	}
+}
+
+func TestValidateTenancyConfig(t *testing.T) {
+	crud := config.DatabaseConfigs{
+		"acme":    &config.DatabaseConfig{DbAlias: "acme"},
+		"tenants": &config.DatabaseConfig{DbAlias: "tenants", Tenancy: &config.TenancyConfig{Enabled: true, Mode: config.TenancyModeDatabase}},
+	}
+	tests := []struct {
+		name    string
+		c       *config.TenancyConfig
+		wantErr bool
+	}{
+		{name: "tenancy disabled", c: &config.TenancyConfig{Mode: "invalid"}},
+		{name: "column mode", c: &config.TenancyConfig{Enabled: true}},
+		{name: "valid database mode", c: &config.TenancyConfig{Enabled: true, Mode: config.TenancyModeDatabase, Databases: map[string]string{"acme": "acme"}}},
+		{name: "unknown tenant database", c: &config.TenancyConfig{Enabled: true, Mode: config.TenancyModeDatabase, Databases: map[string]string{"globex": "globex"}}, wantErr: true},
+		{name: "tenant database with tenancy", c: &config.TenancyConfig{Enabled: true, Mode: config.TenancyModeDatabase, Databases: map[string]string{"acme": "tenants"}}, wantErr: true},
+		{name: "invalid mode", c: &config.TenancyConfig{Enabled: true, Mode: "schema"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTenancyConfig("db", tt.c, crud); (err != nil) != tt.wantErr {
+				t.Errorf("validateTenancyConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// getVersionField returns the field of a collection marked with the @version directive
+func (m *Module) getVersionField(dbAlias, col string) (string, bool) {
+	for name, field := range m.schemaDoc[dbAlias][col] {
+		if field.IsVersion {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// checkVersionConflict tells apart an update of a versioned collection which didn't match any document because of a
+// stale version from one whose document doesn't exist
+func (m *Module) checkVersionConflict(ctx context.Context, crud Crud, dbAlias, col, op string, find map[string]interface{}, n int64) error {
+	field, ok := m.getVersionField(dbAlias, col)
+	if !ok || n > 0 || op == utils.Upsert {
+		return nil
+	}
+	if _, ok := find[field]; !ok {
+		return nil
+	}
+
+	withoutVersion := make(map[string]interface{}, len(find))
+	for k, v := range find {
+		if k != field {
+			withoutVersion[k] = v
+		}
+	}
+	count, _, _, _, err := crud.Read(ctx, col, &model.ReadRequest{Find: withoutVersion, Operation: utils.Count})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return utils.ErrVersionConflict
+	}
+	return nil
+}
+
+// checkBatchVersionConflicts returns a conflict if a versioned update of a batch didn't match any document. The
+// version check itself is done by the database, so the update was never applied. The other operations of the batch
+// however have been committed by then.
+func (m *Module) checkBatchVersionConflicts(ctx context.Context, crud Crud, dbAlias string, req *model.BatchRequest, counts []int64) error {
+	for i, r := range req.Requests {
+		if r.Type != string(model.Update) {
+			continue
+		}
+		if err := m.checkVersionConflict(ctx, crud, dbAlias, r.Col, r.Operation, r.Find, counts[i]); err != nil {
+			return fmt.Errorf("%w - update (%d) of the batch on (%s) was not applied while the rest of the batch was", err, i, r.Col)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// getView returns the prepared query which defines the view having the provided name
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) getView(dbAlias, col string) (*config.DatbasePreparedQuery, bool) {
+	q, ok := m.queries[getPreparedQueryKey(dbAlias, col)]
+	if !ok || !q.IsView {
+		return nil, false
+	}
+	return q, true
+}
+
+// checkNotView returns an error if the collection is a view since views are read only
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) checkNotView(ctx context.Context, dbAlias, col string) error {
+	if _, ok := m.getView(dbAlias, col); ok {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot modify view (%s) of database (%s) since views are read only", col, dbAlias), nil, nil)
+	}
+	return nil
+}
+
+// readView binds the arguments of a view and reads the documents matching the request from it
+// NOTE: the parent function should take lock on module before calling this function
+func (m *Module) readView(ctx context.Context, block Crud, dbAlias string, q *config.DatbasePreparedQuery, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	block = unwrapCrud(block)
+	r, ok := block.(viewReader)
+	if !ok {
+		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Database (%s) of type (%s) does not support views", dbAlias, block.GetDBType()), nil, nil)
+	}
+
+	// Load the arguments
+	state := map[string]interface{}{"args": req.Extras, "auth": params.Claims}
+	values := make(map[string]interface{}, len(q.Arguments))
+	args := make([]interface{}, len(q.Arguments))
+	for i, key := range q.Arguments {
+		arg, err := utils.LoadValue(key, state)
+		if err != nil {
+			return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to load argument (%s) of view (%s)", key, q.ID), err, nil)
+		}
+		values[key] = arg
+		args[i] = arg
+	}
+
+	view := &model.View{Name: q.ID, SQL: q.SQL, Args: args, Col: q.Col}
+	if len(q.Pipeline) > 0 {
+		view.Pipeline = bindViewArgs(q.Pipeline, values).([]interface{})
+	}
+
+	n, result, metaData, err := r.ReadView(ctx, view, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	m.metricHook(m.project, dbAlias, q.ID, n, model.Read)
+
+	if metaData != nil {
+		metaData.DbAlias = dbAlias
+		metaData.Col = q.ID
+	}
+	return result, metaData, nil
+}
+
+// bindViewArgs returns a copy of the pipeline of a view in which the string values matching one of the arguments
+// are replaced by the value of that argument
+func bindViewArgs(value interface{}, values map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if arg, ok := values[v]; ok {
+			return arg
+		}
+		return v
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = bindViewArgs(item, values)
+		}
+		return arr
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			obj[k] = bindViewArgs(item, values)
+		}
+		return obj
+	default:
+		return v
+	}
+}
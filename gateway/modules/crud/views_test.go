@@ -0,0 +1,35 @@
+package crud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_bindViewArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		values map[string]interface{}
+		want   interface{}
+	}{
+		{
+			name:   "arguments nested in the pipeline",
+			value:  []interface{}{map[string]interface{}{"$match": map[string]interface{}{"owner": "auth.id", "age": map[string]interface{}{"$gt": "args.age"}}}},
+			values: map[string]interface{}{"auth.id": "1", "args.age": 18},
+			want:   []interface{}{map[string]interface{}{"$match": map[string]interface{}{"owner": "1", "age": map[string]interface{}{"$gt": 18}}}},
+		},
+		{
+			name:   "values which aren't arguments",
+			value:  []interface{}{map[string]interface{}{"$group": map[string]interface{}{"_id": "$city", "total": map[string]interface{}{"$sum": 1}}}, "args.unknown"},
+			values: map[string]interface{}{"args.age": 18},
+			want:   []interface{}{map[string]interface{}{"$group": map[string]interface{}{"_id": "$city", "total": map[string]interface{}{"$sum": 1}}}, "args.unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bindViewArgs(tt.value, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bindViewArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
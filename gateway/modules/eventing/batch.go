@@ -0,0 +1,231 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+)
+
+const (
+	defaultBatchMaxEvents = 100
+	defaultBatchWindow    = 10 // in seconds
+)
+
+// stagedEvent is a staged event along with the parsed time at which it was created
+type stagedEvent struct {
+	doc *model.EventDocument
+	ts  time.Time
+}
+
+func validateTriggerBatch(trigger *config.EventingTrigger) error {
+	if trigger.Batch == nil {
+		return nil
+	}
+	if trigger.Batch.MaxEvents < 0 || trigger.Batch.Window < 0 {
+		return fmt.Errorf("batch size and window of trigger (%s) cannot be negative", trigger.ID)
+	}
+
+	// Set the defaults
+	if trigger.Batch.MaxEvents == 0 {
+		trigger.Batch.MaxEvents = defaultBatchMaxEvents
+	}
+	if trigger.Batch.Window == 0 {
+		trigger.Batch.Window = defaultBatchWindow
+	}
+	return nil
+}
+
+// isBatched returns true if the events of the trigger are delivered in batches. Events of such triggers are only
+// delivered by the staged event routine.
+func (m *Module) isBatched(ruleName string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.config == nil {
+		return false
+	}
+	rule, ok := m.config.Rules[ruleName]
+	return ok && rule.Batch != nil
+}
+
+// getReadyBatches splits the staged events of a trigger, sorted by their timestamp, into batches and returns the
+// ones which are ready to be delivered. A batch is ready if it is full or its oldest event has waited for the window.
+func getReadyBatches(t time.Time, batch *config.EventingBatch, events []stagedEvent) [][]*model.EventDocument {
+	var batches [][]*model.EventDocument
+	for start := 0; start < len(events); start += batch.MaxEvents {
+		end := start + batch.MaxEvents
+		if end > len(events) {
+			end = len(events)
+
+			// Wait for more events if the last batch isn't full and the window hasn't elapsed
+			if t.Before(events[start].ts.Add(time.Duration(batch.Window) * time.Second)) {
+				break
+			}
+		}
+
+		docs := make([]*model.EventDocument, 0, end-start)
+		for _, ev := range events[start:end] {
+			docs = append(docs, ev.doc)
+		}
+		batches = append(batches, docs)
+	}
+	return batches
+}
+
+func (m *Module) processBatchedEvents(t time.Time, ruleName string, events []stagedEvent) {
+	m.lock.RLock()
+	rule, ok := m.config.Rules[ruleName]
+	m.lock.RUnlock()
+	if !ok || rule.Batch == nil {
+		return
+	}
+
+	for _, docs := range getReadyBatches(t, rule.Batch, events) {
+		go m.processStagedBatch(ruleName, docs)
+	}
+}
+
+// processStagedBatch delivers a batch of staged events of a trigger as a single array of cloud events. The batch is
+// retried as a whole and all its events are marked as failed if it couldn't be delivered.
+func (m *Module) processStagedBatch(triggerName string, eventDocs []*model.EventDocument) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	// Skip the events which are already being processed
+	batch := make([]*model.EventDocument, 0, len(eventDocs))
+	for _, eventDoc := range eventDocs {
+		if _, loaded := m.processingEvents.LoadOrStore(eventDoc.ID, true); loaded {
+			continue
+		}
+		batch = append(batch, eventDoc)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	// Delete the events from the processing list without fail
+	defer func() {
+		for _, eventDoc := range batch {
+			m.processingEvents.Delete(eventDoc.ID)
+		}
+	}()
+
+	rule, err := m.selectRule(triggerName)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error processing staged batch", err, nil)
+		return
+	}
+
+	maxRetries := 3
+	if rule.Retries > 0 {
+		maxRetries = rule.Retries
+	}
+
+	if rule.Timeout == 0 {
+		rule.Timeout = 5000
+	}
+
+	timeoutLocal := time.Duration(5000*maxRetries*rule.Timeout) * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutLocal)
+	defer cancel()
+
+	// The invocation of a batch is logged against its first event
+	batchEventID := batch[0].ID
+	ctx, span := tracing.StartSpan(ctx, "eventing.process_batch", trace.SpanKindConsumer, label.String("eventing.event_id", batchEventID), label.String("eventing.trigger", triggerName), label.Int("eventing.batch_size", len(batch)))
+	defer span.End()
+
+	payload := make([]interface{}, 0, len(batch))
+	delivered := make([]*model.EventDocument, 0, len(batch))
+	for _, eventDoc := range batch {
+		// Payload will be of type json. Unmarshal it before sending
+		if data, ok := eventDoc.Payload.(string); ok {
+			var doc interface{}
+			_ = json.Unmarshal([]byte(data), &doc)
+			eventDoc.Payload = doc
+		}
+
+		cloudEvent := model.CloudEventPayload{SpecVersion: "1.0", Type: eventDoc.Type, Source: m.syncMan.GetEventSource(), ID: eventDoc.ID,
+			Time: eventDoc.Timestamp, Data: eventDoc.Payload}
+
+		newDoc, err := m.adjustReqBody(ctx, triggerName, "", rule, nil, structs.Map(&cloudEvent))
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to adjust request body according to template for trigger (%s)", triggerName), err, nil)
+			_ = m.logInvocation(ctx, eventDoc.ID, []byte("{}"), 0, "", err.Error())
+			m.failBatch(ctx, []*model.EventDocument{eventDoc}, "Unable to adjust request body", false)
+			continue
+		}
+		payload = append(payload, newDoc)
+		delivered = append(delivered, eventDoc)
+	}
+	if len(payload) == 0 {
+		return
+	}
+
+	// The claims of the token are generated from the batch of events
+	token, err := m.generateWebhookToken(ctx, rule, payload)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "error invoking web hook in eventing unable to get internal access token", err, nil)
+		_ = m.logInvocation(ctx, batchEventID, []byte("{}"), 0, "", err.Error())
+		m.failBatch(ctx, delivered, "Unable to generate token", false)
+		return
+	}
+
+	for retries := 0; retries <= maxRetries; retries++ {
+		if retries > 0 {
+			// Sleep for 5 seconds
+			time.Sleep(5 * time.Second)
+		}
+
+		eventResponse, err := m.deliverEvent(ctx, token, &http.Client{}, rule, batchEventID, payload)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Eventing staged batch handler could not get response from service", err, nil)
+			continue
+		}
+
+		// Synchronous responses aren't supported for batches. Only the events in the response are queued.
+		m.queueResponseEvents(ctx, eventResponse, batch[0].BatchID)
+		for _, eventDoc := range delivered {
+			m.enqueueUpdateEvent(&queueUpdateEvent{
+				project: m.project,
+				db:      m.config.DBAlias,
+				col:     utils.TableEventingLogs,
+				req:     m.generateProcessedEventRequest(eventDoc.ID),
+				err:     "Eventing: Couldn't update staged event to processed",
+			})
+		}
+		return
+	}
+
+	m.failBatch(ctx, delivered, "Max retires limit reached", true)
+}
+
+// failBatch marks the events of a batch as failed, optionally queueing a dlq event for each of them
+func (m *Module) failBatch(ctx context.Context, batch []*model.EventDocument, remark string, dlq bool) {
+	for _, eventDoc := range batch {
+		if dlq {
+			if err := m.triggerDLQEvent(ctx, eventDoc); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Couldn't create DLQ event for event id %v", eventDoc.ID), err, nil)
+			}
+		}
+
+		m.enqueueUpdateEvent(&queueUpdateEvent{
+			project: m.project,
+			db:      m.config.DBAlias,
+			col:     utils.TableEventingLogs,
+			req:     m.generateFailedEventRequest(eventDoc.ID, remark),
+			err:     "Eventing staged batch handler could not update event doc",
+		})
+	}
+}
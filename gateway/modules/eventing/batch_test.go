@@ -0,0 +1,65 @@
+package eventing
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_getReadyBatches(t *testing.T) {
+	now := time.Now()
+	events := func(ages ...time.Duration) []stagedEvent {
+		var evs []stagedEvent
+		for i, age := range ages {
+			evs = append(evs, stagedEvent{doc: &model.EventDocument{ID: string(rune('a' + i))}, ts: now.Add(-age)})
+		}
+		return evs
+	}
+	ids := func(batches [][]*model.EventDocument) [][]string {
+		var res [][]string
+		for _, batch := range batches {
+			var batchIDs []string
+			for _, doc := range batch {
+				batchIDs = append(batchIDs, doc.ID)
+			}
+			res = append(res, batchIDs)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		batch  *config.EventingBatch
+		events []stagedEvent
+		want   [][]string
+	}{
+		{name: "batch waiting for more events", batch: &config.EventingBatch{MaxEvents: 3, Window: 10}, events: events(2*time.Second, time.Second)},
+		{name: "window elapsed", batch: &config.EventingBatch{MaxEvents: 3, Window: 10}, events: events(11*time.Second, time.Second), want: [][]string{{"a", "b"}}},
+		{name: "full batch", batch: &config.EventingBatch{MaxEvents: 2, Window: 10}, events: events(2*time.Second, time.Second), want: [][]string{{"a", "b"}}},
+		{name: "full batches with a pending one", batch: &config.EventingBatch{MaxEvents: 2, Window: 10}, events: events(3*time.Second, 2*time.Second, time.Second, time.Second, time.Second), want: [][]string{{"a", "b"}, {"c", "d"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(getReadyBatches(now, tt.batch, tt.events)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getReadyBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateTriggerBatch(t *testing.T) {
+	trigger := &config.EventingTrigger{ID: "t", Batch: &config.EventingBatch{MaxEvents: 50}}
+	if err := validateTriggerBatch(trigger); err != nil {
+		t.Fatalf("validateTriggerBatch() error = %v", err)
+	}
+	if want := (&config.EventingBatch{MaxEvents: 50, Window: defaultBatchWindow}); !reflect.DeepEqual(trigger.Batch, want) {
+		t.Errorf("validateTriggerBatch() batch = %v, want %v", trigger.Batch, want)
+	}
+
+	if err := validateTriggerBatch(&config.EventingTrigger{ID: "t", Batch: &config.EventingBatch{Window: -1}}); err == nil {
+		t.Errorf("validateTriggerBatch() expected an error for a negative window")
+	}
+}
@@ -20,6 +20,11 @@ func (m *Module) ProcessTransmittedEvents(eventDocs []*model.EventDocument) {
 	currentTimestamp := time.Now()
 
 	for _, eventDoc := range eventDocs {
+		// Events of batched triggers are delivered by the staged event routine
+		if m.isBatched(eventDoc.RuleName) {
+			continue
+		}
+
 		if eventDoc.Token >= start && eventDoc.Token <= end {
 			timestamp, err := time.Parse(time.RFC3339Nano, eventDoc.Timestamp)
 			if err != nil {
@@ -28,7 +33,7 @@ func (m *Module) ProcessTransmittedEvents(eventDocs []*model.EventDocument) {
 			}
 
 			if currentTimestamp.After(timestamp) || currentTimestamp.Equal(timestamp) {
-				go m.processStagedEvent(eventDoc)
+				m.dispatchStagedEvent(eventDoc)
 			}
 		}
 	}
@@ -70,4 +70,5 @@ func (m *Module) queueUpdateEvent(ev *queueUpdateEvent) {
 	if err := m.crud.InternalUpdate(ctx, ev.db, ev.project, ev.col, ev.req); err != nil {
 		_ = helpers.Logger.LogError("eventing-update", ev.err, err, nil)
 	}
+	m.removeUpdateEventFromWAL(ev)
 }
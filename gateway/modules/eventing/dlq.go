@@ -0,0 +1,212 @@
+package eventing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// GetDeadLetterEvents returns the events in the dead letter queue matching the filter along with the history of
+// their invocations. The dead letter queue consists of the events which failed even after their retries.
+func (m *Module) GetDeadLetterEvents(ctx context.Context, filter *model.EventLogFilter) ([]*model.DeadLetterEvent, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	filter.Status = utils.EventStatusFailed
+	eventDocs, err := m.readEventLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	unmarshalEventPayloads(eventDocs)
+
+	deadLetters := make([]*model.DeadLetterEvent, len(eventDocs))
+	if len(eventDocs) == 0 {
+		return deadLetters, nil
+	}
+
+	ids := make([]interface{}, len(eventDocs))
+	byID := make(map[string]*model.DeadLetterEvent, len(eventDocs))
+	for i, eventDoc := range eventDocs {
+		ids[i] = eventDoc.ID
+		deadLetters[i] = &model.DeadLetterEvent{EventDocument: eventDoc, Invocations: []*model.InvocationDocument{}}
+		byID[eventDoc.ID] = deadLetters[i]
+	}
+
+	dbAlias, col := m.config.DBAlias, utils.TableInvocationLogs
+	attr := map[string]string{"project": m.project, "db": dbAlias, "col": col}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	readRequest := &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{"event_id": map[string]interface{}{"$in": ids}}, Options: &model.ReadOptions{Sort: []string{"invocation_time"}}}
+	results, _, err := m.crud.Read(ctx, dbAlias, col, readRequest, reqParams)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read the invocations of the dead letter events", err, nil)
+	}
+
+	rows, _ := results.([]interface{})
+	for _, row := range rows {
+		invocation := new(model.InvocationDocument)
+		if err := mapstructure.WeakDecode(row, invocation); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Could not covert object (%v) as invocation doc", row), err, nil)
+		}
+		if deadLetter, ok := byID[invocation.EventID]; ok {
+			deadLetter.Invocations = append(deadLetter.Invocations, invocation)
+		}
+	}
+	return deadLetters, nil
+}
+
+// RetryDeadLetterEvents queues the events in the dead letter queue matching the filter once again. The original
+// events are marked as retried, which removes them from the dead letter queue. It returns the ids of the new events.
+func (m *Module) RetryDeadLetterEvents(ctx context.Context, filter *model.EventLogFilter) ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	filter.Status = utils.EventStatusFailed
+	eventDocs, err := m.readEventLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, ids, err := m.replayEventDocs(ctx, eventDocs)
+	if err != nil || len(sources) == 0 {
+		return ids, err
+	}
+
+	retried := make([]interface{}, len(sources))
+	for i, id := range sources {
+		retried[i] = id
+	}
+	updateRequest := &model.UpdateRequest{
+		Find:      map[string]interface{}{"_id": map[string]interface{}{"$in": retried}},
+		Operation: utils.All,
+		Update:    map[string]interface{}{"$set": map[string]interface{}{"status": utils.EventStatusRetried}},
+	}
+	if err := m.crud.InternalUpdate(ctx, m.config.DBAlias, m.project, utils.TableEventingLogs, updateRequest); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to mark the dead letter events as retried", err, nil)
+	}
+	return ids, nil
+}
+
+// PurgeDeadLetterEvents deletes the events in the dead letter queue matching the filter along with their
+// invocations. Skip and limit of the filter are ignored.
+func (m *Module) PurgeDeadLetterEvents(ctx context.Context, filter *model.EventLogFilter) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	filter.Status = utils.EventStatusFailed
+	find, err := getEventLogFind(filter)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid dead letter queue filter provided", err, nil)
+	}
+
+	if err := m.deleteEventLogs(ctx, m.config.DBAlias, find); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to purge the dead letter queue", err, nil)
+	}
+	return nil
+}
+
+// checkDeadLetterDepth fires the dead letter queue alert when the number of events in it reaches the threshold. The
+// alert is fired once till the depth goes below the threshold again. The growth of the depth is reported to the admin
+// clients as well. Only the worker having the first token checks the depth so that it is reported by a single worker.
+func (m *Module) checkDeadLetterDepth() {
+	// Return if module is not enabled
+	if !m.IsEnabled() {
+		return
+	}
+
+	m.lock.RLock()
+	dbAlias, alert := m.config.DBAlias, m.config.DLQAlert
+	m.lock.RUnlock()
+
+	if start, _ := m.syncMan.GetAssignedTokens(); start != 0 {
+		m.dlqDepthKnown = false
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attr := map[string]string{"project": m.project, "db": dbAlias, "col": utils.TableEventingLogs}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	readRequest := &model.ReadRequest{Operation: utils.Count, Find: map[string]interface{}{"status": utils.EventStatusFailed}, Options: &model.ReadOptions{}}
+	result, _, err := m.crud.Read(ctx, dbAlias, utils.TableEventingLogs, readRequest, reqParams)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Eventing dead letter queue routine could not count failed events", err, nil)
+		return
+	}
+
+	var depth int64
+	switch v := result.(type) {
+	case int64:
+		depth = v
+	case int:
+		depth = int64(v)
+	case float64:
+		depth = int64(v)
+	}
+
+	if m.dlqDepthKnown && depth > m.dlqDepth {
+		m.syncMan.PublishAdminEvent(ctx, &model.AdminEvent{
+			Type:    model.AdminEventDLQGrowth,
+			Project: m.project,
+			Payload: map[string]interface{}{"depth": depth, "previousDepth": m.dlqDepth},
+		})
+	}
+	m.dlqDepth, m.dlqDepthKnown = depth, true
+
+	if alert == nil {
+		m.dlqAlerted = false
+		return
+	}
+	if depth < int64(alert.Threshold) {
+		m.dlqAlerted = false
+		return
+	}
+	if m.dlqAlerted {
+		return
+	}
+
+	if err := m.sendDeadLetterAlert(ctx, alert.URL, depth, alert.Threshold); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to send dead letter queue alert to url (%s)", alert.URL), err, nil)
+		return
+	}
+	m.dlqAlerted = true
+}
+
+func (m *Module) sendDeadLetterAlert(ctx context.Context, url string, depth int64, threshold int) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"project":   m.project,
+		"depth":     depth,
+		"threshold": threshold,
+		"ts":        time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	utils.InjectRequestID(ctx, req.Header)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("alert webhook responded with status (%d)", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package eventing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func TestModule_GetDeadLetterEvents(t *testing.T) {
+	events := []interface{}{
+		map[string]interface{}{"_id": "1", "type": "order_placed", "rule_name": "on_order", "payload": `{"id":"1"}`, "status": utils.EventStatusFailed},
+		map[string]interface{}{"_id": "2", "type": "order_placed", "rule_name": "on_order", "payload": `{"id":"2"}`, "status": utils.EventStatusFailed},
+	}
+	invocations := []interface{}{
+		map[string]interface{}{"_id": "a", "event_id": "1", "response_status_code": 500, "error_msg": "internal error"},
+		map[string]interface{}{"_id": "b", "event_id": "1", "response_status_code": 502},
+		map[string]interface{}{"_id": "c", "event_id": "2", "response_status_code": 500},
+	}
+
+	crud := &mockCrudInterface{}
+	crud.On("Read", mock.Anything, "db", utils.TableEventingLogs, mock.MatchedBy(func(req *model.ReadRequest) bool {
+		return req.Find["status"] == utils.EventStatusFailed
+	})).Return(events, new(model.SQLMetaData), nil)
+	crud.On("Read", mock.Anything, "db", utils.TableInvocationLogs, mock.Anything).Return(invocations, new(model.SQLMetaData), nil)
+
+	m := &Module{project: "project", config: &config.Eventing{DBAlias: "db"}, crud: crud}
+	got, err := m.GetDeadLetterEvents(context.Background(), &model.EventLogFilter{Status: utils.EventStatusProcessed})
+	if err != nil {
+		t.Fatalf("GetDeadLetterEvents() error = %v", err)
+	}
+
+	if len(got) != 2 || len(got[0].Invocations) != 2 || len(got[1].Invocations) != 1 {
+		t.Fatalf("GetDeadLetterEvents() = %v, want the invocations grouped by event", got)
+	}
+	if got[0].Invocations[0].ErrorMessage != "internal error" {
+		t.Errorf("GetDeadLetterEvents() error message = %v, want internal error", got[0].Invocations[0].ErrorMessage)
+	}
+	if payload, ok := got[0].Payload.(map[string]interface{}); !ok || payload["id"] != "1" {
+		t.Errorf("GetDeadLetterEvents() payload = %v, want it to be unmarshalled", got[0].Payload)
+	}
+	crud.AssertExpectations(t)
+}
+
+func TestModule_checkDeadLetterDepth(t *testing.T) {
+	alerts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alerts++
+	}))
+	defer server.Close()
+
+	// The alert is fired when the threshold is crossed and once again after the depth goes below it, while every
+	// growth of the depth after the first check is reported to the admin clients
+	depths := []int64{2, 5, 7, 1, 4}
+	wantAlerts := []int{0, 1, 1, 1, 2}
+	wantGrowths := []int{0, 1, 2, 2, 3}
+
+	crud := &mockCrudInterface{}
+	for _, depth := range depths {
+		crud.On("Read", mock.Anything, "db", utils.TableEventingLogs, mock.Anything).Return(depth, new(model.SQLMetaData), nil).Once()
+	}
+	syncMan := &mockSyncmanEventingInterface{}
+	syncMan.On("GetAssignedTokens").Return(0, 99)
+	syncMan.On("PublishAdminEvent", mock.Anything, mock.MatchedBy(func(event *model.AdminEvent) bool {
+		return event.Type == model.AdminEventDLQGrowth && event.Project == "project"
+	})).Return()
+
+	m := &Module{
+		project: "project",
+		config:  &config.Eventing{Enabled: true, DBAlias: "db", DLQAlert: &config.EventingDLQAlert{URL: server.URL, Threshold: 3}},
+		crud:    crud,
+		syncMan: syncMan,
+	}
+	for i := range depths {
+		m.checkDeadLetterDepth()
+		if alerts != wantAlerts[i] {
+			t.Errorf("checkDeadLetterDepth() alerts after depth %d = %d, want %d", depths[i], alerts, wantAlerts[i])
+		}
+		syncMan.AssertNumberOfCalls(t, "PublishAdminEvent", wantGrowths[i])
+	}
+	crud.AssertExpectations(t)
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tmpl"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
 )
 
 // Module is responsible for managing the eventing system
@@ -40,20 +42,50 @@ type Module struct {
 	fileStore model.FilestoreEventingInterface
 
 	schemas    map[string]model.Fields
+	sources    map[string]*eventSource
 	metricHook model.MetricEventingHook
 	// stores mapping of batchID w.r.t channel for sending synchronous event response
-	eventChanMap sync.Map // key here is batchID
-	tickerIntent *time.Ticker
-	tickerStaged *time.Ticker
+	eventChanMap  sync.Map // key here is batchID
+	tickerIntent  *time.Ticker
+	tickerStaged  *time.Ticker
+	tickerCleanup *time.Ticker
+	tickerResume  *time.Ticker
+	tickerDLQ     *time.Ticker
+
+	// Whether the dead letter queue alert has been fired for the current breach of its threshold
+	dlqAlerted bool
+	// The last known number of events in the dead letter queue, used to report its growth to the admin clients
+	dlqDepth      int64
+	dlqDepthKnown bool
 
 	// Templates for body transformation
 	templates map[string]*template.Template
+	jqQueries map[string]*tmpl.JQ
+
+	// Parsed filter expressions of the triggers
+	filters map[string]filterExpr
 
 	// Pub sub network
 	pubsubClient *pubsub.Module
 
+	// Clients of the functions of cloud providers events are delivered to
+	targets *cloudTargets
+
+	// Queues of the events of triggers which are delivered in order
+	ordered *orderedQueues
+
+	// Workflows along with the runs being executed by this worker
+	workflows        map[string]*config.EventingWorkflow
+	runningWorkflows sync.Map // key here is the id of the run
+	functions        functionsInterface
+
 	// Channel for queuing eventing updates
 	updateEventC chan *queueUpdateEvent
+
+	// Write ahead log the queued updates are persisted in so that they survive a crash
+	lockWAL     sync.RWMutex
+	wal         *wal.WAL
+	walReplayed bool
 }
 
 // synchronous event response
@@ -78,16 +110,24 @@ func New(clusterID, projectID, nodeID string, auth model.AuthEventingInterface,
 		crud:         crud,
 		syncMan:      syncMan,
 		schemas:      map[string]model.Fields{},
+		sources:      map[string]*eventSource{},
 		fileStore:    file,
 		metricHook:   hook,
 		config:       &config.Eventing{Enabled: false, InternalRules: make(config.EventingTriggers)},
 		templates:    map[string]*template.Template{},
+		jqQueries:    map[string]*tmpl.JQ{},
 		pubsubClient: pubsubClient,
+		targets:      newCloudTargets(),
+		ordered:      newOrderedQueues(),
+		workflows:    map[string]*config.EventingWorkflow{},
 	}
 
 	// Start the internal processes
 	go m.routineProcessIntents()
 	go m.routineProcessStaged()
+	go m.routineCleanupEventLogs()
+	go m.routineResumeWorkflows()
+	go m.routineCheckDeadLetterDepth()
 	go m.routineHandleMessages()
 	go m.routineHandleEventResponseMessages()
 	m.createProcessUpdateEventsRoutine()
@@ -109,9 +149,15 @@ func (m *Module) SetConfig(projectID string, eventing *config.EventingConfig) er
 		return errors.New("invalid eventing config provided")
 	}
 
+	if alert := eventing.DLQAlert; alert != nil && (alert.URL == "" || alert.Threshold <= 0) {
+		return errors.New("url and a positive threshold are required for the dead letter queue alert")
+	}
+
 	m.project = projectID
 	m.config.Enabled = eventing.Enabled
 	m.config.DBAlias = eventing.DBAlias
+	m.config.Retention = eventing.Retention
+	m.config.DLQAlert = eventing.DLQAlert
 
 	// `m.config.InternalRules` cannot be set by the eventing module. Its used by other modules only.
 	if m.config.InternalRules == nil {
@@ -130,25 +176,35 @@ func (m *Module) SetSchemaConfig(evSchemas config.EventingSchemas) error {
 	m.schemas = map[string]model.Fields{}
 
 	for _, evSchema := range evSchemas {
-		resourceID := ksuid.New().String()
-		dummyDBSchema := config.DatabaseSchemas{
-			resourceID: {
-				Table:   evSchema.ID,
-				DbAlias: "dummyDBName",
-				Schema:  evSchema.Schema,
-			},
-		}
-		schemaType, err := schemaHelpers.Parser(dummyDBSchema)
+		fields, err := parseEventSchema(evSchema.ID, evSchema.Schema)
 		if err != nil {
 			return err
 		}
-		if len(schemaType["dummyDBName"][evSchema.ID]) != 0 {
-			m.schemas[evSchema.ID] = schemaType["dummyDBName"][evSchema.ID]
+		if len(fields) != 0 {
+			m.schemas[evSchema.ID] = fields
 		}
 	}
 	return nil
 }
 
+// parseEventSchema parses the schema of an event type, or of the payload of an event source, using the parser of
+// the database schemas
+func parseEventSchema(id, schema string) (model.Fields, error) {
+	resourceID := ksuid.New().String()
+	dummyDBSchema := config.DatabaseSchemas{
+		resourceID: {
+			Table:   id,
+			DbAlias: "dummyDBName",
+			Schema:  schema,
+		},
+	}
+	schemaType, err := schemaHelpers.Parser(dummyDBSchema)
+	if err != nil {
+		return nil, err
+	}
+	return schemaType["dummyDBName"][id], nil
+}
+
 // SetTriggerConfig sets eventing trigger config of eventing module
 func (m *Module) SetTriggerConfig(triggers config.EventingTriggers) error {
 	m.lock.Lock()
@@ -160,9 +216,27 @@ func (m *Module) SetTriggerConfig(triggers config.EventingTriggers) error {
 	}
 
 	m.templates = map[string]*template.Template{}
+	m.jqQueries = map[string]*tmpl.JQ{}
+	m.filters = map[string]filterExpr{}
 	for name, trigger := range m.config.Rules {
 		trigger.ID = name
 
+		if err := validateTriggerTarget(trigger); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid eventing trigger provided", err, nil)
+		}
+
+		if err := validateTriggerBatch(trigger); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid eventing trigger provided", err, nil)
+		}
+
+		if trigger.FilterExpression != "" {
+			expr, err := parseFilterExpression(trigger.FilterExpression)
+			if err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Invalid filter expression provided for trigger (%s)", trigger.ID), err, nil)
+			}
+			m.filters[trigger.ID] = expr
+		}
+
 		// Set default templating engine
 		if trigger.Tmpl == "" {
 			trigger.Tmpl = config.TemplatingEngineGo
@@ -185,6 +259,17 @@ func (m *Module) SetTriggerConfig(triggers config.EventingTriggers) error {
 					return err
 				}
 			}
+		case config.TemplatingEngineJQ:
+			if trigger.RequestTemplate != "" {
+				if err := m.createJQQuery("trigger", trigger.ID, trigger.RequestTemplate); err != nil {
+					return err
+				}
+			}
+			if trigger.Claims != "" {
+				if err := m.createJQQuery("claim", trigger.ID, trigger.Claims); err != nil {
+					return err
+				}
+			}
 		default:
 			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Invalid templating engine (%s) provided", trigger.Tmpl), nil, map[string]interface{}{})
 		}
@@ -222,6 +307,9 @@ func (m *Module) CloseConfig() error {
 	for k := range m.schemas {
 		delete(m.schemas, k)
 	}
+	for k := range m.sources {
+		delete(m.sources, k)
+	}
 	for k := range m.config.Rules {
 		delete(m.config.Rules, k)
 	}
@@ -234,7 +322,57 @@ func (m *Module) CloseConfig() error {
 	for k := range m.config.Schemas {
 		delete(m.config.Schemas, k)
 	}
+	for k := range m.workflows {
+		delete(m.workflows, k)
+	}
 	m.tickerIntent.Stop()
 	m.tickerStaged.Stop()
+	if m.tickerCleanup != nil {
+		m.tickerCleanup.Stop()
+	}
+	if m.tickerResume != nil {
+		m.tickerResume.Stop()
+	}
+	if m.tickerDLQ != nil {
+		m.tickerDLQ.Stop()
+	}
 	return nil
 }
+
+// Drain stops picking up new events and waits till the events being processed and the queued
+// status updates of the processed events have been flushed
+func (m *Module) Drain(ctx context.Context) error {
+	m.lock.Lock()
+	project := m.project
+	if m.tickerIntent != nil {
+		m.tickerIntent.Stop()
+	}
+	if m.tickerStaged != nil {
+		m.tickerStaged.Stop()
+	}
+	m.lock.Unlock()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if m.pendingEvents() == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to flush (%d) pending events of project (%s)", m.pendingEvents(), project), ctx.Err(), nil)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Module) pendingEvents() int {
+	count := len(m.updateEventC)
+	m.processingEvents.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
@@ -137,6 +137,6 @@ func (m *Module) HookStage(ctx context.Context, intent *model.EventIntent, err e
 
 	// Broadcast the event so the concerned worker can process it immediately
 	if !intent.Invalid {
-		m.transmitEvents(intent.Token, intent.Docs)
+		m.transmitEventDocs(intent.Token, intent.Docs)
 	}
 }
@@ -0,0 +1,510 @@
+package eventing
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// filterExpr is a parsed filter expression of a trigger. Expressions are evaluated over the payload of the event and
+// look like `doc.amount > 100 && doc.status changed to 'paid'`. The following constructs are supported:
+//   - comparisons using ==, !=, >, >=, < and <=
+//   - `in` to check membership in a list like doc.status in ['paid', 'refunded']
+//   - `contains` to check if a string contains a substring or a list contains a value
+//   - `exists` to check if a field is present in the payload
+//   - `changed` and `changed to` which compare a field of doc with the same field of before (the previous version of
+//     the document) if the event carries it
+//   - &&, ||, ! (or and, or, not) along with parenthesis
+type filterExpr interface {
+	eval(payload map[string]interface{}) (interface{}, error)
+}
+
+type filterToken struct {
+	kind  string // ident, number, string, op, eof
+	value string
+}
+
+// parseFilterExpression parses the filter expression of a trigger
+func parseFilterExpression(expression string) (filterExpr, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != "eof" {
+		return nil, fmt.Errorf("unexpected token (%s) in filter expression", t.value)
+	}
+	return expr, nil
+}
+
+// matchFilterExpression returns true if the expression evaluates to true for the payload
+func matchFilterExpression(expr filterExpr, payload interface{}) (bool, error) {
+	obj, _ := payload.(map[string]interface{})
+	v, err := expr.eval(obj)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter expression evaluated to (%v) instead of a boolean", v)
+	}
+	return b, nil
+}
+
+func tokenizeFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexRune(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in filter expression at position (%d)", i)
+			}
+			tokens = append(tokens, filterToken{kind: "string", value: s[i+1 : i+1+end]})
+			i += end + 2
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1]))):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, filterToken{kind: "number", value: s[i:j]})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_' || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, filterToken{kind: "ident", value: s[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("invalid character (%c) in filter expression at position (%d)", c, i)
+			}
+			tokens = append(tokens, filterToken{kind: "op", value: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, filterToken{kind: "eof"}), nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != "eof" {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the provided operators or keywords
+func (p *filterParser) accept(values ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != "op" && t.kind != "ident" {
+		return "", false
+	}
+	for _, v := range values {
+		if t.value == v {
+			p.pos++
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "||", left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *filterParser) parseNot() (filterExpr, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("exists"); ok {
+		path, ok := left.(*pathExpr)
+		if !ok {
+			return nil, fmt.Errorf("exists can only be used with fields")
+		}
+		return &existsExpr{path: path}, nil
+	}
+
+	if _, ok := p.accept("changed"); ok {
+		path, ok := left.(*pathExpr)
+		if !ok || len(path.keys) < 2 || path.keys[0] != "doc" {
+			return nil, fmt.Errorf("changed can only be used with the fields of doc")
+		}
+		expr := &changedExpr{path: path}
+		if _, ok := p.accept("to"); ok {
+			if expr.to, err = p.parseOperand(); err != nil {
+				return nil, err
+			}
+		}
+		return expr, nil
+	}
+
+	op, ok := p.accept("==", "!=", ">", ">=", "<", "<=", "in", "contains")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &comparisonExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *filterParser) parseOperand() (filterExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case "string":
+		return &literalExpr{value: t.value}, nil
+	case "number":
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number (%s) in filter expression", t.value)
+		}
+		return &literalExpr{value: f}, nil
+	case "ident":
+		switch t.value {
+		case "true":
+			return &literalExpr{value: true}, nil
+		case "false":
+			return &literalExpr{value: false}, nil
+		case "null":
+			return &literalExpr{value: nil}, nil
+		}
+		return &pathExpr{keys: strings.Split(t.value, ".")}, nil
+	case "op":
+		switch t.value {
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("missing closing parenthesis in filter expression")
+			}
+			return expr, nil
+		case "[":
+			list := &listExpr{}
+			if _, ok := p.accept("]"); ok {
+				return list, nil
+			}
+			for {
+				item, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if _, ok := p.accept(","); ok {
+					continue
+				}
+				if _, ok := p.accept("]"); !ok {
+					return nil, fmt.Errorf("missing closing bracket in filter expression")
+				}
+				return list, nil
+			}
+		}
+	}
+	if t.kind == "eof" {
+		return nil, fmt.Errorf("unexpected end of filter expression")
+	}
+	return nil, fmt.Errorf("unexpected token (%s) in filter expression", t.value)
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+func (e *literalExpr) eval(map[string]interface{}) (interface{}, error) {
+	return e.value, nil
+}
+
+type listExpr struct {
+	items []filterExpr
+}
+
+func (e *listExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, len(e.items))
+	for i, item := range e.items {
+		v, err := item.eval(payload)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// pathExpr resolves a field of the payload. Missing fields evaluate to null.
+type pathExpr struct {
+	keys []string
+}
+
+func (e *pathExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	v, _ := e.lookup(payload)
+	return v, nil
+}
+
+func (e *pathExpr) lookup(payload map[string]interface{}) (interface{}, bool) {
+	var current interface{} = payload
+	for _, key := range e.keys {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+type existsExpr struct {
+	path *pathExpr
+}
+
+func (e *existsExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	_, ok := e.path.lookup(payload)
+	return ok, nil
+}
+
+// changedExpr compares a field of doc with the same field of before. The field is considered to have changed if the
+// event doesn't carry the previous version of the document.
+type changedExpr struct {
+	path *pathExpr
+	to   filterExpr
+}
+
+func (e *changedExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	current, _ := e.path.lookup(payload)
+	if e.to != nil {
+		to, err := e.to.eval(payload)
+		if err != nil {
+			return nil, err
+		}
+		if !filterEqual(current, to) {
+			return false, nil
+		}
+	}
+
+	if _, ok := payload["before"].(map[string]interface{}); !ok {
+		return true, nil
+	}
+	before := &pathExpr{keys: append([]string{"before"}, e.path.keys[1:]...)}
+	previous, _ := before.lookup(payload)
+	return !filterEqual(current, previous), nil
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e *notExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	v, err := e.expr.eval(payload)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate non boolean value (%v)", v)
+	}
+	return !b, nil
+}
+
+type logicalExpr struct {
+	op          string
+	left, right filterExpr
+}
+
+func (e *logicalExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	left, err := evalBool(e.left, payload)
+	if err != nil {
+		return nil, err
+	}
+	// Short circuit the evaluation
+	if (e.op == "&&" && !left) || (e.op == "||" && left) {
+		return left, nil
+	}
+	return evalBool(e.right, payload)
+}
+
+func evalBool(expr filterExpr, payload map[string]interface{}) (bool, error) {
+	v, err := expr.eval(payload)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("value (%v) used in logical expression is not a boolean", v)
+	}
+	return b, nil
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right filterExpr
+}
+
+func (e *comparisonExpr) eval(payload map[string]interface{}) (interface{}, error) {
+	left, err := e.left.eval(payload)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==":
+		return filterEqual(left, right), nil
+	case "!=":
+		return !filterEqual(left, right), nil
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		return filterContains(list, left), nil
+	case "contains":
+		switch v := left.(type) {
+		case string:
+			s, ok := right.(string)
+			return ok && strings.Contains(v, s), nil
+		case []interface{}:
+			return filterContains(v, right), nil
+		}
+		return false, nil
+	}
+
+	// Ordering comparisons are only performed between numbers or between strings. Comparisons with missing fields
+	// evaluate to false.
+	if l, ok := toFilterNumber(left); ok {
+		r, ok := toFilterNumber(right)
+		if !ok {
+			return false, nil
+		}
+		return compareOrdered(e.op, l < r, l == r), nil
+	}
+	if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		if !ok {
+			return false, nil
+		}
+		return compareOrdered(e.op, l < r, l == r), nil
+	}
+	return false, nil
+}
+
+func compareOrdered(op string, less, equal bool) bool {
+	switch op {
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	case "<":
+		return less
+	default: // <=
+		return less || equal
+	}
+}
+
+func filterEqual(a, b interface{}) bool {
+	if x, ok := toFilterNumber(a); ok {
+		y, ok := toFilterNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func filterContains(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if filterEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFilterNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
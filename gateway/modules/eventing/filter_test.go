@@ -0,0 +1,59 @@
+package eventing
+
+import "testing"
+
+func Test_matchFilterExpression(t *testing.T) {
+	payload := map[string]interface{}{
+		"db":     "db",
+		"col":    "orders",
+		"doc":    map[string]interface{}{"id": "1", "status": "paid", "amount": float64(150), "tags": []interface{}{"priority"}},
+		"before": map[string]interface{}{"id": "1", "status": "pending", "amount": float64(150)},
+	}
+	withoutBefore := map[string]interface{}{"doc": map[string]interface{}{"status": "paid"}}
+
+	tests := []struct {
+		name       string
+		expression string
+		payload    map[string]interface{}
+		want       bool
+		wantErr    bool
+	}{
+		{name: "number comparison", expression: "doc.amount > 100", payload: payload, want: true},
+		{name: "number comparison not matching", expression: "doc.amount <= 100", payload: payload, want: false},
+		{name: "string equality", expression: "col == 'orders'", payload: payload, want: true},
+		{name: "changed to", expression: "doc.status changed to 'paid'", payload: payload, want: true},
+		{name: "changed to a different value", expression: "doc.status changed to 'refunded'", payload: payload, want: false},
+		{name: "unchanged field", expression: "doc.amount changed", payload: payload, want: false},
+		{name: "changed without previous document", expression: "doc.status changed to \"paid\"", payload: withoutBefore, want: true},
+		{name: "logical operators", expression: "doc.amount > 100 && (doc.status == 'paid' || doc.status == 'refunded')", payload: payload, want: true},
+		{name: "keyword operators", expression: "not doc.amount < 10 and col == 'orders'", payload: payload, want: true},
+		{name: "in list", expression: "doc.status in ['paid', 'refunded']", payload: payload, want: true},
+		{name: "contains", expression: "doc.tags contains 'priority'", payload: payload, want: true},
+		{name: "exists", expression: "doc.coupon exists", payload: payload, want: false},
+		{name: "missing field", expression: "doc.coupon > 10", payload: payload, want: false},
+		{name: "non boolean expression", expression: "doc.amount", payload: payload, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parseFilterExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("parseFilterExpression() error = %v", err)
+			}
+			got, err := matchFilterExpression(expr, tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchFilterExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("matchFilterExpression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseFilterExpression(t *testing.T) {
+	for _, expression := range []string{"doc.amount >", "(doc.amount > 1", "doc.status == 'paid", "doc.amount > 1 doc.amount", "col changed", "doc.status in ['a',", "doc.amount # 1"} {
+		if _, err := parseFilterExpression(expression); err == nil {
+			t.Errorf("parseFilterExpression(%q) expected an error", expression)
+		}
+	}
+}
@@ -38,7 +38,7 @@ func (m *Module) processIntents(t *time.Time) {
 	}}
 
 	attr := map[string]string{"project": m.project, "db": dbAlias, "col": col}
-	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
 	results, _, err := m.crud.Read(ctx, dbAlias, col, &readRequest, reqParams)
 	if err != nil {
 		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Eventing intent routine error", err, nil)
@@ -95,13 +95,13 @@ func (m *Module) processIntent(eventDoc *model.EventDocument) {
 		if err := m.fileStore.DoesExists(ctx, m.project, token, filePayload.Path); err != nil {
 
 			// Mark event as cancelled if it document doesn't exist
-			m.updateEventC <- &queueUpdateEvent{
+			m.enqueueUpdateEvent(&queueUpdateEvent{
 				project: m.project,
 				db:      m.config.DBAlias,
 				col:     utils.TableEventingLogs,
 				req:     m.generateCancelEventRequest(eventID),
 				err:     "Eventing: Couldn't cancel intent",
-			}
+			})
 			return
 		}
 
@@ -127,13 +127,13 @@ func (m *Module) processIntent(eventDoc *model.EventDocument) {
 
 		if err := m.fileStore.DoesExists(ctx, m.project, token, filePayload.Path); err == nil {
 			// Mark the event as cancelled if the object still exists
-			m.updateEventC <- &queueUpdateEvent{
+			m.enqueueUpdateEvent(&queueUpdateEvent{
 				project: m.project,
 				db:      m.config.DBAlias,
 				col:     utils.TableEventingLogs,
 				req:     m.generateCancelEventRequest(eventID),
 				err:     "Eventing: Couldn't update intent to cancelled",
-			}
+			})
 			return
 		}
 
@@ -6,15 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/fatih/structs"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 func (m *Module) processStagedEvents(t *time.Time) {
@@ -23,6 +27,10 @@ func (m *Module) processStagedEvents(t *time.Time) {
 	if !m.IsEnabled() {
 		return
 	}
+
+	// The updates left behind by the previous run are applied once the database of the events is available
+	m.replayWAL()
+
 	m.lock.RLock()
 	dbAlias, col := m.config.DBAlias, utils.TableEventingLogs
 	m.lock.RUnlock()
@@ -42,13 +50,16 @@ func (m *Module) processStagedEvents(t *time.Time) {
 	}}
 
 	attr := map[string]string{"project": m.project, "db": dbAlias, "col": col}
-	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
 	results, _, err := m.crud.Read(ctx, dbAlias, col, &readRequest, reqParams)
 	if err != nil {
 		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Eventing stage routine error", err, nil)
 		return
 	}
 
+	// Staged events of triggers delivering events in batches
+	batched := map[string][]stagedEvent{}
+
 	eventDocs := results.([]interface{})
 	for _, temp := range eventDocs {
 		eventDoc := new(model.EventDocument)
@@ -63,12 +74,21 @@ func (m *Module) processStagedEvents(t *time.Time) {
 			continue
 		}
 
+		if m.isBatched(eventDoc.RuleName) {
+			batched[eventDoc.RuleName] = append(batched[eventDoc.RuleName], stagedEvent{doc: eventDoc, ts: timestamp})
+			continue
+		}
+
 		timestamp = timestamp.Add(15 * time.Second)
 
 		if t.After(timestamp) || t.Equal(timestamp) {
-			go m.processStagedEvent(eventDoc)
+			m.dispatchStagedEvent(eventDoc)
 		}
 	}
+
+	for ruleName, events := range batched {
+		m.processBatchedEvents(*t, ruleName, events)
+	}
 }
 
 func (m *Module) processStagedEvent(eventDoc *model.EventDocument) {
@@ -108,6 +128,9 @@ func (m *Module) processStagedEvent(eventDoc *model.EventDocument) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeoutLocal)
 	defer cancel()
 
+	ctx, span := tracing.StartSpan(ctx, "eventing.process", trace.SpanKindConsumer, label.String("eventing.event_id", eventDoc.ID), label.String("eventing.type", eventType), label.String("eventing.trigger", triggerName))
+	defer span.End()
+
 	// Create a variable to track retries
 	retries := 0
 
@@ -126,13 +149,13 @@ func (m *Module) processStagedEvent(eventDoc *model.EventDocument) {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "eventing module couldn't log the invocation ", err, nil)
 			return
 		}
-		m.updateEventC <- &queueUpdateEvent{
+		m.enqueueUpdateEvent(&queueUpdateEvent{
 			project: m.project,
 			db:      m.config.DBAlias,
 			col:     utils.TableEventingLogs,
 			req:     m.generateFailedEventRequest(eventDoc.ID, "Max retires limit reached"),
 			err:     "Eventing staged event handler could not update event doc",
-		}
+		})
 		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to adjust request body according to template for trigger (%s)", triggerName), err, nil)
 		return
 	}
@@ -144,13 +167,13 @@ func (m *Module) processStagedEvent(eventDoc *model.EventDocument) {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "eventing module couldn't log the invocation ", err, nil)
 			return
 		}
-		m.updateEventC <- &queueUpdateEvent{
+		m.enqueueUpdateEvent(&queueUpdateEvent{
 			project: m.project,
 			db:      m.config.DBAlias,
 			col:     utils.TableEventingLogs,
 			req:     m.generateFailedEventRequest(eventDoc.ID, "Unable to generate token"),
 			err:     "Eventing staged event handler could not update event doc",
-		}
+		})
 		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "error invoking web hook in eventing unable to get internal access token", err, nil)
 		return
 	}
@@ -179,34 +202,92 @@ func (m *Module) processStagedEvent(eventDoc *model.EventDocument) {
 		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Couldn't create DLQ event for event id %v", eventDoc.ID), err, nil)
 	}
 
-	m.updateEventC <- &queueUpdateEvent{
+	m.enqueueUpdateEvent(&queueUpdateEvent{
 		project: m.project,
 		db:      m.config.DBAlias,
 		col:     utils.TableEventingLogs,
 		req:     m.generateFailedEventRequest(eventDoc.ID, "Max retires limit reached"),
 		err:     "Eventing staged event handler could not update event doc",
-	}
+	})
 }
 
 func (m *Module) invokeWebhook(ctx context.Context, token string, client model.HTTPEventingInterface, rule *config.EventingTrigger, eventDoc *model.EventDocument, params interface{}) error {
-	ctxLocal, cancel := context.WithTimeout(ctx, time.Duration(rule.Timeout)*time.Millisecond)
-	defer cancel()
+	ctx, span := tracing.StartSpan(ctx, "eventing.invoke_webhook", trace.SpanKindClient, label.String("http.method", http.MethodPost), label.String("http.url", rule.URL))
+	err := m.callWebhook(ctx, token, client, rule, eventDoc, params)
+	tracing.EndSpan(ctx, span, err)
+	return err
+}
 
-	scToken, err := m.auth.GetSCAccessToken(ctx)
+func (m *Module) callWebhook(ctx context.Context, token string, client model.HTTPEventingInterface, rule *config.EventingTrigger, eventDoc *model.EventDocument, params interface{}) error {
+	eventResponse, err := m.deliverEvent(ctx, token, client, rule, eventDoc.ID, params)
 	if err != nil {
-		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "error invoking web hook in eventing unable to get sc access token", err, nil)
+		return err
 	}
 
+	if eventResponse.Response != nil {
+		if m.pubsubClient != nil {
+			sendTopic := getEventResponseTopic(m.getSpaceCloudIDFromBatchID(eventDoc.BatchID))
+			err := m.pubsubClient.Send(ctx, sendTopic, model.EventResponseMessage{BatchID: eventDoc.BatchID, Response: eventResponse.Response})
+			if err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error invoking web hook in eventing unable to send http request for synchronous response to node %s", sendTopic), err, nil)
+			}
+		}
+	}
+
+	m.queueResponseEvents(ctx, eventResponse, eventDoc.BatchID)
+
+	m.enqueueUpdateEvent(&queueUpdateEvent{
+		project: m.project,
+		db:      m.config.DBAlias,
+		col:     utils.TableEventingLogs,
+		req:     m.generateProcessedEventRequest(eventDoc.ID),
+		err:     "Eventing: Couldn't update staged event to processed",
+	})
+	return nil
+}
+
+// deliverEvent delivers the payload to the target of the trigger and returns the response of the service
+func (m *Module) deliverEvent(ctx context.Context, token string, client model.HTTPEventingInterface, rule *config.EventingTrigger, eventID string, params interface{}) (*model.EventResponse, error) {
+	ctxLocal, cancel := context.WithTimeout(ctx, time.Duration(rule.Timeout)*time.Millisecond)
+	defer cancel()
+
 	var eventResponse model.EventResponse
-	if err := m.MakeInvocationHTTPRequest(ctxLocal, client, http.MethodPost, rule.URL, eventDoc.ID, token, scToken, params, &eventResponse); err != nil {
-		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error invoking web hook in eventing unable to send http request to url %s", rule.URL), err, nil)
+	if rule.Target == config.EventingTargetWorkflow {
+		workflowID := strings.TrimPrefix(rule.ID, workflowTriggerPrefix)
+		if err := m.startWorkflow(ctxLocal, workflowID, eventID, params); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error starting workflow (%s) in eventing", workflowID), err, nil)
+		}
+	} else if rule.Target == config.EventingTargetAWSLambda {
+		if err := m.invokeLambda(ctxLocal, rule, eventID, params, &eventResponse); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error invoking lambda function (%s) in eventing", rule.Lambda.Function), err, nil)
+		}
+	} else {
+		scToken, err := m.auth.GetSCAccessToken(ctx)
+		if err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "error invoking web hook in eventing unable to get sc access token", err, nil)
+		}
+
+		if rule.Target == config.EventingTargetGCPFunction {
+			client, err = m.targets.getGCPClient(client, rule.URL)
+			if err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error invoking cloud function (%s) in eventing unable to get identity token", rule.URL), err, nil)
+			}
+		}
+
+		if err := m.MakeInvocationHTTPRequest(ctxLocal, client, http.MethodPost, rule.URL, eventID, token, scToken, params, &eventResponse); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error invoking web hook in eventing unable to send http request to url %s", rule.URL), err, nil)
+		}
 	}
 
 	// Check if response contains an error
 	if eventResponse.Error != "" {
-		return errors.New(eventResponse.Error)
+		return nil, errors.New(eventResponse.Error)
 	}
+	return &eventResponse, nil
+}
 
+// queueResponseEvents queues the events returned by the service in its response
+func (m *Module) queueResponseEvents(ctx context.Context, eventResponse *model.EventResponse, batchID string) {
 	var eventRequests []*model.QueueEventRequest
 	// Check if response contains an event request
 	if eventResponse.Event != nil {
@@ -217,28 +298,9 @@ func (m *Module) invokeWebhook(ctx context.Context, token string, client model.H
 		eventRequests = append(eventRequests, eventResponse.Events...)
 	}
 
-	if eventResponse.Response != nil {
-		if m.pubsubClient != nil {
-			sendTopic := getEventResponseTopic(m.getSpaceCloudIDFromBatchID(eventDoc.BatchID))
-			err = m.pubsubClient.Send(ctxLocal, sendTopic, model.EventResponseMessage{BatchID: eventDoc.BatchID, Response: eventResponse.Response})
-			if err != nil {
-				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error invoking web hook in eventing unable to send http request for synchronous response to node %s", sendTopic), err, nil)
-			}
-		}
-	}
-
 	if len(eventRequests) > 0 {
-		if err := m.batchRequests(ctx, eventRequests, eventDoc.BatchID); err != nil {
+		if err := m.batchRequests(ctx, eventRequests, batchID); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "error invoking web hook in eventing unable to persist events off", err, nil)
 		}
 	}
-
-	m.updateEventC <- &queueUpdateEvent{
-		project: m.project,
-		db:      m.config.DBAlias,
-		col:     utils.TableEventingLogs,
-		req:     m.generateProcessedEventRequest(eventDoc.ID),
-		err:     "Eventing: Couldn't update staged event to processed",
-	}
-	return nil
 }
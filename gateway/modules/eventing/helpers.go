@@ -41,6 +41,26 @@ func (m *Module) transmitEvents(eventToken int, eventDocs []*model.EventDocument
 	}
 }
 
+// transmitEventDocs transmits the events to the workers assigned to their tokens. Events of triggers with an order key
+// carry their own token while the rest share the token of the batch. The caller must hold the lock of the module.
+func (m *Module) transmitEventDocs(batchToken int, eventDocs []*model.EventDocument) {
+	var tokens []int
+	docsByToken := map[int][]*model.EventDocument{}
+	for _, eventDoc := range eventDocs {
+		token := batchToken
+		if rule, ok := m.config.Rules[eventDoc.RuleName]; ok && rule.OrderBy != "" {
+			token = eventDoc.Token
+		}
+		if _, ok := docsByToken[token]; !ok {
+			tokens = append(tokens, token)
+		}
+		docsByToken[token] = append(docsByToken[token], eventDoc)
+	}
+	for _, token := range tokens {
+		m.transmitEvents(token, docsByToken[token])
+	}
+}
+
 func (m *Module) getSpaceCloudIDFromBatchID(batchID string) string {
 	return strings.Split(batchID, "--")[1]
 }
@@ -84,7 +104,7 @@ func (m *Module) batchRequestsRaw(ctx context.Context, token int, requests []*mo
 	}
 
 	// Broadcast the event so the concerned worker can process it immediately
-	m.transmitEvents(token, eventDocs)
+	m.transmitEventDocs(token, eventDocs)
 	return nil
 }
 
@@ -116,6 +136,13 @@ func (m *Module) generateQueueEventRequestRaw(ctx context.Context, token int, ru
 
 	data, _ := json.Marshal(event.Payload)
 
+	// Events with the same order key are assigned the same token so that they are delivered by the same worker
+	if rule.OrderBy != "" {
+		if key, ok := getOrderKey(rule.OrderBy, string(data)); ok {
+			token = getOrderKeyToken(key)
+		}
+	}
+
 	return &model.EventDocument{
 		ID:          eventDocID,
 		BatchID:     batchID,
@@ -224,6 +251,15 @@ func (m *Module) getMatchingRules(ctx context.Context, req *model.QueueEventRequ
 				continue
 			}
 		}
+		if expr, ok := m.filters[rule.ID]; ok {
+			matched, err := matchFilterExpression(expr, req.Payload)
+			if err != nil {
+				helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to evaluate filter expression of trigger (%s)", rule.ID), map[string]interface{}{"error": err.Error()})
+			}
+			if !matched {
+				continue
+			}
+		}
 
 		// Add rule to list of returned rules
 		rule.TriggerType = "external"
@@ -286,7 +322,7 @@ func (m *Module) selectRule(name string) (*config.EventingTrigger, error) {
 }
 
 func (m *Module) validate(ctx context.Context, project, token string, event *model.QueueEventRequest) error {
-	if event.Type == utils.EventDBCreate || event.Type == utils.EventDBDelete || event.Type == utils.EventDBUpdate || event.Type == utils.EventFileCreate || event.Type == utils.EventFileDelete {
+	if isInternalEventType(event.Type) {
 		return fmt.Errorf("cannot create internal event (%s) with project token", event.Type)
 	}
 
@@ -320,6 +356,16 @@ func (m *Module) createGoTemplate(kind, triggerName, tmpl string) error {
 	return nil
 }
 
+func (m *Module) createJQQuery(kind, triggerName, query string) error {
+	q, err := tmpl2.ParseJQ(query)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid jq expression provided", err, nil)
+	}
+
+	m.jqQueries[getGoTemplateKey(kind, triggerName)] = q
+	return nil
+}
+
 func getGoTemplateKey(kind, triggerName string) string {
 	return fmt.Sprintf("%s---%s", kind, triggerName)
 }
@@ -336,6 +382,13 @@ func (m *Module) adjustReqBody(ctx context.Context, trigger, token string, endpo
 				return nil, err
 			}
 		}
+	case config.TemplatingEngineJQ:
+		if query, p := m.jqQueries[getGoTemplateKey("trigger", trigger)]; p {
+			req, err = tmpl2.JQTemplate(ctx, query, endpoint.OpFormat, token, auth, params)
+			if err != nil {
+				return nil, err
+			}
+		}
 	default:
 		helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid templating engine (%s) provided. Skipping templating step.", endpoint.Tmpl), map[string]interface{}{"trigger": trigger})
 		return params, nil
@@ -359,6 +412,13 @@ func (m *Module) generateWebhookToken(ctx context.Context, trigger *config.Event
 				return "", err
 			}
 		}
+	case config.TemplatingEngineJQ:
+		if query, p := m.jqQueries[getGoTemplateKey("claim", trigger.ID)]; p {
+			req, err = tmpl2.JQTemplate(ctx, query, trigger.OpFormat, "", nil, doc)
+			if err != nil {
+				return "", err
+			}
+		}
 	default:
 		helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid templating engine (%s) provided. Skipping templating step.", trigger.Tmpl), map[string]interface{}{"trigger": trigger})
 		return m.auth.GetInternalAccessToken(ctx)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 func (m *Module) logInvocation(ctx context.Context, eventID string, payload []byte, responseStatusCode int, responseBody, errorMsg string) error {
@@ -58,6 +59,10 @@ func (m *Module) MakeInvocationHTTPRequest(ctx context.Context, client model.HTT
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("x-sc-token", "Bearer "+scToken)
 
+	// Propagate the trace context and the request id to the webhook
+	tracing.Inject(ctx, req.Header)
+	utils.InjectRequestID(ctx, req.Header)
+
 	req = req.WithContext(ctx)
 	resp, err := client.Do(req)
 	if err != nil {
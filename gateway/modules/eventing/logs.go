@@ -0,0 +1,280 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const defaultEventLogLimit int64 = 100
+
+// GetEventLogs returns the events in the event log matching the filter
+func (m *Module) GetEventLogs(ctx context.Context, filter *model.EventLogFilter) ([]*model.EventDocument, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	eventDocs, err := m.readEventLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshalEventPayloads(eventDocs)
+	return eventDocs, nil
+}
+
+// unmarshalEventPayloads unmarshals the payloads of the events which are stored as json
+func unmarshalEventPayloads(eventDocs []*model.EventDocument) {
+	for _, eventDoc := range eventDocs {
+		if data, ok := eventDoc.Payload.(string); ok {
+			var doc interface{}
+			if err := json.Unmarshal([]byte(data), &doc); err == nil {
+				eventDoc.Payload = doc
+			}
+		}
+	}
+}
+
+// ReplayEvents queues the processed and failed events matching the filter once again. The replayed events are new
+// events with the same type and payload so that the history of the original ones is preserved. It returns the ids
+// of the replayed events.
+func (m *Module) ReplayEvents(ctx context.Context, filter *model.EventLogFilter) ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if filter.Status != "" && filter.Status != utils.EventStatusProcessed && filter.Status != utils.EventStatusFailed {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Cannot replay events with status (%s)", filter.Status), nil, nil)
+	}
+
+	eventDocs, err := m.readEventLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ids, err := m.replayEventDocs(ctx, eventDocs)
+	return ids, err
+}
+
+// replayEventDocs queues new copies of the processed and failed events. It returns the ids of the events which were
+// replayed along with the ids of their copies. The caller must hold the lock of the module.
+func (m *Module) replayEventDocs(ctx context.Context, eventDocs []*model.EventDocument) ([]string, []string, error) {
+	token := rand.Intn(utils.MaxEventTokens)
+	batchID := m.generateBatchID()
+	timestamp := time.Now().Format(time.RFC3339Nano)
+
+	replayed := make([]*model.EventDocument, 0, len(eventDocs))
+	sources := make([]string, 0, len(eventDocs))
+	ids := make([]string, 0, len(eventDocs))
+	for _, eventDoc := range eventDocs {
+		// Events which are still being processed or were cancelled are never replayed
+		if eventDoc.Status != utils.EventStatusProcessed && eventDoc.Status != utils.EventStatusFailed {
+			continue
+		}
+
+		rule, ok := m.config.Rules[eventDoc.RuleName]
+		if !ok {
+			helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Skipping replay of event (%s) as trigger (%s) doesn't exist anymore", eventDoc.ID, eventDoc.RuleName), nil)
+			continue
+		}
+
+		eventToken := token
+		if rule.OrderBy != "" {
+			eventToken = eventDoc.Token
+		}
+
+		payload := eventDoc.Payload
+		if _, ok := payload.(string); !ok {
+			data, _ := json.Marshal(payload)
+			payload = string(data)
+		}
+
+		id := ksuid.New().String()
+		replayed = append(replayed, &model.EventDocument{
+			ID:          id,
+			BatchID:     batchID,
+			Type:        eventDoc.Type,
+			RuleName:    eventDoc.RuleName,
+			Token:       eventToken,
+			Timestamp:   timestamp,
+			Payload:     payload,
+			Status:      utils.EventStatusStaged,
+			Remark:      fmt.Sprintf("replay of event %s", eventDoc.ID),
+			TriggerType: eventDoc.TriggerType,
+		})
+		sources = append(sources, eventDoc.ID)
+		ids = append(ids, id)
+	}
+
+	if len(replayed) == 0 {
+		return sources, ids, nil
+	}
+
+	createRequest := &model.CreateRequest{Document: convertToArray(replayed), Operation: utils.All, IsBatch: true}
+	if err := m.crud.InternalCreate(ctx, m.config.DBAlias, m.project, utils.TableEventingLogs, createRequest, false); err != nil {
+		return nil, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to persist replayed events", err, nil)
+	}
+
+	// Broadcast the events so the concerned workers can process them immediately
+	m.transmitEventDocs(token, replayed)
+	return sources, ids, nil
+}
+
+// readEventLogs reads the events matching the filter. The caller must hold the lock of the module.
+func (m *Module) readEventLogs(ctx context.Context, filter *model.EventLogFilter) ([]*model.EventDocument, error) {
+	find, err := getEventLogFind(filter)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid event log filter provided", err, nil)
+	}
+
+	eventLimit := filter.Limit
+	if eventLimit <= 0 {
+		eventLimit = defaultEventLogLimit
+	}
+	if eventLimit > limit {
+		eventLimit = limit
+	}
+
+	options := &model.ReadOptions{Sort: []string{"-ts"}, Limit: &eventLimit}
+	if filter.Skip > 0 {
+		options.Skip = &filter.Skip
+	}
+
+	dbAlias, col := m.config.DBAlias, utils.TableEventingLogs
+	attr := map[string]string{"project": m.project, "db": dbAlias, "col": col}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	results, _, err := m.crud.Read(ctx, dbAlias, col, &model.ReadRequest{Operation: utils.All, Options: options, Find: find}, reqParams)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read the event log", err, nil)
+	}
+
+	rows, _ := results.([]interface{})
+	eventDocs := make([]*model.EventDocument, 0, len(rows))
+	for _, row := range rows {
+		eventDoc := new(model.EventDocument)
+		if err := mapstructure.Decode(row, eventDoc); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Could not covert object (%v) as event doc", row), err, nil)
+		}
+		eventDocs = append(eventDocs, eventDoc)
+	}
+	return eventDocs, nil
+}
+
+func getEventLogFind(filter *model.EventLogFilter) (map[string]interface{}, error) {
+	find := map[string]interface{}{}
+	if len(filter.IDs) > 0 {
+		ids := make([]interface{}, len(filter.IDs))
+		for i, id := range filter.IDs {
+			ids[i] = id
+		}
+		find["_id"] = map[string]interface{}{"$in": ids}
+	}
+	if filter.Trigger != "" {
+		find["rule_name"] = filter.Trigger
+	}
+	if filter.Status != "" {
+		find["status"] = filter.Status
+	}
+
+	ts := map[string]interface{}{}
+	if filter.From != "" {
+		from, err := time.Parse(time.RFC3339, filter.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from timestamp (%s) provided", filter.From)
+		}
+		ts["$gte"] = from.Format(time.RFC3339Nano)
+	}
+	if filter.To != "" {
+		to, err := time.Parse(time.RFC3339, filter.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to timestamp (%s) provided", filter.To)
+		}
+		ts["$lte"] = to.Format(time.RFC3339Nano)
+	}
+	if len(ts) > 0 {
+		find["ts"] = ts
+	}
+	return find, nil
+}
+
+// cleanupEventLogs deletes the processed and failed events, along with their invocations, which are older than the
+// retention period. Failed events retried from the dead letter queue are retained as long as the failed ones. Each
+// worker only deletes the events assigned to it.
+func (m *Module) cleanupEventLogs(t *time.Time) {
+	// Return if module is not enabled
+	if !m.IsEnabled() {
+		return
+	}
+
+	m.lock.RLock()
+	dbAlias, retention := m.config.DBAlias, m.config.Retention
+	m.lock.RUnlock()
+
+	if retention == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	start, end := m.syncMan.GetAssignedTokens()
+	for status, days := range map[string]int{utils.EventStatusProcessed: retention.ProcessedDays, utils.EventStatusFailed: retention.FailedDays, utils.EventStatusRetried: retention.FailedDays} {
+		if days <= 0 {
+			continue
+		}
+
+		find := map[string]interface{}{
+			"status": status,
+			"ts":     map[string]interface{}{"$lt": t.Add(-time.Duration(days) * 24 * time.Hour).Format(time.RFC3339Nano)},
+			"token":  map[string]interface{}{"$gte": start, "$lte": end},
+		}
+		if err := m.deleteEventLogs(ctx, dbAlias, find); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Eventing cleanup routine could not delete %s events", status), err, nil)
+		}
+	}
+}
+
+func (m *Module) deleteEventLogs(ctx context.Context, dbAlias string, find map[string]interface{}) error {
+	attr := map[string]string{"project": m.project, "db": dbAlias, "col": utils.TableEventingLogs}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+
+	for {
+		readRequest := &model.ReadRequest{Operation: utils.All, Options: &model.ReadOptions{Select: map[string]int32{"_id": 1}, Limit: &limit}, Find: find}
+		results, _, err := m.crud.Read(ctx, dbAlias, utils.TableEventingLogs, readRequest, reqParams)
+		if err != nil {
+			return err
+		}
+
+		rows, _ := results.([]interface{})
+		ids := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			if doc, ok := row.(map[string]interface{}); ok {
+				ids = append(ids, doc["_id"])
+			}
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// Invocations are deleted first since they refer to the events
+		invocations := &model.DeleteRequest{Find: map[string]interface{}{"event_id": map[string]interface{}{"$in": ids}}, Operation: utils.All}
+		if err := m.crud.InternalDelete(ctx, dbAlias, m.project, utils.TableInvocationLogs, invocations); err != nil {
+			return err
+		}
+		events := &model.DeleteRequest{Find: map[string]interface{}{"_id": map[string]interface{}{"$in": ids}}, Operation: utils.All}
+		if err := m.crud.InternalDelete(ctx, dbAlias, m.project, utils.TableEventingLogs, events); err != nil {
+			return err
+		}
+
+		if int64(len(rows)) < limit {
+			return nil
+		}
+	}
+}
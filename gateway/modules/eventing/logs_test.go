@@ -0,0 +1,81 @@
+package eventing
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_getEventLogFind(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *model.EventLogFilter
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{name: "empty filter", filter: &model.EventLogFilter{}, want: map[string]interface{}{}},
+		{
+			name:   "trigger, status and time window",
+			filter: &model.EventLogFilter{Trigger: "on_order", Status: utils.EventStatusFailed, From: "2020-01-01T00:00:00Z", To: "2020-01-02T00:00:00Z"},
+			want: map[string]interface{}{
+				"rule_name": "on_order",
+				"status":    utils.EventStatusFailed,
+				"ts":        map[string]interface{}{"$gte": "2020-01-01T00:00:00Z", "$lte": "2020-01-02T00:00:00Z"},
+			},
+		},
+		{name: "ids", filter: &model.EventLogFilter{IDs: []string{"1", "2"}}, want: map[string]interface{}{"_id": map[string]interface{}{"$in": []interface{}{"1", "2"}}}},
+		{name: "invalid time", filter: &model.EventLogFilter{From: "yesterday"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getEventLogFind(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getEventLogFind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getEventLogFind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_ReplayEvents(t *testing.T) {
+	events := []interface{}{
+		map[string]interface{}{"_id": "1", "type": "order_placed", "rule_name": "on_order", "token": 10, "payload": `{"id":"1"}`, "status": utils.EventStatusFailed},
+		map[string]interface{}{"_id": "2", "type": "order_placed", "rule_name": "on_order", "token": 20, "payload": `{"id":"2"}`, "status": utils.EventStatusStaged},
+		map[string]interface{}{"_id": "3", "type": "order_placed", "rule_name": "removed_trigger", "token": 30, "payload": `{"id":"3"}`, "status": utils.EventStatusProcessed},
+	}
+
+	crud := &mockCrudInterface{}
+	crud.On("Read", mock.Anything, "db", utils.TableEventingLogs, mock.Anything).Return(events, new(model.SQLMetaData), nil)
+	crud.On("InternalCreate", mock.Anything, "db", "project", utils.TableEventingLogs, mock.MatchedBy(func(req *model.CreateRequest) bool {
+		docs := req.Document.([]interface{})
+		if len(docs) != 1 {
+			return false
+		}
+		doc := docs[0].(map[string]interface{})
+		return doc["rule_name"] == "on_order" && doc["payload"] == `{"id":"1"}` && doc["status"] == utils.EventStatusStaged && doc["remark"] == "replay of event 1"
+	}), false).Return(nil)
+	syncMan := &mockSyncmanEventingInterface{}
+	syncMan.On("GetAssignedSpaceCloudID", mock.Anything, "project", mock.Anything).Return("node", nil)
+
+	m := &Module{project: "project", nodeID: "node", crud: crud, syncMan: syncMan, config: &config.Eventing{Enabled: true, DBAlias: "db", Rules: config.EventingTriggers{"on_order": {ID: "on_order"}}}}
+	ids, err := m.ReplayEvents(context.Background(), &model.EventLogFilter{Trigger: "on_order"})
+	if err != nil {
+		t.Fatalf("ReplayEvents() error = %v", err)
+	}
+	if len(ids) != 1 {
+		t.Errorf("ReplayEvents() replayed = %v, want a single event", ids)
+	}
+	crud.AssertExpectations(t)
+
+	if _, err := m.ReplayEvents(context.Background(), &model.EventLogFilter{Status: utils.EventStatusStaged}); err == nil {
+		t.Errorf("ReplayEvents() expected an error when replaying staged events")
+	}
+}
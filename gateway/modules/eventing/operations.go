@@ -130,6 +130,24 @@ func (m *Module) SetRealtimeTriggers(eventingRules []*config.EventingTrigger) {
 	}
 }
 
+// SetSearchTriggers adds triggers which are used by the search module to keep the search indexes in sync
+func (m *Module) SetSearchTriggers(eventingRules []*config.EventingTrigger) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for key := range m.config.InternalRules {
+		if strings.HasPrefix(key, "search") {
+			delete(m.config.InternalRules, key)
+		}
+	}
+
+	for _, incomingRule := range eventingRules {
+		key := strings.Join([]string{"search", incomingRule.Options["db"], incomingRule.Options["col"], incomingRule.Type}, "-")
+		incomingRule.ID = key
+		m.config.InternalRules[key] = incomingRule
+	}
+}
+
 // SetInternalTriggersFromDbRules set internal triggers from db rules
 func (m *Module) SetInternalTriggersFromDbRules(dbRules config.DatabaseRules) {
 	m.lock.Lock()
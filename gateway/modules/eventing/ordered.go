@@ -0,0 +1,108 @@
+package eventing
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// orderedQueues delivers the events of triggers having an order key one after the other for each value of the key
+type orderedQueues struct {
+	lock   sync.Mutex
+	queues map[string][]*model.EventDocument // key is the trigger name along with the value of the order key
+	queued map[string]struct{}               // ids of the events which are queued or being delivered
+}
+
+func newOrderedQueues() *orderedQueues {
+	return &orderedQueues{queues: map[string][]*model.EventDocument{}, queued: map[string]struct{}{}}
+}
+
+// getOrderKey returns the value of the order key of the trigger in the payload of the event
+func getOrderKey(orderBy string, payload interface{}) (string, bool) {
+	if data, ok := payload.(string); ok {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(data), &doc); err != nil {
+			return "", false
+		}
+		payload = doc
+	}
+
+	value, err := utils.LoadValue("payload."+orderBy, map[string]interface{}{"payload": payload})
+	if err != nil || value == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%v", value), true
+}
+
+// getOrderKeyToken returns the token for the value of an order key. Events having the same key are assigned the same
+// token so that they are always delivered by the same worker.
+func getOrderKeyToken(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(utils.MaxEventTokens))
+}
+
+// dispatchStagedEvent starts the delivery of a staged event. Events of triggers having an order key are queued behind
+// the events with the same key which are yet to be delivered.
+func (m *Module) dispatchStagedEvent(eventDoc *model.EventDocument) {
+	m.lock.RLock()
+	var orderBy string
+	if m.config != nil {
+		if rule, ok := m.config.Rules[eventDoc.RuleName]; ok {
+			orderBy = rule.OrderBy
+		}
+	}
+	m.lock.RUnlock()
+
+	key, ok := getOrderKey(orderBy, eventDoc.Payload)
+	if orderBy == "" || !ok {
+		go m.processStagedEvent(eventDoc)
+		return
+	}
+
+	if m.ordered.enqueue(eventDoc.RuleName+"::"+key, eventDoc) {
+		go m.ordered.drain(eventDoc.RuleName+"::"+key, m.processStagedEvent)
+	}
+}
+
+// enqueue adds the event to the queue of the key. It returns true if the queue needs to be drained.
+func (q *orderedQueues) enqueue(key string, eventDoc *model.EventDocument) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	// The same event can be dispatched by both the broadcast and the staged event routine
+	if _, ok := q.queued[eventDoc.ID]; ok {
+		return false
+	}
+	q.queued[eventDoc.ID] = struct{}{}
+
+	pending, draining := q.queues[key]
+	q.queues[key] = append(pending, eventDoc)
+	return !draining
+}
+
+// drain delivers the events of the key one at a time till the queue is empty
+func (q *orderedQueues) drain(key string, process func(eventDoc *model.EventDocument)) {
+	for {
+		q.lock.Lock()
+		pending := q.queues[key]
+		if len(pending) == 0 {
+			delete(q.queues, key)
+			q.lock.Unlock()
+			return
+		}
+		eventDoc := pending[0]
+		q.queues[key] = pending[1:]
+		q.lock.Unlock()
+
+		process(eventDoc)
+
+		q.lock.Lock()
+		delete(q.queued, eventDoc.ID)
+		q.lock.Unlock()
+	}
+}
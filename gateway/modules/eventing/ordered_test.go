@@ -0,0 +1,79 @@
+package eventing
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_getOrderKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		orderBy string
+		payload interface{}
+		want    string
+		wantOk  bool
+	}{
+		{name: "key in json payload", orderBy: "doc.id", payload: `{"doc":{"id":"1","status":"paid"}}`, want: "1", wantOk: true},
+		{name: "numeric key", orderBy: "doc.id", payload: map[string]interface{}{"doc": map[string]interface{}{"id": float64(12)}}, want: "12", wantOk: true},
+		{name: "missing key", orderBy: "doc.id", payload: `{"doc":{"status":"paid"}}`},
+		{name: "invalid payload", orderBy: "doc.id", payload: `{"doc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := getOrderKey(tt.orderBy, tt.payload)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("getOrderKey() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_orderedQueues(t *testing.T) {
+	q := newOrderedQueues()
+
+	var lock sync.Mutex
+	var delivered []string
+	started, release := make(chan struct{}), make(chan struct{})
+	process := func(eventDoc *model.EventDocument) {
+		if eventDoc.ID == "1" {
+			close(started)
+			<-release
+		}
+		lock.Lock()
+		delivered = append(delivered, eventDoc.ID)
+		lock.Unlock()
+	}
+
+	if !q.enqueue("trigger::a", &model.EventDocument{ID: "1"}) {
+		t.Fatalf("enqueue() expected the first event of a key to start draining")
+	}
+	done := make(chan struct{})
+	go func() {
+		q.drain("trigger::a", process)
+		close(done)
+	}()
+	<-started
+
+	// Events queued while the key is being drained wait for the previous ones
+	if q.enqueue("trigger::a", &model.EventDocument{ID: "2"}) {
+		t.Errorf("enqueue() expected the key to be drained already")
+	}
+	if q.enqueue("trigger::a", &model.EventDocument{ID: "2"}) {
+		t.Errorf("enqueue() expected a duplicate event to be ignored")
+	}
+	if !q.enqueue("trigger::b", &model.EventDocument{ID: "3"}) {
+		t.Errorf("enqueue() expected an unrelated key to start draining")
+	}
+	close(release)
+	<-done
+
+	if want := []string{"1", "2"}; !reflect.DeepEqual(delivered, want) {
+		t.Errorf("drain() delivered = %v, want %v", delivered, want)
+	}
+	if getOrderKeyToken("1") != getOrderKeyToken("1") {
+		t.Errorf("getOrderKeyToken() expected the same token for a key")
+	}
+}
@@ -0,0 +1,67 @@
+package eventing
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// ValidateEvents checks if the events can be queued by the client making the request
+func (m *Module) ValidateEvents(ctx context.Context, project, token string, events []*model.QueueEventRequest) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, event := range events {
+		if err := m.validate(ctx, project, token, event); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to publish event validation failed", err, nil)
+		}
+	}
+	return nil
+}
+
+// CreateOutboxIntent generates the staged events which are written to the event log in the same transaction as the
+// writes of a batch. The event log acts as the outbox, hence the events get published if and only if the writes get
+// committed. This is only possible when the batch is made on the database used by eventing.
+func (m *Module) CreateOutboxIntent(ctx context.Context, dbAlias string, events []*model.QueueEventRequest) (*model.EventIntent, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if !m.config.Enabled {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Cannot publish events along with the batch as eventing isn't enabled", nil, nil)
+	}
+	if dbAlias != m.config.DBAlias {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Events can only be published along with batches made on the database used by eventing (%s)", m.config.DBAlias), nil, nil)
+	}
+
+	token := rand.Intn(utils.MaxEventTokens)
+	batchID := m.generateBatchID()
+
+	eventDocs := make([]*model.EventDocument, 0)
+	for _, event := range events {
+		for _, rule := range m.getMatchingRules(ctx, event) {
+			eventDocs = append(eventDocs, m.generateQueueEventRequest(ctx, token, rule, batchID, utils.EventStatusStaged, event))
+		}
+	}
+	return &model.EventIntent{BatchID: batchID, Token: token, Docs: eventDocs}, nil
+}
+
+// TransmitOutboxEvents broadcasts the events of a committed batch so that the concerned workers can process them
+// immediately. Events which don't reach a worker are relayed by the staged event routine.
+func (m *Module) TransmitOutboxEvents(intent *model.EventIntent, events []*model.QueueEventRequest) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if len(intent.Docs) > 0 {
+		m.transmitEventDocs(intent.Token, intent.Docs)
+	}
+
+	// Log event metric
+	for _, event := range events {
+		m.metricHook(m.project, event.Type)
+	}
+}
@@ -37,6 +37,27 @@ func (m *Module) routineProcessStaged() {
 	}
 }
 
+func (m *Module) routineCleanupEventLogs() {
+	m.tickerCleanup = time.NewTicker(time.Hour)
+	for t := range m.tickerCleanup.C {
+		m.cleanupEventLogs(&t)
+	}
+}
+
+func (m *Module) routineResumeWorkflows() {
+	m.tickerResume = time.NewTicker(workflowResumeAfter)
+	for t := range m.tickerResume.C {
+		m.resumeWorkflowRuns(&t)
+	}
+}
+
+func (m *Module) routineCheckDeadLetterDepth() {
+	m.tickerDLQ = time.NewTicker(time.Minute)
+	for range m.tickerDLQ.C {
+		m.checkDeadLetterDepth()
+	}
+}
+
 func (m *Module) routineHandleMessages() {
 	ch, err := m.pubsubClient.Subscribe(context.Background(), getEventingTopic(m.nodeID))
 	if err != nil {
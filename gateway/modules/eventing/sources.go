@@ -0,0 +1,185 @@
+package eventing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type eventSource struct {
+	config *config.EventingSource
+	schema model.Fields
+}
+
+// SetSourceConfig sets the custom event sources of the eventing module
+func (m *Module) SetSourceConfig(sources config.EventingSources) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.sources = map[string]*eventSource{}
+	for _, source := range sources {
+		for _, evType := range append([]string{source.DefaultType}, mapValues(source.Types)...) {
+			if isInternalEventType(evType) {
+				return fmt.Errorf("event source (%s) cannot queue internal event (%s)", source.ID, evType)
+			}
+		}
+
+		s := &eventSource{config: source}
+		if source.Schema != "" {
+			fields, err := parseEventSchema(source.ID, source.Schema)
+			if err != nil {
+				return err
+			}
+			s.schema = fields
+		}
+		m.sources[source.ID] = s
+	}
+	return nil
+}
+
+// IngestEvent queues the webhook received by a custom event source as an event. The request is authorised using the
+// signature and the rule of the source, while the type of the event is derived from its mapping.
+func (m *Module) IngestEvent(ctx context.Context, sourceID, token string, headers http.Header, body []byte) (int, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	source, ok := m.sources[sourceID]
+	if !ok {
+		return http.StatusNotFound, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Event source (%s) does not exist", sourceID), nil, nil)
+	}
+
+	if err := verifySourceSignature(source.config.Signature, headers, body); err != nil {
+		return http.StatusUnauthorized, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to verify the signature of event source (%s)", sourceID), err, nil)
+	}
+
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid payload received by event source (%s)", sourceID), err, nil)
+	}
+
+	evType, err := getSourceEventType(source.config, headers, payload)
+	if err != nil {
+		return http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to map the event to an internal event type", err, nil)
+	}
+
+	if status, err := m.authorizeSourceEvent(ctx, source.config, token, headers, payload); err != nil {
+		return status, err
+	}
+
+	// The payload needs to satisfy the schema of the source as well as the one of the event type it is mapped to
+	for _, schema := range []model.Fields{source.schema, m.schemas[evType]} {
+		if schema == nil {
+			continue
+		}
+		if _, err := schemaHelpers.SchemaValidator(ctx, "", "", evType, schema, payload); err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+
+	req := &model.QueueEventRequest{Type: evType, Payload: payload, Options: map[string]string{"source": sourceID}}
+	if err := m.batchRequests(ctx, []*model.QueueEventRequest{req}, m.generateBatchID()); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to queue event cannot batch requests", err, nil)
+	}
+
+	m.metricHook(m.project, evType)
+	return http.StatusOK, nil
+}
+
+func (m *Module) authorizeSourceEvent(ctx context.Context, source *config.EventingSource, token string, headers http.Header, payload map[string]interface{}) (int, error) {
+	if source.Rule == nil {
+		return http.StatusForbidden, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No rule has been provided for event source (%s)", source.ID), nil, nil)
+	}
+
+	// Third party services generally don't send tokens. Hence the token is only parsed if one is provided.
+	var auth map[string]interface{}
+	if token != "" {
+		claims, err := m.auth.ParseToken(ctx, token)
+		if err != nil {
+			return http.StatusUnauthorized, err
+		}
+		auth = claims
+	}
+
+	h := make(map[string]interface{}, len(headers))
+	for k := range headers {
+		h[strings.ToLower(k)] = headers.Get(k)
+	}
+
+	args := map[string]interface{}{"args": map[string]interface{}{"auth": auth, "token": token, "params": payload, "headers": h, "source": source.ID}}
+	if _, err := m.auth.MatchRule(ctx, m.project, source.Rule, args, auth, model.ReturnWhereStub{}); err != nil {
+		return http.StatusForbidden, err
+	}
+	return http.StatusOK, nil
+}
+
+// verifySourceSignature checks the hex encoded hmac sha256 signature of the body
+func verifySourceSignature(signature *config.EventingSourceSignature, headers http.Header, body []byte) error {
+	if signature == nil {
+		return nil
+	}
+
+	value := headers.Get(signature.Header)
+	if value == "" {
+		return fmt.Errorf("signature header (%s) is missing", signature.Header)
+	}
+	if !strings.HasPrefix(value, signature.Prefix) {
+		return fmt.Errorf("signature does not have the prefix (%s)", signature.Prefix)
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(value, signature.Prefix))
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, []byte(signature.Secret))
+	_, _ = mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// getSourceEventType returns the type of the internal event the event received by a source is mapped to
+func getSourceEventType(source *config.EventingSource, headers http.Header, payload map[string]interface{}) (string, error) {
+	var external string
+	switch {
+	case source.TypeHeader != "":
+		external = headers.Get(source.TypeHeader)
+	case source.TypeField != "":
+		if value, err := utils.LoadValue("payload."+source.TypeField, map[string]interface{}{"payload": payload}); err == nil {
+			external, _ = value.(string)
+		}
+	}
+
+	if evType, ok := source.Types[external]; ok && external != "" {
+		return evType, nil
+	}
+	if source.DefaultType != "" {
+		return source.DefaultType, nil
+	}
+	return "", fmt.Errorf("event type (%s) of source (%s) is not mapped to an internal event type", external, source.ID)
+}
+
+func isInternalEventType(evType string) bool {
+	return evType == utils.EventDBCreate || evType == utils.EventDBDelete || evType == utils.EventDBUpdate || evType == utils.EventFileCreate || evType == utils.EventFileDelete
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
@@ -0,0 +1,99 @@
+package eventing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_getSourceEventType(t *testing.T) {
+	stripe := &config.EventingSource{ID: "stripe", TypeField: "data.type", Types: map[string]string{"charge.succeeded": "payment_received"}}
+	github := &config.EventingSource{ID: "github", TypeHeader: "X-GitHub-Event", Types: map[string]string{"push": "code_pushed"}, DefaultType: "github_event"}
+
+	tests := []struct {
+		name    string
+		source  *config.EventingSource
+		headers http.Header
+		payload map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "type read from a nested field", source: stripe, payload: map[string]interface{}{"data": map[string]interface{}{"type": "charge.succeeded"}}, want: "payment_received"},
+		{name: "unmapped type without default", source: stripe, payload: map[string]interface{}{"data": map[string]interface{}{"type": "charge.failed"}}, wantErr: true},
+		{name: "missing type field", source: stripe, payload: map[string]interface{}{}, wantErr: true},
+		{name: "type read from a header", source: github, headers: http.Header{"X-Github-Event": []string{"push"}}, want: "code_pushed"},
+		{name: "unmapped type with default", source: github, headers: http.Header{"X-Github-Event": []string{"issues"}}, want: "github_event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getSourceEventType(tt.source, tt.headers, tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getSourceEventType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getSourceEventType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_verifySourceSignature(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write(body)
+	valid := hex.EncodeToString(mac.Sum(nil))
+	signature := &config.EventingSourceSignature{Header: "X-Hub-Signature-256", Secret: "secret", Prefix: "sha256="}
+
+	tests := []struct {
+		name      string
+		signature *config.EventingSourceSignature
+		header    string
+		wantErr   bool
+	}{
+		{name: "source without signature", header: ""},
+		{name: "valid signature", signature: signature, header: "sha256=" + valid},
+		{name: "missing signature", signature: signature, header: "", wantErr: true},
+		{name: "missing prefix", signature: signature, header: valid, wantErr: true},
+		{name: "invalid signature", signature: signature, header: "sha256=" + hex.EncodeToString([]byte("invalid")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tt.header != "" {
+				headers.Set("X-Hub-Signature-256", tt.header)
+			}
+			if err := verifySourceSignature(tt.signature, headers, body); (err != nil) != tt.wantErr {
+				t.Errorf("verifySourceSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModule_SetSourceConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  *config.EventingSource
+		wantErr bool
+	}{
+		{name: "valid source", source: &config.EventingSource{ID: "stripe", Schema: "type stripe { id: ID! amount: Integer! }", DefaultType: "stripe_event"}},
+		{name: "source mapped to an internal event", source: &config.EventingSource{ID: "stripe", Types: map[string]string{"charge": utils.EventDBCreate}}, wantErr: true},
+		{name: "source with invalid schema", source: &config.EventingSource{ID: "stripe", Schema: "type stripe {"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Module{}
+			err := m.SetSourceConfig(config.EventingSources{"stripe": tt.source})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetSourceConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && m.sources["stripe"].schema == nil {
+				t.Errorf("SetSourceConfig() schema of source not parsed")
+			}
+		})
+	}
+}
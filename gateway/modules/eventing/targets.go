@@ -0,0 +1,191 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// cloudTargets holds the clients used to deliver events to the functions of cloud providers
+type cloudTargets struct {
+	lock sync.Mutex
+
+	lambdaClients   map[string]lambdaiface.LambdaAPI // key is the region
+	gcpTokenSources map[string]oauth2.TokenSource    // key is the url of the function
+}
+
+func newCloudTargets() *cloudTargets {
+	return &cloudTargets{lambdaClients: map[string]lambdaiface.LambdaAPI{}, gcpTokenSources: map[string]oauth2.TokenSource{}}
+}
+
+func validateTriggerTarget(trigger *config.EventingTrigger) error {
+	switch trigger.Target {
+	case "", config.EventingTargetWebhook:
+	case config.EventingTargetGCPFunction:
+		if trigger.URL == "" {
+			return fmt.Errorf("url of trigger (%s) is not provided", trigger.ID)
+		}
+	case config.EventingTargetAWSLambda:
+		if trigger.Lambda == nil || trigger.Lambda.Function == "" {
+			return fmt.Errorf("lambda function of trigger (%s) is not provided", trigger.ID)
+		}
+	default:
+		return fmt.Errorf("invalid target (%s) provided for trigger (%s)", trigger.Target, trigger.ID)
+	}
+	return nil
+}
+
+// invokeLambda invokes the lambda function of the trigger synchronously with the payload of the event. The response
+// of the function is treated like the response of a webhook.
+func (m *Module) invokeLambda(ctx context.Context, rule *config.EventingTrigger, eventID string, params, vPtr interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.targets.getLambdaClient(rule.Lambda.Region)
+	if err != nil {
+		_ = m.logInvocation(ctx, eventID, data, 0, "", err.Error())
+		return err
+	}
+
+	input := &lambda.InvokeInput{FunctionName: aws.String(rule.Lambda.Function), Payload: data}
+	if rule.Lambda.Qualifier != "" {
+		input.Qualifier = aws.String(rule.Lambda.Qualifier)
+	}
+	out, err := client.InvokeWithContext(ctx, input)
+	if err != nil {
+		_ = m.logInvocation(ctx, eventID, data, 0, "", err.Error())
+		return err
+	}
+
+	status := int(aws.Int64Value(out.StatusCode))
+	if out.FunctionError != nil {
+		err := fmt.Errorf("lambda function failed with error (%s)", aws.StringValue(out.FunctionError))
+		_ = m.logInvocation(ctx, eventID, data, status, string(out.Payload), err.Error())
+		return err
+	}
+
+	// Functions which don't return anything respond with null
+	if len(out.Payload) > 0 {
+		if err := json.Unmarshal(out.Payload, vPtr); err != nil {
+			_ = m.logInvocation(ctx, eventID, data, status, string(out.Payload), err.Error())
+			return err
+		}
+	}
+	return m.logInvocation(ctx, eventID, data, status, string(out.Payload), "")
+}
+
+func (t *cloudTargets) getLambdaClient(region string) (lambdaiface.LambdaAPI, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if client, ok := t.lambdaClients[region]; ok {
+		return client, nil
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, err
+	}
+	cfg := aws.NewConfig()
+	if region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+	client := lambda.New(sess, cfg)
+	t.lambdaClients[region] = client
+	return client, nil
+}
+
+// getGCPClient returns a client which authenticates the requests made to a google cloud function with an identity
+// token whose audience is the url of the function
+func (t *cloudTargets) getGCPClient(client model.HTTPEventingInterface, functionURL string) (model.HTTPEventingInterface, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ts, ok := t.gcpTokenSources[functionURL]
+	if !ok {
+		var err error
+		ts, err = newGCPTokenSource(functionURL)
+		if err != nil {
+			return nil, err
+		}
+		t.gcpTokenSources[functionURL] = ts
+	}
+	return &idTokenClient{client: client, ts: ts}, nil
+}
+
+// newGCPTokenSource creates a source of identity tokens using the service account key pointed to by
+// GOOGLE_APPLICATION_CREDENTIALS or the metadata server when running on google cloud
+func newGCPTokenSource(audience string) (oauth2.TokenSource, error) {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		key, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		conf, err := google.JWTConfigFromJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		conf.PrivateClaims = map[string]interface{}{"target_audience": audience}
+		conf.UseIDToken = true
+		return conf.TokenSource(context.Background()), nil
+	}
+
+	if metadata.OnGCE() {
+		return oauth2.ReuseTokenSource(nil, &metadataTokenSource{audience: audience}), nil
+	}
+	return nil, errors.New("google credentials are not available to invoke the cloud function")
+}
+
+// metadataTokenSource fetches the identity tokens of the default service account from the metadata server
+type metadataTokenSource struct {
+	audience string
+}
+
+func (s *metadataTokenSource) Token() (*oauth2.Token, error) {
+	token, err := metadata.Get("instance/service-accounts/default/identity?audience=" + url.QueryEscape(s.audience))
+	if err != nil {
+		return nil, err
+	}
+	// Identity tokens are valid for an hour
+	return &oauth2.Token{AccessToken: token, Expiry: time.Now().Add(55 * time.Minute)}, nil
+}
+
+// idTokenClient adds the identity token to the requests. The token generated for the trigger, if any, is forwarded
+// in the X-Forwarded-Authorization header.
+type idTokenClient struct {
+	client model.HTTPEventingInterface
+	ts     oauth2.TokenSource
+}
+
+func (c *idTokenClient) Do(req *http.Request) (*http.Response, error) {
+	token, err := c.ts.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		req.Header.Set("X-Forwarded-Authorization", auth)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return c.client.Do(req)
+}
@@ -0,0 +1,115 @@
+package eventing
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/oauth2"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type fakeLambdaClient struct {
+	lambdaiface.LambdaAPI
+	out   *lambda.InvokeOutput
+	input *lambda.InvokeInput
+}
+
+func (f *fakeLambdaClient) InvokeWithContext(ctx aws.Context, input *lambda.InvokeInput, opts ...request.Option) (*lambda.InvokeOutput, error) {
+	f.input = input
+	return f.out, nil
+}
+
+func TestModule_invokeLambda(t *testing.T) {
+	rule := &config.EventingTrigger{ID: "process_payment", Target: config.EventingTargetAWSLambda, Lambda: &config.LambdaTarget{Function: "process-payment", Region: "us-east-1", Qualifier: "live"}}
+
+	tests := []struct {
+		name    string
+		out     *lambda.InvokeOutput
+		want    model.EventResponse
+		wantErr bool
+	}{
+		{name: "function without a response", out: &lambda.InvokeOutput{StatusCode: aws.Int64(200), Payload: []byte("null")}},
+		{name: "function queueing an event", out: &lambda.InvokeOutput{StatusCode: aws.Int64(200), Payload: []byte(`{"event":{"type":"payment_processed"}}`)}, want: model.EventResponse{Event: &model.QueueEventRequest{Type: "payment_processed"}}},
+		{name: "function error", out: &lambda.InvokeOutput{StatusCode: aws.Int64(200), FunctionError: aws.String("Unhandled"), Payload: []byte(`{"errorMessage":"failed"}`)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crud := &mockCrudInterface{}
+			crud.On("InternalCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			client := &fakeLambdaClient{out: tt.out}
+			targets := newCloudTargets()
+			targets.lambdaClients["us-east-1"] = client
+			m := &Module{crud: crud, config: &config.Eventing{DBAlias: "db"}, targets: targets}
+
+			var got model.EventResponse
+			err := m.invokeLambda(context.Background(), rule, "event_1", map[string]interface{}{"id": "event_1"}, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("invokeLambda() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invokeLambda() response = %+v, want %+v", got, tt.want)
+			}
+			if aws.StringValue(client.input.FunctionName) != "process-payment" || aws.StringValue(client.input.Qualifier) != "live" || string(client.input.Payload) != `{"id":"event_1"}` {
+				t.Errorf("invokeLambda() input = %v", client.input)
+			}
+		})
+	}
+}
+
+type recordingHTTPClient struct {
+	req *http.Request
+}
+
+func (c *recordingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.req = req
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func Test_idTokenClient(t *testing.T) {
+	recorder := &recordingHTTPClient{}
+	client := &idTokenClient{client: recorder, ts: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "id-token"})}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://us-central1-project.cloudfunctions.net/fn", nil)
+	req.Header.Set("Authorization", "Bearer trigger-token")
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if got := recorder.req.Header.Get("Authorization"); got != "Bearer id-token" {
+		t.Errorf("Do() authorization = %v, want %v", got, "Bearer id-token")
+	}
+	if got := recorder.req.Header.Get("X-Forwarded-Authorization"); got != "Bearer trigger-token" {
+		t.Errorf("Do() forwarded authorization = %v, want %v", got, "Bearer trigger-token")
+	}
+}
+
+func Test_validateTriggerTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger *config.EventingTrigger
+		wantErr bool
+	}{
+		{name: "webhook", trigger: &config.EventingTrigger{ID: "t", URL: "http://localhost"}},
+		{name: "gcp function", trigger: &config.EventingTrigger{ID: "t", Target: config.EventingTargetGCPFunction, URL: "https://fn.cloudfunctions.net/fn"}},
+		{name: "gcp function without url", trigger: &config.EventingTrigger{ID: "t", Target: config.EventingTargetGCPFunction}, wantErr: true},
+		{name: "lambda", trigger: &config.EventingTrigger{ID: "t", Target: config.EventingTargetAWSLambda, Lambda: &config.LambdaTarget{Function: "fn"}}},
+		{name: "lambda without function", trigger: &config.EventingTrigger{ID: "t", Target: config.EventingTargetAWSLambda}, wantErr: true},
+		{name: "unknown target", trigger: &config.EventingTrigger{ID: "t", Target: "azure-function"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTriggerTarget(tt.trigger); (err != nil) != tt.wantErr {
+				t.Errorf("validateTriggerTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ type queueUpdateEvent struct {
 	project, db, col string
 	req              *model.UpdateRequest
 	err              string
+	// walID is the id of the update in the write ahead log. It is zero if the update wasn't persisted.
+	walID uint64
 }
 
 type mockHTTPInterface struct {
@@ -64,6 +66,11 @@ func (m *mockCrudInterface) InternalUpdate(ctx context.Context, dbAlias, project
 	return c.Error(0)
 }
 
+func (m *mockCrudInterface) InternalDelete(ctx context.Context, dbAlias, project, col string, req *model.DeleteRequest) error {
+	c := m.Called(ctx, dbAlias, project, col, req)
+	return c.Error(0)
+}
+
 type mockSyncmanEventingInterface struct {
 	mock.Mock
 }
@@ -100,6 +107,10 @@ func (m *mockSyncmanEventingInterface) GetSpaceCloudURLFromID(ctx context.Contex
 	return c.String(0), nil
 }
 
+func (m *mockSyncmanEventingInterface) PublishAdminEvent(ctx context.Context, event *model.AdminEvent) {
+	m.Called(ctx, event)
+}
+
 func (m *mockSyncmanEventingInterface) MakeHTTPRequest(ctx context.Context, method, url, token, scToken string, params, vPtr interface{}) error {
 	c := m.Called(ctx, method, url, token, scToken, params, vPtr)
 	return c.Error(0)
@@ -113,6 +124,11 @@ func (m *mockAuthEventingInterface) MatchRule(ctx context.Context, project strin
 	return nil, nil
 }
 
+func (m *mockAuthEventingInterface) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	c := m.Called(ctx, token)
+	return c.Get(0).(map[string]interface{}), c.Error(1)
+}
+
 func (m *mockAuthEventingInterface) CreateToken(ctx context.Context, tokenClaims model.TokenClaims) (string, error) {
 	c := m.Called(ctx, tokenClaims)
 	return c.String(0), c.Error(1)
@@ -0,0 +1,112 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
+)
+
+// walAppendTimeout is the duration for which queueing an update waits for space in a full write ahead log. The update
+// is queued without being persisted after that.
+const walAppendTimeout = 10 * time.Second
+
+// walUpdateEvent is the form in which a queued update is persisted in the write ahead log
+type walUpdateEvent struct {
+	Project string               `json:"project"`
+	DB      string               `json:"db"`
+	Col     string               `json:"col"`
+	Req     *model.UpdateRequest `json:"req"`
+	Err     string               `json:"err"`
+}
+
+// SetWAL sets the write ahead log the queued status updates of the events are persisted in. The updates left behind
+// by the previous run are applied once eventing is enabled.
+func (m *Module) SetWAL(w *wal.WAL) {
+	m.lockWAL.Lock()
+	defer m.lockWAL.Unlock()
+	m.wal = w
+}
+
+func getWALQueue(project string) string {
+	return "eventing::" + project
+}
+
+// enqueueUpdateEvent queues the status update of an event after persisting it in the write ahead log, if any. A full
+// log makes the caller wait till the queued updates have been applied.
+func (m *Module) enqueueUpdateEvent(ev *queueUpdateEvent) {
+	m.lockWAL.RLock()
+	w := m.wal
+	m.lockWAL.RUnlock()
+
+	if w != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), walAppendTimeout)
+		defer cancel()
+
+		data, err := json.Marshal(&walUpdateEvent{Project: ev.project, DB: ev.db, Col: ev.col, Req: ev.req, Err: ev.err})
+		if err == nil {
+			ev.walID, err = w.Append(ctx, getWALQueue(ev.project), data)
+		}
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to persist the status update of an event in the write ahead log", err, map[string]interface{}{"project": ev.project})
+		}
+	}
+
+	m.updateEventC <- ev
+}
+
+// removeUpdateEventFromWAL removes the update from the write ahead log once it has been applied
+func (m *Module) removeUpdateEventFromWAL(ev *queueUpdateEvent) {
+	if ev.walID == 0 {
+		return
+	}
+
+	m.lockWAL.RLock()
+	w := m.wal
+	m.lockWAL.RUnlock()
+
+	if err := w.Remove(getWALQueue(ev.project), ev.walID); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to remove the status update of an event from the write ahead log", err, map[string]interface{}{"project": ev.project})
+	}
+}
+
+// replayWAL queues the updates persisted in the write ahead log by the previous run. It is a no-op after the first
+// call.
+func (m *Module) replayWAL() {
+	m.lockWAL.Lock()
+	w := m.wal
+	if w == nil || m.walReplayed {
+		m.lockWAL.Unlock()
+		return
+	}
+	m.walReplayed = true
+	m.lockWAL.Unlock()
+
+	m.lock.RLock()
+	queue := getWALQueue(m.project)
+	m.lock.RUnlock()
+
+	ctx := context.TODO()
+	entries, err := w.Entries(queue)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read the status updates of the events from the write ahead log", err, nil)
+		return
+	}
+	if len(entries) > 0 {
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "Applying the status updates of the events left behind by the previous run", map[string]interface{}{"count": len(entries)})
+	}
+
+	for _, entry := range entries {
+		ev := new(walUpdateEvent)
+		if err := json.Unmarshal(entry.Data, ev); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to decode the status update of an event in the write ahead log", err, nil)
+			_ = w.Remove(queue, entry.ID)
+			continue
+		}
+		m.updateEventC <- &queueUpdateEvent{project: ev.Project, db: ev.DB, col: ev.Col, req: ev.Req, err: ev.Err, walID: entry.ID}
+	}
+}
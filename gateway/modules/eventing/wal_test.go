@@ -0,0 +1,57 @@
+package eventing
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
+)
+
+func TestModule_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.db")
+	w, err := wal.Open(path, 0)
+	if err != nil {
+		t.Fatalf("wal.Open() error = %v", err)
+	}
+
+	// The gateway crashes before the queued update is applied
+	m := &Module{project: "myproject", updateEventC: make(chan *queueUpdateEvent, 5)}
+	m.SetWAL(w)
+	m.enqueueUpdateEvent(&queueUpdateEvent{project: "myproject", db: "db", col: utils.TableEventingLogs, req: m.generateProcessedEventRequest("1"), err: "error"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	w, err = wal.Open(path, 0)
+	if err != nil {
+		t.Fatalf("wal.Open() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	crud := new(mockCrudInterface)
+	crud.On("InternalUpdate", mock.Anything, "db", "myproject", utils.TableEventingLogs, m.generateProcessedEventRequest("1")).Return(nil)
+
+	m = &Module{project: "myproject", crud: crud, updateEventC: make(chan *queueUpdateEvent, 5)}
+	m.SetWAL(w)
+	m.replayWAL()
+	m.replayWAL()
+	if got := len(m.updateEventC); got != 1 {
+		t.Fatalf("replayWAL() queued %d updates, want 1", got)
+	}
+
+	ev := <-m.updateEventC
+	want := &queueUpdateEvent{project: "myproject", db: "db", col: utils.TableEventingLogs, req: m.generateProcessedEventRequest("1"), err: "error", walID: 1}
+	if !reflect.DeepEqual(ev, want) {
+		t.Errorf("replayWAL() got = %v, want %v", ev, want)
+	}
+
+	m.queueUpdateEvent(ev)
+	crud.AssertExpectations(t)
+	if entries, _ := w.Entries(getWALQueue("myproject")); len(entries) != 0 {
+		t.Errorf("queueUpdateEvent() left %d entries in the write ahead log", len(entries))
+	}
+}
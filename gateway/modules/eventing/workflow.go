@@ -0,0 +1,499 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	workflowTriggerPrefix = "workflow--"
+
+	// defaultWorkflowStepTimeout is the timeout in seconds of the steps which don't specify one
+	defaultWorkflowStepTimeout = 10
+
+	// workflowResumeAfter is the duration after which a run which hasn't been updated is resumed by its worker
+	workflowResumeAfter = time.Minute
+)
+
+const (
+	workflowStatusRunning      = "running"
+	workflowStatusCompensating = "compensating"
+	workflowStatusCompleted    = "completed"
+	workflowStatusCompensated  = "compensated"
+	workflowStatusFailed       = "failed"
+)
+
+// workflowRetryDelay is the time to wait before retrying a failed step
+var workflowRetryDelay = 5 * time.Second
+
+// functionsInterface is used to call the remote services of the steps of workflows
+type functionsInterface interface {
+	CallWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest) (int, interface{}, error)
+}
+
+// workflowRun is a run of a workflow as persisted in the workflow runs table. Step is the index of the step being
+// executed while the run is running and of the step being compensated while it is compensating.
+type workflowRun struct {
+	ID        string `mapstructure:"_id"`
+	Workflow  string `mapstructure:"workflow"`
+	Token     int    `mapstructure:"token"`
+	Status    string `mapstructure:"status"`
+	Step      int    `mapstructure:"step"`
+	State     string `mapstructure:"state"`
+	Error     string `mapstructure:"error"`
+	StartedAt string `mapstructure:"started_at"`
+	UpdatedAt string `mapstructure:"updated_at"`
+}
+
+// SetFunctionsModule sets the functions module used to call the remote services of workflows
+func (m *Module) SetFunctionsModule(f functionsInterface) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.functions = f
+}
+
+// SetWorkflowConfig sets the workflows of the eventing module. Each workflow gets an internal trigger on its event
+// type which starts its runs.
+func (m *Module) SetWorkflowConfig(workflows config.EventingWorkflows) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// First delete the internal triggers of the existing workflows
+	for key := range m.config.InternalRules {
+		if strings.HasPrefix(key, workflowTriggerPrefix) {
+			delete(m.config.InternalRules, key)
+		}
+	}
+
+	m.workflows = make(map[string]*config.EventingWorkflow, len(workflows))
+	for _, workflow := range workflows {
+		if err := validateWorkflow(workflow); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid eventing workflow provided", err, nil)
+		}
+		m.workflows[workflow.ID] = workflow
+
+		triggerName := workflowTriggerPrefix + workflow.ID
+		m.config.InternalRules[triggerName] = &config.EventingTrigger{
+			ID:       triggerName,
+			Type:     workflow.Trigger,
+			Options:  workflow.Options,
+			Target:   config.EventingTargetWorkflow,
+			Retries:  3,
+			Timeout:  5000,
+			Tmpl:     config.TemplatingEngineGo,
+			OpFormat: "yaml",
+		}
+	}
+	return nil
+}
+
+func validateWorkflow(workflow *config.EventingWorkflow) error {
+	if workflow.Trigger == "" {
+		return fmt.Errorf("trigger of workflow (%s) is not provided", workflow.ID)
+	}
+	if len(workflow.Steps) == 0 {
+		return fmt.Errorf("workflow (%s) doesn't have any steps", workflow.ID)
+	}
+	if workflow.Timeout < 0 {
+		return fmt.Errorf("timeout of workflow (%s) cannot be negative", workflow.ID)
+	}
+
+	steps := make(map[string]struct{}, len(workflow.Steps))
+	for _, step := range workflow.Steps {
+		if step.ID == "" {
+			return fmt.Errorf("id of a step of workflow (%s) is not provided", workflow.ID)
+		}
+		if _, ok := steps[step.ID]; ok {
+			return fmt.Errorf("step (%s) of workflow (%s) is defined more than once", step.ID, workflow.ID)
+		}
+		steps[step.ID] = struct{}{}
+
+		if step.Retries < 0 || step.Timeout < 0 {
+			return fmt.Errorf("retries and timeout of step (%s) of workflow (%s) cannot be negative", step.ID, workflow.ID)
+		}
+		if err := validateWorkflowAction(step.Action); err != nil {
+			return fmt.Errorf("invalid action of step (%s) of workflow (%s): %v", step.ID, workflow.ID, err)
+		}
+		if step.Compensate != nil {
+			if err := validateWorkflowAction(step.Compensate); err != nil {
+				return fmt.Errorf("invalid compensating action of step (%s) of workflow (%s): %v", step.ID, workflow.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateWorkflowAction(action *config.WorkflowAction) error {
+	if action == nil {
+		return errors.New("action is not provided")
+	}
+	if action.DB == nil {
+		if action.Service == "" || action.Endpoint == "" {
+			return errors.New("either the service and endpoint or the database operation needs to be provided")
+		}
+		return nil
+	}
+
+	if action.DB.DBAlias == "" || action.DB.Col == "" {
+		return errors.New("db alias and collection of the database operation are required")
+	}
+	switch action.DB.Op {
+	case string(model.Create), string(model.Update), string(model.Delete):
+		return nil
+	default:
+		return fmt.Errorf("invalid database operation (%s) provided", action.DB.Op)
+	}
+}
+
+// startWorkflow persists a new run of the workflow for the event and starts executing it. The id of the run is the
+// id of the event so that redelivering the event doesn't start the workflow again.
+func (m *Module) startWorkflow(ctx context.Context, workflowID, eventID string, params interface{}) error {
+	// The state of the run has the data of the cloud event
+	var payload interface{} = params
+	if cloudEvent, ok := params.(map[string]interface{}); ok {
+		payload = cloudEvent["data"]
+	}
+	state, err := json.Marshal(map[string]interface{}{"event": payload, "steps": map[string]interface{}{}})
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Format(time.RFC3339Nano)
+	run := &workflowRun{
+		ID:        eventID,
+		Workflow:  workflowID,
+		Token:     getOrderKeyToken(eventID),
+		Status:    workflowStatusRunning,
+		State:     string(state),
+		StartedAt: timestamp,
+		UpdatedAt: timestamp,
+	}
+
+	createRequest := &model.CreateRequest{Document: workflowRunDoc(run), Operation: utils.One}
+	if err := m.crud.InternalCreate(ctx, m.config.DBAlias, m.project, utils.TableWorkflowRuns, createRequest, false); err != nil {
+		// The run might have been created by an earlier delivery of the event
+		if existing, readErr := m.readWorkflowRuns(ctx, m.config.DBAlias, map[string]interface{}{"_id": eventID}); readErr == nil && len(existing) > 0 {
+			return nil
+		}
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to persist run of workflow (%s)", workflowID), err, nil)
+	}
+
+	go m.runWorkflow(run)
+	return nil
+}
+
+// runWorkflow executes the steps of a run from the step it is at. If a step fails even after its retries, or the run
+// times out, the completed steps are compensated in the reverse order.
+func (m *Module) runWorkflow(run *workflowRun) {
+	if _, loaded := m.runningWorkflows.LoadOrStore(run.ID, true); loaded {
+		return
+	}
+	defer m.runningWorkflows.Delete(run.ID)
+
+	m.lock.RLock()
+	workflow, ok := m.workflows[run.Workflow]
+	dbAlias := m.config.DBAlias
+	m.lock.RUnlock()
+
+	ctx := context.Background()
+	if !ok {
+		run.Status, run.Error = workflowStatusFailed, fmt.Sprintf("workflow (%s) doesn't exist anymore", run.Workflow)
+		m.saveWorkflowRun(ctx, dbAlias, run)
+		return
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal([]byte(run.State), &state); err != nil {
+		run.Status, run.Error = workflowStatusFailed, "unable to parse state of run"
+		m.saveWorkflowRun(ctx, dbAlias, run)
+		return
+	}
+
+	token, err := m.auth.GetInternalAccessToken(ctx)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to get internal access token for run (%s) of workflow (%s)", run.ID, run.Workflow), err, nil)
+		return
+	}
+
+	if run.Status == workflowStatusRunning {
+		runCtx := ctx
+		if workflow.Timeout > 0 {
+			startedAt, _ := time.Parse(time.RFC3339Nano, run.StartedAt)
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithDeadline(ctx, startedAt.Add(time.Duration(workflow.Timeout)*time.Second))
+			defer cancel()
+		}
+
+		for run.Step < len(workflow.Steps) {
+			step := workflow.Steps[run.Step]
+			result, err := m.executeWorkflowStep(runCtx, token, step, state)
+			if err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Step (%s) of run (%s) of workflow (%s) failed", step.ID, run.ID, run.Workflow), err, nil)
+
+				// The failed step itself is not compensated
+				run.Status, run.Error = workflowStatusCompensating, fmt.Sprintf("step (%s) failed: %v", step.ID, err)
+				run.Step--
+				break
+			}
+
+			state["steps"].(map[string]interface{})[step.ID] = result
+			run.State = marshalWorkflowState(state)
+			run.Step++
+
+			// The last step is saved along with the completion of the run
+			if run.Step < len(workflow.Steps) {
+				m.saveWorkflowRun(ctx, dbAlias, run)
+			}
+		}
+
+		if run.Status == workflowStatusRunning {
+			run.Status = workflowStatusCompleted
+			m.saveWorkflowRun(ctx, dbAlias, run)
+			return
+		}
+		m.saveWorkflowRun(ctx, dbAlias, run)
+	}
+
+	// The steps might have been removed since the run failed
+	if run.Step >= len(workflow.Steps) {
+		run.Step = len(workflow.Steps) - 1
+	}
+
+	for run.Step >= 0 {
+		step := workflow.Steps[run.Step]
+		if step.Compensate != nil {
+			if _, err := m.executeWorkflowAction(ctx, token, step, step.Compensate, state); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Compensation of step (%s) of run (%s) of workflow (%s) failed", step.ID, run.ID, run.Workflow), err, nil)
+				run.Status, run.Error = workflowStatusFailed, fmt.Sprintf("%s; compensation of step (%s) failed: %v", run.Error, step.ID, err)
+				m.saveWorkflowRun(ctx, dbAlias, run)
+				return
+			}
+		}
+		run.Step--
+		m.saveWorkflowRun(ctx, dbAlias, run)
+	}
+
+	run.Status, run.Step = workflowStatusCompensated, 0
+	m.saveWorkflowRun(ctx, dbAlias, run)
+}
+
+// executeWorkflowStep executes the action of the step, retrying it as many times as configured
+func (m *Module) executeWorkflowStep(ctx context.Context, token string, step *config.WorkflowStep, state map[string]interface{}) (interface{}, error) {
+	var err error
+	for retries := 0; retries <= step.Retries; retries++ {
+		if retries > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(workflowRetryDelay):
+			}
+		}
+
+		var result interface{}
+		result, err = m.executeWorkflowAction(ctx, token, step, step.Action, state)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+// executeWorkflowAction calls the remote service or performs the database operation of an action. Only the calls to
+// remote services have a result.
+func (m *Module) executeWorkflowAction(ctx context.Context, token string, step *config.WorkflowStep, action *config.WorkflowAction, state map[string]interface{}) (interface{}, error) {
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = defaultWorkflowStepTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	if action.DB == nil {
+		m.lock.RLock()
+		functions := m.functions
+		m.lock.RUnlock()
+		if functions == nil {
+			return nil, errors.New("functions module has not been initialised")
+		}
+
+		status, result, err := functions.CallWithContext(ctx, action.Service, action.Endpoint, token, model.RequestParams{}, &model.FunctionsRequest{Params: state, Timeout: timeout})
+		if err != nil {
+			return nil, err
+		}
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return nil, fmt.Errorf("service (%s) responded with status (%d)", action.Service, status)
+		}
+		return result, nil
+	}
+
+	op := action.DB
+	args := map[string]interface{}{"args": state}
+	switch op.Op {
+	case string(model.Create):
+		doc, err := resolveWorkflowValue(op.Doc, args)
+		if err != nil {
+			return nil, err
+		}
+		return nil, m.crud.InternalCreate(ctx, op.DBAlias, m.project, op.Col, &model.CreateRequest{Document: doc, Operation: utils.One}, false)
+
+	case string(model.Update):
+		find, err := resolveWorkflowValue(op.Find, args)
+		if err != nil {
+			return nil, err
+		}
+		update, err := resolveWorkflowValue(op.Update, args)
+		if err != nil {
+			return nil, err
+		}
+		return nil, m.crud.InternalUpdate(ctx, op.DBAlias, m.project, op.Col, &model.UpdateRequest{Find: find.(map[string]interface{}), Update: update.(map[string]interface{}), Operation: utils.All})
+
+	default:
+		find, err := resolveWorkflowValue(op.Find, args)
+		if err != nil {
+			return nil, err
+		}
+		return nil, m.crud.InternalDelete(ctx, op.DBAlias, m.project, op.Col, &model.DeleteRequest{Find: find.(map[string]interface{}), Operation: utils.All})
+	}
+}
+
+// resolveWorkflowValue replaces the strings starting with `args.` in the value with the value at that path in args
+func resolveWorkflowValue(value interface{}, args map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.HasPrefix(v, "args.") {
+			return v, nil
+		}
+		return utils.LoadValue(v, args)
+
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			newItem, err := resolveWorkflowValue(item, args)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = newItem
+		}
+		return resolved, nil
+
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			newItem, err := resolveWorkflowValue(item, args)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = newItem
+		}
+		return resolved, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func marshalWorkflowState(state map[string]interface{}) string {
+	data, _ := json.Marshal(state)
+	return string(data)
+}
+
+func workflowRunDoc(run *workflowRun) map[string]interface{} {
+	return map[string]interface{}{
+		"_id":        run.ID,
+		"workflow":   run.Workflow,
+		"token":      run.Token,
+		"status":     run.Status,
+		"step":       run.Step,
+		"state":      run.State,
+		"error":      run.Error,
+		"started_at": run.StartedAt,
+		"updated_at": run.UpdatedAt,
+	}
+}
+
+// saveWorkflowRun persists the progress of a run. An error is only logged since the run is resumed from the last
+// persisted step, which means a step can get executed more than once.
+func (m *Module) saveWorkflowRun(ctx context.Context, dbAlias string, run *workflowRun) {
+	run.UpdatedAt = time.Now().Format(time.RFC3339Nano)
+	updateRequest := &model.UpdateRequest{
+		Find:      map[string]interface{}{"_id": run.ID},
+		Operation: utils.All,
+		Update: map[string]interface{}{
+			"$set": map[string]interface{}{"status": run.Status, "step": run.Step, "state": run.State, "error": run.Error, "updated_at": run.UpdatedAt},
+		},
+	}
+	if err := m.crud.InternalUpdate(ctx, dbAlias, m.project, utils.TableWorkflowRuns, updateRequest); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to persist run (%s) of workflow (%s)", run.ID, run.Workflow), err, nil)
+	}
+}
+
+func (m *Module) readWorkflowRuns(ctx context.Context, dbAlias string, find map[string]interface{}) ([]*workflowRun, error) {
+	attr := map[string]string{"project": m.project, "db": dbAlias, "col": utils.TableWorkflowRuns}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	results, _, err := m.crud.Read(ctx, dbAlias, utils.TableWorkflowRuns, &model.ReadRequest{Operation: utils.All, Find: find, Options: &model.ReadOptions{Limit: &limit}}, reqParams)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ := results.([]interface{})
+	runs := make([]*workflowRun, 0, len(rows))
+	for _, row := range rows {
+		run := new(workflowRun)
+		if err := mapstructure.WeakDecode(row, run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// resumeWorkflowRuns resumes the unfinished runs assigned to this worker which haven't been updated for a while. Such
+// runs were being executed by a worker which went down or whose tokens have been reassigned.
+func (m *Module) resumeWorkflowRuns(t *time.Time) {
+	// Return if module is not enabled
+	if !m.IsEnabled() {
+		return
+	}
+
+	m.lock.RLock()
+	dbAlias, hasWorkflows := m.config.DBAlias, len(m.workflows) > 0
+	m.lock.RUnlock()
+	if !hasWorkflows {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start, end := m.syncMan.GetAssignedTokens()
+	find := map[string]interface{}{
+		"status":     map[string]interface{}{"$in": []interface{}{workflowStatusRunning, workflowStatusCompensating}},
+		"token":      map[string]interface{}{"$gte": start, "$lte": end},
+		"updated_at": map[string]interface{}{"$lt": t.Add(-workflowResumeAfter).Format(time.RFC3339Nano)},
+	}
+	runs, err := m.readWorkflowRuns(ctx, dbAlias, find)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Eventing workflow routine could not read unfinished runs", err, nil)
+		return
+	}
+
+	for _, run := range runs {
+		go m.runWorkflow(run)
+	}
+}
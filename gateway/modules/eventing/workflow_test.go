@@ -0,0 +1,144 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type fakeWorkflowFunctions struct {
+	calls  []string
+	failed map[string]bool
+}
+
+func (f *fakeWorkflowFunctions) CallWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest) (int, interface{}, error) {
+	f.calls = append(f.calls, service+"/"+function)
+	if f.failed[service+"/"+function] {
+		return http.StatusInternalServerError, nil, nil
+	}
+	return http.StatusOK, map[string]interface{}{"id": function}, nil
+}
+
+func Test_resolveWorkflowValue(t *testing.T) {
+	args := map[string]interface{}{"args": map[string]interface{}{
+		"event": map[string]interface{}{"doc": map[string]interface{}{"id": "order1", "amount": float64(10)}},
+		"steps": map[string]interface{}{"reserve": map[string]interface{}{"id": "reservation1"}},
+	}}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "plain string", value: "pending", want: "pending"},
+		{
+			name:  "nested values",
+			value: map[string]interface{}{"order": "args.event.doc.id", "items": []interface{}{"args.steps.reserve.id", float64(1)}, "status": "reserved"},
+			want:  map[string]interface{}{"order": "order1", "items": []interface{}{"reservation1", float64(1)}, "status": "reserved"},
+		},
+		{name: "missing value", value: map[string]interface{}{"id": "args.steps.charge.id"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWorkflowValue(tt.value, args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveWorkflowValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveWorkflowValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_runWorkflow(t *testing.T) {
+	workflow := &config.EventingWorkflow{
+		ID:      "place_order",
+		Trigger: "order_placed",
+		Steps: []*config.WorkflowStep{
+			{
+				ID:         "reserve",
+				Action:     &config.WorkflowAction{Service: "inventory", Endpoint: "reserve"},
+				Compensate: &config.WorkflowAction{Service: "inventory", Endpoint: "release"},
+			},
+			{
+				ID:     "notify",
+				Action: &config.WorkflowAction{Service: "mailer", Endpoint: "notify"},
+			},
+			{
+				ID:         "charge",
+				Action:     &config.WorkflowAction{Service: "payments", Endpoint: "charge"},
+				Compensate: &config.WorkflowAction{Service: "payments", Endpoint: "refund"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		failed     map[string]bool
+		wantCalls  []string
+		wantStatus string
+	}{
+		{
+			name:       "all steps succeed",
+			wantCalls:  []string{"inventory/reserve", "mailer/notify", "payments/charge"},
+			wantStatus: workflowStatusCompleted,
+		},
+		{
+			name:       "completed steps are compensated in reverse order",
+			failed:     map[string]bool{"payments/charge": true},
+			wantCalls:  []string{"inventory/reserve", "mailer/notify", "payments/charge", "inventory/release"},
+			wantStatus: workflowStatusCompensated,
+		},
+		{
+			name:       "compensation fails",
+			failed:     map[string]bool{"payments/charge": true, "inventory/release": true},
+			wantCalls:  []string{"inventory/reserve", "mailer/notify", "payments/charge", "inventory/release"},
+			wantStatus: workflowStatusFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crud := &mockCrudInterface{}
+			crud.On("InternalUpdate", mock.Anything, "db", "project", utils.TableWorkflowRuns, mock.Anything).Return(nil)
+			auth := &mockAuthEventingInterface{}
+			auth.On("GetInternalAccessToken").Return("token", nil)
+			functions := &fakeWorkflowFunctions{failed: tt.failed}
+
+			m := &Module{
+				project:   "project",
+				config:    &config.Eventing{DBAlias: "db"},
+				crud:      crud,
+				auth:      auth,
+				functions: functions,
+				workflows: map[string]*config.EventingWorkflow{workflow.ID: workflow},
+			}
+
+			run := &workflowRun{ID: "1", Workflow: workflow.ID, Status: workflowStatusRunning, State: `{"event":{"id":"order1"},"steps":{}}`}
+			m.runWorkflow(run)
+
+			if !reflect.DeepEqual(functions.calls, tt.wantCalls) {
+				t.Errorf("runWorkflow() calls = %v, want %v", functions.calls, tt.wantCalls)
+			}
+			if run.Status != tt.wantStatus {
+				t.Errorf("runWorkflow() status = %v, want %v", run.Status, tt.wantStatus)
+			}
+
+			var state map[string]interface{}
+			_ = json.Unmarshal([]byte(run.State), &state)
+			if got := state["steps"].(map[string]interface{})["reserve"]; !reflect.DeepEqual(got, map[string]interface{}{"id": "reserve"}) {
+				t.Errorf("runWorkflow() result of step reserve = %v", got)
+			}
+			crud.AssertExpectations(t)
+		})
+	}
+}
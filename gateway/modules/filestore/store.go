@@ -13,6 +13,7 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 
 	"github.com/spaceuptech/space-cloud/gateway/modules/filestore/amazons3"
@@ -31,6 +32,8 @@ type Module struct {
 
 	// function to get secrets from runner
 	getSecrets utils.GetSecrets
+	// function to resolve secrets from external secret managers
+	resolveSecret utils.ResolveSecret
 }
 
 // Init creates a new instance of the file store object
@@ -93,6 +96,14 @@ func (m *Module) SetConfig(project string, conf *config.FileStoreConfig) error {
 		if err := setFileSecret(utils.FileStoreType(conf.StoreType), secretKey, value); err != nil {
 			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to create credential file in gateway", err, nil)
 		}
+	} else if secrets.IsReference(conf.Secret) && m.resolveSecret != nil {
+		value, err := m.resolveSecret(context.TODO(), conf.Secret)
+		if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to resolve file store secret", err, nil)
+		}
+		if err := setFileSecret(utils.FileStoreType(conf.StoreType), "", value); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to create credential file in gateway", err, nil)
+		}
 	}
 
 	// Create a new crud blocks
@@ -154,6 +165,11 @@ func (m *Module) IsEnabled() bool {
 	return m.enabled
 }
 
+// NewStore connects to the store described by the config. It lets other modules keep files in the stores supported by the file store module.
+func NewStore(conf *config.FileStoreConfig) (FileStore, error) {
+	return initBlock(conf)
+}
+
 func initBlock(conf *config.FileStoreConfig) (FileStore, error) {
 	switch utils.FileStoreType(conf.StoreType) {
 	case utils.Local:
@@ -174,3 +190,11 @@ func (m *Module) SetGetSecrets(function utils.GetSecrets) {
 
 	m.getSecrets = function
 }
+
+// SetResolveSecret sets the function to resolve secrets from external secret managers
+func (m *Module) SetResolveSecret(function utils.ResolveSecret) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.resolveSecret = function
+}
@@ -0,0 +1,253 @@
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// loadOnStartTimeout is the maximum time the fixtures loaded on start may take
+const loadOnStartTimeout = time.Minute
+
+// ErrTestModeDisabled is returned when the fixtures are used on a gateway which isn't running in test mode
+var ErrTestModeDisabled = errors.New("fixtures can only be used on gateways running in test mode")
+
+// Module loads the seed data of a project into its collections and file storage and removes it again on teardown.
+// It refuses to touch any data unless the gateway runs in test mode.
+type Module struct {
+	lock sync.Mutex
+
+	project  string
+	testMode bool
+	config   *config.FixturesConfig
+
+	// loadedOnStart is set once the fixtures have been loaded on start, so that the later config updates don't
+	// load them again
+	loadedOnStart bool
+
+	crud     crudInterface
+	file     fileInterface
+	getToken func() (string, error)
+}
+
+// New creates a new instance of the fixtures module. The token is used to upload and delete the files of the fixtures
+// bypassing the file storage rules.
+func New(project string, testMode bool, crud crudInterface, file fileInterface, getToken func() (string, error)) *Module {
+	return &Module{project: project, testMode: testMode, crud: crud, file: file, getToken: getToken}
+}
+
+// IsTestMode returns true if the gateway runs in test mode
+func (m *Module) IsTestMode() bool {
+	return m.testMode
+}
+
+// SetConfig sets the fixtures of the project. The fixtures marked to be loaded on start get loaded the first time
+// the config is set on a gateway running in test mode.
+func (m *Module) SetConfig(ctx context.Context, c *config.FixturesConfig) error {
+	if err := validateConfig(c); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = c
+	if !m.testMode || c == nil || !c.Enabled || !c.LoadOnStart || m.loadedOnStart {
+		return nil
+	}
+	m.loadedOnStart = true
+
+	ctx, cancel := context.WithTimeout(ctx, loadOnStartTimeout)
+	defer cancel()
+	result, err := m.load(ctx)
+	if err != nil {
+		return err
+	}
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "Loaded fixtures of project", map[string]interface{}{"project": m.project, "collections": len(result.Collections), "files": result.Files})
+	return nil
+}
+
+// Load loads the fixtures into the collections and the file storage of the project
+func (m *Module) Load(ctx context.Context) (*model.FixturesResult, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m.checkEnabled(); err != nil {
+		return nil, err
+	}
+	return m.load(ctx)
+}
+
+// Teardown removes the documents and the files of the fixtures. Truncated collections lose all their documents.
+func (m *Module) Teardown(ctx context.Context) (*model.FixturesResult, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	result := &model.FixturesResult{Collections: make([]*model.CollectionFixtureResult, 0, len(m.config.Collections))}
+	for _, c := range m.config.Collections {
+		if err := m.clearCollection(ctx, c); err != nil {
+			return nil, err
+		}
+		result.Collections = append(result.Collections, &model.CollectionFixtureResult{DbAlias: c.DbAlias, Col: c.Col, Count: len(c.Docs)})
+	}
+
+	if len(m.config.Files) == 0 {
+		return result, nil
+	}
+	token, err := m.getToken()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range m.config.Files {
+		if _, err := m.file.DeleteFile(ctx, m.project, token, getFilePath(f), nil); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete file (%s) of fixtures", getFilePath(f)), err, nil)
+		}
+		result.Files++
+	}
+	return result, nil
+}
+
+func (m *Module) checkEnabled() error {
+	if !m.testMode {
+		return ErrTestModeDisabled
+	}
+	if m.config == nil || !m.config.Enabled {
+		return fmt.Errorf("fixtures of project (%s) aren't enabled", m.project)
+	}
+	return nil
+}
+
+// load must be called with the lock held
+func (m *Module) load(ctx context.Context) (*model.FixturesResult, error) {
+	result := &model.FixturesResult{Collections: make([]*model.CollectionFixtureResult, 0, len(m.config.Collections))}
+	for _, c := range m.config.Collections {
+		if c.Truncate {
+			if err := m.clearCollection(ctx, c); err != nil {
+				return nil, err
+			}
+		}
+		if len(c.Docs) > 0 {
+			docs := make([]interface{}, len(c.Docs))
+			for i, doc := range c.Docs {
+				docs[i] = copyDoc(doc)
+			}
+			if err := m.crud.Create(ctx, c.DbAlias, c.Col, &model.CreateRequest{Document: docs, Operation: utils.All}, model.RequestParams{}); err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to load fixtures of collection (%s)", c.Col), err, nil)
+			}
+		}
+		result.Collections = append(result.Collections, &model.CollectionFixtureResult{DbAlias: c.DbAlias, Col: c.Col, Count: len(c.Docs)})
+	}
+
+	if len(m.config.Files) == 0 {
+		return result, nil
+	}
+	token, err := m.getToken()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range m.config.Files {
+		content := []byte(f.Content)
+		if f.Base64 {
+			if content, err = base64.StdEncoding.DecodeString(f.Content); err != nil {
+				return nil, fmt.Errorf("content of file (%s) of fixtures isn't valid base64 - %v", getFilePath(f), err)
+			}
+		}
+		req := &model.CreateFileRequest{Path: f.Path, Name: f.Name, Type: "file", MakeAll: true}
+		if _, err := m.file.UploadFile(ctx, m.project, token, req, bytes.NewReader(content)); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to upload file (%s) of fixtures", getFilePath(f)), err, nil)
+		}
+		result.Files++
+	}
+	return result, nil
+}
+
+// clearCollection deletes the documents of the fixtures from the collection or all of them if it gets truncated
+func (m *Module) clearCollection(ctx context.Context, c *config.CollectionFixture) error {
+	find := map[string]interface{}{}
+	if !c.Truncate {
+		if len(c.Docs) == 0 {
+			return nil
+		}
+		key, err := m.getKey(c)
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, 0, len(c.Docs))
+		for _, doc := range c.Docs {
+			values = append(values, doc[key])
+		}
+		find[key] = map[string]interface{}{"$in": values}
+	}
+
+	if err := m.crud.Delete(ctx, c.DbAlias, c.Col, &model.DeleteRequest{Find: find, Operation: utils.All}, model.RequestParams{}); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete fixtures of collection (%s)", c.Col), err, nil)
+	}
+	return nil
+}
+
+func (m *Module) getKey(c *config.CollectionFixture) (string, error) {
+	if c.Key != "" {
+		return c.Key, nil
+	}
+	dbType, err := m.crud.GetDBType(c.DbAlias)
+	if err != nil {
+		return "", err
+	}
+	if dbType == string(model.Mongo) || dbType == string(model.EmbeddedDB) || dbType == string(model.Redis) {
+		return "_id", nil
+	}
+	return "id", nil
+}
+
+func validateConfig(c *config.FixturesConfig) error {
+	if c == nil {
+		return nil
+	}
+	for _, col := range c.Collections {
+		if col.DbAlias == "" || col.Col == "" {
+			return errors.New("db alias and collection of fixtures cannot be empty")
+		}
+		if col.Truncate || col.Key == "" {
+			continue
+		}
+		for i, doc := range col.Docs {
+			if _, p := doc[col.Key]; !p {
+				return fmt.Errorf("document (%d) of fixtures of collection (%s) doesn't have the key (%s)", i, col.Col, col.Key)
+			}
+		}
+	}
+	for _, f := range c.Files {
+		if f.Name == "" {
+			return errors.New("name of file of fixtures cannot be empty")
+		}
+	}
+	return nil
+}
+
+func getFilePath(f *config.FileFixture) string {
+	return strings.TrimRight(f.Path, "/") + "/" + f.Name
+}
+
+// copyDoc makes a shallow copy of the document since the databases may add fields like _id to it
+func copyDoc(doc map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		c[k] = v
+	}
+	return c
+}
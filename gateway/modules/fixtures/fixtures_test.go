@@ -0,0 +1,209 @@
+package fixtures
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type fakeCrud struct {
+	dbType  string
+	created map[string][]interface{} // The key here is the collection
+	deleted map[string][]map[string]interface{}
+}
+
+func (c *fakeCrud) Create(_ context.Context, _, col string, req *model.CreateRequest, _ model.RequestParams) error {
+	c.created[col] = append(c.created[col], req.Document.([]interface{})...)
+	return nil
+}
+
+func (c *fakeCrud) Delete(_ context.Context, _, col string, req *model.DeleteRequest, _ model.RequestParams) error {
+	c.deleted[col] = append(c.deleted[col], req.Find)
+	return nil
+}
+
+func (c *fakeCrud) GetDBType(_ string) (string, error) {
+	return c.dbType, nil
+}
+
+type fakeFile struct {
+	uploaded map[string]string // The key here is the path of the file
+	deleted  []string
+}
+
+func (f *fakeFile) UploadFile(_ context.Context, _, _ string, req *model.CreateFileRequest, reader io.Reader) (int, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return 500, err
+	}
+	f.uploaded[req.Path+"/"+req.Name] = string(data)
+	return 200, nil
+}
+
+func (f *fakeFile) DeleteFile(_ context.Context, _, _ string, path string, _ map[string]interface{}) (int, error) {
+	f.deleted = append(f.deleted, path)
+	return 200, nil
+}
+
+func newFakes(dbType string) (*fakeCrud, *fakeFile) {
+	return &fakeCrud{dbType: dbType, created: map[string][]interface{}{}, deleted: map[string][]map[string]interface{}{}},
+		&fakeFile{uploaded: map[string]string{}}
+}
+
+func getToken() (string, error) { return "token", nil }
+
+func testConfig(loadOnStart bool) *config.FixturesConfig {
+	return &config.FixturesConfig{
+		Enabled:     true,
+		LoadOnStart: loadOnStart,
+		Collections: []*config.CollectionFixture{
+			{DbAlias: "db", Col: "users", Docs: []map[string]interface{}{{"id": "1", "name": "alice"}, {"id": "2", "name": "bob"}}},
+			{DbAlias: "db", Col: "posts", Truncate: true, Docs: []map[string]interface{}{{"id": "1", "title": "hello"}}},
+		},
+		Files: []*config.FileFixture{
+			{Path: "/avatars", Name: "alice.txt", Content: "alice"},
+			{Path: "/avatars/", Name: "bob.txt", Content: "Ym9i", Base64: true},
+		},
+	}
+}
+
+func TestModule_LoadAndTeardown(t *testing.T) {
+	c, f := newFakes(string(model.Postgres))
+	m := New("myproject", true, c, f, getToken)
+	if err := m.SetConfig(context.Background(), testConfig(false)); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if len(c.created) != 0 {
+		t.Fatalf("SetConfig() loaded the fixtures which aren't marked to be loaded on start")
+	}
+
+	result, err := m.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	wantResult := &model.FixturesResult{
+		Collections: []*model.CollectionFixtureResult{{DbAlias: "db", Col: "users", Count: 2}, {DbAlias: "db", Col: "posts", Count: 1}},
+		Files:       2,
+	}
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("Load() got = %v, want %v", result, wantResult)
+	}
+	if len(c.created["users"]) != 2 || len(c.created["posts"]) != 1 {
+		t.Errorf("Load() created = %v", c.created)
+	}
+	// Only the truncated collection gets cleared before loading
+	wantDeleted := map[string][]map[string]interface{}{"posts": {{}}}
+	if !reflect.DeepEqual(c.deleted, wantDeleted) {
+		t.Errorf("Load() deleted = %v, want %v", c.deleted, wantDeleted)
+	}
+	wantUploaded := map[string]string{"/avatars/alice.txt": "alice", "/avatars//bob.txt": "bob"}
+	if !reflect.DeepEqual(f.uploaded, wantUploaded) {
+		t.Errorf("Load() uploaded = %v, want %v", f.uploaded, wantUploaded)
+	}
+
+	c.deleted = map[string][]map[string]interface{}{}
+	if _, err := m.Teardown(context.Background()); err != nil {
+		t.Fatalf("Teardown() error = %v", err)
+	}
+	wantDeleted = map[string][]map[string]interface{}{
+		"users": {{"id": map[string]interface{}{"$in": []interface{}{"1", "2"}}}},
+		"posts": {{}},
+	}
+	if !reflect.DeepEqual(c.deleted, wantDeleted) {
+		t.Errorf("Teardown() deleted = %v, want %v", c.deleted, wantDeleted)
+	}
+	if wantFiles := []string{"/avatars/alice.txt", "/avatars/bob.txt"}; !reflect.DeepEqual(f.deleted, wantFiles) {
+		t.Errorf("Teardown() deleted files = %v, want %v", f.deleted, wantFiles)
+	}
+}
+
+func TestModule_SetConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		testMode    bool
+		dbType      string
+		loadOnStart bool
+		wantCreated int
+		wantErr     bool
+	}{
+		{name: "fixtures are loaded on start in test mode", testMode: true, dbType: string(model.Mongo), loadOnStart: true, wantCreated: 2},
+		{name: "fixtures are not loaded on start outside test mode", dbType: string(model.Mongo), loadOnStart: true},
+		{name: "fixtures are not loaded unless marked to be loaded on start", testMode: true, dbType: string(model.Mongo)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, f := newFakes(tt.dbType)
+			m := New("myproject", tt.testMode, c, f, getToken)
+
+			// The fixtures are loaded on start only once, no matter how often the config gets updated
+			for i := 0; i < 2; i++ {
+				if err := m.SetConfig(context.Background(), testConfig(tt.loadOnStart)); (err != nil) != tt.wantErr {
+					t.Fatalf("SetConfig() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+			if got := len(c.created["users"]); got != tt.wantCreated {
+				t.Errorf("SetConfig() created %d users, want %d", got, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestModule_TestModeDisabled(t *testing.T) {
+	c, f := newFakes(string(model.Mongo))
+	m := New("myproject", false, c, f, getToken)
+	if err := m.SetConfig(context.Background(), testConfig(false)); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	if _, err := m.Load(context.Background()); err != ErrTestModeDisabled {
+		t.Errorf("Load() error = %v, want %v", err, ErrTestModeDisabled)
+	}
+	if _, err := m.Teardown(context.Background()); err != ErrTestModeDisabled {
+		t.Errorf("Teardown() error = %v, want %v", err, ErrTestModeDisabled)
+	}
+	if len(c.created) != 0 || len(c.deleted) != 0 || len(f.uploaded) != 0 || len(f.deleted) != 0 {
+		t.Errorf("fixtures touched the data of a gateway which isn't running in test mode")
+	}
+}
+
+func Test_validateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *config.FixturesConfig
+		wantErr bool
+	}{
+		{name: "no fixtures", c: nil},
+		{name: "valid fixtures", c: testConfig(true)},
+		{
+			name:    "collection without a db alias",
+			c:       &config.FixturesConfig{Collections: []*config.CollectionFixture{{Col: "users"}}},
+			wantErr: true,
+		},
+		{
+			name:    "document without the key",
+			c:       &config.FixturesConfig{Collections: []*config.CollectionFixture{{DbAlias: "db", Col: "users", Key: "email", Docs: []map[string]interface{}{{"id": "1"}}}}},
+			wantErr: true,
+		},
+		{
+			name: "documents of truncated collections need no key",
+			c:    &config.FixturesConfig{Collections: []*config.CollectionFixture{{DbAlias: "db", Col: "users", Key: "email", Truncate: true, Docs: []map[string]interface{}{{"id": "1"}}}}},
+		},
+		{
+			name:    "file without a name",
+			c:       &config.FixturesConfig{Files: []*config.FileFixture{{Path: "/avatars"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateConfig(tt.c); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
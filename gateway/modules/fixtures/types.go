@@ -0,0 +1,19 @@
+package fixtures
+
+import (
+	"context"
+	"io"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type crudInterface interface {
+	Create(ctx context.Context, dbAlias, col string, req *model.CreateRequest, params model.RequestParams) error
+	Delete(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) error
+	GetDBType(dbAlias string) (string, error)
+}
+
+type fileInterface interface {
+	UploadFile(ctx context.Context, project, token string, req *model.CreateFileRequest, reader io.Reader) (int, error)
+	DeleteFile(ctx context.Context, project, token string, path string, meta map[string]interface{}) (int, error)
+}
@@ -0,0 +1,280 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// The operators of the targets of a flag
+const (
+	opEqual    = "=="
+	opNotEqual = "!="
+	opIn       = "in"
+	opNotIn    = "notIn"
+	opExists   = "exists"
+)
+
+// Module evaluates the feature flags of a project and pushes their values to the subscribed clients whenever the
+// flags change
+type Module struct {
+	lock sync.RWMutex
+
+	auth        authInterface
+	flags       map[string]*config.FeatureFlag // Key here is the flag id
+	subscribers map[string]*subscriber         // Key here is the client id
+}
+
+// subscriber is a client receiving the values of the flags evaluated against its claims
+type subscriber struct {
+	claims map[string]interface{}
+	values map[string]bool
+	send   func(values map[string]bool)
+}
+
+// New creates a new instance of the feature flags module
+func New(auth authInterface) *Module {
+	return &Module{auth: auth, flags: map[string]*config.FeatureFlag{}, subscribers: map[string]*subscriber{}}
+}
+
+// SetConfig sets the feature flags of the project. The subscribers whose flags have changed get the new values.
+func (m *Module) SetConfig(featureFlags config.FeatureFlags) error {
+	flags := make(map[string]*config.FeatureFlag, len(featureFlags))
+	for _, flag := range featureFlags {
+		if err := validateFlag(flag); err != nil {
+			return err
+		}
+		flags[flag.ID] = flag
+	}
+
+	m.lock.Lock()
+	m.flags = flags
+	updates := m.evaluateSubscribers()
+	m.lock.Unlock()
+
+	// Values are sent without the lock so that a slow client doesn't hold up the rest
+	for _, update := range updates {
+		update()
+	}
+	return nil
+}
+
+// CloseConfig removes the flags and the subscribers
+func (m *Module) CloseConfig() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.flags = map[string]*config.FeatureFlag{}
+	m.subscribers = map[string]*subscriber{}
+	return nil
+}
+
+// Evaluate returns whether a flag is on for the caller having the provided claims
+func (m *Module) Evaluate(id string, claims map[string]interface{}) (bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	flag, ok := m.flags[id]
+	if !ok {
+		return false, fmt.Errorf("feature flag (%s) does not exist", id)
+	}
+	return evaluate(flag, claims), nil
+}
+
+// EvaluateAll returns the values of all the flags for the caller having the provided claims
+func (m *Module) EvaluateAll(claims map[string]interface{}) map[string]bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.evaluateAll(claims)
+}
+
+// GetFlags returns the values of all the flags for the caller having the provided token
+func (m *Module) GetFlags(ctx context.Context, token string) (map[string]bool, error) {
+	claims, err := m.parseToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return m.EvaluateAll(claims), nil
+}
+
+// Subscribe registers a client to receive the values of the flags whenever they change. The current values are
+// returned.
+func (m *Module) Subscribe(ctx context.Context, clientID, token string, send func(values map[string]bool)) (map[string]bool, error) {
+	claims, err := m.parseToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	values := m.evaluateAll(claims)
+	m.subscribers[clientID] = &subscriber{claims: claims, values: values, send: send}
+	return values, nil
+}
+
+// Unsubscribe stops sending the values of the flags to a client
+func (m *Module) Unsubscribe(clientID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.subscribers, clientID)
+}
+
+// parseToken returns the claims of the token. Callers without a token are evaluated with no claims.
+func (m *Module) parseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	if token == "" {
+		return map[string]interface{}{}, nil
+	}
+	return m.auth.ParseToken(ctx, token)
+}
+
+// evaluateSubscribers re-evaluates the flags of every subscriber and returns the functions sending the values which
+// have changed. It must be called with the lock acquired.
+func (m *Module) evaluateSubscribers() []func() {
+	updates := make([]func(), 0)
+	for _, s := range m.subscribers {
+		values := m.evaluateAll(s.claims)
+		if reflect.DeepEqual(values, s.values) {
+			continue
+		}
+		s.values = values
+		send := s.send
+		updates = append(updates, func() { send(values) })
+	}
+	return updates
+}
+
+func (m *Module) evaluateAll(claims map[string]interface{}) map[string]bool {
+	values := make(map[string]bool, len(m.flags))
+	for id, flag := range m.flags {
+		values[id] = evaluate(flag, claims)
+	}
+	return values
+}
+
+// evaluate returns whether a flag is on for the caller having the provided claims
+func evaluate(flag *config.FeatureFlag, claims map[string]interface{}) bool {
+	if !flag.Enabled {
+		return false
+	}
+
+	for _, target := range flag.Targets {
+		if matchTarget(target, claims) {
+			return true
+		}
+	}
+
+	rollout := 100.0
+	if len(flag.Targets) > 0 {
+		rollout = 0
+	}
+	if flag.Rollout != nil {
+		rollout = *flag.Rollout
+	}
+	switch {
+	case rollout >= 100:
+		return true
+	case rollout <= 0:
+		return false
+	}
+	return getBucket(flag.ID, claims["id"]) < rollout
+}
+
+// getBucket places a caller in one of the buckets from 0 to 100. Flags are bucketed independently so that the same
+// callers don't end up with all the flags being rolled out.
+func getBucket(flagID string, callerID interface{}) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprintf("%s:%v", flagID, callerID)))
+	return float64(h.Sum32()%10000) / 100
+}
+
+func matchTarget(target *config.FeatureFlagTarget, claims map[string]interface{}) bool {
+	value, ok := getClaim(claims, target.Claim)
+
+	switch target.Op {
+	case opExists:
+		return ok
+	case opNotEqual:
+		return !ok || !isEqual(value, target.Value)
+	case opIn:
+		return ok && isIn(value, target.Value)
+	case opNotIn:
+		return !ok || !isIn(value, target.Value)
+	default:
+		return ok && isEqual(value, target.Value)
+	}
+}
+
+// getClaim returns the value of a claim. Nested claims are separated by dots.
+func getClaim(claims map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = claims
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// isEqual compares the values by their string representation, since the claims and the config are decoded
+// separately and might not carry the same numeric types
+func isEqual(a, b interface{}) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// isIn checks if the value is one of the elements of the list. A claim which is an array matches if any of its
+// elements is in the list.
+func isIn(value, list interface{}) bool {
+	elements, ok := list.([]interface{})
+	if !ok {
+		return false
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+	for _, v := range values {
+		for _, e := range elements {
+			if isEqual(v, e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateFlag(flag *config.FeatureFlag) error {
+	if flag.ID == "" {
+		return fmt.Errorf("id of feature flag not provided")
+	}
+	if flag.Rollout != nil && (*flag.Rollout < 0 || *flag.Rollout > 100) {
+		return fmt.Errorf("rollout of feature flag (%s) must be between 0 and 100", flag.ID)
+	}
+	for _, target := range flag.Targets {
+		if target.Claim == "" {
+			return fmt.Errorf("claim not provided in a target of feature flag (%s)", flag.ID)
+		}
+		switch target.Op {
+		case "", opEqual, opNotEqual, opExists:
+		case opIn, opNotIn:
+			if _, ok := target.Value.([]interface{}); !ok {
+				return fmt.Errorf("value of the %s target on claim (%s) of feature flag (%s) must be an array", target.Op, target.Claim, flag.ID)
+			}
+		default:
+			return fmt.Errorf("invalid operator (%s) provided in a target of feature flag (%s)", target.Op, flag.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+type mockAuth struct{}
+
+// ParseToken returns the token as the role of the caller
+func (mockAuth) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	if token == "invalid" {
+		return nil, errors.New("invalid token")
+	}
+	return map[string]interface{}{"role": token}, nil
+}
+
+func rollout(v float64) *float64 {
+	return &v
+}
+
+func TestModule_Evaluate(t *testing.T) {
+	m := New(mockAuth{})
+	err := m.SetConfig(config.FeatureFlags{
+		"off":      {ID: "off", Targets: []*config.FeatureFlagTarget{{Claim: "role", Value: "admin"}}},
+		"on":       {ID: "on", Enabled: true},
+		"none":     {ID: "none", Enabled: true, Rollout: rollout(0)},
+		"admins":   {ID: "admins", Enabled: true, Targets: []*config.FeatureFlagTarget{{Claim: "role", Op: "==", Value: "admin"}}},
+		"beta":     {ID: "beta", Enabled: true, Targets: []*config.FeatureFlagTarget{{Claim: "org.plan", Op: "in", Value: []interface{}{"pro", "enterprise"}}}},
+		"groups":   {ID: "groups", Enabled: true, Targets: []*config.FeatureFlagTarget{{Claim: "groups", Op: "in", Value: []interface{}{"testers"}}}},
+		"verified": {ID: "verified", Enabled: true, Targets: []*config.FeatureFlagTarget{{Claim: "email_verified", Op: "exists"}}},
+		"numeric":  {ID: "numeric", Enabled: true, Targets: []*config.FeatureFlagTarget{{Claim: "level", Value: 3}}},
+	})
+	if err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		claims  map[string]interface{}
+		want    bool
+		wantErr bool
+	}{
+		{name: "disabled flag", id: "off", claims: map[string]interface{}{"role": "admin"}, want: false},
+		{name: "enabled flag", id: "on", claims: map[string]interface{}{}, want: true},
+		{name: "zero rollout", id: "none", claims: map[string]interface{}{"id": "1"}, want: false},
+		{name: "target matched", id: "admins", claims: map[string]interface{}{"role": "admin"}, want: true},
+		{name: "target not matched", id: "admins", claims: map[string]interface{}{"role": "user"}, want: false},
+		{name: "nested claim in list", id: "beta", claims: map[string]interface{}{"org": map[string]interface{}{"plan": "pro"}}, want: true},
+		{name: "nested claim not in list", id: "beta", claims: map[string]interface{}{"org": map[string]interface{}{"plan": "free"}}, want: false},
+		{name: "array claim in list", id: "groups", claims: map[string]interface{}{"groups": []interface{}{"devs", "testers"}}, want: true},
+		{name: "claim exists", id: "verified", claims: map[string]interface{}{"email_verified": false}, want: true},
+		{name: "claim missing", id: "verified", claims: map[string]interface{}{}, want: false},
+		{name: "numeric claim", id: "numeric", claims: map[string]interface{}{"level": float64(3)}, want: true},
+		{name: "unknown flag", id: "unknown", claims: map[string]interface{}{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Evaluate(tt.id, tt.claims)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_EvaluateRollout(t *testing.T) {
+	m := New(mockAuth{})
+	if err := m.SetConfig(config.FeatureFlags{"half": {ID: "half", Enabled: true, Rollout: rollout(50)}}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	on := 0
+	for i := 0; i < 1000; i++ {
+		claims := map[string]interface{}{"id": i}
+		first, _ := m.Evaluate("half", claims)
+		second, _ := m.Evaluate("half", claims)
+		if first != second {
+			t.Fatalf("Evaluate() isn't sticky for caller %d", i)
+		}
+		if first {
+			on++
+		}
+	}
+	if on < 400 || on > 600 {
+		t.Errorf("Evaluate() turned on the flag for %d of 1000 callers, want about 500", on)
+	}
+}
+
+func TestModule_SetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   config.FeatureFlags
+		wantErr bool
+	}{
+		{name: "valid flags", flags: config.FeatureFlags{"a": {ID: "a", Rollout: rollout(10), Targets: []*config.FeatureFlagTarget{{Claim: "role", Op: "notIn", Value: []interface{}{"guest"}}}}}},
+		{name: "no id", flags: config.FeatureFlags{"a": {}}, wantErr: true},
+		{name: "invalid rollout", flags: config.FeatureFlags{"a": {ID: "a", Rollout: rollout(120)}}, wantErr: true},
+		{name: "no claim", flags: config.FeatureFlags{"a": {ID: "a", Targets: []*config.FeatureFlagTarget{{Value: "admin"}}}}, wantErr: true},
+		{name: "invalid operator", flags: config.FeatureFlags{"a": {ID: "a", Targets: []*config.FeatureFlagTarget{{Claim: "role", Op: ">"}}}}, wantErr: true},
+		{name: "in without an array", flags: config.FeatureFlags{"a": {ID: "a", Targets: []*config.FeatureFlagTarget{{Claim: "role", Op: "in", Value: "admin"}}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New(mockAuth{}).SetConfig(tt.flags); (err != nil) != tt.wantErr {
+				t.Errorf("SetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModule_Subscribe(t *testing.T) {
+	m := New(mockAuth{})
+	if err := m.SetConfig(config.FeatureFlags{"a": {ID: "a", Enabled: true}, "b": {ID: "b"}}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	var admin, user []map[string]bool
+	got, err := m.Subscribe(context.Background(), "1", "admin", func(values map[string]bool) { admin = append(admin, values) })
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if want := map[string]bool{"a": true, "b": false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscribe() = %v, want %v", got, want)
+	}
+	if _, err := m.Subscribe(context.Background(), "2", "user", func(values map[string]bool) { user = append(user, values) }); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := m.Subscribe(context.Background(), "3", "invalid", func(values map[string]bool) {}); err == nil {
+		t.Errorf("Subscribe() with an invalid token didn't return an error")
+	}
+
+	// Only the admin gets the values since the flag doesn't change for the user
+	if err := m.SetConfig(config.FeatureFlags{"a": {ID: "a", Enabled: true}, "b": {ID: "b", Enabled: true, Targets: []*config.FeatureFlagTarget{{Claim: "role", Value: "admin"}}}}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if want := []map[string]bool{{"a": true, "b": true}}; !reflect.DeepEqual(admin, want) {
+		t.Errorf("admin got %v, want %v", admin, want)
+	}
+	if len(user) != 0 {
+		t.Errorf("user got %v, want no values", user)
+	}
+
+	// Unsubscribed clients don't get any values
+	m.Unsubscribe("1")
+	if err := m.SetConfig(config.FeatureFlags{}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if len(admin) != 1 {
+		t.Errorf("admin got %v after unsubscribing", admin[1:])
+	}
+	if want := []map[string]bool{{}}; !reflect.DeepEqual(user, want) {
+		t.Errorf("user got %v, want %v", user, want)
+	}
+}
@@ -0,0 +1,7 @@
+package flags
+
+import "context"
+
+type authInterface interface {
+	ParseToken(ctx context.Context, token string) (map[string]interface{}, error)
+}
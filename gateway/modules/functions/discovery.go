@@ -0,0 +1,281 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const (
+	// discoveryCacheTTL is the duration for which the resolved instances of a service are reused
+	discoveryCacheTTL = 10 * time.Second
+
+	// unhealthyCooldown is the duration for which an instance which failed a request is skipped
+	unhealthyCooldown = 30 * time.Second
+)
+
+// serviceResolver resolves the urls of remote services which are consul service names (`consul://orders`) or dns
+// srv records (`srv://_orders._tcp.example.com`) to the address of one of their instances. Instances are picked in
+// a round robin fashion skipping the ones which failed recently. The url scheme can have a `+https` suffix, like
+// `consul+https://orders`, to call the instances over https.
+//
+// The instances in the zone of this node are preferred to cut the traffic across zones. The zone of a consul instance
+// is read from the `zone` key of the metadata of its service or else of its node. The instances in the other zones
+// are used only when none of the instances in the zone of this node are healthy.
+type serviceResolver struct {
+	lock      sync.Mutex
+	targets   map[string]*resolvedTargets // key is the url of the service
+	unhealthy map[string]time.Time        // key is the address of the instance
+
+	consulAddr  string
+	consulToken string
+	client      *http.Client
+	lookupSRV   func(ctx context.Context, name string) ([]*net.SRV, error)
+}
+
+type resolvedTargets struct {
+	addrs   []string
+	zones   map[string]string // key is the address of the instance
+	expires time.Time
+	next    int
+}
+
+func newServiceResolver() *serviceResolver {
+	consulAddr := os.Getenv("CONSUL_HTTP_ADDR")
+	if consulAddr == "" {
+		consulAddr = "http://127.0.0.1:8500"
+	}
+	if !strings.HasPrefix(consulAddr, "http://") && !strings.HasPrefix(consulAddr, "https://") {
+		consulAddr = "http://" + consulAddr
+	}
+
+	return &serviceResolver{
+		targets:     map[string]*resolvedTargets{},
+		unhealthy:   map[string]time.Time{},
+		consulAddr:  strings.TrimSuffix(consulAddr, "/"),
+		consulToken: os.Getenv("CONSUL_HTTP_TOKEN"),
+		client:      &http.Client{Timeout: 5 * time.Second},
+		lookupSRV: func(ctx context.Context, name string) ([]*net.SRV, error) {
+			_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+			return records, err
+		},
+	}
+}
+
+// resolve returns the url of an instance of the service along with its address preferring the instances in the
+// provided zone. Urls which don't need to be discovered are returned as is with an empty address.
+func (r *serviceResolver) resolve(ctx context.Context, serviceURL, zone string) (string, string, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return serviceURL, "", nil
+	}
+
+	scheme := "http"
+	kind := u.Scheme
+	if strings.HasSuffix(kind, "+https") {
+		scheme, kind = "https", strings.TrimSuffix(kind, "+https")
+	}
+	if kind != "consul" && kind != "srv" {
+		return serviceURL, "", nil
+	}
+
+	addrs, err := r.getAddrs(ctx, serviceURL, kind, u.Host)
+	if err != nil {
+		return "", "", err
+	}
+
+	addr := r.pick(serviceURL, zone, addrs)
+	return fmt.Sprintf("%s://%s%s", scheme, addr, strings.TrimSuffix(u.Path, "/")), addr, nil
+}
+
+// markFailed skips the instance for a while since a request to it failed
+func (r *serviceResolver) markFailed(addr string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.unhealthy[addr] = time.Now().Add(unhealthyCooldown)
+}
+
+func (r *serviceResolver) getAddrs(ctx context.Context, serviceURL, kind, name string) ([]string, error) {
+	r.lock.Lock()
+	targets, ok := r.targets[serviceURL]
+	r.lock.Unlock()
+	if ok && time.Now().Before(targets.expires) {
+		return targets.addrs, nil
+	}
+
+	var addrs []string
+	var zones map[string]string
+	var err error
+	if kind == "consul" {
+		addrs, zones, err = r.lookupConsul(ctx, name)
+	} else {
+		addrs, err = r.lookupDNS(ctx, name)
+	}
+	if err != nil {
+		// Keep using the instances resolved earlier if the lookup fails
+		if ok && len(targets.addrs) > 0 {
+			return targets.addrs, nil
+		}
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to resolve instances of service (%s)", serviceURL), err, nil)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no healthy instances found for service (%s)", serviceURL)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if existing, ok := r.targets[serviceURL]; ok {
+		existing.addrs, existing.zones, existing.expires = addrs, zones, time.Now().Add(discoveryCacheTTL)
+		return addrs, nil
+	}
+	r.targets[serviceURL] = &resolvedTargets{addrs: addrs, zones: zones, expires: time.Now().Add(discoveryCacheTTL)}
+	return addrs, nil
+}
+
+// pick returns the next instance in the rotation which hasn't failed recently, preferring the ones in the provided
+// zone. The next instance in the rotation is returned if all of them have failed.
+func (r *serviceResolver) pick(serviceURL, zone string, addrs []string) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	targets, ok := r.targets[serviceURL]
+	if !ok {
+		targets = &resolvedTargets{}
+		r.targets[serviceURL] = targets
+	}
+
+	now := time.Now()
+	start := targets.next % len(addrs)
+	isHealthy := func(addr string) bool {
+		if until, ok := r.unhealthy[addr]; ok && now.Before(until) {
+			return false
+		}
+		delete(r.unhealthy, addr)
+		return true
+	}
+
+	// The first pass only considers the instances in the zone of this node while the second one fails over to the
+	// instances in the other zones
+	for pass := 0; pass < 2; pass++ {
+		if pass == 0 && zone == "" {
+			continue
+		}
+		for i := 0; i < len(addrs); i++ {
+			index := (start + i) % len(addrs)
+			addr := addrs[index]
+			if pass == 0 && targets.zones[addr] != zone {
+				continue
+			}
+			if !isHealthy(addr) {
+				continue
+			}
+			targets.next = index + 1
+			return addr
+		}
+	}
+
+	targets.next = start + 1
+	return addrs[start]
+}
+
+// isInstanceFailure returns true if the instance couldn't be reached or is unable to serve requests
+func isInstanceFailure(status int, err error) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusInternalServerError:
+		// Requests which couldn't be made or whose response couldn't be read are reported with this status
+		return err != nil
+	}
+	return false
+}
+
+type consulServiceEntry struct {
+	Node struct {
+		Address string            `json:"Address"`
+		Meta    map[string]string `json:"Meta"`
+	} `json:"Node"`
+	Service struct {
+		Address string            `json:"Address"`
+		Port    int               `json:"Port"`
+		Meta    map[string]string `json:"Meta"`
+	} `json:"Service"`
+}
+
+// lookupConsul returns the addresses of the instances of the service passing their health checks along with the
+// zones of the instances which have one
+func (r *serviceResolver) lookupConsul(ctx context.Context, name string) ([]string, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/health/service/%s?passing=true", r.consulAddr, url.PathEscape(name)), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.consulToken != "" {
+		req.Header.Set("X-Consul-Token", r.consulToken)
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("consul responded with status (%d)", res.StatusCode)
+	}
+
+	var entries []consulServiceEntry
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, nil, err
+	}
+
+	addrs := make([]string, 0, len(entries))
+	zones := map[string]string{}
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(entry.Service.Port))
+		addrs = append(addrs, addr)
+
+		zone := entry.Service.Meta["zone"]
+		if zone == "" {
+			zone = entry.Node.Meta["zone"]
+		}
+		if zone != "" {
+			zones[addr] = zone
+		}
+	}
+	return addrs, zones, nil
+}
+
+// lookupDNS returns the addresses of the targets of the srv record having the highest priority
+func (r *serviceResolver) lookupDNS(ctx context.Context, name string) ([]string, error) {
+	records, err := r.lookupSRV(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(records))
+	for _, record := range records {
+		// Records are sorted by priority. Only the ones with the lowest value are used.
+		if record.Priority != records[0].Priority {
+			break
+		}
+		addrs = append(addrs, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+	}
+	return addrs, nil
+}
@@ -0,0 +1,144 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_serviceResolver_resolve(t *testing.T) {
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health/service/orders" || r.URL.Query().Get("passing") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"Node":{"Address":"10.0.0.1","Meta":{"zone":"us-east-1a"}},"Service":{"Address":"","Port":8080}},{"Node":{"Address":"10.0.0.2","Meta":{"zone":"us-east-1a"}},"Service":{"Address":"10.0.1.2","Port":9090,"Meta":{"zone":"us-east-1b"}}}]`))
+	}))
+	defer consul.Close()
+
+	r := newServiceResolver()
+	r.consulAddr = consul.URL
+	r.lookupSRV = func(ctx context.Context, name string) ([]*net.SRV, error) {
+		if name != "_orders._tcp.example.com" {
+			return nil, errors.New("no such host")
+		}
+		return []*net.SRV{
+			{Target: "a.example.com.", Port: 80, Priority: 1},
+			{Target: "b.example.com.", Port: 80, Priority: 1},
+			{Target: "backup.example.com.", Port: 80, Priority: 2},
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		serviceURL string
+		zone       string
+		failed     []string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "plain url is returned as is",
+			serviceURL: "http://localhost:8080",
+			want:       []string{"http://localhost:8080", "http://localhost:8080"},
+		},
+		{
+			name:       "consul instances are picked in rotation",
+			serviceURL: "consul://orders",
+			want:       []string{"http://10.0.0.1:8080", "http://10.0.1.2:9090", "http://10.0.0.1:8080"},
+		},
+		{
+			name:       "consul instances in the same zone are preferred",
+			serviceURL: "consul://orders",
+			zone:       "us-east-1b",
+			want:       []string{"http://10.0.1.2:9090", "http://10.0.1.2:9090"},
+		},
+		{
+			name:       "consul instances in other zones are used if none are in the same zone",
+			serviceURL: "consul://orders",
+			zone:       "us-west-2a",
+			want:       []string{"http://10.0.0.1:8080", "http://10.0.1.2:9090"},
+		},
+		{
+			name:       "failed instances are skipped",
+			serviceURL: "consul+https://orders/v1",
+			failed:     []string{"10.0.0.1:8080"},
+			want:       []string{"https://10.0.1.2:9090/v1", "https://10.0.1.2:9090/v1"},
+		},
+		{
+			name:       "srv targets with the highest priority are used",
+			serviceURL: "srv://_orders._tcp.example.com",
+			want:       []string{"http://a.example.com:80", "http://b.example.com:80", "http://a.example.com:80"},
+		},
+		{
+			name:       "unknown srv record",
+			serviceURL: "srv://_payments._tcp.example.com",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown consul service",
+			serviceURL: "consul://payments",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, addr := range tt.failed {
+				r.markFailed(addr)
+			}
+
+			if tt.wantErr {
+				if _, _, err := r.resolve(context.Background(), tt.serviceURL, tt.zone); err == nil {
+					t.Errorf("resolve() error = nil, wantErr %v", tt.wantErr)
+				}
+				return
+			}
+
+			for i, want := range tt.want {
+				got, _, err := r.resolve(context.Background(), tt.serviceURL, tt.zone)
+				if err != nil {
+					t.Fatalf("resolve() error = %v", err)
+				}
+				if got != want {
+					t.Errorf("resolve() call %d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_serviceResolver_pick(t *testing.T) {
+	addrs := []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.1.1:80"}
+	zones := map[string]string{"10.0.0.1:80": "zone-a", "10.0.0.2:80": "zone-a", "10.0.1.1:80": "zone-b"}
+
+	tests := []struct {
+		name   string
+		zone   string
+		failed []string
+		want   []string
+	}{
+		{name: "no zone", want: []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.1.1:80", "10.0.0.1:80"}},
+		{name: "same zone instances are rotated", zone: "zone-a", want: []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.1:80"}},
+		{name: "failed instance in the same zone is skipped", zone: "zone-a", failed: []string{"10.0.0.1:80"}, want: []string{"10.0.0.2:80", "10.0.0.2:80"}},
+		{name: "fails over to another zone", zone: "zone-b", failed: []string{"10.0.1.1:80"}, want: []string{"10.0.0.1:80", "10.0.0.2:80"}},
+		{name: "all instances failed", zone: "zone-b", failed: addrs, want: []string{"10.0.0.1:80", "10.0.0.2:80"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newServiceResolver()
+			r.targets["consul://orders"] = &resolvedTargets{addrs: addrs, zones: zones}
+			for _, addr := range tt.failed {
+				r.markFailed(addr)
+			}
+
+			for i, want := range tt.want {
+				if got := r.pick("consul://orders", tt.zone, addrs); got != want {
+					t.Errorf("pick() call %d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
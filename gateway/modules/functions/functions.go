@@ -11,6 +11,7 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tmpl"
 )
 
 // Module is responsible for functions
@@ -22,6 +23,7 @@ type Module struct {
 	manager        *syncman.Manager
 	integrationMan integrationManagerInterface
 	caching        cachingInterface
+	resolver       *serviceResolver
 
 	// Variable configuration
 	project    string
@@ -31,11 +33,12 @@ type Module struct {
 	clusterID string
 	// Templates for body transformation
 	templates map[string]*template.Template
+	jqQueries map[string]*tmpl.JQ
 }
 
 // Init returns a new instance of the Functions module
 func Init(clusterID string, auth model.AuthFunctionInterface, manager *syncman.Manager, integrationMan integrationManagerInterface, hook model.MetricFunctionHook) *Module {
-	return &Module{clusterID: clusterID, auth: auth, manager: manager, integrationMan: integrationMan, metricHook: hook}
+	return &Module{clusterID: clusterID, auth: auth, manager: manager, integrationMan: integrationMan, metricHook: hook, resolver: newServiceResolver()}
 }
 
 // SetConfig sets the configuration of the functions module
@@ -48,6 +51,7 @@ func (m *Module) SetConfig(project string, c config.Services) error {
 
 	// Set the go templates
 	m.templates = map[string]*template.Template{}
+	m.jqQueries = map[string]*tmpl.JQ{}
 	for _, service := range m.config {
 		for endpointID, endpoint := range service.Endpoints {
 			// Set the default endpoint kind
@@ -91,6 +95,27 @@ func (m *Module) SetConfig(project string, c config.Services) error {
 						return err
 					}
 				}
+			case config.TemplatingEngineJQ:
+				if endpoint.ReqTmpl != "" {
+					if err := m.createJQQuery("request", service.ID, endpointID, endpoint.ReqTmpl); err != nil {
+						return err
+					}
+				}
+				if endpoint.ResTmpl != "" {
+					if err := m.createJQQuery("response", service.ID, endpointID, endpoint.ResTmpl); err != nil {
+						return err
+					}
+				}
+				if endpoint.GraphTmpl != "" {
+					if err := m.createJQQuery("graph", service.ID, endpointID, endpoint.GraphTmpl); err != nil {
+						return err
+					}
+				}
+				if endpoint.Claims != "" {
+					if err := m.createJQQuery("claim", service.ID, endpointID, endpoint.Claims); err != nil {
+						return err
+					}
+				}
 			default:
 				return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Invalid templating engine (%s) provided", endpoint.Tmpl), nil, nil)
 			}
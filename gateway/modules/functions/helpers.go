@@ -19,13 +19,15 @@ import (
 	tmpl2 "github.com/spaceuptech/space-cloud/gateway/utils/tmpl"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
-func (m *Module) handleCall(ctx context.Context, serviceID, endpointID, token string, auth, params interface{}, cacheInfo *config.ReadCacheOptions) (int, interface{}, error) {
+func (m *Module) handleCall(ctx context.Context, serviceID, endpointID, token string, auth, params interface{}, cacheInfo *config.ReadCacheOptions, onChunk model.FunctionsStreamCallback) (int, interface{}, error) {
 	var url string
 	var method string
 	var ogToken string
+	var instance string
 
 	// Load the service rule
 	service := m.loadService(serviceID)
@@ -47,13 +49,19 @@ func (m *Module) handleCall(ctx context.Context, serviceID, endpointID, token st
 			endpointPath = "/" + endpointPath
 		}
 
+		// Services registered in consul or dns are resolved to one of their instances
+		serviceURL, instance, err = m.resolver.resolve(ctx, serviceURL, m.manager.GetNodeZone())
+		if err != nil {
+			return http.StatusServiceUnavailable, nil, err
+		}
+
 		url = serviceURL + endpointPath
 
 	case config.EndpointKindExternal:
 		url = endpointPath
 
 	case config.EndpointKindPrepared:
-		url = fmt.Sprintf("http://localhost:4122/v1/api/%s/graphql", m.getProject())
+		url = fmt.Sprintf("%s/v1/api/%s/graphql", m.manager.GetLocalURL(), m.getProject())
 
 	default:
 		return http.StatusBadRequest, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid endpoint kind (%s) provided", endpoint.Kind), nil, nil)
@@ -135,7 +143,28 @@ func (m *Module) handleCall(ctx context.Context, serviceID, endpointID, token st
 		Token: token, SCToken: scToken,
 		Headers: prepareHeaders(ctx, endpoint.Headers, state),
 	}
-	status, err := utils.MakeHTTPRequest(ctx, req, &res)
+	var status int
+	if onChunk == nil {
+		status, err = utils.MakeHTTPRequest(ctx, req, &res)
+	} else {
+		status, err = utils.MakeStreamingHTTPRequest(ctx, req, &res, func(line []byte) error {
+			chunk := new(model.FunctionsStreamChunk)
+			if err := json.Unmarshal(line, chunk); err != nil {
+				return err
+			}
+
+			// The result is returned once the stream ends
+			if chunk.Type == "result" {
+				res = chunk.Data
+				return nil
+			}
+			onChunk(chunk)
+			return nil
+		})
+	}
+	if instance != "" && isInstanceFailure(status, err) {
+		m.resolver.markFailed(instance)
+	}
 	if err != nil {
 		return status, nil, err
 	}
@@ -196,6 +225,19 @@ func (m *Module) adjustReqBody(ctx context.Context, serviceID, endpointID, token
 				return nil, err
 			}
 		}
+	case config.TemplatingEngineJQ:
+		if query, p := m.jqQueries[getGoTemplateKey("request", serviceID, endpointID)]; p {
+			req, err = tmpl2.JQTemplate(ctx, query, endpoint.OpFormat, token, auth, params)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if query, p := m.jqQueries[getGoTemplateKey("graph", serviceID, endpointID)]; p {
+			graph, err = tmpl2.JQTemplate(ctx, query, "string", token, auth, params)
+			if err != nil {
+				return nil, err
+			}
+		}
 	default:
 		helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid templating engine (%s) provided. Skipping templating step.", endpoint.Tmpl), map[string]interface{}{"serviceId": serviceID, "endpointId": endpointID})
 	}
@@ -260,6 +302,13 @@ func (m *Module) adjustResBody(ctx context.Context, serviceID, endpointID, token
 				return nil, err
 			}
 		}
+	case config.TemplatingEngineJQ:
+		if query, p := m.jqQueries[getGoTemplateKey("response", serviceID, endpointID)]; p {
+			res, err = tmpl2.JQTemplate(ctx, query, endpoint.OpFormat, token, auth, params)
+			if err != nil {
+				return nil, err
+			}
+		}
 	default:
 		helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid templating engine (%s) provided. Skipping templating step.", endpoint.Tmpl), map[string]interface{}{"serviceId": serviceID, "endpointId": endpointID})
 		return params, nil
@@ -283,6 +332,13 @@ func (m *Module) generateWebhookToken(ctx context.Context, serviceID, endpointID
 				return "", err
 			}
 		}
+	case config.TemplatingEngineJQ:
+		if query, p := m.jqQueries[getGoTemplateKey("claim", serviceID, endpointID)]; p {
+			req, err = tmpl2.JQTemplate(ctx, query, endpoint.OpFormat, token, auth, params)
+			if err != nil {
+				return "", err
+			}
+		}
 	default:
 		helpers.Logger.LogWarn(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid templating engine (%s) provided. Skipping templating step.", endpoint.Tmpl), map[string]interface{}{"serviceId": serviceID, "endpointId": endpointID})
 		return token, nil
@@ -366,6 +422,16 @@ func (m *Module) createGoTemplate(kind, serviceID, endpointID, tmpl string) erro
 	return nil
 }
 
+func (m *Module) createJQQuery(kind, serviceID, endpointID, query string) error {
+	q, err := tmpl2.ParseJQ(query)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Invalid jq expression provided", err, nil)
+	}
+
+	m.jqQueries[getGoTemplateKey(kind, serviceID, endpointID)] = q
+	return nil
+}
+
 func getGoTemplateKey(kind, serviceID, endpointID string) string {
 	return fmt.Sprintf("%s---%s---%s", kind, serviceID, endpointID)
 }
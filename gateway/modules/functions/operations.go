@@ -5,14 +5,29 @@ import (
 	"fmt"
 
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 // CallWithContext invokes function on a service. The response from the function is returned back along with
 // any errors if they occurred.
 func (m *Module) CallWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest) (int, interface{}, error) {
+	return m.call(ctx, service, function, token, reqParams, req, nil)
+}
+
+// CallStreamWithContext invokes function on a service which may stream its response. The chunks of a streamed
+// response are passed to onChunk as they arrive while the final result is returned like CallWithContext. Streamed
+// responses are never cached.
+func (m *Module) CallStreamWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest, onChunk model.FunctionsStreamCallback) (int, interface{}, error) {
+	req.Cache = nil
+	return m.call(ctx, service, function, token, reqParams, req, onChunk)
+}
+
+func (m *Module) call(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest, onChunk model.FunctionsStreamCallback) (int, interface{}, error) {
 	reqParams.Payload = map[string]interface{}{
 		"service":  service,
 		"endpoint": function,
@@ -29,8 +44,12 @@ func (m *Module) CallWithContext(ctx context.Context, service, function, token s
 		return hookResponse.Status(), hookResponse.Result(), nil
 	}
 
+	ctx, span := tracing.StartSpan(ctx, "functions.call", trace.SpanKindInternal, label.String("functions.service", service), label.String("functions.endpoint", function))
+
 	// TODO: Add metric hook for cache
-	status, result, err := m.handleCall(ctx, service, function, token, reqParams.Claims, req.Params, req.Cache)
+	status, result, err := m.handleCall(ctx, service, function, token, reqParams.Claims, req.Params, req.Cache, onChunk)
+	span.SetAttributes(label.Int("http.status_code", status))
+	tracing.EndSpan(ctx, span, err)
 	if err != nil {
 		return status, result, err
 	}
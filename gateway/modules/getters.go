@@ -3,14 +3,29 @@ package modules
 import (
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules/auth"
+	"github.com/spaceuptech/space-cloud/gateway/modules/backup"
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
 	"github.com/spaceuptech/space-cloud/gateway/modules/eventing"
 	"github.com/spaceuptech/space-cloud/gateway/modules/filestore"
+	"github.com/spaceuptech/space-cloud/gateway/modules/fixtures"
 	"github.com/spaceuptech/space-cloud/gateway/modules/functions"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/accounting"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/admission"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/caching"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/compression"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/diagnostics"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/idempotency"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/letsencrypt"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/logging"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/operations"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/routing"
+	"github.com/spaceuptech/space-cloud/gateway/modules/kv"
+	"github.com/spaceuptech/space-cloud/gateway/modules/privacy"
+	"github.com/spaceuptech/space-cloud/gateway/modules/retention"
 	"github.com/spaceuptech/space-cloud/gateway/modules/schema"
+	"github.com/spaceuptech/space-cloud/gateway/modules/search"
+	"github.com/spaceuptech/space-cloud/gateway/modules/sessions"
+	"github.com/spaceuptech/space-cloud/gateway/modules/slo"
 	"github.com/spaceuptech/space-cloud/gateway/modules/userman"
 )
 
@@ -59,6 +74,24 @@ func (m *Modules) Functions(projectID string) (*functions.Module, error) {
 	return module.functions, nil
 }
 
+// StreamingFunctions returns the functions module to call functions which may stream their response
+func (m *Modules) StreamingFunctions(projectID string) (FunctionsInterface, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.functions, nil
+}
+
+// FunctionsAuth returns the auth module to authorise function calls
+func (m *Modules) FunctionsAuth(projectID string) (AuthFunctionsInterface, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.auth, nil
+}
+
 // Realtime returns the auth module
 func (m *Modules) Realtime(projectID string) (RealtimeInterface, error) {
 	module, err := m.loadModule(projectID)
@@ -86,6 +119,87 @@ func (m *Modules) GraphQL(projectID string) (GraphQLInterface, error) {
 	return module.graphql, nil
 }
 
+// Search returns the search module
+func (m *Modules) Search(projectID string) (*search.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.search, nil
+}
+
+// Backup returns the backup module
+func (m *Modules) Backup(projectID string) (*backup.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.backup, nil
+}
+
+// Retention returns the retention module
+func (m *Modules) Retention(projectID string) (*retention.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.retention, nil
+}
+
+// KV returns the key value store module
+func (m *Modules) KV(projectID string) (*kv.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.kv, nil
+}
+
+// Privacy returns the privacy module
+func (m *Modules) Privacy(projectID string) (*privacy.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.privacy, nil
+}
+
+// FeatureFlags returns the feature flags module
+func (m *Modules) FeatureFlags(projectID string) (FeatureFlagsInterface, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.flags, nil
+}
+
+// Sessions returns the sessions module
+func (m *Modules) Sessions(projectID string) (*sessions.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.sessions, nil
+}
+
+// SLO returns the module tracking the service level objectives
+func (m *Modules) SLO(projectID string) (*slo.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.slo, nil
+}
+
+// Fixtures returns the module loading the seed data of the project
+func (m *Modules) Fixtures(projectID string) (*fixtures.Module, error) {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return module.fixtures, nil
+}
+
 // Schema returns the auth module
 func (m *Modules) Schema(projectID string) (*schema.Schema, error) {
 	module, err := m.loadModule(projectID)
@@ -126,4 +240,39 @@ func (m *Modules) Routing() *routing.Routing {
 // Caching returns the caching module
 func (m *Modules) Caching() *caching.Cache {
 	return m.GlobalMods.Caching()
-}
\ No newline at end of file
+}
+
+// Logging returns the structured request logger
+func (m *Modules) Logging() *logging.Logger {
+	return m.GlobalMods.Logging()
+}
+
+// Accounting returns the module tracking the resource usage of every project
+func (m *Modules) Accounting() *accounting.Module {
+	return m.GlobalMods.Accounting()
+}
+
+// Diagnostics returns the module guarding the runtime diagnostics
+func (m *Modules) Diagnostics() *diagnostics.Module {
+	return m.GlobalMods.Diagnostics()
+}
+
+// Admission returns the module shedding the low priority traffic under overload
+func (m *Modules) Admission() *admission.Module {
+	return m.GlobalMods.Admission()
+}
+
+// Compression returns the module compressing the responses
+func (m *Modules) Compression() *compression.Module {
+	return m.GlobalMods.Compression()
+}
+
+// Operations returns the registry of the operations in flight
+func (m *Modules) Operations() *operations.Registry {
+	return m.GlobalMods.Operations()
+}
+
+// Idempotency returns the module deduplicating retried requests
+func (m *Modules) Idempotency() *idempotency.Module {
+	return m.GlobalMods.Idempotency()
+}
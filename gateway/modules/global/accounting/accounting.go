@@ -0,0 +1,309 @@
+package accounting
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// defaultExportInterval is used when the export interval isn't set in the accounting config
+const defaultExportInterval = time.Hour
+
+// Usage is the resource usage of a project on a gateway node over a period of time. Usage of the entire
+// cluster is the sum of the usage reported by all nodes.
+type Usage struct {
+	Project   string    `json:"project"`
+	ClusterID string    `json:"clusterId"`
+	NodeID    string    `json:"nodeId"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+
+	Requests          uint64 `json:"requests"`
+	EgressBytes       uint64 `json:"egressBytes"`
+	DBOperations      uint64 `json:"dbOperations"`
+	DBDocuments       uint64 `json:"dbDocuments"`
+	FileBytesUploaded uint64 `json:"fileBytesUploaded"`
+
+	RealtimeConnectionMinutes float64 `json:"realtimeConnectionMinutes"`
+}
+
+func (u *Usage) isEmpty() bool {
+	return u.Requests == 0 && u.EgressBytes == 0 && u.DBOperations == 0 && u.DBDocuments == 0 && u.FileBytesUploaded == 0 && u.RealtimeConnectionMinutes == 0
+}
+
+// counters hold the usage of a project since the gateway started
+type counters struct {
+	requests, egressBytes, dbOperations, dbDocuments, fileBytesUploaded uint64
+
+	// connectionTime is the time for which the closed realtime connections were open
+	connectionTime time.Duration
+}
+
+type connection struct {
+	project string
+	since   time.Time
+}
+
+// Module tracks the resource usage of every project and periodically exports it for billing
+type Module struct {
+	lock sync.Mutex
+
+	clusterID, nodeID string
+	startedAt         time.Time
+
+	projects    map[string]*counters
+	connections map[*connection]struct{}
+
+	// lastExport is the cumulative usage of every project at the time of the last successful export
+	lastExport   map[string]*Usage
+	lastExportAt time.Time
+
+	exporters []exporter
+	done      chan struct{}
+	stopped   chan struct{}
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// New creates a new instance of the accounting module. The usage is always tracked while the export
+// needs to be enabled in the cluster config.
+func New(clusterID, nodeID string) *Module {
+	now := time.Now()
+	return &Module{
+		clusterID:    clusterID,
+		nodeID:       nodeID,
+		startedAt:    now,
+		projects:     map[string]*counters{},
+		connections:  map[*connection]struct{}{},
+		lastExport:   map[string]*Usage{},
+		lastExportAt: now,
+		now:          time.Now,
+	}
+}
+
+// SetConfig applies the accounting config. The usage since the last export is exported before the previous
+// config is discarded.
+func (m *Module) SetConfig(c *config.AccountingConfig) error {
+	m.stopExport()
+
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	exporters, err := newExporters(c)
+	if err != nil {
+		return err
+	}
+
+	interval := defaultExportInterval
+	if c.ExportInterval > 0 {
+		interval = time.Duration(c.ExportInterval) * time.Second
+	}
+
+	m.lock.Lock()
+	m.exporters = exporters
+	m.done = make(chan struct{})
+	m.stopped = make(chan struct{})
+	go m.routineExport(interval, m.done, m.stopped)
+	m.lock.Unlock()
+	return nil
+}
+
+// Close exports the pending usage and stops the export routine
+func (m *Module) Close() {
+	m.stopExport()
+}
+
+func (m *Module) stopExport() {
+	m.lock.Lock()
+	done, stopped := m.done, m.stopped
+	m.done, m.stopped = nil, nil
+	m.lock.Unlock()
+
+	if done == nil {
+		return
+	}
+	close(done)
+	<-stopped
+
+	m.lock.Lock()
+	m.exporters = nil
+	m.lock.Unlock()
+}
+
+func (m *Module) getCounters(project string) *counters {
+	c, p := m.projects[project]
+	if !p {
+		c = new(counters)
+		m.projects[project] = c
+	}
+	return c
+}
+
+// AddRequest records a request served for a project along with the bytes sent in the response
+func (m *Module) AddRequest(project string, egressBytes uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	c := m.getCounters(project)
+	c.requests++
+	c.egressBytes += egressBytes
+}
+
+// AddFileUpload records the bytes uploaded to the file storage of a project
+func (m *Module) AddFileUpload(project string, bytes uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.getCounters(project).fileBytesUploaded += bytes
+}
+
+// AddDBOperation records a database operation along with the number of documents it affected
+func (m *Module) AddDBOperation(project string, documents int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	c := m.getCounters(project)
+	c.dbOperations++
+	if documents > 0 {
+		c.dbDocuments += uint64(documents)
+	}
+}
+
+// OpenConnection records a realtime connection of a project. The returned function must be called once the connection closes.
+func (m *Module) OpenConnection(project string) func() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	conn := &connection{project: project, since: m.now()}
+	m.connections[conn] = struct{}{}
+
+	return func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		if _, p := m.connections[conn]; !p {
+			return
+		}
+		delete(m.connections, conn)
+		m.getCounters(project).connectionTime += m.now().Sub(conn.since)
+	}
+}
+
+// GetUsage returns the usage of the project on this node since the gateway started
+func (m *Module) GetUsage(project string) *Usage {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.cumulativeUsage(project, m.now())
+}
+
+// cumulativeUsage must be called with the lock held
+func (m *Module) cumulativeUsage(project string, now time.Time) *Usage {
+	u := &Usage{Project: project, ClusterID: m.clusterID, NodeID: m.nodeID, From: m.startedAt, To: now}
+
+	connectionTime := time.Duration(0)
+	if c, p := m.projects[project]; p {
+		u.Requests = c.requests
+		u.EgressBytes = c.egressBytes
+		u.DBOperations = c.dbOperations
+		u.DBDocuments = c.dbDocuments
+		u.FileBytesUploaded = c.fileBytesUploaded
+		connectionTime = c.connectionTime
+	}
+
+	// Open connections are accounted till now
+	for conn := range m.connections {
+		if conn.project == project {
+			connectionTime += now.Sub(conn.since)
+		}
+	}
+	u.RealtimeConnectionMinutes = connectionTime.Minutes()
+	return u
+}
+
+// usageSinceLastExport returns the usage of all projects since the last successful export along with
+// the cumulative usage to be stored once the export succeeds. It must be called with the lock held.
+func (m *Module) usageSinceLastExport(now time.Time) ([]*Usage, map[string]*Usage) {
+	projects := make(map[string]struct{}, len(m.projects))
+	for project := range m.projects {
+		projects[project] = struct{}{}
+	}
+	for conn := range m.connections {
+		projects[conn.project] = struct{}{}
+	}
+
+	arr := make([]*Usage, 0, len(projects))
+	cumulative := make(map[string]*Usage, len(projects))
+	for project := range projects {
+		total := m.cumulativeUsage(project, now)
+		cumulative[project] = total
+
+		u := *total
+		u.From = m.lastExportAt
+		if last, p := m.lastExport[project]; p {
+			u.Requests -= last.Requests
+			u.EgressBytes -= last.EgressBytes
+			u.DBOperations -= last.DBOperations
+			u.DBDocuments -= last.DBDocuments
+			u.FileBytesUploaded -= last.FileBytesUploaded
+			u.RealtimeConnectionMinutes -= last.RealtimeConnectionMinutes
+		}
+		if !u.isEmpty() {
+			arr = append(arr, &u)
+		}
+	}
+
+	sort.Slice(arr, func(i, j int) bool { return arr[i].Project < arr[j].Project })
+	return arr, cumulative
+}
+
+func (m *Module) routineExport(interval time.Duration, done, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.export()
+		case <-done:
+			m.export()
+			return
+		}
+	}
+}
+
+// export sends the usage since the last successful export to all exporters. The usage is retained
+// till all the exporters succeed so that no usage is lost if an exporter is temporarily unavailable.
+func (m *Module) export() {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	m.lock.Lock()
+	now := m.now()
+	usage, cumulative := m.usageSinceLastExport(now)
+	exporters := m.exporters
+	m.lock.Unlock()
+
+	if len(usage) == 0 {
+		return
+	}
+
+	for _, e := range exporters {
+		if err := e.export(ctx, usage); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to export resource usage", err, nil)
+			return
+		}
+	}
+
+	m.lock.Lock()
+	m.lastExport = cumulative
+	m.lastExportAt = now
+	m.lock.Unlock()
+}
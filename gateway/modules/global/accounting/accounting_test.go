@@ -0,0 +1,133 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeExporter struct {
+	err     error
+	exports [][]*Usage
+}
+
+func (f *fakeExporter) export(_ context.Context, usage []*Usage) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.exports = append(f.exports, usage)
+	return nil
+}
+
+func newTestModule() (*Module, *time.Time) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := New("cluster", "node")
+	m.startedAt, m.lastExportAt = now, now
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestModule_GetUsage(t *testing.T) {
+	m, now := newTestModule()
+
+	m.AddRequest("p1", 100)
+	m.AddRequest("p1", 50)
+	m.AddDBOperation("p1", 10)
+	m.AddDBOperation("p1", -1)
+	m.AddFileUpload("p1", 1024)
+	m.AddRequest("p2", 1)
+
+	closeFirst := m.OpenConnection("p1")
+	*now = now.Add(2 * time.Minute)
+	closeSecond := m.OpenConnection("p1")
+	closeFirst()
+	closeFirst()
+	*now = now.Add(time.Minute)
+
+	got := m.GetUsage("p1")
+	if got.Requests != 2 || got.EgressBytes != 150 || got.DBOperations != 2 || got.DBDocuments != 10 || got.FileBytesUploaded != 1024 {
+		t.Errorf("GetUsage() = %+v", got)
+	}
+	// 2 minutes of the closed connection and 1 minute of the open one
+	if got.RealtimeConnectionMinutes != 3 {
+		t.Errorf("GetUsage() realtime connection minutes = %v, want 3", got.RealtimeConnectionMinutes)
+	}
+
+	closeSecond()
+	if got := m.GetUsage("p2"); got.Requests != 1 || got.RealtimeConnectionMinutes != 0 {
+		t.Errorf("GetUsage() = %+v", got)
+	}
+}
+
+func TestModule_export(t *testing.T) {
+	m, now := newTestModule()
+	e := &fakeExporter{}
+	m.exporters = []exporter{e}
+
+	m.AddRequest("p1", 10)
+	m.AddRequest("p2", 20)
+	*now = now.Add(time.Hour)
+	m.export()
+	if len(e.exports) != 1 || len(e.exports[0]) != 2 || e.exports[0][0].Project != "p1" || e.exports[0][1].EgressBytes != 20 {
+		t.Fatalf("export() = %+v", e.exports)
+	}
+
+	// Usage is retained till the export succeeds
+	m.AddRequest("p1", 5)
+	e.err = errors.New("unavailable")
+	*now = now.Add(time.Hour)
+	m.export()
+
+	e.err = nil
+	m.AddRequest("p1", 5)
+	*now = now.Add(time.Hour)
+	m.export()
+	if len(e.exports) != 2 {
+		t.Fatalf("export() exported %d times, want 2", len(e.exports))
+	}
+	u := e.exports[1]
+	if len(u) != 1 || u[0].Project != "p1" || u[0].Requests != 2 || u[0].EgressBytes != 10 || !u[0].From.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("export() = %+v", u[0])
+	}
+
+	// Nothing is exported for a period without usage
+	m.export()
+	if len(e.exports) != 2 {
+		t.Errorf("export() exported an empty period")
+	}
+}
+
+func TestCSVExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.csv")
+	e := &csvExporter{path: path}
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	usage := []*Usage{{Project: "p1", ClusterID: "c", NodeID: "n", From: from, To: from.Add(time.Hour), Requests: 3, RealtimeConnectionMinutes: 1.5}}
+
+	for i := 0; i < 2; i++ {
+		if err := e.export(context.Background(), usage); err != nil {
+			t.Fatalf("export() error = %v", err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[0] != strings.Join(csvHeader, ",") {
+		t.Fatalf("csv file = %s", data)
+	}
+	if want := "p1,c,n,2020-01-01T00:00:00Z,2020-01-01T01:00:00Z,3,0,0,0,0,1.50"; lines[1] != want || lines[2] != want {
+		t.Errorf("csv record = %s, want %s", lines[1], want)
+	}
+
+	b := new(bytes.Buffer)
+	if err := WriteCSV(b, usage); err != nil || !strings.HasPrefix(b.String(), "project,") {
+		t.Errorf("WriteCSV() = %s, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,128 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// exportTimeout is the time allowed for all exporters to export the usage of a period
+const exportTimeout = 30 * time.Second
+
+// exporter sends the usage of a period to a billing system
+type exporter interface {
+	export(ctx context.Context, usage []*Usage) error
+}
+
+func newExporters(c *config.AccountingConfig) ([]exporter, error) {
+	exporters := make([]exporter, 0, 2)
+	if c.CSVPath != "" {
+		if err := os.MkdirAll(filepath.Dir(c.CSVPath), 0755); err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, &csvExporter{path: c.CSVPath})
+	}
+	if c.WebhookURL != "" {
+		exporters = append(exporters, &webhookExporter{url: c.WebhookURL, headers: c.Headers, client: &http.Client{Timeout: exportTimeout}})
+	}
+	if len(exporters) == 0 {
+		return nil, fmt.Errorf("either csvPath or webhookUrl needs to be provided to export the resource usage")
+	}
+	return exporters, nil
+}
+
+// csvHeader is the header row of the exported csv files
+var csvHeader = []string{"project", "cluster_id", "node_id", "from", "to", "requests", "egress_bytes", "db_operations", "db_documents", "file_bytes_uploaded", "realtime_connection_minutes"}
+
+// WriteCSV writes the usage in the csv format along with the header row
+func WriteCSV(w io.Writer, usage []*Usage) error {
+	return writeCSV(w, usage, true)
+}
+
+func writeCSV(w io.Writer, usage []*Usage, withHeader bool) error {
+	cw := csv.NewWriter(w)
+	if withHeader {
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	for _, u := range usage {
+		record := []string{
+			u.Project, u.ClusterID, u.NodeID, u.From.UTC().Format(time.RFC3339), u.To.UTC().Format(time.RFC3339),
+			strconv.FormatUint(u.Requests, 10),
+			strconv.FormatUint(u.EgressBytes, 10),
+			strconv.FormatUint(u.DBOperations, 10),
+			strconv.FormatUint(u.DBDocuments, 10),
+			strconv.FormatUint(u.FileBytesUploaded, 10),
+			strconv.FormatFloat(u.RealtimeConnectionMinutes, 'f', 2, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvExporter appends the usage to a csv file. The header is written when the file is created.
+type csvExporter struct {
+	path string
+}
+
+func (e *csvExporter) export(_ context.Context, usage []*Usage) error {
+	file, err := os.OpenFile(e.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return writeCSV(file, usage, info.Size() == 0)
+}
+
+// webhookExporter posts the usage as a json array to a webhook
+type webhookExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (e *webhookExporter) export(ctx context.Context, usage []*Usage) error {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("usage webhook (%s) responded with status code %d", e.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+const (
+	defaultQueueTimeout = time.Second
+	defaultRetryAfter   = 5
+
+	// latencyWeight is the weight of the latest request in the moving average of the latency
+	latencyWeight = 0.1
+
+	// latencyStaleness is the time after which the average latency is ignored if no request has completed since.
+	// It keeps the node from shedding traffic forever on the basis of an old average.
+	latencyStaleness = 10 * time.Second
+)
+
+// ErrOverloaded is returned for the requests which have been shed
+var ErrOverloaded = errors.New("gateway is overloaded")
+
+// ranks orders the priorities in which the queued requests are served
+var ranks = map[config.RequestPriority]int{config.PriorityCritical: 0, config.PriorityHigh: 1, config.PriorityNormal: 2, config.PriorityLow: 3}
+
+// Module sheds the low priority traffic of the gateway node when it is overloaded
+type Module struct {
+	lock sync.Mutex
+
+	config       *config.AdmissionConfig
+	queueTimeout time.Duration
+
+	inFlight int
+	queue    waitQueue
+	seq      uint64
+
+	// latency is the moving average of the latency of the recent requests
+	latency    time.Duration
+	lastSample time.Time
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// New creates a new instance of the admission control module. It admits every request until it is enabled.
+func New() *Module {
+	return &Module{now: time.Now}
+}
+
+// SetConfig sets the thresholds and the priority rules of the admission control
+func (m *Module) SetConfig(c *config.AdmissionConfig) error {
+	if c != nil {
+		if _, ok := ranks[c.DefaultPriority]; !ok && c.DefaultPriority != "" {
+			return fmt.Errorf("invalid default request priority (%s) provided", c.DefaultPriority)
+		}
+		for _, rule := range c.Rules {
+			if _, ok := ranks[rule.Priority]; !ok {
+				return fmt.Errorf("invalid request priority (%s) provided for the rule of path (%s)", rule.Priority, rule.Path)
+			}
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = c
+	m.queueTimeout = defaultQueueTimeout
+	if c != nil && c.QueueTimeout > 0 {
+		m.queueTimeout = time.Duration(c.QueueTimeout) * time.Millisecond
+	}
+
+	// The queued requests might fit in the new thresholds
+	m.dispatch()
+	return nil
+}
+
+// RetryAfter returns the time in seconds after which the shed requests should be retried
+func (m *Module) RetryAfter() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.config != nil && m.config.RetryAfter > 0 {
+		return m.config.RetryAfter
+	}
+	return defaultRetryAfter
+}
+
+// GetPriority returns the priority of a request. The project is empty for the requests which aren't made to the
+// client apis.
+func (m *Module) GetPriority(project, path string) config.RequestPriority {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.config == nil {
+		return config.PriorityCritical
+	}
+	for _, rule := range m.config.Rules {
+		if (rule.Project == "" || rule.Project == project) && strings.HasPrefix(path, rule.Path) {
+			return rule.Priority
+		}
+	}
+	if project == "" {
+		return config.PriorityCritical
+	}
+	if m.config.DefaultPriority != "" {
+		return m.config.DefaultPriority
+	}
+	return config.PriorityNormal
+}
+
+// Admit decides whether a request gets served. Requests exceeding the max concurrency wait for their turn unless
+// they are of a low priority. The returned function must be called once the request has been served.
+func (m *Module) Admit(ctx context.Context, priority config.RequestPriority) (func(), error) {
+	m.lock.Lock()
+
+	c := m.config
+	if c == nil || !c.Enabled {
+		m.lock.Unlock()
+		return func() {}, nil
+	}
+
+	now := m.now()
+	latencyExceeded := c.MaxLatency > 0 && m.latency > time.Duration(c.MaxLatency)*time.Millisecond && now.Sub(m.lastSample) < latencyStaleness
+	concurrencyExceeded := c.MaxConcurrency > 0 && (m.inFlight >= c.MaxConcurrency || m.queue.Len() > 0)
+
+	switch {
+	case priority == config.PriorityCritical:
+	case priority == config.PriorityLow && (latencyExceeded || concurrencyExceeded):
+		m.lock.Unlock()
+		return nil, ErrOverloaded
+	case priority == config.PriorityNormal && latencyExceeded:
+		m.lock.Unlock()
+		return nil, ErrOverloaded
+	case concurrencyExceeded:
+		return m.wait(ctx, priority)
+	}
+
+	m.inFlight++
+	m.lock.Unlock()
+	return m.done(now), nil
+}
+
+// wait queues a request until a slot gets free. It must be called with the lock acquired.
+func (m *Module) wait(ctx context.Context, priority config.RequestPriority) (func(), error) {
+	m.seq++
+	w := &waiter{rank: ranks[priority], seq: m.seq, ready: make(chan struct{})}
+	heap.Push(&m.queue, w)
+	timeout := m.queueTimeout
+	m.lock.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+		return m.done(m.now()), nil
+	case <-timer.C:
+		err = ErrOverloaded
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// The request might have been admitted while the wait was being abandoned
+	if w.index < 0 {
+		return m.done(m.now()), nil
+	}
+	heap.Remove(&m.queue, w.index)
+	return nil, err
+}
+
+// done returns the function releasing the slot of an admitted request
+func (m *Module) done(start time.Time) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.lock.Lock()
+			defer m.lock.Unlock()
+
+			now := m.now()
+			latency := now.Sub(start)
+			if m.lastSample.IsZero() || now.Sub(m.lastSample) >= latencyStaleness {
+				m.latency = latency
+			} else {
+				m.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(m.latency))
+			}
+			m.lastSample = now
+
+			m.inFlight--
+			m.dispatch()
+		})
+	}
+}
+
+// dispatch hands the free slots over to the queued requests in the order of their priority. It must be called with
+// the lock acquired.
+func (m *Module) dispatch() {
+	for m.queue.Len() > 0 {
+		c := m.config
+		if c != nil && c.Enabled && c.MaxConcurrency > 0 && m.inFlight >= c.MaxConcurrency {
+			return
+		}
+		w := heap.Pop(&m.queue).(*waiter)
+		m.inFlight++
+		close(w.ready)
+	}
+}
+
+// waiter is a request waiting in the queue for a slot
+type waiter struct {
+	rank  int
+	seq   uint64
+	ready chan struct{}
+	index int // index in the queue, -1 once the request has left it
+}
+
+// waitQueue is a heap of the waiting requests ordered by their priority and then by their arrival
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+
+func (q *waitQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
@@ -0,0 +1,155 @@
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestModule_GetPriority(t *testing.T) {
+	m := New()
+	if err := m.SetConfig(&config.AdmissionConfig{
+		Enabled:         true,
+		DefaultPriority: config.PriorityHigh,
+		Rules: []*config.AdmissionRule{
+			{Project: "analytics", Priority: config.PriorityLow},
+			{Path: "/v1/api/myproject/graphql", Priority: config.PriorityCritical},
+			{Path: "/v1/config/projects/myproject/database", Priority: config.PriorityNormal},
+		},
+	}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		path    string
+		want    config.RequestPriority
+	}{
+		{name: "project rule", project: "analytics", path: "/v1/api/analytics/crud/db/users/read", want: config.PriorityLow},
+		{name: "route rule", project: "myproject", path: "/v1/api/myproject/graphql", want: config.PriorityCritical},
+		{name: "client api without a rule", project: "myproject", path: "/v1/api/myproject/crud/db/users/read", want: config.PriorityHigh},
+		{name: "config api with a rule", path: "/v1/config/projects/myproject/database/db/config/db", want: config.PriorityNormal},
+		{name: "config api without a rule", path: "/v1/config/projects/myproject", want: config.PriorityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.GetPriority(tt.project, tt.path); got != tt.want {
+				t.Errorf("GetPriority() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := m.SetConfig(&config.AdmissionConfig{Rules: []*config.AdmissionRule{{Path: "/v1/api", Priority: "urgent"}}}); err == nil {
+		t.Error("SetConfig() didn't reject an invalid priority")
+	}
+}
+
+func TestModule_Admit_concurrency(t *testing.T) {
+	m := New()
+	if err := m.SetConfig(&config.AdmissionConfig{Enabled: true, MaxConcurrency: 1, QueueTimeout: 1000}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	done, err := m.Admit(context.Background(), config.PriorityNormal)
+	if err != nil {
+		t.Fatalf("Admit() of the first request error = %v", err)
+	}
+
+	// Low priority requests are shed while critical requests are always admitted
+	if _, err := m.Admit(context.Background(), config.PriorityLow); err != ErrOverloaded {
+		t.Errorf("Admit() of a low priority request error = %v, want %v", err, ErrOverloaded)
+	}
+	critical, err := m.Admit(context.Background(), config.PriorityCritical)
+	if err != nil {
+		t.Errorf("Admit() of a critical request error = %v", err)
+	}
+	critical()
+
+	// Queued requests are served in the order of their priority
+	order := make(chan config.RequestPriority, 2)
+	for i, p := range []config.RequestPriority{config.PriorityNormal, config.PriorityHigh} {
+		go func(p config.RequestPriority) {
+			release, err := m.Admit(context.Background(), p)
+			if err != nil {
+				t.Errorf("Admit() of a queued %s request error = %v", p, err)
+				order <- ""
+				return
+			}
+			order <- p
+			release()
+		}(p)
+		waitForQueue(t, m, i+1)
+	}
+	done()
+	if first, second := <-order, <-order; first != config.PriorityHigh || second != config.PriorityNormal {
+		t.Errorf("Admit() served the queued requests in the order (%s, %s), want (high, normal)", first, second)
+	}
+
+	// Queued requests are shed once they time out
+	_ = m.SetConfig(&config.AdmissionConfig{Enabled: true, MaxConcurrency: 1, QueueTimeout: 10})
+	done, _ = m.Admit(context.Background(), config.PriorityNormal)
+	defer done()
+	if _, err := m.Admit(context.Background(), config.PriorityHigh); err != ErrOverloaded {
+		t.Errorf("Admit() of a timed out request error = %v, want %v", err, ErrOverloaded)
+	}
+	if m.queue.Len() != 0 {
+		t.Errorf("Admit() left %d timed out requests in the queue", m.queue.Len())
+	}
+}
+
+func TestModule_Admit_latency(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := New()
+	m.now = func() time.Time { return now }
+	if err := m.SetConfig(&config.AdmissionConfig{Enabled: true, MaxLatency: 100}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	// A slow request pushes the latency over the threshold
+	done, _ := m.Admit(context.Background(), config.PriorityNormal)
+	now = now.Add(time.Second)
+	done()
+
+	tests := []struct {
+		priority config.RequestPriority
+		wantErr  bool
+	}{
+		{priority: config.PriorityLow, wantErr: true},
+		{priority: config.PriorityNormal, wantErr: true},
+		{priority: config.PriorityHigh},
+		{priority: config.PriorityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			release, err := m.Admit(context.Background(), tt.priority)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Admit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				release()
+			}
+		})
+	}
+
+	// The latency is ignored once it gets stale
+	now = now.Add(latencyStaleness)
+	if _, err := m.Admit(context.Background(), config.PriorityLow); err != nil {
+		t.Errorf("Admit() with a stale latency error = %v", err)
+	}
+}
+
+func waitForQueue(t *testing.T, m *Module, n int) {
+	for i := 0; i < 100; i++ {
+		m.lock.Lock()
+		l := m.queue.Len()
+		m.lock.Unlock()
+		if l == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("queue didn't reach %d requests", n)
+}
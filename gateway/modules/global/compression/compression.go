@@ -0,0 +1,141 @@
+package compression
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+const defaultMinSize = 1024
+
+// defaultEncodings are the encodings offered in the order of preference when none are configured
+var defaultEncodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+
+// Module compresses the responses of the gateway with the encoding negotiated with the client
+type Module struct {
+	lock sync.RWMutex
+
+	config    *config.CompressionConfig
+	encodings []string
+	minSize   int
+}
+
+// New creates a new instance of the compression module. Responses aren't compressed until it is enabled.
+func New() *Module {
+	return &Module{}
+}
+
+// SetConfig sets the encodings and the levels used to compress the responses
+func (m *Module) SetConfig(c *config.CompressionConfig) error {
+	encodings := defaultEncodings
+	minSize := defaultMinSize
+	if c != nil {
+		if len(c.Encodings) > 0 {
+			encodings = c.Encodings
+		}
+		if c.MinSize > 0 {
+			minSize = c.MinSize
+		}
+
+		for _, encoding := range encodings {
+			factory, ok := getEncoder(encoding)
+			if !ok {
+				return fmt.Errorf("unsupported compression encoding (%s) provided", encoding)
+			}
+			// Make sure the configured level is accepted by the encoder
+			enc, err := factory(ioutil.Discard, c.Levels[encoding])
+			if err != nil {
+				return fmt.Errorf("invalid compression level provided for encoding (%s) - %v", encoding, err)
+			}
+			_ = enc.Close()
+		}
+		for encoding := range c.Levels {
+			if _, ok := getEncoder(encoding); !ok {
+				return fmt.Errorf("compression level provided for unsupported encoding (%s)", encoding)
+			}
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = c
+	m.encodings = encodings
+	m.minSize = minSize
+	return nil
+}
+
+// NewResponseWriter returns a response writer compressing the response of the request with the encoding negotiated
+// from its Accept-Encoding header. False is returned if the response of the request mustn't be compressed. The
+// returned writer must be closed once the response has been written.
+func (m *Module) NewResponseWriter(w http.ResponseWriter, r *http.Request) (*ResponseWriter, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.config == nil || !m.config.Enabled {
+		return nil, false
+	}
+
+	// Partial responses refer to the offsets of the uncompressed body
+	if r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+		return nil, false
+	}
+	for _, prefix := range m.config.ExcludePaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return nil, false
+		}
+	}
+
+	encoding, ok := negotiate(r.Header.Get("Accept-Encoding"), m.encodings)
+	if !ok {
+		return nil, false
+	}
+	factory, _ := getEncoder(encoding)
+	return &ResponseWriter{ResponseWriter: w, encoding: encoding, level: m.config.Levels[encoding], factory: factory, minSize: m.minSize}, true
+}
+
+// negotiate picks the encoding with the highest quality in the Accept-Encoding header. Ties are broken by the order
+// of the offered encodings.
+func negotiate(acceptEncoding string, offered []string) (string, bool) {
+	if acceptEncoding == "" {
+		return "", false
+	}
+
+	accepted := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if encoding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					v = 0
+				}
+				q = v
+			}
+		}
+		accepted[encoding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range offered {
+		q, ok := accepted[encoding]
+		if !ok {
+			q = accepted["*"]
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best, best != ""
+}
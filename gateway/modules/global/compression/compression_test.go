@@ -0,0 +1,231 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_negotiate(t *testing.T) {
+	offered := []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		want           string
+		wantOk         bool
+	}{
+		{name: "no accept encoding", acceptEncoding: ""},
+		{name: "single encoding", acceptEncoding: "gzip", want: EncodingGzip, wantOk: true},
+		{name: "ties are broken by the server preference", acceptEncoding: "gzip, deflate, br", want: EncodingBrotli, wantOk: true},
+		{name: "higher quality wins", acceptEncoding: "zstd;q=0.5, gzip;q=0.8", want: EncodingGzip, wantOk: true},
+		{name: "zero quality excludes an encoding", acceptEncoding: "*, zstd;q=0", want: EncodingBrotli, wantOk: true},
+		{name: "wildcard", acceptEncoding: "*", want: EncodingZstd, wantOk: true},
+		{name: "unsupported encodings", acceptEncoding: "deflate, identity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := negotiate(tt.acceptEncoding, offered)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("negotiate() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestModule_SetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *config.CompressionConfig
+		wantErr bool
+	}{
+		{name: "defaults", config: &config.CompressionConfig{Enabled: true}},
+		{name: "valid levels", config: &config.CompressionConfig{Enabled: true, Levels: map[string]int{EncodingGzip: 9, EncodingBrotli: 4, EncodingZstd: 3}}},
+		{name: "unsupported encoding", config: &config.CompressionConfig{Enabled: true, Encodings: []string{"lzma"}}, wantErr: true},
+		{name: "invalid level", config: &config.CompressionConfig{Enabled: true, Levels: map[string]int{EncodingGzip: 42}}, wantErr: true},
+		{name: "level of an unsupported encoding", config: &config.CompressionConfig{Enabled: true, Levels: map[string]int{"lzma": 1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New().SetConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("SetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModule_NewResponseWriter(t *testing.T) {
+	body := strings.Repeat(`{"name":"space-cloud"}`, 100)
+
+	tests := []struct {
+		name         string
+		config       *config.CompressionConfig
+		header       http.Header
+		path         string
+		handler      http.HandlerFunc
+		wantEncoding string
+		wantBody     string
+	}{
+		{
+			name:         "gzip",
+			header:       http.Header{"Accept-Encoding": []string{"gzip"}},
+			handler:      jsonHandler(body),
+			wantEncoding: EncodingGzip,
+			wantBody:     body,
+		},
+		{
+			name:         "brotli",
+			header:       http.Header{"Accept-Encoding": []string{"gzip, br"}},
+			handler:      jsonHandler(body),
+			wantEncoding: EncodingBrotli,
+			wantBody:     body,
+		},
+		{
+			name:         "zstd",
+			header:       http.Header{"Accept-Encoding": []string{"gzip, br, zstd"}},
+			handler:      jsonHandler(body),
+			wantEncoding: EncodingZstd,
+			wantBody:     body,
+		},
+		{
+			name:     "response below the min size",
+			header:   http.Header{"Accept-Encoding": []string{"gzip"}},
+			handler:  jsonHandler(`{"ack":true}`),
+			wantBody: `{"ack":true}`,
+		},
+		{
+			name:     "excluded path",
+			config:   &config.CompressionConfig{Enabled: true, ExcludePaths: []string{"/v1/api/project/files"}},
+			header:   http.Header{"Accept-Encoding": []string{"gzip"}},
+			path:     "/v1/api/project/files/report.json",
+			handler:  jsonHandler(body),
+			wantBody: body,
+		},
+		{
+			name:   "already compressed content",
+			header: http.Header{"Accept-Encoding": []string{"gzip"}},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(body))
+			},
+			wantBody: body,
+		},
+		{
+			name:   "no transform",
+			header: http.Header{"Accept-Encoding": []string{"gzip"}},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-transform")
+				_, _ = w.Write([]byte(body))
+			},
+			wantBody: body,
+		},
+		{
+			name:   "flushed stream",
+			header: http.Header{"Accept-Encoding": []string{"gzip"}},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("data: 1\n\n"))
+				w.(http.Flusher).Flush()
+				_, _ = w.Write([]byte(body))
+			},
+			wantBody: "data: 1\n\n" + body,
+		},
+		{
+			name:     "range request",
+			header:   http.Header{"Accept-Encoding": []string{"gzip"}, "Range": []string{"bytes=0-10"}},
+			handler:  jsonHandler(body),
+			wantBody: body,
+		},
+		{
+			name:     "compression disabled",
+			config:   &config.CompressionConfig{},
+			header:   http.Header{"Accept-Encoding": []string{"gzip"}},
+			handler:  jsonHandler(body),
+			wantBody: body,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			if c == nil {
+				c = &config.CompressionConfig{Enabled: true}
+			}
+			m := New()
+			if err := m.SetConfig(c); err != nil {
+				t.Fatalf("SetConfig() error = %v", err)
+			}
+
+			path := tt.path
+			if path == "" {
+				path = "/v1/api/project/graphql"
+			}
+			r := httptest.NewRequest(http.MethodPost, path, nil)
+			r.Header = tt.header
+			rec := httptest.NewRecorder()
+
+			if cw, ok := m.NewResponseWriter(rec, r); ok {
+				tt.handler(cw, r)
+				if err := cw.Close(); err != nil {
+					t.Fatalf("Close() error = %v", err)
+				}
+			} else {
+				tt.handler(rec, r)
+			}
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %v, want %v", got, tt.wantEncoding)
+			}
+			if tt.wantEncoding != "" && rec.Header().Get("Content-Length") != "" {
+				t.Errorf("Content-Length of the compressed response wasn't removed")
+			}
+			if got := decode(t, tt.wantEncoding, rec.Body.Bytes()); got != tt.wantBody {
+				t.Errorf("body = %v, want %v", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func jsonHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func decode(t *testing.T, encoding string, data []byte) string {
+	var (
+		b   []byte
+		err error
+	)
+	switch encoding {
+	case EncodingGzip:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			b, err = ioutil.ReadAll(r)
+		}
+	case EncodingBrotli:
+		b, err = ioutil.ReadAll(brotli.NewReader(bytes.NewReader(data)))
+	case EncodingZstd:
+		var r *zstd.Decoder
+		if r, err = zstd.NewReader(bytes.NewReader(data)); err == nil {
+			b, err = ioutil.ReadAll(r)
+			r.Close()
+		}
+	default:
+		b = data
+	}
+	if err != nil {
+		t.Fatalf("unable to decode %s body - %v", encoding, err)
+	}
+	return string(b)
+}
@@ -0,0 +1,75 @@
+package compression
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The encodings supported out of the box
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+)
+
+// Encoder compresses the data written to it. Flush writes out the data compressed so far.
+type Encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// EncoderFactory creates an encoder writing to w. A level of 0 stands for the default level of the encoding.
+type EncoderFactory func(w io.Writer, level int) (Encoder, error)
+
+var (
+	encodersLock sync.RWMutex
+	encoders     = map[string]EncoderFactory{
+		EncodingGzip:   newGzipEncoder,
+		EncodingBrotli: newBrotliEncoder,
+		EncodingZstd:   newZstdEncoder,
+	}
+)
+
+// RegisterEncoder makes an encoding available to the compression middleware. The encoding is the token used for
+// it in the Accept-Encoding and Content-Encoding headers.
+func RegisterEncoder(encoding string, factory EncoderFactory) {
+	encodersLock.Lock()
+	defer encodersLock.Unlock()
+	encoders[encoding] = factory
+}
+
+func getEncoder(encoding string) (EncoderFactory, bool) {
+	encodersLock.RLock()
+	defer encodersLock.RUnlock()
+	factory, ok := encoders[encoding]
+	return factory, ok
+}
+
+func newGzipEncoder(w io.Writer, level int) (Encoder, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+func newBrotliEncoder(w io.Writer, level int) (Encoder, error) {
+	if level == 0 {
+		level = brotli.DefaultCompression
+	}
+	if level < brotli.BestSpeed || level > brotli.BestCompression {
+		return nil, fmt.Errorf("invalid brotli compression level (%d) provided", level)
+	}
+	return brotli.NewWriterLevel(w, level), nil
+}
+
+func newZstdEncoder(w io.Writer, level int) (Encoder, error) {
+	if level == 0 {
+		return zstd.NewWriter(w)
+	}
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+}
@@ -0,0 +1,177 @@
+package compression
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// incompressibleTypes are the prefixes of the content types which are either compressed already or streamed
+var incompressibleTypes = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip", "application/zstd", "application/x-bzip2",
+	"application/x-7z-compressed", "application/x-rar-compressed", "application/x-xz",
+	"text/event-stream",
+}
+
+// ResponseWriter compresses the response written to it. The response is buffered until it reaches the min size, so
+// that small responses are written as is. Streamed responses which get flushed before reaching the min size aren't
+// compressed either.
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	encoding string
+	level    int
+	factory  EncoderFactory
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     Encoder
+}
+
+// WriteHeader records the status code. The header is written once it is known whether the response gets compressed.
+func (w *ResponseWriter) WriteHeader(status int) {
+	// Informational responses don't end the response
+	if w.decided || status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+
+	w.status = status
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		_ = w.decide(false)
+	}
+}
+
+// Write buffers the response until it is known whether it gets compressed
+func (w *ResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minSize || !w.compressible() {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes out the response compressed so far
+func (w *ResponseWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the underlying connection over to the caller
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer doesn't support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Close writes out the rest of the response
+func (w *ResponseWriter) Close() error {
+	if !w.decided {
+		// Nothing has been written by the handler
+		if w.status == 0 {
+			return nil
+		}
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+	return nil
+}
+
+// decide writes the header along with the buffered response. The response is compressed only if it is asked for and
+// the response is compressible.
+func (w *ResponseWriter) decide(compress bool) error {
+	w.decided = true
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// Sniff the content type before the body gets compressed
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if w.compressible() {
+		header.Add("Vary", "Accept-Encoding")
+		if compress && len(w.buf) >= w.minSize {
+			enc, err := w.factory(w.ResponseWriter, w.level)
+			if err == nil {
+				w.enc = enc
+				header.Set("Content-Encoding", w.encoding)
+				header.Del("Content-Length")
+			}
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.enc != nil {
+		_, err := w.enc.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// compressible returns whether the response may be compressed judging by its header
+func (w *ResponseWriter) compressible() bool {
+	if w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	if strings.HasPrefix(contentType, "image/svg+xml") {
+		return true
+	}
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package diagnostics
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// recentPauses is the number of the most recent gc pauses reported in the runtime stats
+const recentPauses = 16
+
+// Module guards the runtime diagnostics of the gateway. The diagnostics can be toggled at runtime so that the
+// profiles of a gateway in production can be captured without restarting it with the profiler flag.
+type Module struct {
+	lock    sync.RWMutex
+	enabled bool
+}
+
+// New creates a new instance of the diagnostics module. The diagnostics are disabled until enabled in the config.
+func New() *Module {
+	return &Module{}
+}
+
+// SetConfig toggles the diagnostics and sets the sampling rates of the block and mutex profiles
+func (m *Module) SetConfig(c *config.DiagnosticsConfig) error {
+	if c != nil && (c.BlockProfileRate < 0 || c.MutexProfileFraction < 0) {
+		return fmt.Errorf("block profile rate and mutex profile fraction cannot be negative")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// Sampling the blocking events and mutexes has an overhead, hence they are only sampled while enabled
+	enabled := c != nil && c.Enabled
+	blockProfileRate, mutexProfileFraction := 0, 0
+	if enabled {
+		blockProfileRate, mutexProfileFraction = c.BlockProfileRate, c.MutexProfileFraction
+	}
+	runtime.SetBlockProfileRate(blockProfileRate)
+	runtime.SetMutexProfileFraction(mutexProfileFraction)
+
+	m.enabled = enabled
+	return nil
+}
+
+// IsEnabled returns true if the diagnostics have been enabled
+func (m *Module) IsEnabled() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.enabled
+}
+
+// GetRuntimeStats returns the memory and gc stats of the gateway
+func (m *Module) GetRuntimeStats() *model.RuntimeStats {
+	mem := new(runtime.MemStats)
+	runtime.ReadMemStats(mem)
+
+	gc := new(debug.GCStats)
+	debug.ReadGCStats(gc)
+	if len(gc.Pause) > recentPauses {
+		gc.Pause = gc.Pause[:recentPauses]
+	}
+
+	stats := &model.RuntimeStats{
+		GoVersion:     runtime.Version(),
+		NumCPU:        runtime.NumCPU(),
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapInuse:     mem.HeapInuse,
+		HeapIdle:      mem.HeapIdle,
+		HeapReleased:  mem.HeapReleased,
+		HeapObjects:   mem.HeapObjects,
+		StackInuse:    mem.StackInuse,
+		Sys:           mem.Sys,
+		TotalAlloc:    mem.TotalAlloc,
+		Mallocs:       mem.Mallocs,
+		Frees:         mem.Frees,
+		NumGC:         mem.NumGC,
+		NextGC:        mem.NextGC,
+		LastGC:        gc.LastGC,
+		PauseTotal:    gc.PauseTotal.String(),
+		GCCPUFraction: mem.GCCPUFraction,
+		RecentPauses:  make([]string, 0, len(gc.Pause)),
+	}
+	for _, pause := range gc.Pause {
+		stats.RecentPauses = append(stats.RecentPauses, pause.String())
+	}
+	return stats
+}
@@ -0,0 +1,58 @@
+package diagnostics
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestModule_SetConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *config.DiagnosticsConfig
+		wantErr     bool
+		wantEnabled bool
+	}{
+		{name: "no config", config: nil},
+		{name: "disabled", config: &config.DiagnosticsConfig{BlockProfileRate: 1}},
+		{name: "enabled", config: &config.DiagnosticsConfig{Enabled: true, BlockProfileRate: 1, MutexProfileFraction: 5}, wantEnabled: true},
+		{name: "negative block profile rate", config: &config.DiagnosticsConfig{Enabled: true, BlockProfileRate: -1}, wantErr: true},
+		{name: "negative mutex profile fraction", config: &config.DiagnosticsConfig{Enabled: true, MutexProfileFraction: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			if err := m.SetConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("SetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := m.IsEnabled(); got != tt.wantEnabled {
+				t.Errorf("IsEnabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			wantFraction := 0
+			if tt.wantEnabled {
+				wantFraction = tt.config.MutexProfileFraction
+			}
+			if got := runtime.SetMutexProfileFraction(-1); got != wantFraction {
+				t.Errorf("SetConfig() mutex profile fraction = %v, want %v", got, wantFraction)
+			}
+		})
+	}
+	_ = New().SetConfig(nil)
+}
+
+func TestModule_GetRuntimeStats(t *testing.T) {
+	runtime.GC()
+
+	stats := New().GetRuntimeStats()
+	if stats.GoVersion != runtime.Version() || stats.Goroutines == 0 || stats.HeapAlloc == 0 || stats.Sys == 0 {
+		t.Errorf("GetRuntimeStats() = %+v, want the stats of the runtime", stats)
+	}
+	if stats.NumGC == 0 || stats.LastGC.IsZero() || len(stats.RecentPauses) == 0 || len(stats.RecentPauses) > recentPauses {
+		t.Errorf("GetRuntimeStats() = %+v, want the stats of the gc", stats)
+	}
+}
@@ -1,11 +1,20 @@
 package global
 
 import (
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/accounting"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/admission"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/caching"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/compression"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/diagnostics"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/idempotency"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/letsencrypt"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/logging"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/metrics"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/operations"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global/routing"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
 )
 
 // Global holds global modules
@@ -14,6 +23,14 @@ type Global struct {
 	metrics     *metrics.Module
 	routing     *routing.Routing
 	caching     *caching.Cache
+	logging     *logging.Logger
+	operations  *operations.Registry
+	secrets     *secrets.Manager
+	accounting  *accounting.Module
+	idempotency *idempotency.Module
+	admission   *admission.Module
+	compression *compression.Module
+	diagnostics *diagnostics.Module
 }
 
 // New creates a new global object
@@ -38,7 +55,16 @@ func New(clusterID, nodeID string, isDev bool, managers *managers.Managers) (*Gl
 	c.SetAdminModule(managers.Admin())
 	r.SetCachingModule(c)
 
-	return &Global{letsencrypt: le, metrics: m, routing: r, caching: c}, nil
+	// Initialise the structured request logger
+	l := logging.New(nodeID, clusterID)
+
+	// Initialise the module deduplicating retried requests
+	i, err := idempotency.New(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Global{letsencrypt: le, metrics: m, routing: r, caching: c, logging: l, operations: operations.New(), secrets: secrets.New(), accounting: accounting.New(clusterID, nodeID), idempotency: i, admission: admission.New(), compression: compression.New(), diagnostics: diagnostics.New()}, nil
 }
 
 // LetsEncrypt returns the letsencrypt module
@@ -60,3 +86,78 @@ func (g *Global) Routing() *routing.Routing {
 func (g *Global) Caching() *caching.Cache {
 	return g.caching
 }
+
+// Logging returns the structured request logger
+func (g *Global) Logging() *logging.Logger {
+	return g.logging
+}
+
+// Operations returns the registry of the operations in flight
+func (g *Global) Operations() *operations.Registry {
+	return g.operations
+}
+
+// Secrets returns the manager resolving secrets from external secret managers
+func (g *Global) Secrets() *secrets.Manager {
+	return g.secrets
+}
+
+// Accounting returns the module tracking the resource usage of every project
+func (g *Global) Accounting() *accounting.Module {
+	return g.accounting
+}
+
+// Idempotency returns the module deduplicating retried requests
+func (g *Global) Idempotency() *idempotency.Module {
+	return g.idempotency
+}
+
+// Admission returns the module shedding the low priority traffic under overload
+func (g *Global) Admission() *admission.Module {
+	return g.admission
+}
+
+// Compression returns the module compressing the responses
+func (g *Global) Compression() *compression.Module {
+	return g.compression
+}
+
+// Diagnostics returns the module guarding the runtime diagnostics
+func (g *Global) Diagnostics() *diagnostics.Module {
+	return g.diagnostics
+}
+
+// SetMetricsConfig sets the config of the metrics module
+func (g *Global) SetMetricsConfig(isMetricsEnabled bool) {
+	g.metrics.SetMetricsConfig(isMetricsEnabled)
+}
+
+// SetLoggingConfig sets the config of the structured request logger
+func (g *Global) SetLoggingConfig(c *config.LoggingConfig) error {
+	return g.logging.SetConfig(c)
+}
+
+// SetAccountingConfig sets the config of the resource usage export
+func (g *Global) SetAccountingConfig(c *config.AccountingConfig) error {
+	return g.accounting.SetConfig(c)
+}
+
+// SetAdmissionConfig sets the config of the admission control
+func (g *Global) SetAdmissionConfig(c *config.AdmissionConfig) error {
+	return g.admission.SetConfig(c)
+}
+
+// SetCompressionConfig sets the config of the response compression
+func (g *Global) SetCompressionConfig(c *config.CompressionConfig) error {
+	return g.compression.SetConfig(c)
+}
+
+// SetMetricsSinksConfig sets the config of the sinks the metrics are pushed to
+func (g *Global) SetMetricsSinksConfig(c *config.MetricsConfig) error {
+	return g.metrics.SetSinksConfig(c)
+}
+
+// SetDiagnosticsConfig sets the config of the runtime diagnostics
+func (g *Global) SetDiagnosticsConfig(c *config.DiagnosticsConfig) error {
+	return g.diagnostics.SetConfig(c)
+}
@@ -0,0 +1,201 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+const (
+	// defaultTTL is the window in which retried requests are deduplicated
+	defaultTTL = 24 * time.Hour
+
+	// processingTTL bounds how long a request in flight blocks its retries if the node processing it dies
+	processingTTL = 5 * time.Minute
+
+	// maxKeyLength is the maximum length of an idempotency key
+	maxKeyLength = 255
+)
+
+const (
+	statusProcessing = "processing"
+	statusCompleted  = "completed"
+)
+
+// store is the subset of the redis client used to save the responses
+type store interface {
+	SetKeyIfNotExists(ctx context.Context, key, value string, t time.Duration) (bool, error)
+	SetKey(ctx context.Context, key, value string, t time.Duration) error
+	GetKey(ctx context.Context, key string) (string, error)
+	DeleteKey(ctx context.Context, key string) error
+}
+
+var _ store = (*pubsub.Module)(nil)
+
+// record is the state of a request saved against its idempotency key
+type record struct {
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Module deduplicates retried requests carrying the same idempotency key and replays the original response
+type Module struct {
+	clusterID string
+	store     store
+	ttl       time.Duration
+}
+
+// New creates a new instance of the idempotency module backed by redis
+func New(clusterID string) (*Module, error) {
+	client, err := pubsub.New("idempotency", os.Getenv("REDIS_CONN"))
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to initialize the idempotency module, ensure that redis database is running", err, nil)
+	}
+	return newModule(clusterID, client, defaultTTL), nil
+}
+
+func newModule(clusterID string, s store, ttl time.Duration) *Module {
+	return &Module{clusterID: clusterID, store: s, ttl: ttl}
+}
+
+// ServeHTTP serves the request with the next handler unless a request with the same idempotency key was already
+// served, in which case the original response is replayed. Requests which failed with a server error aren't
+// saved so that they can be retried.
+func (m *Module) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	ctx := r.Context()
+
+	idempotencyKey := r.Header.Get(utils.HeaderIdempotencyKey)
+	if len(idempotencyKey) > maxKeyLength {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("idempotency key cannot be longer than %d characters", maxKeyLength))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+	key := m.getKey(utils.GetTokenFromHeader(r), idempotencyKey)
+	fingerprint := getFingerprint(r, body)
+
+	data, _ := json.Marshal(record{Status: statusProcessing, Fingerprint: fingerprint})
+	acquired, err := m.store.SetKeyIfNotExists(ctx, key, string(data), processingTTL)
+	if err != nil {
+		// Requests are served without deduplication if redis isn't reachable
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to check idempotency key", err, nil)
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	if !acquired {
+		m.replay(ctx, w, key, fingerprint)
+		return
+	}
+
+	rec := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	next.ServeHTTP(rec, r)
+
+	// Server errors are usually transient. Free the key so that the request can be retried.
+	if rec.statusCode >= http.StatusInternalServerError {
+		if err := m.store.DeleteKey(context.Background(), key); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to release idempotency key", err, nil)
+		}
+		return
+	}
+
+	data, _ = json.Marshal(record{Status: statusCompleted, Fingerprint: fingerprint, StatusCode: rec.statusCode, ContentType: w.Header().Get("Content-Type"), Body: rec.body.Bytes()})
+	if err := m.store.SetKey(context.Background(), key, string(data), m.ttl); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to save response of idempotent request", err, nil)
+	}
+}
+
+func (m *Module) replay(ctx context.Context, w http.ResponseWriter, key, fingerprint string) {
+	value, err := m.store.GetKey(ctx, key)
+	if err == redis.Nil {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusConflict, utils.NewError(model.ErrorCodeRequestInProgress, errors.New("request with the same idempotency key was just completed - retry the request")))
+		return
+	}
+	if err != nil {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to get response of idempotent request", err, nil))
+		return
+	}
+
+	rec := new(record)
+	if err := json.Unmarshal([]byte(value), rec); err != nil {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid response saved for idempotent request", err, nil))
+		return
+	}
+
+	if rec.Fingerprint != fingerprint {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusUnprocessableEntity, utils.NewError(model.ErrorCodeIdempotencyKeyReused, errors.New("idempotency key has already been used for a different request")))
+		return
+	}
+
+	if rec.Status == statusProcessing {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusConflict, utils.NewError(model.ErrorCodeRequestInProgress, errors.New("request with the same idempotency key is being processed")))
+		return
+	}
+
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set(utils.HeaderIdempotentReplayed, "true")
+	w.WriteHeader(rec.StatusCode)
+	_, _ = w.Write(rec.Body)
+}
+
+// getKey scopes the idempotency key to the credentials of the caller so that the responses of one caller are
+// never replayed to another
+func (m *Module) getKey(token, idempotencyKey string) string {
+	h := sha256.Sum256([]byte(token + "::" + idempotencyKey))
+	return fmt.Sprintf("%s::idempotency::%s", m.clusterID, hex.EncodeToString(h[:]))
+}
+
+func getFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder captures the response written by the handler while passing it on
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(buf []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(buf)
+	return r.ResponseWriter.Write(buf)
+}
@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type fakeStore struct {
+	lock sync.Mutex
+	keys map[string]string
+}
+
+func (s *fakeStore) SetKeyIfNotExists(ctx context.Context, key, value string, t time.Duration) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, p := s.keys[key]; p {
+		return false, nil
+	}
+	s.keys[key] = value
+	return true, nil
+}
+
+func (s *fakeStore) SetKey(ctx context.Context, key, value string, t time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.keys[key] = value
+	return nil
+}
+
+func (s *fakeStore) GetKey(ctx context.Context, key string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	value, p := s.keys[key]
+	if !p {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (s *fakeStore) DeleteKey(ctx context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+type request struct {
+	key   string
+	token string
+	body  string
+}
+
+func TestModule_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCodes  []int
+		requests     []request
+		wantStatus   []int
+		wantReplayed []bool
+		wantCalls    int
+	}{
+		{
+			name:         "Retried request is replayed",
+			statusCodes:  []int{http.StatusOK},
+			requests:     []request{{key: "k1", token: "t1", body: `{"a":1}`}, {key: "k1", token: "t1", body: `{"a":1}`}},
+			wantStatus:   []int{http.StatusOK, http.StatusOK},
+			wantReplayed: []bool{false, true},
+			wantCalls:    1,
+		},
+		{
+			name:         "Client errors are replayed",
+			statusCodes:  []int{http.StatusBadRequest},
+			requests:     []request{{key: "k1", token: "t1", body: `{}`}, {key: "k1", token: "t1", body: `{}`}},
+			wantStatus:   []int{http.StatusBadRequest, http.StatusBadRequest},
+			wantReplayed: []bool{false, true},
+			wantCalls:    1,
+		},
+		{
+			name:         "Server errors can be retried",
+			statusCodes:  []int{http.StatusInternalServerError, http.StatusOK},
+			requests:     []request{{key: "k1", token: "t1", body: `{}`}, {key: "k1", token: "t1", body: `{}`}},
+			wantStatus:   []int{http.StatusInternalServerError, http.StatusOK},
+			wantReplayed: []bool{false, false},
+			wantCalls:    2,
+		},
+		{
+			name:         "Key reused with a different body",
+			statusCodes:  []int{http.StatusOK},
+			requests:     []request{{key: "k1", token: "t1", body: `{"a":1}`}, {key: "k1", token: "t1", body: `{"a":2}`}},
+			wantStatus:   []int{http.StatusOK, http.StatusUnprocessableEntity},
+			wantReplayed: []bool{false, false},
+			wantCalls:    1,
+		},
+		{
+			name:         "Keys are scoped to the caller",
+			statusCodes:  []int{http.StatusOK, http.StatusOK},
+			requests:     []request{{key: "k1", token: "t1", body: `{}`}, {key: "k1", token: "t2", body: `{}`}},
+			wantStatus:   []int{http.StatusOK, http.StatusOK},
+			wantReplayed: []bool{false, false},
+			wantCalls:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModule("chicago", &fakeStore{keys: map[string]string{}}, time.Minute)
+
+			var calls int
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statusCodes[calls]
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"calls":` + string(rune('0'+calls)) + `}`))
+			})
+
+			var firstBody string
+			for i, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodPost, "/v1/api/myproject/crud/db/users/create", strings.NewReader(req.body))
+				r.Header.Set(utils.HeaderIdempotencyKey, req.key)
+				r.Header.Set("Authorization", "Bearer "+req.token)
+				w := httptest.NewRecorder()
+
+				m.ServeHTTP(w, r, next)
+
+				if w.Code != tt.wantStatus[i] {
+					t.Errorf("ServeHTTP() request %d status = %v, want %v", i, w.Code, tt.wantStatus[i])
+				}
+				replayed := w.Header().Get(utils.HeaderIdempotentReplayed) == "true"
+				if replayed != tt.wantReplayed[i] {
+					t.Errorf("ServeHTTP() request %d replayed = %v, want %v", i, replayed, tt.wantReplayed[i])
+				}
+				if i == 0 {
+					firstBody = w.Body.String()
+				} else if replayed && w.Body.String() != firstBody {
+					t.Errorf("ServeHTTP() replayed body = %v, want %v", w.Body.String(), firstBody)
+				}
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("ServeHTTP() handler calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// Level is the severity of a log entry
+type Level int
+
+// The supported log levels
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the name of the log level
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+func parseLevel(level string) Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// The types of log entries
+const (
+	TypeAccess = "access"
+	TypeError  = "error"
+)
+
+// Entry is a structured log entry of a request served by the gateway
+type Entry struct {
+	Time       time.Time              `json:"ts"`
+	Level      string                 `json:"level"`
+	Type       string                 `json:"type"`
+	RequestID  string                 `json:"requestId"`
+	TraceID    string                 `json:"traceId,omitempty"`
+	Project    string                 `json:"project,omitempty"`
+	Module     string                 `json:"module,omitempty"`
+	Method     string                 `json:"method"`
+	Path       string                 `json:"path"`
+	Query      map[string]interface{} `json:"query,omitempty"`
+	Status     int                    `json:"status"`
+	DurationMS float64                `json:"durationMs"`
+	Bytes      uint64                 `json:"bytes"`
+	RemoteAddr string                 `json:"remoteAddr,omitempty"`
+	UserAgent  string                 `json:"userAgent,omitempty"`
+	Body       interface{}            `json:"body,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	// DeniedRules are the paths of the security rules which denied the request
+	DeniedRules []string `json:"deniedRules,omitempty"`
+}
+
+// Logger writes structured access and error logs to the configured sinks
+type Logger struct {
+	lock sync.RWMutex
+
+	nodeID, clusterID string
+
+	enabled       bool
+	level         Level
+	projectLevels map[string]Level
+	sampleRate    float64
+	logDenials    bool
+	redactor      *redactor
+	sinks         []sink
+
+	randLock sync.Mutex
+	random   *rand.Rand
+}
+
+// New creates a new instance of the logging module. Nothing gets logged till the module is enabled in the cluster config.
+func New(nodeID, clusterID string) *Logger {
+	return &Logger{nodeID: nodeID, clusterID: clusterID, sampleRate: 1, redactor: newRedactor(nil), random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// SetConfig applies the logging config. The sinks of the previous config are flushed and closed.
+func (l *Logger) SetConfig(c *config.LoggingConfig) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.closeSinks()
+
+	if c == nil || !c.Enabled {
+		l.enabled = false
+		return nil
+	}
+
+	sinks := make([]sink, 0, len(c.Sinks))
+	for _, sinkConfig := range c.Sinks {
+		s, err := newSink(sinkConfig, l.nodeID, l.clusterID)
+		if err != nil {
+			for _, s := range sinks {
+				_ = s.Close()
+			}
+			l.enabled = false
+			return err
+		}
+		sinks = append(sinks, s)
+	}
+
+	// Log to stdout if no sinks have been configured
+	if len(sinks) == 0 {
+		sinks = append(sinks, newStdoutSink())
+	}
+
+	projectLevels := make(map[string]Level, len(c.ProjectLevels))
+	for project, level := range c.ProjectLevels {
+		projectLevels[project] = parseLevel(level)
+	}
+
+	l.sampleRate = 1
+	if c.SampleRate != nil {
+		l.sampleRate = *c.SampleRate
+	}
+
+	l.enabled = true
+	l.level = parseLevel(c.Level)
+	l.logDenials = c.LogRuleDenials
+	l.projectLevels = projectLevels
+	l.redactor = newRedactor(c.RedactFields)
+	l.sinks = sinks
+	return nil
+}
+
+// Close flushes and closes all the sinks
+func (l *Logger) Close() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.closeSinks()
+	l.enabled = false
+}
+
+func (l *Logger) closeSinks() {
+	for _, s := range l.sinks {
+		if err := s.Close(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to close log sink", err, nil)
+		}
+	}
+	l.sinks = nil
+}
+
+// IsEnabled returns true if a request of the provided project with the provided level would be logged
+func (l *Logger) IsEnabled(project string, level Level) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.enabled && level >= l.getLevel(project)
+}
+
+// LogsRuleDenials returns true if the requests denied by the security rules need to be logged along with the failing rules
+func (l *Logger) LogsRuleDenials() bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.enabled && l.logDenials
+}
+
+// IsDebug returns true if the bodies of the requests of the provided project need to be logged
+func (l *Logger) IsDebug(project string) bool {
+	return l.IsEnabled(project, LevelDebug)
+}
+
+func (l *Logger) getLevel(project string) Level {
+	if level, p := l.projectLevels[project]; p {
+		return level
+	}
+	return l.level
+}
+
+// LevelForStatus returns the level at which a request with the provided status code gets logged
+func LevelForStatus(status int) Level {
+	switch {
+	case status >= 500:
+		return LevelError
+	case status >= 400:
+		return LevelWarn
+	default:
+		return LevelInfo
+	}
+}
+
+// Log writes the entry to all sinks after redacting it. Successful requests are sampled as per the sample rate.
+func (l *Logger) Log(entry *Entry) {
+	level := LevelForStatus(entry.Status)
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if !l.enabled {
+		return
+	}
+
+	// Denials are logged irrespective of the level when asked to
+	denied := l.logDenials && len(entry.DeniedRules) > 0
+	if level < l.getLevel(entry.Project) && !denied {
+		return
+	}
+
+	if level < LevelWarn && !l.sample() {
+		return
+	}
+
+	entry.Level = level.String()
+	entry.Type = TypeAccess
+	if level >= LevelWarn {
+		entry.Type = TypeError
+	}
+	entry.Query = l.redactor.redactMap(entry.Query)
+	entry.Body = l.redactor.redact(entry.Body)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		_ = helpers.Logger.LogError(entry.RequestID, "Unable to marshal log entry", err, nil)
+		return
+	}
+
+	// Limit the capacity so that sinks appending to the data never share the backing array
+	data = data[:len(data):len(data)]
+	for _, s := range l.sinks {
+		s.Write(entry, data)
+	}
+}
+
+func (l *Logger) sample() bool {
+	if l.sampleRate >= 1 {
+		return true
+	}
+	if l.sampleRate <= 0 {
+		return false
+	}
+
+	l.randLock.Lock()
+	defer l.randLock.Unlock()
+	return l.random.Float64() < l.sampleRate
+}
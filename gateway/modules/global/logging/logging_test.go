@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// memorySink collects the entries written to it
+type memorySink struct {
+	lines []string
+}
+
+func (s *memorySink) Write(_ *Entry, data []byte) { s.lines = append(s.lines, string(data)) }
+func (s *memorySink) Close() error                { return nil }
+
+func TestRedactor(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra []string
+		value interface{}
+		want  interface{}
+	}{
+		{
+			name:  "default fields are redacted irrespective of case and separators",
+			value: map[string]interface{}{"Password": "abc", "access_token": "xyz", "name": "john"},
+			want:  map[string]interface{}{"Password": redactedValue, "access_token": redactedValue, "name": "john"},
+		},
+		{
+			name:  "nested objects and arrays are redacted",
+			value: map[string]interface{}{"users": []interface{}{map[string]interface{}{"secret": 1, "id": 2}}},
+			want:  map[string]interface{}{"users": []interface{}{map[string]interface{}{"secret": redactedValue, "id": 2}}},
+		},
+		{
+			name:  "extra fields are redacted",
+			extra: []string{"email"},
+			value: map[string]interface{}{"email": "john@example.com", "age": 20},
+			want:  map[string]interface{}{"email": redactedValue, "age": 20},
+		},
+		{
+			name:  "primitives are left untouched",
+			value: "password",
+			want:  "password",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newRedactor(tt.extra).redact(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_Log(t *testing.T) {
+	zero := float64(0)
+	tests := []struct {
+		name    string
+		config  *config.LoggingConfig
+		entry   *Entry
+		want    bool
+		level   string
+		logType string
+	}{
+		{name: "logging disabled", config: &config.LoggingConfig{}, entry: &Entry{Status: 500}, want: false},
+		{name: "successful request at info level", config: &config.LoggingConfig{Enabled: true}, entry: &Entry{Status: 200}, want: true, level: "info", logType: TypeAccess},
+		{name: "successful request at warn level", config: &config.LoggingConfig{Enabled: true, Level: "warn"}, entry: &Entry{Status: 200}, want: false},
+		{name: "client error at warn level", config: &config.LoggingConfig{Enabled: true, Level: "warn"}, entry: &Entry{Status: 404}, want: true, level: "warn", logType: TypeError},
+		{name: "project level overrides the global level", config: &config.LoggingConfig{Enabled: true, Level: "error", ProjectLevels: map[string]string{"myproject": "info"}}, entry: &Entry{Project: "myproject", Status: 200}, want: true, level: "info", logType: TypeAccess},
+		{name: "successful requests are sampled", config: &config.LoggingConfig{Enabled: true, SampleRate: &zero}, entry: &Entry{Status: 200}, want: false},
+		{name: "errors are never sampled", config: &config.LoggingConfig{Enabled: true, SampleRate: &zero}, entry: &Entry{Status: 500}, want: true, level: "error", logType: TypeError},
+		{name: "denials are logged irrespective of the level", config: &config.LoggingConfig{Enabled: true, Level: "error", LogRuleDenials: true}, entry: &Entry{Status: 403, DeniedRules: []string{"rule.clause"}}, want: true, level: "warn", logType: TypeError},
+		{name: "denials follow the level when not asked to", config: &config.LoggingConfig{Enabled: true, Level: "error"}, entry: &Entry{Status: 403, DeniedRules: []string{"rule.clause"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New("node", "cluster")
+			if err := l.SetConfig(tt.config); err != nil {
+				t.Fatalf("SetConfig() error = %v", err)
+			}
+			s := new(memorySink)
+			if len(l.sinks) > 0 {
+				l.sinks = []sink{s}
+			}
+
+			l.Log(tt.entry)
+			if got := len(s.lines) == 1; got != tt.want {
+				t.Fatalf("Log() logged = %v, want %v", got, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+
+			entry := new(Entry)
+			if err := json.Unmarshal([]byte(s.lines[0]), entry); err != nil {
+				t.Fatalf("Log() wrote invalid json: %v", err)
+			}
+			if entry.Level != tt.level || entry.Type != tt.logType {
+				t.Errorf("Log() level = %v type = %v, want level = %v type = %v", entry.Level, entry.Type, tt.level, tt.logType)
+			}
+		})
+	}
+}
+
+func TestFileSink_Rotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "access.log")
+	s, err := newFileSink(path, 1, 2)
+	if err != nil {
+		t.Fatalf("newFileSink() error = %v", err)
+	}
+	s.maxSize = 10
+
+	for _, line := range []string{"first", "second", "third", "fourth"} {
+		s.Write(&Entry{}, []byte(line))
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: path, want: "fourth"},
+		{file: path + ".1", want: "third"},
+		{file: path + ".2", want: "second"},
+	}
+	for _, tt := range tests {
+		data, err := ioutil.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("Unable to read %s: %v", tt.file, err)
+		}
+		if got := strings.TrimSpace(string(data)); got != tt.want {
+			t.Errorf("File %s = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("File %s exists, want only %d backups", path+".3", 2)
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import "strings"
+
+const redactedValue = "[REDACTED]"
+
+// defaultRedactFields are the fields which commonly carry secrets or personally identifiable information
+var defaultRedactFields = []string{
+	"password", "pass", "passwd", "secret", "token", "accesstoken", "refreshtoken", "authorization", "cookie",
+	"x-sc-token", "apikey", "api_key", "accesskey", "secretkey", "privatekey", "ssn", "creditcard", "cardnumber", "cvv",
+}
+
+// redactor masks the values of sensitive fields in log entries
+type redactor struct {
+	fields map[string]struct{}
+}
+
+func newRedactor(extra []string) *redactor {
+	fields := make(map[string]struct{}, len(defaultRedactFields)+len(extra))
+	for _, f := range defaultRedactFields {
+		fields[normaliseField(f)] = struct{}{}
+	}
+	for _, f := range extra {
+		fields[normaliseField(f)] = struct{}{}
+	}
+	return &redactor{fields: fields}
+}
+
+// normaliseField makes the comparison insensitive to case and to the common word separators
+func normaliseField(field string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(field))
+}
+
+func (r *redactor) isSensitive(field string) bool {
+	_, p := r.fields[normaliseField(field)]
+	return p
+}
+
+// redact returns a copy of the value with the sensitive fields of all nested objects masked
+func (r *redactor) redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return r.redactMap(v)
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = r.redact(item)
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+func (r *redactor) redactMap(obj map[string]interface{}) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+
+	newObj := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if r.isSensitive(k) {
+			newObj[k] = redactedValue
+			continue
+		}
+		newObj[k] = r.redact(v)
+	}
+	return newObj
+}
@@ -0,0 +1,361 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// The supported sink types
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkHTTP   = "http"
+	SinkLoki   = "loki"
+)
+
+const (
+	defaultMaxSizeMB   = 100
+	defaultMaxBackups  = 5
+	pushQueueSize      = 4096
+	pushBatchSize      = 500
+	pushFlushInterval  = 2 * time.Second
+	pushRequestTimeout = 10 * time.Second
+)
+
+// sink is a destination of the structured logs
+type sink interface {
+	// Write writes the json encoded entry. It must never block the request being logged.
+	Write(entry *Entry, data []byte)
+	Close() error
+}
+
+func newSink(c *config.LogSink, nodeID, clusterID string) (sink, error) {
+	switch c.Type {
+	case SinkStdout, "":
+		return newStdoutSink(), nil
+	case SinkFile:
+		return newFileSink(c.Path, c.MaxSizeMB, c.MaxBackups)
+	case SinkHTTP, SinkLoki:
+		if c.URL == "" {
+			return nil, fmt.Errorf("url is required for the %s log sink", c.Type)
+		}
+		return newPushSink(c.Type, c.URL, c.Headers, nodeID, clusterID), nil
+	default:
+		return nil, fmt.Errorf("invalid log sink type (%s) provided", c.Type)
+	}
+}
+
+// writerSink writes one entry per line to a writer
+type writerSink struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func newStdoutSink() *writerSink {
+	return &writerSink{w: os.Stdout}
+}
+
+func (s *writerSink) Write(_ *Entry, data []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, _ = s.w.Write(append(data, '\n'))
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
+
+// fileSink writes to a file which gets rotated once it exceeds the max size. Rotated files are
+// suffixed with .1, .2 and so on, with .1 being the most recent.
+type fileSink struct {
+	lock sync.Mutex
+
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+func newFileSink(path string, maxSizeMB, maxBackups int) (*fileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required for the %s log sink", SinkFile)
+	}
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultMaxSizeMB
+	}
+	if maxBackups <= 0 {
+		maxBackups = defaultMaxBackups
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	s := &fileSink{path: path, maxSize: int64(maxSizeMB) * 1024 * 1024, maxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	// Shift the existing backups. The oldest one gets overwritten.
+	for i := s.maxBackups - 1; i > 0; i-- {
+		from := s.path + "." + strconv.Itoa(i)
+		if _, err := os.Stat(from); err == nil {
+			_ = os.Rename(from, s.path+"."+strconv.Itoa(i+1))
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+func (s *fileSink) Write(entry *Entry, data []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return
+	}
+
+	data = append(data, '\n')
+	if s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			_ = helpers.Logger.LogError(entry.RequestID, fmt.Sprintf("Unable to rotate log file (%s)", s.path), err, nil)
+			if s.file == nil {
+				return
+			}
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	if err != nil {
+		_ = helpers.Logger.LogError(entry.RequestID, fmt.Sprintf("Unable to write to log file (%s)", s.path), err, nil)
+	}
+}
+
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+type pushEntry struct {
+	entry *Entry
+	data  []byte
+}
+
+// pushSink pushes the entries in batches to a http endpoint. The http type posts newline delimited json
+// while the loki type uses the loki push api.
+type pushSink struct {
+	sinkType string
+	url      string
+	headers  map[string]string
+	labels   map[string]string
+	client   *http.Client
+
+	entries chan pushEntry
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newPushSink(sinkType, url string, headers map[string]string, nodeID, clusterID string) *pushSink {
+	if sinkType == SinkLoki {
+		url = strings.TrimSuffix(url, "/") + "/loki/api/v1/push"
+	}
+
+	s := &pushSink{
+		sinkType: sinkType,
+		url:      url,
+		headers:  headers,
+		labels:   map[string]string{"job": "space-cloud", "cluster": clusterID, "node": nodeID},
+		client:   &http.Client{Timeout: pushRequestTimeout},
+		entries:  make(chan pushEntry, pushQueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go s.routineFlush()
+	return s
+}
+
+// Write queues the entry. Entries are dropped if the queue is full.
+func (s *pushSink) Write(entry *Entry, data []byte) {
+	select {
+	case <-s.done:
+	case s.entries <- pushEntry{entry: entry, data: data}:
+	default:
+	}
+}
+
+// Close pushes the pending entries and stops the flush routine
+func (s *pushSink) Close() error {
+	close(s.done)
+	<-s.stopped
+	return nil
+}
+
+func (s *pushSink) routineFlush() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(pushFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pushEntry, 0, pushBatchSize)
+	for {
+		select {
+		case e := <-s.entries:
+			batch = append(batch, e)
+			if len(batch) < pushBatchSize {
+				continue
+			}
+
+		case <-ticker.C:
+
+		case <-s.done:
+		drain:
+			for {
+				select {
+				case e := <-s.entries:
+					batch = append(batch, e)
+				default:
+					break drain
+				}
+			}
+			s.push(batch)
+			return
+		}
+
+		s.push(batch)
+		batch = batch[:0]
+	}
+}
+
+func (s *pushSink) push(batch []pushEntry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var body []byte
+	contentType := "application/x-ndjson"
+	if s.sinkType == SinkLoki {
+		data, err := json.Marshal(s.encodeLoki(batch))
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to marshal loki push request", err, nil)
+			return
+		}
+		body, contentType = data, "application/json"
+	} else {
+		buf := new(bytes.Buffer)
+		for _, e := range batch {
+			buf.Write(e.data)
+			buf.WriteByte('\n')
+		}
+		body = buf.Bytes()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create request for pushing logs", err, nil)
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to push logs to (%s)", s.url), err, nil)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to push logs to (%s)", s.url), fmt.Errorf("log sink responded with status code %d", resp.StatusCode), nil)
+	}
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encodeLoki groups the entries into streams labelled by project, level and type
+func (s *pushSink) encodeLoki(batch []pushEntry) lokiPushRequest {
+	streams := map[string]*lokiStream{}
+	keys := make([]string, 0)
+	for _, e := range batch {
+		key := e.entry.Project + "/" + e.entry.Level + "/" + e.entry.Type
+		stream, p := streams[key]
+		if !p {
+			labels := map[string]string{"level": e.entry.Level, "type": e.entry.Type}
+			if e.entry.Project != "" {
+				labels["project"] = e.entry.Project
+			}
+			for k, v := range s.labels {
+				labels[k] = v
+			}
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(e.entry.Time.UnixNano(), 10), string(e.data)})
+	}
+
+	req := lokiPushRequest{Streams: make([]lokiStream, len(keys))}
+	for i, key := range keys {
+		req.Streams[i] = *streams[key]
+	}
+	return req
+}
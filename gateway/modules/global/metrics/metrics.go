@@ -6,8 +6,10 @@ import (
 	api "github.com/spaceuptech/space-api-go"
 	"github.com/spaceuptech/space-api-go/db"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
 	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
 )
 
 // Module struct for metrics
@@ -19,11 +21,24 @@ type Module struct {
 	clusterID string
 	nodeID    string
 	projects  sync.Map // key -> project; value -> *metrics
+	requests  sync.Map // key -> requestKey; value -> *requestStats
 	// Variables to store the configuration
 	isMetricDisabled bool
 	// Variables to interact with the sink
 	sink *db.DB
 
+	// Variables to push the metrics to the monitoring backends
+	pushLock   sync.Mutex
+	pushConfig *config.MetricsConfig
+	sinks      []Sink
+	gauges     func() []Gauge
+	// pushed is the value of every counter at the time of the last push. Key here is the id of the series
+	pushed  map[string]float64
+	done    chan struct{}
+	stopped chan struct{}
+	// wal persists the counters till they have been pushed so that they survive a crash or an unavailable sink
+	wal *wal.WAL
+
 	// Global modules
 	adminMan *admin.Manager
 	syncMan  *syncman.Manager
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets are the upper bounds (in seconds) of the request latency histogram
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Gauge is a point in time value exported on the prometheus endpoint
+type Gauge struct {
+	Name   string
+	Help   string
+	Labels map[string]string
+	Value  float64
+}
+
+// labelEscaper escapes label values as required by the prometheus text format. Unlike %q, it leaves every other
+// character (tabs and non ascii ones included) as it is.
+var labelEscaper = strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`)
+
+// helpEscaper escapes the help text of metric families
+var helpEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`)
+
+type requestKey struct {
+	project, module string
+}
+
+type requestStats struct {
+	lock sync.Mutex
+
+	codes   map[int]uint64
+	errors  uint64
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func newRequestStats() *requestStats {
+	return &requestStats{codes: map[int]uint64{}, buckets: make([]uint64, len(latencyBuckets))}
+}
+
+// AddRequest records a request served by a module of a project. Unlike the other metric hooks, request stats
+// are always collected since they are exported locally on the prometheus endpoint and never leave the cluster.
+func (m *Module) AddRequest(project, module string, status int, duration time.Duration) {
+	value, _ := m.requests.LoadOrStore(requestKey{project: project, module: module}, newRequestStats())
+	stats := value.(*requestStats)
+
+	seconds := duration.Seconds()
+
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	stats.codes[status]++
+	if status >= 500 {
+		atomic.AddUint64(&stats.errors, 1)
+	}
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+	stats.count++
+	stats.sum += seconds
+}
+
+// WritePrometheusMetrics writes the request stats along with the provided gauges in the prometheus text format
+func (m *Module) WritePrometheusMetrics(w io.Writer, gauges []Gauge) error {
+	keys := m.getRequestKeys()
+
+	b := new(strings.Builder)
+
+	writeHeader(b, "space_cloud_requests_total", "Total number of requests served per project and module", "counter")
+	for _, key := range keys {
+		stats := m.loadRequestStats(key)
+		stats.lock.Lock()
+		codes := make([]int, 0, len(stats.codes))
+		for code := range stats.codes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			writeSample(b, "space_cloud_requests_total", map[string]string{"project": key.project, "module": key.module, "code": strconv.Itoa(code)}, float64(stats.codes[code]))
+		}
+		stats.lock.Unlock()
+	}
+
+	writeHeader(b, "space_cloud_request_errors_total", "Total number of requests which resulted in a server error per project and module", "counter")
+	for _, key := range keys {
+		stats := m.loadRequestStats(key)
+		writeSample(b, "space_cloud_request_errors_total", map[string]string{"project": key.project, "module": key.module}, float64(atomic.LoadUint64(&stats.errors)))
+	}
+
+	writeHeader(b, "space_cloud_request_duration_seconds", "Latency of requests served per project and module", "histogram")
+	for _, key := range keys {
+		stats := m.loadRequestStats(key)
+		stats.lock.Lock()
+		for i, bound := range latencyBuckets {
+			writeSample(b, "space_cloud_request_duration_seconds_bucket", map[string]string{"project": key.project, "module": key.module, "le": strconv.FormatFloat(bound, 'g', -1, 64)}, float64(stats.buckets[i]))
+		}
+		writeSample(b, "space_cloud_request_duration_seconds_bucket", map[string]string{"project": key.project, "module": key.module, "le": "+Inf"}, float64(stats.count))
+		writeSample(b, "space_cloud_request_duration_seconds_sum", map[string]string{"project": key.project, "module": key.module}, stats.sum)
+		writeSample(b, "space_cloud_request_duration_seconds_count", map[string]string{"project": key.project, "module": key.module}, float64(stats.count))
+		stats.lock.Unlock()
+	}
+
+	// Samples of a metric family need to be contiguous, so gauges are grouped by name in the order they first appear
+	names := make([]string, 0)
+	families := map[string][]Gauge{}
+	for _, g := range gauges {
+		if _, p := families[g.Name]; !p {
+			names = append(names, g.Name)
+		}
+		families[g.Name] = append(families[g.Name], g)
+	}
+	for _, name := range names {
+		family := families[name]
+		writeHeader(b, name, family[0].Help, "gauge")
+		for _, g := range family {
+			writeSample(b, name, g.Labels, g.Value)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// getRequestKeys returns the keys of the request stats sorted by project and module
+func (m *Module) getRequestKeys() []requestKey {
+	keys := make([]requestKey, 0)
+	m.requests.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key.(requestKey))
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].project == keys[j].project {
+			return keys[i].module < keys[j].module
+		}
+		return keys[i].project < keys[j].project
+	})
+	return keys
+}
+
+func (m *Module) loadRequestStats(key requestKey) *requestStats {
+	value, _ := m.requests.Load(key)
+	return value.(*requestStats)
+}
+
+func writeHeader(b *strings.Builder, name, help, metricType string) {
+	_, _ = fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, helpEscaper.Replace(help), name, metricType)
+}
+
+func writeSample(b *strings.Builder, name string, labels map[string]string, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = k + `="` + labelEscaper.Replace(labels[k]) + `"`
+		}
+		b.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	b.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModule_WritePrometheusMetrics(t *testing.T) {
+	m := &Module{}
+	m.AddRequest("myproject", "crud", 200, 20*time.Millisecond)
+	m.AddRequest("myproject", "crud", 500, 2*time.Second)
+	m.AddRequest("myproject", "functions", 200, time.Millisecond)
+
+	gauges := []Gauge{
+		{Name: "space_cloud_realtime_subscriptions", Help: "subs", Labels: map[string]string{"project": "p1"}, Value: 2},
+		{Name: "space_cloud_cluster_nodes", Help: "nodes", Value: 3},
+		{Name: "space_cloud_realtime_subscriptions", Help: "subs", Labels: map[string]string{"project": "p2"}, Value: 1},
+	}
+
+	b := new(strings.Builder)
+	if err := m.WritePrometheusMetrics(b, gauges); err != nil {
+		t.Fatalf("WritePrometheusMetrics() unexpected error = %v", err)
+	}
+	got := b.String()
+
+	wantLines := []string{
+		`# TYPE space_cloud_requests_total counter`,
+		`space_cloud_requests_total{code="200",module="crud",project="myproject"} 1`,
+		`space_cloud_requests_total{code="500",module="crud",project="myproject"} 1`,
+		`space_cloud_requests_total{code="200",module="functions",project="myproject"} 1`,
+		`space_cloud_request_errors_total{module="crud",project="myproject"} 1`,
+		`space_cloud_request_errors_total{module="functions",project="myproject"} 0`,
+		`space_cloud_request_duration_seconds_bucket{le="0.025",module="crud",project="myproject"} 1`,
+		`space_cloud_request_duration_seconds_bucket{le="2.5",module="crud",project="myproject"} 2`,
+		`space_cloud_request_duration_seconds_bucket{le="+Inf",module="crud",project="myproject"} 2`,
+		`space_cloud_request_duration_seconds_count{module="crud",project="myproject"} 2`,
+		`space_cloud_cluster_nodes 3`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("WritePrometheusMetrics() output does not contain %q\n%s", line, got)
+		}
+	}
+
+	// Samples of the same gauge must be contiguous
+	want := "# TYPE space_cloud_realtime_subscriptions gauge\nspace_cloud_realtime_subscriptions{project=\"p1\"} 2\nspace_cloud_realtime_subscriptions{project=\"p2\"} 1\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("WritePrometheusMetrics() gauges are not grouped\n%s", got)
+	}
+}
+
+func Test_writeSample(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{name: "no labels", want: "m 1\n"},
+		{name: "plain labels", labels: map[string]string{"b": "2", "a": "1"}, want: "m{a=\"1\",b=\"2\"} 1\n"},
+		{name: "newline, quote and backslash", labels: map[string]string{"a": "x\n\"y\"\\z"}, want: "m{a=\"x\\n\\\"y\\\"\\\\z\"} 1\n"},
+		{name: "tab and non ascii characters", labels: map[string]string{"a": "x\ty é"}, want: "m{a=\"x\ty é\"} 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := new(strings.Builder)
+			writeSample(b, "m", tt.labels, 1)
+			if got := b.String(); got != tt.want {
+				t.Errorf("writeSample() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
+)
+
+const (
+	// defaultPushInterval is used when the flush interval isn't set in the metrics config
+	defaultPushInterval = 10 * time.Second
+	// defaultPrefix is used when the prefix isn't set in the metrics config
+	defaultPrefix = "space_cloud"
+
+	pushTimeout = 10 * time.Second
+)
+
+// SetGaugesSource sets the function providing the gauges pushed along with the request stats
+func (m *Module) SetGaugesSource(fn func() []Gauge) {
+	m.pushLock.Lock()
+	defer m.pushLock.Unlock()
+	m.gauges = fn
+}
+
+// SetWAL sets the write ahead log the counters are persisted in till they have been pushed. The counters which
+// couldn't be pushed, including the ones left behind by the previous run, are pushed along with the next push.
+func (m *Module) SetWAL(w *wal.WAL) {
+	m.pushLock.Lock()
+	defer m.pushLock.Unlock()
+	m.wal = w
+}
+
+// SetSinksConfig applies the config of the sinks the metrics are pushed to. The pending metrics are pushed to the
+// previous sinks before they are closed.
+func (m *Module) SetSinksConfig(c *config.MetricsConfig) error {
+	m.stopPush()
+
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	sinks, err := newSinks(c)
+	if err != nil {
+		return err
+	}
+
+	interval := defaultPushInterval
+	if c.FlushInterval > 0 {
+		interval = time.Duration(c.FlushInterval) * time.Second
+	}
+
+	m.pushLock.Lock()
+	m.pushConfig = c
+	m.sinks = sinks
+	m.done = make(chan struct{})
+	m.stopped = make(chan struct{})
+	go m.routinePush(interval, m.done, m.stopped)
+	m.pushLock.Unlock()
+	return nil
+}
+
+// Close pushes the pending metrics and closes the sinks
+func (m *Module) Close() {
+	m.stopPush()
+}
+
+func (m *Module) stopPush() {
+	m.pushLock.Lock()
+	done, stopped := m.done, m.stopped
+	m.done, m.stopped = nil, nil
+	m.pushLock.Unlock()
+
+	if done == nil {
+		return
+	}
+	close(done)
+	<-stopped
+
+	m.pushLock.Lock()
+	sinks := m.sinks
+	m.sinks, m.pushConfig = nil, nil
+	m.pushLock.Unlock()
+
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to close metrics sink", err, nil)
+		}
+	}
+}
+
+func (m *Module) routinePush(interval time.Duration, done, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.push()
+		case <-done:
+			m.push()
+			return
+		}
+	}
+}
+
+// push sends the metrics to all sinks. A sink which is unavailable misses the increase of the counters since the
+// last push, so that the other sinks don't count it twice, unless the counters are persisted in the write ahead log.
+func (m *Module) push() {
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+
+	m.pushLock.Lock()
+	gaugesFn := m.gauges
+	m.pushLock.Unlock()
+
+	var gauges []Gauge
+	if gaugesFn != nil {
+		gauges = gaugesFn()
+	}
+
+	m.pushLock.Lock()
+	c, sinks, w := m.pushConfig, m.sinks, m.wal
+	if c == nil {
+		m.pushLock.Unlock()
+		return
+	}
+	samples := m.collectSamples(c, gauges)
+	m.pushLock.Unlock()
+
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	for i, sink := range sinks {
+		if w != nil {
+			pushWithWAL(ctx, w, getSinkQueue(c.Sinks[i]), prefix, sink, samples)
+			continue
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		if err := sink.Push(ctx, prefix, samples); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to push metrics to sink", err, nil)
+		}
+	}
+}
+
+// pushWithWAL appends the counters to the queue of the sink in the write ahead log and pushes the queued counters in
+// the order they were collected. The gauges are point in time values, hence they are pushed right away and never
+// queued. The queued counters are removed once pushed, so a sink which is unavailable gets them in a later push.
+func pushWithWAL(ctx context.Context, w *wal.WAL, queue, prefix string, sink Sink, samples []*Sample) {
+	counters, gauges := make([]*Sample, 0), make([]*Sample, 0)
+	for _, sample := range samples {
+		if sample.Kind == SampleCounter {
+			counters = append(counters, sample)
+		} else {
+			gauges = append(gauges, sample)
+		}
+	}
+
+	if len(counters) > 0 {
+		data, err := json.Marshal(counters)
+		if err == nil {
+			_, err = w.Append(ctx, queue, data)
+		}
+		if err != nil {
+			// The counters are pushed along with the gauges if they can't be persisted
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to persist metrics in the write ahead log", err, nil)
+			gauges = samples
+		}
+	}
+
+	if len(gauges) > 0 {
+		if err := sink.Push(ctx, prefix, gauges); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to push metrics to sink", err, nil)
+		}
+	}
+
+	entries, err := w.Entries(queue)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read metrics from the write ahead log", err, nil)
+		return
+	}
+	for _, entry := range entries {
+		queued := make([]*Sample, 0)
+		if err := json.Unmarshal(entry.Data, &queued); err == nil {
+			if err := sink.Push(ctx, prefix, queued); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to push metrics to sink", err, map[string]interface{}{"pending": len(entries)})
+				return
+			}
+		}
+		if err := w.Remove(queue, entry.ID); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to remove metrics from the write ahead log", err, nil)
+			return
+		}
+	}
+}
+
+// getSinkQueue returns the queue of the write ahead log holding the counters yet to be pushed to the sink
+func getSinkQueue(s *config.MetricsSink) string {
+	return strings.Join([]string{"metrics", s.Type, s.Address, s.Namespace, s.Region}, "::")
+}
+
+// collectSamples returns the increase of the request counters since the last push along with the average latency
+// of the requests and the gauges. It must be called with the push lock held.
+func (m *Module) collectSamples(c *config.MetricsConfig, gauges []Gauge) []*Sample {
+	if m.pushed == nil {
+		m.pushed = map[string]float64{}
+	}
+
+	samples := make([]*Sample, 0)
+	addCounter := func(name string, labels map[string]string, value float64) {
+		id := getSeriesID(name, labels)
+		delta := value - m.pushed[id]
+		m.pushed[id] = value
+		if delta > 0 {
+			samples = append(samples, &Sample{Name: name, Kind: SampleCounter, Value: delta, Tags: mapTags(c, labels)})
+		}
+	}
+
+	for _, key := range m.getRequestKeys() {
+		labels := map[string]string{"project": key.project, "module": key.module}
+		stats := m.loadRequestStats(key)
+
+		stats.lock.Lock()
+		codes := make([]int, 0, len(stats.codes))
+		for code := range stats.codes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			addCounter("requests", map[string]string{"project": key.project, "module": key.module, "code": strconv.Itoa(code)}, float64(stats.codes[code]))
+		}
+		count, sum := float64(stats.count), stats.sum
+		stats.lock.Unlock()
+
+		addCounter("request_errors", labels, float64(atomic.LoadUint64(&stats.errors)))
+
+		// The average latency over the period is pushed since the sinks can't ingest the buckets of a histogram
+		countID, sumID := getSeriesID("request_count", labels), getSeriesID("request_duration_sum", labels)
+		if deltaCount := count - m.pushed[countID]; deltaCount > 0 {
+			samples = append(samples, &Sample{Name: "request_duration_seconds", Kind: SampleGauge, Value: (sum - m.pushed[sumID]) / deltaCount, Tags: mapTags(c, labels)})
+		}
+		m.pushed[countID], m.pushed[sumID] = count, sum
+	}
+
+	for _, g := range gauges {
+		samples = append(samples, &Sample{Name: strings.TrimPrefix(g.Name, "space_cloud_"), Kind: SampleGauge, Value: g.Value, Tags: mapTags(c, g.Labels)})
+	}
+	return samples
+}
+
+// mapTags renames the labels as per the tag mapping and adds the tags of the config
+func mapTags(c *config.MetricsConfig, labels map[string]string) map[string]string {
+	tags := make(map[string]string, len(labels)+len(c.Tags))
+	for k, v := range c.Tags {
+		tags[k] = v
+	}
+	for k, v := range labels {
+		if mapped, p := c.TagMapping[k]; p {
+			if mapped == "" {
+				continue
+			}
+			k = mapped
+		}
+		tags[k] = v
+	}
+	return tags
+}
+
+func getSeriesID(name string, labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, k := range sortedTagKeys(labels) {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
+)
+
+func TestModule_collectSamples(t *testing.T) {
+	c := &config.MetricsConfig{Tags: map[string]string{"env": "prod"}, TagMapping: map[string]string{"project": "service", "db": ""}}
+	m := &Module{}
+	m.AddRequest("myproject", "crud", 200, 100*time.Millisecond)
+	m.AddRequest("myproject", "crud", 500, 300*time.Millisecond)
+
+	gauges := []Gauge{{Name: "space_cloud_db_pool_open_connections", Labels: map[string]string{"project": "myproject", "db": "postgres"}, Value: 4}}
+	want := []*Sample{
+		{Name: "requests", Kind: SampleCounter, Value: 1, Tags: map[string]string{"env": "prod", "service": "myproject", "module": "crud", "code": "200"}},
+		{Name: "requests", Kind: SampleCounter, Value: 1, Tags: map[string]string{"env": "prod", "service": "myproject", "module": "crud", "code": "500"}},
+		{Name: "request_errors", Kind: SampleCounter, Value: 1, Tags: map[string]string{"env": "prod", "service": "myproject", "module": "crud"}},
+		{Name: "request_duration_seconds", Kind: SampleGauge, Value: 0.2, Tags: map[string]string{"env": "prod", "service": "myproject", "module": "crud"}},
+		{Name: "db_pool_open_connections", Kind: SampleGauge, Value: 4, Tags: map[string]string{"env": "prod", "service": "myproject"}},
+	}
+	if got := m.collectSamples(c, gauges); !reflect.DeepEqual(got, want) {
+		t.Errorf("collectSamples() = %v, want %v", got, want)
+	}
+
+	// Only the increase since the last push is pushed
+	m.AddRequest("myproject", "crud", 200, 400*time.Millisecond)
+	want = []*Sample{
+		{Name: "requests", Kind: SampleCounter, Value: 1, Tags: map[string]string{"env": "prod", "service": "myproject", "module": "crud", "code": "200"}},
+		{Name: "request_duration_seconds", Kind: SampleGauge, Value: 0.4, Tags: map[string]string{"env": "prod", "service": "myproject", "module": "crud"}},
+	}
+	got := m.collectSamples(c, nil)
+	if len(got) != len(want) {
+		t.Fatalf("collectSamples() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].Name != want[i].Name || got[i].Value-want[i].Value > 1e-9 || want[i].Value-got[i].Value > 1e-9 || !reflect.DeepEqual(got[i].Tags, want[i].Tags) {
+			t.Errorf("collectSamples() [%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+type fakeSink struct {
+	down   bool
+	pushed [][]*Sample
+}
+
+func (s *fakeSink) Push(_ context.Context, _ string, samples []*Sample) error {
+	if s.down {
+		return errors.New("sink is unavailable")
+	}
+	s.pushed = append(s.pushed, samples)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	return nil
+}
+
+func Test_pushWithWAL(t *testing.T) {
+	w, err := wal.Open(filepath.Join(t.TempDir(), "wal.db"), 0)
+	if err != nil {
+		t.Fatalf("wal.Open() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	queue := getSinkQueue(&config.MetricsSink{Type: "statsd"})
+	counter1 := &Sample{Name: "requests", Kind: SampleCounter, Value: 1, Tags: map[string]string{}}
+	counter2 := &Sample{Name: "requests", Kind: SampleCounter, Value: 2, Tags: map[string]string{}}
+	gauge := &Sample{Name: "goroutines", Kind: SampleGauge, Value: 10, Tags: map[string]string{}}
+
+	// The counters are kept while the sink is unavailable
+	sink := &fakeSink{down: true}
+	pushWithWAL(context.Background(), w, queue, "space_cloud", sink, []*Sample{counter1, gauge})
+	if entries, _ := w.Entries(queue); len(entries) != 1 {
+		t.Fatalf("pushWithWAL() queued %d entries, want 1", len(entries))
+	}
+
+	// The queued counters are pushed in order once the sink is back. The stale gauges are never pushed.
+	sink.down = false
+	pushWithWAL(context.Background(), w, queue, "space_cloud", sink, []*Sample{counter2, gauge})
+	want := [][]*Sample{{gauge}, {counter1}, {counter2}}
+	if !reflect.DeepEqual(sink.pushed, want) {
+		t.Errorf("pushWithWAL() pushed = %v, want %v", sink.pushed, want)
+	}
+	if entries, _ := w.Entries(queue); len(entries) != 0 {
+		t.Errorf("pushWithWAL() left %d entries in the write ahead log", len(entries))
+	}
+}
@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// The kinds of samples pushed to the sinks
+const (
+	// SampleCounter is the increase of a counter since the last push
+	SampleCounter = "counter"
+	// SampleGauge is a point in time value
+	SampleGauge = "gauge"
+)
+
+const (
+	defaultStatsDAddress       = "localhost:8125"
+	defaultCloudWatchNamespace = "SpaceCloud"
+
+	// statsDPacketSize keeps the packets within the MTU of most networks
+	statsDPacketSize = 1432
+	// cloudWatchBatchSize is the maximum number of metrics cloudwatch accepts in a request
+	cloudWatchBatchSize = 20
+	// cloudWatchMaxDimensions is the maximum number of dimensions cloudwatch accepts for a metric
+	cloudWatchMaxDimensions = 30
+)
+
+// Sample is the value of a metric pushed to the sinks
+type Sample struct {
+	// Name is the name of the metric without the prefix
+	Name  string
+	Kind  string
+	Value float64
+	Tags  map[string]string
+}
+
+// Sink pushes the metrics of the gateway to a monitoring backend
+type Sink interface {
+	Push(ctx context.Context, prefix string, samples []*Sample) error
+	Close() error
+}
+
+func newSinks(c *config.MetricsConfig) ([]Sink, error) {
+	if len(c.Sinks) == 0 {
+		return nil, fmt.Errorf("at least one sink needs to be provided to push the metrics")
+	}
+
+	sinks := make([]Sink, 0, len(c.Sinks))
+	for _, s := range c.Sinks {
+		sink, err := newSink(s)
+		if err != nil {
+			for _, sink := range sinks {
+				_ = sink.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+func newSink(s *config.MetricsSink) (Sink, error) {
+	switch s.Type {
+	case "statsd", "dogstatsd":
+		address := s.Address
+		if address == "" {
+			address = defaultStatsDAddress
+		}
+		conn, err := net.Dial("udp", address)
+		if err != nil {
+			return nil, err
+		}
+		return &statsDSink{conn: conn, dogStatsD: s.Type == "dogstatsd"}, nil
+
+	case "cloudwatch":
+		sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+		if err != nil {
+			return nil, err
+		}
+		cfg := aws.NewConfig()
+		if s.Region != "" {
+			cfg = cfg.WithRegion(s.Region)
+		}
+		namespace := s.Namespace
+		if namespace == "" {
+			namespace = defaultCloudWatchNamespace
+		}
+		return &cloudWatchSink{client: cloudwatch.New(sess, cfg), namespace: namespace}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid metrics sink type (%s) provided - it must be one of statsd, dogstatsd or cloudwatch", s.Type)
+	}
+}
+
+// statsDSink pushes the metrics to a statsd agent over udp. DogStatsD carries the tags natively while plain statsd
+// gets them appended to the name of the metric as key.value segments.
+type statsDSink struct {
+	conn      net.Conn
+	dogStatsD bool
+}
+
+func (s *statsDSink) Push(_ context.Context, prefix string, samples []*Sample) error {
+	packet := new(strings.Builder)
+	for _, sample := range samples {
+		line := formatStatsD(prefix, sample, s.dogStatsD)
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > statsDPacketSize {
+			if _, err := s.conn.Write([]byte(packet.String())); err != nil {
+				return err
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteString("\n")
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() > 0 {
+		if _, err := s.conn.Write([]byte(packet.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *statsDSink) Close() error {
+	return s.conn.Close()
+}
+
+func formatStatsD(prefix string, sample *Sample, dogStatsD bool) string {
+	name := sample.Name
+	if prefix != "" {
+		name = prefix + "." + name
+	}
+
+	keys := sortedTagKeys(sample.Tags)
+	if !dogStatsD {
+		for _, k := range keys {
+			name += "." + sanitizeStatsD(k) + "." + sanitizeStatsD(sample.Tags[k])
+		}
+	}
+
+	metricType := "g"
+	if sample.Kind == SampleCounter {
+		metricType = "c"
+	}
+	line := fmt.Sprintf("%s:%s|%s", name, strconv.FormatFloat(sample.Value, 'f', -1, 64), metricType)
+
+	if dogStatsD && len(keys) > 0 {
+		tags := make([]string, len(keys))
+		for i, k := range keys {
+			tags[i] = sanitizeStatsD(k) + ":" + sanitizeStatsD(sample.Tags[k])
+		}
+		line += "|#" + strings.Join(tags, ",")
+	}
+	return line
+}
+
+// sanitizeStatsD replaces the characters having a meaning in the statsd protocol
+func sanitizeStatsD(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ':', '|', '@', ',', '#', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// cloudWatchSink pushes the metrics to aws cloudwatch with the tags as the dimensions. The prefix is left out of the
+// names of the metrics since the namespace serves the same purpose.
+type cloudWatchSink struct {
+	client    cloudwatchiface.CloudWatchAPI
+	namespace string
+}
+
+func (s *cloudWatchSink) Push(ctx context.Context, _ string, samples []*Sample) error {
+	now := time.Now()
+	data := make([]*cloudwatch.MetricDatum, 0, len(samples))
+	for _, sample := range samples {
+		datum := &cloudwatch.MetricDatum{
+			MetricName: aws.String(sample.Name),
+			Timestamp:  aws.Time(now),
+			Value:      aws.Float64(sample.Value),
+			Unit:       aws.String(getCloudWatchUnit(sample)),
+		}
+		for _, k := range sortedTagKeys(sample.Tags) {
+			if len(datum.Dimensions) == cloudWatchMaxDimensions {
+				break
+			}
+			datum.Dimensions = append(datum.Dimensions, &cloudwatch.Dimension{Name: aws.String(k), Value: aws.String(sample.Tags[k])})
+		}
+		data = append(data, datum)
+	}
+
+	for start := 0; start < len(data); start += cloudWatchBatchSize {
+		end := start + cloudWatchBatchSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := s.client.PutMetricDataWithContext(ctx, &cloudwatch.PutMetricDataInput{Namespace: aws.String(s.namespace), MetricData: data[start:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *cloudWatchSink) Close() error {
+	return nil
+}
+
+func getCloudWatchUnit(sample *Sample) string {
+	switch {
+	case strings.HasSuffix(sample.Name, "_seconds"):
+		return cloudwatch.StandardUnitSeconds
+	case sample.Kind == SampleCounter:
+		return cloudwatch.StandardUnitCount
+	default:
+		return cloudwatch.StandardUnitNone
+	}
+}
+
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
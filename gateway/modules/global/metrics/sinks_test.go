@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_formatStatsD(t *testing.T) {
+	sample := &Sample{Name: "requests", Kind: SampleCounter, Value: 3, Tags: map[string]string{"project": "my.project", "code": "200"}}
+	tests := []struct {
+		name      string
+		sample    *Sample
+		dogStatsD bool
+		want      string
+	}{
+		{name: "statsd counter", sample: sample, want: "space_cloud.requests.code.200.project.my_project:3|c"},
+		{name: "dogstatsd counter", sample: sample, dogStatsD: true, want: "space_cloud.requests:3|c|#code:200,project:my_project"},
+		{name: "gauge without tags", sample: &Sample{Name: "cluster_nodes", Kind: SampleGauge, Value: 0.5}, dogStatsD: true, want: "space_cloud.cluster_nodes:0.5|g"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatStatsD("space_cloud", tt.sample, tt.dogStatsD); got != tt.want {
+				t.Errorf("formatStatsD() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsDSink_Push(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen for udp packets: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	sink, err := newSink(&config.MetricsSink{Type: "dogstatsd", Address: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("newSink() error = %v", err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	// The samples are split into packets which fit in the mtu
+	samples := make([]*Sample, 100)
+	for i := range samples {
+		samples[i] = &Sample{Name: "requests", Kind: SampleCounter, Value: 1, Tags: map[string]string{"project": "myproject"}}
+	}
+	if err := sink.Push(context.Background(), "space_cloud", samples); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	lines := 0
+	buf := make([]byte, 65536)
+	for lines < len(samples) {
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Received %d lines, want %d: %v", lines, len(samples), err)
+		}
+		if n > statsDPacketSize {
+			t.Errorf("Push() sent packet of %d bytes, want at most %d", n, statsDPacketSize)
+		}
+		lines += len(strings.Split(string(buf[:n]), "\n"))
+	}
+}
+
+type mockCloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	inputs []*cloudwatch.PutMetricDataInput
+}
+
+func (m *mockCloudWatch) PutMetricDataWithContext(_ aws.Context, input *cloudwatch.PutMetricDataInput, _ ...request.Option) (*cloudwatch.PutMetricDataOutput, error) {
+	m.inputs = append(m.inputs, input)
+	return &cloudwatch.PutMetricDataOutput{}, nil
+}
+
+func TestCloudWatchSink_Push(t *testing.T) {
+	client := new(mockCloudWatch)
+	sink := &cloudWatchSink{client: client, namespace: "SpaceCloud"}
+
+	samples := make([]*Sample, 25)
+	for i := range samples {
+		samples[i] = &Sample{Name: "request_duration_seconds", Kind: SampleGauge, Value: 0.1, Tags: map[string]string{"project": "myproject", "module": "crud"}}
+	}
+	if err := sink.Push(context.Background(), "space_cloud", samples); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	if len(client.inputs) != 2 || len(client.inputs[0].MetricData) != cloudWatchBatchSize || len(client.inputs[1].MetricData) != 5 {
+		t.Fatalf("Push() sent %d requests, want the samples in batches of %d", len(client.inputs), cloudWatchBatchSize)
+	}
+	datum := client.inputs[0].MetricData[0]
+	if *datum.MetricName != "request_duration_seconds" || *datum.Unit != cloudwatch.StandardUnitSeconds || len(datum.Dimensions) != 2 || *datum.Dimensions[0].Name != "module" {
+		t.Errorf("Push() sent datum %v", datum)
+	}
+}
+
+func Test_newSinks(t *testing.T) {
+	if _, err := newSinks(&config.MetricsConfig{Enabled: true}); err == nil {
+		t.Errorf("newSinks() without sinks didn't return an error")
+	}
+	if _, err := newSinks(&config.MetricsConfig{Enabled: true, Sinks: []*config.MetricsSink{{Type: "statsd"}, {Type: "graphite"}}}); err == nil {
+		t.Errorf("newSinks() with invalid sink type didn't return an error")
+	}
+}
@@ -0,0 +1,109 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+)
+
+// The types of operations tracked by the registry
+const (
+	TypeQuery        = "query"
+	TypeFileTransfer = "file-transfer"
+	TypeRemoteCall   = "remote-call"
+)
+
+// Operation describes an operation in flight on this node
+type Operation struct {
+	ID         string    `json:"id"`
+	Project    string    `json:"project"`
+	Type       string    `json:"type"`
+	Module     string    `json:"module"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	RequestID  string    `json:"requestId,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMS float64   `json:"durationMs"`
+}
+
+type entry struct {
+	op     Operation
+	seq    uint64
+	cancel context.CancelFunc
+}
+
+// Registry tracks the operations in flight and allows them to be cancelled
+type Registry struct {
+	lock sync.RWMutex
+	ops  map[string]*entry
+	seq  uint64
+}
+
+// New creates a new operations registry
+func New() *Registry {
+	return &Registry{ops: map[string]*entry{}}
+}
+
+// Start registers an operation. The returned context gets cancelled when the operation is killed. The
+// returned function must be called once the operation completes.
+func (r *Registry) Start(ctx context.Context, op Operation) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	op.ID = ksuid.New().String()
+	op.StartedAt = time.Now()
+
+	r.lock.Lock()
+	r.seq++
+	r.ops[op.ID] = &entry{op: op, seq: r.seq, cancel: cancel}
+	r.lock.Unlock()
+
+	return ctx, func() {
+		r.lock.Lock()
+		delete(r.ops, op.ID)
+		r.lock.Unlock()
+		cancel()
+	}
+}
+
+// List returns the operations of a project in flight with the oldest one first
+func (r *Registry) List(project string) []*Operation {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	entries := make([]*entry, 0)
+	for _, e := range r.ops {
+		if e.op.Project == project {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+
+	now := time.Now()
+	arr := make([]*Operation, len(entries))
+	for i, e := range entries {
+		op := e.op
+		op.DurationMS = float64(now.Sub(op.StartedAt).Microseconds()) / 1000
+		arr[i] = &op
+	}
+	return arr
+}
+
+// Cancel kills an operation of a project. The cancellation propagates through the context of the operation.
+func (r *Registry) Cancel(ctx context.Context, project, id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e, p := r.ops[id]
+	if !p || e.op.Project != project {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Operation (%s) of project (%s) does not exist", id, project), nil, nil)
+	}
+
+	e.cancel()
+	delete(r.ops, id)
+	return nil
+}
@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := New()
+
+	ctx1, done1 := r.Start(context.Background(), Operation{Project: "project1", Type: TypeQuery, Module: "crud"})
+	_, done2 := r.Start(context.Background(), Operation{Project: "project1", Type: TypeRemoteCall, Module: "functions"})
+	_, done3 := r.Start(context.Background(), Operation{Project: "project2", Type: TypeFileTransfer, Module: "file"})
+	defer done2()
+	defer done3()
+
+	ops := r.List("project1")
+	if len(ops) != 2 || ops[0].Type != TypeQuery || ops[1].Type != TypeRemoteCall {
+		t.Fatalf("List() = %+v, want the query and the remote call of project1", ops)
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		id      string
+		wantErr bool
+	}{
+		{name: "operation of another project", project: "project2", id: ops[0].ID, wantErr: true},
+		{name: "unknown operation", project: "project1", id: "unknown", wantErr: true},
+		{name: "valid operation", project: "project1", id: ops[0].ID, wantErr: false},
+		{name: "operation already cancelled", project: "project1", id: ops[0].ID, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Cancel(context.Background(), tt.project, tt.id); (err != nil) != tt.wantErr {
+				t.Errorf("Cancel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if ctx1.Err() != context.Canceled {
+		t.Errorf("Cancel() did not cancel the context of the operation")
+	}
+	if got := r.List("project1"); len(got) != 1 || got[0].Type != TypeRemoteCall {
+		t.Errorf("List() after cancel = %+v, want only the remote call", got)
+	}
+
+	// Completing a cancelled operation must be a no-op
+	done1()
+	if got := len(r.List("project2")); got != 1 {
+		t.Errorf("List() = %d operations of project2, want 1", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spaceuptech/helpers"
 
@@ -29,6 +30,9 @@ var httpClient = http.Client{
 	},
 }
 
+// unhealthyCooldown is the duration for which a target which failed a request is skipped
+const unhealthyCooldown = 30 * time.Second
+
 // HandleRoutes handles incoming http requests and routes them according to the configured rules.
 func (r *Routing) HandleRoutes(modules modulesInterface) http.HandlerFunc {
 	return func(writer http.ResponseWriter, request *http.Request) {
@@ -41,15 +45,13 @@ func (r *Routing) HandleRoutes(modules modulesInterface) http.HandlerFunc {
 		// Select a route based on host and url
 		route, err := r.selectRoute(request.Context(), host, request.Method, url)
 		if err != nil {
-			writer.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(writer).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(request.Context(), writer, http.StatusBadRequest, err)
 			return
 		}
 
 		token, claims, status, err := r.modifyRequest(request.Context(), modules, route, request)
 		if err != nil {
-			writer.WriteHeader(status)
-			_ = json.NewEncoder(writer).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(request.Context(), writer, status, err)
 			return
 		}
 
@@ -61,9 +63,8 @@ func (r *Routing) HandleRoutes(modules modulesInterface) http.HandlerFunc {
 
 		// Proxy the request
 
-		if err := setRequest(request.Context(), request, route, url); err != nil {
-			writer.WriteHeader(http.StatusInternalServerError)
-			_ = json.NewEncoder(writer).Encode(map[string]string{"error": err.Error()})
+		if err := r.setRequest(request.Context(), request, route, url); err != nil {
+			_ = utils.SendErrorResponse(request.Context(), writer, http.StatusInternalServerError, err)
 			_ = helpers.Logger.LogError(helpers.GetRequestID(request.Context()), fmt.Sprintf("Failed set request for route (%v)", route), err, nil)
 			return
 		}
@@ -74,7 +75,7 @@ func (r *Routing) HandleRoutes(modules modulesInterface) http.HandlerFunc {
 			for _, key := range route.CacheOptions {
 				value, err := utils.LoadValue(key, map[string]interface{}{"args": map[string]interface{}{"auth": claims, "token": token, "url": request.URL.String()}})
 				if err != nil {
-					_ = helpers.Response.SendErrorResponse(request.Context(), writer, http.StatusBadRequest, err)
+					_ = utils.SendErrorResponse(request.Context(), writer, http.StatusBadRequest, err)
 					return
 				}
 				cacheOptionsArray = append(cacheOptionsArray, value)
@@ -82,7 +83,7 @@ func (r *Routing) HandleRoutes(modules modulesInterface) http.HandlerFunc {
 
 			key, isCacheHit, result, err := r.caching.GetIngressRoute(request.Context(), route.ID, cacheOptionsArray)
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(request.Context(), writer, http.StatusBadRequest, err)
+				_ = utils.SendErrorResponse(request.Context(), writer, http.StatusBadRequest, err)
 				return
 			}
 			if isCacheHit {
@@ -102,17 +103,18 @@ func (r *Routing) HandleRoutes(modules modulesInterface) http.HandlerFunc {
 
 		// TODO: Use http2 client if that was the incoming request protocol
 		response, err := httpClient.Do(request)
+		if err != nil || isTargetFailure(response.StatusCode) {
+			r.markTargetFailed(request.URL.Host)
+		}
 		if err != nil {
-			writer.WriteHeader(http.StatusInternalServerError)
-			_ = json.NewEncoder(writer).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(request.Context(), writer, http.StatusInternalServerError, utils.NewError(model.ErrorCodeUpstream, err))
 			_ = helpers.Logger.LogError(helpers.GetRequestID(request.Context()), fmt.Sprintf("Failed to make request for route (%v)", route), err, nil)
 			return
 		}
 		defer utils.CloseTheCloser(response.Body)
 
 		if err := r.modifyResponse(request.Context(), response, route, token, claims); err != nil {
-			writer.WriteHeader(http.StatusInternalServerError)
-			_ = json.NewEncoder(writer).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(request.Context(), writer, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -180,13 +182,13 @@ func rewriteURL(url string, route *config.Route) string {
 	return url
 }
 
-func setRequest(ctx context.Context, request *http.Request, route *config.Route, url string) error {
+func (r *Routing) setRequest(ctx context.Context, request *http.Request, route *config.Route, url string) error {
 	// http: Request.RequestURI can't be set in client requests.
 	// http://golang.org/src/pkg/net/http/client.go
 	request.RequestURI = ""
 
-	// Change the request with the destination host, port and url
-	target, err := route.SelectTarget(ctx, -1) // pass a -ve weight to randomly generate
+	// Change the request with the destination host, port and url. The targets in the zone of this node are preferred.
+	target, err := route.SelectZoneTarget(ctx, r.getZone(), -1, r.isTargetHealthy) // pass a -ve weight to randomly generate
 	if err != nil {
 		return err
 	}
@@ -224,3 +226,41 @@ func prepareHeaders(headers config.Headers, state map[string]interface{}) config
 	}
 	return out
 }
+
+func (r *Routing) getZone() string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if r.zoneSource == nil {
+		return ""
+	}
+	return r.zoneSource()
+}
+
+// isTargetHealthy checks if the target hasn't failed a request recently
+func (r *Routing) isTargetHealthy(target config.RouteTarget) bool {
+	r.lockTargets.Lock()
+	defer r.lockTargets.Unlock()
+
+	addr := fmt.Sprintf("%s:%d", target.Host, target.Port)
+	until, ok := r.unhealthy[addr]
+	if !ok {
+		return true
+	}
+	if time.Now().Before(until) {
+		return false
+	}
+	delete(r.unhealthy, addr)
+	return true
+}
+
+// markTargetFailed skips the target for a while since a request to it failed
+func (r *Routing) markTargetFailed(addr string) {
+	r.lockTargets.Lock()
+	defer r.lockTargets.Unlock()
+	r.unhealthy[addr] = time.Now().Add(unhealthyCooldown)
+}
+
+// isTargetFailure returns true if the target responded that it is unable to serve requests
+func isTargetFailure(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
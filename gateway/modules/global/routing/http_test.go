@@ -123,7 +123,7 @@ func Test_setRequest(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_ = setRequest(context.Background(), tt.args.request, tt.args.route, tt.args.url)
+			_ = New().setRequest(context.Background(), tt.args.request, tt.args.route, tt.args.url)
 			if !reflect.DeepEqual(tt.args.request, tt.want) {
 				t.Errorf("Routing.addProjectRoutes(): wanted - %v; got - %v", tt.want, tt.args.request)
 
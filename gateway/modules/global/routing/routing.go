@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
@@ -17,11 +18,17 @@ type Routing struct {
 	globalConfig *config.GlobalRoutesConfig
 	caching      cachingInterface
 	goTemplates  map[string]*template.Template
+
+	// zoneSource returns the zone of this node. The targets of the routes in the same zone are preferred.
+	zoneSource func() string
+
+	lockTargets sync.Mutex
+	unhealthy   map[string]time.Time // key is the address of the target
 }
 
 // New creates a new instance of the routing module
 func New() *Routing {
-	return &Routing{routes: make(config.Routes, 0), goTemplates: map[string]*template.Template{}, globalConfig: new(config.GlobalRoutesConfig)}
+	return &Routing{routes: make(config.Routes, 0), goTemplates: map[string]*template.Template{}, globalConfig: new(config.GlobalRoutesConfig), unhealthy: map[string]time.Time{}}
 }
 
 // SetCachingModule sets caching module
@@ -29,6 +36,13 @@ func (r *Routing) SetCachingModule(c cachingInterface) {
 	r.caching = c
 }
 
+// SetZoneSource sets the function which returns the zone of this node
+func (r *Routing) SetZoneSource(fn func() string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.zoneSource = fn
+}
+
 type cachingInterface interface {
 	SetIngressRouteKey(ctx context.Context, redisKey string, cache *config.ReadCacheOptions, result *model.CacheIngressRoute) error
 	GetIngressRoute(ctx context.Context, routeID string, cacheOptions []interface{}) (string, bool, *model.CacheIngressRoute, error)
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 )
@@ -22,6 +23,7 @@ func TestNew(t *testing.T) {
 				routes:       make(config.Routes, 0),
 				goTemplates:  map[string]*template.Template{},
 				globalConfig: new(config.GlobalRoutesConfig),
+				unhealthy:    map[string]time.Time{},
 			},
 		},
 	}
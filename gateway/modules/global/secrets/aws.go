@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// awsProvider reads secrets from aws secrets manager. The credentials and region are picked up from the
+// default credential chain of the aws sdk.
+type awsProvider struct {
+	lock   sync.Mutex
+	client *secretsmanager.SecretsManager
+}
+
+func newAWSProvider() *awsProvider {
+	return &awsProvider{}
+}
+
+func (a *awsProvider) getClient() (*secretsmanager.SecretsManager, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.client == nil {
+		sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+		if err != nil {
+			return nil, err
+		}
+		a.client = secretsmanager.New(sess)
+	}
+	return a.client, nil
+}
+
+// fetch gets the secret with the provided id. Secrets storing json objects are split into their keys while
+// plain text secrets are returned against an empty key.
+func (a *awsProvider) fetch(ctx context.Context, id string) (map[string]string, time.Duration, error) {
+	client, err := a.getClient()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	out, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value := aws.StringValue(out.SecretString)
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &obj); err != nil {
+		return map[string]string{"": value}, 0, nil
+	}
+
+	values := make(map[string]string, len(obj))
+	for k, v := range obj {
+		values[k] = toString(v)
+	}
+	return values, 0, nil
+}
@@ -0,0 +1,221 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+)
+
+// The supported secret managers. Secrets are referenced as <scheme>://<path>#<key>.
+const (
+	SchemeVault = "vault"
+	SchemeAWS   = "aws-sm"
+)
+
+const (
+	// defaultTTL is the time for which secrets without a lease are cached
+	defaultTTL = 5 * time.Minute
+
+	// refreshInterval is the interval at which the expired secrets get re-fetched
+	refreshInterval = 30 * time.Second
+)
+
+// provider fetches a secret from a secret manager. It returns the key value pairs stored in the secret
+// along with the duration for which they are valid. A zero duration indicates that the secret has no lease.
+type provider interface {
+	fetch(ctx context.Context, path string) (map[string]string, time.Duration, error)
+}
+
+type cachedSecret struct {
+	data      map[string]string
+	expiresAt time.Time
+}
+
+// Manager resolves references to secrets stored in external secret managers. Secrets are cached till their lease
+// expires after which they get re-fetched in the background.
+type Manager struct {
+	lock sync.RWMutex
+
+	providers map[string]provider
+	cache     map[string]*cachedSecret // key is <scheme>://<path>
+
+	// rotationHook is invoked when the value of a cached secret changes on re-fetching it
+	rotationHook func()
+}
+
+// New creates a new secrets manager
+func New() *Manager {
+	m := &Manager{
+		providers: map[string]provider{SchemeVault: newVaultProvider(), SchemeAWS: newAWSProvider()},
+		cache:     map[string]*cachedSecret{},
+	}
+	go m.routineRefresh()
+	return m
+}
+
+// SetRotationHook sets the function to be invoked when a secret gets rotated in the secret manager
+func (m *Manager) SetRotationHook(hook func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rotationHook = hook
+}
+
+// IsReference returns true if the value refers to a secret in an external secret manager
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, SchemeVault+"://") || strings.HasPrefix(value, SchemeAWS+"://")
+}
+
+// parseReference splits a reference of the form <scheme>://<path>#<key>
+func parseReference(ref string) (scheme, path, key string, err error) {
+	arr := strings.SplitN(ref, "://", 2)
+	if len(arr) != 2 || arr[1] == "" {
+		return "", "", "", fmt.Errorf("invalid secret reference (%s) provided", ref)
+	}
+	scheme = arr[0]
+
+	path = arr[1]
+	if index := strings.LastIndex(path, "#"); index != -1 {
+		path, key = path[:index], path[index+1:]
+	}
+	if path == "" {
+		return "", "", "", fmt.Errorf("invalid secret reference (%s) provided", ref)
+	}
+	return scheme, path, key, nil
+}
+
+// Resolve returns the value of the referenced secret
+func (m *Manager) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, path, key, err := parseReference(ref)
+	if err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to resolve secret", err, nil)
+	}
+
+	data, err := m.getSecret(ctx, scheme, path)
+	if err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to fetch secret (%s://%s)", scheme, path), err, nil)
+	}
+
+	if key == "" {
+		// The key can be skipped for secrets holding a single value
+		if len(data) == 1 {
+			for _, v := range data {
+				return v, nil
+			}
+		}
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Secret (%s://%s) holds multiple values, specify the key to be used as %s://%s#<key>", scheme, path, scheme, path), nil, nil)
+	}
+
+	value, p := data[key]
+	if !p {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Key (%s) does not exist in secret (%s://%s)", key, scheme, path), nil, nil)
+	}
+	return value, nil
+}
+
+func (m *Manager) getSecret(ctx context.Context, scheme, path string) (map[string]string, error) {
+	cacheKey := scheme + "://" + path
+
+	m.lock.RLock()
+	cached, p := m.cache[cacheKey]
+	m.lock.RUnlock()
+	if p && time.Now().Before(cached.expiresAt) {
+		return cached.data, nil
+	}
+
+	data, err := m.fetch(ctx, scheme, path)
+	if err != nil {
+		// Keep using the stale value if the secret manager is unreachable
+		if p {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to re-fetch secret (%s), using the cached value", cacheKey), err, nil)
+			return cached.data, nil
+		}
+		return nil, err
+	}
+	return data.data, nil
+}
+
+// fetch gets the secret from the secret manager and caches it
+func (m *Manager) fetch(ctx context.Context, scheme, path string) (*cachedSecret, error) {
+	m.lock.RLock()
+	pr, p := m.providers[scheme]
+	m.lock.RUnlock()
+	if !p {
+		return nil, fmt.Errorf("secret manager (%s) is not supported", scheme)
+	}
+
+	data, ttl, err := pr.fetch(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	secret := &cachedSecret{data: data, expiresAt: time.Now().Add(ttl)}
+	m.lock.Lock()
+	m.cache[scheme+"://"+path] = secret
+	m.lock.Unlock()
+	return secret, nil
+}
+
+func (m *Manager) routineRefresh() {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.refresh()
+	}
+}
+
+// refresh re-fetches the expired secrets and invokes the rotation hook if any of them have changed
+func (m *Manager) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshInterval)
+	defer cancel()
+
+	m.lock.RLock()
+	expired := map[string]*cachedSecret{}
+	now := time.Now()
+	for key, secret := range m.cache {
+		if !now.Before(secret.expiresAt) {
+			expired[key] = secret
+		}
+	}
+	m.lock.RUnlock()
+
+	rotated := false
+	for key, old := range expired {
+		arr := strings.SplitN(key, "://", 2)
+		secret, err := m.fetch(ctx, arr[0], arr[1])
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to re-fetch secret (%s)", key), err, nil)
+			continue
+		}
+		if !isEqual(old.data, secret.data) {
+			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Secret (%s) has been rotated", key), nil)
+			rotated = true
+		}
+	}
+
+	m.lock.RLock()
+	hook := m.rotationHook
+	m.lock.RUnlock()
+	if rotated && hook != nil {
+		hook()
+	}
+}
+
+func isEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if value, p := b[k]; !p || value != v {
+			return false
+		}
+	}
+	return true
+}
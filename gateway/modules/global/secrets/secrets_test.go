@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name              string
+		ref               string
+		scheme, path, key string
+		wantErr           bool
+	}{
+		{name: "vault reference with key", ref: "vault://secret/data/db#password", scheme: SchemeVault, path: "secret/data/db", key: "password"},
+		{name: "aws reference without key", ref: "aws-sm://prod/jwt", scheme: SchemeAWS, path: "prod/jwt"},
+		{name: "path with hash", ref: "vault://secret/a#b#c", scheme: SchemeVault, path: "secret/a#b", key: "c"},
+		{name: "missing path", ref: "vault://#password", wantErr: true},
+		{name: "missing scheme", ref: "secret/data/db", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, path, key, err := parseReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if scheme != tt.scheme || path != tt.path || key != tt.key {
+				t.Errorf("parseReference() = (%s, %s, %s), want (%s, %s, %s)", scheme, path, key, tt.scheme, tt.path, tt.key)
+			}
+		})
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"pass","port":5432},"metadata":{"version":1}}}`))
+		case "/v1/database/creds/app":
+			_, _ = w.Write([]byte(`{"lease_duration":60,"data":{"username":"user","password":"pass"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	v := &vaultProvider{addr: server.URL, token: "token", client: server.Client()}
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]string
+		wantTTL time.Duration
+		wantErr bool
+	}{
+		{name: "kv v2 secret", path: "secret/data/db", want: map[string]string{"password": "pass", "port": "5432"}},
+		{name: "leased secret", path: "database/creds/app", want: map[string]string{"username": "user", "password": "pass"}, wantTTL: time.Minute},
+		{name: "unknown secret", path: "secret/data/unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ttl, err := v.fetch(context.Background(), tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !isEqual(got, tt.want) || ttl != tt.wantTTL {
+				t.Errorf("fetch() = (%v, %v), want (%v, %v)", got, ttl, tt.want, tt.wantTTL)
+			}
+		})
+	}
+}
+
+type fakeProvider struct {
+	calls int
+	data  map[string]string
+	err   error
+}
+
+func (f *fakeProvider) fetch(context.Context, string) (map[string]string, time.Duration, error) {
+	f.calls++
+	return f.data, time.Minute, f.err
+}
+
+func TestManager_Resolve(t *testing.T) {
+	p := &fakeProvider{data: map[string]string{"password": "pass", "user": "admin"}}
+	m := &Manager{providers: map[string]provider{SchemeVault: p}, cache: map[string]*cachedSecret{}}
+	ctx := context.Background()
+
+	if got, err := m.Resolve(ctx, "vault://db#password"); err != nil || got != "pass" {
+		t.Fatalf("Resolve() = (%s, %v), want pass", got, err)
+	}
+	if _, err := m.Resolve(ctx, "vault://db"); err == nil {
+		t.Errorf("Resolve() without key of a secret with multiple values should fail")
+	}
+	if _, err := m.Resolve(ctx, "vault://db#unknown"); err == nil {
+		t.Errorf("Resolve() of an unknown key should fail")
+	}
+	if _, err := m.Resolve(ctx, "aws-sm://db#password"); err == nil {
+		t.Errorf("Resolve() with an unregistered provider should fail")
+	}
+	if p.calls != 1 {
+		t.Errorf("Resolve() fetched the secret %d times, want the cached value to be used", p.calls)
+	}
+
+	// Expired secrets are re-fetched and the stale value is used if the secret manager is unreachable
+	m.cache["vault://db"].expiresAt = time.Now()
+	p.err = errors.New("unreachable")
+	if got, err := m.Resolve(ctx, "vault://db#user"); err != nil || got != "admin" {
+		t.Errorf("Resolve() = (%s, %v), want the stale value", got, err)
+	}
+	if p.calls != 2 {
+		t.Errorf("Resolve() did not re-fetch the expired secret")
+	}
+}
+
+func TestManager_Refresh(t *testing.T) {
+	p := &fakeProvider{data: map[string]string{"password": "old"}}
+	m := &Manager{providers: map[string]provider{SchemeVault: p}, cache: map[string]*cachedSecret{}}
+	rotations := 0
+	m.SetRotationHook(func() { rotations++ })
+
+	if got, err := m.Resolve(context.Background(), "vault://db"); err != nil || got != "old" {
+		t.Fatalf("Resolve() = (%s, %v), want old", got, err)
+	}
+
+	// Secrets which haven't expired are left untouched
+	m.refresh()
+	if p.calls != 1 || rotations != 0 {
+		t.Errorf("refresh() re-fetched a secret which has not expired")
+	}
+
+	// Expired secrets which haven't changed don't trigger the hook
+	m.cache["vault://db"].expiresAt = time.Now()
+	m.refresh()
+	if p.calls != 2 || rotations != 0 {
+		t.Errorf("refresh() calls = %d rotations = %d, want 2 and 0", p.calls, rotations)
+	}
+
+	m.cache["vault://db"].expiresAt = time.Now()
+	p.data = map[string]string{"password": "new"}
+	m.refresh()
+	if rotations != 1 {
+		t.Errorf("refresh() did not invoke the rotation hook on a rotated secret")
+	}
+	if got, _ := m.Resolve(context.Background(), "vault://db"); got != "new" {
+		t.Errorf("Resolve() = %s after rotation, want new", got)
+	}
+}
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// vaultProvider reads secrets from the kv secrets engine (v1 and v2) of hashicorp vault. The address and
+// token of vault are picked up from the VAULT_ADDR and VAULT_TOKEN environment variables.
+type vaultProvider struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+type vaultResponse struct {
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+	Errors        []string               `json:"errors"`
+}
+
+func newVaultProvider() *vaultProvider {
+	return &vaultProvider{
+		addr:      strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (v *vaultProvider) fetch(ctx context.Context, path string) (map[string]string, time.Duration, error) {
+	if v.addr == "" {
+		return nil, 0, errors.New("vault address has not been provided through the VAULT_ADDR environment variable")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s", v.addr, strings.TrimPrefix(path, "/")), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	res := new(vaultResponse)
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("vault responded with status code (%d): %s", resp.StatusCode, strings.Join(res.Errors, ", "))
+	}
+
+	// The kv v2 engine nests the secret within data along with its metadata
+	data := res.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	values := make(map[string]string, len(data))
+	for k, val := range data {
+		values[k] = toString(val)
+	}
+	return values, time.Duration(res.LeaseDuration) * time.Second, nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,40 @@
+package modules
+
+import (
+	"context"
+	"sort"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// CheckDatabaseHealth checks the connectivity of the databases of all projects
+func (m *Modules) CheckDatabaseHealth(ctx context.Context) []*model.ComponentHealth {
+	m.lock.RLock()
+	projectIDs := make([]string, 0, len(m.blocks))
+	for id := range m.blocks {
+		projectIDs = append(projectIDs, id)
+	}
+	m.lock.RUnlock()
+	sort.Strings(projectIDs)
+
+	components := make([]*model.ComponentHealth, 0)
+	for _, projectID := range projectIDs {
+		module, err := m.loadModule(projectID)
+		if err != nil {
+			// The project might have been deleted in the meantime
+			continue
+		}
+
+		result := module.db.CheckHealth(ctx)
+		dbAliases := make([]string, 0, len(result))
+		for dbAlias := range result {
+			dbAliases = append(dbAliases, dbAlias)
+		}
+		sort.Strings(dbAliases)
+
+		for _, dbAlias := range dbAliases {
+			components = append(components, model.NewComponentHealth(dbAlias, model.HealthComponentDatabase, projectID, result[dbAlias]))
+		}
+	}
+	return components
+}
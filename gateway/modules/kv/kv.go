@@ -0,0 +1,141 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+// defaultMaxValueSize is the max size of a value when it isn't configured
+const defaultMaxValueSize = 64 * 1024
+
+// Module is the namespaced key value store of a project
+type Module struct {
+	lock sync.RWMutex
+
+	clusterID string
+	project   string
+
+	config *config.KVConfig
+	store  store
+	conn   string
+	prefix string
+
+	// The external modules kv depends on
+	auth authInterface
+
+	resolveSecret utils.ResolveSecret
+
+	// newStore connects to the redis at the provided address. It is overridden in tests
+	newStore func(project, conn string) (store, error)
+}
+
+// New creates a new instance of the key value store
+func New(clusterID, project string, auth authInterface) *Module {
+	return &Module{clusterID: clusterID, project: project, auth: auth, newStore: newRedisStore}
+}
+
+func newRedisStore(project, conn string) (store, error) {
+	return pubsub.New(project, conn)
+}
+
+// SetConfig sets the config of the key value store and connects to its store
+func (m *Module) SetConfig(c *config.KVConfig) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if c == nil || !c.Enabled {
+		m.closeStore()
+		m.config = c
+		return nil
+	}
+
+	for name, ns := range c.Namespaces {
+		if !isValidName(name) {
+			return fmt.Errorf("invalid name (%s) provided for key value namespace", name)
+		}
+		if ns == nil {
+			return fmt.Errorf("config of key value namespace (%s) not provided", name)
+		}
+		for op := range ns.Rules {
+			switch op {
+			case config.KVRead, config.KVWrite, config.KVDelete, config.KVList:
+			default:
+				return fmt.Errorf("invalid operation (%s) provided in the rules of key value namespace (%s)", op, name)
+			}
+		}
+		if ns.DefaultTTL < 0 {
+			return fmt.Errorf("default ttl of key value namespace (%s) cannot be negative", name)
+		}
+	}
+
+	var conn, prefix string
+	switch c.Store {
+	case "", config.KVStoreCluster:
+		// The keys of different clusters sharing a redis are kept apart
+		conn, prefix = os.Getenv("REDIS_CONN"), fmt.Sprintf("kv/%s/%s/", m.clusterID, m.project)
+	case config.KVStoreRedis:
+		if c.Conn == "" {
+			return fmt.Errorf("conn of the redis store not provided")
+		}
+		v, err := m.getSecret(c.Conn)
+		if err != nil {
+			return err
+		}
+		conn, prefix = v, fmt.Sprintf("kv/%s/", m.project)
+	default:
+		return fmt.Errorf("invalid key value store (%s) provided", c.Store)
+	}
+
+	// Reuse the existing connection if the store hasn't changed
+	if m.store == nil || m.conn != conn {
+		s, err := m.newStore(m.project, conn)
+		if err != nil {
+			return fmt.Errorf("unable to connect to the redis store of the key value store - %v", err)
+		}
+		m.closeStore()
+		m.store, m.conn = s, conn
+	}
+
+	m.config = c
+	m.prefix = prefix
+	return nil
+}
+
+// SetResolveSecret sets the function to resolve secrets from external secret managers
+func (m *Module) SetResolveSecret(function utils.ResolveSecret) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.resolveSecret = function
+}
+
+// CloseConfig closes the connection to the store
+func (m *Module) CloseConfig() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = nil
+	m.closeStore()
+	return nil
+}
+
+func (m *Module) closeStore() {
+	if m.store != nil {
+		m.store.Close()
+	}
+	m.store, m.conn = nil, ""
+}
+
+func (m *Module) getSecret(value string) (string, error) {
+	if secrets.IsReference(value) && m.resolveSecret != nil {
+		return m.resolveSecret(context.TODO(), value)
+	}
+	return value, nil
+}
@@ -0,0 +1,190 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type mockAuth struct{}
+
+func (mockAuth) AuthorizeRequest(ctx context.Context, rule *config.Rule, project, token string, args map[string]interface{}) (map[string]interface{}, error) {
+	if rule.Rule == "deny" {
+		return nil, errors.New("access denied")
+	}
+	return map[string]interface{}{}, nil
+}
+
+type mockStore struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *mockStore) GetKey(ctx context.Context, key string) (string, error) {
+	v, ok := s.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return v, nil
+}
+
+func (s *mockStore) GetKeyTTL(ctx context.Context, key string) (time.Duration, error) {
+	if ttl, ok := s.ttls[key]; ok && ttl > 0 {
+		return ttl, nil
+	}
+	return -1, nil
+}
+
+func (s *mockStore) SetKey(ctx context.Context, key, value string, t time.Duration) error {
+	s.values[key], s.ttls[key] = value, t
+	return nil
+}
+
+func (s *mockStore) DeleteKey(ctx context.Context, key string) error {
+	delete(s.values, key)
+	delete(s.ttls, key)
+	return nil
+}
+
+func (s *mockStore) GetKeysWithPrefix(ctx context.Context, prefix string) (map[string]string, error) {
+	result := map[string]string{}
+	for k, v := range s.values {
+		if strings.HasPrefix(k, prefix) {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+func (s *mockStore) Close() {}
+
+func newTestModule(t *testing.T, s *mockStore) *Module {
+	m := New("chicago", "project", mockAuth{})
+	m.newStore = func(project, conn string) (store, error) { return s, nil }
+	allow, deny := &config.Rule{Rule: "allow"}, &config.Rule{Rule: "deny"}
+	err := m.SetConfig(&config.KVConfig{Enabled: true, MaxValueSize: 64, Namespaces: map[string]*config.KVNamespace{
+		"flags":   {Rules: map[config.KVOp]*config.Rule{config.KVRead: allow, config.KVWrite: allow, config.KVDelete: allow, config.KVList: allow}},
+		"session": {Rules: map[config.KVOp]*config.Rule{config.KVRead: allow, config.KVWrite: allow}, DefaultTTL: 60},
+		"default": {Rules: map[config.KVOp]*config.Rule{config.KVRead: deny}},
+	}})
+	if err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	return m
+}
+
+func TestModule_SetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *config.KVConfig
+		wantErr bool
+	}{
+		{name: "disabled", config: &config.KVConfig{}},
+		{name: "cluster store", config: &config.KVConfig{Enabled: true, Namespaces: map[string]*config.KVNamespace{"flags": {Rules: map[config.KVOp]*config.Rule{config.KVRead: {Rule: "allow"}}}}}},
+		{name: "redis store without a conn", config: &config.KVConfig{Enabled: true, Store: config.KVStoreRedis}, wantErr: true},
+		{name: "unknown store", config: &config.KVConfig{Enabled: true, Store: "etcd"}, wantErr: true},
+		{name: "invalid namespace", config: &config.KVConfig{Enabled: true, Namespaces: map[string]*config.KVNamespace{"feature/flags": {}}}, wantErr: true},
+		{name: "invalid operation", config: &config.KVConfig{Enabled: true, Namespaces: map[string]*config.KVNamespace{"flags": {Rules: map[config.KVOp]*config.Rule{"update": {Rule: "allow"}}}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New("chicago", "project", mockAuth{})
+			m.newStore = func(project, conn string) (store, error) { return newMockStore(), nil }
+			if err := m.SetConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("SetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModule_operations(t *testing.T) {
+	s := newMockStore()
+	m := newTestModule(t, s)
+	ctx := context.Background()
+
+	// Set a few keys
+	sets := []struct {
+		namespace  string
+		key        string
+		req        *model.KVSetRequest
+		wantStatus int
+	}{
+		{namespace: "flags", key: "dark-mode", req: &model.KVSetRequest{Value: true}, wantStatus: http.StatusOK},
+		{namespace: "flags", key: "beta", req: &model.KVSetRequest{Value: map[string]interface{}{"rollout": 0.5}, TTL: 30}, wantStatus: http.StatusOK},
+		{namespace: "session", key: "abc", req: &model.KVSetRequest{Value: "xyz"}, wantStatus: http.StatusOK},
+		{namespace: "flags", key: "large", req: &model.KVSetRequest{Value: strings.Repeat("a", 100)}, wantStatus: http.StatusRequestEntityTooLarge},
+		{namespace: "flags", key: "a/b", req: &model.KVSetRequest{Value: 1}, wantStatus: http.StatusBadRequest},
+		{namespace: "other", key: "a", req: &model.KVSetRequest{Value: 1}, wantStatus: http.StatusForbidden},
+		{namespace: "flags", key: "a", req: &model.KVSetRequest{Value: 1, TTL: -1}, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range sets {
+		if status, err := m.Set(ctx, tt.namespace, tt.key, "", tt.req); status != tt.wantStatus {
+			t.Errorf("Set(%s, %s) = (%d, %v), want status %d", tt.namespace, tt.key, status, err, tt.wantStatus)
+		}
+	}
+
+	// The default ttl of the namespace applies to the keys set without a ttl
+	if got := s.ttls["kv/chicago/project/session/abc"]; got != time.Minute {
+		t.Errorf("Set() used ttl %v, want the default ttl of the namespace", got)
+	}
+	if _, entry, _ := m.Get(ctx, "flags", "beta", ""); entry == nil || entry.ExpiresAt == nil {
+		t.Errorf("Get() didn't return the expiry of a key with a ttl")
+	}
+
+	gets := []struct {
+		namespace  string
+		key        string
+		want       interface{}
+		wantStatus int
+	}{
+		{namespace: "flags", key: "dark-mode", want: true, wantStatus: http.StatusOK},
+		{namespace: "flags", key: "missing", wantStatus: http.StatusNotFound},
+		{namespace: "other", key: "dark-mode", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range gets {
+		status, entry, err := m.Get(ctx, tt.namespace, tt.key, "")
+		if status != tt.wantStatus {
+			t.Errorf("Get(%s, %s) = (%d, %v), want status %d", tt.namespace, tt.key, status, err, tt.wantStatus)
+			continue
+		}
+		if status == http.StatusOK && !reflect.DeepEqual(entry.Value, tt.want) {
+			t.Errorf("Get(%s, %s) = %v, want %v", tt.namespace, tt.key, entry.Value, tt.want)
+		}
+	}
+
+	// Keys of other namespaces aren't listed
+	_, entries, err := m.List(ctx, "flags", "", "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var keys []string
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"beta", "dark-mode"}) {
+		t.Errorf("List() = %v, want [beta dark-mode]", keys)
+	}
+	if status, _, _ := m.List(ctx, "session", "", ""); status != http.StatusForbidden {
+		t.Errorf("List() of a namespace without a list rule returned status %d, want %d", status, http.StatusForbidden)
+	}
+
+	if _, err := m.Delete(ctx, "flags", "dark-mode", ""); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if status, _, _ := m.Get(ctx, "flags", "dark-mode", ""); status != http.StatusNotFound {
+		t.Errorf("Get() of a deleted key returned status %d, want %d", status, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,191 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// Get returns the value of a key in a namespace
+func (m *Module) Get(ctx context.Context, namespace, key, token string) (int, *model.KVEntry, error) {
+	s, prefix, _, status, err := m.authorize(ctx, namespace, key, token, config.KVRead, map[string]interface{}{"key": key})
+	if err != nil {
+		return status, nil, err
+	}
+
+	data, err := s.GetKey(ctx, prefix+key)
+	if err == redis.Nil {
+		return http.StatusNotFound, nil, fmt.Errorf("key (%s) not found in namespace (%s)", key, namespace)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to get key (%s)", key), err, map[string]interface{}{"namespace": namespace})
+	}
+
+	entry, err := newEntry(key, data)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	ttl, err := s.GetKeyTTL(ctx, prefix+key)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to get ttl of key (%s)", key), err, map[string]interface{}{"namespace": namespace})
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		entry.ExpiresAt = &expiresAt
+	}
+	return http.StatusOK, entry, nil
+}
+
+// Set sets the value of a key in a namespace. The key expires after the ttl of the request or else the default ttl
+// of the namespace.
+func (m *Module) Set(ctx context.Context, namespace, key, token string, req *model.KVSetRequest) (int, error) {
+	if req.TTL < 0 {
+		return http.StatusBadRequest, errors.New("ttl of a key cannot be negative")
+	}
+
+	s, prefix, c, status, err := m.authorize(ctx, namespace, key, token, config.KVWrite, map[string]interface{}{"key": key, "value": req.Value, "ttl": req.TTL})
+	if err != nil {
+		return status, err
+	}
+
+	data, err := json.Marshal(req.Value)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	if max := getMaxValueSize(c); len(data) > max {
+		return http.StatusRequestEntityTooLarge, fmt.Errorf("value of key (%s) is larger than the max size of %d bytes", key, max)
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		if ns := getNamespace(c, namespace); ns != nil {
+			ttl = ns.DefaultTTL
+		}
+	}
+
+	if err := s.SetKey(ctx, prefix+key, string(data), time.Duration(ttl)*time.Second); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to set key (%s)", key), err, map[string]interface{}{"namespace": namespace})
+	}
+	return http.StatusOK, nil
+}
+
+// Delete deletes a key from a namespace. Deleting a key which doesn't exist isn't an error.
+func (m *Module) Delete(ctx context.Context, namespace, key, token string) (int, error) {
+	s, prefix, _, status, err := m.authorize(ctx, namespace, key, token, config.KVDelete, map[string]interface{}{"key": key})
+	if err != nil {
+		return status, err
+	}
+
+	if err := s.DeleteKey(ctx, prefix+key); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to delete key (%s)", key), err, map[string]interface{}{"namespace": namespace})
+	}
+	return http.StatusOK, nil
+}
+
+// List returns the keys of a namespace starting with the prefix along with their values, sorted by the key
+func (m *Module) List(ctx context.Context, namespace, keyPrefix, token string) (int, []*model.KVEntry, error) {
+	s, prefix, _, status, err := m.authorize(ctx, namespace, keyPrefix, token, config.KVList, map[string]interface{}{"prefix": keyPrefix})
+	if err != nil {
+		return status, nil, err
+	}
+
+	values, err := s.GetKeysWithPrefix(ctx, prefix+keyPrefix)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to list keys", err, map[string]interface{}{"namespace": namespace, "prefix": keyPrefix})
+	}
+
+	entries := make([]*model.KVEntry, 0, len(values))
+	for k, data := range values {
+		entry, err := newEntry(strings.TrimPrefix(k, prefix), data)
+		if err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return http.StatusOK, entries, nil
+}
+
+// authorize checks the security rule of the operation and returns the store along with the prefix of the keys of
+// the namespace
+func (m *Module) authorize(ctx context.Context, namespace, key, token string, op config.KVOp, args map[string]interface{}) (store, string, *config.KVConfig, int, error) {
+	m.lock.RLock()
+	s, prefix, c := m.store, m.prefix, m.config
+	m.lock.RUnlock()
+
+	if c == nil || !c.Enabled || s == nil {
+		return nil, "", nil, http.StatusBadRequest, errors.New("key value store is not enabled for this project")
+	}
+	if !isValidName(namespace) {
+		return nil, "", nil, http.StatusBadRequest, fmt.Errorf("invalid namespace (%s) provided", namespace)
+	}
+	// An empty key is only valid as the prefix of a list
+	if (key != "" || op != config.KVList) && !isValidKey(key) {
+		return nil, "", nil, http.StatusBadRequest, fmt.Errorf("invalid key (%s) provided", key)
+	}
+
+	ns := getNamespace(c, namespace)
+	if ns == nil || ns.Rules[op] == nil {
+		return nil, "", nil, http.StatusForbidden, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No security rule provided for operation (%s) on key value namespace (%s)", op, namespace), nil, nil)
+	}
+
+	args["namespace"] = namespace
+	args["op"] = string(op)
+	if _, err := m.auth.AuthorizeRequest(ctx, ns.Rules[op], m.project, token, args); err != nil {
+		return nil, "", nil, http.StatusForbidden, err
+	}
+
+	return s, prefix + namespace + "/", c, http.StatusOK, nil
+}
+
+// getNamespace returns the config of a namespace, falling back to the default namespace
+func getNamespace(c *config.KVConfig, namespace string) *config.KVNamespace {
+	if ns, ok := c.Namespaces[namespace]; ok {
+		return ns
+	}
+	return c.Namespaces["default"]
+}
+
+func getMaxValueSize(c *config.KVConfig) int {
+	if c.MaxValueSize > 0 {
+		return c.MaxValueSize
+	}
+	return defaultMaxValueSize
+}
+
+func newEntry(key, data string) (*model.KVEntry, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal value of key (%s) - %v", key, err)
+	}
+	return &model.KVEntry{Key: key, Value: value}, nil
+}
+
+// isValidName checks if the name of a namespace is made of letters, digits, dashes and underscores
+func isValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidKey checks that a key doesn't contain slashes or the characters of a redis glob pattern
+func isValidKey(key string) bool {
+	return key != "" && len(key) <= 512 && !strings.ContainsAny(key, "/*?[]\\")
+}
@@ -0,0 +1,25 @@
+package kv
+
+import (
+	"context"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+type authInterface interface {
+	AuthorizeRequest(ctx context.Context, rule *config.Rule, project, token string, args map[string]interface{}) (map[string]interface{}, error)
+}
+
+// store is the subset of the redis client used to save the keys
+type store interface {
+	GetKey(ctx context.Context, key string) (string, error)
+	GetKeyTTL(ctx context.Context, key string) (time.Duration, error)
+	SetKey(ctx context.Context, key, value string, t time.Duration) error
+	DeleteKey(ctx context.Context, key string) error
+	GetKeysWithPrefix(ctx context.Context, prefix string) (map[string]string, error)
+	Close()
+}
+
+var _ store = (*pubsub.Module)(nil)
@@ -0,0 +1,28 @@
+package modules
+
+import (
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// Maintenance returns the maintenance config of the project. Nil is returned if the project serves the requests
+// as usual.
+func (m *Modules) Maintenance(projectID string) *config.MaintenanceConfig {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return nil
+	}
+
+	module.maintenanceLock.RLock()
+	defer module.maintenanceLock.RUnlock()
+	return module.maintenance
+}
+
+func (m *Module) setMaintenance(c *config.MaintenanceConfig) {
+	m.maintenanceLock.Lock()
+	defer m.maintenanceLock.Unlock()
+
+	if c != nil && c.Mode == config.MaintenanceModeOff {
+		c = nil
+	}
+	m.maintenance = c
+}
@@ -0,0 +1,60 @@
+package modules
+
+import (
+	"sort"
+
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/metrics"
+)
+
+// Metrics returns the global metrics module
+func (m *Modules) Metrics() *metrics.Module {
+	return m.GlobalMods.Metrics()
+}
+
+// MetricGauges returns the database pool stats and realtime subscription counts of all projects
+func (m *Modules) MetricGauges() []metrics.Gauge {
+	m.lock.RLock()
+	projectIDs := make([]string, 0, len(m.blocks))
+	for id := range m.blocks {
+		projectIDs = append(projectIDs, id)
+	}
+	m.lock.RUnlock()
+	sort.Strings(projectIDs)
+
+	gauges := make([]metrics.Gauge, 0)
+	for _, projectID := range projectIDs {
+		module, err := m.loadModule(projectID)
+		if err != nil {
+			// The project might have been deleted in the meantime
+			continue
+		}
+
+		poolStats := module.db.GetPoolStats()
+		dbAliases := make([]string, 0, len(poolStats))
+		for dbAlias := range poolStats {
+			dbAliases = append(dbAliases, dbAlias)
+		}
+		sort.Strings(dbAliases)
+
+		for _, dbAlias := range dbAliases {
+			stats := poolStats[dbAlias]
+			labels := map[string]string{"project": projectID, "db": dbAlias}
+			gauges = append(gauges,
+				metrics.Gauge{Name: "space_cloud_db_pool_max_open_connections", Help: "Maximum number of open connections allowed to the database", Labels: labels, Value: float64(stats.MaxOpenConnections)},
+				metrics.Gauge{Name: "space_cloud_db_pool_open_connections", Help: "Number of established connections to the database", Labels: labels, Value: float64(stats.OpenConnections)},
+				metrics.Gauge{Name: "space_cloud_db_pool_in_use_connections", Help: "Number of connections currently in use", Labels: labels, Value: float64(stats.InUse)},
+				metrics.Gauge{Name: "space_cloud_db_pool_idle_connections", Help: "Number of idle connections", Labels: labels, Value: float64(stats.Idle)},
+				metrics.Gauge{Name: "space_cloud_db_pool_wait_count", Help: "Total number of connections waited for", Labels: labels, Value: float64(stats.WaitCount)},
+			)
+		}
+
+		gauges = append(gauges, metrics.Gauge{
+			Name:   "space_cloud_realtime_subscriptions",
+			Help:   "Number of live query subscriptions handled by this gateway",
+			Labels: map[string]string{"project": projectID},
+			Value:  float64(module.realtime.SubscriptionCount()),
+		})
+	}
+
+	return gauges
+}
@@ -1,17 +1,31 @@
 package modules
 
 import (
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers"
+	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules/auth"
+	"github.com/spaceuptech/space-cloud/gateway/modules/backup"
 	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
 	"github.com/spaceuptech/space-cloud/gateway/modules/eventing"
 	"github.com/spaceuptech/space-cloud/gateway/modules/filestore"
+	"github.com/spaceuptech/space-cloud/gateway/modules/fixtures"
+	"github.com/spaceuptech/space-cloud/gateway/modules/flags"
 	"github.com/spaceuptech/space-cloud/gateway/modules/functions"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global"
+	"github.com/spaceuptech/space-cloud/gateway/modules/kv"
+	"github.com/spaceuptech/space-cloud/gateway/modules/privacy"
 	"github.com/spaceuptech/space-cloud/gateway/modules/realtime"
+	"github.com/spaceuptech/space-cloud/gateway/modules/retention"
 	"github.com/spaceuptech/space-cloud/gateway/modules/schema"
+	"github.com/spaceuptech/space-cloud/gateway/modules/search"
+	"github.com/spaceuptech/space-cloud/gateway/modules/sessions"
+	"github.com/spaceuptech/space-cloud/gateway/modules/slo"
 	"github.com/spaceuptech/space-cloud/gateway/modules/userman"
 	"github.com/spaceuptech/space-cloud/gateway/utils/graphql"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
 )
 
 // Module is an object that sets up the modules
@@ -25,6 +39,18 @@ type Module struct {
 	eventing  *eventing.Module
 	graphql   *graphql.Module
 	schema    *schema.Schema
+	search    *search.Module
+	backup    *backup.Module
+	retention *retention.Module
+	kv        *kv.Module
+	privacy   *privacy.Module
+	flags     *flags.Module
+	sessions  *sessions.Module
+	slo       *slo.Module
+	fixtures  *fixtures.Module
+
+	maintenanceLock sync.RWMutex
+	maintenance     *config.MaintenanceConfig
 
 	// Global Modules
 	GlobalMods *global.Global
@@ -33,7 +59,7 @@ type Module struct {
 	Managers *managers.Managers
 }
 
-func newModule(projectID, clusterID, nodeID string, managers *managers.Managers, globalMods *global.Global) (*Module, error) {
+func newModule(projectID, clusterID, nodeID string, testMode bool, w *wal.WAL, managers *managers.Managers, globalMods *global.Global) (*Module, error) {
 	// Get managers
 	adminMan := managers.Admin()
 	syncMan := managers.Sync()
@@ -45,6 +71,7 @@ func newModule(projectID, clusterID, nodeID string, managers *managers.Managers,
 	c := crud.Init()
 	c.SetAdminManager(adminMan)
 	c.SetGetSecrets(syncMan.GetSecrets)
+	c.SetResolveSecret(globalMods.Secrets().Resolve)
 	c.SetIntegrationManager(integrationMan)
 	c.SetCachingModule(globalMods.Caching())
 
@@ -52,28 +79,58 @@ func newModule(projectID, clusterID, nodeID string, managers *managers.Managers,
 
 	a := auth.Init(clusterID, nodeID, c, adminMan, integrationMan)
 	a.SetMakeHTTPRequest(syncMan.MakeHTTPRequest)
+	a.SetResolveSecret(globalMods.Secrets().Resolve)
 
 	fn := functions.Init(clusterID, a, syncMan, integrationMan, metrics.AddFunctionOperation)
 	fn.SetCachingModule(globalMods.Caching())
+	c.SetFunctionsModule(fn)
 	f := filestore.Init(a, metrics.AddFileOperation)
 	f.SetGetSecrets(syncMan.GetSecrets)
+	f.SetResolveSecret(globalMods.Secrets().Resolve)
 
 	e, err := eventing.New(clusterID, projectID, nodeID, a, c, syncMan, f, metrics.AddEventingType)
 	if err != nil {
 		return nil, err
 	}
 
+	e.SetFunctionsModule(fn)
+	e.SetWAL(w)
 	f.SetEventingModule(e)
+	c.SetEventingModule(e)
 
-	c.SetHooks(metrics.AddDBOperation)
+	accounting := globalMods.Accounting()
+	c.SetHooks(func(project, dbAlias, col string, count int64, op model.OperationType) {
+		metrics.AddDBOperation(project, dbAlias, col, count, op)
+		accounting.AddDBOperation(project, count)
+	})
 
 	rt, err := realtime.Init(projectID, nodeID, e, a, c, s, metrics, syncMan)
 	if err != nil {
 		return nil, err
 	}
 
+	sr := search.New(projectID, syncMan.GetSearchURL(projectID), a, c, e)
+	sr.SetResolveSecret(globalMods.Secrets().Resolve)
+
+	b := backup.New(projectID, c, syncMan.IsLeader)
+	rn := retention.New(projectID, c, syncMan.IsLeader)
+
+	pr := privacy.New(projectID, c)
+
+	k := kv.New(clusterID, projectID, a)
+	k.SetResolveSecret(globalMods.Secrets().Resolve)
+
+	fl := flags.New(a)
+	a.SetEvaluateFeatureFlag(fl.Evaluate)
+
+	se := sessions.New(clusterID, projectID, a)
+	a.SetIsTokenRevoked(se.IsRevoked)
+
 	u := userman.Init(c, a)
+	u.SetResolveSecret(globalMods.Secrets().Resolve)
+	u.SetSessionsModule(se)
 	graphqlMan := graphql.New(a, c, fn, s)
+	graphqlMan.SetSearchModule(sr)
 
-	return &Module{auth: a, db: c, user: u, file: f, functions: fn, realtime: rt, eventing: e, graphql: graphqlMan, schema: s, Managers: managers, GlobalMods: globalMods}, nil
+	return &Module{auth: a, db: c, user: u, file: f, functions: fn, realtime: rt, eventing: e, graphql: graphqlMan, schema: s, search: sr, backup: b, retention: rn, kv: k, privacy: pr, flags: fl, sessions: se, slo: slo.New(projectID), fixtures: fixtures.New(projectID, testMode, c, f, adminMan.GetInternalAccessToken), Managers: managers, GlobalMods: globalMods}, nil
 }
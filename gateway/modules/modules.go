@@ -11,6 +11,7 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
 )
 
 // Modules is an object that sets up the modules
@@ -20,6 +21,8 @@ type Modules struct {
 
 	clusterID string
 	nodeID    string
+	testMode  bool
+	wal       *wal.WAL
 
 	// Global Modules
 	GlobalMods *global.Global
@@ -39,6 +42,21 @@ func New(_, clusterID, nodeID string, managers *managers.Managers, globalMods *g
 	}, nil
 }
 
+// SetTestMode lets the projects load their fixtures. It must be called before the config of the projects is set.
+func (m *Modules) SetTestMode(testMode bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.testMode = testMode
+}
+
+// SetWAL sets the write ahead log the projects persist their queued deliveries in. It must be called before the config
+// of the projects is set.
+func (m *Modules) SetWAL(w *wal.WAL) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.wal = w
+}
+
 // SetInitialProjectConfig sets the config all modules
 func (m *Modules) SetInitialProjectConfig(ctx context.Context, projects config.Projects) error {
 	for projectID, project := range projects {
@@ -142,6 +160,24 @@ func (m *Modules) SetEventingSchemaConfig(ctx context.Context, projectID string,
 	return module.SetEventingSchemaConfig(ctx, eventingSchemas)
 }
 
+// SetEventingSourceConfig sets the custom event sources of eventing module
+func (m *Modules) SetEventingSourceConfig(ctx context.Context, projectID string, sources config.EventingSources) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetEventingSourceConfig(ctx, sources)
+}
+
+// SetEventingWorkflowConfig sets the workflows of eventing module
+func (m *Modules) SetEventingWorkflowConfig(ctx context.Context, projectID string, workflows config.EventingWorkflows) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetEventingWorkflowConfig(ctx, workflows)
+}
+
 // SetEventingTriggerConfig sets the config of eventing module
 func (m *Modules) SetEventingTriggerConfig(ctx context.Context, projectID string, eventingTriggers config.EventingTriggers) error {
 	module, err := m.loadModule(projectID)
@@ -178,6 +214,51 @@ func (m *Modules) SetLetsencryptConfig(ctx context.Context, projectID string, c
 	return module.SetLetsencryptConfig(ctx, projectID, c)
 }
 
+// SetSearchConfig sets the config of the search module
+func (m *Modules) SetSearchConfig(ctx context.Context, projectID string, c *config.SearchConfig) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetSearchConfig(ctx, c)
+}
+
+// SetBackupConfig sets the config of the backup module
+func (m *Modules) SetBackupConfig(ctx context.Context, projectID string, c *config.BackupConfig) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetBackupConfig(ctx, c)
+}
+
+// SetKVConfig sets the config of the key value store
+func (m *Modules) SetKVConfig(ctx context.Context, projectID string, c *config.KVConfig) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetKVConfig(ctx, c)
+}
+
+// SetPrivacyConfig sets the config of the privacy module
+func (m *Modules) SetPrivacyConfig(ctx context.Context, projectID string, c *config.PrivacyConfig) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetPrivacyConfig(ctx, c)
+}
+
+// SetFeatureFlagsConfig sets the feature flags of the project
+func (m *Modules) SetFeatureFlagsConfig(ctx context.Context, projectID string, featureFlags config.FeatureFlags) error {
+	module, err := m.loadModule(projectID)
+	if err != nil {
+		return err
+	}
+	return module.SetFeatureFlagsConfig(ctx, featureFlags)
+}
+
 // SetIngressRouteConfig set the config of routing module
 func (m *Modules) SetIngressRouteConfig(ctx context.Context, projectID string, routes config.IngressRoutes) error {
 	module, err := m.loadModule(projectID)
@@ -245,6 +326,46 @@ func (m *Modules) Delete(projectID string) {
 		if err := block.realtime.CloseConfig(); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing realtime module config", err, map[string]interface{}{"project": projectID})
 		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of search module", nil)
+		if err := block.search.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing search module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of backup module", nil)
+		if err := block.backup.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing backup module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of retention module", nil)
+		if err := block.retention.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing retention module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of kv module", nil)
+		if err := block.kv.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing kv module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of privacy module", nil)
+		if err := block.privacy.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing privacy module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of feature flags module", nil)
+		if err := block.flags.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing feature flags module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of sessions module", nil)
+		if err := block.sessions.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing sessions module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(context.TODO()), "Closing config of slo module", nil)
+		if err := block.slo.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error closing slo module config", err, map[string]interface{}{"project": projectID})
+		}
 	}
 
 	delete(m.blocks, projectID)
@@ -254,6 +375,40 @@ func (m *Modules) Delete(projectID string) {
 	m.Routing().DeleteProjectRoutes(projectID)
 }
 
+// Shutdown flushes the pending events and closes the database connections of all projects
+func (m *Modules) Shutdown(ctx context.Context) {
+	m.lock.RLock()
+	blocks := make(map[string]*Module, len(m.blocks))
+	for projectID, block := range m.blocks {
+		blocks[projectID] = block
+	}
+	m.lock.RUnlock()
+
+	for projectID, block := range blocks {
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Flushing pending events", map[string]interface{}{"project": projectID})
+		if err := block.eventing.Drain(ctx); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to flush pending events", err, map[string]interface{}{"project": projectID})
+		}
+		if err := block.eventing.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Error closing eventing module config", err, map[string]interface{}{"project": projectID})
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Closing database connections", map[string]interface{}{"project": projectID})
+		if err := block.db.CloseConfig(); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Error closing db module config", err, map[string]interface{}{"project": projectID})
+		}
+	}
+}
+
+// ProjectExists checks if the project is present in the config of the node
+func (m *Modules) ProjectExists(projectID string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	_, p := m.blocks[projectID]
+	return p
+}
+
 func (m *Modules) loadModule(projectID string) (*Module, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -275,7 +430,7 @@ func (m *Modules) newModule(config *config.ProjectConfig) (*Module, error) {
 		return nil, errors.New("upgrade your plan to create new project")
 	}
 
-	module, err := newModule(config.ID, m.clusterID, m.nodeID, m.Managers, m.GlobalMods)
+	module, err := newModule(config.ID, m.clusterID, m.nodeID, m.testMode, m.wal, m.Managers, m.GlobalMods)
 	if err != nil {
 		return nil, err
 	}
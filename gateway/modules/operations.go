@@ -47,7 +47,9 @@ func (m *Module) SetInitialProjectConfig(ctx context.Context, projects config.Pr
 		}
 
 		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of user management module", nil)
-		m.user.SetConfig(project.Auths)
+		if err := m.user.SetConfig(project.Auths); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set user management module config", err, nil)
+		}
 		if err := m.user.SetProjectAESKey(project.ProjectConfig.AESKey); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set aes key for user module config", err, nil)
 		}
@@ -67,6 +69,16 @@ func (m *Module) SetInitialProjectConfig(ctx context.Context, projects config.Pr
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set eventing module triggers", err, nil)
 		}
 
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting sources of eventing module", nil)
+		if err := m.eventing.SetSourceConfig(project.EventingSources); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set eventing module sources", err, nil)
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting workflows of eventing module", nil)
+		if err := m.eventing.SetWorkflowConfig(project.EventingWorkflows); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set eventing module workflows", err, nil)
+		}
+
 		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of realtime module", nil)
 		if err := m.realtime.SetConfig(project.DatabaseConfigs, project.DatabaseRules, project.DatabaseSchemas); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set realtime module config", err, nil)
@@ -75,8 +87,48 @@ func (m *Module) SetInitialProjectConfig(ctx context.Context, projects config.Pr
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set aes key for realtime module config", err, nil)
 		}
 
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of search module", nil)
+		if err := m.search.SetConfig(project.SearchConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set search module config", err, nil)
+		}
+		if err := m.search.SetProjectAESKey(project.ProjectConfig.AESKey); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set aes key for search module config", err, nil)
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of backup module", nil)
+		if err := m.backup.SetConfig(project.BackupConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set backup module config", err, nil)
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of kv module", nil)
+		if err := m.kv.SetConfig(project.KVConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set kv module config", err, nil)
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of privacy module", nil)
+		if err := m.privacy.SetConfig(project.PrivacyConfig); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set privacy module config", err, nil)
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of feature flags module", nil)
+		if err := m.flags.SetConfig(project.FeatureFlags); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set feature flags module config", err, nil)
+		}
+
 		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of graphql module", nil)
 		m.graphql.SetConfig(projectID)
+		m.graphql.SetLimits(project.ProjectConfig.GraphQLLimits)
+		m.db.SetQueryBudget(project.ProjectConfig.QueryBudget)
+		m.graphql.SetPersistedQueries(project.ProjectConfig.PersistedQueries)
+		m.graphql.SetIntrospection(project.ProjectConfig.DisableIntrospection)
+		m.setMaintenance(project.ProjectConfig.Maintenance)
+		if err := m.sessions.SetConfig(project.ProjectConfig.Sessions); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set sessions module config", err, nil)
+		}
+		if err := m.slo.SetConfig(project.ProjectConfig.SLOs); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set slo module config", err, nil)
+		}
+		m.graphql.SetRemoteServices(project.RemoteService)
 		if err := m.graphql.SetProjectAESKey(project.ProjectConfig.AESKey); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set aes key for graphql module config", err, nil)
 		}
@@ -92,7 +144,20 @@ func (m *Module) SetInitialProjectConfig(ctx context.Context, projects config.Pr
 		}
 		m.GlobalMods.Routing().SetGlobalConfig(project.IngressGlobal)
 		m.eventing.SetInternalTriggersFromDbRules(project.DatabaseRules)
+		m.db.SetWriteHooks(project.DatabaseRules)
+		m.db.SetShardedCounters(project.DatabaseRules)
 		m.GlobalMods.Caching().AddDBRules(projectID, project.DatabaseRules)
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of retention module", nil)
+		if err := m.retention.SetConfig(project.DatabaseRules); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set retention module config", err, nil)
+		}
+
+		// The fixtures are loaded last since they need the databases, the file storage and the eventing to be set
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of fixtures module", nil)
+		if err := m.fixtures.SetConfig(ctx, project.ProjectConfig.Fixtures); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to set fixtures module config", err, nil)
+		}
 	}
 	return nil
 }
@@ -107,8 +172,20 @@ func (m *Module) SetProjectConfig(ctx context.Context, p *config.ProjectConfig)
 	_ = m.realtime.SetProjectAESKey(p.AESKey)
 	_ = m.user.SetProjectAESKey(p.AESKey)
 	_ = m.graphql.SetProjectAESKey(p.AESKey)
+	_ = m.search.SetProjectAESKey(p.AESKey)
 	m.graphql.SetConfig(p.ID)
-	return nil
+	m.graphql.SetLimits(p.GraphQLLimits)
+	m.db.SetQueryBudget(p.QueryBudget)
+	m.graphql.SetPersistedQueries(p.PersistedQueries)
+	m.graphql.SetIntrospection(p.DisableIntrospection)
+	m.setMaintenance(p.Maintenance)
+	if err := m.slo.SetConfig(p.SLOs); err != nil {
+		return err
+	}
+	if err := m.fixtures.SetConfig(ctx, p.Fixtures); err != nil {
+		return err
+	}
+	return m.sessions.SetConfig(p.Sessions)
 }
 
 // SetDatabaseConfig sets the config of db, auth, schema and realtime modules
@@ -163,8 +240,10 @@ func (m *Module) SetDatabaseRulesConfig(ctx context.Context, projectID string, r
 	m.auth.SetDatabaseRules(ruleConfigs)
 	m.realtime.SetDatabaseRules(ruleConfigs)
 	m.eventing.SetInternalTriggersFromDbRules(ruleConfigs)
+	m.db.SetWriteHooks(ruleConfigs)
+	m.db.SetShardedCounters(ruleConfigs)
 	m.GlobalMods.Caching().AddDBRules(projectID, ruleConfigs)
-	return nil
+	return m.retention.SetConfig(ruleConfigs)
 }
 
 // SetDatabasePreparedQueryConfig set prepared config of database moudle
@@ -223,6 +302,18 @@ func (m *Module) SetEventingSchemaConfig(ctx context.Context, eventingSchemas co
 	return m.eventing.SetSchemaConfig(eventingSchemas)
 }
 
+// SetEventingSourceConfig sets the custom event sources of eventing module
+func (m *Module) SetEventingSourceConfig(ctx context.Context, sources config.EventingSources) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting source config of eventing module", nil)
+	return m.eventing.SetSourceConfig(sources)
+}
+
+// SetEventingWorkflowConfig sets the workflows of eventing module
+func (m *Module) SetEventingWorkflowConfig(ctx context.Context, workflows config.EventingWorkflows) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting workflow config of eventing module", nil)
+	return m.eventing.SetWorkflowConfig(workflows)
+}
+
 // SetEventingTriggerConfig sets the config of eventing module
 func (m *Module) SetEventingTriggerConfig(ctx context.Context, eventingTriggers config.EventingTriggers) error {
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting trigger config of eventing module", nil)
@@ -242,8 +333,7 @@ func (m *Module) SetEventingRuleConfig(ctx context.Context, secureObj config.Eve
 // SetUsermanConfig set the config of the userman module
 func (m *Module) SetUsermanConfig(ctx context.Context, _ string, auth config.Auths) error {
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of user management module", nil)
-	m.user.SetConfig(auth)
-	return nil
+	return m.user.SetConfig(auth)
 }
 
 // SetLetsencryptConfig set the config of letsencrypt module
@@ -252,6 +342,36 @@ func (m *Module) SetLetsencryptConfig(ctx context.Context, projectID string, c *
 	return m.GlobalMods.LetsEncrypt().SetProjectDomains(projectID, c)
 }
 
+// SetSearchConfig sets the config of the search module
+func (m *Module) SetSearchConfig(ctx context.Context, c *config.SearchConfig) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of search module", nil)
+	return m.search.SetConfig(c)
+}
+
+// SetBackupConfig sets the config of the backup module
+func (m *Module) SetBackupConfig(ctx context.Context, c *config.BackupConfig) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of backup module", nil)
+	return m.backup.SetConfig(c)
+}
+
+// SetKVConfig sets the config of the key value store
+func (m *Module) SetKVConfig(ctx context.Context, c *config.KVConfig) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of kv module", nil)
+	return m.kv.SetConfig(c)
+}
+
+// SetPrivacyConfig sets the config of the privacy module
+func (m *Module) SetPrivacyConfig(ctx context.Context, c *config.PrivacyConfig) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of privacy module", nil)
+	return m.privacy.SetConfig(c)
+}
+
+// SetFeatureFlagsConfig sets the feature flags of the project
+func (m *Module) SetFeatureFlagsConfig(ctx context.Context, featureFlags config.FeatureFlags) error {
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of feature flags module", nil)
+	return m.flags.SetConfig(featureFlags)
+}
+
 // SetIngressRouteConfig set the config of routing module
 func (m *Module) SetIngressRouteConfig(ctx context.Context, projectID string, routes config.IngressRoutes) error {
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of routing module", nil)
@@ -269,6 +389,7 @@ func (m *Module) SetIngressGlobalRouteConfig(ctx context.Context, _ string, c *c
 func (m *Module) SetRemoteServiceConfig(ctx context.Context, projectID string, services config.Services) error {
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of auth module", nil)
 	m.auth.SetRemoteServiceConfig(services)
+	m.graphql.SetRemoteServices(services)
 
 	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Setting config of remote service module", nil)
 	return m.functions.SetConfig(projectID, services)
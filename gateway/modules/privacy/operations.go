@@ -0,0 +1,174 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// exportBatchSize is the number of documents read at a time while exporting the data of a user
+const exportBatchSize = 1000
+
+// Export returns the documents of a user from all the collections holding personal data
+func (m *Module) Export(ctx context.Context, userID string, req *model.PrivacyRequest, params model.RequestParams) (*model.PrivacyExport, error) {
+	c, err := m.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	report := m.newReport(model.PrivacyRequestExport, userID, req, params)
+	result := &model.PrivacyExport{Report: report, Collections: make([]*model.PrivacyExportCollection, 0, len(c.Collections))}
+	for _, col := range c.Collections {
+		colReport := &model.PrivacyCollectionReport{DbAlias: col.DbAlias, Col: col.Col, Action: model.PrivacyRequestExport}
+		report.Collections = append(report.Collections, colReport)
+
+		docs := make([]interface{}, 0)
+		readParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: map[string]string{"project": m.project, "db": col.DbAlias, "col": col.Col}, Claims: utils.InternalClaims()}
+		err := m.crud.Export(ctx, col.DbAlias, col.Col, &model.ReadRequest{Find: getUserFind(col, userID)}, exportBatchSize, readParams, func(page []interface{}) error {
+			docs = append(docs, page...)
+			return nil
+		})
+		if err != nil {
+			return result, m.completeReport(ctx, c, report, fmt.Errorf("unable to export the documents of (%s) in database (%s): %v", col.Col, col.DbAlias, err))
+		}
+
+		colReport.Documents, colReport.Committed = int64(len(docs)), true
+		result.Collections = append(result.Collections, &model.PrivacyExportCollection{DbAlias: col.DbAlias, Col: col.Col, Documents: docs})
+	}
+	return result, m.completeReport(ctx, c, report, nil)
+}
+
+// Erase deletes or anonymizes the documents of a user in all the collections holding personal data. The collections
+// of a database are erased in a single transaction. Databases are erased one after the other and the erasure stops at
+// the first database which fails, hence the report tells which of them have been committed.
+func (m *Module) Erase(ctx context.Context, userID string, req *model.PrivacyRequest, params model.RequestParams) (*model.PrivacyReport, error) {
+	c, err := m.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	report := m.newReport(model.PrivacyRequestErasure, userID, req, params)
+	for _, dbAlias := range getDatabases(c.Collections) {
+		if err := m.eraseDatabase(ctx, c, dbAlias, userID, report); err != nil {
+			return report, m.completeReport(ctx, c, report, err)
+		}
+	}
+	return report, m.completeReport(ctx, c, report, nil)
+}
+
+// eraseDatabase erases the documents of a user from the collections of a database in a single batch
+func (m *Module) eraseDatabase(ctx context.Context, c *config.PrivacyConfig, dbAlias, userID string, report *model.PrivacyReport) error {
+	batch := new(model.BatchRequest)
+	colReports := make([]*model.PrivacyCollectionReport, 0)
+	for _, col := range c.Collections {
+		if col.DbAlias != dbAlias {
+			continue
+		}
+
+		// The documents are counted first since the batch doesn't return the number of documents affected
+		readParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: map[string]string{"project": m.project, "db": col.DbAlias, "col": col.Col}, Claims: utils.InternalClaims()}
+		result, _, err := m.crud.Read(ctx, dbAlias, col.Col, &model.ReadRequest{Find: getUserFind(col, userID), Operation: utils.Count, Options: &model.ReadOptions{}}, readParams)
+		if err != nil {
+			return fmt.Errorf("unable to count the documents of (%s) in database (%s): %v", col.Col, dbAlias, err)
+		}
+		count, _ := result.(int64)
+
+		colReport := &model.PrivacyCollectionReport{DbAlias: dbAlias, Col: col.Col, Action: getErasure(col), Documents: count}
+		report.Collections = append(report.Collections, colReport)
+		colReports = append(colReports, colReport)
+		if count == 0 {
+			colReport.Committed = true
+			continue
+		}
+
+		r := &model.AllRequest{Col: col.Col, Find: getUserFind(col, userID), Operation: utils.All, DBAlias: dbAlias}
+		switch getErasure(col) {
+		case config.PrivacyErasureAnonymize:
+			r.Type, r.Update = string(model.Update), map[string]interface{}{"$set": col.Anonymize}
+		default:
+			r.Type = string(model.Delete)
+		}
+		batch.Requests = append(batch.Requests, r)
+	}
+
+	if len(batch.Requests) > 0 {
+		params := model.RequestParams{Resource: "db-batch", Op: "access", Attributes: map[string]string{"project": m.project, "db": dbAlias}}
+		if err := m.crud.Batch(ctx, dbAlias, batch, params); err != nil {
+			return fmt.Errorf("unable to erase the documents in database (%s): %v", dbAlias, err)
+		}
+	}
+	for _, colReport := range colReports {
+		colReport.Committed = true
+	}
+	return nil
+}
+
+func (m *Module) newReport(reqType, userID string, req *model.PrivacyRequest, params model.RequestParams) *model.PrivacyReport {
+	report := &model.PrivacyReport{ID: ksuid.New().String(), Type: reqType, UserID: userID, StartedAt: m.now(), Collections: make([]*model.PrivacyCollectionReport, 0)}
+	if req != nil {
+		report.Reason = req.Reason
+	}
+	if id, ok := params.Claims["id"]; ok {
+		report.RequestedBy = fmt.Sprint(id)
+	}
+	return report
+}
+
+// completeReport marks the report as completed or failed and records it. The error of the request is returned, or
+// the one of recording the report since a request which can't be audited is treated as failed.
+func (m *Module) completeReport(ctx context.Context, c *config.PrivacyConfig, report *model.PrivacyReport, err error) error {
+	report.CompletedAt = m.now()
+	report.Status = model.PrivacyStatusCompleted
+	if err != nil {
+		report.Status, report.Error = model.PrivacyStatusFailed, err.Error()
+	}
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Privacy request (%s) of type (%s) for user (%s) of project (%s) %s", report.ID, report.Type, report.UserID, m.project, report.Status), map[string]interface{}{"requestedBy": report.RequestedBy, "collections": len(report.Collections)})
+	if c.Audit == nil {
+		return err
+	}
+
+	params := model.RequestParams{Resource: "db-create", Op: "access", Attributes: map[string]string{"project": m.project, "db": c.Audit.DbAlias, "col": c.Audit.Col}}
+	cols := make([]interface{}, len(report.Collections))
+	for i, col := range report.Collections {
+		cols[i] = map[string]interface{}{"dbAlias": col.DbAlias, "col": col.Col, "action": col.Action, "documents": col.Documents, "committed": col.Committed}
+	}
+	doc := map[string]interface{}{
+		"id": report.ID, "type": report.Type, "user_id": report.UserID, "requested_by": report.RequestedBy, "reason": report.Reason,
+		"status": report.Status, "error": report.Error, "started_at": report.StartedAt, "completed_at": report.CompletedAt,
+		"collections": cols,
+	}
+	if auditErr := m.crud.Create(ctx, c.Audit.DbAlias, c.Audit.Col, &model.CreateRequest{Document: doc, Operation: utils.One}, params); auditErr != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to record the report of privacy request (%s)", report.ID), auditErr, nil)
+		if err == nil {
+			return fmt.Errorf("request completed but its report could not be recorded: %v", auditErr)
+		}
+	}
+	return err
+}
+
+func getUserFind(c *config.PrivacyCollection, userID string) map[string]interface{} {
+	return map[string]interface{}{c.Field: userID}
+}
+
+// getDatabases returns the databases of the collections in a stable order
+func getDatabases(cols []*config.PrivacyCollection) []string {
+	dbs := make([]string, 0)
+	seen := map[string]struct{}{}
+	for _, col := range cols {
+		if _, ok := seen[col.DbAlias]; ok {
+			continue
+		}
+		seen[col.DbAlias] = struct{}{}
+		dbs = append(dbs, col.DbAlias)
+	}
+	sort.Strings(dbs)
+	return dbs
+}
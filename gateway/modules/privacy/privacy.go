@@ -0,0 +1,99 @@
+package privacy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// Module exports and erases the personal data of the users of a project on their request. Every request produces a
+// report which is written to the audit collection.
+type Module struct {
+	lock sync.RWMutex
+
+	project string
+	config  *config.PrivacyConfig
+
+	crud crudInterface
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// New creates a new instance of the privacy module
+func New(project string, crud crudInterface) *Module {
+	return &Module{project: project, crud: crud, now: time.Now}
+}
+
+// SetConfig sets the config of the privacy module
+func (m *Module) SetConfig(c *config.PrivacyConfig) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = nil
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	if len(c.Collections) == 0 {
+		return errors.New("at least one collection holding personal data must be provided")
+	}
+	for _, col := range c.Collections {
+		if err := validateCollection(col); err != nil {
+			return err
+		}
+	}
+	if c.Audit != nil && (c.Audit.DbAlias == "" || c.Audit.Col == "") {
+		return errors.New("both the database and the collection of the audit reports must be provided")
+	}
+	m.config = c
+	return nil
+}
+
+// CloseConfig removes the config of the privacy module
+func (m *Module) CloseConfig() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = nil
+	return nil
+}
+
+func (m *Module) getConfig() (*config.PrivacyConfig, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.config == nil {
+		return nil, fmt.Errorf("privacy module is not enabled for project (%s)", m.project)
+	}
+	return m.config, nil
+}
+
+func validateCollection(c *config.PrivacyCollection) error {
+	if c.DbAlias == "" || c.Col == "" {
+		return errors.New("both the database and the collection holding personal data must be provided")
+	}
+	if c.Field == "" {
+		return fmt.Errorf("field holding the user id not provided for (%s) in database (%s)", c.Col, c.DbAlias)
+	}
+	switch getErasure(c) {
+	case config.PrivacyErasureDelete:
+	case config.PrivacyErasureAnonymize:
+		if len(c.Anonymize) == 0 {
+			return fmt.Errorf("fields to anonymize not provided for (%s) in database (%s)", c.Col, c.DbAlias)
+		}
+	default:
+		return fmt.Errorf("invalid erasure (%s) provided for (%s) in database (%s)", c.Erasure, c.Col, c.DbAlias)
+	}
+	return nil
+}
+
+func getErasure(c *config.PrivacyCollection) string {
+	if c.Erasure == "" {
+		return config.PrivacyErasureDelete
+	}
+	return c.Erasure
+}
@@ -0,0 +1,214 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type fakeCrud struct {
+	docs     map[string][]interface{} // The key here is dbAlias::col
+	batches  map[string]*model.BatchRequest
+	failDB   string
+	reports  []interface{}
+	auditErr error
+}
+
+func (c *fakeCrud) Create(_ context.Context, _, _ string, req *model.CreateRequest, _ model.RequestParams) error {
+	if c.auditErr != nil {
+		return c.auditErr
+	}
+	c.reports = append(c.reports, req.Document)
+	return nil
+}
+
+func (c *fakeCrud) Read(_ context.Context, dbAlias, col string, req *model.ReadRequest, _ model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	docs := c.find(dbAlias, col, req.Find)
+	if req.Operation == utils.Count {
+		return int64(len(docs)), nil, nil
+	}
+	return docs, nil, nil
+}
+
+func (c *fakeCrud) Batch(_ context.Context, dbAlias string, req *model.BatchRequest, _ model.RequestParams) error {
+	if dbAlias == c.failDB {
+		return errors.New("transaction aborted")
+	}
+	c.batches[dbAlias] = req
+	return nil
+}
+
+func (c *fakeCrud) Export(_ context.Context, dbAlias, col string, req *model.ReadRequest, _ int, _ model.RequestParams, write func(docs []interface{}) error) error {
+	return write(c.find(dbAlias, col, req.Find))
+}
+
+func (c *fakeCrud) find(dbAlias, col string, find map[string]interface{}) []interface{} {
+	docs := make([]interface{}, 0)
+	for _, doc := range c.docs[dbAlias+"::"+col] {
+		obj := doc.(map[string]interface{})
+		matches := true
+		for k, v := range find {
+			if obj[k] != v {
+				matches = false
+			}
+		}
+		if matches {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+func newFakeCrud() *fakeCrud {
+	return &fakeCrud{
+		docs: map[string][]interface{}{
+			"db::users":      {map[string]interface{}{"id": "1", "email": "a@b.c"}, map[string]interface{}{"id": "2", "email": "d@e.f"}},
+			"db::orders":     {map[string]interface{}{"id": "o1", "user_id": "1"}, map[string]interface{}{"id": "o2", "user_id": "1"}},
+			"events::clicks": {},
+		},
+		batches: map[string]*model.BatchRequest{},
+	}
+}
+
+var privacyConfig = &config.PrivacyConfig{
+	Enabled: true,
+	Collections: []*config.PrivacyCollection{
+		{DbAlias: "db", Col: "users", Field: "id"},
+		{DbAlias: "db", Col: "orders", Field: "user_id", Erasure: config.PrivacyErasureAnonymize, Anonymize: map[string]interface{}{"address": nil}},
+		{DbAlias: "events", Col: "clicks", Field: "user"},
+	},
+	Audit: &config.PrivacyAudit{DbAlias: "db", Col: "privacy_reports"},
+}
+
+func TestModule_Export(t *testing.T) {
+	c := newFakeCrud()
+	m := New("myproject", c)
+	if err := m.SetConfig(privacyConfig); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	export, err := m.Export(context.Background(), "1", &model.PrivacyRequest{Reason: "ticket 42"}, model.RequestParams{Claims: map[string]interface{}{"id": "admin"}})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	got := map[string]int{}
+	for _, col := range export.Collections {
+		got[col.Col] = len(col.Documents)
+	}
+	if want := map[string]int{"users": 1, "orders": 2, "clicks": 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Export() documents = %v, want %v", got, want)
+	}
+	if r := export.Report; r.Status != model.PrivacyStatusCompleted || r.RequestedBy != "admin" || r.Reason != "ticket 42" || len(r.Collections) != 3 {
+		t.Errorf("Export() report = %+v", r)
+	}
+	if len(c.reports) != 1 {
+		t.Errorf("Export() recorded %d reports, want 1", len(c.reports))
+	}
+}
+
+func TestModule_Erase(t *testing.T) {
+	tests := []struct {
+		name          string
+		failDB        string
+		auditErr      error
+		wantErr       bool
+		wantStatus    string
+		wantCommitted map[string]bool
+	}{
+		{
+			name:          "erased",
+			wantStatus:    model.PrivacyStatusCompleted,
+			wantCommitted: map[string]bool{"users": true, "orders": true, "clicks": true},
+		},
+		{
+			name:          "second database fails",
+			failDB:        "events",
+			wantErr:       true,
+			wantStatus:    model.PrivacyStatusFailed,
+			wantCommitted: map[string]bool{"users": true, "orders": true, "clicks": false},
+		},
+		{
+			name:          "first database fails",
+			failDB:        "db",
+			wantErr:       true,
+			wantStatus:    model.PrivacyStatusFailed,
+			wantCommitted: map[string]bool{"users": false, "orders": false},
+		},
+		{
+			name:          "report not recorded",
+			auditErr:      errors.New("database unavailable"),
+			wantErr:       true,
+			wantStatus:    model.PrivacyStatusCompleted,
+			wantCommitted: map[string]bool{"users": true, "orders": true, "clicks": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeCrud()
+			c.failDB, c.auditErr = tt.failDB, tt.auditErr
+			c.docs["events::clicks"] = []interface{}{map[string]interface{}{"user": "1"}}
+			m := New("myproject", c)
+			if err := m.SetConfig(privacyConfig); err != nil {
+				t.Fatalf("SetConfig() error = %v", err)
+			}
+
+			report, err := m.Erase(context.Background(), "1", nil, model.RequestParams{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Erase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("Erase() status = %v, want %v", report.Status, tt.wantStatus)
+			}
+			got := map[string]bool{}
+			for _, col := range report.Collections {
+				got[col.Col] = col.Committed
+			}
+			if !reflect.DeepEqual(got, tt.wantCommitted) {
+				t.Errorf("Erase() committed = %v, want %v", got, tt.wantCommitted)
+			}
+
+			if tt.failDB == "db" {
+				return
+			}
+			batch := c.batches["db"]
+			if batch == nil || len(batch.Requests) != 2 {
+				t.Fatalf("Erase() made batch %v on database (db), want the users deleted and the orders anonymized", batch)
+			}
+			if r := batch.Requests[0]; r.Type != string(model.Delete) || r.Col != "users" || !reflect.DeepEqual(r.Find, map[string]interface{}{"id": "1"}) {
+				t.Errorf("Erase() made request %+v for the users", r)
+			}
+			if r := batch.Requests[1]; r.Type != string(model.Update) || r.Col != "orders" || !reflect.DeepEqual(r.Update, map[string]interface{}{"$set": map[string]interface{}{"address": nil}}) {
+				t.Errorf("Erase() made request %+v for the orders", r)
+			}
+		})
+	}
+}
+
+func TestModule_SetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *config.PrivacyConfig
+		wantErr bool
+	}{
+		{name: "disabled", config: &config.PrivacyConfig{}},
+		{name: "valid", config: privacyConfig},
+		{name: "no collections", config: &config.PrivacyConfig{Enabled: true}, wantErr: true},
+		{name: "no field", config: &config.PrivacyConfig{Enabled: true, Collections: []*config.PrivacyCollection{{DbAlias: "db", Col: "users"}}}, wantErr: true},
+		{name: "nothing to anonymize", config: &config.PrivacyConfig{Enabled: true, Collections: []*config.PrivacyCollection{{DbAlias: "db", Col: "users", Field: "id", Erasure: config.PrivacyErasureAnonymize}}}, wantErr: true},
+		{name: "invalid erasure", config: &config.PrivacyConfig{Enabled: true, Collections: []*config.PrivacyCollection{{DbAlias: "db", Col: "users", Field: "id", Erasure: "truncate"}}}, wantErr: true},
+		{name: "audit without collection", config: &config.PrivacyConfig{Enabled: true, Collections: privacyConfig.Collections, Audit: &config.PrivacyAudit{DbAlias: "db"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New("myproject", newFakeCrud()).SetConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("SetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
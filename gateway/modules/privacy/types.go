@@ -0,0 +1,14 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type crudInterface interface {
+	Create(ctx context.Context, dbAlias, col string, req *model.CreateRequest, params model.RequestParams) error
+	Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error)
+	Batch(ctx context.Context, dbAlias string, req *model.BatchRequest, params model.RequestParams) error
+	Export(ctx context.Context, dbAlias, col string, req *model.ReadRequest, batchSize int, params model.RequestParams, write func(docs []interface{}) error) error
+}
@@ -83,6 +83,20 @@ func (m *Module) RemoveClient(clientID string) {
 	})
 }
 
+// SubscriptionCount returns the number of live queries tracked by this gateway
+func (m *Module) SubscriptionCount() int {
+	count := 0
+	m.groups.Range(func(_, value interface{}) bool {
+		clients := value.(*clientsStub)
+		clients.clients.Range(func(_, queries interface{}) bool {
+			count += mapLen(queries.(*sync.Map))
+			return true
+		})
+		return true
+	})
+	return count
+}
+
 func mapLen(m *sync.Map) int {
 	counter := 0
 	m.Range(func(k, v interface{}) bool {
@@ -3,6 +3,7 @@ package realtime
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -23,6 +24,16 @@ func (m *Module) Subscribe(clientID string, data *model.RealtimeRequest, sendFee
 	if data.Group == "" || data.DBType == "" || data.Where == nil {
 		return nil, errors.New("invalid request parameters provided")
 	}
+
+	// Read only databases don't have writes to feed the live queries
+	dbType, err := m.crud.GetDBType(data.DBType)
+	if err != nil {
+		return nil, err
+	}
+	if model.IsReadOnlyDB(dbType) {
+		return nil, fmt.Errorf("realtime is not supported for database (%s) of type %s", data.DBType, dbType)
+	}
+
 	readReq := model.ReadRequest{Find: data.Where, Operation: utils.All}
 
 	// Check if the user is authorised to make the request
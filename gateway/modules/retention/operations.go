@@ -0,0 +1,98 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// enforceBatch deletes or archives the oldest batch of the expired documents. It returns the number of documents
+// processed and whether more of them might be left.
+//
+// Batches are bound by the timestamp of their last document, since databases don't delete a limited number of
+// documents. The documents sharing the timestamp of the last document of a full batch are left for the next batch, so
+// that no document is deleted without being archived.
+func (m *Module) enforceBatch(ctx context.Context, p *policy, cutoff time.Time) (int, bool, error) {
+	column := p.config.Column
+	limit := int64(p.batchSize)
+	docs, err := m.read(ctx, p, map[string]interface{}{column: map[string]interface{}{"$lt": cutoff}}, &limit)
+	if err != nil || len(docs) == 0 {
+		return 0, false, err
+	}
+
+	bound, err := getTimestamp(docs[len(docs)-1], column)
+	if err != nil {
+		return 0, false, err
+	}
+	full := len(docs) == p.batchSize
+	find := map[string]interface{}{column: map[string]interface{}{"$lte": bound}}
+	if full {
+		i := len(docs)
+		for i > 0 {
+			v, _ := getTimestamp(docs[i-1], column)
+			if !reflect.DeepEqual(v, bound) {
+				break
+			}
+			i--
+		}
+		docs = docs[:i]
+		find = map[string]interface{}{column: map[string]interface{}{"$lt": bound}}
+
+		// The whole batch shares the same timestamp, hence all the documents with that timestamp make the batch
+		if len(docs) == 0 {
+			find = map[string]interface{}{column: bound}
+			all := int64(math.MaxInt32)
+			if docs, err = m.read(ctx, p, find, &all); err != nil {
+				return 0, false, err
+			}
+		}
+	}
+
+	attr := map[string]string{"project": m.project, "db": p.dbAlias, "col": p.col}
+	params := model.RequestParams{Resource: "db-delete", Op: "access", Attributes: attr}
+	del := &model.DeleteRequest{Find: find, Operation: utils.All}
+	if getAction(p.config) == config.RetentionActionDelete {
+		return len(docs), full, m.crud.Delete(ctx, p.dbAlias, p.col, del, params)
+	}
+
+	// The documents are archived and deleted in the same batch so that they are never lost or archived twice
+	err = m.crud.Batch(ctx, p.dbAlias, &model.BatchRequest{Requests: []*model.AllRequest{
+		{Type: string(model.Create), Col: p.config.ArchiveCol, Document: docs, Operation: utils.All, DBAlias: p.dbAlias},
+		{Type: string(model.Delete), Col: p.col, Find: del.Find, Operation: utils.All, DBAlias: p.dbAlias},
+	}}, params)
+	return len(docs), full, err
+}
+
+// read returns the expired documents sorted by their timestamp
+func (m *Module) read(ctx context.Context, p *policy, find map[string]interface{}, limit *int64) ([]interface{}, error) {
+	attr := map[string]string{"project": m.project, "db": p.dbAlias, "col": p.col}
+	params := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	req := &model.ReadRequest{Find: find, Operation: utils.All, Options: &model.ReadOptions{Sort: []string{p.config.Column}, Limit: limit, HasOptions: true}}
+	result, _, err := m.crud.Read(ctx, p.dbAlias, p.col, req, params)
+	if err != nil {
+		return nil, err
+	}
+	docs, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("database returned documents of unexpected type (%T)", result)
+	}
+	return docs, nil
+}
+
+func getTimestamp(doc interface{}, column string) (interface{}, error) {
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("database returned a document of unexpected type (%T)", doc)
+	}
+	v, ok := obj[column]
+	if !ok || v == nil {
+		return nil, fmt.Errorf("timestamp column (%s) not found in the document", column)
+	}
+	return v, nil
+}
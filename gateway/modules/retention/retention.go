@@ -0,0 +1,276 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+const (
+	// defaultInterval is used when the interval isn't set in the retention policy
+	defaultInterval = time.Hour
+
+	// defaultBatchSize is used when the batch size isn't set in the retention policy
+	defaultBatchSize = 1000
+
+	// schedulerTick is the interval at which the leader checks if a policy is due
+	schedulerTick = time.Minute
+
+	// batchTimeout is the maximum time a batch may take
+	batchTimeout = 5 * time.Minute
+)
+
+// Module enforces the retention policies of the collections of a project. Policies are only enforced on the leader
+// of the cluster, which deletes or archives the expired documents in batches.
+type Module struct {
+	lock sync.RWMutex
+
+	project  string
+	policies map[string]*policy // Key here is dbAlias::col
+
+	crud     crudInterface
+	isLeader func() bool
+
+	// cancel stops the scheduler along with the batch being enforced, after which stopped gets closed
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// policy is the retention policy of a collection along with the progress of enforcing it
+type policy struct {
+	dbAlias   string
+	col       string
+	config    *config.RetentionPolicy
+	interval  time.Duration
+	batchSize int
+
+	status    model.RetentionStatus
+	nextRunAt time.Time
+}
+
+// New creates a new instance of the retention module
+func New(project string, crud crudInterface, isLeader func() bool) *Module {
+	return &Module{project: project, crud: crud, isLeader: isLeader, policies: map[string]*policy{}, now: time.Now}
+}
+
+// SetConfig sets the retention policies from the database rules and starts enforcing them. The progress of the
+// policies which haven't changed is retained.
+func (m *Module) SetConfig(rules config.DatabaseRules) error {
+	policies := map[string]*policy{}
+	for _, rule := range rules {
+		c := rule.Retention
+		if c == nil || !c.Enabled {
+			continue
+		}
+		if err := validatePolicy(rule.DbAlias, rule.Table, c); err != nil {
+			return err
+		}
+
+		p := &policy{dbAlias: rule.DbAlias, col: rule.Table, config: c, interval: defaultInterval, batchSize: defaultBatchSize}
+		if c.Interval > 0 {
+			p.interval = time.Duration(c.Interval) * time.Second
+		}
+		if c.BatchSize > 0 {
+			p.batchSize = c.BatchSize
+		}
+		p.status = model.RetentionStatus{DbAlias: rule.DbAlias, Col: rule.Table, Action: getAction(c)}
+		policies[getKey(rule.DbAlias, rule.Table)] = p
+	}
+
+	m.stopScheduler()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for key, p := range policies {
+		if old, ok := m.policies[key]; ok {
+			p.status, p.nextRunAt = old.status, old.nextRunAt
+			p.status.Action, p.status.Running = getAction(p.config), false
+		}
+	}
+	m.policies = policies
+	if len(policies) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.stopped = make(chan struct{})
+	go m.routineSchedule(ctx, m.stopped)
+	return nil
+}
+
+// CloseConfig stops enforcing the retention policies
+func (m *Module) CloseConfig() error {
+	m.stopScheduler()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.policies = map[string]*policy{}
+	return nil
+}
+
+// GetStatus returns the progress of the retention policies enforced by this node
+func (m *Module) GetStatus() []*model.RetentionStatus {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	statuses := make([]*model.RetentionStatus, 0, len(m.policies))
+	for _, p := range m.policies {
+		status := p.status
+		if !p.nextRunAt.IsZero() {
+			nextRunAt := p.nextRunAt
+			status.NextRunAt = &nextRunAt
+		}
+		statuses = append(statuses, &status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return getKey(statuses[i].DbAlias, statuses[i].Col) < getKey(statuses[j].DbAlias, statuses[j].Col)
+	})
+	return statuses
+}
+
+// stopScheduler stops the scheduler and waits for it to return. The batch being enforced is cancelled, so that
+// changing the config doesn't have to wait for it to complete.
+func (m *Module) stopScheduler() {
+	m.lock.Lock()
+	cancel, stopped := m.cancel, m.stopped
+	m.cancel, m.stopped = nil, nil
+	m.lock.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-stopped
+}
+
+func (m *Module) routineSchedule(ctx context.Context, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !m.isLeader() {
+				continue
+			}
+			for _, p := range m.getDuePolicies() {
+				m.enforce(ctx, p)
+			}
+		}
+	}
+}
+
+// getDuePolicies returns the policies whose interval has passed since they were last enforced
+func (m *Module) getDuePolicies() []*policy {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := m.now()
+	policies := make([]*policy, 0)
+	for _, p := range m.policies {
+		if !now.Before(p.nextRunAt) {
+			policies = append(policies, p)
+		}
+	}
+	return policies
+}
+
+// enforce deletes or archives the documents of the collection which have expired. It stops as soon as the context
+// gets cancelled, which cancels the current batch as well.
+func (m *Module) enforce(parent context.Context, p *policy) {
+	start := m.now()
+	cutoff := start.AddDate(0, 0, -p.config.MaxAge)
+
+	m.lock.Lock()
+	p.status.Running, p.status.Processed, p.status.Batches, p.status.LastError = true, 0, 0, ""
+	p.status.LastRunAt = &start
+	m.lock.Unlock()
+
+	var err error
+	for more := true; more; {
+		if parent.Err() != nil {
+			break
+		}
+
+		var n int
+		ctx, cancel := context.WithTimeout(parent, batchTimeout)
+		n, more, err = m.enforceBatch(ctx, p, cutoff)
+		cancel()
+		if err != nil && parent.Err() != nil {
+			// The batch was cancelled since the config changed. It is enforced again in the next run.
+			err = nil
+			break
+		}
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to enforce the retention policy of (%s) in database (%s) of project (%s)", p.col, p.dbAlias, m.project), err, nil)
+			break
+		}
+		if n == 0 {
+			break
+		}
+
+		m.lock.Lock()
+		p.status.Processed += int64(n)
+		p.status.Batches++
+		processed := p.status.Processed
+		m.lock.Unlock()
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Enforced a batch of the retention policy of (%s) in database (%s)", p.col, p.dbAlias), map[string]interface{}{"project": m.project, "action": getAction(p.config), "batch": n, "processed": processed})
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	p.status.Running = false
+	p.status.LastRunTime = m.now().Sub(start).String()
+	if err != nil {
+		p.status.LastError = err.Error()
+	}
+	p.nextRunAt = start.Add(p.interval)
+	helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Enforced the retention policy of (%s) in database (%s) of project (%s)", p.col, p.dbAlias, m.project), map[string]interface{}{"action": getAction(p.config), "processed": p.status.Processed, "batches": p.status.Batches})
+}
+
+func validatePolicy(dbAlias, col string, c *config.RetentionPolicy) error {
+	if c.Column == "" {
+		return fmt.Errorf("timestamp column not provided in the retention policy of (%s) in database (%s)", col, dbAlias)
+	}
+	if c.MaxAge <= 0 {
+		return fmt.Errorf("max age of the retention policy of (%s) in database (%s) must be at least a day", col, dbAlias)
+	}
+	switch getAction(c) {
+	case config.RetentionActionDelete:
+	case config.RetentionActionArchive:
+		if c.ArchiveCol == "" || c.ArchiveCol == col {
+			return fmt.Errorf("a collection other than (%s) must be provided to archive its expired documents in database (%s)", col, dbAlias)
+		}
+	default:
+		return fmt.Errorf("invalid action (%s) provided in the retention policy of (%s) in database (%s)", c.Action, col, dbAlias)
+	}
+	return nil
+}
+
+func getAction(c *config.RetentionPolicy) string {
+	if c.Action == "" {
+		return config.RetentionActionDelete
+	}
+	return c.Action
+}
+
+func getKey(dbAlias, col string) string {
+	return fmt.Sprintf("%s::%s", dbAlias, col)
+}
@@ -0,0 +1,234 @@
+package retention
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// fakeCrud keeps the documents in memory. It understands the where clauses made by the retention module only.
+type fakeCrud struct {
+	docs map[string][]interface{} // The key here is the collection
+
+	// blocked makes the reads block until their context is done. It is closed once a read blocks.
+	blocked chan struct{}
+}
+
+func (c *fakeCrud) Read(ctx context.Context, _, col string, req *model.ReadRequest, _ model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	if c.blocked != nil {
+		close(c.blocked)
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+	docs := c.filter(col, req.Find, true)
+	sort.SliceStable(docs, func(i, j int) bool { return getTime(docs[i]).Before(getTime(docs[j])) })
+	if req.Options.Limit != nil && int64(len(docs)) > *req.Options.Limit {
+		docs = docs[:*req.Options.Limit]
+	}
+	return docs, nil, nil
+}
+
+func (c *fakeCrud) Delete(_ context.Context, _, col string, req *model.DeleteRequest, _ model.RequestParams) error {
+	c.docs[col] = c.filter(col, req.Find, false)
+	return nil
+}
+
+func (c *fakeCrud) Batch(_ context.Context, _ string, req *model.BatchRequest, _ model.RequestParams) error {
+	for _, r := range req.Requests {
+		switch r.Type {
+		case string(model.Create):
+			c.docs[r.Col] = append(c.docs[r.Col], r.Document.([]interface{})...)
+		case string(model.Delete):
+			c.docs[r.Col] = c.filter(r.Col, r.Find, false)
+		}
+	}
+	return nil
+}
+
+// filter returns the documents which either match the where clause or don't
+func (c *fakeCrud) filter(col string, find map[string]interface{}, match bool) []interface{} {
+	docs := make([]interface{}, 0)
+	for _, doc := range c.docs[col] {
+		if matches(getTime(doc), find["ts"]) == match {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+func matches(ts time.Time, cond interface{}) bool {
+	ops, ok := cond.(map[string]interface{})
+	if !ok {
+		return ts.Equal(cond.(time.Time))
+	}
+	for op, v := range ops {
+		switch op {
+		case "$lt":
+			if !ts.Before(v.(time.Time)) {
+				return false
+			}
+		case "$lte":
+			if ts.After(v.(time.Time)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func getTime(doc interface{}) time.Time {
+	return doc.(map[string]interface{})["ts"].(time.Time)
+}
+
+func ids(docs []interface{}) []string {
+	result := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, doc.(map[string]interface{})["id"].(string))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestModule_enforce(t *testing.T) {
+	now := time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -40)
+	newDocs := func() []interface{} {
+		return []interface{}{
+			map[string]interface{}{"id": "1", "ts": old},
+			map[string]interface{}{"id": "2", "ts": old.Add(time.Hour)},
+			map[string]interface{}{"id": "3", "ts": old.Add(time.Hour)},
+			map[string]interface{}{"id": "4", "ts": old.Add(time.Hour)},
+			map[string]interface{}{"id": "5", "ts": old.Add(2 * time.Hour)},
+			map[string]interface{}{"id": "6", "ts": now.AddDate(0, 0, -1)},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		policy      *config.RetentionPolicy
+		wantLeft    []string
+		wantArchive []string
+		wantBatches int
+	}{
+		{
+			name:        "delete",
+			policy:      &config.RetentionPolicy{Enabled: true, Column: "ts", MaxAge: 30, BatchSize: 2},
+			wantLeft:    []string{"6"},
+			wantBatches: 3,
+		},
+		{
+			name:        "archive",
+			policy:      &config.RetentionPolicy{Enabled: true, Column: "ts", MaxAge: 30, Action: config.RetentionActionArchive, ArchiveCol: "events_archive", BatchSize: 2},
+			wantLeft:    []string{"6"},
+			wantArchive: []string{"1", "2", "3", "4", "5"},
+			wantBatches: 3,
+		},
+		{
+			name:        "single batch",
+			policy:      &config.RetentionPolicy{Enabled: true, Column: "ts", MaxAge: 30},
+			wantLeft:    []string{"6"},
+			wantBatches: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &fakeCrud{docs: map[string][]interface{}{"events": newDocs()}}
+			m := New("myproject", c, func() bool { return true })
+			m.now = func() time.Time { return now }
+			if err := m.SetConfig(config.DatabaseRules{"events": {DbAlias: "db", Table: "events", Retention: tt.policy}}); err != nil {
+				t.Fatalf("SetConfig() error = %v", err)
+			}
+			defer func() { _ = m.CloseConfig() }()
+
+			for _, p := range m.getDuePolicies() {
+				m.enforce(context.Background(), p)
+			}
+
+			if got := ids(c.docs["events"]); !equal(got, tt.wantLeft) {
+				t.Errorf("enforce() left %v, want %v", got, tt.wantLeft)
+			}
+			if got := ids(c.docs["events_archive"]); !equal(got, tt.wantArchive) {
+				t.Errorf("enforce() archived %v, want %v", got, tt.wantArchive)
+			}
+
+			status := m.GetStatus()
+			if len(status) != 1 || status[0].Processed != 5 || status[0].Batches != tt.wantBatches || status[0].Running || status[0].LastError != "" {
+				t.Errorf("GetStatus() = %+v, want 5 documents processed in %d batches", status[0], tt.wantBatches)
+			}
+			if len(m.getDuePolicies()) != 0 {
+				t.Errorf("getDuePolicies() returned the policy right after it was enforced")
+			}
+		})
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_validatePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *config.RetentionPolicy
+		wantErr bool
+	}{
+		{name: "delete", policy: &config.RetentionPolicy{Column: "ts", MaxAge: 30}},
+		{name: "archive", policy: &config.RetentionPolicy{Column: "ts", MaxAge: 30, Action: config.RetentionActionArchive, ArchiveCol: "events_archive"}},
+		{name: "no column", policy: &config.RetentionPolicy{MaxAge: 30}, wantErr: true},
+		{name: "no max age", policy: &config.RetentionPolicy{Column: "ts"}, wantErr: true},
+		{name: "archive to itself", policy: &config.RetentionPolicy{Column: "ts", MaxAge: 30, Action: config.RetentionActionArchive, ArchiveCol: "events"}, wantErr: true},
+		{name: "invalid action", policy: &config.RetentionPolicy{Column: "ts", MaxAge: 30, Action: "truncate"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePolicy("db", "events", tt.policy); (err != nil) != tt.wantErr {
+				t.Errorf("validatePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModule_enforce_cancel(t *testing.T) {
+	c := &fakeCrud{docs: map[string][]interface{}{}, blocked: make(chan struct{})}
+	m := New("myproject", c, func() bool { return true })
+	policy := &config.RetentionPolicy{Enabled: true, Column: "ts", MaxAge: 30}
+	if err := m.SetConfig(config.DatabaseRules{"events": {DbAlias: "db", Table: "events", Retention: policy}}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	defer func() { _ = m.CloseConfig() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	enforced := make(chan struct{})
+	go func() {
+		defer close(enforced)
+		for _, p := range m.getDuePolicies() {
+			m.enforce(ctx, p)
+		}
+	}()
+
+	// Cancelling the context cancels the batch being enforced instead of waiting for it
+	<-c.blocked
+	cancel()
+	select {
+	case <-enforced:
+	case <-time.After(time.Second):
+		t.Fatalf("enforce() didn't return after its context was cancelled")
+	}
+
+	status := m.GetStatus()
+	if len(status) != 1 || status[0].Running || status[0].LastError != "" {
+		t.Errorf("GetStatus() = %+v, want the cancelled run to be recorded without an error", status[0])
+	}
+}
@@ -0,0 +1,13 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type crudInterface interface {
+	Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error)
+	Delete(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) error
+	Batch(ctx context.Context, dbAlias string, req *model.BatchRequest, params model.RequestParams) error
+}
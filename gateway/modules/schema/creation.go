@@ -29,7 +29,7 @@ func (s *Schema) SchemaCreation(ctx context.Context, dbAlias, tableName, logical
 	}
 
 	// Return gracefully if db type is mongo
-	if dbType == string(model.Mongo) || dbType == string(model.EmbeddedDB) {
+	if dbType == string(model.Mongo) || dbType == string(model.EmbeddedDB) || dbType == string(model.Redis) || model.IsReadOnlyDB(dbType) {
 		return nil
 	}
 
@@ -42,7 +42,15 @@ func (s *Schema) SchemaCreation(ctx context.Context, dbAlias, tableName, logical
 	if err != nil {
 		return err
 	}
-	return s.crud.RawBatch(ctx, dbAlias, queries)
+	if err := s.crud.RawBatch(ctx, dbAlias, queries); err != nil {
+		return err
+	}
+
+	// The history table of a collection is kept in sync with the collection itself
+	if historyTable, ok := schemaHelpers.GetHistoryTable(parsedSchema, dbAlias, tableName); ok {
+		return s.SchemaCreation(ctx, dbAlias, historyTable, logicalDBName, parsedSchema)
+	}
+	return nil
 }
 
 func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName, logicalDBName string, parsedSchema model.Type, currentSchema model.Collection) ([]string, error) {
@@ -88,6 +96,7 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 				Kind:                realColumnInfo.Kind,
 				IsPrimary:           realColumnInfo.IsPrimary,
 				NestedObject:        realColumnInfo.NestedObject,
+				EnumValues:          realColumnInfo.EnumValues,
 			}
 			currentTableInfo[realColumnName] = &temp
 		}
@@ -179,6 +188,11 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 
 		} else {
 			if !realColumnInfo.IsLinked {
+				// Columns which are no longer enums only need their check constraint to be removed
+				if c.currentColumnInfo.Kind == model.TypeEnum && c.realColumnInfo.Kind == model.TypeString && model.DBType(dbType) == model.Postgres {
+					batchedQueries = append(batchedQueries, c.removeEnumCheck(dbType))
+					c.currentColumnInfo.Kind = model.TypeString
+				}
 				if arr := deep.Equal(c.realColumnInfo.Args, c.currentColumnInfo.Args); c.realColumnInfo.Kind != c.currentColumnInfo.Kind || (c.realColumnInfo.TypeIDSize != c.currentColumnInfo.TypeIDSize) || (c.currentColumnInfo.Args != nil && len(arr) > 0) {
 					// As we are making sure that tables can only be created with primary key, this condition will occur if primary key is removed from a field
 					if c.realColumnInfo.IsPrimary {
@@ -190,6 +204,10 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 					// for changing the type of column, drop the column then add new column
 					queries := c.modifyColumnType(dbType)
 					batchedQueries = append(batchedQueries, queries...)
+				} else if c.realColumnInfo.Kind == model.TypeEnum && len(deep.Equal(c.realColumnInfo.EnumValues, c.currentColumnInfo.EnumValues)) > 0 {
+					// The values of enums enforced with a check constraint aren't inspected. Hence the constraint is
+					// replaced every time the schema is applied.
+					batchedQueries = append(batchedQueries, c.modifyEnum(dbType)...)
 				}
 				if c.currentColumnInfo.IsPrimary && (!c.realColumnInfo.IsPrimary || c.realColumnInfo.IsForeign || !c.realColumnInfo.IsFieldTypeRequired || c.realColumnInfo.IsDefault) {
 					return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf(`Mutation is not allowed on field ("%s") with primary key, Delete the table to change primary key`, c.ColumnName), nil, nil)
@@ -208,19 +226,31 @@ func (s *Schema) generateCreationQueries(ctx context.Context, dbAlias, tableName
 	}
 
 	for indexName, fields := range realIndexMap {
-		if _, ok := currentIndexMap[indexName]; !ok {
-			batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties))
+		if fields.Where != "" && model.DBType(dbType) == model.MySQL {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Partial index (%s) cannot be created - mysql doesn't support indexes with a where clause", indexName), nil, nil)
+		}
+		current, ok := currentIndexMap[indexName]
+		if !ok {
+			batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties, fields.Where))
 			continue
 		}
-		if arr := deep.Equal(fields.IndexTableProperties, cleanIndexMap(currentIndexMap[indexName].IndexTableProperties)); len(arr) > 0 {
-			batchedQueries = append(batchedQueries, s.removeIndex(dbType, dbAlias, logicalDBName, tableName, currentIndexMap[indexName].IndexName))
-			batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties))
+		if isIndexChanged(fields, current) {
+			batchedQueries = append(batchedQueries, s.removeIndex(dbType, dbAlias, logicalDBName, tableName, current.IndexName))
+			batchedQueries = append(batchedQueries, s.addIndex(dbType, dbAlias, logicalDBName, tableName, indexName, fields.IsIndexUnique, fields.IndexTableProperties, fields.Where))
 		}
 	}
 
 	return batchedQueries, nil
 }
 
+// isIndexChanged checks if an index in the database differs from the one declared in the schema
+func isIndexChanged(real, current *indexStruct) bool {
+	if arr := deep.Equal(real.IndexTableProperties, cleanIndexMap(current.IndexTableProperties)); len(arr) > 0 {
+		return true
+	}
+	return normalizeIndexPredicate(real.Where) != normalizeIndexPredicate(current.Where)
+}
+
 func cleanIndexMap(v []*model.TableProperties) []*model.TableProperties {
 	for _, indexInfo := range v {
 		indexInfo.ConstraintName = ""
@@ -1669,6 +1669,19 @@ func TestSchema_generateCreationQueries(t *testing.T) {
 			want:    []string{"CREATE TABLE test.table1 (id character varying(100) NOT NULL , col1 jsonb NOT NULL ,PRIMARY KEY (id));"},
 			wantErr: false,
 		},
+		{
+			name: "adding a table and column of type Point",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}, IsFieldTypeRequired: true}, "col1": &model.FieldType{FieldName: "col1", Kind: model.TypePoint, IsFieldTypeRequired: true}}}},
+				currentSchema: model.Collection{},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"CREATE TABLE test.table1 (id character varying(100) NOT NULL , col1 geometry(Point,4326) NOT NULL ,PRIMARY KEY (id));"},
+			wantErr: false,
+		},
 		{
 			name: "adding a table and column of type integer with default key",
 			args: args{
@@ -3498,6 +3511,69 @@ func TestSchema_generateCreationQueries(t *testing.T) {
 			want:    []string{"ALTER TABLE test.table1 ADD COLUMN col2 integer", "CREATE INDEX index__table1__i2 ON test.table1 (col2 asc)"},
 			wantErr: false,
 		},
+		{
+			name: "adding partial index",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsUnique: true, Group: "i1", Order: 1, Sort: "asc", Where: "status = 'active'"}}}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col2", IsUnique: true, Group: "i1", Order: 2, Sort: "asc"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeInteger}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"CREATE UNIQUE INDEX index__table1__i1 ON test.table1 (col1 asc, col2 asc) WHERE status = 'active'"},
+			wantErr: false,
+		},
+		{
+			name: "partial index rewritten by the database",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsIndex: true, Group: "i1", Order: 1, Sort: "asc", Where: "status = 'active'"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsIndex: true, Group: "i1", ConstraintName: getIndexName("table1", "i1"), Order: 1, Sort: "asc", Where: "((status)::text = 'active'::text)"}}}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{},
+			wantErr: false,
+		},
+		{
+			name: "changing where clause of partial index",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsIndex: true, Group: "i1", Order: 1, Sort: "asc", Where: "deleted_at IS NULL"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsIndex: true, Group: "i1", ConstraintName: getIndexName("table1", "i1"), Order: 1, Sort: "asc", Where: "((status)::text = 'active'::text)"}}}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"DROP INDEX test.index__table1__i1", "CREATE INDEX index__table1__i1 ON test.table1 (col1 asc) WHERE deleted_at IS NULL"},
+			wantErr: false,
+		},
+		{
+			name: "partial index with different where clauses on its columns",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsIndex: true, Group: "i1", Order: 1, Sort: "asc", Where: "col1 > 0"}}}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col2", IsIndex: true, Group: "i1", Order: 2, Sort: "asc", Where: "col2 > 0"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger}, "col2": &model.FieldType{FieldName: "col2", Kind: model.TypeInteger}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			wantErr: true,
+		},
+		{
+			name: "partial index in mysql",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger, IndexInfo: []*model.TableProperties{{Field: "col1", IsIndex: true, Group: "i1", Order: 1, Sort: "asc", Where: "col1 > 0"}}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeInteger}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			wantErr: true,
+		},
 		{
 			name: "changing index to unique",
 			args: args{
@@ -3884,6 +3960,84 @@ func TestSchema_generateCreationQueries(t *testing.T) {
 			fields:  fields{crud: crudPostgres, project: "test"},
 			wantErr: false,
 		},
+		{
+			name: "Mysql adding a table with a column of type enum",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsFieldTypeRequired: true, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}}, "col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE", "BLOCKED"}}}}},
+				currentSchema: model.Collection{},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{"CREATE TABLE table1 (id varchar(100) NOT NULL , col1 enum('ACTIVE','BLOCKED') ,PRIMARY KEY (id));"},
+			wantErr: false,
+		},
+		{
+			name: "Postgres adding a table with a column of type enum",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, TypeIDSize: model.DefaultCharacterSize, IsFieldTypeRequired: true, IsPrimary: true, PrimaryKeyInfo: &model.TableProperties{}}, "col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE", "BLOCKED"}}}}},
+				currentSchema: model.Collection{},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"CREATE TABLE test.table1 (id character varying(100) NOT NULL , col1 text constraint enum_check_table1_col1 CHECK (col1 IN ('ACTIVE','BLOCKED')) ,PRIMARY KEY (id));"},
+			wantErr: false,
+		},
+		{
+			name: "Mysql changing the values of an enum",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE", "BLOCKED"}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE"}}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{"ALTER TABLE table1 MODIFY col1 enum('ACTIVE','BLOCKED')"},
+			wantErr: false,
+		},
+		{
+			name: "Mysql no queries generated when the values of an enum are unchanged",
+			args: args{
+				dbAlias:       "mysql",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"mysql": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE", "BLOCKED"}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE", "BLOCKED"}}}},
+			},
+			fields:  fields{crud: crudMySQL, project: "test"},
+			want:    []string{},
+			wantErr: false,
+		},
+		{
+			name: "Postgres replacing the check constraint of an inspected enum",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum, EnumValues: []string{"ACTIVE", "BLOCKED"}}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"ALTER TABLE test.table1 DROP CONSTRAINT IF EXISTS enum_check_table1_col1", "ALTER TABLE test.table1 ADD constraint enum_check_table1_col1 CHECK (col1 IN ('ACTIVE','BLOCKED'))"},
+			wantErr: false,
+		},
+		{
+			name: "Postgres removing the check constraint of a column which is no longer an enum",
+			args: args{
+				dbAlias:       "postgres",
+				tableName:     "table1",
+				project:       "test",
+				parsedSchema:  model.Type{"postgres": model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeString}}}},
+				currentSchema: model.Collection{"table1": model.Fields{"col1": &model.FieldType{FieldName: "col1", Kind: model.TypeEnum}}},
+			},
+			fields:  fields{crud: crudPostgres, project: "test"},
+			want:    []string{"ALTER TABLE test.table1 DROP CONSTRAINT IF EXISTS enum_check_table1_col1"},
+			wantErr: false,
+		},
 	}
 
 	testCases := make([]testGenerateCreationQueries, 0)
@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -10,16 +11,24 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/geo"
 )
 
+// indexPredicateCast matches the type casts postgres adds to the predicate of a partial index
+var indexPredicateCast = regexp.MustCompile(`::\w+(\s+varying)?`)
+
 // GetSQLType return sql type
 func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldType) (string, error) {
 	switch realColumnInfo.Kind {
 	case model.TypeUUID:
-		if dbType == string(model.Postgres) {
+		switch dbType {
+		case string(model.Postgres):
 			return "uuid", nil
+		case string(model.MySQL):
+			return "char(36)", nil
+		case string(model.SQLServer):
+			return "uniqueidentifier", nil
 		}
-		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "UUID type is only supported by postgres database", nil, nil)
 	case model.TypeTime:
 		return fmt.Sprintf("time(%d)", realColumnInfo.Args.Precision), nil
 	case model.TypeDate:
@@ -102,6 +111,16 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 		case string(model.MySQL), string(model.SQLServer):
 			return fmt.Sprintf("decimal(%d,%d)", realColumnInfo.Args.Precision, realColumnInfo.Args.Scale), nil
 		}
+	case model.TypeEnum:
+		// Mysql has a native enum type. The values of enums are enforced with a check constraint in other databases.
+		switch dbType {
+		case string(model.Postgres):
+			return "text", nil
+		case string(model.MySQL):
+			return fmt.Sprintf("enum(%s)", quoteEnumValues(realColumnInfo.EnumValues)), nil
+		case string(model.SQLServer):
+			return fmt.Sprintf("nvarchar(%d)", model.DefaultCharacterSize), nil
+		}
 	case model.TypeInteger:
 		return "integer", nil
 	case model.TypeSmallInteger:
@@ -117,6 +136,12 @@ func getSQLType(ctx context.Context, dbType string, realColumnInfo *model.FieldT
 		case string(model.SQLServer):
 			return "nvarchar(max)", nil
 		}
+	case model.TypePoint, model.TypeGeometry:
+		// Geometries are only supported by postgres with the PostGIS extension
+		if dbType != string(model.Postgres) {
+			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("%s not supported for database %s", realColumnInfo.Kind, dbType), nil, nil)
+		}
+		return fmt.Sprintf("geometry(%s,%d)", realColumnInfo.Kind, geo.SRID), nil
 	default:
 		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid schema type (%s) provided", realColumnInfo.Kind), fmt.Errorf("%s type not allowed", realColumnInfo.Kind), nil)
 	}
@@ -203,6 +228,9 @@ func (c *creationModule) addNewColumn() string {
 	case model.MySQL:
 		return "ALTER TABLE " + c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName) + " ADD " + c.ColumnName + " " + c.columnType
 	case model.Postgres:
+		if c.realColumnInfo.Kind == model.TypeEnum {
+			return "ALTER TABLE " + c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName) + " ADD COLUMN " + c.ColumnName + " " + c.columnType + " " + getEnumCheck(c.TableName, c.realColumnInfo)
+		}
 		return "ALTER TABLE " + c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName) + " ADD COLUMN " + c.ColumnName + " " + c.columnType
 	case model.SQLServer:
 		if c.columnType == "timestamp" && !c.realColumnInfo.IsFieldTypeRequired {
@@ -213,11 +241,57 @@ func (c *creationModule) addNewColumn() string {
 		if c.columnType == "nvarchar(max)" && c.realColumnInfo.Kind == model.TypeJSON {
 			sqlDataType = fmt.Sprintf("%s constraint json_check_%s_%s CHECK (ISJSON(%s)=1)", c.columnType, c.TableName, c.ColumnName, c.ColumnName)
 		}
+		if c.realColumnInfo.Kind == model.TypeEnum {
+			sqlDataType += " " + getEnumCheck(c.TableName, c.realColumnInfo)
+		}
 		return "ALTER TABLE " + c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName) + " ADD " + c.ColumnName + " " + sqlDataType
 	}
 	return ""
 }
 
+// modifyEnum updates the values allowed in an enum column
+func (c *creationModule) modifyEnum(dbType string) []string {
+	c.currentColumnInfo.EnumValues = c.realColumnInfo.EnumValues // Mark the field as processed
+	tableName := c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName)
+	switch model.DBType(dbType) {
+	case model.MySQL:
+		query := "ALTER TABLE " + tableName + " MODIFY " + c.ColumnName + " " + c.columnType
+		if c.realColumnInfo.IsFieldTypeRequired {
+			query += " NOT NULL"
+		}
+		return []string{query}
+	case model.Postgres, model.SQLServer:
+		return []string{
+			"ALTER TABLE " + tableName + " DROP CONSTRAINT IF EXISTS " + getEnumCheckName(c.TableName, c.ColumnName),
+			"ALTER TABLE " + tableName + " ADD " + getEnumCheck(c.TableName, c.realColumnInfo),
+		}
+	}
+	return nil
+}
+
+// removeEnumCheck removes the check constraint of a column which is no longer an enum
+func (c *creationModule) removeEnumCheck(dbType string) string {
+	return "ALTER TABLE " + c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName) + " DROP CONSTRAINT IF EXISTS " + getEnumCheckName(c.TableName, c.ColumnName)
+}
+
+// getEnumCheckName returns the name of the check constraint enforcing the values of an enum column. Inspection relies
+// on this name to identify enum columns.
+func getEnumCheckName(tableName, columnName string) string {
+	return fmt.Sprintf("enum_check_%s_%s", tableName, columnName)
+}
+
+func getEnumCheck(tableName string, field *model.FieldType) string {
+	return fmt.Sprintf("constraint %s CHECK (%s IN (%s))", getEnumCheckName(tableName, field.FieldName), field.FieldName, quoteEnumValues(field.EnumValues))
+}
+
+func quoteEnumValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + strings.Replace(value, "'", "''", -1) + "'"
+	}
+	return strings.Join(quoted, ",")
+}
+
 func (c *creationModule) removeColumn(dbType string) []string {
 	queries := c.removeDirectives(dbType)
 	return append(queries, "ALTER TABLE "+c.schemaModule.getTableName(dbType, c.logicalDBName, c.TableName)+" DROP COLUMN "+c.ColumnName+"")
@@ -404,6 +478,9 @@ func (s *Schema) addNewTable(ctx context.Context, logicalDBName, dbType, dbAlias
 		if model.DBType(dbType) == model.SQLServer && realFieldStruct.Kind == model.TypeJSON && sqlType == "nvarchar(max)" {
 			query += fmt.Sprintf(" constraint json_check_%s_%s CHECK (ISJSON(%s)=1)", realColName, realFieldStruct.FieldName, realFieldStruct.FieldName)
 		}
+		if model.DBType(dbType) != model.MySQL && realFieldStruct.Kind == model.TypeEnum {
+			query += " " + getEnumCheck(realColName, realFieldStruct)
+		}
 		if realFieldStruct.IsFieldTypeRequired {
 			query += " NOT NULL"
 		}
@@ -541,6 +618,11 @@ func (c *creationModule) removeDirectives(dbType string) []string {
 		c.currentColumnInfo.IsDefault = false
 	}
 
+	// Sql server doesn't drop the constraints of a column along with it
+	if c.currentColumnInfo.Kind == model.TypeEnum && model.DBType(dbType) == model.SQLServer {
+		queries = append(queries, c.removeEnumCheck(dbType))
+	}
+
 	// if c.currentColumnInfo.IsPrimary {
 	// 	queries = append(queries, c.removePrimaryKey())
 	// 	c.currentColumnInfo.IsPrimary = false
@@ -571,7 +653,7 @@ func (c *creationModule) modifyColumnType(dbType string) []string {
 	return queries
 }
 
-func (s *Schema) addIndex(dbType, dbAlias, logicalDBName, tableName, indexName string, isIndexUnique bool, mapArray []*model.TableProperties) string {
+func (s *Schema) addIndex(dbType, dbAlias, logicalDBName, tableName, indexName string, isIndexUnique bool, mapArray []*model.TableProperties, where string) string {
 	a := " ("
 	for _, schemaFieldType := range mapArray {
 		a += schemaFieldType.Field + " " + schemaFieldType.Sort + ", "
@@ -583,6 +665,9 @@ func (s *Schema) addIndex(dbType, dbAlias, logicalDBName, tableName, indexName s
 	} else {
 		p = "CREATE INDEX " + getIndexName(tableName, indexName) + " ON " + s.getTableName(dbType, logicalDBName, tableName) + a + ")"
 	}
+	if where != "" {
+		p += " WHERE " + where
+	}
 	return p
 }
 
@@ -600,6 +685,14 @@ func (s *Schema) removeIndex(dbType, dbAlias, logicalDBName, tableName, indexNam
 	return ""
 }
 
+// normalizeIndexPredicate brings the predicate of a partial index to a form which can be compared with the one reported
+// by the database. Databases rewrite the predicate by adding parentheses, quotes and type casts to it.
+func normalizeIndexPredicate(where string) string {
+	where = indexPredicateCast.ReplaceAllString(where, "")
+	where = strings.NewReplacer("(", "", ")", "", "[", "", "]", "", `"`, "", "`", "").Replace(where)
+	return strings.ToLower(strings.Join(strings.Fields(where), ""))
+}
+
 func getIndexName(tableName, indexName string) string {
 	return fmt.Sprintf("index__%s__%s", tableName, indexName)
 }
@@ -608,6 +701,8 @@ type indexStruct struct {
 	IsIndexUnique        bool
 	IndexTableProperties []*model.TableProperties
 	IndexName            string
+	// Where is the predicate of a partial index
+	Where string
 }
 
 func getIndexMap(ctx context.Context, tableInfo model.Fields) (map[string]*indexStruct, error) {
@@ -625,8 +720,18 @@ func getIndexMap(ctx context.Context, tableInfo model.Fields) (map[string]*index
 					value = &indexStruct{IndexName: indexInfo.ConstraintName, IndexTableProperties: []*model.TableProperties{}}
 					indexMap[indexInfo.Group] = value
 				}
+				// The predicate of a partial index belongs to the index as a whole. It can be declared on any of its columns.
+				if indexInfo.Where != "" {
+					if value.Where != "" && value.Where != indexInfo.Where {
+						return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Different where clauses provided for index (%s)", indexInfo.Group), nil, nil)
+					}
+					value.Where = indexInfo.Where
+				}
+				column := *indexInfo
+				column.Where = ""
+
 				// value.IndexMap = append(value.IndexMap, columnInfo)
-				value.IndexTableProperties = append(value.IndexTableProperties, indexInfo)
+				value.IndexTableProperties = append(value.IndexTableProperties, &column)
 
 				// Mark the index group as unique if even on column had the unique tag
 				if indexInfo.IsUnique {
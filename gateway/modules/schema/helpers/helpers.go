@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/kinds"
+	uuid "github.com/satori/go.uuid"
 	"github.com/spaceuptech/helpers"
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/geo"
 )
 
 func checkType(ctx context.Context, dbAlias, dbType, col string, value interface{}, fieldValue *model.FieldType) (interface{}, error) {
@@ -38,8 +42,38 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 			if err != nil {
 				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid datetime format recieved for field %s in collection %s - use RFC3339 fromat", fieldValue.FieldName, col), err, nil)
 			}
+			if fieldValue.Kind == model.TypeDateTime {
+				// Columns without a time zone store the time in UTC
+				return unitTimeInRFC3339Nano.UTC(), nil
+			}
 			return unitTimeInRFC3339Nano, nil
-		case model.TypeID, model.TypeString, model.TypeTime, model.TypeDate, model.TypeUUID, model.TypeVarChar, model.TypeChar:
+		case model.TypeUUID:
+			id, err := uuid.FromString(v)
+			if err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid uuid received for field %s in collection %s", fieldValue.FieldName, col), err, nil)
+			}
+			return id.String(), nil
+		case model.TypeDecimal:
+			// Decimals are sent as strings to retain their precision which would be lost on converting them to floats
+			if _, ok := new(big.Float).SetString(v); !ok {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid decimal (%s) received for field %s in collection %s", v, fieldValue.FieldName, col), nil, nil)
+			}
+			return v, nil
+		case model.TypeBigInteger:
+			// Big integers are sent as strings since json numbers can't represent integers larger than 2^53 exactly
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid big integer (%s) received for field %s in collection %s", v, fieldValue.FieldName, col), err, nil)
+			}
+			return i, nil
+		case model.TypeEnum:
+			for _, allowed := range fieldValue.EnumValues {
+				if v == allowed {
+					return value, nil
+				}
+			}
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid value (%s) received for enum field %s in collection %s - allowed values are %v", v, fieldValue.FieldName, col, fieldValue.EnumValues), nil, nil)
+		case model.TypeID, model.TypeString, model.TypeTime, model.TypeDate, model.TypeVarChar, model.TypeChar:
 			return value, nil
 		default:
 			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid type received for field %s in collection %s - wanted %s got String", fieldValue.FieldName, col, fieldValue.Kind), nil, nil)
@@ -68,6 +102,9 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 		return v, nil
 
 	case map[string]interface{}:
+		if fieldValue.Kind == model.TypePoint || fieldValue.Kind == model.TypeGeometry {
+			return checkGeometry(ctx, dbType, col, v, fieldValue)
+		}
 		if fieldValue.Kind == model.TypeJSON {
 			if model.DBType(dbType) == model.Mongo {
 				return value, nil
@@ -107,20 +144,58 @@ func checkType(ctx context.Context, dbAlias, dbType, col string, value interface
 	}
 }
 
-func validateArrayOperations(ctx context.Context, dbAlias, dbType, col string, doc interface{}, SchemaDoc model.Fields) error {
+// checkGeometry validates the value of a geometry field. Geometries are stored as GeoJSON in mongo and in the EWKT
+// format everywhere else.
+func checkGeometry(ctx context.Context, dbType, col string, value map[string]interface{}, fieldValue *model.FieldType) (interface{}, error) {
+	g, err := geo.ParseGeometry(value)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid geometry received for field %s in collection %s", fieldValue.FieldName, col), err, nil)
+	}
+	if fieldValue.Kind == model.TypePoint && g["type"] != geo.TypePoint {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("invalid geometry received for field %s in collection %s - wanted %s got %v", fieldValue.FieldName, col, geo.TypePoint, g["type"]), nil, nil)
+	}
+
+	if model.DBType(dbType) == model.Mongo {
+		return g, nil
+	}
+	return geo.ToEWKT(g)
+}
+
+// validateArrayOperations validates the values of the $push, $addToSet and $pull operators. The values to be added or
+// removed can either be provided directly or as an array within the $each (or $in for $pull) modifier.
+func validateArrayOperations(ctx context.Context, dbAlias, dbType, col, op string, doc interface{}, SchemaDoc model.Fields) error {
 
 	v, ok := doc.(map[string]interface{})
 	if !ok {
 		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Document not of type object in collection %s", col), nil, nil)
 	}
 
+	modifier := "$each"
+	if op == "$pull" {
+		modifier = "$in"
+	}
+
 	for fieldKey, fieldValue := range v {
 
-		schemaDocValue, ok := SchemaDoc[fieldKey]
+		schemaDocValue, ok := SchemaDoc[strings.Split(fieldKey, ".")[0]]
 		if !ok {
 			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field %s from collection %s is not defined in the schema", fieldKey, col), nil, nil)
 		}
 
+		// Arrays stored in json fields can hold values of any type
+		if schemaDocValue.Kind == model.TypeJSON {
+			continue
+		}
+
+		if obj, ok := fieldValue.(map[string]interface{}); ok && len(obj) == 1 {
+			if values, ok := obj[modifier]; ok {
+				if _, ok := values.([]interface{}); !ok {
+					return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Value of %s for field %s in collection %s should be an array", modifier, fieldKey, col), nil, nil)
+				}
+				fieldValue = values
+			}
+		}
+
 		switch t := fieldValue.(type) {
 		case []interface{}:
 			if schemaDocValue.IsForeign && !schemaDocValue.IsList {
@@ -131,7 +206,6 @@ func validateArrayOperations(ctx context.Context, dbAlias, dbType, col string, d
 					return err
 				}
 			}
-			return nil
 		case interface{}:
 			if _, err := checkType(ctx, dbAlias, dbType, col, t, schemaDocValue); err != nil {
 				return err
@@ -156,20 +230,19 @@ func validateMathOperations(ctx context.Context, col string, doc interface{}, Sc
 		if !ok {
 			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field %s from collection %s is not defined in the schema", fieldKey, col), nil, nil)
 		}
-		if schemaDocValue.Kind == model.TypeInteger && reflect.TypeOf(fieldValue).Kind() == reflect.Float64 {
+		isInteger := schemaDocValue.Kind == model.TypeInteger || schemaDocValue.Kind == model.TypeSmallInteger || schemaDocValue.Kind == model.TypeBigInteger
+		if isInteger && reflect.TypeOf(fieldValue).Kind() == reflect.Float64 {
 			fieldValue = int(fieldValue.(float64))
 		}
 		switch fieldValue.(type) {
 		case int:
-			if schemaDocValue.Kind != model.TypeInteger && schemaDocValue.Kind != model.TypeFloat {
+			if !isInteger && schemaDocValue.Kind != model.TypeFloat {
 				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type received for field %s in collection %s - wanted %s got Integer", fieldKey, col, schemaDocValue.Kind), nil, nil)
 			}
-			return nil
 		case float32, float64:
 			if schemaDocValue.Kind != model.TypeFloat {
 				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type received for field %s in collection %s - wanted %s got Float", fieldKey, col, schemaDocValue.Kind), nil, nil)
 			}
-			return nil
 		default:
 			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid type received for field %s in collection %s - wanted %s", fieldKey, col, schemaDocValue.Kind), nil, nil)
 		}
@@ -248,11 +321,19 @@ func validateSetOperation(ctx context.Context, dbAlias, dbType, col string, doc
 		if !ok {
 			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field (%s) from collection (%s) is not defined in the schema", key, col), nil, nil)
 		}
+		// Fields within a json document can hold any value. They are left as is for the database module to set.
+		if SchemaDocValue.Kind == model.TypeJSON && strings.Contains(key, ".") {
+			newMap[key] = value
+			continue
+		}
 		// check type
 		newDoc, err := checkType(ctx, dbAlias, dbType, col, value, SchemaDocValue)
 		if err != nil {
 			return nil, err
 		}
+		if err := validateField(col, newDoc, SchemaDocValue); err != nil {
+			return nil, err
+		}
 		newMap[key] = newDoc
 	}
 
@@ -280,16 +361,17 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 	var isCollectionFound bool
 
 	fieldMap := model.Fields{}
-	for _, v := range doc.Definitions {
-		colName := v.(*ast.ObjectDefinition).Name.Value
-		if colName != collectionName {
+	for _, definition := range doc.Definitions {
+		// Enum definitions are looked up while parsing the fields which use them
+		v, ok := definition.(*ast.ObjectDefinition)
+		if !ok || v.Name.Value != collectionName {
 			continue
 		}
 
 		// Mark the collection as found
 		isCollectionFound = true
 
-		for _, field := range v.(*ast.ObjectDefinition).Fields {
+		for _, field := range v.Fields {
 
 			if field.Type == nil {
 				return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Type not provided in graphql SDL for collection/table schema (%s) with field (%s)", collectionName, field.Name.Value), nil, nil)
@@ -305,6 +387,12 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 					switch directive.Name.Value {
 					case model.DirectiveAutoIncrement:
 						fieldTypeStuct.IsAutoIncrement = true
+					case model.DirectiveAutoGenerate:
+						fieldTypeStuct.IsAutoGenerate = true
+					case model.DirectiveMin, model.DirectiveMax, model.DirectiveLength, model.DirectiveRegex, model.DirectiveEmail:
+						if err := parseValidationDirective(&fieldTypeStuct, directive); err != nil {
+							return nil, err
+						}
 					case model.DirectivePrimary:
 						fieldTypeStuct.IsPrimary = true
 						fieldTypeStuct.PrimaryKeyInfo = &model.TableProperties{}
@@ -356,6 +444,8 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 						fieldTypeStuct.IsCreatedAt = true
 					case model.DirectiveUpdatedAt:
 						fieldTypeStuct.IsUpdatedAt = true
+					case model.DirectiveVersion:
+						fieldTypeStuct.IsVersion = true
 					case model.DirectiveStringSize:
 						for _, arg := range directive.Arguments {
 							switch arg.Name.Value {
@@ -418,6 +508,12 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 									return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unknow value provided for field (%s) directive @(%s) argument (%s) got (%v) expected either (asc) or (desc)", fieldTypeStuct.FieldName, directive.Name.Value, arg.Name.Value, reflect.TypeOf(val)), nil, map[string]interface{}{"arg": arg.Name.Value})
 								}
 								indexInfo.Sort = sort
+							case "where":
+								val, _ := utils.ParseGraphqlValue(arg.Value, nil)
+								indexInfo.Where, ok = val.(string)
+								if !ok || strings.TrimSpace(indexInfo.Where) == "" {
+									return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unexpected argument type provided for field (%s) directive @(%s) argument (%s) got (%v) expected a non empty string", fieldTypeStuct.FieldName, directive.Name.Value, arg.Name.Value, reflect.TypeOf(val)), nil, map[string]interface{}{"arg": arg.Name.Value})
+								}
 							}
 						}
 						if indexInfo.Group == "" {
@@ -501,6 +597,15 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 				return nil, err
 			}
 			fieldTypeStuct.Kind = kind
+			if fieldTypeStuct.IsVersion && (fieldTypeStuct.IsList || (kind != model.TypeInteger && kind != model.TypeBigInteger)) {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Version directive can only be added on fields of type Integer or BigInteger - field (%s) has type (%s)", fieldTypeStuct.FieldName, kind), nil, nil)
+			}
+			if fieldTypeStuct.IsAutoGenerate && (fieldTypeStuct.IsList || kind != model.TypeUUID) {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Auto generate directive can only be added on fields of type UUID - field (%s) has type (%s)", fieldTypeStuct.FieldName, kind), nil, nil)
+			}
+			if err := checkValidationKind(&fieldTypeStuct); err != nil {
+				return nil, err
+			}
 			// Set defaults
 			switch kind {
 			case model.TypeTime, model.TypeDateTime, model.TypeDateTimeWithZone:
@@ -539,6 +644,23 @@ func getCollectionSchema(doc *ast.Document, dbName, collectionName string) (mode
 	return fieldMap, nil
 }
 
+// getEnumValues returns the values of the enum declared with the provided name
+func getEnumValues(doc *ast.Document, name string) ([]string, bool) {
+	for _, definition := range doc.Definitions {
+		enum, ok := definition.(*ast.EnumDefinition)
+		if !ok || enum.Name.Value != name {
+			continue
+		}
+
+		values := make([]string, len(enum.Values))
+		for i, value := range enum.Values {
+			values[i] = value.Name.Value
+		}
+		return values, true
+	}
+	return nil, false
+}
+
 func getFieldType(dbName string, fieldType ast.Type, fieldTypeStuct *model.FieldType, doc *ast.Document) (string, error) {
 	switch fieldType.GetKind() {
 	case kinds.NonNull:
@@ -588,6 +710,10 @@ func getFieldType(dbName string, fieldType ast.Type, fieldTypeStuct *model.Field
 			return model.TypeDate, nil
 		case model.TypeUUID:
 			return model.TypeUUID, nil
+		case model.TypePoint:
+			return model.TypePoint, nil
+		case model.TypeGeometry:
+			return model.TypeGeometry, nil
 
 		default:
 			if fieldTypeStuct.IsLinked {
@@ -596,6 +722,12 @@ func getFieldType(dbName string, fieldType ast.Type, fieldTypeStuct *model.Field
 				return myType, nil
 			}
 
+			// The field is an enum if an enum with the same name is declared in the schema
+			if values, ok := getEnumValues(doc, myType); ok {
+				fieldTypeStuct.EnumValues = values
+				return model.TypeEnum, nil
+			}
+
 			// The field is a nested type. Update the nestedObject field and return typeObject. This is a side effect.
 			nestedschemaField, err := getCollectionSchema(doc, dbName, myType)
 			if err != nil {
@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// GetHistoryTable returns the name of the history table of a collection if the collection is marked with the
+// @history directive
+func GetHistoryTable(schemaDoc model.Type, dbAlias, col string) (string, bool) {
+	table := col + model.HistoryTableSuffix
+	fields, ok := schemaDoc[dbAlias][table]
+	if !ok {
+		return "", false
+	}
+
+	// A collection might be named like a history table without being one
+	if _, ok := fields[model.HistoryFieldAt]; !ok {
+		return "", false
+	}
+	return table, true
+}
+
+// IsHistoryTable checks if the collection is the history table of another collection
+func IsHistoryTable(schemaDoc model.Type, dbAlias, col string) bool {
+	if len(col) <= len(model.HistoryTableSuffix) || col[len(col)-len(model.HistoryTableSuffix):] != model.HistoryTableSuffix {
+		return false
+	}
+	table, ok := GetHistoryTable(schemaDoc, dbAlias, col[:len(col)-len(model.HistoryTableSuffix)])
+	return ok && table == col
+}
+
+// isHistoryEnabled checks if the type of a collection is marked with the @history directive
+func isHistoryEnabled(doc *ast.Document, collectionName string) bool {
+	for _, definition := range doc.Definitions {
+		v, ok := definition.(*ast.ObjectDefinition)
+		if !ok || v.Name.Value != collectionName {
+			continue
+		}
+		for _, directive := range v.Directives {
+			if directive.Name.Value == model.DirectiveHistory {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getHistorySchema returns the schema of the history table of a collection. It holds the fields of the collection
+// without their constraints, since a document has many versions in it, along with the fields describing each version.
+func getHistorySchema(fields model.Fields) model.Fields {
+	historyFields := make(model.Fields, len(fields)+4)
+	for name, field := range fields {
+		// Linked fields aren't stored in the collection
+		if field.IsLinked {
+			continue
+		}
+		historyFields[name] = &model.FieldType{
+			FieldName:    name,
+			IsList:       field.IsList,
+			Kind:         field.Kind,
+			Args:         field.Args,
+			NestedObject: field.NestedObject,
+			TypeIDSize:   field.TypeIDSize,
+			EnumValues:   field.EnumValues,
+		}
+	}
+
+	historyFields[model.HistoryFieldID] = &model.FieldType{
+		FieldName:           model.HistoryFieldID,
+		IsFieldTypeRequired: true,
+		Kind:                model.TypeID,
+		TypeIDSize:          model.DefaultCharacterSize,
+		IsPrimary:           true,
+		PrimaryKeyInfo:      &model.TableProperties{},
+	}
+	historyFields[model.HistoryFieldOp] = &model.FieldType{
+		FieldName:           model.HistoryFieldOp,
+		IsFieldTypeRequired: true,
+		Kind:                model.TypeVarChar,
+		TypeIDSize:          model.DefaultCharacterSize,
+	}
+	historyFields[model.HistoryFieldAt] = &model.FieldType{
+		FieldName:           model.HistoryFieldAt,
+		IsFieldTypeRequired: true,
+		Kind:                model.TypeDateTime,
+		Args:                &model.FieldArgs{Precision: model.DefaultDateTimePrecision},
+		IndexInfo:           []*model.TableProperties{{IsIndex: true, Group: model.HistoryFieldAt, Field: model.HistoryFieldAt, Order: model.DefaultIndexOrder, Sort: model.DefaultIndexSort}},
+	}
+	return historyFields
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
+	uuid "github.com/satori/go.uuid"
 	"github.com/segmentio/ksuid"
 	"github.com/spaceuptech/helpers"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,6 +18,7 @@ import (
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/geo"
 )
 
 // SchemaValidator validates provided doc object against it's schema
@@ -43,6 +45,10 @@ func SchemaValidator(ctx context.Context, dbAlias, dbType, col string, collectio
 			continue
 		}
 
+		if !ok && fieldValue.IsAutoGenerate {
+			value, ok = uuid.NewV4().String(), true
+		}
+
 		if !ok && fieldValue.IsDefault {
 			defaultStringValue, isString := fieldValue.Default.(string)
 			if fieldValue.Kind == model.TypeJSON && isString {
@@ -60,6 +66,11 @@ func SchemaValidator(ctx context.Context, dbAlias, dbType, col string, collectio
 			continue
 		}
 
+		// Documents start off at version 1
+		if fieldValue.IsVersion && !ok {
+			value, ok = 1, true
+		}
+
 		if fieldValue.IsFieldTypeRequired {
 			if fieldValue.Kind == model.TypeID && !ok {
 				value = ksuid.New().String()
@@ -73,6 +84,9 @@ func SchemaValidator(ctx context.Context, dbAlias, dbType, col string, collectio
 		if err != nil {
 			return nil, err
 		}
+		if err := validateField(col, val, fieldValue); err != nil {
+			return nil, err
+		}
 
 		mutatedDoc[fieldKey] = val
 	}
@@ -137,6 +151,10 @@ func ValidateUpdateOperation(ctx context.Context, dbAlias, dbType, col, op strin
 		return nil
 	}
 
+	if err := applyVersion(ctx, col, op, updateDoc, find, SchemaDoc); err != nil {
+		return err
+	}
+
 	for key, doc := range updateDoc {
 		switch key {
 		case "$unset":
@@ -147,8 +165,8 @@ func ValidateUpdateOperation(ctx context.Context, dbAlias, dbType, col, op strin
 				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error validating set operation in schema module unable to validate (%s) data", key), err, nil)
 			}
 			updateDoc[key] = newDoc
-		case "$push":
-			err := validateArrayOperations(ctx, dbAlias, dbType, col, doc, SchemaDoc)
+		case "$push", "$addToSet", "$pull":
+			err := validateArrayOperations(ctx, dbAlias, dbType, col, key, doc, SchemaDoc)
 			if err != nil {
 				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error validating array operation in schema module unable to validate (%s) data", key), err, nil)
 			}
@@ -179,6 +197,36 @@ func ValidateUpdateOperation(ctx context.Context, dbAlias, dbType, col, op strin
 	return nil
 }
 
+// applyVersion makes updates of a collection with a version field require the current version of the document in the
+// where clause and increments it. Since the version is checked by the database as part of the update, a concurrent
+// update of the document makes this one match nothing. Upserts are allowed without a version.
+func applyVersion(ctx context.Context, col, op string, updateDoc, find map[string]interface{}, SchemaDoc model.Fields) error {
+	for fieldName, fieldValue := range SchemaDoc {
+		if !fieldValue.IsVersion {
+			continue
+		}
+
+		for operator, fields := range updateDoc {
+			if m, ok := fields.(map[string]interface{}); ok {
+				if _, p := m[fieldName]; p {
+					return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Field (%s) of collection (%s) holds the version of the document and cannot be updated using (%s)", fieldName, col, operator), nil, nil)
+				}
+			}
+		}
+		if _, p := find[fieldName]; !p && op != utils.Upsert {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Current version of the document must be provided as field (%s) in the where clause while updating collection (%s)", fieldName, col), nil, nil)
+		}
+
+		inc, ok := updateDoc["$inc"].(map[string]interface{})
+		if !ok {
+			inc = map[string]interface{}{}
+			updateDoc["$inc"] = inc
+		}
+		inc[fieldName] = 1
+	}
+	return nil
+}
+
 type fieldsToPostProcess struct {
 	kind string
 	name string
@@ -366,6 +414,15 @@ func AdjustWhereClause(ctx context.Context, dbAlias string, dbType model.DBType,
 					return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid format (%s) of datetime (%v) provided for field (%s)", reflect.TypeOf(param), param, k), nil, nil)
 				}
 			}
+		case model.TypePoint, model.TypeGeometry:
+			// The geospatial operators are validated here since the database modules cannot report invalid values
+			if param, ok := v.(map[string]interface{}); ok {
+				for operator, paramInterface := range param {
+					if err := geo.ValidateOperator(operator, paramInterface); err != nil {
+						return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid value provided for operator (%s) of field (%s)", operator, k), err, nil)
+					}
+				}
+			}
 		}
 	}
 
@@ -402,6 +459,11 @@ func Parser(dbSchemas config.DatabaseSchemas) (model.Type, error) {
 		} else {
 			schema[dbSchema.DbAlias][dbSchema.Table] = value
 		}
+
+		// The history table of a collection is derived from its schema
+		if isHistoryEnabled(doc, dbSchema.Table) {
+			schema[dbSchema.DbAlias][dbSchema.Table+model.HistoryTableSuffix] = getHistorySchema(value)
+		}
 	}
 	return schema, nil
 }
@@ -291,6 +291,22 @@ func TestParseSchema(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "version directive on a string field",
+			IsErrExpected: true,
+			schema:        nil,
+			Data: config.DatabaseSchemas{
+				config.GenerateResourceID("chicago", "myproject", config.ResourceDatabaseSchema, "mongo", "tweet"): &config.DatabaseSchema{
+					Table:   "tweet",
+					DbAlias: "mongo",
+					Schema: `
+						type tweet {
+							id: ID! @primary
+							version: String! @version
+						  }`,
+				},
+			},
+		},
 		{
 			name:          "invalid collection name",
 			schema:        nil,
@@ -697,6 +713,21 @@ func TestSchema_ValidateUpdateOperation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "Test case setting a field within json type",
+			IsErrExpected: false,
+			args: args{
+				dbAlias: "mongo",
+				dbType:  string(model.Mongo),
+				col:     "tweet",
+				updateDoc: map[string]interface{}{
+					"$set": map[string]interface{}{
+						"spec.name":  "goku",
+						"spec.power": 9001,
+					},
+				},
+			},
+		},
 		{
 			name:          "Invalid Test case got integer wanted object for json type",
 			IsErrExpected: true,
@@ -862,6 +893,51 @@ func TestSchema_ValidateUpdateOperation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "Test case-add to set and pull with modifiers",
+			IsErrExpected: false,
+			args: args{
+				dbAlias: "mongo",
+				dbType:  string(model.Mongo),
+				col:     "tweet",
+				updateDoc: map[string]interface{}{
+					"$addToSet": map[string]interface{}{
+						"friends": map[string]interface{}{"$each": []interface{}{"goku", "vegeta"}},
+					},
+					"$pull": map[string]interface{}{
+						"spec.tags": map[string]interface{}{"$in": []interface{}{1, "two"}},
+					},
+				},
+			},
+		},
+		{
+			name:          "Invalid Test case-pull value of wrong type",
+			IsErrExpected: true,
+			args: args{
+				dbAlias: "mongo",
+				dbType:  string(model.Mongo),
+				col:     "tweet",
+				updateDoc: map[string]interface{}{
+					"$pull": map[string]interface{}{
+						"friends": map[string]interface{}{"$in": []interface{}{"goku", 12}},
+					},
+				},
+			},
+		},
+		{
+			name:          "Invalid Test case-each modifier which isn't an array",
+			IsErrExpected: true,
+			args: args{
+				dbAlias: "mongo",
+				dbType:  string(model.Mongo),
+				col:     "tweet",
+				updateDoc: map[string]interface{}{
+					"$push": map[string]interface{}{
+						"friends": map[string]interface{}{"$each": "goku"},
+					},
+				},
+			},
+		},
 		{
 			name:          "Invalid Test case-invalid type for field owner",
 			IsErrExpected: true,
@@ -1768,3 +1844,166 @@ func TestSchema_CheckType(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyVersion(t *testing.T) {
+	fields := model.Fields{
+		"id":      &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+		"views":   &model.FieldType{FieldName: "views", Kind: model.TypeInteger},
+		"version": &model.FieldType{FieldName: "version", Kind: model.TypeBigInteger, IsVersion: true},
+	}
+
+	tests := []struct {
+		name      string
+		op        string
+		updateDoc map[string]interface{}
+		find      map[string]interface{}
+		want      map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "version is incremented along with other increments",
+			op:        utils.All,
+			updateDoc: map[string]interface{}{"$inc": map[string]interface{}{"views": 1}},
+			find:      map[string]interface{}{"id": "1", "version": 2},
+			want:      map[string]interface{}{"$inc": map[string]interface{}{"views": 1, "version": 1}},
+		},
+		{
+			name:      "version is incremented",
+			op:        utils.One,
+			updateDoc: map[string]interface{}{"$set": map[string]interface{}{"views": 1}},
+			find:      map[string]interface{}{"id": "1", "version": map[string]interface{}{"$eq": 2}},
+			want:      map[string]interface{}{"$set": map[string]interface{}{"views": 1}, "$inc": map[string]interface{}{"version": 1}},
+		},
+		{
+			name:      "upsert without version",
+			op:        utils.Upsert,
+			updateDoc: map[string]interface{}{"$set": map[string]interface{}{"views": 1}},
+			find:      map[string]interface{}{"id": "1"},
+			want:      map[string]interface{}{"$set": map[string]interface{}{"views": 1}, "$inc": map[string]interface{}{"version": 1}},
+		},
+		{
+			name:      "update without version",
+			op:        utils.All,
+			updateDoc: map[string]interface{}{"$set": map[string]interface{}{"views": 1}},
+			find:      map[string]interface{}{"id": "1"},
+			wantErr:   true,
+		},
+		{
+			name:      "update of the version",
+			op:        utils.All,
+			updateDoc: map[string]interface{}{"$set": map[string]interface{}{"version": 5}},
+			find:      map[string]interface{}{"id": "1", "version": 2},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := applyVersion(context.Background(), "tweet", tt.op, tt.updateDoc, tt.find, fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.updateDoc, tt.want) {
+				t.Errorf("applyVersion() updateDoc = %v, want %v", tt.updateDoc, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkTypeScalars(t *testing.T) {
+	testCases := []struct {
+		name          string
+		kind          string
+		value         interface{}
+		result        interface{}
+		IsErrExpected bool
+	}{
+		{name: "datetime with offset is converted to utc", kind: model.TypeDateTime, value: "2020-10-19T11:45:26.371+05:30", result: time.Date(2020, 10, 19, 6, 15, 26, 371000000, time.UTC)},
+		{name: "datetime with zone retains its offset", kind: model.TypeDateTimeWithZone, value: "2020-10-19T11:45:26Z", result: time.Date(2020, 10, 19, 11, 45, 26, 0, time.UTC)},
+		{name: "valid uuid is canonicalized", kind: model.TypeUUID, value: "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", result: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{name: "invalid uuid", kind: model.TypeUUID, value: "not-a-uuid", IsErrExpected: true},
+		{name: "decimal string retains its precision", kind: model.TypeDecimal, value: "12345678901234567890.123456789", result: "12345678901234567890.123456789"},
+		{name: "invalid decimal string", kind: model.TypeDecimal, value: "12.3.4", IsErrExpected: true},
+		{name: "big integer string", kind: model.TypeBigInteger, value: "9007199254740993", result: int64(9007199254740993)},
+		{name: "invalid big integer string", kind: model.TypeBigInteger, value: "9.5", IsErrExpected: true},
+		{name: "allowed enum value", kind: model.TypeEnum, value: "ACTIVE", result: "ACTIVE"},
+		{name: "enum value which isn't allowed", kind: model.TypeEnum, value: "DELETED", IsErrExpected: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			field := &model.FieldType{FieldName: "field", Kind: testCase.kind, EnumValues: []string{"ACTIVE", "BLOCKED"}}
+			retval, err := checkType(context.Background(), "db", string(model.Postgres), "col", testCase.value, field)
+			if (err != nil) != testCase.IsErrExpected {
+				t.Errorf("checkType() error = %v, wantErr %v", err, testCase.IsErrExpected)
+				return
+			}
+			if t1, ok := retval.(time.Time); ok {
+				if !t1.Equal(testCase.result.(time.Time)) || t1.Location() != time.UTC && testCase.kind == model.TypeDateTime {
+					t.Errorf("checkType() = %v, want %v", retval, testCase.result)
+				}
+				return
+			}
+			if !testCase.IsErrExpected && !reflect.DeepEqual(retval, testCase.result) {
+				t.Errorf("checkType() = %v, want %v", retval, testCase.result)
+			}
+		})
+	}
+}
+
+func TestParser_enum(t *testing.T) {
+	schema := "enum Status { ACTIVE BLOCKED } type users { id: ID! @primary status: Status! tags: [Status] }"
+	got, err := Parser(config.DatabaseSchemas{"users": &config.DatabaseSchema{DbAlias: "mongo", Table: "users", Schema: schema}})
+	if err != nil {
+		t.Fatalf("Parser() error = %v", err)
+	}
+
+	for _, field := range []string{"status", "tags"} {
+		fieldType := got["mongo"]["users"][field]
+		if fieldType.Kind != model.TypeEnum || !reflect.DeepEqual(fieldType.EnumValues, []string{"ACTIVE", "BLOCKED"}) {
+			t.Errorf("Parser() field %s = %s %v, want enum with values [ACTIVE BLOCKED]", field, fieldType.Kind, fieldType.EnumValues)
+		}
+	}
+}
+
+func TestParser_history(t *testing.T) {
+	schema := "type users @history { id: ID! @primary email: String! @unique age: Integer @default(value: 18) }"
+	got, err := Parser(config.DatabaseSchemas{"users": &config.DatabaseSchema{DbAlias: "db", Table: "users", Schema: schema}})
+	if err != nil {
+		t.Fatalf("Parser() error = %v", err)
+	}
+
+	table, ok := GetHistoryTable(got, "db", "users")
+	if !ok || table != "users_history" {
+		t.Fatalf("GetHistoryTable() = %s, %v, want users_history, true", table, ok)
+	}
+	if !IsHistoryTable(got, "db", "users_history") || IsHistoryTable(got, "db", "users") {
+		t.Errorf("IsHistoryTable() should only be true for the history table")
+	}
+
+	history := got["db"]["users_history"]
+	for _, field := range []string{"id", "email", "age"} {
+		f, ok := history[field]
+		if !ok {
+			t.Errorf("Parser() history table doesn't have field %s", field)
+			continue
+		}
+		if f.IsPrimary || f.IsFieldTypeRequired || f.IsDefault || len(f.IndexInfo) > 0 {
+			t.Errorf("Parser() history field %s should not have any constraints, got %+v", field, f)
+		}
+	}
+	if f := history[model.HistoryFieldID]; f == nil || !f.IsPrimary {
+		t.Errorf("Parser() history table should have the primary key %s", model.HistoryFieldID)
+	}
+	if f := history[model.HistoryFieldAt]; f == nil || f.Kind != model.TypeDateTime {
+		t.Errorf("Parser() history table should have the field %s of type DateTime", model.HistoryFieldAt)
+	}
+
+	// Collections without the directive don't have a history table
+	got, err = Parser(config.DatabaseSchemas{"users": &config.DatabaseSchema{DbAlias: "db", Table: "users", Schema: "type users { id: ID! @primary name: String }"}})
+	if err != nil {
+		t.Fatalf("Parser() error = %v", err)
+	}
+	if _, ok := GetHistoryTable(got, "db", "users"); ok {
+		t.Errorf("GetHistoryTable() = true for a collection without the @history directive")
+	}
+}
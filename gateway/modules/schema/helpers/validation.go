@@ -0,0 +1,205 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// regexCache stores the compiled patterns of the regex directives
+var regexCache sync.Map
+
+func getRegex(pattern string) (*regexp.Regexp, error) {
+	if r, ok := regexCache.Load(pattern); ok {
+		return r.(*regexp.Regexp), nil
+	}
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, r)
+	return r, nil
+}
+
+// parseValidationDirective adds the constraint of a validation directive to the field
+func parseValidationDirective(field *model.FieldType, directive *ast.Directive) error {
+	if field.Validation == nil {
+		field.Validation = new(model.FieldValidation)
+	}
+
+	args := map[string]interface{}{}
+	for _, arg := range directive.Arguments {
+		val, _ := utils.ParseGraphqlValue(arg.Value, nil)
+		args[arg.Name.Value] = val
+	}
+
+	invalidArg := func(name string) error {
+		return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unexpected argument (%s) of type (%T) provided for field (%s) directive @(%s)", name, args[name], field.FieldName, directive.Name.Value), nil, nil)
+	}
+
+	switch directive.Name.Value {
+	case model.DirectiveMin, model.DirectiveMax:
+		var value float64
+		switch v := args["value"].(type) {
+		case int:
+			value = float64(v)
+		case float64:
+			value = v
+		default:
+			return invalidArg("value")
+		}
+		if directive.Name.Value == model.DirectiveMin {
+			field.Validation.Min = &value
+		} else {
+			field.Validation.Max = &value
+		}
+
+	case model.DirectiveLength:
+		for name, arg := range args {
+			length, ok := arg.(int)
+			if !ok || length < 0 {
+				return invalidArg(name)
+			}
+			switch name {
+			case "min":
+				field.Validation.MinLength = &length
+			case "max":
+				field.Validation.MaxLength = &length
+			default:
+				return invalidArg(name)
+			}
+		}
+		if field.Validation.MinLength == nil && field.Validation.MaxLength == nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Directive @(%s) of field (%s) requires the min or max argument", directive.Name.Value, field.FieldName), nil, nil)
+		}
+
+	case model.DirectiveRegex:
+		pattern, ok := args["value"].(string)
+		if !ok {
+			return invalidArg("value")
+		}
+		if _, err := getRegex(pattern); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Invalid pattern provided in directive @(%s) of field (%s)", directive.Name.Value, field.FieldName), err, nil)
+		}
+		field.Validation.Regex = pattern
+
+	case model.DirectiveEmail:
+		field.Validation.Email = true
+	}
+	return nil
+}
+
+// checkValidationKind checks that the validation directives of a field are applicable to its type
+func checkValidationKind(field *model.FieldType) error {
+	v := field.Validation
+	if v == nil {
+		return nil
+	}
+
+	switch field.Kind {
+	case model.TypeInteger, model.TypeSmallInteger, model.TypeBigInteger, model.TypeFloat, model.TypeDecimal:
+		if v.MinLength == nil && v.MaxLength == nil && v.Regex == "" && !v.Email {
+			return nil
+		}
+	case model.TypeString, model.TypeVarChar, model.TypeChar, model.TypeID:
+		if v.Min == nil && v.Max == nil {
+			return nil
+		}
+	}
+	return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Validation directives @min and @max can only be added on numeric fields and @length, @regex and @email on string fields - field (%s) has type (%s)", field.FieldName, field.Kind), nil, nil)
+}
+
+// validateField checks the value of a field against its validation directives. The value must already have been
+// converted to the type of the field.
+func validateField(col string, value interface{}, field *model.FieldType) error {
+	v := field.Validation
+	if v == nil || value == nil {
+		return nil
+	}
+
+	if arr, ok := value.([]interface{}); ok {
+		for _, item := range arr {
+			if err := validateField(col, item, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	newError := func(rule, format string, a ...interface{}) error {
+		return &utils.ValidationError{Col: col, Field: field.FieldName, Rule: rule, Msg: fmt.Sprintf(format, a...)}
+	}
+
+	checkRange := func(number float64) error {
+		if v.Min != nil && number < *v.Min {
+			return newError(model.DirectiveMin, "value must be at least %v", *v.Min)
+		}
+		if v.Max != nil && number > *v.Max {
+			return newError(model.DirectiveMax, "value must be at most %v", *v.Max)
+		}
+		return nil
+	}
+
+	switch val := value.(type) {
+	case string:
+		// Decimals are sent as strings to retain their precision
+		if field.Kind == model.TypeDecimal {
+			number, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil
+			}
+			return checkRange(number)
+		}
+
+		length := utf8.RuneCountInString(val)
+		if v.MinLength != nil && length < *v.MinLength {
+			return newError(model.DirectiveLength, "length must be at least %d", *v.MinLength)
+		}
+		if v.MaxLength != nil && length > *v.MaxLength {
+			return newError(model.DirectiveLength, "length must be at most %d", *v.MaxLength)
+		}
+		if v.Regex != "" {
+			r, err := getRegex(v.Regex)
+			if err != nil || !r.MatchString(val) {
+				return newError(model.DirectiveRegex, "value must match the pattern %s", v.Regex)
+			}
+		}
+		if v.Email {
+			if address, err := mail.ParseAddress(val); err != nil || address.Address != val {
+				return newError(model.DirectiveEmail, "value must be an email address")
+			}
+		}
+
+	default:
+		if number, ok := toFloat64(val); ok {
+			return checkRange(number)
+		}
+	}
+	return nil
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+func Test_validateField(t *testing.T) {
+	schema := `type users {
+		id: ID! @primary
+		age: Integer @min(value: 18) @max(value: 60)
+		score: Decimal @max(value: 10.5)
+		name: String @length(min: 2, max: 5)
+		code: String @regex(value: "^[A-Z]{3}$")
+		email: String @email
+		tags: [String] @length(max: 3)
+	}`
+	parsed, err := Parser(config.DatabaseSchemas{"users": &config.DatabaseSchema{DbAlias: "mongo", Table: "users", Schema: schema}})
+	if err != nil {
+		t.Fatalf("Parser() error = %v", err)
+	}
+	fields := parsed["mongo"]["users"]
+
+	tests := []struct {
+		name     string
+		field    string
+		value    interface{}
+		wantRule string
+	}{
+		{name: "number within range", field: "age", value: 18},
+		{name: "number below min", field: "age", value: int64(17), wantRule: model.DirectiveMin},
+		{name: "number above max", field: "age", value: float64(61), wantRule: model.DirectiveMax},
+		{name: "decimal string above max", field: "score", value: "10.51", wantRule: model.DirectiveMax},
+		{name: "string within length", field: "name", value: "tony"},
+		{name: "string too short", field: "name", value: "t", wantRule: model.DirectiveLength},
+		{name: "multi byte characters are counted once", field: "name", value: "héllo"},
+		{name: "string too long", field: "name", value: "tony stark", wantRule: model.DirectiveLength},
+		{name: "string matching the pattern", field: "code", value: "ABC"},
+		{name: "string not matching the pattern", field: "code", value: "ABCD", wantRule: model.DirectiveRegex},
+		{name: "valid email", field: "email", value: "tony@stark.com"},
+		{name: "invalid email", field: "email", value: "Tony <tony@stark.com>", wantRule: model.DirectiveEmail},
+		{name: "every item of a list is validated", field: "tags", value: []interface{}{"a", "abcd"}, wantRule: model.DirectiveLength},
+		{name: "null values are skipped", field: "email", value: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateField("users", tt.value, fields[tt.field])
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Errorf("validateField() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *utils.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Rule != tt.wantRule || validationErr.Field != tt.field {
+				t.Errorf("validateField() error = %v, want violation of rule %s on field %s", err, tt.wantRule, tt.field)
+			}
+		})
+	}
+}
+
+func Test_checkValidationKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{name: "min on a number", schema: "type users { id: ID! @primary age: Integer @min(value: 1) }"},
+		{name: "min on a string", schema: "type users { id: ID! @primary name: String @min(value: 1) }", wantErr: true},
+		{name: "email on a number", schema: "type users { id: ID! @primary age: Integer @email }", wantErr: true},
+		{name: "invalid regex", schema: `type users { id: ID! @primary name: String @regex(value: "[a-") }`, wantErr: true},
+		{name: "length without arguments", schema: "type users { id: ID! @primary name: String @length }", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parser(config.DatabaseSchemas{"users": &config.DatabaseSchema{DbAlias: "mongo", Table: "users", Schema: tt.schema}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// GetIndexDrift compares the indexes declared in the schema of a collection with the ones present in the database
+func (s *Schema) GetIndexDrift(ctx context.Context, dbAlias, col string) (*model.IndexDrift, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	dbType, err := s.crud.GetDBType(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+	if dbType == string(model.Mongo) || dbType == string(model.EmbeddedDB) || dbType == string(model.Redis) || model.IsReadOnlyDB(dbType) {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Indexes of database (%s) of type (%s) are not managed by the schema module", dbAlias, dbType), nil, nil)
+	}
+
+	declared, err := getIndexMap(ctx, s.SchemaDoc[dbAlias][col])
+	if err != nil {
+		return nil, err
+	}
+
+	fields, indexes, err := s.crud.DescribeTable(ctx, dbAlias, col)
+	if err != nil {
+		return nil, err
+	}
+	inspection, err := generateInspection(dbType, col, fields, indexes)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := getIndexMap(ctx, inspection[col])
+	if err != nil {
+		return nil, err
+	}
+
+	return generateIndexDrift(declared, actual), nil
+}
+
+func generateIndexDrift(declared, actual map[string]*indexStruct) *model.IndexDrift {
+	drift := &model.IndexDrift{
+		Declared: describeIndexes(declared),
+		Actual:   describeIndexes(actual),
+		Missing:  []string{},
+		Extra:    []string{},
+		Changed:  []string{},
+	}
+
+	for group, index := range declared {
+		current, ok := actual[group]
+		if !ok {
+			drift.Missing = append(drift.Missing, group)
+			continue
+		}
+		if isIndexChanged(index, current) {
+			drift.Changed = append(drift.Changed, group)
+		}
+	}
+	for group := range actual {
+		if _, ok := declared[group]; !ok {
+			drift.Extra = append(drift.Extra, group)
+		}
+	}
+
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.Extra)
+	sort.Strings(drift.Changed)
+	return drift
+}
+
+func describeIndexes(indexMap map[string]*indexStruct) []*model.IndexDescription {
+	indexes := make([]*model.IndexDescription, 0, len(indexMap))
+	for group, index := range indexMap {
+		description := &model.IndexDescription{Group: group, Name: index.IndexName, Unique: index.IsIndexUnique, Where: index.Where, Fields: make([]model.IndexFieldDetail, len(index.IndexTableProperties))}
+		for i, column := range index.IndexTableProperties {
+			description.Fields[i] = model.IndexFieldDetail{Field: column.Field, Sort: column.Sort}
+		}
+		indexes = append(indexes, description)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Group < indexes[j].Group })
+	return indexes
+}
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_generateIndexDrift(t *testing.T) {
+	declared := model.Fields{
+		"email":  &model.FieldType{FieldName: "email", IndexInfo: []*model.TableProperties{{Field: "email", IsUnique: true, Group: "email", Order: 1, Sort: "asc", Where: "deleted_at IS NULL"}}},
+		"name":   &model.FieldType{FieldName: "name", IndexInfo: []*model.TableProperties{{Field: "name", IsIndex: true, Group: "name", Order: 1, Sort: "asc"}}},
+		"age":    &model.FieldType{FieldName: "age", IndexInfo: []*model.TableProperties{{Field: "age", IsIndex: true, Group: "age_city", Order: 1, Sort: "desc"}}},
+		"city":   &model.FieldType{FieldName: "city", IndexInfo: []*model.TableProperties{{Field: "city", IsIndex: true, Group: "age_city", Order: 2, Sort: "asc"}}},
+		"status": &model.FieldType{FieldName: "status"},
+	}
+	actual := model.Fields{
+		"email":  &model.FieldType{FieldName: "email", IndexInfo: []*model.TableProperties{{Field: "email", IsUnique: true, Group: "email", ConstraintName: "index__users__email", Order: 1, Sort: "asc", Where: "(deleted_at IS NULL)"}}},
+		"age":    &model.FieldType{FieldName: "age", IndexInfo: []*model.TableProperties{{Field: "age", IsIndex: true, Group: "age_city", ConstraintName: "index__users__age_city", Order: 1, Sort: "asc"}}},
+		"city":   &model.FieldType{FieldName: "city", IndexInfo: []*model.TableProperties{{Field: "city", IsIndex: true, Group: "age_city", ConstraintName: "index__users__age_city", Order: 2, Sort: "asc"}}},
+		"status": &model.FieldType{FieldName: "status", IndexInfo: []*model.TableProperties{{Field: "status", IsIndex: true, Group: "users_status_idx", ConstraintName: "users_status_idx", Order: 1, Sort: "asc"}}},
+	}
+
+	declaredMap, err := getIndexMap(context.Background(), declared)
+	if err != nil {
+		t.Fatalf("getIndexMap() error = %v", err)
+	}
+	actualMap, err := getIndexMap(context.Background(), actual)
+	if err != nil {
+		t.Fatalf("getIndexMap() error = %v", err)
+	}
+
+	got := generateIndexDrift(declaredMap, actualMap)
+	if want := []string{"name"}; !reflect.DeepEqual(got.Missing, want) {
+		t.Errorf("generateIndexDrift() missing = %v, want %v", got.Missing, want)
+	}
+	if want := []string{"users_status_idx"}; !reflect.DeepEqual(got.Extra, want) {
+		t.Errorf("generateIndexDrift() extra = %v, want %v", got.Extra, want)
+	}
+	if want := []string{"age_city"}; !reflect.DeepEqual(got.Changed, want) {
+		t.Errorf("generateIndexDrift() changed = %v, want %v", got.Changed, want)
+	}
+
+	wantDeclared := &model.IndexDescription{Group: "email", Unique: true, Fields: []model.IndexFieldDetail{{Field: "email", Sort: "asc"}}, Where: "deleted_at IS NULL"}
+	if len(got.Declared) != 3 || !reflect.DeepEqual(got.Declared[1], wantDeclared) {
+		t.Errorf("generateIndexDrift() declared = %v, want %v at index 1", got.Declared, wantDeclared)
+	}
+}
@@ -69,6 +69,9 @@ func (s *Schema) Inspector(ctx context.Context, dbAlias, dbType, project, col st
 		if realColumnInfo.IsUpdatedAt {
 			currentTableInfo.IsUpdatedAt = true
 		}
+		if realColumnInfo.IsVersion {
+			currentTableInfo.IsVersion = true
+		}
 	}
 
 	return currentSchema, nil
@@ -137,7 +140,7 @@ func generateInspection(dbType, col string, fields []model.InspectorFieldType, i
 
 		for _, indexValue := range indexes {
 			if indexValue.ColumnName == field.ColumnName {
-				temp := &model.TableProperties{Order: indexValue.Order, Sort: indexValue.Sort, ConstraintName: indexValue.IndexName}
+				temp := &model.TableProperties{Order: indexValue.Order, Sort: indexValue.Sort, ConstraintName: indexValue.IndexName, Where: indexValue.Where}
 				if indexValue.IsPrimary {
 					fieldDetails.IsPrimary = true
 					fieldDetails.PrimaryKeyInfo = &model.TableProperties{
@@ -230,6 +233,9 @@ func inspectionMySQLCheckFieldType(col string, field model.InspectorFieldType, f
 		}
 	case "bit", "tinyint":
 		fieldDetails.Kind = model.TypeBoolean
+	case "enum":
+		fieldDetails.Kind = model.TypeEnum
+		fieldDetails.EnumValues = parseMySQLEnumValues(field.FieldType)
 	case "json":
 		fieldDetails.Kind = model.TypeJSON
 	default:
@@ -295,6 +301,10 @@ func inspectionSQLServerCheckFieldType(col string, field model.InspectorFieldTyp
 		}
 	case "bit", "tinyint":
 		fieldDetails.Kind = model.TypeBoolean
+	case "uniqueidentifier":
+		fieldDetails.Kind = model.TypeUUID
+	case "enum":
+		fieldDetails.Kind = model.TypeEnum
 	case "json":
 		fieldDetails.Kind = model.TypeJSON
 	default:
@@ -303,12 +313,28 @@ func inspectionSQLServerCheckFieldType(col string, field model.InspectorFieldTyp
 	return nil
 }
 
+// parseMySQLEnumValues parses the values of an enum column from its type like enum('a','b')
+func parseMySQLEnumValues(columnType string) []string {
+	columnType = strings.TrimSuffix(strings.TrimPrefix(columnType, "enum("), ")")
+	if columnType == "" {
+		return nil
+	}
+
+	values := strings.Split(columnType, "','")
+	for i, value := range values {
+		values[i] = strings.Replace(strings.Trim(value, "'"), "''", "'", -1)
+	}
+	return values
+}
+
 func inspectionPostgresCheckFieldType(col string, field model.InspectorFieldType, fieldDetails *model.FieldType) error {
 	result := strings.Split(field.FieldType, "(")
 
 	switch result[0] {
 	case "uuid":
 		fieldDetails.Kind = model.TypeUUID
+	case "enum":
+		fieldDetails.Kind = model.TypeEnum
 	case "date":
 		fieldDetails.Kind = model.TypeDate
 	case "time without time zone", "time with time zone":
@@ -360,6 +386,12 @@ func inspectionPostgresCheckFieldType(col string, field model.InspectorFieldType
 		fieldDetails.Kind = model.TypeBoolean
 	case "jsonb", "json":
 		fieldDetails.Kind = model.TypeJSON
+	case "geometry", "geography":
+		// The subtype of PostGIS columns is reported along with the type, for eg: geometry(Point,4326)
+		fieldDetails.Kind = model.TypeGeometry
+		if len(result) > 1 && (strings.HasPrefix(strings.ToLower(result[1]), "point,") || strings.HasPrefix(strings.ToLower(result[1]), "point)")) {
+			fieldDetails.Kind = model.TypePoint
+		}
 	default:
 		return helpers.Logger.LogError("", fmt.Sprintf("Cannot track/inspect table (%s)", col), fmt.Errorf("table contains a column (%s) with type (%s) which is not supported by space cloud", fieldDetails.FieldName, result), nil)
 	}
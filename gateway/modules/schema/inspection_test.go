@@ -885,6 +885,26 @@ func Test_generateInspection(t *testing.T) {
 			want:    model.Collection{"table1": model.Fields{"column1": &model.FieldType{FieldName: "column1", IsFieldTypeRequired: true, IsDefault: true, Kind: model.TypeJSON, Default: `"{\"id\":\"zerfvnex\",\"name\":\"john\"}"`}}},
 			wantErr: false,
 		},
+		{
+			name: "Postgres field col1 with type geometry point",
+			args: args{
+				dbType: "postgres",
+				col:    "table1",
+				fields: []model.InspectorFieldType{{ColumnName: "column1", FieldType: "geometry(Point,4326)", FieldNull: "YES", AutoIncrement: "false"}},
+			},
+			want:    model.Collection{"table1": model.Fields{"column1": &model.FieldType{FieldName: "column1", Kind: model.TypePoint}}},
+			wantErr: false,
+		},
+		{
+			name: "Postgres field col1 with type geometry",
+			args: args{
+				dbType: "postgres",
+				col:    "table1",
+				fields: []model.InspectorFieldType{{ColumnName: "column1", FieldType: "geometry(Geometry,4326)", FieldNull: "YES", AutoIncrement: "false"}},
+			},
+			want:    model.Collection{"table1": model.Fields{"column1": &model.FieldType{FieldName: "column1", Kind: model.TypeGeometry}}},
+			wantErr: false,
+		},
 		// sql server
 		{
 			name: "SQL-Server field col1 which is not null with type varchar having default value INDIA",
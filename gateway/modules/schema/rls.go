@@ -0,0 +1,271 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// rlsAuthSetting is the setting of a postgres session holding the token of the user. Clients accessing the database
+// directly set it to a token signed with the HS256 secret of the project before running their queries.
+const rlsAuthSetting = "spacecloud.auth"
+
+// rlsClaimsFunction verifies the token held by the rlsAuthSetting and returns its claims. It runs with the privileges
+// of its owner, since only the owner can read the secret the token is verified with.
+const rlsClaimsFunction = "sc_auth_claims"
+
+// rlsSecretTable holds the secret the tokens are verified with. Only its owner can read it.
+const rlsSecretTable = "sc_rls_secret"
+
+// rlsPolicies are the row level security policies generated for a table along with the operation they enforce
+var rlsPolicies = []struct {
+	op          model.OperationType
+	name        string
+	command     string
+	clause      string
+	fieldPrefix string
+}{
+	{op: model.Read, name: "sc_read", command: "SELECT", clause: "USING", fieldPrefix: "args.find."},
+	{op: model.Create, name: "sc_create", command: "INSERT", clause: "WITH CHECK", fieldPrefix: "args.doc."},
+	{op: model.Update, name: "sc_update", command: "UPDATE", clause: "USING", fieldPrefix: "args.find."},
+	{op: model.Delete, name: "sc_delete", command: "DELETE", clause: "USING", fieldPrefix: "args.find."},
+}
+
+// ApplyRowLevelSecurity enforces the security rules of a postgres table in the database itself. The tokens of the
+// clients accessing the database directly are verified with the provided HS256 secret.
+func (s *Schema) ApplyRowLevelSecurity(ctx context.Context, dbAlias, logicalDBName, col, secret string, rules map[string]*config.Rule) error {
+	queries, err := s.GetRowLevelSecurityQueries(ctx, dbAlias, logicalDBName, col, rules)
+	if err != nil {
+		return err
+	}
+
+	dbType, _ := s.crud.GetDBType(dbAlias)
+	queries = append(queries, getRLSSecretQueries(s.getTableName(dbType, logicalDBName, rlsSecretTable), secret)...)
+	return s.crud.RawBatch(ctx, dbAlias, queries)
+}
+
+// SetRowLevelSecuritySecret replaces the HS256 secret the tokens of the clients accessing the database directly are
+// verified with. No token is valid once the secret is empty, hence only the policies which don't need claims pass.
+func (s *Schema) SetRowLevelSecuritySecret(ctx context.Context, dbAlias, logicalDBName, secret string) error {
+	dbType, err := s.crud.GetDBType(dbAlias)
+	if err != nil {
+		return err
+	}
+	if model.DBType(dbType) != model.Postgres {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Row level security is only supported on postgres - database (%s) is of type (%s)", dbAlias, dbType), nil, nil)
+	}
+
+	secretTable := s.getTableName(dbType, logicalDBName, rlsSecretTable)
+	queries := append(getRLSClaimsQueries(secretTable, s.getTableName(dbType, logicalDBName, rlsClaimsFunction)+"()"), getRLSSecretQueries(secretTable, secret)...)
+	return s.crud.RawBatch(ctx, dbAlias, queries)
+}
+
+// getRLSSecretQueries returns the queries replacing the secret held by the secret table
+func getRLSSecretQueries(secretTable, secret string) []string {
+	queries := []string{fmt.Sprintf("DELETE FROM %s", secretTable)}
+	if secret != "" {
+		queries = append(queries, fmt.Sprintf("INSERT INTO %s (secret) VALUES (%s)", secretTable, quoteRLSString(secret)))
+	}
+	return queries
+}
+
+// GetRowLevelSecurityQueries translates the security rules of a postgres table into row level security policies. The
+// claims of the user are read from the token held by the spacecloud.auth setting of the session, once its signature
+// has been verified. The gateway enforces the rules on its own, hence it should connect as the owner of the table,
+// who isn't subject to these policies. Clients accessing the database directly must connect as another role.
+func (s *Schema) GetRowLevelSecurityQueries(ctx context.Context, dbAlias, logicalDBName, col string, rules map[string]*config.Rule) ([]string, error) {
+	dbType, err := s.crud.GetDBType(dbAlias)
+	if err != nil {
+		return nil, err
+	}
+	if model.DBType(dbType) != model.Postgres {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Row level security is only supported on postgres - database (%s) is of type (%s)", dbAlias, dbType), nil, nil)
+	}
+
+	table := s.getTableName(dbType, logicalDBName, col)
+	claims := s.getTableName(dbType, logicalDBName, rlsClaimsFunction) + "()"
+	queries := append(getRLSClaimsQueries(s.getTableName(dbType, logicalDBName, rlsSecretTable), claims), fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", table))
+	for _, policy := range rlsPolicies {
+		// Operations without a rule are denied
+		expr := "false"
+		if rule, ok := rules[string(policy.op)]; ok && rule != nil {
+			expr, err = translateRLSRule(rule, policy.fieldPrefix, claims)
+			if err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to translate the (%s) rule of (%s) to a row level security policy", policy.op, col), err, nil)
+			}
+		}
+		queries = append(queries,
+			fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s", policy.name, table),
+			fmt.Sprintf("CREATE POLICY %s ON %s FOR %s %s (%s)", policy.name, table, policy.command, policy.clause, expr),
+		)
+	}
+	return queries, nil
+}
+
+// getRLSClaimsQueries returns the queries creating the table holding the secret and the function verifying the token of
+// the session. The claims are null unless the token carries a valid HS256 signature and hasn't expired. The function
+// relies on the hmac function of the pgcrypto extension installed in the public schema.
+func getRLSClaimsQueries(secretTable, claims string) []string {
+	return []string{
+		"CREATE EXTENSION IF NOT EXISTS pgcrypto WITH SCHEMA public",
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (secret text NOT NULL)", secretTable),
+		fmt.Sprintf("REVOKE ALL ON %s FROM PUBLIC", secretTable),
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %[2]s RETURNS jsonb LANGUAGE plpgsql STABLE SECURITY DEFINER SET search_path = pg_catalog, pg_temp AS $$
+DECLARE
+	token text := nullif(current_setting('%[3]s', true), '');
+	parts text[];
+	key text;
+	payload jsonb;
+BEGIN
+	parts := string_to_array(token, '.');
+	IF token IS NULL OR array_length(parts, 1) <> 3 THEN
+		RETURN NULL;
+	END IF;
+	SELECT secret INTO key FROM %[1]s LIMIT 1;
+	IF key IS NULL OR translate(encode(public.hmac(parts[1] || '.' || parts[2], key, 'sha256'), 'base64'), E'+/=\n', '-_') <> parts[3] THEN
+		RETURN NULL;
+	END IF;
+	payload := convert_from(decode(translate(parts[2], '-_', '+/') || repeat('=', (4 - length(parts[2]) %% 4) %% 4), 'base64'), 'UTF8')::jsonb;
+	IF payload ? 'exp' AND (payload ->> 'exp')::numeric < extract(epoch FROM now()) THEN
+		RETURN NULL;
+	END IF;
+	RETURN payload;
+END
+$$`, secretTable, claims, rlsAuthSetting),
+	}
+}
+
+// translateRLSRule returns the sql predicate enforcing a security rule. The fields of the row are referred to with
+// the provided prefix in the rule, while the claims of the user are read with the provided expression. Rules which
+// depend on anything apart from the row and the claims of the user can't be translated.
+func translateRLSRule(rule *config.Rule, fieldPrefix, claims string) (string, error) {
+	switch rule.Rule {
+	case "allow":
+		return "true", nil
+	case "deny":
+		return "false", nil
+	case "authenticated":
+		return claims + " IS NOT NULL", nil
+	case "and", "or":
+		if len(rule.Clauses) == 0 {
+			return fmt.Sprintf("%v", rule.Rule == "and"), nil
+		}
+		exprs := make([]string, len(rule.Clauses))
+		for i, clause := range rule.Clauses {
+			expr, err := translateRLSRule(clause, fieldPrefix, claims)
+			if err != nil {
+				return "", err
+			}
+			exprs[i] = "(" + expr + ")"
+		}
+		return strings.Join(exprs, " "+strings.ToUpper(rule.Rule)+" "), nil
+	case "match":
+		return translateRLSMatch(rule, fieldPrefix, claims)
+	}
+	return "", fmt.Errorf("rule (%s) can't be enforced with row level security", rule.Rule)
+}
+
+func translateRLSMatch(rule *config.Rule, fieldPrefix, claims string) (string, error) {
+	cast, ok := map[string]string{"string": "text", "number": "numeric", "bool": "boolean", "date": "timestamptz"}[rule.Type]
+	if !ok {
+		return "", fmt.Errorf("invalid variable data type (%s) provided", rule.Type)
+	}
+
+	isList := rule.Eval == "in" || rule.Eval == "notIn"
+	f1, err := translateRLSOperand(rule.F1, fieldPrefix, claims, cast, false)
+	if err != nil {
+		return "", err
+	}
+	f2, err := translateRLSOperand(rule.F2, fieldPrefix, claims, cast, isList)
+	if err != nil {
+		return "", err
+	}
+
+	switch rule.Eval {
+	case "==":
+		return f1 + " = " + f2, nil
+	case "!=":
+		return f1 + " <> " + f2, nil
+	case "<", "<=", ">", ">=":
+		return f1 + " " + rule.Eval + " " + f2, nil
+	case "in":
+		return f1 + " = ANY(" + f2 + ")", nil
+	case "notIn":
+		return "NOT (" + f1 + " = ANY(" + f2 + "))", nil
+	}
+	return "", fmt.Errorf("invalid eval (%s) provided", rule.Eval)
+}
+
+// translateRLSOperand returns the sql expression of a field of a match rule. Lists are returned as arrays.
+func translateRLSOperand(field interface{}, fieldPrefix, claims, cast string, isList bool) (string, error) {
+	switch v := field.(type) {
+	case string:
+		switch {
+		case strings.HasPrefix(v, fieldPrefix):
+			column := strings.TrimPrefix(v, fieldPrefix)
+			if column == "" || strings.Contains(column, ".") {
+				return "", fmt.Errorf("field (%s) should refer to a column of the table", v)
+			}
+			if isList {
+				return "", fmt.Errorf("field (%s) can't be used as a list", v)
+			}
+			return fmt.Sprintf(`"%s"::%s`, strings.ReplaceAll(column, `"`, `""`), cast), nil
+
+		case strings.HasPrefix(v, "args.auth."):
+			path := strings.Split(strings.TrimPrefix(v, "args.auth."), ".")
+			for i, p := range path {
+				path[i] = strings.ReplaceAll(strings.ReplaceAll(p, "'", "''"), ",", `\,`)
+			}
+			if isList {
+				return fmt.Sprintf("ARRAY(SELECT jsonb_array_elements_text(%s #> '{%s}'))::%s[]", claims, strings.Join(path, ","), cast), nil
+			}
+			return fmt.Sprintf("(%s #>> '{%s}')::%s", claims, strings.Join(path, ","), cast), nil
+
+		case strings.HasPrefix(v, "args."):
+			return "", fmt.Errorf("variable (%s) isn't available to the database", v)
+		}
+	case []interface{}:
+		if !isList {
+			return "", fmt.Errorf("list (%v) can only be used with in and notIn", v)
+		}
+		values := make([]string, len(v))
+		for i, value := range v {
+			literal, err := translateRLSLiteral(value)
+			if err != nil {
+				return "", err
+			}
+			values[i] = literal
+		}
+		return fmt.Sprintf("ARRAY[%s]::%s[]", strings.Join(values, ", "), cast), nil
+	}
+
+	if isList {
+		return "", fmt.Errorf("value (%v) should be a list", field)
+	}
+	literal, err := translateRLSLiteral(field)
+	if err != nil {
+		return "", err
+	}
+	return literal + "::" + cast, nil
+}
+
+func translateRLSLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return quoteRLSString(v), nil
+	case bool:
+		return fmt.Sprintf("%v", v), nil
+	case int, int32, int64, float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	}
+	return "", fmt.Errorf("value (%v) of type (%T) isn't supported", value, value)
+}
+
+func quoteRLSString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
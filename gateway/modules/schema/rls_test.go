@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestSchema_GetRowLevelSecurityQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbType  string
+		rules   map[string]*config.Rule
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "rules of all the operations",
+			dbType: "postgres",
+			rules: map[string]*config.Rule{
+				"read":   {Rule: "or", Clauses: []*config.Rule{{Rule: "match", Type: "string", Eval: "==", F1: "args.find.owner", F2: "args.auth.id"}, {Rule: "match", Type: "bool", Eval: "==", F1: "args.find.public", F2: true}}},
+				"create": {Rule: "authenticated"},
+				"update": {Rule: "match", Type: "string", Eval: "in", F1: "args.auth.role", F2: []interface{}{"admin", "o'brien"}},
+			},
+			want: []string{
+				"ALTER TABLE myproject.posts ENABLE ROW LEVEL SECURITY",
+				"DROP POLICY IF EXISTS sc_read ON myproject.posts",
+				`CREATE POLICY sc_read ON myproject.posts FOR SELECT USING (("owner"::text = (myproject.sc_auth_claims() #>> '{id}')::text) OR ("public"::boolean = true::boolean))`,
+				"DROP POLICY IF EXISTS sc_create ON myproject.posts",
+				"CREATE POLICY sc_create ON myproject.posts FOR INSERT WITH CHECK (myproject.sc_auth_claims() IS NOT NULL)",
+				"DROP POLICY IF EXISTS sc_update ON myproject.posts",
+				"CREATE POLICY sc_update ON myproject.posts FOR UPDATE USING ((myproject.sc_auth_claims() #>> '{role}')::text = ANY(ARRAY['admin', 'o''brien']::text[]))",
+				"DROP POLICY IF EXISTS sc_delete ON myproject.posts",
+				"CREATE POLICY sc_delete ON myproject.posts FOR DELETE USING (false)",
+			},
+		},
+		{
+			name:   "claims holding a list",
+			dbType: "postgres",
+			rules: map[string]*config.Rule{
+				"read": {Rule: "match", Type: "number", Eval: "notIn", F1: "args.find.team", F2: "args.auth.teams"},
+			},
+			want: []string{
+				"ALTER TABLE myproject.posts ENABLE ROW LEVEL SECURITY",
+				"DROP POLICY IF EXISTS sc_read ON myproject.posts",
+				"CREATE POLICY sc_read ON myproject.posts FOR SELECT USING (NOT (\"team\"::numeric = ANY(ARRAY(SELECT jsonb_array_elements_text(myproject.sc_auth_claims() #> '{teams}'))::numeric[])))",
+				"DROP POLICY IF EXISTS sc_create ON myproject.posts",
+				"CREATE POLICY sc_create ON myproject.posts FOR INSERT WITH CHECK (false)",
+				"DROP POLICY IF EXISTS sc_update ON myproject.posts",
+				"CREATE POLICY sc_update ON myproject.posts FOR UPDATE USING (false)",
+				"DROP POLICY IF EXISTS sc_delete ON myproject.posts",
+				"CREATE POLICY sc_delete ON myproject.posts FOR DELETE USING (false)",
+			},
+		},
+		{
+			name:    "rule which depends on another table",
+			dbType:  "postgres",
+			rules:   map[string]*config.Rule{"read": {Rule: "query", DB: "db", Col: "members"}},
+			wantErr: true,
+		},
+		{
+			name:    "field of the document in a read rule",
+			dbType:  "postgres",
+			rules:   map[string]*config.Rule{"read": {Rule: "match", Type: "string", Eval: "==", F1: "args.doc.owner", F2: "args.auth.id"}},
+			wantErr: true,
+		},
+		{
+			name:    "database other than postgres",
+			dbType:  "mysql",
+			rules:   map[string]*config.Rule{"read": {Rule: "allow"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCrud := mockCrudSchemaInterface{}
+			mockCrud.On("GetDBType", "db").Return(tt.dbType)
+			s := &Schema{crud: &mockCrud}
+
+			got, err := s.GetRowLevelSecurityQueries(context.Background(), "db", "myproject", "posts", tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRowLevelSecurityQueries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil {
+				// The function verifying the token of the session gets created before the policies
+				tt.want = append(getRLSClaimsQueries("myproject.sc_rls_secret", "myproject.sc_auth_claims()"), tt.want...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRowLevelSecurityQueries() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getRLSSecretQueries(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   []string
+	}{
+		{name: "secret", secret: "it's secret", want: []string{"DELETE FROM myproject.sc_rls_secret", "INSERT INTO myproject.sc_rls_secret (secret) VALUES ('it''s secret')"}},
+		{name: "revoked secret", want: []string{"DELETE FROM myproject.sc_rls_secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getRLSSecretQueries("myproject.sc_rls_secret", tt.secret); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getRLSSecretQueries() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -59,6 +59,21 @@ func (s *Schema) GetSchema(dbAlias, col string) (model.Fields, bool) {
 	return fields, true
 }
 
+// GetSchemas returns the schemas of all the collections keyed by the db alias and collection name
+func (s *Schema) GetSchemas() model.Type {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	schemas := make(model.Type, len(s.SchemaDoc))
+	for dbAlias, collections := range s.SchemaDoc {
+		schemas[dbAlias] = make(model.Collection, len(collections))
+		for col, fields := range collections {
+			schemas[dbAlias][col] = fields
+		}
+	}
+	return schemas
+}
+
 // parseSchema Initializes Schema field in Module struct
 func (s *Schema) parseSchema(crud config.DatabaseSchemas) error {
 	schema, err := schemaHelpers.Parser(crud)
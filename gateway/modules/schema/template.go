@@ -19,6 +19,8 @@ func generateSDL(schemaCol model.Collection) (string, error) {
 		"{{$fieldValue.JointTable.Table}}" +
 		"{{else if and $fieldValue.IsLinked $fieldValue.IsList}}" +
 		"[{{$fieldValue.Kind}}]" +
+		"{{else if eq $fieldValue.Kind \"Enum\"}}" +
+		"String" +
 		"{{else}}" +
 		"{{$fieldValue.Kind}}" +
 		"{{end}}" +
@@ -70,15 +72,18 @@ func generateSDL(schemaCol model.Collection) (string, error) {
 		"{{if $fieldValue.IsUpdatedAt}}" +
 		"@updatedAt " +
 		"{{end}}" +
+		"{{if $fieldValue.IsVersion}}" +
+		"@version " +
+		"{{end}}" +
 
 		// @unique or @index directive
 		"{{ range $i, $sequence :=  (repeat 2) }}" + // for loop indexInfo
 		"{{range $k,$v := $fieldValue.IndexInfo }}" +
 		"{{if and (eq $sequence 1) $v.IsUnique}}" +
-		"@unique(group: \"{{$v.Group}}\", sort: \"{{$v.Sort}}\", order: {{$v.Order}}) " +
+		"@unique(group: \"{{$v.Group}}\", sort: \"{{$v.Sort}}\", order: {{$v.Order}}{{if $v.Where}}, where: {{printf \"%q\" $v.Where}}{{end}}) " +
 		"{{else}}" +
 		"{{if and (eq $sequence 2) $v.IsIndex}}" +
-		"@index(group: \"{{$v.Group}}\", sort: \"{{$v.Sort}}\", order: {{$v.Order}}) " +
+		"@index(group: \"{{$v.Group}}\", sort: \"{{$v.Sort}}\", order: {{$v.Order}}{{if $v.Where}}, where: {{printf \"%q\" $v.Where}}{{end}}) " +
 		"{{end}}" +
 		"{{end}}" +
 		"{{end}}" +
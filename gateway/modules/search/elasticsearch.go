@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type elasticsearch struct {
+	client   *http.Client
+	url      string
+	apiKey   string
+	username string
+	password string
+}
+
+type elasticsearchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Score     float64                `json:"_score"`
+			Source    map[string]interface{} `json:"_source"`
+			Highlight map[string][]string    `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (e *elasticsearch) setAuth(r *http.Request) {
+	if e.apiKey != "" {
+		r.Header.Set("Authorization", "ApiKey "+e.apiKey)
+		return
+	}
+	if e.username != "" {
+		r.SetBasicAuth(e.username, e.password)
+	}
+}
+
+func (e *elasticsearch) Index(ctx context.Context, index, id string, doc map[string]interface{}) error {
+	_, err := sendRequest(ctx, e.client, http.MethodPut, fmt.Sprintf("%s/%s/_doc/%s", e.url, index, url.PathEscape(id)), e.setAuth, doc, nil)
+	return err
+}
+
+func (e *elasticsearch) Delete(ctx context.Context, index, id string) error {
+	status, err := sendRequest(ctx, e.client, http.MethodDelete, fmt.Sprintf("%s/%s/_doc/%s", e.url, index, url.PathEscape(id)), e.setAuth, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (e *elasticsearch) Search(ctx context.Context, index string, query *engineQuery) ([]*hit, error) {
+	var q map[string]interface{}
+	if query.query == "" {
+		q = map[string]interface{}{"match_all": map[string]interface{}{}}
+	} else {
+		match := map[string]interface{}{"query": query.query}
+		if len(query.fields) > 0 {
+			match["fields"] = query.fields
+		}
+		q = map[string]interface{}{"multi_match": match}
+	}
+
+	body := map[string]interface{}{"query": q, "from": query.offset, "size": query.limit}
+	if query.highlight {
+		fields := map[string]interface{}{}
+		if len(query.fields) == 0 {
+			fields["*"] = map[string]interface{}{}
+		}
+		for _, field := range query.fields {
+			fields[field] = map[string]interface{}{}
+		}
+		body["highlight"] = map[string]interface{}{"fields": fields, "pre_tags": []string{highlightPreTag}, "post_tags": []string{highlightPostTag}}
+	}
+
+	res := new(elasticsearchResponse)
+	status, err := sendRequest(ctx, e.client, http.MethodPost, fmt.Sprintf("%s/%s/_search", e.url, index), e.setAuth, body, res)
+	if status == http.StatusNotFound {
+		// The index gets created along with the first document
+		return []*hit{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*hit, len(res.Hits.Hits))
+	for i, h := range res.Hits.Hits {
+		hits[i] = &hit{source: h.Source, score: h.Score, highlights: h.Highlight}
+	}
+	return hits, nil
+}
@@ -0,0 +1,151 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// The supported search engines
+const (
+	EngineElasticsearch = "elasticsearch"
+	EngineMeilisearch   = "meilisearch"
+)
+
+// The tags wrapping the matching terms in the highlighted fragments
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+)
+
+// engine is implemented by the clients of the search engines
+type engine interface {
+	// Index adds the document to the index or replaces it if a document with the same id exists
+	Index(ctx context.Context, index, id string, doc map[string]interface{}) error
+	// Delete removes the document from the index. Missing documents and indexes aren't an error
+	Delete(ctx context.Context, index, id string) error
+	// Search returns the documents of the index matching the query ordered by their relevance
+	Search(ctx context.Context, index string, query *engineQuery) ([]*hit, error)
+}
+
+// engineQuery is a search query which has been validated against the config of the collection
+type engineQuery struct {
+	query     string
+	fields    []string
+	limit     int
+	offset    int
+	highlight bool
+}
+
+// hit is a document matching a search query
+type hit struct {
+	source     map[string]interface{}
+	score      float64
+	highlights map[string][]string
+}
+
+func newEngine(c *config.SearchConfig, apiKey, username, password string) (engine, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	url := strings.TrimSuffix(c.URL, "/")
+
+	switch c.Engine {
+	case EngineElasticsearch:
+		return &elasticsearch{client: client, url: url, apiKey: apiKey, username: username, password: password}, nil
+	case EngineMeilisearch:
+		return &meilisearch{client: client, url: url, apiKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("invalid search engine (%s) provided - engine should either be %s or %s", c.Engine, EngineElasticsearch, EngineMeilisearch)
+	}
+}
+
+// getIndexName returns the name of the index of a collection. Both engines accept lower case names made of
+// letters, digits, hyphens and underscores only.
+func getIndexName(prefix, project, dbAlias, col string) string {
+	name := strings.ToLower(fmt.Sprintf("%s%s-%s-%s", prefix, project, dbAlias, col))
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// getDocumentID returns the id of a document in the index. The id is made from the values of the primary keys and
+// is hex encoded since meilisearch only accepts a limited set of characters in ids.
+func getDocumentID(primaryKeys []string, doc map[string]interface{}) (string, bool) {
+	key, ok := getDocumentKey(primaryKeys, doc)
+	if !ok {
+		return "", false
+	}
+	return hex.EncodeToString([]byte(key)), true
+}
+
+// getDocumentKey returns the values of the primary keys of the document joined together. Values are formatted the
+// same way irrespective of their numeric type so that the keys of the database and the search engine match.
+func getDocumentKey(primaryKeys []string, doc map[string]interface{}) (string, bool) {
+	if len(primaryKeys) == 0 {
+		return "", false
+	}
+
+	values := make([]string, len(primaryKeys))
+	for i, key := range primaryKeys {
+		value, ok := doc[key]
+		if !ok || value == nil {
+			return "", false
+		}
+		values[i] = fmt.Sprint(value)
+	}
+	return strings.Join(values, "--"), true
+}
+
+// sendRequest sends a json request to the search engine and decodes the response in out. The status code of the
+// response is returned along with an error for every status code other than 2xx.
+func sendRequest(ctx context.Context, client *http.Client, method, url string, setAuth func(r *http.Request), body, out interface{}) (int, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	utils.InjectRequestID(ctx, req.Header)
+	setAuth(req)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	resData, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("search engine responded with status code (%d) - %s", res.StatusCode, string(resData))
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(resData, out); err != nil {
+			return res.StatusCode, fmt.Errorf("invalid response received from the search engine - %v", err)
+		}
+	}
+	return res.StatusCode, nil
+}
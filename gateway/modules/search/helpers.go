@@ -0,0 +1,92 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// The limits on the number of documents returned by a search
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// The fields added to the documents returned by a search
+const (
+	ScoreField      = "_score"
+	HighlightsField = "_highlights"
+)
+
+var dbEvents = []string{utils.EventDBCreate, utils.EventDBUpdate, utils.EventDBDelete}
+
+func getCollectionKey(dbAlias, col string) string {
+	return dbAlias + "--" + col
+}
+
+func generateTriggers(collections []*config.SearchCollection, url string) []*config.EventingTrigger {
+	triggers := make([]*config.EventingTrigger, 0, len(collections)*len(dbEvents))
+	for _, col := range collections {
+		for _, eventType := range dbEvents {
+			triggers = append(triggers, &config.EventingTrigger{
+				Type:    eventType,
+				URL:     url,
+				Options: map[string]string{"db": col.DbAlias, "col": col.Col},
+				Retries: 3,
+				Timeout: 5000, // Timeout is in milliseconds
+			})
+		}
+	}
+	return triggers
+}
+
+// getIndexDocument returns the part of the document which gets indexed. The primary keys are always indexed since
+// they are needed to load the documents matching a search from the database.
+func getIndexDocument(c *config.SearchCollection, primaryKeys []string, doc map[string]interface{}) map[string]interface{} {
+	if len(c.Fields) == 0 {
+		return doc
+	}
+
+	obj := make(map[string]interface{}, len(c.Fields)+len(primaryKeys))
+	for _, key := range primaryKeys {
+		obj[key] = doc[key]
+	}
+	for _, field := range c.Fields {
+		if value, ok := doc[field]; ok {
+			obj[field] = value
+		}
+	}
+	return obj
+}
+
+// getSearchFields returns the fields a search runs on. Requested fields have to be indexed.
+func getSearchFields(c *config.SearchCollection, fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return c.Fields, nil
+	}
+	if len(c.Fields) == 0 {
+		return fields, nil
+	}
+
+	indexed := make(map[string]struct{}, len(c.Fields))
+	for _, field := range c.Fields {
+		indexed[field] = struct{}{}
+	}
+	for _, field := range fields {
+		if _, ok := indexed[field]; !ok {
+			return nil, fmt.Errorf("field (%s) of collection (%s) is not indexed for full text search", field, c.Col)
+		}
+	}
+	return fields, nil
+}
+
+func getSearchLimit(limit int) int {
+	if limit <= 0 {
+		return defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		return maxSearchLimit
+	}
+	return limit
+}
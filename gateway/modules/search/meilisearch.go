@@ -0,0 +1,98 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// meilisearchIDField is the field holding the id of the documents in meilisearch, which needs the id to be a part
+// of the document
+const meilisearchIDField = "_scId"
+
+type meilisearch struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+type meilisearchResponse struct {
+	Hits []map[string]interface{} `json:"hits"`
+}
+
+func (m *meilisearch) setAuth(r *http.Request) {
+	if m.apiKey != "" {
+		r.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+}
+
+func (m *meilisearch) Index(ctx context.Context, index, id string, doc map[string]interface{}) error {
+	obj := make(map[string]interface{}, len(doc)+1)
+	for k, v := range doc {
+		obj[k] = v
+	}
+	obj[meilisearchIDField] = id
+
+	_, err := sendRequest(ctx, m.client, http.MethodPost, fmt.Sprintf("%s/indexes/%s/documents?primaryKey=%s", m.url, index, meilisearchIDField), m.setAuth, []interface{}{obj}, nil)
+	return err
+}
+
+func (m *meilisearch) Delete(ctx context.Context, index, id string) error {
+	status, err := sendRequest(ctx, m.client, http.MethodDelete, fmt.Sprintf("%s/indexes/%s/documents/%s", m.url, index, url.PathEscape(id)), m.setAuth, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (m *meilisearch) Search(ctx context.Context, index string, query *engineQuery) ([]*hit, error) {
+	body := map[string]interface{}{"q": query.query, "offset": query.offset, "limit": query.limit, "showRankingScore": true}
+	if len(query.fields) > 0 {
+		body["attributesToSearchOn"] = query.fields
+	}
+	if query.highlight {
+		fields := query.fields
+		if len(fields) == 0 {
+			fields = []string{"*"}
+		}
+		body["attributesToHighlight"] = fields
+		body["highlightPreTag"] = highlightPreTag
+		body["highlightPostTag"] = highlightPostTag
+	}
+
+	res := new(meilisearchResponse)
+	status, err := sendRequest(ctx, m.client, http.MethodPost, fmt.Sprintf("%s/indexes/%s/search", m.url, index), m.setAuth, body, res)
+	if status == http.StatusNotFound {
+		// The index gets created along with the first document
+		return []*hit{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*hit, len(res.Hits))
+	for i, doc := range res.Hits {
+		h := &hit{source: doc}
+		if score, ok := doc["_rankingScore"].(float64); ok {
+			h.score = score
+		}
+
+		// Only the fields where a term matched are reported as highlights
+		if formatted, ok := doc["_formatted"].(map[string]interface{}); ok {
+			h.highlights = map[string][]string{}
+			for field, value := range formatted {
+				if s, ok := value.(string); ok && field != meilisearchIDField && strings.Contains(s, highlightPreTag) {
+					h.highlights[field] = []string{s}
+				}
+			}
+		}
+
+		delete(doc, "_formatted")
+		delete(doc, "_rankingScore")
+		delete(doc, meilisearchIDField)
+		hits[i] = h
+	}
+	return hits, nil
+}
@@ -0,0 +1,174 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleEvent updates the index of a collection with a database event delivered by the eventing module
+func (m *Module) HandleEvent(ctx context.Context, eventDoc *model.CloudEventPayload) error {
+	dbEvent := new(model.DatabaseEventMessage)
+	if err := mapstructure.Decode(eventDoc.Data, dbEvent); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to decode database event in search module", err, nil)
+	}
+
+	m.lock.RLock()
+	e := m.engine
+	c, ok := m.collections[getCollectionKey(dbEvent.DBType, dbEvent.Col)]
+	prefix := ""
+	if m.config != nil {
+		prefix = m.config.IndexPrefix
+	}
+	m.lock.RUnlock()
+
+	// The collection might have been removed from the config after the event got queued
+	if e == nil || !ok {
+		return nil
+	}
+
+	index := getIndexName(prefix, m.project, dbEvent.DBType, dbEvent.Col)
+	primaryKeys := m.crud.GetPrimaryKeys(dbEvent.DBType, dbEvent.Col)
+	find, _ := dbEvent.Find.(map[string]interface{})
+
+	switch eventDoc.Type {
+	case utils.EventDBCreate, utils.EventDBUpdate:
+		doc, ok := dbEvent.Doc.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("document of the database event of collection (%s) is not an object", dbEvent.Col)
+		}
+
+		id, ok := getDocumentID(primaryKeys, doc)
+		if !ok {
+			if id, ok = getDocumentID(primaryKeys, find); !ok {
+				return fmt.Errorf("primary keys of collection (%s) not found in the database event", dbEvent.Col)
+			}
+		}
+		return e.Index(ctx, index, id, getIndexDocument(c, primaryKeys, doc))
+
+	case utils.EventDBDelete:
+		id, ok := getDocumentID(primaryKeys, find)
+		if !ok {
+			return fmt.Errorf("primary keys of collection (%s) not found in the database event", dbEvent.Col)
+		}
+		return e.Delete(ctx, index, id)
+	}
+	return nil
+}
+
+// Search runs a full text search on a collection. The matching documents are loaded from the database with the
+// read rule of the collection applied, which means that documents the user isn't allowed to read are left out. The
+// documents are ordered by their relevance and carry their score and highlights in the _score and _highlights fields.
+func (m *Module) Search(ctx context.Context, dbAlias, col, token string, req *model.SearchRequest) ([]interface{}, error) {
+	m.lock.RLock()
+	e := m.engine
+	c, ok := m.collections[getCollectionKey(dbAlias, col)]
+	prefix := ""
+	if m.config != nil {
+		prefix = m.config.IndexPrefix
+	}
+	aesKey := m.aesKey
+	m.lock.RUnlock()
+
+	if e == nil {
+		return nil, errors.New("full text search is not enabled for this project")
+	}
+	if !ok {
+		return nil, fmt.Errorf("collection (%s) of database (%s) is not indexed for full text search", col, dbAlias)
+	}
+
+	fields, err := getSearchFields(c, req.Fields)
+	if err != nil {
+		return nil, err
+	}
+	primaryKeys := m.crud.GetPrimaryKeys(dbAlias, col)
+	if len(primaryKeys) == 0 {
+		return nil, fmt.Errorf("collection (%s) needs a primary key to be searched", col)
+	}
+
+	hits, err := e.Search(ctx, getIndexName(prefix, m.project, dbAlias, col), &engineQuery{query: req.Query, fields: fields, limit: getSearchLimit(req.Limit), offset: req.Offset, highlight: req.Highlight})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to search collection (%s)", col), err, nil)
+	}
+
+	clauses := make([]interface{}, 0, len(hits))
+	for _, h := range hits {
+		if _, ok := getDocumentKey(primaryKeys, h.source); !ok {
+			continue
+		}
+		find := make(map[string]interface{}, len(primaryKeys))
+		for _, key := range primaryKeys {
+			find[key] = h.source[key]
+		}
+		clauses = append(clauses, find)
+	}
+	if len(clauses) == 0 {
+		return []interface{}{}, nil
+	}
+
+	// Load the documents from the database so that the read rule of the collection gets applied
+	readReq := &model.ReadRequest{Find: map[string]interface{}{"$or": clauses}, Operation: utils.All, Options: &model.ReadOptions{}}
+	dbType, _ := m.crud.GetDBType(dbAlias)
+	returnWhere := model.ReturnWhereStub{Col: col, ReturnWhere: dbType != string(model.Mongo), Where: map[string]interface{}{}}
+	actions, reqParams, err := m.auth.IsReadOpAuthorised(ctx, m.project, dbAlias, col, token, readReq, returnWhere)
+	if err != nil {
+		return nil, err
+	}
+	if len(returnWhere.Where) > 0 {
+		readReq.MatchWhere = append(readReq.MatchWhere, returnWhere.Where)
+	}
+
+	result, _, err := m.crud.Read(ctx, dbAlias, col, readReq, reqParams)
+	if err != nil {
+		return nil, err
+	}
+	docs, _ := result.([]interface{})
+
+	// The primary keys are read before post processing since the rules might remove them
+	docsByKey := make(map[string]map[string]interface{}, len(docs))
+	for _, doc := range docs {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if key, ok := getDocumentKey(primaryKeys, obj); ok {
+			docsByKey[key] = obj
+		}
+	}
+	if err := authHelpers.PostProcessMethod(ctx, aesKey, actions, docs); err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, 0, len(docsByKey))
+	for _, h := range hits {
+		key, ok := getDocumentKey(primaryKeys, h.source)
+		if !ok {
+			continue
+		}
+		doc, ok := docsByKey[key]
+		if !ok {
+			continue
+		}
+
+		doc[ScoreField] = h.score
+		if req.Highlight {
+			// Highlights of fields removed by the read rule would leak their values
+			highlights := map[string]interface{}{}
+			for field, fragments := range h.highlights {
+				if _, ok := doc[field]; ok {
+					highlights[field] = fragments
+				}
+			}
+			doc[HighlightsField] = highlights
+		}
+		results = append(results, doc)
+	}
+	return results, nil
+}
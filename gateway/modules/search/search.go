@@ -0,0 +1,126 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Module syncs the collections of a project to a full text search engine and runs searches on them
+type Module struct {
+	lock sync.RWMutex
+
+	project string
+	url     string // The url at which the search module receives the database events
+
+	config      *config.SearchConfig
+	engine      engine
+	collections map[string]*config.SearchCollection // Key here is dbAlias--col
+
+	// The external modules search depends on
+	auth     authInterface
+	crud     crudInterface
+	eventing eventingInterface
+
+	resolveSecret utils.ResolveSecret
+
+	// Auth module
+	aesKey []byte
+}
+
+// New creates a new instance of the search module. Database events are delivered to the provided url.
+func New(project, url string, auth authInterface, crud crudInterface, eventing eventingInterface) *Module {
+	return &Module{project: project, url: url, auth: auth, crud: crud, eventing: eventing, collections: map[string]*config.SearchCollection{}}
+}
+
+// SetConfig sets the config of the search module and registers the triggers which keep the indexes in sync
+func (m *Module) SetConfig(c *config.SearchConfig) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if c == nil || !c.Enabled {
+		m.config = c
+		m.engine = nil
+		m.collections = map[string]*config.SearchCollection{}
+		m.eventing.SetSearchTriggers(nil)
+		return nil
+	}
+
+	if c.URL == "" {
+		return errors.New("url of the search engine not provided")
+	}
+
+	apiKey, err := m.getSecret(c.APIKey)
+	if err != nil {
+		return err
+	}
+	username, err := m.getSecret(c.Username)
+	if err != nil {
+		return err
+	}
+	password, err := m.getSecret(c.Password)
+	if err != nil {
+		return err
+	}
+
+	e, err := newEngine(c, apiKey, username, password)
+	if err != nil {
+		return err
+	}
+
+	collections := make(map[string]*config.SearchCollection, len(c.Collections))
+	for _, col := range c.Collections {
+		collections[getCollectionKey(col.DbAlias, col.Col)] = col
+	}
+
+	m.config = c
+	m.engine = e
+	m.collections = collections
+	m.eventing.SetSearchTriggers(generateTriggers(c.Collections, m.url))
+	return nil
+}
+
+// SetProjectAESKey sets the aes key used to post process the search results
+func (m *Module) SetProjectAESKey(aesKey string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	decodedAESKey, err := base64.StdEncoding.DecodeString(aesKey)
+	if err != nil {
+		return err
+	}
+	m.aesKey = decodedAESKey
+	return nil
+}
+
+// SetResolveSecret sets the function to resolve secrets from external secret managers
+func (m *Module) SetResolveSecret(function utils.ResolveSecret) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.resolveSecret = function
+}
+
+// CloseConfig removes the triggers of the search module
+func (m *Module) CloseConfig() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = nil
+	m.engine = nil
+	m.collections = map[string]*config.SearchCollection{}
+	m.eventing.SetSearchTriggers(nil)
+	return nil
+}
+
+func (m *Module) getSecret(value string) (string, error) {
+	if secrets.IsReference(value) && m.resolveSecret != nil {
+		return m.resolveSecret(context.TODO(), value)
+	}
+	return value, nil
+}
@@ -0,0 +1,263 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+type fakeAuth struct {
+	err     error
+	actions *model.PostProcess
+}
+
+func (a *fakeAuth) IsReadOpAuthorised(_ context.Context, _, _, _, _ string, _ *model.ReadRequest, _ model.ReturnWhereStub) (*model.PostProcess, model.RequestParams, error) {
+	return a.actions, model.RequestParams{}, a.err
+}
+
+type fakeCrud struct {
+	docs []interface{}
+	req  *model.ReadRequest
+}
+
+func (c *fakeCrud) Read(_ context.Context, _, _ string, req *model.ReadRequest, _ model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	c.req = req
+	return c.docs, nil, nil
+}
+
+func (c *fakeCrud) GetDBType(string) (string, error) {
+	return string(model.Postgres), nil
+}
+
+func (c *fakeCrud) GetPrimaryKeys(string, string) []string {
+	return []string{"id"}
+}
+
+type fakeEventing struct {
+	triggers []*config.EventingTrigger
+}
+
+func (e *fakeEventing) SetSearchTriggers(triggers []*config.EventingTrigger) {
+	e.triggers = triggers
+}
+
+type engineRequest struct {
+	method string
+	path   string
+	body   interface{}
+}
+
+// newEngineServer returns a fake search engine which records the requests it receives and responds with the
+// provided response
+func newEngineServer(t *testing.T, response interface{}, requests *[]engineRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		var body interface{}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Errorf("invalid body sent to the search engine - %v", err)
+			}
+		}
+		*requests = append(*requests, engineRequest{method: r.Method, path: r.URL.String(), body: body})
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestModule_HandleEvent(t *testing.T) {
+	id := getDocumentIDOrFail(t, map[string]interface{}{"id": "1"})
+
+	tests := []struct {
+		name   string
+		engine string
+		fields []string
+		event  *model.CloudEventPayload
+		want   engineRequest
+	}{
+		{
+			name:   "elasticsearch insert",
+			engine: EngineElasticsearch,
+			fields: []string{"title"},
+			event:  &model.CloudEventPayload{Type: utils.EventDBCreate, Data: map[string]interface{}{"db": "db", "col": "posts", "doc": map[string]interface{}{"id": "1", "title": "hello", "views": 10}}},
+			want:   engineRequest{method: http.MethodPut, path: "/sc_project-db-posts/_doc/" + id, body: map[string]interface{}{"id": "1", "title": "hello"}},
+		},
+		{
+			name:   "elasticsearch delete",
+			engine: EngineElasticsearch,
+			event:  &model.CloudEventPayload{Type: utils.EventDBDelete, Data: map[string]interface{}{"db": "db", "col": "posts", "find": map[string]interface{}{"id": "1"}}},
+			want:   engineRequest{method: http.MethodDelete, path: "/sc_project-db-posts/_doc/" + id},
+		},
+		{
+			name:   "meilisearch update",
+			engine: EngineMeilisearch,
+			event:  &model.CloudEventPayload{Type: utils.EventDBUpdate, Data: map[string]interface{}{"db": "db", "col": "posts", "doc": map[string]interface{}{"id": "1", "title": "hello"}}},
+			want:   engineRequest{method: http.MethodPost, path: "/indexes/sc_project-db-posts/documents?primaryKey=_scId", body: []interface{}{map[string]interface{}{"id": "1", "title": "hello", "_scId": id}}},
+		},
+		{
+			name:   "meilisearch delete",
+			engine: EngineMeilisearch,
+			event:  &model.CloudEventPayload{Type: utils.EventDBDelete, Data: map[string]interface{}{"db": "db", "col": "posts", "find": map[string]interface{}{"id": "1"}}},
+			want:   engineRequest{method: http.MethodDelete, path: "/indexes/sc_project-db-posts/documents/" + id},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make([]engineRequest, 0)
+			server := newEngineServer(t, map[string]interface{}{}, &requests)
+			defer server.Close()
+
+			eventing := new(fakeEventing)
+			m := New("project", "http://localhost:4122/v1/api/project/search/handle", &fakeAuth{}, &fakeCrud{}, eventing)
+			c := &config.SearchConfig{Enabled: true, Engine: tt.engine, URL: server.URL, IndexPrefix: "sc_", Collections: []*config.SearchCollection{{DbAlias: "db", Col: "posts", Fields: tt.fields}}}
+			if err := m.SetConfig(c); err != nil {
+				t.Fatalf("SetConfig() error = %v", err)
+			}
+			if len(eventing.triggers) != 3 {
+				t.Errorf("SetConfig() registered %d triggers, want 3", len(eventing.triggers))
+			}
+
+			if err := m.HandleEvent(context.Background(), tt.event); err != nil {
+				t.Fatalf("HandleEvent() error = %v", err)
+			}
+			if len(requests) != 1 || !reflect.DeepEqual(requests[0], tt.want) {
+				t.Errorf("HandleEvent() sent %v, want %v", requests, tt.want)
+			}
+
+			// Events of collections which aren't indexed are ignored
+			if err := m.HandleEvent(context.Background(), &model.CloudEventPayload{Type: utils.EventDBCreate, Data: map[string]interface{}{"db": "db", "col": "users", "doc": map[string]interface{}{"id": "1"}}}); err != nil {
+				t.Fatalf("HandleEvent() error = %v", err)
+			}
+			if len(requests) != 1 {
+				t.Errorf("HandleEvent() sent a request for a collection which isn't indexed")
+			}
+		})
+	}
+}
+
+func TestModule_Search(t *testing.T) {
+	esResponse := map[string]interface{}{"hits": map[string]interface{}{"hits": []interface{}{
+		map[string]interface{}{"_score": 2.5, "_source": map[string]interface{}{"id": float64(2), "title": "second"}, "highlight": map[string]interface{}{"title": []string{"<em>second</em>"}, "secret": []string{"<em>x</em>"}}},
+		map[string]interface{}{"_score": 1.5, "_source": map[string]interface{}{"id": float64(1), "title": "first"}},
+		map[string]interface{}{"_score": 0.5, "_source": map[string]interface{}{"id": float64(3), "title": "third"}},
+	}}}
+	meiliResponse := map[string]interface{}{"hits": []interface{}{
+		map[string]interface{}{"id": float64(1), "title": "first", "_scId": "31", "_rankingScore": 0.9, "_formatted": map[string]interface{}{"id": "1", "title": "<em>first</em>"}},
+	}}
+
+	tests := []struct {
+		name     string
+		engine   string
+		response interface{}
+		auth     *fakeAuth
+		docs     []interface{}
+		req      *model.SearchRequest
+		want     []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "documents are ordered by their score and unauthorised ones are left out",
+			engine:   EngineElasticsearch,
+			response: esResponse,
+			auth:     &fakeAuth{actions: &model.PostProcess{PostProcessAction: []model.PostProcessAction{{Action: "remove", Field: "res.secret"}}}},
+			// The third document is filtered by the read rule of the collection
+			docs: []interface{}{
+				map[string]interface{}{"id": int64(1), "title": "first", "secret": "a"},
+				map[string]interface{}{"id": int64(2), "title": "second", "secret": "b"},
+			},
+			req: &model.SearchRequest{Query: "hello", Highlight: true},
+			want: []interface{}{
+				map[string]interface{}{"id": int64(2), "title": "second", ScoreField: 2.5, HighlightsField: map[string]interface{}{"title": []string{"<em>second</em>"}}},
+				map[string]interface{}{"id": int64(1), "title": "first", ScoreField: 1.5, HighlightsField: map[string]interface{}{}},
+			},
+		},
+		{
+			name:     "meilisearch",
+			engine:   EngineMeilisearch,
+			response: meiliResponse,
+			auth:     &fakeAuth{},
+			docs:     []interface{}{map[string]interface{}{"id": int64(1), "title": "first"}},
+			req:      &model.SearchRequest{Query: "first", Highlight: true},
+			want:     []interface{}{map[string]interface{}{"id": int64(1), "title": "first", ScoreField: 0.9, HighlightsField: map[string]interface{}{"title": []string{"<em>first</em>"}}}},
+		},
+		{
+			name:     "search denied by the read rule",
+			engine:   EngineElasticsearch,
+			response: esResponse,
+			auth:     &fakeAuth{err: errors.New("denied")},
+			req:      &model.SearchRequest{Query: "hello"},
+			wantErr:  true,
+		},
+		{
+			name:     "field which isn't indexed",
+			engine:   EngineElasticsearch,
+			response: esResponse,
+			auth:     &fakeAuth{},
+			req:      &model.SearchRequest{Query: "hello", Fields: []string{"secret"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make([]engineRequest, 0)
+			server := newEngineServer(t, tt.response, &requests)
+			defer server.Close()
+
+			crud := &fakeCrud{docs: tt.docs}
+			m := New("project", "", tt.auth, crud, new(fakeEventing))
+			c := &config.SearchConfig{Enabled: true, Engine: tt.engine, URL: server.URL, Collections: []*config.SearchCollection{{DbAlias: "db", Col: "posts", Fields: []string{"title"}}}}
+			if err := m.SetConfig(c); err != nil {
+				t.Fatalf("SetConfig() error = %v", err)
+			}
+
+			got, err := m.Search(context.Background(), "db", "posts", "token", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+			if _, ok := crud.req.Find["$or"]; !ok {
+				t.Errorf("Search() read the documents with %v, want the primary keys of the hits", crud.req.Find)
+			}
+		})
+	}
+}
+
+func TestModule_SearchDisabled(t *testing.T) {
+	eventing := &fakeEventing{triggers: []*config.EventingTrigger{{}}}
+	m := New("project", "", &fakeAuth{}, &fakeCrud{}, eventing)
+	if err := m.SetConfig(&config.SearchConfig{Enabled: false}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if len(eventing.triggers) != 0 {
+		t.Errorf("SetConfig() kept %d triggers of a disabled search module", len(eventing.triggers))
+	}
+	if _, err := m.Search(context.Background(), "db", "posts", "token", &model.SearchRequest{Query: "hello"}); err == nil {
+		t.Errorf("Search() error = nil, want an error when search is disabled")
+	}
+}
+
+func TestGetIndexName(t *testing.T) {
+	if got, want := getIndexName("SC_", "My Project", "db", "Posts.v1"), "sc_my_project-db-posts_v1"; got != want {
+		t.Errorf("getIndexName() = %v, want %v", got, want)
+	}
+}
+
+func getDocumentIDOrFail(t *testing.T, doc map[string]interface{}) string {
+	id, ok := getDocumentID([]string{"id"}, doc)
+	if !ok {
+		t.Fatalf("getDocumentID() could not find the primary key in %v", doc)
+	}
+	return id
+}
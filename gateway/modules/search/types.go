@@ -0,0 +1,22 @@
+package search
+
+import (
+	"context"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type authInterface interface {
+	IsReadOpAuthorised(ctx context.Context, project, dbAlias, col, token string, req *model.ReadRequest, stub model.ReturnWhereStub) (*model.PostProcess, model.RequestParams, error)
+}
+
+type crudInterface interface {
+	Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error)
+	GetDBType(dbAlias string) (string, error)
+	GetPrimaryKeys(dbAlias, col string) []string
+}
+
+type eventingInterface interface {
+	SetSearchTriggers(eventingRules []*config.EventingTrigger)
+}
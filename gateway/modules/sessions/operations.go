@@ -0,0 +1,205 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	jwtUtils "github.com/spaceuptech/space-cloud/gateway/utils/jwt"
+)
+
+// Create starts a session of the user and returns its id, which is to be set as the sid claim of the token issued.
+// The id is empty if the sessions aren't enabled. The oldest sessions are revoked if the user has too many.
+func (m *Module) Create(ctx context.Context, userID string) (string, error) {
+	m.lock.RLock()
+	s, c := m.store, m.config
+	m.lock.RUnlock()
+
+	if s == nil {
+		return "", nil
+	}
+
+	if c.MaxSessions > 0 {
+		sessions, err := m.list(ctx, s, userID)
+		if err != nil {
+			return "", err
+		}
+		// The sessions are sorted with the newest first
+		for i := c.MaxSessions - 1; i >= 0 && i < len(sessions); i++ {
+			if err := m.revoke(ctx, s, sessions[i]); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	now := time.Now()
+	session := &model.Session{ID: ksuid.New().String(), UserID: userID, CreatedAt: now, ExpiresAt: now.Add(jwtUtils.DefaultExpiry)}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	if err := s.SetKey(ctx, m.getUserPrefix(userID)+session.ID, string(data), jwtUtils.DefaultExpiry); err != nil {
+		return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to save session", err, nil)
+	}
+	return session.ID, nil
+}
+
+// List returns the active sessions of a user with the newest first
+func (m *Module) List(ctx context.Context, userID string) ([]*model.Session, error) {
+	s, err := m.getStore()
+	if err != nil {
+		return nil, err
+	}
+	return m.list(ctx, s, userID)
+}
+
+// Revoke revokes a session of a user. The tokens of the session stop working on all the gateways.
+func (m *Module) Revoke(ctx context.Context, userID, sessionID string) error {
+	s, err := m.getStore()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := m.list(ctx, s, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			return m.revoke(ctx, s, session)
+		}
+	}
+	return fmt.Errorf("session (%s) of user not found", sessionID)
+}
+
+// RevokeAll revokes all the sessions of a user, signing the user out everywhere. It returns the number of sessions
+// revoked.
+func (m *Module) RevokeAll(ctx context.Context, userID string) (int, error) {
+	s, err := m.getStore()
+	if err != nil {
+		return 0, err
+	}
+
+	sessions, err := m.list(ctx, s, userID)
+	if err != nil {
+		return 0, err
+	}
+	for i, session := range sessions {
+		if err := m.revoke(ctx, s, session); err != nil {
+			return i, err
+		}
+	}
+	return len(sessions), nil
+}
+
+// GetSessions returns the active sessions of the user having the provided token. The session of the token is
+// marked as the current one.
+func (m *Module) GetSessions(ctx context.Context, token string) ([]*model.Session, error) {
+	claims, userID, err := m.parseToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := m.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == claims["sid"]
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes a session of the user having the provided token
+func (m *Module) RevokeSession(ctx context.Context, token, sessionID string) error {
+	_, userID, err := m.parseToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	return m.Revoke(ctx, userID, sessionID)
+}
+
+// RevokeSessions revokes all the sessions of the user having the provided token, including the one of the token
+func (m *Module) RevokeSessions(ctx context.Context, token string) (int, error) {
+	_, userID, err := m.parseToken(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return m.RevokeAll(ctx, userID)
+}
+
+// revoke adds the session to the revocation list and broadcasts it to the other gateways. The revocation is kept
+// until the token of the session expires.
+func (m *Module) revoke(ctx context.Context, s store, session *model.Session) error {
+	key := m.getUserPrefix(session.UserID) + session.ID
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return s.DeleteKey(ctx, key)
+	}
+
+	r := &revocation{ID: session.ID, ExpiresAt: session.ExpiresAt}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := s.SetKey(ctx, m.getRevokedPrefix()+session.ID, string(data), ttl); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to revoke session", err, nil)
+	}
+	m.addRevoked(s, r)
+
+	// The other gateways pick the revocation up on their next reload if it couldn't be broadcast
+	if err := s.Publish(ctx, revocationTopic, string(data)); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to broadcast revocation of session", err, nil)
+	}
+	return s.DeleteKey(ctx, key)
+}
+
+func (m *Module) list(ctx context.Context, s store, userID string) ([]*model.Session, error) {
+	values, err := s.GetKeysWithPrefix(ctx, m.getUserPrefix(userID))
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to list sessions", err, nil)
+	}
+
+	sessions := make([]*model.Session, 0, len(values))
+	for _, value := range values {
+		session := new(model.Session)
+		if err := json.Unmarshal([]byte(value), session); err != nil {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (m *Module) getStore() (store, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.store == nil {
+		return nil, errors.New("sessions are not enabled")
+	}
+	return m.store, nil
+}
+
+// parseToken returns the claims of the token along with the id of the user it was issued to
+func (m *Module) parseToken(ctx context.Context, token string) (map[string]interface{}, string, error) {
+	claims, err := m.auth.ParseToken(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+	userID, ok := claims["id"].(string)
+	if !ok || userID == "" {
+		return nil, "", errors.New("token does not identify a user")
+	}
+	return claims, userID, nil
+}
@@ -0,0 +1,231 @@
+package sessions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+const (
+	// revocationTopic is the topic the revocations are broadcast on to the other gateways
+	revocationTopic = "revocations"
+
+	// reloadInterval is how often the revocation list is reloaded from redis, so that the revocations broadcast
+	// while a gateway wasn't subscribed are picked up as well
+	reloadInterval = time.Minute
+)
+
+// Module tracks the sessions of the tokens issued to the users of a project. Every gateway keeps the list of the
+// revoked sessions in memory, so that tokens can be checked without a round trip to redis. The list is replicated
+// by broadcasting the revocations to all the gateways.
+type Module struct {
+	lock sync.RWMutex
+
+	clusterID string
+	project   string
+
+	config  *config.SessionsConfig
+	store   store
+	revoked map[string]time.Time // Key is the session id. Value is the time its token expires
+	closeC  chan struct{}
+
+	// The external modules sessions depends on
+	auth authInterface
+
+	// newStore connects to the redis at the provided address. It is overridden in tests
+	newStore func(name, conn string) (store, error)
+}
+
+// revocation is the message broadcast when a session is revoked
+type revocation struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// New creates a new instance of the sessions module
+func New(clusterID, project string, auth authInterface) *Module {
+	return &Module{clusterID: clusterID, project: project, auth: auth, revoked: map[string]time.Time{}, newStore: newRedisStore}
+}
+
+func newRedisStore(name, conn string) (store, error) {
+	return pubsub.New(name, conn)
+}
+
+// SetConfig sets the config of the sessions. Enabling the sessions connects to the redis database of the cluster
+// and loads the revocation list.
+func (m *Module) SetConfig(c *config.SessionsConfig) error {
+	if c != nil && c.MaxSessions < 0 {
+		return fmt.Errorf("max sessions of a user cannot be negative")
+	}
+
+	m.lock.Lock()
+	if c == nil || !c.Enabled {
+		m.closeStore()
+		m.config = c
+		m.lock.Unlock()
+		return nil
+	}
+	if m.store != nil {
+		m.config = c
+		m.lock.Unlock()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(utils.DefaultContextTime)*time.Second)
+	defer cancel()
+
+	s, err := m.newStore(fmt.Sprintf("sessions/%s/%s", m.clusterID, m.project), os.Getenv("REDIS_CONN"))
+	if err != nil {
+		m.lock.Unlock()
+		return fmt.Errorf("unable to connect to the redis store of the sessions - %v", err)
+	}
+	ch, err := s.Subscribe(ctx, revocationTopic)
+	if err != nil {
+		s.Close()
+		m.lock.Unlock()
+		return fmt.Errorf("unable to subscribe to the revocations of the sessions - %v", err)
+	}
+
+	m.config = c
+	m.store = s
+	m.revoked = map[string]time.Time{}
+	m.closeC = make(chan struct{})
+	go m.routine(s, ch, m.closeC)
+	m.lock.Unlock()
+
+	// Revocations broadcast from now on are received, hence loading the list afterwards doesn't miss any
+	return m.reload(ctx, s)
+}
+
+// CloseConfig disconnects from redis and forgets the revocation list
+func (m *Module) CloseConfig() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = nil
+	m.closeStore()
+	return nil
+}
+
+// IsRevoked checks if the token having the claims belongs to a revoked session. Tokens without a session are never
+// revoked.
+func (m *Module) IsRevoked(claims map[string]interface{}) bool {
+	sid, ok := claims["sid"].(string)
+	if !ok {
+		return false
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	_, ok = m.revoked[sid]
+	return ok
+}
+
+// closeStore must be called with the lock acquired. The routine isn't waited upon since it needs the lock.
+func (m *Module) closeStore() {
+	if m.store == nil {
+		return
+	}
+	close(m.closeC)
+	m.store.CancelSubscription(revocationTopic)
+	m.store.Close()
+	m.store = nil
+	m.revoked = map[string]time.Time{}
+}
+
+func (m *Module) routine(s store, ch <-chan *redis.Message, closeC chan struct{}) {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closeC:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			r := new(revocation)
+			if err := json.Unmarshal([]byte(msg.Payload), r); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to unmarshal revocation of session", err, nil)
+				continue
+			}
+			m.addRevoked(s, r)
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(utils.DefaultContextTime)*time.Second)
+			if err := m.reload(ctx, s); err != nil {
+				_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to reload the revoked sessions", err, map[string]interface{}{"project": m.project})
+			}
+			cancel()
+		}
+	}
+}
+
+// reload replaces the revocation list with the one in redis. Revocations expire from redis along with their tokens.
+func (m *Module) reload(ctx context.Context, s store) error {
+	values, err := s.GetKeysWithPrefix(ctx, m.getRevokedPrefix())
+	if err != nil {
+		return err
+	}
+
+	revoked := make(map[string]time.Time, len(values))
+	for _, value := range values {
+		r := new(revocation)
+		if err := json.Unmarshal([]byte(value), r); err != nil {
+			continue
+		}
+		revoked[r.ID] = r.ExpiresAt
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// The config might have changed while the list was being loaded
+	if m.store != s {
+		return nil
+	}
+	now := time.Now()
+	for id, expiresAt := range m.revoked {
+		// Keep the revocations which were received after the list was read
+		if _, ok := revoked[id]; !ok && now.Before(expiresAt) {
+			revoked[id] = expiresAt
+		}
+	}
+	m.revoked = revoked
+	return nil
+}
+
+func (m *Module) addRevoked(s store, r *revocation) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.store == s {
+		m.revoked[r.ID] = r.ExpiresAt
+	}
+}
+
+func (m *Module) getPrefix() string {
+	return fmt.Sprintf("sessions/%s/%s/", m.clusterID, m.project)
+}
+
+func (m *Module) getRevokedPrefix() string {
+	return m.getPrefix() + "revoked/"
+}
+
+// getUserPrefix returns the prefix of the sessions of a user. The id is encoded since it may hold characters having
+// a special meaning in the key patterns of redis.
+func (m *Module) getUserPrefix(userID string) string {
+	return m.getPrefix() + "users/" + base64.RawURLEncoding.EncodeToString([]byte(userID)) + "/"
+}
@@ -0,0 +1,187 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// mockRedis is the redis shared by the gateways in the tests
+type mockRedis struct {
+	lock sync.Mutex
+	keys map[string]string
+	subs []chan *redis.Message
+}
+
+// mockStore is the connection of a gateway to the shared redis
+type mockStore struct {
+	redis *mockRedis
+}
+
+func (s *mockStore) SetKey(ctx context.Context, key, value string, t time.Duration) error {
+	s.redis.lock.Lock()
+	defer s.redis.lock.Unlock()
+	s.redis.keys[key] = value
+	return nil
+}
+
+func (s *mockStore) DeleteKey(ctx context.Context, key string) error {
+	s.redis.lock.Lock()
+	defer s.redis.lock.Unlock()
+	delete(s.redis.keys, key)
+	return nil
+}
+
+func (s *mockStore) GetKeysWithPrefix(ctx context.Context, prefix string) (map[string]string, error) {
+	s.redis.lock.Lock()
+	defer s.redis.lock.Unlock()
+	result := map[string]string{}
+	for k, v := range s.redis.keys {
+		if strings.HasPrefix(k, prefix) {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+func (s *mockStore) Publish(ctx context.Context, topic, value string) error {
+	s.redis.lock.Lock()
+	defer s.redis.lock.Unlock()
+	for _, ch := range s.redis.subs {
+		ch <- &redis.Message{Channel: topic, Payload: value}
+	}
+	return nil
+}
+
+func (s *mockStore) Subscribe(ctx context.Context, topic string) (<-chan *redis.Message, error) {
+	s.redis.lock.Lock()
+	defer s.redis.lock.Unlock()
+	ch := make(chan *redis.Message, 10)
+	s.redis.subs = append(s.redis.subs, ch)
+	return ch, nil
+}
+
+func (s *mockStore) CancelSubscription(topic string) {}
+
+func (s *mockStore) Close() {}
+
+type mockAuth struct{}
+
+// ParseToken treats the token as the id of the user followed by the id of the session
+func (mockAuth) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	arr := strings.Split(token, ":")
+	if len(arr) != 2 {
+		return nil, errors.New("invalid token")
+	}
+	return map[string]interface{}{"id": arr[0], "sid": arr[1]}, nil
+}
+
+func newTestModule(t *testing.T, r *mockRedis, c *config.SessionsConfig) *Module {
+	m := New("cluster", "project", mockAuth{})
+	m.newStore = func(name, conn string) (store, error) {
+		return &mockStore{redis: r}, nil
+	}
+	if err := m.SetConfig(c); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	return m
+}
+
+// waitForRevoked waits for the revocation of the session to be received
+func waitForRevoked(m *Module, sid string) bool {
+	for i := 0; i < 100; i++ {
+		if m.IsRevoked(map[string]interface{}{"sid": sid}) {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestModule_Revoke(t *testing.T) {
+	r := &mockRedis{keys: map[string]string{}}
+	c := &config.SessionsConfig{Enabled: true}
+	first, second := newTestModule(t, r, c), newTestModule(t, r, c)
+	defer func() { _ = first.CloseConfig(); _ = second.CloseConfig() }()
+	ctx := context.Background()
+
+	a, _ := first.Create(ctx, "1")
+	b, _ := first.Create(ctx, "1")
+	other, _ := first.Create(ctx, "2")
+
+	sessions, err := second.GetSessions(ctx, "1:"+a)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("GetSessions() = %v error = %v, want 2 sessions", sessions, err)
+	}
+	for _, s := range sessions {
+		if s.Current != (s.ID == a) {
+			t.Errorf("GetSessions() marked session (%s) current = %v", s.ID, s.Current)
+		}
+	}
+
+	// The revocation is broadcast to the other gateway
+	if err := first.RevokeSession(ctx, "1:"+a, b); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if !waitForRevoked(second, b) {
+		t.Fatalf("IsRevoked() of the other gateway = false, want true")
+	}
+	if first.IsRevoked(map[string]interface{}{"sid": a}) || first.IsRevoked(map[string]interface{}{"id": "1"}) {
+		t.Errorf("IsRevoked() = true for a session which wasn't revoked")
+	}
+	if err := first.RevokeSession(ctx, "2:"+other, a); err == nil {
+		t.Errorf("RevokeSession() of the session of another user didn't return an error")
+	}
+
+	// Signing out everywhere leaves the sessions of other users alone
+	if n, err := second.RevokeSessions(ctx, "1:"+a); err != nil || n != 1 {
+		t.Fatalf("RevokeSessions() = %d error = %v, want 1", n, err)
+	}
+	if !waitForRevoked(first, a) || first.IsRevoked(map[string]interface{}{"sid": other}) {
+		t.Errorf("RevokeSessions() didn't revoke exactly the sessions of the user")
+	}
+
+	// A gateway started later loads the revocation list
+	third := newTestModule(t, r, c)
+	defer func() { _ = third.CloseConfig() }()
+	if !third.IsRevoked(map[string]interface{}{"sid": a}) || !third.IsRevoked(map[string]interface{}{"sid": b}) {
+		t.Errorf("IsRevoked() of a new gateway = false, want true")
+	}
+}
+
+func TestModule_Create(t *testing.T) {
+	r := &mockRedis{keys: map[string]string{}}
+	ctx := context.Background()
+
+	// No sessions are tracked unless enabled
+	m := newTestModule(t, r, nil)
+	if sid, err := m.Create(ctx, "1"); err != nil || sid != "" {
+		t.Fatalf("Create() = %s error = %v, want no session", sid, err)
+	}
+	if _, err := m.List(ctx, "1"); err == nil {
+		t.Errorf("List() of disabled sessions didn't return an error")
+	}
+
+	m = newTestModule(t, r, &config.SessionsConfig{Enabled: true, MaxSessions: 2})
+	defer func() { _ = m.CloseConfig() }()
+	oldest, _ := m.Create(ctx, "1")
+	time.Sleep(time.Millisecond)
+	_, _ = m.Create(ctx, "1")
+	time.Sleep(time.Millisecond)
+	newest, _ := m.Create(ctx, "1")
+
+	sessions, err := m.List(ctx, "1")
+	if err != nil || len(sessions) != 2 || sessions[0].ID != newest {
+		t.Fatalf("List() = %v error = %v, want the 2 newest sessions", sessions, err)
+	}
+	if !m.IsRevoked(map[string]interface{}{"sid": oldest}) {
+		t.Errorf("Create() didn't revoke the oldest session")
+	}
+}
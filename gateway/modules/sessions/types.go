@@ -0,0 +1,27 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+type authInterface interface {
+	ParseToken(ctx context.Context, token string) (map[string]interface{}, error)
+}
+
+// store is the subset of the redis client used to keep the sessions and broadcast their revocations
+type store interface {
+	SetKey(ctx context.Context, key, value string, t time.Duration) error
+	DeleteKey(ctx context.Context, key string) error
+	GetKeysWithPrefix(ctx context.Context, prefix string) (map[string]string, error)
+	Publish(ctx context.Context, topic, value string) error
+	Subscribe(ctx context.Context, topic string) (<-chan *redis.Message, error)
+	CancelSubscription(topic string)
+	Close()
+}
+
+var _ store = (*pubsub.Module)(nil)
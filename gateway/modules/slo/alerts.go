@@ -0,0 +1,132 @@
+package slo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// The states of an alert sent to the webhook
+const (
+	alertFiring   = "firing"
+	alertResolved = "resolved"
+)
+
+// alert is the payload posted to the webhook of a service level objective
+type alert struct {
+	Project       string    `json:"project"`
+	SLO           string    `json:"slo"`
+	Status        string    `json:"status"`
+	Objective     float64   `json:"objective"`
+	LongWindow    int       `json:"longWindow"`
+	ShortWindow   int       `json:"shortWindow"`
+	BurnRate      float64   `json:"burnRate"`
+	LongBurnRate  float64   `json:"longBurnRate"`
+	ShortBurnRate float64   `json:"shortBurnRate"`
+	Timestamp     time.Time `json:"ts"`
+
+	url     string
+	headers map[string]string
+}
+
+func (m *Module) routineEvaluate(done chan struct{}) {
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, a := range m.evaluate() {
+				if err := m.sendAlert(a); err != nil {
+					_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unable to send alert of slo (%s) to url (%s)", a.SLO, a.url), err, nil)
+				}
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// evaluate returns the alerts which started firing or got resolved since the last evaluation. An alert fires when
+// the burn rate over both its windows exceeds its threshold.
+func (m *Module) evaluate() []*alert {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := m.now()
+	minute := now.Unix() / 60
+
+	alerts := make([]*alert, 0)
+	for _, o := range m.objectives {
+		o.lock.Lock()
+		for i, c := range o.alerts {
+			long, short := o.burnRate(minute, c.LongWindow), o.burnRate(minute, c.ShortWindow)
+			firing := long >= c.BurnRate && short >= c.BurnRate
+			if firing == !o.firingSince[i].IsZero() {
+				continue
+			}
+
+			status := alertResolved
+			o.firingSince[i] = time.Time{}
+			if firing {
+				status = alertFiring
+				o.firingSince[i] = now
+			}
+			helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Burn rate alert of slo (%s) %s", o.config.ID, status), map[string]interface{}{"project": m.project, "longBurnRate": long, "shortBurnRate": short})
+
+			if o.config.WebhookURL == "" {
+				continue
+			}
+			alerts = append(alerts, &alert{
+				Project:       m.project,
+				SLO:           o.config.ID,
+				Status:        status,
+				Objective:     o.config.Objective,
+				LongWindow:    c.LongWindow,
+				ShortWindow:   c.ShortWindow,
+				BurnRate:      c.BurnRate,
+				LongBurnRate:  long,
+				ShortBurnRate: short,
+				Timestamp:     now,
+				url:           o.config.WebhookURL,
+				headers:       o.config.Headers,
+			})
+		}
+		o.lock.Unlock()
+	}
+	return alerts
+}
+
+func (m *Module) sendAlert(a *alert) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, a.url, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range a.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("alert webhook responded with status (%d)", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package slo
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+const (
+	// evaluateInterval is how often the alerts are evaluated
+	evaluateInterval = time.Minute
+	// maxWindow is the longest window in minutes the requests can be tracked for
+	maxWindow      = 7 * 24 * 60
+	webhookTimeout = 10 * time.Second
+)
+
+// defaultAlerts page on a fast burn which would use up 2% of a 30 day error budget in an hour and on a slower burn
+// which would use up 5% of it in six hours
+var defaultAlerts = []*config.SLOAlert{
+	{LongWindow: 60, ShortWindow: 5, BurnRate: 14.4},
+	{LongWindow: 360, ShortWindow: 30, BurnRate: 6},
+}
+
+// Module tracks the service level objectives of a project over rolling windows and fires the alert webhooks when the
+// error budget burns too fast. Every gateway tracks the requests it serves.
+type Module struct {
+	lock sync.RWMutex
+
+	project    string
+	objectives []*objective
+	done       chan struct{}
+
+	client *http.Client
+	// now is overridden in tests
+	now func() time.Time
+}
+
+type objective struct {
+	lock sync.Mutex
+
+	config *config.SLOConfig
+	alerts []*config.SLOAlert
+	// buckets is a ring of the requests counted per minute. It spans the longest window of the alerts.
+	buckets []bucket
+	// firingSince is the time each alert started firing. It is zero while the alert isn't firing
+	firingSince []time.Time
+}
+
+type bucket struct {
+	minute     int64
+	total, bad uint64
+}
+
+// New creates a new instance of the slo module
+func New(project string) *Module {
+	return &Module{project: project, client: &http.Client{Timeout: webhookTimeout}, now: time.Now}
+}
+
+// SetConfig sets the service level objectives of the project. The requests counted so far are retained for the
+// objectives whose windows haven't changed.
+func (m *Module) SetConfig(slos []*config.SLOConfig) error {
+	if err := ValidateConfig(slos); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	existing := make(map[string]*objective, len(m.objectives))
+	for _, o := range m.objectives {
+		existing[o.config.ID] = o
+	}
+
+	objectives := make([]*objective, len(slos))
+	for i, c := range slos {
+		alerts := c.Alerts
+		if len(alerts) == 0 {
+			alerts = defaultAlerts
+		}
+		o := &objective{config: c, alerts: alerts, buckets: make([]bucket, getLongestWindow(alerts)), firingSince: make([]time.Time, len(alerts))}
+		if prev, p := existing[c.ID]; p {
+			prev.lock.Lock()
+			if len(prev.buckets) == len(o.buckets) {
+				copy(o.buckets, prev.buckets)
+			}
+			if reflect.DeepEqual(prev.alerts, o.alerts) {
+				copy(o.firingSince, prev.firingSince)
+			}
+			prev.lock.Unlock()
+		}
+		objectives[i] = o
+	}
+	m.objectives = objectives
+
+	if len(objectives) == 0 {
+		m.stopEvaluation()
+		return nil
+	}
+	if m.done == nil {
+		m.done = make(chan struct{})
+		go m.routineEvaluate(m.done)
+	}
+	return nil
+}
+
+// CloseConfig stops tracking the service level objectives
+func (m *Module) CloseConfig() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.objectives = nil
+	m.stopEvaluation()
+	return nil
+}
+
+// stopEvaluation must be called with the lock held
+func (m *Module) stopEvaluation() {
+	if m.done == nil {
+		return
+	}
+	close(m.done)
+	m.done = nil
+}
+
+// Record counts a request served by a module of the project. The route is the path of the request following
+// /v1/api/{project}.
+func (m *Module) Record(module, route string, status int, duration time.Duration) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if len(m.objectives) == 0 {
+		return
+	}
+
+	minute := m.now().Unix() / 60
+	for _, o := range m.objectives {
+		if (o.config.Module != "" && o.config.Module != module) || !strings.HasPrefix(route, o.config.Route) {
+			continue
+		}
+		bad := status >= http.StatusInternalServerError || (o.config.Latency > 0 && duration > time.Duration(o.config.Latency)*time.Millisecond)
+		o.add(minute, bad)
+	}
+}
+
+// GetStatus returns the current state of the service level objectives of the project
+func (m *Module) GetStatus() []*model.SLOStatus {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	minute := m.now().Unix() / 60
+	arr := make([]*model.SLOStatus, len(m.objectives))
+	for i, o := range m.objectives {
+		o.lock.Lock()
+		window := len(o.buckets)
+		total, bad := o.count(minute, window)
+		status := &model.SLOStatus{
+			ID:                   o.config.ID,
+			Module:               o.config.Module,
+			Route:                o.config.Route,
+			Objective:            o.config.Objective,
+			Window:               window,
+			Requests:             total,
+			BadRequests:          bad,
+			ErrorBudgetRemaining: 1 - o.burnRate(minute, window),
+			Alerts:               make([]*model.SLOAlertStatus, len(o.alerts)),
+		}
+		for j, alert := range o.alerts {
+			s := &model.SLOAlertStatus{
+				LongWindow:    alert.LongWindow,
+				ShortWindow:   alert.ShortWindow,
+				BurnRate:      alert.BurnRate,
+				LongBurnRate:  o.burnRate(minute, alert.LongWindow),
+				ShortBurnRate: o.burnRate(minute, alert.ShortWindow),
+				Firing:        !o.firingSince[j].IsZero(),
+			}
+			if s.Firing {
+				since := o.firingSince[j]
+				s.FiringSince = &since
+			}
+			status.Alerts[j] = s
+		}
+		o.lock.Unlock()
+		arr[i] = status
+	}
+	return arr
+}
+
+// add counts a request in the bucket of the minute
+func (o *objective) add(minute int64, bad bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	b := &o.buckets[minute%int64(len(o.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if bad {
+		b.bad++
+	}
+}
+
+// count returns the requests counted in the window ending at the provided minute. It must be called with the lock of
+// the objective held.
+func (o *objective) count(minute int64, window int) (total, bad uint64) {
+	for _, b := range o.buckets {
+		if b.minute > minute-int64(window) && b.minute <= minute {
+			total += b.total
+			bad += b.bad
+		}
+	}
+	return total, bad
+}
+
+// burnRate returns the rate at which the error budget was used in the window. A burn rate of 1 uses up the error
+// budget exactly by the end of the window. It must be called with the lock of the objective held.
+func (o *objective) burnRate(minute int64, window int) float64 {
+	total, bad := o.count(minute, window)
+	if total == 0 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - o.config.Objective)
+}
+
+func getLongestWindow(alerts []*config.SLOAlert) int {
+	window := 0
+	for _, alert := range alerts {
+		if alert.LongWindow > window {
+			window = alert.LongWindow
+		}
+	}
+	return window
+}
+
+// ValidateConfig checks the service level objectives of a project
+func ValidateConfig(slos []*config.SLOConfig) error {
+	ids := make(map[string]bool, len(slos))
+	for _, c := range slos {
+		if c.ID == "" {
+			return fmt.Errorf("id of slo cannot be empty")
+		}
+		if ids[c.ID] {
+			return fmt.Errorf("slo (%s) has been defined more than once", c.ID)
+		}
+		ids[c.ID] = true
+
+		if c.Objective <= 0 || c.Objective >= 1 {
+			return fmt.Errorf("objective of slo (%s) must be between 0 and 1", c.ID)
+		}
+		if c.Latency < 0 {
+			return fmt.Errorf("latency threshold of slo (%s) cannot be negative", c.ID)
+		}
+		if c.Route != "" && !strings.HasPrefix(c.Route, "/") {
+			return fmt.Errorf("route of slo (%s) must begin with /", c.ID)
+		}
+		for _, alert := range c.Alerts {
+			if alert.ShortWindow <= 0 || alert.LongWindow <= alert.ShortWindow || alert.LongWindow > maxWindow {
+				return fmt.Errorf("windows of the alerts of slo (%s) must satisfy 0 < short window < long window <= %d minutes", c.ID, maxWindow)
+			}
+			if alert.BurnRate <= 0 {
+				return fmt.Errorf("burn rate of the alerts of slo (%s) must be positive", c.ID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package slo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_ValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		slos    []*config.SLOConfig
+		wantErr bool
+	}{
+		{name: "valid", slos: []*config.SLOConfig{{ID: "a", Objective: 0.99, Route: "/crud"}, {ID: "b", Objective: 0.9, Alerts: []*config.SLOAlert{{LongWindow: 10, ShortWindow: 2, BurnRate: 2}}}}},
+		{name: "duplicate id", slos: []*config.SLOConfig{{ID: "a", Objective: 0.99}, {ID: "a", Objective: 0.9}}, wantErr: true},
+		{name: "objective of 1", slos: []*config.SLOConfig{{ID: "a", Objective: 1}}, wantErr: true},
+		{name: "relative route", slos: []*config.SLOConfig{{ID: "a", Objective: 0.99, Route: "crud"}}, wantErr: true},
+		{name: "short window longer than long window", slos: []*config.SLOConfig{{ID: "a", Objective: 0.99, Alerts: []*config.SLOAlert{{LongWindow: 5, ShortWindow: 10, BurnRate: 2}}}}, wantErr: true},
+		{name: "window longer than max", slos: []*config.SLOConfig{{ID: "a", Objective: 0.99, Alerts: []*config.SLOAlert{{LongWindow: maxWindow + 1, ShortWindow: 10, BurnRate: 2}}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateConfig(tt.slos); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModule_evaluate(t *testing.T) {
+	alerts := make(chan *alert, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := new(alert)
+		_ = json.NewDecoder(r.Body).Decode(a)
+		alerts <- a
+	}))
+	defer server.Close()
+
+	now := time.Unix(1600000000, 0)
+	m := New("project")
+	m.now = func() time.Time { return now }
+	c := &config.SLOConfig{
+		ID:         "crud",
+		Module:     "crud",
+		Route:      "/crud/db",
+		Objective:  0.9,
+		Latency:    100,
+		Alerts:     []*config.SLOAlert{{LongWindow: 10, ShortWindow: 2, BurnRate: 2}},
+		WebhookURL: server.URL,
+	}
+	if err := m.SetConfig([]*config.SLOConfig{c}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	defer func() { _ = m.CloseConfig() }()
+
+	// A third of the requests are bad, which burns the error budget at 10/3 of the sustainable rate
+	for i := 0; i < 10; i++ {
+		m.Record("crud", "/crud/db/read", http.StatusOK, 10*time.Millisecond)
+	}
+	for i := 0; i < 2; i++ {
+		m.Record("crud", "/crud/db/read", http.StatusInternalServerError, 10*time.Millisecond)
+	}
+	m.Record("crud", "/crud/db/read", http.StatusOK, time.Second)
+	m.Record("crud", "/crud/db/read", http.StatusOK, time.Second)
+	m.Record("crud", "/crud/db/read", http.StatusOK, time.Second)
+	// Requests outside the objective aren't counted
+	m.Record("functions", "/crud/db/read", http.StatusInternalServerError, 0)
+	m.Record("crud", "/crud/other/read", http.StatusInternalServerError, 0)
+
+	// The config is applied once more when any part of the project config changes
+	if err := m.SetConfig([]*config.SLOConfig{c}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	for _, a := range m.evaluate() {
+		if err := m.sendAlert(a); err != nil {
+			t.Fatalf("sendAlert() error = %v", err)
+		}
+	}
+	a := <-alerts
+	if a.Status != alertFiring || a.SLO != "crud" || a.Project != "project" || a.LongBurnRate < 3.33 || a.LongBurnRate > 3.34 {
+		t.Errorf("evaluate() fired alert %v, want firing with burn rate 10/3", a)
+	}
+	status := m.GetStatus()
+	if len(status) != 1 || status[0].Requests != 15 || status[0].BadRequests != 5 || !status[0].Alerts[0].Firing {
+		t.Errorf("GetStatus() = %v, want the alert firing", status[0])
+	}
+
+	// The alert keeps firing till the short window recovers
+	if got := m.evaluate(); len(got) != 0 {
+		t.Errorf("evaluate() returned %v, want no change", got)
+	}
+	now = now.Add(3 * time.Minute)
+	m.Record("crud", "/crud/db/read", http.StatusOK, 0)
+	got := m.evaluate()
+	if len(got) != 1 || got[0].Status != alertResolved {
+		t.Errorf("evaluate() returned %v, want the alert resolved", got)
+	}
+}
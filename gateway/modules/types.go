@@ -23,4 +23,25 @@ type RealtimeInterface interface {
 type GraphQLInterface interface {
 	GetDBAlias(ctx context.Context, field *ast.Field, token string, store utils.M) (string, error)
 	ExecGraphQLQuery(ctx context.Context, req *model.GraphQLRequest, token string, cb model.GraphQLCallback)
+	GetSDL() string
+	IsIntrospectionDisabled() bool
+}
+
+// FunctionsInterface is used to mock the functions module
+type FunctionsInterface interface {
+	GetEndpointContextTimeout(ctx context.Context, projectID, service, function string) (int, error)
+	CallStreamWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest, onChunk model.FunctionsStreamCallback) (int, interface{}, error)
+}
+
+// FeatureFlagsInterface is used to mock the feature flags module
+type FeatureFlagsInterface interface {
+	GetFlags(ctx context.Context, token string) (map[string]bool, error)
+	Subscribe(ctx context.Context, clientID, token string, send func(values map[string]bool)) (map[string]bool, error)
+	Unsubscribe(clientID string)
+}
+
+// AuthFunctionsInterface is used to mock the auth module while calling functions
+type AuthFunctionsInterface interface {
+	IsFuncCallAuthorised(ctx context.Context, project, service, function, token string, params interface{}) (*model.PostProcess, model.RequestParams, error)
+	GetAESKey() []byte
 }
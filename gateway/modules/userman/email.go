@@ -0,0 +1,194 @@
+package userman
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// The fields of the users collection holding the state of the email flows
+const (
+	fieldVerified       = "verified"
+	fieldFailedAttempts = "failed_attempts"
+	fieldLockedUntil    = "locked_until"
+)
+
+const (
+	// defaultVerificationTTL is the time in minutes a verification link is valid for
+	defaultVerificationTTL = 24 * 60
+
+	// defaultResetTTL is the time in minutes a password reset link is valid for
+	defaultResetTTL = 60
+
+	// defaultLockoutDuration is the time in minutes an account stays locked
+	defaultLockoutDuration = 15
+)
+
+func validateEmailConfig(c *config.EmailAuthConfig) error {
+	if c.RequireVerification && c.VerificationURL == "" {
+		return errors.New("verification url must be provided to require the verification of emails")
+	}
+	for _, link := range []string{c.VerificationURL, c.ResetURL} {
+		if link == "" {
+			continue
+		}
+		if c.Mail == nil {
+			return errors.New("mail config must be provided to send verification or password reset mails")
+		}
+		if u, err := url.Parse(link); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid link (%s) provided in email sign in config", link)
+		}
+	}
+	if c.Mail != nil {
+		if err := validateMailConfig(c.Mail); err != nil {
+			return err
+		}
+	}
+	if c.VerificationTTL < 0 || c.ResetTTL < 0 {
+		return errors.New("ttl of verification and password reset links cannot be negative")
+	}
+	if c.PasswordPolicy != nil && c.PasswordPolicy.MinLength < 0 {
+		return errors.New("minimum length of password policy cannot be negative")
+	}
+	if c.TwoFactor != nil && c.TwoFactor.BackupCodes < 0 {
+		return errors.New("number of backup codes of two factor authentication cannot be negative")
+	}
+	if c.Lockout != nil && (c.Lockout.MaxAttempts <= 0 || c.Lockout.Duration < 0) {
+		return errors.New("max attempts of account lockout must be greater than zero and its duration cannot be negative")
+	}
+	return nil
+}
+
+func validateMailConfig(c *config.MailConfig) error {
+	if c.From == "" {
+		return errors.New("from address of mail config not provided")
+	}
+	switch c.Provider {
+	case config.MailProviderSMTP:
+		if c.Host == "" || c.Port <= 0 {
+			return errors.New("host and port of the smtp server must be provided")
+		}
+	case config.MailProviderSendGrid:
+		if c.APIKey == "" {
+			return errors.New("api key of sendgrid not provided")
+		}
+	default:
+		return fmt.Errorf("invalid mail provider (%s) provided. It must be either %s or %s", c.Provider, config.MailProviderSMTP, config.MailProviderSendGrid)
+	}
+	return nil
+}
+
+// checkPassword checks that the password satisfies the policy. A nil policy allows any password.
+func checkPassword(policy *config.PasswordPolicy, password string) error {
+	if policy == nil {
+		return nil
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	missing := make([]string, 0)
+	if policy.MinLength > 0 && len([]rune(password)) < policy.MinLength {
+		missing = append(missing, fmt.Sprintf("at least %d characters", policy.MinLength))
+	}
+	if policy.RequireUpper && !upper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if policy.RequireLower && !lower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if policy.RequireDigit && !digit {
+		missing = append(missing, "a digit")
+	}
+	if policy.RequireSymbol && !symbol {
+		missing = append(missing, "a symbol")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("password must contain %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// getPasswordPolicy returns the password policy of the email sign in method if any
+func getPasswordPolicy(c *config.EmailAuthConfig) *config.PasswordPolicy {
+	if c == nil {
+		return nil
+	}
+	return c.PasswordPolicy
+}
+
+// isLocked checks if the account of the user is locked out of signing in
+func isLocked(user map[string]interface{}, now time.Time) bool {
+	var lockedUntil time.Time
+	switch v := user[fieldLockedUntil].(type) {
+	case time.Time:
+		lockedUntil = v
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return false
+		}
+		lockedUntil = t
+	default:
+		return false
+	}
+	return now.Before(lockedUntil)
+}
+
+// getFailedAttempts returns the number of consecutive failed sign in attempts of the user
+func getFailedAttempts(user map[string]interface{}) int64 {
+	return getInt(user, fieldFailedAttempts)
+}
+
+// getInt returns an integer field of the user. The type of the value depends on the database.
+func getInt(user map[string]interface{}, field string) int64 {
+	switch v := user[field].(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// getLockoutUpdate returns the update locking the account of a user for the lockout duration
+func getLockoutUpdate(lockout *config.AccountLockout, now time.Time) map[string]interface{} {
+	duration := lockout.Duration
+	if duration == 0 {
+		duration = defaultLockoutDuration
+	}
+	lockedUntil := now.Add(time.Duration(duration) * time.Minute).UTC().Format(time.RFC3339)
+	return map[string]interface{}{"$set": map[string]interface{}{fieldFailedAttempts: 0, fieldLockedUntil: lockedUntil}}
+}
+
+// addTokenToLink adds the token to the link as the token query parameter
+func addTokenToLink(link, token string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
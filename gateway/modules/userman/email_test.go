@@ -0,0 +1,425 @@
+package userman
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// mockCrud keeps the users and groups in memory. Only equality and $in where clauses, sorting on a single field and
+// $set / $inc updates are supported.
+type mockCrud struct {
+	users  []map[string]interface{}
+	groups []map[string]interface{}
+
+	// beforeUpdate runs before an update is applied to simulate a concurrent request
+	beforeUpdate func()
+}
+
+func (c *mockCrud) GetDBType(dbAlias string) (string, error) {
+	return string(model.Postgres), nil
+}
+
+func (c *mockCrud) Read(ctx context.Context, dbAlias, col string, req *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error) {
+	switch req.Operation {
+	case utils.All:
+		docs := []interface{}{}
+		for _, doc := range *c.col(col) {
+			if matches(doc, req.Find) {
+				docs = append(docs, copyDoc(doc))
+			}
+		}
+		if req.Options == nil {
+			return docs, nil, nil
+		}
+		if len(req.Options.Sort) > 0 {
+			field := req.Options.Sort[0]
+			sort.SliceStable(docs, func(i, j int) bool {
+				return fmt.Sprintf("%v", docs[i].(map[string]interface{})[field]) < fmt.Sprintf("%v", docs[j].(map[string]interface{})[field])
+			})
+		}
+		if skip := req.Options.Skip; skip != nil {
+			docs = docs[*skip:]
+		}
+		if limit := req.Options.Limit; limit != nil && *limit < int64(len(docs)) {
+			docs = docs[:*limit]
+		}
+		return docs, nil, nil
+	case utils.Count:
+		var count int64
+		for _, doc := range *c.col(col) {
+			if matches(doc, req.Find) {
+				count++
+			}
+		}
+		return count, nil, nil
+	}
+	if doc := c.find(col, req.Find); doc != nil {
+		return copyDoc(doc), nil, nil
+	}
+	return nil, nil, errors.New("no document found")
+}
+
+func (c *mockCrud) Create(ctx context.Context, dbAlias, col string, req *model.CreateRequest, params model.RequestParams) error {
+	docs := c.col(col)
+	*docs = append(*docs, copyDoc(req.Document.(map[string]interface{})))
+	return nil
+}
+
+func (c *mockCrud) Delete(ctx context.Context, dbAlias, col string, req *model.DeleteRequest, params model.RequestParams) error {
+	docs := c.col(col)
+	for i, doc := range *docs {
+		if matches(doc, req.Find) {
+			*docs = append((*docs)[:i], (*docs)[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("no document found")
+}
+
+func (c *mockCrud) Update(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) error {
+	n, err := c.UpdateCount(ctx, dbAlias, col, req, params)
+	if err == nil && n == 0 {
+		return errors.New("no document found")
+	}
+	return err
+}
+
+func (c *mockCrud) UpdateCount(ctx context.Context, dbAlias, col string, req *model.UpdateRequest, params model.RequestParams) (int64, error) {
+	if c.beforeUpdate != nil {
+		c.beforeUpdate()
+	}
+	user := c.find(col, req.Find)
+	if user == nil {
+		return 0, nil
+	}
+	if set, ok := req.Update["$set"].(map[string]interface{}); ok {
+		for k, v := range set {
+			user[k] = v
+		}
+	}
+	if inc, ok := req.Update["$inc"].(map[string]interface{}); ok {
+		for k, v := range inc {
+			user[k] = getFailedAttempts(map[string]interface{}{fieldFailedAttempts: user[k]}) + int64(v.(int))
+		}
+	}
+	return 1, nil
+}
+
+func (c *mockCrud) find(col string, find map[string]interface{}) map[string]interface{} {
+	for _, doc := range *c.col(col) {
+		if matches(doc, find) {
+			return doc
+		}
+	}
+	return nil
+}
+
+func (c *mockCrud) col(col string) *[]map[string]interface{} {
+	if col == "users" {
+		return &c.users
+	}
+	return &c.groups
+}
+
+func matches(doc, find map[string]interface{}) bool {
+	for k, v := range find {
+		if cond, ok := v.(map[string]interface{}); ok {
+			if !isIn(doc[k], cond["$in"]) {
+				return false
+			}
+			continue
+		}
+		if doc[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func isIn(value, values interface{}) bool {
+	arr, _ := values.([]interface{})
+	for _, v := range arr {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func copyDoc(doc map[string]interface{}) map[string]interface{} {
+	copied := map[string]interface{}{}
+	for k, v := range doc {
+		copied[k] = v
+	}
+	return copied
+}
+
+type mockAuth struct {
+	model.AuthUserInterface
+}
+
+// CreateToken returns the claims marshalled as the token so that ParseToken can return them
+func (mockAuth) CreateToken(ctx context.Context, tokenClaims model.TokenClaims) (string, error) {
+	data, err := json.Marshal(tokenClaims)
+	return string(data), err
+}
+
+func (mockAuth) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	claims := map[string]interface{}{}
+	err := json.Unmarshal([]byte(token), &claims)
+	return claims, err
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+func newTestModule(t *testing.T, email *config.EmailAuthConfig) (*Module, *mockCrud, *[]sentMail) {
+	crud := new(mockCrud)
+	m := Init(crud, mockAuth{})
+	if err := m.SetConfig(config.Auths{"email": {ID: "email", Enabled: true, Email: email}}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if err := m.SetProjectAESKey("bWRPYmxJYkxCb2hiVk12NGxtQWNBbldraUJkcUNEYWQ="); err != nil {
+		t.Fatalf("SetProjectAESKey() error = %v", err)
+	}
+
+	mails := new([]sentMail)
+	m.sendMail = func(ctx context.Context, c *config.MailConfig, to, subject, body string) error {
+		*mails = append(*mails, sentMail{to: to, subject: subject, body: body})
+		return nil
+	}
+	return m, crud, mails
+}
+
+// getMailedToken returns the token of the link in the mail
+func getMailedToken(t *testing.T, mail sentMail) string {
+	for _, word := range strings.Fields(mail.body) {
+		if u, err := url.Parse(word); err == nil && u.Query().Get("token") != "" {
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatalf("no link found in mail %q", mail.body)
+	return ""
+}
+
+var testMailConfig = &config.MailConfig{Provider: config.MailProviderSMTP, From: "noreply@example.com", Host: "localhost", Port: 25}
+
+func Test_checkPassword(t *testing.T) {
+	policy := &config.PasswordPolicy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+	tests := []struct {
+		name     string
+		policy   *config.PasswordPolicy
+		password string
+		wantErr  bool
+	}{
+		{name: "no policy", password: "a"},
+		{name: "satisfies policy", policy: policy, password: "Secr3t-pass"},
+		{name: "too short", policy: policy, password: "S3c-r", wantErr: true},
+		{name: "no uppercase letter", policy: policy, password: "secr3t-pass", wantErr: true},
+		{name: "no digit", policy: policy, password: "Secret-pass", wantErr: true},
+		{name: "no symbol", policy: policy, password: "Secr3tpass", wantErr: true},
+		{name: "length counted in characters", policy: &config.PasswordPolicy{MinLength: 4}, password: "äöü", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPassword(tt.policy, tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("checkPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateEmailConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *config.EmailAuthConfig
+		wantErr bool
+	}{
+		{name: "policy and lockout only", config: &config.EmailAuthConfig{PasswordPolicy: &config.PasswordPolicy{MinLength: 8}, Lockout: &config.AccountLockout{MaxAttempts: 5}}},
+		{name: "verification and reset", config: &config.EmailAuthConfig{Mail: testMailConfig, VerificationURL: "https://example.com/verify", RequireVerification: true, ResetURL: "https://example.com/reset"}},
+		{name: "verification required without link", config: &config.EmailAuthConfig{Mail: testMailConfig, RequireVerification: true}, wantErr: true},
+		{name: "link without mail config", config: &config.EmailAuthConfig{ResetURL: "https://example.com/reset"}, wantErr: true},
+		{name: "relative link", config: &config.EmailAuthConfig{Mail: testMailConfig, ResetURL: "/reset"}, wantErr: true},
+		{name: "invalid provider", config: &config.EmailAuthConfig{Mail: &config.MailConfig{Provider: "ses", From: "noreply@example.com"}}, wantErr: true},
+		{name: "sendgrid without api key", config: &config.EmailAuthConfig{Mail: &config.MailConfig{Provider: config.MailProviderSendGrid, From: "noreply@example.com"}}, wantErr: true},
+		{name: "lockout without attempts", config: &config.EmailAuthConfig{Lockout: &config.AccountLockout{}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateEmailConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("validateEmailConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parseEmailToken(t *testing.T) {
+	key := []byte("key")
+	now := time.Now()
+	valid, _ := createEmailToken(key, &emailToken{Purpose: tokenPurposeReset, ID: "1", Email: "a@b.com", Exp: now.Add(time.Hour).Unix()})
+	expired, _ := createEmailToken(key, &emailToken{Purpose: tokenPurposeReset, ID: "1", Email: "a@b.com", Exp: now.Add(-time.Hour).Unix()})
+
+	tests := []struct {
+		name    string
+		key     []byte
+		token   string
+		purpose string
+		wantErr bool
+	}{
+		{name: "valid token", key: key, token: valid, purpose: tokenPurposeReset},
+		{name: "other purpose", key: key, token: valid, purpose: tokenPurposeVerify, wantErr: true},
+		{name: "other key", key: []byte("other"), token: valid, purpose: tokenPurposeReset, wantErr: true},
+		{name: "expired token", key: key, token: expired, purpose: tokenPurposeReset, wantErr: true},
+		{name: "tampered token", key: key, token: "e30" + valid[3:], purpose: tokenPurposeReset, wantErr: true},
+		{name: "malformed token", key: key, token: "abc", purpose: tokenPurposeReset, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEmailToken(tt.key, tt.token, tt.purpose, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseEmailToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (got.ID != "1" || got.Email != "a@b.com") {
+				t.Errorf("parseEmailToken() = %v", got)
+			}
+		})
+	}
+}
+
+func Test_getLockoutUpdate(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	lockout := &config.AccountLockout{MaxAttempts: 3}
+
+	got := getLockoutUpdate(lockout, now)
+	want := map[string]interface{}{"$set": map[string]interface{}{fieldFailedAttempts: 0, fieldLockedUntil: "2020-01-01T00:15:00Z"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getLockoutUpdate() = %v, want %v", got, want)
+	}
+
+	locked := map[string]interface{}{fieldLockedUntil: "2020-01-01T00:15:00Z"}
+	if !isLocked(locked, now) || isLocked(locked, now.Add(time.Hour)) {
+		t.Errorf("isLocked() doesn't respect the lockout duration")
+	}
+}
+
+func TestModule_EmailSignIn_Lockout(t *testing.T) {
+	m, crud, _ := newTestModule(t, &config.EmailAuthConfig{Lockout: &config.AccountLockout{MaxAttempts: 2}})
+	if status, _, err := m.EmailSignUp(context.Background(), "db", "project", "a@b.com", "a", "secret", "user"); err != nil {
+		t.Fatalf("EmailSignUp() status = %d error = %v", status, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if status, _, _ := m.EmailSignIn(context.Background(), "db", "project", "a@b.com", "wrong"); status != 401 {
+			t.Fatalf("EmailSignIn() with wrong password status = %d, want 401", status)
+		}
+	}
+
+	// The account is locked even for the right password
+	if status, _, _ := m.EmailSignIn(context.Background(), "db", "project", "a@b.com", "secret"); status != 403 {
+		t.Fatalf("EmailSignIn() of locked account status = %d, want 403", status)
+	}
+
+	crud.users[0][fieldLockedUntil] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	if status, _, err := m.EmailSignIn(context.Background(), "db", "project", "a@b.com", "secret"); err != nil {
+		t.Fatalf("EmailSignIn() after lockout status = %d error = %v", status, err)
+	}
+
+	// An attempt made in parallel counts towards the lockout even though it wasn't read
+	crud.beforeUpdate = func() {
+		crud.users[0][fieldFailedAttempts] = int64(1)
+		crud.beforeUpdate = nil
+	}
+	if status, _, _ := m.EmailSignIn(context.Background(), "db", "project", "a@b.com", "wrong"); status != 401 {
+		t.Fatalf("EmailSignIn() with wrong password status = %d, want 401", status)
+	}
+	if status, _, _ := m.EmailSignIn(context.Background(), "db", "project", "a@b.com", "secret"); status != 403 {
+		t.Fatalf("EmailSignIn() after parallel failed attempts status = %d, want 403", status)
+	}
+}
+
+func TestModule_EmailVerification(t *testing.T) {
+	m, crud, mails := newTestModule(t, &config.EmailAuthConfig{Mail: testMailConfig, VerificationURL: "https://example.com/verify?app=1", RequireVerification: true})
+	ctx := context.Background()
+
+	status, result, err := m.EmailSignUp(ctx, "db", "project", "a@b.com", "a", "secret", "user")
+	if err != nil {
+		t.Fatalf("EmailSignUp() status = %d error = %v", status, err)
+	}
+	if _, ok := result["token"]; ok || len(*mails) != 1 || (*mails)[0].to != "a@b.com" {
+		t.Fatalf("EmailSignUp() = %v with mails %v, want a verification mail and no token", result, *mails)
+	}
+	if status, _, _ := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret"); status != 403 {
+		t.Fatalf("EmailSignIn() of unverified user status = %d, want 403", status)
+	}
+
+	// No mail is sent for unknown users but the request succeeds
+	if status, _, err := m.SendVerificationEmail(ctx, "db", "project", "unknown@b.com"); err != nil || status != 200 || len(*mails) != 1 {
+		t.Fatalf("SendVerificationEmail() for unknown user status = %d error = %v mails = %d", status, err, len(*mails))
+	}
+
+	token := getMailedToken(t, (*mails)[0])
+	if status, _, err := m.VerifyEmail(ctx, "db", "project", token); err != nil {
+		t.Fatalf("VerifyEmail() status = %d error = %v", status, err)
+	}
+	if crud.users[0][fieldVerified] != true {
+		t.Fatalf("VerifyEmail() didn't mark the user as verified")
+	}
+	if _, result, err := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret"); err != nil || result["token"] == nil {
+		t.Fatalf("EmailSignIn() of verified user = %v error = %v", result, err)
+	}
+}
+
+func TestModule_ResetPassword(t *testing.T) {
+	m, _, mails := newTestModule(t, &config.EmailAuthConfig{Mail: testMailConfig, ResetURL: "https://example.com/reset", PasswordPolicy: &config.PasswordPolicy{MinLength: 8}})
+	ctx := context.Background()
+
+	if status, _, _ := m.EmailSignUp(ctx, "db", "project", "a@b.com", "a", "short", "user"); status != 400 {
+		t.Fatalf("EmailSignUp() with weak password status = %d, want 400", status)
+	}
+	if status, _, err := m.EmailSignUp(ctx, "db", "project", "a@b.com", "a", "long-secret", "user"); err != nil {
+		t.Fatalf("EmailSignUp() status = %d error = %v", status, err)
+	}
+	if status, _, err := m.SendPasswordReset(ctx, "db", "project", "a@b.com"); err != nil || len(*mails) != 1 {
+		t.Fatalf("SendPasswordReset() status = %d error = %v mails = %d", status, err, len(*mails))
+	}
+	token := getMailedToken(t, (*mails)[0])
+
+	// Failing to send the mail doesn't reveal whether the user exists
+	m.sendMail = func(ctx context.Context, c *config.MailConfig, to, subject, body string) error {
+		return errors.New("mail server unavailable")
+	}
+	for _, email := range []string{"a@b.com", "unknown@b.com"} {
+		if status, _, err := m.SendPasswordReset(ctx, "db", "project", email); status != 200 || err != nil {
+			t.Fatalf("SendPasswordReset() of (%s) with failing mail server status = %d error = %v, want 200", email, status, err)
+		}
+	}
+
+	if status, _, _ := m.ResetPassword(ctx, "db", "project", token, "short"); status != 400 {
+		t.Fatalf("ResetPassword() with weak password status = %d, want 400", status)
+	}
+	if status, _, err := m.ResetPassword(ctx, "db", "project", token, "new-long-secret"); err != nil {
+		t.Fatalf("ResetPassword() status = %d error = %v", status, err)
+	}
+
+	// The link can't be used again once the password has changed
+	if status, _, _ := m.ResetPassword(ctx, "db", "project", token, "another-secret"); status != 400 {
+		t.Fatalf("ResetPassword() with used token status = %d, want 400", status)
+	}
+	if _, _, err := m.EmailSignIn(ctx, "db", "project", "a@b.com", "new-long-secret"); err != nil {
+		t.Fatalf("EmailSignIn() with new password error = %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package userman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// SendVerificationEmail mails a verification link to the user having the email. The request succeeds even if there
+// is no such user or the user is already verified, so that it can't be used to find out the emails signed up.
+func (m *Module) SendVerificationEmail(ctx context.Context, dbAlias, project, email string) (int, map[string]interface{}, error) {
+	c := m.getEmailConfig()
+	if c == nil || c.VerificationURL == "" {
+		return http.StatusNotFound, nil, errors.New("Email verification is not enabled")
+	}
+
+	user, err := m.readUser(ctx, dbAlias, project, map[string]interface{}{"email": email})
+	if err != nil || user[fieldVerified] == true {
+		return http.StatusOK, map[string]interface{}{}, nil
+	}
+
+	if err := m.mailVerificationLink(ctx, c, dbAlias, user); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send verification mail", err, nil)
+	}
+	return http.StatusOK, map[string]interface{}{}, nil
+}
+
+// VerifyEmail marks the email of the user the verification token was mailed to as verified
+func (m *Module) VerifyEmail(ctx context.Context, dbAlias, project, token string) (int, map[string]interface{}, error) {
+	c := m.getEmailConfig()
+	if c == nil || c.VerificationURL == "" {
+		return http.StatusNotFound, nil, errors.New("Email verification is not enabled")
+	}
+
+	m.RLock()
+	aesKey := m.aesKey
+	m.RUnlock()
+
+	t, err := parseEmailToken(aesKey, token, tokenPurposeVerify, time.Now())
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	// The email of the user might have changed since the token was mailed
+	user, err := m.readUser(ctx, dbAlias, project, map[string]interface{}{idField: t.ID})
+	if err != nil || user["email"] != t.Email {
+		return http.StatusBadRequest, nil, errors.New("Verification link is no longer valid")
+	}
+
+	update := map[string]interface{}{"$set": map[string]interface{}{fieldVerified: true}}
+	if err := m.updateUser(ctx, dbAlias, project, map[string]interface{}{idField: t.ID}, update); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to mark email as verified", err, nil)
+	}
+	return http.StatusOK, map[string]interface{}{"email": t.Email, "verified": true}, nil
+}
+
+// SendPasswordReset mails a password reset link to the user having the email. The request succeeds even if there
+// is no such user, so that it can't be used to find out the emails signed up.
+func (m *Module) SendPasswordReset(ctx context.Context, dbAlias, project, email string) (int, map[string]interface{}, error) {
+	c := m.getEmailConfig()
+	if c == nil || c.ResetURL == "" {
+		return http.StatusNotFound, nil, errors.New("Password reset is not enabled")
+	}
+
+	user, err := m.readUser(ctx, dbAlias, project, map[string]interface{}{"email": email})
+	if err != nil {
+		return http.StatusOK, map[string]interface{}{}, nil
+	}
+
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send password reset mail", err, nil)
+		return http.StatusOK, map[string]interface{}{}, nil
+	}
+	hash, _ := user["pass"].(string)
+
+	ttl := c.ResetTTL
+	if ttl == 0 {
+		ttl = defaultResetTTL
+	}
+	t := &emailToken{Purpose: tokenPurposeReset, ID: fmt.Sprintf("%v", user[idField]), Email: email, Exp: time.Now().Add(time.Duration(ttl) * time.Minute).Unix(), Fingerprint: passwordFingerprint(hash)}
+	body := "A password reset was requested for your account. Open the link below to set a new password. The link expires in %d minutes.\n\nIf you didn't request it, you can ignore this mail.\n\n%s\n"
+	// Failing to send the mail is only logged, since an error would reveal that the user exists
+	if err := m.mailTokenLink(ctx, c, c.ResetURL, t, "Reset your password", body, ttl); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send password reset mail", err, nil)
+	}
+	return http.StatusOK, map[string]interface{}{}, nil
+}
+
+// ResetPassword sets the password of the user the reset token was mailed to. The token can only be used once since
+// it stops matching the user as soon as the password changes. Resetting the password also unlocks the account and
+// verifies the email, since the user has proven to own it.
+func (m *Module) ResetPassword(ctx context.Context, dbAlias, project, token, password string) (int, map[string]interface{}, error) {
+	c := m.getEmailConfig()
+	if c == nil || c.ResetURL == "" {
+		return http.StatusNotFound, nil, errors.New("Password reset is not enabled")
+	}
+
+	m.RLock()
+	aesKey := m.aesKey
+	m.RUnlock()
+
+	t, err := parseEmailToken(aesKey, token, tokenPurposeReset, time.Now())
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if err := checkPassword(c.PasswordPolicy, password); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	user, err := m.readUser(ctx, dbAlias, project, map[string]interface{}{idField: t.ID})
+	if err != nil {
+		return http.StatusBadRequest, nil, errors.New("Password reset link is no longer valid")
+	}
+	if hash, _ := user["pass"].(string); user["email"] != t.Email || passwordFingerprint(hash) != t.Fingerprint {
+		return http.StatusBadRequest, nil, errors.New("Password reset link is no longer valid")
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to hash password", err, nil)
+	}
+	set := map[string]interface{}{"pass": hash}
+	if c.Lockout != nil {
+		set[fieldFailedAttempts] = 0
+		set[fieldLockedUntil] = nil
+	}
+	if c.VerificationURL != "" {
+		set[fieldVerified] = true
+	}
+	if err := m.updateUser(ctx, dbAlias, project, map[string]interface{}{idField: t.ID}, map[string]interface{}{"$set": set}); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to reset password", err, nil)
+	}
+	return http.StatusOK, map[string]interface{}{}, nil
+}
+
+// mailVerificationLink mails a verification link to the user
+func (m *Module) mailVerificationLink(ctx context.Context, c *config.EmailAuthConfig, dbAlias string, user map[string]interface{}) error {
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		return err
+	}
+
+	ttl := c.VerificationTTL
+	if ttl == 0 {
+		ttl = defaultVerificationTTL
+	}
+	t := &emailToken{Purpose: tokenPurposeVerify, ID: fmt.Sprintf("%v", user[idField]), Email: fmt.Sprintf("%v", user["email"]), Exp: time.Now().Add(time.Duration(ttl) * time.Minute).Unix()}
+	body := "Please verify your email by opening the link below. The link expires in %d minutes.\n\n%s\n"
+	return m.mailTokenLink(ctx, c, c.VerificationURL, t, "Verify your email", body, ttl)
+}
+
+// mailTokenLink mails the link having the token to the email of the token. The body is formatted with the ttl of the
+// link and the link.
+func (m *Module) mailTokenLink(ctx context.Context, c *config.EmailAuthConfig, link string, t *emailToken, subject, body string, ttl int) error {
+	m.RLock()
+	aesKey := m.aesKey
+	m.RUnlock()
+
+	token, err := createEmailToken(aesKey, t)
+	if err != nil {
+		return err
+	}
+	link, err = addTokenToLink(link, token)
+	if err != nil {
+		return err
+	}
+	return m.sendMail(ctx, c.Mail, t.Email, subject, fmt.Sprintf(body, ttl, link))
+}
+
+// readUser reads the user matching the find clause bypassing the security rules
+func (m *Module) readUser(ctx context.Context, dbAlias, project string, find map[string]interface{}) (map[string]interface{}, error) {
+	attr := map[string]string{"project": project, "db": dbAlias, "col": "users"}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	res, _, err := m.crud.Read(ctx, dbAlias, "users", &model.ReadRequest{Find: find, Operation: utils.One}, reqParams)
+	if err != nil {
+		return nil, err
+	}
+	user, ok := res.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("User not found")
+	}
+	return user, nil
+}
+
+// updateUser updates the user matching the find clause bypassing the security rules
+func (m *Module) updateUser(ctx context.Context, dbAlias, project string, find, update map[string]interface{}) error {
+	attr := map[string]string{"project": project, "db": dbAlias, "col": "users"}
+	reqParams := model.RequestParams{Resource: "db-update", Op: "access", Attributes: attr}
+	return m.crud.Update(ctx, dbAlias, "users", &model.UpdateRequest{Find: find, Operation: utils.One, Update: update}, reqParams)
+}
+
+// recordFailedSignIn increments the failed sign in attempts of the user matching the find clause. The account gets
+// locked once the attempts reach the maximum. The count is incremented atomically and the lock is based on the
+// count read back, so that parallel attempts can't go past the maximum.
+func (m *Module) recordFailedSignIn(ctx context.Context, lockout *config.AccountLockout, dbAlias, project string, find map[string]interface{}) {
+	if err := m.updateUser(ctx, dbAlias, project, find, map[string]interface{}{"$inc": map[string]interface{}{fieldFailedAttempts: 1}}); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to record failed sign in attempt", err, nil)
+		return
+	}
+
+	user, err := m.readUser(ctx, dbAlias, project, find)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read failed sign in attempts", err, nil)
+		return
+	}
+	if getFailedAttempts(user) < int64(lockout.MaxAttempts) {
+		return
+	}
+	if err := m.updateUser(ctx, dbAlias, project, find, getLockoutUpdate(lockout, time.Now())); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to lock account", err, nil)
+	}
+}
+
+// updateUserCount updates the user matching the find clause like updateUser. It returns the number of users updated,
+// which is zero if the user no longer matches the find clause.
+func (m *Module) updateUserCount(ctx context.Context, dbAlias, project string, find, update map[string]interface{}) (int64, error) {
+	attr := map[string]string{"project": project, "db": dbAlias, "col": "users"}
+	reqParams := model.RequestParams{Resource: "db-update", Op: "access", Attributes: attr}
+	return m.crud.UpdateCount(ctx, dbAlias, "users", &model.UpdateRequest{Find: find, Operation: utils.One, Update: update}, reqParams)
+}
+
+// getIDField returns the field holding the ids of the users
+func (m *Module) getIDField(dbAlias string) (string, error) {
+	actualDbType, err := m.crud.GetDBType(dbAlias)
+	if err != nil {
+		return "", err
+	}
+	if actualDbType == string(model.Mongo) || actualDbType == string(model.EmbeddedDB) {
+		return "_id", nil
+	}
+	return "id", nil
+}
@@ -0,0 +1,93 @@
+package userman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// sendMailWithProvider sends a plain text mail with the provider configured
+func (m *Module) sendMailWithProvider(ctx context.Context, c *config.MailConfig, to, subject, body string) error {
+	switch c.Provider {
+	case config.MailProviderSMTP:
+		password, err := m.getSecret(ctx, c.Password)
+		if err != nil {
+			return err
+		}
+		return sendSMTPMail(c, password, to, subject, body)
+	case config.MailProviderSendGrid:
+		apiKey, err := m.getSecret(ctx, c.APIKey)
+		if err != nil {
+			return err
+		}
+		return sendSendGridMail(ctx, c, apiKey, to, subject, body)
+	default:
+		return fmt.Errorf("invalid mail provider (%s) provided", c.Provider)
+	}
+}
+
+func sendSMTPMail(c *config.MailConfig, password, to, subject, body string) error {
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, password, c.Host)
+	}
+
+	msg := new(bytes.Buffer)
+	fmt.Fprintf(msg, "From: %s\r\n", c.From)
+	fmt.Fprintf(msg, "To: %s\r\n", to)
+	fmt.Fprintf(msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	return smtp.SendMail(addr, auth, c.From, []string{to}, msg.Bytes())
+}
+
+func sendSendGridMail(ctx context.Context, c *config.MailConfig, apiKey, to, subject, body string) error {
+	type address struct {
+		Email string `json:"email"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"personalizations": []interface{}{map[string]interface{}{"to": []address{{Email: to}}}},
+		"from":             address{Email: c.From},
+		"subject":          subject,
+		"content":          []content{{Type: "text/plain", Value: body}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid responded with status code (%d)", res.StatusCode)
+	}
+	return nil
+}
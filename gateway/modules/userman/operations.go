@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/spaceuptech/helpers"
 	"golang.org/x/crypto/bcrypt"
 
 	uuid "github.com/satori/go.uuid"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
@@ -53,7 +55,7 @@ func (m *Module) Profile(ctx context.Context, token, dbAlias, project, id string
 	_ = authHelpers.PostProcessMethod(ctx, m.aesKey, actions, res)
 
 	// Delete password from user object
-	delete(res.(map[string]interface{}), "pass")
+	sanitizeUser(res.(map[string]interface{}))
 
 	return http.StatusOK, res.(map[string]interface{}), nil
 }
@@ -85,8 +87,7 @@ func (m *Module) Profiles(ctx context.Context, token, dbAlias, project string) (
 	// Delete password from user object
 	if usersArray, ok := res.([]interface{}); ok {
 		for _, user := range usersArray {
-			userObj := user.(map[string]interface{})
-			delete(userObj, "pass")
+			sanitizeUser(user.(map[string]interface{}))
 		}
 	}
 
@@ -102,7 +103,7 @@ func (m *Module) EmailSignIn(ctx context.Context, dbAlias, project, email, passw
 
 	// Create read request
 	attr := map[string]string{"project": project, "db": dbAlias, "col": "users"}
-	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
 	readReq := &model.ReadRequest{Find: map[string]interface{}{"email": email}, Operation: utils.One}
 
 	user, _, err := m.crud.Read(ctx, dbAlias, "users", readReq, reqParams)
@@ -112,14 +113,48 @@ func (m *Module) EmailSignIn(ctx context.Context, dbAlias, project, email, passw
 
 	userObj := user.(map[string]interface{})
 
+	emailConfig := m.getEmailConfig()
+	var lockout *config.AccountLockout
+	if emailConfig != nil {
+		lockout = emailConfig.Lockout
+	}
+	if lockout != nil && isLocked(userObj, time.Now()) {
+		return http.StatusForbidden, nil, errors.New("Account is locked due to too many failed sign in attempts. Try again later")
+	}
+
 	// Compares if the given password is correct
 	err = bcrypt.CompareHashAndPassword([]byte(userObj["pass"].(string)), []byte(password))
 	if err != nil {
+		if lockout != nil {
+			m.recordFailedSignIn(ctx, lockout, dbAlias, project, map[string]interface{}{"email": email})
+		}
 		return http.StatusUnauthorized, nil, errors.New("Given credentials are not correct")
 	}
+	if lockout != nil && getFailedAttempts(userObj) > 0 {
+		if err := m.updateUser(ctx, dbAlias, project, map[string]interface{}{"email": email}, map[string]interface{}{"$set": map[string]interface{}{fieldFailedAttempts: 0}}); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to reset failed sign in attempts", err, nil)
+		}
+	}
+
+	// Users who haven't verified their email are only denied once they have proven their credentials
+	if emailConfig != nil && emailConfig.RequireVerification && userObj[fieldVerified] != true {
+		return http.StatusForbidden, nil, errors.New("Email has not been verified")
+	}
+	if !isActive(userObj) {
+		return http.StatusForbidden, nil, errors.New("User has been deactivated")
+	}
+
+	// Users who have enrolled in two factor authentication get a challenge to be completed with the second factor
+	if m.getTwoFactorConfig() != nil && userObj[fieldTOTPEnabled] == true {
+		challenge, err := m.createTwoFactorChallenge(dbAlias, userObj)
+		if err != nil {
+			return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to create two factor challenge", err, nil)
+		}
+		return http.StatusOK, map[string]interface{}{"twoFactorRequired": true, "challenge": challenge}, nil
+	}
 
 	// Delete password from user
-	delete(userObj, "pass")
+	sanitizeUser(userObj)
 
 	req := map[string]interface{}{}
 	req["email"] = email
@@ -134,8 +169,10 @@ func (m *Module) EmailSignIn(ctx context.Context, dbAlias, project, email, passw
 		req["id"] = userObj["id"]
 	}
 	req["role"] = userObj["role"]
+	req["mfa"] = false
+	setGroupsClaim(req, userObj)
 
-	token, err := m.auth.CreateToken(ctx, req)
+	token, err := m.createToken(ctx, req)
 	if err != nil {
 		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
 	}
@@ -149,6 +186,11 @@ func (m *Module) EmailSignUp(ctx context.Context, dbAlias, project, email, name,
 		return http.StatusNotFound, nil, errors.New("Email sign in feature is not enabled")
 	}
 
+	emailConfig := m.getEmailConfig()
+	if err := checkPassword(getPasswordPolicy(emailConfig), password); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
 	// Hash the password that's in the request
 	var err error
 	password, err = hashPassword(password)
@@ -159,7 +201,7 @@ func (m *Module) EmailSignUp(ctx context.Context, dbAlias, project, email, name,
 
 	// Create read request
 	attr := map[string]string{"project": project, "db": dbAlias, "col": "users"}
-	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
 	readReq := &model.ReadRequest{Find: map[string]interface{}{"email": email}, Operation: utils.One}
 	_, _, err = m.crud.Read(ctx, dbAlias, "users", readReq, reqParams)
 	if err == nil {
@@ -171,6 +213,9 @@ func (m *Module) EmailSignUp(ctx context.Context, dbAlias, project, email, name,
 	req["pass"] = password
 	req["name"] = name
 	req["role"] = role
+	if emailConfig != nil && emailConfig.VerificationURL != "" {
+		req[fieldVerified] = false
+	}
 	actualDbType, err := m.crud.GetDBType(dbAlias)
 	if err != nil {
 		return http.StatusInternalServerError, nil, err
@@ -193,13 +238,26 @@ func (m *Module) EmailSignUp(ctx context.Context, dbAlias, project, email, name,
 
 	delete(req, "pass")
 
+	// The user can ask for the verification mail again if it couldn't be sent
+	if emailConfig != nil && emailConfig.VerificationURL != "" {
+		if err := m.mailVerificationLink(ctx, emailConfig, dbAlias, req); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to send verification mail", err, nil)
+		}
+	}
+
+	// No token is issued to users who can't sign in until they verify their email
+	if emailConfig != nil && emailConfig.RequireVerification {
+		return http.StatusOK, map[string]interface{}{"user": req}, nil
+	}
+
 	// Create a new token Object
 	tokenObj := map[string]interface{}{
 		"email": email,
 		"role":  role,
-		"id":    id.String()}
+		"id":    id.String(),
+		"mfa":   false}
 
-	token, err := m.auth.CreateToken(ctx, tokenObj)
+	token, err := m.createToken(ctx, tokenObj)
 	if err != nil {
 		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
 	}
@@ -237,6 +295,9 @@ func (m *Module) EmailEditProfile(ctx context.Context, token, dbAlias, project,
 		set["name"] = name
 	}
 	if password != "" {
+		if err := checkPassword(getPasswordPolicy(m.getEmailConfig()), password); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
 		var err1 error
 		password, err1 = hashPassword(password)
 		if err1 != nil {
@@ -269,14 +330,19 @@ func (m *Module) EmailEditProfile(ctx context.Context, token, dbAlias, project,
 	userObj := user.(map[string]interface{})
 
 	// Delete password from user
-	delete(userObj, "pass")
+	sanitizeUser(userObj)
 
 	req1 := map[string]interface{}{}
 	req1["email"] = userObj["email"]
 	req1["id"] = userObj[idString]
 	req1["role"] = userObj["role"]
 
-	token1, err := m.auth.CreateToken(ctx, req1)
+	// The new token keeps the two factor status of the one it replaces
+	claims, err := m.auth.ParseToken(ctx, token)
+	req1["mfa"] = err == nil && claims["mfa"] == true
+	setGroupsClaim(req1, userObj)
+
+	token1, err := m.createToken(ctx, req1)
 	if err != nil {
 		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
 	}
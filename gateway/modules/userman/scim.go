@@ -0,0 +1,450 @@
+package userman
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// The fields of the users and groups collections holding the resources provisioned over scim
+const (
+	fieldActive     = "active"
+	fieldExternalID = "external_id"
+	fieldGroups     = "groups"
+	fieldMembers    = "members"
+)
+
+// The attributes of the resources which can be filtered on along with the fields they are stored in. The id
+// attribute is stored in the id field of the database.
+var (
+	scimUserAttributes  = map[string]string{"username": "email", "emails.value": "email", "externalid": fieldExternalID, "displayname": "name"}
+	scimGroupAttributes = map[string]string{"displayname": "name", "externalid": fieldExternalID}
+)
+
+var (
+	scimFilterClause = regexp.MustCompile(`^\s*([\w.:]+)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*`)
+	scimFilterAnd    = regexp.MustCompile(`^(?i:and)\s+`)
+)
+
+func validateSCIMConfig(c *config.SCIMConfig) error {
+	if c == nil || c.DBAlias == "" {
+		return errors.New("db alias must be provided for scim provisioning")
+	}
+	if c.Token == "" {
+		return errors.New("token must be provided for scim provisioning")
+	}
+	return nil
+}
+
+// getSCIMFind returns the where clause of a scim filter. Only the equality of attributes joined with and is
+// supported, which is all the identity providers use to look resources up.
+func getSCIMFind(filter, idField string, attributes map[string]string) (map[string]interface{}, error) {
+	find := map[string]interface{}{}
+	for rest := strings.TrimSpace(filter); rest != ""; {
+		match := scimFilterClause.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported filter (%s) provided", filter)
+		}
+		value, err := strconv.Unquote(`"` + match[2] + `"`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in filter (%s) provided", filter)
+		}
+
+		attribute := strings.ToLower(match[1])
+		field, ok := attributes[attribute]
+		if attribute == "id" {
+			field, ok = idField, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("filtering on attribute (%s) is not supported", match[1])
+		}
+		find[field] = value
+
+		rest = rest[len(match[0]):]
+		if rest == "" {
+			break
+		}
+		and := scimFilterAnd.FindString(rest)
+		if and == "" {
+			return nil, fmt.Errorf("unsupported filter (%s) provided", filter)
+		}
+		rest = rest[len(and):]
+	}
+	return find, nil
+}
+
+// getSCIMStartIndex returns the one based start index of a page. Start indexes less than one are treated as one.
+func getSCIMStartIndex(startIndex int) int {
+	if startIndex < 1 {
+		return 1
+	}
+	return startIndex
+}
+
+func copyFind(find map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(find))
+	for k, v := range find {
+		copied[k] = v
+	}
+	return copied
+}
+
+// userToSCIM returns the scim representation of a user. The email of the user is its user name.
+func userToSCIM(user map[string]interface{}, idField string, groups []model.SCIMValue) *model.SCIMUser {
+	email, _ := user["email"].(string)
+	name, _ := user["name"].(string)
+	externalID, _ := user[fieldExternalID].(string)
+	active := isActive(user)
+	return &model.SCIMUser{
+		Schemas:     []string{model.SCIMUserSchema},
+		ID:          fmt.Sprintf("%v", user[idField]),
+		ExternalID:  externalID,
+		UserName:    email,
+		Name:        &model.SCIMName{Formatted: name},
+		DisplayName: name,
+		Emails:      []model.SCIMValue{{Value: email, Primary: true}},
+		Active:      &active,
+		Groups:      groups,
+		Meta:        &model.SCIMMeta{ResourceType: "User"},
+	}
+}
+
+// getSCIMUserFields returns the fields of the users collection holding a scim user. The password is left to the
+// caller since it needs to be hashed.
+func getSCIMUserFields(u *model.SCIMUser) (map[string]interface{}, error) {
+	if u.UserName == "" {
+		return nil, errors.New("userName of user is required")
+	}
+
+	name := u.DisplayName
+	if name == "" && u.Name != nil {
+		name = u.Name.Formatted
+		if name == "" {
+			name = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+	return map[string]interface{}{
+		"email":         u.UserName,
+		"name":          name,
+		fieldExternalID: u.ExternalID,
+		fieldActive:     u.Active == nil || *u.Active,
+	}, nil
+}
+
+// applySCIMUserPatch applies a patch operation to a user. The attributes which aren't stored are ignored, since
+// the identity providers send all the attributes they know of.
+func applySCIMUserPatch(u *model.SCIMUser, op *model.SCIMPatchOperation) error {
+	remove, err := isSCIMRemove(op)
+	if err != nil {
+		return err
+	}
+	if op.Path != "" {
+		return setSCIMUserAttribute(u, op.Path, op.Value, remove)
+	}
+
+	values, ok := op.Value.(map[string]interface{})
+	if !ok {
+		return errors.New("value of patch operation without a path must be an object")
+	}
+	for k, v := range values {
+		if err := setSCIMUserAttribute(u, k, v, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setSCIMUserAttribute(u *model.SCIMUser, path string, value interface{}, remove bool) error {
+	path = strings.TrimPrefix(strings.ToLower(path), strings.ToLower(model.SCIMUserSchema)+":")
+	switch path {
+	case "active":
+		active := false
+		if !remove {
+			v, err := getSCIMBool(value)
+			if err != nil {
+				return err
+			}
+			active = v
+		}
+		u.Active = &active
+		return nil
+	case "username":
+		if remove {
+			return errors.New("userName of user cannot be removed")
+		}
+		return setSCIMString(&u.UserName, value, false)
+	case "displayname":
+		return setSCIMString(&u.DisplayName, value, remove)
+	case "externalid":
+		return setSCIMString(&u.ExternalID, value, remove)
+	case "password":
+		return setSCIMString(&u.Password, value, remove)
+	case "name":
+		u.Name = new(model.SCIMName)
+		if remove {
+			return nil
+		}
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.New("invalid value of attribute (name) provided")
+		}
+		for k, v := range values {
+			if err := setSCIMUserAttribute(u, "name."+k, v, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "name.formatted", "name.givenname", "name.familyname":
+		if u.Name == nil {
+			u.Name = new(model.SCIMName)
+		}
+		// The stored name is derived from the display name first
+		u.DisplayName = ""
+		switch path {
+		case "name.formatted":
+			return setSCIMString(&u.Name.Formatted, value, remove)
+		case "name.givenname":
+			return setSCIMString(&u.Name.GivenName, value, remove)
+		default:
+			return setSCIMString(&u.Name.FamilyName, value, remove)
+		}
+	}
+	return nil
+}
+
+// applySCIMGroupPatch applies a patch operation to a group
+func applySCIMGroupPatch(g *model.SCIMGroup, op *model.SCIMPatchOperation) error {
+	remove, err := isSCIMRemove(op)
+	if err != nil {
+		return err
+	}
+	if op.Path != "" {
+		return setSCIMGroupAttribute(g, strings.ToLower(op.Op), op.Path, op.Value, remove)
+	}
+
+	values, ok := op.Value.(map[string]interface{})
+	if !ok {
+		return errors.New("value of patch operation without a path must be an object")
+	}
+	for k, v := range values {
+		if err := setSCIMGroupAttribute(g, strings.ToLower(op.Op), k, v, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setSCIMGroupAttribute(g *model.SCIMGroup, op, path string, value interface{}, remove bool) error {
+	path = strings.TrimPrefix(path, model.SCIMGroupSchema+":")
+	switch lower := strings.ToLower(path); {
+	case lower == "displayname":
+		if remove {
+			return errors.New("displayName of group cannot be removed")
+		}
+		return setSCIMString(&g.DisplayName, value, false)
+	case lower == "externalid":
+		return setSCIMString(&g.ExternalID, value, remove)
+	case lower == "members":
+		if remove && value == nil {
+			g.Members = nil
+			return nil
+		}
+		members, err := getSCIMMembers(value)
+		if err != nil {
+			return err
+		}
+		switch {
+		case remove:
+			g.Members = removeSCIMMembers(g.Members, members)
+		case op == "replace":
+			g.Members = members
+		default:
+			g.Members = append(removeSCIMMembers(g.Members, members), members...)
+		}
+		return nil
+	case strings.HasPrefix(lower, "members[") && strings.HasSuffix(lower, "]"):
+		// Members are removed by a filter on their id like members[value eq "id"]
+		if !remove {
+			return fmt.Errorf("unsupported path (%s) provided", path)
+		}
+		find, err := getSCIMFind(path[len("members["):len(path)-1], "", map[string]string{"value": "value"})
+		if err != nil {
+			return err
+		}
+		id, _ := find["value"].(string)
+		g.Members = removeSCIMMembers(g.Members, []model.SCIMValue{{Value: id}})
+		return nil
+	}
+	return nil
+}
+
+func isSCIMRemove(op *model.SCIMPatchOperation) (bool, error) {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		return false, nil
+	case "remove":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid patch operation (%s) provided", op.Op)
+}
+
+func setSCIMString(field *string, value interface{}, remove bool) error {
+	if remove {
+		*field = ""
+		return nil
+	}
+	v, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("invalid value (%v) provided for string attribute", value)
+	}
+	*field = v
+	return nil
+}
+
+// getSCIMBool returns a boolean value. Azure AD sends them as strings.
+func getSCIMBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.ToLower(v))
+	}
+	return false, fmt.Errorf("invalid value (%v) provided for boolean attribute", value)
+}
+
+// getSCIMMembers returns the members in the value of a patch operation. It is a list of members or a single one.
+func getSCIMMembers(value interface{}) ([]model.SCIMValue, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		list = []interface{}{value}
+	}
+
+	members := make([]model.SCIMValue, 0, len(list))
+	for _, item := range list {
+		obj, _ := item.(map[string]interface{})
+		id, ok := obj["value"].(string)
+		if !ok || id == "" {
+			return nil, errors.New("invalid value of attribute (members) provided")
+		}
+		members = append(members, model.SCIMValue{Value: id})
+	}
+	return members, nil
+}
+
+func removeSCIMMembers(members, removed []model.SCIMValue) []model.SCIMValue {
+	result := make([]model.SCIMValue, 0, len(members))
+	for _, member := range members {
+		found := false
+		for _, r := range removed {
+			if member.Value == r.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// groupToSCIM returns the scim representation of a group
+func groupToSCIM(group map[string]interface{}, idField string) *model.SCIMGroup {
+	name, _ := group["name"].(string)
+	externalID, _ := group[fieldExternalID].(string)
+	members := []model.SCIMValue{}
+	for _, id := range getGroupMembers(group) {
+		members = append(members, model.SCIMValue{Value: id})
+	}
+	return &model.SCIMGroup{
+		Schemas:     []string{model.SCIMGroupSchema},
+		ID:          fmt.Sprintf("%v", group[idField]),
+		ExternalID:  externalID,
+		DisplayName: name,
+		Members:     members,
+		Meta:        &model.SCIMMeta{ResourceType: "Group"},
+	}
+}
+
+// getSCIMGroupFields returns the fields of the groups collection holding a scim group. The members are kept as the
+// comma separated ids of the users so that the groups collection needs no other table in sql databases.
+func getSCIMGroupFields(g *model.SCIMGroup) (map[string]interface{}, error) {
+	if g.DisplayName == "" {
+		return nil, errors.New("displayName of group is required")
+	}
+	if strings.Contains(g.DisplayName, ",") {
+		return nil, errors.New("displayName of group cannot contain a comma")
+	}
+
+	ids := make([]string, 0, len(g.Members))
+	seen := map[string]bool{}
+	for _, member := range g.Members {
+		if member.Value == "" || strings.Contains(member.Value, ",") {
+			return nil, fmt.Errorf("invalid member (%s) of group provided", member.Value)
+		}
+		if !seen[member.Value] {
+			seen[member.Value] = true
+			ids = append(ids, member.Value)
+		}
+	}
+	return map[string]interface{}{"name": g.DisplayName, fieldExternalID: g.ExternalID, fieldMembers: strings.Join(ids, ",")}, nil
+}
+
+// getGroupMembers returns the ids of the members of a group
+func getGroupMembers(group map[string]interface{}) []string {
+	return splitList(group[fieldMembers])
+}
+
+// setGroupsClaim sets the names of the groups of the user as the groups claim of its token
+func setGroupsClaim(claims, user map[string]interface{}) {
+	if groups := splitList(user[fieldGroups]); len(groups) > 0 {
+		claims["groups"] = groups
+	}
+}
+
+// getUserGroupNames returns the sorted names of the groups of the user after a group changed from before to after
+func getUserGroupNames(user map[string]interface{}, userID string, before, after map[string]interface{}) []string {
+	former, _ := before["name"].(string)
+	names := []string{}
+	for _, name := range splitList(user[fieldGroups]) {
+		if before == nil || name != former {
+			names = append(names, name)
+		}
+	}
+	for _, id := range getGroupMembers(after) {
+		if id == userID {
+			name, _ := after["name"].(string)
+			names = append(names, name)
+			break
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func splitList(value interface{}) []string {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// isActive tells if the user is active. Users provisioned before the field existed are active.
+func isActive(user map[string]interface{}) bool {
+	switch v := user[fieldActive].(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case int:
+		return v != 0
+	}
+	return true
+}
@@ -0,0 +1,574 @@
+package userman
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// SCIMAuthenticate checks the bearer token a scim request was made with
+func (m *Module) SCIMAuthenticate(ctx context.Context, token string) (int, error) {
+	c := m.getSCIMConfig()
+	if c == nil {
+		return http.StatusNotFound, errors.New("SCIM provisioning is not enabled")
+	}
+
+	want, err := m.getSecret(ctx, c.Token)
+	if err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to resolve token of scim provisioning", err, nil)
+	}
+	if token == "" || want == "" || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+		return http.StatusUnauthorized, errors.New("Invalid token provided")
+	}
+	return http.StatusOK, nil
+}
+
+// SCIMListUsers returns the page of the users matching the filter
+func (m *Module) SCIMListUsers(ctx context.Context, project, filter string, startIndex, count int) (int, *model.SCIMListResponse, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	find, err := getSCIMFind(filter, idField, scimUserAttributes)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	users, total, err := m.readPage(ctx, c.DBAlias, project, "users", idField, find, startIndex, count)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	groups, err := m.readUserGroups(ctx, c, project, users...)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	resources := make([]*model.SCIMUser, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, userToSCIM(user, idField, getSCIMUserGroups(groups, idField, fmt.Sprintf("%v", user[idField]))))
+	}
+	return http.StatusOK, &model.SCIMListResponse{Schemas: []string{model.SCIMListSchema}, TotalResults: total, StartIndex: getSCIMStartIndex(startIndex), ItemsPerPage: len(resources), Resources: resources}, nil
+}
+
+// SCIMGetUser returns a user
+func (m *Module) SCIMGetUser(ctx context.Context, project, id string) (int, *model.SCIMUser, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	user, err := m.readUser(ctx, c.DBAlias, project, map[string]interface{}{idField: id})
+	if err != nil {
+		return http.StatusNotFound, nil, fmt.Errorf("User (%s) not found", id)
+	}
+	groups, err := m.readUserGroups(ctx, c, project, user)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusOK, userToSCIM(user, idField, getSCIMUserGroups(groups, idField, id)), nil
+}
+
+// SCIMCreateUser provisions a user. Users provisioned without a password can only sign in once they reset it.
+func (m *Module) SCIMCreateUser(ctx context.Context, project string, u *model.SCIMUser) (int, *model.SCIMUser, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	fields, err := getSCIMUserFields(u)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if _, err := m.readUser(ctx, c.DBAlias, project, map[string]interface{}{"email": u.UserName}); err == nil {
+		return http.StatusConflict, nil, fmt.Errorf("User (%s) already exists", u.UserName)
+	}
+
+	password := u.Password
+	if password == "" {
+		if password, err = generateUnusablePassword(); err != nil {
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+	if fields["pass"], err = hashPassword(password); err != nil {
+		return http.StatusInternalServerError, nil, errors.New("Failed to hash password")
+	}
+
+	fields[idField] = uuid.NewV1().String()
+	fields["role"] = c.DefaultRole
+	if c.DefaultRole == "" {
+		fields["role"] = "user"
+	}
+	fields[fieldGroups] = ""
+	// The identity provider has verified the email already
+	if emailConfig := m.getEmailConfig(); emailConfig != nil && emailConfig.VerificationURL != "" {
+		fields[fieldVerified] = true
+	}
+
+	attr := map[string]string{"project": project, "db": c.DBAlias, "col": "users"}
+	reqParams := model.RequestParams{Resource: "db-create", Op: "access", Attributes: attr}
+	if err := m.crud.Create(ctx, c.DBAlias, "users", &model.CreateRequest{Operation: utils.One, Document: fields}, reqParams); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to provision user", err, nil)
+	}
+	return http.StatusCreated, userToSCIM(fields, idField, []model.SCIMValue{}), nil
+}
+
+// SCIMReplaceUser replaces the attributes of a user
+func (m *Module) SCIMReplaceUser(ctx context.Context, project, id string, u *model.SCIMUser) (int, *model.SCIMUser, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	user, err := m.readUser(ctx, c.DBAlias, project, map[string]interface{}{idField: id})
+	if err != nil {
+		return http.StatusNotFound, nil, fmt.Errorf("User (%s) not found", id)
+	}
+	return m.saveSCIMUser(ctx, c, project, idField, user, u)
+}
+
+// SCIMPatchUser modifies the attributes of a user. Deactivating a user revokes its sessions.
+func (m *Module) SCIMPatchUser(ctx context.Context, project, id string, req *model.SCIMPatchRequest) (int, *model.SCIMUser, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	user, err := m.readUser(ctx, c.DBAlias, project, map[string]interface{}{idField: id})
+	if err != nil {
+		return http.StatusNotFound, nil, fmt.Errorf("User (%s) not found", id)
+	}
+
+	u := userToSCIM(user, idField, nil)
+	for _, op := range req.Operations {
+		if err := applySCIMUserPatch(u, op); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+	}
+	return m.saveSCIMUser(ctx, c, project, idField, user, u)
+}
+
+// SCIMDeleteUser deprovisions a user. The user is removed from its groups and its sessions are revoked.
+func (m *Module) SCIMDeleteUser(ctx context.Context, project, id string) (int, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	find := map[string]interface{}{idField: id}
+	user, err := m.readUser(ctx, c.DBAlias, project, find)
+	if err != nil {
+		return http.StatusNotFound, fmt.Errorf("User (%s) not found", id)
+	}
+
+	attr := map[string]string{"project": project, "db": c.DBAlias, "col": "users"}
+	reqParams := model.RequestParams{Resource: "db-delete", Op: "access", Attributes: attr}
+	if err := m.crud.Delete(ctx, c.DBAlias, "users", &model.DeleteRequest{Find: find, Operation: utils.One}, reqParams); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to deprovision user", err, nil)
+	}
+
+	groups, err := m.readUserGroups(ctx, c, project, user)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, group := range groups {
+		g := groupToSCIM(group, idField)
+		members := removeSCIMMembers(g.Members, []model.SCIMValue{{Value: id}})
+		if len(members) == len(g.Members) {
+			continue
+		}
+		g.Members = members
+		fields, _ := getSCIMGroupFields(g)
+		if err := m.updateGroup(ctx, c, project, map[string]interface{}{idField: group[idField]}, fields); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	m.revokeSessions(ctx, id)
+	return http.StatusNoContent, nil
+}
+
+// SCIMListGroups returns the page of the groups matching the filter
+func (m *Module) SCIMListGroups(ctx context.Context, project, filter string, startIndex, count int) (int, *model.SCIMListResponse, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	find, err := getSCIMFind(filter, idField, scimGroupAttributes)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	groups, total, err := m.readPage(ctx, c.DBAlias, project, getGroupsCollection(c), idField, find, startIndex, count)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	resources := make([]*model.SCIMGroup, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, groupToSCIM(group, idField))
+	}
+	return http.StatusOK, &model.SCIMListResponse{Schemas: []string{model.SCIMListSchema}, TotalResults: total, StartIndex: getSCIMStartIndex(startIndex), ItemsPerPage: len(resources), Resources: resources}, nil
+}
+
+// SCIMGetGroup returns a group
+func (m *Module) SCIMGetGroup(ctx context.Context, project, id string) (int, *model.SCIMGroup, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	group, err := m.readGroup(ctx, c, project, map[string]interface{}{idField: id})
+	if err != nil {
+		return http.StatusNotFound, nil, fmt.Errorf("Group (%s) not found", id)
+	}
+	return http.StatusOK, groupToSCIM(group, idField), nil
+}
+
+// SCIMCreateGroup provisions a group
+func (m *Module) SCIMCreateGroup(ctx context.Context, project string, g *model.SCIMGroup) (int, *model.SCIMGroup, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	fields, err := getSCIMGroupFields(g)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if _, err := m.readGroup(ctx, c, project, map[string]interface{}{"name": g.DisplayName}); err == nil {
+		return http.StatusConflict, nil, fmt.Errorf("Group (%s) already exists", g.DisplayName)
+	}
+
+	fields[idField] = uuid.NewV1().String()
+	col := getGroupsCollection(c)
+	attr := map[string]string{"project": project, "db": c.DBAlias, "col": col}
+	reqParams := model.RequestParams{Resource: "db-create", Op: "access", Attributes: attr}
+	if err := m.crud.Create(ctx, c.DBAlias, col, &model.CreateRequest{Operation: utils.One, Document: fields}, reqParams); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to provision group", err, nil)
+	}
+
+	if err := m.syncUserGroups(ctx, c, project, idField, nil, fields); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusCreated, groupToSCIM(fields, idField), nil
+}
+
+// SCIMReplaceGroup replaces the attributes of a group
+func (m *Module) SCIMReplaceGroup(ctx context.Context, project, id string, g *model.SCIMGroup) (int, *model.SCIMGroup, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	group, err := m.readGroup(ctx, c, project, map[string]interface{}{idField: id})
+	if err != nil {
+		return http.StatusNotFound, nil, fmt.Errorf("Group (%s) not found", id)
+	}
+	return m.saveSCIMGroup(ctx, c, project, idField, group, g)
+}
+
+// SCIMPatchGroup modifies the attributes of a group. It is how the identity providers add and remove members.
+func (m *Module) SCIMPatchGroup(ctx context.Context, project, id string, req *model.SCIMPatchRequest) (int, *model.SCIMGroup, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, nil, err
+	}
+	group, err := m.readGroup(ctx, c, project, map[string]interface{}{idField: id})
+	if err != nil {
+		return http.StatusNotFound, nil, fmt.Errorf("Group (%s) not found", id)
+	}
+
+	g := groupToSCIM(group, idField)
+	for _, op := range req.Operations {
+		if err := applySCIMGroupPatch(g, op); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+	}
+	return m.saveSCIMGroup(ctx, c, project, idField, group, g)
+}
+
+// SCIMDeleteGroup deprovisions a group
+func (m *Module) SCIMDeleteGroup(ctx context.Context, project, id string) (int, error) {
+	c, idField, err := m.getSCIMState()
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	find := map[string]interface{}{idField: id}
+	group, err := m.readGroup(ctx, c, project, find)
+	if err != nil {
+		return http.StatusNotFound, fmt.Errorf("Group (%s) not found", id)
+	}
+
+	col := getGroupsCollection(c)
+	attr := map[string]string{"project": project, "db": c.DBAlias, "col": col}
+	reqParams := model.RequestParams{Resource: "db-delete", Op: "access", Attributes: attr}
+	if err := m.crud.Delete(ctx, c.DBAlias, col, &model.DeleteRequest{Find: find, Operation: utils.One}, reqParams); err != nil {
+		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to deprovision group", err, nil)
+	}
+
+	if err := m.syncUserGroups(ctx, c, project, idField, group, nil); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusNoContent, nil
+}
+
+// saveSCIMUser saves the attributes of an existing user
+func (m *Module) saveSCIMUser(ctx context.Context, c *config.SCIMConfig, project, idField string, user map[string]interface{}, u *model.SCIMUser) (int, *model.SCIMUser, error) {
+	fields, err := getSCIMUserFields(u)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if u.UserName != user["email"] {
+		if _, err := m.readUser(ctx, c.DBAlias, project, map[string]interface{}{"email": u.UserName}); err == nil {
+			return http.StatusConflict, nil, fmt.Errorf("User (%s) already exists", u.UserName)
+		}
+	}
+	if u.Password != "" {
+		if fields["pass"], err = hashPassword(u.Password); err != nil {
+			return http.StatusInternalServerError, nil, errors.New("Failed to hash password")
+		}
+	}
+
+	id := fmt.Sprintf("%v", user[idField])
+	if err := m.updateUser(ctx, c.DBAlias, project, map[string]interface{}{idField: user[idField]}, map[string]interface{}{"$set": fields}); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update provisioned user", err, nil)
+	}
+	if isActive(user) && fields[fieldActive] == false {
+		m.revokeSessions(ctx, id)
+	}
+
+	for k, v := range fields {
+		user[k] = v
+	}
+	groups, err := m.readUserGroups(ctx, c, project, user)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	return http.StatusOK, userToSCIM(user, idField, getSCIMUserGroups(groups, idField, id)), nil
+}
+
+// saveSCIMGroup saves the attributes of an existing group and updates the groups of the users affected
+func (m *Module) saveSCIMGroup(ctx context.Context, c *config.SCIMConfig, project, idField string, group map[string]interface{}, g *model.SCIMGroup) (int, *model.SCIMGroup, error) {
+	fields, err := getSCIMGroupFields(g)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if g.DisplayName != group["name"] {
+		if _, err := m.readGroup(ctx, c, project, map[string]interface{}{"name": g.DisplayName}); err == nil {
+			return http.StatusConflict, nil, fmt.Errorf("Group (%s) already exists", g.DisplayName)
+		}
+	}
+
+	if err := m.updateGroup(ctx, c, project, map[string]interface{}{idField: group[idField]}, fields); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	if err := m.syncUserGroups(ctx, c, project, idField, group, fields); err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	for k, v := range fields {
+		group[k] = v
+	}
+	return http.StatusOK, groupToSCIM(group, idField), nil
+}
+
+// syncUserGroups updates the names of the groups of the users affected by a change of a group, which are issued as the
+// groups claim of their tokens. The former members of the group lose its former name while its current members get its
+// current name. The group is nil before it's created and after it's deleted.
+func (m *Module) syncUserGroups(ctx context.Context, c *config.SCIMConfig, project, idField string, before, after map[string]interface{}) error {
+	done := map[string]bool{}
+	for _, id := range append(getGroupMembers(before), getGroupMembers(after)...) {
+		if done[id] {
+			continue
+		}
+		done[id] = true
+
+		user, err := m.readUser(ctx, c.DBAlias, project, map[string]interface{}{idField: id})
+		if err != nil {
+			// Groups can have members which haven't been provisioned yet
+			continue
+		}
+		update := map[string]interface{}{"$set": map[string]interface{}{fieldGroups: strings.Join(getUserGroupNames(user, id, before, after), ",")}}
+		if err := m.updateUser(ctx, c.DBAlias, project, map[string]interface{}{idField: id}, update); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to update groups of user (%s)", id), err, nil)
+		}
+	}
+	return nil
+}
+
+// revokeSessions signs a deprovisioned user out everywhere. The tokens of the user stay valid till they expire if the
+// sessions aren't tracked.
+func (m *Module) revokeSessions(ctx context.Context, userID string) {
+	m.RLock()
+	sessions := m.sessions
+	m.RUnlock()
+
+	if sessions == nil {
+		return
+	}
+	if _, err := sessions.RevokeAll(ctx, userID); err != nil {
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Unable to revoke sessions of deprovisioned user", map[string]interface{}{"user": userID, "error": err.Error()})
+	}
+}
+
+func (m *Module) readGroup(ctx context.Context, c *config.SCIMConfig, project string, find map[string]interface{}) (map[string]interface{}, error) {
+	groups, err := m.readAll(ctx, c.DBAlias, project, getGroupsCollection(c), find)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, errors.New("Group not found")
+	}
+	return groups[0], nil
+}
+
+func (m *Module) updateGroup(ctx context.Context, c *config.SCIMConfig, project string, find, fields map[string]interface{}) error {
+	col := getGroupsCollection(c)
+	attr := map[string]string{"project": project, "db": c.DBAlias, "col": col}
+	reqParams := model.RequestParams{Resource: "db-update", Op: "access", Attributes: attr}
+	req := &model.UpdateRequest{Find: find, Operation: utils.One, Update: map[string]interface{}{"$set": fields}}
+	if err := m.crud.Update(ctx, c.DBAlias, col, req, reqParams); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to update provisioned group", err, nil)
+	}
+	return nil
+}
+
+// readUserGroups reads the groups the users are members of. The names of the groups of a user are kept in the user
+// itself, hence only those groups are read instead of looking the user up in the members of every group.
+func (m *Module) readUserGroups(ctx context.Context, c *config.SCIMConfig, project string, users ...map[string]interface{}) ([]map[string]interface{}, error) {
+	names := []interface{}{}
+	seen := map[string]bool{}
+	for _, user := range users {
+		for _, name := range splitList(user[fieldGroups]) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+	return m.readAll(ctx, c.DBAlias, project, getGroupsCollection(c), map[string]interface{}{"name": map[string]interface{}{"$in": names}})
+}
+
+// readPage reads a page of the documents matching the find clause along with the number of documents matching it. The
+// documents are sorted on their id so that the pages are stable. The start index is one based and a negative count
+// reads all the documents after it, which are still limited by the fetch limit of the database.
+func (m *Module) readPage(ctx context.Context, dbAlias, project, col, idField string, find map[string]interface{}, startIndex, count int) ([]map[string]interface{}, int, error) {
+	res, err := m.read(ctx, dbAlias, project, col, &model.ReadRequest{Find: copyFind(find), Operation: utils.Count, Options: &model.ReadOptions{}})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, ok := res.(int64)
+	if !ok {
+		return nil, 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to count the documents of (%s)", col), fmt.Errorf("count of type (%T) returned by the database", res), nil)
+	}
+
+	skip := int64(getSCIMStartIndex(startIndex) - 1)
+	if count == 0 || skip >= total {
+		return []map[string]interface{}{}, int(total), nil
+	}
+	options := &model.ReadOptions{Sort: []string{idField}, Skip: &skip}
+	if count > 0 {
+		limit := int64(count)
+		options.Limit = &limit
+	}
+	docs, err := m.readDocs(ctx, dbAlias, project, col, &model.ReadRequest{Find: copyFind(find), Operation: utils.All, Options: options})
+	return docs, int(total), err
+}
+
+// readAll reads the documents of a collection matching the find clause bypassing the security rules
+func (m *Module) readAll(ctx context.Context, dbAlias, project, col string, find map[string]interface{}) ([]map[string]interface{}, error) {
+	return m.readDocs(ctx, dbAlias, project, col, &model.ReadRequest{Find: find, Operation: utils.All})
+}
+
+// readDocs reads the documents of a collection bypassing the security rules
+func (m *Module) readDocs(ctx context.Context, dbAlias, project, col string, req *model.ReadRequest) ([]map[string]interface{}, error) {
+	res, err := m.read(ctx, dbAlias, project, col, req)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, _ := res.([]interface{})
+	docs := make([]map[string]interface{}, 0, len(arr))
+	for _, item := range arr {
+		if doc, ok := item.(map[string]interface{}); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// read makes a read request bypassing the security rules
+func (m *Module) read(ctx context.Context, dbAlias, project, col string, req *model.ReadRequest) (interface{}, error) {
+	attr := map[string]string{"project": project, "db": dbAlias, "col": col}
+	reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr, Claims: utils.InternalClaims()}
+	res, _, err := m.crud.Read(ctx, dbAlias, col, req, reqParams)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read collection (%s)", col), err, nil)
+	}
+	return res, nil
+}
+
+// getSCIMState returns the config of scim provisioning along with the field holding the ids of its database
+func (m *Module) getSCIMState() (*config.SCIMConfig, string, error) {
+	c := m.getSCIMConfig()
+	if c == nil {
+		return nil, "", errors.New("SCIM provisioning is not enabled")
+	}
+	idField, err := m.getIDField(c.DBAlias)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, idField, nil
+}
+
+// getSCIMConfig returns the config of scim provisioning. It is nil unless enabled.
+func (m *Module) getSCIMConfig() *config.SCIMConfig {
+	m.RLock()
+	defer m.RUnlock()
+
+	s, p := m.methods["scim"]
+	if !p || !s.Enabled {
+		return nil
+	}
+	return s.SCIM
+}
+
+func getGroupsCollection(c *config.SCIMConfig) string {
+	if c.GroupsCollection == "" {
+		return "groups"
+	}
+	return c.GroupsCollection
+}
+
+// getSCIMUserGroups returns the groups attribute of a user
+func getSCIMUserGroups(groups []map[string]interface{}, idField, userID string) []model.SCIMValue {
+	values := []model.SCIMValue{}
+	for _, group := range groups {
+		for _, id := range getGroupMembers(group) {
+			if id == userID {
+				name, _ := group["name"].(string)
+				values = append(values, model.SCIMValue{Value: fmt.Sprintf("%v", group[idField]), Display: name})
+				break
+			}
+		}
+	}
+	return values
+}
+
+// generateUnusablePassword returns a random password for the users provisioned without one
+func generateUnusablePassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,242 @@
+package userman
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type mockSessions struct {
+	revoked []string
+}
+
+func (s *mockSessions) Create(ctx context.Context, userID string) (string, error) {
+	return "", nil
+}
+
+func (s *mockSessions) RevokeAll(ctx context.Context, userID string) (int, error) {
+	s.revoked = append(s.revoked, userID)
+	return 1, nil
+}
+
+func Test_getSCIMFind(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{name: "no filter", filter: "", want: map[string]interface{}{}},
+		{name: "user name", filter: `userName eq "a@b.com"`, want: map[string]interface{}{"email": "a@b.com"}},
+		{name: "case insensitive", filter: `UserName EQ "a@b.com" AND externalId eq "x\"y"`, want: map[string]interface{}{"email": "a@b.com", fieldExternalID: `x"y`}},
+		{name: "id", filter: `id eq "1"`, want: map[string]interface{}{"id": "1"}},
+		{name: "unsupported operator", filter: `userName sw "a"`, wantErr: true},
+		{name: "unsupported attribute", filter: `title eq "a"`, wantErr: true},
+		{name: "or", filter: `userName eq "a" or userName eq "b"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getSCIMFind(tt.filter, "id", scimUserAttributes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getSCIMFind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getSCIMFind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applySCIMUserPatch(t *testing.T) {
+	u := &model.SCIMUser{UserName: "a@b.com", DisplayName: "A", Active: new(bool)}
+	ops := []*model.SCIMPatchOperation{
+		// Azure AD sends booleans as strings and capitalises the operations
+		{Op: "Replace", Path: "active", Value: "True"},
+		{Op: "replace", Value: map[string]interface{}{"userName": "c@d.com", "title": "ignored"}},
+		{Op: "add", Path: "name.givenName", Value: "C"},
+	}
+	for _, op := range ops {
+		if err := applySCIMUserPatch(u, op); err != nil {
+			t.Fatalf("applySCIMUserPatch() error = %v", err)
+		}
+	}
+	fields, _ := getSCIMUserFields(u)
+	want := map[string]interface{}{"email": "c@d.com", "name": "C", fieldExternalID: "", fieldActive: true}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("applySCIMUserPatch() resulted in fields %v, want %v", fields, want)
+	}
+
+	if err := applySCIMUserPatch(u, &model.SCIMPatchOperation{Op: "move", Path: "active"}); err == nil {
+		t.Errorf("applySCIMUserPatch() with invalid operation didn't return an error")
+	}
+}
+
+func Test_applySCIMGroupPatch(t *testing.T) {
+	g := &model.SCIMGroup{DisplayName: "a", Members: []model.SCIMValue{{Value: "1"}}}
+	ops := []*model.SCIMPatchOperation{
+		{Op: "add", Path: "members", Value: []interface{}{map[string]interface{}{"value": "2"}, map[string]interface{}{"value": "3"}}},
+		{Op: "remove", Path: `members[value eq "1"]`},
+		{Op: "replace", Value: map[string]interface{}{"displayName": "b"}},
+	}
+	for _, op := range ops {
+		if err := applySCIMGroupPatch(g, op); err != nil {
+			t.Fatalf("applySCIMGroupPatch() error = %v", err)
+		}
+	}
+	fields, _ := getSCIMGroupFields(g)
+	want := map[string]interface{}{"name": "b", fieldExternalID: "", fieldMembers: "2,3"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("applySCIMGroupPatch() resulted in fields %v, want %v", fields, want)
+	}
+}
+
+func TestModule_SCIM(t *testing.T) {
+	m, crud, _ := newTestModule(t, nil)
+	auths := config.Auths{
+		"email": {ID: "email", Enabled: true},
+		"scim":  {ID: "scim", Enabled: true, SCIM: &config.SCIMConfig{DBAlias: "db", Token: "scim-token"}},
+	}
+	if err := m.SetConfig(auths); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	sessions := new(mockSessions)
+	m.SetSessionsModule(sessions)
+	ctx := context.Background()
+
+	if status, _ := m.SCIMAuthenticate(ctx, "wrong"); status != 401 {
+		t.Fatalf("SCIMAuthenticate() with wrong token status = %d, want 401", status)
+	}
+
+	_, user, err := m.SCIMCreateUser(ctx, "project", &model.SCIMUser{UserName: "a@b.com", DisplayName: "A", Password: "secret"})
+	if err != nil {
+		t.Fatalf("SCIMCreateUser() error = %v", err)
+	}
+	if status, _, _ := m.SCIMCreateUser(ctx, "project", &model.SCIMUser{UserName: "a@b.com"}); status != 409 {
+		t.Fatalf("SCIMCreateUser() of existing user status = %d, want 409", status)
+	}
+
+	_, group, err := m.SCIMCreateGroup(ctx, "project", &model.SCIMGroup{DisplayName: "admins", Members: []model.SCIMValue{{Value: user.ID}}})
+	if err != nil {
+		t.Fatalf("SCIMCreateGroup() error = %v", err)
+	}
+	_, list, err := m.SCIMListUsers(ctx, "project", `userName eq "a@b.com"`, 1, -1)
+	if err != nil || list.TotalResults != 1 {
+		t.Fatalf("SCIMListUsers() = %v error = %v", list, err)
+	}
+	if groups := list.Resources.([]*model.SCIMUser)[0].Groups; len(groups) != 1 || groups[0].Display != "admins" {
+		t.Errorf("SCIMListUsers() returned groups %v, want admins", groups)
+	}
+
+	// The group membership is issued as a claim
+	_, result, err := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret")
+	if err != nil {
+		t.Fatalf("EmailSignIn() error = %v", err)
+	}
+	claims, _ := mockAuth{}.ParseToken(ctx, result["token"].(string))
+	if !reflect.DeepEqual(claims["groups"], []interface{}{"admins"}) {
+		t.Errorf("EmailSignIn() issued claims %v, want groups claim", claims)
+	}
+
+	// Renaming the group renames it for its members
+	patch := &model.SCIMPatchRequest{Operations: []*model.SCIMPatchOperation{{Op: "replace", Path: "displayName", Value: "owners"}}}
+	if _, _, err := m.SCIMPatchGroup(ctx, "project", group.ID, patch); err != nil {
+		t.Fatalf("SCIMPatchGroup() error = %v", err)
+	}
+	if crud.users[0][fieldGroups] != "owners" {
+		t.Errorf("SCIMPatchGroup() left groups of user as %v, want owners", crud.users[0][fieldGroups])
+	}
+
+	// Deactivated users can't sign in and are signed out
+	patch = &model.SCIMPatchRequest{Operations: []*model.SCIMPatchOperation{{Op: "replace", Path: "active", Value: false}}}
+	if _, _, err := m.SCIMPatchUser(ctx, "project", user.ID, patch); err != nil {
+		t.Fatalf("SCIMPatchUser() error = %v", err)
+	}
+	if status, _, _ := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret"); status != 403 {
+		t.Errorf("EmailSignIn() of deactivated user status = %d, want 403", status)
+	}
+	if !reflect.DeepEqual(sessions.revoked, []string{user.ID}) {
+		t.Errorf("SCIMPatchUser() revoked sessions of %v, want the deactivated user", sessions.revoked)
+	}
+
+	// Deleting the user removes it from its groups
+	if _, err := m.SCIMDeleteUser(ctx, "project", user.ID); err != nil {
+		t.Fatalf("SCIMDeleteUser() error = %v", err)
+	}
+	if _, g, err := m.SCIMGetGroup(ctx, "project", group.ID); err != nil || len(g.Members) != 0 {
+		t.Errorf("SCIMGetGroup() after deleting its member = %v error = %v", g, err)
+	}
+	if status, _, _ := m.SCIMGetUser(ctx, "project", user.ID); status != 404 {
+		t.Errorf("SCIMGetUser() of deleted user status = %d, want 404", status)
+	}
+}
+
+func Test_getUserGroupNames(t *testing.T) {
+	user := map[string]interface{}{fieldGroups: "admins,devs"}
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		after  map[string]interface{}
+		want   []string
+	}{
+		{name: "added to a new group", after: map[string]interface{}{"name": "ops", fieldMembers: "1,2"}, want: []string{"admins", "devs", "ops"}},
+		{name: "group renamed", before: map[string]interface{}{"name": "devs", fieldMembers: "1"}, after: map[string]interface{}{"name": "engineers", fieldMembers: "2,1"}, want: []string{"admins", "engineers"}},
+		{name: "removed from a group", before: map[string]interface{}{"name": "devs", fieldMembers: "1"}, after: map[string]interface{}{"name": "devs", fieldMembers: "2"}, want: []string{"admins"}},
+		{name: "group deleted", before: map[string]interface{}{"name": "admins", fieldMembers: "1"}, want: []string{"devs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getUserGroupNames(user, "1", tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getUserGroupNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModule_SCIMListUsers_pagination(t *testing.T) {
+	m, crud, _ := newTestModule(t, nil)
+	auths := config.Auths{"scim": {ID: "scim", Enabled: true, SCIM: &config.SCIMConfig{DBAlias: "db", Token: "scim-token"}}}
+	if err := m.SetConfig(auths); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	for _, id := range []string{"3", "1", "2"} {
+		crud.users = append(crud.users, map[string]interface{}{"id": id, "email": id + "@b.com", fieldGroups: "group" + id})
+		crud.groups = append(crud.groups, map[string]interface{}{"id": "g" + id, "name": "group" + id, fieldMembers: id})
+	}
+
+	tests := []struct {
+		name       string
+		startIndex int
+		count      int
+		want       []string
+	}{
+		{name: "first page", startIndex: 1, count: 2, want: []string{"1", "2"}},
+		{name: "second page", startIndex: 3, count: 2, want: []string{"3"}},
+		{name: "past the end", startIndex: 4, count: 2, want: []string{}},
+		{name: "only the total", startIndex: 1, count: 0, want: []string{}},
+		{name: "all after the start index", startIndex: 2, count: -1, want: []string{"2", "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, list, err := m.SCIMListUsers(context.Background(), "project", "", tt.startIndex, tt.count)
+			if err != nil {
+				t.Fatalf("SCIMListUsers() error = %v", err)
+			}
+			if list.TotalResults != 3 || list.StartIndex != tt.startIndex || list.ItemsPerPage != len(tt.want) {
+				t.Errorf("SCIMListUsers() = %+v, want %d users starting at %d of 3", list, len(tt.want), tt.startIndex)
+			}
+			got := []string{}
+			for _, u := range list.Resources.([]*model.SCIMUser) {
+				if len(u.Groups) != 1 || u.Groups[0].Value != "g"+u.ID {
+					t.Errorf("SCIMListUsers() returned groups %v of user (%s), want its own group", u.Groups, u.ID)
+				}
+				got = append(got, u.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SCIMListUsers() returned users %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package userman
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// The purposes of the tokens mailed to the users, and of the challenge returned on signing in with two factor
+// authentication
+const (
+	tokenPurposeVerify    = "verify"
+	tokenPurposeReset     = "reset"
+	tokenPurposeTwoFactor = "2fa"
+)
+
+// emailToken is the payload of the tokens mailed to the users. The tokens are signed with a key derived from the aes
+// key of the project, hence they can't be used as the JWT tokens of the project and vice versa.
+type emailToken struct {
+	Purpose string `json:"purpose"`
+	ID      string `json:"id"`
+	Email   string `json:"email"`
+	Exp     int64  `json:"exp"`
+
+	// Fingerprint is the fingerprint of the password hash of the user when the token was created. It makes the
+	// password reset tokens single use since they stop matching once the password changes.
+	Fingerprint string `json:"fp,omitempty"`
+}
+
+func createEmailToken(aesKey []byte, t *emailToken) (string, error) {
+	if len(aesKey) == 0 {
+		return "", errors.New("aes key of the project is not set")
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signEmailToken(aesKey, payload), nil
+}
+
+// parseEmailToken verifies the signature, purpose and expiry of a token and returns its payload
+func parseEmailToken(aesKey []byte, token, purpose string, now time.Time) (*emailToken, error) {
+	if len(aesKey) == 0 {
+		return nil, errors.New("aes key of the project is not set")
+	}
+	arr := strings.Split(token, ".")
+	if len(arr) != 2 || !hmac.Equal([]byte(arr[1]), []byte(signEmailToken(aesKey, arr[0]))) {
+		return nil, errors.New("invalid token provided")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(arr[0])
+	if err != nil {
+		return nil, errors.New("invalid token provided")
+	}
+	t := new(emailToken)
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, errors.New("invalid token provided")
+	}
+	if t.Purpose != purpose {
+		return nil, errors.New("token provided is not meant for this operation")
+	}
+	if now.Unix() > t.Exp {
+		return nil, errors.New("token provided has expired")
+	}
+	return t, nil
+}
+
+func signEmailToken(aesKey []byte, payload string) string {
+	// The key is derived so that the signatures can't be confused with the ones made with the aes key elsewhere
+	derived := hmac.New(sha256.New, aesKey)
+	_, _ = derived.Write([]byte("userman-email-token"))
+
+	mac := hmac.New(sha256.New, derived.Sum(nil))
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// passwordFingerprint returns a fingerprint of the password hash which doesn't reveal the hash
+func passwordFingerprint(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
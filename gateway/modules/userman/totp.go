@@ -0,0 +1,132 @@
+package userman
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpPeriod is the time in seconds a code is valid for
+	totpPeriod = 30
+
+	// totpDigits is the number of digits of a code
+	totpDigits = 6
+
+	// totpSkew is the number of periods a code is accepted for before and after its own, to allow for clock drift
+	totpSkew = 1
+
+	// defaultBackupCodes is the number of backup codes given on enrollment
+	defaultBackupCodes = 10
+
+	// backupCodeAlphabet leaves out the characters which are easily confused with each other
+	backupCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateTOTPSecret returns a random base32 encoded secret of 160 bits as recommended by RFC 4226
+func generateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// getTOTPCode returns the code of the secret for a time step as described in RFC 6238
+func getTOTPCode(secret []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	_, _ = mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// verifyTOTP checks the code against the secret and returns the time step it belongs to. Codes of the steps up to
+// the last one used are rejected so that a code can't be replayed.
+func verifyTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(getTOTPCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// getTOTPURI returns the provisioning uri of the secret. The authenticator apps enroll by scanning it as a qr code.
+func getTOTPURI(issuer, account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprintf("%d", totpDigits))
+	q.Set("period", fmt.Sprintf("%d", totpPeriod))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// generateBackupCodes returns the backup codes to be given to the user along with their hashes to be stored
+func generateBackupCodes(n int) ([]string, string, error) {
+	codes := make([]string, n)
+	hashes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+		for j := range b {
+			b[j] = backupCodeAlphabet[int(b[j])%len(backupCodeAlphabet)]
+		}
+		codes[i] = string(b[:5]) + "-" + string(b[5:])
+		hashes[i] = hashBackupCode(codes[i])
+	}
+	return codes, strings.Join(hashes, ","), nil
+}
+
+// useBackupCode checks the code against the hashes of the unused backup codes. The hashes remaining after using up
+// the code are returned.
+func useBackupCode(hashes, code string) (string, bool) {
+	if hashes == "" {
+		return "", false
+	}
+
+	hash := hashBackupCode(code)
+	arr := strings.Split(hashes, ",")
+	for i, h := range arr {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			return strings.Join(append(arr[:i:i], arr[i+1:]...), ","), true
+		}
+	}
+	return hashes, false
+}
+
+// hashBackupCode hashes the code ignoring its case and separators. The codes are random enough to not need a salt.
+func hashBackupCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,301 @@
+package userman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// The fields of the users collection holding the state of two factor authentication
+const (
+	fieldTOTPSecret   = "totp_secret"
+	fieldTOTPEnabled  = "totp_enabled"
+	fieldTOTPLastStep = "totp_last_step"
+	fieldBackupCodes  = "backup_codes"
+)
+
+// twoFactorChallengeTTL is the time a user has to provide the second factor after the password
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// EnrollTwoFactor starts the enrollment of the user having the token in two factor authentication. The secret is
+// returned along with its provisioning uri, which the client shows as a qr code to be scanned by an authenticator
+// app. Two factor authentication is enabled once the user confirms the enrollment with a code.
+func (m *Module) EnrollTwoFactor(ctx context.Context, token, dbAlias, project string) (int, map[string]interface{}, error) {
+	c := m.getTwoFactorConfig()
+	if c == nil {
+		return http.StatusNotFound, nil, errors.New("Two factor authentication is not enabled")
+	}
+
+	user, find, status, err := m.getTokenUser(ctx, token, dbAlias, project)
+	if err != nil {
+		return status, nil, err
+	}
+	if user[fieldTOTPEnabled] == true {
+		return http.StatusConflict, nil, errors.New("Two factor authentication is already enabled for the user")
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate totp secret", err, nil)
+	}
+	m.RLock()
+	sealed, err := utils.Seal(m.aesKey, secret)
+	m.RUnlock()
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to encrypt totp secret", err, nil)
+	}
+
+	update := map[string]interface{}{"$set": map[string]interface{}{fieldTOTPSecret: sealed, fieldTOTPEnabled: false}}
+	if err := m.updateUser(ctx, dbAlias, project, find, update); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to save totp secret", err, nil)
+	}
+
+	issuer := c.Issuer
+	if issuer == "" {
+		issuer = project
+	}
+	return http.StatusOK, map[string]interface{}{"secret": secret, "uri": getTOTPURI(issuer, fmt.Sprintf("%v", user["email"]), secret)}, nil
+}
+
+// ConfirmTwoFactor enables two factor authentication for the user having the token once the code generated by the
+// authenticator app matches. The backup codes are returned only this once.
+func (m *Module) ConfirmTwoFactor(ctx context.Context, token, dbAlias, project, code string) (int, map[string]interface{}, error) {
+	c := m.getTwoFactorConfig()
+	if c == nil {
+		return http.StatusNotFound, nil, errors.New("Two factor authentication is not enabled")
+	}
+
+	user, find, status, err := m.getTokenUser(ctx, token, dbAlias, project)
+	if err != nil {
+		return status, nil, err
+	}
+	if user[fieldTOTPEnabled] == true {
+		return http.StatusConflict, nil, errors.New("Two factor authentication is already enabled for the user")
+	}
+	secret, err := m.getTOTPSecret(user)
+	if err != nil {
+		return http.StatusBadRequest, nil, errors.New("Enrollment in two factor authentication has not been started")
+	}
+	step, ok := verifyTOTP(secret, code, time.Now(), 0)
+	if !ok {
+		return http.StatusBadRequest, nil, errors.New("Invalid code provided")
+	}
+
+	n := c.BackupCodes
+	if n == 0 {
+		n = defaultBackupCodes
+	}
+	codes, hashes, err := generateBackupCodes(n)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to generate backup codes", err, nil)
+	}
+
+	update := map[string]interface{}{"$set": map[string]interface{}{fieldTOTPEnabled: true, fieldTOTPLastStep: step, fieldBackupCodes: hashes}}
+	if err := m.updateUser(ctx, dbAlias, project, find, update); err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to enable two factor authentication", err, nil)
+	}
+	return http.StatusOK, map[string]interface{}{"backupCodes": codes}, nil
+}
+
+// DisableTwoFactor disables two factor authentication for the user having the token. A code or a backup code must
+// be provided so that a stolen token isn't enough to disable it.
+func (m *Module) DisableTwoFactor(ctx context.Context, token, dbAlias, project, code string) (int, map[string]interface{}, error) {
+	if m.getTwoFactorConfig() == nil {
+		return http.StatusNotFound, nil, errors.New("Two factor authentication is not enabled")
+	}
+
+	user, find, status, err := m.getTokenUser(ctx, token, dbAlias, project)
+	if err != nil {
+		return status, nil, err
+	}
+	if user[fieldTOTPEnabled] != true {
+		return http.StatusBadRequest, nil, errors.New("Two factor authentication is not enabled for the user")
+	}
+	_, unused, ok := m.checkSecondFactor(user, code, time.Now())
+	if !ok {
+		return http.StatusUnauthorized, nil, errors.New("Invalid code provided")
+	}
+
+	update := map[string]interface{}{"$set": map[string]interface{}{fieldTOTPEnabled: false, fieldTOTPSecret: "", fieldTOTPLastStep: 0, fieldBackupCodes: ""}}
+	n, err := m.updateUserCount(ctx, dbAlias, project, mergeFind(find, unused), update)
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to disable two factor authentication", err, nil)
+	}
+	if n == 0 {
+		return http.StatusUnauthorized, nil, errors.New("Invalid code provided")
+	}
+	return http.StatusOK, map[string]interface{}{}, nil
+}
+
+// EmailSignInTwoFactor completes the sign in of a user who has enrolled in two factor authentication. The challenge
+// is the one returned on providing the password. Failed codes count towards the lockout of the account.
+func (m *Module) EmailSignInTwoFactor(ctx context.Context, dbAlias, project, challenge, code string) (int, map[string]interface{}, error) {
+	if m.getTwoFactorConfig() == nil {
+		return http.StatusNotFound, nil, errors.New("Two factor authentication is not enabled")
+	}
+
+	m.RLock()
+	aesKey := m.aesKey
+	m.RUnlock()
+
+	t, err := parseEmailToken(aesKey, challenge, tokenPurposeTwoFactor, time.Now())
+	if err != nil {
+		return http.StatusUnauthorized, nil, err
+	}
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	find := map[string]interface{}{idField: t.ID}
+
+	// The challenge stops working if the password changes in the meantime
+	user, err := m.readUser(ctx, dbAlias, project, find)
+	if err != nil {
+		return http.StatusUnauthorized, nil, errors.New("Sign in challenge is no longer valid")
+	}
+	if hash, _ := user["pass"].(string); user["email"] != t.Email || passwordFingerprint(hash) != t.Fingerprint || user[fieldTOTPEnabled] != true {
+		return http.StatusUnauthorized, nil, errors.New("Sign in challenge is no longer valid")
+	}
+	if !isActive(user) {
+		return http.StatusForbidden, nil, errors.New("User has been deactivated")
+	}
+
+	var lockout *config.AccountLockout
+	if c := m.getEmailConfig(); c != nil {
+		lockout = c.Lockout
+	}
+	if lockout != nil && isLocked(user, time.Now()) {
+		return http.StatusForbidden, nil, errors.New("Account is locked due to too many failed sign in attempts. Try again later")
+	}
+
+	set, unused, ok := m.checkSecondFactor(user, code, time.Now())
+	if !ok {
+		if lockout != nil {
+			m.recordFailedSignIn(ctx, lockout, dbAlias, project, find)
+		}
+		return http.StatusUnauthorized, nil, errors.New("Invalid code provided")
+	}
+	if lockout != nil {
+		set[fieldFailedAttempts] = 0
+	}
+
+	// The use of the code is recorded before the token is issued so that it can't be replayed. The update only goes
+	// through if no concurrent request has used the code in the meantime.
+	n, err := m.updateUserCount(ctx, dbAlias, project, mergeFind(find, unused), map[string]interface{}{"$set": set})
+	if err != nil {
+		return http.StatusInternalServerError, nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to record use of second factor", err, nil)
+	}
+	if n == 0 {
+		return http.StatusUnauthorized, nil, errors.New("Invalid code provided")
+	}
+
+	sanitizeUser(user)
+	claims := map[string]interface{}{"email": user["email"], "id": user[idField], "role": user["role"], "mfa": true}
+	setGroupsClaim(claims, user)
+	token, err := m.createToken(ctx, claims)
+	if err != nil {
+		return http.StatusInternalServerError, nil, errors.New("Failed to create a JWT token")
+	}
+	return http.StatusOK, map[string]interface{}{"user": user, "token": token}, nil
+}
+
+// createTwoFactorChallenge returns the challenge to be completed with the second factor after the password
+func (m *Module) createTwoFactorChallenge(dbAlias string, user map[string]interface{}) (string, error) {
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		return "", err
+	}
+	hash, _ := user["pass"].(string)
+
+	m.RLock()
+	defer m.RUnlock()
+
+	t := &emailToken{Purpose: tokenPurposeTwoFactor, ID: fmt.Sprintf("%v", user[idField]), Email: fmt.Sprintf("%v", user["email"]), Exp: time.Now().Add(twoFactorChallengeTTL).Unix(), Fingerprint: passwordFingerprint(hash)}
+	return createEmailToken(m.aesKey, t)
+}
+
+// checkSecondFactor checks a totp code or a backup code of the user. It returns the fields to be set to record the
+// use of the code along with the current values of those fields, which must still hold when the use is recorded.
+func (m *Module) checkSecondFactor(user map[string]interface{}, code string, now time.Time) (map[string]interface{}, map[string]interface{}, bool) {
+	if secret, err := m.getTOTPSecret(user); err == nil {
+		if step, ok := verifyTOTP(secret, code, now, getInt(user, fieldTOTPLastStep)); ok {
+			return map[string]interface{}{fieldTOTPLastStep: step}, map[string]interface{}{fieldTOTPLastStep: user[fieldTOTPLastStep]}, true
+		}
+	}
+
+	hashes, _ := user[fieldBackupCodes].(string)
+	if remaining, ok := useBackupCode(hashes, code); ok {
+		return map[string]interface{}{fieldBackupCodes: remaining}, map[string]interface{}{fieldBackupCodes: hashes}, true
+	}
+	return nil, nil, false
+}
+
+// mergeFind returns a find clause matching both the provided find clauses
+func mergeFind(find, other map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(find)+len(other))
+	for k, v := range find {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// getTOTPSecret returns the decrypted totp secret of the user
+func (m *Module) getTOTPSecret(user map[string]interface{}) (string, error) {
+	sealed, _ := user[fieldTOTPSecret].(string)
+	if sealed == "" {
+		return "", errors.New("user has no totp secret")
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+	return utils.Unseal(m.aesKey, sealed)
+}
+
+// getTokenUser returns the user the token was issued to along with the where clause matching the user
+func (m *Module) getTokenUser(ctx context.Context, token, dbAlias, project string) (map[string]interface{}, map[string]interface{}, int, error) {
+	claims, err := m.auth.ParseToken(ctx, token)
+	if err != nil {
+		return nil, nil, http.StatusUnauthorized, err
+	}
+	id, ok := claims["id"].(string)
+	if !ok || id == "" {
+		return nil, nil, http.StatusUnauthorized, errors.New("Token does not identify a user")
+	}
+
+	idField, err := m.getIDField(dbAlias)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	find := map[string]interface{}{idField: id}
+	user, err := m.readUser(ctx, dbAlias, project, find)
+	if err != nil {
+		return nil, nil, http.StatusNotFound, errors.New("User not found")
+	}
+	return user, find, http.StatusOK, nil
+}
+
+// getTwoFactorConfig returns the config of two factor authentication. It is nil unless enabled.
+func (m *Module) getTwoFactorConfig() *config.TwoFactorAuth {
+	c := m.getEmailConfig()
+	if c == nil || c.TwoFactor == nil || !c.TwoFactor.Enabled {
+		return nil
+	}
+	return c.TwoFactor
+}
+
+// sanitizeUser removes the password and the internal state of the flows from a user before it is returned
+func sanitizeUser(user map[string]interface{}) {
+	for _, field := range []string{"pass", fieldFailedAttempts, fieldLockedUntil, fieldTOTPSecret, fieldTOTPLastStep, fieldBackupCodes} {
+		delete(user, field)
+	}
+}
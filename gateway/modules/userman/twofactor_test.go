@@ -0,0 +1,142 @@
+package userman
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func Test_verifyTOTP(t *testing.T) {
+	// The test vectors of RFC 6238 truncated to 6 digits
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		name     string
+		code     string
+		now      int64
+		lastStep int64
+		wantStep int64
+		want     bool
+	}{
+		{name: "code of the current step", code: "287082", now: 59, wantStep: 1, want: true},
+		{name: "code of another vector", code: "081804", now: 1111111109, wantStep: 37037036, want: true},
+		{name: "code of the previous step within skew", code: "287082", now: 89, wantStep: 1, want: true},
+		{name: "code outside skew", code: "287082", now: 150},
+		{name: "code already used", code: "287082", now: 59, lastStep: 1},
+		{name: "wrong code", code: "123456", now: 59},
+		{name: "code of wrong length", code: "28708", now: 59},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := verifyTOTP(secret, tt.code, time.Unix(tt.now, 0), tt.lastStep)
+			if ok != tt.want || step != tt.wantStep {
+				t.Errorf("verifyTOTP() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.want)
+			}
+		})
+	}
+}
+
+func Test_useBackupCode(t *testing.T) {
+	codes, hashes, err := generateBackupCodes(3)
+	if err != nil || len(codes) != 3 {
+		t.Fatalf("generateBackupCodes() = %v error = %v", codes, err)
+	}
+
+	remaining, ok := useBackupCode(hashes, strings.ToUpper(codes[1]))
+	if !ok || len(strings.Split(remaining, ",")) != 2 {
+		t.Fatalf("useBackupCode() = (%s, %v), want the other 2 codes", remaining, ok)
+	}
+	if _, ok := useBackupCode(remaining, codes[1]); ok {
+		t.Errorf("useBackupCode() accepted a code which was used up")
+	}
+	if _, ok := useBackupCode(remaining, codes[0]); !ok {
+		t.Errorf("useBackupCode() rejected an unused code")
+	}
+	if _, ok := useBackupCode("", codes[0]); ok {
+		t.Errorf("useBackupCode() accepted a code without any hashes")
+	}
+}
+
+func TestModule_TwoFactor(t *testing.T) {
+	m, crud, _ := newTestModule(t, &config.EmailAuthConfig{TwoFactor: &config.TwoFactorAuth{Enabled: true, BackupCodes: 2}})
+	ctx := context.Background()
+
+	_, result, err := m.EmailSignUp(ctx, "db", "project", "a@b.com", "a", "secret", "user")
+	if err != nil {
+		t.Fatalf("EmailSignUp() error = %v", err)
+	}
+	token := result["token"].(string)
+
+	_, result, err = m.EnrollTwoFactor(ctx, token, "db", "project")
+	if err != nil || !strings.HasPrefix(result["uri"].(string), "otpauth://totp/project:a@b.com?") {
+		t.Fatalf("EnrollTwoFactor() = %v error = %v", result, err)
+	}
+	key, _ := totpEncoding.DecodeString(result["secret"].(string))
+	step := time.Now().Unix() / totpPeriod
+
+	// The second factor isn't required until the enrollment is confirmed
+	if _, result, err := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret"); err != nil || result["token"] == nil {
+		t.Fatalf("EmailSignIn() before confirming enrollment = %v error = %v", result, err)
+	}
+	if status, _, _ := m.ConfirmTwoFactor(ctx, token, "db", "project", getTOTPCode(key, step+5)); status != 400 {
+		t.Fatalf("ConfirmTwoFactor() with wrong code status = %d, want 400", status)
+	}
+	_, result, err = m.ConfirmTwoFactor(ctx, token, "db", "project", getTOTPCode(key, step))
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor() error = %v", err)
+	}
+	backupCodes := result["backupCodes"].([]string)
+
+	signIn := func() string {
+		_, result, err := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret")
+		if err != nil || result["twoFactorRequired"] != true || result["token"] != nil {
+			t.Fatalf("EmailSignIn() = %v error = %v, want a two factor challenge", result, err)
+		}
+		return result["challenge"].(string)
+	}
+
+	// The code used to confirm the enrollment can't be replayed
+	if status, _, _ := m.EmailSignInTwoFactor(ctx, "db", "project", signIn(), getTOTPCode(key, step)); status != 401 {
+		t.Fatalf("EmailSignInTwoFactor() with replayed code status = %d, want 401", status)
+	}
+	_, result, err = m.EmailSignInTwoFactor(ctx, "db", "project", signIn(), getTOTPCode(key, step+1))
+	if err != nil {
+		t.Fatalf("EmailSignInTwoFactor() error = %v", err)
+	}
+	claims, _ := mockAuth{}.ParseToken(ctx, result["token"].(string))
+	if claims["mfa"] != true {
+		t.Errorf("EmailSignInTwoFactor() issued token with claims %v, want mfa claim", claims)
+	}
+	user := result["user"].(map[string]interface{})
+	if _, ok := user[fieldTOTPSecret]; ok {
+		t.Errorf("EmailSignInTwoFactor() returned the totp secret of the user")
+	}
+
+	// A code used by a concurrent request after it was checked can't be used again
+	challenge := signIn()
+	hashes := crud.users[0][fieldBackupCodes]
+	crud.beforeUpdate = func() { crud.users[0][fieldBackupCodes] = "used-concurrently" }
+	if status, _, _ := m.EmailSignInTwoFactor(ctx, "db", "project", challenge, backupCodes[0]); status != 401 {
+		t.Fatalf("EmailSignInTwoFactor() with code used concurrently status = %d, want 401", status)
+	}
+	crud.beforeUpdate = nil
+	crud.users[0][fieldBackupCodes] = hashes
+
+	// Backup codes work once
+	challenge = signIn()
+	if _, _, err := m.EmailSignInTwoFactor(ctx, "db", "project", challenge, backupCodes[0]); err != nil {
+		t.Fatalf("EmailSignInTwoFactor() with backup code error = %v", err)
+	}
+	if status, _, _ := m.EmailSignInTwoFactor(ctx, "db", "project", challenge, backupCodes[0]); status != 401 {
+		t.Fatalf("EmailSignInTwoFactor() with used backup code status = %d, want 401", status)
+	}
+
+	if _, _, err := m.DisableTwoFactor(ctx, token, "db", "project", backupCodes[1]); err != nil {
+		t.Fatalf("DisableTwoFactor() error = %v", err)
+	}
+	if _, result, err := m.EmailSignIn(ctx, "db", "project", "a@b.com", "secret"); err != nil || result["token"] == nil {
+		t.Fatalf("EmailSignIn() after disabling two factor authentication = %v error = %v", result, err)
+	}
+}
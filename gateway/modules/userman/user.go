@@ -1,11 +1,15 @@
 package userman
 
 import (
+	"context"
 	"encoding/base64"
+	"fmt"
 	"sync"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/secrets"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 // Module is responsible for user management
@@ -17,23 +21,55 @@ type Module struct {
 
 	// auth module
 	aesKey []byte
+
+	resolveSecret utils.ResolveSecret
+	sendMail      func(ctx context.Context, c *config.MailConfig, to, subject, body string) error
+
+	sessions sessionsInterface
+}
+
+type sessionsInterface interface {
+	Create(ctx context.Context, userID string) (string, error)
+	RevokeAll(ctx context.Context, userID string) (int, error)
 }
 
 // Init creates a new instance of the user management object
 func Init(crud model.CrudUserInterface, auth model.AuthUserInterface) *Module {
-	return &Module{crud: crud, auth: auth}
+	m := &Module{crud: crud, auth: auth}
+	m.sendMail = m.sendMailWithProvider
+	return m
 }
 
 // SetConfig sets the config required by the user management module
-func (m *Module) SetConfig(auth config.Auths) {
+func (m *Module) SetConfig(auth config.Auths) error {
+	methods := make(map[string]*config.AuthStub, len(auth))
+	for _, v := range auth {
+		if v.ID == "email" && v.Email != nil {
+			if err := validateEmailConfig(v.Email); err != nil {
+				return err
+			}
+		}
+		if v.ID == "scim" && v.Enabled {
+			if err := validateSCIMConfig(v.SCIM); err != nil {
+				return err
+			}
+		}
+		methods[v.ID] = v
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
-	m.methods = make(map[string]*config.AuthStub, len(auth))
+	m.methods = methods
+	return nil
+}
+
+// SetResolveSecret sets the function to resolve secrets from external secret managers
+func (m *Module) SetResolveSecret(function utils.ResolveSecret) {
+	m.Lock()
+	defer m.Unlock()
 
-	for _, v := range auth {
-		m.methods[v.ID] = v
-	}
+	m.resolveSecret = function
 }
 
 // IsActive shows if a given method is active
@@ -65,3 +101,53 @@ func (m *Module) SetProjectAESKey(aesKey string) error {
 	m.aesKey = decodedAESKey
 	return nil
 }
+
+// SetSessionsModule sets the module tracking the sessions of the tokens issued
+func (m *Module) SetSessionsModule(sessions sessionsInterface) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.sessions = sessions
+}
+
+// createToken creates a token with the claims. The token carries the id of its session in the sid claim if the
+// sessions are tracked.
+func (m *Module) createToken(ctx context.Context, claims map[string]interface{}) (string, error) {
+	m.RLock()
+	sessions := m.sessions
+	m.RUnlock()
+
+	if sessions != nil {
+		sid, err := sessions.Create(ctx, fmt.Sprintf("%v", claims["id"]))
+		if err != nil {
+			return "", err
+		}
+		if sid != "" {
+			claims["sid"] = sid
+		}
+	}
+	return m.auth.CreateToken(ctx, claims)
+}
+
+// getEmailConfig returns the config of the flows of the email sign in method. It is nil if none are configured.
+func (m *Module) getEmailConfig() *config.EmailAuthConfig {
+	m.RLock()
+	defer m.RUnlock()
+
+	s, p := m.methods["email"]
+	if !p || !s.Enabled {
+		return nil
+	}
+	return s.Email
+}
+
+func (m *Module) getSecret(ctx context.Context, value string) (string, error) {
+	m.RLock()
+	resolve := m.resolveSecret
+	m.RUnlock()
+
+	if secrets.IsReference(value) && resolve != nil {
+		return resolve(ctx, value)
+	}
+	return value, nil
+}
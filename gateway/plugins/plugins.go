@@ -0,0 +1,105 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// registry holds all the plugins registered at build time
+type registry struct {
+	lock sync.RWMutex
+
+	authProviders    []AuthProvider
+	crudInterceptors []CrudInterceptor
+	routers          []Router
+}
+
+var defaultRegistry = &registry{}
+
+// RegisterAuthProvider registers a custom auth provider. It must be called before the server is started,
+// typically from an init function of the embedding program.
+func RegisterAuthProvider(provider AuthProvider) error {
+	defaultRegistry.lock.Lock()
+	defer defaultRegistry.lock.Unlock()
+
+	for _, p := range defaultRegistry.authProviders {
+		if p.Name() == provider.Name() {
+			return fmt.Errorf("auth provider (%s) has already been registered", provider.Name())
+		}
+	}
+	defaultRegistry.authProviders = append(defaultRegistry.authProviders, provider)
+	return nil
+}
+
+// RegisterCrudInterceptor registers a custom crud interceptor
+func RegisterCrudInterceptor(interceptor CrudInterceptor) error {
+	defaultRegistry.lock.Lock()
+	defer defaultRegistry.lock.Unlock()
+
+	for _, i := range defaultRegistry.crudInterceptors {
+		if i.Name() == interceptor.Name() {
+			return fmt.Errorf("crud interceptor (%s) has already been registered", interceptor.Name())
+		}
+	}
+	defaultRegistry.crudInterceptors = append(defaultRegistry.crudInterceptors, interceptor)
+	return nil
+}
+
+// RegisterRouter registers a function which adds custom routes to the gateway router
+func RegisterRouter(router Router) {
+	defaultRegistry.lock.Lock()
+	defer defaultRegistry.lock.Unlock()
+
+	defaultRegistry.routers = append(defaultRegistry.routers, router)
+}
+
+// ParseToken tries to parse the token using the registered auth providers
+func ParseToken(ctx context.Context, token string) (map[string]interface{}, bool, error) {
+	defaultRegistry.lock.RLock()
+	defer defaultRegistry.lock.RUnlock()
+
+	for _, p := range defaultRegistry.authProviders {
+		claims, ok, err := p.ParseToken(ctx, token)
+		if !ok {
+			continue
+		}
+		return claims, true, err
+	}
+	return nil, false, nil
+}
+
+// BeforeCrud invokes the before hook of all registered crud interceptors. The first error aborts the chain.
+func BeforeCrud(ctx context.Context, req *CrudRequest) error {
+	defaultRegistry.lock.RLock()
+	defer defaultRegistry.lock.RUnlock()
+
+	for _, i := range defaultRegistry.crudInterceptors {
+		if err := i.BeforeCrud(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterCrud invokes the after hook of all registered crud interceptors
+func AfterCrud(ctx context.Context, req *CrudRequest, result interface{}, err error) {
+	defaultRegistry.lock.RLock()
+	defer defaultRegistry.lock.RUnlock()
+
+	for _, i := range defaultRegistry.crudInterceptors {
+		i.AfterCrud(ctx, req, result, err)
+	}
+}
+
+// AddRoutes adds the routes of all registered routers to the provided router
+func AddRoutes(router *mux.Router) {
+	defaultRegistry.lock.RLock()
+	defer defaultRegistry.lock.RUnlock()
+
+	for _, r := range defaultRegistry.routers {
+		r(router)
+	}
+}
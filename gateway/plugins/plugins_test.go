@@ -0,0 +1,101 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type mockAuthProvider struct {
+	name   string
+	prefix string
+	claims map[string]interface{}
+}
+
+func (p *mockAuthProvider) Name() string { return p.name }
+
+func (p *mockAuthProvider) ParseToken(_ context.Context, token string) (map[string]interface{}, bool, error) {
+	if len(token) < len(p.prefix) || token[:len(p.prefix)] != p.prefix {
+		return nil, false, nil
+	}
+	return p.claims, true, nil
+}
+
+type mockCrudInterceptor struct {
+	name  string
+	err   error
+	calls []string
+}
+
+func (i *mockCrudInterceptor) Name() string { return i.name }
+
+func (i *mockCrudInterceptor) BeforeCrud(_ context.Context, req *CrudRequest) error {
+	i.calls = append(i.calls, "before:"+string(req.Op))
+	return i.err
+}
+
+func (i *mockCrudInterceptor) AfterCrud(_ context.Context, req *CrudRequest, _ interface{}, _ error) {
+	i.calls = append(i.calls, "after:"+string(req.Op))
+}
+
+func TestParseToken(t *testing.T) {
+	defaultRegistry = &registry{}
+	if err := RegisterAuthProvider(&mockAuthProvider{name: "p1", prefix: "p1.", claims: map[string]interface{}{"id": "1"}}); err != nil {
+		t.Fatalf("RegisterAuthProvider() unexpected error = %v", err)
+	}
+	if err := RegisterAuthProvider(&mockAuthProvider{name: "p2", prefix: "p2.", claims: map[string]interface{}{"id": "2"}}); err != nil {
+		t.Fatalf("RegisterAuthProvider() unexpected error = %v", err)
+	}
+	if err := RegisterAuthProvider(&mockAuthProvider{name: "p1"}); err == nil {
+		t.Fatalf("RegisterAuthProvider() expected error for duplicate provider")
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		want   map[string]interface{}
+		wantOk bool
+	}{
+		{name: "first provider", token: "p1.token", want: map[string]interface{}{"id": "1"}, wantOk: true},
+		{name: "second provider", token: "p2.token", want: map[string]interface{}{"id": "2"}, wantOk: true},
+		{name: "unknown token", token: "p3.token", want: nil, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := ParseToken(context.Background(), tt.token)
+			if err != nil {
+				t.Errorf("ParseToken() unexpected error = %v", err)
+			}
+			if ok != tt.wantOk {
+				t.Errorf("ParseToken() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseToken() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeforeCrud(t *testing.T) {
+	defaultRegistry = &registry{}
+	i1 := &mockCrudInterceptor{name: "i1", err: errors.New("denied")}
+	i2 := &mockCrudInterceptor{name: "i2"}
+	_ = RegisterCrudInterceptor(i1)
+	_ = RegisterCrudInterceptor(i2)
+
+	req := &CrudRequest{Op: model.Create}
+	if err := BeforeCrud(context.Background(), req); err == nil {
+		t.Errorf("BeforeCrud() expected error")
+	}
+	AfterCrud(context.Background(), req, nil, nil)
+
+	if want := []string{"before:create", "after:create"}; !reflect.DeepEqual(i1.calls, want) {
+		t.Errorf("BeforeCrud() i1 calls = %v, want %v", i1.calls, want)
+	}
+	if want := []string{"after:create"}; !reflect.DeepEqual(i2.calls, want) {
+		t.Errorf("BeforeCrud() i2 calls = %v, want %v", i2.calls, want)
+	}
+}
@@ -0,0 +1,51 @@
+package plugins
+
+import (
+	"context"
+
+	"github.com/gorilla/mux"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// AuthProvider is used to verify tokens which were not issued by any of the secrets configured in space cloud.
+// Providers are consulted in the order they were registered, only after the native jwt verification fails.
+type AuthProvider interface {
+	// Name returns the unique name of the auth provider
+	Name() string
+
+	// ParseToken verifies the token and returns its claims. The boolean must be false if the provider
+	// does not recognise the token, in which case the next provider is consulted.
+	ParseToken(ctx context.Context, token string) (map[string]interface{}, bool, error)
+}
+
+// CrudRequest describes a database operation passed to the crud interceptors
+type CrudRequest struct {
+	Project string
+	DBAlias string
+	Col     string
+	Op      model.OperationType
+
+	// Payload is one of *model.CreateRequest, *model.ReadRequest, *model.UpdateRequest, *model.DeleteRequest,
+	// *model.AggregateRequest, *model.BatchRequest or *model.PreparedQueryRequest. Col is the id of the prepared
+	// query for prepared queries and is empty for batches.
+	Payload interface{}
+	Params  model.RequestParams
+}
+
+// CrudInterceptor is invoked around every crud operation performed by the crud module
+type CrudInterceptor interface {
+	// Name returns the unique name of the interceptor
+	Name() string
+
+	// BeforeCrud is invoked before the operation hits the database. The payload may be mutated in place.
+	// Returning an error aborts the operation.
+	BeforeCrud(ctx context.Context, req *CrudRequest) error
+
+	// AfterCrud is invoked once the operation has completed along with its result and error (if any)
+	AfterCrud(ctx context.Context, req *CrudRequest, result interface{}, err error)
+}
+
+// Router registers custom http routes on the gateway router. Routes are added before the ingress routing
+// handler, so they take precedence over ingress routes but not over the built in space cloud routes.
+type Router func(router *mux.Router)
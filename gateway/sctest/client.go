@@ -0,0 +1,168 @@
+package sctest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// Error is returned by the clients when the gateway responds with an error
+type Error struct {
+	Status int
+	*model.ErrorResponse
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("space cloud responded with status (%d) - %s", e.Status, e.ErrorResponse.Error)
+}
+
+// do makes a request to the gateway and decodes the result field of the response into the result if provided
+func do(ctx context.Context, c *http.Client, method, url, token string, body, result interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res.Body)
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		errRes := new(model.ErrorResponse)
+		if err := json.Unmarshal(data, errRes); err != nil || errRes.Error == "" {
+			errRes.Error = string(data)
+		}
+		return &Error{Status: res.StatusCode, ErrorResponse: errRes}
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, &struct {
+		Result interface{} `json:"result"`
+	}{Result: result})
+}
+
+// Client makes requests to the apis of a project
+type Client struct {
+	http    *http.Client
+	url     string
+	project string
+	token   string
+}
+
+// Do makes a request to a path of the apis of the project like /crud/db/users/read. The result field of the
+// response is decoded into the result if provided.
+func (c *Client) Do(ctx context.Context, method, path string, body, result interface{}) error {
+	return do(ctx, c.http, method, fmt.Sprintf("%s/v1/api/%s%s", c.url, c.project, path), c.token, body, result)
+}
+
+// DB returns a client of the crud apis of a database
+func (c *Client) DB(dbAlias string) *DBClient {
+	return &DBClient{client: c, dbAlias: dbAlias}
+}
+
+// DBClient makes requests to the crud apis of a database
+type DBClient struct {
+	client  *Client
+	dbAlias string
+}
+
+func (db *DBClient) do(ctx context.Context, col, op string, body, result interface{}) error {
+	return db.client.Do(ctx, http.MethodPost, fmt.Sprintf("/crud/%s/%s/%s", db.dbAlias, col, op), body, result)
+}
+
+// Create inserts the documents into the collection
+func (db *DBClient) Create(ctx context.Context, col string, docs ...map[string]interface{}) error {
+	return db.do(ctx, col, "create", &model.CreateRequest{Document: docs, Operation: utils.All}, nil)
+}
+
+// Read decodes the documents of the collection matching the find clause into the result, which must be a pointer
+// to a slice
+func (db *DBClient) Read(ctx context.Context, col string, find map[string]interface{}, result interface{}) error {
+	if find == nil {
+		find = map[string]interface{}{}
+	}
+	return db.do(ctx, col, "read", &model.ReadRequest{Find: find, Operation: utils.All}, result)
+}
+
+// Update applies the update to the documents of the collection matching the find clause
+func (db *DBClient) Update(ctx context.Context, col string, find, update map[string]interface{}) error {
+	return db.do(ctx, col, "update", &model.UpdateRequest{Find: find, Operation: utils.All, Update: update}, nil)
+}
+
+// Delete deletes the documents of the collection matching the find clause
+func (db *DBClient) Delete(ctx context.Context, col string, find map[string]interface{}) error {
+	if find == nil {
+		find = map[string]interface{}{}
+	}
+	return db.do(ctx, col, "delete", &model.DeleteRequest{Find: find, Operation: utils.All}, nil)
+}
+
+// AdminClient makes requests to the admin apis with the token of the admin user
+type AdminClient struct {
+	http  *http.Client
+	url   string
+	token string
+}
+
+// Do makes a request to a path of the admin apis like /v1/api/config/export. The result field of the response is
+// decoded into the result if provided.
+func (c *AdminClient) Do(ctx context.Context, method, path string, body, result interface{}) error {
+	return do(ctx, c.http, method, c.url+path, c.token, body, result)
+}
+
+// ImportConfig applies the config with the provided strategy
+func (c *AdminClient) ImportConfig(ctx context.Context, conf *config.Config, strategy string) (*model.ConfigImportResult, error) {
+	result := new(model.ConfigImportResult)
+	bundle := &model.ConfigBundle{Version: model.ConfigBundleVersion, Config: conf}
+	if err := c.Do(ctx, http.MethodPost, "/v1/api/config/import?strategy="+strategy, bundle, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LoadFixtures loads the fixtures of a project
+func (c *AdminClient) LoadFixtures(ctx context.Context, projectID string) (*model.FixturesResult, error) {
+	result := new(model.FixturesResult)
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/v1/api/config/projects/%s/fixtures/load", projectID), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TeardownFixtures removes the fixtures of a project
+func (c *AdminClient) TeardownFixtures(ctx context.Context, projectID string) (*model.FixturesResult, error) {
+	result := new(model.FixturesResult)
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/v1/api/config/projects/%s/fixtures/teardown", projectID), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,190 @@
+// Package sctest runs a complete space cloud gateway inside the process of a go test. The gateway keeps its config
+// in memory, uses the in memory broker instead of redis and serves the same routes and middlewares as a deployed
+// gateway, so that integration tests can be written without running any containers.
+//
+// Every gateway runs as a cluster of its own. The projects of all the gateways of a process share the in memory
+// broker of that process though.
+package sctest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/server"
+	"github.com/spaceuptech/space-cloud/gateway/utils/pubsub"
+)
+
+const (
+	defaultClusterID = "sctest"
+	adminUser        = "admin"
+	shutdownTimeout  = 10 * time.Second
+)
+
+// Options configure the gateway started by the harness
+type Options struct {
+	// Config is applied to the gateway once it has started. The resource ids of the config may belong to any cluster.
+	Config *config.Config
+	// ClusterID and NodeID default to sctest and a random id
+	ClusterID string
+	NodeID    string
+	// TestMode lets the projects load their fixtures. It is enabled by New.
+	TestMode bool
+}
+
+// Gateway is a gateway running inside the process
+type Gateway struct {
+	server     *server.Server
+	httpServer *httptest.Server
+	handler    http.Handler
+	adminToken string
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// New starts a gateway in test mode with the provided config. The test fails if the gateway cannot be started and
+// the gateway is shut down once the test completes.
+func New(t testing.TB, c *config.Config) *Gateway {
+	t.Helper()
+
+	g, err := Start(&Options{Config: c, TestMode: true})
+	if err != nil {
+		t.Fatalf("Unable to start space cloud - %v", err)
+	}
+	t.Cleanup(func() {
+		if err := g.Close(); err != nil {
+			t.Errorf("Unable to shut down space cloud - %v", err)
+		}
+	})
+	return g
+}
+
+// Start starts a gateway with the provided options. The gateway must be closed by the caller.
+func Start(opts *Options) (*Gateway, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	clusterID := opts.ClusterID
+	if clusterID == "" {
+		clusterID = defaultClusterID
+	}
+	nodeID := opts.NodeID
+	if nodeID == "" {
+		nodeID = "sctest-" + randomHex(4)
+	}
+
+	// The pub sub clients read the connection string from the environment
+	if os.Getenv("REDIS_CONN") == "" {
+		if err := os.Setenv("REDIS_CONN", pubsub.InMemoryConn); err != nil {
+			return nil, err
+		}
+	}
+
+	adminUserInfo := &config.AdminUser{User: adminUser, Pass: randomHex(16), Secret: randomHex(32)}
+	s, err := server.New(nodeID, clusterID, "memory", syncman.ClusterModeNone, "", false, adminUserInfo, &config.SSL{}, &config.Listener{}, &config.GitOps{})
+	if err != nil {
+		return nil, err
+	}
+	s.SetTestMode(opts.TestMode)
+
+	// The listener is created first since the gateway needs to know the port it is reachable on
+	g := &Gateway{server: s}
+	g.httpServer = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.handler.ServeHTTP(w, r)
+	}))
+	port, err := getPort(g.httpServer.Listener.Addr())
+	if err != nil {
+		g.httpServer.Close()
+		return nil, err
+	}
+
+	if err := s.Init(port); err != nil {
+		g.httpServer.Close()
+		return nil, err
+	}
+	g.handler = s.Handler(false, "", nil)
+	g.httpServer.Start()
+
+	if g.adminToken, err = s.GetAdminToken(); err != nil {
+		_ = g.Close()
+		return nil, err
+	}
+
+	if opts.Config != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := g.Admin().ImportConfig(ctx, opts.Config, model.ConfigImportMerge); err != nil {
+			_ = g.Close()
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Handler returns the handler serving the routes of the gateway. It can be used with httptest.NewRecorder to make
+// requests without going over the network.
+func (g *Gateway) Handler() http.Handler {
+	return g.handler
+}
+
+// URL returns the base url of the gateway. It can be used with any http or websocket client.
+func (g *Gateway) URL() string {
+	return g.httpServer.URL
+}
+
+// AdminToken returns a token of the admin user
+func (g *Gateway) AdminToken() string {
+	return g.adminToken
+}
+
+// Token signs a token with the claims for the users of a project
+func (g *Gateway) Token(projectID string, claims map[string]interface{}) (string, error) {
+	return g.server.CreateToken(context.Background(), projectID, claims)
+}
+
+// Client returns a client of the apis of a project which makes the requests with the provided token
+func (g *Gateway) Client(projectID, token string) *Client {
+	return &Client{http: g.httpServer.Client(), url: g.URL(), project: projectID, token: token}
+}
+
+// Admin returns a client of the admin apis
+func (g *Gateway) Admin() *AdminClient {
+	return &AdminClient{http: g.httpServer.Client(), url: g.URL(), token: g.adminToken}
+}
+
+// Close shuts down the gateway like on receiving SIGTERM
+func (g *Gateway) Close() error {
+	g.closeOnce.Do(func() {
+		g.httpServer.Close()
+		g.closeErr = g.server.Shutdown(shutdownTimeout)
+	})
+	return g.closeErr
+}
+
+func getPort(addr net.Addr) (int, error) {
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("unable to generate random bytes - %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,109 @@
+package sctest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func testConfig(dbPath string) *config.Config {
+	c := config.GenerateEmptyConfig()
+	project := config.GenerateEmptyProject(&config.ProjectConfig{
+		ID:      "myproject",
+		Name:    "myproject",
+		Secrets: []*config.Secret{{KID: "myproject", Alg: config.HS256, Secret: "some-secret", IsPrimary: true}},
+		Fixtures: &config.FixturesConfig{
+			Enabled:     true,
+			LoadOnStart: true,
+			Collections: []*config.CollectionFixture{{DbAlias: "db", Col: "users", Docs: []map[string]interface{}{{"_id": "1", "name": "alice"}}}},
+		},
+	})
+	// The resource ids are moved to the cluster of the gateway on being applied
+	project.DatabaseConfigs[config.GenerateResourceID("other-cluster", "myproject", config.ResourceDatabaseConfig, "db", "config")] = &config.DatabaseConfig{
+		DbAlias: "db", Type: string(model.EmbeddedDB), DBName: "myproject", Conn: dbPath, Enabled: true,
+	}
+	project.DatabaseSchemas[config.GenerateResourceID("other-cluster", "myproject", config.ResourceDatabaseSchema, "db", "users")] = &config.DatabaseSchema{
+		Table: "users", DbAlias: "db", Schema: "type users { _id: ID! @primary name: String }",
+	}
+	project.DatabaseRules[config.GenerateResourceID("other-cluster", "myproject", config.ResourceDatabaseRule, "db", "default", "rule")] = &config.DatabaseRule{
+		Table: "default", DbAlias: "db",
+		Rules: map[string]*config.Rule{
+			"create": {Rule: "authenticated"},
+			"read":   {Rule: "allow"},
+			"update": {Rule: "authenticated"},
+			"delete": {Rule: "authenticated"},
+		},
+	}
+	c.Projects["myproject"] = project
+	return c
+}
+
+type user struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+func TestGateway(t *testing.T) {
+	ctx := context.Background()
+	g := New(t, testConfig(filepath.Join(t.TempDir(), "test.db")))
+
+	// The fixtures are loaded on start
+	anonymous := g.Client("myproject", "").DB("db")
+	users := make([]*user, 0)
+	if err := anonymous.Read(ctx, "users", nil, &users); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "alice" {
+		t.Errorf("Read() got = %v, want the fixtures", users)
+	}
+
+	// The security rules are enforced
+	err := anonymous.Create(ctx, "users", map[string]interface{}{"_id": "2", "name": "bob"})
+	if e, ok := err.(*Error); !ok || e.Status != http.StatusUnauthorized && e.Status != http.StatusForbidden {
+		t.Errorf("Create() without a token error = %v, want an auth error", err)
+	}
+
+	token, err := g.Token("myproject", map[string]interface{}{"id": "1"})
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	db := g.Client("myproject", token).DB("db")
+	if err := db.Create(ctx, "users", map[string]interface{}{"_id": "2", "name": "bob"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := db.Update(ctx, "users", map[string]interface{}{"_id": "2"}, map[string]interface{}{"$set": map[string]interface{}{"name": "robert"}}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	users = make([]*user, 0)
+	if err := db.Read(ctx, "users", map[string]interface{}{"_id": "2"}, &users); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "robert" {
+		t.Errorf("Read() got = %v, want the updated user", users)
+	}
+
+	// The teardown removes only the fixtures
+	if _, err := g.Admin().TeardownFixtures(ctx, "myproject"); err != nil {
+		t.Fatalf("TeardownFixtures() error = %v", err)
+	}
+	users = make([]*user, 0)
+	if err := db.Read(ctx, "users", nil, &users); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(users) != 1 || users[0].ID != "2" {
+		t.Errorf("Read() after teardown got = %v, want only the created user", users)
+	}
+
+	// The handler serves the requests without going over the network
+	w := httptest.NewRecorder()
+	g.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/api/myproject/crud/db/users/read", strings.NewReader(`{"find":{},"op":"all"}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("Handler() responded with status (%d) - %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleAdminEvents streams the admin events of the cluster over a websocket. The events can be filtered with the
+// comma separated types and the project provided as query params. Browsers can't set the headers of a websocket
+// request, hence the token may be provided in the token query param as well.
+func HandleAdminEvents(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		if _, err := adminMan.IsTokenValid(ctx, token, "cluster", "read", map[string]string{}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		types := map[string]bool{}
+		if v := r.URL.Query().Get("types"); v != "" {
+			for _, t := range strings.Split(v, ",") {
+				types[strings.TrimSpace(t)] = true
+			}
+		}
+		project := r.URL.Query().Get("project")
+
+		socket, err := upgradeSocket(w, r, nil)
+		if err != nil {
+			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "upgrade:", map[string]interface{}{"error": err})
+			return
+		}
+		defer releaseSocket(socket)
+		defer utils.CloseTheCloser(socket)
+
+		id := ksuid.New().String()
+		events := syncMan.SubscribeAdminEvents(id)
+		defer syncMan.UnsubscribeAdminEvents(id)
+
+		// The client only sends control messages. Reading them is required to notice the socket getting closed.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := socket.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if !isAdminEventRequested(event, types, project) {
+					continue
+				}
+				_ = socket.SetWriteDeadline(time.Now().Add(10 * time.Second))
+				if err := socket.WriteJSON(event); err != nil {
+					helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Unable to send admin event", map[string]interface{}{"error": err.Error()})
+					return
+				}
+			}
+		}
+	}
+}
+
+// isAdminEventRequested checks if the event matches the filters of the client. The events of the cluster which don't
+// belong to a project match every project.
+func isAdminEventRequested(event *model.AdminEvent, types map[string]bool, project string) bool {
+	if len(types) > 0 && !types[event.Type] {
+		return false
+	}
+	return project == "" || event.Project == "" || event.Project == project
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// backupTimeout is the maximum time a backup or restore requested over http may take
+const backupTimeout = 30 * time.Minute
+
+// HandleListBackups returns the backups of the data of a project with the most recent backup first
+func HandleListBackups(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if _, err := adminMan.IsTokenValid(ctx, token, "backup", "read", map[string]string{"project": projectID}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		backupMod, err := modules.Backup(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		backups, err := backupMod.ListBackups(ctx)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: backups})
+	}
+}
+
+// HandleTakeBackup takes a backup of the data of a project right away
+func HandleTakeBackup(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), backupTimeout)
+		defer cancel()
+
+		if _, err := adminMan.IsTokenValid(ctx, token, "backup", "modify", map[string]string{"project": projectID}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		backupMod, err := modules.Backup(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		info, err := backupMod.Backup(ctx, model.BackupTriggerManual)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: info})
+	}
+}
+
+// HandleRestoreBackup writes the documents of a backup back to the collections of a project
+func HandleRestoreBackup(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := vars["id"]
+
+		req := new(model.BackupRestoreRequest)
+		_ = json.NewDecoder(r.Body).Decode(req)
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), backupTimeout)
+		defer cancel()
+
+		if _, err := adminMan.IsTokenValid(ctx, token, "backup", "modify", map[string]string{"project": projectID, "id": id}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		backupMod, err := modules.Backup(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		result, err := backupMod.Restore(ctx, id, req)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: result})
+	}
+}
@@ -3,18 +3,24 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"time"
 
+	"github.com/ghodss/yaml"
+	"github.com/gorilla/mux"
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 // HandleBatchApplyConfig applies all the config at once
-func HandleBatchApplyConfig(adminMan *admin.Manager) http.HandlerFunc {
+func HandleBatchApplyConfig(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		token := utils.GetTokenFromHeader(r)
 
@@ -25,14 +31,14 @@ func HandleBatchApplyConfig(adminMan *admin.Manager) http.HandlerFunc {
 		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
 		defer cancel()
 
-		if err := adminMan.CheckIfAdmin(ctx, token); err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+		if err := adminMan.CheckAdminScope(ctx, token, config.AdminScopeConfigWrite); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		for _, specObject := range req.Specs {
-			if err := utils.ApplySpec(ctx, token, "http://localhost:4122", specObject); err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			if err := utils.ApplySpec(ctx, token, syncMan.GetLocalURL(), specObject); err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 				return
 			}
 		}
@@ -40,3 +46,113 @@ func HandleBatchApplyConfig(adminMan *admin.Manager) http.HandlerFunc {
 		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
 	}
 }
+
+// HandleExportConfig returns the config of all projects along with the cluster level config as a yaml bundle
+func HandleExportConfig(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if err := adminMan.CheckAdminScope(ctx, token, config.AdminScopeConfigRead); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams := utils.ExtractRequestParams(r, model.RequestParams{Resource: "config", Op: "read"}, nil)
+		status, bundle, err := syncMan.ExportConfig(ctx, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		data, err := yaml.Marshal(bundle)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-yaml")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=space-cloud-config-%s.yaml", bundle.ExportedAt.Format("20060102-150405")))
+		w.WriteHeader(status)
+		_, _ = w.Write(data)
+	}
+}
+
+// HandleImportConfig applies a config bundle generated by the export endpoint. The strategy query parameter is either merge or replace.
+func HandleImportConfig(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if err := adminMan.CheckAdminScope(ctx, token, config.AdminScopeConfigWrite); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		// The bundle can either be in yaml or json
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		bundle := new(model.ConfigBundle)
+		if err := yaml.Unmarshal(data, bundle); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid config bundle provided: %v", err))
+			return
+		}
+
+		reqParams := utils.ExtractRequestParams(r, model.RequestParams{Resource: "config", Op: "modify"}, bundle)
+		status, result, err := syncMan.ImportConfig(ctx, bundle, r.URL.Query().Get("strategy"), reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: result})
+	}
+}
+
+// HandleDiffProjectConfig returns the changes which applying the provided config would make to the config of a project.
+// The config can either be in yaml or json.
+func HandleDiffProjectConfig(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		defer utils.CloseTheCloser(r.Body)
+
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "project", "read", map[string]string{"project": projectID})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		req := new(model.ConfigDiffRequest)
+		if err := yaml.Unmarshal(data, req); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid config provided: %v", err))
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, req)
+		status, diff, err := syncMan.DiffProjectConfig(ctx, projectID, req, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: diff})
+	}
+}
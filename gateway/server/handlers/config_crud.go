@@ -37,18 +37,18 @@ func HandleGetAllTableNames(adminMan *admin.Manager, modules *modules.Modules) h
 		// Check if the request is authorised
 		_, err := adminMan.IsTokenValid(ctx, token, "db-config", "read", map[string]string{"project": projectID, "db": dbAlias})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		crud, err := modules.DB(projectID)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 		collections, err := crud.GetCollections(ctx, dbAlias)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -79,13 +79,13 @@ func HandleGetDatabaseConnectionState(adminMan *admin.Manager, modules *modules.
 		// Check if the request is authorised
 		_, err := adminMan.IsTokenValid(ctx, token, "db-config", "read", map[string]string{"project": projectID, "db": dbAlias})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		crud, err := modules.DB(projectID)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -114,20 +114,20 @@ func HandleDeleteTable(adminMan *admin.Manager, modules *modules.Modules, syncma
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		crud, err := modules.DB(projectID)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 		status, err := syncman.SetDeleteCollection(ctx, projectID, dbAlias, col, crud, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -155,7 +155,7 @@ func HandleSetDatabaseConfig(adminMan *admin.Manager, syncman *syncman.Manager)
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-config", "modify", map[string]string{"project": projectID, "db": dbAlias})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -163,7 +163,7 @@ func HandleSetDatabaseConfig(adminMan *admin.Manager, syncman *syncman.Manager)
 		status, err := syncman.SetDatabaseConnection(ctx, projectID, dbAlias, &v, reqParams)
 		fmt.Println("ABCDEF", err == nil)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -193,7 +193,7 @@ func HandleGetDatabaseConfig(adminMan *admin.Manager, syncMan *syncman.Manager)
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-config", "read", map[string]string{"project": projectID, "db": dbAlias})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -201,7 +201,7 @@ func HandleGetDatabaseConfig(adminMan *admin.Manager, syncMan *syncman.Manager)
 
 		status, dbConfig, err := syncMan.GetDatabaseConfig(ctx, projectID, dbAlias, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -228,14 +228,14 @@ func HandleRemoveDatabaseConfig(adminMan *admin.Manager, syncman *syncman.Manage
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-config", "modify", map[string]string{"project": projectID, "db": dbAlias})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 		status, err := syncman.RemoveDatabaseConfig(ctx, projectID, dbAlias, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -269,7 +269,7 @@ func HandleGetPreparedQuery(adminMan *admin.Manager, syncMan *syncman.Manager) h
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-prepared-query", "read", map[string]string{"project": projectID, "db": dbAlias, "id": id})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
@@ -277,7 +277,7 @@ func HandleGetPreparedQuery(adminMan *admin.Manager, syncMan *syncman.Manager) h
 
 		status, result, err := syncMan.GetPreparedQuery(ctx, projectID, dbAlias, id, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -307,14 +307,14 @@ func HandleSetPreparedQueries(adminMan *admin.Manager, syncman *syncman.Manager)
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-prepared-query", "modify", map[string]string{"project": projectID, "db": dbAlias, "id": id})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, &v)
 		status, err := syncman.SetPreparedQueries(ctx, projectID, dbAlias, id, &v, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -342,14 +342,14 @@ func HandleRemovePreparedQueries(adminMan *admin.Manager, syncman *syncman.Manag
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-prepared-query", "modify", map[string]string{"project": projectID, "db": dbAlias, "id": id})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 		status, err := syncman.RemovePreparedQueries(ctx, projectID, dbAlias, id, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -379,14 +379,14 @@ func HandleModifySchema(adminMan *admin.Manager, modules *modules.Modules, syncm
 
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, v)
 		status, err := syncman.SetModifySchema(ctx, projectID, dbAlias, col, &v, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -425,7 +425,7 @@ func HandleGetSchemas(adminMan *admin.Manager, syncMan *syncman.Manager) http.Ha
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "read", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -433,7 +433,7 @@ func HandleGetSchemas(adminMan *admin.Manager, syncMan *syncman.Manager) http.Ha
 
 		status, schemas, err := syncMan.GetSchemas(ctx, projectID, dbAlias, col, format, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: schemas})
@@ -462,14 +462,14 @@ func HandleSetTableRules(adminMan *admin.Manager, syncman *syncman.Manager) http
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-rule", "modify", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, v)
 		status, err := syncman.SetCollectionRules(ctx, projectID, dbAlias, col, &v, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -504,7 +504,7 @@ func HandleGetTableRules(adminMan *admin.Manager, syncMan *syncman.Manager) http
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-rule", "read", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -512,7 +512,7 @@ func HandleGetTableRules(adminMan *admin.Manager, syncMan *syncman.Manager) http
 
 		status, dbConfig, err := syncMan.GetCollectionRules(ctx, projectID, dbAlias, col, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: dbConfig})
@@ -541,14 +541,14 @@ func HandleDeleteTableRules(adminMan *admin.Manager, syncman *syncman.Manager) h
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-rule", "delete", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, v)
 		status, err := syncman.DeleteCollectionRules(ctx, projectID, dbAlias, col, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -556,6 +556,40 @@ func HandleDeleteTableRules(adminMan *admin.Manager, syncman *syncman.Manager) h
 	}
 }
 
+// HandleGetRowLevelSecurityPolicies is an endpoint handler which returns the queries creating the row level security
+// policies of a postgres table as generated from its security rules
+func HandleGetRowLevelSecurityPolicies(adminMan *admin.Manager, syncman *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		dbAlias := vars["dbAlias"]
+		projectID := vars["project"]
+		col := vars["col"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-rule", "read", map[string]string{"project": projectID, "db": dbAlias, "col": col})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+		status, queries, err := syncman.GetRowLevelSecurityPolicies(ctx, projectID, dbAlias, col, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: queries})
+	}
+}
+
 // HandleReloadSchema is an endpoint handler which return & sets the schemas of all collection in config
 func HandleReloadSchema(adminMan *admin.Manager, modules *modules.Modules, syncman *syncman.Manager) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -575,7 +609,7 @@ func HandleReloadSchema(adminMan *admin.Manager, modules *modules.Modules, syncm
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": dbAlias, "col": "*"})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -583,7 +617,7 @@ func HandleReloadSchema(adminMan *admin.Manager, modules *modules.Modules, syncm
 
 		status, err := syncman.SetReloadSchema(ctx, dbAlias, projectID, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -611,14 +645,14 @@ func HandleInspectCollectionSchema(adminMan *admin.Manager, modules *modules.Mod
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": dbAlias, "col": col})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 		status, err := syncman.SetSchemaInspection(ctx, projectID, dbAlias, col, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -645,14 +679,14 @@ func HandleUntrackCollectionSchema(adminMan *admin.Manager, modules *modules.Mod
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": dbAlias})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 		status, err := syncman.RemoveCollection(ctx, projectID, dbAlias, col, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -681,14 +715,14 @@ func HandleModifyAllSchema(adminMan *admin.Manager, syncman *syncman.Manager) ht
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "db-schema", "modify", map[string]string{"project": projectID, "db": dbAlias, "col": "*"})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, v)
 		status, err := syncman.SetModifyAllSchema(ctx, dbAlias, projectID, v, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -716,22 +750,64 @@ func HandleInspectTrackedCollectionsSchema(adminMan *admin.Manager, modules *mod
 		// Check if the request is authorised
 		_, err := adminMan.IsTokenValid(ctx, token, "db-schema", "read", map[string]string{"project": projectID, "db": dbAlias, "col": "*"})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		schema, err := modules.Schema(projectID)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		schemas, err := schema.GetCollectionSchema(ctx, projectID, dbAlias)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
 		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: schemas})
 	}
 }
+
+// HandleGetIndexDrift is an endpoint handler which returns the difference between the indexes declared in the schema
+// of a collection and the ones present in the database
+func HandleGetIndexDrift(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		dbAlias := vars["dbAlias"]
+		col := vars["col"]
+		projectID := vars["project"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		_, err := adminMan.IsTokenValid(ctx, token, "db-schema", "read", map[string]string{"project": projectID, "db": dbAlias, "col": col})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		schema, err := modules.Schema(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		drift, err := schema.GetIndexDrift(ctx, dbAlias, col)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: drift})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleMintToken creates a token of the project with arbitrary claims to impersonate its users while debugging
+func HandleMintToken(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	type request struct {
+		Claims map[string]interface{} `json:"claims"`
+		// ExpiresIn is the validity of the token in seconds. It defaults to an hour and can be at most a day
+		ExpiresIn int `json:"expiresIn"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+
+		req := new(request)
+		_ = json.NewDecoder(r.Body).Decode(req)
+		defer utils.CloseTheCloser(r.Body)
+
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if err := adminMan.CheckIfAdmin(ctx, token); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		authModule, err := modules.Auth(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		newToken, err := authModule.MintToken(ctx, req.Claims, time.Duration(req.ExpiresIn)*time.Second)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "Minted a token to impersonate a user", map[string]interface{}{"project": projectID})
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: map[string]string{"token": newToken}})
+	}
+}
+
+// HandleDebugRule evaluates a security rule against the provided request and claims and returns the decision trace
+func HandleDebugRule(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+
+		req := new(model.RuleDebugRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+		defer utils.CloseTheCloser(r.Body)
+
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if err := adminMan.CheckAdminScope(ctx, token, config.AdminScopeConfigRead); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		authModule, err := modules.Auth(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := authModule.DebugRule(ctx, projectID, req)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: res})
+	}
+}
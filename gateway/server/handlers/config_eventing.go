@@ -37,14 +37,14 @@ func HandleAddEventingTriggerRule(adminMan *admin.Manager, syncMan *syncman.Mana
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-trigger", "modify", map[string]string{"project": projectID, "id": ruleName})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, value)
 		status, err := syncMan.SetEventingRule(ctx, projectID, ruleName, &value, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -74,7 +74,7 @@ func HandleGetEventingTriggers(adminMan *admin.Manager, syncMan *syncman.Manager
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-trigger", "read", map[string]string{"project": projectID, "id": id})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -82,7 +82,7 @@ func HandleGetEventingTriggers(adminMan *admin.Manager, syncMan *syncman.Manager
 
 		status, rules, err := syncMan.GetEventingTriggerRules(ctx, projectID, id, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: rules})
@@ -108,14 +108,14 @@ func HandleDeleteEventingTriggerRule(adminMan *admin.Manager, syncMan *syncman.M
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-trigger", "modify", map[string]string{"project": projectID, "id": ruleName})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
 		status, err := syncMan.SetDeleteEventingRule(ctx, projectID, ruleName, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -141,7 +141,7 @@ func HandleSetEventingConfig(adminMan *admin.Manager, syncMan *syncman.Manager)
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-config", "modify", map[string]string{"project": projectID})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -149,9 +149,9 @@ func HandleSetEventingConfig(adminMan *admin.Manager, syncMan *syncman.Manager)
 		_ = json.NewDecoder(r.Body).Decode(c)
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, c)
-		status, err := syncMan.SetEventingConfig(ctx, projectID, c.DBAlias, c.Enabled, reqParams)
+		status, err := syncMan.SetEventingConfig(ctx, projectID, c.DBAlias, c.Enabled, c.Retention, c.DLQAlert, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -176,7 +176,7 @@ func HandleGetEventingConfig(adminMan *admin.Manager, syncMan *syncman.Manager)
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-config", "read", map[string]string{"project": projectID})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -185,7 +185,7 @@ func HandleGetEventingConfig(adminMan *admin.Manager, syncMan *syncman.Manager)
 
 		status, e, err := syncMan.GetEventingConfig(ctx, projectID, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -215,7 +215,7 @@ func HandleSetEventingSchema(adminMan *admin.Manager, syncMan *syncman.Manager)
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-schema", "modify", map[string]string{"project": projectID, "id": evType})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for set eventing schema", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -225,7 +225,7 @@ func HandleSetEventingSchema(adminMan *admin.Manager, syncMan *syncman.Manager)
 		reqParams = utils.ExtractRequestParams(r, reqParams, c)
 		status, err := syncMan.SetEventingSchema(ctx, projectID, evType, c.Schema, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -255,7 +255,7 @@ func HandleGetEventingSchema(adminMan *admin.Manager, syncMan *syncman.Manager)
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-schema", "read", map[string]string{"project": projectID, "id": id})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -263,7 +263,7 @@ func HandleGetEventingSchema(adminMan *admin.Manager, syncMan *syncman.Manager)
 
 		status, schemas, err := syncMan.GetEventingSchema(ctx, projectID, id, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: schemas})
@@ -290,7 +290,7 @@ func HandleDeleteEventingSchema(adminMan *admin.Manager, syncMan *syncman.Manage
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-schema", "modify", map[string]string{"project": projectID, "id": evType})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for delete eventing schema", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -298,7 +298,7 @@ func HandleDeleteEventingSchema(adminMan *admin.Manager, syncMan *syncman.Manage
 		status, err := syncMan.SetDeleteEventingSchema(ctx, projectID, evType, reqParams)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to delete eventing schema", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -329,7 +329,7 @@ func HandleAddEventingSecurityRule(adminMan *admin.Manager, syncMan *syncman.Man
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-rule", "modify", map[string]string{"project": projectID, "id": evType})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for set eventing rules", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -337,7 +337,7 @@ func HandleAddEventingSecurityRule(adminMan *admin.Manager, syncMan *syncman.Man
 		status, err := syncMan.SetEventingSecurityRules(ctx, projectID, evType, c, reqParams)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to add eventing rules", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -367,7 +367,7 @@ func HandleGetEventingSecurityRules(adminMan *admin.Manager, syncMan *syncman.Ma
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-rule", "read", map[string]string{"project": projectID, "id": id})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -375,7 +375,7 @@ func HandleGetEventingSecurityRules(adminMan *admin.Manager, syncMan *syncman.Ma
 
 		status, securityRules, err := syncMan.GetEventingSecurityRules(ctx, projectID, id, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: securityRules})
@@ -401,7 +401,7 @@ func HandleDeleteEventingSecurityRule(adminMan *admin.Manager, syncMan *syncman.
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-rule", "modify", map[string]string{"project": projectID, "id": evType})
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for delete eventing rules", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -409,7 +409,231 @@ func HandleDeleteEventingSecurityRule(adminMan *admin.Manager, syncMan *syncman.
 		status, err := syncMan.SetDeleteEventingSecurityRules(ctx, projectID, evType, reqParams)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to delete eventing rules", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleSetEventingSource is an endpoint handler which sets a custom event source in eventing
+func HandleSetEventingSource(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := vars["id"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-source", "modify", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for set eventing source", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		value := new(config.EventingSource)
+		if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, value)
+		status, err := syncMan.SetEventingSource(ctx, projectID, id, value, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleGetEventingSources returns handler to get the custom event sources of eventing
+func HandleGetEventingSources(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// get project id and source id from url
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := "*"
+		if sourceID, exists := r.URL.Query()["id"]; exists {
+			id = sourceID[0]
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-source", "read", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		status, sources, err := syncMan.GetEventingSources(ctx, projectID, id, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: sources})
+	}
+}
+
+// HandleDeleteEventingSource is an endpoint handler which deletes a custom event source in eventing
+func HandleDeleteEventingSource(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := vars["id"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-source", "modify", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for delete eventing source", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+		status, err := syncMan.SetDeleteEventingSource(ctx, projectID, id, reqParams)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to delete eventing source", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleSetEventingWorkflow is an endpoint handler which sets a workflow in eventing
+func HandleSetEventingWorkflow(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := vars["id"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-workflow", "modify", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for set eventing workflow", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		value := new(config.EventingWorkflow)
+		if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, value)
+		status, err := syncMan.SetEventingWorkflow(ctx, projectID, id, value, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleGetEventingWorkflows returns handler to get the workflows of eventing
+func HandleGetEventingWorkflows(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		// get project id and workflow id from url
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := "*"
+		if workflowID, exists := r.URL.Query()["id"]; exists {
+			id = workflowID[0]
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-workflow", "read", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		status, workflows, err := syncMan.GetEventingWorkflows(ctx, projectID, id, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: workflows})
+	}
+}
+
+// HandleDeleteEventingWorkflow is an endpoint handler which deletes a workflow in eventing
+func HandleDeleteEventingWorkflow(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		id := vars["id"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "eventing-workflow", "modify", map[string]string{"project": projectID, "id": id})
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for delete eventing workflow", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+		status, err := syncMan.SetDeleteEventingWorkflow(ctx, projectID, id, reqParams)
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to delete eventing workflow", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -33,7 +33,7 @@ func HandleGetProjectConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "project", "read", map[string]string{"project": projectID})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -41,7 +41,7 @@ func HandleGetProjectConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 
 		status, project, err := syncMan.GetProjectConfig(ctx, projectID, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -70,7 +70,7 @@ func HandleApplyProject(adminMan *admin.Manager, syncman *syncman.Manager) http.
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "project", "modify", map[string]string{"project": projectID})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -78,7 +78,7 @@ func HandleApplyProject(adminMan *admin.Manager, syncman *syncman.Manager) http.
 
 		statusCode, err := syncman.ApplyProjectConfig(ctx, &projectConfig, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, statusCode, err)
+			_ = utils.SendErrorResponse(ctx, w, statusCode, err)
 			return
 		}
 
@@ -99,7 +99,7 @@ func HandleDeleteProjectConfig(adminMan *admin.Manager, syncMan *syncman.Manager
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(r.Context(), token, "project", "modify", map[string]string{"project": projectID})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -111,7 +111,7 @@ func HandleDeleteProjectConfig(adminMan *admin.Manager, syncMan *syncman.Manager
 		reqParams.Headers = r.Header
 		status, err := syncMan.DeleteProjectConfig(ctx, projectID, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -132,7 +132,7 @@ func HandleGetClusterConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "cluster", "read", map[string]string{})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -140,7 +140,7 @@ func HandleGetClusterConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 
 		status, clusterConfig, err := syncMan.GetClusterConfig(ctx, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
@@ -162,7 +162,7 @@ func HandleSetClusterConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 
 		// Throw error if request was of incorrect type
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusBadRequest, fmt.Errorf("Admin Config was of invalid type - %v", err.Error()))
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, fmt.Errorf("Admin Config was of invalid type - %v", err.Error()))
 			return
 		}
 
@@ -172,7 +172,7 @@ func HandleSetClusterConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 		// Check if the request is authorised
 		reqParams, err := adminMan.IsTokenValid(ctx, token, "cluster", "modify", map[string]string{})
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -181,10 +181,53 @@ func HandleSetClusterConfig(adminMan *admin.Manager, syncMan *syncman.Manager) h
 		// Sync the Adminconfig
 		status, err := syncMan.SetClusterConfig(ctx, req, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
 		_ = helpers.Response.SendResponse(ctx, w, status, map[string]interface{}{})
 	}
 }
+
+// HandleCloneProject returns the handler to clone the config of a project into another project via a REST endpoint
+func HandleCloneProject(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		req := new(model.ProjectCloneRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Check if the request is authorised to read the source project and modify the target project
+		if _, err := adminMan.IsTokenValid(ctx, token, "project", "read", map[string]string{"project": projectID}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "project", "modify", map[string]string{"project": req.ID})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, req)
+		status, result, err := syncMan.CloneProject(ctx, projectID, req, reqParams)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: result})
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules"
 	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
@@ -37,12 +39,12 @@ func HandleCrudPreparedQuery(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(project)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 		crud, err := modules.DB(project)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 		// Load the request from the body
@@ -53,7 +55,7 @@ func HandleCrudPreparedQuery(modules *modules.Modules) http.HandlerFunc {
 		// Check if the user is authenticated
 		actions, reqParams, err := auth.IsPreparedQueryAuthorised(ctx, project, dbAlias, id, token, &req)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
@@ -62,7 +64,7 @@ func HandleCrudPreparedQuery(modules *modules.Modules) http.HandlerFunc {
 		// Perform the PreparedQuery operation
 		result, _, err := crud.ExecPreparedQuery(ctx, dbAlias, id, &req, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -86,16 +88,12 @@ func HandleCrudCreate(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 		crud, err := modules.DB(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -107,18 +105,34 @@ func HandleCrudCreate(modules *modules.Modules) http.HandlerFunc {
 		// Check if the user is authenticated
 		reqParams, err := auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
+		// The affected documents are only returned if the user is allowed to read them
+		var actions *model.PostProcess
+		if req.Returning {
+			actions, _, err = auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.All, Options: &model.ReadOptions{}}, model.ReturnWhereStub{})
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+		}
+
 		reqParams = utils.ExtractRequestParams(r, reqParams, req)
 
 		// Perform the write operation
-		err = crud.Create(ctx, meta.dbType, meta.col, &req, reqParams)
+		docs, err := crud.CreateReturning(ctx, meta.dbType, meta.col, &req, reqParams)
 		if err != nil {
 
 			// Send http response
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			sendWriteErrorResponse(ctx, w, err)
+			return
+		}
+
+		if req.Returning {
+			_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, docs)
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": docs})
 			return
 		}
 
@@ -140,17 +154,13 @@ func HandleCrudRead(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		crud, err := modules.DB(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -171,7 +181,7 @@ func HandleCrudRead(modules *modules.Modules) http.HandlerFunc {
 		returnWhere := model.ReturnWhereStub{Col: meta.col, PrefixColName: len(req.Options.Join) > 0, ReturnWhere: dbType != string(model.Mongo), Where: map[string]interface{}{}}
 		actions, reqParams, err := auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req, returnWhere)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 		if len(returnWhere.Where) > 0 {
@@ -184,24 +194,47 @@ func HandleCrudRead(modules *modules.Modules) http.HandlerFunc {
 		req.PostProcess[meta.col] = actions
 
 		if err := auth.RunAuthForJoins(ctx, meta.projectID, dbType, meta.dbType, meta.token, &req, req.Options.Join); err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
 		reqParams = utils.ExtractRequestParams(r, reqParams, req)
 
-		result, _, err := crud.Read(ctx, meta.dbType, meta.col, &req, reqParams)
-		// Perform the read operation
+		// Stream the documents as they are read if the client asked for it. The stream isn't bound by the timeout of
+		// the request since large exports take longer. It ends when the client disconnects.
+		if r.URL.Query().Get("stream") == "true" {
+			fields, _ := crud.GetSchema(meta.dbType, meta.col)
+			streamDocuments(r.Context(), w, r, crud, auth.GetAESKey(), meta.dbType, meta.col, getReadColumns(fields, &req), &req, reqParams, actions)
+			return
+		}
 
+		// Perform the read operation. The pagination metadata is only returned if the client asked for it.
+		var result interface{}
+		var pageInfo *model.PageInfo
+		if req.Options.PageInfo || req.Options.TotalCount != "" || req.Options.After != "" {
+			result, pageInfo, err = crud.ReadPage(ctx, meta.dbType, meta.col, &req, reqParams)
+		} else {
+			result, _, err = crud.Read(ctx, meta.dbType, meta.col, &req, reqParams)
+		}
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
 		// function to do postProcessing on result
 		_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, result)
 
+		// Send the documents as csv or ndjson if the client asked for it
+		fields, _ := crud.GetSchema(meta.dbType, meta.col)
+		if sendDocuments(ctx, w, r, getReadColumns(fields, &req), result) {
+			return
+		}
+
 		// Give positive acknowledgement
+		if pageInfo != nil {
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": result, "pageInfo": pageInfo})
+			return
+		}
 		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": result})
 	}
 }
@@ -218,16 +251,12 @@ func HandleCrudUpdate(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 		crud, err := modules.DB(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -238,18 +267,105 @@ func HandleCrudUpdate(modules *modules.Modules) http.HandlerFunc {
 
 		reqParams, err := auth.IsUpdateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
+		// The affected documents are only returned if the user is allowed to read them
+		var actions *model.PostProcess
+		if req.Returning {
+			actions, _, err = auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}}, model.ReturnWhereStub{})
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+		}
+
 		reqParams = utils.ExtractRequestParams(r, reqParams, req)
 
 		// Perform the update operation
-		err = crud.Update(ctx, meta.dbType, meta.col, &req, reqParams)
+		docs, err := crud.UpdateReturning(ctx, meta.dbType, meta.col, &req, reqParams)
 		if err != nil {
 
 			// Send http response
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			sendWriteErrorResponse(ctx, w, err)
+			return
+		}
+
+		if req.Returning {
+			_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, docs)
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": docs})
+			return
+		}
+
+		// Give positive acknowledgement
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
+	}
+}
+
+// HandleCrudIncrement creates the endpoint to atomically increment the counter fields of documents
+func HandleCrudIncrement(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the request from the body
+		req := model.IncrementRequest{}
+		defer utils.CloseTheCloser(r.Body)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// An increment is authorised like the update it is made with
+		op := utils.All
+		if req.Upsert {
+			op = utils.Upsert
+		}
+		updateReq := &model.UpdateRequest{Find: req.Find, Operation: op, Update: map[string]interface{}{"$inc": req.Inc}}
+		reqParams, err := auth.IsUpdateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, updateReq)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			return
+		}
+		req.Find = updateReq.Find
+
+		// The incremented documents are only returned if the user is allowed to read them
+		var actions *model.PostProcess
+		if req.Returning {
+			actions, _, err = auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}}, model.ReturnWhereStub{})
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+		}
+
+		reqParams = utils.ExtractRequestParams(r, reqParams, req)
+
+		docs, err := crud.Increment(ctx, meta.dbType, meta.col, &req, reqParams)
+		if err != nil {
+			sendWriteErrorResponse(ctx, w, err)
+			return
+		}
+
+		if req.Returning {
+			_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, docs)
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": docs})
 			return
 		}
 
@@ -269,17 +385,13 @@ func HandleCrudDelete(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		crud, err := modules.DB(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -290,17 +402,33 @@ func HandleCrudDelete(modules *modules.Modules) http.HandlerFunc {
 
 		reqParams, err := auth.IsDeleteOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
+		// The affected documents are only returned if the user is allowed to read them
+		var actions *model.PostProcess
+		if req.Returning {
+			actions, _, err = auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}}, model.ReturnWhereStub{})
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+		}
+
 		reqParams = utils.ExtractRequestParams(r, reqParams, req)
 
 		// Perform the delete operation
-		err = crud.Delete(ctx, meta.dbType, meta.col, &req, reqParams)
+		docs, err := crud.DeleteReturning(ctx, meta.dbType, meta.col, &req, reqParams)
 		if err != nil {
 			// Send http response
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if req.Returning {
+			_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, docs)
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": docs})
 			return
 		}
 
@@ -321,17 +449,13 @@ func HandleCrudAggregate(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		crud, err := modules.DB(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -342,7 +466,7 @@ func HandleCrudAggregate(modules *modules.Modules) http.HandlerFunc {
 
 		reqParams, err := auth.IsAggregateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
@@ -351,7 +475,12 @@ func HandleCrudAggregate(modules *modules.Modules) http.HandlerFunc {
 		// Perform the aggregate operation
 		result, err := crud.Aggregate(ctx, meta.dbType, meta.col, &req, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// Send the documents as csv or ndjson if the client asked for it
+		if sendDocuments(ctx, w, r, nil, result) {
 			return
 		}
 
@@ -390,17 +519,13 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		crud, err := modules.DB(meta.projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -432,7 +557,20 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 			// Send error response
 			if err != nil {
 				// Send http response
-				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+		}
+
+		// Events published along with the batch need to be authorised as well
+		if len(txRequest.Events) > 0 {
+			eventing, err := modules.Eventing(meta.projectID)
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+			if err := eventing.ValidateEvents(ctx, meta.projectID, meta.token, txRequest.Events); err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 				return
 			}
 		}
@@ -442,7 +580,7 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 
 		err = crud.Batch(ctx, meta.dbType, &txRequest, reqParams)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			sendWriteErrorResponse(ctx, w, err)
 			return
 		}
 
@@ -450,3 +588,62 @@ func HandleCrudBatch(modules *modules.Modules) http.HandlerFunc {
 		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
 	}
 }
+
+// HandleGetSlowQueries returns the query latency distribution and the recent slow queries of a database
+func HandleGetSlowQueries(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+		col := r.URL.Query().Get("col")
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		if _, err := adminMan.IsTokenValid(ctx, token, "db-config", "read", map[string]string{"project": projectID, "db": dbAlias}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		crud, err := modules.DB(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		stats, err := crud.GetQueryStats(ctx, dbAlias, col)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: stats})
+	}
+}
+
+// getUpdateErrorStatus returns the status code for an error of an update. Updates made against a stale version of a
+// document result in a conflict.
+func getUpdateErrorStatus(err error) int {
+	if errors.Is(err, utils.ErrVersionConflict) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// sendWriteErrorResponse sends the error of a write operation. Documents violating the validation directives of the
+// schema are rejected with the violated rule in the details of the error.
+func sendWriteErrorResponse(ctx context.Context, w http.ResponseWriter, err error) {
+	status := getUpdateErrorStatus(err)
+	var validationErr *utils.ValidationError
+	if errors.As(err, &validationErr) {
+		status = http.StatusBadRequest
+	}
+	_ = utils.SendErrorResponse(ctx, w, status, err)
+}
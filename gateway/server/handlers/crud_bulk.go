@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/modules/crud"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/docformat"
+)
+
+// HandleCrudImport creates the endpoint to import documents in a collection from a ndjson or csv file. Admin tokens
+// bypass the security rules while the create rule of the collection is evaluated for every document otherwise.
+func HandleCrudImport(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer utils.CloseTheCloser(r.Body)
+
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		format, batchSize, err := getBulkParams(r, r.Header.Get("Content-Type"))
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		attr := map[string]string{"project": meta.projectID, "db": meta.dbType, "col": meta.col}
+		reqParams := model.RequestParams{Resource: "db-create", Op: "access", Attributes: attr}
+		var authorise func(ctx context.Context, doc map[string]interface{}) error
+		if err := adminMan.CheckIfAdmin(ctx, meta.token); err != nil {
+			// Check if the user is allowed to import documents at all before reading the file
+			reqParams, err = auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.CreateRequest{Document: []interface{}{}, Operation: utils.All})
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+			authorise = func(ctx context.Context, doc map[string]interface{}) error {
+				_, err := auth.IsCreateOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &model.CreateRequest{Document: doc, Operation: utils.One})
+				return err
+			}
+		}
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		fields, _ := crud.GetSchema(meta.dbType, meta.col)
+		decoder, err := docformat.NewDecoder(format, r.Body, fields)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		result, err := crud.Import(ctx, meta.dbType, meta.col, decoder, batchSize, authorise, reqParams)
+		if err != nil {
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusBadRequest, model.Response{Error: err.Error(), Result: result})
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: result})
+	}
+}
+
+// HandleCrudExport creates the endpoint to stream the documents of a collection as a ndjson or csv file. Admin tokens
+// bypass the security rules while the read rule of the collection is applied otherwise.
+func HandleCrudExport(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		meta := getRequestMetaData(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		format, batchSize, err := getBulkParams(r, r.Header.Get("Accept"))
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		auth, err := modules.Auth(meta.projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		crud, err := modules.DB(meta.projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.ReadRequest{Find: map[string]interface{}{}, Operation: utils.All, Options: new(model.ReadOptions)}
+		attr := map[string]string{"project": meta.projectID, "db": meta.dbType, "col": meta.col}
+		reqParams := model.RequestParams{Resource: "db-read", Op: "access", Attributes: attr}
+		var actions *model.PostProcess
+		if err := adminMan.CheckIfAdmin(ctx, meta.token); err != nil {
+			dbType, _ := crud.GetDBType(meta.dbType)
+			returnWhere := model.ReturnWhereStub{Col: meta.col, ReturnWhere: dbType != string(model.Mongo), Where: map[string]interface{}{}}
+			actions, reqParams, err = auth.IsReadOpAuthorised(ctx, meta.projectID, meta.dbType, meta.col, meta.token, &req, returnWhere)
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+				return
+			}
+			if len(returnWhere.Where) > 0 {
+				req.MatchWhere = append(req.MatchWhere, returnWhere.Where)
+			}
+		}
+		reqParams = utils.ExtractRequestParams(r, reqParams, nil)
+
+		var columns []string
+		if fields, ok := crud.GetSchema(meta.dbType, meta.col); ok {
+			columns = docformat.Columns(fields, nil)
+		}
+		encoder, err := docformat.NewEncoder(format, w, columns)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		wroteHeader := false
+		writeHeader := func() {
+			if wroteHeader {
+				return
+			}
+			wroteHeader = true
+			w.Header().Set("Content-Type", docformat.ContentType(format))
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", meta.col, format))
+			w.WriteHeader(http.StatusOK)
+		}
+
+		err = crud.Export(ctx, meta.dbType, meta.col, &req, batchSize, reqParams, func(docs []interface{}) error {
+			if err := authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, docs); err != nil {
+				return err
+			}
+
+			writeHeader()
+			for _, doc := range docs {
+				obj, ok := doc.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if err := encoder.Encode(obj); err != nil {
+					return err
+				}
+			}
+
+			// Send the page to the client right away
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			// The error can only be reported if nothing has been sent yet
+			if !wroteHeader {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+				return
+			}
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Export of (%s) ended abruptly", meta.col), err, nil)
+			return
+		}
+
+		// Collections without any documents still get the header of the csv file
+		writeHeader()
+		_ = encoder.Flush()
+	}
+}
+
+// docsPerFlush is the number of documents of a read query after which the response is flushed to the client
+const docsPerFlush = 100
+
+// sendDocuments writes the result of a read query in the csv or ndjson format if the accept header of the request
+// asks for it. It returns false if the result needs to be sent as json instead.
+func sendDocuments(ctx context.Context, w http.ResponseWriter, r *http.Request, columns []string, result interface{}) bool {
+	format, ok := docformat.FromAccept(r.Header.Get("Accept"))
+	if !ok {
+		return false
+	}
+
+	var docs []interface{}
+	switch v := result.(type) {
+	case []interface{}:
+		docs = v
+	case map[string]interface{}:
+		docs = []interface{}{v}
+	default:
+		return false
+	}
+
+	encoder, err := docformat.NewEncoder(format, w, columns)
+	if err != nil {
+		return false
+	}
+
+	w.Header().Set("Content-Type", docformat.ContentType(format))
+	w.WriteHeader(http.StatusOK)
+
+	flusher, canFlush := w.(http.Flusher)
+	for i, doc := range docs {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := encoder.Encode(obj); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to encode the result of the read query", err, nil)
+			return true
+		}
+		if (i+1)%docsPerFlush == 0 && canFlush {
+			_ = encoder.Flush()
+			flusher.Flush()
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to encode the result of the read query", err, nil)
+	}
+	return true
+}
+
+// streamDocuments sends the documents of a read query as they are read from the database as ndjson, or csv if the
+// accept header asks for it. Writes to a slow client block the read, which keeps the memory used by the gateway
+// bounded. Errors occurring before the first document is sent are returned as a regular error response, while the
+// stream is simply cut short for the ones occurring later.
+func streamDocuments(ctx context.Context, w http.ResponseWriter, r *http.Request, crudModule *crud.Module, aesKey []byte, dbAlias, col string, columns []string, req *model.ReadRequest, reqParams model.RequestParams, actions *model.PostProcess) {
+	format, ok := docformat.FromAccept(r.Header.Get("Accept"))
+	if !ok {
+		format = docformat.NDJSON
+	}
+
+	encoder, err := docformat.NewEncoder(format, w, columns)
+	if err != nil {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var count int
+	flusher, canFlush := w.(http.Flusher)
+	err = crudModule.ReadStream(ctx, dbAlias, col, req, reqParams, func(doc map[string]interface{}) error {
+		if count == 0 {
+			w.Header().Set("Content-Type", docformat.ContentType(format))
+			w.WriteHeader(http.StatusOK)
+		}
+		count++
+
+		_ = authHelpers.PostProcessMethod(ctx, aesKey, actions, doc)
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		if count%docsPerFlush == 0 && canFlush {
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil && count == 0 {
+		_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Stream of collection (%s) ended after %d documents", col, count), err, nil)
+		return
+	}
+
+	if count == 0 {
+		w.Header().Set("Content-Type", docformat.ContentType(format))
+		w.WriteHeader(http.StatusOK)
+	}
+	if err := encoder.Flush(); err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to encode the result of the read query", err, nil)
+	}
+}
+
+// getReadColumns returns the columns of a csv response of a read query. The fields of the collection are used unless
+// the query changes the shape of the documents, in which case the columns are taken from the first document.
+func getReadColumns(fields model.Fields, req *model.ReadRequest) []string {
+	if len(fields) == 0 || len(req.GroupBy) > 0 || len(req.Aggregate) > 0 || (req.Options != nil && (len(req.Options.Select) > 0 || len(req.Options.Join) > 0 || req.Options.Distinct != nil)) {
+		return nil
+	}
+	return docformat.Columns(fields, nil)
+}
+
+// getBulkParams returns the format and batch size of a bulk request. The format is taken from the query params and
+// falls back to the provided content type header.
+func getBulkParams(r *http.Request, contentType string) (string, int, error) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		f, ok := docformat.FromAccept(contentType)
+		if !ok {
+			return "", 0, fmt.Errorf("format of the documents should be provided either with the format query param or the %s or %s content types", docformat.ContentTypeNDJSON, docformat.ContentTypeCSV)
+		}
+		format = f
+	}
+	if err := docformat.Validate(format); err != nil {
+		return "", 0, err
+	}
+
+	batchSize := 0
+	if s := query.Get("batchSize"); s != "" {
+		size, err := strconv.Atoi(s)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid batch size (%s) provided - %v", s, err)
+		}
+		batchSize = size
+	}
+	return format, batchSize, nil
+}
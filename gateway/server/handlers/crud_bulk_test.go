@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestSendDocuments(t *testing.T) {
+	docs := []interface{}{
+		map[string]interface{}{"id": "1", "name": "a"},
+		map[string]interface{}{"id": "2", "name": "b"},
+	}
+
+	tests := []struct {
+		name            string
+		accept          string
+		columns         []string
+		result          interface{}
+		wantSent        bool
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "json is the default", accept: "", result: docs, wantSent: false},
+		{name: "json is preferred", accept: "application/json, text/csv", result: docs, wantSent: false},
+		{name: "csv", accept: "text/csv", columns: []string{"id", "name"}, result: docs, wantSent: true, wantContentType: "text/csv", wantBody: "id,name\n1,a\n2,b\n"},
+		{name: "ndjson", accept: "application/x-ndjson", result: docs, wantSent: true, wantContentType: "application/x-ndjson", wantBody: "{\"id\":\"1\",\"name\":\"a\"}\n{\"id\":\"2\",\"name\":\"b\"}\n"},
+		{name: "single document", accept: "text/csv", result: docs[0], wantSent: true, wantContentType: "text/csv", wantBody: "id,name\n1,a\n"},
+		{name: "unsupported result", accept: "text/csv", result: int64(2), wantSent: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/api/project/crud/db/col/read", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			if got := sendDocuments(context.Background(), w, r, tt.columns, tt.result); got != tt.wantSent {
+				t.Fatalf("sendDocuments() = %v, want %v", got, tt.wantSent)
+			}
+			if !tt.wantSent {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("sendDocuments() content type = %v, want %v", got, tt.wantContentType)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("sendDocuments() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestGetReadColumns(t *testing.T) {
+	fields := model.Fields{"id": &model.FieldType{FieldName: "id", IsPrimary: true}, "name": &model.FieldType{FieldName: "name"}}
+	distinct := "name"
+
+	tests := []struct {
+		name   string
+		fields model.Fields
+		req    *model.ReadRequest
+		want   []string
+	}{
+		{name: "fields of the collection", fields: fields, req: &model.ReadRequest{}, want: []string{"id", "name"}},
+		{name: "no schema", req: &model.ReadRequest{}, want: nil},
+		{name: "select", fields: fields, req: &model.ReadRequest{Options: &model.ReadOptions{Select: map[string]int32{"name": 1}}}, want: nil},
+		{name: "distinct", fields: fields, req: &model.ReadRequest{Options: &model.ReadOptions{Distinct: &distinct}}, want: nil},
+		{name: "aggregate", fields: fields, req: &model.ReadRequest{Aggregate: map[string][]string{"count": {"id"}}}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getReadColumns(tt.fields, tt.req)
+			if len(got) != len(tt.want) {
+				t.Fatalf("getReadColumns() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("getReadColumns() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
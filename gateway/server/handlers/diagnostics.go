@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	runtimePprof "runtime/pprof"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// diagnosticProfiles are the named pprof profiles served by the diagnostics endpoints. The cpu profile and the
+// execution trace are served as profile and trace.
+var diagnosticProfiles = map[string]bool{"heap": true, "allocs": true, "goroutine": true, "block": true, "mutex": true, "threadcreate": true}
+
+// HandleGetProfile returns the handler serving the pprof profiles of this gateway. The cpu profile and the execution
+// trace are captured for the duration provided in the seconds query param.
+func HandleGetProfile(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handleDiagnostics(adminMan, modules, func(w http.ResponseWriter, r *http.Request) {
+		switch profile := mux.Vars(r)["profile"]; {
+		case profile == "profile":
+			pprof.Profile(w, r)
+		case profile == "trace":
+			pprof.Trace(w, r)
+		case diagnosticProfiles[profile]:
+			pprof.Handler(profile).ServeHTTP(w, r)
+		default:
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, fmt.Errorf("unknown profile (%s) provided", profile))
+		}
+	})
+}
+
+// HandleGetRuntimeStats returns the handler serving the memory and gc stats of this gateway
+func HandleGetRuntimeStats(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handleDiagnostics(adminMan, modules, func(w http.ResponseWriter, r *http.Request) {
+		_ = helpers.Response.SendResponse(r.Context(), w, http.StatusOK, model.Response{Result: modules.Diagnostics().GetRuntimeStats()})
+	})
+}
+
+// HandleGetGoroutineDump returns the handler serving the stack traces of all the goroutines of this gateway
+func HandleGetGoroutineDump(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handleDiagnostics(adminMan, modules, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := runtimePprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "Unable to write goroutine dump", err, nil)
+		}
+	})
+}
+
+// handleDiagnostics serves the diagnostics to the admins while they are enabled in the cluster config
+func handleDiagnostics(adminMan *admin.Manager, modules *modules.Modules, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		defer utils.CloseTheCloser(r.Body)
+
+		// The profiles are captured with the context of the request, hence the timeout only applies to the authorisation
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		if _, err := adminMan.IsTokenValid(ctx, token, "diagnostics", "read", map[string]string{}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !modules.Diagnostics().IsEnabled() {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, errors.New("diagnostics are disabled - enable them in the cluster config"))
+			return
+		}
+
+		fn(w, r)
+	}
+}
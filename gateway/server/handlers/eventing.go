@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/spaceuptech/helpers"
@@ -30,9 +32,7 @@ func HandleAdminQueueEvent(adminMan *admin.Manager, modules *modules.Modules) ht
 
 		eventing, err := modules.Eventing(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -44,7 +44,7 @@ func HandleAdminQueueEvent(adminMan *admin.Manager, modules *modules.Modules) ht
 		// Return if the eventing module is not enabled
 		if !eventing.IsEnabled() {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling queue event request eventing feature isn't enabled", nil, nil)
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
 			return
 		}
 
@@ -53,14 +53,14 @@ func HandleAdminQueueEvent(adminMan *admin.Manager, modules *modules.Modules) ht
 
 		// Get the JWT token from header
 		if err := adminMan.CheckIfAdmin(ctx, utils.GetTokenFromHeader(r)); err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
 			return
 		}
 
 		// Queue the event
 		if err := eventing.QueueAdminEvent(ctx, req.Events); err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling queue event request", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -77,9 +77,7 @@ func HandleQueueEvent(modules *modules.Modules) http.HandlerFunc {
 
 		eventing, err := modules.Eventing(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 		// Load the params from the body
@@ -90,7 +88,7 @@ func HandleQueueEvent(modules *modules.Modules) http.HandlerFunc {
 		// Return if the eventing module is not enabled
 		if !eventing.IsEnabled() {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling queue event request eventing feature isn't enabled", nil, nil)
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
 			return
 		}
 
@@ -103,7 +101,7 @@ func HandleQueueEvent(modules *modules.Modules) http.HandlerFunc {
 		res, err := eventing.QueueEvent(ctx, projectID, token, &req)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling queue event request", err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -114,3 +112,283 @@ func HandleQueueEvent(modules *modules.Modules) http.HandlerFunc {
 		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
 	}
 }
+
+// HandleIngestEvent creates an endpoint which queues the webhooks received by a custom event source as events
+func HandleIngestEvent(modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		source := vars["source"]
+
+		eventing, err := modules.Eventing(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// The raw body is required to verify the signature of the source
+		body, err := ioutil.ReadAll(r.Body)
+		defer utils.CloseTheCloser(r.Body)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Return if the eventing module is not enabled
+		if !eventing.IsEnabled() {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling ingest event request eventing feature isn't enabled", nil, nil)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		status, err := eventing.IngestEvent(ctx, source, utils.GetTokenFromHeader(r), r.Header, body)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendOkayResponse(ctx, status, w)
+	}
+}
+
+// HandleGetEventLogs creates an endpoint to browse the event log of the project. Events can be filtered by trigger,
+// status and time window using the query parameters.
+func HandleGetEventLogs(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		eventing, err := modules.Eventing(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Return if the eventing module is not enabled
+		if !eventing.IsEnabled() {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling get event logs request eventing feature isn't enabled", nil, nil)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		// Get the JWT token from header
+		if err := adminMan.CheckIfAdmin(ctx, utils.GetTokenFromHeader(r)); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := &model.EventLogFilter{Trigger: query.Get("trigger"), Status: query.Get("status"), From: query.Get("from"), To: query.Get("to")}
+		if id := query.Get("id"); id != "" {
+			filter.IDs = []string{id}
+		}
+		if skip, err := strconv.ParseInt(query.Get("skip"), 10, 64); err == nil {
+			filter.Skip = skip
+		}
+		if limit, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil {
+			filter.Limit = limit
+		}
+
+		events, err := eventing.GetEventLogs(ctx, filter)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": events})
+	}
+}
+
+// HandleReplayEvents creates an endpoint to re-deliver the processed and failed events matching a filter
+func HandleReplayEvents(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		eventing, err := modules.Eventing(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the filter from the body
+		filter := new(model.EventLogFilter)
+		if err := json.NewDecoder(r.Body).Decode(filter); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+		defer utils.CloseTheCloser(r.Body)
+
+		// Return if the eventing module is not enabled
+		if !eventing.IsEnabled() {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling replay events request eventing feature isn't enabled", nil, nil)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		// Get the JWT token from header
+		if err := adminMan.CheckIfAdmin(ctx, utils.GetTokenFromHeader(r)); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			return
+		}
+
+		ids, err := eventing.ReplayEvents(ctx, filter)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": ids})
+	}
+}
+
+// HandleGetDeadLetterEvents creates an endpoint to list the events in the dead letter queue along with the history of
+// their invocations. Events can be filtered by trigger and time window using the query parameters.
+func HandleGetDeadLetterEvents(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		eventing, err := modules.Eventing(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Return if the eventing module is not enabled
+		if !eventing.IsEnabled() {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling get dead letter events request eventing feature isn't enabled", nil, nil)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		// Get the JWT token from header
+		if err := adminMan.CheckIfAdmin(ctx, utils.GetTokenFromHeader(r)); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := &model.EventLogFilter{Trigger: query.Get("trigger"), From: query.Get("from"), To: query.Get("to")}
+		if id := query.Get("id"); id != "" {
+			filter.IDs = []string{id}
+		}
+		if skip, err := strconv.ParseInt(query.Get("skip"), 10, 64); err == nil {
+			filter.Skip = skip
+		}
+		if limit, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil {
+			filter.Limit = limit
+		}
+
+		events, err := eventing.GetDeadLetterEvents(ctx, filter)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": events})
+	}
+}
+
+// HandleRetryDeadLetterEvents creates an endpoint to queue the events in the dead letter queue matching a filter once again
+func HandleRetryDeadLetterEvents(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		eventing, err := modules.Eventing(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the filter from the body
+		filter := new(model.EventLogFilter)
+		if err := json.NewDecoder(r.Body).Decode(filter); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+		defer utils.CloseTheCloser(r.Body)
+
+		// Return if the eventing module is not enabled
+		if !eventing.IsEnabled() {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling retry dead letter events request eventing feature isn't enabled", nil, nil)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		// Get the JWT token from header
+		if err := adminMan.CheckIfAdmin(ctx, utils.GetTokenFromHeader(r)); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			return
+		}
+
+		ids, err := eventing.RetryDeadLetterEvents(ctx, filter)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"result": ids})
+	}
+}
+
+// HandlePurgeDeadLetterEvents creates an endpoint to delete the events in the dead letter queue matching a filter
+func HandlePurgeDeadLetterEvents(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		eventing, err := modules.Eventing(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Load the filter from the body
+		filter := new(model.EventLogFilter)
+		if err := json.NewDecoder(r.Body).Decode(filter); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+		defer utils.CloseTheCloser(r.Body)
+
+		// Return if the eventing module is not enabled
+		if !eventing.IsEnabled() {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(r.Context()), "error handling purge dead letter events request eventing feature isn't enabled", nil, nil)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusNotFound, errors.New("This feature isn't enabled"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		// Get the JWT token from header
+		if err := adminMan.CheckIfAdmin(ctx, utils.GetTokenFromHeader(r)); err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			return
+		}
+
+		if err := eventing.PurgeDeadLetterEvents(ctx, filter); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
+	}
+}
@@ -35,9 +35,7 @@ func HandleCreateFile(modules *modules.Modules) http.HandlerFunc {
 
 		fileStore, err := modules.File(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -51,7 +49,7 @@ func HandleCreateFile(modules *modules.Modules) http.HandlerFunc {
 			err = r.ParseForm()
 		}
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, fmt.Errorf("Could not parse form: %s", err))
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, fmt.Errorf("Could not parse form: %s", err))
 			return
 		}
 
@@ -65,7 +63,7 @@ func HandleCreateFile(modules *modules.Modules) http.HandlerFunc {
 		if makeAllString != "" {
 			makeAll, err = strconv.ParseBool(makeAllString)
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("Incorrect value for makeAll"))
+				_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("Incorrect value for makeAll"))
 				return
 			}
 		}
@@ -73,7 +71,7 @@ func HandleCreateFile(modules *modules.Modules) http.HandlerFunc {
 		if fileType == "file" {
 			file, header, err := r.FormFile("file")
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("Incorrect value for file: %s", err))
+				_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("Incorrect value for file: %s", err))
 				return
 			}
 			defer utils.CloseTheCloser(file)
@@ -89,7 +87,7 @@ func HandleCreateFile(modules *modules.Modules) http.HandlerFunc {
 
 			status, err := fileStore.UploadFile(ctx, projectID, token, &model.CreateFileRequest{Name: fileName, Path: path, Type: fileType, MakeAll: makeAll, Meta: v}, file)
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+				_ = utils.SendErrorResponse(ctx, w, status, err)
 				return
 			}
 			_ = helpers.Response.SendResponse(ctx, w, status, map[string]string{})
@@ -97,7 +95,7 @@ func HandleCreateFile(modules *modules.Modules) http.HandlerFunc {
 			name := r.FormValue("name")
 			status, err := fileStore.CreateDir(ctx, projectID, token, &model.CreateFileRequest{Name: name, Path: path, Type: fileType, MakeAll: makeAll}, v)
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+				_ = utils.SendErrorResponse(ctx, w, status, err)
 				return
 			}
 			_ = helpers.Response.SendResponse(ctx, w, status, map[string]string{})
@@ -114,9 +112,7 @@ func HandleRead(modules *modules.Modules) http.HandlerFunc {
 
 		fileStore, err := modules.File(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -130,14 +126,14 @@ func HandleRead(modules *modules.Modules) http.HandlerFunc {
 			mode := r.URL.Query().Get("mode")
 			status, res, err := fileStore.ListFiles(ctx, projectID, token, &model.ListFilesRequest{Path: path, Type: mode})
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+				_ = utils.SendErrorResponse(ctx, w, status, err)
 				return
 			}
 			_ = helpers.Response.SendResponse(ctx, w, status, map[string]interface{}{"result": res})
 			return
 		} else if op == "exist" {
 			if err := fileStore.DoesExists(ctx, projectID, token, path); err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusNotFound, err)
+				_ = utils.SendErrorResponse(ctx, w, http.StatusNotFound, err)
 				return
 			}
 			_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
@@ -147,7 +143,7 @@ func HandleRead(modules *modules.Modules) http.HandlerFunc {
 		// Read the file from file storage
 		status, file, err := fileStore.DownloadFile(ctx, projectID, token, path)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		defer func() { _ = file.Close() }()
@@ -172,9 +168,7 @@ func HandleDelete(modules *modules.Modules) http.HandlerFunc {
 
 		fileStore, err := modules.File(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -187,14 +181,14 @@ func HandleDelete(modules *modules.Modules) http.HandlerFunc {
 		if fileType == "file" {
 			status, err := fileStore.DeleteFile(ctx, projectID, token, path, v)
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+				_ = utils.SendErrorResponse(ctx, w, status, err)
 				return
 			}
 			_ = helpers.Response.SendResponse(ctx, w, status, map[string]string{})
 		} else if fileType == "dir" {
 			status, err := fileStore.DeleteDir(ctx, projectID, token, path, v)
 			if err != nil {
-				_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+				_ = utils.SendErrorResponse(ctx, w, status, err)
 				return
 			}
 			_ = helpers.Response.SendResponse(ctx, w, status, map[string]string{})
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/fixtures"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+const fixturesTimeout = 5 * time.Minute
+
+// HandleLoadFixtures loads the fixtures of a project. It is only available on gateways running in test mode.
+func HandleLoadFixtures(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handleFixtures(adminMan, modules, func(ctx context.Context, m *fixtures.Module) (*model.FixturesResult, error) {
+		return m.Load(ctx)
+	})
+}
+
+// HandleTeardownFixtures removes the fixtures of a project. It is only available on gateways running in test mode.
+func HandleTeardownFixtures(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handleFixtures(adminMan, modules, func(ctx context.Context, m *fixtures.Module) (*model.FixturesResult, error) {
+		return m.Teardown(ctx)
+	})
+}
+
+func handleFixtures(adminMan *admin.Manager, modules *modules.Modules, fn func(ctx context.Context, m *fixtures.Module) (*model.FixturesResult, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		projectID := mux.Vars(r)["project"]
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), fixturesTimeout)
+		defer cancel()
+
+		if _, err := adminMan.IsTokenValid(ctx, token, "fixtures", "modify", map[string]string{"project": projectID}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		fixturesMod, err := modules.Fixtures(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		if !fixturesMod.IsTestMode() {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, fixtures.ErrTestModeDisabled)
+			return
+		}
+
+		result, err := fn(ctx, fixturesMod)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: result})
+	}
+}
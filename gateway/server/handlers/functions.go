@@ -28,17 +28,13 @@ func HandleFunctionCall(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
 		functions, err := modules.Functions(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -52,7 +48,7 @@ func HandleFunctionCall(modules *modules.Modules) http.HandlerFunc {
 
 		timeOut, err := functions.GetEndpointContextTimeout(r.Context(), projectID, serviceID, function)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -62,7 +58,7 @@ func HandleFunctionCall(modules *modules.Modules) http.HandlerFunc {
 
 		actions, reqParams, err := auth.IsFuncCallAuthorised(ctx, projectID, serviceID, function, token, req.Params)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusForbidden, err)
 			return
 		}
 
@@ -71,7 +67,7 @@ func HandleFunctionCall(modules *modules.Modules) http.HandlerFunc {
 		status, result, err := functions.CallWithContext(ctx, serviceID, function, token, reqParams, &req)
 		if err != nil {
 			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Receieved error from service call (%s:%s)", serviceID, function), err, nil)
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 
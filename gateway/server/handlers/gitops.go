@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/gitops"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleGetGitOpsStatus returns the status of the recent syncs of the gitops repository along with the drift of the cluster
+func HandleGetGitOpsStatus(adminMan *admin.Manager, gitOpsMan *gitops.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if err := adminMan.CheckAdminScope(ctx, token, config.AdminScopeConfigRead); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		status, result, err := gitOpsMan.GetStatus(ctx)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: result})
+	}
+}
+
+// HandleGitOpsSync syncs the config of the gitops repository right away. The config gets applied even if
+// the latest commit has already been synced, which reverts the drift of the cluster.
+func HandleGitOpsSync(adminMan *admin.Manager, gitOpsMan *gitops.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+		defer cancel()
+
+		if err := adminMan.CheckAdminScope(ctx, token, config.AdminScopeConfigWrite); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !gitOpsMan.IsEnabled() {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "GitOps mode is not enabled", nil, nil))
+			return
+		}
+
+		record, err := gitOpsMan.Sync(ctx, model.GitOpsTriggerManual)
+		if err != nil {
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusInternalServerError, model.Response{Result: record, Error: err.Error()})
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: record})
+	}
+}
+
+// HandleGitOpsWebhook triggers a sync of the gitops repository on receiving a push webhook of the git provider
+func HandleGitOpsWebhook(gitOpsMan *gitops.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if !gitOpsMan.IsEnabled() {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, helpers.Logger.LogError(helpers.GetRequestID(ctx), "GitOps mode is not enabled", nil, nil))
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := gitOpsMan.VerifyWebhook(r.Header, body); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		gitOpsMan.TriggerSync(model.GitOpsTriggerWebhook)
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusAccepted, w)
+	}
+}
@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
 	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules"
@@ -23,7 +29,7 @@ func HandleGraphQLRequest(modules *modules.Modules, syncMan *syncman.Manager) ht
 
 		projectConfig, err := syncMan.GetConfig(projectID)
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -37,41 +43,142 @@ func HandleGraphQLRequest(modules *modules.Modules, syncMan *syncman.Manager) ht
 
 		graphql, err := modules.GraphQL(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
-		// Load the request from the body
-		req := model.GraphQLRequest{}
-		_ = json.NewDecoder(r.Body).Decode(&req)
-		defer utils.CloseTheCloser(r.Body)
-
 		// Get the path parameters
 		token := getRequestMetaData(r).token
+		timeout := time.Duration(projectConfig.ContextTimeGraphQL) * time.Second
 
-		ch := make(chan struct{}, 1)
-
-		graphql.ExecGraphQLQuery(ctx, &req, token, func(op interface{}, err error) {
-			defer func() { ch <- struct{}{} }()
-			if err != nil {
-				errMes := map[string]interface{}{"message": err.Error()}
-				_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"errors": []interface{}{errMes}})
+		// Load the request from the query params for get requests, which clients use to send persisted queries
+		defer utils.CloseTheCloser(r.Body)
+		if r.Method == http.MethodGet {
+			req := model.GraphQLRequest{}
+			if err := getGraphQLRequestFromQuery(r, &req); err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 				return
 			}
-			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"data": op})
-		})
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, execGraphQLOperation(ctx, graphql, &req, token, timeout))
+			return
+		}
 
-		select {
-		case <-ch:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
-		case <-time.After(time.Duration(projectConfig.ContextTimeGraphQL) * time.Second):
-			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "GraphQL Handler: Request timed out", nil)
-			errMes := map[string]interface{}{"message": "GraphQL Handler: Request timed out"}
-			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, map[string]interface{}{"errors": []interface{}{errMes}})
+		}
+
+		// The body is either a single operation or an array of operations to be executed in a batch
+		if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+			req := model.GraphQLRequest{}
+			_ = json.Unmarshal(body, &req)
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, execGraphQLOperation(ctx, graphql, &req, token, timeout))
+			return
+		}
+
+		reqs := make([]*model.GraphQLRequest, 0)
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
+		limits := projectConfig.GraphQLLimits
+		if limits == nil {
+			limits = new(config.GraphQLLimits)
+		}
+		if limits.MaxBatchSize > 0 && len(reqs) > limits.MaxBatchSize {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("batch of %d operations exceeds the max batch size (%d) allowed by the project", len(reqs), limits.MaxBatchSize))
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, execGraphQLBatch(ctx, graphql, reqs, token, timeout, limits.BatchParallelism))
+	}
+
+}
+
+// execGraphQLBatch executes the operations of a batch and returns their responses in the same order. The operations
+// are executed one after the other unless a parallelism greater than one is provided.
+func execGraphQLBatch(ctx context.Context, graphql modules.GraphQLInterface, reqs []*model.GraphQLRequest, token string, timeout time.Duration, parallelism int) []interface{} {
+	if parallelism < 1 {
+		parallelism = 1
 	}
 
+	results := make([]interface{}, len(reqs))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, req *model.GraphQLRequest) {
+			defer func() { <-sem; wg.Done() }()
+			results[i] = execGraphQLOperation(ctx, graphql, req, token, timeout)
+		}(i, req)
+	}
+	wg.Wait()
+	return results
+}
+
+// execGraphQLOperation executes a graphql operation and returns its response
+func execGraphQLOperation(ctx context.Context, graphql modules.GraphQLInterface, req *model.GraphQLRequest, token string, timeout time.Duration) map[string]interface{} {
+	ch := make(chan map[string]interface{}, 1)
+	graphql.ExecGraphQLQuery(ctx, req, token, func(op interface{}, err error) {
+		if err != nil {
+			ch <- map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": err.Error()}}}
+			return
+		}
+		ch <- map[string]interface{}{"data": op}
+	})
+
+	select {
+	case res := <-ch:
+		return res
+	case <-time.After(timeout):
+		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "GraphQL Handler: Request timed out", nil)
+		return map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": "GraphQL Handler: Request timed out"}}}
+	}
+}
+
+func getGraphQLRequestFromQuery(r *http.Request, req *model.GraphQLRequest) error {
+	query := r.URL.Query()
+	req.Query = query.Get("query")
+	req.OperationName = query.Get("operationName")
+	if v := query.Get("variables"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+			return fmt.Errorf("invalid variables provided in query params - %v", err)
+		}
+	}
+	if v := query.Get("extensions"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Extensions); err != nil {
+			return fmt.Errorf("invalid extensions provided in query params - %v", err)
+		}
+	}
+	return nil
+}
+
+// HandleGetGraphQLSchema exports the graphql schema generated for the project in the schema definition language, so
+// that typed clients can be generated from it. Only admins can export it when introspection is disabled.
+func HandleGetGraphQLSchema(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		graphql, err := modules.GraphQL(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		if graphql.IsIntrospectionDisabled() {
+			token := utils.GetTokenFromHeader(r)
+			if _, err := adminMan.IsTokenValid(ctx, token, "graphql-schema", "read", map[string]string{"project": projectID}); err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(graphql.GetSDL()))
+	}
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_execGraphQLBatch(t *testing.T) {
+	reqs := []*model.GraphQLRequest{{Query: "query { a }"}, {Query: "query { b }"}, {Query: "query { c }"}}
+	want := []interface{}{
+		map[string]interface{}{"data": "query { a }"},
+		map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": "failed"}}},
+		map[string]interface{}{"data": "query { c }"},
+	}
+
+	for _, parallelism := range []int{0, 1, 2, 5} {
+		graph := &mockGraphQLModule{}
+		graph.On("ExecGraphQLQuery", mock.Anything, mock.Anything, "token", mock.Anything).Run(func(args mock.Arguments) {
+			req := args.Get(1).(*model.GraphQLRequest)
+			cb := args.Get(3).(model.GraphQLCallback)
+			if req.Query == "query { b }" {
+				cb(nil, errors.New("failed"))
+				return
+			}
+			cb(req.Query, nil)
+		})
+
+		if got := execGraphQLBatch(context.Background(), graph, reqs, "token", time.Second, parallelism); !reflect.DeepEqual(got, want) {
+			t.Errorf("execGraphQLBatch() with parallelism %d = %v, want %v", parallelism, got, want)
+		}
+	}
+}
@@ -3,11 +3,16 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/spaceuptech/helpers"
 
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
 	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
 // HandleHealthCheck check health of gateway
@@ -18,10 +23,121 @@ func HandleHealthCheck(syncMan *syncman.Manager) http.HandlerFunc {
 		defer cancel()
 
 		if err := syncMan.HealthCheck(); err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
 			return
 		}
 
 		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
 	}
 }
+
+// HandleLivenessCheck reports if the gateway process is able to serve requests. It doesn't check any dependencies
+// so that the gateway doesn't get restarted when a database or the broker goes down.
+func HandleLivenessCheck(syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		status := http.StatusOK
+		res := &model.HealthResponse{Status: model.HealthStatusUp, NodeID: syncMan.GetNodeID()}
+		if err := syncMan.HealthCheck(); err != nil {
+			status = http.StatusServiceUnavailable
+			res.Status = model.HealthStatusDown
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, res)
+	}
+}
+
+// HandleReadinessCheck reports if the gateway is ready to receive traffic by checking its cluster membership and
+// the connectivity to the broker and all configured databases
+func HandleReadinessCheck(syncMan *syncman.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		// Check the cluster and the databases in parallel
+		var clusterComponents, dbComponents []*model.ComponentHealth
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			clusterComponents = syncMan.CheckClusterHealth(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			dbComponents = modules.CheckDatabaseHealth(ctx)
+		}()
+		wg.Wait()
+
+		status := http.StatusOK
+		res := &model.HealthResponse{Status: model.HealthStatusUp, NodeID: syncMan.GetNodeID(), Components: append(clusterComponents, dbComponents...)}
+		for _, c := range res.Components {
+			if c.Status != model.HealthStatusUp {
+				status = http.StatusServiceUnavailable
+				res.Status = model.HealthStatusDown
+				break
+			}
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, status, res)
+	}
+}
+
+// HandleGetClusterRole returns whether this node is the leader or a follower in the cluster
+func HandleGetClusterRole(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		if _, err := adminMan.IsTokenValid(ctx, token, "cluster", "read", map[string]string{}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		role, err := syncMan.GetClusterRole(ctx)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: role})
+	}
+}
+
+// HandleGetClusterTopology returns every node of the cluster along with its role, health and loaded projects
+func HandleGetClusterTopology(adminMan *admin.Manager, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		if _, err := adminMan.IsTokenValid(ctx, token, "cluster", "read", map[string]string{}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		topology, err := syncMan.GetClusterTopology(ctx)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: topology})
+	}
+}
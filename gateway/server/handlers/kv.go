@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/kv"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleGetKey returns the value of a key of the key value store
+func HandleGetKey(modules *modules.Modules) http.HandlerFunc {
+	return handleKVRequest(modules, func(ctx context.Context, m *kv.Module, namespace, key, token string, r *http.Request) (int, interface{}, error) {
+		return m.Get(ctx, namespace, key, token)
+	})
+}
+
+// HandleSetKey sets the value of a key of the key value store
+func HandleSetKey(modules *modules.Modules) http.HandlerFunc {
+	return handleKVRequest(modules, func(ctx context.Context, m *kv.Module, namespace, key, token string, r *http.Request) (int, interface{}, error) {
+		req := new(model.KVSetRequest)
+		defer utils.CloseTheCloser(r.Body)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, err := m.Set(ctx, namespace, key, token, req)
+		return status, nil, err
+	})
+}
+
+// HandleDeleteKey deletes a key of the key value store
+func HandleDeleteKey(modules *modules.Modules) http.HandlerFunc {
+	return handleKVRequest(modules, func(ctx context.Context, m *kv.Module, namespace, key, token string, r *http.Request) (int, interface{}, error) {
+		status, err := m.Delete(ctx, namespace, key, token)
+		return status, nil, err
+	})
+}
+
+// HandleListKeys returns the keys of a namespace of the key value store starting with the prefix query parameter
+func HandleListKeys(modules *modules.Modules) http.HandlerFunc {
+	return handleKVRequest(modules, func(ctx context.Context, m *kv.Module, namespace, key, token string, r *http.Request) (int, interface{}, error) {
+		return m.List(ctx, namespace, r.URL.Query().Get("prefix"), token)
+	})
+}
+
+// handleKVRequest loads the key value store of the project before performing the operation. The security rules of the
+// namespace are checked by the key value store.
+func handleKVRequest(modules *modules.Modules, fn func(ctx context.Context, m *kv.Module, namespace, key, token string, r *http.Request) (int, interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		m, err := modules.KV(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		status, result, err := fn(ctx, m, vars["namespace"], vars["key"], token, r)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		if result == nil {
+			_ = helpers.Response.SendOkayResponse(ctx, status, w)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: result})
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleAcquireLock acquires a named lock shared by all nodes of the cluster
+func HandleAcquireLock(modules *modules.Modules, syncMan *syncman.Manager) http.HandlerFunc {
+	return handleLockRequest(modules, func(ctx context.Context, projectID, name string, req *model.LockRequest) (int, interface{}, error) {
+		return syncMan.AcquireLock(ctx, projectID, name, req.TTL)
+	})
+}
+
+// HandleRenewLock extends the lease on a lock held by the owner of the token
+func HandleRenewLock(modules *modules.Modules, syncMan *syncman.Manager) http.HandlerFunc {
+	return handleLockRequest(modules, func(ctx context.Context, projectID, name string, req *model.LockRequest) (int, interface{}, error) {
+		return syncMan.RenewLock(ctx, projectID, name, req.Token, req.TTL)
+	})
+}
+
+// HandleReleaseLock releases a lock held by the owner of the token
+func HandleReleaseLock(modules *modules.Modules, syncMan *syncman.Manager) http.HandlerFunc {
+	return handleLockRequest(modules, func(ctx context.Context, projectID, name string, req *model.LockRequest) (int, interface{}, error) {
+		status, err := syncMan.ReleaseLock(ctx, projectID, name, req.Token)
+		return status, nil, err
+	})
+}
+
+// handleLockRequest authenticates the request with a token of the project before performing the lock operation
+func handleLockRequest(modules *modules.Modules, fn func(ctx context.Context, projectID, name string, req *model.LockRequest) (int, interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get the JWT token from header
+		token := utils.GetTokenFromHeader(r)
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		name := vars["name"]
+
+		req := new(model.LockRequest)
+		_ = json.NewDecoder(r.Body).Decode(req)
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		auth, err := modules.Auth(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Any valid token of the project can use the locks
+		if _, err := auth.ParseToken(ctx, token); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		status, result, err := fn(ctx, projectID, name, req)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		if result == nil {
+			_ = helpers.Response.SendOkayResponse(ctx, status, w)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, model.Response{Result: result})
+	}
+}
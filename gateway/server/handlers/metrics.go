@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/metrics"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandlePrometheusMetrics exports the metrics of this gateway in the prometheus text format
+func HandlePrometheusMetrics(adminMan *admin.Manager, modules *modules.Modules, syncMan *syncman.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Check if the request is authorised
+		if _, err := adminMan.IsTokenValid(ctx, utils.GetTokenFromHeader(r), "metrics", "read", map[string]string{}); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to validate token for prometheus metrics", err, nil)
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		gauges := MetricGauges(syncMan, modules)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := modules.Metrics().WritePrometheusMetrics(w, gauges); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to write prometheus metrics", err, nil)
+		}
+	}
+}
+
+// MetricGauges returns the gauges of the cluster along with the ones of all projects
+func MetricGauges(syncMan *syncman.Manager, modules *modules.Modules) []metrics.Gauge {
+	return append(clusterGauges(syncMan), modules.MetricGauges()...)
+}
+
+func clusterGauges(syncMan *syncman.Manager) []metrics.Gauge {
+	labels := map[string]string{"cluster": syncMan.GetClusterID(), "node": syncMan.GetNodeID()}
+
+	healthy := 1.0
+	if err := syncMan.HealthCheck(); err != nil {
+		healthy = 0
+	}
+
+	leader := 0.0
+	if isLeader, err := syncMan.CheckIfLeaderGateway(syncMan.GetNodeID()); err == nil && isLeader {
+		leader = 1
+	}
+
+	return []metrics.Gauge{
+		{Name: "space_cloud_cluster_healthy", Help: "Whether this gateway is healthy (1) or not (0)", Labels: labels, Value: healthy},
+		{Name: "space_cloud_cluster_leader", Help: "Whether this gateway is the current leader of the cluster (1) or not (0)", Labels: labels, Value: leader},
+		{Name: "space_cloud_cluster_nodes", Help: "Number of gateways in the cluster", Labels: labels, Value: float64(syncMan.GetNodesInCluster())},
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/privacy"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// privacyTimeout is the maximum time the export or erasure of the data of a user may take
+const privacyTimeout = 5 * time.Minute
+
+// HandleExportUserData returns the documents of a user from all the collections holding personal data
+func HandleExportUserData(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handlePrivacyRequest(adminMan, modules, "read", func(ctx context.Context, m *privacy.Module, userID string, req *model.PrivacyRequest, params model.RequestParams) (interface{}, error) {
+		export, err := m.Export(ctx, userID, req, params)
+		if export == nil {
+			return nil, err
+		}
+		return export, err
+	})
+}
+
+// HandleEraseUserData deletes or anonymizes the documents of a user in all the collections holding personal data
+func HandleEraseUserData(adminMan *admin.Manager, modules *modules.Modules) http.HandlerFunc {
+	return handlePrivacyRequest(adminMan, modules, "modify", func(ctx context.Context, m *privacy.Module, userID string, req *model.PrivacyRequest, params model.RequestParams) (interface{}, error) {
+		report, err := m.Erase(ctx, userID, req, params)
+		if report == nil {
+			return nil, err
+		}
+		return report, err
+	})
+}
+
+// handlePrivacyRequest authorises the request and performs it. The result is sent even if the request fails midway,
+// since its report tells what has been done. It is nil if the request couldn't be started.
+func handlePrivacyRequest(adminMan *admin.Manager, modules *modules.Modules, op string, fn func(ctx context.Context, m *privacy.Module, userID string, req *model.PrivacyRequest, params model.RequestParams) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+
+		req := new(model.PrivacyRequest)
+		defer utils.CloseTheCloser(r.Body)
+		_ = json.NewDecoder(r.Body).Decode(req)
+
+		ctx, cancel := context.WithTimeout(r.Context(), privacyTimeout)
+		defer cancel()
+
+		reqParams, err := adminMan.IsTokenValid(ctx, token, "privacy", op, map[string]string{"project": projectID})
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		privacyMod, err := modules.Privacy(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		result, err := fn(ctx, privacyMod, vars["id"], req, reqParams)
+		if err != nil {
+			if result == nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusInternalServerError, model.Response{Error: err.Error(), Result: result})
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: result})
+	}
+}
@@ -19,17 +19,13 @@ func HandleRealtimeEvent(modules *modules.Modules) http.HandlerFunc {
 
 		auth, err := modules.Auth(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
 		realtime, err := modules.Realtime(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -43,12 +39,12 @@ func HandleRealtimeEvent(modules *modules.Modules) http.HandlerFunc {
 
 		// Check if the token is valid
 		if err := auth.IsTokenInternal(r.Context(), token); err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
 			return
 		}
 
 		if err := realtime.HandleRealtimeEvent(r.Context(), &eventDoc); err != nil {
-			_ = helpers.Response.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusForbidden, err)
 			return
 		}
 
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/managers/syncman"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// HandleGetRetentionStatus returns the progress of the retention policies of the collections of a project. Only the
+// leader of the cluster enforces the policies, hence the other nodes fetch the progress from the leader. The local
+// query parameter returns the progress known to the node itself.
+func HandleGetRetentionStatus(adminMan *admin.Manager, syncMan *syncman.Manager, modules *modules.Modules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := utils.GetTokenFromHeader(r)
+		projectID := mux.Vars(r)["project"]
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		if _, err := adminMan.IsTokenValid(ctx, token, "db-rule", "read", map[string]string{"project": projectID, "db": "*", "col": "*"}); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		retentionMod, err := modules.Retention(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		if !syncMan.IsLeader() && r.URL.Query().Get("local") != "true" {
+			status, err := getLeaderRetentionStatus(ctx, syncMan, r.URL.Path, token)
+			if err != nil {
+				_ = utils.SendErrorResponse(ctx, w, http.StatusServiceUnavailable, err)
+				return
+			}
+			_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: status})
+			return
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: retentionMod.GetStatus()})
+	}
+}
+
+// getLeaderRetentionStatus fetches the progress of the retention policies from the leader of the cluster. The leader
+// is asked for its local progress, so that the request isn't forwarded again if the leadership has changed.
+func getLeaderRetentionStatus(ctx context.Context, syncMan *syncman.Manager, path, token string) ([]*model.RetentionStatus, error) {
+	role, err := syncMan.GetClusterRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if role.LeaderURL == "" {
+		return nil, errors.New("leader of the cluster isn't known yet")
+	}
+
+	res := new(struct {
+		Result []*model.RetentionStatus `json:"result"`
+	})
+	if err := syncMan.MakeHTTPRequest(ctx, http.MethodGet, role.LeaderURL+path+"?local=true", token, "", map[string]interface{}{}, res); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to fetch the retention status from the leader", err, map[string]interface{}{"leader": role.LeaderID})
+	}
+	return res.Result, nil
+}
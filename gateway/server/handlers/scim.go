@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/userman"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// scimServiceProviderConfig describes the features of the scim endpoint to the identity providers
+var scimServiceProviderConfig = map[string]interface{}{
+	"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+	"patch":          map[string]interface{}{"supported": true},
+	"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+	"filter":         map[string]interface{}{"supported": true, "maxResults": 0},
+	"changePassword": map[string]interface{}{"supported": true},
+	"sort":           map[string]interface{}{"supported": false},
+	"etag":           map[string]interface{}{"supported": false},
+	"authenticationSchemes": []interface{}{
+		map[string]interface{}{"type": "oauthbearertoken", "name": "OAuth Bearer Token", "description": "Authentication with the bearer token of the scim config"},
+	},
+}
+
+// HandleSCIMServiceProviderConfig returns the handler describing the features of the scim endpoint
+func HandleSCIMServiceProviderConfig(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		return http.StatusOK, scimServiceProviderConfig, nil
+	})
+}
+
+// HandleSCIMListUsers returns the handler for querying the provisioned users
+func HandleSCIMListUsers(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		startIndex, count := getSCIMPagination(r)
+		status, result, err := userManagement.SCIMListUsers(ctx, projectID, r.URL.Query().Get("filter"), startIndex, count)
+		return status, result, err
+	})
+}
+
+// HandleSCIMGetUser returns the handler for fetching a provisioned user
+func HandleSCIMGetUser(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		status, result, err := userManagement.SCIMGetUser(ctx, projectID, mux.Vars(r)["id"])
+		return status, result, err
+	})
+}
+
+// HandleSCIMCreateUser returns the handler for provisioning a user
+func HandleSCIMCreateUser(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		req := new(model.SCIMUser)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, result, err := userManagement.SCIMCreateUser(ctx, projectID, req)
+		return status, result, err
+	})
+}
+
+// HandleSCIMReplaceUser returns the handler for replacing the attributes of a provisioned user
+func HandleSCIMReplaceUser(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		req := new(model.SCIMUser)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, result, err := userManagement.SCIMReplaceUser(ctx, projectID, mux.Vars(r)["id"], req)
+		return status, result, err
+	})
+}
+
+// HandleSCIMPatchUser returns the handler for modifying a provisioned user
+func HandleSCIMPatchUser(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		req := new(model.SCIMPatchRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, result, err := userManagement.SCIMPatchUser(ctx, projectID, mux.Vars(r)["id"], req)
+		return status, result, err
+	})
+}
+
+// HandleSCIMDeleteUser returns the handler for deprovisioning a user
+func HandleSCIMDeleteUser(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		status, err := userManagement.SCIMDeleteUser(ctx, projectID, mux.Vars(r)["id"])
+		return status, nil, err
+	})
+}
+
+// HandleSCIMListGroups returns the handler for querying the provisioned groups
+func HandleSCIMListGroups(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		startIndex, count := getSCIMPagination(r)
+		status, result, err := userManagement.SCIMListGroups(ctx, projectID, r.URL.Query().Get("filter"), startIndex, count)
+		return status, result, err
+	})
+}
+
+// HandleSCIMGetGroup returns the handler for fetching a provisioned group
+func HandleSCIMGetGroup(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		status, result, err := userManagement.SCIMGetGroup(ctx, projectID, mux.Vars(r)["id"])
+		return status, result, err
+	})
+}
+
+// HandleSCIMCreateGroup returns the handler for provisioning a group
+func HandleSCIMCreateGroup(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		req := new(model.SCIMGroup)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, result, err := userManagement.SCIMCreateGroup(ctx, projectID, req)
+		return status, result, err
+	})
+}
+
+// HandleSCIMReplaceGroup returns the handler for replacing the attributes of a provisioned group
+func HandleSCIMReplaceGroup(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		req := new(model.SCIMGroup)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, result, err := userManagement.SCIMReplaceGroup(ctx, projectID, mux.Vars(r)["id"], req)
+		return status, result, err
+	})
+}
+
+// HandleSCIMPatchGroup returns the handler for modifying a provisioned group
+func HandleSCIMPatchGroup(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		req := new(model.SCIMPatchRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return http.StatusBadRequest, nil, err
+		}
+		status, result, err := userManagement.SCIMPatchGroup(ctx, projectID, mux.Vars(r)["id"], req)
+		return status, result, err
+	})
+}
+
+// HandleSCIMDeleteGroup returns the handler for deprovisioning a group
+func HandleSCIMDeleteGroup(modules *modules.Modules) http.HandlerFunc {
+	return handleSCIM(modules, func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error) {
+		status, err := userManagement.SCIMDeleteGroup(ctx, projectID, mux.Vars(r)["id"])
+		return status, nil, err
+	})
+}
+
+// handleSCIM authenticates a scim request and sends the result in the format of scim. The result is only used if
+// no error is returned.
+func handleSCIM(modules *modules.Modules, fn func(ctx context.Context, userManagement *userman.Module, projectID string, r *http.Request) (int, interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID := mux.Vars(r)["project"]
+		defer utils.CloseTheCloser(r.Body)
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			sendSCIMError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		if status, err := userManagement.SCIMAuthenticate(ctx, utils.GetTokenFromHeader(r)); err != nil {
+			sendSCIMError(ctx, w, status, err)
+			return
+		}
+
+		status, result, err := fn(ctx, userManagement, projectID, r)
+		if err != nil {
+			sendSCIMError(ctx, w, status, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/scim+json")
+		w.WriteHeader(status)
+		if status == http.StatusNoContent {
+			return
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Unable to send scim response", map[string]interface{}{"error": err.Error()})
+		}
+	}
+}
+
+func sendSCIMError(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	res := &model.SCIMError{Schemas: []string{model.SCIMErrorSchema}, Status: strconv.Itoa(status), Detail: err.Error()}
+	if status == http.StatusConflict {
+		res.ScimType = "uniqueness"
+	}
+
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Unable to send scim error", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// getSCIMPagination returns the start index and the count of the page requested. A negative count returns all.
+func getSCIMPagination(r *http.Request) (int, int) {
+	startIndex, err := strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if err != nil {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil {
+		count = -1
+	}
+	return startIndex, count
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spaceuptech/helpers"
 
 	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/userman"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
 
@@ -24,9 +25,7 @@ func HandleProfile(modules *modules.Modules) http.HandlerFunc {
 
 		userManagement, err := modules.User(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -41,7 +40,7 @@ func HandleProfile(modules *modules.Modules) http.HandlerFunc {
 		status, result, err := userManagement.Profile(ctx, token, dbAlias, projectID, id)
 
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, map[string]interface{}{"user": result})
@@ -58,9 +57,7 @@ func HandleProfiles(modules *modules.Modules) http.HandlerFunc {
 
 		userManagement, err := modules.User(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -75,7 +72,7 @@ func HandleProfiles(modules *modules.Modules) http.HandlerFunc {
 		status, result, err := userManagement.Profiles(ctx, token, dbAlias, projectID)
 
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, result)
@@ -91,9 +88,7 @@ func HandleEmailSignIn(modules *modules.Modules) http.HandlerFunc {
 
 		userManagement, err := modules.User(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -110,7 +105,7 @@ func HandleEmailSignIn(modules *modules.Modules) http.HandlerFunc {
 		status, result, err := userManagement.EmailSignIn(ctx, dbAlias, projectID, req["email"].(string), req["pass"].(string))
 
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, result)
@@ -127,9 +122,7 @@ func HandleEmailSignUp(modules *modules.Modules) http.HandlerFunc {
 
 		userManagement, err := modules.User(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -144,7 +137,7 @@ func HandleEmailSignUp(modules *modules.Modules) http.HandlerFunc {
 
 		status, result, err := userManagement.EmailSignUp(ctx, dbAlias, projectID, req["email"].(string), req["name"].(string), req["pass"].(string), req["role"].(string))
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, result)
@@ -162,9 +155,7 @@ func HandleEmailEditProfile(modules *modules.Modules) http.HandlerFunc {
 
 		userManagement, err := modules.User(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -183,7 +174,105 @@ func HandleEmailEditProfile(modules *modules.Modules) http.HandlerFunc {
 		status, result, err := userManagement.EmailEditProfile(ctx, token, dbAlias, projectID, id, req["email"].(string), req["name"].(string), req["pass"].(string))
 
 		if err != nil {
-			_ = helpers.Response.SendErrorResponse(ctx, w, status, err)
+			_ = utils.SendErrorResponse(ctx, w, status, err)
+			return
+		}
+		_ = helpers.Response.SendResponse(ctx, w, status, result)
+	}
+}
+
+type emailFlowRequest struct {
+	Email     string `json:"email"`
+	Token     string `json:"token"`
+	Pass      string `json:"pass"`
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// HandleSendVerificationEmail returns the handler for mailing a verification link
+func HandleSendVerificationEmail(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.SendVerificationEmail(ctx, dbAlias, projectID, req.Email)
+	})
+}
+
+// HandleVerifyEmail returns the handler for verifying an email with the token mailed to it
+func HandleVerifyEmail(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.VerifyEmail(ctx, dbAlias, projectID, req.Token)
+	})
+}
+
+// HandleSendPasswordReset returns the handler for mailing a password reset link
+func HandleSendPasswordReset(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.SendPasswordReset(ctx, dbAlias, projectID, req.Email)
+	})
+}
+
+// HandleResetPassword returns the handler for resetting a password with the token mailed to the user
+func HandleResetPassword(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.ResetPassword(ctx, dbAlias, projectID, req.Token, req.Pass)
+	})
+}
+
+// HandleEmailSignInTwoFactor returns the handler for completing an email sign in with the second factor
+func HandleEmailSignInTwoFactor(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.EmailSignInTwoFactor(ctx, dbAlias, projectID, req.Challenge, req.Code)
+	})
+}
+
+// HandleEnrollTwoFactor returns the handler for starting the enrollment of a user in two factor authentication
+func HandleEnrollTwoFactor(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.EnrollTwoFactor(ctx, token, dbAlias, projectID)
+	})
+}
+
+// HandleConfirmTwoFactor returns the handler for confirming the enrollment of a user in two factor authentication
+func HandleConfirmTwoFactor(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.ConfirmTwoFactor(ctx, token, dbAlias, projectID, req.Code)
+	})
+}
+
+// HandleDisableTwoFactor returns the handler for disabling two factor authentication of a user
+func HandleDisableTwoFactor(modules *modules.Modules) http.HandlerFunc {
+	return handleEmailFlow(modules, func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error) {
+		return userManagement.DisableTwoFactor(ctx, token, dbAlias, projectID, req.Code)
+	})
+}
+
+func handleEmailFlow(modules *modules.Modules, fn func(ctx context.Context, userManagement *userman.Module, dbAlias, projectID, token string, req *emailFlowRequest) (int, map[string]interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the path parameters
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		dbAlias := vars["dbAlias"]
+
+		userManagement, err := modules.User(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Create a context of execution
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(utils.DefaultContextTime)*time.Second)
+		defer cancel()
+
+		// Load the request from the body
+		req := new(emailFlowRequest)
+		defer utils.CloseTheCloser(r.Body)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		status, result, err := fn(ctx, userManagement, dbAlias, projectID, utils.GetTokenFromHeader(r), req)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, status, err)
 			return
 		}
 		_ = helpers.Response.SendResponse(ctx, w, status, result)
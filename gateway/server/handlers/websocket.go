@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -21,6 +21,7 @@ import (
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules"
+	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 	"github.com/spaceuptech/space-cloud/gateway/utils/client"
 	"github.com/spaceuptech/space-cloud/gateway/utils/graphql"
@@ -30,6 +31,9 @@ import (
 type WebsocketModulesInterface interface {
 	Realtime(projectID string) (modules.RealtimeInterface, error)
 	GraphQL(projectID string) (modules.GraphQLInterface, error)
+	StreamingFunctions(projectID string) (modules.FunctionsInterface, error)
+	FunctionsAuth(projectID string) (modules.AuthFunctionsInterface, error)
+	FeatureFlags(projectID string) (modules.FeatureFlagsInterface, error)
 }
 
 var upgrader = websocket.Upgrader{
@@ -38,6 +42,37 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// activeSockets tracks the open websocket connections. These are hijacked from the http server and
+// hence need to be closed explicitly on shutdown.
+var activeSockets sync.Map
+
+// upgradeSocket upgrades the http connection to a websocket and tracks it till it gets released
+func upgradeSocket(w http.ResponseWriter, r *http.Request, respHeader http.Header) (*websocket.Conn, error) {
+	socket, err := upgrader.Upgrade(w, r, respHeader)
+	if err != nil {
+		return nil, err
+	}
+	activeSockets.Store(socket, struct{}{})
+	return socket, nil
+}
+
+func releaseSocket(socket *websocket.Conn) {
+	activeSockets.Delete(socket)
+}
+
+// CloseWebsockets closes all open websocket connections with the service restart close code. This hints
+// the clients to reconnect, which lands them on another node of the cluster.
+func CloseWebsockets(reason string) {
+	msg := websocket.FormatCloseMessage(websocket.CloseServiceRestart, reason)
+	activeSockets.Range(func(key, value interface{}) bool {
+		socket := key.(*websocket.Conn)
+		_ = socket.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+		_ = socket.Close()
+		activeSockets.Delete(key)
+		return true
+	})
+}
+
 // HandleWebsocket handles all websocket communications
 func HandleWebsocket(modules WebsocketModulesInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -46,11 +81,12 @@ func HandleWebsocket(modules WebsocketModulesInterface) http.HandlerFunc {
 
 		ctx := r.Context()
 
-		socket, err := upgrader.Upgrade(w, r, nil)
+		socket, err := upgradeSocket(w, r, nil)
 		if err != nil {
 			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "upgrade:", map[string]interface{}{"error": err})
 			return
 		}
+		defer releaseSocket(socket)
 
 		// Create a new client
 		c := client.CreateWebsocketClient(socket)
@@ -58,14 +94,20 @@ func HandleWebsocket(modules WebsocketModulesInterface) http.HandlerFunc {
 
 		realtime, err := modules.Realtime(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		defer realtime.RemoveClient(c.ClientID())
 
+		flags, err := modules.FeatureFlags(projectID)
+		if err != nil {
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		defer flags.Unsubscribe(c.ClientID())
+
 		go c.RoutineWrite(ctx)
 
 		// Get client details
@@ -120,6 +162,41 @@ func HandleWebsocket(modules WebsocketModulesInterface) http.HandlerFunc {
 				// Send response to client
 				res := model.RealtimeResponse{Group: data.Group, ID: data.ID, Ack: true}
 				c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: res})
+
+			case utils.TypeServiceCall:
+				data := new(model.WebsocketFunctionsRequest)
+				if err := mapstructure.Decode(req.Data, data); err != nil {
+					_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to decode incoming service call request", err, nil)
+					res := model.WebsocketFunctionsResponse{Ack: false, Status: http.StatusBadRequest, Error: err.Error()}
+					c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: res})
+					return true
+				}
+
+				// Calls can take long. Hence they are made in the background while the socket keeps reading.
+				go handleServiceCall(c, modules, projectID, req.ID, data)
+
+			case utils.TypeFlagsSubscribe:
+				data := new(model.FlagsRequest)
+				if err := mapstructure.Decode(req.Data, data); err != nil {
+					_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to decode incoming feature flags subscription request", err, nil)
+					c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: model.FlagsResponse{Ack: false, Error: err.Error()}})
+					return true
+				}
+
+				// Subscribe to the changes of the flags evaluated for the token
+				values, err := flags.Subscribe(ctx, clientID, data.Token, func(values map[string]bool) {
+					c.Write(&model.Message{Type: utils.TypeFlagsFeed, Data: values})
+				})
+				if err != nil {
+					_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to process incoming feature flags subscription request", err, nil)
+					c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: model.FlagsResponse{Ack: false, Error: err.Error()}})
+					return true
+				}
+				c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: model.FlagsResponse{Ack: true, Flags: values}})
+
+			case utils.TypeFlagsUnsubscribe:
+				flags.Unsubscribe(clientID)
+				c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: model.FlagsResponse{Ack: true}})
 			default:
 				c.Write(&model.Message{ID: req.ID, Type: req.Type, Data: map[string]string{"error": "Invalid message type"}})
 			}
@@ -128,6 +205,55 @@ func HandleWebsocket(modules WebsocketModulesInterface) http.HandlerFunc {
 	}
 }
 
+// handleServiceCall calls the function of a remote service and writes the chunks of its response to the client as
+// they arrive. The call ends with a message having the final result.
+func handleServiceCall(c *client.WebsocketClient, modules WebsocketModulesInterface, projectID, id string, data *model.WebsocketFunctionsRequest) {
+	ctx := c.Context()
+	sendError := func(status int, err error) {
+		c.Write(&model.Message{ID: id, Type: utils.TypeServiceCall, Data: model.WebsocketFunctionsResponse{Ack: false, Status: status, Error: err.Error()}})
+	}
+
+	functions, err := modules.StreamingFunctions(projectID)
+	if err != nil {
+		sendError(http.StatusBadRequest, err)
+		return
+	}
+	auth, err := modules.FunctionsAuth(projectID)
+	if err != nil {
+		sendError(http.StatusBadRequest, err)
+		return
+	}
+
+	timeout, err := functions.GetEndpointContextTimeout(ctx, projectID, data.Service, data.Endpoint)
+	if err != nil {
+		sendError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	actions, reqParams, err := auth.IsFuncCallAuthorised(ctx, projectID, data.Service, data.Endpoint, data.Token, data.Params)
+	if err != nil {
+		sendError(http.StatusForbidden, err)
+		return
+	}
+
+	req := &model.FunctionsRequest{Params: data.Params, Timeout: timeout}
+	status, result, err := functions.CallStreamWithContext(ctx, data.Service, data.Endpoint, data.Token, reqParams, req, func(chunk *model.FunctionsStreamChunk) {
+		_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, chunk.Data)
+		c.Write(&model.Message{ID: id, Type: utils.TypeServiceCallChunk, Data: chunk})
+	})
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Receieved error from service call (%s:%s)", data.Service, data.Endpoint), err, nil)
+		sendError(status, err)
+		return
+	}
+
+	_ = authHelpers.PostProcessMethod(ctx, auth.GetAESKey(), actions, result)
+	c.Write(&model.Message{ID: id, Type: utils.TypeServiceCall, Data: model.WebsocketFunctionsResponse{Ack: true, Status: status, Result: result}})
+}
+
 type graphqlMessage struct {
 	Payload payloadObject `json:"payload"`
 	ID      string        `json:"id"`
@@ -155,17 +281,13 @@ func HandleGraphqlSocket(modules WebsocketModulesInterface) http.HandlerFunc {
 
 		realtime, err := modules.Realtime(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 
 		graph, err := modules.GraphQL(projectID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			_ = utils.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
 			return
 		}
 		// Create a map to store subscription ids
@@ -173,11 +295,12 @@ func HandleGraphqlSocket(modules WebsocketModulesInterface) http.HandlerFunc {
 
 		respHeader := make(http.Header)
 		respHeader.Add("Sec-WebSocket-Protocol", "graphql-ws")
-		socket, err := upgrader.Upgrade(w, r, respHeader)
+		socket, err := upgradeSocket(w, r, respHeader)
 		if err != nil {
 			helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "upgrade:", map[string]interface{}{"error": err})
 			return
 		}
+		defer releaseSocket(socket)
 		defer utils.CloseTheCloser(socket)
 
 		// Create a new client ID that we will use to make subscriptions
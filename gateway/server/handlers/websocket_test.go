@@ -3,9 +3,11 @@ package handlers
 import (
 	"context"
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -260,6 +262,127 @@ func TestHandleWebsocket(t *testing.T) {
 	}
 }
 
+func TestHandleWebsocket_serviceCall(t *testing.T) {
+	t.Parallel()
+	functions := &mockStreamingFunctions{
+		chunks: []*model.FunctionsStreamChunk{{Type: "progress", Progress: 0.5}, {Type: "chunk", Data: "page 1"}},
+		result: map[string]interface{}{"pages": float64(2)},
+	}
+	tests := []struct {
+		name string
+		auth *mockFunctionsAuth
+		rcv  []*model.Message
+	}{
+		{
+			name: "streamed response",
+			auth: &mockFunctionsAuth{},
+			rcv: []*model.Message{
+				{Type: utils.TypeServiceCallChunk, ID: "1", Data: map[string]interface{}{"type": "progress", "progress": 0.5}},
+				{Type: utils.TypeServiceCallChunk, ID: "1", Data: map[string]interface{}{"type": "chunk", "data": "page 1"}},
+				{Type: utils.TypeServiceCall, ID: "1", Data: map[string]interface{}{"ack": true, "status": float64(http.StatusOK), "result": map[string]interface{}{"pages": float64(2)}}},
+			},
+		},
+		{
+			name: "unauthorised call",
+			auth: &mockFunctionsAuth{err: errors.New("access denied")},
+			rcv: []*model.Message{
+				{Type: utils.TypeServiceCall, ID: "1", Data: map[string]interface{}{"ack": false, "status": float64(http.StatusForbidden), "error": "access denied"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			realtime := mockRealtimeModule{}
+			realtime.On("RemoveClient", mock.Anything).Return()
+
+			s := httptest.NewServer(HandleWebsocket(&mockWebsocketModules{realtime: &realtime, functions: functions, auth: tt.auth}))
+			defer s.Close()
+
+			ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+			if err != nil {
+				t.Fatalf("HandleWebsocket() = Unable to connect to server - %v", err)
+			}
+			defer utils.CloseTheCloser(ws)
+
+			send := model.Message{Type: utils.TypeServiceCall, ID: "1", Data: model.WebsocketFunctionsRequest{Service: "reports", Endpoint: "generate"}}
+			if err := ws.WriteJSON(send); err != nil {
+				t.Fatalf("HandleWebsocket() = Unable to send message to server - %v", err)
+			}
+
+			for _, m := range tt.rcv {
+				res := new(model.Message)
+				if err := ws.ReadJSON(res); err != nil {
+					t.Fatalf("HandleWebsocket() = Unable to read message to server - %v", err)
+				}
+				if !reflect.DeepEqual(m, res) {
+					t.Fatalf("HandleWebsocket() = got - %v; wanted - %v", res, m)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleWebsocket_flags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		token string
+		rcv   []*model.Message
+		push  map[string]bool
+	}{
+		{
+			name: "subscription with feed",
+			rcv: []*model.Message{
+				{Type: utils.TypeFlagsSubscribe, ID: "1", Data: map[string]interface{}{"ack": true, "flags": map[string]interface{}{"beta": false}}},
+				{Type: utils.TypeFlagsFeed, Data: map[string]interface{}{"beta": true}},
+			},
+			push: map[string]bool{"beta": true},
+		},
+		{
+			name:  "invalid token",
+			token: "invalid",
+			rcv: []*model.Message{
+				{Type: utils.TypeFlagsSubscribe, ID: "1", Data: map[string]interface{}{"ack": false, "error": "invalid token"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			realtime := mockRealtimeModule{}
+			realtime.On("RemoveClient", mock.Anything).Return()
+			flags := &mockFeatureFlags{values: map[string]bool{"beta": false}}
+
+			s := httptest.NewServer(HandleWebsocket(&mockWebsocketModules{realtime: &realtime, flags: flags}))
+			defer s.Close()
+
+			ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+			if err != nil {
+				t.Fatalf("HandleWebsocket() = Unable to connect to server - %v", err)
+			}
+			defer utils.CloseTheCloser(ws)
+
+			send := model.Message{Type: utils.TypeFlagsSubscribe, ID: "1", Data: model.FlagsRequest{Token: tt.token}}
+			if err := ws.WriteJSON(send); err != nil {
+				t.Fatalf("HandleWebsocket() = Unable to send message to server - %v", err)
+			}
+
+			for i, m := range tt.rcv {
+				// The flags change once the subscription is acknowledged
+				if i == 1 {
+					flags.push(tt.push)
+				}
+				res := new(model.Message)
+				if err := ws.ReadJSON(res); err != nil {
+					t.Fatalf("HandleWebsocket() = Unable to read message to server - %v", err)
+				}
+				if !reflect.DeepEqual(m, res) {
+					t.Fatalf("HandleWebsocket() = got - %v; wanted - %v", res, m)
+				}
+			}
+		})
+	}
+}
+
 func TestHandleGraphqlSocket(t *testing.T) {
 	t.Parallel()
 	type mockArg struct {
@@ -580,7 +703,7 @@ subscription {
 			}
 
 			// Create the mock server
-			s := httptest.NewServer(HandleGraphqlSocket(&mockWebsocketModules{&realtime, &graph}))
+			s := httptest.NewServer(HandleGraphqlSocket(&mockWebsocketModules{realtime: &realtime, graphql: &graph}))
 			defer s.Close()
 
 			// Convert http://127.0.0.1 to ws://127.0.0.
@@ -625,8 +748,11 @@ subscription {
 }
 
 type mockWebsocketModules struct {
-	realtime modules.RealtimeInterface
-	graphql  modules.GraphQLInterface
+	realtime  modules.RealtimeInterface
+	graphql   modules.GraphQLInterface
+	functions modules.FunctionsInterface
+	auth      modules.AuthFunctionsInterface
+	flags     modules.FeatureFlagsInterface
 }
 
 func (m *mockWebsocketModules) Realtime(projectID string) (modules.RealtimeInterface, error) {
@@ -637,6 +763,83 @@ func (m *mockWebsocketModules) GraphQL(projectID string) (modules.GraphQLInterfa
 	return m.graphql, nil
 }
 
+func (m *mockWebsocketModules) StreamingFunctions(projectID string) (modules.FunctionsInterface, error) {
+	return m.functions, nil
+}
+
+func (m *mockWebsocketModules) FunctionsAuth(projectID string) (modules.AuthFunctionsInterface, error) {
+	return m.auth, nil
+}
+
+func (m *mockWebsocketModules) FeatureFlags(projectID string) (modules.FeatureFlagsInterface, error) {
+	if m.flags == nil {
+		return &mockFeatureFlags{}, nil
+	}
+	return m.flags, nil
+}
+
+type mockFeatureFlags struct {
+	lock   sync.Mutex
+	values map[string]bool
+	send   func(values map[string]bool)
+}
+
+func (m *mockFeatureFlags) GetFlags(ctx context.Context, token string) (map[string]bool, error) {
+	return m.values, nil
+}
+
+func (m *mockFeatureFlags) Subscribe(ctx context.Context, clientID, token string, send func(values map[string]bool)) (map[string]bool, error) {
+	if token == "invalid" {
+		return nil, errors.New("invalid token")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.send = send
+	return m.values, nil
+}
+
+func (m *mockFeatureFlags) Unsubscribe(clientID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.send = nil
+}
+
+func (m *mockFeatureFlags) push(values map[string]bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.send != nil {
+		m.send(values)
+	}
+}
+
+type mockStreamingFunctions struct {
+	chunks []*model.FunctionsStreamChunk
+	result interface{}
+}
+
+func (m *mockStreamingFunctions) GetEndpointContextTimeout(ctx context.Context, projectID, service, function string) (int, error) {
+	return 10, nil
+}
+
+func (m *mockStreamingFunctions) CallStreamWithContext(ctx context.Context, service, function, token string, reqParams model.RequestParams, req *model.FunctionsRequest, onChunk model.FunctionsStreamCallback) (int, interface{}, error) {
+	for _, chunk := range m.chunks {
+		onChunk(chunk)
+	}
+	return http.StatusOK, m.result, nil
+}
+
+type mockFunctionsAuth struct {
+	err error
+}
+
+func (m *mockFunctionsAuth) IsFuncCallAuthorised(ctx context.Context, project, service, function, token string, params interface{}) (*model.PostProcess, model.RequestParams, error) {
+	return nil, model.RequestParams{}, m.err
+}
+
+func (m *mockFunctionsAuth) GetAESKey() []byte {
+	return nil
+}
+
 // Create all the mock interfaces
 type mockRealtimeModule struct {
 	mock.Mock
@@ -691,3 +894,49 @@ func (m *mockGraphQLModule) GetDBAlias(ctx context.Context, field *ast.Field, to
 func (m *mockGraphQLModule) ExecGraphQLQuery(ctx context.Context, req *model.GraphQLRequest, token string, cb model.GraphQLCallback) {
 	m.Called(ctx, req, token, cb)
 }
+
+func (m *mockGraphQLModule) GetSDL() string {
+	return m.Called().String(0)
+}
+
+func (m *mockGraphQLModule) IsIntrospectionDisabled() bool {
+	return m.Called().Bool(0)
+}
+
+func TestCloseWebsockets(t *testing.T) {
+	// This test must not run in parallel since it closes all the websockets of the package
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		socket, err := upgradeSocket(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer releaseSocket(socket)
+		_, _, _ = socket.ReadMessage()
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Unable to dial websocket: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Wait for the server to track the socket
+	for i := 0; i < 100; i++ {
+		count := 0
+		activeSockets.Range(func(key, value interface{}) bool { count++; return true })
+		if count == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	CloseWebsockets("shutting down")
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseServiceRestart) {
+		t.Errorf("CloseWebsockets() client got error %v, want close error with code %d", err, websocket.CloseServiceRestart)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !http3
+// +build !http3
+
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+)
+
+// startHTTP3 fails since the gateway hasn't been built with the http3 tag
+func startHTTP3(string, http.Handler, *tls.Config) (shutdowner, func(http.Handler) http.Handler, error) {
+	return nil, nil, errors.New("http3 is only available in the gateways built with the http3 tag")
+}
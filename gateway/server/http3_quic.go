@@ -0,0 +1,43 @@
+//go:build http3
+// +build http3
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"github.com/lucas-clemente/quic-go/http3"
+	"github.com/spaceuptech/helpers"
+)
+
+// http3Server closes the QUIC listener on being shut down. The http3 server doesn't support draining the requests in
+// flight yet.
+type http3Server struct {
+	*http3.Server
+}
+
+func (s *http3Server) Shutdown(context.Context) error {
+	return s.Close()
+}
+
+// startHTTP3 serves the handler over QUIC on the udp port of the https listener. It returns the server along with
+// a middleware advertising it to the clients of the https listener through the Alt-Svc header.
+func startHTTP3(addr string, handler http.Handler, tlsConfig *tls.Config) (shutdowner, func(http.Handler) http.Handler, error) {
+	server := &http3.Server{Server: &http.Server{Addr: addr, Handler: handler, TLSConfig: tlsConfig}}
+	go func() {
+		helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Starting experimental http3 server on udp address: "+addr, nil)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Error serving http3", err, nil)
+		}
+	}()
+
+	advertise := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = server.SetQuicHeaders(w.Header())
+			next.ServeHTTP(w, r)
+		})
+	}
+	return &http3Server{server}, advertise, nil
+}
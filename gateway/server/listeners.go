@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// systemdListenFDsStart is the first file descriptor passed by systemd socket activation
+const systemdListenFDsStart = 3
+
+// getListeners returns the listeners served by the http server. The sockets passed by systemd socket activation
+// replace the http port. The unix domain socket is served in addition to them.
+func getListeners(port int, c *config.Listener) ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, 2)
+
+	if c.SystemdActivation {
+		l, err := getSystemdListeners()
+		if err != nil {
+			return nil, err
+		}
+		if len(l) == 0 {
+			return nil, fmt.Errorf("systemd socket activation is enabled but no sockets have been passed to the gateway")
+		}
+		listeners = append(listeners, l...)
+	} else {
+		l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+
+	if c.UnixSocket != "" {
+		l, err := listenUnix(c.UnixSocket, os.FileMode(c.UnixSocketMode))
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// listenUnix listens on a unix domain socket. A socket left behind by a previous run is removed.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("unable to listen on unix socket (%s) - file exists and isn't a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("unable to remove stale unix socket (%s) - %v", path, err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if mode == 0 {
+		mode = 0660
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("unable to set the permissions of unix socket (%s) - %v", path, err)
+	}
+	return l, nil
+}
+
+// getSystemdListeners returns the sockets passed by systemd socket activation. The environment variables describing
+// them are unset so that they aren't inherited by the child processes.
+func getSystemdListeners() ([]net.Listener, error) {
+	defer func() {
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+
+	listeners := make([]net.Listener, 0, n)
+	for fd := systemdListenFDsStart; fd < systemdListenFDsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "systemd-socket-"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			closeListeners(listeners)
+			return nil, fmt.Errorf("unable to use the socket (%d) passed by systemd - %v", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, l := range listeners {
+		_ = l.Close()
+	}
+}
@@ -1,24 +1,56 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
 	"github.com/segmentio/ksuid"
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/managers/admin"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/modules"
+	"github.com/spaceuptech/space-cloud/gateway/modules/auth"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/accounting"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/admission"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/compression"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/idempotency"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/logging"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/metrics"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/operations"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 func loggerMiddleWare(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		requestID := r.Header.Get(helpers.HeaderRequestID)
-		if requestID == "" {
+		if !utils.IsValidRequestID(requestID) {
 			// set a new request id header of request
 			requestID = ksuid.New().String()
 			r.Header.Set(helpers.HeaderRequestID, requestID)
 		}
 
+		// Return the request id so that the clients can correlate their requests with the logs
+		w.Header().Set(helpers.HeaderRequestID, requestID)
+
 		var reqBody []byte
 		if r.Header.Get("Content-Type") == "application/json" {
 			reqBody, _ = ioutil.ReadAll(r.Body)
@@ -30,3 +62,607 @@ func loggerMiddleWare(next http.Handler) http.Handler {
 
 	})
 }
+
+func tracingMiddleWare(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Continue the trace started by the caller (if any)
+		ctx := tracing.Extract(r.Context(), r.Header)
+
+		ctx, span := tracing.StartSpan(ctx, "HTTP "+r.Method, trace.SpanKindServer,
+			label.String("http.method", r.Method),
+			label.String("http.target", r.URL.Path),
+			label.String("http.host", r.Host),
+			label.String("http.request_id", r.Header.Get(helpers.HeaderRequestID)),
+		)
+		defer span.End()
+
+		if project, module, ok := getAPIModule(r.URL.Path); ok {
+			span.SetAttributes(label.String("space_cloud.project", project), label.String("space_cloud.module", module))
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		span.SetAttributes(label.Int("http.status_code", recorder.status))
+	})
+}
+
+// ruleTraceMiddleWare records the evaluations of the security rules of a request. The trace gets attached to the
+// error responses of the requests carrying a valid admin token in the debug rules header. It is also recorded
+// when the denials need to be logged.
+func ruleTraceMiddleWare(adminMan *admin.Manager, l *logging.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		debug := false
+		if token := r.Header.Get(utils.HeaderDebugRules); token != "" {
+			debug = adminMan.CheckIfAdmin(r.Context(), token) == nil
+		}
+		if (!debug && !l.LogsRuleDenials()) || websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, trace := auth.WithRuleTrace(r.Context())
+		if !debug {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		writer := &ruleTraceWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r.WithContext(ctx))
+		writer.flushError(trace)
+	})
+}
+
+// ruleTraceWriter holds back error responses so that the trace of the security rules can be added to them
+type ruleTraceWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	errorBody   bytes.Buffer
+}
+
+func (w *ruleTraceWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = statusCode
+	w.wroteHeader = true
+	if statusCode < http.StatusBadRequest {
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (w *ruleTraceWriter) Write(buf []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.status >= http.StatusBadRequest {
+		return w.errorBody.Write(buf)
+	}
+	return w.ResponseWriter.Write(buf)
+}
+
+func (w *ruleTraceWriter) Flush() {
+	if w.status >= http.StatusBadRequest {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// flushError writes the error response held back with the trace added to it. Responses which aren't json
+// objects are written as is.
+func (w *ruleTraceWriter) flushError(trace *model.RuleTrace) {
+	if w.status < http.StatusBadRequest {
+		return
+	}
+
+	body := w.errorBody.Bytes()
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err == nil {
+		obj["ruleTrace"] = trace.Evaluations
+		if data, err := json.Marshal(obj); err == nil {
+			body = data
+			w.Header().Del("Content-Length")
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// maxLoggedErrorSize is the maximum size of an error response captured in the access logs
+const maxLoggedErrorSize = 1024
+
+func accessLogMiddleWare(l *logging.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		project, module, _ := getAPIModule(r.URL.Path)
+		if !l.IsEnabled(project, logging.LevelError) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := &logging.Entry{
+			RequestID:  r.Header.Get(helpers.HeaderRequestID),
+			Project:    project,
+			Module:     module,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+		}
+		if sc := trace.SpanFromContext(r.Context()).SpanContext(); sc.IsValid() {
+			entry.TraceID = sc.TraceID.String()
+		}
+		if query := r.URL.Query(); len(query) > 0 {
+			entry.Query = make(map[string]interface{}, len(query))
+			for k, v := range query {
+				entry.Query[k] = strings.Join(v, ",")
+			}
+		}
+
+		// Request bodies are only logged at the debug level
+		if l.IsDebug(project) && r.Header.Get("Content-Type") == "application/json" {
+			reqBody, _ := ioutil.ReadAll(r.Body)
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(reqBody))
+			var body interface{}
+			if err := json.Unmarshal(reqBody, &body); err == nil {
+				entry.Body = body
+			}
+		}
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK, captureErrors: true}
+		next.ServeHTTP(recorder, r)
+
+		entry.Time = start
+		entry.Status = recorder.status
+		entry.Bytes = recorder.bytes
+		entry.DurationMS = float64(time.Since(start).Microseconds()) / 1000
+		if recorder.status >= http.StatusBadRequest {
+			entry.Error = getErrorMessage(recorder.errorBody)
+			if trace, ok := auth.GetRuleTrace(r.Context()); ok && l.LogsRuleDenials() {
+				if rules := trace.FailedRules(); len(rules) > 0 {
+					entry.DeniedRules = rules
+				}
+			}
+		}
+		l.Log(entry)
+	})
+}
+
+// getErrorMessage extracts the error message from the body of an error response
+func getErrorMessage(body []byte) string {
+	v := map[string]interface{}{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if msg, ok := v["error"].(string); ok {
+			return msg
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// apiModules maps the path segment following the project id in the client api to the module serving it
+var apiModules = map[string]string{
+	"crud":     "crud",
+	"graphql":  "graphql",
+	"socket":   "realtime",
+	"realtime": "realtime",
+	"services": "functions",
+	"files":    "file",
+	"eventing": "eventing",
+	"auth":     "userman",
+	"scim":     "userman",
+	"locks":    "locks",
+	"search":   "search",
+	"kv":       "kv",
+	"flags":    "flags",
+}
+
+// unknownProjectLabel is the project the requests made to projects absent in the config are recorded under. The
+// project id comes from the path of unauthenticated requests, hence it can't be used as a label as is.
+const unknownProjectLabel = "_unknown"
+
+func metricsMiddleWare(mods *modules.Modules, m *metrics.Module, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		project, module, ok := getAPIModule(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !mods.ProjectExists(project) {
+			project = unknownProjectLabel
+		}
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Websocket connections live for as long as the client is connected, which would skew the latencies
+		if recorder.hijacked {
+			return
+		}
+		m.AddRequest(project, module, recorder.status, time.Since(start))
+	})
+}
+
+// sloMiddleWare counts the requests made to the client api of a project towards its service level objectives
+func sloMiddleWare(m *modules.Modules, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		project, module, ok := getAPIModule(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		objectives, err := m.SLO(project)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Websocket connections live for as long as the client is connected, which would skew the latencies
+		if recorder.hijacked {
+			return
+		}
+		objectives.Record(module, strings.TrimPrefix(r.URL.Path, "/v1/api/"+project), recorder.status, time.Since(start))
+	})
+}
+
+// accountingMiddleWare records the resource usage of the requests made to the client api of a project.
+// Hijacked requests are realtime connections which are accounted for the time they stay open.
+// Only the projects present in the config are accounted for, since the project id comes from the path of
+// unauthenticated requests.
+func accountingMiddleWare(mods *modules.Modules, a *accounting.Module, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		project, module, ok := getAPIModule(r.URL.Path)
+		if !ok || !mods.ProjectExists(project) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if websocket.IsWebSocketUpgrade(r) {
+			closeConnection := a.OpenConnection(project)
+			defer closeConnection()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body := &countingReader{ReadCloser: r.Body}
+		r.Body = body
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		a.AddRequest(project, recorder.bytes)
+		if module == "file" && r.Method == http.MethodPost && recorder.status < http.StatusBadRequest {
+			a.AddFileUpload(project, body.bytes)
+		}
+	})
+}
+
+// countingReader counts the bytes read from the request body
+type countingReader struct {
+	io.ReadCloser
+	bytes uint64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.bytes += uint64(n)
+	return n, err
+}
+
+// operationTypes maps the modules whose requests are tracked as operations to the type of the operation
+var operationTypes = map[string]string{
+	"crud":      operations.TypeQuery,
+	"graphql":   operations.TypeQuery,
+	"file":      operations.TypeFileTransfer,
+	"functions": operations.TypeRemoteCall,
+}
+
+// operationsMiddleWare registers the queries, file transfers and remote calls in the operations registry so that
+// they can be inspected and killed by the admin
+func operationsMiddleWare(registry *operations.Registry, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		project, module, ok := getAPIModule(r.URL.Path)
+		opType, tracked := operationTypes[module]
+		if !ok || !tracked || websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, done := registry.Start(r.Context(), operations.Operation{
+			Project:   project,
+			Type:      opType,
+			Module:    module,
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			RequestID: r.Header.Get(helpers.HeaderRequestID),
+		})
+		defer done()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// crudMutations are the crud operations whose retries are deduplicated with an idempotency key
+var crudMutations = map[string]bool{"create": true, "update": true, "increment": true, "delete": true, "batch": true, "import": true}
+
+// idempotencyMiddleWare replays the original response of config writes and crud mutations retried with the same
+// idempotency key instead of applying them again
+func idempotencyMiddleWare(m *idempotency.Module, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(utils.HeaderIdempotencyKey) == "" || !isIdempotentMutation(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		m.ServeHTTP(w, r, next)
+	})
+}
+
+// defaultMaintenanceRetryAfter is the time in seconds after which the clients are asked to retry the requests rejected
+// by the read only and maintenance modes
+const defaultMaintenanceRetryAfter = 60
+
+// maintenanceMiddleWare rejects the writes to the projects in the read only mode and answers the requests to the
+// projects in the maintenance mode with the configured response. The config apis are never affected.
+func maintenanceMiddleWare(m *modules.Modules, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		project, module, ok := getAPIModule(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c := m.Maintenance(project)
+		if c == nil || (c.Mode == config.MaintenanceModeReadOnly && !isWriteRequest(r, module)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter := c.RetryAfter
+		if retryAfter == 0 {
+			retryAfter = defaultMaintenanceRetryAfter
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+
+		msg := c.Message
+		if msg == "" && c.Mode == config.MaintenanceModeReadOnly {
+			msg = fmt.Sprintf("Project (%s) is in read only mode - writes are disabled", project)
+		} else if msg == "" {
+			msg = fmt.Sprintf("Project (%s) is under maintenance", project)
+		}
+
+		status := c.StatusCode
+		if status == 0 || c.Mode == config.MaintenanceModeReadOnly {
+			status = http.StatusServiceUnavailable
+		}
+
+		if c.Mode == config.MaintenanceModeFull && c.Body != "" {
+			contentType := c.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(c.Body))
+			return
+		}
+
+		err := utils.NewError(model.ErrorCodeUnavailable, errors.New(msg)).WithDetails(map[string]interface{}{"mode": c.Mode})
+		_ = utils.SendErrorResponse(r.Context(), w, status, err)
+	})
+}
+
+// admissionMiddleWare sheds the low priority requests when the gateway node is overloaded. The shed requests are
+// answered with a 503 instead of piling up until everything times out. Realtime connections are long lived, hence
+// they aren't subject to the admission control.
+func admissionMiddleWare(m *admission.Module, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		project, _, _ := getAPIModule(r.URL.Path)
+		priority := m.GetPriority(project, r.URL.Path)
+		done, err := m.Admit(r.Context(), priority)
+		if err != nil {
+			w.Header().Set("Retry-After", strconv.Itoa(m.RetryAfter()))
+			err := utils.NewError(model.ErrorCodeUnavailable, err).WithDetails(map[string]interface{}{"priority": priority})
+			_ = utils.SendErrorResponse(r.Context(), w, http.StatusServiceUnavailable, err)
+			return
+		}
+		defer done()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// compressionMiddleWare compresses the responses with the encoding negotiated with the client. It sits above the
+// middlewares inspecting the responses so that they get to see the uncompressed body.
+func compressionMiddleWare(m *compression.Module, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw, ok := m.NewResponseWriter(w, r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer func() { _ = cw.Close() }()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// usermanWrites are the last path segments of the user management apis which modify the users
+var usermanWrites = map[string]bool{"signup": true, "verify": true, "reset": true, "enroll": true, "confirm": true, "disable": true}
+
+// isWriteRequest checks if a request of the client apis modifies the data of the project. Prepared queries are raw
+// queries, hence they are treated as writes. The json websocket only carries realtime subscriptions, service calls and
+// flag subscriptions, none of which modify the data, hence it's never a write.
+func isWriteRequest(r *http.Request, module string) bool {
+	arr := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	last := arr[len(arr)-1]
+
+	switch module {
+	case "crud":
+		return crudMutations[last] || arr[len(arr)-2] == "prepared-queries"
+	case "graphql":
+		return r.Method == http.MethodPost && last == "graphql" && isGraphQLMutation(r)
+	case "file":
+		return r.Method != http.MethodGet
+	case "userman":
+		// Scim provisions the users and groups with the usual http methods, while revoking sessions is a delete
+		if arr[4] == "scim" || r.Method == http.MethodDelete {
+			return r.Method != http.MethodGet
+		}
+		return usermanWrites[last] || arr[len(arr)-2] == "edit_profile"
+	case "eventing":
+		return r.Method == http.MethodPost
+	case "kv":
+		return r.Method != http.MethodGet
+	}
+	return false
+}
+
+// isGraphQLMutation checks if the graphql request carries a mutation. Queries which can't be inspected, like the
+// persisted queries sent by their hash, are treated as mutations.
+func isGraphQLMutation(r *http.Request) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return true
+	}
+
+	req := model.GraphQLRequest{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return true
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil || req.Query == "" {
+		return true
+	}
+
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
+
+// apiModuleMiddleWare stores the module serving the request in its context so that errors can be attributed to it
+func apiModuleMiddleWare(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(utils.WithAPIModule(r.Context(), getRequestModule(r.URL.Path))))
+	})
+}
+
+// getRequestModule returns the module serving a request of any of the apis
+func getRequestModule(path string) string {
+	if _, module, ok := getAPIModule(path); ok {
+		return module
+	}
+	switch {
+	case strings.HasPrefix(path, "/v1/config/"):
+		return "config"
+	case strings.HasPrefix(path, "/v1/runner/"):
+		return "runner"
+	}
+	return "gateway"
+}
+
+// isIdempotentMutation checks if the request is a config write or a crud mutation
+func isIdempotentMutation(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/v1/config/") {
+		return true
+	}
+
+	_, module, ok := getAPIModule(r.URL.Path)
+	if !ok || module != "crud" {
+		return false
+	}
+	arr := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	return crudMutations[arr[len(arr)-1]]
+}
+
+// getAPIModule returns the project and module of a client api request of the form /v1/api/{project}/{module}/...
+func getAPIModule(path string) (string, string, bool) {
+	arr := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(arr) < 4 || arr[0] != "v1" || arr[1] != "api" {
+		return "", "", false
+	}
+
+	module, p := apiModules[arr[3]]
+	if !p {
+		return "", "", false
+	}
+	return arr[2], module, true
+}
+
+// statusRecorder captures the status code and size of the response written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	hijacked    bool
+	bytes       uint64
+
+	// captureErrors stores the beginning of error responses in errorBody
+	captureErrors bool
+	errorBody     []byte
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if !s.wroteHeader {
+		s.status = statusCode
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (s *statusRecorder) Write(buf []byte) (int, error) {
+	s.wroteHeader = true
+	if s.captureErrors && s.status >= http.StatusBadRequest && len(s.errorBody) < maxLoggedErrorSize {
+		n := maxLoggedErrorSize - len(s.errorBody)
+		if n > len(buf) {
+			n = len(buf)
+		}
+		s.errorBody = append(s.errorBody, buf[:n]...)
+	}
+	n, err := s.ResponseWriter.Write(buf)
+	s.bytes += uint64(n)
+	return n, err
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	s.hijacked = true
+	return h.Hijack()
+}
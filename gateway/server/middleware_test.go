@@ -0,0 +1,60 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_isWriteRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   bool
+	}{
+		{name: "crud read", method: http.MethodPost, path: "/v1/api/p/crud/db/posts/read"},
+		{name: "crud create", method: http.MethodPost, path: "/v1/api/p/crud/db/posts/create", want: true},
+		{name: "crud batch", method: http.MethodPost, path: "/v1/api/p/crud/db/batch", want: true},
+		{name: "crud prepared query", method: http.MethodPost, path: "/v1/api/p/crud/db/prepared-queries/top", want: true},
+		{name: "graphql query", method: http.MethodPost, path: "/v1/api/p/graphql", body: `{"query":"query { posts @db { id } }"}`},
+		{name: "graphql mutation", method: http.MethodPost, path: "/v1/api/p/graphql", body: `{"query":"mutation { insert_posts(docs: []) @db { status } }"}`, want: true},
+		{name: "json websocket", method: http.MethodGet, path: "/v1/api/p/socket/json"},
+		{name: "file read", method: http.MethodGet, path: "/v1/api/p/files/a.txt"},
+		{name: "file upload", method: http.MethodPost, path: "/v1/api/p/files", want: true},
+		{name: "email sign in", method: http.MethodPost, path: "/v1/api/p/auth/db/email/signin"},
+		{name: "email sign up", method: http.MethodPost, path: "/v1/api/p/auth/db/email/signup", want: true},
+		{name: "edit profile", method: http.MethodPost, path: "/v1/api/p/auth/db/edit_profile/1", want: true},
+		{name: "send verification email", method: http.MethodPost, path: "/v1/api/p/auth/db/email/verify/send"},
+		{name: "verify email", method: http.MethodPost, path: "/v1/api/p/auth/db/email/verify", want: true},
+		{name: "send password reset", method: http.MethodPost, path: "/v1/api/p/auth/db/email/reset/send"},
+		{name: "reset password", method: http.MethodPost, path: "/v1/api/p/auth/db/email/reset", want: true},
+		{name: "enroll two factor", method: http.MethodPost, path: "/v1/api/p/auth/db/2fa/enroll", want: true},
+		{name: "confirm two factor", method: http.MethodPost, path: "/v1/api/p/auth/db/2fa/confirm", want: true},
+		{name: "disable two factor", method: http.MethodPost, path: "/v1/api/p/auth/db/2fa/disable", want: true},
+		{name: "list sessions", method: http.MethodGet, path: "/v1/api/p/auth/sessions"},
+		{name: "revoke sessions", method: http.MethodDelete, path: "/v1/api/p/auth/sessions", want: true},
+		{name: "scim list users", method: http.MethodGet, path: "/v1/api/p/scim/v2/Users"},
+		{name: "scim create user", method: http.MethodPost, path: "/v1/api/p/scim/v2/Users", want: true},
+		{name: "scim replace user", method: http.MethodPut, path: "/v1/api/p/scim/v2/Users/1", want: true},
+		{name: "scim patch group", method: http.MethodPatch, path: "/v1/api/p/scim/v2/Groups/1", want: true},
+		{name: "scim delete user", method: http.MethodDelete, path: "/v1/api/p/scim/v2/Users/1", want: true},
+		{name: "queue event", method: http.MethodPost, path: "/v1/api/p/eventing/queue", want: true},
+		{name: "get key", method: http.MethodGet, path: "/v1/api/p/kv/ns/keys/a"},
+		{name: "set key", method: http.MethodPost, path: "/v1/api/p/kv/ns/keys/a", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, module, ok := getAPIModule(tt.path)
+			if !ok {
+				t.Fatalf("getAPIModule() did not find the module of (%s)", tt.path)
+			}
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if got := isWriteRequest(r, module); got != tt.want {
+				t.Errorf("isWriteRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/spaceuptech/space-cloud/gateway/plugins"
 	"github.com/spaceuptech/space-cloud/gateway/server/handlers"
 )
 
@@ -35,14 +36,21 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodGet).Path("/v1/config/refresh-token").HandlerFunc(handlers.HandleRefreshToken(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}").HandlerFunc(handlers.HandleGetProjectConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}").HandlerFunc(handlers.HandleApplyProject(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/maintenance").HandlerFunc(handlers.HandleGetProjectMaintenance(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/maintenance").HandlerFunc(handlers.HandleSetProjectMaintenance(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}").HandlerFunc(handlers.HandleDeleteProjectConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/generate-internal-token").HandlerFunc(handlers.HandleGenerateTokenForMissionControl(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/debug/token").HandlerFunc(handlers.HandleMintToken(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/debug/rules").HandlerFunc(handlers.HandleDebugRule(s.managers.Admin(), s.modules))
 	router.Methods(http.MethodGet).Path("/v1/config/cluster").HandlerFunc(handlers.HandleGetClusterConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/cluster").HandlerFunc(handlers.HandleSetClusterConfig(s.managers.Admin(), s.managers.Sync()))
 
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/remote-service/service").HandlerFunc(handlers.HandleGetService(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/remote-service/service/{id}").HandlerFunc(handlers.HandleAddService(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/remote-service/service/{id}").HandlerFunc(handlers.HandleDeleteService(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/deploy/secrets").HandlerFunc(handlers.HandleGetDeploySecrets(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/deploy/secrets/{id}").HandlerFunc(handlers.HandleSetDeploySecret(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/deploy/secrets/{id}").HandlerFunc(handlers.HandleDeleteDeploySecret(s.managers.Admin(), s.managers.Sync()))
 
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/user-management/provider").HandlerFunc(handlers.HandleGetUserManagement(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/user-management/provider/{id}").HandlerFunc(handlers.HandleSetUserManagement(s.managers.Admin(), s.managers.Sync()))
@@ -62,6 +70,12 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/eventing/schema").HandlerFunc(handlers.HandleGetEventingSchema(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/eventing/schema/{id}").HandlerFunc(handlers.HandleSetEventingSchema(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/eventing/schema/{id}").HandlerFunc(handlers.HandleDeleteEventingSchema(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/eventing/sources").HandlerFunc(handlers.HandleGetEventingSources(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/eventing/sources/{id}").HandlerFunc(handlers.HandleSetEventingSource(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/eventing/sources/{id}").HandlerFunc(handlers.HandleDeleteEventingSource(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/eventing/workflows").HandlerFunc(handlers.HandleGetEventingWorkflows(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/eventing/workflows/{id}").HandlerFunc(handlers.HandleSetEventingWorkflow(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/eventing/workflows/{id}").HandlerFunc(handlers.HandleDeleteEventingWorkflow(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/eventing/rules").HandlerFunc(handlers.HandleGetEventingSecurityRules(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/eventing/rules/{id}").HandlerFunc(handlers.HandleAddEventingSecurityRule(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/eventing/rules/{id}").HandlerFunc(handlers.HandleDeleteEventingSecurityRule(s.managers.Admin(), s.managers.Sync()))
@@ -80,6 +94,7 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/collections/schema/mutate").HandlerFunc(handlers.HandleGetSchemas(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/rules").HandlerFunc(handlers.HandleSetTableRules(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/rules").HandlerFunc(handlers.HandleDeleteTableRules(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/rules/rls").HandlerFunc(handlers.HandleGetRowLevelSecurityPolicies(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/config/{id}").HandlerFunc(handlers.HandleSetDatabaseConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/database/{dbAlias}/config/{id}").HandlerFunc(handlers.HandleRemoveDatabaseConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/database/prepared-queries").HandlerFunc(handlers.HandleGetPreparedQuery(s.managers.Admin(), s.managers.Sync()))
@@ -92,22 +107,112 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/schema/track").HandlerFunc(handlers.HandleInspectCollectionSchema(s.managers.Admin(), s.modules, s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/database/{dbAlias}/collections/{col}/schema/untrack").HandlerFunc(handlers.HandleUntrackCollectionSchema(s.managers.Admin(), s.modules, s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/external/projects/{project}/database/{dbAlias}/schema/inspect").HandlerFunc(handlers.HandleInspectTrackedCollectionsSchema(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/external/projects/{project}/database/{dbAlias}/collections/{col}/indexes").HandlerFunc(handlers.HandleGetIndexDrift(s.managers.Admin(), s.modules))
 
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/letsencrypt/config").HandlerFunc(handlers.HandleGetEncryptWhitelistedDomain(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/letsencrypt/config/{id}").HandlerFunc(handlers.HandleLetsEncryptWhitelistedDomain(s.managers.Admin(), s.managers.Sync()))
 
+	// Initialize the routes for the search module
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/search/config").HandlerFunc(handlers.HandleGetSearchConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/search/config/{id}").HandlerFunc(handlers.HandleSetSearchConfig(s.managers.Admin(), s.managers.Sync()))
+
+	// Initialize the routes for the backup module
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/backups/config").HandlerFunc(handlers.HandleGetBackupConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/backups/config/{id}").HandlerFunc(handlers.HandleSetBackupConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/config/projects/{project}/backups").HandlerFunc(handlers.HandleListBackups(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/backups").HandlerFunc(handlers.HandleTakeBackup(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/backups/{id}/restore").HandlerFunc(handlers.HandleRestoreBackup(s.managers.Admin(), s.modules))
+
+	// Initialize the routes for the fixtures module
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/fixtures/load").HandlerFunc(handlers.HandleLoadFixtures(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/fixtures/teardown").HandlerFunc(handlers.HandleTeardownFixtures(s.managers.Admin(), s.modules))
+
+	// Initialize the routes for the retention policies
+	router.Methods(http.MethodGet).Path("/v1/api/config/projects/{project}/retention").HandlerFunc(handlers.HandleGetRetentionStatus(s.managers.Admin(), s.managers.Sync(), s.modules))
+
+	// Initialize the routes for the key value store
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/kv/config").HandlerFunc(handlers.HandleGetKVConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/kv/config/{id}").HandlerFunc(handlers.HandleSetKVConfig(s.managers.Admin(), s.managers.Sync()))
+
+	// Initialize the routes for the privacy module
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/privacy/config").HandlerFunc(handlers.HandleGetPrivacyConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/privacy/config/{id}").HandlerFunc(handlers.HandleSetPrivacyConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/privacy/users/{id}/export").HandlerFunc(handlers.HandleExportUserData(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/privacy/users/{id}/erase").HandlerFunc(handlers.HandleEraseUserData(s.managers.Admin(), s.modules))
+
+	// Initialize the routes for the sessions of the users managed by the admin
+	router.Methods(http.MethodGet).Path("/v1/api/config/projects/{project}/users/{id}/sessions").HandlerFunc(handlers.HandleGetUserSessions(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodDelete).Path("/v1/api/config/projects/{project}/users/{id}/sessions").HandlerFunc(handlers.HandleRevokeUserSessions(s.managers.Admin(), s.modules))
+
+	// Initialize the routes for the feature flags
+	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/feature-flags").HandlerFunc(handlers.HandleGetFeatureFlags(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/feature-flags/{id}").HandlerFunc(handlers.HandleSetFeatureFlag(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/feature-flags/{id}").HandlerFunc(handlers.HandleDeleteFeatureFlag(s.managers.Admin(), s.managers.Sync()))
+
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/routing/ingress").HandlerFunc(handlers.HandleGetProjectRoute(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/routing/ingress/global").HandlerFunc(handlers.HandleSetGlobalRouteConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodGet).Path("/v1/config/projects/{project}/routing/ingress/global").HandlerFunc(handlers.HandleGetGlobalRouteConfig(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodPost).Path("/v1/config/projects/{project}/routing/ingress/{id}").HandlerFunc(handlers.HandleSetProjectRoute(s.managers.Admin(), s.managers.Sync()))
 	router.Methods(http.MethodDelete).Path("/v1/config/projects/{project}/routing/ingress/{id}").HandlerFunc(handlers.HandleDeleteProjectRoute(s.managers.Admin(), s.managers.Sync()))
 
-	router.Methods(http.MethodPost).Path("/v1/config/batch-apply").HandlerFunc(handlers.HandleBatchApplyConfig(s.managers.Admin()))
+	router.Methods(http.MethodPost).Path("/v1/config/batch-apply").HandlerFunc(handlers.HandleBatchApplyConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/config/export").HandlerFunc(handlers.HandleExportConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/import").HandlerFunc(handlers.HandleImportConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/from-template").HandlerFunc(handlers.HandleCreateProjectFromTemplate(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/clone").HandlerFunc(handlers.HandleCloneProject(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/projects/{project}/diff").HandlerFunc(handlers.HandleDiffProjectConfig(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/config/templates").HandlerFunc(handlers.HandleGetProjectTemplates(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/templates/{id}").HandlerFunc(handlers.HandleSetProjectTemplate(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/api/config/templates/{id}").HandlerFunc(handlers.HandleDeleteProjectTemplate(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/config/admin-tokens").HandlerFunc(handlers.HandleGetAdminTokens(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/admin-tokens").HandlerFunc(handlers.HandleCreateAdminToken(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/api/config/admin-tokens/{id}").HandlerFunc(handlers.HandleDeleteAdminToken(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/config/gitops/status").HandlerFunc(handlers.HandleGetGitOpsStatus(s.managers.Admin(), s.managers.GitOps()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/gitops/sync").HandlerFunc(handlers.HandleGitOpsSync(s.managers.Admin(), s.managers.GitOps()))
+	router.Methods(http.MethodPost).Path("/v1/api/config/gitops/webhook").HandlerFunc(handlers.HandleGitOpsWebhook(s.managers.GitOps()))
 
 	// Health check
 	router.Methods(http.MethodGet).Path("/v1/api/health-check").HandlerFunc(handlers.HandleHealthCheck(s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/health/live").HandlerFunc(handlers.HandleLivenessCheck(s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/health/ready").HandlerFunc(handlers.HandleReadinessCheck(s.managers.Sync(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/cluster/role").HandlerFunc(handlers.HandleGetClusterRole(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/cluster/topology").HandlerFunc(handlers.HandleGetClusterTopology(s.managers.Admin(), s.managers.Sync()))
+	router.Methods(http.MethodGet).Path("/v1/api/cluster/events").HandlerFunc(handlers.HandleAdminEvents(s.managers.Admin(), s.managers.Sync()))
+
+	// Runtime diagnostics
+	router.Methods(http.MethodGet).Path("/v1/api/diagnostics/runtime").HandlerFunc(handlers.HandleGetRuntimeStats(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/diagnostics/goroutines").HandlerFunc(handlers.HandleGetGoroutineDump(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/diagnostics/pprof/{profile}").HandlerFunc(handlers.HandleGetProfile(s.managers.Admin(), s.modules))
+
+	// Operations in flight
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/operations").HandlerFunc(handlers.HandleGetOperations(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodDelete).Path("/v1/api/{project}/operations/{id}").HandlerFunc(handlers.HandleCancelOperation(s.managers.Admin(), s.modules))
+
+	// Distributed locks
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/locks/{name}").HandlerFunc(handlers.HandleAcquireLock(s.modules, s.managers.Sync()))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/locks/{name}/renew").HandlerFunc(handlers.HandleRenewLock(s.modules, s.managers.Sync()))
+	router.Methods(http.MethodDelete).Path("/v1/api/{project}/locks/{name}").HandlerFunc(handlers.HandleReleaseLock(s.modules, s.managers.Sync()))
+
+	// Key value store
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/kv/{namespace}/keys").HandlerFunc(handlers.HandleListKeys(s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/kv/{namespace}/keys/{key}").HandlerFunc(handlers.HandleGetKey(s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/kv/{namespace}/keys/{key}").HandlerFunc(handlers.HandleSetKey(s.modules))
+	router.Methods(http.MethodDelete).Path("/v1/api/{project}/kv/{namespace}/keys/{key}").HandlerFunc(handlers.HandleDeleteKey(s.modules))
+
+	// Feature flags
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/flags").HandlerFunc(handlers.HandleGetFlags(s.modules))
+
+	// Resource usage
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/usage").HandlerFunc(handlers.HandleGetUsage(s.managers.Admin(), s.modules))
+
+	// Service level objectives
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/slo").HandlerFunc(handlers.HandleGetSLOStatus(s.managers.Admin(), s.modules))
+
+	// Prometheus metrics
+	router.Methods(http.MethodGet).Path("/v1/metrics").HandlerFunc(handlers.HandlePrometheusMetrics(s.managers.Admin(), s.modules, s.managers.Sync()))
 
 	// Initialize route for graphql
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/graphql/schema").HandlerFunc(handlers.HandleGetGraphQLSchema(s.managers.Admin(), s.modules))
 	router.Path("/v1/api/{project}/graphql").HandlerFunc(handlers.HandleGraphQLRequest(s.modules, s.managers.Sync()))
 
 	// Initialize the route for websocket
@@ -122,24 +227,67 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	// Initialize the routes for realtime service
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/realtime/handle").HandlerFunc(handlers.HandleRealtimeEvent(s.modules))
 
+	// Initialize the routes for full text search
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/search/handle").HandlerFunc(handlers.HandleSearchEvent(s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/search/{dbAlias}/{col}").HandlerFunc(handlers.HandleSearch(s.modules))
+
 	// Initialize the routes for eventing service
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/eventing/queue").HandlerFunc(handlers.HandleQueueEvent(s.modules))
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/eventing/admin-queue").HandlerFunc(handlers.HandleAdminQueueEvent(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/eventing/ingest/{source}").HandlerFunc(handlers.HandleIngestEvent(s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/eventing/events").HandlerFunc(handlers.HandleGetEventLogs(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/eventing/replay").HandlerFunc(handlers.HandleReplayEvents(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/eventing/dlq").HandlerFunc(handlers.HandleGetDeadLetterEvents(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/eventing/dlq/retry").HandlerFunc(handlers.HandleRetryDeadLetterEvents(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodPost).Path("/v1/api/{project}/eventing/dlq/purge").HandlerFunc(handlers.HandlePurgeDeadLetterEvents(s.managers.Admin(), s.modules))
 
 	// Initialize the routes for the crud operations
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/crud/{dbAlias}/batch").HandlerFunc(handlers.HandleCrudBatch(s.modules))
 	router.Methods(http.MethodPost).Path("/v1/api/{project}/crud/{dbAlias}/prepared-queries/{id}").HandlerFunc(handlers.HandleCrudPreparedQuery(s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/crud/{dbAlias}/slow-queries").HandlerFunc(handlers.HandleGetSlowQueries(s.managers.Admin(), s.modules))
 	crudRouter := router.Methods(http.MethodPost).PathPrefix("/v1/api/{project}/crud/{dbAlias}/{col}").Subrouter()
 	crudRouter.HandleFunc("/create", handlers.HandleCrudCreate(s.modules))
 	crudRouter.HandleFunc("/read", handlers.HandleCrudRead(s.modules))
 	crudRouter.HandleFunc("/update", handlers.HandleCrudUpdate(s.modules))
+	crudRouter.HandleFunc("/increment", handlers.HandleCrudIncrement(s.modules))
 	crudRouter.HandleFunc("/delete", handlers.HandleCrudDelete(s.modules))
 	crudRouter.HandleFunc("/aggr", handlers.HandleCrudAggregate(s.modules))
+	crudRouter.HandleFunc("/import", handlers.HandleCrudImport(s.managers.Admin(), s.modules))
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/crud/{dbAlias}/{col}/export").HandlerFunc(handlers.HandleCrudExport(s.managers.Admin(), s.modules))
+
+	// Initialize the routes for the sessions of the users
+	router.Methods(http.MethodGet).Path("/v1/api/{project}/auth/sessions").HandlerFunc(handlers.HandleGetSessions(s.modules))
+	router.Methods(http.MethodDelete).Path("/v1/api/{project}/auth/sessions").HandlerFunc(handlers.HandleRevokeSessions(s.modules))
+	router.Methods(http.MethodDelete).Path("/v1/api/{project}/auth/sessions/{id}").HandlerFunc(handlers.HandleRevokeSession(s.modules))
+
+	// Initialize the routes for provisioning the users and groups over scim
+	scimRouter := router.PathPrefix("/v1/api/{project}/scim/v2").Subrouter()
+	scimRouter.Methods(http.MethodGet).Path("/ServiceProviderConfig").HandlerFunc(handlers.HandleSCIMServiceProviderConfig(s.modules))
+	scimRouter.Methods(http.MethodGet).Path("/Users").HandlerFunc(handlers.HandleSCIMListUsers(s.modules))
+	scimRouter.Methods(http.MethodPost).Path("/Users").HandlerFunc(handlers.HandleSCIMCreateUser(s.modules))
+	scimRouter.Methods(http.MethodGet).Path("/Users/{id}").HandlerFunc(handlers.HandleSCIMGetUser(s.modules))
+	scimRouter.Methods(http.MethodPut).Path("/Users/{id}").HandlerFunc(handlers.HandleSCIMReplaceUser(s.modules))
+	scimRouter.Methods(http.MethodPatch).Path("/Users/{id}").HandlerFunc(handlers.HandleSCIMPatchUser(s.modules))
+	scimRouter.Methods(http.MethodDelete).Path("/Users/{id}").HandlerFunc(handlers.HandleSCIMDeleteUser(s.modules))
+	scimRouter.Methods(http.MethodGet).Path("/Groups").HandlerFunc(handlers.HandleSCIMListGroups(s.modules))
+	scimRouter.Methods(http.MethodPost).Path("/Groups").HandlerFunc(handlers.HandleSCIMCreateGroup(s.modules))
+	scimRouter.Methods(http.MethodGet).Path("/Groups/{id}").HandlerFunc(handlers.HandleSCIMGetGroup(s.modules))
+	scimRouter.Methods(http.MethodPut).Path("/Groups/{id}").HandlerFunc(handlers.HandleSCIMReplaceGroup(s.modules))
+	scimRouter.Methods(http.MethodPatch).Path("/Groups/{id}").HandlerFunc(handlers.HandleSCIMPatchGroup(s.modules))
+	scimRouter.Methods(http.MethodDelete).Path("/Groups/{id}").HandlerFunc(handlers.HandleSCIMDeleteGroup(s.modules))
 
 	// Initialize the routes for the user management operations
 	userRouter := router.PathPrefix("/v1/api/{project}/auth/{dbAlias}").Subrouter()
 	userRouter.Methods(http.MethodPost).Path("/email/signin").HandlerFunc(handlers.HandleEmailSignIn(s.modules))
 	userRouter.Methods(http.MethodPost).Path("/email/signup").HandlerFunc(handlers.HandleEmailSignUp(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/verify/send").HandlerFunc(handlers.HandleSendVerificationEmail(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/verify").HandlerFunc(handlers.HandleVerifyEmail(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/reset/send").HandlerFunc(handlers.HandleSendPasswordReset(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/reset").HandlerFunc(handlers.HandleResetPassword(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/email/signin/2fa").HandlerFunc(handlers.HandleEmailSignInTwoFactor(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/2fa/enroll").HandlerFunc(handlers.HandleEnrollTwoFactor(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/2fa/confirm").HandlerFunc(handlers.HandleConfirmTwoFactor(s.modules))
+	userRouter.Methods(http.MethodPost).Path("/2fa/disable").HandlerFunc(handlers.HandleDisableTwoFactor(s.modules))
 	userRouter.Methods(http.MethodGet).Path("/profile/{id}").HandlerFunc(handlers.HandleProfile(s.modules))
 	userRouter.Methods(http.MethodGet).Path("/profiles").HandlerFunc(handlers.HandleProfiles(s.modules))
 	userRouter.Methods(http.MethodPost).Path("/edit_profile/{id}").HandlerFunc(handlers.HandleEmailEditProfile(s.modules))
@@ -178,6 +326,12 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 	runnerRouter.Methods(http.MethodPost).Path("/{project}/service-routes/{serviceId}").HandlerFunc(s.managers.Sync().HandleRunnerServiceRoutingRequest(s.managers.Admin()))
 	runnerRouter.Methods(http.MethodGet).Path("/{project}/service-routes").HandlerFunc(s.managers.Sync().HandleRunnerGetServiceRoutingRequest(s.managers.Admin()))
 
+	// service rollouts
+	runnerRouter.Methods(http.MethodPost).Path("/{project}/rollouts/{serviceId}").HandlerFunc(s.managers.Sync().HandleRunnerRolloutRequest(s.managers.Admin()))
+	runnerRouter.Methods(http.MethodGet).Path("/{project}/rollouts").HandlerFunc(s.managers.Sync().HandleRunnerGetRollouts(s.managers.Admin()))
+	runnerRouter.Methods(http.MethodPost).Path("/{project}/rollouts/{serviceId}/promote").HandlerFunc(s.managers.Sync().HandleRunnerRolloutRequest(s.managers.Admin()))
+	runnerRouter.Methods(http.MethodPost).Path("/{project}/rollouts/{serviceId}/abort").HandlerFunc(s.managers.Sync().HandleRunnerRolloutRequest(s.managers.Admin()))
+
 	// service role
 	runnerRouter.Methods(http.MethodPost).Path("/{project}/service-roles/{serviceId}/{roleId}").HandlerFunc(s.managers.Sync().HandleRunnerSetServiceRole(s.managers.Admin()))
 	runnerRouter.Methods(http.MethodGet).Path("/{project}/service-roles").HandlerFunc(s.managers.Sync().HandleRunnerGetServiceRoleRequest(s.managers.Admin()))
@@ -191,6 +345,9 @@ func (s *Server) routes(profiler bool, staticPath string, restrictedHosts []stri
 		router.PathPrefix("/mission-control").HandlerFunc(handlers.HandleMissionControl(staticPath))
 	}
 
+	// Add the routes registered by the embedding program
+	plugins.AddRoutes(router)
+
 	// Add handler for routing module
 	router.PathPrefix("/").HandlerFunc(s.modules.Routing().HandleRoutes(s.modules))
 	return s.restrictDomainMiddleware(restrictedHosts, router)
@@ -2,32 +2,51 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/spaceuptech/helpers"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/managers"
+	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/modules"
 	"github.com/spaceuptech/space-cloud/gateway/modules/global"
+	"github.com/spaceuptech/space-cloud/gateway/modules/global/metrics"
+	"github.com/spaceuptech/space-cloud/gateway/server/handlers"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+	"github.com/spaceuptech/space-cloud/gateway/utils/wal"
 )
 
 // Server is the object which sets up the server and handles all server operations
 type Server struct {
 	nodeID   string
 	ssl      *config.SSL
+	listener *config.Listener
 	modules  *modules.Modules
 	managers *managers.Managers
+	wal      *wal.WAL
 }
 
 // New creates a new server instance
-func New(nodeID, clusterID, storeType, runnerAddr string, isDev bool, adminUserInfo *config.AdminUser, ssl *config.SSL) (*Server, error) {
+func New(nodeID, clusterID, storeType, clusterMode, runnerAddr string, isDev bool, adminUserInfo *config.AdminUser, ssl *config.SSL, listener *config.Listener, gitOps *config.GitOps) (*Server, error) {
 
-	managers, err := managers.New(nodeID, clusterID, storeType, runnerAddr, isDev, adminUserInfo, ssl)
+	// Initialise the tracer. Spans are exported once tracing is enabled in the cluster config
+	tracing.Init(nodeID, clusterID)
+
+	managers, err := managers.New(nodeID, clusterID, storeType, clusterMode, runnerAddr, isDev, adminUserInfo, ssl, gitOps)
 	if err != nil {
 		return nil, err
 	}
@@ -43,28 +62,105 @@ func New(nodeID, clusterID, storeType, runnerAddr string, isDev bool, adminUserI
 	}
 
 	managers.Sync().SetModules(modules)
-	managers.Sync().SetGlobalModules(globalMods.Metrics())
+	managers.Sync().SetGlobalModules(globalMods)
+	globalMods.Metrics().SetGaugesSource(func() []metrics.Gauge { return handlers.MetricGauges(managers.Sync(), modules) })
+	globalMods.Secrets().SetRotationHook(managers.Sync().ReloadSecrets)
+	globalMods.Routing().SetZoneSource(managers.Sync().GetNodeZone)
 
 	helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Creating a new server with id %s", nodeID), nil)
 
-	return &Server{nodeID: nodeID, managers: managers, modules: modules, ssl: ssl}, nil
+	return &Server{nodeID: nodeID, managers: managers, modules: modules, ssl: ssl, listener: listener}, nil
+}
+
+// SetTestMode lets the projects load their fixtures
+func (s *Server) SetTestMode(testMode bool) {
+	s.modules.SetTestMode(testMode)
+}
+
+// SetWAL persists the queued status updates of the events and the metrics yet to be pushed in a write ahead log kept
+// in the directory, so that they survive a crash. The log stops growing at the max size in bytes by slowing down the
+// producers. It must be called before the server is started.
+func (s *Server) SetWAL(dir string, maxSize int64) error {
+	if dir == "" {
+		return nil
+	}
+
+	w, err := wal.Open(filepath.Join(dir, "wal.db"), maxSize)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Unable to open the write ahead log in (%s)", dir), err, nil)
+	}
+
+	s.wal = w
+	s.modules.SetWAL(w)
+	s.modules.Metrics().SetWAL(w)
+	return nil
+}
+
+// SetAdvertiseAddr sets the address and the scheme at which the other nodes of the cluster can reach this node
+func (s *Server) SetAdvertiseAddr(addr, scheme string) error {
+	return s.managers.Sync().SetAdvertiseAddr(addr, scheme)
+}
+
+// SetNodeMetadata sets the custom metadata this node advertises to the other nodes of the cluster
+func (s *Server) SetNodeMetadata(metadata *model.NodeMetadata) error {
+	return s.managers.Sync().SetNodeMetadata(metadata)
+}
+
+// OnMembershipEvent registers a callback which gets invoked whenever a node joins, leaves or updates the metadata it
+// advertises to the cluster
+func (s *Server) OnMembershipEvent(id string, cb func(event *model.MembershipEvent)) {
+	s.managers.Sync().OnMembershipEvent(id, cb)
 }
 
-// Start begins the server operations
-func (s *Server) Start(profiler bool, staticPath string, port int, restrictedHosts []string) error {
+// GetAdminToken returns a token of the admin user. It is logged in the dev mode so that the admin apis can be used
+// right away.
+func (s *Server) GetAdminToken() (string, error) {
+	return s.managers.Admin().GetAdminToken()
+}
+
+// CreateToken signs a token for the users of a project with its primary secret
+func (s *Server) CreateToken(ctx context.Context, projectID string, claims model.TokenClaims) (string, error) {
+	a, err := s.modules.Auth(projectID)
+	if err != nil {
+		return "", err
+	}
+	return a.CreateToken(ctx, claims)
+}
+
+// Init starts the sync manager and loads the config without starting any listeners. The port is the one the gateway
+// is reachable on.
+func (s *Server) Init(port int) error {
 	// Start the sync manager
 	if err := s.managers.Sync().Start(port); err != nil {
 		return err
 	}
 
+	// Start syncing the config from the gitops repository
+	return s.managers.GitOps().Start()
+}
+
+// Handler returns the handler serving all the routes of the gateway along with its middlewares
+func (s *Server) Handler(profiler bool, staticPath string, restrictedHosts []string) http.Handler {
 	// Allow cors
 	corsObj := utils.CreateCorsObject()
 
+	handler := corsObj.Handler(loggerMiddleWare(apiModuleMiddleWare(tracingMiddleWare(compressionMiddleWare(s.modules.Compression(), ruleTraceMiddleWare(s.managers.Admin(), s.modules.Logging(), accessLogMiddleWare(s.modules.Logging(), metricsMiddleWare(s.modules, s.modules.Metrics(), sloMiddleWare(s.modules, admissionMiddleWare(s.modules.Admission(), maintenanceMiddleWare(s.modules, accountingMiddleWare(s.modules, s.modules.Accounting(), operationsMiddleWare(s.modules.Operations(), idempotencyMiddleWare(s.modules.Idempotency(), s.routes(profiler, staticPath, restrictedHosts)))))))))))))))
+	return s.modules.LetsEncrypt().LetsEncryptHTTPChallengeHandler(handler)
+}
+
+// Start begins the server operations. It blocks till the server gets shut down on receiving SIGTERM or SIGINT.
+func (s *Server) Start(profiler bool, staticPath string, port int, restrictedHosts []string, drainTimeout time.Duration) error {
+	if err := s.Init(port); err != nil {
+		return err
+	}
+
+	servers := make([]shutdowner, 0, 3)
+	h2 := &http2.Server{MaxConcurrentStreams: s.listener.MaxConcurrentStreams}
+
 	if s.ssl != nil && s.ssl.Enabled {
 
 		// Setup the handler
-		handler := corsObj.Handler(loggerMiddleWare(s.routes(profiler, staticPath, restrictedHosts)))
-		handler = s.modules.LetsEncrypt().LetsEncryptHTTPChallengeHandler(handler)
+		handler := s.Handler(profiler, staticPath, restrictedHosts)
 
 		// Add existing certificates if any
 		if s.ssl.Key != "none" && s.ssl.Crt != "none" {
@@ -74,18 +170,37 @@ func (s *Server) Start(profiler bool, staticPath string, port int, restrictedHos
 			}
 		}
 
+		addr := ":" + strconv.Itoa(port+4)
+		if s.listener.HTTP3 {
+			h3, advertise, err := startHTTP3(addr, handler, s.modules.LetsEncrypt().TLSConfig())
+			if err != nil {
+				return err
+			}
+			servers = append(servers, h3)
+			handler = advertise(handler)
+		}
+
+		httpsServer := &http.Server{Addr: addr, Handler: handler, TLSConfig: s.modules.LetsEncrypt().TLSConfig()}
+		if s.listener.DisableHTTP2 {
+			// A non nil map keeps the server from negotiating http2
+			httpsServer.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		} else if err := http2.ConfigureServer(httpsServer, h2); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), "Unable to configure http2 on the https server", err, nil)
+		}
+		servers = append(servers, httpsServer)
 		go func() {
 			// Start the server
 			helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Starting https server on port: "+strconv.Itoa(port+4), nil)
-			httpsServer := &http.Server{Addr: ":" + strconv.Itoa(port+4), Handler: handler, TLSConfig: s.modules.LetsEncrypt().TLSConfig()}
-			if err := httpsServer.ListenAndServeTLS("", ""); err != nil {
+			if err := httpsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 				log.Fatalln("Error starting https server:", err)
 			}
 		}()
 	}
 
-	handler := corsObj.Handler(loggerMiddleWare(s.routes(profiler, staticPath, restrictedHosts)))
-	handler = s.modules.LetsEncrypt().LetsEncryptHTTPChallengeHandler(handler)
+	handler := s.Handler(profiler, staticPath, restrictedHosts)
+	if s.listener.H2C {
+		handler = h2c.NewHandler(handler, h2)
+	}
 
 	helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Starting http server on port: "+strconv.Itoa(port), nil)
 
@@ -93,6 +208,87 @@ func (s *Server) Start(profiler bool, staticPath string, port int, restrictedHos
 		helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), "Hosting mission control on http://localhost:"+strconv.Itoa(port)+"/mission-control/", nil)
 	}
 
-	helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Space cloud is running on the specified ports :%v", port), nil)
-	return http.ListenAndServe(":"+strconv.Itoa(port), handler)
+	listeners, err := getListeners(port, s.listener)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{Addr: ":" + strconv.Itoa(port), Handler: handler}
+	servers = append(servers, httpServer)
+	errC := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Space cloud is running on the address (%s)", l.Addr()), nil)
+			errC <- httpServer.Serve(l)
+		}(l)
+	}
+
+	// Wait for the server to fail or for a signal to shut down
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(stop)
+
+	select {
+	case err := <-errC:
+		return err
+	case sig := <-stop:
+		helpers.Logger.LogInfo(helpers.GetRequestID(context.TODO()), fmt.Sprintf("Received signal (%s), shutting down gracefully", sig), map[string]interface{}{"drainTimeout": drainTimeout.String()})
+	}
+
+	return s.shutdown(servers, drainTimeout)
+}
+
+// Shutdown drains the node like on receiving SIGTERM. It is meant for the gateways started with Init whose listeners
+// are managed by the caller.
+func (s *Server) Shutdown(timeout time.Duration) error {
+	return s.shutdown(nil, timeout)
+}
+
+// shutdowner is a server which stops accepting new requests and waits for the in flight requests to complete on
+// being shut down
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// shutdown drains the node within the provided timeout. The node stops accepting new requests, waits for the
+// in flight requests to complete, closes the websockets, flushes the pending events and finally leaves the cluster.
+func (s *Server) shutdown(servers []shutdowner, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Fail the readiness checks so that the load balancers stop routing requests to this node
+	s.managers.Sync().StartDraining()
+	s.managers.GitOps().Close()
+
+	// Stop accepting new connections and wait for the in flight requests to complete
+	var err error
+	for _, server := range servers {
+		if e := server.Shutdown(ctx); e != nil {
+			err = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to complete in flight requests before the drain timeout", e, nil)
+		}
+	}
+
+	// Websockets are hijacked from the http server and hence need to be closed separately
+	handlers.CloseWebsockets("Server is shutting down. Please reconnect.")
+
+	s.modules.Shutdown(ctx)
+
+	if e := s.managers.Sync().Leave(ctx); e != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to leave the cluster cleanly", e, nil)
+	}
+
+	// Export the pending resource usage and metrics and flush the buffered logs and spans
+	s.modules.Accounting().Close()
+	s.modules.Metrics().Close()
+	s.modules.Logging().Close()
+	tracing.SetConfig(nil)
+
+	if s.wal != nil {
+		if e := s.wal.Close(); e != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to close the write ahead log", e, nil)
+		}
+	}
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), "Space cloud has been shut down", nil)
+	return err
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
 
@@ -34,9 +35,8 @@ func ApplySpec(ctx context.Context, token, hostAddr string, specObj *model.SpecO
 		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "error while applying service unable to send http request", err, nil)
 	}
 
-	v := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&v)
-	CloseTheCloser(req.Body)
+	body, _ := ioutil.ReadAll(resp.Body)
+	CloseTheCloser(resp.Body)
 
 	if resp.StatusCode == http.StatusAccepted {
 		// Make checker send this status
@@ -44,8 +44,9 @@ func ApplySpec(ctx context.Context, token, hostAddr string, specObj *model.SpecO
 	} else if resp.StatusCode == http.StatusOK {
 		helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Successfully applied %s", specObj.Type), nil)
 	} else {
-		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error while applying service got http status code %s", resp.Status), fmt.Errorf("%s", v["error"]), nil)
-		return fmt.Errorf("%v", v["error"])
+		err := ParseErrorResponse(resp.StatusCode, body)
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("error while applying service got http status code %s", resp.Status), err, nil)
+		return err
 	}
 	return nil
 }
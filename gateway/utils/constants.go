@@ -7,6 +7,10 @@ import (
 // BuildVersion is the current version of Space Cloud
 const BuildVersion = "0.21.5"
 
+// ProtocolVersion is the version of the config replication protocol spoken by this node. It must be bumped
+// whenever a resource type is added so that nodes of a mixed version cluster can tell what their peers understand.
+const ProtocolVersion = 2
+
 // DLQEventTriggerPrefix used as suffix for DLQ event trigger
 const DLQEventTriggerPrefix = "dlq_"
 
@@ -72,6 +76,21 @@ const (
 
 	// TypeRealtimeFeed is the response type for realtime feed
 	TypeRealtimeFeed string = "realtime-feed"
+
+	// TypeServiceCall is the request type for calling a function of a remote service
+	TypeServiceCall string = "service-call"
+
+	// TypeServiceCallChunk is the response type for the chunks of a function streaming its response
+	TypeServiceCallChunk string = "service-call-chunk"
+
+	// TypeFlagsSubscribe is the request type for subscribing to the feature flags of a project
+	TypeFlagsSubscribe string = "flags-subscribe"
+
+	// TypeFlagsUnsubscribe is the request type for unsubscribing from the feature flags of a project
+	TypeFlagsUnsubscribe string = "flags-unsubscribe"
+
+	// TypeFlagsFeed is the response type carrying the feature flags whenever they change
+	TypeFlagsFeed string = "flags-feed"
 )
 
 // DefaultConfigFilePath is the default path to load / store the config file
@@ -168,6 +187,9 @@ const (
 
 	// EventStatusCancelled signifies that the event has been cancelled and should not be processed
 	EventStatusCancelled string = "cancel"
+
+	// EventStatusRetried signifies that the failed event has been queued again from the dead letter queue
+	EventStatusRetried string = "retried"
 )
 
 // RequestKind specifies the kind of the request
@@ -190,6 +212,12 @@ type TypeMakeHTTPRequest func(ctx context.Context, method, url, token, scToken s
 // GetSecrets gets fileStore and database secrets from runner
 type GetSecrets func(project, secretName, key string) (string, error)
 
+// ResolveSecret resolves a reference to a secret stored in an external secret manager
+type ResolveSecret func(ctx context.Context, ref string) (string, error)
+
+// EvaluateFeatureFlag returns whether a feature flag is on for the caller having the provided claims
+type EvaluateFeatureFlag func(id string, claims map[string]interface{}) (bool, error)
+
 // DefaultContextTime used for creating default context time for endpoints
 const DefaultContextTime = 100
 
@@ -199,3 +227,12 @@ const DefaultCacheTTLTimeout = 60 * 5
 
 // AdminSecretKID describes the kid to be used for admin secrets
 const AdminSecretKID = "sc-admin-kid"
+
+// HeaderDebugRules carries an admin token to attach the trace of the security rules to the error responses of a request
+const HeaderDebugRules = "X-SC-Debug-Rules"
+
+// HeaderIdempotencyKey carries the key used to deduplicate retries of config writes and crud mutations
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// HeaderIdempotentReplayed is set on the responses replayed from an earlier request with the same idempotency key
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
@@ -0,0 +1,129 @@
+package docformat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// maxLineSize is the maximum size of a line of a ndjson stream
+const maxLineSize = 16 * 1024 * 1024
+
+// Decoder reads documents one at a time. It returns io.EOF once the stream ends and a *RowError for a row
+// which could not be decoded.
+type Decoder interface {
+	Decode() (map[string]interface{}, error)
+}
+
+// NewDecoder returns a decoder of the format. The values of csv files are converted to the types of the fields
+// of the collection. Values of fields whose type isn't known are left as strings.
+func NewDecoder(format string, r io.Reader, fields model.Fields) (Decoder, error) {
+	switch format {
+	case NDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &ndjsonDecoder{scanner: scanner}, nil
+	case CSV:
+		reader := csv.NewReader(r)
+		reader.ReuseRecord = true
+		return &csvDecoder{reader: reader, fields: fields}, nil
+	default:
+		return nil, Validate(format)
+	}
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+}
+
+func (d *ndjsonDecoder) Decode() (map[string]interface{}, error) {
+	for d.scanner.Scan() {
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(line, &doc); err != nil {
+			return nil, &RowError{Err: fmt.Errorf("invalid json document - %v", err)}
+		}
+		return doc, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	fields  model.Fields
+	columns []string
+}
+
+func (d *csvDecoder) Decode() (map[string]interface{}, error) {
+	if d.columns == nil {
+		header, err := d.reader.Read()
+		if err == io.EOF {
+			return nil, errNoColumns
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.columns = append([]string{}, header...)
+	}
+
+	record, err := d.reader.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			return nil, &RowError{Err: err}
+		}
+		return nil, err
+	}
+
+	doc := make(map[string]interface{}, len(d.columns))
+	for i, column := range d.columns {
+		// Empty cells are left out so that the database can use the default value of the field
+		if record[i] == "" {
+			continue
+		}
+		value, err := parseCell(d.fields[column], record[i])
+		if err != nil {
+			return nil, &RowError{Err: fmt.Errorf("invalid value provided for field (%s) - %v", column, err)}
+		}
+		doc[column] = value
+	}
+	return doc, nil
+}
+
+func parseCell(field *model.FieldType, cell string) (interface{}, error) {
+	if field == nil {
+		return cell, nil
+	}
+	if field.IsList {
+		var v interface{}
+		err := json.Unmarshal([]byte(cell), &v)
+		return v, err
+	}
+
+	switch field.Kind {
+	case model.TypeInteger, model.TypeSmallInteger, model.TypeBigInteger:
+		return strconv.ParseInt(cell, 10, 64)
+	case model.TypeFloat, model.TypeDecimal:
+		return strconv.ParseFloat(cell, 64)
+	case model.TypeBoolean:
+		return strconv.ParseBool(cell)
+	case model.TypeJSON, model.TypeObject:
+		var v interface{}
+		err := json.Unmarshal([]byte(cell), &v)
+		return v, err
+	default:
+		return cell, nil
+	}
+}
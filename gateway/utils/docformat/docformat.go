@@ -0,0 +1,110 @@
+// Package docformat encodes and decodes streams of documents in the NDJSON and CSV formats
+package docformat
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// The supported formats
+const (
+	NDJSON = "ndjson"
+	CSV    = "csv"
+)
+
+// The content types of the supported formats
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeCSV    = "text/csv"
+)
+
+// RowError is returned by the decoders for a row which could not be decoded. Decoding can continue with the next row.
+type RowError struct {
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return e.Err.Error()
+}
+
+// FromContentType returns the format of the provided content type
+func FromContentType(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	switch mediaType {
+	case ContentTypeNDJSON, "application/ndjson", "application/jsonl":
+		return NDJSON, true
+	case ContentTypeCSV:
+		return CSV, true
+	}
+	return "", false
+}
+
+// FromAccept returns the supported format listed in the accept header of a request. Nothing is returned if json
+// or any content type is listed before it.
+func FromAccept(accept string) (string, bool) {
+	for _, contentType := range strings.Split(accept, ",") {
+		contentType = strings.TrimSpace(contentType)
+		if format, ok := FromContentType(contentType); ok {
+			return format, true
+		}
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && (mediaType == "application/json" || mediaType == "*/*") {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// ContentType returns the content type of the format
+func ContentType(format string) string {
+	if format == CSV {
+		return ContentTypeCSV
+	}
+	return ContentTypeNDJSON
+}
+
+// Validate checks if the format is supported
+func Validate(format string) error {
+	if format != NDJSON && format != CSV {
+		return fmt.Errorf("invalid format (%s) provided - format should either be %s or %s", format, NDJSON, CSV)
+	}
+	return nil
+}
+
+// Columns returns the columns of a csv file for a collection. The primary keys come first followed by the rest
+// of the fields sorted by their name. Top level fields of the document are used if the schema isn't available.
+func Columns(fields model.Fields, doc map[string]interface{}) []string {
+	columns := make([]string, 0)
+	if len(fields) > 0 {
+		others := make([]string, 0, len(fields))
+		for name, field := range fields {
+			if field.IsPrimary {
+				columns = append(columns, name)
+				continue
+			}
+			// Linked fields aren't stored in the collection
+			if field.IsLinked {
+				continue
+			}
+			others = append(others, name)
+		}
+		sort.Strings(columns)
+		sort.Strings(others)
+		return append(columns, others...)
+	}
+
+	for name := range doc {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+var errNoColumns = errors.New("csv file does not have a header row")
@@ -0,0 +1,193 @@
+package docformat
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestDecoder(t *testing.T) {
+	fields := model.Fields{
+		"id":     &model.FieldType{FieldName: "id", Kind: model.TypeID, IsPrimary: true},
+		"age":    &model.FieldType{FieldName: "age", Kind: model.TypeInteger},
+		"score":  &model.FieldType{FieldName: "score", Kind: model.TypeFloat},
+		"active": &model.FieldType{FieldName: "active", Kind: model.TypeBoolean},
+		"tags":   &model.FieldType{FieldName: "tags", Kind: model.TypeVarChar, IsList: true},
+	}
+
+	tests := []struct {
+		name       string
+		format     string
+		data       string
+		wantDocs   []map[string]interface{}
+		wantErrors []int
+	}{
+		{
+			name:     "ndjson documents",
+			format:   NDJSON,
+			data:     "{\"id\":\"1\",\"age\":20}\n\n{\"id\":\"2\",\"nested\":{\"a\":true}}\n",
+			wantDocs: []map[string]interface{}{{"id": "1", "age": float64(20)}, {"id": "2", "nested": map[string]interface{}{"a": true}}},
+		},
+		{
+			name:       "invalid ndjson line",
+			format:     NDJSON,
+			data:       "{\"id\":\"1\"}\n{\"id\":\n{\"id\":\"3\"}",
+			wantDocs:   []map[string]interface{}{{"id": "1"}, {"id": "3"}},
+			wantErrors: []int{2},
+		},
+		{
+			name:   "csv values are converted to the types of the fields",
+			format: CSV,
+			data:   "id,age,score,active,tags,unknown\n1,20,4.5,true,\"[\"\"a\"\"]\",42\n2,,,false,,\n",
+			wantDocs: []map[string]interface{}{
+				{"id": "1", "age": int64(20), "score": 4.5, "active": true, "tags": []interface{}{"a"}, "unknown": "42"},
+				{"id": "2", "active": false},
+			},
+		},
+		{
+			name:       "invalid csv rows",
+			format:     CSV,
+			data:       "id,age\n1,abc\n2\n3,30\n",
+			wantDocs:   []map[string]interface{}{{"id": "3", "age": int64(30)}},
+			wantErrors: []int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewDecoder(tt.format, strings.NewReader(tt.data), fields)
+			if err != nil {
+				t.Fatalf("NewDecoder() error = %v", err)
+			}
+
+			docs := make([]map[string]interface{}, 0)
+			rowErrors := make([]int, 0)
+			for row := 1; ; row++ {
+				doc, err := decoder.Decode()
+				if err == io.EOF {
+					break
+				}
+				var rowErr *RowError
+				if errors.As(err, &rowErr) {
+					rowErrors = append(rowErrors, row)
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				docs = append(docs, doc)
+			}
+
+			if !reflect.DeepEqual(docs, tt.wantDocs) {
+				t.Errorf("Decode() docs = %v, want %v", docs, tt.wantDocs)
+			}
+			if len(tt.wantErrors) > 0 && !reflect.DeepEqual(rowErrors, tt.wantErrors) {
+				t.Errorf("Decode() failed rows = %v, want %v", rowErrors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	docs := []map[string]interface{}{
+		{"id": "1", "age": int64(20), "score": 4.5, "createdAt": ts, "tags": []interface{}{"a", "b"}},
+		{"id": "2", "extra": true},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		columns []string
+		docs    []map[string]interface{}
+		want    string
+	}{
+		{
+			name:   "ndjson",
+			format: NDJSON,
+			docs:   docs[1:],
+			want:   "{\"extra\":true,\"id\":\"2\"}\n",
+		},
+		{
+			name:    "csv with columns",
+			format:  CSV,
+			columns: []string{"id", "age", "score", "createdAt", "tags"},
+			docs:    docs,
+			want:    "id,age,score,createdAt,tags\n1,20,4.5,2020-01-02T03:04:05Z,\"[\"\"a\"\",\"\"b\"\"]\"\n2,,,,\n",
+		},
+		{
+			name:   "csv columns are taken from the first document",
+			format: CSV,
+			docs:   docs[1:],
+			want:   "extra,id\ntrue,2\n",
+		},
+		{
+			name:    "csv without documents",
+			format:  CSV,
+			columns: []string{"id"},
+			want:    "id\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			encoder, err := NewEncoder(tt.format, buf, tt.columns)
+			if err != nil {
+				t.Fatalf("NewEncoder() error = %v", err)
+			}
+			for _, doc := range tt.docs {
+				if err := encoder.Encode(doc); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+			}
+			if err := encoder.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	fields := model.Fields{
+		"name":  &model.FieldType{FieldName: "name"},
+		"id":    &model.FieldType{FieldName: "id", IsPrimary: true},
+		"posts": &model.FieldType{FieldName: "posts", IsLinked: true},
+		"age":   &model.FieldType{FieldName: "age"},
+	}
+	if got, want := Columns(fields, nil), []string{"id", "age", "name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+	if got, want := Columns(nil, map[string]interface{}{"b": 1, "a": 2}), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+}
+
+func TestFromAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		wantOk bool
+	}{
+		{accept: "text/csv", want: CSV, wantOk: true},
+		{accept: "application/x-ndjson; charset=utf-8", want: NDJSON, wantOk: true},
+		{accept: "image/png, text/csv;q=0.9", want: CSV, wantOk: true},
+		{accept: "application/json, text/csv;q=0.9", wantOk: false},
+		{accept: "*/*", wantOk: false},
+		{accept: "application/json", wantOk: false},
+		{accept: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		got, ok := FromAccept(tt.accept)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("FromAccept(%s) = %v, %v, want %v, %v", tt.accept, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
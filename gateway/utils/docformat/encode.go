@@ -0,0 +1,118 @@
+package docformat
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Encoder writes documents one at a time. Flush writes the documents buffered by the encoder.
+type Encoder interface {
+	Encode(doc map[string]interface{}) error
+	Flush() error
+}
+
+// NewEncoder returns an encoder of the format. The columns of a csv file are taken from the first document if
+// they aren't provided. Fields of the documents which aren't columns are left out of csv files.
+func NewEncoder(format string, w io.Writer, columns []string) (Encoder, error) {
+	switch format {
+	case NDJSON:
+		return &ndjsonEncoder{encoder: json.NewEncoder(w)}, nil
+	case CSV:
+		return &csvEncoder{writer: csv.NewWriter(w), columns: columns}, nil
+	default:
+		return nil, Validate(format)
+	}
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(doc map[string]interface{}) error {
+	// The json encoder terminates every document with a new line
+	return e.encoder.Encode(doc)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+type csvEncoder struct {
+	writer      *csv.Writer
+	columns     []string
+	wroteHeader bool
+	record      []string
+}
+
+func (e *csvEncoder) writeHeader() error {
+	e.wroteHeader = true
+	e.record = make([]string, len(e.columns))
+	return e.writer.Write(e.columns)
+}
+
+func (e *csvEncoder) Encode(doc map[string]interface{}) error {
+	if !e.wroteHeader {
+		if len(e.columns) == 0 {
+			e.columns = Columns(nil, doc)
+		}
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	for i, column := range e.columns {
+		cell, err := formatCell(doc[column])
+		if err != nil {
+			return fmt.Errorf("unable to encode field (%s) - %v", column, err)
+		}
+		e.record[i] = cell
+	}
+	return e.writer.Write(e.record)
+}
+
+func (e *csvEncoder) Flush() error {
+	// Files without any documents still carry the header if the columns are known
+	if !e.wroteHeader && len(e.columns) > 0 {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func formatCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case []byte:
+		return string(v), nil
+	default:
+		// Objects and arrays are stored as json
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// Error is an error carrying a machine readable code. It is sent to the clients in the error envelope.
+type Error struct {
+	Code      string
+	Module    string
+	Retryable bool
+	Details   map[string]interface{}
+
+	err error
+}
+
+// NewError creates an error with the provided code. Its retryability is derived from the code.
+func NewError(code string, err error) *Error {
+	return &Error{Code: code, Retryable: isRetryableCode(code), err: err}
+}
+
+// WithDetails attaches details to the error
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	e.Details = details
+	return e
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	if e.err == nil {
+		return e.Code
+	}
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+type apiModuleKey struct{}
+
+// WithAPIModule stores the module serving the request in the context
+func WithAPIModule(ctx context.Context, module string) context.Context {
+	return context.WithValue(ctx, apiModuleKey{}, module)
+}
+
+// GetAPIModule returns the module serving the request
+func GetAPIModule(ctx context.Context) string {
+	module, _ := ctx.Value(apiModuleKey{}).(string)
+	return module
+}
+
+// SendErrorResponse sends the error in the error envelope
+func SendErrorResponse(ctx context.Context, w http.ResponseWriter, statusCode int, err error) error {
+	res := NewErrorResponse(statusCode, GetAPIModule(ctx), err)
+	res.RequestID = RequestIDFromContext(ctx)
+	return helpers.Response.SendResponse(ctx, w, statusCode, res)
+}
+
+// NewErrorResponse creates the error envelope for the error. The code of errors which don't carry one is derived
+// from the status code.
+func NewErrorResponse(statusCode int, module string, err error) *model.ErrorResponse {
+	if err == nil {
+		err = errors.New("")
+	}
+
+	res := &model.ErrorResponse{Error: err.Error(), Module: module}
+	if rawErr, ok := err.(helpers.Error); ok {
+		res.RawError = rawErr.RawError()
+	}
+
+	var apiErr *Error
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &apiErr):
+		res.Code, res.Retryable, res.Details = apiErr.Code, apiErr.Retryable, apiErr.Details
+		if apiErr.Module != "" {
+			res.Module = apiErr.Module
+		}
+	case errors.As(err, &validationErr):
+		res.Code, res.Details = model.ErrorCodeValidationFailed, map[string]interface{}{"validation": validationErr}
+	case errors.Is(err, ErrVersionConflict):
+		res.Code = model.ErrorCodeVersionConflict
+	case errors.Is(err, context.DeadlineExceeded):
+		res.Code, res.Retryable = model.ErrorCodeTimeout, true
+	default:
+		res.Code = GetErrorCode(statusCode)
+		res.Retryable = isRetryableCode(res.Code)
+	}
+	return res
+}
+
+// ParseErrorResponse creates an error from the body of an error response of space cloud or the runner. Bodies
+// which aren't an error envelope are used as the message with the code derived from the status code.
+func ParseErrorResponse(statusCode int, body []byte) *Error {
+	res := new(model.ErrorResponse)
+	if err := json.Unmarshal(body, res); err != nil || res.Error == "" {
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(statusCode)
+		}
+		res = &model.ErrorResponse{Error: msg}
+	}
+
+	if res.Code == "" {
+		res.Code = GetErrorCode(statusCode)
+		res.Retryable = isRetryableCode(res.Code)
+	}
+	return &Error{Code: res.Code, Module: res.Module, Retryable: res.Retryable, Details: res.Details, err: errors.New(res.Error)}
+}
+
+// GetErrorCode returns the error code for the status code of a response
+func GetErrorCode(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return model.ErrorCodeInvalidRequest
+	case http.StatusUnauthorized:
+		return model.ErrorCodeUnauthenticated
+	case http.StatusForbidden:
+		return model.ErrorCodePermissionDenied
+	case http.StatusNotFound:
+		return model.ErrorCodeNotFound
+	case http.StatusConflict:
+		return model.ErrorCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return model.ErrorCodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return model.ErrorCodeUnprocessable
+	case http.StatusTooManyRequests:
+		return model.ErrorCodeRateLimited
+	case http.StatusBadGateway:
+		return model.ErrorCodeUpstream
+	case http.StatusServiceUnavailable:
+		return model.ErrorCodeUnavailable
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return model.ErrorCodeTimeout
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		return model.ErrorCodeInternal
+	}
+	return model.ErrorCodeInvalidRequest
+}
+
+func isRetryableCode(code string) bool {
+	switch code {
+	case model.ErrorCodeRateLimited, model.ErrorCodeUpstream, model.ErrorCodeUnavailable, model.ErrorCodeTimeout, model.ErrorCodeRequestInProgress:
+		return true
+	}
+	return false
+}
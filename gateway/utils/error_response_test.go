@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	validationErr := &ValidationError{Col: "users", Field: "age", Rule: "min", Msg: "value must be at least 18"}
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		want       *model.ErrorResponse
+	}{
+		{
+			name:       "code is derived from the status code",
+			statusCode: http.StatusForbidden,
+			err:        errors.New("access denied"),
+			want:       &model.ErrorResponse{Error: "access denied", Code: model.ErrorCodePermissionDenied, Module: "crud"},
+		},
+		{
+			name:       "unavailable errors are retryable",
+			statusCode: http.StatusServiceUnavailable,
+			err:        errors.New("node is draining"),
+			want:       &model.ErrorResponse{Error: "node is draining", Code: model.ErrorCodeUnavailable, Module: "crud", Retryable: true},
+		},
+		{
+			name:       "code of the error takes precedence over the status code",
+			statusCode: http.StatusConflict,
+			err:        NewError(model.ErrorCodeRequestInProgress, errors.New("request is being processed")),
+			want:       &model.ErrorResponse{Error: "request is being processed", Code: model.ErrorCodeRequestInProgress, Module: "crud", Retryable: true},
+		},
+		{
+			name:       "wrapped version conflict",
+			statusCode: http.StatusConflict,
+			err:        fmt.Errorf("unable to update: %w", ErrVersionConflict),
+			want:       &model.ErrorResponse{Error: "unable to update: " + ErrVersionConflict.Error(), Code: model.ErrorCodeVersionConflict, Module: "crud"},
+		},
+		{
+			name:       "validation error carries the violated rule",
+			statusCode: http.StatusBadRequest,
+			err:        validationErr,
+			want:       &model.ErrorResponse{Error: validationErr.Error(), Code: model.ErrorCodeValidationFailed, Module: "crud", Details: map[string]interface{}{"validation": validationErr}},
+		},
+		{
+			name:       "deadline exceeded",
+			statusCode: http.StatusInternalServerError,
+			err:        context.DeadlineExceeded,
+			want:       &model.ErrorResponse{Error: context.DeadlineExceeded.Error(), Code: model.ErrorCodeTimeout, Module: "crud", Retryable: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewErrorResponse(tt.statusCode, "crud", tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewErrorResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrorResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       *Error
+		wantMsg    string
+	}{
+		{
+			name:       "error envelope",
+			statusCode: http.StatusConflict,
+			body:       `{"error":"document was modified","code":"version_conflict","module":"crud","retryable":false}`,
+			want:       &Error{Code: model.ErrorCodeVersionConflict, Module: "crud"},
+			wantMsg:    "document was modified",
+		},
+		{
+			name:       "legacy error body",
+			statusCode: http.StatusBadGateway,
+			body:       `{"error":"service is down"}`,
+			want:       &Error{Code: model.ErrorCodeUpstream, Retryable: true},
+			wantMsg:    "service is down",
+		},
+		{
+			name:       "body which isn't json",
+			statusCode: http.StatusNotFound,
+			body:       "404 page not found\n",
+			want:       &Error{Code: model.ErrorCodeNotFound},
+			wantMsg:    "404 page not found",
+		},
+		{
+			name:       "empty body",
+			statusCode: http.StatusTooManyRequests,
+			want:       &Error{Code: model.ErrorCodeRateLimited, Retryable: true},
+			wantMsg:    http.StatusText(http.StatusTooManyRequests),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseErrorResponse(tt.statusCode, []byte(tt.body))
+			if got.Error() != tt.wantMsg {
+				t.Errorf("ParseErrorResponse() message = %v, want %v", got.Error(), tt.wantMsg)
+			}
+			got.err = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseErrorResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
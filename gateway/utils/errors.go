@@ -1,6 +1,9 @@
 package utils
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrInvalidParams is thrown when the input parameters for an operation are invalid
 var ErrInvalidParams = errors.New("Invalid parameter provided")
@@ -13,3 +16,19 @@ var ErrUnsupportedDatabase = errors.New("Unsupported database. Make sure your da
 
 // ErrDatabaseConnection is thrown when SC was unable to connect to the requested database
 var ErrDatabaseConnection = errors.New("Could not connect to database. Make sure it is up and connection string provided to SC is correct")
+
+// ErrVersionConflict is thrown when a document was modified after the version provided in an update was read
+var ErrVersionConflict = errors.New("Document has been modified since it was read. Read it again and retry the update")
+
+// ValidationError is thrown when the value of a field violates a validation directive of its schema
+type ValidationError struct {
+	Col   string `json:"col"`
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Msg   string `json:"message"`
+}
+
+// Error returns the message of the validation error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid value for field (%s) in collection (%s) - %s", e.Field, e.Col, e.Msg)
+}
@@ -31,4 +31,18 @@ const (
 		trigger_type: ID @size(value: 10)
 		invocations: [invocation_logs]! @link(table: "invocation_logs", from: "_id", to: "event_id")
 	  }`
+	// TableWorkflowRuns is a variable for "workflow_runs"
+	TableWorkflowRuns string = "workflow_runs"
+	// SchemaWorkflowRuns is a variable for workflow run schema
+	SchemaWorkflowRuns string = `type workflow_runs {
+		_id: ID! @primary
+		workflow: String
+		token: Integer
+		status: String
+		step: Integer
+		state: String
+		error: String
+		started_at: DateTime
+		updated_at: DateTime
+	  }`
 )
@@ -0,0 +1,203 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The flags PostGIS sets on the geometry type of the extended well known binary format
+const (
+	ewkbFlagZ    = 0x80000000
+	ewkbFlagM    = 0x40000000
+	ewkbFlagSRID = 0x20000000
+)
+
+var wkbTypes = map[uint32]string{
+	1: TypePoint,
+	2: TypeLineString,
+	3: TypePolygon,
+	4: TypeMultiPoint,
+	5: TypeMultiLineString,
+	6: TypeMultiPolygon,
+}
+
+// DecodeHexEWKB converts a hex encoded geometry in the extended well known binary format, which is how PostGIS
+// returns geometry and geography columns, to GeoJSON. The z and m coordinates are dropped. It returns false if the
+// value isn't a valid geometry.
+func DecodeHexEWKB(value string) (map[string]interface{}, bool) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, false
+	}
+
+	r := &wkbReader{data: data}
+	g, err := r.readGeometry()
+	if err != nil || len(r.data) != 0 {
+		return nil, false
+	}
+	return g.geoJSON(), true
+}
+
+type wkbReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) readGeometry() (*geometry, error) {
+	if len(r.data) < 5 {
+		return nil, errors.New("unexpected end of geometry")
+	}
+	switch r.data[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, errors.New("invalid byte order")
+	}
+	r.data = r.data[1:]
+
+	wkbType, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	dimensions := 2
+	if wkbType&ewkbFlagZ != 0 {
+		dimensions++
+	}
+	if wkbType&ewkbFlagM != 0 {
+		dimensions++
+	}
+	if wkbType&ewkbFlagSRID != 0 {
+		if _, err := r.readUint32(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Geometries with z and m coordinates might also be encoded with the ISO type codes
+	base := wkbType & 0x0fffffff
+	switch base / 1000 {
+	case 1, 2:
+		dimensions++
+	case 3:
+		dimensions += 2
+	}
+	typ, ok := wkbTypes[base%1000]
+	if !ok {
+		return nil, fmt.Errorf("unsupported geometry type (%d)", base)
+	}
+
+	g := &geometry{typ: typ}
+	switch typ {
+	case TypePoint:
+		p, err := r.readPosition(dimensions)
+		if err != nil {
+			return nil, err
+		}
+		g.points = []position{p}
+	case TypeLineString:
+		g.points, err = r.readPositions(dimensions)
+	case TypePolygon:
+		g.lines, err = r.readRings(dimensions)
+	case TypeMultiPoint, TypeMultiLineString, TypeMultiPolygon:
+		err = r.readCollection(g)
+	}
+	return g, err
+}
+
+// readCollection reads the geometries of a multi geometry, each of which carries its own header
+func (r *wkbReader) readCollection(g *geometry) error {
+	n, err := r.readCount()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		order := r.order
+		part, err := r.readGeometry()
+		if err != nil {
+			return err
+		}
+		r.order = order
+
+		switch {
+		case g.typ == TypeMultiPoint && part.typ == TypePoint:
+			g.points = append(g.points, part.points...)
+		case g.typ == TypeMultiLineString && part.typ == TypeLineString:
+			g.lines = append(g.lines, part.points)
+		case g.typ == TypeMultiPolygon && part.typ == TypePolygon:
+			g.polygons = append(g.polygons, part.lines)
+		default:
+			return fmt.Errorf("geometry of type (%s) cannot be a part of (%s)", part.typ, g.typ)
+		}
+	}
+	return nil
+}
+
+func (r *wkbReader) readRings(dimensions int) ([][]position, error) {
+	n, err := r.readCount()
+	if err != nil {
+		return nil, err
+	}
+	rings := make([][]position, n)
+	for i := range rings {
+		if rings[i], err = r.readPositions(dimensions); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
+
+func (r *wkbReader) readPositions(dimensions int) ([]position, error) {
+	n, err := r.readCount()
+	if err != nil {
+		return nil, err
+	}
+	if int(n)*dimensions*8 > len(r.data) {
+		return nil, errors.New("unexpected end of geometry")
+	}
+	positions := make([]position, n)
+	for i := range positions {
+		if positions[i], err = r.readPosition(dimensions); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
+
+func (r *wkbReader) readPosition(dimensions int) (position, error) {
+	if len(r.data) < dimensions*8 {
+		return position{}, errors.New("unexpected end of geometry")
+	}
+	lng := math.Float64frombits(r.order.Uint64(r.data))
+	lat := math.Float64frombits(r.order.Uint64(r.data[8:]))
+	r.data = r.data[dimensions*8:]
+	if math.IsNaN(lng) || math.IsNaN(lat) {
+		return position{}, errors.New("empty geometries are not supported")
+	}
+	return position{lng, lat}, nil
+}
+
+// readCount reads the number of elements which follow. The count is checked against the remaining data since every
+// element takes up at least 4 bytes.
+func (r *wkbReader) readCount() (uint32, error) {
+	n, err := r.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	if int(n)*4 > len(r.data) {
+		return 0, errors.New("unexpected end of geometry")
+	}
+	return n, nil
+}
+
+func (r *wkbReader) readUint32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, errors.New("unexpected end of geometry")
+	}
+	n := r.order.Uint32(r.data)
+	r.data = r.data[4:]
+	return n, nil
+}
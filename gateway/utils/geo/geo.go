@@ -0,0 +1,245 @@
+// Package geo implements the geospatial operators of the where clause along with the conversions between the formats
+// geometries are accepted, stored and returned in. Coordinates are always in the [lng, lat] order of GeoJSON and
+// distances are in meters.
+package geo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The geospatial operators of the where clause
+const (
+	// OpNear matches geometries within a distance range of a point
+	OpNear = "$near"
+	// OpGeoWithin matches geometries which lie within a polygon
+	OpGeoWithin = "$geoWithin"
+	// OpGeoBox matches geometries which lie within a bounding box
+	OpGeoBox = "$geoBox"
+)
+
+// SRID is the spatial reference system of the stored geometries (WGS 84)
+const SRID = 4326
+
+// The geometry types which are supported
+const (
+	TypePoint           = "Point"
+	TypeLineString      = "LineString"
+	TypePolygon         = "Polygon"
+	TypeMultiPoint      = "MultiPoint"
+	TypeMultiLineString = "MultiLineString"
+	TypeMultiPolygon    = "MultiPolygon"
+)
+
+// IsOperator returns true if op is a geospatial operator
+func IsOperator(op string) bool {
+	switch op {
+	case OpNear, OpGeoWithin, OpGeoBox:
+		return true
+	}
+	return false
+}
+
+// ValidateOperator checks the value of a geospatial operator. Other operators are ignored.
+func ValidateOperator(op string, value interface{}) error {
+	var err error
+	switch op {
+	case OpNear:
+		_, err = ParseNear(value)
+	case OpGeoWithin:
+		_, err = parseWithin(value)
+	case OpGeoBox:
+		_, err = ParseBox(value)
+	}
+	return err
+}
+
+// Near is the parsed value of the $near operator
+type Near struct {
+	Lng, Lat    float64
+	MaxDistance float64
+	MinDistance float64
+}
+
+// ParseNear parses the value of the $near operator which is of the form {"lat", "lng", "maxDistance", "minDistance"}
+func ParseNear(value interface{}) (*Near, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("value of $near should be an object")
+	}
+
+	lng, lat, err := parseLatLng(obj)
+	if err != nil {
+		return nil, err
+	}
+	near := &Near{Lng: lng, Lat: lat}
+	if near.MaxDistance, err = parseDistance(obj, "maxDistance"); err != nil {
+		return nil, err
+	}
+	if near.MinDistance, err = parseDistance(obj, "minDistance"); err != nil {
+		return nil, err
+	}
+	if near.MaxDistance == 0 && near.MinDistance == 0 {
+		return nil, errors.New("either maxDistance or minDistance is required by $near")
+	}
+	if near.MaxDistance > 0 && near.MinDistance > near.MaxDistance {
+		return nil, errors.New("minDistance of $near cannot be greater than its maxDistance")
+	}
+	return near, nil
+}
+
+// Box is the parsed value of the $geoBox operator
+type Box struct {
+	MinLng, MinLat float64
+	MaxLng, MaxLat float64
+}
+
+// ParseBox parses the value of the $geoBox operator which is of the form {"minLng", "minLat", "maxLng", "maxLat"}
+func ParseBox(value interface{}) (*Box, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("value of $geoBox should be an object")
+	}
+
+	box := new(Box)
+	for key, ptr := range map[string]*float64{"minLng": &box.MinLng, "minLat": &box.MinLat, "maxLng": &box.MaxLng, "maxLat": &box.MaxLat} {
+		v, ok := toFloat(obj[key])
+		if !ok {
+			return nil, fmt.Errorf("field (%s) of $geoBox should be a number", key)
+		}
+		*ptr = v
+	}
+	if err := checkPosition(position{box.MinLng, box.MinLat}); err != nil {
+		return nil, err
+	}
+	if err := checkPosition(position{box.MaxLng, box.MaxLat}); err != nil {
+		return nil, err
+	}
+	if box.MinLng > box.MaxLng || box.MinLat > box.MaxLat {
+		return nil, errors.New("min coordinates of $geoBox cannot be greater than its max coordinates")
+	}
+	return box, nil
+}
+
+// Polygon returns the bounding box as a GeoJSON polygon
+func (b *Box) Polygon() map[string]interface{} {
+	return b.geometry().geoJSON()
+}
+
+func (b *Box) geometry() *geometry {
+	return &geometry{typ: TypePolygon, lines: [][]position{{
+		{b.MinLng, b.MinLat}, {b.MaxLng, b.MinLat}, {b.MaxLng, b.MaxLat}, {b.MinLng, b.MaxLat}, {b.MinLng, b.MinLat},
+	}}}
+}
+
+// ParseWithin parses the value of the $geoWithin operator and returns it as a GeoJSON polygon or multi polygon. The
+// value is either of the form {"polygon": [[lng, lat], ...]} or a GeoJSON polygon or multi polygon.
+func ParseWithin(value interface{}) (map[string]interface{}, error) {
+	g, err := parseWithin(value)
+	if err != nil {
+		return nil, err
+	}
+	return g.geoJSON(), nil
+}
+
+func parseWithin(value interface{}) (*geometry, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("value of $geoWithin should be an object")
+	}
+
+	if ring, ok := obj["polygon"]; ok {
+		positions, err := parseRing(ring)
+		if err != nil {
+			return nil, err
+		}
+		return &geometry{typ: TypePolygon, lines: [][]position{positions}}, nil
+	}
+
+	g, err := parseGeoJSON(obj)
+	if err != nil {
+		return nil, err
+	}
+	if g.typ != TypePolygon && g.typ != TypeMultiPolygon {
+		return nil, fmt.Errorf("value of $geoWithin should be a polygon - got (%s)", g.typ)
+	}
+	return g, nil
+}
+
+// ParseGeometry validates a geometry and returns it as GeoJSON. Apart from GeoJSON, a point can also be provided as
+// an object of the form {"lat", "lng"}.
+func ParseGeometry(value interface{}) (map[string]interface{}, error) {
+	g, err := parseGeometry(value)
+	if err != nil {
+		return nil, err
+	}
+	return g.geoJSON(), nil
+}
+
+// ToEWKT converts a geometry to the extended well known text format which PostGIS accepts as the input of geometry
+// and geography columns
+func ToEWKT(value interface{}) (string, error) {
+	g, err := parseGeometry(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("SRID=%d;%s", SRID, g.wkt()), nil
+}
+
+func parseGeometry(value interface{}) (*geometry, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("geometry should be an object")
+	}
+	if _, ok := obj["type"]; !ok {
+		lng, lat, err := parseLatLng(obj)
+		if err != nil {
+			return nil, err
+		}
+		return &geometry{typ: TypePoint, points: []position{{lng, lat}}}, nil
+	}
+	return parseGeoJSON(obj)
+}
+
+func parseLatLng(obj map[string]interface{}) (float64, float64, error) {
+	lat, ok := toFloat(obj["lat"])
+	if !ok {
+		return 0, 0, errors.New("field (lat) should be a number")
+	}
+	lng, ok := toFloat(obj["lng"])
+	if !ok {
+		return 0, 0, errors.New("field (lng) should be a number")
+	}
+	if err := checkPosition(position{lng, lat}); err != nil {
+		return 0, 0, err
+	}
+	return lng, lat, nil
+}
+
+func parseDistance(obj map[string]interface{}, key string) (float64, error) {
+	v, ok := obj[key]
+	if !ok {
+		return 0, nil
+	}
+	distance, ok := toFloat(v)
+	if !ok || distance < 0 {
+		return 0, fmt.Errorf("field (%s) should be a positive number", key)
+	}
+	return distance, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,233 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestToEWKT(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "point with lat and lng",
+			value: map[string]interface{}{"lat": 19.07, "lng": 72.87},
+			want:  "SRID=4326;POINT(72.87 19.07)",
+		},
+		{
+			name:  "line string",
+			value: map[string]interface{}{"type": "LineString", "coordinates": []interface{}{[]interface{}{0, 0}, []interface{}{1.5, 2}}},
+			want:  "SRID=4326;LINESTRING(0 0,1.5 2)",
+		},
+		{
+			name:  "polygon which isn't closed",
+			value: map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{[]interface{}{[]interface{}{0, 0}, []interface{}{1, 0}, []interface{}{1, 1}}}},
+			want:  "SRID=4326;POLYGON((0 0,1 0,1 1,0 0))",
+		},
+		{
+			name:  "multi point",
+			value: map[string]interface{}{"type": "MultiPoint", "coordinates": []interface{}{[]interface{}{0, 0}, []interface{}{1, 1}}},
+			want:  "SRID=4326;MULTIPOINT((0 0),(1 1))",
+		},
+		{
+			name: "multi polygon",
+			value: map[string]interface{}{"type": "MultiPolygon", "coordinates": []interface{}{
+				[]interface{}{[]interface{}{[]interface{}{0, 0}, []interface{}{1, 0}, []interface{}{1, 1}, []interface{}{0, 0}}},
+				[]interface{}{[]interface{}{[]interface{}{2, 2}, []interface{}{3, 2}, []interface{}{3, 3}, []interface{}{2, 2}}},
+			}},
+			want: "SRID=4326;MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((2 2,3 2,3 3,2 2)))",
+		},
+		{
+			name:    "latitude out of range",
+			value:   map[string]interface{}{"lat": 91, "lng": 0},
+			wantErr: true,
+		},
+		{
+			name:    "unknown geometry type",
+			value:   map[string]interface{}{"type": "Circle", "coordinates": []interface{}{0, 0}},
+			wantErr: true,
+		},
+		{
+			name:    "polygon with too few positions",
+			value:   map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{[]interface{}{[]interface{}{0, 0}, []interface{}{1, 0}, []interface{}{0, 0}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToEWKT(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToEWKT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToEWKT() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeHexEWKB(t *testing.T) {
+	square := [][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 0}}
+
+	tests := []struct {
+		name   string
+		value  string
+		want   map[string]interface{}
+		wantOk bool
+	}{
+		{
+			name:   "point with srid",
+			value:  "0101000020E6100000000000000000F03F0000000000000040",
+			want:   map[string]interface{}{"type": "Point", "coordinates": []interface{}{float64(1), float64(2)}},
+			wantOk: true,
+		},
+		{
+			name:   "big endian point with a z coordinate",
+			value:  "00800000013FF000000000000040000000000000004008000000000000",
+			want:   map[string]interface{}{"type": "Point", "coordinates": []interface{}{float64(1), float64(2)}},
+			wantOk: true,
+		},
+		{
+			name:   "polygon",
+			value:  encodePolygon(square),
+			want:   map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{positionsJSON([]position{{0, 0}, {1, 0}, {1, 1}, {0, 0}})}},
+			wantOk: true,
+		},
+		{
+			name:  "multi polygon",
+			value: "0106000000" + "02000000" + encodePolygon(square) + encodePolygon(square),
+			want: map[string]interface{}{"type": "MultiPolygon", "coordinates": []interface{}{
+				[]interface{}{positionsJSON([]position{{0, 0}, {1, 0}, {1, 1}, {0, 0}})},
+				[]interface{}{positionsJSON([]position{{0, 0}, {1, 0}, {1, 1}, {0, 0}})},
+			}},
+			wantOk: true,
+		},
+		{
+			name:  "multi polygon with a point as its part",
+			value: "0106000000" + "01000000" + "0101000000000000000000F03F0000000000000040",
+		},
+		{
+			name:  "trailing bytes",
+			value: "0101000020E6100000000000000000F03F000000000000004000",
+		},
+		{
+			name:  "truncated polygon",
+			value: encodePolygon(square)[:40],
+		},
+		{
+			name:  "text which looks like hex",
+			value: "cafe",
+		},
+		{
+			name:  "not hex",
+			value: "hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DecodeHexEWKB(tt.value)
+			if ok != tt.wantOk {
+				t.Fatalf("DecodeHexEWKB() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeHexEWKB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	// A square with a square hole in the middle
+	polygon := map[string]interface{}{"type": "Polygon", "coordinates": []interface{}{
+		[]interface{}{[]interface{}{0, 0}, []interface{}{10, 0}, []interface{}{10, 10}, []interface{}{0, 10}, []interface{}{0, 0}},
+		[]interface{}{[]interface{}{4, 4}, []interface{}{6, 4}, []interface{}{6, 6}, []interface{}{4, 6}, []interface{}{4, 4}},
+	}}
+
+	tests := []struct {
+		name  string
+		op    string
+		cond  interface{}
+		value interface{}
+		want  bool
+	}{
+		{
+			name:  "point within polygon",
+			op:    OpGeoWithin,
+			cond:  polygon,
+			value: map[string]interface{}{"lat": 2, "lng": 2},
+			want:  true,
+		},
+		{
+			name:  "point in the hole of a polygon",
+			op:    OpGeoWithin,
+			cond:  polygon,
+			value: map[string]interface{}{"lat": 5, "lng": 5},
+		},
+		{
+			name:  "point on the boundary of a polygon",
+			op:    OpGeoWithin,
+			cond:  polygon,
+			value: map[string]interface{}{"lat": 0, "lng": 5},
+			want:  true,
+		},
+		{
+			name:  "line partly outside of a box",
+			op:    OpGeoBox,
+			cond:  map[string]interface{}{"minLng": 0, "minLat": 0, "maxLng": 1, "maxLat": 1},
+			value: map[string]interface{}{"type": "LineString", "coordinates": []interface{}{[]interface{}{0.5, 0.5}, []interface{}{2, 2}}},
+		},
+		{
+			name:  "point closer than the min distance",
+			op:    OpNear,
+			cond:  map[string]interface{}{"lat": 0, "lng": 0, "minDistance": 200000},
+			value: map[string]interface{}{"lat": 1, "lng": 0},
+		},
+		{
+			name:  "point within the distance range",
+			op:    OpNear,
+			cond:  map[string]interface{}{"lat": 0, "lng": 0, "minDistance": 100000, "maxDistance": 200000},
+			value: map[string]interface{}{"lat": 1, "lng": 0},
+			want:  true,
+		},
+		{
+			name:  "value which isn't a geometry",
+			op:    OpNear,
+			cond:  map[string]interface{}{"lat": 0, "lng": 0, "maxDistance": 100},
+			value: "0,0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(tt.op, tt.cond, tt.value); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// One degree of latitude is roughly 111.2 km
+	if got := Distance([2]float64{0, 0}, [2]float64{0, 1}); math.Abs(got-111195) > 10 {
+		t.Errorf("Distance() = %v, want ~111195", got)
+	}
+}
+
+// encodePolygon returns a polygon with a single ring in the little endian well known binary format
+func encodePolygon(ring [][2]float64) string {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(1)
+	_ = binary.Write(buf, binary.LittleEndian, uint32(3))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(1))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(ring)))
+	for _, p := range ring {
+		_ = binary.Write(buf, binary.LittleEndian, p)
+	}
+	return hex.EncodeToString(buf.Bytes())
+}
@@ -0,0 +1,242 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// position is a pair of coordinates in the [lng, lat] order
+type position [2]float64
+
+// geometry is a parsed geometry. Only the field matching the depth of the coordinates of its type is set.
+type geometry struct {
+	typ string
+	// points holds the coordinates of points, multi points and line strings
+	points []position
+	// lines holds the coordinates of multi line strings and the rings of polygons
+	lines [][]position
+	// polygons holds the coordinates of multi polygons
+	polygons [][][]position
+}
+
+func parseGeoJSON(obj map[string]interface{}) (*geometry, error) {
+	typ, _ := obj["type"].(string)
+	coordinates, ok := obj["coordinates"]
+	if !ok {
+		return nil, errors.New("field (coordinates) is required by a geometry")
+	}
+
+	var err error
+	g := &geometry{typ: typ}
+	switch typ {
+	case TypePoint:
+		var p position
+		p, err = parsePosition(coordinates)
+		g.points = []position{p}
+	case TypeMultiPoint:
+		g.points, err = parsePositions(coordinates, 1)
+	case TypeLineString:
+		g.points, err = parsePositions(coordinates, 2)
+	case TypeMultiLineString:
+		err = forEach(coordinates, func(v interface{}) error {
+			line, err := parsePositions(v, 2)
+			g.lines = append(g.lines, line)
+			return err
+		})
+	case TypePolygon:
+		g.lines, err = parseRings(coordinates)
+	case TypeMultiPolygon:
+		err = forEach(coordinates, func(v interface{}) error {
+			rings, err := parseRings(v)
+			g.polygons = append(g.polygons, rings)
+			return err
+		})
+	default:
+		return nil, fmt.Errorf("invalid geometry type (%s) provided", typ)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid coordinates provided for geometry of type (%s) - %v", typ, err)
+	}
+	return g, nil
+}
+
+func forEach(value interface{}, fn func(v interface{}) error) error {
+	arr, ok := value.([]interface{})
+	if !ok || len(arr) == 0 {
+		return errors.New("coordinates should be a non empty array")
+	}
+	for _, v := range arr {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parsePosition(value interface{}) (position, error) {
+	arr, ok := value.([]interface{})
+	if !ok || len(arr) < 2 {
+		return position{}, errors.New("position should be an array of the form [lng, lat]")
+	}
+	lng, ok1 := toFloat(arr[0])
+	lat, ok2 := toFloat(arr[1])
+	if !ok1 || !ok2 {
+		return position{}, errors.New("coordinates of a position should be numbers")
+	}
+	p := position{lng, lat}
+	return p, checkPosition(p)
+}
+
+func parsePositions(value interface{}, min int) ([]position, error) {
+	positions := make([]position, 0)
+	err := forEach(value, func(v interface{}) error {
+		p, err := parsePosition(v)
+		positions = append(positions, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(positions) < min {
+		return nil, fmt.Errorf("at least %d positions are required", min)
+	}
+	return positions, nil
+}
+
+func parseRings(value interface{}) ([][]position, error) {
+	rings := make([][]position, 0)
+	err := forEach(value, func(v interface{}) error {
+		ring, err := parseRing(v)
+		rings = append(rings, ring)
+		return err
+	})
+	return rings, err
+}
+
+// parseRing parses a linear ring of a polygon. Rings which aren't closed get closed.
+func parseRing(value interface{}) ([]position, error) {
+	ring, err := parsePositions(value, 3)
+	if err != nil {
+		return nil, err
+	}
+	if ring[0] != ring[len(ring)-1] {
+		ring = append(ring, ring[0])
+	}
+	if len(ring) < 4 {
+		return nil, errors.New("a polygon ring needs at least 3 distinct positions")
+	}
+	return ring, nil
+}
+
+func checkPosition(p position) error {
+	if p[0] < -180 || p[0] > 180 {
+		return fmt.Errorf("longitude (%v) should be between -180 and 180", p[0])
+	}
+	if p[1] < -90 || p[1] > 90 {
+		return fmt.Errorf("latitude (%v) should be between -90 and 90", p[1])
+	}
+	return nil
+}
+
+// geoJSON returns the geometry as a GeoJSON object
+func (g *geometry) geoJSON() map[string]interface{} {
+	var coordinates interface{}
+	switch g.typ {
+	case TypePoint:
+		coordinates = positionJSON(g.points[0])
+	case TypeMultiPoint, TypeLineString:
+		coordinates = positionsJSON(g.points)
+	case TypeMultiLineString, TypePolygon:
+		coordinates = linesJSON(g.lines)
+	case TypeMultiPolygon:
+		arr := make([]interface{}, len(g.polygons))
+		for i, rings := range g.polygons {
+			arr[i] = linesJSON(rings)
+		}
+		coordinates = arr
+	}
+	return map[string]interface{}{"type": g.typ, "coordinates": coordinates}
+}
+
+func positionJSON(p position) interface{} {
+	return []interface{}{p[0], p[1]}
+}
+
+func positionsJSON(positions []position) interface{} {
+	arr := make([]interface{}, len(positions))
+	for i, p := range positions {
+		arr[i] = positionJSON(p)
+	}
+	return arr
+}
+
+func linesJSON(lines [][]position) interface{} {
+	arr := make([]interface{}, len(lines))
+	for i, line := range lines {
+		arr[i] = positionsJSON(line)
+	}
+	return arr
+}
+
+// wkt returns the geometry in the well known text format
+func (g *geometry) wkt() string {
+	switch g.typ {
+	case TypePoint:
+		return "POINT(" + positionWKT(g.points[0]) + ")"
+	case TypeMultiPoint:
+		parts := make([]string, len(g.points))
+		for i, p := range g.points {
+			parts[i] = "(" + positionWKT(p) + ")"
+		}
+		return "MULTIPOINT(" + strings.Join(parts, ",") + ")"
+	case TypeLineString:
+		return "LINESTRING" + positionsWKT(g.points)
+	case TypeMultiLineString:
+		return "MULTILINESTRING" + linesWKT(g.lines)
+	case TypePolygon:
+		return "POLYGON" + linesWKT(g.lines)
+	case TypeMultiPolygon:
+		parts := make([]string, len(g.polygons))
+		for i, rings := range g.polygons {
+			parts[i] = linesWKT(rings)
+		}
+		return "MULTIPOLYGON(" + strings.Join(parts, ",") + ")"
+	}
+	return ""
+}
+
+func positionWKT(p position) string {
+	return strconv.FormatFloat(p[0], 'f', -1, 64) + " " + strconv.FormatFloat(p[1], 'f', -1, 64)
+}
+
+func positionsWKT(positions []position) string {
+	parts := make([]string, len(positions))
+	for i, p := range positions {
+		parts[i] = positionWKT(p)
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+func linesWKT(lines [][]position) string {
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = positionsWKT(line)
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+// vertices returns all the positions of the geometry
+func (g *geometry) vertices() []position {
+	vertices := append([]position{}, g.points...)
+	for _, line := range g.lines {
+		vertices = append(vertices, line...)
+	}
+	for _, rings := range g.polygons {
+		for _, ring := range rings {
+			vertices = append(vertices, ring...)
+		}
+	}
+	return vertices
+}
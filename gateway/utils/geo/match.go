@@ -0,0 +1,117 @@
+package geo
+
+import "math"
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+// Matches evaluates a geospatial operator against a geometry in memory. Geometries other than points are matched
+// using their vertices, which means a line or polygon is near a point if any of its vertices is and lies within a
+// polygon if all of them do.
+func Matches(op string, cond, value interface{}) bool {
+	g, err := parseGeometry(value)
+	if err != nil {
+		return false
+	}
+	vertices := g.vertices()
+
+	switch op {
+	case OpNear:
+		near, err := ParseNear(cond)
+		if err != nil {
+			return false
+		}
+		minDistance := math.Inf(1)
+		for _, p := range vertices {
+			minDistance = math.Min(minDistance, Distance(position{near.Lng, near.Lat}, p))
+		}
+		if near.MaxDistance > 0 && minDistance > near.MaxDistance {
+			return false
+		}
+		return minDistance >= near.MinDistance
+
+	case OpGeoWithin, OpGeoBox:
+		var area *geometry
+		if op == OpGeoBox {
+			box, err := ParseBox(cond)
+			if err != nil {
+				return false
+			}
+			area = box.geometry()
+		} else if area, err = parseWithin(cond); err != nil {
+			return false
+		}
+
+		for _, p := range vertices {
+			if !area.containsPosition(p) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Distance returns the great circle distance between two positions in meters
+func Distance(a, b [2]float64) float64 {
+	lat1, lat2 := toRadians(a[1]), toRadians(b[1])
+	dLat := lat2 - lat1
+	dLng := toRadians(b[0] - a[0])
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// containsPosition returns true if a position lies within a polygon or multi polygon. Positions which lie in one of
+// the holes of a polygon are outside of it.
+func (g *geometry) containsPosition(p position) bool {
+	polygons := g.polygons
+	if g.typ == TypePolygon {
+		polygons = [][][]position{g.lines}
+	}
+
+	for _, rings := range polygons {
+		if len(rings) == 0 || !ringContains(rings[0], p) {
+			continue
+		}
+		inHole := false
+		for _, hole := range rings[1:] {
+			if ringContains(hole, p) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains checks if a position lies within a closed ring using the ray casting algorithm. Positions on the
+// boundary of the ring are considered to be within it.
+func ringContains(ring []position, p position) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if onSegment(a, b, p) {
+			return true
+		}
+		if (a[1] > p[1]) != (b[1] > p[1]) && p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func onSegment(a, b, p position) bool {
+	cross := (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+	if math.Abs(cross) > 1e-12 {
+		return false
+	}
+	return p[0] >= math.Min(a[0], b[0]) && p[0] <= math.Max(a[0], b[0]) && p[1] >= math.Min(a[1], b[1]) && p[1] <= math.Max(a[1], b[1])
+}
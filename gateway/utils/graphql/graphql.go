@@ -12,6 +12,7 @@ import (
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
 
+	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
 	"github.com/spaceuptech/space-cloud/gateway/utils"
 )
@@ -23,6 +24,17 @@ type Module struct {
 	crud      CrudInterface
 	functions FunctionInterface
 	schema    SchemaInterface
+	search    SearchInterface
+
+	limits   *config.GraphQLLimits
+	services config.Services
+
+	// isIntrospectionDisabled rejects the introspection queries
+	isIntrospectionDisabled bool
+
+	persistedLock     sync.RWMutex
+	persisted         *config.PersistedQueries
+	registeredQueries map[string]string // hash to query of the auto registered persisted queries
 
 	// 	Auth module
 	aesKey []byte
@@ -55,6 +67,10 @@ func (graph *Module) GetProjectID() string {
 
 // ExecGraphQLQuery executes the provided graphql query
 func (graph *Module) ExecGraphQLQuery(ctx context.Context, req *model.GraphQLRequest, token string, cb model.GraphQLCallback) {
+	if err := graph.resolvePersistedQuery(req); err != nil {
+		cb(nil, err)
+		return
+	}
 
 	s := source.NewSource(&source.Source{
 		Body: []byte(req.Query),
@@ -67,6 +83,23 @@ func (graph *Module) ExecGraphQLQuery(ctx context.Context, req *model.GraphQLReq
 		return
 	}
 
+	// Reject expensive queries before executing any part of them
+	if err := graph.checkLimits(doc); err != nil {
+		cb(nil, err)
+		return
+	}
+
+	// Introspection queries are answered with the schema generated for the project
+	isIntrospection, err := isIntrospectionQuery(doc)
+	if err != nil {
+		cb(nil, err)
+		return
+	}
+	if isIntrospection {
+		cb(graph.execIntrospectionQuery(ctx, req))
+		return
+	}
+
 	graph.execGraphQLDocument(ctx, doc, token, utils.M{"vars": req.Variables, "path": "", "_query": utils.NewArray(0), "directive": ""}, nil, createCallback(cb))
 }
 
@@ -272,7 +305,7 @@ func (graph *Module) execGraphQLDocument(ctx context.Context, node ast.Node, tok
 						cb(nil, nil)
 						return
 					}
-					req := &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{linkedInfo.To: val}, PostProcess: map[string]*model.PostProcess{}, Options: &model.ReadOptions{}}
+					req := &model.ReadRequest{Operation: utils.All, Find: map[string]interface{}{linkedInfo.To: graph.getLinkFindValue(linkedInfo, val)}, PostProcess: map[string]*model.PostProcess{}, Options: &model.ReadOptions{}}
 					options, hasOptions, _ := generateOptions(ctx, field.Arguments, store)
 					if hasOptions {
 						req.Options.Debug = options.Debug
@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	authHelpers "github.com/spaceuptech/space-cloud/gateway/modules/auth/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// execIncrement atomically increments the counters of the documents matching the where clause. Unlike the other
+// mutations, increments aren't batched since they return the incremented documents.
+func (graph *Module) execIncrement(ctx context.Context, field *ast.Field, dbAlias, token string, store utils.M) (map[string]interface{}, error) {
+	col := strings.TrimPrefix(field.Name.Value, "increment_")
+	req, err := generateIncrementRequest(field, store)
+	if err != nil {
+		return nil, err
+	}
+
+	// An increment is authorised like the update it is made with
+	op := utils.All
+	if req.Upsert {
+		op = utils.Upsert
+	}
+	updateReq := &model.UpdateRequest{Find: req.Find, Operation: op, Update: map[string]interface{}{"$inc": req.Inc}}
+	reqParams, err := graph.auth.IsUpdateOpAuthorised(ctx, graph.project, dbAlias, col, token, updateReq)
+	if err != nil {
+		return nil, err
+	}
+	req.Find = updateReq.Find
+
+	// The incremented documents are only returned if they are queried and the user is allowed to read them
+	var actions *model.PostProcess
+	req.Returning = selectsReturning(field)
+	if req.Returning {
+		actions, _, err = graph.auth.IsReadOpAuthorised(ctx, graph.project, dbAlias, col, token, &model.ReadRequest{Find: req.Find, Operation: utils.All, Options: &model.ReadOptions{}}, model.ReturnWhereStub{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	docs, err := graph.crud.Increment(ctx, dbAlias, col, req, reqParams)
+	if err != nil {
+		return map[string]interface{}{"status": 500, "error": err.Error()}, nil
+	}
+
+	if actions != nil {
+		_ = authHelpers.PostProcessMethod(ctx, graph.aesKey, actions, docs)
+	}
+	return map[string]interface{}{"status": 200, "error": nil, "returning": docs}, nil
+}
+
+func selectsReturning(field *ast.Field) bool {
+	if field.SelectionSet == nil {
+		return false
+	}
+	for _, selection := range field.SelectionSet.Selections {
+		if f, ok := selection.(*ast.Field); ok && f.Name.Value == "returning" {
+			return true
+		}
+	}
+	return false
+}
+
+func generateIncrementRequest(field *ast.Field, store utils.M) (*model.IncrementRequest, error) {
+	req := new(model.IncrementRequest)
+
+	var err error
+	req.Find, err = ExtractWhereClause(field.Arguments, store)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range field.Arguments {
+		switch v.Name.Value {
+		case "inc":
+			temp, err := utils.ParseGraphqlValue(v.Value, store)
+			if err != nil {
+				return nil, err
+			}
+			inc, ok := temp.(map[string]interface{})
+			if !ok {
+				return nil, utils.ErrInvalidParams
+			}
+			req.Inc = inc
+		case "upsert":
+			temp, err := utils.ParseGraphqlValue(v.Value, store)
+			if err != nil {
+				return nil, err
+			}
+			req.Upsert, _ = temp.(bool)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,119 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// SetIntrospection enables or disables the introspection queries of the project
+func (graph *Module) SetIntrospection(disabled bool) {
+	graph.isIntrospectionDisabled = disabled
+}
+
+// IsIntrospectionDisabled returns true if the introspection queries of the project are disabled
+func (graph *Module) IsIntrospectionDisabled() bool {
+	return graph.isIntrospectionDisabled
+}
+
+// isIntrospectionQuery returns true if the operation of the document only selects the introspection fields. An error
+// is returned if the introspection fields are mixed with other fields since they are executed separately.
+func isIntrospectionQuery(doc *ast.Document) (bool, error) {
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok || op.SelectionSet == nil {
+			continue
+		}
+
+		var introspection, others int
+		for _, selection := range op.SelectionSet.Selections {
+			if field, ok := selection.(*ast.Field); ok && strings.HasPrefix(field.Name.Value, "__") {
+				introspection++
+				continue
+			}
+			others++
+		}
+		if introspection > 0 && others > 0 {
+			return false, errors.New("introspection fields cannot be queried along with other fields")
+		}
+		return introspection > 0, nil
+	}
+	return false, nil
+}
+
+// execIntrospectionQuery answers an introspection query with the schema generated for the project
+func (graph *Module) execIntrospectionQuery(ctx context.Context, req *model.GraphQLRequest) (interface{}, error) {
+	if graph.isIntrospectionDisabled {
+		return nil, errors.New("introspection is disabled for this project")
+	}
+
+	schema, err := graph.generateSDLDocument().toSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	result := gql.Do(gql.Params{Schema: schema, RequestString: req.Query, VariableValues: req.Variables, OperationName: req.OperationName, Context: ctx})
+	if len(result.Errors) > 0 {
+		return nil, result.Errors[0]
+	}
+	return result.Data, nil
+}
+
+// toSchema builds the executable schema of the document. Its fields are never resolved since it is only used to
+// answer introspection queries.
+func (doc *sdlDocument) toSchema() (gql.Schema, error) {
+	named := map[string]gql.Type{
+		"ID": gql.ID, "String": gql.String, "Int": gql.Int, "Float": gql.Float, "Boolean": gql.Boolean,
+	}
+	for _, scalar := range doc.scalars {
+		named[scalar] = gql.NewScalar(gql.ScalarConfig{Name: scalar, Serialize: func(value interface{}) interface{} { return value }})
+	}
+
+	objects := map[string]*gql.Object{}
+	for _, t := range doc.types {
+		t := t
+		obj := gql.NewObject(gql.ObjectConfig{Name: t.name, Description: t.description, Fields: gql.FieldsThunk(func() gql.Fields {
+			fields := gql.Fields{}
+			for _, f := range t.fields {
+				args := gql.FieldConfigArgument{}
+				for _, arg := range f.args {
+					args[arg.name] = &gql.ArgumentConfig{Type: resolveTypeRef(arg.typ, named)}
+				}
+				fields[f.name] = &gql.Field{Type: resolveTypeRef(f.typ, named), Description: f.description, Args: args}
+			}
+			return fields
+		})})
+		named[t.name] = obj
+		objects[t.name] = obj
+	}
+
+	directives := append([]*gql.Directive{}, gql.SpecifiedDirectives...)
+	for _, name := range doc.directives {
+		directives = append(directives, gql.NewDirective(gql.DirectiveConfig{Name: name, Locations: []string{gql.DirectiveLocationField}}))
+	}
+
+	config := gql.SchemaConfig{Query: objects["Query"], Directives: directives}
+	if mutation, ok := objects["Mutation"]; ok {
+		config.Mutation = mutation
+	}
+	return gql.NewSchema(config)
+}
+
+// resolveTypeRef returns the type of a type reference like [users!]!
+func resolveTypeRef(ref string, named map[string]gql.Type) gql.Output {
+	if strings.HasSuffix(ref, "!") {
+		return gql.NewNonNull(resolveTypeRef(strings.TrimSuffix(ref, "!"), named))
+	}
+	if strings.HasPrefix(ref, "[") && strings.HasSuffix(ref, "]") {
+		return gql.NewList(resolveTypeRef(ref[1:len(ref)-1], named))
+	}
+	if t, ok := named[ref]; ok {
+		return t
+	}
+	return named["JSON"]
+}
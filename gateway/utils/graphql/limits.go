@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// SetLimits sets the limits on the depth, complexity and aliases of the queries of the project
+func (graph *Module) SetLimits(limits *config.GraphQLLimits) {
+	graph.limits = limits
+}
+
+// queryCost holds the metrics of a graphql document which are checked against the limits of the project
+type queryCost struct {
+	depth      int
+	complexity int
+	aliases    int
+}
+
+// checkLimits returns an error if the document exceeds any of the limits of the project
+func (graph *Module) checkLimits(doc *ast.Document) error {
+	limits := graph.limits
+	if limits == nil {
+		return nil
+	}
+
+	cost := new(queryCost)
+	for _, definition := range doc.Definitions {
+		if op, ok := definition.(*ast.OperationDefinition); ok {
+			cost.addSelectionSet(op.SelectionSet, 0)
+		}
+	}
+
+	if limits.MaxDepth > 0 && cost.depth > limits.MaxDepth {
+		return fmt.Errorf("query depth (%d) exceeds the max depth (%d) allowed by the project", cost.depth, limits.MaxDepth)
+	}
+	if limits.MaxComplexity > 0 && cost.complexity > limits.MaxComplexity {
+		return fmt.Errorf("query complexity (%d) exceeds the max complexity (%d) allowed by the project - the complexity is the number of fields selected", cost.complexity, limits.MaxComplexity)
+	}
+	if limits.MaxAliases > 0 && cost.aliases > limits.MaxAliases {
+		return fmt.Errorf("query has %d aliases which exceeds the max aliases (%d) allowed by the project", cost.aliases, limits.MaxAliases)
+	}
+	return nil
+}
+
+func (cost *queryCost) addSelectionSet(selectionSet *ast.SelectionSet, depth int) {
+	if selectionSet == nil || len(selectionSet.Selections) == 0 {
+		return
+	}
+
+	depth++
+	if depth > cost.depth {
+		cost.depth = depth
+	}
+
+	for _, selection := range selectionSet.Selections {
+		switch v := selection.(type) {
+		case *ast.Field:
+			cost.complexity++
+			if v.Alias != nil {
+				cost.aliases++
+			}
+			cost.addSelectionSet(v.SelectionSet, depth)
+		case *ast.InlineFragment:
+			// Fields of an inline fragment belong to the selection set containing the fragment
+			cost.addSelectionSet(v.SelectionSet, depth-1)
+		}
+	}
+}
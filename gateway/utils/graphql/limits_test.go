@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func TestModule_checkLimits(t *testing.T) {
+	const query = `query {
+		a: users @db { id posts { id comments { id } } }
+		b: users @db { id }
+	}`
+
+	tests := []struct {
+		name    string
+		limits  *config.GraphQLLimits
+		wantErr bool
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name:   "within limits",
+			limits: &config.GraphQLLimits{MaxDepth: 4, MaxComplexity: 8, MaxAliases: 2},
+		},
+		{
+			name:    "depth exceeded",
+			limits:  &config.GraphQLLimits{MaxDepth: 3},
+			wantErr: true,
+		},
+		{
+			name:    "complexity exceeded",
+			limits:  &config.GraphQLLimits{MaxComplexity: 7},
+			wantErr: true,
+		},
+		{
+			name:    "aliases exceeded",
+			limits:  &config.GraphQLLimits{MaxAliases: 1},
+			wantErr: true,
+		},
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query)})})
+	if err != nil {
+		t.Fatalf("Unable to parse query - %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := &Module{limits: tt.limits}
+			if err := graph.checkLimits(doc); (err != nil) != tt.wantErr {
+				t.Errorf("checkLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
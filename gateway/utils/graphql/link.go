@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// getLinkFindValue returns the value the to field of a linked collection is matched with for the value of the from
+// field of a document. Links across databases are described in docs/graphql-links.md.
+func (graph *Module) getLinkFindValue(linkedInfo *model.TableProperties, value interface{}) interface{} {
+	dbType, err := graph.crud.GetDBType(linkedInfo.DBType)
+	if err != nil {
+		return value
+	}
+
+	var field *model.FieldType
+	if fields, p := graph.schema.GetSchema(linkedInfo.DBType, linkedInfo.Table); p {
+		field = fields[linkedInfo.To]
+	}
+	return convertLinkValue(value, dbType, field)
+}
+
+// convertLinkValue converts the value to the type of the field in the database. Values which can't be converted are
+// returned as is, in which case they match no documents.
+func convertLinkValue(value interface{}, dbType string, field *model.FieldType) interface{} {
+	if arr, ok := value.([]interface{}); ok {
+		converted := make([]interface{}, len(arr))
+		for i, v := range arr {
+			converted[i] = convertLinkValue(v, dbType, field)
+		}
+		return converted
+	}
+
+	// Object ids only exist in mongo
+	if id, ok := value.(primitive.ObjectID); ok && dbType != string(model.Mongo) {
+		value = id.Hex()
+	}
+	if field == nil {
+		return value
+	}
+
+	switch field.Kind {
+	case model.TypeInteger, model.TypeSmallInteger, model.TypeBigInteger:
+		switch v := value.(type) {
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i
+			}
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v)
+			}
+		case float32:
+			if float64(v) == math.Trunc(float64(v)) {
+				return int64(v)
+			}
+		}
+
+	case model.TypeFloat, model.TypeDecimal:
+		switch v := value.(type) {
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		case int:
+			return float64(v)
+		case int32:
+			return float64(v)
+		case int64:
+			return float64(v)
+		}
+
+	case model.TypeID, model.TypeString, model.TypeChar, model.TypeVarChar:
+		switch v := value.(type) {
+		case int, int32, int64:
+			return fmt.Sprintf("%d", v)
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return value
+}
@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_convertLinkValue(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		value  interface{}
+		dbType string
+		field  *model.FieldType
+		want   interface{}
+	}{
+		{
+			name:   "no schema",
+			value:  "1",
+			dbType: string(model.Postgres),
+			want:   "1",
+		},
+		{
+			name:   "string to integer",
+			value:  "12",
+			dbType: string(model.Postgres),
+			field:  &model.FieldType{Kind: model.TypeInteger},
+			want:   int64(12),
+		},
+		{
+			name:   "integral float to integer",
+			value:  float64(12),
+			dbType: string(model.MySQL),
+			field:  &model.FieldType{Kind: model.TypeBigInteger},
+			want:   int64(12),
+		},
+		{
+			name:   "fractional float isn't converted to integer",
+			value:  1.5,
+			dbType: string(model.MySQL),
+			field:  &model.FieldType{Kind: model.TypeInteger},
+			want:   1.5,
+		},
+		{
+			name:   "integer to float",
+			value:  int64(3),
+			dbType: string(model.Mongo),
+			field:  &model.FieldType{Kind: model.TypeFloat},
+			want:   float64(3),
+		},
+		{
+			name:   "integer to string",
+			value:  int64(7),
+			dbType: string(model.Mongo),
+			field:  &model.FieldType{Kind: model.TypeString},
+			want:   "7",
+		},
+		{
+			name:   "float to id",
+			value:  float64(7),
+			dbType: string(model.Mongo),
+			field:  &model.FieldType{Kind: model.TypeID},
+			want:   "7",
+		},
+		{
+			name:   "object id outside mongo",
+			value:  id,
+			dbType: string(model.Postgres),
+			field:  &model.FieldType{Kind: model.TypeID},
+			want:   id.Hex(),
+		},
+		{
+			name:   "object id within mongo",
+			value:  id,
+			dbType: string(model.Mongo),
+			field:  &model.FieldType{Kind: model.TypeID},
+			want:   id,
+		},
+		{
+			name:   "array",
+			value:  []interface{}{"1", "2"},
+			dbType: string(model.Postgres),
+			field:  &model.FieldType{Kind: model.TypeInteger},
+			want:   []interface{}{int64(1), int64(2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertLinkValue(tt.value, tt.dbType, tt.field); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertLinkValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -108,6 +108,17 @@ func (graph *Module) handleMutation(ctx context.Context, node ast.Node, token st
 			return
 		}
 
+		// Increments are made right away instead of being batched with the other mutations
+		if strings.HasPrefix(field.Name.Value, "increment_") {
+			result, err := graph.execIncrement(ctx, field, dbAlias, token, store)
+			if err != nil {
+				cb(nil, err)
+				return
+			}
+			results[getFieldName(field)] = result
+			continue
+		}
+
 		// Generate a *model.AllRequest object for this given field
 		params, generatedRequests, returningDocs, err := graph.generateAllReq(ctx, field, dbAlias, token, store)
 		if err != nil {
@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+// The errors returned for persisted queries. Apollo clients look for the not found error to retry the request along
+// with the text of the query.
+var (
+	errPersistedQueryNotFound   = errors.New("PersistedQueryNotFound")
+	errPersistedQueryNotAllowed = errors.New("PersistedQueryNotAllowed: only persisted queries can be executed in this project")
+	errPersistedQueryMismatch   = errors.New("provided sha256 hash does not match the query")
+)
+
+// SetPersistedQueries sets the persisted queries of the project. The queries registered automatically are forgotten.
+func (graph *Module) SetPersistedQueries(c *config.PersistedQueries) {
+	graph.persistedLock.Lock()
+	defer graph.persistedLock.Unlock()
+
+	graph.persisted = c
+	graph.registeredQueries = map[string]string{}
+}
+
+// resolvePersistedQuery fills the text of the query if the request only has its hash. It also enforces the
+// allow-list of the project and auto registers the queries when enabled.
+func (graph *Module) resolvePersistedQuery(req *model.GraphQLRequest) error {
+	graph.persistedLock.RLock()
+	c := graph.persisted
+	graph.persistedLock.RUnlock()
+
+	var hash string
+	if req.Extensions != nil && req.Extensions.PersistedQuery != nil {
+		hash = strings.ToLower(req.Extensions.PersistedQuery.Sha256Hash)
+	}
+
+	// Queries sent without a hash only need to be checked against the allow-list
+	if hash == "" {
+		if c != nil && c.AllowListOnly {
+			if _, ok := c.Queries[hashQuery(req.Query)]; !ok {
+				return errPersistedQueryNotAllowed
+			}
+		}
+		return nil
+	}
+
+	if req.Query == "" {
+		query, ok := graph.getPersistedQuery(c, hash)
+		if !ok {
+			return errPersistedQueryNotFound
+		}
+		req.Query = query
+		return nil
+	}
+
+	if hashQuery(req.Query) != hash {
+		return errPersistedQueryMismatch
+	}
+	if _, ok := graph.getPersistedQuery(c, hash); ok || c == nil {
+		return nil
+	}
+	if c.AllowListOnly {
+		return errPersistedQueryNotAllowed
+	}
+	if c.AutoRegister {
+		graph.persistedLock.Lock()
+		graph.registeredQueries[hash] = req.Query
+		graph.persistedLock.Unlock()
+	}
+	return nil
+}
+
+func (graph *Module) getPersistedQuery(c *config.PersistedQueries, hash string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	if query, ok := c.Queries[hash]; ok {
+		return query, true
+	}
+
+	graph.persistedLock.RLock()
+	defer graph.persistedLock.RUnlock()
+	query, ok := graph.registeredQueries[hash]
+	return query, ok
+}
+
+func hashQuery(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
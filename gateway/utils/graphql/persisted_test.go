@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func TestModule_resolvePersistedQuery(t *testing.T) {
+	const query = "query { users @db { id } }"
+	hash := hashQuery(query)
+	withHash := func(q, h string) *model.GraphQLRequest {
+		return &model.GraphQLRequest{Query: q, Extensions: &model.GraphQLExtensions{PersistedQuery: &model.PersistedQuery{Version: 1, Sha256Hash: h}}}
+	}
+
+	tests := []struct {
+		name      string
+		persisted *config.PersistedQueries
+		req       *model.GraphQLRequest
+		wantQuery string
+		wantErr   bool
+	}{
+		{
+			name:      "plain query without persisted queries",
+			req:       &model.GraphQLRequest{Query: query},
+			wantQuery: query,
+		},
+		{
+			name:      "hash of a persisted query",
+			persisted: &config.PersistedQueries{Queries: map[string]string{hash: query}},
+			req:       withHash("", hash),
+			wantQuery: query,
+		},
+		{
+			name:      "unknown hash",
+			persisted: &config.PersistedQueries{},
+			req:       withHash("", hash),
+			wantErr:   true,
+		},
+		{
+			name:      "hash not matching the query",
+			persisted: &config.PersistedQueries{},
+			req:       withHash(query, "abcd"),
+			wantErr:   true,
+		},
+		{
+			name:      "persisted query sent as text in allow-list mode",
+			persisted: &config.PersistedQueries{AllowListOnly: true, Queries: map[string]string{hash: query}},
+			req:       &model.GraphQLRequest{Query: query},
+			wantQuery: query,
+		},
+		{
+			name:      "unknown query in allow-list mode",
+			persisted: &config.PersistedQueries{AllowListOnly: true, AutoRegister: true},
+			req:       withHash(query, hash),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := &Module{}
+			graph.SetPersistedQueries(tt.persisted)
+			err := graph.resolvePersistedQuery(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolvePersistedQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && tt.req.Query != tt.wantQuery {
+				t.Errorf("resolvePersistedQuery() query = %v, want %v", tt.req.Query, tt.wantQuery)
+			}
+		})
+	}
+
+	// Queries sent along with their hash can be executed with just the hash once they are auto registered
+	graph := &Module{}
+	graph.SetPersistedQueries(&config.PersistedQueries{AutoRegister: true})
+	if err := graph.resolvePersistedQuery(withHash(query, hash)); err != nil {
+		t.Fatalf("resolvePersistedQuery() error = %v", err)
+	}
+	req := withHash("", hash)
+	if err := graph.resolvePersistedQuery(req); err != nil || req.Query != query {
+		t.Errorf("resolvePersistedQuery() did not resolve the auto registered query - %v", err)
+	}
+}
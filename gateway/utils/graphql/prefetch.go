@@ -0,0 +1,103 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// The prefetch hints of a linked field pushed down as a join. They are described in docs/graphql-links.md.
+const (
+	prefetchAuto  = "auto"
+	prefetchJSON  = "json"
+	prefetchJoin  = "join"
+	prefetchBatch = "batch"
+)
+
+// getPrefetchHint returns the prefetch hint of a linked field
+func getPrefetchHint(ctx context.Context, field *ast.Field, store utils.M) (string, error) {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "prefetch" {
+			continue
+		}
+
+		val, err := utils.ParseGraphqlValue(arg.Value, store)
+		if err != nil {
+			return "", err
+		}
+		hint, ok := val.(string)
+		switch {
+		case !ok:
+			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), "Field (prefetch) should be of type string", nil, nil)
+		case hint != prefetchAuto && hint != prefetchJSON && hint != prefetchJoin && hint != prefetchBatch:
+			return "", helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid prefetch hint (%s) provided - it should be one of auto, json, join or batch", hint), nil, nil)
+		}
+		return hint, nil
+	}
+	return prefetchAuto, nil
+}
+
+// getJoinStrategy returns the strategy of the join a link is pushed down as
+func getJoinStrategy(hint, dbType string, fieldStruct *model.FieldType) string {
+	if dbType != string(model.Postgres) && dbType != string(model.MySQL) {
+		return model.JoinStrategyRows
+	}
+	switch hint {
+	case prefetchJSON:
+		return model.JoinStrategyJSON
+	case prefetchJoin:
+		return model.JoinStrategyRows
+	}
+	if fieldStruct.IsList {
+		return model.JoinStrategyJSON
+	}
+	return model.JoinStrategyRows
+}
+
+// flattenReferencedJSONJoins makes the joins using json aggregation flatten their rows if the where or sort clause of
+// the request refers to the joined table, since the columns of the joined table aren't available to the parent query
+func flattenReferencedJSONJoins(req *model.ReadRequest, join []*model.JoinOption) {
+	tables := map[string]bool{}
+	addFindTables(req.Find, tables)
+	for _, field := range req.Options.Sort {
+		tables[strings.Split(strings.TrimPrefix(field, "-"), ".")[0]] = true
+	}
+
+	for _, j := range join {
+		if j.Strategy == model.JoinStrategyJSON && tables[j.Table] {
+			j.Strategy = model.JoinStrategyRows
+		}
+		flattenReferencedJSONJoins(req, j.Join)
+	}
+}
+
+func addFindTables(find map[string]interface{}, tables map[string]bool) {
+	for k, v := range find {
+		if strings.HasPrefix(k, "$or") {
+			arr, _ := v.([]interface{})
+			for _, item := range arr {
+				if f, ok := item.(map[string]interface{}); ok {
+					addFindTables(f, tables)
+				}
+			}
+			continue
+		}
+		tables[strings.Split(k, ".")[0]] = true
+	}
+}
+
+// flattenJSONJoins makes the joins using json aggregation flatten their rows instead
+func flattenJSONJoins(join []*model.JoinOption) {
+	for _, j := range join {
+		if j.Strategy == model.JoinStrategyJSON {
+			j.Strategy = model.JoinStrategyRows
+		}
+		flattenJSONJoins(j.Join)
+	}
+}
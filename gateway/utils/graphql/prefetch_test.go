@@ -0,0 +1,68 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func Test_getJoinStrategy(t *testing.T) {
+	list := &model.FieldType{IsList: true}
+	single := &model.FieldType{}
+
+	tests := []struct {
+		name   string
+		hint   string
+		dbType string
+		field  *model.FieldType
+		want   string
+	}{
+		{name: "auto list", hint: prefetchAuto, dbType: string(model.Postgres), field: list, want: model.JoinStrategyJSON},
+		{name: "auto single", hint: prefetchAuto, dbType: string(model.MySQL), field: single, want: model.JoinStrategyRows},
+		{name: "json single", hint: prefetchJSON, dbType: string(model.MySQL), field: single, want: model.JoinStrategyJSON},
+		{name: "join list", hint: prefetchJoin, dbType: string(model.Postgres), field: list, want: model.JoinStrategyRows},
+		{name: "json on sql server", hint: prefetchJSON, dbType: string(model.SQLServer), field: list, want: model.JoinStrategyRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getJoinStrategy(tt.hint, tt.dbType, tt.field); got != tt.want {
+				t.Errorf("getJoinStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_flattenReferencedJSONJoins(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *model.ReadRequest
+		want []string
+	}{
+		{
+			name: "not referred",
+			req:  &model.ReadRequest{Find: map[string]interface{}{"users.id": 1}, Options: &model.ReadOptions{Sort: []string{"-users.name"}}},
+			want: []string{model.JoinStrategyJSON, model.JoinStrategyJSON},
+		},
+		{
+			name: "referred by where",
+			req:  &model.ReadRequest{Find: map[string]interface{}{"$or": []interface{}{map[string]interface{}{"orders.amount": 1}}}, Options: &model.ReadOptions{}},
+			want: []string{model.JoinStrategyRows, model.JoinStrategyJSON},
+		},
+		{
+			name: "referred by sort",
+			req:  &model.ReadRequest{Options: &model.ReadOptions{Sort: []string{"-carts.id"}}},
+			want: []string{model.JoinStrategyJSON, model.JoinStrategyRows},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			join := []*model.JoinOption{{Table: "orders", Strategy: model.JoinStrategyJSON}, {Table: "carts", Strategy: model.JoinStrategyJSON}}
+			flattenReferencedJSONJoins(tt.req, join)
+			for i, j := range join {
+				if j.Strategy != tt.want[i] {
+					t.Errorf("flattenReferencedJSONJoins() strategy of join (%s) = %v, want %v", j.Table, j.Strategy, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -90,6 +90,18 @@ func (graph *Module) execReadRequest(ctx context.Context, field *ast.Field, toke
 		cb("", "", nil, err)
 		return
 	}
+
+	// Queries with a search argument are full text searches
+	searchReq, isSearch, err := getSearchRequest(ctx, field, store)
+	if err != nil {
+		cb("", "", nil, err)
+		return
+	}
+	if isSearch {
+		graph.execSearchRequest(ctx, dbAlias, col, token, searchReq, cb)
+		return
+	}
+
 	req, hasOptions, err := generateReadRequest(ctx, field, store)
 	if err != nil {
 		cb("", "", nil, err)
@@ -102,6 +114,10 @@ func (graph *Module) execReadRequest(ctx context.Context, field *ast.Field, toke
 		return
 	}
 	req.Aggregate = functionMap
+	if len(functionMap) > 0 {
+		flattenJSONJoins(req.Options.Join)
+	}
+	flattenReferencedJSONJoins(req, req.Options.Join)
 	if len(selectionSet) > 0 {
 		req.Options.Select = selectionSet
 	}
@@ -184,7 +200,11 @@ func (graph *Module) runAuthForJoins(ctx context.Context, dbType, dbAlias, token
 		}
 
 		if len(returnWhere.Where) > 0 {
-			req.MatchWhere = append(req.MatchWhere, returnWhere.Where)
+			if j.Strategy == model.JoinStrategyJSON {
+				j.MatchWhere = append(j.MatchWhere, returnWhere.Where)
+			} else {
+				req.MatchWhere = append(req.MatchWhere, returnWhere.Where)
+			}
 		}
 
 		req.PostProcess[j.Table] = actions
@@ -288,9 +308,9 @@ func generateArguments(ctx context.Context, field *ast.Field, store utils.M) map
 	obj := map[string]interface{}{}
 	for _, arg := range field.Arguments {
 		switch arg.Name.Value {
-		case "where", "group", "skip", "limit", "sort", "distinct": // read & delete
+		case "where", "group", "skip", "limit", "sort", "distinct", "readAt": // read & delete
 			continue
-		case "op", "set", "inc", "mul", "max", "min", "currentTimestamp", "currentDate", "push", "rename", "unset": // update
+		case "op", "set", "inc", "mul", "max", "min", "currentTimestamp", "currentDate", "push", "addToSet", "pull", "rename", "unset": // update
 			continue
 		case "docs": // create
 			continue
@@ -306,7 +326,10 @@ func generateArguments(ctx context.Context, field *ast.Field, store utils.M) map
 	}
 	return obj
 }
-func (graph *Module) checkIfLinkCanBeOptimized(fieldStruct *model.FieldType, dbAlias, col string) (*model.JoinOption, bool) {
+func (graph *Module) checkIfLinkCanBeOptimized(fieldStruct *model.FieldType, dbAlias, col, hint string) (*model.JoinOption, bool) {
+	if hint == prefetchBatch {
+		return nil, false
+	}
 	currentTableFieldID := fieldStruct.LinkedTable.From
 	referredTableFieldID := fieldStruct.LinkedTable.To
 	if fieldStruct.LinkedTable.Field != "" {
@@ -339,7 +362,8 @@ func (graph *Module) checkIfLinkCanBeOptimized(fieldStruct *model.FieldType, dbA
 		On: map[string]interface{}{
 			fmt.Sprintf("%s.%s", col, currentTableFieldID): fmt.Sprintf("%s.%s", referredTableName, referredTableFieldID),
 		},
-		Type: "LEFT",
+		Type:     "LEFT",
+		Strategy: getJoinStrategy(hint, dbType, fieldStruct),
 	}, true
 }
 
@@ -394,7 +418,11 @@ func (graph *Module) extractSelectionSet(ctx context.Context, field *ast.Field,
 					continue
 				}
 				// check if the link can be optimised to join
-				joinInfo, isOptimized := graph.checkIfLinkCanBeOptimized(fieldStruct, dbAlias, col)
+				hint, err := getPrefetchHint(ctx, v, store)
+				if err != nil {
+					return nil, nil, err
+				}
+				joinInfo, isOptimized := graph.checkIfLinkCanBeOptimized(fieldStruct, dbAlias, col, hint)
 				if !isOptimized {
 					continue
 				}
@@ -413,6 +441,10 @@ func (graph *Module) extractSelectionSet(ctx context.Context, field *ast.Field,
 			if err != nil {
 				return nil, nil, err
 			}
+			// The rows of a join using json aggregation can't be joined further
+			if joinTable.Strategy == model.JoinStrategyJSON && (len(joinTable.Join) > 0 || len(f) > 0) {
+				joinTable.Strategy = model.JoinStrategyRows
+			}
 			for key, value := range f {
 				v, ok := functionMap[key]
 				if !ok {
@@ -787,6 +819,20 @@ func generateOptions(ctx context.Context, args []*ast.Argument, store utils.M) (
 			}
 
 			options.Distinct = &tempString
+		case "readAt":
+			hasOptions = true // Set the flag to true
+
+			temp, err := utils.ParseGraphqlValue(v.Value, store)
+			if err != nil {
+				return nil, hasOptions, err
+			}
+
+			tempString, ok := temp.(string)
+			if !ok {
+				return nil, hasOptions, fmt.Errorf("invalid type (%s) for readAt expecting an RFC3339 string", reflect.TypeOf(temp))
+			}
+
+			options.ReadAt = tempString
 		case "debug":
 			hasOptions = true // Set the flag to true
 
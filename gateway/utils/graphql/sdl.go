@@ -0,0 +1,282 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+)
+
+// sdlDocument is the graphql schema generated from the collections and remote services of a project. It is used to
+// export the schema as SDL and to answer introspection queries.
+type sdlDocument struct {
+	scalars    []string
+	directives []string
+	types      []*sdlType
+}
+
+type sdlType struct {
+	name        string
+	description string
+	fields      []*sdlField
+}
+
+type sdlField struct {
+	name        string
+	typ         string // type reference like [users!]!
+	description string
+	args        []*sdlArg
+}
+
+type sdlArg struct {
+	name string
+	typ  string
+}
+
+// The scalars of graphql which don't need to be declared
+var builtinScalars = map[string]bool{"ID": true, "String": true, "Int": true, "Float": true, "Boolean": true}
+
+// SetRemoteServices sets the remote services of the project whose endpoints are added to the generated schema
+func (graph *Module) SetRemoteServices(services config.Services) {
+	graph.services = services
+}
+
+// GetSDL returns the generated schema of the project in the graphql schema definition language
+func (graph *Module) GetSDL() string {
+	return graph.generateSDLDocument().String()
+}
+
+func (graph *Module) generateSDLDocument() *sdlDocument {
+	schemas := graph.schema.GetSchemas()
+	doc := &sdlDocument{}
+	scalars := map[string]bool{"JSON": true}
+
+	// Type names are the names of the collections. The db alias is prefixed to the collections present in multiple
+	// databases to keep the names unique.
+	dbAliases := make([]string, 0, len(schemas))
+	colCount := map[string]int{}
+	for dbAlias, collections := range schemas {
+		dbAliases = append(dbAliases, dbAlias)
+		for col := range collections {
+			colCount[col]++
+		}
+	}
+	sort.Strings(dbAliases)
+	typeName := func(dbAlias, col string) (string, bool) {
+		if _, ok := schemas[dbAlias][col]; !ok {
+			return "", false
+		}
+		if colCount[col] > 1 {
+			return fmt.Sprintf("%s_%s", dbAlias, col), true
+		}
+		return col, true
+	}
+
+	query := &sdlType{name: "Query"}
+	mutation := &sdlType{name: "Mutation"}
+	for _, dbAlias := range dbAliases {
+		doc.addDirective(dbAlias)
+
+		collections := schemas[dbAlias]
+		cols := make([]string, 0, len(collections))
+		for col := range collections {
+			if col != "default" {
+				cols = append(cols, col)
+			}
+		}
+		sort.Strings(cols)
+
+		for _, col := range cols {
+			name, _ := typeName(dbAlias, col)
+			t := &sdlType{name: name, description: fmt.Sprintf("Collection %s of database %s", col, dbAlias)}
+			doc.types = append(doc.types, t)
+			doc.addFields(t, collections[col], scalars, typeName)
+
+			args := []*sdlArg{
+				{name: "where", typ: "JSON"}, {name: "sort", typ: "[String!]"}, {name: "skip", typ: "Int"}, {name: "limit", typ: "Int"},
+				{name: "distinct", typ: "String"}, {name: "group", typ: "[String!]"},
+			}
+			if _, ok := schemaHelpers.GetHistoryTable(schemas, dbAlias, col); ok {
+				args = append(args, &sdlArg{name: "readAt", typ: "String"})
+			}
+			query.addField(&sdlField{
+				name:        col,
+				typ:         fmt.Sprintf("[%s!]", name),
+				description: fmt.Sprintf("Reads from %s with the @%s directive", col, dbAlias),
+				args:        args,
+			})
+
+			// History tables are maintained by space cloud
+			if schemaHelpers.IsHistoryTable(schemas, dbAlias, col) {
+				continue
+			}
+			mutation.addField(&sdlField{name: "insert_" + col, typ: "MutationResponse", args: []*sdlArg{{name: "docs", typ: "[JSON!]!"}}})
+			mutation.addField(&sdlField{name: "update_" + col, typ: "MutationResponse", args: []*sdlArg{
+				{name: "where", typ: "JSON"}, {name: "set", typ: "JSON"}, {name: "inc", typ: "JSON"}, {name: "mul", typ: "JSON"},
+				{name: "max", typ: "JSON"}, {name: "min", typ: "JSON"}, {name: "currentDate", typ: "JSON"}, {name: "unset", typ: "JSON"},
+			}})
+			mutation.addField(&sdlField{name: "increment_" + col, typ: "MutationResponse", args: []*sdlArg{
+				{name: "where", typ: "JSON"}, {name: "inc", typ: "JSON!"}, {name: "upsert", typ: "Boolean"},
+			}})
+			mutation.addField(&sdlField{name: "delete_" + col, typ: "MutationResponse", args: []*sdlArg{{name: "where", typ: "JSON"}}})
+		}
+	}
+
+	// The arguments and responses of the endpoints of remote services aren't typed
+	serviceIDs := make([]string, 0, len(graph.services))
+	for id := range graph.services {
+		serviceIDs = append(serviceIDs, id)
+	}
+	sort.Strings(serviceIDs)
+	for _, id := range serviceIDs {
+		doc.addDirective(id)
+		endpoints := make([]string, 0, len(graph.services[id].Endpoints))
+		for endpoint := range graph.services[id].Endpoints {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		for _, endpoint := range endpoints {
+			query.addField(&sdlField{name: endpoint, typ: "JSON", description: fmt.Sprintf("Calls endpoint %s of remote service %s with the @%s directive", endpoint, id, id)})
+		}
+	}
+
+	doc.types = append(doc.types, &sdlType{name: "MutationResponse", fields: []*sdlField{
+		{name: "status", typ: "Int"}, {name: "error", typ: "String"}, {name: "returning", typ: "[JSON]"},
+	}})
+	if len(query.fields) == 0 {
+		// The query type can't be empty
+		query.fields = append(query.fields, &sdlField{name: "_empty", typ: "Boolean"})
+	}
+	doc.types = append(doc.types, query)
+	if len(mutation.fields) > 0 {
+		doc.types = append(doc.types, mutation)
+	}
+
+	for scalar := range scalars {
+		doc.scalars = append(doc.scalars, scalar)
+	}
+	sort.Strings(doc.scalars)
+	return doc
+}
+
+// addFields adds the fields of a collection, or a nested object, to its type
+func (doc *sdlDocument) addFields(t *sdlType, fields model.Fields, scalars map[string]bool, typeName func(dbAlias, col string) (string, bool)) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fields[name]
+
+		var typ string
+		var isLinked bool
+		if field.IsLinked && field.LinkedTable != nil {
+			typ, isLinked = typeName(field.LinkedTable.DBType, field.LinkedTable.Table)
+		}
+		switch {
+		case isLinked:
+		case field.IsLinked:
+			// The linked collection doesn't have a schema
+			typ = "JSON"
+		case field.Kind == model.TypeObject && len(field.NestedObject) > 0:
+			typ = fmt.Sprintf("%s_%s", t.name, name)
+			nested := &sdlType{name: typ}
+			doc.types = append(doc.types, nested)
+			doc.addFields(nested, field.NestedObject, scalars, typeName)
+		default:
+			typ = getScalarType(field.Kind)
+			if !builtinScalars[typ] {
+				scalars[typ] = true
+			}
+		}
+
+		if field.IsList {
+			typ = fmt.Sprintf("[%s]", typ)
+		}
+		if field.IsFieldTypeRequired {
+			typ += "!"
+		}
+		t.fields = append(t.fields, &sdlField{name: name, typ: typ})
+	}
+}
+
+func (doc *sdlDocument) addDirective(name string) {
+	for _, directive := range doc.directives {
+		if directive == name {
+			return
+		}
+	}
+	doc.directives = append(doc.directives, name)
+}
+
+// addField adds a field to the type unless it already has a field with the same name. This happens when collections
+// of different databases, or endpoints of different services, share the same name.
+func (t *sdlType) addField(field *sdlField) {
+	for _, f := range t.fields {
+		if f.name == field.name {
+			return
+		}
+	}
+	t.fields = append(t.fields, field)
+}
+
+func getScalarType(kind string) string {
+	switch kind {
+	case model.TypeID:
+		return "ID"
+	case model.TypeString, model.TypeChar, model.TypeVarChar, model.TypeEnum:
+		return "String"
+	case model.TypeInteger, model.TypeSmallInteger:
+		return "Int"
+	case model.TypeFloat:
+		return "Float"
+	case model.TypeBoolean:
+		return "Boolean"
+	case model.TypeObject:
+		return "JSON"
+	default:
+		// Date, Time, DateTime, UUID, JSON, BigInteger, Decimal and the geographic types
+		return kind
+	}
+}
+
+// String returns the document in the graphql schema definition language
+func (doc *sdlDocument) String() string {
+	var b strings.Builder
+	for _, scalar := range doc.scalars {
+		fmt.Fprintf(&b, "scalar %s\n", scalar)
+	}
+	b.WriteString("\n")
+	for _, directive := range doc.directives {
+		fmt.Fprintf(&b, "directive @%s on FIELD\n", directive)
+	}
+
+	for _, t := range doc.types {
+		b.WriteString("\n")
+		if t.description != "" {
+			fmt.Fprintf(&b, "\"\"\"%s\"\"\"\n", t.description)
+		}
+		fmt.Fprintf(&b, "type %s {\n", t.name)
+		for _, field := range t.fields {
+			if field.description != "" {
+				fmt.Fprintf(&b, "  \"\"\"%s\"\"\"\n", field.description)
+			}
+			fmt.Fprintf(&b, "  %s", field.name)
+			if len(field.args) > 0 {
+				args := make([]string, len(field.args))
+				for i, arg := range field.args {
+					args[i] = fmt.Sprintf("%s: %s", arg.name, arg.typ)
+				}
+				fmt.Fprintf(&b, "(%s)", strings.Join(args, ", "))
+			}
+			fmt.Fprintf(&b, ": %s\n", field.typ)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
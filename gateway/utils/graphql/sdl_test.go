@@ -0,0 +1,116 @@
+package graphql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+type sdlTestSchema model.Type
+
+func (s sdlTestSchema) GetSchema(dbAlias, col string) (model.Fields, bool) {
+	fields, ok := s[dbAlias][col]
+	return fields, ok
+}
+
+func (s sdlTestSchema) GetSchemas() model.Type {
+	return model.Type(s)
+}
+
+func newSDLTestModule() *Module {
+	schema := sdlTestSchema{"db": model.Collection{
+		"users": model.Fields{
+			"id":      &model.FieldType{FieldName: "id", Kind: model.TypeID, IsFieldTypeRequired: true, IsPrimary: true},
+			"born_at": &model.FieldType{FieldName: "born_at", Kind: model.TypeDateTime},
+			"posts":   &model.FieldType{FieldName: "posts", Kind: model.TypeObject, IsList: true, IsLinked: true, LinkedTable: &model.TableProperties{DBType: "db", Table: "posts", From: "id", To: "author_id"}},
+		},
+		"posts": model.Fields{
+			"id":        &model.FieldType{FieldName: "id", Kind: model.TypeID, IsFieldTypeRequired: true},
+			"author_id": &model.FieldType{FieldName: "author_id", Kind: model.TypeID},
+		},
+	}}
+	graph := New(nil, nil, nil, schema)
+	graph.SetRemoteServices(config.Services{"payments": &config.Service{Endpoints: map[string]*config.Endpoint{"charge": {}}}})
+	return graph
+}
+
+func TestModule_GetSDL(t *testing.T) {
+	sdl := newSDLTestModule().GetSDL()
+	for _, want := range []string{
+		"scalar DateTime\n",
+		"directive @db on FIELD\n",
+		"directive @payments on FIELD\n",
+		"type users {\n  born_at: DateTime\n  id: ID!\n  posts: [posts]\n}\n",
+		"  users(where: JSON, sort: [String!], skip: Int, limit: Int, distinct: String, group: [String!]): [users!]\n",
+		"  charge: JSON\n",
+		"  increment_posts(where: JSON, inc: JSON!, upsert: Boolean): MutationResponse\n",
+		"  delete_posts(where: JSON): MutationResponse\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("GetSDL() does not contain %q, got:\n%s", want, sdl)
+		}
+	}
+
+	// The generated schema should be valid
+	if _, err := newSDLTestModule().generateSDLDocument().toSchema(); err != nil {
+		t.Errorf("toSchema() error = %v", err)
+	}
+}
+
+func TestModule_GetSDL_history(t *testing.T) {
+	schema := sdlTestSchema{"db": model.Collection{
+		"users": model.Fields{
+			"id": &model.FieldType{FieldName: "id", Kind: model.TypeID, IsFieldTypeRequired: true, IsPrimary: true},
+		},
+		"users_history": model.Fields{
+			"id":                 &model.FieldType{FieldName: "id", Kind: model.TypeID},
+			model.HistoryFieldID: &model.FieldType{FieldName: model.HistoryFieldID, Kind: model.TypeID, IsFieldTypeRequired: true, IsPrimary: true},
+			model.HistoryFieldOp: &model.FieldType{FieldName: model.HistoryFieldOp, Kind: model.TypeVarChar, IsFieldTypeRequired: true},
+			model.HistoryFieldAt: &model.FieldType{FieldName: model.HistoryFieldAt, Kind: model.TypeDateTime, IsFieldTypeRequired: true},
+		},
+	}}
+	sdl := New(nil, nil, nil, schema).GetSDL()
+
+	if want := "  users(where: JSON, sort: [String!], skip: Int, limit: Int, distinct: String, group: [String!], readAt: String): [users!]\n"; !strings.Contains(sdl, want) {
+		t.Errorf("GetSDL() does not contain %q, got:\n%s", want, sdl)
+	}
+	if strings.Contains(sdl, "insert_users_history") {
+		t.Errorf("GetSDL() should not have mutations on the history table, got:\n%s", sdl)
+	}
+}
+
+func TestModule_execIntrospectionQuery(t *testing.T) {
+	const query = `{ __type(name: "users") { fields { name } } }`
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query)})})
+	if err != nil {
+		t.Fatalf("Unable to parse query - %v", err)
+	}
+	if ok, err := isIntrospectionQuery(doc); !ok || err != nil {
+		t.Fatalf("isIntrospectionQuery() = %v, %v, want true", ok, err)
+	}
+
+	graph := newSDLTestModule()
+	got, err := graph.execIntrospectionQuery(context.Background(), &model.GraphQLRequest{Query: query})
+	if err != nil {
+		t.Fatalf("execIntrospectionQuery() error = %v", err)
+	}
+	want := map[string]interface{}{"__type": map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"name": "born_at"}, map[string]interface{}{"name": "id"}, map[string]interface{}{"name": "posts"},
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("execIntrospectionQuery() = %v, want %v", got, want)
+	}
+
+	graph.SetIntrospection(true)
+	if _, err := graph.execIntrospectionQuery(context.Background(), &model.GraphQLRequest{Query: query}); err == nil {
+		t.Errorf("execIntrospectionQuery() did not reject the query when introspection is disabled")
+	}
+}
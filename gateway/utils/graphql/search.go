@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// SetSearchModule sets the module used for full text searches
+func (graph *Module) SetSearchModule(s SearchInterface) {
+	graph.search = s
+}
+
+func (graph *Module) execSearchRequest(ctx context.Context, dbAlias, col, token string, req *model.SearchRequest, cb dbCallback) {
+	if graph.search == nil {
+		cb("", "", nil, errors.New("full text search is not available"))
+		return
+	}
+
+	go func() {
+		result, err := graph.search.Search(ctx, dbAlias, col, token, req)
+		if err != nil {
+			cb("", "", nil, err)
+			return
+		}
+		cb(dbAlias, col, result, nil)
+	}()
+}
+
+// getSearchRequest returns the full text search described by the search argument of a query. The argument is either
+// the search query itself or an object with the query, fields, limit, offset and highlight keys.
+func getSearchRequest(ctx context.Context, field *ast.Field, store utils.M) (*model.SearchRequest, bool, error) {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "search" {
+			continue
+		}
+
+		val, err := utils.ParseGraphqlValue(arg.Value, store)
+		if err != nil {
+			return nil, false, err
+		}
+
+		req := new(model.SearchRequest)
+		switch v := val.(type) {
+		case string:
+			req.Query = v
+		case map[string]interface{}:
+			if err := mapstructure.Decode(v, req); err != nil {
+				return nil, false, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid search argument provided", err, nil)
+			}
+		default:
+			return nil, false, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Field (search) should either be a string or an object", nil, nil)
+		}
+		return req, true, nil
+	}
+	return nil, false, nil
+}
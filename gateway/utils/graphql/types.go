@@ -11,6 +11,7 @@ type CrudInterface interface {
 	Create(ctx context.Context, dbAlias, collection string, request *model.CreateRequest, params model.RequestParams) error
 	Read(ctx context.Context, dbAlias, collection string, request *model.ReadRequest, params model.RequestParams) (interface{}, *model.SQLMetaData, error)
 	Update(ctx context.Context, dbAlias, collection string, request *model.UpdateRequest, params model.RequestParams) error
+	Increment(ctx context.Context, dbAlias, collection string, request *model.IncrementRequest, params model.RequestParams) ([]interface{}, error)
 	Delete(ctx context.Context, dbAlias, collection string, request *model.DeleteRequest, params model.RequestParams) error
 	Batch(ctx context.Context, dbAlias string, req *model.BatchRequest, params model.RequestParams) error
 	GetDBType(dbAlias string) (string, error)
@@ -37,4 +38,10 @@ type FunctionInterface interface {
 // SchemaInterface is an interface consisting of functions of schema module used by graphql module
 type SchemaInterface interface {
 	GetSchema(dbAlias, col string) (model.Fields, bool)
+	GetSchemas() model.Type
+}
+
+// SearchInterface is an interface consisting of functions of search module used by graphql module
+type SearchInterface interface {
+	Search(ctx context.Context, dbAlias, col, token string, req *model.SearchRequest) ([]interface{}, error)
 }
@@ -2627,10 +2627,11 @@ var queryTestCases = []tests{
 						Select: map[string]int32{"trainers.id": 1, "trainers.name": 1, "pokemons.id": 1, "pokemons.name": 1},
 						Join: []*model.JoinOption{
 							{
-								Op:    utils.All,
-								Type:  "LEFT",
-								On:    map[string]interface{}{"trainers.id": "pokemons.trainer_id"},
-								Table: "pokemons",
+								Op:       utils.All,
+								Type:     "LEFT",
+								On:       map[string]interface{}{"trainers.id": "pokemons.trainer_id"},
+								Table:    "pokemons",
+								Strategy: model.JoinStrategyJSON,
 							},
 						},
 					},
@@ -2710,10 +2711,11 @@ var queryTestCases = []tests{
 						Select:     map[string]int32{"trainers.id": 1, "trainers.name": 1, "pokemons.id": 1, "pokemons.name": 1},
 						Join: []*model.JoinOption{
 							{
-								Op:    utils.All,
-								Type:  "LEFT",
-								On:    map[string]interface{}{"trainers.id": "pokemons.trainer_id"},
-								Table: "pokemons",
+								Op:       utils.All,
+								Type:     "LEFT",
+								On:       map[string]interface{}{"trainers.id": "pokemons.trainer_id"},
+								Table:    "pokemons",
+								Strategy: model.JoinStrategyRows,
 							},
 						},
 						Sort: []string{"pokemons.name"},
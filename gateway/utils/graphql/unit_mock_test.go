@@ -24,6 +24,10 @@ func (m *mockGraphQLCrudInterface) Update(ctx context.Context, dbAlias, collecti
 	args := m.Called(ctx, dbAlias, collection, request, params)
 	return args.Error(0)
 }
+func (m *mockGraphQLCrudInterface) Increment(ctx context.Context, dbAlias, collection string, request *model.IncrementRequest, params model.RequestParams) ([]interface{}, error) {
+	args := m.Called(ctx, dbAlias, collection, request, params)
+	return args.Get(0).([]interface{}), args.Error(1)
+}
 func (m *mockGraphQLCrudInterface) Delete(ctx context.Context, dbAlias, collection string, request *model.DeleteRequest, params model.RequestParams) error {
 	args := m.Called(ctx, dbAlias, collection, request, params)
 	return args.Error(0)
@@ -100,3 +104,8 @@ func (m *mockGraphQLSchemaInterface) GetSchema(dbAlias, col string) (model.Field
 	args := m.Called(dbAlias, col)
 	return args.Get(0).(model.Fields), args.Bool(1)
 }
+
+func (m *mockGraphQLSchemaInterface) GetSchemas() model.Type {
+	args := m.Called()
+	return args.Get(0).(model.Type)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,6 +12,10 @@ import (
 
 	"github.com/rs/cors"
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
 // HTTPRequest describes the request object
@@ -26,10 +32,80 @@ type headers interface {
 
 // MakeHTTPRequest fires an http request and returns a response
 func MakeHTTPRequest(ctx context.Context, request *HTTPRequest, vPtr interface{}) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "HTTP "+request.Method, trace.SpanKindClient, label.String("http.method", request.Method), label.String("http.url", request.URL))
+	status, err := makeHTTPRequest(ctx, request, vPtr)
+	span.SetAttributes(label.Int("http.status_code", status))
+	tracing.EndSpan(ctx, span, err)
+	return status, err
+}
+
+func makeHTTPRequest(ctx context.Context, request *HTTPRequest, vPtr interface{}) (int, error) {
+	resp, err := doHTTPRequest(ctx, request)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer CloseTheCloser(resp.Body)
+
+	if resp.StatusCode != 204 {
+		if err := json.NewDecoder(resp.Body).Decode(vPtr); err != nil {
+			return resp.StatusCode, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to json unmarshal response of http request url (%s)", request.URL), err, nil)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// MakeStreamingHTTPRequest fires an http request whose response may be streamed. A streamed response has the
+// `application/x-ndjson` content type and each of its lines is passed to onLine as it arrives. Other responses are
+// unmarshalled into vPtr like MakeHTTPRequest.
+func MakeStreamingHTTPRequest(ctx context.Context, request *HTTPRequest, vPtr interface{}, onLine func(line []byte) error) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "HTTP "+request.Method, trace.SpanKindClient, label.String("http.method", request.Method), label.String("http.url", request.URL))
+	status, err := makeStreamingHTTPRequest(ctx, request, vPtr, onLine)
+	span.SetAttributes(label.Int("http.status_code", status))
+	tracing.EndSpan(ctx, span, err)
+	return status, err
+}
+
+func makeStreamingHTTPRequest(ctx context.Context, request *HTTPRequest, vPtr interface{}, onLine func(line []byte) error) (int, error) {
+	resp, err := doHTTPRequest(ctx, request)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer CloseTheCloser(resp.Body)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/x-ndjson") {
+		if resp.StatusCode != 204 {
+			if err := json.NewDecoder(resp.Body).Decode(vPtr); err != nil {
+				return resp.StatusCode, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to json unmarshal response of http request url (%s)", request.URL), err, nil)
+			}
+		}
+		return resp.StatusCode, nil
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := onLine(line); err != nil {
+			return resp.StatusCode, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to process streamed response of http request url (%s)", request.URL), err, nil)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return resp.StatusCode, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read streamed response of http request url (%s)", request.URL), err, nil)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// doHTTPRequest fires the http request. The caller must close the body of the response.
+func doHTTPRequest(ctx context.Context, request *HTTPRequest) (*http.Response, error) {
 	// Make a request object
 	req, err := http.NewRequestWithContext(ctx, request.Method, request.URL, request.Params)
 	if err != nil {
-		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create http request for url (%s)", request.URL), err, nil)
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create http request for url (%s)", request.URL), err, nil)
 	}
 
 	// Add the token only if its provided
@@ -47,23 +123,19 @@ func MakeHTTPRequest(ctx context.Context, request *HTTPRequest, vPtr interface{}
 		request.Headers.UpdateHeader(req.Header)
 	}
 
+	// Propagate the trace context and the request id
+	tracing.Inject(ctx, req.Header)
+	InjectRequestID(ctx, req.Header)
+
 	// Create a http client and fire the request
 	client := &http.Client{}
 
 	req = req.WithContext(ctx)
 	resp, err := client.Do(req)
 	if err != nil {
-		return http.StatusInternalServerError, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to make http request for url (%s)", request.URL), err, nil)
-	}
-	defer CloseTheCloser(resp.Body)
-
-	if resp.StatusCode != 204 {
-		if err := json.NewDecoder(resp.Body).Decode(vPtr); err != nil {
-			return resp.StatusCode, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to json unmarshal response of http request url (%s)", request.URL), err, nil)
-		}
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to make http request for url (%s)", request.URL), err, nil)
 	}
-
-	return resp.StatusCode, nil
+	return resp, nil
 }
 
 // GetTokenFromHeader returns the token from the request header
@@ -90,8 +162,8 @@ func CreateCorsObject() *cors.Cors {
 			return true
 		},
 		AllowedMethods: []string{"GET", "PUT", "POST", "DELETE"},
-		AllowedHeaders: []string{"Authorization", "Content-Type"},
-		ExposedHeaders: []string{"Authorization", "Content-Type"},
+		AllowedHeaders: []string{"Authorization", "Content-Type", HeaderDebugRules, HeaderIdempotencyKey},
+		ExposedHeaders: []string{"Authorization", "Content-Type", HeaderIdempotentReplayed, helpers.HeaderRequestID},
 	})
 }
 
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMakeStreamingHTTPRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantLines   []string
+		wantResult  interface{}
+	}{
+		{
+			name:        "streamed response",
+			contentType: "application/x-ndjson",
+			body:        "{\"type\":\"progress\",\"progress\":0.5}\n\n{\"type\":\"result\",\"data\":1}\n",
+			wantLines:   []string{`{"type":"progress","progress":0.5}`, `{"type":"result","data":1}`},
+		},
+		{
+			name:        "regular response",
+			contentType: "application/json",
+			body:        `{"ack":true}`,
+			wantResult:  map[string]interface{}{"ack": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var lines []string
+			var result interface{}
+			status, err := MakeStreamingHTTPRequest(context.Background(), &HTTPRequest{Method: http.MethodPost, URL: server.URL}, &result, func(line []byte) error {
+				lines = append(lines, string(line))
+				return nil
+			})
+			if err != nil || status != http.StatusOK {
+				t.Fatalf("MakeStreamingHTTPRequest() status = %v, error = %v", status, err)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("MakeStreamingHTTPRequest() lines = %v, want %v", lines, tt.wantLines)
+			}
+			if !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("MakeStreamingHTTPRequest() result = %v, want %v", result, tt.wantResult)
+			}
+		})
+	}
+}
@@ -21,6 +21,9 @@ type JWT struct {
 	jwkSecrets           map[string]*jwkSecret
 	closeJwkRoutineChan  chan struct{}
 	mapJwkKidToSecretKid map[string]string
+
+	// isRevoked checks if the token having the claims has been revoked
+	isRevoked func(claims map[string]interface{}) bool
 }
 
 type jwkSecret struct {
@@ -33,6 +36,9 @@ type jwkSecret struct {
 
 const defaultRefreshTime = 1 * time.Hour
 
+// DefaultExpiry is the time the tokens created without an expiry are valid for
+const DefaultExpiry = 30 * time.Minute
+
 // New initializes the package
 func New() *JWT {
 	ch := make(chan struct{}, 1)
@@ -56,6 +62,14 @@ func New() *JWT {
 	return j
 }
 
+// SetIsRevoked sets the function checking if a token has been revoked. Revoked tokens fail to parse.
+func (j *JWT) SetIsRevoked(fn func(claims map[string]interface{}) bool) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.isRevoked = fn
+}
+
 // SetSecrets set the internal fields of jwt struct
 func (j *JWT) SetSecrets(secrets []*config.Secret) error {
 	j.lock.Lock()
@@ -8,13 +8,58 @@ import (
 
 	"github.com/golang-jwt/jwt"
 	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 
 	"github.com/spaceuptech/space-cloud/gateway/config"
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/plugins"
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
 )
 
-// ParseToken verifies the token
+// ParseToken verifies the token. Tokens which cannot be verified using the configured secrets are
+// handed over to the auth providers registered by the embedding program.
 func (j *JWT) ParseToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "auth.parse_token", trace.SpanKindInternal)
+
+	claims, err := j.parseNativeToken(ctx, token)
+	if err == nil {
+		err = j.checkRevoked(ctx, claims)
+		tracing.EndSpan(ctx, span, err)
+		if err != nil {
+			return nil, err
+		}
+		return claims, nil
+	}
+
+	if pluginClaims, ok, pluginErr := plugins.ParseToken(ctx, token); ok {
+		span.SetAttributes(label.Bool("auth.plugin", true))
+		if pluginErr == nil {
+			pluginErr = j.checkRevoked(ctx, pluginClaims)
+		}
+		tracing.EndSpan(ctx, span, pluginErr)
+		if pluginErr != nil {
+			return nil, pluginErr
+		}
+		return pluginClaims, nil
+	}
+
+	tracing.EndSpan(ctx, span, err)
+	return nil, err
+}
+
+func (j *JWT) checkRevoked(ctx context.Context, claims map[string]interface{}) error {
+	j.lock.RLock()
+	isRevoked := j.isRevoked
+	j.lock.RUnlock()
+
+	if isRevoked != nil && isRevoked(claims) {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Token has been revoked", nil, nil)
+	}
+	return nil
+}
+
+func (j *JWT) parseNativeToken(ctx context.Context, token string) (map[string]interface{}, error) {
 	j.lock.RLock()
 	defer j.lock.RUnlock()
 
@@ -68,6 +113,11 @@ func (j *JWT) ParseToken(ctx context.Context, token string) (map[string]interfac
 
 // CreateToken create a token with primary secret
 func (j *JWT) CreateToken(ctx context.Context, tokenClaims model.TokenClaims) (string, error) {
+	return j.CreateTokenWithExpiry(ctx, tokenClaims, DefaultExpiry)
+}
+
+// CreateTokenWithExpiry create a token with primary secret which expires after the provided duration
+func (j *JWT) CreateTokenWithExpiry(ctx context.Context, tokenClaims model.TokenClaims, expiresIn time.Duration) (string, error) {
 	j.lock.RLock()
 	defer j.lock.RUnlock()
 
@@ -77,8 +127,7 @@ func (j *JWT) CreateToken(ctx context.Context, tokenClaims model.TokenClaims) (s
 	}
 	var tokenString string
 	var err error
-	// Add expiry of one week
-	claims["exp"] = time.Now().Add(30 * time.Minute).Unix()
+	claims["exp"] = time.Now().Add(expiresIn).Unix()
 	for _, s := range j.staticSecrets {
 		if s.IsPrimary {
 			switch s.Alg {
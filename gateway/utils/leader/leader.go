@@ -18,14 +18,33 @@ const leaderTime = 10 * time.Second // time for which a gateway can be a leader
 type Module struct {
 	lock sync.RWMutex
 
-	pubsubClient *pubsub.Module
+	pubsubClient pubsubInterface
 	nodeID       string
 	cbs          map[string]func()
+	lostCbs      map[string]func()
+
+	// isLeader is set while this node holds the leader position. lastRenewed is the time at which the
+	// position was last acquired or renewed.
+	isLeader    bool
+	lastRenewed time.Time
+
+	// done is closed once the node resigns
+	done chan struct{}
+}
+
+// pubsubInterface is the subset of the pubsub module used for the leader election
+type pubsubInterface interface {
+	SetKeyIfNotExists(ctx context.Context, key, value string, t time.Duration) (bool, error)
+	CompareAndRenewKey(ctx context.Context, key, value string, t time.Duration) (bool, error)
+	DeleteKeyOnMatch(ctx context.Context, key, value string) error
+	GetKey(ctx context.Context, key string) (string, error)
 }
 
+var _ pubsubInterface = (*pubsub.Module)(nil)
+
 // New initializes leader module
 func New(nodeID string, module *pubsub.Module) *Module {
-	m := &Module{pubsubClient: module, nodeID: nodeID, cbs: map[string]func(){}}
+	m := newModule(nodeID, module)
 
 	// Start the background routines
 	go m.applyForLeaderPosition()
@@ -34,18 +53,72 @@ func New(nodeID string, module *pubsub.Module) *Module {
 	return m
 }
 
-// AddCallBack adds a call back function
+func newModule(nodeID string, client pubsubInterface) *Module {
+	return &Module{pubsubClient: client, nodeID: nodeID, cbs: map[string]func(){}, lostCbs: map[string]func(){}, done: make(chan struct{})}
+}
+
+// AddCallBack adds a call back function which gets invoked whenever this node becomes the leader. It is
+// invoked right away if this node is already the leader.
 func (s *Module) AddCallBack(id string, cb func()) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.cbs[id] = cb
+	if s.isLeader {
+		go cb()
+	}
 }
 
-// RemoveCallBack removes a call back function
+// AddLostCallBack adds a call back function which gets invoked whenever this node loses the leader position
+func (s *Module) AddLostCallBack(id string, cb func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lostCbs[id] = cb
+}
+
+// RemoveCallBack removes the call back functions registered with the id
 func (s *Module) RemoveCallBack(id string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	delete(s.cbs, id)
+	delete(s.lostCbs, id)
+}
+
+// IsLeaderNode returns true if this node currently holds the leader position
+func (s *Module) IsLeaderNode() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.isLeader
+}
+
+// setLeader updates the role of this node and invokes the callbacks if the role has changed
+func (s *Module) setLeader(isLeader bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if isLeader {
+		// Ignore elections which complete after resigning
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		s.lastRenewed = time.Now()
+	}
+	if s.isLeader == isLeader {
+		return
+	}
+	s.isLeader = isLeader
+
+	cbs := s.lostCbs
+	if isLeader {
+		helpers.Logger.LogInfo("leader", "Selected as leader", map[string]interface{}{"nodeId": s.nodeID})
+		cbs = s.cbs
+	} else {
+		helpers.Logger.LogInfo("leader", "Lost the leader position", map[string]interface{}{"nodeId": s.nodeID})
+	}
+	for _, cb := range cbs {
+		go cb()
+	}
 }
 
 func (s *Module) applyForLeaderPosition() {
@@ -54,8 +127,13 @@ func (s *Module) applyForLeaderPosition() {
 	defer ticker.Stop()
 
 	s.applyForLeader()
-	for range ticker.C {
-		s.applyForLeader()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.applyForLeader()
+		}
 	}
 }
 
@@ -68,13 +146,7 @@ func (s *Module) applyForLeader() {
 
 	// If key has been set in redis, that means you have become the leader
 	if isKeySet {
-		helpers.Logger.LogDebug("applyForLeaderPosition", "Selected as leader", map[string]interface{}{"nodeId": s.nodeID})
-		// execute all the callbacks
-		s.lock.RLock()
-		for _, cb := range s.cbs {
-			go cb()
-		}
-		s.lock.RUnlock()
+		s.setLeader(true)
 	}
 }
 
@@ -83,10 +155,33 @@ func (s *Module) renewYourLeaderPosition() {
 	ticker := time.NewTicker(3 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if err := s.pubsubClient.RenewKeyTTLOnMatch(context.Background(), leaderElectionRedisKey, s.nodeID, leaderTime); err != nil {
-			helpers.Logger.LogDebug("renewYourLeaderPosition", "Unable to renew leader position", map[string]interface{}{"key": leaderElectionRedisKey, "nodeId": s.nodeID})
-			continue
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.renewLeaderPosition()
+		}
+	}
+}
+
+func (s *Module) renewLeaderPosition() {
+	if !s.IsLeaderNode() {
+		return
+	}
+
+	isRenewed, err := s.pubsubClient.CompareAndRenewKey(context.Background(), leaderElectionRedisKey, s.nodeID, leaderTime)
+	if err != nil {
+		helpers.Logger.LogDebug("renewYourLeaderPosition", "Unable to renew leader position", map[string]interface{}{"key": leaderElectionRedisKey, "nodeId": s.nodeID})
+
+		// Another node could have taken over once the position expires
+		s.lock.RLock()
+		isExpired := time.Since(s.lastRenewed) >= leaderTime
+		s.lock.RUnlock()
+		if isExpired {
+			s.setLeader(false)
 		}
+		return
 	}
+	s.setLeader(isRenewed)
 }
@@ -0,0 +1,145 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakePubsub struct {
+	lock  sync.Mutex
+	value string
+	err   error
+}
+
+func (f *fakePubsub) SetKeyIfNotExists(_ context.Context, _, value string, _ time.Duration) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.value != "" {
+		return false, nil
+	}
+	f.value = value
+	return true, nil
+}
+
+func (f *fakePubsub) CompareAndRenewKey(_ context.Context, _, value string, _ time.Duration) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.value == value, nil
+}
+
+func (f *fakePubsub) DeleteKeyOnMatch(_ context.Context, _, value string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.value == value {
+		f.value = ""
+	}
+	return nil
+}
+
+func (f *fakePubsub) GetKey(context.Context, string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.value, nil
+}
+
+func (f *fakePubsub) set(value string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.value, f.err = value, err
+}
+
+func expectCall(t *testing.T, ch chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if got != want {
+			t.Fatalf("callback = %s, want %s", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("callback (%s) was not invoked", want)
+	}
+}
+
+func expectNoCall(t *testing.T, ch chan string) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpected callback (%s) invoked", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestModule_LeadershipCallbacks(t *testing.T) {
+	client := &fakePubsub{}
+	m := newModule("node-1", client)
+
+	calls := make(chan string, 10)
+	m.AddCallBack("cron", func() { calls <- "become" })
+	m.AddLostCallBack("cron", func() { calls <- "lose" })
+
+	m.applyForLeader()
+	expectCall(t, calls, "become")
+	if !m.IsLeaderNode() {
+		t.Fatalf("IsLeaderNode() = false after winning the election")
+	}
+
+	// Renewing the position doesn't invoke the callbacks again
+	m.renewLeaderPosition()
+	m.applyForLeader()
+	expectNoCall(t, calls)
+
+	// Callbacks registered by a leader are invoked right away
+	late := make(chan string, 1)
+	m.AddCallBack("late", func() { late <- "become" })
+	expectCall(t, late, "become")
+
+	// Losing the key to another node
+	client.set("node-2", nil)
+	m.renewLeaderPosition()
+	expectCall(t, calls, "lose")
+	if m.IsLeaderNode() {
+		t.Fatalf("IsLeaderNode() = true after losing the position")
+	}
+
+	// Temporary errors don't cost the leader position till it expires
+	client.set("", nil)
+	m.applyForLeader()
+	expectCall(t, calls, "become")
+	client.set("node-1", errors.New("unreachable"))
+	m.renewLeaderPosition()
+	expectNoCall(t, calls)
+	m.lock.Lock()
+	m.lastRenewed = time.Now().Add(-leaderTime)
+	m.lock.Unlock()
+	m.renewLeaderPosition()
+	expectCall(t, calls, "lose")
+
+	// Resigning gives up the position
+	client.set("", nil)
+	m.applyForLeader()
+	expectCall(t, calls, "become")
+	m.RemoveCallBack("late")
+	if err := m.Resign(context.Background()); err != nil {
+		t.Fatalf("Resign() error = %v", err)
+	}
+	expectCall(t, calls, "lose")
+	if value, _ := client.GetKey(context.Background(), leaderElectionRedisKey); value != "" {
+		t.Errorf("Resign() did not delete the leader key")
+	}
+
+	// Elections completing after resigning are ignored
+	m.applyForLeader()
+	expectNoCall(t, calls)
+	if m.IsLeaderNode() {
+		t.Errorf("IsLeaderNode() = true after resigning")
+	}
+}
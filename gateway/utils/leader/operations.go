@@ -33,3 +33,24 @@ func (s *Module) IsLeader(ctx context.Context, nodeID string) (bool, error) {
 
 	return nodeID == value, nil
 }
+
+// Resign stops participating in the leader election and gives up the leader position if this node holds it,
+// so that another node can take over without waiting for the position to expire
+func (s *Module) Resign(ctx context.Context) error {
+	s.lock.Lock()
+	select {
+	case <-s.done:
+		s.lock.Unlock()
+		return nil
+	default:
+		close(s.done)
+	}
+	s.lock.Unlock()
+
+	err := s.pubsubClient.DeleteKeyOnMatch(ctx, leaderElectionRedisKey, s.nodeID)
+	s.setLeader(false)
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// The formats the findings can be written in
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "space-cloud-lint"
+)
+
+// Write writes the findings for the config file in the provided format
+func Write(w io.Writer, format, file, version string, findings []*Finding) error {
+	switch format {
+	case FormatText:
+		return writeText(w, file, findings)
+	case FormatJSON:
+		return writeJSON(w, map[string]interface{}{"file": file, "findings": findings})
+	case FormatSARIF:
+		return writeJSON(w, toSARIF(file, version, findings))
+	default:
+		return fmt.Errorf("invalid format (%s) provided - it must be one of text, json or sarif", format)
+	}
+}
+
+func writeText(w io.Writer, file string, findings []*Finding) error {
+	for _, f := range findings {
+		location := file
+		if f.Project != "" {
+			location += ": " + f.Project
+		}
+		if f.Resource != "" {
+			location += ": " + f.Resource
+		}
+		if _, err := fmt.Fprintf(w, "%s [%s] %s: %s\n", location, f.Severity, f.Rule, f.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d findings\n", len(findings))
+	return err
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string       `json:"name"`
+	Version string       `json:"version,omitempty"`
+	Rules   []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	RuleIndex int              `json:"ruleIndex"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation   `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// toSARIF converts the findings to a sarif log. The config doesn't keep the lines of the resources, hence the
+// resources are reported as logical locations within the config file.
+func toSARIF(file, version string, findings []*Finding) *sarifLog {
+	rules := make([]*sarifRule, len(Rules))
+	ruleIndexes := make(map[string]int, len(Rules))
+	for i, r := range Rules {
+		rules[i] = &sarifRule{ID: r.ID, ShortDescription: sarifMessage{Text: r.Description}, DefaultConfiguration: sarifConfiguration{Level: getSARIFLevel(r.Severity)}}
+		ruleIndexes[r.ID] = i
+	}
+
+	results := make([]*sarifResult, len(findings))
+	for i, f := range findings {
+		location := &sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: file}}}
+		switch {
+		case f.Resource != "":
+			location.LogicalLocations = []*sarifLogicalLocation{{FullyQualifiedName: f.Resource, Kind: "resource"}}
+		case f.Project != "":
+			location.LogicalLocations = []*sarifLogicalLocation{{FullyQualifiedName: f.Project, Kind: "project"}}
+		}
+		results[i] = &sarifResult{RuleID: f.Rule, RuleIndex: ruleIndexes[f.Rule], Level: getSARIFLevel(f.Severity), Message: sarifMessage{Text: f.Message}, Locations: []*sarifLocation{location}}
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []*sarifRun{{Tool: sarifTool{Driver: sarifDriver{Name: toolName, Version: version, Rules: rules}}, Results: results}},
+	}
+}
+
+func getSARIFLevel(severity string) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,286 @@
+package lint
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+	"github.com/spaceuptech/space-cloud/gateway/model"
+	schemaHelpers "github.com/spaceuptech/space-cloud/gateway/modules/schema/helpers"
+	"github.com/spaceuptech/space-cloud/gateway/modules/slo"
+	"github.com/spaceuptech/space-cloud/gateway/utils"
+)
+
+// The severities of the findings, the most severe first
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+var severityRanks = map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// Rule is a check performed on the config
+type Rule struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// Finding is a violation of a rule found in the config
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Project  string `json:"project,omitempty"`
+	// Resource is the id of the resource in the config the finding belongs to
+	Resource string `json:"resource,omitempty"`
+}
+
+// Rules are all the checks performed by the linter
+var Rules = []*Rule{
+	{ID: "resource-id", Severity: SeverityError, Description: "Resource ids must be of the form cluster--project--type--id and belong to their project"},
+	{ID: "project-id", Severity: SeverityError, Description: "The id of a project must match the key it is stored under"},
+	{ID: "project-secrets", Severity: SeverityError, Description: "Projects need a valid secret to verify the tokens of their users"},
+	{ID: "primary-secret", Severity: SeverityWarning, Description: "Projects should have exactly one primary secret to sign tokens with"},
+	{ID: "database-type", Severity: SeverityError, Description: "Databases must be of a supported type"},
+	{ID: "database-conn", Severity: SeverityError, Description: "Enabled databases need a connection string"},
+	{ID: "database-disabled", Severity: SeverityInfo, Description: "Disabled databases reject all requests"},
+	{ID: "unknown-database", Severity: SeverityError, Description: "Rules, schemas, prepared queries, triggers and eventing must refer to a configured database"},
+	{ID: "invalid-schema", Severity: SeverityError, Description: "Schemas must be valid graphql type definitions"},
+	{ID: "open-write-rule", Severity: SeverityWarning, Description: "Collections which anyone can write to are rarely intended in production"},
+	{ID: "eventing-trigger", Severity: SeverityError, Description: "Triggers need a url and the database triggers need a collection"},
+	{ID: "read-only-database", Severity: SeverityError, Description: "Eventing and database triggers need a database which can be written to"},
+	{ID: "remote-service-url", Severity: SeverityError, Description: "Remote services need a url"},
+	{ID: "invalid-slo", Severity: SeverityError, Description: "Service level objectives must have a valid objective and alert windows"},
+}
+
+var rulesByID = func() map[string]*Rule {
+	m := make(map[string]*Rule, len(Rules))
+	for _, r := range Rules {
+		m[r.ID] = r
+	}
+	return m
+}()
+
+var supportedDBTypes = map[string]bool{
+	string(model.Mongo): true, string(model.EmbeddedDB): true, string(model.MySQL): true, string(model.Postgres): true, string(model.SQLServer): true,
+	string(model.Redis): true, string(model.BigQuery): true, string(model.ClickHouse): true,
+}
+
+var dbEvents = map[string]bool{utils.EventDBCreate: true, utils.EventDBUpdate: true, utils.EventDBDelete: true}
+
+var writeOps = []string{"create", "update", "delete"}
+
+type linter struct {
+	findings []*Finding
+	project  string
+}
+
+func (l *linter) add(rule, resource, format string, a ...interface{}) {
+	l.findings = append(l.findings, &Finding{Rule: rule, Severity: rulesByID[rule].Severity, Message: fmt.Sprintf(format, a...), Project: l.project, Resource: resource})
+}
+
+// Lint checks the config without connecting to any of the databases or services it refers to. The findings are
+// sorted by their severity, the most severe first.
+func Lint(c *config.Config) []*Finding {
+	l := new(linter)
+	projects := make([]string, 0, len(c.Projects))
+	for id := range c.Projects {
+		projects = append(projects, id)
+	}
+	sort.Strings(projects)
+
+	for _, id := range projects {
+		l.project = id
+		l.lintProject(id, c.Projects[id])
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return severityRanks[l.findings[i].Severity] < severityRanks[l.findings[j].Severity]
+	})
+	return l.findings
+}
+
+// HasFindings checks if any of the findings is at least as severe as the provided severity
+func HasFindings(findings []*Finding, severity string) bool {
+	for _, f := range findings {
+		if severityRanks[f.Severity] <= severityRanks[severity] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidSeverity checks if the severity is one of the severities of the findings
+func IsValidSeverity(severity string) bool {
+	_, p := severityRanks[severity]
+	return p
+}
+
+func (l *linter) lintProject(id string, p *config.Project) {
+	if p == nil {
+		return
+	}
+	if p.ProjectConfig == nil || p.ProjectConfig.ID != id {
+		l.add("project-id", "", "project is stored under (%s) but has a different id in its project config", id)
+	}
+	if p.ProjectConfig != nil {
+		l.lintSecrets(p.ProjectConfig)
+		if err := slo.ValidateConfig(p.ProjectConfig.SLOs); err != nil {
+			l.add("invalid-slo", "", "%v", err)
+		}
+	}
+
+	for _, resourceID := range sortedKeys(p.DatabaseConfigs) {
+		l.lintResourceID(resourceID)
+		db := p.DatabaseConfigs[resourceID]
+		switch {
+		case !supportedDBTypes[db.Type]:
+			l.add("database-type", resourceID, "database (%s) has an unsupported type (%s)", db.DbAlias, db.Type)
+		case !db.Enabled:
+			l.add("database-disabled", resourceID, "database (%s) is disabled", db.DbAlias)
+		case db.Conn == "":
+			l.add("database-conn", resourceID, "database (%s) is enabled but has no connection string", db.DbAlias)
+		}
+	}
+
+	for _, resourceID := range sortedKeys(p.DatabaseSchemas) {
+		l.lintResourceID(resourceID)
+		s := p.DatabaseSchemas[resourceID]
+		l.lintDBAlias(p, resourceID, s.DbAlias, "schema of collection (%s)", s.Table)
+		parsed, err := schemaHelpers.Parser(config.DatabaseSchemas{resourceID: s})
+		if err != nil {
+			l.add("invalid-schema", resourceID, "schema of collection (%s) is invalid - %v", s.Table, err)
+			continue
+		}
+		l.lintLinks(p, resourceID, s, parsed[s.DbAlias][s.Table])
+	}
+
+	for _, resourceID := range sortedKeys(p.DatabaseRules) {
+		l.lintResourceID(resourceID)
+		r := p.DatabaseRules[resourceID]
+		l.lintDBAlias(p, resourceID, r.DbAlias, "rules of collection (%s)", r.Table)
+		if r.Table == "default" {
+			continue
+		}
+		for _, op := range writeOps {
+			if rule, p := r.Rules[op]; p && rule != nil && rule.Rule == "allow" {
+				l.add("open-write-rule", resourceID, "anyone can %s the documents of collection (%s)", op, r.Table)
+			}
+		}
+	}
+
+	for _, resourceID := range sortedKeys(p.DatabasePreparedQueries) {
+		l.lintResourceID(resourceID)
+		q := p.DatabasePreparedQueries[resourceID]
+		l.lintDBAlias(p, resourceID, q.DbAlias, "prepared query (%s)", q.ID)
+	}
+
+	if p.EventingConfig != nil && p.EventingConfig.Enabled {
+		l.lintDBAlias(p, "", p.EventingConfig.DBAlias, "eventing")
+		l.lintWritableDB(p, "", p.EventingConfig.DBAlias, "eventing")
+	}
+	for _, resourceID := range sortedKeys(p.EventingTriggers) {
+		l.lintResourceID(resourceID)
+		t := p.EventingTriggers[resourceID]
+		if t.URL == "" {
+			l.add("eventing-trigger", resourceID, "trigger (%s) has no url", t.ID)
+		}
+		if dbEvents[t.Type] {
+			if t.Options["col"] == "" {
+				l.add("eventing-trigger", resourceID, "trigger (%s) of type (%s) has no collection", t.ID, t.Type)
+			}
+			l.lintDBAlias(p, resourceID, t.Options["db"], "trigger (%s)", t.ID)
+			l.lintWritableDB(p, resourceID, t.Options["db"], "trigger (%s)", t.ID)
+		}
+	}
+
+	for _, resourceID := range sortedKeys(p.RemoteService) {
+		l.lintResourceID(resourceID)
+		if s := p.RemoteService[resourceID]; s.URL == "" {
+			l.add("remote-service-url", resourceID, "remote service (%s) has no url", s.ID)
+		}
+	}
+}
+
+func (l *linter) lintSecrets(p *config.ProjectConfig) {
+	if len(p.Secrets) == 0 && p.SecretSource == "" {
+		l.add("project-secrets", "", "project has no secrets")
+		return
+	}
+
+	primary := 0
+	for _, s := range p.Secrets {
+		if s.IsPrimary {
+			primary++
+		}
+		switch s.Alg {
+		case config.HS256, "":
+			if s.Secret == "" {
+				l.add("project-secrets", "", "secret (%s) of algorithm HS256 is empty", s.KID)
+			}
+		case config.RS256:
+			if s.PublicKey == "" {
+				l.add("project-secrets", "", "secret (%s) of algorithm RS256 has no public key", s.KID)
+			}
+		}
+	}
+	if len(p.Secrets) > 0 && primary != 1 {
+		l.add("primary-secret", "", "project has %d primary secrets", primary)
+	}
+}
+
+// lintResourceID checks that the resource id has the format generated by config.GenerateResourceID
+func (l *linter) lintResourceID(resourceID string) {
+	arr := strings.Split(resourceID, "--")
+	if len(arr) < 4 {
+		l.add("resource-id", resourceID, "resource id (%s) isn't of the form cluster--project--type--id", resourceID)
+		return
+	}
+	if arr[1] != l.project {
+		l.add("resource-id", resourceID, "resource id (%s) belongs to project (%s)", resourceID, arr[1])
+	}
+}
+
+func (l *linter) lintDBAlias(p *config.Project, resourceID, dbAlias, format string, a ...interface{}) {
+	for _, db := range p.DatabaseConfigs {
+		if db.DbAlias == dbAlias {
+			return
+		}
+	}
+	l.add("unknown-database", resourceID, "%s refers to database (%s) which isn't configured", fmt.Sprintf(format, a...), dbAlias)
+}
+
+// lintLinks checks that the links of a collection to the collections of other databases refer to a configured database
+func (l *linter) lintLinks(p *config.Project, resourceID string, s *config.DatabaseSchema, fields model.Fields) {
+	for _, name := range sortedKeys(fields) {
+		field := fields[name]
+		if !field.IsLinked || field.LinkedTable == nil || field.LinkedTable.DBType == s.DbAlias {
+			continue
+		}
+		l.lintDBAlias(p, resourceID, field.LinkedTable.DBType, "link (%s) of collection (%s)", name, s.Table)
+	}
+}
+
+// lintWritableDB reports the use of a read only database where writes are needed
+func (l *linter) lintWritableDB(p *config.Project, resourceID, dbAlias, format string, a ...interface{}) {
+	for _, db := range p.DatabaseConfigs {
+		if db.DbAlias == dbAlias && model.IsReadOnlyDB(db.Type) {
+			l.add("read-only-database", resourceID, "%s refers to database (%s) which is read only", fmt.Sprintf(format, a...), dbAlias)
+		}
+	}
+}
+
+// sortedKeys returns the keys of a map with string keys in a sorted order
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	arr := make([]string, len(keys))
+	for i, key := range keys {
+		arr[i] = key.String()
+	}
+	sort.Strings(arr)
+	return arr
+}
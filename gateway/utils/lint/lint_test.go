@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+func newProject(id string) *config.Project {
+	return config.GenerateEmptyProject(&config.ProjectConfig{ID: id, Secrets: []*config.Secret{{KID: "1", Alg: config.HS256, Secret: "secret", IsPrimary: true}}})
+}
+
+func getRules(findings []*Finding) []string {
+	rules := make([]string, 0)
+	for _, f := range findings {
+		rules = append(rules, f.Rule)
+	}
+	return rules
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		project func(p *config.Project)
+		want    []string
+	}{
+		{name: "valid project", project: func(p *config.Project) {
+			p.DatabaseConfigs["c--p--db-config--db"] = &config.DatabaseConfig{DbAlias: "db", Type: "postgres", Conn: "postgres://", Enabled: true}
+			p.DatabaseSchemas["c--p--db-schema--db-users"] = &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: "type users { id: ID! @primary }"}
+			p.DatabaseRules["c--p--db-rule--db-users-rule"] = &config.DatabaseRule{Table: "users", DbAlias: "db", Rules: map[string]*config.Rule{"read": {Rule: "allow"}, "create": {Rule: "authenticated"}}}
+		}, want: []string{}},
+		{name: "database", project: func(p *config.Project) {
+			p.DatabaseConfigs["c--p--db-config--a"] = &config.DatabaseConfig{DbAlias: "a", Type: "oracle", Enabled: true}
+			p.DatabaseConfigs["c--p--db-config--b"] = &config.DatabaseConfig{DbAlias: "b", Type: "mongo", Enabled: true}
+			p.DatabaseConfigs["c--p--db-config--c"] = &config.DatabaseConfig{DbAlias: "c", Type: "mongo"}
+		}, want: []string{"database-type", "database-conn", "database-disabled"}},
+		{name: "references", project: func(p *config.Project) {
+			p.DatabaseSchemas["c--p--db-schema--db-users"] = &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: "type users {"}
+			p.DatabaseRules["c--other--db-rule--db-users-rule"] = &config.DatabaseRule{Table: "users", DbAlias: "db", Rules: map[string]*config.Rule{"delete": {Rule: "allow"}}}
+			p.EventingTriggers["c--p--eventing-triggers--t"] = &config.EventingTrigger{ID: "t", Type: "DB_INSERT", Options: map[string]string{"db": "db"}}
+		}, want: []string{"unknown-database", "invalid-schema", "resource-id", "unknown-database", "eventing-trigger", "eventing-trigger", "unknown-database", "open-write-rule"}},
+		{name: "links across databases", project: func(p *config.Project) {
+			p.DatabaseConfigs["c--p--db-config--db"] = &config.DatabaseConfig{DbAlias: "db", Type: "postgres", Conn: "postgres://", Enabled: true}
+			p.DatabaseConfigs["c--p--db-config--mongo"] = &config.DatabaseConfig{DbAlias: "mongo", Type: "mongo", Conn: "mongodb://", Enabled: true}
+			p.DatabaseSchemas["c--p--db-schema--db-users"] = &config.DatabaseSchema{Table: "users", DbAlias: "db", Schema: `type users { id: ID! @primary orders: [orders] @link(table: "orders", from: "id", to: "user_id", db: "mongo") carts: [carts] @link(table: "carts", from: "id", to: "user_id", db: "redis") }`}
+		}, want: []string{"unknown-database"}},
+		{name: "read only database", project: func(p *config.Project) {
+			p.DatabaseConfigs["c--p--db-config--db"] = &config.DatabaseConfig{DbAlias: "db", Type: "clickhouse", Conn: "http://localhost:8123", Enabled: true}
+			p.EventingTriggers["c--p--eventing-triggers--t"] = &config.EventingTrigger{ID: "t", Type: "DB_INSERT", URL: "http://localhost", Options: map[string]string{"db": "db", "col": "events"}}
+		}, want: []string{"read-only-database"}},
+		{name: "project config", project: func(p *config.Project) {
+			p.ProjectConfig.ID = "other"
+			p.ProjectConfig.Secrets = append(p.ProjectConfig.Secrets, &config.Secret{KID: "2", Alg: config.RS256, IsPrimary: true})
+			p.ProjectConfig.SLOs = []*config.SLOConfig{{ID: "slo", Objective: 2}}
+			p.RemoteService["c--p--remote-service--s"] = &config.Service{ID: "s"}
+		}, want: []string{"project-id", "project-secrets", "invalid-slo", "remote-service-url", "primary-secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProject("p")
+			tt.project(p)
+			conf := config.GenerateEmptyConfig()
+			conf.Projects["p"] = p
+
+			if got := getRules(Lint(conf)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasFindings(t *testing.T) {
+	findings := []*Finding{{Rule: "primary-secret", Severity: SeverityWarning}}
+	if HasFindings(findings, SeverityError) {
+		t.Errorf("HasFindings() = true, want false for a warning when failing on errors")
+	}
+	if !HasFindings(findings, SeverityWarning) || !HasFindings(findings, SeverityInfo) {
+		t.Errorf("HasFindings() = false, want true for a warning when failing on warnings")
+	}
+}
+
+func TestWrite_SARIF(t *testing.T) {
+	findings := []*Finding{{Rule: "open-write-rule", Severity: SeverityWarning, Message: "open", Project: "p", Resource: "c--p--db-rule--db-users-rule"}}
+
+	buf := new(bytes.Buffer)
+	if err := Write(buf, FormatSARIF, "config.yaml", "1.0.0", findings); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	log := new(sarifLog)
+	if err := json.Unmarshal(buf.Bytes(), log); err != nil {
+		t.Fatalf("Write() wrote invalid json - %v", err)
+	}
+	result := log.Runs[0].Results[0]
+	if result.Level != "warning" || Rules[result.RuleIndex].ID != "open-write-rule" || result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "config.yaml" || result.Locations[0].LogicalLocations[0].FullyQualifiedName != "c--p--db-rule--db-users-rule" {
+		t.Errorf("Write() result = %+v, want the finding", result)
+	}
+	if len(log.Runs[0].Tool.Driver.Rules) != len(Rules) {
+		t.Errorf("Write() wrote %d rules, want %d", len(log.Runs[0].Tool.Driver.Rules), len(Rules))
+	}
+
+	if err := Write(buf, "xml", "config.yaml", "1.0.0", findings); err == nil {
+		t.Errorf("Write() error = nil, want an error for an invalid format")
+	}
+}
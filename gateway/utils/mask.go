@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 
 	"github.com/spaceuptech/helpers"
 )
@@ -35,3 +37,48 @@ func encryptAESCFB(dst, src, key, iv []byte) error {
 	aesEncrypter.XORKeyStream(dst, src)
 	return nil
 }
+
+// Seal encrypts a value with aes-gcm using a random nonce and base64 encodes the nonce followed by the result. Unlike
+// Encrypt, sealing the same value twice gives different results which makes it suitable for storing secrets.
+func Seal(aesKey []byte, value string) (string, error) {
+	aead, err := newGCM(aesKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// Unseal decrypts a value sealed by Seal
+func Unseal(aesKey []byte, sealed string) (string, error) {
+	aead, err := newGCM(aesKey)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("sealed value is too short")
+	}
+
+	value, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -46,3 +46,28 @@ func base64DecodeString(key string) []byte {
 	decodedKey, _ := base64.StdEncoding.DecodeString(key)
 	return decodedKey
 }
+
+func TestSealUnseal(t *testing.T) {
+	key := base64DecodeString("Olw6AhA/GzSxfhwKLxO7JJsUL6VUwwGEFTgxzoZPy9g=")
+
+	sealed1, err := Seal(key, "password")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	sealed2, _ := Seal(key, "password")
+	if sealed1 == sealed2 {
+		t.Errorf("Seal() returned the same result twice - the nonce must be random")
+	}
+
+	value, err := Unseal(key, sealed1)
+	if err != nil || value != "password" {
+		t.Errorf("Unseal() = (%v, %v), want (password, nil)", value, err)
+	}
+
+	if _, err := Unseal(base64DecodeString("C2h6HX2HK/vQ7yFpGG+9NMbUZ2wKvG8jA8PmA9HF9Zk="), sealed1); err == nil {
+		t.Error("Unseal() error = nil, want error for the wrong key")
+	}
+	if _, err := Unseal(key, "c2hvcnQ="); err == nil {
+		t.Error("Unseal() error = nil, want error for a truncated value")
+	}
+}
@@ -2,13 +2,15 @@ package pubsub
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/go-redis/redis/v8"
 )
 
 type subscription struct {
-	ch     <-chan *redis.Message
-	pubsub *redis.PubSub
+	ch <-chan *redis.Message
+	// pubsub is the redis or the in memory subscription
+	pubsub io.Closer
 }
 
 func (m *Module) getTopicName(topic string) string {
@@ -0,0 +1,232 @@
+package pubsub
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// InMemoryConn is the connection string which makes the pub sub clients use a broker in the memory of this process
+// instead of redis. It is meant for the dev mode where a single gateway runs without any external dependencies.
+const InMemoryConn = "memory"
+
+const (
+	// memoryChannelSize and memorySendTimeout match the delivery of the go-redis subscriptions. A message is dropped
+	// for a subscriber which can't receive it within the timeout.
+	memoryChannelSize = 100
+	memorySendTimeout = time.Minute
+)
+
+// broker is shared by all the in memory clients of the process the same way the clients of a cluster share redis
+var broker = newMemoryBroker()
+
+// memoryBroker implements the subset of redis used by the pub sub module
+type memoryBroker struct {
+	lock sync.Mutex
+	subs map[string]map[*memorySubscription]struct{}
+	keys map[string]*memoryKey
+}
+
+type memoryKey struct {
+	value string
+	// expiresAt is zero for the keys which don't expire
+	expiresAt time.Time
+}
+
+type memorySubscription struct {
+	lock    sync.Mutex
+	broker  *memoryBroker
+	channel string
+	ch      chan *redis.Message
+	closed  bool
+}
+
+func newMemoryBroker() *memoryBroker {
+	return &memoryBroker{subs: map[string]map[*memorySubscription]struct{}{}, keys: map[string]*memoryKey{}}
+}
+
+func (b *memoryBroker) subscribe(channel string) *memorySubscription {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	sub := &memorySubscription{broker: b, channel: channel, ch: make(chan *redis.Message, memoryChannelSize)}
+	if _, p := b.subs[channel]; !p {
+		b.subs[channel] = map[*memorySubscription]struct{}{}
+	}
+	b.subs[channel][sub] = struct{}{}
+	return sub
+}
+
+func (b *memoryBroker) publish(channel, payload string) {
+	b.lock.Lock()
+	subs := make([]*memorySubscription, 0, len(b.subs[channel]))
+	for sub := range b.subs[channel] {
+		subs = append(subs, sub)
+	}
+	b.lock.Unlock()
+
+	for _, sub := range subs {
+		sub.send(&redis.Message{Channel: channel, Payload: payload})
+	}
+}
+
+func (s *memorySubscription) send(msg *redis.Message) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	timer := time.NewTimer(memorySendTimeout)
+	defer timer.Stop()
+	select {
+	case s.ch <- msg:
+	case <-timer.C:
+	}
+}
+
+// receive waits for the next message of the subscription
+func (s *memorySubscription) receive(ctx context.Context) (*redis.Message, error) {
+	select {
+	case msg, ok := <-s.ch:
+		if !ok {
+			return nil, redis.ErrClosed
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close unsubscribes from the channel and closes the channel of the messages like a redis subscription
+func (s *memorySubscription) Close() error {
+	s.broker.lock.Lock()
+	delete(s.broker.subs[s.channel], s)
+	if len(s.broker.subs[s.channel]) == 0 {
+		delete(s.broker.subs, s.channel)
+	}
+	s.broker.lock.Unlock()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+	return nil
+}
+
+// getKey returns the key if it exists and hasn't expired. It must be called with the lock held.
+func (b *memoryBroker) getKey(key string) (*memoryKey, bool) {
+	k, p := b.keys[key]
+	if !p {
+		return nil, false
+	}
+	if !k.expiresAt.IsZero() && !time.Now().Before(k.expiresAt) {
+		delete(b.keys, key)
+		return nil, false
+	}
+	return k, true
+}
+
+func newMemoryKey(value string, t time.Duration) *memoryKey {
+	k := &memoryKey{value: value}
+	if t > 0 {
+		k.expiresAt = time.Now().Add(t)
+	}
+	return k
+}
+
+func (b *memoryBroker) set(key, value string, t time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.keys[key] = newMemoryKey(value, t)
+}
+
+func (b *memoryBroker) setIfNotExists(key, value string, t time.Duration) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, p := b.getKey(key); p {
+		return false
+	}
+	b.keys[key] = newMemoryKey(value, t)
+	return true
+}
+
+// get returns redis.Nil for the keys which don't exist so that the callers handle both clients alike
+func (b *memoryBroker) get(key string) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	k, p := b.getKey(key)
+	if !p {
+		return "", redis.Nil
+	}
+	return k.value, nil
+}
+
+func (b *memoryBroker) delete(key string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.keys, key)
+}
+
+// ttl returns -2 for the keys which don't exist and -1 for the keys which don't expire like go-redis does
+func (b *memoryBroker) ttl(key string) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	k, p := b.getKey(key)
+	if !p {
+		return -2
+	}
+	if k.expiresAt.IsZero() {
+		return -1
+	}
+	return time.Until(k.expiresAt)
+}
+
+func (b *memoryBroker) getWithPrefix(prefix string) map[string]string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	result := map[string]string{}
+	for key := range b.keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if k, p := b.getKey(key); p {
+			result[key] = k.value
+		}
+	}
+	return result
+}
+
+func (b *memoryBroker) compareAndRenew(key, value string, t time.Duration) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	k, p := b.getKey(key)
+	if !p || k.value != value {
+		return false
+	}
+	b.keys[key] = newMemoryKey(value, t)
+	return true
+}
+
+func (b *memoryBroker) compareAndDelete(key, value string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	k, p := b.getKey(key)
+	if !p || k.value != value {
+		return false
+	}
+	delete(b.keys, key)
+	return true
+}
@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/spaceuptech/space-cloud/gateway/model"
+)
+
+func newInMemoryModule(projectID string, b *memoryBroker) *Module {
+	return &Module{memory: b, projectID: projectID, mapping: map[string]*subscription{}}
+}
+
+func TestModule_InMemorySend(t *testing.T) {
+	b := newMemoryBroker()
+	sender, receiver := newInMemoryModule("project", b), newInMemoryModule("project", b)
+
+	ch, err := receiver.Subscribe(context.Background(), "topic")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	go func() {
+		for msg := range ch {
+			pubsubMsg := new(model.PubSubMessage)
+			_ = json.Unmarshal([]byte(msg.Payload), pubsubMsg)
+			_ = receiver.SendAck(context.Background(), pubsubMsg.ReplyTo, pubsubMsg.Payload == "ack")
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sender.Send(ctx, "topic", "ack"); err != nil {
+		t.Errorf("Send() error = %v, want the message to be acknowledged", err)
+	}
+	if err := sender.Send(ctx, "topic", "nack"); err == nil {
+		t.Errorf("Send() error = nil, want the message to be rejected")
+	}
+
+	// A message sent to a topic of another project doesn't reach the subscriber
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := newInMemoryModule("other", b).Send(ctx, "topic", "ack"); err == nil {
+		t.Errorf("Send() error = nil, want a timeout")
+	}
+
+	receiver.Close()
+	if _, ok := <-ch; ok {
+		t.Errorf("Close() didn't close the channel of the subscription")
+	}
+	if len(b.subs) != 0 {
+		t.Errorf("Close() left %d subscriptions in the broker", len(b.subs))
+	}
+}
+
+func TestModule_InMemoryKeys(t *testing.T) {
+	ctx := context.Background()
+	m := newInMemoryModule("project", newMemoryBroker())
+
+	if ok, _ := m.SetKeyIfNotExists(ctx, "lock", "a", time.Minute); !ok {
+		t.Errorf("SetKeyIfNotExists() = false, want the key to be set")
+	}
+	if ok, _ := m.SetKeyIfNotExists(ctx, "lock", "b", time.Minute); ok {
+		t.Errorf("SetKeyIfNotExists() = true, want the existing key to be retained")
+	}
+	if ok, _ := m.CompareAndRenewKey(ctx, "lock", "b", time.Minute); ok {
+		t.Errorf("CompareAndRenewKey() = true, want false for a different value")
+	}
+	if ok, _ := m.CompareAndDeleteKey(ctx, "lock", "a"); !ok {
+		t.Errorf("CompareAndDeleteKey() = false, want the key to be deleted")
+	}
+	if _, err := m.GetKey(ctx, "lock"); err != redis.Nil {
+		t.Errorf("GetKey() error = %v, want redis.Nil", err)
+	}
+
+	_ = m.SetKey(ctx, "heartbeat/1", "1", 10*time.Millisecond)
+	_ = m.SetKey(ctx, "heartbeat/2", "2", 0)
+	_ = m.SetKey(ctx, "other", "3", 0)
+	if ttl, _ := m.GetKeyTTL(ctx, "heartbeat/2"); ttl != -1 {
+		t.Errorf("GetKeyTTL() = %v, want -1 for a key which doesn't expire", ttl)
+	}
+	if got, _ := m.GetKeysWithPrefix(ctx, "heartbeat/"); len(got) != 2 {
+		t.Errorf("GetKeysWithPrefix() = %v, want 2 keys", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if got, _ := m.GetKeysWithPrefix(ctx, "heartbeat/"); len(got) != 1 || got["heartbeat/2"] != "2" {
+		t.Errorf("GetKeysWithPrefix() = %v, want the expired key to be left out", got)
+	}
+	if ttl, _ := m.GetKeyTTL(ctx, "heartbeat/1"); ttl != -2 {
+		t.Errorf("GetKeyTTL() = %v, want -2 for an expired key", ttl)
+	}
+	if err := m.DeleteKeyOnMatch(ctx, "heartbeat/1", "1"); err != redis.Nil {
+		t.Errorf("DeleteKeyOnMatch() error = %v, want redis.Nil", err)
+	}
+}
@@ -18,6 +18,10 @@ func (m *Module) Send(ctx context.Context, topic string, value interface{}) erro
 	// Create a new subscription on reply to channel
 	replyTo := m.getTopicName(ksuid.New().String())
 
+	if m.memory != nil {
+		return m.sendInMemory(ctx, replyTo, topic, value)
+	}
+
 	// Create a subscription
 	pubsub := m.client.Subscribe(context.TODO(), replyTo)
 	defer utils.CloseTheCloser(pubsub)
@@ -50,6 +54,15 @@ func (m *Module) Send(ctx context.Context, topic string, value interface{}) erro
 	return nil
 }
 
+// Publish publishes a message on a topic without waiting for it to be received
+func (m *Module) Publish(ctx context.Context, topic, value string) error {
+	if m.memory != nil {
+		m.memory.publish(m.getTopicName(topic), value)
+		return nil
+	}
+	return m.client.Publish(ctx, m.getTopicName(topic), value).Err()
+}
+
 // SendAck acknowledges the receipt of a message
 func (m *Module) SendAck(ctx context.Context, replyTo string, ack bool) error {
 	// Prepare response message
@@ -59,6 +72,10 @@ func (m *Module) SendAck(ctx context.Context, replyTo string, ack bool) error {
 	}
 
 	// Send the acknowledgement
+	if m.memory != nil {
+		m.memory.publish(replyTo, msg)
+		return nil
+	}
 	return m.client.Publish(ctx, replyTo, msg).Err()
 }
 
@@ -72,6 +89,12 @@ func (m *Module) Subscribe(ctx context.Context, topic string) (<-chan *redis.Mes
 		return sub.ch, nil
 	}
 
+	if m.memory != nil {
+		sub := m.memory.subscribe(m.getTopicName(topic))
+		m.mapping[topic] = &subscription{sub.ch, sub}
+		return sub.ch, nil
+	}
+
 	// Make a redis subscription
 	pubsub := m.client.Subscribe(context.TODO(), m.getTopicName(topic))
 	if _, err := pubsub.Receive(ctx); err != nil {
@@ -103,6 +126,9 @@ func (m *Module) SetKeyIfNotExists(ctx context.Context, key, value string, t tim
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	if m.memory != nil {
+		return m.memory.setIfNotExists(key, value, t), nil
+	}
 	return m.client.SetNX(ctx, key, value, t).Result()
 }
 
@@ -111,6 +137,14 @@ func (m *Module) RenewKeyTTLOnMatch(ctx context.Context, key, value string, t ti
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	if m.memory != nil {
+		if _, err := m.memory.get(key); err != nil {
+			return err
+		}
+		m.memory.compareAndRenew(key, value, t)
+		return nil
+	}
+
 	result, err := m.client.Get(ctx, key).Result()
 	if err != nil {
 		return err
@@ -123,10 +157,183 @@ func (m *Module) RenewKeyTTLOnMatch(ctx context.Context, key, value string, t ti
 	return nil
 }
 
+// DeleteKeyOnMatch deletes the key if its value matches
+func (m *Module) DeleteKeyOnMatch(ctx context.Context, key, value string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		if _, err := m.memory.get(key); err != nil {
+			return err
+		}
+		m.memory.compareAndDelete(key, value)
+		return nil
+	}
+
+	result, err := m.client.Get(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	if result == value {
+		return m.client.Del(ctx, key).Err()
+	}
+
+	return nil
+}
+
 // GetKey gets value of specified key from database
 func (m *Module) GetKey(ctx context.Context, key string) (string, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	if m.memory != nil {
+		return m.memory.get(key)
+	}
 	return m.client.Get(ctx, key).Result()
 }
+
+// DeleteKey deletes the key
+func (m *Module) DeleteKey(ctx context.Context, key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		m.memory.delete(key)
+		return nil
+	}
+	return m.client.Del(ctx, key).Err()
+}
+
+// SetKey sets the value of the key along with its ttl
+func (m *Module) SetKey(ctx context.Context, key, value string, t time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		m.memory.set(key, value, t)
+		return nil
+	}
+	return m.client.Set(ctx, key, value, t).Err()
+}
+
+// GetKeyTTL returns the remaining ttl of the key. A negative duration is returned for the keys which don't expire.
+func (m *Module) GetKeyTTL(ctx context.Context, key string) (time.Duration, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		return m.memory.ttl(key), nil
+	}
+	return m.client.PTTL(ctx, key).Result()
+}
+
+// GetKeysWithPrefix returns the values of all the keys starting with the prefix
+func (m *Module) GetKeysWithPrefix(ctx context.Context, prefix string) (map[string]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		return m.memory.getWithPrefix(prefix), nil
+	}
+
+	keys := make([]string, 0)
+	iter := m.client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	values, err := m.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		// Keys which expired after the scan are skipped
+		if v, ok := value.(string); ok {
+			result[keys[i]] = v
+		}
+	}
+	return result, nil
+}
+
+// Ping checks if the redis server is reachable
+func (m *Module) Ping(ctx context.Context) error {
+	if m.memory != nil {
+		return nil
+	}
+	return m.client.Ping(ctx).Err()
+}
+
+// renewOnMatchScript and deleteOnMatchScript compare the value and modify the key atomically, so that a
+// key which expired and got set by someone else in between is never touched
+var (
+	renewOnMatchScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	deleteOnMatchScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// CompareAndRenewKey atomically renews the ttl of the key if its value matches. It returns false if the key
+// doesn't exist or holds a different value.
+func (m *Module) CompareAndRenewKey(ctx context.Context, key, value string, t time.Duration) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		return m.memory.compareAndRenew(key, value, t), nil
+	}
+	n, err := renewOnMatchScript.Run(ctx, m.client, []string{key}, value, t.Milliseconds()).Int()
+	return n == 1, err
+}
+
+// CompareAndDeleteKey atomically deletes the key if its value matches. It returns false if the key
+// doesn't exist or holds a different value.
+func (m *Module) CompareAndDeleteKey(ctx context.Context, key, value string) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.memory != nil {
+		return m.memory.compareAndDelete(key, value), nil
+	}
+	n, err := deleteOnMatchScript.Run(ctx, m.client, []string{key}, value).Int()
+	return n == 1, err
+}
+
+// sendInMemory delivers a message reliably through the in memory broker
+func (m *Module) sendInMemory(ctx context.Context, replyTo, topic string, value interface{}) error {
+	// Create a subscription on the reply to channel before sending the message
+	sub := m.memory.subscribe(replyTo)
+	defer utils.CloseTheCloser(sub)
+
+	data, err := json.Marshal(model.PubSubMessage{ReplyTo: replyTo, Payload: value})
+	if err != nil {
+		return err
+	}
+	m.memory.publish(m.getTopicName(topic), string(data))
+
+	// Wait for the message to come back
+	msg, err := sub.receive(ctx)
+	if err != nil {
+		return err
+	}
+
+	if msg.Payload != "ACK" {
+		return fmt.Errorf("invalid response received in redis send - %s", msg.Payload)
+	}
+	return nil
+}
@@ -12,8 +12,9 @@ import (
 type Module struct {
 	lock sync.Mutex
 
-	// Redis client
+	// Redis client. The in memory broker is used instead when the connection string is InMemoryConn.
 	client *redis.Client
+	memory *memoryBroker
 
 	// Internal variables
 	projectID string
@@ -27,6 +28,10 @@ func New(projectID, conn string) (*Module, error) {
 		conn = "localhost:6379"
 	}
 
+	if conn == InMemoryConn {
+		return &Module{memory: broker, projectID: projectID, mapping: map[string]*subscription{}}, nil
+	}
+
 	c := redis.NewClient(&redis.Options{
 		Addr:     conn,
 		Password: "",
@@ -44,6 +49,12 @@ func New(projectID, conn string) (*Module, error) {
 	return &Module{client: c, projectID: projectID, mapping: map[string]*subscription{}}, nil
 }
 
+// NewInMemory creates a client with a broker of its own. It suits the components of a single node which need no
+// coordination with other nodes, including the other gateways running in the same process.
+func NewInMemory(projectID string) *Module {
+	return &Module{memory: newMemoryBroker(), projectID: projectID, mapping: map[string]*subscription{}}
+}
+
 // Close closes the redis client along with the active subscriptions on it
 func (m *Module) Close() {
 	m.lock.Lock()
@@ -56,5 +67,7 @@ func (m *Module) Close() {
 	m.mapping = map[string]*subscription{}
 
 	// Close the redis client
-	_ = m.client.Close()
+	if m.client != nil {
+		_ = m.client.Close()
+	}
 }
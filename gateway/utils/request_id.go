@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+)
+
+// maxRequestIDLength is the maximum length of a request id accepted from the clients
+const maxRequestIDLength = 128
+
+// IsValidRequestID checks if a request id provided by a client can be used as is. Request ids end up in the logs,
+// the headers of forwarded calls and the comments of database queries, hence only a restricted set of characters
+// is allowed.
+func IsValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDFromContext returns the id of the request being served. An empty string is returned for the operations
+// which aren't a part of a request.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	// The request id is generated on the fly by the helpers if the context doesn't carry one
+	id := helpers.GetRequestID(ctx)
+	if strings.HasPrefix(id, "noValue-") || !IsValidRequestID(id) {
+		return ""
+	}
+	return id
+}
+
+// InjectRequestID adds the id of the request being served to the headers of an outgoing request
+func InjectRequestID(ctx context.Context, header http.Header) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		header.Set(helpers.HeaderRequestID, id)
+	}
+}
+
+// GetQueryComment returns a sqlcommenter style comment which tags a database query with the id of the request being
+// served and the trace context of the span it's made in
+func GetQueryComment(ctx context.Context, withRequestID bool) string {
+	tags := GetQueryTags(ctx, withRequestID)
+	if tags == "" {
+		return ""
+	}
+	return "/*" + tags + "*/ "
+}
+
+// GetQueryTags returns the tags of a database query in the sqlcommenter format. The request id is only added if asked
+// for, while the traceparent is added whenever the span is sampled so that the query can be found in its trace.
+func GetQueryTags(ctx context.Context, withRequestID bool) string {
+	tags := make([]string, 0, 2)
+	if id := RequestIDFromContext(ctx); withRequestID && id != "" {
+		tags = append(tags, "request_id="+formatQueryTag(id))
+	}
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" {
+		tags = append(tags, "traceparent="+formatQueryTag(traceParent))
+	}
+	return strings.Join(tags, ",")
+}
+
+// formatQueryTag url encodes the value of a tag and quotes it, so that it can't end the comment it's placed in
+func formatQueryTag(value string) string {
+	return "'" + url.QueryEscape(value) + "'"
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel/api/trace"
+
+	"github.com/spaceuptech/space-cloud/gateway/utils/tracing"
+)
+
+func TestIsValidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "ksuid", id: "1sPnvvWGjUMUTNYNvSRmsN4k6Bk", want: true},
+		{name: "uuid", id: "6f1c7cde-3e0a-4b7e-9a48-1f5b2d6c2a10", want: true},
+		{name: "dotted id with a prefix", id: "web:checkout.42_a", want: true},
+		{name: "empty id", id: "", want: false},
+		{name: "id with spaces", id: "my request", want: false},
+		{name: "id closing a sql comment", id: "abc*/drop", want: false},
+		{name: "id with a new line", id: "abc\ninjected log", want: false},
+		{name: "id which is too long", id: strings.Repeat("a", maxRequestIDLength+1), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidRequestID(tt.id); got != tt.want {
+				t.Errorf("IsValidRequestID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInjectRequestID(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantHeader  string
+		wantComment string
+	}{
+		{
+			name:        "context of a request",
+			ctx:         newRequestContext("req-1"),
+			wantHeader:  "req-1",
+			wantComment: "/*request_id='req-1'*/ ",
+		},
+		{
+			name: "context without a request id",
+			ctx:  context.Background(),
+		},
+		{
+			name: "context with an invalid request id",
+			ctx:  newRequestContext("*/ drop table users; /*"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			InjectRequestID(tt.ctx, header)
+			if got := header.Get(helpers.HeaderRequestID); got != tt.wantHeader {
+				t.Errorf("InjectRequestID() header = %v, want %v", got, tt.wantHeader)
+			}
+			if got := GetQueryComment(tt.ctx, true); got != tt.wantComment {
+				t.Errorf("GetQueryComment() = %v, want %v", got, tt.wantComment)
+			}
+		})
+	}
+}
+
+func newRequestContext(requestID string) context.Context {
+	r := httptest.NewRequest(http.MethodGet, "/v1/api/myproject/crud/db/users/read", nil)
+	r.Header.Set(helpers.HeaderRequestID, requestID)
+	return helpers.CreateContext(r)
+}
+
+func TestGetQueryComment(t *testing.T) {
+	tracing.Init("node", "cluster")
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traced := func(ctx context.Context, traceParent string) context.Context {
+		header := http.Header{}
+		header.Set("traceparent", traceParent)
+		ctx, _ = tracing.StartSpan(tracing.Extract(ctx, header), "db.read", trace.SpanKindClient)
+		return ctx
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		withRequestID bool
+		want          string
+	}{
+		{name: "request id not asked for", ctx: newRequestContext("req-1")},
+		{name: "sampled span", ctx: traced(context.Background(), traceParent), want: "/*traceparent='" + traceParent + "'*/ "},
+		{name: "sampled span of a request", ctx: traced(newRequestContext("req-1"), traceParent), withRequestID: true, want: "/*request_id='req-1',traceparent='" + traceParent + "'*/ "},
+		{name: "span which isn't sampled", ctx: traced(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetQueryComment(tt.ctx, tt.withRequestID); got != tt.want {
+				t.Errorf("GetQueryComment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tmpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+	"github.com/spaceuptech/helpers"
+)
+
+// JQ is a compiled jq expression
+type JQ struct {
+	query string
+	code  *gojq.Code
+}
+
+// ParseJQ compiles the provided jq expression
+func ParseJQ(query string) (*JQ, error) {
+	parsed, err := gojq.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &JQ{query: query, code: code}, nil
+}
+
+// Exec runs the jq expression against the input. A single output is returned as is, while multiple
+// outputs are wrapped in an array. Nil is returned if the expression yields no output. The expression
+// stops running once the context gets cancelled.
+func (q *JQ) Exec(ctx context.Context, input interface{}) (interface{}, error) {
+	// Normalise the input so that the expression only ever sees json types
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var normalised interface{}
+	if err := json.Unmarshal(data, &normalised); err != nil {
+		return nil, err
+	}
+
+	outputs := make([]interface{}, 0)
+	iter := q.code.RunWithContext(ctx, normalised)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		outputs = append(outputs, v)
+	}
+	switch len(outputs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return outputs[0], nil
+	default:
+		return outputs, nil
+	}
+}
+
+// JQTemplate executes a jq expression on the same object which is made available to go templates
+func JQTemplate(ctx context.Context, query *JQ, format, token string, claims, params interface{}) (interface{}, error) {
+	object := map[string]interface{}{"args": params, "auth": claims, "token": token}
+	result, err := query.Exec(ctx, object)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to execute jq expression (%s)", query.query), err, nil)
+	}
+
+	if format == "string" {
+		if s, ok := result.(string); ok {
+			return s, nil
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to marshal jq output to string", err, nil)
+		}
+		return string(data), nil
+	}
+	return result, nil
+}
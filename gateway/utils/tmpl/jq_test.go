@@ -0,0 +1,102 @@
+package tmpl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestJQ_Exec(t *testing.T) {
+	input := map[string]interface{}{
+		"foo":   "bar",
+		"num":   10,
+		"obj":   map[string]interface{}{"nested": map[string]interface{}{"key": "value"}, "my key": 1},
+		"items": []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}},
+	}
+	tests := []struct {
+		name    string
+		query   string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "identity", query: ".", want: map[string]interface{}{"foo": "bar", "num": float64(10), "obj": map[string]interface{}{"nested": map[string]interface{}{"key": "value"}, "my key": float64(1)}, "items": []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}}}},
+		{name: "field", query: ".foo", want: "bar"},
+		{name: "nested field", query: ".obj.nested.key", want: "value"},
+		{name: "quoted field", query: `.obj."my key"`, want: float64(1)},
+		{name: "bracket field", query: `.obj["my key"]`, want: float64(1)},
+		{name: "missing field", query: ".missing.key", want: nil},
+		{name: "index", query: ".items[1].id", want: "2"},
+		{name: "negative index", query: ".items[-1].id", want: "2"},
+		{name: "iterate", query: ".items[].id", want: []interface{}{"1", "2"}},
+		{name: "collect", query: "[.items[] | .id]", want: []interface{}{"1", "2"}},
+		{name: "comma", query: ".foo, .num", want: []interface{}{"bar", float64(10)}},
+		{name: "object construction", query: `{name: .foo, "count": .num, num, static: "x", flag: true}`, want: map[string]interface{}{"name": "bar", "count": float64(10), "num": float64(10), "static": "x", "flag": true}},
+		{name: "object with pipe", query: `{key: .obj | .nested.key}`, want: map[string]interface{}{"key": "value"}},
+		{name: "grouping", query: `(.obj | .nested) | .key`, want: "value"},
+		{name: "empty array", query: "[]", want: []interface{}{}},
+		{name: "select", query: `[.items[] | select(.id == "2")]`, want: []interface{}{map[string]interface{}{"id": "2"}}},
+		{name: "map and length", query: `.items | map(.id) | length`, want: 2},
+		{name: "string interpolation", query: `"id-\(.items[0].id)"`, want: "id-1"},
+		{name: "alternative", query: `.missing // .foo`, want: "bar"},
+		{name: "empty", query: "empty", want: nil},
+		{name: "iterate over string", query: ".foo[]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseJQ(tt.query)
+			if err != nil {
+				t.Fatalf("ParseJQ() unexpected error = %v", err)
+			}
+			got, err := q.Exec(context.Background(), input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Exec() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exec() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJQ(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{name: "valid", query: `{id: .args.id}`},
+		{name: "unterminated object", query: `{id: .args.id`, wantErr: true},
+		{name: "unterminated string", query: `."foo`, wantErr: true},
+		{name: "undefined function", query: `undefined_fn(1)`, wantErr: true},
+		{name: "dangling pipe", query: `.foo |`, wantErr: true},
+		{name: "trailing characters", query: `.foo )`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJQ(tt.query); (err != nil) != tt.wantErr {
+				t.Errorf("ParseJQ() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJQTemplate(t *testing.T) {
+	q, _ := ParseJQ(`{user: .auth.id, token: .token, name: .args.name}`)
+	got, err := JQTemplate(context.Background(), q, "json", "abc", map[string]interface{}{"id": "1"}, map[string]interface{}{"name": "foo"})
+	if err != nil {
+		t.Fatalf("JQTemplate() unexpected error = %v", err)
+	}
+	want := map[string]interface{}{"user": "1", "token": "abc", "name": "foo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JQTemplate() got = %v, want %v", got, want)
+	}
+
+	got, err = JQTemplate(context.Background(), q, "string", "abc", map[string]interface{}{"id": "1"}, map[string]interface{}{"name": "foo"})
+	if err != nil {
+		t.Fatalf("JQTemplate() unexpected error = %v", err)
+	}
+	if want := `{"name":"foo","token":"abc","user":"1"}`; got != want {
+		t.Errorf("JQTemplate() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
+)
+
+type tracer struct {
+	provider *Provider
+}
+
+// Start starts a new span with the sdk tracer. While tracing is disabled the span isn't recorded but still
+// carries the trace context of the parent, so that it gets propagated to downstream services as is.
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	if sdkTracer := t.provider.getTracer(); sdkTracer != nil {
+		return sdkTracer.Start(ctx, name, opts...)
+	}
+
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() {
+		sc = trace.RemoteSpanContextFromContext(ctx)
+	}
+	if trace.NewSpanConfig(opts...).NewRoot {
+		sc = trace.EmptySpanContext()
+	}
+	s := &nonRecordingSpan{tracer: t, sc: sc}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// nonRecordingSpan only carries the span context
+type nonRecordingSpan struct {
+	tracer *tracer
+	sc     trace.SpanContext
+}
+
+func (s *nonRecordingSpan) Tracer() trace.Tracer                                     { return s.tracer }
+func (s *nonRecordingSpan) End(...trace.SpanOption)                                  {}
+func (s *nonRecordingSpan) AddEvent(context.Context, string, ...label.KeyValue)      {}
+func (s *nonRecordingSpan) IsRecording() bool                                        { return false }
+func (s *nonRecordingSpan) RecordError(context.Context, error, ...trace.ErrorOption) {}
+func (s *nonRecordingSpan) SpanContext() trace.SpanContext                           { return s.sc }
+func (s *nonRecordingSpan) SetStatus(codes.Code, string)                             {}
+func (s *nonRecordingSpan) SetName(string)                                           {}
+func (s *nonRecordingSpan) SetAttributes(...label.KeyValue)                          {}
+func (s *nonRecordingSpan) AddEventWithTimestamp(context.Context, time.Time, string, ...label.KeyValue) {
+}
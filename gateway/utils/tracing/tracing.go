@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagators"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+const instrumentationName = "github.com/spaceuptech/space-cloud/gateway"
+
+// shutdownTimeout is the time given to the exporter to flush the buffered spans when the config changes
+const shutdownTimeout = 5 * time.Second
+
+// Provider is a tracer provider which records spans with the OpenTelemetry SDK once tracing gets enabled
+// and exports them to an OTLP collector
+type Provider struct {
+	lock sync.RWMutex
+
+	nodeID, clusterID string
+
+	sdk       *sdktrace.TracerProvider
+	processor *sdktrace.BatchSpanProcessor
+	exporter  export.SpanExporter
+}
+
+var defaultProvider = newProvider("", "")
+
+func newProvider(nodeID, clusterID string) *Provider {
+	return &Provider{nodeID: nodeID, clusterID: clusterID}
+}
+
+// Init initialises the global tracer provider along with the W3C trace context propagator. Spans are only
+// recorded once tracing gets enabled in the cluster config.
+func Init(nodeID, clusterID string) {
+	defaultProvider = newProvider(nodeID, clusterID)
+	global.SetTracerProvider(defaultProvider)
+	global.SetTextMapPropagator(otel.NewCompositeTextMapPropagator(propagators.TraceContext{}, propagators.Baggage{}))
+}
+
+// SetConfig applies the tracing config of the cluster
+func SetConfig(c *config.TracingConfig) {
+	defaultProvider.SetConfig(c)
+}
+
+// SetConfig applies the tracing config
+func (p *Provider) SetConfig(c *config.TracingConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.stop()
+
+	if c == nil || !c.Enabled || c.Endpoint == "" {
+		return
+	}
+
+	exporter, err := otlp.NewExporter(getExporterOptions(c)...)
+	if err != nil {
+		_ = helpers.Logger.LogError("tracing", "Unable to start tracing exporter", err, nil)
+		return
+	}
+	p.start(c, exporter)
+}
+
+// start records the spans with a new sdk tracer provider exporting them to the exporter
+func (p *Provider) start(c *config.TracingConfig, exporter export.SpanExporter) {
+	serviceName := c.ServiceName
+	if serviceName == "" {
+		serviceName = "space-cloud"
+	}
+
+	sampleRatio := 1.0
+	if c.SampleRatio != nil {
+		sampleRatio = math.Max(0, math.Min(1, *c.SampleRatio))
+	}
+
+	// The ratio sampler decides based on the trace id, so that all gateways in the cluster arrive at the
+	// same decision for a root span
+	p.exporter = exporter
+	p.processor = sdktrace.NewBatchSpanProcessor(exporter)
+	p.sdk = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(p.processor),
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))}),
+		sdktrace.WithResource(resource.New(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceInstanceIDKey.String(p.nodeID),
+			label.String("space_cloud.cluster_id", p.clusterID),
+		)),
+	)
+}
+
+// stop stops the existing sdk tracer provider after flushing the spans it has buffered
+func (p *Provider) stop() {
+	if p.sdk == nil {
+		return
+	}
+
+	p.processor.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := p.exporter.Shutdown(ctx); err != nil {
+		_ = helpers.Logger.LogError("tracing", "Unable to stop tracing exporter", err, nil)
+	}
+	p.sdk, p.processor, p.exporter = nil, nil, nil
+}
+
+// Tracer returns a tracer. The instrumentation name is ignored since all spans are exported together.
+func (p *Provider) Tracer(_ string, _ ...trace.TracerOption) trace.Tracer {
+	return &tracer{provider: p}
+}
+
+func (p *Provider) getTracer() trace.Tracer {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if p.sdk == nil {
+		return nil
+	}
+	return p.sdk.Tracer(instrumentationName)
+}
+
+// getExporterOptions returns the options of the OTLP exporter
+func getExporterOptions(c *config.TracingConfig) []otlp.ExporterOption {
+	addr, secure := getCollectorAddress(c.Endpoint)
+	opts := []otlp.ExporterOption{otlp.WithAddress(addr), otlp.WithInsecure()}
+	if secure {
+		opts = []otlp.ExporterOption{otlp.WithAddress(addr), otlp.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))}
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlp.WithHeaders(c.Headers))
+	}
+	return opts
+}
+
+// getCollectorAddress returns the OTLP/gRPC address of the collector and whether tls is to be used. The endpoint
+// is either a plain host:port or a url whose scheme decides whether tls is used.
+func getCollectorAddress(endpoint string) (string, bool) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint, false
+	}
+	switch u.Scheme {
+	case "https":
+		return getHostPort(u, "443"), true
+	case "http":
+		return getHostPort(u, "80"), false
+	}
+	return endpoint, false
+}
+
+func getHostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return fmt.Sprintf("%s:%s", u.Hostname(), defaultPort)
+}
+
+// StartSpan starts a new span as a child of the span present in the context
+func StartSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...label.KeyValue) (context.Context, trace.Span) {
+	return global.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the span after recording the error (if any)
+func EndSpan(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(ctx, err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Extract returns a context containing the trace context propagated in the headers of an incoming request
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return global.TextMapPropagator().Extract(ctx, header)
+}
+
+// Inject adds the trace context present in the context to the headers of an outgoing request
+func Inject(ctx context.Context, header http.Header) {
+	global.TextMapPropagator().Inject(ctx, header)
+}
+
+// TraceParent returns the W3C traceparent of the span present in the context. It is empty unless the span is sampled,
+// since the span can't be looked up otherwise.
+func TraceParent(ctx context.Context) string {
+	if !trace.SpanFromContext(ctx).SpanContext().IsSampled() {
+		return ""
+	}
+	header := http.Header{}
+	propagators.TraceContext{}.Inject(ctx, header)
+	return header.Get("traceparent")
+}
@@ -0,0 +1,155 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/spaceuptech/space-cloud/gateway/config"
+)
+
+// recordingExporter records the spans exported by the sdk
+type recordingExporter struct {
+	lock     sync.Mutex
+	spans    []*export.SpanData
+	shutdown bool
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []*export.SpanData) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+// enable enables tracing on the default provider with an exporter recording the spans
+func enable(c *config.TracingConfig) *recordingExporter {
+	e := new(recordingExporter)
+	defaultProvider.lock.Lock()
+	defaultProvider.start(c, e)
+	defaultProvider.lock.Unlock()
+	return e
+}
+
+func TestPropagation(t *testing.T) {
+	Init("node", "cluster")
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incoming := http.Header{}
+	incoming.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	tests := []struct {
+		name    string
+		config  *config.TracingConfig
+		headers http.Header
+		want    string
+	}{
+		{name: "tracing disabled with remote parent", headers: incoming, want: "00-" + traceID + "-00f067aa0ba902b7-01"},
+		{name: "tracing enabled with remote parent", config: &config.TracingConfig{Enabled: true}, headers: incoming, want: "00-" + traceID + "-"},
+		{name: "tracing disabled without parent", headers: http.Header{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config != nil {
+				enable(tt.config)
+			}
+			defer SetConfig(nil)
+
+			ctx := Extract(context.Background(), tt.headers)
+			ctx, span := StartSpan(ctx, "test", trace.SpanKindServer)
+			defer span.End()
+
+			outgoing := http.Header{}
+			Inject(ctx, outgoing)
+			if got := outgoing.Get("traceparent"); !strings.HasPrefix(got, tt.want) || (tt.want == "" && got != "") {
+				t.Errorf("Inject() traceparent = %v, want prefix %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_export(t *testing.T) {
+	Init("node", "cluster")
+	exporter := enable(&config.TracingConfig{Enabled: true})
+
+	ctx, parent := StartSpan(context.Background(), "parent", trace.SpanKindServer)
+	ctx, child := StartSpan(ctx, "child", trace.SpanKindClient)
+	EndSpan(ctx, child, errors.New("failed"))
+	parent.End()
+
+	// Disabling tracing flushes the pending spans and stops the exporter
+	SetConfig(nil)
+
+	exporter.lock.Lock()
+	defer exporter.lock.Unlock()
+	if !exporter.shutdown {
+		t.Errorf("SetConfig() did not stop the exporter")
+	}
+	if len(exporter.spans) != 2 {
+		t.Fatalf("Provider exported %d spans, want 2", len(exporter.spans))
+	}
+	c, p := exporter.spans[0], exporter.spans[1]
+	if c.Name != "child" || c.ParentSpanID != p.SpanContext.SpanID || c.SpanContext.TraceID != p.SpanContext.TraceID {
+		t.Errorf("Provider child span is not linked to its parent: %+v", exporter.spans)
+	}
+	if c.StatusCode != codes.Error || len(c.MessageEvents) != 1 {
+		t.Errorf("Provider child span does not record the error: %+v", c)
+	}
+	if v, ok := c.Resource.LabelSet().Value(semconv.ServiceInstanceIDKey); !ok || v.AsString() != "node" {
+		t.Errorf("Provider did not describe the gateway in the resource of the spans: %v", c.Resource)
+	}
+}
+
+func TestProvider_sampling(t *testing.T) {
+	Init("node", "cluster")
+	ratio := 0.0
+	exporter := enable(&config.TracingConfig{Enabled: true, SampleRatio: &ratio})
+
+	_, span := StartSpan(context.Background(), "root", trace.SpanKindServer)
+	if span.SpanContext().IsSampled() {
+		t.Errorf("StartSpan() sampled a root span with a sample ratio of 0")
+	}
+	span.End()
+	SetConfig(nil)
+
+	if len(exporter.spans) != 0 {
+		t.Errorf("Provider exported %d spans, want 0", len(exporter.spans))
+	}
+}
+
+func TestGetCollectorAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		wantAddr   string
+		wantSecure bool
+	}{
+		{name: "host and port", endpoint: "otel-collector:4317", wantAddr: "otel-collector:4317"},
+		{name: "ip and port", endpoint: "10.0.0.1:4317", wantAddr: "10.0.0.1:4317"},
+		{name: "http url", endpoint: "http://otel-collector:4317", wantAddr: "otel-collector:4317"},
+		{name: "https url without port", endpoint: "https://otlp.example.com", wantAddr: "otlp.example.com:443", wantSecure: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, secure := getCollectorAddress(tt.endpoint)
+			if addr != tt.wantAddr || secure != tt.wantSecure {
+				t.Errorf("getCollectorAddress() = (%v, %v), want (%v, %v)", addr, secure, tt.wantAddr, tt.wantSecure)
+			}
+		})
+	}
+}
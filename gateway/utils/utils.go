@@ -154,3 +154,8 @@ func checkJoinType(value interface{}, jointTableName string) (bool, string) {
 		return false, "none"
 	}
 }
+
+// InternalClaims returns the claims identifying the requests made by the gateway itself
+func InternalClaims() map[string]interface{} {
+	return map[string]interface{}{"id": InternalUserID}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spaceuptech/helpers"
 
 	"github.com/spaceuptech/space-cloud/gateway/model"
+	"github.com/spaceuptech/space-cloud/gateway/utils/geo"
 )
 
 func attemptConvertBoolToInt64(val interface{}) interface{} {
@@ -138,8 +139,15 @@ func Validate(dbType string, where map[string]interface{}, obj interface{}) bool
 
 			// match condition
 			for k2, v2 := range cond {
+				if geo.IsOperator(k2) {
+					if !geo.Matches(k2, v2, val) {
+						return false
+					}
+					continue
+				}
+
 				v2, val = adjustValTypes(v2, val)
-				if k2 != "$in" && k2 != "$nin" {
+				if k2 != "$in" && k2 != "$nin" && k2 != "$contains" {
 					// In case of in and not in, the value of v2 will be an array
 					if reflect.TypeOf(val) != reflect.TypeOf(v2) {
 						return false
@@ -254,15 +262,17 @@ func Validate(dbType string, where map[string]interface{}, obj interface{}) bool
 
 				case "$contains":
 					switch v := v2.(type) {
-					case map[string]interface{}:
+					case map[string]interface{}, []interface{}:
 						// check if result contains specified contains field
-						result, ok := res[k]
-						if !ok {
+						if !checkIfObjContainsWhereObj(val, v, false) {
 							return false
 						}
-						return checkIfObjContainsWhereObj(result, v, false)
 					default:
-						return false
+						// A single value is contained by an array holding it
+						array, ok := val.([]interface{})
+						if !ok || !ArrayContains(array, v) {
+							return false
+						}
 					}
 				case "$regex":
 					regex := v2.(string)
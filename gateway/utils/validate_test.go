@@ -728,6 +728,51 @@ func TestValidate(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "contains value within array of json field",
+			args: args{
+				dbType: string(model.Postgres),
+				where:  map[string]interface{}{"meta.tags": map[string]interface{}{"$contains": "beta"}},
+				obj:    map[string]interface{}{"meta": map[string]interface{}{"tags": []interface{}{"alpha", "beta"}}},
+			},
+			want: true,
+		},
+		{
+			name: "does not contain value within array of json field",
+			args: args{
+				dbType: string(model.Postgres),
+				where:  map[string]interface{}{"meta.tags": map[string]interface{}{"$contains": "gamma"}},
+				obj:    map[string]interface{}{"meta": map[string]interface{}{"tags": []interface{}{"alpha", "beta"}}},
+			},
+			want: false,
+		},
+		{
+			name: "point near another point",
+			args: args{
+				dbType: string(model.Postgres),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"lat": 19.076, "lng": 72.877, "maxDistance": 1000}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.88, 19.08}}},
+			},
+			want: true,
+		},
+		{
+			name: "point too far from another point",
+			args: args{
+				dbType: string(model.Postgres),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$near": map[string]interface{}{"lat": 19.076, "lng": 72.877, "maxDistance": 100}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"lat": 19.08, "lng": 72.88}},
+			},
+			want: false,
+		},
+		{
+			name: "point within a bounding box",
+			args: args{
+				dbType: string(model.Mongo),
+				where:  map[string]interface{}{"location": map[string]interface{}{"$geoBox": map[string]interface{}{"minLng": 72, "minLat": 19, "maxLng": 73, "maxLat": 20}}},
+				obj:    map[string]interface{}{"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{72.88, 19.08}}},
+			},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,174 @@
+package wal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// ErrFull is returned when an entry can't be appended since the log has reached its maximum size
+var ErrFull = errors.New("write ahead log is full")
+
+// WAL is a write ahead log kept in a local bolt file. The entries are appended to named queues before they are
+// delivered and are removed once delivered, so that the entries pending delivery survive a crash of the gateway.
+//
+// The size of the log is bounded. Appending an entry to a full log waits till enough entries have been removed,
+// which slows down the producers to the speed at which the entries get delivered.
+type WAL struct {
+	db      *bbolt.DB
+	maxSize int64
+
+	lock sync.Mutex
+	size int64
+	// freed is closed whenever entries are removed to wake up the appends waiting for space
+	freed chan struct{}
+}
+
+// Entry is an entry of a queue of the log
+type Entry struct {
+	ID   uint64
+	Data []byte
+}
+
+// Open opens the log stored at the path creating it if it doesn't exist. A max size of zero or less doesn't limit
+// the size of the log.
+func Open(path string, maxSize int64) (*WAL, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	// The size of the entries left behind by the previous run counts towards the max size
+	var size int64
+	err = db.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(_ []byte, b *bbolt.Bucket) error {
+			return b.ForEach(func(_, v []byte) error {
+				size += int64(len(v))
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &WAL{db: db, maxSize: maxSize, size: size, freed: make(chan struct{})}, nil
+}
+
+// Append appends the data to the queue and returns the id of the entry. It waits for space while the log is full and
+// returns ErrFull if the context expires before that.
+func (w *WAL) Append(ctx context.Context, queue string, data []byte) (uint64, error) {
+	if w.maxSize > 0 && int64(len(data)) > w.maxSize {
+		return 0, ErrFull
+	}
+
+	for {
+		w.lock.Lock()
+		if w.maxSize <= 0 || w.size+int64(len(data)) <= w.maxSize {
+			id, err := w.append(queue, data)
+			if err == nil {
+				w.size += int64(len(data))
+			}
+			w.lock.Unlock()
+			return id, err
+		}
+		freed := w.freed
+		w.lock.Unlock()
+
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return 0, ErrFull
+		}
+	}
+}
+
+func (w *WAL) append(queue string, data []byte) (uint64, error) {
+	var id uint64
+	err := w.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(queue))
+		if err != nil {
+			return err
+		}
+		id, err = b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(encodeID(id), data)
+	})
+	return id, err
+}
+
+// Remove removes the entry from the queue once it has been delivered
+func (w *WAL) Remove(queue string, id uint64) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var size int
+	err := w.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(queue))
+		if b == nil {
+			return nil
+		}
+		key := encodeID(id)
+		size = len(b.Get(key))
+		return b.Delete(key)
+	})
+	if err != nil || size == 0 {
+		return err
+	}
+
+	w.size -= int64(size)
+	close(w.freed)
+	w.freed = make(chan struct{})
+	return nil
+}
+
+// Entries returns the entries of the queue in the order they were appended
+func (w *WAL) Entries(queue string) ([]*Entry, error) {
+	entries := make([]*Entry, 0)
+	err := w.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(queue))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			// The data is only valid for the life of the transaction
+			data := make([]byte, len(v))
+			copy(data, v)
+			entries = append(entries, &Entry{ID: binary.BigEndian.Uint64(k), Data: data})
+			return nil
+		})
+	})
+	return entries, err
+}
+
+// Size returns the size in bytes of the entries in the log
+func (w *WAL) Size() int64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.size
+}
+
+// Close closes the file of the log
+func (w *WAL) Close() error {
+	return w.db.Close()
+}
+
+// encodeID encodes the id in big endian so that the entries are iterated in the order they were appended
+func encodeID(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
@@ -0,0 +1,97 @@
+package wal
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.db")
+	w, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	for _, data := range []string{"a", "bb", "ccc"} {
+		if _, err := w.Append(context.Background(), "events", []byte(data)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	if _, err := w.Append(context.Background(), "metrics", []byte("dddd")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := w.Remove("events", 2); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The entries which weren't removed are available once the log is opened again
+	w, err = Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	if got := w.Size(); got != 8 {
+		t.Errorf("Size() = %v, want %v", got, 8)
+	}
+	got, err := w.Entries("events")
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	want := []*Entry{{ID: 1, Data: []byte("a")}, {ID: 3, Data: []byte("ccc")}}
+	if arr := deep.Equal(got, want); len(arr) > 0 {
+		t.Errorf("Entries() differences = %v", arr)
+	}
+	if got, _ := w.Entries("unknown"); len(got) != 0 {
+		t.Errorf("Entries() of unknown queue = %v, want none", got)
+	}
+}
+
+func TestWAL_Append_Backpressure(t *testing.T) {
+	w, err := Open(filepath.Join(t.TempDir(), "wal.db"), 4)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Append(context.Background(), "events", []byte("aaaaa")); err != ErrFull {
+		t.Errorf("Append() of entry larger than the log error = %v, want %v", err, ErrFull)
+	}
+
+	id, err := w.Append(context.Background(), "events", []byte("aaa"))
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := w.Append(ctx, "events", []byte("bb")); err != ErrFull {
+		t.Errorf("Append() to full log error = %v, want %v", err, ErrFull)
+	}
+
+	// An append waiting for space completes once an entry is removed
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Append(context.Background(), "events", []byte("bb"))
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := w.Remove("events", id); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Append() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Append() didn't complete after space was freed")
+	}
+}
@@ -94,13 +94,13 @@ func main() {
 				cli.StringFlag{
 					Name:   "driver",
 					EnvVar: "DRIVER",
-					Usage:  "The driver to use for deployment",
+					Usage:  "The driver to use for deployment (istio, kubernetes or docker)",
 					Value:  "istio",
 				},
 				cli.StringFlag{
 					Name:   "driver-config",
 					EnvVar: "DRIVER_CONFIG",
-					Usage:  "Driver config file path",
+					Usage:  "Driver config file path. It is the directory used to store secrets and routes for the docker driver",
 				},
 				cli.StringFlag{
 					Name:   "prometheus-addr",
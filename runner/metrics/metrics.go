@@ -6,6 +6,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	promapi "github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/segmentio/ksuid"
 	api "github.com/spaceuptech/space-api-go"
 	"github.com/spaceuptech/space-api-go/db"
@@ -28,6 +30,9 @@ type Module struct {
 
 	// Variables to interact with the sink
 	sink *db.DB
+
+	// Client to query the request metrics of services. It is nil if prometheus isn't configured.
+	prometheusClient v1.API
 }
 
 type metrics struct {
@@ -35,15 +40,25 @@ type metrics struct {
 }
 
 // New creates a instance of metrics package
-func New(isMetricDisabled bool, driverType model.DriverType) *Module {
+func New(isMetricDisabled bool, driverType model.DriverType, prometheusAddr string) (*Module, error) {
+	var prometheusClient v1.API
+	if prometheusAddr != "" {
+		client, err := promapi.NewClient(promapi.Config{Address: prometheusAddr})
+		if err != nil {
+			return nil, err
+		}
+		prometheusClient = v1.NewAPI(client)
+	}
+
 	m := &Module{
 		isMetricsDisabled: true,
 		clusterID:         os.Getenv("CLUSTER_ID"),
 		nodeID:            ksuid.New().String(),
 		sink:              api.New("spacecloud", "api.spaceuptech.com", true).DB("db"),
 		driverType:        string(driverType),
+		prometheusClient:  prometheusClient,
 	}
-	return m
+	return m, nil
 }
 
 func newMetrics() *metrics {
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// ErrMetricsUnavailable is returned when the request metrics of services cannot be queried
+var ErrMetricsUnavailable = errors.New("request metrics are not available - prometheus is not configured")
+
+// GetErrorRate returns the ratio of requests of a service version which failed with a 5xx status code in the last
+// 30 seconds. The boolean is false if the service version hasn't received any requests in that duration.
+func (m *Module) GetErrorRate(ctx context.Context, project, service, version string) (float64, bool, error) {
+	if m.prometheusClient == nil {
+		return 0, false, ErrMetricsUnavailable
+	}
+
+	total, ok, err := m.queryPrometheus(ctx, prepareRateQuery(project, service, version, "sc:total_requests:rate30s"))
+	if err != nil || !ok || total == 0 {
+		return 0, false, err
+	}
+
+	failed, _, err := m.queryPrometheus(ctx, prepareRateQuery(project, service, version, "sc:error_requests:rate30s"))
+	if err != nil {
+		return 0, false, err
+	}
+	return failed / total, true, nil
+}
+
+func (m *Module) queryPrometheus(ctx context.Context, query string) (float64, bool, error) {
+	result, _, err := m.prometheusClient.Query(ctx, query, time.Now())
+	if err != nil {
+		return 0, false, err
+	}
+	vector, ok := result.(model.Vector)
+	if !ok || len(vector) == 0 {
+		return 0, false, nil
+	}
+	return float64(vector[0].Value), true, nil
+}
+
+func prepareRateQuery(project, service, version, metric string) string {
+	return fmt.Sprintf("sum(%s{kubernetes_namespace=\"%s\", app=\"%s\", version=\"%s\"})", metric, project, service, version)
+}
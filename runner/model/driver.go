@@ -9,4 +9,7 @@ const (
 
 	// TypeDocker is the driver type used to target docker
 	TypeDocker DriverType = "docker"
+
+	// TypeKubernetes is the driver type used to target kubernetes without istio
+	TypeKubernetes DriverType = "kubernetes"
 )
@@ -1,4 +1,10 @@
 package model
 
+import "context"
+
 // ServiceCallMetricHook logs apply service operation
 type ServiceCallMetricHook func(projectID string)
+
+// ErrorRateHook returns the ratio of failed requests of a service version. The boolean is false if the service
+// version hasn't received any requests recently.
+type ErrorRateHook func(ctx context.Context, projectID, serviceID, version string) (float64, bool, error)
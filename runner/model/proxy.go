@@ -9,6 +9,15 @@ type ProxyMessage struct {
 	Version        string `json:"version,omitempty"`
 }
 
+// ProxyTarget describes the service version a proxied request is to be forwarded to
+type ProxyTarget struct {
+	Project string `json:"project"`
+	Service string `json:"service"`
+	Version string `json:"version"`
+	Host    string `json:"host"`
+	Port    string `json:"port"`
+}
+
 // EnvoyMetrics is the metrics collected from envoy
 type EnvoyMetrics struct {
 	Stats []EnvoyStat `json:"stats"`
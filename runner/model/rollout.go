@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Rollout describes the progressive shift of the traffic of a service from its stable version to a new version
+type Rollout struct {
+	ProjectID     string          `json:"projectId" yaml:"projectId"`
+	ServiceID     string          `json:"serviceId" yaml:"serviceId"`
+	Strategy      RolloutStrategy `json:"strategy" yaml:"strategy"`
+	StableVersion string          `json:"stableVersion" yaml:"stableVersion"`
+	CanaryVersion string          `json:"canaryVersion" yaml:"canaryVersion"`
+
+	// Steps are the weights (in percentage) of the traffic sent to the canary version at every step. It defaults to
+	// [10, 25, 50, 100] for canary rollouts and [100] for blue green rollouts.
+	Steps []int32 `json:"steps,omitempty" yaml:"steps,omitempty"`
+
+	// StepInterval is the time (in seconds) to wait before moving on to the next step. Default 60
+	StepInterval int64 `json:"stepInterval,omitempty" yaml:"stepInterval,omitempty"`
+
+	// MaxErrorRate is the ratio (between 0 and 1) of failed requests of the canary version beyond which the rollout
+	// is rolled back. Default 0.05
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty" yaml:"maxErrorRate,omitempty"`
+
+	// AutoPromote promotes the canary version once the last step has passed its analysis. Rollouts which aren't
+	// auto promoted wait to be promoted manually.
+	AutoPromote bool `json:"autoPromote" yaml:"autoPromote"`
+
+	// The state of the rollout
+	Status        RolloutStatus `json:"status" yaml:"status"`
+	CurrentStep   int           `json:"currentStep" yaml:"currentStep"`
+	CanaryWeight  int32         `json:"canaryWeight" yaml:"canaryWeight"`
+	LastErrorRate float64       `json:"lastErrorRate" yaml:"lastErrorRate"`
+	Message       string        `json:"message,omitempty" yaml:"message,omitempty"`
+	StartedAt     time.Time     `json:"startedAt" yaml:"startedAt"`
+	UpdatedAt     time.Time     `json:"updatedAt" yaml:"updatedAt"`
+}
+
+// RolloutStrategy describes how the traffic is shifted to the new version
+type RolloutStrategy string
+
+const (
+	// RolloutCanary shifts the traffic to the new version gradually
+	RolloutCanary RolloutStrategy = "canary"
+
+	// RolloutBlueGreen shifts all the traffic to the new version at once while keeping the old version around
+	RolloutBlueGreen RolloutStrategy = "blue-green"
+)
+
+// RolloutStatus describes the state of a rollout
+type RolloutStatus string
+
+const (
+	// RolloutProgressing is the status of rollouts which are shifting traffic
+	RolloutProgressing RolloutStatus = "progressing"
+
+	// RolloutPaused is the status of rollouts which have completed their steps and are waiting to be promoted
+	RolloutPaused RolloutStatus = "paused"
+
+	// RolloutPromoted is the status of rollouts whose new version receives all the traffic
+	RolloutPromoted RolloutStatus = "promoted"
+
+	// RolloutAborted is the status of rollouts which were aborted manually
+	RolloutAborted RolloutStatus = "aborted"
+
+	// RolloutRolledBack is the status of rollouts which were rolled back due to elevated error rates
+	RolloutRolledBack RolloutStatus = "rolled-back"
+)
+
+// IsActive returns true if the rollout is still in control of the routes of the service
+func (r *Rollout) IsActive() bool {
+	return r.Status == RolloutProgressing || r.Status == RolloutPaused
+}
@@ -0,0 +1,110 @@
+package rollout
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+const (
+	defaultStepInterval int64   = 60 // Time in seconds
+	defaultMaxErrorRate float64 = 0.05
+)
+
+func getRolloutKey(projectID, serviceID string) string {
+	return fmt.Sprintf("%s:%s", projectID, serviceID)
+}
+
+// setDefaults validates the rollout and sets the default values of the fields which weren't provided
+func setDefaults(rollout *model.Rollout) error {
+	if rollout.StableVersion == "" || rollout.CanaryVersion == "" {
+		return fmt.Errorf("both the stable and canary versions need to be provided")
+	}
+	if rollout.StableVersion == rollout.CanaryVersion {
+		return fmt.Errorf("the stable and canary versions cannot be the same")
+	}
+
+	switch rollout.Strategy {
+	case "", model.RolloutCanary:
+		rollout.Strategy = model.RolloutCanary
+		if len(rollout.Steps) == 0 {
+			rollout.Steps = []int32{10, 25, 50, 100}
+		}
+	case model.RolloutBlueGreen:
+		if len(rollout.Steps) > 0 {
+			return fmt.Errorf("steps cannot be provided for blue green rollouts")
+		}
+		rollout.Steps = []int32{100}
+	default:
+		return fmt.Errorf("invalid rollout strategy (%s) provided", rollout.Strategy)
+	}
+
+	var prev int32
+	for _, weight := range rollout.Steps {
+		if weight <= prev || weight > 100 {
+			return fmt.Errorf("steps of a rollout must be increasing weights between 1 and 100")
+		}
+		prev = weight
+	}
+
+	if rollout.StepInterval <= 0 {
+		rollout.StepInterval = defaultStepInterval
+	}
+	if rollout.MaxErrorRate <= 0 {
+		rollout.MaxErrorRate = defaultMaxErrorRate
+	}
+	if rollout.MaxErrorRate > 1 {
+		return fmt.Errorf("max error rate (%v) must be a ratio between 0 and 1", rollout.MaxErrorRate)
+	}
+	return nil
+}
+
+// generateDefaultRoutes returns a route for every port of the service which sends all the traffic to the version
+func generateDefaultRoutes(service *model.Service) model.Routes {
+	routes := make(model.Routes, 0)
+	for _, task := range service.Tasks {
+		for _, port := range task.Ports {
+			routes = append(routes, &model.Route{
+				ID:             strconv.Itoa(int(port.Port)),
+				RequestRetries: model.DefaultRequestRetries,
+				RequestTimeout: model.DefaultRequestTimeout,
+				Source:         model.RouteSource{Protocol: port.Protocol, Port: port.Port},
+				Targets:        []model.RouteTarget{{Type: model.RouteTargetVersion, Version: service.Version, Port: port.Port, Weight: 100}},
+			})
+		}
+	}
+	return routes
+}
+
+// generateWeightedRoutes splits the traffic of the routes which target versions of the service between the stable
+// and canary versions. Routes to external targets are left as is.
+func generateWeightedRoutes(routes model.Routes, stableVersion, canaryVersion string, canaryWeight int32) model.Routes {
+	newRoutes := make(model.Routes, len(routes))
+	for i, route := range routes {
+		newRoute := *route
+
+		// The port of the first version target is used for both the versions
+		port, ok := getVersionTargetPort(route)
+		if ok {
+			newRoute.Targets = make([]model.RouteTarget, 0, 2)
+			if canaryWeight < 100 {
+				newRoute.Targets = append(newRoute.Targets, model.RouteTarget{Type: model.RouteTargetVersion, Version: stableVersion, Port: port, Weight: 100 - canaryWeight})
+			}
+			if canaryWeight > 0 {
+				newRoute.Targets = append(newRoute.Targets, model.RouteTarget{Type: model.RouteTargetVersion, Version: canaryVersion, Port: port, Weight: canaryWeight})
+			}
+		}
+		newRoutes[i] = &newRoute
+	}
+	return newRoutes
+}
+
+func getVersionTargetPort(route *model.Route) (int32, bool) {
+	for _, target := range route.Targets {
+		if target.Type == model.RouteTargetVersion {
+			return target.Port, true
+		}
+	}
+	return 0, false
+}
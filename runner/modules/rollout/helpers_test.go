@@ -0,0 +1,130 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+func Test_setDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		rollout *model.Rollout
+		want    *model.Rollout
+		wantErr bool
+	}{
+		{
+			name:    "Canary rollout with defaults",
+			rollout: &model.Rollout{StableVersion: "v1", CanaryVersion: "v2"},
+			want:    &model.Rollout{Strategy: model.RolloutCanary, StableVersion: "v1", CanaryVersion: "v2", Steps: []int32{10, 25, 50, 100}, StepInterval: 60, MaxErrorRate: 0.05},
+		},
+		{
+			name:    "Blue green rollout",
+			rollout: &model.Rollout{Strategy: model.RolloutBlueGreen, StableVersion: "blue", CanaryVersion: "green", StepInterval: 300, MaxErrorRate: 0.01},
+			want:    &model.Rollout{Strategy: model.RolloutBlueGreen, StableVersion: "blue", CanaryVersion: "green", Steps: []int32{100}, StepInterval: 300, MaxErrorRate: 0.01},
+		},
+		{
+			name:    "Blue green rollout with steps",
+			rollout: &model.Rollout{Strategy: model.RolloutBlueGreen, StableVersion: "blue", CanaryVersion: "green", Steps: []int32{50, 100}},
+			wantErr: true,
+		},
+		{
+			name:    "Decreasing steps",
+			rollout: &model.Rollout{StableVersion: "v1", CanaryVersion: "v2", Steps: []int32{50, 20}},
+			wantErr: true,
+		},
+		{
+			name:    "Steps beyond 100",
+			rollout: &model.Rollout{StableVersion: "v1", CanaryVersion: "v2", Steps: []int32{50, 150}},
+			wantErr: true,
+		},
+		{
+			name:    "Same versions",
+			rollout: &model.Rollout{StableVersion: "v1", CanaryVersion: "v1"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid strategy",
+			rollout: &model.Rollout{Strategy: "rolling", StableVersion: "v1", CanaryVersion: "v2"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid max error rate",
+			rollout: &model.Rollout{StableVersion: "v1", CanaryVersion: "v2", MaxErrorRate: 5},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := setDefaults(tt.rollout)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("setDefaults() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if arr := deep.Equal(tt.rollout, tt.want); len(arr) > 0 {
+				t.Errorf("setDefaults() differences = %v", arr)
+			}
+		})
+	}
+}
+
+func Test_generateWeightedRoutes(t *testing.T) {
+	routes := model.Routes{
+		{
+			ID:       "http",
+			Source:   model.RouteSource{Protocol: model.HTTP, Port: 80},
+			Matchers: []*model.Matcher{{URL: &model.HTTPMatcher{Value: "/v1", Type: model.RouteHTTPMatchTypePrefix}}},
+			Targets:  []model.RouteTarget{{Type: model.RouteTargetVersion, Version: "v1", Port: 8080, Weight: 100}},
+		},
+		{
+			ID:      "external",
+			Source:  model.RouteSource{Protocol: model.HTTP, Port: 9090},
+			Targets: []model.RouteTarget{{Type: model.RouteTargetExternal, Host: "example.com", Port: 443, Weight: 100}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		weight int32
+		want   []model.RouteTarget
+	}{
+		{
+			name:   "All traffic to stable version",
+			weight: 0,
+			want:   []model.RouteTarget{{Type: model.RouteTargetVersion, Version: "v1", Port: 8080, Weight: 100}},
+		},
+		{
+			name:   "Split traffic",
+			weight: 25,
+			want: []model.RouteTarget{
+				{Type: model.RouteTargetVersion, Version: "v1", Port: 8080, Weight: 75},
+				{Type: model.RouteTargetVersion, Version: "v2", Port: 8080, Weight: 25},
+			},
+		},
+		{
+			name:   "All traffic to canary version",
+			weight: 100,
+			want:   []model.RouteTarget{{Type: model.RouteTargetVersion, Version: "v2", Port: 8080, Weight: 100}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateWeightedRoutes(routes, "v1", "v2", tt.weight)
+			if arr := deep.Equal(got[0].Targets, tt.want); len(arr) > 0 {
+				t.Errorf("generateWeightedRoutes() differences = %v", arr)
+			}
+			if arr := deep.Equal(got[0].Matchers, routes[0].Matchers); len(arr) > 0 {
+				t.Errorf("generateWeightedRoutes() matchers differences = %v", arr)
+			}
+			// Routes to external targets are left as is
+			if arr := deep.Equal(got[1], routes[1]); len(arr) > 0 {
+				t.Errorf("generateWeightedRoutes() external route differences = %v", arr)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+// StartRollout starts shifting the traffic of a service from the stable version to the canary version
+func (m *Module) StartRollout(ctx context.Context, rollout *model.Rollout) error {
+	if err := setDefaults(rollout); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid rollout provided", err, nil)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := getRolloutKey(rollout.ProjectID, rollout.ServiceID)
+	if state, p := m.rollouts[key]; p && state.rollout.IsActive() {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Rollout of service (%s) is already in progress - promote or abort it first", key), nil, nil)
+	}
+
+	// Make sure both the versions are deployed
+	services, err := m.driver.GetServices(ctx, rollout.ProjectID)
+	if err != nil {
+		return err
+	}
+	var stable, canary *model.Service
+	for _, service := range services {
+		if service.ID != rollout.ServiceID {
+			continue
+		}
+		switch service.Version {
+		case rollout.StableVersion:
+			stable = service
+		case rollout.CanaryVersion:
+			canary = service
+		}
+	}
+	if stable == nil || canary == nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Both versions (%s, %s) of service (%s) need to be deployed before starting a rollout", rollout.StableVersion, rollout.CanaryVersion, key), nil, nil)
+	}
+
+	// Use the existing routes of the service if there are any. Routes are generated from the ports of the stable
+	// version otherwise.
+	serviceRoutes, err := m.driver.GetServiceRoutes(ctx, rollout.ProjectID)
+	if err != nil {
+		return err
+	}
+	routes := serviceRoutes[rollout.ServiceID]
+	if len(routes) == 0 {
+		routes = generateDefaultRoutes(stable)
+	}
+	if len(routes) == 0 {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Service (%s) doesn't expose any ports to route traffic to", key), nil, nil)
+	}
+
+	now := time.Now()
+	rollout.Status = model.RolloutProgressing
+	rollout.CurrentStep = 0
+	rollout.CanaryWeight = 0
+	rollout.LastErrorRate = 0
+	rollout.Message = ""
+	rollout.StartedAt = now
+	rollout.UpdatedAt = now
+
+	state := &rolloutState{rollout: rollout, routes: routes}
+	if err := m.applyWeight(ctx, state, rollout.Steps[0]); err != nil {
+		return err
+	}
+
+	routineCtx, cancel := context.WithCancel(context.Background())
+	state.cancel = cancel
+	m.rollouts[key] = state
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Started %s rollout of service (%s) from version (%s) to (%s)", rollout.Strategy, key, rollout.StableVersion, rollout.CanaryVersion), nil)
+	go m.routineAnalyse(routineCtx, key, time.Duration(rollout.StepInterval)*time.Second)
+	return nil
+}
+
+// GetRollouts returns the rollouts of the services of a project
+func (m *Module) GetRollouts(projectID string) []*model.Rollout {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	rollouts := make([]*model.Rollout, 0)
+	for _, state := range m.rollouts {
+		if state.rollout.ProjectID == projectID {
+			rollout := *state.rollout
+			rollouts = append(rollouts, &rollout)
+		}
+	}
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].ServiceID < rollouts[j].ServiceID })
+	return rollouts
+}
+
+// PromoteRollout sends all the traffic of the service to the canary version and ends the rollout
+func (m *Module) PromoteRollout(ctx context.Context, projectID, serviceID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	state, err := m.getActiveRollout(ctx, projectID, serviceID)
+	if err != nil {
+		return err
+	}
+	return m.finish(ctx, state, model.RolloutPromoted, "Promoted manually")
+}
+
+// AbortRollout sends all the traffic of the service back to the stable version and ends the rollout
+func (m *Module) AbortRollout(ctx context.Context, projectID, serviceID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	state, err := m.getActiveRollout(ctx, projectID, serviceID)
+	if err != nil {
+		return err
+	}
+	return m.finish(ctx, state, model.RolloutAborted, "Aborted manually")
+}
+
+func (m *Module) getActiveRollout(ctx context.Context, projectID, serviceID string) (*rolloutState, error) {
+	key := getRolloutKey(projectID, serviceID)
+	state, p := m.rollouts[key]
+	if !p || !state.rollout.IsActive() {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("No rollout of service (%s) is in progress", key), nil, nil)
+	}
+	return state, nil
+}
+
+// finish ends the rollout by sending all the traffic to one of the versions. The lock must be held by the caller.
+func (m *Module) finish(ctx context.Context, state *rolloutState, status model.RolloutStatus, message string) error {
+	var weight int32
+	if status == model.RolloutPromoted {
+		weight = 100
+	}
+	if err := m.applyWeight(ctx, state, weight); err != nil {
+		return err
+	}
+
+	if state.cancel != nil {
+		state.cancel()
+	}
+	state.rollout.Status = status
+	state.rollout.Message = message
+	state.rollout.UpdatedAt = time.Now()
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Rollout of service (%s) ended with status (%s) - %s", getRolloutKey(state.rollout.ProjectID, state.rollout.ServiceID), status, message), nil)
+	return nil
+}
+
+// applyWeight sends the provided percentage of the traffic to the canary version. The lock must be held by the caller.
+func (m *Module) applyWeight(ctx context.Context, state *rolloutState, weight int32) error {
+	rollout := state.rollout
+	routes := generateWeightedRoutes(state.routes, rollout.StableVersion, rollout.CanaryVersion, weight)
+	if err := m.driver.ApplyServiceRoutes(ctx, rollout.ProjectID, rollout.ServiceID, routes); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to shift traffic of service (%s)", getRolloutKey(rollout.ProjectID, rollout.ServiceID)), err, nil)
+	}
+	rollout.CanaryWeight = weight
+	rollout.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,163 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+type fakeDriver struct {
+	services []*model.Service
+	routes   map[string]model.Routes
+}
+
+func (d *fakeDriver) GetServices(ctx context.Context, projectID string) ([]*model.Service, error) {
+	return d.services, nil
+}
+
+func (d *fakeDriver) ApplyServiceRoutes(ctx context.Context, projectID, serviceID string, routes model.Routes) error {
+	d.routes[serviceID] = routes
+	return nil
+}
+
+func (d *fakeDriver) GetServiceRoutes(ctx context.Context, projectID string) (map[string]model.Routes, error) {
+	return d.routes, nil
+}
+
+func (d *fakeDriver) canaryWeight(serviceID string) int32 {
+	for _, target := range d.routes[serviceID][0].Targets {
+		if target.Version == "v2" {
+			return target.Weight
+		}
+	}
+	return 0
+}
+
+func newFakeDriver() *fakeDriver {
+	tasks := []model.Task{{ID: "app", Ports: []model.Port{{Name: "http", Protocol: model.HTTP, Port: 8080}}}}
+	return &fakeDriver{
+		services: []*model.Service{
+			{ID: "orders", ProjectID: "myproject", Version: "v1", Tasks: tasks},
+			{ID: "orders", ProjectID: "myproject", Version: "v2", Tasks: tasks},
+		},
+		routes: map[string]model.Routes{},
+	}
+}
+
+func TestModule_analyse(t *testing.T) {
+	tests := []struct {
+		name        string
+		rollout     *model.Rollout
+		errorRates  []float64
+		wantWeights []int32
+		wantStatus  model.RolloutStatus
+	}{
+		{
+			name:        "Healthy canary rollout waits to be promoted",
+			rollout:     &model.Rollout{Steps: []int32{20, 50, 100}},
+			errorRates:  []float64{0, 0.01, 0, 0},
+			wantWeights: []int32{50, 100, 100, 100},
+			wantStatus:  model.RolloutPaused,
+		},
+		{
+			name:        "Healthy canary rollout is promoted automatically",
+			rollout:     &model.Rollout{Steps: []int32{50, 100}, AutoPromote: true},
+			errorRates:  []float64{0, 0},
+			wantWeights: []int32{100, 100},
+			wantStatus:  model.RolloutPromoted,
+		},
+		{
+			name:        "Canary rollout is rolled back on errors",
+			rollout:     &model.Rollout{Steps: []int32{20, 50, 100}},
+			errorRates:  []float64{0, 0.2},
+			wantWeights: []int32{50, 0},
+			wantStatus:  model.RolloutRolledBack,
+		},
+		{
+			name:        "Blue green rollout is rolled back on errors",
+			rollout:     &model.Rollout{Strategy: model.RolloutBlueGreen},
+			errorRates:  []float64{0, 0.1},
+			wantWeights: []int32{100, 0},
+			wantStatus:  model.RolloutRolledBack,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := newFakeDriver()
+			var tick int
+			m := New(driver, func(ctx context.Context, projectID, serviceID, version string) (float64, bool, error) {
+				return tt.errorRates[tick], true, nil
+			})
+			defer m.Close()
+
+			tt.rollout.ProjectID, tt.rollout.ServiceID, tt.rollout.StableVersion, tt.rollout.CanaryVersion = "myproject", "orders", "v1", "v2"
+			tt.rollout.StepInterval = 3600
+			if err := m.StartRollout(context.Background(), tt.rollout); err != nil {
+				t.Fatalf("StartRollout() error = %v", err)
+			}
+			if got := driver.canaryWeight("orders"); got != tt.rollout.Steps[0] {
+				t.Errorf("StartRollout() canary weight = %v, want %v", got, tt.rollout.Steps[0])
+			}
+
+			for tick = range tt.errorRates {
+				m.analyse(context.Background(), getRolloutKey("myproject", "orders"))
+				if got := driver.canaryWeight("orders"); got != tt.wantWeights[tick] {
+					t.Errorf("analyse() tick %d canary weight = %v, want %v", tick, got, tt.wantWeights[tick])
+				}
+			}
+
+			if got := m.GetRollouts("myproject")[0].Status; got != tt.wantStatus {
+				t.Errorf("analyse() status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestModule_PromoteAndAbort(t *testing.T) {
+	driver := newFakeDriver()
+	m := New(driver, func(ctx context.Context, projectID, serviceID, version string) (float64, bool, error) {
+		return 0, false, nil
+	})
+	defer m.Close()
+
+	ctx := context.Background()
+	if err := m.PromoteRollout(ctx, "myproject", "orders"); err == nil {
+		t.Error("PromoteRollout() error = nil, want error when no rollout is in progress")
+	}
+
+	rollout := &model.Rollout{ProjectID: "myproject", ServiceID: "orders", StableVersion: "v1", CanaryVersion: "v2"}
+	if err := m.StartRollout(ctx, rollout); err != nil {
+		t.Fatalf("StartRollout() error = %v", err)
+	}
+	if err := m.StartRollout(ctx, &model.Rollout{ProjectID: "myproject", ServiceID: "orders", StableVersion: "v1", CanaryVersion: "v2"}); err == nil {
+		t.Error("StartRollout() error = nil, want error when a rollout is in progress")
+	}
+
+	if err := m.AbortRollout(ctx, "myproject", "orders"); err != nil {
+		t.Fatalf("AbortRollout() error = %v", err)
+	}
+	if got := driver.canaryWeight("orders"); got != 0 {
+		t.Errorf("AbortRollout() canary weight = %v, want 0", got)
+	}
+	if err := m.AbortRollout(ctx, "myproject", "orders"); err == nil {
+		t.Error("AbortRollout() error = nil, want error when the rollout has ended")
+	}
+
+	if err := m.StartRollout(ctx, &model.Rollout{ProjectID: "myproject", ServiceID: "orders", StableVersion: "v1", CanaryVersion: "v2"}); err != nil {
+		t.Fatalf("StartRollout() error = %v", err)
+	}
+	if err := m.PromoteRollout(ctx, "myproject", "orders"); err != nil {
+		t.Fatalf("PromoteRollout() error = %v", err)
+	}
+	if got := driver.canaryWeight("orders"); got != 100 {
+		t.Errorf("PromoteRollout() canary weight = %v, want 100", got)
+	}
+	if got := m.GetRollouts("myproject")[0].Status; got != model.RolloutPromoted {
+		t.Errorf("PromoteRollout() status = %v, want %v", got, model.RolloutPromoted)
+	}
+
+	if err := m.StartRollout(ctx, &model.Rollout{ProjectID: "myproject", ServiceID: "orders", StableVersion: "v1", CanaryVersion: "v3"}); err == nil {
+		t.Error("StartRollout() error = nil, want error for a version which isn't deployed")
+	}
+}
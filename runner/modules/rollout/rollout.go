@@ -0,0 +1,52 @@
+package rollout
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+// Driver is the subset of the driver used to shift the traffic of services
+type Driver interface {
+	GetServices(ctx context.Context, projectID string) ([]*model.Service, error)
+	ApplyServiceRoutes(ctx context.Context, projectID, serviceID string, routes model.Routes) error
+	GetServiceRoutes(ctx context.Context, projectID string) (map[string]model.Routes, error)
+}
+
+// Module is responsible for the blue green and canary rollouts of services. It shifts the traffic of a service from
+// its stable version to a new version by updating the service routes, and rolls the traffic back to the stable
+// version if the new version has an elevated error rate. Rollouts are held in memory and are lost when the runner
+// restarts, while the routes applied till that point stay in effect.
+type Module struct {
+	lock sync.Mutex
+
+	// Rollouts of services (key -> project:service)
+	rollouts map[string]*rolloutState
+
+	driver    Driver
+	errorRate model.ErrorRateHook
+}
+
+type rolloutState struct {
+	rollout *model.Rollout
+	routes  model.Routes // The routes of the service which get their weights updated
+	cancel  context.CancelFunc
+}
+
+// New creates a new instance of the rollout module
+func New(driver Driver, errorRate model.ErrorRateHook) *Module {
+	return &Module{rollouts: map[string]*rolloutState{}, driver: driver, errorRate: errorRate}
+}
+
+// Close stops all the active rollouts. The routes of the services are left as is.
+func (m *Module) Close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, state := range m.rollouts {
+		if state.cancel != nil {
+			state.cancel()
+		}
+	}
+}
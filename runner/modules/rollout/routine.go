@@ -0,0 +1,76 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+func (m *Module) routineAnalyse(ctx context.Context, key string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if done := m.analyse(ctx, key); done {
+				return
+			}
+		}
+	}
+}
+
+// analyse checks the error rate of the canary version and moves the rollout to the next step if it is healthy. The
+// rollout is rolled back if the error rate exceeds the max error rate. It returns true once the rollout has ended.
+func (m *Module) analyse(ctx context.Context, key string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	state, p := m.rollouts[key]
+	if !p || !state.rollout.IsActive() {
+		return true
+	}
+	rollout := state.rollout
+
+	// Missing metrics don't block the rollout. The step is considered healthy in that case.
+	errorRate, ok, err := m.errorRate(ctx, rollout.ProjectID, rollout.ServiceID, rollout.CanaryVersion)
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to get error rate of service (%s) during rollout", key), err, nil)
+	}
+	if ok {
+		rollout.LastErrorRate = errorRate
+		if errorRate > rollout.MaxErrorRate {
+			message := fmt.Sprintf("Error rate (%.4f) of version (%s) exceeded the max error rate (%.4f)", errorRate, rollout.CanaryVersion, rollout.MaxErrorRate)
+			if err := m.finish(ctx, state, model.RolloutRolledBack, message); err != nil {
+				// Retry the rollback on the next tick
+				return false
+			}
+			return true
+		}
+	}
+
+	// Rollouts which have completed their steps wait for a promotion while their analysis continues
+	if rollout.CurrentStep == len(rollout.Steps)-1 {
+		if rollout.AutoPromote {
+			return m.finish(ctx, state, model.RolloutPromoted, "Promoted automatically after a successful analysis") == nil
+		}
+		if rollout.Status != model.RolloutPaused {
+			rollout.Status = model.RolloutPaused
+			rollout.UpdatedAt = time.Now()
+		}
+		return false
+	}
+
+	if err := m.applyWeight(ctx, state, rollout.Steps[rollout.CurrentStep+1]); err != nil {
+		// Retry the step on the next tick
+		return false
+	}
+	rollout.CurrentStep++
+	return false
+}
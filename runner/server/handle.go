@@ -448,6 +448,11 @@ func (s *Server) HandleGetServiceRoutingRequest() http.HandlerFunc {
 	}
 }
 
+// proxyTargetResolver resolves the destination of proxied requests which don't have the original destination headers
+type proxyTargetResolver interface {
+	ResolveProxyTarget(ctx context.Context, host string) (*model.ProxyTarget, error)
+}
+
 func (s *Server) handleProxy() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Minute)
@@ -467,6 +472,22 @@ func (s *Server) handleProxy() http.HandlerFunc {
 		ogPort := r.Header.Get("x-og-port")
 		ogVersion := r.Header.Get("x-og-version")
 
+		// Requests routed to the proxy without istio don't carry the original destination headers. The driver
+		// resolves the destination from the host instead.
+		if project == "" {
+			resolver, ok := s.driver.(proxyTargetResolver)
+			if !ok {
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, fmt.Errorf("original destination headers not provided"))
+				return
+			}
+			target, err := resolver.ResolveProxyTarget(ctx, r.Host)
+			if err != nil {
+				_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadGateway, err)
+				return
+			}
+			project, service, ogHost, ogPort, ogVersion = target.Project, target.Service, target.Host, target.Port, target.Version
+		}
+
 		// Delete the headers
 		r.Header.Del("x-og-project")
 		r.Header.Del("x-og-service")
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+	"github.com/spaceuptech/space-cloud/runner/utils"
+)
+
+// HandleStartRollout handles request to start a rollout of a service
+func (s *Server) HandleStartRollout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Verify token
+		_, err := s.auth.VerifyToken(utils.GetToken(r))
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to start rollout", err, nil)
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		req := new(model.Rollout)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to start rollout", err, nil)
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		vars := mux.Vars(r)
+		req.ProjectID = vars["project"]
+		req.ServiceID = vars["serviceId"]
+
+		if err := s.rollout.StartRollout(ctx, req); err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
+	}
+}
+
+// HandleGetRollouts handles request to get the rollouts of the services of a project
+func (s *Server) HandleGetRollouts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Verify token
+		_, err := s.auth.VerifyToken(utils.GetToken(r))
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to get rollouts", err, nil)
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		vars := mux.Vars(r)
+		projectID := vars["project"]
+		serviceID, exists := r.URL.Query()["id"]
+
+		rollouts := s.rollout.GetRollouts(projectID)
+		if exists {
+			result := make([]*model.Rollout, 0)
+			for _, rollout := range rollouts {
+				if rollout.ServiceID == serviceID[0] {
+					result = append(result, rollout)
+				}
+			}
+			rollouts = result
+		}
+
+		_ = helpers.Response.SendResponse(ctx, w, http.StatusOK, model.Response{Result: rollouts})
+	}
+}
+
+// HandlePromoteRollout handles request to send all the traffic of a service to the new version of its rollout
+func (s *Server) HandlePromoteRollout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Verify token
+		_, err := s.auth.VerifyToken(utils.GetToken(r))
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to promote rollout", err, nil)
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		vars := mux.Vars(r)
+		if err := s.rollout.PromoteRollout(ctx, vars["project"], vars["serviceId"]); err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
+	}
+}
+
+// HandleAbortRollout handles request to send all the traffic of a service back to the stable version of its rollout
+func (s *Server) HandleAbortRollout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		defer utils.CloseTheCloser(r.Body)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Verify token
+		_, err := s.auth.VerifyToken(utils.GetToken(r))
+		if err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to abort rollout", err, nil)
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusUnauthorized, err)
+			return
+		}
+
+		vars := mux.Vars(r)
+		if err := s.rollout.AbortRollout(ctx, vars["project"], vars["serviceId"]); err != nil {
+			_ = helpers.Response.SendErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		_ = helpers.Response.SendOkayResponse(ctx, http.StatusOK, w)
+	}
+}
@@ -22,6 +22,12 @@ func (s *Server) routes() {
 	s.router.Methods(http.MethodPost).Path("/v1/runner/{project}/service-routes/{serviceId}").HandlerFunc(s.HandleServiceRoutingRequest())
 	s.router.Methods(http.MethodGet).Path("/v1/runner/{project}/service-routes").HandlerFunc(s.HandleGetServiceRoutingRequest())
 
+	// rollout routes
+	s.router.Methods(http.MethodPost).Path("/v1/runner/{project}/rollouts/{serviceId}").HandlerFunc(s.HandleStartRollout())
+	s.router.Methods(http.MethodGet).Path("/v1/runner/{project}/rollouts").HandlerFunc(s.HandleGetRollouts())
+	s.router.Methods(http.MethodPost).Path("/v1/runner/{project}/rollouts/{serviceId}/promote").HandlerFunc(s.HandlePromoteRollout())
+	s.router.Methods(http.MethodPost).Path("/v1/runner/{project}/rollouts/{serviceId}/abort").HandlerFunc(s.HandleAbortRollout())
+
 	s.router.Methods(http.MethodPost).Path("/v1/runner/{project}/service-roles/{serviceId}/{roleId}").HandlerFunc(s.HandleSetServiceRole())
 	s.router.Methods(http.MethodGet).Path("/v1/runner/{project}/service-roles").HandlerFunc(s.HandleGetServiceRoleRequest())
 	s.router.Methods(http.MethodDelete).Path("/v1/runner/{project}/service-roles/{serviceId}/{roleId}").HandlerFunc(s.HandleDeleteServiceRole())
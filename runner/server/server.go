@@ -9,6 +9,7 @@ import (
 	"github.com/spaceuptech/helpers"
 
 	"github.com/spaceuptech/space-cloud/runner/metrics"
+	"github.com/spaceuptech/space-cloud/runner/modules/rollout"
 
 	"github.com/gorilla/mux"
 
@@ -32,6 +33,9 @@ type Server struct {
 	auth     *auth.Module
 	driver   driver.Interface
 	debounce *utils.Debounce
+
+	// For shifting traffic between versions of services
+	rollout *rollout.Module
 }
 
 // New creates a new instance of the runner
@@ -43,7 +47,10 @@ func New(c *Config) (*Server, error) {
 	}
 	c.Driver.ProxyPort = uint32(proxyPort)
 
-	metric := metrics.New(c.IsMetricDisabled, c.Driver.DriverType)
+	metric, err := metrics.New(c.IsMetricDisabled, c.Driver.DriverType, c.Driver.PrometheusAddr)
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialise all modules
 	a, err := auth.New(c.Auth)
@@ -69,6 +76,8 @@ func New(c *Config) (*Server, error) {
 		auth:     a,
 		driver:   d,
 		debounce: debounce,
+
+		rollout: rollout.New(d, metric.GetErrorRate),
 	}, nil
 }
 
@@ -0,0 +1,236 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+	"github.com/spaceuptech/space-cloud/runner/utils"
+)
+
+// ApplyService runs the service on the docker daemon. The existing containers of the same version of the service
+// are replaced.
+func (d *Docker) ApplyService(ctx context.Context, service *model.Service) error {
+	// Get the list of secrets required for this service
+	secrets, err := d.getSecrets(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	// Create the project network if it doesn't already exist
+	if err := d.createNetworkIfNotExist(ctx, service.ProjectID); err != nil {
+		return err
+	}
+
+	// Pull the images of all the tasks
+	for _, task := range service.Tasks {
+		if err := d.pullImageIfRequired(ctx, task.Docker, secrets); err != nil {
+			return err
+		}
+	}
+
+	// Remove the previous containers of this version
+	if err := d.DeleteService(ctx, service.ProjectID, service.ID, service.Version); err != nil {
+		return err
+	}
+
+	// Atleast one replica is created so that the service can be scaled up from zero
+	replicas := getDesiredReplicas(service)
+	for i := 0; i < replicas || i == 0; i++ {
+		replicaID := getReplicaID(service.ID, service.Version, i)
+		configs, err := d.generateContainerConfigs(service, replicaID, secrets)
+		if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to generate containers of service (%s:%s)", service.ProjectID, service.ID), err, nil)
+		}
+
+		for j, config := range configs {
+			name := getContainerName(service.ProjectID, replicaID, service.Tasks[j].ID)
+			helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Creating container (%s)", name), nil)
+			if err := d.do(ctx, http.MethodPost, "/containers/create", url.Values{"name": []string{name}}, nil, config, nil); err != nil {
+				return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create container (%s)", name), err, nil)
+			}
+		}
+
+		if i < replicas {
+			if err := d.startReplica(ctx, service.ProjectID, replicaID, service.Tasks); err != nil {
+				return err
+			}
+		}
+	}
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Service (%s:%s) applied successfully", service.ProjectID, service.ID), nil)
+	return nil
+}
+
+// ApplyServiceRoutes stores the routing rules of the service
+func (d *Docker) ApplyServiceRoutes(ctx context.Context, projectID, serviceID string, routes model.Routes) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	serviceRoutes := map[string]model.Routes{}
+	if err := d.readStore(d.getRoutesPath(projectID), &serviceRoutes); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read routes of project (%s)", projectID), err, nil)
+	}
+
+	serviceRoutes[serviceID] = routes
+	if err := d.writeStore(d.getRoutesPath(projectID), serviceRoutes); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to store routes of service (%s:%s)", projectID, serviceID), err, nil)
+	}
+	return nil
+}
+
+// ApplyServiceRole isn't supported since containers on docker don't get any access to the cluster
+func (d *Docker) ApplyServiceRole(ctx context.Context, role *model.Role) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Service roles are not supported by the docker driver", nil, nil)
+}
+
+// ScaleUp starts the containers of a service which has been scaled down to zero
+func (d *Docker) ScaleUp(ctx context.Context, projectID, serviceID, version string) error {
+	containers, err := d.listContainers(ctx, map[string]string{labelProject: projectID, labelService: serviceID, labelVersion: version})
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to scale up service", err, map[string]interface{}{"project": projectID, "service": serviceID, "version": version})
+	}
+	if len(containers) == 0 {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Service (%s:%s:%s) does not exist", projectID, serviceID, version), nil, nil)
+	}
+
+	for _, c := range containers {
+		if c.State == "running" {
+			return nil
+		}
+	}
+
+	service := new(model.Service)
+	if err := json.Unmarshal([]byte(containers[0].Labels[labelSpec]), service); err != nil {
+		return err
+	}
+	return d.startReplica(ctx, projectID, getReplicaID(serviceID, version, 0), service.Tasks)
+}
+
+// WaitForService waits till all the tasks of a replica of the service are running
+func (d *Docker) WaitForService(ctx context.Context, service *model.Service) error {
+	ns := service.ProjectID
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Waiting for service (%s:%s:%s) to start", ns, service.ID, service.Version), nil)
+
+	ctx, cancel := context.WithTimeout(ctx, waitForServiceTimeout)
+	defer cancel()
+
+	for {
+		containers, err := d.listContainers(ctx, map[string]string{labelProject: ns, labelService: service.ID, labelVersion: service.Version})
+		if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("service (%s:%s:%s) could not be started", ns, service.ID, service.Version), err, nil)
+		}
+		for _, status := range groupReplicas(containers) {
+			if status.Status == "RUNNING" {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("service (%s:%s) could not be started", ns, service.ID), ctx.Err(), nil)
+		case <-time.After(waitForServiceInterval):
+		}
+	}
+}
+
+func (d *Docker) startReplica(ctx context.Context, projectID, replicaID string, tasks []model.Task) error {
+	for _, task := range tasks {
+		name := getContainerName(projectID, replicaID, task.ID)
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Starting container (%s)", name), nil)
+		if err := d.do(ctx, http.MethodPost, fmt.Sprintf("/containers/%s/start", name), nil, nil, nil, nil); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to start container (%s)", name), err, nil)
+		}
+	}
+	return nil
+}
+
+func (d *Docker) createNetworkIfNotExist(ctx context.Context, projectID string) error {
+	name := getNetworkName(projectID)
+	err := d.do(ctx, http.MethodGet, "/networks/"+name, nil, nil, nil, nil)
+	if err == nil {
+		return nil
+	}
+	if err != errNotFound {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to inspect network (%s)", name), err, nil)
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Creating network (%s)", name), nil)
+	network := map[string]interface{}{
+		"Name":           name,
+		"CheckDuplicate": true,
+		"Labels":         map[string]string{labelManagedBy: managedBy, labelCluster: d.config.ClusterName, labelProject: projectID},
+	}
+	if err := d.do(ctx, http.MethodPost, "/networks/create", nil, nil, network, nil); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to create network (%s)", name), err, nil)
+	}
+	return nil
+}
+
+// pullImageIfRequired pulls the image as per the pull policy of the task
+func (d *Docker) pullImageIfRequired(ctx context.Context, task model.Docker, secrets map[string]*model.Secret) error {
+	if task.ImagePullPolicy == model.PullIfNotExists {
+		err := d.do(ctx, http.MethodGet, fmt.Sprintf("/images/%s/json", task.Image), nil, nil, nil, nil)
+		if err == nil {
+			return nil
+		}
+		if err != errNotFound {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to inspect image (%s)", task.Image), err, nil)
+		}
+	}
+
+	headers := map[string]string{}
+	if task.Secret != "" {
+		secret, ok := secrets[task.Secret]
+		if !ok || secret.Type != model.DockerType {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Docker secret (%s) used to pull image (%s) does not exist", task.Secret, task.Image), nil, nil)
+		}
+		data, _ := json.Marshal(map[string]string{"username": secret.Data["username"], "password": secret.Data["password"], "serveraddress": secret.Data["url"]})
+		headers["X-Registry-Auth"] = base64.URLEncoding.EncodeToString(data)
+	}
+
+	image, tag := splitImageTag(task.Image)
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Pulling image (%s)", task.Image), nil)
+	res, err := d.stream(ctx, http.MethodPost, "/images/create", url.Values{"fromImage": []string{image}, "tag": []string{tag}}, headers, nil)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to pull image (%s)", task.Image), err, nil)
+	}
+	defer utils.CloseTheCloser(res)
+
+	// The progress of the pull is streamed as json messages. Errors are reported in the same stream.
+	decoder := json.NewDecoder(res)
+	for {
+		msg := struct {
+			Error string `json:"error"`
+		}{}
+		if err := decoder.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to pull image (%s)", task.Image), err, nil)
+		}
+		if msg.Error != "" {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to pull image (%s)", task.Image), errors.New(msg.Error), nil)
+		}
+	}
+}
+
+// splitImageTag splits the image into its name and tag. The tag defaults to latest.
+func splitImageTag(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
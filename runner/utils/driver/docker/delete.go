@@ -0,0 +1,36 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spaceuptech/helpers"
+)
+
+// DeleteService removes the containers of the provided version of the service
+func (d *Docker) DeleteService(ctx context.Context, projectID, serviceID, version string) error {
+	containers, err := d.listContainers(ctx, map[string]string{labelProject: projectID, labelService: serviceID, labelVersion: version})
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to find containers of service (%s:%s:%s)", projectID, serviceID, version), err, nil)
+	}
+
+	return d.removeContainers(ctx, containers)
+}
+
+// DeleteServiceRole is a no-op since service roles aren't supported by the docker driver
+func (d *Docker) DeleteServiceRole(ctx context.Context, projectID, serviceID, id string) error {
+	return nil
+}
+
+func (d *Docker) removeContainers(ctx context.Context, containers []*containerSummary) error {
+	for _, c := range containers {
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Removing container (%s)", c.ID), nil)
+		err := d.do(ctx, http.MethodDelete, "/containers/"+c.ID, url.Values{"force": []string{"true"}}, nil, nil, nil)
+		if err != nil && err != errNotFound {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to remove container (%s)", c.ID), err, nil)
+		}
+	}
+	return nil
+}
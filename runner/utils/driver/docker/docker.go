@@ -0,0 +1,182 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+	"github.com/spaceuptech/space-cloud/runner/utils"
+	"github.com/spaceuptech/space-cloud/runner/utils/auth"
+)
+
+const (
+	// apiVersion is the version of the docker engine api used by the driver
+	apiVersion = "v1.40"
+
+	// waitForServiceTimeout is the duration for which the driver waits for a service to start
+	waitForServiceTimeout = 5 * time.Minute
+
+	// waitForServiceInterval is the interval at which the status of a starting service is checked
+	waitForServiceInterval = time.Second
+)
+
+// errNotFound is returned when the docker daemon responds with a 404
+var errNotFound = errors.New("resource not found in docker")
+
+// Config describes the configuration used by the docker driver
+type Config struct {
+	// Host is the address of the docker daemon (eg. unix:///var/run/docker.sock or tcp://127.0.0.1:2375)
+	Host string
+
+	// StorePath is the directory in which the secrets and routes of the services are stored
+	StorePath string
+
+	// ClusterName is added as a label to every container started by the driver
+	ClusterName string
+}
+
+// GenerateConfig returns the docker config. The docker host is read from the `DOCKER_HOST` environment variable
+// and the store path defaults to `~/.space-cloud/runner` if not provided.
+func GenerateConfig(storePath, clusterName string) *Config {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+
+	if storePath == "" {
+		home, _ := os.UserHomeDir()
+		storePath = filepath.Join(home, ".space-cloud", "runner")
+	}
+	return &Config{Host: host, StorePath: storePath, ClusterName: clusterName}
+}
+
+// Docker manages the services on the local docker daemon
+type Docker struct {
+	// For internal use
+	auth   *auth.Module
+	config *Config
+
+	// lock guards the files in the store path
+	lock sync.Mutex
+
+	// Client to talk to the docker daemon
+	client  *http.Client
+	baseURL string
+}
+
+// NewDockerDriver creates a new instance of the docker driver
+func NewDockerDriver(auth *auth.Module, c *Config) (*Docker, error) {
+	u, err := url.Parse(c.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host (%s) provided - %v", c.Host, err)
+	}
+
+	d := &Docker{auth: auth, config: c}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		d.client = &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return new(net.Dialer).DialContext(ctx, "unix", socket)
+			},
+		}}
+		d.baseURL = "http://docker"
+	case "tcp", "http":
+		d.client = http.DefaultClient
+		d.baseURL = "http://" + u.Host
+	case "https":
+		d.client = http.DefaultClient
+		d.baseURL = "https://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported docker host scheme (%s) provided", u.Scheme)
+	}
+
+	if err := os.MkdirAll(c.StorePath, 0700); err != nil {
+		return nil, err
+	}
+
+	// Make sure the docker daemon is reachable
+	if err := d.do(context.Background(), http.MethodGet, "/_ping", nil, nil, nil, nil); err != nil {
+		return nil, fmt.Errorf("unable to connect to the docker daemon at (%s) - %v", c.Host, err)
+	}
+	return d, nil
+}
+
+// Type returns the type of the driver
+func (d *Docker) Type() model.DriverType {
+	return model.TypeDocker
+}
+
+// do makes a request to the docker engine api and decodes the response in `out` if provided
+func (d *Docker) do(ctx context.Context, method, path string, query url.Values, headers map[string]string, body, out interface{}) error {
+	res, err := d.stream(ctx, method, path, query, headers, body)
+	if err != nil {
+		return err
+	}
+	defer utils.CloseTheCloser(res)
+
+	if out == nil {
+		_, _ = io.Copy(ioutil.Discard, res)
+		return nil
+	}
+	return json.NewDecoder(res).Decode(out)
+}
+
+// stream makes a request to the docker engine api and returns the response body
+func (d *Docker) stream(ctx context.Context, method, path string, query url.Values, headers map[string]string, body interface{}) (io.ReadCloser, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := fmt.Sprintf("%s/%s%s", d.baseURL, apiVersion, path)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		defer utils.CloseTheCloser(res.Body)
+		if res.StatusCode == http.StatusNotFound {
+			return nil, errNotFound
+		}
+
+		msg := struct {
+			Message string `json:"message"`
+		}{}
+		_ = json.NewDecoder(res.Body).Decode(&msg)
+		return nil, fmt.Errorf("docker responded with status (%d) - %s", res.StatusCode, strings.TrimSpace(msg.Message))
+	}
+	return res.Body, nil
+}
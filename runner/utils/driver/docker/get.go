@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+// GetServices gets the services running on docker
+func (d *Docker) GetServices(ctx context.Context, projectID string) ([]*model.Service, error) {
+	containers, err := d.listContainers(ctx, map[string]string{labelProject: projectID})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to find containers in project", err, nil)
+	}
+
+	services := []*model.Service{}
+	seen := map[string]bool{}
+	for _, c := range containers {
+		key := c.Labels[labelService] + ":" + c.Labels[labelVersion]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		// The spec of the service is stored as a label on each of its containers
+		service := new(model.Service)
+		if err := json.Unmarshal([]byte(c.Labels[labelSpec]), service); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid spec of service (%s) found in container (%s)", key, c.ID), err, nil)
+		}
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].ID != services[j].ID {
+			return services[i].ID < services[j].ID
+		}
+		return services[i].Version < services[j].Version
+	})
+	return services, nil
+}
+
+// GetServiceStatus gets the status of the replicas of each service
+func (d *Docker) GetServiceStatus(ctx context.Context, projectID string) ([]*model.ServiceStatus, error) {
+	containers, err := d.listContainers(ctx, map[string]string{labelProject: projectID})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Error getting service in docker - unable to find containers", err, nil)
+	}
+
+	result := make([]*model.ServiceStatus, 0)
+	byService := map[string]*model.ServiceStatus{}
+	for _, c := range containers {
+		key := c.Labels[labelService] + ":" + c.Labels[labelVersion]
+		if _, ok := byService[key]; ok {
+			continue
+		}
+
+		desired, _ := strconv.Atoi(c.Labels[labelReplicas])
+		status := &model.ServiceStatus{ServiceID: c.Labels[labelService], Version: c.Labels[labelVersion], DesiredReplicas: desired, Replicas: make([]*model.ReplicaInfo, 0)}
+		byService[key] = status
+		result = append(result, status)
+	}
+
+	for _, replica := range groupReplicas(containers) {
+		serviceID, version := splitReplicaID(replica.ID)
+		if status, ok := byService[serviceID+":"+version]; ok {
+			status.Replicas = append(status.Replicas, replica)
+		}
+	}
+	return result, nil
+}
+
+// groupReplicas returns the status of each replica. A replica is running only if all of its tasks are running.
+// Replicas which were never started are skipped.
+func groupReplicas(containers []*containerSummary) []*model.ReplicaInfo {
+	replicas := make([]*model.ReplicaInfo, 0)
+	byID := map[string]*model.ReplicaInfo{}
+	for _, c := range containers {
+		id := c.Labels[labelReplica]
+		state := strings.ToUpper(c.State)
+
+		replica, ok := byID[id]
+		if !ok {
+			replica = &model.ReplicaInfo{ID: id, Status: state}
+			byID[id] = replica
+			replicas = append(replicas, replica)
+			continue
+		}
+		if replica.Status == "RUNNING" {
+			replica.Status = state
+		}
+	}
+
+	result := make([]*model.ReplicaInfo, 0, len(replicas))
+	for _, replica := range replicas {
+		if replica.Status != "CREATED" {
+			result = append(result, replica)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// GetServiceRoutes gets the routing rules of each service
+func (d *Docker) GetServiceRoutes(ctx context.Context, projectID string) (map[string]model.Routes, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	serviceRoutes := map[string]model.Routes{}
+	if err := d.readStore(d.getRoutesPath(projectID), &serviceRoutes); err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read routes of project (%s)", projectID), err, nil)
+	}
+	return serviceRoutes, nil
+}
+
+// GetServiceRole returns an empty list since service roles aren't supported by the docker driver
+func (d *Docker) GetServiceRole(ctx context.Context, projectID string) ([]*model.Role, error) {
+	return []*model.Role{}, nil
+}
+
+func (d *Docker) listContainers(ctx context.Context, labels map[string]string) ([]*containerSummary, error) {
+	containers := []*containerSummary{}
+	query := url.Values{"all": []string{"true"}, "filters": []string{getLabelFilters(labels)}}
+	if err := d.do(ctx, http.MethodGet, "/containers/json", query, nil, nil, &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
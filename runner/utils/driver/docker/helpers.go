@@ -0,0 +1,177 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+	"github.com/spaceuptech/space-cloud/runner/utils"
+)
+
+const (
+	runtimeEnvVariable string = "SC_RUNTIME"
+
+	// restartPolicy restarts the containers when they exit unless they were stopped explicitly
+	restartPolicy = "unless-stopped"
+)
+
+type containerConfig struct {
+	Image            string              `json:"Image"`
+	Entrypoint       []string            `json:"Entrypoint,omitempty"`
+	Cmd              []string            `json:"Cmd,omitempty"`
+	Env              []string            `json:"Env,omitempty"`
+	Labels           map[string]string   `json:"Labels"`
+	ExposedPorts     map[string]struct{} `json:"ExposedPorts,omitempty"`
+	HostConfig       hostConfig          `json:"HostConfig"`
+	NetworkingConfig *networkingConfig   `json:"NetworkingConfig,omitempty"`
+}
+
+type hostConfig struct {
+	RestartPolicy  restartPolicyConfig `json:"RestartPolicy"`
+	NetworkMode    string              `json:"NetworkMode,omitempty"`
+	NanoCpus       int64               `json:"NanoCpus,omitempty"`
+	Memory         int64               `json:"Memory,omitempty"`
+	Binds          []string            `json:"Binds,omitempty"`
+	DeviceRequests []deviceRequest     `json:"DeviceRequests,omitempty"`
+}
+
+type restartPolicyConfig struct {
+	Name string `json:"Name"`
+}
+
+type deviceRequest struct {
+	Driver       string     `json:"Driver"`
+	Count        int64      `json:"Count"`
+	Capabilities [][]string `json:"Capabilities"`
+}
+
+type networkingConfig struct {
+	EndpointsConfig map[string]endpointConfig `json:"EndpointsConfig"`
+}
+
+type endpointConfig struct {
+	Aliases []string `json:"Aliases"`
+}
+
+type containerSummary struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	State  string            `json:"State"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// getDesiredReplicas returns the number of replicas to be started for the service
+func getDesiredReplicas(service *model.Service) int {
+	if service.AutoScale != nil {
+		return int(service.AutoScale.MinReplicas)
+	}
+	if service.Scale != nil {
+		return int(service.Scale.Replicas)
+	}
+	return 1
+}
+
+// generateContainerConfigs returns the config of the containers of a replica of the service. The first task joins
+// the project network with the service domains as aliases, while the rest of the tasks share its network namespace
+// so that the tasks of a replica can reach each other on localhost like the containers of a pod.
+func (d *Docker) generateContainerConfigs(service *model.Service, replicaID string, secrets map[string]*model.Secret) ([]*containerConfig, error) {
+	spec, err := json.Marshal(service)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*containerConfig, len(service.Tasks))
+	for i, task := range service.Tasks {
+		// Prepare env variables
+		env := make([]string, 0, len(task.Env)+1)
+		for k, v := range task.Env {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+		// Add an environment variable to hold the runtime value
+		env = append(env, fmt.Sprintf("%s=%s", runtimeEnvVariable, task.Runtime))
+
+		// Inject the secrets
+		var binds []string
+		for _, secretName := range task.Secrets {
+			secret, ok := secrets[secretName]
+			if !ok {
+				return nil, fmt.Errorf("secret (%s) used by task (%s) does not exist", secretName, task.ID)
+			}
+			switch secret.Type {
+			case model.EnvType:
+				for k, v := range secret.Data {
+					env = append(env, fmt.Sprintf("%s=%s", k, v))
+				}
+			case model.FileType:
+				binds = append(binds, fmt.Sprintf("%s:%s:ro", d.getFileSecretPath(service.ProjectID, secret.ID), secret.RootPath))
+			}
+		}
+		sort.Strings(env)
+
+		// Prepare command and args
+		var entrypoint, cmd []string
+		if len(task.Docker.Cmd) > 0 {
+			entrypoint = task.Docker.Cmd[0:1]
+			cmd = task.Docker.Cmd[1:]
+		}
+
+		ports := make(map[string]struct{}, len(task.Ports))
+		for _, port := range task.Ports {
+			ports[strconv.Itoa(int(port.Port))+"/tcp"] = struct{}{}
+		}
+
+		config := &containerConfig{
+			Image:        task.Docker.Image,
+			Entrypoint:   entrypoint,
+			Cmd:          cmd,
+			Env:          env,
+			ExposedPorts: ports,
+			Labels: map[string]string{
+				labelManagedBy: managedBy,
+				labelCluster:   d.config.ClusterName,
+				labelProject:   service.ProjectID,
+				labelService:   service.ID,
+				labelVersion:   service.Version,
+				labelReplica:   replicaID,
+				labelTask:      task.ID,
+				labelReplicas:  strconv.Itoa(getDesiredReplicas(service)),
+				labelSpec:      string(spec),
+			},
+			HostConfig: hostConfig{
+				RestartPolicy: restartPolicyConfig{Name: restartPolicy},
+				Binds:         binds,
+			},
+		}
+		setResourceLimits(&config.HostConfig, task.Resources)
+
+		if i == 0 {
+			config.HostConfig.NetworkMode = getNetworkName(service.ProjectID)
+			config.NetworkingConfig = &networkingConfig{EndpointsConfig: map[string]endpointConfig{
+				getNetworkName(service.ProjectID): {Aliases: []string{
+					utils.GetServiceDomain(service.ProjectID, service.ID),
+					utils.GetInternalServiceDomain(service.ProjectID, service.ID, service.Version),
+				}},
+			}}
+		} else {
+			config.HostConfig.NetworkMode = "container:" + getContainerName(service.ProjectID, replicaID, service.Tasks[0].ID)
+		}
+		configs[i] = config
+	}
+	return configs, nil
+}
+
+// setResourceLimits sets the cpu (in millicores), memory (in MB) and gpu limits of the container
+func setResourceLimits(c *hostConfig, r model.Resources) {
+	if r.Memory == 0 || r.CPU == 0 {
+		r.Memory = 512
+		r.CPU = 250
+	}
+	c.NanoCpus = r.CPU * 1000000
+	c.Memory = r.Memory * 1024 * 1024
+
+	if r.GPU != nil && r.GPU.Value > 0 {
+		c.DeviceRequests = []deviceRequest{{Driver: r.GPU.Type, Count: r.GPU.Value, Capabilities: [][]string{{"gpu"}}}}
+	}
+}
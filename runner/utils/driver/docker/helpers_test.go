@@ -0,0 +1,130 @@
+package docker
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+func TestDocker_generateContainerConfigs(t *testing.T) {
+	d := &Docker{config: &Config{StorePath: "/store", ClusterName: "cluster"}}
+	service := &model.Service{
+		ID:        "orders",
+		ProjectID: "myproject",
+		Version:   "v1",
+		Scale:     &model.ScaleConfig{Replicas: 2},
+		Tasks: []model.Task{
+			{
+				ID:        "app",
+				Ports:     []model.Port{{Name: "http", Protocol: model.HTTP, Port: 8080}},
+				Resources: model.Resources{CPU: 500, Memory: 256},
+				Docker:    model.Docker{Image: "orders:v1", Cmd: []string{"./orders", "--port", "8080"}},
+				Env:       map[string]string{"MODE": "prod"},
+				Secrets:   []string{"db", "certs"},
+				Runtime:   model.Image,
+			},
+			{
+				ID:      "sidecar",
+				Docker:  model.Docker{Image: "proxy:latest"},
+				Runtime: model.Image,
+			},
+		},
+	}
+	secrets := map[string]*model.Secret{
+		"db":    {ID: "db", Type: model.EnvType, Data: map[string]string{"DB_PASS": "secret"}},
+		"certs": {ID: "certs", Type: model.FileType, RootPath: "/etc/certs"},
+	}
+
+	configs, err := d.generateContainerConfigs(service, "orders--v1--0", secrets)
+	if err != nil {
+		t.Fatalf("generateContainerConfigs() error = %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("generateContainerConfigs() returned %d configs, want 2", len(configs))
+	}
+
+	app := configs[0]
+	if want := []string{"DB_PASS=secret", "MODE=prod", "SC_RUNTIME=image"}; !reflect.DeepEqual(app.Env, want) {
+		t.Errorf("generateContainerConfigs() env = %v, want %v", app.Env, want)
+	}
+	if !reflect.DeepEqual(app.Entrypoint, []string{"./orders"}) || !reflect.DeepEqual(app.Cmd, []string{"--port", "8080"}) {
+		t.Errorf("generateContainerConfigs() entrypoint = %v cmd = %v", app.Entrypoint, app.Cmd)
+	}
+	if want := []string{"/store/projects/myproject/files/certs:/etc/certs:ro"}; !reflect.DeepEqual(app.HostConfig.Binds, want) {
+		t.Errorf("generateContainerConfigs() binds = %v, want %v", app.HostConfig.Binds, want)
+	}
+	if app.HostConfig.NanoCpus != 500000000 || app.HostConfig.Memory != 256*1024*1024 {
+		t.Errorf("generateContainerConfigs() limits = (%d, %d)", app.HostConfig.NanoCpus, app.HostConfig.Memory)
+	}
+	if app.HostConfig.RestartPolicy.Name != restartPolicy || app.HostConfig.NetworkMode != "space-cloud-myproject" {
+		t.Errorf("generateContainerConfigs() host config = %v", app.HostConfig)
+	}
+	if aliases := app.NetworkingConfig.EndpointsConfig["space-cloud-myproject"].Aliases; len(aliases) != 2 || aliases[0] != "orders.myproject.svc.cluster.local" {
+		t.Errorf("generateContainerConfigs() aliases = %v", aliases)
+	}
+	if app.Labels[labelReplicas] != "2" || app.Labels[labelReplica] != "orders--v1--0" || app.Labels[labelTask] != "app" {
+		t.Errorf("generateContainerConfigs() labels = %v", app.Labels)
+	}
+
+	// The tasks of a replica share the network of the first task
+	sidecar := configs[1]
+	if sidecar.HostConfig.NetworkMode != "container:myproject--orders--v1--0--app" || sidecar.NetworkingConfig != nil {
+		t.Errorf("generateContainerConfigs() sidecar network mode = %v", sidecar.HostConfig.NetworkMode)
+	}
+	if sidecar.HostConfig.NanoCpus != 250000000 || sidecar.HostConfig.Memory != 512*1024*1024 {
+		t.Errorf("generateContainerConfigs() sidecar limits = (%d, %d), want the defaults", sidecar.HostConfig.NanoCpus, sidecar.HostConfig.Memory)
+	}
+
+	if _, err := d.generateContainerConfigs(service, "orders--v1--0", map[string]*model.Secret{}); err == nil {
+		t.Error("generateContainerConfigs() error = nil, want error for missing secrets")
+	}
+}
+
+func Test_splitImageTag(t *testing.T) {
+	tests := []struct {
+		image, wantName, wantTag string
+	}{
+		{image: "nginx", wantName: "nginx", wantTag: "latest"},
+		{image: "nginx:1.19", wantName: "nginx", wantTag: "1.19"},
+		{image: "localhost:5000/orders", wantName: "localhost:5000/orders", wantTag: "latest"},
+		{image: "localhost:5000/orders:v2", wantName: "localhost:5000/orders", wantTag: "v2"},
+		{image: "orders@sha256:abcd", wantName: "orders@sha256:abcd", wantTag: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := splitImageTag(tt.image)
+			if name != tt.wantName || tag != tt.wantTag {
+				t.Errorf("splitImageTag() = (%v, %v), want (%v, %v)", name, tag, tt.wantName, tt.wantTag)
+			}
+		})
+	}
+}
+
+func Test_demuxLogs(t *testing.T) {
+	frame := func(stream byte, s string) []byte {
+		return append([]byte{stream, 0, 0, 0, 0, 0, 0, byte(len(s))}, s...)
+	}
+	input := append(frame(1, "hello\n"), frame(2, "oops\n")...)
+
+	out := new(bytes.Buffer)
+	_ = demuxLogs(bytes.NewReader(input), out)
+	if out.String() != "hello\noops\n" {
+		t.Errorf("demuxLogs() = %q, want %q", out.String(), "hello\noops\n")
+	}
+}
+
+func Test_groupReplicas(t *testing.T) {
+	containers := []*containerSummary{
+		{State: "running", Labels: map[string]string{labelReplica: "orders--v1--1"}},
+		{State: "running", Labels: map[string]string{labelReplica: "orders--v1--0"}},
+		{State: "exited", Labels: map[string]string{labelReplica: "orders--v1--0"}},
+		{State: "created", Labels: map[string]string{labelReplica: "orders--v1--2"}},
+	}
+
+	want := []*model.ReplicaInfo{{ID: "orders--v1--0", Status: "EXITED"}, {ID: "orders--v1--1", Status: "RUNNING"}}
+	if got := groupReplicas(containers); !reflect.DeepEqual(got, want) {
+		t.Errorf("groupReplicas() = %v, want %v", got, want)
+	}
+}
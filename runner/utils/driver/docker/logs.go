@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+	"github.com/spaceuptech/space-cloud/runner/utils"
+)
+
+// GetLogs get logs of specified services
+func (d *Docker) GetLogs(ctx context.Context, projectID string, info *model.LogRequest) (io.ReadCloser, error) {
+	if info.TaskID == "" {
+		containers, err := d.listContainers(ctx, map[string]string{labelProject: projectID, labelReplica: info.ReplicaID})
+		if err != nil {
+			return nil, err
+		}
+		if len(containers) == 0 {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Invalid replica id", nil, nil)
+		}
+		info.TaskID = containers[0].Labels[labelTask]
+	}
+
+	query := url.Values{"stdout": []string{"true"}, "stderr": []string{"true"}, "follow": []string{strconv.FormatBool(info.IsFollow)}}
+	if info.Tail != nil {
+		query.Set("tail", strconv.FormatInt(*info.Tail, 10))
+	}
+	if info.SinceTime != nil {
+		query.Set("since", strconv.FormatInt(info.SinceTime.Unix(), 10))
+	} else if info.Since != nil {
+		query.Set("since", strconv.FormatInt(time.Now().Unix()-*info.Since, 10))
+	}
+
+	name := getContainerName(projectID, info.ReplicaID, info.TaskID)
+	b, err := d.stream(ctx, http.MethodGet, fmt.Sprintf("/containers/%s/logs", name), query, nil, nil)
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to get logs of container (%s)", name), err, nil)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "Sending logs to client", map[string]interface{}{})
+	go func() {
+		defer utils.CloseTheCloser(b)
+		defer utils.CloseTheCloser(pipeWriter)
+		if err := demuxLogs(bufio.NewReader(b), pipeWriter); err != nil && err != io.EOF {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to read logs from container", err, nil)
+			return
+		}
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), "End of file reached for logs", map[string]interface{}{})
+	}()
+	return pipeReader, nil
+}
+
+// demuxLogs copies the multiplexed stdout and stderr streams of a container. Each frame is prefixed by an 8 byte
+// header, the last 4 bytes of which are the size of the frame in big endian.
+func demuxLogs(r io.Reader, w io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(w, r, size); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Labels added to the docker objects managed by space cloud
+const (
+	labelManagedBy = "app.space-cloud.io/managed-by"
+	labelCluster   = "app.space-cloud.io/cluster"
+	labelProject   = "app.space-cloud.io/project"
+	labelService   = "app.space-cloud.io/service"
+	labelVersion   = "app.space-cloud.io/version"
+	labelReplica   = "app.space-cloud.io/replica"
+	labelTask      = "app.space-cloud.io/task"
+	labelReplicas  = "app.space-cloud.io/replicas"
+	labelSpec      = "app.space-cloud.io/spec"
+
+	managedBy = "space-cloud"
+)
+
+func getNetworkName(projectID string) string {
+	return fmt.Sprintf("space-cloud-%s", projectID)
+}
+
+func getReplicaID(serviceID, version string, index int) string {
+	return fmt.Sprintf("%s--%s--%d", serviceID, version, index)
+}
+
+func getContainerName(projectID, replicaID, taskID string) string {
+	return fmt.Sprintf("%s--%s--%s", projectID, replicaID, taskID)
+}
+
+// splitReplicaID returns the service id and version of a replica
+func splitReplicaID(replicaID string) (serviceID, version string) {
+	arr := strings.Split(replicaID, "--")
+	if len(arr) != 3 {
+		return "", ""
+	}
+	return arr[0], arr[1]
+}
+
+// getLabelFilters returns the filters used to list the objects with the provided labels
+func getLabelFilters(labels map[string]string) string {
+	filters := []string{fmt.Sprintf("%s=%s", labelManagedBy, managedBy)}
+	for k, v := range labels {
+		filters = append(filters, fmt.Sprintf("%s=%s", k, v))
+	}
+	return fmt.Sprintf(`{"label":["%s"]}`, strings.Join(filters, `","`))
+}
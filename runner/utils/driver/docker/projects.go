@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+// CreateProject creates the network in which the services of the project run
+func (d *Docker) CreateProject(ctx context.Context, project *model.Project) error {
+	return d.createNetworkIfNotExist(ctx, project.ID)
+}
+
+// DeleteProject removes all the containers, the network and the stored secrets and routes of the project
+func (d *Docker) DeleteProject(ctx context.Context, projectID string) error {
+	containers, err := d.listContainers(ctx, map[string]string{labelProject: projectID})
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to find containers of project (%s)", projectID), err, nil)
+	}
+	if err := d.removeContainers(ctx, containers); err != nil {
+		return err
+	}
+
+	name := getNetworkName(projectID)
+	if err := d.do(ctx, http.MethodDelete, "/networks/"+name, nil, nil, nil, nil); err != nil && err != errNotFound {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to remove network (%s)", name), err, nil)
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if err := os.RemoveAll(d.getProjectStorePath(projectID)); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to remove the secrets and routes of project (%s)", projectID), err, nil)
+	}
+	return nil
+}
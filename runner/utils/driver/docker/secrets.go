@@ -0,0 +1,232 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spaceuptech/helpers"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+// CreateSecret is used to upsert secret. Secrets are stored as files in the store path of the driver.
+func (d *Docker) CreateSecret(ctx context.Context, projectID string, secretObj *model.Secret) error {
+	// check whether the secret type is correct!
+	if secretObj.Type != model.FileType && secretObj.Type != model.EnvType && secretObj.Type != model.DockerType {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid secret type (%s) provided", secretObj.Type), nil, nil)
+	}
+	if secretObj.Type == model.DockerType {
+		_, p1 := secretObj.Data["username"]
+		_, p2 := secretObj.Data["password"]
+		_, p3 := secretObj.Data["url"]
+		if !p1 || !p2 || !p3 {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Incorrect secret value provided for secret type docker", nil, nil)
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	oldSecret := new(model.Secret)
+	if err := d.readStore(d.getSecretPath(projectID, secretObj.ID), oldSecret); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to create secret (%s)", secretObj.ID), err, nil)
+	}
+	if oldSecret.ID != "" && oldSecret.Type != secretObj.Type {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Secret type mismatch. Wanted - %s; Got - %s", oldSecret.Type, secretObj.Type), nil, nil)
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Storing secret (%s)", secretObj.ID), nil)
+	return d.storeSecret(ctx, projectID, secretObj)
+}
+
+// ListSecrets lists all the secrets of the project
+func (d *Docker) ListSecrets(ctx context.Context, projectID string) ([]*model.Secret, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	files, err := ioutil.ReadDir(filepath.Join(d.getProjectStorePath(projectID), "secrets"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to fetch list of secrets", err, nil)
+	}
+
+	listOfSecrets := make([]*model.Secret, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		secret := new(model.Secret)
+		if err := d.readStore(d.getSecretPath(projectID, strings.TrimSuffix(file.Name(), ".json")), secret); err != nil {
+			return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Failed to fetch list of secrets", err, nil)
+		}
+		listOfSecrets = append(listOfSecrets, secret)
+	}
+	sort.Slice(listOfSecrets, func(i, j int) bool { return listOfSecrets[i].ID < listOfSecrets[j].ID })
+	return listOfSecrets, nil
+}
+
+// DeleteSecret is used to delete secrets!
+func (d *Docker) DeleteSecret(ctx context.Context, projectID string, secretName string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if err := os.Remove(d.getSecretPath(projectID, secretName)); err != nil && !os.IsNotExist(err) {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to delete secret (%s)", secretName), err, nil)
+	}
+	if err := os.RemoveAll(d.getFileSecretPath(projectID, secretName)); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to delete secret (%s)", secretName), err, nil)
+	}
+	return nil
+}
+
+// SetFileSecretRootPath is used to set the file secret root path
+func (d *Docker) SetFileSecretRootPath(ctx context.Context, projectID string, secretName, rootPath string) error {
+	if secretName == "" || rootPath == "" {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Empty secret name (%s) or root path (%s) provided", secretName, rootPath), nil, nil)
+	}
+
+	return d.updateSecret(ctx, projectID, secretName, "set root path", func(secret *model.Secret) {
+		secret.RootPath = rootPath
+	})
+}
+
+// SetKey adds a new secret key-value pair
+func (d *Docker) SetKey(ctx context.Context, projectID string, secretName string, secretKey string, secretValObj *model.SecretValue) error {
+	if secretName == "" || secretValObj.Value == "" {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("key/value not provided; got (%s,%s)", secretName, secretValObj.Value), nil, nil)
+	}
+
+	return d.updateSecret(ctx, projectID, secretName, "set key", func(secret *model.Secret) {
+		if secret.Data == nil {
+			secret.Data = make(map[string]string, 1)
+		}
+		secret.Data[secretKey] = secretValObj.Value
+	})
+}
+
+// DeleteKey is used to delete a key from the secret!
+func (d *Docker) DeleteKey(ctx context.Context, projectID string, secretName string, secretKey string) error {
+	return d.updateSecret(ctx, projectID, secretName, "delete key", func(secret *model.Secret) {
+		delete(secret.Data, secretKey)
+	})
+}
+
+// updateSecret updates a file or env secret. Docker secrets can only be replaced as a whole.
+func (d *Docker) updateSecret(ctx context.Context, projectID, secretName, op string, update func(secret *model.Secret)) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	secret := new(model.Secret)
+	if err := d.readStore(d.getSecretPath(projectID, secretName), secret); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read secret (%s)", secretName), err, nil)
+	}
+	if secret.ID == "" {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("secret with name (%s) does not exist", secretName), nil, nil)
+	}
+	if secret.Type == model.DockerType {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("%s operation cannot be performed on secrets with type docker", op), nil, nil)
+	}
+
+	update(secret)
+	return d.storeSecret(ctx, projectID, secret)
+}
+
+// storeSecret writes the secret to the store. The keys of file secrets are also written as individual files so
+// that they can be mounted in the containers.
+func (d *Docker) storeSecret(ctx context.Context, projectID string, secret *model.Secret) error {
+	if err := d.writeStore(d.getSecretPath(projectID, secret.ID), secret); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to store secret (%s)", secret.ID), err, nil)
+	}
+	if secret.Type != model.FileType {
+		return nil
+	}
+
+	dir := d.getFileSecretPath(projectID, secret.ID)
+	if err := os.RemoveAll(dir); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to store secret (%s)", secret.ID), err, nil)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to store secret (%s)", secret.ID), err, nil)
+	}
+	for k, v := range secret.Data {
+		if err := ioutil.WriteFile(filepath.Join(dir, filepath.Base(k)), []byte(v), 0600); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Failed to store secret (%s)", secret.ID), err, nil)
+		}
+	}
+	return nil
+}
+
+// getSecrets returns the secrets used by the tasks of the service
+func (d *Docker) getSecrets(ctx context.Context, service *model.Service) (map[string]*model.Secret, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	listOfSecrets := map[string]*model.Secret{}
+	for _, task := range service.Tasks {
+		names := append([]string{}, task.Secrets...)
+		if task.Docker.Secret != "" {
+			names = append(names, task.Docker.Secret)
+		}
+
+		for _, secretName := range names {
+			if _, p := listOfSecrets[secretName]; p {
+				continue
+			}
+
+			secret := new(model.Secret)
+			if err := d.readStore(d.getSecretPath(service.ProjectID, secretName), secret); err != nil {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to read secret (%s)", secretName), err, nil)
+			}
+			if secret.ID == "" {
+				return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Secret (%s) used by service (%s) does not exist", secretName, service.ID), nil, nil)
+			}
+			listOfSecrets[secretName] = secret
+		}
+	}
+	return listOfSecrets, nil
+}
+
+func (d *Docker) getProjectStorePath(projectID string) string {
+	return filepath.Join(d.config.StorePath, "projects", filepath.Base(projectID))
+}
+
+func (d *Docker) getSecretPath(projectID, secretName string) string {
+	return filepath.Join(d.getProjectStorePath(projectID), "secrets", filepath.Base(secretName)+".json")
+}
+
+func (d *Docker) getFileSecretPath(projectID, secretName string) string {
+	return filepath.Join(d.getProjectStorePath(projectID), "files", filepath.Base(secretName))
+}
+
+func (d *Docker) getRoutesPath(projectID string) string {
+	return filepath.Join(d.getProjectStorePath(projectID), "routes.json")
+}
+
+// readStore decodes the file in `v`. It is left untouched if the file doesn't exist.
+func (d *Docker) readStore(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (d *Docker) writeStore(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
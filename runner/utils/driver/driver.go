@@ -9,6 +9,7 @@ import (
 
 	"github.com/spaceuptech/space-cloud/runner/model"
 	"github.com/spaceuptech/space-cloud/runner/utils/auth"
+	"github.com/spaceuptech/space-cloud/runner/utils/driver/docker"
 	"github.com/spaceuptech/space-cloud/runner/utils/driver/istio"
 )
 
@@ -53,6 +54,12 @@ type Interface interface {
 	SetFileSecretRootPath(ctx context.Context, projectID string, secretName, rootPath string) error
 }
 
+// proxyTargetResolver is implemented by the drivers which route requests to the proxy without the original
+// destination headers
+type proxyTargetResolver interface {
+	ResolveProxyTarget(ctx context.Context, host string) (*model.ProxyTarget, error)
+}
+
 // Module holds config of driver package
 type Module struct {
 	driver     Interface
@@ -83,6 +90,21 @@ func initDriver(auth *auth.Module, c *Config) (Interface, error) {
 
 		return istio.NewIstioDriver(auth, istioConfig)
 
+	case model.TypeKubernetes:
+		// Generate the config file
+		var kubeConfig *istio.Config
+		if c.IsInCluster {
+			kubeConfig = istio.GenerateInClusterConfig()
+		} else {
+			kubeConfig = istio.GenerateOutsideClusterConfig(c.ConfigFilePath)
+		}
+		kubeConfig.SetProxyPort(c.ProxyPort)
+
+		return istio.NewKubernetesDriver(auth, kubeConfig)
+
+	case model.TypeDocker:
+		return docker.NewDockerDriver(auth, docker.GenerateConfig(c.ConfigFilePath, c.ClusterName))
+
 	default:
 		return nil, helpers.Logger.LogError(helpers.GetRequestID(context.TODO()), fmt.Sprintf("invalid driver type (%s) provided", c.DriverType), nil, nil)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/kedacore/keda/api/v1alpha1"
 	"github.com/segmentio/ksuid"
@@ -298,3 +299,148 @@ func extractRequiredNodeAffinityObject(arr []v1.NodeSelectorTerm) []model.Affini
 	}
 	return affinities
 }
+
+// getServiceFromDeployment extracts the config of the service, except its scale config, from its deployment
+func getServiceFromDeployment(projectID string, deployment appsv1.Deployment) *model.Service {
+	service := new(model.Service)
+	service.ProjectID = projectID
+	service.ID = deployment.Labels["app"]
+	service.Version = deployment.Labels["version"]
+	service.Affinity = make([]model.Affinity, 0)
+	service.StatsInclusionPrefixes = deployment.Spec.Template.Annotations["sidecar.istio.io/statsInclusionPrefixes"]
+
+	// Extract affinities
+	if deployment.Spec.Template.Spec.Affinity != nil {
+
+		// node affinity preferred
+		if deployment.Spec.Template.Spec.Affinity.NodeAffinity != nil {
+
+			if deployment.Spec.Template.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution != nil {
+				affinities := extractPreferredNodeAffinityObject(deployment.Spec.Template.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+				if len(affinities) > 0 {
+					service.Affinity = append(service.Affinity, affinities...)
+				}
+			}
+
+			// node affinity required
+			if deployment.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+				affinities := extractRequiredNodeAffinityObject(deployment.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+				if len(affinities) > 0 {
+					service.Affinity = append(service.Affinity, affinities...)
+				}
+			}
+		}
+
+		// service affinity
+		if deployment.Spec.Template.Spec.Affinity.PodAffinity != nil {
+			affinities := extractPreferredServiceAffinityObject(deployment.Spec.Template.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+			if len(affinities) > 0 {
+				service.Affinity = append(service.Affinity, affinities...)
+			}
+			affinities = extractRequiredServiceAffinityObject(deployment.Spec.Template.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+			if len(affinities) > 0 {
+				service.Affinity = append(service.Affinity, affinities...)
+			}
+		}
+
+		// service anti affinity
+		if deployment.Spec.Template.Spec.Affinity.PodAntiAffinity != nil {
+			affinities := extractPreferredServiceAffinityObject(deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, -1)
+			if len(affinities) > 0 {
+				service.Affinity = append(service.Affinity, affinities...)
+			}
+			affinities = extractRequiredServiceAffinityObject(deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, -1)
+			if len(affinities) > 0 {
+				service.Affinity = append(service.Affinity, affinities...)
+			}
+		}
+	}
+
+	// service labels
+	service.Labels = deployment.Spec.Template.Labels
+
+	for _, containerInfo := range deployment.Spec.Template.Spec.Containers {
+		if containerInfo.Name == "metric-proxy" || containerInfo.Name == "istio-proxy" {
+			continue
+		}
+		// get ports
+		ports := make([]model.Port, len(containerInfo.Ports))
+		for i, port := range containerInfo.Ports {
+			proto := strings.Split(port.Name, "-")[0]
+			ports[i] = model.Port{Name: port.Name, Protocol: model.Protocol(proto), Port: port.ContainerPort}
+		}
+
+		var dockerSecret string
+		secretsMap := make(map[string]struct{})
+
+		// get environment variables
+		envs := map[string]string{}
+		for _, env := range containerInfo.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				secretsMap[env.ValueFrom.SecretKeyRef.LocalObjectReference.Name] = struct{}{}
+				continue
+			}
+			envs[env.Name] = env.Value
+		}
+
+		// Range over the file mounts for secrets
+		for _, volume := range containerInfo.VolumeMounts {
+			if checkIfVolumeIsSecret(volume.Name, deployment.Spec.Template.Spec.Volumes) {
+				secretsMap[volume.Name] = struct{}{}
+			}
+		}
+
+		// Get docker secret
+		// TODO: Handle case when different tasks have different secrets
+		if len(deployment.Spec.Template.Spec.ImagePullSecrets) > 0 {
+			dockerSecret = deployment.Spec.Template.Spec.ImagePullSecrets[0].Name
+		}
+
+		// Extract the runtime from the environment variable
+		runtime := model.Runtime(envs[runtimeEnvVariable])
+		delete(envs, runtimeEnvVariable)
+
+		// Delete internal environment variables if runtime was code
+		// if runtime == model.Code {
+		// 	delete(envs, model.ArtifactURL)
+		// 	delete(envs, model.ArtifactToken)
+		// 	delete(envs, model.ArtifactProject)
+		// 	delete(envs, model.ArtifactService)
+		// 	delete(envs, model.ArtifactVersion)
+		// }
+
+		// Get the image pull policy
+		imagePullPolicy := model.PullIfNotExists
+		if containerInfo.ImagePullPolicy == v1.PullAlways {
+			imagePullPolicy = model.PullAlways
+		}
+
+		// Move all secrets from map to array
+		var secrets []string
+		for k := range secretsMap {
+			secrets = append(secrets, k)
+		}
+
+		// set tasks
+		service.Tasks = append(service.Tasks, model.Task{
+			ID:    containerInfo.Name,
+			Name:  containerInfo.Name,
+			Ports: ports,
+			Resources: model.Resources{
+				CPU:    containerInfo.Resources.Requests.Cpu().MilliValue(),
+				Memory: containerInfo.Resources.Requests.Memory().Value() / (1024 * 1024),
+			},
+			Docker: model.Docker{
+				Image:           containerInfo.Image,
+				Cmd:             containerInfo.Command,
+				Secret:          dockerSecret,
+				ImagePullPolicy: imagePullPolicy,
+			},
+			Env:     envs,
+			Runtime: runtime,
+			Secrets: secrets,
+		})
+	}
+
+	return service
+}
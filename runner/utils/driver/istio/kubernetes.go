@@ -0,0 +1,538 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceuptech/helpers"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/api/core/v1"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+	"github.com/spaceuptech/space-cloud/runner/utils/auth"
+)
+
+const (
+	// Annotations used by the kubernetes driver
+	annotationMinReplicas      = "minReplicas"
+	annotationMaxReplicas      = "maxReplicas"
+	annotationCoolDownInterval = "coolDownInterval"
+	annotationCPUTarget        = "cpuTarget"
+	annotationProxyVersion     = "space-cloud.io/proxy-version"
+
+	// labelProxied marks the general services whose requests are routed through the proxy of the runner
+	labelProxied = "space-cloud.io/proxied"
+
+	// defaultCPUTarget is the average cpu utilisation (in percentage) the autoscaler maintains by default
+	defaultCPUTarget = 80
+
+	// scaleDownInterval is the interval at which idle services are scaled down to zero
+	scaleDownInterval = 15 * time.Second
+)
+
+// Kubernetes manages the services on a kubernetes cluster which doesn't have istio installed. Services are exposed
+// with kubernetes services and autoscaled on their cpu utilisation. Services with zero min replicas have their
+// requests routed through the proxy of the runner which scales them down to zero when idle and back up on the next
+// request. The secrets, roles, logs and status of services are managed the same way as the istio driver.
+type Kubernetes struct {
+	*Istio
+
+	lock       sync.Mutex
+	lastActive map[string]time.Time // key is project:service:version
+}
+
+// NewKubernetesDriver creates a new instance of the kubernetes driver
+func NewKubernetesDriver(auth *auth.Module, c *Config) (*Kubernetes, error) {
+	var restConfig *rest.Config
+	var err error
+
+	if c.IsInsideCluster {
+		restConfig, err = rest.InClusterConfig()
+	} else {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", c.KubeConfigPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the kubernetes client
+	kube, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Kubernetes{Istio: &Istio{auth: auth, config: c, kube: kube}, lastActive: map[string]time.Time{}}
+
+	// Start the routine which scales down idle services
+	go k.routineScaleDown()
+
+	return k, nil
+}
+
+// Type returns the type of the driver
+func (k *Kubernetes) Type() model.DriverType {
+	return model.TypeKubernetes
+}
+
+// CreateProject creates a new namespace for the client
+func (k *Kubernetes) CreateProject(ctx context.Context, project *model.Project) error {
+	return k.createNamespace(ctx, project, nil)
+}
+
+// ApplyService deploys the service on kubernetes
+func (k *Kubernetes) ApplyService(ctx context.Context, service *model.Service) error {
+	ns := service.ProjectID
+
+	// Get the list of secrets required for this service
+	listOfSecrets, err := k.getSecrets(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	cpuTarget, err := setKubernetesScaleConfig(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	// Create the appropriate kubernetes objects
+	kubeServiceAccount := generateServiceAccount(service)
+	kubeDeployment := k.generateDeployment(service, listOfSecrets)
+	kubeDeployment.Annotations = map[string]string{
+		annotationMinReplicas:      strconv.Itoa(int(service.AutoScale.MinReplicas)),
+		annotationMaxReplicas:      strconv.Itoa(int(service.AutoScale.MaxReplicas)),
+		annotationCoolDownInterval: strconv.Itoa(int(service.AutoScale.CoolDownInterval)),
+		annotationCPUTarget:        strconv.Itoa(int(cpuTarget)),
+	}
+	kubeDeployment.Spec.Template.Annotations = nil
+	kubeInternalService := generateInternalService(service)
+	kubeGeneralService := generateGeneralService(service)
+	kubeAutoscaler := generateHorizontalPodAutoscaler(service, cpuTarget)
+
+	// Create a service account if it doesn't already exist
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Create service account (%s) in %s", kubeServiceAccount.Name, ns), nil)
+	if err := k.createServiceAccountIfNotExist(ctx, ns, kubeServiceAccount); err != nil {
+		return err
+	}
+
+	// Apply the deployment config
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Applying deployment (%s) in %s", kubeDeployment.Name, ns), nil)
+	if err := k.applyDeployment(ctx, ns, kubeDeployment); err != nil {
+		return err
+	}
+
+	// Apply the internal service config
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Applying internal service (%s) in %s", kubeInternalService.Name, ns), nil)
+	if err := k.applyService(ctx, ns, kubeInternalService); err != nil {
+		return err
+	}
+
+	// Apply the general service config. Requests of services which can be scaled down to zero go through the proxy.
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Applying general service (%s) in %s", kubeGeneralService.Name, ns), nil)
+	if err := k.applyGeneralService(ctx, ns, kubeGeneralService, service.Version, service.AutoScale.MinReplicas == 0); err != nil {
+		return err
+	}
+
+	// Apply the autoscaler config
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Applying autoscaler (%s) in %s", kubeAutoscaler.Name, ns), nil)
+	if err := k.applyHorizontalPodAutoscaler(ctx, ns, kubeAutoscaler); err != nil {
+		return err
+	}
+
+	helpers.Logger.LogInfo(helpers.GetRequestID(ctx), fmt.Sprintf("Service (%s:%s) applied successfully", service.ProjectID, service.ID), nil)
+	return nil
+}
+
+// GetServices gets the services deployed on kubernetes
+func (k *Kubernetes) GetServices(ctx context.Context, projectID string) ([]*model.Service, error) {
+	// Get all deployments in project
+	deploymentList, err := k.kube.AppsV1().Deployments(projectID).List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/managed-by=space-cloud"})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to find deployments in project", err, nil)
+	}
+
+	services := make([]*model.Service, len(deploymentList.Items))
+	for i, deployment := range deploymentList.Items {
+		services[i] = getServiceFromDeployment(projectID, deployment)
+		services[i].AutoScale = getKubernetesScaleConfig(deployment)
+	}
+	return services, nil
+}
+
+// DeleteService deletes a service version
+func (k *Kubernetes) DeleteService(ctx context.Context, projectID, serviceID, version string) error {
+	// Get the count of versions running for this service. This is important to make sure we do not delete shared resources.
+	count, err := k.getServiceDeploymentsCount(ctx, projectID, serviceID)
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Error in delete service - could not get count of versions for service (%s)", getServiceUniqueID(projectID, serviceID, version)), err, nil)
+	}
+
+	if count == 1 {
+		if err := k.deleteServiceRoleIfExist(ctx, projectID, serviceID, "*"); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Could not delete service - service role could not be deleted", err, nil)
+		}
+		if err := k.deleteServiceAccountIfExist(ctx, projectID, serviceID); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Could not delete service - service account could not be deleted", err, nil)
+		}
+		if err := k.deleteGeneralService(ctx, projectID, serviceID); err != nil {
+			return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Could not delete service - general service could not be deleted", err, nil)
+		}
+	}
+
+	if err := k.deleteDeployment(ctx, projectID, serviceID, version); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Could not delete service - deployment could not be deleted", err, nil)
+	}
+	if err := k.deleteInternalService(ctx, projectID, serviceID, version); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Could not delete service - internal service could not be deleted", err, nil)
+	}
+	err = k.kube.AutoscalingV1().HorizontalPodAutoscalers(projectID).Delete(ctx, getDeploymentName(serviceID, version), metav1.DeleteOptions{})
+	if err := ignoreErrorIfNotFound(err); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Could not delete service - autoscaler config could not be deleted", err, nil)
+	}
+
+	k.lock.Lock()
+	delete(k.lastActive, getServiceUniqueID(projectID, serviceID, version))
+	k.lock.Unlock()
+	return nil
+}
+
+// ApplyServiceRoutes isn't supported since traffic splitting between versions requires istio
+func (k *Kubernetes) ApplyServiceRoutes(ctx context.Context, projectID, serviceID string, routes model.Routes) error {
+	return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Service routes are not supported by the kubernetes driver - use the istio driver for traffic splitting", nil, nil)
+}
+
+// GetServiceRoutes returns an empty map since the general service of a service routes requests to all its versions
+func (k *Kubernetes) GetServiceRoutes(ctx context.Context, projectID string) (map[string]model.Routes, error) {
+	return map[string]model.Routes{}, nil
+}
+
+// ScaleUp scales up a service which has been scaled down to zero. It is invoked by the proxy for every request of
+// the services which can be scaled down to zero which is used to keep track of their activity.
+func (k *Kubernetes) ScaleUp(ctx context.Context, projectID, serviceID, version string) error {
+	k.lock.Lock()
+	k.lastActive[getServiceUniqueID(projectID, serviceID, version)] = time.Now()
+	k.lock.Unlock()
+
+	scale, err := k.kube.AppsV1().Deployments(projectID).GetScale(ctx, getDeploymentName(serviceID, version), metav1.GetOptions{})
+	if err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to scale up service", err, map[string]interface{}{"project": projectID, "service": serviceID, "version": version})
+	}
+	if scale.Spec.Replicas > 0 {
+		return nil
+	}
+
+	helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Scaling up service (%s:%s:%s) from zero", projectID, serviceID, version), nil)
+	scale.Spec.Replicas = 1
+	if _, err := k.kube.AppsV1().Deployments(projectID).UpdateScale(ctx, getDeploymentName(serviceID, version), scale, metav1.UpdateOptions{}); err != nil {
+		return helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to scale up service", err, map[string]interface{}{"project": projectID, "service": serviceID, "version": version})
+	}
+	return nil
+}
+
+// WaitForService waits for the service to have a ready replica
+func (k *Kubernetes) WaitForService(ctx context.Context, service *model.Service) error {
+	// Skip watching the deployment if the service is already available
+	deployment, err := k.kube.AppsV1().Deployments(service.ProjectID).Get(ctx, getDeploymentName(service.ID, service.Version), metav1.GetOptions{})
+	if err == nil && deployment.Status.AvailableReplicas >= 1 && deployment.Status.ReadyReplicas >= 1 {
+		return nil
+	}
+	return k.Istio.WaitForService(ctx, service)
+}
+
+// ResolveProxyTarget returns the version and internal address of the service a request, which was routed through the
+// proxy by a general service, was meant for. The host is the general domain of the service with an optional port.
+func (k *Kubernetes) ResolveProxyTarget(ctx context.Context, host string) (*model.ProxyTarget, error) {
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname, port = host, "80"
+	}
+
+	arr := strings.Split(hostname, ".")
+	if len(arr) < 2 {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid host (%s) received by proxy", host), nil, nil)
+	}
+	projectID, serviceID := arr[1], arr[0]
+
+	service, err := k.kube.CoreV1().Services(projectID).Get(ctx, getServiceName(serviceID), metav1.GetOptions{})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to find the service for host (%s)", host), err, nil)
+	}
+	version, ok := service.Annotations[annotationProxyVersion]
+	if !ok {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Service (%s:%s) is not routed through the proxy", projectID, serviceID), nil, nil)
+	}
+
+	return &model.ProxyTarget{
+		Project: projectID,
+		Service: serviceID,
+		Version: version,
+		Host:    getInternalServiceDomain(projectID, serviceID, version),
+		Port:    port,
+	}, nil
+}
+
+// setKubernetesScaleConfig sets the default values of the scale config and returns the target cpu utilisation
+func setKubernetesScaleConfig(ctx context.Context, service *model.Service) (int32, error) {
+	// Generate a default auto scale config if not provided
+	if service.AutoScale == nil {
+		service.AutoScale = getDefaultAutoScaleConfig()
+		if service.Scale != nil {
+			service.AutoScale.MinReplicas = service.Scale.MinReplicas
+			service.AutoScale.MaxReplicas = service.Scale.MaxReplicas
+		}
+	}
+
+	// Set default values for auto scale config
+	if service.AutoScale.MaxReplicas == 0 {
+		service.AutoScale.MaxReplicas = 100
+	}
+	if service.AutoScale.CoolDownInterval == 0 {
+		service.AutoScale.CoolDownInterval = 120
+	}
+
+	cpuTarget := int32(defaultCPUTarget)
+	for _, trigger := range service.AutoScale.Triggers {
+		if trigger.Type != "cpu" {
+			return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Scaling trigger (%s) is not supported by the kubernetes driver - only cpu triggers are supported", trigger.Type), nil, nil)
+		}
+
+		target, err := strconv.Atoi(trigger.MetaData["target"])
+		if err != nil || target <= 0 {
+			return 0, helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Invalid target (%s) provided in scaling trigger (%s)", trigger.MetaData["target"], trigger.Name), err, nil)
+		}
+		cpuTarget = int32(target)
+	}
+	return cpuTarget, nil
+}
+
+func getKubernetesScaleConfig(deployment appsv1.Deployment) *model.AutoScaleConfig {
+	autoscale := getDefaultAutoScaleConfig()
+	if v, err := strconv.Atoi(deployment.Annotations[annotationMinReplicas]); err == nil {
+		autoscale.MinReplicas = int32(v)
+	}
+	if v, err := strconv.Atoi(deployment.Annotations[annotationMaxReplicas]); err == nil {
+		autoscale.MaxReplicas = int32(v)
+	}
+	if v, err := strconv.Atoi(deployment.Annotations[annotationCoolDownInterval]); err == nil {
+		autoscale.CoolDownInterval = int32(v)
+	}
+
+	target := deployment.Annotations[annotationCPUTarget]
+	if target == "" {
+		target = strconv.Itoa(defaultCPUTarget)
+	}
+	autoscale.Triggers = []model.AutoScaleTrigger{{Name: "cpu", Type: "cpu", MetaData: map[string]string{"target": target}}}
+	return autoscale
+}
+
+func generateHorizontalPodAutoscaler(service *model.Service, cpuTarget int32) *autoscalingv1.HorizontalPodAutoscaler {
+	// The autoscaler can't scale down to zero. That is taken care of by the proxy.
+	minReplicas := service.AutoScale.MinReplicas
+	if minReplicas < 1 {
+		minReplicas = 1
+	}
+
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: getDeploymentName(service.ID, service.Version),
+			Labels: map[string]string{
+				"app":                          service.ID,
+				"version":                      service.Version,
+				"app.kubernetes.io/name":       service.ID,
+				"app.kubernetes.io/version":    service.Version,
+				"app.kubernetes.io/managed-by": "space-cloud",
+				"space-cloud.io/version":       model.Version,
+			},
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef:                 autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: getDeploymentName(service.ID, service.Version), APIVersion: "apps/v1"},
+			MinReplicas:                    &minReplicas,
+			MaxReplicas:                    service.AutoScale.MaxReplicas,
+			TargetCPUUtilizationPercentage: &cpuTarget,
+		},
+	}
+}
+
+func (k *Kubernetes) applyHorizontalPodAutoscaler(ctx context.Context, ns string, hpa *autoscalingv1.HorizontalPodAutoscaler) error {
+	prevHPA, err := k.kube.AutoscalingV1().HorizontalPodAutoscalers(ns).Get(ctx, hpa.Name, metav1.GetOptions{})
+	if kubeErrors.IsNotFound(err) {
+		_, err = k.kube.AutoscalingV1().HorizontalPodAutoscalers(ns).Create(ctx, hpa, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	prevHPA.Labels = hpa.Labels
+	prevHPA.Spec = hpa.Spec
+	_, err = k.kube.AutoscalingV1().HorizontalPodAutoscalers(ns).Update(ctx, prevHPA, metav1.UpdateOptions{})
+	return err
+}
+
+// applyGeneralService applies the general service. Proxied services don't have a selector. Their endpoints are the
+// addresses of the runner instead.
+func (k *Kubernetes) applyGeneralService(ctx context.Context, ns string, service *v1.Service, version string, proxied bool) error {
+	if proxied {
+		service.Labels[labelProxied] = "true"
+		service.Annotations = map[string]string{annotationProxyVersion: version}
+		service.Spec.Selector = nil
+	}
+
+	prevService, err := k.kube.CoreV1().Services(ns).Get(ctx, service.Name, metav1.GetOptions{})
+	if kubeErrors.IsNotFound(err) {
+		_, err = k.kube.CoreV1().Services(ns).Create(ctx, service, metav1.CreateOptions{})
+	} else if err == nil {
+		prevService.Spec.Ports = service.Spec.Ports
+		prevService.Spec.Selector = service.Spec.Selector
+		prevService.Annotations = service.Annotations
+		prevService.Labels = service.Labels
+		_, err = k.kube.CoreV1().Services(ns).Update(ctx, prevService, metav1.UpdateOptions{})
+	}
+	if err != nil || !proxied {
+		return err
+	}
+
+	addresses, err := k.getRunnerAddresses(ctx)
+	if err != nil {
+		return err
+	}
+	return k.applyProxyEndpoints(ctx, ns, service, addresses)
+}
+
+// applyProxyEndpoints points all the ports of the service to the proxy port of the runner
+func (k *Kubernetes) applyProxyEndpoints(ctx context.Context, ns string, service *v1.Service, addresses []v1.EndpointAddress) error {
+	ports := make([]v1.EndpointPort, len(service.Spec.Ports))
+	for i, port := range service.Spec.Ports {
+		ports[i] = v1.EndpointPort{Name: port.Name, Port: int32(k.config.ProxyPort), Protocol: v1.ProtocolTCP}
+	}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: service.Name, Labels: service.Labels},
+		Subsets:    []v1.EndpointSubset{{Addresses: addresses, Ports: ports}},
+	}
+
+	prevEndpoints, err := k.kube.CoreV1().Endpoints(ns).Get(ctx, endpoints.Name, metav1.GetOptions{})
+	if kubeErrors.IsNotFound(err) {
+		_, err = k.kube.CoreV1().Endpoints(ns).Create(ctx, endpoints, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	prevEndpoints.Labels = endpoints.Labels
+	prevEndpoints.Subsets = endpoints.Subsets
+	_, err = k.kube.CoreV1().Endpoints(ns).Update(ctx, prevEndpoints, metav1.UpdateOptions{})
+	return err
+}
+
+// getRunnerAddresses returns the addresses of the instances of the runner
+func (k *Kubernetes) getRunnerAddresses(ctx context.Context) ([]v1.EndpointAddress, error) {
+	endpoints, err := k.kube.CoreV1().Endpoints("space-cloud").Get(ctx, "runner", metav1.GetOptions{})
+	if err != nil {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to get the addresses of the runner", err, nil)
+	}
+
+	addresses := make([]v1.EndpointAddress, 0)
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			addresses = append(addresses, v1.EndpointAddress{IP: address.IP})
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, helpers.Logger.LogError(helpers.GetRequestID(ctx), "No ready instances of the runner found", nil, nil)
+	}
+	return addresses, nil
+}
+
+func (k *Kubernetes) routineScaleDown() {
+	ticker := time.NewTicker(scaleDownInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), scaleDownInterval)
+		k.scaleDownIdleServices(ctx)
+		k.syncProxyEndpoints(ctx)
+		cancel()
+	}
+}
+
+// scaleDownIdleServices scales down the services which can be scaled down to zero and haven't received a request
+// for longer than their cool down interval
+func (k *Kubernetes) scaleDownIdleServices(ctx context.Context) {
+	deployments, err := k.kube.AppsV1().Deployments("").List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/managed-by=space-cloud"})
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to list deployments to scale down", err, nil)
+		return
+	}
+
+	now := time.Now()
+	for _, deployment := range deployments.Items {
+		if deployment.Annotations[annotationMinReplicas] != "0" || deployment.Spec.Replicas == nil || *deployment.Spec.Replicas == 0 {
+			continue
+		}
+
+		id := getServiceUniqueID(deployment.Namespace, deployment.Labels["app"], deployment.Labels["version"])
+		coolDown, _ := strconv.Atoi(deployment.Annotations[annotationCoolDownInterval])
+
+		k.lock.Lock()
+		lastActive, ok := k.lastActive[id]
+		if !ok {
+			// Services which were running before the runner started get a full cool down interval
+			k.lastActive[id] = now
+		}
+		k.lock.Unlock()
+		if !ok || now.Sub(lastActive) < time.Duration(coolDown)*time.Second {
+			continue
+		}
+
+		helpers.Logger.LogDebug(helpers.GetRequestID(ctx), fmt.Sprintf("Scaling down idle service (%s) to zero", id), nil)
+		if err := k.scaleToZero(ctx, deployment.Namespace, deployment.Name); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to scale down service (%s)", id), err, nil)
+		}
+	}
+}
+
+func (k *Kubernetes) scaleToZero(ctx context.Context, ns, name string) error {
+	// Remove the autoscaler's lower bound by scaling the deployment directly. The autoscaler stays inactive while the
+	// deployment has zero replicas.
+	scale, err := k.kube.AppsV1().Deployments(ns).GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	scale.Spec.Replicas = 0
+	_, err = k.kube.AppsV1().Deployments(ns).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+	return err
+}
+
+// syncProxyEndpoints keeps the endpoints of the proxied services in sync with the addresses of the runner
+func (k *Kubernetes) syncProxyEndpoints(ctx context.Context) {
+	services, err := k.kube.CoreV1().Services("").List(ctx, metav1.ListOptions{LabelSelector: labelProxied + "=true"})
+	if err != nil {
+		_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), "Unable to list proxied services", err, nil)
+		return
+	}
+	if len(services.Items) == 0 {
+		return
+	}
+
+	addresses, err := k.getRunnerAddresses(ctx)
+	if err != nil {
+		return
+	}
+	for i := range services.Items {
+		service := &services.Items[i]
+		if err := k.applyProxyEndpoints(ctx, service.Namespace, service, addresses); err != nil {
+			_ = helpers.Logger.LogError(helpers.GetRequestID(ctx), fmt.Sprintf("Unable to update endpoints of service (%s:%s)", service.Namespace, service.Name), err, nil)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package istio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spaceuptech/space-cloud/runner/model"
+)
+
+func Test_setKubernetesScaleConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		service       *model.Service
+		want          *model.AutoScaleConfig
+		wantCPUTarget int32
+		wantErr       bool
+	}{
+		{
+			name:          "Default scale config",
+			service:       &model.Service{},
+			want:          &model.AutoScaleConfig{PollingInterval: 15, CoolDownInterval: 120, MinReplicas: 1, MaxReplicas: 100, Triggers: []model.AutoScaleTrigger{}},
+			wantCPUTarget: defaultCPUTarget,
+		},
+		{
+			name:          "Scale config from legacy scale",
+			service:       &model.Service{Scale: &model.ScaleConfig{MinReplicas: 0, MaxReplicas: 5}},
+			want:          &model.AutoScaleConfig{PollingInterval: 15, CoolDownInterval: 120, MinReplicas: 0, MaxReplicas: 5, Triggers: []model.AutoScaleTrigger{}},
+			wantCPUTarget: defaultCPUTarget,
+		},
+		{
+			name: "Cpu trigger",
+			service: &model.Service{AutoScale: &model.AutoScaleConfig{MinReplicas: 2, MaxReplicas: 10, CoolDownInterval: 30, Triggers: []model.AutoScaleTrigger{
+				{Name: "cpu", Type: "cpu", MetaData: map[string]string{"target": "60"}},
+			}}},
+			want: &model.AutoScaleConfig{MinReplicas: 2, MaxReplicas: 10, CoolDownInterval: 30, Triggers: []model.AutoScaleTrigger{
+				{Name: "cpu", Type: "cpu", MetaData: map[string]string{"target": "60"}},
+			}},
+			wantCPUTarget: 60,
+		},
+		{
+			name: "Unsupported trigger",
+			service: &model.Service{AutoScale: &model.AutoScaleConfig{Triggers: []model.AutoScaleTrigger{
+				{Name: "requests", Type: "requests-per-second", MetaData: map[string]string{"target": "50"}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "Invalid cpu target",
+			service: &model.Service{AutoScale: &model.AutoScaleConfig{Triggers: []model.AutoScaleTrigger{
+				{Name: "cpu", Type: "cpu", MetaData: map[string]string{"target": "high"}},
+			}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setKubernetesScaleConfig(context.Background(), tt.service)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("setKubernetesScaleConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.wantCPUTarget {
+				t.Errorf("setKubernetesScaleConfig() cpu target = %v, want %v", got, tt.wantCPUTarget)
+			}
+			if arr := deep.Equal(tt.service.AutoScale, tt.want); len(arr) > 0 {
+				t.Errorf("setKubernetesScaleConfig() differences = %v", arr)
+			}
+		})
+	}
+}
+
+func Test_generateHorizontalPodAutoscaler(t *testing.T) {
+	service := &model.Service{ID: "orders", ProjectID: "myproject", Version: "v1", AutoScale: &model.AutoScaleConfig{MinReplicas: 0, MaxReplicas: 5}}
+
+	hpa := generateHorizontalPodAutoscaler(service, 70)
+	if hpa.Name != "orders-v1" || hpa.Spec.ScaleTargetRef.Name != "orders-v1" || hpa.Spec.ScaleTargetRef.Kind != "Deployment" {
+		t.Errorf("generateHorizontalPodAutoscaler() target = %v", hpa.Spec.ScaleTargetRef)
+	}
+	// Scaling down to zero is taken care of by the proxy
+	if *hpa.Spec.MinReplicas != 1 || hpa.Spec.MaxReplicas != 5 || *hpa.Spec.TargetCPUUtilizationPercentage != 70 {
+		t.Errorf("generateHorizontalPodAutoscaler() spec = (%d, %d, %d)", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas, *hpa.Spec.TargetCPUUtilizationPercentage)
+	}
+}
+
+func TestKubernetes_ResolveProxyTarget(t *testing.T) {
+	kube := kubefake.NewSimpleClientset(
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "myproject", Annotations: map[string]string{annotationProxyVersion: "v2"}}},
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "greeter", Namespace: "myproject"}},
+	)
+	k := &Kubernetes{Istio: &Istio{kube: kube}}
+
+	tests := []struct {
+		name    string
+		host    string
+		want    *model.ProxyTarget
+		wantErr bool
+	}{
+		{
+			name: "Host with port",
+			host: "orders.myproject.svc.cluster.local:8080",
+			want: &model.ProxyTarget{Project: "myproject", Service: "orders", Version: "v2", Host: "orders-v2-internal.myproject.svc.cluster.local", Port: "8080"},
+		},
+		{
+			name: "Host without port",
+			host: "orders.myproject.svc.cluster.local",
+			want: &model.ProxyTarget{Project: "myproject", Service: "orders", Version: "v2", Host: "orders-v2-internal.myproject.svc.cluster.local", Port: "80"},
+		},
+		{
+			name:    "Service which isn't proxied",
+			host:    "greeter.myproject.svc.cluster.local",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown service",
+			host:    "payments.myproject.svc.cluster.local",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid host",
+			host:    "localhost",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := k.ResolveProxyTarget(context.Background(), tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveProxyTarget() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if arr := deep.Equal(got, tt.want); len(arr) > 0 {
+				t.Errorf("ResolveProxyTarget() differences = %v", arr)
+			}
+		})
+	}
+}
@@ -12,6 +12,10 @@ import (
 
 // CreateProject creates a new namespace for the client
 func (i *Istio) CreateProject(ctx context.Context, project *model.Project) error {
+	return i.createNamespace(ctx, project, map[string]string{"istio-injection": "enabled"})
+}
+
+func (i *Istio) createNamespace(ctx context.Context, project *model.Project, labels map[string]string) error {
 	// Set the kind field if empty
 	if project.Kind == "" {
 		project.Kind = "project"
@@ -22,13 +26,16 @@ func (i *Istio) CreateProject(ctx context.Context, project *model.Project) error
 		ObjectMeta: metav1.ObjectMeta{
 			Name: namespace,
 			Labels: map[string]string{
-				"istio-injection":              "enabled",
 				"app.kubernetes.io/name":       namespace,
 				"app.kubernetes.io/managed-by": "space-cloud",
 				"space-cloud.io/kind":          project.Kind,
 			},
 		},
 	}
+	for k, v := range labels {
+		ns.Labels[k] = v
+	}
+
 	_, err := i.kube.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 	if kubeErrors.IsAlreadyExists(err) {
 		return nil
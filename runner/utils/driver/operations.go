@@ -2,6 +2,7 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/spaceuptech/space-cloud/runner/model"
@@ -115,3 +116,13 @@ func (m *Module) DeleteKey(ctx context.Context, projectID, secretName, secretKey
 func (m *Module) SetFileSecretRootPath(ctx context.Context, projectID string, secretName, rootPath string) error {
 	return m.driver.SetFileSecretRootPath(ctx, projectID, secretName, rootPath)
 }
+
+// ResolveProxyTarget returns the service version a request received by the proxy without the original destination
+// headers is meant for. It is only supported by drivers which route requests to the proxy without istio.
+func (m *Module) ResolveProxyTarget(ctx context.Context, host string) (*model.ProxyTarget, error) {
+	resolver, ok := m.driver.(proxyTargetResolver)
+	if !ok {
+		return nil, fmt.Errorf("driver (%s) does not support resolving the proxy target", m.driver.Type())
+	}
+	return resolver.ResolveProxyTarget(ctx, host)
+}
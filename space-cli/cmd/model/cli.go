@@ -6,6 +6,15 @@ type LoginResponse struct {
 	Error string `json:"error"`
 }
 
+// ErrorResponse is the error envelope returned by the apis of space cloud
+type ErrorResponse struct {
+	Error     string                 `json:"error"`
+	Code      string                 `json:"code"`
+	Module    string                 `json:"module"`
+	Retryable bool                   `json:"retryable"`
+	Details   map[string]interface{} `json:"details"`
+}
+
 // Credential is the object for representing all the account information in accounts.yaml file
 type Credential struct {
 	Accounts        []*Account `json:"accounts" yaml:"accounts"`
@@ -147,9 +147,8 @@ func ApplySpec(token string, account *model.Account, specObj *model.SpecObject)
 		return err
 	}
 
-	v := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&v)
-	utils.CloseTheCloser(req.Body)
+	data, _ := ioutil.ReadAll(resp.Body)
+	utils.CloseTheCloser(resp.Body)
 
 	if resp.StatusCode == http.StatusAccepted {
 		// Make checker send this status
@@ -157,8 +156,9 @@ func ApplySpec(token string, account *model.Account, specObj *model.SpecObject)
 	} else if resp.StatusCode == http.StatusOK {
 		utils.LogInfo(fmt.Sprintf("Successfully applied %s", specObj.Type))
 	} else {
-		_ = utils.LogError(fmt.Sprintf("error while applying service got http status code %s - %s", resp.Status, v["error"]), nil)
-		return fmt.Errorf("%v", v["error"])
+		err := utils.ParseErrorResponse(resp.StatusCode, data)
+		_ = utils.LogError(fmt.Sprintf("error while applying service got http status code %s", resp.Status), err)
+		return err
 	}
 	return nil
 }
@@ -2,10 +2,14 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
+
+	"github.com/spaceuptech/space-cloud/space-cli/cmd/model"
 )
 
 // Get gets spec object
@@ -38,12 +42,9 @@ func Get(method, url string, params map[string]string, vPtr interface{}) error {
 	data, _ := ioutil.ReadAll(resp.Body)
 
 	if resp.StatusCode != 200 {
-		respBody := map[string]interface{}{}
-		if err := json.Unmarshal(data, &respBody); err != nil {
-			return err
-		}
-		_ = LogError(fmt.Sprintf("error while getting service got http status code %s - %s", resp.Status, respBody["error"]), nil)
-		return fmt.Errorf("received invalid status code (%d)", resp.StatusCode)
+		err := ParseErrorResponse(resp.StatusCode, data)
+		_ = LogError(fmt.Sprintf("error while getting service got http status code %s", resp.Status), err)
+		return err
 	}
 
 	if err := json.Unmarshal(data, vPtr); err != nil {
@@ -53,6 +54,19 @@ func Get(method, url string, params map[string]string, vPtr interface{}) error {
 	return nil
 }
 
+// ParseErrorResponse returns the error sent by space cloud in the body of an error response. The code of the error
+// is added to its message so that it can be looked up in the api reference.
+func ParseErrorResponse(statusCode int, data []byte) error {
+	res := new(model.ErrorResponse)
+	if err := json.Unmarshal(data, res); err != nil || res.Error == "" {
+		return fmt.Errorf("received invalid status code (%d) - %s", statusCode, strings.TrimSpace(string(data)))
+	}
+	if res.Code == "" {
+		return errors.New(res.Error)
+	}
+	return fmt.Errorf("%s (%s)", res.Error, res.Code)
+}
+
 // CloseTheCloser closes the closer
 func CloseTheCloser(c io.Closer) {
 	_ = c.Close()
@@ -59,12 +59,9 @@ func (d *def) MakeHTTPRequest(method, url string, params map[string]string, vPtr
 	data, _ := ioutil.ReadAll(resp.Body)
 
 	if resp.StatusCode != 200 {
-		respBody := map[string]interface{}{}
-		if err := json.Unmarshal(data, &respBody); err != nil {
-			return err
-		}
-		_ = utils.LogError(fmt.Sprintf("error while getting service got http status code %s - %s", resp.Status, respBody["error"]), nil)
-		return fmt.Errorf("received invalid status code (%d)", resp.StatusCode)
+		err := utils.ParseErrorResponse(resp.StatusCode, data)
+		_ = utils.LogError(fmt.Sprintf("error while getting service got http status code %s", resp.Status), err)
+		return err
 	}
 
 	if err := json.Unmarshal(data, vPtr); err != nil {
@@ -98,12 +95,9 @@ func (d *def) GetLogs(url string) error {
 
 	if resp.StatusCode != 200 {
 		data, _ := ioutil.ReadAll(resp.Body)
-		respBody := map[string]interface{}{}
-		if err := json.Unmarshal(data, &respBody); err != nil {
-			return err
-		}
-		_ = utils.LogError(fmt.Sprintf("error while getting service logs got error %s", respBody["error"]), nil)
-		return fmt.Errorf("received invalid status code (%d)", resp.StatusCode)
+		err := utils.ParseErrorResponse(resp.StatusCode, data)
+		_ = utils.LogError("error while getting service logs", err)
+		return err
 	}
 	if resp.StatusCode == http.StatusNoContent {
 		return nil